@@ -2,28 +2,65 @@ package main
 
 import (
 	"context"
+	"io/fs"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 
 	_ "github.com/marcos-nsantos/field-notes-backend/docs"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/geoip"
 	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/identity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/mail"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/notification"
 	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository/postgres"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
 	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/auth"
 	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/cache"
 	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/config"
 	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/database"
+	embeddingInfra "github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/embedding"
+	geoipInfra "github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/geoip"
+	mailInfra "github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/mail"
 	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/middleware"
+	notificationInfra "github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/notification"
 	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/observability"
 	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/server"
 	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/storage"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/clock"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/account"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/anomaly"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/audit"
 	authUC "github.com/marcos-nsantos/field-notes-backend/internal/usecase/auth"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/authz"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/autotitle"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/cleanup"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/demo"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/health"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/jobs"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/kpi"
 	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/note"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/preference"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/privacy"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/quality"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/realtime"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/semantic"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/share"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/stats"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/summary"
 	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/sync"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/team"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/telemetry"
 	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/upload"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/usage"
+	"github.com/marcos-nsantos/field-notes-backend/migrations"
 )
 
 //	@title			Field Notes API
@@ -55,6 +92,12 @@ func main() {
 	}
 	defer logger.Sync()
 
+	logger.Info("config loaded",
+		zap.String("profile", string(cfg.Server.Environment)),
+		zap.String("file", cfg.File),
+		zap.Any("settings", cfg.Settings()),
+	)
+
 	ctx := context.Background()
 
 	pool, err := database.NewPostgresPool(ctx, cfg.Database)
@@ -64,76 +107,693 @@ func main() {
 	defer pool.Close()
 
 	// Run database migrations at startup to ensure schema is up-to-date
-	if err := database.RunMigrations(ctx, pool, "migrations"); err != nil {
+	var migrationsFS fs.FS = migrations.FS
+	if cfg.Database.MigrationsPath != "" {
+		migrationsFS = os.DirFS(cfg.Database.MigrationsPath)
+	}
+	if err := database.RunMigrations(ctx, pool, migrationsFS); err != nil {
 		logger.Fatal("failed to run migrations", zap.Error(err))
 	}
 
+	var replica *pgxpool.Pool
+	if cfg.Database.ReplicaHost != "" {
+		replica, err = database.NewPostgresPool(ctx, cfg.Database.Replica())
+		if err != nil {
+			logger.Fatal("failed to connect to read replica", zap.Error(err))
+		}
+		defer replica.Close()
+	}
+	reads := postgres.NewReadRouter(pool, replica)
+
+	// Warm the pools before the server listens, so the first requests after
+	// a deploy don't pay for connecting and planning. A failed warmup only
+	// leaves that to the first requests.
+	if cfg.Database.Warmup {
+		start := time.Now()
+		for _, p := range []*pgxpool.Pool{pool, replica} {
+			if p == nil {
+				continue
+			}
+			if err := database.Warmup(ctx, p, cfg.Database.MaxIdleConns, postgres.HotQueries()); err != nil {
+				logger.Warn("database warmup failed", zap.Error(err))
+			}
+		}
+		logger.Info("database pools warmed up", zap.Duration("duration", time.Since(start)))
+	}
+
+	// Latency histograms of storage and image operations, served to
+	// operators at /admin/metrics.
+	latencies := observability.NewLatencies()
+
+	s3Storage, err := storage.NewS3Storage(cfg.S3, latencies)
+	if err != nil {
+		logger.Fatal("failed to create s3 storage", zap.Error(err))
+	}
+	if cfg.S3.VerifyBucket {
+		if err := s3Storage.VerifyBucket(ctx); err != nil {
+			logger.Fatal("s3 bucket does not meet the storage policy", zap.Error(err))
+		}
+	}
+
+	// Everything that stamps or compares times reads the same clock.
+	clk := clock.System{}
+
 	// Repositories
 	userRepo := postgres.NewUserRepo(pool)
-	noteRepo := postgres.NewNoteRepo(pool)
-	photoRepo := postgres.NewPhotoRepo(pool)
+	noteRepo := postgres.NewNoteRepo(pool, reads, &postgres.ContentOffload{
+		Storage:   s3Storage,
+		Threshold: cfg.Note.ContentOffloadThreshold,
+	}, clk)
+	photoRepo := postgres.NewPhotoRepo(pool, clk)
+	attachmentRepo := postgres.NewAttachmentRepo(pool)
 	deviceRepo := postgres.NewDeviceRepo(pool)
-	refreshTokenRepo := postgres.NewRefreshTokenRepo(pool)
+	refreshTokenRepo := postgres.NewRefreshTokenRepo(pool, clk)
+	authProviderRepo := postgres.NewAuthProviderRepo(pool)
+	ssoSecrets, err := auth.NewSecretBox(cfg.SSO.SecretKey)
+	if err != nil {
+		logger.Fatal("invalid SSO_SECRET_KEY", zap.Error(err))
+	}
+	orgRepo := postgres.NewOrganizationRepo(pool, ssoSecrets)
+	deviceUsageRepo := postgres.NewDeviceUsageRepo(pool)
+	clientUsageRepo := postgres.NewClientUsageRepo(pool)
+	deprecationUsageRepo := postgres.NewDeprecationUsageRepo(pool)
+	kpiRepo := postgres.NewKPIRepo(pool)
+	userStatsRepo := postgres.NewUserStatsRepo(pool)
+	summaryRepo := postgres.NewNoteSummaryRepo(pool, reads)
+	authEventRepo := postgres.NewAuthEventRepo(pool)
+	auditEventRepo := postgres.NewAuditEventRepo(pool)
+	resetTokenRepo := postgres.NewPasswordResetTokenRepo(pool)
+	alertRepo := postgres.NewSecurityAlertRepo(pool)
+	qualityRuleRepo := postgres.NewQualityRuleRepo(pool)
+	shareRepo := postgres.NewShareRepo(pool)
+	teamRepo := postgres.NewTeamRepo(pool)
+	accountDeletionRepo := postgres.NewAccountDeletionRepo(pool)
+	syncConflictRepo := postgres.NewSyncConflictRepo(pool)
+	storageOrphanRepo := postgres.NewStorageOrphanRepo(pool)
+	noteDocumentRepo := postgres.NewNoteDocumentRepo(pool)
 
 	// Infrastructure services
-	jwtSvc := auth.NewJWTService(cfg.JWT.SecretKey, cfg.JWT.AccessTokenTTL)
+	privateKeys := make(map[string][]byte, len(cfg.JWT.PrivateKeyFiles))
+	for id, path := range cfg.JWT.PrivateKeyFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			logger.Fatal("failed to read jwt private key", zap.String("kid", id), zap.Error(err))
+		}
+		privateKeys[id] = data
+	}
+	keyring, err := auth.NewKeyring(cfg.JWT.Keyring(), cfg.JWT.SigningKeyID, privateKeys)
+	if err != nil {
+		logger.Fatal("failed to load jwt keys", zap.Error(err))
+	}
+	jwtSvc := auth.NewJWTServiceWithKeyring(keyring, cfg.JWT.AccessTokenTTL)
 	passwordHasher := auth.NewPasswordHasher(12)
+	oidcClient := auth.NewOIDCClient(cfg.SSO.CallbackBaseURL, cfg.SSO.HTTPTimeout, cfg.SSO.KeyRefreshInterval)
+	socialVerifiers := make(map[string]identity.SocialVerifier)
+	if len(cfg.OAuth.GoogleClientIDs) > 0 {
+		socialVerifiers[entity.AuthProviderGoogle] = auth.NewGoogleVerifier(oidcClient, cfg.OAuth.GoogleClientIDs)
+	}
+	if len(cfg.OAuth.AppleClientIDs) > 0 {
+		socialVerifiers[entity.AuthProviderApple] = auth.NewAppleVerifier(oidcClient, cfg.OAuth.AppleClientIDs)
+	}
 
-	s3Storage, err := storage.NewS3Storage(cfg.S3)
-	if err != nil {
-		logger.Fatal("failed to create s3 storage", zap.Error(err))
+	imageProcessor := storage.NewImageProcessor(latencies)
+
+	var notifier notification.Notifier
+	if cfg.Notification.WebhookURL != "" {
+		notifier = notificationInfra.NewWebhookNotifier(
+			cfg.Notification.WebhookURL, cfg.Notification.WebhookSecret, cfg.Notification.AppURL, cfg.Notification.Timeout,
+		)
+	}
+
+	var apnsPusher *notificationInfra.APNsPusher
+	if cfg.Push.APNsKeyFile != "" {
+		key, err := os.ReadFile(cfg.Push.APNsKeyFile)
+		if err != nil {
+			logger.Fatal("failed to read PUSH_APNS_KEY_FILE", zap.Error(err))
+		}
+		apnsURL := notificationInfra.APNsProductionURL
+		if cfg.Push.APNsSandbox {
+			apnsURL = notificationInfra.APNsSandboxURL
+		}
+		apnsPusher, err = notificationInfra.NewAPNsPusher(notificationInfra.APNsConfig{
+			KeyPEM: key, KeyID: cfg.Push.APNsKeyID, TeamID: cfg.Push.APNsTeamID, Topic: cfg.Push.APNsTopic, BaseURL: apnsURL,
+		}, cfg.Push.Timeout)
+		if err != nil {
+			logger.Fatal("invalid apns key", zap.Error(err))
+		}
+	}
+	var fcmPusher *notificationInfra.FCMPusher
+	if cfg.Push.FCMCredentialsFile != "" {
+		credentials, err := os.ReadFile(cfg.Push.FCMCredentialsFile)
+		if err != nil {
+			logger.Fatal("failed to read PUSH_FCM_CREDENTIALS_FILE", zap.Error(err))
+		}
+		fcmPusher, err = notificationInfra.NewFCMPusher(credentials, notificationInfra.FCMURL, cfg.Push.Timeout)
+		if err != nil {
+			logger.Fatal("invalid fcm credentials", zap.Error(err))
+		}
+	}
+	var pusher notification.Pusher
+	if apnsPusher != nil || fcmPusher != nil {
+		pusher = notificationInfra.NewPushDispatcher(apnsPusher, fcmPusher)
+	}
+
+	var mailer mail.Mailer
+	if cfg.Mail.SMTPHost != "" {
+		mailer = mailInfra.NewSMTPMailer(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUsername, cfg.Mail.SMTPPassword, cfg.Mail.From)
+	}
+
+	var geoResolver geoip.Resolver
+	if cfg.GeoIP.APIURL != "" {
+		geoResolver = geoipInfra.NewHTTPResolver(cfg.GeoIP.APIURL, cfg.GeoIP.Timeout)
+	}
+
+	// Redis is optional; without REDIS_HOST the rate limiter keeps its state in-process
+	var redisClient *redis.Client
+	if cfg.Redis.Enabled() {
+		redisClient, err = cache.NewRedisClient(cfg.Redis)
+		if err != nil {
+			logger.Fatal("failed to connect to redis", zap.Error(err))
+		}
+		defer redisClient.Close()
+	} else {
+		logger.Info("REDIS_HOST not set, using in-process rate limiting")
 	}
-	imageProcessor := storage.NewImageProcessor()
 
 	// Rate limiter
 	var rateLimiter *middleware.RateLimiter
 	if cfg.RateLimit.Enabled {
-		redisClient, err := cache.NewRedisClient(cfg.Redis)
+		store := middleware.NewRateLimitStore(redisClient, cfg.RateLimit.CleanupInterval)
+		rateLimiter, err = middleware.NewRateLimiter(store, cfg.RateLimit)
 		if err != nil {
-			logger.Fatal("failed to connect to redis", zap.Error(err))
+			logger.Fatal("failed to create rate limiter", zap.Error(err))
 		}
-		defer redisClient.Close()
-		rateLimiter = middleware.NewRateLimiter(redisClient, cfg.RateLimit)
+	}
+
+	var uploadLimiter *middleware.UploadLimiter
+	if cfg.UploadLimit.Enabled {
+		store := middleware.NewUploadLimitStore(redisClient, cfg.RateLimit.CleanupInterval)
+		uploadLimiter = middleware.NewUploadLimiter(store, cfg.UploadLimit)
+	}
+
+	var idempotency *middleware.Idempotency
+	if cfg.Idempotency.Enabled {
+		store := middleware.NewIdempotencyStore(redisClient, cfg.RateLimit.CleanupInterval)
+		idempotency = middleware.NewIdempotency(store, cfg.Idempotency)
+	}
+
+	// Change feed; with Redis, events reach devices connected to any instance
+	var changeRelay realtime.Relay
+	if redisClient != nil {
+		changeRelay = cache.NewChangeRelay(redisClient)
+	}
+	changeHub := realtime.NewHub(changeRelay)
+
+	// Presence on open notes, shared the same way; only kept in Redis
+	var presenceStore realtime.PresenceStore = cache.NewMemoryPresenceStore()
+	var presenceRelay realtime.PresenceRelay
+	if redisClient != nil {
+		presenceStore = cache.NewRedisPresenceStore(redisClient)
+		presenceRelay = cache.NewPresenceRelay(redisClient)
+	}
+
+	// Updates to collaborative notes, relayed the same way
+	var documentRelay realtime.DocumentRelay
+	if redisClient != nil {
+		documentRelay = cache.NewDocumentRelay(redisClient)
+	}
+
+	// Failed password logins, counted across instances with Redis
+	var loginAttempts authUC.LoginAttemptStore = cache.NewMemoryLoginAttemptStore()
+	if redisClient != nil {
+		loginAttempts = cache.NewRedisLoginAttemptStore(redisClient)
 	}
 
 	// Use cases
-	authSvc := authUC.NewService(userRepo, deviceRepo, refreshTokenRepo, jwtSvc, passwordHasher, cfg.JWT.RefreshTokenTTL)
-	noteSvc := note.NewService(noteRepo, photoRepo)
-	syncSvc := sync.NewService(noteRepo, deviceRepo)
-	uploadSvc := upload.NewService(photoRepo, noteRepo, s3Storage, imageProcessor)
+	sessions, err := authUC.NewSessionConfig(cfg.Device.Platforms, cfg.Device.AccessTTL, cfg.Device.RefreshTTL, cfg.Device.MaxSessions)
+	if err != nil {
+		logger.Fatal("invalid device platform config", zap.Error(err))
+	}
+	auditRecorder := audit.NewRecorder(auditEventRepo)
+	var lockout *authUC.Lockout
+	if cfg.Security.LockoutEnabled {
+		lockout = authUC.NewLockout(loginAttempts, authUC.LockoutConfig{
+			Threshold:   cfg.Security.LockoutThreshold,
+			IPThreshold: cfg.Security.LockoutIPThreshold,
+			Window:      cfg.Security.LockoutWindow,
+			Duration:    cfg.Security.LockoutDuration,
+			MaxDuration: cfg.Security.LockoutMaxDuration,
+		})
+	}
+	authSvc := authUC.NewService(userRepo, deviceRepo, refreshTokenRepo, orgRepo, jwtSvc, passwordHasher, oidcClient, geoResolver, authEventRepo, resetTokenRepo, mailer, cfg.JWT.RefreshTokenTTL, authUC.PasswordResetConfig{
+		TokenTTL: cfg.Reset.TokenTTL,
+		URL:      cfg.Reset.URL,
+	}, sessions, authProviderRepo, socialVerifiers, auditRecorder, lockout, clk)
+	authorizer := authz.NewAuthorizer(shareRepo, orgRepo, teamRepo)
+	titler := autotitle.NewTitler(nil)
+	noteSvc := note.NewService(noteRepo, photoRepo, qualityRuleRepo, authorizer, auditRecorder, titler, clk)
+	syncSvc := sync.NewService(noteRepo, photoRepo, deviceRepo, qualityRuleRepo, userRepo, notifier, pusher, syncConflictRepo, cfg.Sync.ConflictRetention, titler, clk)
+	uploadSvc := upload.NewService(photoRepo, attachmentRepo, noteRepo, qualityRuleRepo, s3Storage, imageProcessor, authorizer)
+	usageSvc := usage.NewService(deviceUsageRepo, deviceRepo)
+	telemetrySvc := telemetry.NewService(clientUsageRepo, cfg.Telemetry.Retention)
+	deprecationSvc := telemetry.NewDeprecationService(deprecationUsageRepo, server.Deprecations(), cfg.Telemetry.Retention)
+	kpiSvc := kpi.NewService(kpiRepo)
+	statsSvc := stats.NewService(userStatsRepo)
+	summarySvc := summary.NewService(summaryRepo, authorizer)
+	presenceSvc := realtime.NewPresence(noteRepo, authorizer, presenceStore, presenceRelay, cfg.Realtime.PresenceTTL)
+	documentsSvc := realtime.NewDocuments(noteRepo, noteDocumentRepo, authorizer, documentRelay)
+	accountSvc := account.NewService(accountDeletionRepo, s3Storage, cfg.Account.PurgeDelay, auditRecorder)
+	cleanupSvc := cleanup.NewService(refreshTokenRepo, noteRepo, storageOrphanRepo, s3Storage, cfg.Cleanup.NoteRetention, cfg.Cleanup.Batch)
+	var alertNotifier notification.Notifier
+	if cfg.Anomaly.NotifyUsers {
+		alertNotifier = notifier
+	}
+	anomalySvc := anomaly.NewService(authEventRepo, alertRepo, deviceUsageRepo, deviceRepo, userRepo, alertNotifier, anomaly.Thresholds{
+		MaxTravelSpeed:    cfg.Anomaly.MaxTravelSpeed,
+		MinTravelDistance: cfg.Anomaly.MinTravelDistance,
+		RefreshLimit:      cfg.Anomaly.RefreshLimit,
+		RefreshWindow:     cfg.Anomaly.RefreshWindow,
+		DailySyncBytes:    cfg.Anomaly.DailySyncBytes,
+	})
+	qualitySvc := quality.NewService(qualityRuleRepo, noteRepo, photoRepo)
+	prefSvc := preference.NewService(userRepo)
+	shareSvc := share.NewService(noteRepo, userRepo, shareRepo, authorizer)
+	teamSvc := team.NewService(teamRepo, userRepo, authorizer)
+	for _, d := range cfg.PII.Detectors {
+		if !entity.IsPIIDetector(d) {
+			logger.Fatal("unknown PII detector", zap.String("detector", d))
+		}
+	}
+	privacySvc := privacy.NewService(noteRepo, authorizer, entity.PIIScanner{
+		Detectors:       cfg.PII.Detectors,
+		PreciseAccuracy: cfg.PII.PreciseAccuracy,
+	})
 
 	// Handlers
 	authHandler := handler.NewAuthHandler(authSvc)
-	noteHandler := handler.NewNoteHandler(noteSvc)
-	syncHandler := handler.NewSyncHandler(syncSvc)
+	locationMask := entity.LocationMask{LowGrid: cfg.Sensitive.LowGrid, HighGrid: cfg.Sensitive.HighGrid}
+	noteHandler := handler.NewNoteHandler(noteSvc, prefSvc, locationMask)
+	summaryHandler := handler.NewNoteSummaryHandler(summarySvc, locationMask)
+	syncHandler := handler.NewSyncHandler(syncSvc, prefSvc, locationMask)
 	uploadHandler := handler.NewUploadHandler(uploadSvc)
+	deviceHandler := handler.NewDeviceHandler(usageSvc, syncSvc)
+	qualityHandler := handler.NewQualityHandler(qualitySvc)
+	prefHandler := handler.NewPreferenceHandler(prefSvc)
+	shareHandler := handler.NewShareHandler(shareSvc)
+	teamHandler := handler.NewTeamHandler(teamSvc)
+	privacyHandler := handler.NewPrivacyHandler(privacySvc)
+	labelHandler := handler.NewLabelHandler(noteSvc, cfg.Label.LinkURL)
+	statsHandler := handler.NewStatsHandler(statsSvc)
+	accountHandler := handler.NewAccountHandler(accountSvc)
+	alertHandler := handler.NewAlertHandler(anomalySvc)
+	var clientHandler *handler.ClientHandler
+	var clientRecorder middleware.ClientRecorder
+	var deprecationHandler *handler.DeprecationHandler
+	var deprecationRecorder middleware.DeprecationRecorder
+	if cfg.Telemetry.Enabled {
+		clientHandler = handler.NewClientHandler(telemetrySvc)
+		clientRecorder = telemetrySvc
+		deprecationHandler = handler.NewDeprecationHandler(deprecationSvc)
+		deprecationRecorder = deprecationSvc
+	}
+	var documentHandler *handler.DocumentHandler
+	var documents handler.DocumentService
+	if cfg.Collab.Enabled {
+		documentHandler = handler.NewDocumentHandler(documentsSvc)
+		documents = documentsSvc
+	}
+	// Semantic search needs an embeddings provider and pgvector; without a
+	// provider the routes and the indexing job are left out
+	var semanticSvc *semantic.Service
+	var semanticHandler *handler.SemanticHandler
+	if cfg.Semantic.APIURL != "" {
+		embeddingRepo := postgres.NewNoteEmbeddingRepo(pool)
+		available, err := embeddingRepo.Available(ctx)
+		if err != nil {
+			logger.Fatal("failed to check note embeddings", zap.Error(err))
+		}
+		if !available {
+			logger.Fatal("EMBEDDINGS_API_URL is set but the database has no note_embeddings table; install pgvector and run migration 000061 again")
+		}
+		provider := embeddingInfra.NewHTTPProvider(cfg.Semantic.APIURL, cfg.Semantic.APIKey, cfg.Semantic.Model, cfg.Semantic.Timeout)
+		semanticSvc = semantic.NewService(embeddingRepo, noteRepo, userRepo, photoRepo, authorizer, provider, cfg.Semantic.Model)
+		semanticHandler = handler.NewSemanticHandler(semanticSvc, prefSvc, locationMask)
+	}
+	var realtimeHandler *handler.RealtimeHandler
+	var changePublisher middleware.ChangePublisher
+	if cfg.Realtime.Enabled {
+		var presence handler.PresenceService
+		if cfg.Realtime.Presence {
+			presence = presenceSvc
+		}
+		realtimeHandler = handler.NewRealtimeHandler(changeHub, presence, documents, cfg.Realtime.Heartbeat)
+		changePublisher = changeHub
+	}
+	var kpiHandler *handler.KPIHandler
+	var kpiRecorder middleware.KPIRecorder
+	if cfg.KPI.Enabled {
+		kpiHandler = handler.NewKPIHandler(kpiSvc)
+		kpiRecorder = kpiSvc
+	}
+
+	// Demo mode seeds a read-only account and resets it periodically
+	// Periodic jobs, started once everything is wired
+	scheduler := jobs.NewScheduler(cfg.Jobs.HistorySize)
+
+	var demoHandler *handler.DemoHandler
+	var demoUserID uuid.UUID
+	if cfg.Demo.Enabled {
+		demoSvc := demo.NewService(userRepo, noteRepo, passwordHasher, demo.Credentials{
+			Email:    cfg.Demo.Email,
+			Password: cfg.Demo.Password,
+		})
+		demoUserID, err = demoSvc.Reset(ctx)
+		if err != nil {
+			logger.Fatal("failed to seed demo account", zap.Error(err))
+		}
+		demoHandler = handler.NewDemoHandler(demoSvc)
+
+		scheduler.Add("demo-reset", cfg.Demo.ResetInterval, func(ctx context.Context) error {
+			if _, err := demoSvc.Reset(ctx); err != nil {
+				logger.Warn("failed to reset demo account", zap.Error(err))
+				return err
+			}
+			return nil
+		})
+	}
+
+	// Readiness probe: the dependencies requests can't be served without
+	readiness := health.NewChecker(cfg.Server.ReadinessTimeout)
+	readiness.Add("postgres", pool.Ping)
+	if replica != nil {
+		readiness.Add("postgres_replica", replica.Ping)
+	}
+	if redisClient != nil {
+		readiness.Add("redis", func(ctx context.Context) error {
+			return redisClient.Ping(ctx).Err()
+		})
+	}
+	readiness.Add("s3", s3Storage.Ping)
 
 	// Middleware
-	authMiddleware := middleware.NewAuthMiddleware(jwtSvc)
+	authMiddleware := middleware.NewAuthMiddleware(jwtSvc, middleware.NewTokenVersionCache(userRepo, cfg.JWT.TokenVersionCacheTTL))
 
 	// Router
+	// Write versions only matter to clients when reads can hit a replica.
+	var versions middleware.VersionSource
+	if replica != nil {
+		versions = reads
+	}
+
 	router := server.NewRouter(server.RouterConfig{
-		AuthHandler:     authHandler,
-		NoteHandler:     noteHandler,
-		SyncHandler:     syncHandler,
-		UploadHandler:   uploadHandler,
-		AuthMiddleware:  authMiddleware,
-		RateLimiter:     rateLimiter,
-		RateLimitEnable: cfg.RateLimit.Enabled,
-		Logger:          logger,
-		Environment:     cfg.Server.Environment,
+		AuthHandler:       authHandler,
+		NoteHandler:       noteHandler,
+		SummaryHandler:    summaryHandler,
+		SyncHandler:       syncHandler,
+		UploadHandler:     uploadHandler,
+		DeviceHandler:     deviceHandler,
+		QualityHandler:    qualityHandler,
+		PreferenceHandler: prefHandler,
+		ShareHandler:      shareHandler,
+		TeamHandler:       teamHandler,
+		PrivacyHandler:    privacyHandler,
+		LabelHandler:      labelHandler,
+		StatsHandler:      statsHandler,
+		AccountHandler:    accountHandler,
+		AlertHandler:      alertHandler,
+		DemoHandler:       demoHandler,
+		JobHandler:        handler.NewJobHandler(scheduler),
+		ClientHandler:     clientHandler,
+		KPIHandler:        kpiHandler,
+		MetricsHandler:    handler.NewMetricsHandler(latencies),
+		RealtimeHandler:   realtimeHandler,
+		HealthHandler:     handler.NewHealthHandler(readiness),
+		AuthMiddleware:    authMiddleware,
+		UsageRecorder:     usageSvc,
+		ClientRecorder:    clientRecorder,
+		KPIRecorder:       kpiRecorder,
+		ChangePublisher:   changePublisher,
+		RateLimiter:       rateLimiter,
+		RateLimitEnable:   cfg.RateLimit.Enabled,
+		UploadLimiter:     uploadLimiter,
+		Latencies:         latencies,
+		UploadBudget:      cfg.Latency.UploadBudget,
+		Idempotency:       idempotency,
+		Versions:          versions,
+		PasswordReset:     mailer != nil,
+		JobsPassword:      cfg.Jobs.DashboardPassword,
+		DemoUserID:        demoUserID,
+		HandlerTimeout:    cfg.Server.HandlerTimeout,
+		LongTimeout:       cfg.Server.LongHandlerTimeout,
+		MaxBodyBytes:      cfg.Server.MaxBodyBytes,
+		MaxSyncBodyBytes:  cfg.Server.MaxSyncBodyBytes,
+		Logger:            logger,
+		Environment:       string(cfg.Server.Environment),
+
+		Deprecations:        server.Deprecations(),
+		DeprecationRecorder: deprecationRecorder,
+		DeprecationHandler:  deprecationHandler,
+
+		AuditHandler:    handler.NewAuditHandler(auditRecorder),
+		DocumentHandler: documentHandler,
+		SemanticHandler: semanticHandler,
+		CORSOrigins:     cfg.Server.CORSAllowedOrigins,
+		SwaggerExposure: server.Exposure(cfg.Server.SwaggerExposure),
+		AdminUserIDs:    cfg.Jobs.AdminUserIDs,
+		JWKSHandler:     handler.NewJWKSHandler(jwtSvc),
 	})
 
 	// Server
 	srv := server.NewServer(server.ServerConfig{
-		Port:            cfg.Server.Port,
-		ReadTimeout:     cfg.Server.ReadTimeout,
-		WriteTimeout:    cfg.Server.WriteTimeout,
-		ShutdownTimeout: cfg.Server.ShutdownTimeout,
-		Handler:         router.Engine(),
-		Logger:          logger,
+		Port:              cfg.Server.Port,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		ShutdownTimeout:   cfg.Server.ShutdownTimeout,
+		Handler:           router.Engine(),
+		Logger:            logger,
 	})
 
+	// Data usage is accounted in memory and persisted periodically
+	scheduler.Add("usage-flush", cfg.Usage.FlushInterval, func(ctx context.Context) error {
+		if err := usageSvc.Flush(ctx); err != nil {
+			logger.Warn("failed to flush device usage", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+
+	// Client versions are counted the same way; old daily counts are pruned
+	if cfg.Telemetry.Enabled {
+		scheduler.Add("client-telemetry-flush", cfg.Telemetry.FlushInterval, func(ctx context.Context) error {
+			if err := telemetrySvc.Flush(ctx); err != nil {
+				logger.Warn("failed to flush client usage", zap.Error(err))
+				return err
+			}
+			if _, err := telemetrySvc.Prune(ctx, time.Now().UTC()); err != nil {
+				logger.Warn("failed to prune client usage", zap.Error(err))
+				return err
+			}
+			if err := deprecationSvc.Flush(ctx); err != nil {
+				logger.Warn("failed to flush deprecation usage", zap.Error(err))
+				return err
+			}
+			if _, err := deprecationSvc.Prune(ctx, time.Now().UTC()); err != nil {
+				logger.Warn("failed to prune deprecation usage", zap.Error(err))
+				return err
+			}
+			return nil
+		})
+	}
+
+	// KPI events are counted the same way and the day's KPIs recomputed
+	if cfg.KPI.Enabled {
+		scheduler.Add("kpi-compute", cfg.KPI.Interval, func(ctx context.Context) error {
+			if err := kpiSvc.Flush(ctx); err != nil {
+				logger.Warn("failed to flush kpi events", zap.Error(err))
+				return err
+			}
+			if _, err := kpiSvc.Compute(ctx, time.Now().UTC()); err != nil {
+				logger.Warn("failed to compute kpis", zap.Error(err))
+				return err
+			}
+			return nil
+		})
+	}
+
+	// User stats are kept by triggers; recount periodically to catch drift
+	scheduler.Add("stats-reconcile", cfg.Stats.ReconcileInterval, func(ctx context.Context) error {
+		drifts, err := statsSvc.Reconcile(ctx)
+		if err != nil {
+			logger.Warn("failed to reconcile user stats", zap.Error(err))
+			return err
+		}
+		for _, d := range drifts {
+			logger.Warn("user stats drifted",
+				zap.String("user_id", d.Stored.UserID.String()),
+				zap.Int64("stored_notes", d.Stored.NoteCount), zap.Int64("actual_notes", d.Actual.NoteCount),
+				zap.Int64("stored_photos", d.Stored.PhotoCount), zap.Int64("actual_photos", d.Actual.PhotoCount),
+				zap.Int64("stored_bytes", d.Stored.StorageBytes), zap.Int64("actual_bytes", d.Actual.StorageBytes),
+			)
+		}
+		return nil
+	})
+
+	// Note summaries are kept by triggers; rebuild periodically to catch drift
+	scheduler.Add("note-summaries-rebuild", cfg.Summary.RebuildInterval, func(ctx context.Context) error {
+		fixed, err := summarySvc.Rebuild(ctx)
+		if err != nil {
+			logger.Warn("failed to rebuild note summaries", zap.Error(err))
+			return err
+		}
+		if fixed > 0 {
+			logger.Warn("note summaries drifted", zap.Int64("fixed", fixed))
+		}
+		return nil
+	})
+
+	// Anomaly detection rereads the recent auth history each run; alerts are
+	// deduplicated, so overlapping windows raise each one only once
+	scheduler.Add("anomaly-analysis", cfg.Anomaly.Interval, func(ctx context.Context) error {
+		now := time.Now().UTC()
+		alerts, err := anomalySvc.Analyze(ctx, now.Add(-cfg.Anomaly.Lookback), now)
+		if err != nil {
+			logger.Warn("failed to analyze auth events", zap.Error(err))
+			return err
+		}
+		for _, a := range alerts {
+			logger.Warn("security alert",
+				zap.String("user_id", a.UserID.String()),
+				zap.String("kind", a.Kind),
+				zap.String("detail", a.Detail),
+			)
+		}
+		if _, err := anomalySvc.Prune(ctx, now.Add(-cfg.Anomaly.EventRetention)); err != nil {
+			logger.Warn("failed to prune auth events", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+
+	scheduler.Add("audit-prune", cfg.Cleanup.Interval, func(ctx context.Context) error {
+		if _, err := auditRecorder.Prune(ctx, time.Now().UTC().Add(-cfg.Audit.Retention)); err != nil {
+			logger.Warn("failed to prune audit events", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+
+	scheduler.Add("sync-conflict-prune", cfg.Sync.ConflictPruneInterval, func(ctx context.Context) error {
+		if _, err := syncSvc.PruneConflicts(ctx, time.Now().UTC()); err != nil {
+			logger.Warn("failed to prune sync conflicts", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+
+	// Deleted accounts are locked at once and purged here, files first
+	scheduler.Add("account-purge", cfg.Account.PurgeInterval, func(ctx context.Context) error {
+		purged, err := accountSvc.Purge(ctx, cfg.Account.PurgeBatch)
+		for _, d := range purged {
+			logger.Info("account purged",
+				zap.String("user_id", d.UserID.String()),
+				zap.Int("objects_deleted", d.ObjectsDeleted),
+			)
+		}
+		if err != nil {
+			logger.Warn("failed to purge deleted accounts", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+
+	if cfg.Collab.Enabled {
+		scheduler.Add("document-compact", cfg.Collab.CompactInterval, func(ctx context.Context) error {
+			compacted, err := documentsSvc.Compact(ctx, cfg.Collab.CompactThreshold, cfg.Collab.CompactBatch)
+			if compacted > 0 {
+				logger.Info("note documents compacted", zap.Int("documents", compacted))
+			}
+			if err != nil {
+				logger.Warn("failed to compact note documents", zap.Error(err))
+				return err
+			}
+			return nil
+		})
+	}
+
+	// Notes of opted-in users are embedded after they change; embeddings of
+	// deleted notes and of users who opted out are dropped
+	if semanticSvc != nil {
+		scheduler.Add("note-embeddings", cfg.Semantic.Interval, func(ctx context.Context) error {
+			indexed, err := semanticSvc.Index(ctx, cfg.Semantic.Batch)
+			if indexed > 0 {
+				logger.Info("notes embedded", zap.Int("notes", indexed))
+			}
+			if err != nil {
+				logger.Warn("failed to embed notes", zap.Error(err))
+				return err
+			}
+			if _, err := semanticSvc.Prune(ctx); err != nil {
+				logger.Warn("failed to prune note embeddings", zap.Error(err))
+				return err
+			}
+			return nil
+		})
+	}
+
+	scheduler.Add("token-cleanup", cfg.Cleanup.Interval, func(ctx context.Context) error {
+		if _, err := cleanupSvc.DeleteExpiredTokens(ctx); err != nil {
+			logger.Warn("failed to delete expired tokens", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+
+	// Purged notes queue their files in storage_orphans for storage-gc
+	scheduler.Add("note-purge", cfg.Cleanup.Interval, func(ctx context.Context) error {
+		purged, err := cleanupSvc.PurgeDeletedNotes(ctx, time.Now().UTC())
+		if purged > 0 {
+			logger.Info("deleted notes purged", zap.Int64("notes", purged))
+		}
+		if err != nil {
+			logger.Warn("failed to purge deleted notes", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+
+	scheduler.Add("storage-gc", cfg.Cleanup.Interval, func(ctx context.Context) error {
+		if _, err := cleanupSvc.CollectOrphans(ctx); err != nil {
+			logger.Warn("failed to delete orphaned objects", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+
+	jobsCtx, stopJobs := context.WithCancel(ctx)
+	scheduler.Start(jobsCtx)
+	if cfg.Realtime.Enabled {
+		go func() {
+			if err := changeHub.Run(jobsCtx); err != nil {
+				logger.Error("change feed stopped relaying events", zap.Error(err))
+			}
+		}()
+	}
+	if cfg.Realtime.Enabled && cfg.Realtime.Presence {
+		go func() {
+			if err := presenceSvc.Run(jobsCtx); err != nil {
+				logger.Error("presence stopped relaying events", zap.Error(err))
+			}
+		}()
+	}
+	if cfg.Realtime.Enabled && cfg.Collab.Enabled {
+		go func() {
+			if err := documentsSvc.Run(jobsCtx); err != nil {
+				logger.Error("collaborative notes stopped relaying updates", zap.Error(err))
+			}
+		}()
+	}
+
 	// Graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -149,9 +809,25 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
+	// Hijacked WebSocket connections outlive Shutdown unless told to close
+	changeHub.Close()
 	if err := srv.Shutdown(ctx); err != nil {
 		logger.Error("server shutdown error", zap.Error(err))
 	}
 
+	stopJobs()
+	if err := usageSvc.Flush(ctx); err != nil {
+		logger.Error("failed to flush device usage", zap.Error(err))
+	}
+	if err := telemetrySvc.Flush(ctx); err != nil {
+		logger.Error("failed to flush client usage", zap.Error(err))
+	}
+	if err := deprecationSvc.Flush(ctx); err != nil {
+		logger.Error("failed to flush deprecation usage", zap.Error(err))
+	}
+	if err := kpiSvc.Flush(ctx); err != nil {
+		logger.Error("failed to flush kpi events", zap.Error(err))
+	}
+
 	logger.Info("server stopped")
 }
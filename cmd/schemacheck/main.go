@@ -0,0 +1,96 @@
+// Command schemacheck checks a database and its pending migrations before a
+// deploy. It reports migrations that would lock or fail on tables holding
+// data, and indexes the repositories' frequent queries depend on that the
+// database lacks. It exits with status 1 when it finds any.
+//
+// Pending migrations are those after the version golang-migrate recorded in
+// the database, or after -since. The database is configured with the same
+// DB_* variables as the API.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository/postgres"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/config"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/database"
+	"github.com/marcos-nsantos/field-notes-backend/migrations"
+)
+
+func main() {
+	dir := flag.String("dir", "", "migrations directory (default: the migrations built into the binary)")
+	since := flag.Int("since", -1, "check migrations after this version (default: the database's version, or all)")
+	offline := flag.Bool("offline", false, "only check migrations, without connecting to the database")
+	flag.Parse()
+
+	os.Exit(run(*dir, *since, *offline))
+}
+
+func run(dir string, since int, offline bool) int {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var migrationsFS fs.FS = migrations.FS
+	if dir != "" {
+		migrationsFS = os.DirFS(dir)
+	}
+
+	failed := false
+	if !offline {
+		dbCfg, err := config.LoadDatabase()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "schemacheck: %v (use -offline to only check migrations)\n", err)
+			return 2
+		}
+		pool, err := database.NewPostgresPool(ctx, *dbCfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "schemacheck: %v\n", err)
+			return 2
+		}
+		defer pool.Close()
+
+		if since < 0 {
+			version, ok, err := database.MigratedVersion(ctx, pool)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "schemacheck: %v\n", err)
+				return 2
+			}
+			if ok {
+				since = version
+			}
+		}
+
+		for _, use := range postgres.RequiredIndexes() {
+			ok, err := database.HasIndex(ctx, pool, use.Table, use.Columns)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "schemacheck: %v\n", err)
+				return 2
+			}
+			if !ok {
+				fmt.Printf("missing index on %s(%s), used by %s\n", use.Table, strings.Join(use.Columns, ", "), use.Query)
+				failed = true
+			}
+		}
+	}
+
+	issues, err := database.LintMigrations(migrationsFS, since)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "schemacheck: %v\n", err)
+		return 2
+	}
+	for _, issue := range issues {
+		fmt.Println(issue)
+		failed = true
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
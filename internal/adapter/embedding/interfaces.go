@@ -0,0 +1,11 @@
+package embedding
+
+import "context"
+
+//go:generate mockgen -source=interfaces.go -destination=../../mocks/embedding_mocks.go -package=mocks
+
+type Provider interface {
+	// Embed returns one vector per text, in the order given. All vectors come
+	// from the same model and have the same length.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
@@ -0,0 +1,14 @@
+package geoip
+
+import (
+	"context"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+//go:generate mockgen -source=interfaces.go -destination=../../mocks/geoip_mocks.go -package=mocks
+
+type Resolver interface {
+	// Lookup returns the approximate location of a public IP address.
+	Lookup(ctx context.Context, ip string) (*entity.GeoLocation, error)
+}
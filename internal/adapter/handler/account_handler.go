@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+type AccountHandler struct {
+	accountSvc AccountService
+}
+
+func NewAccountHandler(accountSvc AccountService) *AccountHandler {
+	return &AccountHandler{accountSvc: accountSvc}
+}
+
+// Delete godoc
+//
+//	@Summary		Delete account
+//	@Description	Lock the account, revoke every session and schedule the purge of all notes, photos, attachments, devices and tokens. The account cannot log in again and the purge cannot be undone.
+//	@Tags			me
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		202	{object}	response.AccountDeletionResponse
+//	@Failure		401	{object}	httputil.ErrorResponse
+//	@Failure		404	{object}	httputil.ErrorResponse	"Account already deleted"
+//	@Router			/me [delete]
+func (h *AccountHandler) Delete(c *gin.Context) {
+	deletion, err := h.accountSvc.Delete(c.Request.Context(), httputil.GetUserID(c))
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "account not found")
+			return
+		}
+		httputil.InternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response.AccountDeletionFromEntity(deletion))
+}
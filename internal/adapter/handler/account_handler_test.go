@@ -0,0 +1,64 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+)
+
+func TestAccountHandler_Delete(t *testing.T) {
+	setup := func(t *testing.T) (*mocks.MockAccountService, *gin.Engine, uuid.UUID) {
+		ctrl := gomock.NewController(t)
+		accountSvc := mocks.NewMockAccountService(ctrl)
+		h := handler.NewAccountHandler(accountSvc)
+
+		router := setupRouter()
+		userID := uuid.New()
+		router.DELETE("/me", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Delete(c)
+		})
+		return accountSvc, router, userID
+	}
+
+	t.Run("schedules the deletion", func(t *testing.T) {
+		accountSvc, router, userID := setup(t)
+
+		deletion := entity.NewAccountDeletion(userID)
+		accountSvc.EXPECT().Delete(gomock.Any(), userID).Return(deletion, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/me", nil))
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+
+		var resp response.AccountDeletionResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "scheduled", resp.Status)
+		assert.WithinDuration(t, deletion.RequestedAt, resp.RequestedAt, 0)
+	})
+
+	t.Run("account already deleted", func(t *testing.T) {
+		accountSvc, router, _ := setup(t)
+
+		accountSvc.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil, domain.ErrUserNotFound)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/me", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/request"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/anomaly"
+)
+
+type AlertHandler struct {
+	alertSvc AlertService
+}
+
+func NewAlertHandler(alertSvc AlertService) *AlertHandler {
+	return &AlertHandler{alertSvc: alertSvc}
+}
+
+// List godoc
+//
+//	@Summary		List security alerts
+//	@Description	List impossible travel, sync volume and refresh storm alerts about members of the organizations the caller administers, newest first
+//	@Tags			admin
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			page		query		int		false	"Page number"		default(1)
+//	@Param			per_page	query		int		false	"Items per page"	default(20)
+//	@Param			kind		query		string	false	"Only alerts of this kind"	Enums(impossible_travel, sync_volume, refresh_storm)
+//	@Success		200			{object}	response.SecurityAlertsListResponse
+//	@Failure		400			{object}	httputil.ErrorResponse
+//	@Failure		401			{object}	httputil.ErrorResponse
+//	@Router			/admin/alerts [get]
+func (h *AlertHandler) List(c *gin.Context) {
+	var req request.ListAlertsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	alerts, pageInfo, err := h.alertSvc.ListAlerts(c.Request.Context(), anomaly.ListInput{
+		AdminID: httputil.GetUserID(c),
+		Kind:    req.Kind,
+		Page:    req.Page,
+		PerPage: req.PerPage,
+	})
+	if err != nil {
+		httputil.InternalError(c)
+		return
+	}
+
+	httputil.OK(c, response.SecurityAlertsListResponse{
+		Alerts:     response.SecurityAlertsFromEntities(alerts),
+		Pagination: response.PaginationFromInfo(pageInfo),
+	})
+}
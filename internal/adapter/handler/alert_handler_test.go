@@ -0,0 +1,74 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/anomaly"
+)
+
+func TestAlertHandler_List(t *testing.T) {
+	t.Run("lists alerts of the given kind", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		alertSvc := mocks.NewMockAlertService(ctrl)
+		h := handler.NewAlertHandler(alertSvc)
+
+		router := setupRouter()
+		adminID := uuid.New()
+		router.GET("/admin/alerts", func(c *gin.Context) {
+			c.Set("user_id", adminID)
+			h.List(c)
+		})
+
+		alert := entity.NewSecurityAlert(uuid.New(), nil, entity.AlertRefreshStorm, "fp", "more than 30 token refreshes")
+		alertSvc.EXPECT().ListAlerts(gomock.Any(), anomaly.ListInput{
+			AdminID: adminID,
+			Kind:    entity.AlertRefreshStorm,
+		}).Return([]entity.SecurityAlert{*alert}, pagination.NewInfo(1, 20, 1), nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/alerts?kind=refresh_storm", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp response.SecurityAlertsListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Alerts, 1)
+		assert.Equal(t, alert.ID, resp.Alerts[0].ID)
+		assert.Equal(t, 1, resp.Pagination.TotalItems)
+	})
+
+	t.Run("rejects unknown kind", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		h := handler.NewAlertHandler(mocks.NewMockAlertService(ctrl))
+
+		router := setupRouter()
+		router.GET("/admin/alerts", h.List)
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/alerts?kind=bogus", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
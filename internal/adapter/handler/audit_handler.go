@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/request"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/audit"
+)
+
+type AuditHandler struct {
+	auditSvc AuditService
+}
+
+func NewAuditHandler(auditSvc AuditService) *AuditHandler {
+	return &AuditHandler{auditSvc: auditSvc}
+}
+
+// List godoc
+//
+//	@Summary		List account activity
+//	@Description	List the security-relevant activity on the user's account, newest first: logins and failed logins, logouts, token refreshes, password resets, deleted notes and account changes, each with the device, address and user agent it came from. The target is the login method, the deleted note or the number of sessions revoked.
+//	@Tags			me
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			page		query		int		false	"Page number"		default(1)
+//	@Param			per_page	query		int		false	"Items per page"	default(20)
+//	@Param			action		query		string	false	"Only events of this action"	Enums(account_created, login, login_failed, login_locked, logout, token_refresh, sessions_revoked, password_reset, sso_settings_changed, note_deleted, account_deletion_requested)
+//	@Success		200			{object}	response.AuditEventsListResponse
+//	@Failure		400			{object}	httputil.ErrorResponse
+//	@Failure		401			{object}	httputil.ErrorResponse
+//	@Router			/me/audit [get]
+func (h *AuditHandler) List(c *gin.Context) {
+	var req request.ListAuditEventsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	events, pageInfo, err := h.auditSvc.List(c.Request.Context(), audit.ListInput{
+		UserID:  httputil.GetUserID(c),
+		Action:  req.Action,
+		Page:    req.Page,
+		PerPage: req.PerPage,
+	})
+	if err != nil {
+		httputil.InternalError(c)
+		return
+	}
+
+	httputil.OK(c, response.AuditEventsListResponse{
+		Events:     response.AuditEventsFromEntities(events),
+		Pagination: response.PaginationFromInfo(pageInfo),
+	})
+}
@@ -0,0 +1,79 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/audit"
+)
+
+func TestAuditHandler_List(t *testing.T) {
+	t.Run("lists the user's events of the given action", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		auditSvc := mocks.NewMockAuditService(ctrl)
+		h := handler.NewAuditHandler(auditSvc)
+
+		router := setupRouter()
+		userID := uuid.New()
+		router.GET("/me/audit", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.List(c)
+		})
+
+		event := entity.NewAuditEvent(userID, entity.AuditLoginFailed, "password")
+		event.IP = "203.0.113.7"
+		auditSvc.EXPECT().List(gomock.Any(), audit.ListInput{
+			UserID:  userID,
+			Action:  entity.AuditLoginFailed,
+			Page:    2,
+			PerPage: 10,
+		}).Return([]entity.AuditEvent{*event}, pagination.NewInfo(2, 10, 11), nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/me/audit?action=login_failed&page=2&per_page=10", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp response.AuditEventsListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Events, 1)
+		assert.Equal(t, event.ID, resp.Events[0].ID)
+		assert.Equal(t, "password", resp.Events[0].Target)
+		assert.Equal(t, "203.0.113.7", resp.Events[0].IP)
+		assert.Equal(t, 11, resp.Pagination.TotalItems)
+	})
+
+	t.Run("rejects unknown action", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		h := handler.NewAuditHandler(mocks.NewMockAuditService(ctrl))
+
+		router := setupRouter()
+		router.GET("/me/audit", h.List)
+
+		req := httptest.NewRequest(http.MethodGet, "/me/audit?action=bogus", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -2,7 +2,9 @@ package handler
 
 import (
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
@@ -31,6 +33,7 @@ func NewAuthHandler(authSvc AuthService) *AuthHandler {
 //	@Param			request	body		request.RegisterRequest	true	"Registration data"
 //	@Success		201		{object}	response.UserResponse
 //	@Failure		400		{object}	httputil.ErrorResponse
+//	@Failure		403		{object}	httputil.ErrorResponse	"Organization requires SSO"
 //	@Failure		409		{object}	httputil.ErrorResponse	"Email already exists"
 //	@Router			/auth/register [post]
 func (h *AuthHandler) Register(c *gin.Context) {
@@ -46,11 +49,14 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		Name:     req.Name,
 	})
 	if err != nil {
-		if errors.Is(err, domain.ErrUserAlreadyExists) {
-			httputil.ErrorWithCode(c, http.StatusConflict, "USER_EXISTS", "email already registered")
-			return
+		switch {
+		case errors.Is(err, domain.ErrUserAlreadyExists):
+			httputil.ErrorWithCode(c, http.StatusConflict, httputil.CodeUserExists, "email already registered")
+		case errors.Is(err, domain.ErrSSORequired):
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeSSORequired, "organization requires sso login")
+		default:
+			httputil.InternalError(c)
 		}
-		httputil.InternalError(c)
 		return
 	}
 
@@ -60,14 +66,16 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // Login godoc
 //
 //	@Summary		Login user
-//	@Description	Authenticate user and return tokens
+//	@Description	Authenticate user and return tokens. Repeated wrong passwords for an email from one address, or for any email from one address, lock further attempts for a while with LOGIN_LOCKED and Retry-After.
 //	@Tags			auth
 //	@Accept			json
 //	@Produce		json
 //	@Param			request	body		request.LoginRequest	true	"Login credentials"
 //	@Success		200		{object}	response.LoginResponse
-//	@Failure		400		{object}	httputil.ErrorResponse
+//	@Failure		400		{object}	httputil.ErrorResponse	"Validation error or unsupported platform"
 //	@Failure		401		{object}	httputil.ErrorResponse	"Invalid credentials"
+//	@Failure		403		{object}	httputil.ErrorResponse	"Organization requires SSO"
+//	@Failure		429		{object}	httputil.ErrorResponse	"Too many failed logins"
 //	@Router			/auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req request.LoginRequest
@@ -82,13 +90,79 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		DeviceID:   req.DeviceID,
 		DeviceName: req.DeviceName,
 		Platform:   req.Platform,
+		IP:         c.ClientIP(),
 	})
 	if err != nil {
-		if errors.Is(err, domain.ErrInvalidCredentials) {
-			httputil.ErrorWithCode(c, http.StatusUnauthorized, "INVALID_CREDENTIALS", "invalid email or password")
-			return
+		var locked *auth.LockedError
+		switch {
+		case errors.As(err, &locked):
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+			httputil.ErrorWithCode(c, http.StatusTooManyRequests, httputil.CodeLoginLocked, "too many failed logins; try again later")
+		case errors.Is(err, domain.ErrUnsupportedPlatform):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeUnsupportedPlatform, "platform is not supported")
+		case errors.Is(err, domain.ErrInvalidCredentials):
+			httputil.ErrorWithCode(c, http.StatusUnauthorized, httputil.CodeInvalidCredentials, "invalid email or password")
+		case errors.Is(err, domain.ErrSSORequired):
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeSSORequired, "organization requires sso login")
+		default:
+			httputil.InternalError(c)
+		}
+		return
+	}
+
+	httputil.OK(c, response.LoginResponse{
+		User:         response.UserFromEntity(user),
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresAt:    tokens.ExpiresAt,
+	})
+}
+
+// SocialLogin godoc
+//
+//	@Summary		Sign in with Google or Apple
+//	@Description	Verify an ID token from the provider's sign-in SDK and return tokens. The first sign-in links the provider account to the user with the same verified email, creating the account if needed.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			provider	path		string						true	"Identity provider"	Enums(google, apple)
+//	@Param			request		body		request.SocialLoginRequest	true	"ID token and device"
+//	@Success		200			{object}	response.LoginResponse
+//	@Failure		400			{object}	httputil.ErrorResponse	"Validation error or unsupported platform"
+//	@Failure		401			{object}	httputil.ErrorResponse	"ID token could not be verified"
+//	@Failure		403			{object}	httputil.ErrorResponse	"Organization requires SSO"
+//	@Failure		404			{object}	httputil.ErrorResponse	"Provider not enabled"
+//	@Router			/auth/oauth/{provider} [post]
+func (h *AuthHandler) SocialLogin(c *gin.Context) {
+	var req request.SocialLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	tokens, user, err := h.authSvc.SocialLogin(c.Request.Context(), auth.SocialLoginInput{
+		Provider:   c.Param("provider"),
+		IDToken:    req.IDToken,
+		Nonce:      req.Nonce,
+		Name:       req.Name,
+		DeviceID:   req.DeviceID,
+		DeviceName: req.DeviceName,
+		Platform:   req.Platform,
+		IP:         c.ClientIP(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnsupportedPlatform):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeUnsupportedPlatform, "platform is not supported")
+		case errors.Is(err, domain.ErrUnknownProvider):
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeUnknownProvider, "identity provider not enabled")
+		case errors.Is(err, domain.ErrSocialLoginFailed):
+			httputil.ErrorWithCode(c, http.StatusUnauthorized, httputil.CodeSocialLoginFailed, "could not verify id token")
+		case errors.Is(err, domain.ErrSSORequired):
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeSSORequired, "organization requires sso login")
+		default:
+			httputil.InternalError(c)
 		}
-		httputil.InternalError(c)
 		return
 	}
 
@@ -119,15 +193,15 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 		return
 	}
 
-	tokens, err := h.authSvc.Refresh(c.Request.Context(), req.RefreshToken)
+	tokens, err := h.authSvc.Refresh(c.Request.Context(), req.RefreshToken, c.ClientIP())
 	if err != nil {
 		switch {
 		case errors.Is(err, domain.ErrTokenExpired):
-			httputil.ErrorWithCode(c, http.StatusUnauthorized, "TOKEN_EXPIRED", "refresh token expired")
+			httputil.ErrorWithCode(c, http.StatusUnauthorized, httputil.CodeTokenExpired, "refresh token expired")
 		case errors.Is(err, domain.ErrTokenRevoked):
-			httputil.ErrorWithCode(c, http.StatusUnauthorized, "TOKEN_REVOKED", "refresh token revoked")
+			httputil.ErrorWithCode(c, http.StatusUnauthorized, httputil.CodeTokenRevoked, "refresh token revoked")
 		case errors.Is(err, domain.ErrTokenInvalid):
-			httputil.ErrorWithCode(c, http.StatusUnauthorized, "TOKEN_INVALID", "invalid refresh token")
+			httputil.ErrorWithCode(c, http.StatusUnauthorized, httputil.CodeTokenInvalid, "invalid refresh token")
 		default:
 			httputil.InternalError(c)
 		}
@@ -158,3 +232,242 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	}
 	httputil.NoContent(c)
 }
+
+// ForgotPassword godoc
+//
+//	@Summary		Request a password reset
+//	@Description	Email a single-use link to set a new password. Always succeeds, whether or not the email has an account.
+//	@Tags			auth
+//	@Accept			json
+//	@Param			request	body	request.ForgotPasswordRequest	true	"Account email"
+//	@Success		204		"No content"
+//	@Failure		400		{object}	httputil.ErrorResponse
+//	@Router			/auth/forgot-password [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req request.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	if err := h.authSvc.ForgotPassword(c.Request.Context(), req.Email); err != nil {
+		httputil.InternalError(c)
+		return
+	}
+	httputil.NoContent(c)
+}
+
+// ResetPassword godoc
+//
+//	@Summary		Reset password
+//	@Description	Set a new password with the token from a reset email. Logs the user out of every device.
+//	@Tags			auth
+//	@Accept			json
+//	@Param			request	body	request.ResetPasswordRequest	true	"Reset token and new password"
+//	@Success		204		"No content"
+//	@Failure		400		{object}	httputil.ErrorResponse	"Invalid, expired or used token"
+//	@Failure		403		{object}	httputil.ErrorResponse	"SSO required"
+//	@Router			/auth/reset-password [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req request.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	err := h.authSvc.ResetPassword(c.Request.Context(), auth.ResetPasswordInput{
+		Token:    req.Token,
+		Password: req.Password,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrResetTokenInvalid):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeResetTokenInvalid, "invalid or expired reset token")
+		case errors.Is(err, domain.ErrSSORequired):
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeSSORequired, "this account must log in with SSO")
+		default:
+			httputil.InternalError(c)
+		}
+		return
+	}
+	httputil.NoContent(c)
+}
+
+// Sessions godoc
+//
+//	@Summary		List active sessions
+//	@Description	List the user's devices with the address and approximate location of their last login or token refresh
+//	@Tags			me
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		response.SessionResponse
+//	@Failure		401	{object}	httputil.ErrorResponse
+//	@Router			/me/sessions [get]
+func (h *AuthHandler) Sessions(c *gin.Context) {
+	userID := httputil.GetUserID(c)
+	devices, err := h.authSvc.Sessions(c.Request.Context(), userID)
+	if err != nil {
+		httputil.InternalError(c)
+		return
+	}
+	httputil.OK(c, response.SessionsFromEntities(devices))
+}
+
+// RevokeOtherSessions godoc
+//
+//	@Summary		Log out other devices
+//	@Description	Revoke every session except the one the access token belongs to, e.g. after losing a phone. Access tokens already issued to other devices stay valid until they expire.
+//	@Tags			me
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.RevokeSessionsResponse
+//	@Failure		401	{object}	httputil.ErrorResponse	"Current session is no longer active"
+//	@Router			/me/sessions/revoke-others [post]
+func (h *AuthHandler) RevokeOtherSessions(c *gin.Context) {
+	revoked, err := h.authSvc.RevokeOtherSessions(c.Request.Context(), httputil.GetUserID(c), httputil.GetSessionID(c))
+	if err != nil {
+		if errors.Is(err, domain.ErrTokenInvalid) {
+			httputil.ErrorWithCode(c, http.StatusUnauthorized, httputil.CodeUnauthorized, "session is no longer active")
+			return
+		}
+		httputil.InternalError(c)
+		return
+	}
+	httputil.OK(c, response.RevokeSessionsResponse{Revoked: revoked})
+}
+
+// SSOLogin godoc
+//
+//	@Summary		Start organization SSO login
+//	@Description	Redirect to the organization's OpenID Connect identity provider
+//	@Tags			auth
+//	@Param			org			path	string	true	"Organization slug"
+//	@Param			device_id	query	string	true	"Device ID"
+//	@Param			device_name	query	string	false	"Device name"
+//	@Param			platform	query	string	true	"Platform"	Enums(ios, android, web, cli)
+//	@Success		302			"Redirect to identity provider"
+//	@Failure		400			{object}	httputil.ErrorResponse
+//	@Failure		404			{object}	httputil.ErrorResponse	"Organization not found"
+//	@Router			/auth/sso/{org} [get]
+func (h *AuthHandler) SSOLogin(c *gin.Context) {
+	var req request.SSOLoginRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	authURL, err := h.authSvc.StartSSO(c.Request.Context(), auth.SSOStartInput{
+		OrgSlug:    c.Param("org"),
+		DeviceID:   req.DeviceID,
+		DeviceName: req.DeviceName,
+		Platform:   req.Platform,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnsupportedPlatform):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeUnsupportedPlatform, "platform is not supported")
+		case errors.Is(err, domain.ErrOrgNotFound):
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "organization not found")
+		default:
+			httputil.InternalError(c)
+		}
+		return
+	}
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// SSOCallback godoc
+//
+//	@Summary		Complete organization SSO login
+//	@Description	Handle the identity provider callback, provisioning the user on first login
+//	@Tags			auth
+//	@Produce		json
+//	@Param			org		path		string	true	"Organization slug"
+//	@Param			code	query		string	true	"Authorization code"
+//	@Param			state	query		string	true	"State returned by the identity provider"
+//	@Success		200		{object}	response.LoginResponse
+//	@Failure		400		{object}	httputil.ErrorResponse
+//	@Failure		401		{object}	httputil.ErrorResponse	"SSO verification failed"
+//	@Failure		404		{object}	httputil.ErrorResponse	"Organization not found"
+//	@Router			/auth/sso/{org}/callback [get]
+func (h *AuthHandler) SSOCallback(c *gin.Context) {
+	var req request.SSOCallbackRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	tokens, user, err := h.authSvc.CompleteSSO(c.Request.Context(), auth.SSOCallbackInput{
+		OrgSlug: c.Param("org"),
+		Code:    req.Code,
+		State:   req.State,
+		IP:      c.ClientIP(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrOrgNotFound):
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "organization not found")
+		case errors.Is(err, domain.ErrSSOFailed):
+			httputil.ErrorWithCode(c, http.StatusUnauthorized, httputil.CodeSSOFailed, "sso verification failed")
+		default:
+			httputil.InternalError(c)
+		}
+		return
+	}
+
+	httputil.OK(c, response.LoginResponse{
+		User:         response.UserFromEntity(user),
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresAt:    tokens.ExpiresAt,
+	})
+}
+
+// UpdateSSOSettings godoc
+//
+//	@Summary		Update organization SSO settings
+//	@Description	Set the organization's OpenID Connect issuer, client ID and client secret. Only organization admins may call it. The client secret is stored encrypted and never returned; omit it to keep the current one.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			slug	path		string						true	"Organization slug"
+//	@Param			request	body		request.SSOSettingsRequest	true	"Identity provider settings"
+//	@Success		200		{object}	response.SSOSettingsResponse
+//	@Failure		400		{object}	httputil.ErrorResponse
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Failure		403		{object}	httputil.ErrorResponse	"Not an admin of the organization"
+//	@Failure		404		{object}	httputil.ErrorResponse	"Organization not found"
+//	@Router			/admin/orgs/{slug}/sso [put]
+func (h *AuthHandler) UpdateSSOSettings(c *gin.Context) {
+	var req request.SSOSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	org, err := h.authSvc.UpdateSSOSettings(c.Request.Context(), auth.SSOSettingsInput{
+		UserID:       httputil.GetUserID(c),
+		OrgSlug:      c.Param("slug"),
+		Issuer:       req.Issuer,
+		ClientID:     req.ClientID,
+		ClientSecret: req.ClientSecret,
+		SSOEnforced:  req.SSOEnforced,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidIssuer):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "issuer must be an https URL")
+		case errors.Is(err, domain.ErrOrgNotFound):
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "organization not found")
+		case errors.Is(err, domain.ErrForbidden):
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeForbidden, "only organization admins can change sso settings")
+		default:
+			httputil.InternalError(c)
+		}
+		return
+	}
+
+	httputil.OK(c, response.SSOSettingsFromEntity(org))
+}
@@ -15,6 +15,7 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
 	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
@@ -137,6 +138,7 @@ func TestAuthHandler_Login(t *testing.T) {
 			DeviceID:   "device-123",
 			DeviceName: "iPhone",
 			Platform:   "ios",
+			IP:         "192.0.2.1",
 		}).Return(tokens, user, nil)
 
 		body := `{"email":"test@example.com","password":"password123","device_id":"device-123","device_name":"iPhone","platform":"ios"}`
@@ -176,6 +178,53 @@ func TestAuthHandler_Login(t *testing.T) {
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
 	})
+
+	t.Run("returns too many requests while locked", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		authSvc := mocks.NewMockAuthService(ctrl)
+		h := handler.NewAuthHandler(authSvc)
+
+		router := setupRouter()
+		router.POST("/login", h.Login)
+
+		authSvc.EXPECT().Login(gomock.Any(), gomock.Any()).Return(nil, nil, &auth.LockedError{RetryAfter: 90500 * time.Millisecond})
+
+		body := `{"email":"test@example.com","password":"wrong","device_id":"device-123","platform":"ios"}`
+		req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "91", w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), "LOGIN_LOCKED")
+	})
+
+	t.Run("returns bad request for unsupported platform", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		authSvc := mocks.NewMockAuthService(ctrl)
+		h := handler.NewAuthHandler(authSvc)
+
+		router := setupRouter()
+		router.POST("/login", h.Login)
+
+		authSvc.EXPECT().Login(gomock.Any(), gomock.Any()).Return(nil, nil, domain.ErrUnsupportedPlatform)
+
+		body := `{"email":"test@example.com","password":"password123","device_id":"device-123","platform":"windows"}`
+		req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "UNSUPPORTED_PLATFORM")
+	})
 }
 
 func TestAuthHandler_Refresh(t *testing.T) {
@@ -195,7 +244,7 @@ func TestAuthHandler_Refresh(t *testing.T) {
 			ExpiresAt:    time.Now().Add(15 * time.Minute),
 		}
 
-		authSvc.EXPECT().Refresh(gomock.Any(), "valid-refresh-token").Return(tokens, nil)
+		authSvc.EXPECT().Refresh(gomock.Any(), "valid-refresh-token", gomock.Any()).Return(tokens, nil)
 
 		body := `{"refresh_token":"valid-refresh-token"}`
 		req := httptest.NewRequest(http.MethodPost, "/refresh", bytes.NewBufferString(body))
@@ -223,7 +272,7 @@ func TestAuthHandler_Refresh(t *testing.T) {
 		router := setupRouter()
 		router.POST("/refresh", h.Refresh)
 
-		authSvc.EXPECT().Refresh(gomock.Any(), "expired-token").Return(nil, domain.ErrTokenExpired)
+		authSvc.EXPECT().Refresh(gomock.Any(), "expired-token", gomock.Any()).Return(nil, domain.ErrTokenExpired)
 
 		body := `{"refresh_token":"expired-token"}`
 		req := httptest.NewRequest(http.MethodPost, "/refresh", bytes.NewBufferString(body))
@@ -245,7 +294,7 @@ func TestAuthHandler_Refresh(t *testing.T) {
 		router := setupRouter()
 		router.POST("/refresh", h.Refresh)
 
-		authSvc.EXPECT().Refresh(gomock.Any(), "revoked-token").Return(nil, domain.ErrTokenRevoked)
+		authSvc.EXPECT().Refresh(gomock.Any(), "revoked-token", gomock.Any()).Return(nil, domain.ErrTokenRevoked)
 
 		body := `{"refresh_token":"revoked-token"}`
 		req := httptest.NewRequest(http.MethodPost, "/refresh", bytes.NewBufferString(body))
@@ -258,6 +307,77 @@ func TestAuthHandler_Refresh(t *testing.T) {
 	})
 }
 
+func TestAuthHandler_ForgotPassword(t *testing.T) {
+	t.Run("accepts any email", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		authSvc := mocks.NewMockAuthService(ctrl)
+		h := handler.NewAuthHandler(authSvc)
+
+		router := setupRouter()
+		router.POST("/forgot-password", h.ForgotPassword)
+
+		authSvc.EXPECT().ForgotPassword(gomock.Any(), "ana@example.com").Return(nil)
+
+		body := `{"email":"ana@example.com"}`
+		req := httptest.NewRequest(http.MethodPost, "/forgot-password", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+}
+
+func TestAuthHandler_ResetPassword(t *testing.T) {
+	t.Run("resets password", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		authSvc := mocks.NewMockAuthService(ctrl)
+		h := handler.NewAuthHandler(authSvc)
+
+		router := setupRouter()
+		router.POST("/reset-password", h.ResetPassword)
+
+		authSvc.EXPECT().ResetPassword(gomock.Any(), auth.ResetPasswordInput{Token: "token", Password: "new-password"}).Return(nil)
+
+		body := `{"token":"token","password":"new-password"}`
+		req := httptest.NewRequest(http.MethodPost, "/reset-password", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("returns bad request for invalid token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		authSvc := mocks.NewMockAuthService(ctrl)
+		h := handler.NewAuthHandler(authSvc)
+
+		router := setupRouter()
+		router.POST("/reset-password", h.ResetPassword)
+
+		authSvc.EXPECT().ResetPassword(gomock.Any(), gomock.Any()).Return(domain.ErrResetTokenInvalid)
+
+		body := `{"token":"used","password":"new-password"}`
+		req := httptest.NewRequest(http.MethodPost, "/reset-password", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "RESET_TOKEN_INVALID")
+	})
+}
+
 func TestAuthHandler_Logout(t *testing.T) {
 	t.Run("logs out successfully", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
@@ -283,3 +403,300 @@ func TestAuthHandler_Logout(t *testing.T) {
 		assert.Equal(t, http.StatusNoContent, w.Code)
 	})
 }
+
+func TestAuthHandler_SSOLogin(t *testing.T) {
+	t.Run("redirects to identity provider", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		authSvc := mocks.NewMockAuthService(ctrl)
+		h := handler.NewAuthHandler(authSvc)
+
+		router := setupRouter()
+		router.GET("/sso/:org", h.SSOLogin)
+
+		authSvc.EXPECT().StartSSO(gomock.Any(), auth.SSOStartInput{
+			OrgSlug:  "acme",
+			DeviceID: "device-123",
+			Platform: "ios",
+		}).Return("https://idp.acme.org/authorize", nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/sso/acme?device_id=device-123&platform=ios", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusFound, w.Code)
+		assert.Equal(t, "https://idp.acme.org/authorize", w.Header().Get("Location"))
+	})
+
+	t.Run("returns not found for unknown organization", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		authSvc := mocks.NewMockAuthService(ctrl)
+		h := handler.NewAuthHandler(authSvc)
+
+		router := setupRouter()
+		router.GET("/sso/:org", h.SSOLogin)
+
+		authSvc.EXPECT().StartSSO(gomock.Any(), gomock.Any()).Return("", domain.ErrOrgNotFound)
+
+		req := httptest.NewRequest(http.MethodGet, "/sso/missing?device_id=device-123&platform=ios", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestAuthHandler_SSOCallback(t *testing.T) {
+	t.Run("returns unauthorized when verification fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		authSvc := mocks.NewMockAuthService(ctrl)
+		h := handler.NewAuthHandler(authSvc)
+
+		router := setupRouter()
+		router.GET("/sso/:org/callback", h.SSOCallback)
+
+		authSvc.EXPECT().CompleteSSO(gomock.Any(), auth.SSOCallbackInput{
+			OrgSlug: "acme",
+			Code:    "code-1",
+			State:   "state-1",
+			IP:      "192.0.2.1",
+		}).Return(nil, nil, domain.ErrSSOFailed)
+
+		req := httptest.NewRequest(http.MethodGet, "/sso/acme/callback?code=code-1&state=state-1", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestAuthHandler_Sessions(t *testing.T) {
+	t.Run("lists devices with last access", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		authSvc := mocks.NewMockAuthService(ctrl)
+		h := handler.NewAuthHandler(authSvc)
+
+		router := setupRouter()
+		userID := uuid.New()
+		router.GET("/me/sessions", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Sessions(c)
+		})
+
+		authSvc.EXPECT().Sessions(gomock.Any(), userID).Return([]entity.Device{
+			{
+				DeviceID: "device-123",
+				Platform: "ios",
+				LastAccess: &entity.DeviceAccess{
+					IP:      "203.0.113.7",
+					City:    "Lisbon",
+					Country: "PT",
+					At:      time.Now(),
+				},
+			},
+			{DeviceID: "device-456", Platform: "web"},
+		}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/me/sessions", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp []response.SessionResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp, 2)
+		require.NotNil(t, resp[0].LastAccess)
+		assert.Equal(t, "Lisbon", resp[0].LastAccess.City)
+		assert.Equal(t, "PT", resp[0].LastAccess.Country)
+		assert.Nil(t, resp[1].LastAccess)
+	})
+}
+
+func TestAuthHandler_RevokeOtherSessions(t *testing.T) {
+	t.Run("revokes sessions other than the current one", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		authSvc := mocks.NewMockAuthService(ctrl)
+		h := handler.NewAuthHandler(authSvc)
+
+		router := setupRouter()
+		userID := uuid.New()
+		sessionID := uuid.New()
+		router.POST("/me/sessions/revoke-others", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			c.Set("session_id", sessionID)
+			h.RevokeOtherSessions(c)
+		})
+
+		authSvc.EXPECT().RevokeOtherSessions(gomock.Any(), userID, sessionID).Return(2, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/me/sessions/revoke-others", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp response.RevokeSessionsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 2, resp.Revoked)
+	})
+
+	t.Run("current session no longer active", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		authSvc := mocks.NewMockAuthService(ctrl)
+		h := handler.NewAuthHandler(authSvc)
+
+		router := setupRouter()
+		router.POST("/me/sessions/revoke-others", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.RevokeOtherSessions(c)
+		})
+
+		authSvc.EXPECT().RevokeOtherSessions(gomock.Any(), gomock.Any(), uuid.Nil).Return(0, domain.ErrTokenInvalid)
+
+		req := httptest.NewRequest(http.MethodPost, "/me/sessions/revoke-others", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestAuthHandler_UpdateSSOSettings(t *testing.T) {
+	body := `{"issuer":"https://login.acme.org","client_id":"client","client_secret":"secret","sso_enforced":true}`
+
+	t.Run("returns the settings without the secret", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		authSvc := mocks.NewMockAuthService(ctrl)
+		h := handler.NewAuthHandler(authSvc)
+
+		router := setupRouter()
+		userID := uuid.New()
+		router.PUT("/admin/orgs/:slug/sso", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.UpdateSSOSettings(c)
+		})
+
+		authSvc.EXPECT().UpdateSSOSettings(gomock.Any(), auth.SSOSettingsInput{
+			UserID:       userID,
+			OrgSlug:      "acme",
+			Issuer:       "https://login.acme.org",
+			ClientID:     "client",
+			ClientSecret: "secret",
+			SSOEnforced:  true,
+		}).Return(&entity.Organization{
+			Slug:             "acme",
+			OIDCIssuer:       "https://login.acme.org",
+			OIDCClientID:     "client",
+			OIDCClientSecret: "secret",
+			SSOEnforced:      true,
+		}, nil)
+
+		req := httptest.NewRequest(http.MethodPut, "/admin/orgs/acme/sso", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "secret")
+	})
+
+	t.Run("forbidden for non-admins", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		authSvc := mocks.NewMockAuthService(ctrl)
+		h := handler.NewAuthHandler(authSvc)
+
+		router := setupRouter()
+		router.PUT("/admin/orgs/:slug/sso", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.UpdateSSOSettings(c)
+		})
+
+		authSvc.EXPECT().UpdateSSOSettings(gomock.Any(), gomock.Any()).Return(nil, domain.ErrForbidden)
+
+		req := httptest.NewRequest(http.MethodPut, "/admin/orgs/acme/sso", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestAuthHandler_SocialLogin(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{"signs in", nil, http.StatusOK, ""},
+		{"unknown provider", domain.ErrUnknownProvider, http.StatusNotFound, "UNKNOWN_PROVIDER"},
+		{"invalid token", domain.ErrSocialLoginFailed, http.StatusUnauthorized, "SOCIAL_LOGIN_FAILED"},
+		{"sso enforced", domain.ErrSSORequired, http.StatusForbidden, "SSO_REQUIRED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			authSvc := mocks.NewMockAuthService(ctrl)
+			h := handler.NewAuthHandler(authSvc)
+
+			router := setupRouter()
+			router.POST("/oauth/:provider", h.SocialLogin)
+
+			var tokens *auth.TokenPair
+			var user *entity.User
+			if tt.err == nil {
+				tokens = &auth.TokenPair{AccessToken: "access-token", RefreshToken: "refresh-token"}
+				user = &entity.User{ID: uuid.New(), Email: "ranger@example.com"}
+			}
+			authSvc.EXPECT().SocialLogin(gomock.Any(), auth.SocialLoginInput{
+				Provider: "google",
+				IDToken:  "id-token",
+				DeviceID: "device-123",
+				Platform: "android",
+				IP:       "192.0.2.1",
+			}).Return(tokens, user, tt.err)
+
+			body := `{"id_token":"id-token","device_id":"device-123","platform":"android"}`
+			req := httptest.NewRequest(http.MethodPost, "/oauth/google", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantCode != "" {
+				assert.Contains(t, w.Body.String(), tt.wantCode)
+			} else {
+				assert.Contains(t, w.Body.String(), "access-token")
+			}
+		})
+	}
+}
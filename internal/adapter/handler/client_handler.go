@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/request"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+const defaultAdoptionDays = 30
+
+// ClientHandler serves operators the adoption of each app version, to decide
+// when old versions can stop being supported. Like the jobs dashboard it is
+// not part of the public API.
+type ClientHandler struct {
+	clientSvc ClientService
+}
+
+func NewClientHandler(clientSvc ClientService) *ClientHandler {
+	return &ClientHandler{clientSvc: clientSvc}
+}
+
+// Adoption lists the app versions seen over the last days days.
+func (h *ClientHandler) Adoption(c *gin.Context) {
+	var req request.ClientAdoptionRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+	if req.Days == 0 {
+		req.Days = defaultAdoptionDays
+	}
+
+	adoption, err := h.clientSvc.Adoption(c.Request.Context(), req.Days)
+	if err != nil {
+		httputil.InternalError(c)
+		return
+	}
+
+	to := entity.UsageDay(time.Now())
+	httputil.OK(c, response.ClientAdoptionFromEntities(to.AddDate(0, 0, -(req.Days-1)), to, adoption))
+}
@@ -0,0 +1,76 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+)
+
+func TestClientHandler_Adoption(t *testing.T) {
+	t.Run("lists versions over the default period", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		clientSvc := mocks.NewMockClientService(ctrl)
+		h := handler.NewClientHandler(clientSvc)
+
+		router := setupRouter()
+		router.GET("/admin/clients", h.Adoption)
+
+		day := time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC)
+		clientSvc.EXPECT().Adoption(gomock.Any(), 30).Return([]entity.ClientAdoption{{
+			Platform:   entity.PlatformIOS,
+			AppVersion: "2.3.1",
+			Requests:   120,
+			Share:      0.8,
+			Devices:    9,
+			FirstSeen:  day,
+			LastSeen:   day.AddDate(0, 0, 1),
+			OS:         map[string]int64{"iOS 17": 120},
+		}}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/clients", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp response.ClientAdoptionListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Versions, 1)
+		assert.Equal(t, "2.3.1", resp.Versions[0].AppVersion)
+		assert.Equal(t, "2026-05-04", resp.Versions[0].FirstSeen)
+		assert.Equal(t, "2026-05-05", resp.Versions[0].LastSeen)
+		assert.Equal(t, int64(120), resp.Versions[0].OS["iOS 17"])
+		assert.Equal(t, time.Now().UTC().Format("2006-01-02"), resp.To)
+	})
+
+	t.Run("rejects an out of range period", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		h := handler.NewClientHandler(mocks.NewMockClientService(ctrl))
+
+		router := setupRouter()
+		router.GET("/admin/clients", h.Adoption)
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/clients?days=400", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+type DemoHandler struct {
+	demoSvc DemoService
+}
+
+func NewDemoHandler(demoSvc DemoService) *DemoHandler {
+	return &DemoHandler{demoSvc: demoSvc}
+}
+
+// Credentials godoc
+//
+//	@Summary		Get demo credentials
+//	@Description	Login of the read-only demo account, shown on the login screen. Only registered when demo mode is enabled
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	{object}	response.DemoCredentialsResponse
+//	@Router			/auth/demo [get]
+func (h *DemoHandler) Credentials(c *gin.Context) {
+	httputil.OK(c, response.DemoCredentialsFrom(h.demoSvc.Credentials()))
+}
@@ -0,0 +1,44 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/demo"
+)
+
+func TestDemoHandler_Credentials(t *testing.T) {
+	t.Run("returns demo credentials", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		demoSvc := mocks.NewMockDemoService(ctrl)
+		h := handler.NewDemoHandler(demoSvc)
+
+		router := setupRouter()
+		router.GET("/auth/demo", h.Credentials)
+
+		demoSvc.EXPECT().Credentials().Return(demo.Credentials{Email: "demo@fieldnotes.app", Password: "demo1234"})
+
+		req := httptest.NewRequest(http.MethodGet, "/auth/demo", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp response.DemoCredentialsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "demo@fieldnotes.app", resp.Email)
+		assert.Equal(t, "demo1234", resp.Password)
+	})
+}
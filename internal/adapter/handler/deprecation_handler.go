@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/request"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+const defaultDeprecationDays = 30
+
+// DeprecationHandler serves operators how much each deprecated endpoint and
+// field is still used, and by which app versions, to decide when it can be
+// removed. Like the jobs dashboard it is not part of the public API.
+type DeprecationHandler struct {
+	deprecationSvc DeprecationService
+}
+
+func NewDeprecationHandler(deprecationSvc DeprecationService) *DeprecationHandler {
+	return &DeprecationHandler{deprecationSvc: deprecationSvc}
+}
+
+// Report lists the deprecations with their use over the last days days.
+func (h *DeprecationHandler) Report(c *gin.Context) {
+	var req request.DeprecationReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+	if req.Days == 0 {
+		req.Days = defaultDeprecationDays
+	}
+
+	reports, err := h.deprecationSvc.Report(c.Request.Context(), req.Days)
+	if err != nil {
+		httputil.InternalError(c)
+		return
+	}
+
+	to := entity.UsageDay(time.Now())
+	httputil.OK(c, response.DeprecationReportsFromEntities(to.AddDate(0, 0, -(req.Days-1)), to, reports))
+}
+
+// Metrics exposes today's uses of each deprecation per app version as
+// Prometheus gauges, as counted up to the last flush.
+func (h *DeprecationHandler) Metrics(c *gin.Context) {
+	reports, err := h.deprecationSvc.Report(c.Request.Context(), 1)
+	if err != nil {
+		httputil.InternalError(c)
+		return
+	}
+
+	const name = "fieldnotes_deprecated_requests"
+	var b strings.Builder
+	fmt.Fprintf(&b, "# HELP %s Requests today (UTC) that used a deprecated endpoint or field.\n# TYPE %s gauge\n", name, name)
+	for _, r := range reports {
+		for _, client := range r.Clients {
+			fmt.Fprintf(&b, "%s{deprecation=%q,platform=%q,app_version=%q} %d\n",
+				name, r.Name(), client.Platform, client.AppVersion, client.Requests)
+		}
+	}
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(b.String()))
+}
@@ -0,0 +1,129 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+)
+
+func deprecationReport() entity.DeprecationReport {
+	return entity.DeprecationReport{
+		Deprecation: entity.Deprecation{
+			Method: http.MethodPost,
+			Route:  "/api/v1/sync",
+			Field:  "notes[].tags",
+			Since:  time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+			Sunset: time.Date(2027, 5, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Requests: 50,
+		LastSeen: time.Date(2026, 11, 20, 0, 0, 0, 0, time.UTC),
+		Clients: []entity.DeprecatedClient{
+			{Platform: entity.PlatformIOS, AppVersion: "2.2.0", Requests: 50, LastSeen: time.Date(2026, 11, 20, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+}
+
+func TestDeprecationHandler_Report(t *testing.T) {
+	t.Run("lists the deprecations with their use", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		deprecationSvc := mocks.NewMockDeprecationService(ctrl)
+		h := handler.NewDeprecationHandler(deprecationSvc)
+
+		router := setupRouter()
+		router.GET("/admin/deprecations", h.Report)
+
+		deprecationSvc.EXPECT().Report(gomock.Any(), 30).Return([]entity.DeprecationReport{deprecationReport()}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/deprecations", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp response.DeprecationReportListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Deprecations, 1)
+		d := resp.Deprecations[0]
+		assert.Equal(t, "POST /api/v1/sync notes[].tags", d.Name)
+		assert.Equal(t, "2026-11-01", d.Since)
+		assert.Equal(t, "2027-05-01", d.Sunset)
+		assert.Equal(t, "2026-11-20", d.LastSeen)
+		assert.Equal(t, []response.DeprecatedClientResponse{
+			{Platform: entity.PlatformIOS, AppVersion: "2.2.0", Requests: 50, LastSeen: "2026-11-20"},
+		}, d.Clients)
+	})
+
+	t.Run("rejects out of range days", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		h := handler.NewDeprecationHandler(mocks.NewMockDeprecationService(ctrl))
+
+		router := setupRouter()
+		router.GET("/admin/deprecations", h.Report)
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/deprecations?days=400", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("returns 500 when the report fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		deprecationSvc := mocks.NewMockDeprecationService(ctrl)
+		h := handler.NewDeprecationHandler(deprecationSvc)
+
+		router := setupRouter()
+		router.GET("/admin/deprecations", h.Report)
+
+		deprecationSvc.EXPECT().Report(gomock.Any(), 7).Return(nil, errors.New("db error"))
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/deprecations?days=7", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestDeprecationHandler_Metrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deprecationSvc := mocks.NewMockDeprecationService(ctrl)
+	h := handler.NewDeprecationHandler(deprecationSvc)
+
+	router := setupRouter()
+	router.GET("/admin/deprecations/metrics", h.Metrics)
+
+	deprecationSvc.EXPECT().Report(gomock.Any(), 1).Return([]entity.DeprecationReport{deprecationReport()}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/deprecations/metrics", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/plain; version=0.0.4")
+	assert.Contains(t, w.Body.String(),
+		`fieldnotes_deprecated_requests{deprecation="POST /api/v1/sync notes[].tags",platform="ios",app_version="2.2.0"} 50`+"\n")
+}
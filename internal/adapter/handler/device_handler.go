@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/request"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/apperror"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/sync"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/usage"
+)
+
+type DeviceHandler struct {
+	usageSvc UsageService
+	syncSvc  SyncService
+}
+
+func NewDeviceHandler(usageSvc UsageService, syncSvc SyncService) *DeviceHandler {
+	return &DeviceHandler{usageSvc: usageSvc, syncSvc: syncSvc}
+}
+
+// Usage godoc
+//
+//	@Summary		Get device data usage
+//	@Description	Get bytes uploaded and downloaded per day by one of the current user's devices
+//	@Tags			devices
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			id		path		string	true	"Client device ID"
+//	@Param			from	query		string	false	"First day (YYYY-MM-DD), defaults to 30 days ago"
+//	@Param			to		query		string	false	"Last day (YYYY-MM-DD), defaults to today"
+//	@Success		200		{object}	response.DeviceUsageResponse
+//	@Failure		400		{object}	httputil.ErrorResponse
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Failure		404		{object}	httputil.ErrorResponse
+//	@Router			/me/devices/{id}/usage [get]
+func (h *DeviceHandler) Usage(c *gin.Context) {
+	var req request.DeviceUsageRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	input := usage.DeviceUsageInput{
+		UserID:   httputil.GetUserID(c),
+		DeviceID: c.Param("id"),
+	}
+	if req.From != "" {
+		from, _ := time.Parse(time.DateOnly, req.From)
+		input.From = &from
+	}
+	if req.To != "" {
+		to, _ := time.Parse(time.DateOnly, req.To)
+		input.To = &to
+	}
+	if input.From != nil && input.To != nil && input.From.After(*input.To) {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidRange, "from must not be after to")
+		return
+	}
+
+	days, err := h.usageSvc.GetDeviceUsage(c.Request.Context(), input)
+	if err != nil {
+		if errors.Is(err, domain.ErrDeviceNotFound) {
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "device not found")
+			return
+		}
+		httputil.InternalError(c)
+		return
+	}
+
+	httputil.OK(c, response.DeviceUsageFromEntities(input.DeviceID, days))
+}
+
+// SetAuthor godoc
+//
+//	@Summary		Set the device's active author
+//	@Description	Record who is using a shared device; notes it creates are stamped with the name until it is changed or cleared
+//	@Tags			devices
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Client device ID"
+//	@Param			request	body		request.DeviceAuthorRequest	true	"Name or initials"
+//	@Success		200		{object}	response.DeviceAuthorResponse
+//	@Failure		400		{object}	httputil.ErrorResponse
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Failure		404		{object}	httputil.ErrorResponse
+//	@Router			/me/devices/{id}/author [post]
+func (h *DeviceHandler) SetAuthor(c *gin.Context) {
+	var req request.DeviceAuthorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	device, err := h.syncSvc.SetActiveAuthor(c.Request.Context(), sync.AuthorInput{
+		UserID:   httputil.GetUserID(c),
+		DeviceID: c.Param("id"),
+		Name:     req.Name,
+	})
+	if err != nil {
+		writeDeviceAuthorError(c, err)
+		return
+	}
+
+	httputil.OK(c, response.DeviceAuthorFromEntity(device))
+}
+
+// ClearAuthor godoc
+//
+//	@Summary		Clear the device's active author
+//	@Description	Check out whoever was using a shared device; new notes carry no author
+//	@Tags			devices
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			id	path		string	true	"Client device ID"
+//	@Success		200	{object}	response.DeviceAuthorResponse
+//	@Failure		401	{object}	httputil.ErrorResponse
+//	@Failure		404	{object}	httputil.ErrorResponse
+//	@Router			/me/devices/{id}/author [delete]
+func (h *DeviceHandler) ClearAuthor(c *gin.Context) {
+	device, err := h.syncSvc.ClearActiveAuthor(c.Request.Context(), httputil.GetUserID(c), c.Param("id"))
+	if err != nil {
+		writeDeviceAuthorError(c, err)
+		return
+	}
+
+	httputil.OK(c, response.DeviceAuthorFromEntity(device))
+}
+
+// SetPushToken godoc
+//
+//	@Summary		Register the device's push token
+//	@Description	Store the APNs (ios) or FCM (android) token of the device's app. The device then gets a silent push whenever another of the user's devices syncs changes, so it pulls them without waiting for its next poll. A token registered by another device moves to this one.
+//	@Tags			devices
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Param			id		path	string							true	"Client device ID"
+//	@Param			request	body	request.DevicePushTokenRequest	true	"Push token"
+//	@Success		204
+//	@Failure		400	{object}	httputil.ErrorResponse
+//	@Failure		401	{object}	httputil.ErrorResponse
+//	@Failure		404	{object}	httputil.ErrorResponse
+//	@Router			/me/devices/{id}/push-token [put]
+func (h *DeviceHandler) SetPushToken(c *gin.Context) {
+	var req request.DevicePushTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	err := h.syncSvc.SetPushToken(c.Request.Context(), sync.PushTokenInput{
+		UserID:   httputil.GetUserID(c),
+		DeviceID: c.Param("id"),
+		Token:    req.Token,
+	})
+	if err != nil {
+		writeDevicePushError(c, err)
+		return
+	}
+
+	httputil.NoContent(c)
+}
+
+// ClearPushToken godoc
+//
+//	@Summary		Remove the device's push token
+//	@Description	Stop silent pushes to the device, as when the user signs out of the app
+//	@Tags			devices
+//	@Security		BearerAuth
+//	@Param			id	path	string	true	"Client device ID"
+//	@Success		204
+//	@Failure		401	{object}	httputil.ErrorResponse
+//	@Failure		404	{object}	httputil.ErrorResponse
+//	@Router			/me/devices/{id}/push-token [delete]
+func (h *DeviceHandler) ClearPushToken(c *gin.Context) {
+	if err := h.syncSvc.ClearPushToken(c.Request.Context(), httputil.GetUserID(c), c.Param("id")); err != nil {
+		writeDevicePushError(c, err)
+		return
+	}
+
+	httputil.NoContent(c)
+}
+
+func writeDevicePushError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrDeviceNotFound):
+		httputil.Fail(c, apperror.New(http.StatusNotFound, httputil.CodeNotFound, "device not found"))
+	case errors.Is(err, domain.ErrPushNotSupported):
+		httputil.Fail(c, apperror.New(http.StatusBadRequest, httputil.CodeUnsupportedPlatform, "push tokens are only accepted from ios and android devices"))
+	default:
+		httputil.InternalError(c)
+	}
+}
+
+func writeDeviceAuthorError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrDeviceNotFound):
+		httputil.Fail(c, apperror.New(http.StatusNotFound, httputil.CodeNotFound, "device not found"))
+	case errors.Is(err, domain.ErrInvalidDeviceAuthor):
+		httputil.Fail(c, apperror.New(http.StatusBadRequest, httputil.CodeValidationError, "name must not be blank"))
+	default:
+		httputil.InternalError(c)
+	}
+}
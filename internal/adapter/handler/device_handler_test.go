@@ -0,0 +1,225 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/sync"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/usage"
+)
+
+func TestDeviceHandler_Usage(t *testing.T) {
+	t.Run("returns daily usage with totals", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		usageSvc := mocks.NewMockUsageService(ctrl)
+		h := handler.NewDeviceHandler(usageSvc, nil)
+
+		router := setupRouter()
+		userID := uuid.New()
+		router.GET("/me/devices/:id/usage", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Usage(c)
+		})
+
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+		usageSvc.EXPECT().GetDeviceUsage(gomock.Any(), usage.DeviceUsageInput{
+			UserID:   userID,
+			DeviceID: "device-123",
+			From:     &from,
+			To:       &to,
+		}).Return([]entity.DeviceUsage{
+			{Day: from, BytesIn: 100, BytesOut: 1000},
+			{Day: to, BytesIn: 50, BytesOut: 500},
+		}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/me/devices/device-123/usage?from=2024-01-01&to=2024-01-02", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp response.DeviceUsageResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "device-123", resp.DeviceID)
+		assert.Len(t, resp.Days, 2)
+		assert.Equal(t, "2024-01-01", resp.Days[0].Date)
+		assert.Equal(t, int64(150), resp.TotalBytesIn)
+		assert.Equal(t, int64(1500), resp.TotalBytesOut)
+	})
+
+	t.Run("returns not found for unknown device", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		usageSvc := mocks.NewMockUsageService(ctrl)
+		h := handler.NewDeviceHandler(usageSvc, nil)
+
+		router := setupRouter()
+		router.GET("/me/devices/:id/usage", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.Usage(c)
+		})
+
+		usageSvc.EXPECT().GetDeviceUsage(gomock.Any(), gomock.Any()).Return(nil, domain.ErrDeviceNotFound)
+
+		req := httptest.NewRequest(http.MethodGet, "/me/devices/unknown/usage", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("rejects inverted range", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		h := handler.NewDeviceHandler(mocks.NewMockUsageService(ctrl), nil)
+
+		router := setupRouter()
+		router.GET("/me/devices/:id/usage", h.Usage)
+
+		req := httptest.NewRequest(http.MethodGet, "/me/devices/device-123/usage?from=2024-02-01&to=2024-01-01", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestDeviceHandler_SetAuthor(t *testing.T) {
+	setup := func(t *testing.T) (*mocks.MockSyncService, *gin.Engine, uuid.UUID) {
+		ctrl := gomock.NewController(t)
+		syncSvc := mocks.NewMockSyncService(ctrl)
+		h := handler.NewDeviceHandler(nil, syncSvc)
+
+		router := setupRouter()
+		userID := uuid.New()
+		auth := func(c *gin.Context) { c.Set("user_id", userID) }
+		router.POST("/me/devices/:id/author", auth, h.SetAuthor)
+		router.DELETE("/me/devices/:id/author", auth, h.ClearAuthor)
+		return syncSvc, router, userID
+	}
+
+	t.Run("sets the active author", func(t *testing.T) {
+		syncSvc, router, userID := setup(t)
+		since := time.Date(2024, 5, 2, 8, 0, 0, 0, time.UTC)
+		syncSvc.EXPECT().SetActiveAuthor(gomock.Any(), sync.AuthorInput{
+			UserID: userID, DeviceID: "kit-3", Name: "AS",
+		}).Return(&entity.Device{DeviceID: "kit-3", ActiveAuthor: "AS", ActiveAuthorSince: &since}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/me/devices/kit-3/author", bytes.NewBufferString(`{"name":"AS"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp response.DeviceAuthorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "AS", resp.Name)
+		assert.Equal(t, since, *resp.Since)
+	})
+
+	t.Run("requires a name", func(t *testing.T) {
+		_, router, _ := setup(t)
+
+		req := httptest.NewRequest(http.MethodPost, "/me/devices/kit-3/author", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("clears the active author of an unknown device", func(t *testing.T) {
+		syncSvc, router, userID := setup(t)
+		syncSvc.EXPECT().ClearActiveAuthor(gomock.Any(), userID, "unknown").Return(nil, domain.ErrDeviceNotFound)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/me/devices/unknown/author", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestDeviceHandler_PushToken(t *testing.T) {
+	setup := func(t *testing.T) (*mocks.MockSyncService, *gin.Engine, uuid.UUID) {
+		ctrl := gomock.NewController(t)
+		syncSvc := mocks.NewMockSyncService(ctrl)
+		h := handler.NewDeviceHandler(nil, syncSvc)
+
+		router := setupRouter()
+		userID := uuid.New()
+		auth := func(c *gin.Context) { c.Set("user_id", userID) }
+		router.PUT("/me/devices/:id/push-token", auth, h.SetPushToken)
+		router.DELETE("/me/devices/:id/push-token", auth, h.ClearPushToken)
+		return syncSvc, router, userID
+	}
+
+	t.Run("registers the token", func(t *testing.T) {
+		syncSvc, router, userID := setup(t)
+		syncSvc.EXPECT().SetPushToken(gomock.Any(), sync.PushTokenInput{
+			UserID: userID, DeviceID: "phone", Token: "apns-token",
+		}).Return(nil)
+
+		req := httptest.NewRequest(http.MethodPut, "/me/devices/phone/push-token", bytes.NewBufferString(`{"token":"apns-token"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("rejects devices that cannot receive pushes", func(t *testing.T) {
+		syncSvc, router, _ := setup(t)
+		syncSvc.EXPECT().SetPushToken(gomock.Any(), gomock.Any()).Return(domain.ErrPushNotSupported)
+
+		req := httptest.NewRequest(http.MethodPut, "/me/devices/laptop/push-token", bytes.NewBufferString(`{"token":"token"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("requires a token", func(t *testing.T) {
+		_, router, _ := setup(t)
+
+		req := httptest.NewRequest(http.MethodPut, "/me/devices/phone/push-token", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("clears the token of an unknown device", func(t *testing.T) {
+		syncSvc, router, userID := setup(t)
+		syncSvc.EXPECT().ClearPushToken(gomock.Any(), userID, "unknown").Return(domain.ErrDeviceNotFound)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/me/devices/unknown/push-token", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
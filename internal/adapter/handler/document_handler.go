@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/request"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/apperror"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/realtime"
+)
+
+type DocumentHandler struct {
+	documents DocumentService
+}
+
+func NewDocumentHandler(documents DocumentService) *DocumentHandler {
+	return &DocumentHandler{documents: documents}
+}
+
+// Enable godoc
+//
+//	@Summary		Enable collaborative editing
+//	@Description	Make the note's content a CRDT document (an RGA of characters) that the owner and editors change with updates instead of saving the whole content, so concurrent edits merge rather than the last save winning. The document starts from the current content, inserted by the replica "server" with seq 1 onwards. Once enabled, PUT /notes/{id} and sync keep the server's content, and merging the note is refused. Owner only; enabling it again returns the current document.
+//	@Tags			notes
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			id	path		string	true	"Note ID"	format(uuid)
+//	@Success		200	{object}	response.NoteDocumentResponse
+//	@Failure		400	{object}	httputil.ErrorResponse
+//	@Failure		401	{object}	httputil.ErrorResponse
+//	@Failure		403	{object}	httputil.ErrorResponse
+//	@Failure		404	{object}	httputil.ErrorResponse
+//	@Router			/notes/{id}/collaboration [post]
+func (h *DocumentHandler) Enable(c *gin.Context) {
+	noteID, ok := documentNoteID(c)
+	if !ok {
+		return
+	}
+
+	doc, err := h.documents.Enable(c.Request.Context(), httputil.GetUserID(c), noteID)
+	if err != nil {
+		httputil.Fail(c, documentError(err))
+		return
+	}
+
+	httputil.OK(c, response.NoteDocumentFromEntity(doc))
+}
+
+// Disable godoc
+//
+//	@Summary		Disable collaborative editing
+//	@Description	Drop the note's document and its update log; the content stays as the document left it and is saved whole again. Owner only.
+//	@Tags			notes
+//	@Security		BearerAuth
+//	@Param			id	path	string	true	"Note ID"	format(uuid)
+//	@Success		204
+//	@Failure		400	{object}	httputil.ErrorResponse
+//	@Failure		401	{object}	httputil.ErrorResponse
+//	@Failure		403	{object}	httputil.ErrorResponse
+//	@Failure		404	{object}	httputil.ErrorResponse
+//	@Failure		409	{object}	httputil.ErrorResponse
+//	@Router			/notes/{id}/collaboration [delete]
+func (h *DocumentHandler) Disable(c *gin.Context) {
+	noteID, ok := documentNoteID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.documents.Disable(c.Request.Context(), httputil.GetUserID(c), noteID); err != nil {
+		httputil.Fail(c, documentError(err))
+		return
+	}
+
+	httputil.NoContent(c)
+}
+
+// Get godoc
+//
+//	@Summary		Get a note's document
+//	@Description	Get the document of a collaborative note: a snapshot, with deleted characters kept as counts, and the updates since. With since, the last seq the app applied, only the updates after it are returned while the log still holds them; the snapshot is then left out.
+//	@Tags			notes
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			id		path		string	true	"Note ID"	format(uuid)
+//	@Param			since	query		int		false	"Last seq the app applied"
+//	@Success		200		{object}	response.NoteDocumentResponse
+//	@Failure		400		{object}	httputil.ErrorResponse
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Failure		403		{object}	httputil.ErrorResponse
+//	@Failure		404		{object}	httputil.ErrorResponse
+//	@Failure		409		{object}	httputil.ErrorResponse
+//	@Router			/notes/{id}/collaboration [get]
+func (h *DocumentHandler) Get(c *gin.Context) {
+	noteID, ok := documentNoteID(c)
+	if !ok {
+		return
+	}
+	var req request.GetDocumentRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	doc, err := h.documents.Get(c.Request.Context(), httputil.GetUserID(c), noteID)
+	if err != nil {
+		httputil.Fail(c, documentError(err))
+		return
+	}
+
+	if req.Since != nil {
+		httputil.OK(c, response.NoteDocumentSince(doc, *req.Since))
+		return
+	}
+	httputil.OK(c, response.NoteDocumentFromEntity(doc))
+}
+
+// Update godoc
+//
+//	@Summary		Update a note's document
+//	@Description	Apply ops to the document of a collaborative note and save the resulting content. An insert puts text after the character with ID after, or at the start; its characters get the app's replica and consecutive seqs from id.s, which must be above every seq the app has seen. A delete removes count characters from id. The update is sent to the apps that have the note open on /ws. Resending an update is harmless. Owner and editors only.
+//	@Tags			notes
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string							true	"Note ID"	format(uuid)
+//	@Param			request	body		request.DocumentUpdateRequest	true	"Ops"
+//	@Success		200		{object}	response.DocumentSeqResponse
+//	@Failure		400		{object}	httputil.ErrorResponse
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Failure		403		{object}	httputil.ErrorResponse
+//	@Failure		404		{object}	httputil.ErrorResponse
+//	@Failure		409		{object}	httputil.ErrorResponse
+//	@Router			/notes/{id}/collaboration/updates [post]
+func (h *DocumentHandler) Update(c *gin.Context) {
+	noteID, ok := documentNoteID(c)
+	if !ok {
+		return
+	}
+	var req request.DocumentUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	seq, err := h.documents.Apply(c.Request.Context(), realtime.ApplyInput{
+		UserID:   httputil.GetUserID(c),
+		DeviceID: httputil.GetTokenDeviceID(c),
+		NoteID:   noteID,
+		Ops:      req.Ops,
+	})
+	if err != nil {
+		httputil.Fail(c, documentError(err))
+		return
+	}
+
+	httputil.MarkNotesChanged(c)
+	httputil.OK(c, response.DocumentSeqResponse{Seq: seq})
+}
+
+func documentNoteID(c *gin.Context) (uuid.UUID, bool) {
+	noteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidID, "invalid note id")
+		return uuid.Nil, false
+	}
+	return noteID, true
+}
+
+// documentError maps the errors of the document service, for both the HTTP
+// endpoints and the /ws channel.
+func documentError(err error) *apperror.Error {
+	switch {
+	case errors.Is(err, domain.ErrDocumentNotFound):
+		return apperror.New(http.StatusConflict, httputil.CodeNotCollaborative, "note is not collaborative")
+	case errors.Is(err, domain.ErrInvalidDocumentUpdate):
+		return apperror.New(http.StatusBadRequest, httputil.CodeInvalidUpdate, err.Error())
+	default:
+		return presenceError(err)
+	}
+}
@@ -0,0 +1,144 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/crdt"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/realtime"
+)
+
+func setupDocumentRouter(t *testing.T) (*mocks.MockDocumentService, *gin.Engine, uuid.UUID) {
+	ctrl := gomock.NewController(t)
+	documents := mocks.NewMockDocumentService(ctrl)
+	h := handler.NewDocumentHandler(documents)
+
+	router := setupRouter()
+	userID := uuid.New()
+	router.Use(func(c *gin.Context) { c.Set("user_id", userID) })
+	router.POST("/notes/:id/collaboration", h.Enable)
+	router.GET("/notes/:id/collaboration", h.Get)
+	router.POST("/notes/:id/collaboration/updates", h.Update)
+	return documents, router, userID
+}
+
+func TestDocumentHandler_Get(t *testing.T) {
+	noteID := uuid.New()
+	doc := entity.NewNoteDocument(noteID, crdt.FromText(realtime.ServerReplica, "abc").Snapshot())
+	doc.SnapshotSeq = 4
+	for seq := int64(5); seq <= 7; seq++ {
+		doc.Updates = append(doc.Updates, entity.DocumentUpdate{NoteID: noteID, Seq: seq})
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		snapshot     bool
+		snapshotSeq  int64
+		updatesAfter []int64
+	}{
+		{"whole document", "", true, 4, []int64{5, 6, 7}},
+		{"updates since a seq the log covers", "?since=6", false, 6, []int64{7}},
+		{"up to date", "?since=7", false, 7, []int64{}},
+		{"whole document when the log was compacted past since", "?since=2", true, 4, []int64{5, 6, 7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			documents, router, userID := setupDocumentRouter(t)
+			documents.EXPECT().Get(gomock.Any(), userID, noteID).Return(doc, nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/notes/"+noteID.String()+"/collaboration"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			var resp response.NoteDocumentResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, int64(7), resp.Seq)
+			assert.Equal(t, tt.snapshot, resp.Snapshot != nil)
+			assert.Equal(t, tt.snapshotSeq, resp.SnapshotSeq)
+			seqs := []int64{}
+			for _, u := range resp.Updates {
+				seqs = append(seqs, u.Seq)
+			}
+			assert.Equal(t, tt.updatesAfter, seqs)
+		})
+	}
+}
+
+func TestDocumentHandler_Update(t *testing.T) {
+	noteID := uuid.New()
+	ops := []crdt.Op{{Op: crdt.OpInsert, ID: crdt.ID{Replica: "tablet", Seq: 4}, Text: "x"}}
+
+	t.Run("applies the ops", func(t *testing.T) {
+		documents, router, userID := setupDocumentRouter(t)
+		documents.EXPECT().Apply(gomock.Any(), realtime.ApplyInput{UserID: userID, NoteID: noteID, Ops: ops}).Return(int64(3), nil)
+
+		body, _ := json.Marshal(map[string]any{"ops": ops})
+		req := httptest.NewRequest(http.MethodPost, "/notes/"+noteID.String()+"/collaboration/updates", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp response.DocumentSeqResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, int64(3), resp.Seq)
+	})
+
+	errorTests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"note not collaborative", domain.ErrDocumentNotFound, http.StatusConflict, httputil.CodeNotCollaborative},
+		{"ops that do not fit the document", fmt.Errorf("%w: %w", domain.ErrInvalidDocumentUpdate, crdt.ErrInvalidOp), http.StatusBadRequest, httputil.CodeInvalidUpdate},
+		{"viewer", domain.ErrForbidden, http.StatusForbidden, httputil.CodeForbidden},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			documents, router, _ := setupDocumentRouter(t)
+			documents.EXPECT().Apply(gomock.Any(), gomock.Any()).Return(int64(0), tt.err)
+
+			body, _ := json.Marshal(map[string]any{"ops": ops})
+			req := httptest.NewRequest(http.MethodPost, "/notes/"+noteID.String()+"/collaboration/updates", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			var resp httputil.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.code, resp.Code)
+		})
+	}
+
+	t.Run("rejects an update without ops", func(t *testing.T) {
+		_, router, _ := setupDocumentRouter(t)
+
+		req := httptest.NewRequest(http.MethodPost, "/notes/"+noteID.String()+"/collaboration/updates", bytes.NewReader([]byte(`{"ops":[]}`)))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
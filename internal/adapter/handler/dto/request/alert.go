@@ -0,0 +1,7 @@
+package request
+
+type ListAlertsRequest struct {
+	Page    int    `form:"page" binding:"omitempty,min=1"`
+	PerPage int    `form:"per_page" binding:"omitempty,min=1,max=100"`
+	Kind    string `form:"kind" binding:"omitempty,oneof=impossible_travel sync_volume refresh_storm"`
+}
@@ -0,0 +1,7 @@
+package request
+
+type ListAuditEventsRequest struct {
+	Page    int    `form:"page" binding:"omitempty,min=1"`
+	PerPage int    `form:"per_page" binding:"omitempty,min=1,max=100"`
+	Action  string `form:"action" binding:"omitempty,oneof=account_created login login_failed login_locked logout token_refresh sessions_revoked password_reset sso_settings_changed note_deleted account_deletion_requested"`
+}
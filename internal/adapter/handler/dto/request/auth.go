@@ -11,9 +11,45 @@ type LoginRequest struct {
 	Password   string `json:"password" binding:"required"`
 	DeviceID   string `json:"device_id" binding:"required,max=255"`
 	DeviceName string `json:"device_name" binding:"max=255"`
-	Platform   string `json:"platform" binding:"required,oneof=ios android web"`
+	Platform   string `json:"platform" binding:"required,max=32" example:"ios"`
+}
+
+type SocialLoginRequest struct {
+	IDToken    string `json:"id_token" binding:"required"`
+	Nonce      string `json:"nonce" binding:"max=255"`
+	Name       string `json:"name" binding:"max=255"`
+	DeviceID   string `json:"device_id" binding:"required,max=255"`
+	DeviceName string `json:"device_name" binding:"max=255"`
+	Platform   string `json:"platform" binding:"required,max=32" example:"ios"`
 }
 
 type RefreshRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email,max=255"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required,max=255"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+}
+
+type SSOLoginRequest struct {
+	DeviceID   string `form:"device_id" binding:"required,max=255"`
+	DeviceName string `form:"device_name" binding:"max=255"`
+	Platform   string `form:"platform" binding:"required,max=32"`
+}
+
+type SSOCallbackRequest struct {
+	Code  string `form:"code" binding:"required"`
+	State string `form:"state" binding:"required"`
+}
+
+type SSOSettingsRequest struct {
+	Issuer       string `json:"issuer" binding:"required,url,max=1024" example:"https://login.acme.com"`
+	ClientID     string `json:"client_id" binding:"required,max=255"`
+	ClientSecret string `json:"client_secret" binding:"max=512"`
+	SSOEnforced  bool   `json:"sso_enforced"`
+}
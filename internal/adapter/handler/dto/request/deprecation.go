@@ -0,0 +1,5 @@
+package request
+
+type DeprecationReportRequest struct {
+	Days int `form:"days" binding:"omitempty,min=1,max=365"`
+}
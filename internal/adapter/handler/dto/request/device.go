@@ -0,0 +1,14 @@
+package request
+
+type DeviceUsageRequest struct {
+	From string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To   string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+}
+
+type DeviceAuthorRequest struct {
+	Name string `json:"name" binding:"required,max=64" example:"Ana Silva"`
+}
+
+type DevicePushTokenRequest struct {
+	Token string `json:"token" binding:"required,max=4096" example:"f3c1e6a0b2d4..."`
+}
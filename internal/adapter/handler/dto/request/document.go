@@ -0,0 +1,13 @@
+package request
+
+import "github.com/marcos-nsantos/field-notes-backend/internal/pkg/crdt"
+
+type GetDocumentRequest struct {
+	// Since is the last seq the app applied; when the log still holds the
+	// updates after it, only those are returned.
+	Since *int64 `form:"since" binding:"omitempty,min=0"`
+}
+
+type DocumentUpdateRequest struct {
+	Ops []crdt.Op `json:"ops" binding:"required,min=1,max=1000"`
+}
@@ -1,13 +1,32 @@
 package request
 
+import "time"
+
 type CreateNoteRequest struct {
-	Title     string   `json:"title" binding:"required,max=255"`
-	Content   string   `json:"content" binding:"required"`
-	Latitude  *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
-	Longitude *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
-	Altitude  *float64 `json:"altitude"`
-	Accuracy  *float64 `json:"accuracy" binding:"omitempty,min=0"`
-	ClientID  string   `json:"client_id" binding:"omitempty,max=36"`
+	// Title is generated from the content when empty or a placeholder such
+	// as "Untitled", unless AutoTitle is false.
+	Title        string               `json:"title" binding:"max=255"`
+	Content      string               `json:"content" binding:"required"`
+	Latitude     *float64             `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude    *float64             `json:"longitude" binding:"omitempty,min=-180,max=180"`
+	Altitude     *float64             `json:"altitude"`
+	Accuracy     *float64             `json:"accuracy" binding:"omitempty,min=0"`
+	Measurements []MeasurementRequest `json:"measurements" binding:"omitempty,max=50,dive"`
+	ClientID     string               `json:"client_id" binding:"omitempty,max=36"`
+	// Sensitivity generalizes the location for everyone but the owner.
+	Sensitivity string `json:"sensitivity" binding:"omitempty,oneof=none low high" example:"high"`
+	// Source is manual (default) or integration:<name> for notes created
+	// by an integration; SourceMeta holds up to 4 KB of details about it.
+	Source     string         `json:"source" binding:"omitempty,max=52" example:"integration:weather-station"`
+	SourceMeta map[string]any `json:"source_meta"`
+	// TeamID creates the note for one of the user's teams.
+	TeamID string `json:"team_id" binding:"omitempty,uuid"`
+	// Color and Icon mark the note on maps and lists, by name from the
+	// palette and icon set the API accepts.
+	Color string `json:"color" binding:"omitempty,max=32" example:"green"`
+	Icon  string `json:"icon" binding:"omitempty,max=32" example:"tree"`
+	// AutoTitle false keeps an empty or placeholder title as sent.
+	AutoTitle *bool `json:"auto_title" example:"false"`
 }
 
 type UpdateNoteRequest struct {
@@ -17,13 +36,145 @@ type UpdateNoteRequest struct {
 	Longitude *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
 	Altitude  *float64 `json:"altitude"`
 	Accuracy  *float64 `json:"accuracy" binding:"omitempty,min=0"`
+	// Measurements replaces all measurements when present; send [] to clear them.
+	Measurements []MeasurementRequest `json:"measurements" binding:"omitempty,max=50,dive"`
+	// Sensitivity can only be changed by the owner.
+	Sensitivity *string `json:"sensitivity" binding:"omitempty,oneof=none low high" example:"high"`
+	// Color and Icon change the note's when present; send "" to take them off.
+	Color *string `json:"color" binding:"omitempty,max=32" example:"green"`
+	Icon  *string `json:"icon" binding:"omitempty,max=32" example:"tree"`
+	// AutoTitle false keeps the title as sent, and a generated title as it
+	// is when the content changes.
+	AutoTitle *bool `json:"auto_title" example:"false"`
+}
+
+// NoteTagsRequest lists tags to add to or remove from a note.
+type NoteTagsRequest struct {
+	Tags []string `json:"tags" binding:"required,min=1,max=20,dive,required,max=50" example:"soil-sample"`
+}
+
+// MergeNotesRequest merges notes into the first one listed, which survives.
+type MergeNotesRequest struct {
+	NoteIDs []string `json:"note_ids" binding:"required,min=2,max=20,dive,uuid"`
+	// TitleFrom is the note whose title is kept; defaults to the first note.
+	TitleFrom string `json:"title_from" binding:"omitempty,uuid"`
+	// Content is concatenate (default) or keep, which keeps the first note's content.
+	Content string `json:"content" binding:"omitempty,oneof=concatenate keep" example:"concatenate"`
+}
+
+// NotesExistRequest asks which notes exist, by server ID, by the client's
+// own ID, or both.
+type NotesExistRequest struct {
+	IDs       []string `json:"ids" binding:"omitempty,max=500,dive,uuid"`
+	ClientIDs []string `json:"client_ids" binding:"omitempty,max=500,dive,required,max=36"`
+}
+
+// MeasurementRequest is a named value in one of the supported units:
+// m, cm, mm, km, in, ft, yd, mi (length), kg, g, mg, lb, oz (mass), C, F, K (temperature).
+type MeasurementRequest struct {
+	Name  string  `json:"name" binding:"required,max=64" example:"dbh"`
+	Value float64 `json:"value" example:"12.5"`
+	Unit  string  `json:"unit" binding:"required,oneof=m cm mm km in ft yd mi kg g mg lb oz C F K" example:"in"`
+}
+
+type NearbyNotesRequest struct {
+	Latitude  *float64 `form:"lat" binding:"required,min=-90,max=90"`
+	Longitude *float64 `form:"lng" binding:"required,min=-180,max=180"`
+	Radius    float64  `form:"radius_m" binding:"required,gt=0,max=50000"`
+	Limit     int      `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type NoteCoverageRequest struct {
+	Zoom int `form:"zoom" binding:"omitempty,min=1,max=18"`
+}
+
+// SearchNotesRequest takes either lat, lng and radius_m or the four bounding
+// box bounds to limit the search to an area.
+type SearchNotesRequest struct {
+	Query     string   `form:"q" binding:"required,max=200"`
+	Latitude  *float64 `form:"lat" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64 `form:"lng" binding:"omitempty,min=-180,max=180"`
+	Radius    float64  `form:"radius_m" binding:"omitempty,gt=0,max=50000"`
+	MinLat    *float64 `form:"min_lat" binding:"omitempty,min=-90,max=90"`
+	MaxLat    *float64 `form:"max_lat" binding:"omitempty,min=-90,max=90"`
+	MinLng    *float64 `form:"min_lng" binding:"omitempty,min=-180,max=180"`
+	MaxLng    *float64 `form:"max_lng" binding:"omitempty,min=-180,max=180"`
+	Limit     int      `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type SemanticSearchRequest struct {
+	Query string `form:"q" binding:"required,max=200"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type RelatedNotesRequest struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// ImportNotesRequest picks the format of the uploaded file; when omitted
+// it comes from the file extension.
+type ImportNotesRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=geojson csv"`
+}
+
+// ExportNotesRequest takes the filters of ListNotesRequest, without
+// pagination, and a creation date range.
+type ExportNotesRequest struct {
+	Format   string     `form:"format" binding:"omitempty,oneof=ndjson csv gpx"`
+	MinLat   *float64   `form:"min_lat" binding:"omitempty,min=-90,max=90"`
+	MaxLat   *float64   `form:"max_lat" binding:"omitempty,min=-90,max=90"`
+	MinLng   *float64   `form:"min_lng" binding:"omitempty,min=-180,max=180"`
+	MaxLng   *float64   `form:"max_lng" binding:"omitempty,min=-180,max=180"`
+	DeviceID string     `form:"device_id" binding:"omitempty,max=255"`
+	Quality  string     `form:"quality" binding:"omitempty,oneof=unchecked passed failed"`
+	Tags     []string   `form:"tag" binding:"omitempty,max=10,dive,max=50"`
+	Colors   []string   `form:"color" binding:"omitempty,max=20,dive,max=32"`
+	Icons    []string   `form:"icon" binding:"omitempty,max=20,dive,max=32"`
+	Source   string     `form:"source" binding:"omitempty,max=52"`
+	From     *time.Time `form:"from"`
+	To       *time.Time `form:"to"`
 }
 
 type ListNotesRequest struct {
-	Page    int      `form:"page" binding:"omitempty,min=1"`
+	Page     int      `form:"page" binding:"omitempty,min=1"`
+	PerPage  int      `form:"per_page" binding:"omitempty,min=1,max=100"`
+	MinLat   *float64 `form:"min_lat" binding:"omitempty,min=-90,max=90"`
+	MaxLat   *float64 `form:"max_lat" binding:"omitempty,min=-90,max=90"`
+	MinLng   *float64 `form:"min_lng" binding:"omitempty,min=-180,max=180"`
+	MaxLng   *float64 `form:"max_lng" binding:"omitempty,min=-180,max=180"`
+	DeviceID string   `form:"device_id" binding:"omitempty,max=255"`
+	Quality  string   `form:"quality" binding:"omitempty,oneof=unchecked passed failed"`
+	Number   string   `form:"number" binding:"omitempty,max=40"`
+	Tags     []string `form:"tag" binding:"omitempty,max=10,dive,max=50"`
+	Colors   []string `form:"color" binding:"omitempty,max=20,dive,max=32"`
+	Icons    []string `form:"icon" binding:"omitempty,max=20,dive,max=32"`
+	Cursor   string   `form:"cursor" binding:"omitempty,max=200"`
+	Source   string   `form:"source" binding:"omitempty,max=52"`
+	TeamID   string   `form:"team_id" binding:"omitempty,uuid"`
+	Sort     string   `form:"sort" binding:"omitempty,max=100"`
+}
+
+type ListNoteSummariesRequest struct {
 	PerPage int      `form:"per_page" binding:"omitempty,min=1,max=100"`
+	Cursor  string   `form:"cursor" binding:"omitempty,max=200"`
 	MinLat  *float64 `form:"min_lat" binding:"omitempty,min=-90,max=90"`
 	MaxLat  *float64 `form:"max_lat" binding:"omitempty,min=-90,max=90"`
 	MinLng  *float64 `form:"min_lng" binding:"omitempty,min=-180,max=180"`
 	MaxLng  *float64 `form:"max_lng" binding:"omitempty,min=-180,max=180"`
+	Tags    []string `form:"tag" binding:"omitempty,max=10,dive,max=50"`
+	Colors  []string `form:"color" binding:"omitempty,max=20,dive,max=32"`
+	Icons   []string `form:"icon" binding:"omitempty,max=20,dive,max=32"`
+	TeamID  string   `form:"team_id" binding:"omitempty,uuid"`
+}
+
+type QRCodeRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=png svg"`
+	Size   int    `form:"size" binding:"min=64,max=2048"`
+	Level  string `form:"level" binding:"oneof=L M Q H"`
+}
+
+type ListRevisionsRequest struct {
+	// Before pages back: it lists the revisions numbered below it.
+	Before int `form:"before" binding:"omitempty,min=1"`
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=100"`
 }
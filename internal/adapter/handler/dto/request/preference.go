@@ -0,0 +1,9 @@
+package request
+
+// PreferencesRequest changes only the fields that are present.
+type PreferencesRequest struct {
+	UnitSystem          string `json:"unit_system" binding:"omitempty,oneof=metric imperial"`
+	NotePrefix          string `json:"note_prefix" binding:"omitempty,max=16,alphanum" example:"PLOT"`
+	NotifySyncConflicts *bool  `json:"notify_sync_conflicts" example:"true"`
+	SemanticSearch      *bool  `json:"semantic_search" example:"false"`
+}
@@ -0,0 +1,15 @@
+package request
+
+type QualityRulesRequest struct {
+	RequirePhoto     bool          `json:"require_photo"`
+	MinContentLength int           `json:"min_content_length" binding:"omitempty,min=0,max=100000"`
+	Fence            *FenceRequest `json:"fence"`
+	MaxAccuracy      *float64      `json:"max_accuracy" binding:"omitempty,gt=0"`
+}
+
+type FenceRequest struct {
+	MinLat float64 `json:"min_lat" binding:"min=-90,max=90"`
+	MaxLat float64 `json:"max_lat" binding:"min=-90,max=90"`
+	MinLng float64 `json:"min_lng" binding:"min=-180,max=180"`
+	MaxLng float64 `json:"max_lng" binding:"min=-180,max=180"`
+}
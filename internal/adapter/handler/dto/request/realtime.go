@@ -0,0 +1,32 @@
+package request
+
+import "github.com/marcos-nsantos/field-notes-backend/internal/pkg/crdt"
+
+// Types of the messages apps send on the /ws channel.
+const (
+	RealtimeOpen  = "open"
+	RealtimeMove  = "move"
+	RealtimeClose = "close"
+	// RealtimeUpdate sends ops to the document of a collaborative note.
+	RealtimeUpdate = "update"
+)
+
+// RealtimeMessage is a message from the app on the /ws channel: open when
+// it shows a note, move when the cursor or selection in it changes, and
+// close when it stops showing it; update with ops to a collaborative note's
+// document, as POST /notes/{id}/collaboration/updates takes them.
+type RealtimeMessage struct {
+	Type   string `json:"type" enums:"open,move,close,update"`
+	NoteID string `json:"note_id"`
+	// Selection is sent with open and move; omit it to share only that the
+	// note is open.
+	Selection *SelectionRequest `json:"selection,omitempty"`
+	Ops       []crdt.Op         `json:"ops,omitempty"`
+}
+
+// SelectionRequest is a range of the note content in characters; a cursor
+// has Start equal to End.
+type SelectionRequest struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
@@ -0,0 +1,19 @@
+package request
+
+// BulkShareRequest grants and revokes access to several notes in one call.
+type BulkShareRequest struct {
+	NoteIDs []string            `json:"note_ids" binding:"required,min=1,max=200,dive,uuid"`
+	Grants  []ShareGrantRequest `json:"grants" binding:"omitempty,max=50,dive"`
+	Revokes []string            `json:"revokes" binding:"omitempty,max=50,dive,email"`
+}
+
+type ShareGrantRequest struct {
+	Email string `json:"email" binding:"required,email" example:"colleague@example.com"`
+	Role  string `json:"role" binding:"required,oneof=viewer editor" example:"editor"`
+}
+
+// PhotoSharingRequest excludes a photo from its note's shares, or includes
+// it again.
+type PhotoSharingRequest struct {
+	Excluded *bool `json:"excluded" binding:"required" example:"true"`
+}
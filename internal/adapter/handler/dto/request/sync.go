@@ -3,19 +3,85 @@ package request
 import "time"
 
 type SyncRequest struct {
-	DeviceID   string     `json:"device_id" binding:"required,max=255"`
-	SyncCursor *time.Time `json:"sync_cursor"`
-	Notes      []SyncNote `json:"notes" binding:"dive"`
+	DeviceID   string      `json:"device_id" binding:"required,max=255"`
+	SyncCursor *time.Time  `json:"sync_cursor"`
+	Notes      []SyncNote  `json:"notes" binding:"dive"`
+	Photos     []SyncPhoto `json:"photos" binding:"omitempty,max=1000,dive"`
+	// ConflictStrategy keep_both saves the losing side of a conflict as a
+	// new note instead of discarding it; manual holds the client side for
+	// the user to replay later.
+	ConflictStrategy string `json:"conflict_strategy" binding:"omitempty,oneof=last_write_wins keep_both manual" enums:"last_write_wins,keep_both,manual"`
+	// Limit caps server_notes; PageToken continues from a previous response's
+	// next_page_token.
+	Limit     int    `json:"limit" binding:"omitempty,min=1,max=1000"`
+	PageToken string `json:"page_token" binding:"omitempty,max=255"`
+	// AutoTitle false keeps the empty and placeholder titles of the notes
+	// as sent rather than generating them from the content.
+	AutoTitle *bool `json:"auto_title" example:"false"`
+}
+
+// SyncPhoto is a photo held by the device, sent so the server can say
+// whether it still needs uploading.
+type SyncPhoto struct {
+	ClientID     string `json:"client_id" binding:"required,max=36"`
+	NoteClientID string `json:"note_client_id" binding:"required,max=36"`
 }
 
 type SyncNote struct {
-	ClientID  string    `json:"client_id" binding:"required,max=36"`
-	Title     string    `json:"title" binding:"required,max=255"`
-	Content   string    `json:"content" binding:"required"`
-	Latitude  *float64  `json:"latitude" binding:"omitempty,min=-90,max=90"`
-	Longitude *float64  `json:"longitude" binding:"omitempty,min=-180,max=180"`
-	Altitude  *float64  `json:"altitude"`
-	Accuracy  *float64  `json:"accuracy" binding:"omitempty,min=0"`
+	ClientID     string               `json:"client_id" binding:"required,max=36"`
+	Title        string               `json:"title" binding:"max=255"`
+	Content      string               `json:"content" binding:"required"`
+	Latitude     *float64             `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude    *float64             `json:"longitude" binding:"omitempty,min=-180,max=180"`
+	Altitude     *float64             `json:"altitude"`
+	Accuracy     *float64             `json:"accuracy" binding:"omitempty,min=0"`
+	Measurements []MeasurementRequest `json:"measurements" binding:"omitempty,max=50,dive"`
+	// Tags replaces the note's tags; omit it to leave them unchanged.
+	Tags []string `json:"tags" binding:"omitempty,max=100,dive,max=100"`
+	// Color and Icon replace the note's; omit them to leave them unchanged
+	// or send "" to take them off.
+	Color     *string   `json:"color" binding:"omitempty,max=32" example:"green"`
+	Icon      *string   `json:"icon" binding:"omitempty,max=32" example:"tree"`
 	UpdatedAt time.Time `json:"updated_at" binding:"required"`
-	IsDeleted bool      `json:"is_deleted"`
+	// FieldUpdatedAt is when the title, content and location last changed:
+	// for fields the device changed, when it did, and for the others the
+	// times it last pulled. With it a conflict is merged field by field.
+	FieldUpdatedAt SyncFieldTimes `json:"field_updated_at"`
+	IsDeleted      bool           `json:"is_deleted"`
+}
+
+// SyncFieldTimes are per-field edit times; an omitted one stands for the
+// note's updated_at.
+type SyncFieldTimes struct {
+	Title    time.Time `json:"title"`
+	Content  time.Time `json:"content"`
+	Location time.Time `json:"location"`
+}
+
+type PhotoManifestRequest struct {
+	DeviceID string `form:"device_id" binding:"omitempty,max=255"`
+	Cursor   string `form:"cursor" binding:"omitempty,max=255"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=1000"`
+}
+
+type SyncChangesRequest struct {
+	DeviceID string `form:"device_id" binding:"omitempty,max=255"`
+	Cursor   string `form:"cursor" binding:"omitempty,max=255"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=1000"`
+}
+
+type SyncScopeRequest struct {
+	DeviceID      string     `json:"device_id" binding:"required,max=255"`
+	NotesSince    *time.Time `json:"notes_since"`
+	ExcludePhotos bool       `json:"exclude_photos"`
+}
+
+// ReplayConflictRequest keeps one side of a conflict held by the manual
+// strategy.
+type ReplayConflictRequest struct {
+	ConflictID string `json:"conflict_id" binding:"required,uuid"`
+	Side       string `json:"side" binding:"required,oneof=client server" enums:"client,server"`
+	// DeviceID is recorded as the device that made the change when the
+	// client side is kept.
+	DeviceID string `json:"device_id" binding:"omitempty,max=255"`
 }
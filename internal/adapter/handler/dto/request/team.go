@@ -0,0 +1,14 @@
+package request
+
+type CreateTeamRequest struct {
+	Name string `json:"name" binding:"required,max=100" example:"River survey 2026"`
+}
+
+type InviteTeamMemberRequest struct {
+	Email string `json:"email" binding:"required,email" example:"colleague@example.com"`
+	Role  string `json:"role" binding:"required,oneof=admin editor viewer" example:"editor"`
+}
+
+type UpdateTeamRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin editor viewer" example:"viewer"`
+}
@@ -0,0 +1,19 @@
+package response
+
+import (
+	"time"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+type AccountDeletionResponse struct {
+	Status      string    `json:"status" example:"scheduled"`
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+func AccountDeletionFromEntity(d *entity.AccountDeletion) AccountDeletionResponse {
+	return AccountDeletionResponse{
+		Status:      "scheduled",
+		RequestedAt: d.RequestedAt,
+	}
+}
@@ -0,0 +1,38 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+type SecurityAlertResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	DeviceID  *uuid.UUID `json:"device_id,omitempty"`
+	Kind      string     `json:"kind" example:"impossible_travel"`
+	Detail    string     `json:"detail" example:"login from Tokyo, JP, 11140 km from the login from Lisbon, PT 12m0s earlier"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type SecurityAlertsListResponse struct {
+	Alerts     []SecurityAlertResponse `json:"alerts"`
+	Pagination PaginationResponse      `json:"pagination"`
+}
+
+func SecurityAlertsFromEntities(alerts []entity.SecurityAlert) []SecurityAlertResponse {
+	resp := make([]SecurityAlertResponse, 0, len(alerts))
+	for _, a := range alerts {
+		resp = append(resp, SecurityAlertResponse{
+			ID:        a.ID,
+			UserID:    a.UserID,
+			DeviceID:  a.DeviceID,
+			Kind:      a.Kind,
+			Detail:    a.Detail,
+			CreatedAt: a.CreatedAt,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,40 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+type AuditEventResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	Action    string     `json:"action" example:"login"`
+	Target    string     `json:"target,omitempty" example:"password"`
+	DeviceID  *uuid.UUID `json:"device_id,omitempty"`
+	IP        string     `json:"ip,omitempty" example:"203.0.113.7"`
+	UserAgent string     `json:"user_agent,omitempty" example:"FieldNotes/2.4.0 (iOS 18.1)"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type AuditEventsListResponse struct {
+	Events     []AuditEventResponse `json:"events"`
+	Pagination PaginationResponse   `json:"pagination"`
+}
+
+func AuditEventsFromEntities(events []entity.AuditEvent) []AuditEventResponse {
+	resp := make([]AuditEventResponse, 0, len(events))
+	for _, e := range events {
+		resp = append(resp, AuditEventResponse{
+			ID:        e.ID,
+			Action:    e.Action,
+			Target:    e.Target,
+			DeviceID:  e.DeviceID,
+			IP:        e.IP,
+			UserAgent: e.UserAgent,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+	return resp
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/demo"
 )
 
 type UserResponse struct {
@@ -36,3 +37,38 @@ func UserFromEntity(user *entity.User) UserResponse {
 		CreatedAt: user.CreatedAt,
 	}
 }
+
+// SSOSettingsResponse never includes the client secret.
+type SSOSettingsResponse struct {
+	Slug        string    `json:"slug"`
+	Name        string    `json:"name"`
+	Domain      string    `json:"domain"`
+	Issuer      string    `json:"issuer"`
+	ClientID    string    `json:"client_id"`
+	SSOEnforced bool      `json:"sso_enforced"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func SSOSettingsFromEntity(org *entity.Organization) SSOSettingsResponse {
+	return SSOSettingsResponse{
+		Slug:        org.Slug,
+		Name:        org.Name,
+		Domain:      org.Domain,
+		Issuer:      org.OIDCIssuer,
+		ClientID:    org.OIDCClientID,
+		SSOEnforced: org.SSOEnforced,
+		UpdatedAt:   org.UpdatedAt,
+	}
+}
+
+type DemoCredentialsResponse struct {
+	Email    string `json:"email" example:"demo@fieldnotes.app"`
+	Password string `json:"password" example:"demo1234"`
+}
+
+func DemoCredentialsFrom(c demo.Credentials) DemoCredentialsResponse {
+	return DemoCredentialsResponse{
+		Email:    c.Email,
+		Password: c.Password,
+	}
+}
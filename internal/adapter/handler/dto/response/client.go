@@ -0,0 +1,45 @@
+package response
+
+import (
+	"time"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+type ClientAdoptionResponse struct {
+	Platform   string           `json:"platform"`
+	AppVersion string           `json:"app_version"`
+	Requests   int64            `json:"requests"`
+	Share      float64          `json:"share"`
+	Devices    int64            `json:"devices"`
+	FirstSeen  string           `json:"first_seen"`
+	LastSeen   string           `json:"last_seen"`
+	OS         map[string]int64 `json:"os"`
+}
+
+type ClientAdoptionListResponse struct {
+	From     string                   `json:"from"`
+	To       string                   `json:"to"`
+	Versions []ClientAdoptionResponse `json:"versions"`
+}
+
+func ClientAdoptionFromEntities(from, to time.Time, adoption []entity.ClientAdoption) ClientAdoptionListResponse {
+	resp := ClientAdoptionListResponse{
+		From:     from.Format("2006-01-02"),
+		To:       to.Format("2006-01-02"),
+		Versions: make([]ClientAdoptionResponse, 0, len(adoption)),
+	}
+	for _, a := range adoption {
+		resp.Versions = append(resp.Versions, ClientAdoptionResponse{
+			Platform:   a.Platform,
+			AppVersion: a.AppVersion,
+			Requests:   a.Requests,
+			Share:      a.Share,
+			Devices:    a.Devices,
+			FirstSeen:  a.FirstSeen.Format("2006-01-02"),
+			LastSeen:   a.LastSeen.Format("2006-01-02"),
+			OS:         a.OS,
+		})
+	}
+	return resp
+}
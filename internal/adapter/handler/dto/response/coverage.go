@@ -0,0 +1,57 @@
+package response
+
+import (
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+)
+
+type BoundingBoxResponse struct {
+	MinLat float64 `json:"min_lat"`
+	MaxLat float64 `json:"max_lat"`
+	MinLng float64 `json:"min_lng"`
+	MaxLng float64 `json:"max_lng"`
+}
+
+// CoverageResponse lists the map tiles to download for offline use of the
+// user's notes.
+type CoverageResponse struct {
+	Zoom int `json:"zoom" example:"12"`
+	// Notes counts the notes with a location; Bounds encloses them.
+	Notes  int                  `json:"notes"`
+	Bounds *BoundingBoxResponse `json:"bounds"`
+	Tiles  []TileResponse       `json:"tiles"`
+	// Truncated is set when the notes span more tiles than are returned;
+	// ask again with a lower zoom.
+	Truncated bool `json:"truncated"`
+}
+
+// TileResponse is a Web Mercator (slippy map) tile, as in {z}/{x}/{y} tile
+// URLs, with the area it covers.
+type TileResponse struct {
+	Z      int                 `json:"z" example:"12"`
+	X      int                 `json:"x" example:"1943"`
+	Y      int                 `json:"y" example:"1556"`
+	Notes  int                 `json:"notes"`
+	Bounds BoundingBoxResponse `json:"bounds"`
+}
+
+func BoundingBoxFromValue(bb valueobject.BoundingBox) BoundingBoxResponse {
+	return BoundingBoxResponse{MinLat: bb.MinLat, MaxLat: bb.MaxLat, MinLng: bb.MinLng, MaxLng: bb.MaxLng}
+}
+
+func CoverageFromEntity(c *entity.NoteCoverage) CoverageResponse {
+	resp := CoverageResponse{
+		Zoom:      c.Zoom,
+		Notes:     c.Notes,
+		Tiles:     make([]TileResponse, len(c.Tiles)),
+		Truncated: c.Truncated,
+	}
+	if c.Bounds != nil {
+		bounds := BoundingBoxFromValue(*c.Bounds)
+		resp.Bounds = &bounds
+	}
+	for i, t := range c.Tiles {
+		resp.Tiles[i] = TileResponse{Z: t.Z, X: t.X, Y: t.Y, Notes: t.Notes, Bounds: BoundingBoxFromValue(t.Bounds())}
+	}
+	return resp
+}
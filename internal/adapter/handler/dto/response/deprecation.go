@@ -0,0 +1,69 @@
+package response
+
+import (
+	"time"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+type DeprecatedClientResponse struct {
+	Platform   string `json:"platform"`
+	AppVersion string `json:"app_version"`
+	Requests   int64  `json:"requests"`
+	LastSeen   string `json:"last_seen"`
+}
+
+type DeprecationReportResponse struct {
+	Name     string                     `json:"name"`
+	Method   string                     `json:"method"`
+	Route    string                     `json:"route"`
+	Field    string                     `json:"field,omitempty"`
+	Since    string                     `json:"since"`
+	Sunset   string                     `json:"sunset,omitempty"`
+	Link     string                     `json:"link,omitempty"`
+	Requests int64                      `json:"requests"`
+	LastSeen string                     `json:"last_seen,omitempty"`
+	Clients  []DeprecatedClientResponse `json:"clients"`
+}
+
+type DeprecationReportListResponse struct {
+	From         string                      `json:"from"`
+	To           string                      `json:"to"`
+	Deprecations []DeprecationReportResponse `json:"deprecations"`
+}
+
+func DeprecationReportsFromEntities(from, to time.Time, reports []entity.DeprecationReport) DeprecationReportListResponse {
+	resp := DeprecationReportListResponse{
+		From:         from.Format("2006-01-02"),
+		To:           to.Format("2006-01-02"),
+		Deprecations: make([]DeprecationReportResponse, 0, len(reports)),
+	}
+	for _, r := range reports {
+		item := DeprecationReportResponse{
+			Name:     r.Name(),
+			Method:   r.Method,
+			Route:    r.Route,
+			Field:    r.Field,
+			Since:    r.Since.Format("2006-01-02"),
+			Link:     r.Link,
+			Requests: r.Requests,
+			Clients:  make([]DeprecatedClientResponse, 0, len(r.Clients)),
+		}
+		if !r.Sunset.IsZero() {
+			item.Sunset = r.Sunset.Format("2006-01-02")
+		}
+		if !r.LastSeen.IsZero() {
+			item.LastSeen = r.LastSeen.Format("2006-01-02")
+		}
+		for _, client := range r.Clients {
+			item.Clients = append(item.Clients, DeprecatedClientResponse{
+				Platform:   client.Platform,
+				AppVersion: client.AppVersion,
+				Requests:   client.Requests,
+				LastSeen:   client.LastSeen.Format("2006-01-02"),
+			})
+		}
+		resp.Deprecations = append(resp.Deprecations, item)
+	}
+	return resp
+}
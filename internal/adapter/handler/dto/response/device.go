@@ -0,0 +1,92 @@
+package response
+
+import (
+	"time"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+type DailyUsageResponse struct {
+	Date     string `json:"date"`
+	BytesIn  int64  `json:"bytes_in"`
+	BytesOut int64  `json:"bytes_out"`
+}
+
+type DeviceUsageResponse struct {
+	DeviceID      string               `json:"device_id"`
+	Days          []DailyUsageResponse `json:"days"`
+	TotalBytesIn  int64                `json:"total_bytes_in"`
+	TotalBytesOut int64                `json:"total_bytes_out"`
+}
+
+func DeviceUsageFromEntities(deviceID string, usage []entity.DeviceUsage) DeviceUsageResponse {
+	resp := DeviceUsageResponse{
+		DeviceID: deviceID,
+		Days:     make([]DailyUsageResponse, 0, len(usage)),
+	}
+	for _, u := range usage {
+		resp.Days = append(resp.Days, DailyUsageResponse{
+			Date:     u.Day.Format("2006-01-02"),
+			BytesIn:  u.BytesIn,
+			BytesOut: u.BytesOut,
+		})
+		resp.TotalBytesIn += u.BytesIn
+		resp.TotalBytesOut += u.BytesOut
+	}
+	return resp
+}
+
+type LastAccessResponse struct {
+	IP      string    `json:"ip" example:"203.0.113.7"`
+	City    string    `json:"city,omitempty" example:"Lisbon"`
+	Country string    `json:"country,omitempty" example:"PT"`
+	At      time.Time `json:"at"`
+}
+
+type SessionResponse struct {
+	DeviceID     string              `json:"device_id"`
+	Name         string              `json:"name,omitempty"`
+	Platform     string              `json:"platform"`
+	ActiveAuthor string              `json:"active_author,omitempty" example:"Ana Silva"`
+	LastAccess   *LastAccessResponse `json:"last_access,omitempty"`
+}
+
+type DeviceAuthorResponse struct {
+	DeviceID string     `json:"device_id"`
+	Name     string     `json:"name,omitempty" example:"Ana Silva"`
+	Since    *time.Time `json:"since,omitempty"`
+}
+
+func DeviceAuthorFromEntity(device *entity.Device) DeviceAuthorResponse {
+	return DeviceAuthorResponse{
+		DeviceID: device.DeviceID,
+		Name:     device.ActiveAuthor,
+		Since:    device.ActiveAuthorSince,
+	}
+}
+
+type RevokeSessionsResponse struct {
+	Revoked int `json:"revoked" example:"2"`
+}
+
+func SessionsFromEntities(devices []entity.Device) []SessionResponse {
+	resp := make([]SessionResponse, 0, len(devices))
+	for _, d := range devices {
+		s := SessionResponse{
+			DeviceID:     d.DeviceID,
+			Name:         d.Name,
+			Platform:     d.Platform,
+			ActiveAuthor: d.ActiveAuthor,
+		}
+		if d.LastAccess != nil {
+			s.LastAccess = &LastAccessResponse{
+				IP:      d.LastAccess.IP,
+				City:    d.LastAccess.City,
+				Country: d.LastAccess.Country,
+				At:      d.LastAccess.At,
+			}
+		}
+		resp = append(resp, s)
+	}
+	return resp
+}
@@ -0,0 +1,69 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/crdt"
+)
+
+// NoteDocumentResponse is the document of a collaborative note: Updates
+// are the ones after SnapshotSeq, up to Seq, and Snapshot the document
+// after update SnapshotSeq. Snapshot is left out when the app asked for the
+// updates since a seq the log still covers; SnapshotSeq is then that seq.
+type NoteDocumentResponse struct {
+	NoteID      uuid.UUID                `json:"note_id"`
+	Seq         int64                    `json:"seq"`
+	Snapshot    *crdt.Snapshot           `json:"snapshot,omitempty"`
+	SnapshotSeq int64                    `json:"snapshot_seq"`
+	Updates     []DocumentUpdateResponse `json:"updates"`
+}
+
+type DocumentUpdateResponse struct {
+	Seq       int64      `json:"seq"`
+	UserID    *uuid.UUID `json:"user_id,omitempty"`
+	DeviceID  *uuid.UUID `json:"device_id,omitempty"`
+	Ops       []crdt.Op  `json:"ops"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// DocumentSeqResponse is the seq of the document after an update.
+type DocumentSeqResponse struct {
+	Seq int64 `json:"seq"`
+}
+
+func DocumentUpdateFromEntity(u entity.DocumentUpdate) DocumentUpdateResponse {
+	return DocumentUpdateResponse{Seq: u.Seq, UserID: u.UserID, DeviceID: u.DeviceID, Ops: u.Ops, CreatedAt: u.CreatedAt}
+}
+
+// NoteDocumentFromEntity returns the whole document.
+func NoteDocumentFromEntity(doc *entity.NoteDocument) NoteDocumentResponse {
+	resp := documentUpdates(doc, doc.SnapshotSeq, doc.Updates)
+	resp.Snapshot = &doc.Snapshot
+	return resp
+}
+
+// NoteDocumentSince returns the updates after seq, or the whole document
+// when the log no longer holds them all.
+func NoteDocumentSince(doc *entity.NoteDocument, seq int64) NoteDocumentResponse {
+	updates, ok := doc.UpdatesSince(seq)
+	if !ok {
+		return NoteDocumentFromEntity(doc)
+	}
+	return documentUpdates(doc, seq, updates)
+}
+
+func documentUpdates(doc *entity.NoteDocument, from int64, updates []entity.DocumentUpdate) NoteDocumentResponse {
+	resp := NoteDocumentResponse{
+		NoteID:      doc.NoteID,
+		Seq:         doc.Seq(),
+		SnapshotSeq: from,
+		Updates:     make([]DocumentUpdateResponse, 0, len(updates)),
+	}
+	for _, u := range updates {
+		resp.Updates = append(resp.Updates, DocumentUpdateFromEntity(u))
+	}
+	return resp
+}
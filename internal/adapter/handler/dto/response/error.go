@@ -0,0 +1,27 @@
+package response
+
+import (
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+type ErrorCodeResponse struct {
+	Code        string `json:"code" example:"NOT_FOUND"`
+	Status      int    `json:"status" example:"404"`
+	Description string `json:"description" example:"The requested resource does not exist or was deleted"`
+}
+
+type ErrorCatalogResponse struct {
+	Errors []ErrorCodeResponse `json:"errors"`
+}
+
+func ErrorCatalogFromInfo(catalog []httputil.ErrorCodeInfo) ErrorCatalogResponse {
+	resp := ErrorCatalogResponse{Errors: make([]ErrorCodeResponse, 0, len(catalog))}
+	for _, e := range catalog {
+		resp.Errors = append(resp.Errors, ErrorCodeResponse{
+			Code:        e.Code,
+			Status:      e.Status,
+			Description: e.Description,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,38 @@
+package response
+
+import (
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/health"
+)
+
+const (
+	healthUp   = "up"
+	healthDown = "down"
+)
+
+type HealthCheckResponse struct {
+	Status    string `json:"status"`
+	LatencyMS int64  `json:"latency_ms"`
+}
+
+type ReadinessResponse struct {
+	Status string                         `json:"status"`
+	Checks map[string]HealthCheckResponse `json:"checks"`
+}
+
+func ReadinessFromReport(report health.Report) ReadinessResponse {
+	resp := ReadinessResponse{
+		Status: "ready",
+		Checks: make(map[string]HealthCheckResponse, len(report.Checks)),
+	}
+	if !report.Ready() {
+		resp.Status = "unavailable"
+	}
+	for _, s := range report.Checks {
+		status := healthUp
+		if !s.Up() {
+			status = healthDown
+		}
+		resp.Checks[s.Name] = HealthCheckResponse{Status: status, LatencyMS: s.Latency.Milliseconds()}
+	}
+	return resp
+}
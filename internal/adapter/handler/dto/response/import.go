@@ -0,0 +1,48 @@
+package response
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/note"
+)
+
+// ImportResponse summarizes an import, with the outcome of every row.
+type ImportResponse struct {
+	Created int                 `json:"created"`
+	Skipped int                 `json:"skipped"`
+	Failed  int                 `json:"failed"`
+	Rows    []ImportRowResponse `json:"rows"`
+}
+
+type ImportRowResponse struct {
+	// Row is the row's position in the file, starting at 1.
+	Row      int    `json:"row" example:"3"`
+	ClientID string `json:"client_id,omitempty"`
+	// Status is created, skipped (the client ID was imported before) or failed.
+	Status string     `json:"status" example:"created"`
+	NoteID *uuid.UUID `json:"note_id,omitempty"`
+	Error  string     `json:"error,omitempty" example:"invalid location"`
+}
+
+func ImportFromResults(results []note.ImportResult) ImportResponse {
+	resp := ImportResponse{Rows: make([]ImportRowResponse, len(results))}
+	for i, r := range results {
+		row := ImportRowResponse{Row: r.Row, ClientID: r.ClientID, Status: r.Status}
+		switch r.Status {
+		case note.ImportCreated:
+			resp.Created++
+		case note.ImportSkipped:
+			resp.Skipped++
+		case note.ImportFailed:
+			resp.Failed++
+		}
+		if r.NoteID != uuid.Nil {
+			row.NoteID = &r.NoteID
+		}
+		if r.Err != nil {
+			row.Error = r.Err.Error()
+		}
+		resp.Rows[i] = row
+	}
+	return resp
+}
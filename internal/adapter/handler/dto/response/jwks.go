@@ -0,0 +1,56 @@
+package response
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"sort"
+)
+
+// JWKSResponse is a JSON Web Key Set (RFC 7517).
+type JWKSResponse struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK is a public key; N and E are set for RSA keys and Crv and X for
+// Ed25519 keys (RFC 8037).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKSFromKeys returns the key set of keys by key id, sorted by key id.
+// Keys of other types are left out.
+func JWKSFromKeys(keys map[string]crypto.PublicKey) JWKSResponse {
+	resp := JWKSResponse{Keys: make([]JWK, 0, len(keys))}
+	for kid, key := range keys {
+		switch k := key.(type) {
+		case *rsa.PublicKey:
+			resp.Keys = append(resp.Keys, JWK{
+				Kty: "RSA", Kid: kid, Use: "sig", Alg: "RS256",
+				N: base64URL(k.N.Bytes()),
+				E: base64URL(big.NewInt(int64(k.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			resp.Keys = append(resp.Keys, JWK{
+				Kty: "OKP", Kid: kid, Use: "sig", Alg: "EdDSA",
+				Crv: "Ed25519",
+				X:   base64URL(k),
+			})
+		}
+	}
+	sort.Slice(resp.Keys, func(i, j int) bool { return resp.Keys[i].Kid < resp.Keys[j].Kid })
+	return resp
+}
+
+func base64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,53 @@
+package response
+
+import (
+	"time"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+type KPIReportResponse struct {
+	Day                    string    `json:"day"`
+	ActiveUsers            int64     `json:"active_users"`
+	NotesCreated           int64     `json:"notes_created"`
+	Syncs                  int64     `json:"syncs"`
+	SyncFailures           int64     `json:"sync_failures"`
+	SyncSuccessRate        float64   `json:"sync_success_rate"`
+	NotesPushed            int64     `json:"notes_pushed"`
+	Conflicts              int64     `json:"conflicts"`
+	ConflictRate           float64   `json:"conflict_rate"`
+	PhotoUploads           int64     `json:"photo_uploads"`
+	PhotoUploadFailures    int64     `json:"photo_upload_failures"`
+	PhotoUploadFailureRate float64   `json:"photo_upload_failure_rate"`
+	ComputedAt             time.Time `json:"computed_at"`
+}
+
+type KPIReportListResponse struct {
+	Days []KPIReportResponse `json:"days"`
+}
+
+func KPIReportFromEntity(r entity.KPIReport) KPIReportResponse {
+	return KPIReportResponse{
+		Day:                    r.Day.Format("2006-01-02"),
+		ActiveUsers:            r.ActiveUsers,
+		NotesCreated:           r.NotesCreated,
+		Syncs:                  r.Syncs,
+		SyncFailures:           r.SyncFailures,
+		SyncSuccessRate:        r.SyncSuccessRate(),
+		NotesPushed:            r.NotesPushed,
+		Conflicts:              r.Conflicts,
+		ConflictRate:           r.ConflictRate(),
+		PhotoUploads:           r.PhotoUploads,
+		PhotoUploadFailures:    r.PhotoUploadFailures,
+		PhotoUploadFailureRate: r.PhotoUploadFailureRate(),
+		ComputedAt:             r.ComputedAt,
+	}
+}
+
+func KPIReportsFromEntities(reports []entity.KPIReport) KPIReportListResponse {
+	resp := KPIReportListResponse{Days: make([]KPIReportResponse, 0, len(reports))}
+	for _, r := range reports {
+		resp.Days = append(resp.Days, KPIReportFromEntity(r))
+	}
+	return resp
+}
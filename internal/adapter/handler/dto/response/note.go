@@ -6,19 +6,90 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
 	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/note"
 )
 
 type NoteResponse struct {
-	ID        uuid.UUID         `json:"id"`
-	Title     string            `json:"title"`
-	Content   string            `json:"content"`
-	Location  *LocationResponse `json:"location,omitempty"`
-	Photos    []PhotoResponse   `json:"photos"`
-	ClientID  string            `json:"client_id,omitempty"`
-	CreatedAt time.Time         `json:"created_at"`
-	UpdatedAt time.Time         `json:"updated_at"`
-	DeletedAt *time.Time        `json:"deleted_at,omitempty"`
+	ID        uuid.UUID `json:"id"`
+	Number    int64     `json:"number" example:"42"`
+	Reference string    `json:"reference" example:"PLOT-0042"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	// ContentURL serves the full content of notes too large to keep in the
+	// database. ContentTruncated is set when Content is only its excerpt,
+	// as in lists and sync.
+	ContentURL       string            `json:"content_url,omitempty"`
+	ContentTruncated bool              `json:"content_truncated,omitempty"`
+	Location         *LocationResponse `json:"location,omitempty"`
+	// LocationGeneralized is set when the location was snapped to a grid
+	// cell because the note is sensitive and the viewer is not the owner.
+	LocationGeneralized  bool                  `json:"location_generalized,omitempty"`
+	Sensitivity          string                `json:"sensitivity" example:"none"`
+	Source               string                `json:"source" example:"manual"`
+	SourceMeta           map[string]any        `json:"source_meta,omitempty"`
+	Measurements         []MeasurementResponse `json:"measurements"`
+	Tags                 []string              `json:"tags" example:"soil-sample"`
+	Photos               []PhotoResponse       `json:"photos"`
+	Attachments          []AttachmentResponse  `json:"attachments"`
+	ClientID             string                `json:"client_id,omitempty"`
+	CreatedByDevice      string                `json:"created_by_device,omitempty"`
+	LastModifiedByDevice string                `json:"last_modified_by_device,omitempty"`
+	CreatedAt            time.Time             `json:"created_at"`
+	UpdatedAt            time.Time             `json:"updated_at"`
+	DeletedAt            *time.Time            `json:"deleted_at,omitempty"`
+	// MergedInto is the surviving note when this one was deleted by a merge.
+	MergedInto *uuid.UUID `json:"merged_into,omitempty"`
+	// Author is who was using the creating device, when it had one set.
+	Author string `json:"author,omitempty" example:"Ana Silva"`
+	// TeamID is set on notes owned by a team.
+	TeamID   *uuid.UUID        `json:"team_id,omitempty"`
+	Quality  QualityResponse   `json:"quality"`
+	Warnings []WarningResponse `json:"warnings,omitempty"`
+	// DistanceMeters is set on results of a nearby search.
+	DistanceMeters *float64 `json:"distance_m,omitempty" example:"125.4"`
+	// Score orders the results of a text search; higher is better.
+	Score *float64 `json:"score,omitempty" example:"0.82"`
+	// Collaborative is set when the content is edited through the note's
+	// document at /notes/{id}/collaboration.
+	Collaborative bool `json:"collaborative,omitempty"`
+	// Color and Icon are omitted when the note has none.
+	Color string `json:"color,omitempty" example:"green"`
+	Icon  string `json:"icon,omitempty" example:"tree"`
+	// AutoTitled is set while the title is one the server generated from
+	// the content.
+	AutoTitled bool `json:"auto_titled,omitempty"`
+	// FieldUpdatedAt is when the title, content and location last changed,
+	// for clients to send back in sync.
+	FieldUpdatedAt FieldTimesResponse `json:"field_updated_at"`
+}
+
+type FieldTimesResponse struct {
+	Title    time.Time `json:"title"`
+	Content  time.Time `json:"content"`
+	Location time.Time `json:"location"`
+}
+
+type QualityResponse struct {
+	Status    string     `json:"status" example:"failed"`
+	Passed    []string   `json:"passed" example:"min_content_length"`
+	Failed    []string   `json:"failed" example:"required_photo"`
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+}
+
+type WarningResponse struct {
+	Code    string `json:"code" example:"LOW_LOCATION_ACCURACY"`
+	Field   string `json:"field,omitempty" example:"accuracy"`
+	Message string `json:"message"`
+}
+
+// MeasurementResponse is a measurement converted to the requested unit system.
+type MeasurementResponse struct {
+	Name  string  `json:"name" example:"dbh"`
+	Kind  string  `json:"kind" example:"length"`
+	Value float64 `json:"value" example:"0.3175"`
+	Unit  string  `json:"unit" example:"m"`
 }
 
 type LocationResponse struct {
@@ -29,13 +100,50 @@ type LocationResponse struct {
 }
 
 type PhotoResponse struct {
-	ID        uuid.UUID `json:"id"`
-	URL       string    `json:"url"`
-	MimeType  string    `json:"mime_type"`
-	Size      int64     `json:"size"`
-	Width     int       `json:"width,omitempty"`
-	Height    int       `json:"height,omitempty"`
-	CreatedAt time.Time `json:"created_at"`
+	ID       uuid.UUID `json:"id"`
+	URL      string    `json:"url"`
+	MimeType string    `json:"mime_type"`
+	Size     int64     `json:"size"`
+	Width    int       `json:"width,omitempty"`
+	Height   int       `json:"height,omitempty"`
+	Checksum string    `json:"checksum,omitempty"`
+	// SourceChecksum is the SHA-256 of the file as uploaded; clients skip
+	// uploading files whose checksum a photo of the note already has.
+	SourceChecksum string `json:"source_checksum,omitempty"`
+	ClientID       string `json:"client_id,omitempty"`
+	Encryption     string `json:"encryption,omitempty" example:"aws:kms"`
+	// ThumbnailURL is the small thumbnail, for lists; empty when the photo
+	// has no thumbnails.
+	ThumbnailURL string                       `json:"thumbnail_url,omitempty"`
+	Thumbnails   map[string]ThumbnailResponse `json:"thumbnails,omitempty"`
+	// TakenAt and Location come from the photo's EXIF data.
+	TakenAt  *time.Time        `json:"taken_at,omitempty"`
+	Location *LocationResponse `json:"location,omitempty"`
+	// ExcludedFromShares is only ever set for the owner: other users don't
+	// see excluded photos at all.
+	ExcludedFromShares bool      `json:"excluded_from_shares,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// ThumbnailResponse is one downscaled JPEG variant of a photo.
+type ThumbnailResponse struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// AttachmentResponse is a non-image file stored with a note, such as a
+// voice memo.
+type AttachmentResponse struct {
+	ID         uuid.UUID `json:"id"`
+	Kind       string    `json:"kind" example:"audio"`
+	URL        string    `json:"url"`
+	MimeType   string    `json:"mime_type" example:"audio/m4a"`
+	Size       int64     `json:"size"`
+	DurationMS int64     `json:"duration_ms" example:"42000"`
+	Checksum   string    `json:"checksum,omitempty"`
+	ClientID   string    `json:"client_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
 }
 
 type PaginationResponse struct {
@@ -45,6 +153,16 @@ type PaginationResponse struct {
 	TotalPages int  `json:"total_pages"`
 	HasNext    bool `json:"has_next"`
 	HasPrev    bool `json:"has_prev"`
+	// NextCursor continues the list with ?cursor=; empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type NearbyNotesResponse struct {
+	Notes []NoteResponse `json:"notes"`
+}
+
+type SearchNotesResponse struct {
+	Notes []NoteResponse `json:"notes"`
 }
 
 type NotesListResponse struct {
@@ -52,51 +170,257 @@ type NotesListResponse struct {
 	Pagination PaginationResponse `json:"pagination"`
 }
 
-func NoteFromEntity(n *entity.Note) NoteResponse {
+// NoteSummaryResponse is a note as lists and maps show it; clients fetch
+// the note for its content, photos and measurements.
+type NoteSummaryResponse struct {
+	ID       uuid.UUID         `json:"id"`
+	Title    string            `json:"title" example:"Soil sample A"`
+	Excerpt  string            `json:"excerpt" example:"Clay, dark brown"`
+	CoverURL string            `json:"cover_url,omitempty"`
+	Location *LocationResponse `json:"location,omitempty"`
+	// LocationGeneralized is set as on NoteResponse.
+	LocationGeneralized bool       `json:"location_generalized,omitempty"`
+	Tags                []string   `json:"tags" example:"soil-sample"`
+	PhotoCount          int        `json:"photo_count" example:"3"`
+	TeamID              *uuid.UUID `json:"team_id,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+	// Color and Icon are omitted when the note has none.
+	Color string `json:"color,omitempty" example:"green"`
+	Icon  string `json:"icon,omitempty" example:"tree"`
+}
+
+type NoteSummariesResponse struct {
+	Notes      []NoteSummaryResponse `json:"notes"`
+	Pagination PaginationResponse    `json:"pagination"`
+}
+
+// NoteSummaryFromEntity builds the response as seen through the view.
+func NoteSummaryFromEntity(s *entity.NoteSummary, view NoteView) NoteSummaryResponse {
+	resp := NoteSummaryResponse{
+		ID:         s.ID,
+		Title:      s.Title,
+		Excerpt:    s.Excerpt,
+		CoverURL:   s.CoverURL,
+		Tags:       append([]string{}, s.Tags...),
+		PhotoCount: s.PhotoCountFor(view.Viewer),
+		TeamID:     s.TeamID,
+		CreatedAt:  s.CreatedAt,
+		UpdatedAt:  s.UpdatedAt,
+		Color:      s.Color,
+		Icon:       s.Icon,
+	}
+	if loc, generalized := view.Mask.Location(s.AsNote(), view.Viewer); loc != nil {
+		resp.Location = &LocationResponse{Latitude: loc.Latitude, Longitude: loc.Longitude}
+		resp.LocationGeneralized = generalized
+	}
+	return resp
+}
+
+func NoteSummariesFromEntities(summaries []entity.NoteSummary, view NoteView) []NoteSummaryResponse {
+	resp := make([]NoteSummaryResponse, 0, len(summaries))
+	for i := range summaries {
+		resp = append(resp, NoteSummaryFromEntity(&summaries[i], view))
+	}
+	return resp
+}
+
+// NoteView is how notes are presented to the user making the request:
+// measurements in their unit system and, when they are not the owner,
+// sensitive locations generalized by Mask and photos excluded from shares
+// left out. Every note response is built through it, so no output can leak
+// an exact protected location or an excluded photo.
+type NoteView struct {
+	Units  string
+	Viewer uuid.UUID
+	Mask   entity.LocationMask
+}
+
+// NoteFromEntity builds the response as seen through the view.
+func NoteFromEntity(n *entity.Note, view NoteView) NoteResponse {
 	resp := NoteResponse{
-		ID:        n.ID,
-		Title:     n.Title,
-		Content:   n.Content,
-		ClientID:  n.ClientID,
-		Photos:    make([]PhotoResponse, 0, len(n.Photos)),
-		CreatedAt: n.CreatedAt,
-		UpdatedAt: n.UpdatedAt,
-		DeletedAt: n.DeletedAt,
+		ID:                   n.ID,
+		Number:               n.Number,
+		Reference:            n.Reference,
+		Title:                n.Title,
+		Content:              n.Content,
+		ContentURL:           n.ContentURL,
+		ContentTruncated:     n.ContentExcerpt,
+		ClientID:             n.ClientID,
+		CreatedByDevice:      n.CreatedByDevice,
+		LastModifiedByDevice: n.LastModifiedByDevice,
+		Author:               n.Author,
+		Measurements:         make([]MeasurementResponse, 0, len(n.Measurements)),
+		Tags:                 append([]string{}, n.Tags...),
+		Photos:               make([]PhotoResponse, 0, len(n.Photos)),
+		Attachments:          make([]AttachmentResponse, 0, len(n.Attachments)),
+		CreatedAt:            n.CreatedAt,
+		UpdatedAt:            n.UpdatedAt,
+		DeletedAt:            n.DeletedAt,
+		MergedInto:           n.MergedInto,
+		TeamID:               n.TeamID,
+		Quality:              QualityFromResult(n.Quality),
+		Sensitivity:          n.Sensitivity,
+		Source:               n.Source,
+		SourceMeta:           n.SourceMeta,
+		Collaborative:        n.Collaborative,
+		AutoTitled:           n.AutoTitled,
+	}
+	fields := n.FieldTimes()
+	resp.FieldUpdatedAt = FieldTimesResponse{Title: fields.Title, Content: fields.Content, Location: fields.Location}
+	if resp.Sensitivity == "" {
+		resp.Sensitivity = entity.SensitivityNone
+	}
+	if resp.Source == "" {
+		resp.Source = entity.NoteSourceManual
+	}
+	if n.Color != nil {
+		resp.Color = *n.Color
+	}
+	if n.Icon != nil {
+		resp.Icon = *n.Icon
 	}
 
-	if n.Location != nil {
+	if loc, generalized := view.Mask.Location(n, view.Viewer); loc != nil {
 		resp.Location = &LocationResponse{
-			Latitude:  n.Location.Latitude,
-			Longitude: n.Location.Longitude,
-			Altitude:  n.Location.Altitude,
-			Accuracy:  n.Location.Accuracy,
+			Latitude:  loc.Latitude,
+			Longitude: loc.Longitude,
+			Altitude:  loc.Altitude,
+			Accuracy:  loc.Accuracy,
 		}
+		resp.LocationGeneralized = generalized
+	}
+
+	for _, m := range n.Measurements {
+		value, unit := m.In(view.Units)
+		resp.Measurements = append(resp.Measurements, MeasurementResponse{
+			Name:  m.Name,
+			Kind:  m.Kind,
+			Value: value,
+			Unit:  unit,
+		})
 	}
 
 	for _, p := range n.Photos {
-		resp.Photos = append(resp.Photos, PhotoFromEntity(&p))
+		if !p.VisibleTo(n.UserID, view.Viewer) {
+			continue
+		}
+		photo := PhotoFromEntity(&p)
+		// The EXIF position is as exact as the note's own, so it is left
+		// out wherever the note's location is generalized.
+		if n.IsSensitive() && n.UserID != view.Viewer {
+			photo.Location = nil
+		}
+		resp.Photos = append(resp.Photos, photo)
+	}
+
+	for _, a := range n.Attachments {
+		resp.Attachments = append(resp.Attachments, AttachmentFromEntity(&a))
+	}
+
+	for _, w := range n.Warnings {
+		resp.Warnings = append(resp.Warnings, WarningFromValue(w))
 	}
 
 	return resp
 }
 
-func NotesFromEntities(notes []entity.Note) []NoteResponse {
+func WarningFromValue(w valueobject.Warning) WarningResponse {
+	return WarningResponse{
+		Code:    w.Code,
+		Field:   w.Field,
+		Message: w.Message,
+	}
+}
+
+func QualityFromResult(r entity.QualityResult) QualityResponse {
+	resp := QualityResponse{
+		Status:    r.Status,
+		Passed:    r.Passed,
+		Failed:    r.Failed,
+		CheckedAt: r.CheckedAt,
+	}
+	if resp.Status == "" {
+		resp.Status = entity.QualityUnchecked
+	}
+	if resp.Passed == nil {
+		resp.Passed = []string{}
+	}
+	if resp.Failed == nil {
+		resp.Failed = []string{}
+	}
+	return resp
+}
+
+func NotesFromEntities(notes []entity.Note, view NoteView) []NoteResponse {
 	result := make([]NoteResponse, 0, len(notes))
 	for _, n := range notes {
-		result = append(result, NoteFromEntity(&n))
+		result = append(result, NoteFromEntity(&n, view))
+	}
+	return result
+}
+
+func SearchHitsFromEntities(hits []entity.SearchHit, view NoteView) []NoteResponse {
+	result := make([]NoteResponse, 0, len(hits))
+	for _, h := range hits {
+		resp := NoteFromEntity(&h.Note, view)
+		resp.Score = &h.Score
+		resp.DistanceMeters = h.Distance
+		result = append(result, resp)
+	}
+	return result
+}
+
+func NearbyNotesFromEntities(notes []entity.NearbyNote, view NoteView) []NoteResponse {
+	result := make([]NoteResponse, 0, len(notes))
+	for _, n := range notes {
+		resp := NoteFromEntity(&n.Note, view)
+		resp.DistanceMeters = &n.Distance
+		result = append(result, resp)
 	}
 	return result
 }
 
 func PhotoFromEntity(p *entity.Photo) PhotoResponse {
-	return PhotoResponse{
-		ID:        p.ID,
-		URL:       p.URL,
-		MimeType:  p.MimeType,
-		Size:      p.Size,
-		Width:     p.Width,
-		Height:    p.Height,
-		CreatedAt: p.CreatedAt,
+	resp := PhotoResponse{
+		ID:                 p.ID,
+		URL:                p.URL,
+		MimeType:           p.MimeType,
+		Size:               p.Size,
+		Width:              p.Width,
+		Height:             p.Height,
+		Checksum:           p.Checksum,
+		SourceChecksum:     p.SourceChecksum,
+		ClientID:           p.ClientID,
+		Encryption:         p.Encryption,
+		ThumbnailURL:       p.Thumbnails[entity.ThumbnailSmall].URL,
+		TakenAt:            p.TakenAt,
+		ExcludedFromShares: p.ExcludedFromShares,
+		CreatedAt:          p.CreatedAt,
+	}
+	if p.Location != nil {
+		resp.Location = &LocationResponse{Latitude: p.Location.Latitude, Longitude: p.Location.Longitude}
+	}
+	if len(p.Thumbnails) > 0 {
+		resp.Thumbnails = make(map[string]ThumbnailResponse, len(p.Thumbnails))
+		for size, t := range p.Thumbnails {
+			resp.Thumbnails[size] = ThumbnailResponse{URL: t.URL, Width: t.Width, Height: t.Height}
+		}
+	}
+	return resp
+}
+
+func AttachmentFromEntity(a *entity.Attachment) AttachmentResponse {
+	return AttachmentResponse{
+		ID:         a.ID,
+		Kind:       a.Kind,
+		URL:        a.URL,
+		MimeType:   a.MimeType,
+		Size:       a.Size,
+		DurationMS: a.Duration.Milliseconds(),
+		Checksum:   a.Checksum,
+		ClientID:   a.ClientID,
+		CreatedAt:  a.CreatedAt,
 	}
 }
 
@@ -108,5 +432,41 @@ func PaginationFromInfo(info *pagination.Info) PaginationResponse {
 		TotalPages: info.TotalPages,
 		HasNext:    info.HasNext,
 		HasPrev:    info.HasPrev,
+		NextCursor: info.NextCursor,
+	}
+}
+
+// NotesExistResponse lists the notes asked about that exist, deleted ones
+// included, and the IDs and client IDs that match no note.
+type NotesExistResponse struct {
+	Notes            []NoteExistenceResponse `json:"notes"`
+	MissingIDs       []uuid.UUID             `json:"missing_ids"`
+	MissingClientIDs []string                `json:"missing_client_ids"`
+}
+
+// NoteExistenceResponse is a note's version: clients compare updated_at with
+// their copy to decide whether to upload it again.
+type NoteExistenceResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	ClientID  string     `json:"client_id,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+func NotesExistFromResult(r *note.ExistsResult) NotesExistResponse {
+	resp := NotesExistResponse{
+		Notes:            make([]NoteExistenceResponse, len(r.Found)),
+		MissingIDs:       r.MissingIDs,
+		MissingClientIDs: r.MissingClientIDs,
+	}
+	for i, n := range r.Found {
+		resp.Notes[i] = NoteExistenceResponse{ID: n.ID, ClientID: n.ClientID, UpdatedAt: n.UpdatedAt, DeletedAt: n.DeletedAt}
+	}
+	if resp.MissingIDs == nil {
+		resp.MissingIDs = []uuid.UUID{}
 	}
+	if resp.MissingClientIDs == nil {
+		resp.MissingClientIDs = []string{}
+	}
+	return resp
 }
@@ -0,0 +1,19 @@
+package response
+
+import "github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+
+type PreferencesResponse struct {
+	UnitSystem          string `json:"unit_system" example:"metric"`
+	NotePrefix          string `json:"note_prefix" example:"PLOT"`
+	NotifySyncConflicts bool   `json:"notify_sync_conflicts" example:"true"`
+	SemanticSearch      bool   `json:"semantic_search" example:"false"`
+}
+
+func PreferencesFromEntity(u *entity.User) PreferencesResponse {
+	return PreferencesResponse{
+		UnitSystem:          u.UnitSystem,
+		NotePrefix:          u.NotePrefix,
+		NotifySyncConflicts: u.NotifySyncConflicts,
+		SemanticSearch:      u.SemanticSearch,
+	}
+}
@@ -0,0 +1,33 @@
+package response
+
+import "github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+
+type PIIFindingResponse struct {
+	Kind  string `json:"kind" example:"email"`
+	Field string `json:"field" example:"content"`
+	Match string `json:"match,omitempty" example:"jane@example.com"`
+	Start int    `json:"start" example:"42"`
+	End   int    `json:"end" example:"58"`
+}
+
+type LintResponse struct {
+	Clean    bool                 `json:"clean" example:"false"`
+	Findings []PIIFindingResponse `json:"findings"`
+}
+
+func LintFromFindings(findings []entity.PIIFinding) LintResponse {
+	result := LintResponse{
+		Clean:    len(findings) == 0,
+		Findings: make([]PIIFindingResponse, 0, len(findings)),
+	}
+	for _, f := range findings {
+		result.Findings = append(result.Findings, PIIFindingResponse{
+			Kind:  f.Kind,
+			Field: f.Field,
+			Match: f.Match,
+			Start: f.Start,
+			End:   f.End,
+		})
+	}
+	return result
+}
@@ -0,0 +1,64 @@
+package response
+
+import (
+	"time"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+type QualityRulesResponse struct {
+	RequirePhoto     bool           `json:"require_photo"`
+	MinContentLength int            `json:"min_content_length"`
+	Fence            *FenceResponse `json:"fence,omitempty"`
+	MaxAccuracy      *float64       `json:"max_accuracy,omitempty"`
+	UpdatedAt        *time.Time     `json:"updated_at,omitempty"`
+}
+
+type FenceResponse struct {
+	MinLat float64 `json:"min_lat"`
+	MaxLat float64 `json:"max_lat"`
+	MinLng float64 `json:"min_lng"`
+	MaxLng float64 `json:"max_lng"`
+}
+
+type QualityReportResponse struct {
+	Total        int            `json:"total"`
+	Unchecked    int            `json:"unchecked"`
+	Passed       int            `json:"passed"`
+	Failed       int            `json:"failed"`
+	RuleFailures map[string]int `json:"rule_failures"`
+}
+
+func QualityRulesFromEntity(r *entity.QualityRules) QualityRulesResponse {
+	resp := QualityRulesResponse{
+		RequirePhoto:     r.RequirePhoto,
+		MinContentLength: r.MinContentLength,
+		MaxAccuracy:      r.MaxAccuracy,
+	}
+	if r.Fence != nil {
+		resp.Fence = &FenceResponse{
+			MinLat: r.Fence.MinLat,
+			MaxLat: r.Fence.MaxLat,
+			MinLng: r.Fence.MinLng,
+			MaxLng: r.Fence.MaxLng,
+		}
+	}
+	if !r.UpdatedAt.IsZero() {
+		resp.UpdatedAt = &r.UpdatedAt
+	}
+	return resp
+}
+
+func QualityReportFromEntity(r *entity.QualityReport) QualityReportResponse {
+	resp := QualityReportResponse{
+		Total:        r.Total,
+		Unchecked:    r.Unchecked,
+		Passed:       r.Passed,
+		Failed:       r.Failed,
+		RuleFailures: r.RuleFailures,
+	}
+	if resp.RuleFailures == nil {
+		resp.RuleFailures = map[string]int{}
+	}
+	return resp
+}
@@ -0,0 +1,88 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+// Types of the messages sent on the /ws channel.
+const (
+	RealtimeChanges         = "changes"
+	RealtimePing            = "ping"
+	RealtimePresence        = "presence"
+	RealtimePresenceChanged = "presence_changed"
+	RealtimeError           = "error"
+	RealtimeDocument        = "document"
+	RealtimeDocumentUpdate  = "document_update"
+	RealtimeUpdateAck       = "update_ack"
+)
+
+// RealtimeMessage is a message on the /ws channel: changes when the user's
+// notes changed from another device, and a periodic ping that keeps the
+// connection open. For the notes the app opened it also gets presence, the
+// devices that have the note open, on opening and with every ping;
+// presence_changed when one of them opens the note, moves its selection or
+// closes it; and error when a note cannot be opened. For collaborative
+// notes it also gets document on opening, with the seq of the document;
+// document_update with every update another device makes to it; and
+// update_ack with the seq after each update the app sent.
+type RealtimeMessage struct {
+	Type string `json:"type" example:"changes"`
+	// Since is set on changes: the server has changes after this time,
+	// which can be passed as cursor to /sync/changes.
+	Since   *time.Time        `json:"since,omitempty"`
+	NoteID  *uuid.UUID        `json:"note_id,omitempty"`
+	Viewers []RealtimeViewer  `json:"viewers,omitempty"`
+	Viewer  *RealtimeViewer   `json:"viewer,omitempty"`
+	Left    bool              `json:"left,omitempty"`
+	Error   *RealtimeErrorMsg `json:"error,omitempty"`
+	// Seq is set on document and update_ack, Update on document_update.
+	Seq    *int64                  `json:"seq,omitempty"`
+	Update *DocumentUpdateResponse `json:"update,omitempty"`
+}
+
+// RealtimeViewer is a device that has a note open.
+type RealtimeViewer struct {
+	UserID    uuid.UUID          `json:"user_id"`
+	DeviceID  uuid.UUID          `json:"device_id"`
+	Selection *SelectionResponse `json:"selection,omitempty"`
+	SeenAt    time.Time          `json:"seen_at"`
+}
+
+type SelectionResponse struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// RealtimeErrorMsg carries one of the error codes of the HTTP API.
+type RealtimeErrorMsg struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func RealtimeViewerFromEntity(p entity.Presence) RealtimeViewer {
+	v := RealtimeViewer{UserID: p.UserID, DeviceID: p.DeviceID, SeenAt: p.SeenAt}
+	if p.Selection != nil {
+		v.Selection = &SelectionResponse{Start: p.Selection.Start, End: p.Selection.End}
+	}
+	return v
+}
+
+// RealtimePresenceFromEntities is the presence message of a note.
+func RealtimePresenceFromEntities(noteID uuid.UUID, viewers []entity.Presence) RealtimeMessage {
+	msg := RealtimeMessage{Type: RealtimePresence, NoteID: &noteID, Viewers: make([]RealtimeViewer, 0, len(viewers))}
+	for _, p := range viewers {
+		msg.Viewers = append(msg.Viewers, RealtimeViewerFromEntity(p))
+	}
+	return msg
+}
+
+// RealtimePresenceChangedFromEntity is the presence_changed message of an
+// event.
+func RealtimePresenceChangedFromEntity(event entity.PresenceEvent) RealtimeMessage {
+	viewer := RealtimeViewerFromEntity(event.Presence)
+	return RealtimeMessage{Type: RealtimePresenceChanged, NoteID: &event.NoteID, Viewer: &viewer, Left: event.Left}
+}
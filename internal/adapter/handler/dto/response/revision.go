@@ -0,0 +1,81 @@
+package response
+
+import (
+	"time"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+// NoteRevisionResponse is a version of a note that an update replaced.
+type NoteRevisionResponse struct {
+	Revision int    `json:"revision" example:"3"`
+	Title    string `json:"title"`
+	Content  string `json:"content"`
+	// ContentURL and ContentTruncated are as on NoteResponse: offloaded
+	// content is listed as its excerpt.
+	ContentURL          string                `json:"content_url,omitempty"`
+	ContentTruncated    bool                  `json:"content_truncated,omitempty"`
+	Location            *LocationResponse     `json:"location,omitempty"`
+	LocationGeneralized bool                  `json:"location_generalized,omitempty"`
+	Measurements        []MeasurementResponse `json:"measurements"`
+	Color               string                `json:"color,omitempty" example:"green"`
+	Icon                string                `json:"icon,omitempty" example:"tree"`
+	// LastModifiedByDevice is the device that saved this version.
+	LastModifiedByDevice string `json:"last_modified_by_device,omitempty"`
+	// UpdatedAt is when this version was saved and ReplacedAt when the
+	// update that replaced it was.
+	UpdatedAt  time.Time `json:"updated_at"`
+	ReplacedAt time.Time `json:"replaced_at"`
+}
+
+type NoteRevisionsResponse struct {
+	Revisions []NoteRevisionResponse `json:"revisions"`
+}
+
+// NoteRevisionFromEntity builds the response as seen through the view.
+func NoteRevisionFromEntity(r *entity.NoteRevision, view NoteView) NoteRevisionResponse {
+	resp := NoteRevisionResponse{
+		Revision:             r.Revision,
+		Title:                r.Title,
+		Content:              r.Content,
+		ContentURL:           r.ContentURL,
+		ContentTruncated:     r.ContentExcerpt,
+		Measurements:         make([]MeasurementResponse, 0, len(r.Measurements)),
+		LastModifiedByDevice: r.LastModifiedByDevice,
+		UpdatedAt:            r.UpdatedAt,
+		ReplacedAt:           r.ReplacedAt,
+	}
+	if r.Color != nil {
+		resp.Color = *r.Color
+	}
+	if r.Icon != nil {
+		resp.Icon = *r.Icon
+	}
+	if loc, generalized := view.Mask.Location(r.AsNote(), view.Viewer); loc != nil {
+		resp.Location = &LocationResponse{
+			Latitude:  loc.Latitude,
+			Longitude: loc.Longitude,
+			Altitude:  loc.Altitude,
+			Accuracy:  loc.Accuracy,
+		}
+		resp.LocationGeneralized = generalized
+	}
+	for _, m := range r.Measurements {
+		value, unit := m.In(view.Units)
+		resp.Measurements = append(resp.Measurements, MeasurementResponse{
+			Name:  m.Name,
+			Kind:  m.Kind,
+			Value: value,
+			Unit:  unit,
+		})
+	}
+	return resp
+}
+
+func NoteRevisionsFromEntities(revisions []entity.NoteRevision, view NoteView) NoteRevisionsResponse {
+	resp := NoteRevisionsResponse{Revisions: make([]NoteRevisionResponse, 0, len(revisions))}
+	for i := range revisions {
+		resp.Revisions = append(resp.Revisions, NoteRevisionFromEntity(&revisions[i], view))
+	}
+	return resp
+}
@@ -0,0 +1,41 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/share"
+)
+
+type ShareResponse struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Role      string    `json:"role" example:"viewer"`
+	GrantedBy uuid.UUID `json:"granted_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type BulkShareResponse struct {
+	Granted int   `json:"granted" example:"6"`
+	Revoked int64 `json:"revoked" example:"2"`
+}
+
+func SharesFromEntities(shares []entity.NoteShare) []ShareResponse {
+	result := make([]ShareResponse, 0, len(shares))
+	for _, s := range shares {
+		result = append(result, ShareResponse{
+			UserID:    s.UserID,
+			Role:      s.Role,
+			GrantedBy: s.GrantedBy,
+			CreatedAt: s.CreatedAt,
+			UpdatedAt: s.UpdatedAt,
+		})
+	}
+	return result
+}
+
+func BulkShareFromResult(r *share.BulkResult) BulkShareResponse {
+	return BulkShareResponse{Granted: r.Granted, Revoked: r.Revoked}
+}
@@ -0,0 +1,23 @@
+package response
+
+import (
+	"time"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+type UserStatsResponse struct {
+	NoteCount    int64     `json:"note_count" example:"128"`
+	PhotoCount   int64     `json:"photo_count" example:"342"`
+	StorageBytes int64     `json:"storage_bytes" example:"524288000"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func UserStatsFromEntity(s *entity.UserStats) UserStatsResponse {
+	return UserStatsResponse{
+		NoteCount:    s.NoteCount,
+		PhotoCount:   s.PhotoCount,
+		StorageBytes: s.StorageBytes,
+		UpdatedAt:    s.UpdatedAt,
+	}
+}
@@ -3,31 +3,70 @@ package response
 import (
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
 	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/sync"
 )
 
 type SyncResponse struct {
-	ServerNotes []NoteResponse     `json:"server_notes"`
-	NewCursor   time.Time          `json:"new_cursor"`
-	Conflicts   []ConflictResponse `json:"conflicts"`
+	ServerNotes []NoteResponse        `json:"server_notes"`
+	NewCursor   time.Time             `json:"new_cursor"`
+	Cursors     map[string]time.Time  `json:"cursors,omitempty"`
+	Conflicts   []ConflictResponse    `json:"conflicts"`
+	Warnings    []SyncWarningResponse `json:"warnings,omitempty"`
+	Numbers     []NoteNumberResponse  `json:"numbers,omitempty"`
+	Photos      []SyncPhotoResponse   `json:"photos,omitempty"`
+	// HasMore means more server changes remain; sync again with
+	// NextPageToken as page_token until it is false.
+	HasMore       bool   `json:"has_more"`
+	NextPageToken string `json:"next_page_token,omitempty"`
+}
+
+// SyncPhotoResponse is the server's view of a photo the device sent.
+type SyncPhotoResponse struct {
+	ClientID string `json:"client_id"`
+	Status   string `json:"status" enums:"uploaded,pending_upload,deleted,missing_note"`
+	// PendingUpload is true when the device should upload the photo to NoteID.
+	PendingUpload bool           `json:"pending_upload"`
+	NoteID        *uuid.UUID     `json:"note_id,omitempty"`
+	Photo         *PhotoResponse `json:"photo,omitempty"`
+}
+
+// NoteNumberResponse tells the client which number a pushed note was given.
+type NoteNumberResponse struct {
+	ClientID  string `json:"client_id"`
+	Number    int64  `json:"number" example:"42"`
+	Reference string `json:"reference" example:"PLOT-0042"`
+}
+
+type SyncWarningResponse struct {
+	ClientID string `json:"client_id"`
+	WarningResponse
 }
 
 type ConflictResponse struct {
 	ClientID      string        `json:"client_id"`
 	Resolution    string        `json:"resolution"`
 	ServerVersion *NoteResponse `json:"server_version,omitempty"`
+	// CopyID is the note holding the losing version under keep_both.
+	CopyID *uuid.UUID `json:"copy_id,omitempty"`
+	// ConflictID is the conflict held for review under manual.
+	ConflictID *uuid.UUID `json:"conflict_id,omitempty"`
 }
 
-func SyncResultToResponse(result *sync.SyncResult) SyncResponse {
+func SyncResultToResponse(result *sync.SyncResult, view NoteView) SyncResponse {
 	resp := SyncResponse{
-		ServerNotes: make([]NoteResponse, 0, len(result.ServerNotes)),
-		NewCursor:   result.NewCursor,
-		Conflicts:   make([]ConflictResponse, 0, len(result.Conflicts)),
+		ServerNotes:   make([]NoteResponse, 0, len(result.ServerNotes)),
+		NewCursor:     result.NewCursor,
+		Cursors:       result.Cursors,
+		Conflicts:     make([]ConflictResponse, 0, len(result.Conflicts)),
+		HasMore:       result.HasMore,
+		NextPageToken: result.NextPageToken,
 	}
 
 	for _, n := range result.ServerNotes {
-		resp.ServerNotes = append(resp.ServerNotes, NoteFromEntity(&n))
+		resp.ServerNotes = append(resp.ServerNotes, NoteFromEntity(&n, view))
 	}
 
 	for _, c := range result.Conflicts {
@@ -36,19 +75,202 @@ func SyncResultToResponse(result *sync.SyncResult) SyncResponse {
 			Resolution: c.Resolution,
 		}
 		if c.ServerVersion != nil {
-			serverNote := NoteFromEntity(c.ServerVersion)
+			serverNote := NoteFromEntity(c.ServerVersion, view)
 			conflict.ServerVersion = &serverNote
 		}
+		if c.CopyID != uuid.Nil {
+			copyID := c.CopyID
+			conflict.CopyID = &copyID
+		}
+		if c.ConflictID != uuid.Nil {
+			conflictID := c.ConflictID
+			conflict.ConflictID = &conflictID
+		}
 		resp.Conflicts = append(resp.Conflicts, conflict)
 	}
 
+	for _, w := range result.Warnings {
+		resp.Warnings = append(resp.Warnings, SyncWarningResponse{
+			ClientID:        w.ClientID,
+			WarningResponse: WarningFromValue(w.Warning),
+		})
+	}
+
+	for _, n := range result.Numbers {
+		resp.Numbers = append(resp.Numbers, NoteNumberResponse{
+			ClientID:  n.ClientID,
+			Number:    n.Number,
+			Reference: n.Reference,
+		})
+	}
+
+	for _, p := range result.Photos {
+		photo := SyncPhotoResponse{
+			ClientID:      p.ClientID,
+			Status:        p.Status,
+			PendingUpload: p.PendingUpload(),
+		}
+		if p.NoteID != uuid.Nil {
+			noteID := p.NoteID
+			photo.NoteID = &noteID
+		}
+		if p.Photo != nil {
+			stored := PhotoFromEntity(p.Photo)
+			photo.Photo = &stored
+		}
+		resp.Photos = append(resp.Photos, photo)
+	}
+
 	return resp
 }
 
-func SyncNotesFromEntities(notes []entity.Note) []NoteResponse {
+func SyncNotesFromEntities(notes []entity.Note, view NoteView) []NoteResponse {
 	result := make([]NoteResponse, 0, len(notes))
 	for _, n := range notes {
-		result = append(result, NoteFromEntity(&n))
+		result = append(result, NoteFromEntity(&n, view))
 	}
 	return result
 }
+
+type ManifestPhotoResponse struct {
+	PhotoResponse
+	NoteID uuid.UUID `json:"note_id"`
+}
+
+type RemovedPhotoResponse struct {
+	ID        uuid.UUID `json:"id"`
+	NoteID    uuid.UUID `json:"note_id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type PhotoManifestResponse struct {
+	Added      []ManifestPhotoResponse `json:"added"`
+	Removed    []RemovedPhotoResponse  `json:"removed"`
+	NextCursor string                  `json:"next_cursor"`
+	HasMore    bool                    `json:"has_more"`
+}
+
+func PhotoManifestToResponse(m *sync.PhotoManifest) PhotoManifestResponse {
+	resp := PhotoManifestResponse{
+		Added:      make([]ManifestPhotoResponse, 0, len(m.Added)),
+		Removed:    make([]RemovedPhotoResponse, 0, len(m.Removed)),
+		NextCursor: m.NextCursor,
+		HasMore:    m.HasMore,
+	}
+
+	for _, p := range m.Added {
+		resp.Added = append(resp.Added, ManifestPhotoResponse{
+			PhotoResponse: PhotoFromEntity(&p),
+			NoteID:        p.NoteID,
+		})
+	}
+
+	for _, t := range m.Removed {
+		resp.Removed = append(resp.Removed, RemovedPhotoResponse{
+			ID:        t.PhotoID,
+			NoteID:    t.NoteID,
+			DeletedAt: t.DeletedAt,
+		})
+	}
+
+	return resp
+}
+
+type SyncChangesResponse struct {
+	Notes      []NoteResponse `json:"notes"`
+	NextCursor string         `json:"next_cursor"`
+	HasMore    bool           `json:"has_more"`
+}
+
+func SyncChangesToResponse(changes *sync.Changes, view NoteView) SyncChangesResponse {
+	resp := SyncChangesResponse{
+		Notes:      make([]NoteResponse, 0, len(changes.Notes)),
+		NextCursor: changes.NextCursor,
+		HasMore:    changes.HasMore,
+	}
+	for _, n := range changes.Notes {
+		resp.Notes = append(resp.Notes, NoteFromEntity(&n, view))
+	}
+	return resp
+}
+
+// SyncConflictResponse is a client version held by the manual conflict
+// strategy, shown next to the note's current version for review.
+type SyncConflictResponse struct {
+	ID              uuid.UUID    `json:"id"`
+	NoteID          uuid.UUID    `json:"note_id"`
+	ClientID        string       `json:"client_id"`
+	DeviceID        string       `json:"device_id"`
+	ClientVersion   NoteResponse `json:"client_version"`
+	ServerUpdatedAt time.Time    `json:"server_updated_at"`
+	CreatedAt       time.Time    `json:"created_at"`
+	ExpiresAt       time.Time    `json:"expires_at"`
+}
+
+type SyncConflictsResponse struct {
+	Conflicts []SyncConflictResponse `json:"conflicts"`
+}
+
+func SyncConflictsToResponse(conflicts []entity.SyncConflict, view NoteView) SyncConflictsResponse {
+	resp := SyncConflictsResponse{Conflicts: make([]SyncConflictResponse, 0, len(conflicts))}
+	for _, c := range conflicts {
+		resp.Conflicts = append(resp.Conflicts, SyncConflictResponse{
+			ID:              c.ID,
+			NoteID:          c.NoteID,
+			ClientID:        c.ClientID,
+			DeviceID:        c.DeviceID,
+			ClientVersion:   NoteFromEntity(&c.ClientVersion, view),
+			ServerUpdatedAt: c.ServerUpdatedAt,
+			CreatedAt:       c.CreatedAt,
+			ExpiresAt:       c.ExpiresAt,
+		})
+	}
+	return resp
+}
+
+type SyncScopeResponse struct {
+	DeviceID      string     `json:"device_id"`
+	NotesSince    *time.Time `json:"notes_since,omitempty"`
+	ExcludePhotos bool       `json:"exclude_photos"`
+}
+
+func SyncScopeFromDevice(d *entity.Device) SyncScopeResponse {
+	return SyncScopeResponse{
+		DeviceID:      d.DeviceID,
+		NotesSince:    d.Scope.NotesSince,
+		ExcludePhotos: d.Scope.ExcludePhotos,
+	}
+}
+
+// SyncCapabilitiesResponse lists the sync features the server supports.
+type SyncCapabilitiesResponse struct {
+	ProtocolVersion         int      `json:"protocol_version" example:"1"`
+	MaxBatchSize            int      `json:"max_batch_size" example:"1000"`
+	MaxPhotosPerSync        int      `json:"max_photos_per_sync" example:"1000"`
+	MaxChangesLimit         int      `json:"max_changes_limit" example:"1000"`
+	MaxManifestLimit        int      `json:"max_manifest_limit" example:"1000"`
+	ConflictStrategies      []string `json:"conflict_strategies" example:"last_write_wins,keep_both,manual"`
+	DefaultConflictStrategy string   `json:"default_conflict_strategy" example:"last_write_wins"`
+	CursorTypes             []string `json:"cursor_types" example:"timestamp,opaque"`
+	PhotoSync               bool     `json:"photo_sync"`
+	SyncScopes              bool     `json:"sync_scopes"`
+	FieldMerge              bool     `json:"field_merge"`
+	Compression             []string `json:"compression"`
+}
+
+func SyncCapabilitiesToResponse(c sync.Capabilities) SyncCapabilitiesResponse {
+	return SyncCapabilitiesResponse{
+		ProtocolVersion:         c.ProtocolVersion,
+		MaxBatchSize:            c.MaxBatchSize,
+		MaxPhotosPerSync:        c.MaxPhotosPerSync,
+		MaxChangesLimit:         c.MaxChangesLimit,
+		MaxManifestLimit:        c.MaxManifestLimit,
+		ConflictStrategies:      c.ConflictStrategies,
+		DefaultConflictStrategy: c.DefaultConflictStrategy,
+		CursorTypes:             c.CursorTypes,
+		PhotoSync:               c.PhotoSync,
+		SyncScopes:              c.SyncScopes,
+		FieldMerge:              c.FieldMerge,
+		Compression:             c.Compression,
+	}
+}
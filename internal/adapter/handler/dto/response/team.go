@@ -0,0 +1,67 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+// TeamResponse is a team with the requesting user's role in it. Pending is
+// set while the user has not accepted the invitation.
+type TeamResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name" example:"River survey 2026"`
+	Role      string    `json:"role" example:"editor"`
+	Pending   bool      `json:"pending"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TeamMemberResponse struct {
+	UserID     uuid.UUID  `json:"user_id"`
+	Email      string     `json:"email" example:"colleague@example.com"`
+	Role       string     `json:"role" example:"editor"`
+	Pending    bool       `json:"pending"`
+	InvitedBy  *uuid.UUID `json:"invited_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+}
+
+func TeamFromEntity(t *entity.UserTeam) TeamResponse {
+	return TeamResponse{
+		ID:        t.ID,
+		Name:      t.Name,
+		Role:      t.Membership.Role,
+		Pending:   t.Membership.Pending(),
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+func TeamsFromEntities(teams []entity.UserTeam) []TeamResponse {
+	result := make([]TeamResponse, 0, len(teams))
+	for i := range teams {
+		result = append(result, TeamFromEntity(&teams[i]))
+	}
+	return result
+}
+
+func TeamMemberFromEntity(m *entity.TeamMember) TeamMemberResponse {
+	return TeamMemberResponse{
+		UserID:     m.UserID,
+		Email:      m.Email,
+		Role:       m.Role,
+		Pending:    m.Pending(),
+		InvitedBy:  m.InvitedBy,
+		CreatedAt:  m.CreatedAt,
+		AcceptedAt: m.AcceptedAt,
+	}
+}
+
+func TeamMembersFromEntities(members []entity.TeamMember) []TeamMemberResponse {
+	result := make([]TeamMemberResponse, 0, len(members))
+	for i := range members {
+		result = append(result, TeamMemberFromEntity(&members[i]))
+	}
+	return result
+}
@@ -8,12 +8,61 @@ type UploadResponse struct {
 	Photo     PhotoResponse `json:"photo"`
 	URL       string        `json:"url"`
 	SignedURL string        `json:"signed_url,omitempty"`
+	// NoteLocationSet is true when the note took the photo's EXIF position.
+	NoteLocationSet bool `json:"note_location_set,omitempty"`
+	// Duplicate is true when the file was already uploaded to the note; the
+	// photo is the stored one.
+	Duplicate bool `json:"duplicate,omitempty"`
 }
 
 func UploadResultToResponse(result *upload.UploadResult) UploadResponse {
 	return UploadResponse{
-		Photo:     PhotoFromEntity(result.Photo),
-		URL:       result.URL,
-		SignedURL: result.SignedURL,
+		Photo:           PhotoFromEntity(result.Photo),
+		URL:             result.URL,
+		SignedURL:       result.SignedURL,
+		NoteLocationSet: result.NoteLocationSet,
+		Duplicate:       result.Duplicate,
+	}
+}
+
+// Statuses of the files of a batch upload.
+const (
+	BatchFileUploaded = "uploaded"
+	BatchFileFailed   = "failed"
+)
+
+// BatchUploadResponse has one result per file, in the order the files were
+// sent.
+type BatchUploadResponse struct {
+	Results  []BatchFileResponse `json:"results"`
+	Uploaded int                 `json:"uploaded"`
+	Failed   int                 `json:"failed"`
+}
+
+type BatchFileResponse struct {
+	Filename string `json:"filename"`
+	ClientID string `json:"client_id,omitempty"`
+	Status   string `json:"status" example:"uploaded" enums:"uploaded,failed"`
+	// Upload is set for uploaded files, Error for failed ones.
+	Upload *UploadResponse `json:"upload,omitempty"`
+	Error  *BatchFileError `json:"error,omitempty"`
+}
+
+// BatchFileError is why a file of a batch was not stored, with a code from
+// the error catalog.
+type BatchFileError struct {
+	Code    string `json:"code" example:"INVALID_TYPE"`
+	Message string `json:"message"`
+}
+
+type AttachmentUploadResponse struct {
+	Attachment AttachmentResponse `json:"attachment"`
+	SignedURL  string             `json:"signed_url,omitempty"`
+}
+
+func AttachmentResultToResponse(result *upload.AttachmentResult) AttachmentUploadResponse {
+	return AttachmentUploadResponse{
+		Attachment: AttachmentFromEntity(result.Attachment),
+		SignedURL:  result.SignedURL,
 	}
 }
@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+type ErrorCatalogHandler struct{}
+
+func NewErrorCatalogHandler() *ErrorCatalogHandler {
+	return &ErrorCatalogHandler{}
+}
+
+// List godoc
+//
+//	@Summary		List error codes
+//	@Description	Machine-readable catalog of every error code the API returns, with its HTTP status
+//	@Tags			meta
+//	@Produce		json
+//	@Success		200	{object}	response.ErrorCatalogResponse
+//	@Router			/errors [get]
+func (h *ErrorCatalogHandler) List(c *gin.Context) {
+	httputil.OK(c, response.ErrorCatalogFromInfo(httputil.ErrorCatalog()))
+}
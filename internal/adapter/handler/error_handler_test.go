@@ -0,0 +1,50 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+func TestErrorCatalogHandler_List(t *testing.T) {
+	t.Run("returns every error code once", func(t *testing.T) {
+		h := handler.NewErrorCatalogHandler()
+
+		router := setupRouter()
+		router.GET("/errors", h.List)
+
+		req := httptest.NewRequest(http.MethodGet, "/errors", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp response.ErrorCatalogResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Len(t, resp.Errors, len(httputil.ErrorCatalog()))
+
+		seen := make(map[string]bool)
+		for _, e := range resp.Errors {
+			assert.False(t, seen[e.Code], "duplicate code %s", e.Code)
+			seen[e.Code] = true
+			assert.GreaterOrEqual(t, e.Status, 400)
+			assert.NotEmpty(t, e.Description)
+		}
+
+		for _, code := range []string{
+			httputil.CodeValidationError, httputil.CodeInternalError, httputil.CodeNotFound,
+			httputil.CodeDeviceNotFound, httputil.CodeRateLimited, httputil.CodeSSORequired,
+		} {
+			assert.True(t, seen[code], "missing code %s", code)
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+)
+
+// HealthHandler serves the orchestrator probes. Liveness only tells that the
+// process answers; readiness checks the dependencies so traffic is held back
+// while one of them is unreachable.
+type HealthHandler struct {
+	checker HealthChecker
+}
+
+func NewHealthHandler(checker HealthChecker) *HealthHandler {
+	return &HealthHandler{checker: checker}
+}
+
+// Live godoc
+//
+//	@Summary		Liveness probe
+//	@Description	Reports that the process is up, without checking its dependencies
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	map[string]string
+//	@Router			/health/live [get]
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Ready godoc
+//
+//	@Summary		Readiness probe
+//	@Description	Checks Postgres, Redis and S3 and reports the status of each; 503 when any is down
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	response.ReadinessResponse
+//	@Failure		503	{object}	response.ReadinessResponse
+//	@Router			/health/ready [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	report := h.checker.Check(c.Request.Context())
+
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	// The probe is unauthenticated, so the reasons go to the request log
+	// rather than the response.
+	for _, s := range report.Checks {
+		if !s.Up() {
+			_ = c.Error(fmt.Errorf("%s: %w", s.Name, s.Err))
+		}
+	}
+	c.JSON(status, response.ReadinessFromReport(report))
+}
@@ -0,0 +1,77 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/health"
+)
+
+func TestHealthHandler_Ready(t *testing.T) {
+	t.Run("returns 200 when every dependency is up", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		checker := mocks.NewMockHealthChecker(ctrl)
+		h := handler.NewHealthHandler(checker)
+
+		router := setupRouter()
+		router.GET("/health/ready", h.Ready)
+
+		checker.EXPECT().Check(gomock.Any()).Return(health.Report{Checks: []health.Status{
+			{Name: "postgres", Latency: 3 * time.Millisecond},
+			{Name: "s3", Latency: 40 * time.Millisecond},
+		}})
+
+		req := httptest.NewRequest(http.MethodGet, "/health/ready", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp response.ReadinessResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "ready", resp.Status)
+		assert.Equal(t, response.HealthCheckResponse{Status: "up", LatencyMS: 40}, resp.Checks["s3"])
+	})
+
+	t.Run("returns 503 with the dependency that is down", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		checker := mocks.NewMockHealthChecker(ctrl)
+		h := handler.NewHealthHandler(checker)
+
+		router := setupRouter()
+		router.GET("/health/ready", h.Ready)
+
+		checker.EXPECT().Check(gomock.Any()).Return(health.Report{Checks: []health.Status{
+			{Name: "postgres"},
+			{Name: "redis", Err: errors.New("dial tcp 10.0.0.5:6379: connection refused")},
+		}})
+
+		req := httptest.NewRequest(http.MethodGet, "/health/ready", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		var resp response.ReadinessResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "unavailable", resp.Status)
+		assert.Equal(t, "up", resp.Checks["postgres"].Status)
+		assert.Equal(t, "down", resp.Checks["redis"].Status)
+		assert.NotContains(t, w.Body.String(), "10.0.0.5")
+	})
+}
@@ -2,15 +2,30 @@ package handler
 
 import (
 	"context"
+	"crypto"
+	"io"
 
 	"github.com/google/uuid"
 
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
 	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/anomaly"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/audit"
 	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/auth"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/demo"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/health"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/jobs"
 	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/note"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/preference"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/quality"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/realtime"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/semantic"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/share"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/summary"
 	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/sync"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/team"
 	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/upload"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/usage"
 )
 
 //go:generate mockgen -source=interfaces.go -destination=../../mocks/handler_mocks.go -package=mocks
@@ -18,23 +33,172 @@ import (
 type AuthService interface {
 	Register(ctx context.Context, input auth.RegisterInput) (*entity.User, error)
 	Login(ctx context.Context, input auth.LoginInput) (*auth.TokenPair, *entity.User, error)
-	Refresh(ctx context.Context, refreshToken string) (*auth.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken, ip string) (*auth.TokenPair, error)
 	Logout(ctx context.Context, userID uuid.UUID) error
+	Sessions(ctx context.Context, userID uuid.UUID) ([]entity.Device, error)
+	RevokeOtherSessions(ctx context.Context, userID, sessionID uuid.UUID) (int, error)
+	StartSSO(ctx context.Context, input auth.SSOStartInput) (string, error)
+	CompleteSSO(ctx context.Context, input auth.SSOCallbackInput) (*auth.TokenPair, *entity.User, error)
+	SocialLogin(ctx context.Context, input auth.SocialLoginInput) (*auth.TokenPair, *entity.User, error)
+	UpdateSSOSettings(ctx context.Context, input auth.SSOSettingsInput) (*entity.Organization, error)
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, input auth.ResetPasswordInput) error
+}
+
+type NoteSummaryService interface {
+	List(ctx context.Context, input summary.ListInput) ([]entity.NoteSummary, *pagination.Info, error)
 }
 
 type NoteService interface {
 	Create(ctx context.Context, input note.CreateInput) (*entity.Note, error)
 	List(ctx context.Context, input note.ListInput) ([]entity.Note, *pagination.Info, error)
+	Nearby(ctx context.Context, input note.NearbyInput) ([]entity.NearbyNote, error)
+	Search(ctx context.Context, input note.SearchInput) ([]entity.SearchHit, error)
+	Export(ctx context.Context, input note.ExportInput, fn func([]entity.Note) error) error
+	Coverage(ctx context.Context, userID uuid.UUID, zoom int) (*entity.NoteCoverage, error)
+	Import(ctx context.Context, input note.ImportInput) ([]note.ImportResult, error)
 	GetByID(ctx context.Context, userID, noteID uuid.UUID) (*entity.Note, error)
 	Update(ctx context.Context, userID, noteID uuid.UUID, input note.UpdateInput) (*entity.Note, error)
 	Delete(ctx context.Context, userID, noteID uuid.UUID) error
+	Restore(ctx context.Context, userID, noteID uuid.UUID) (*entity.Note, error)
+	Merge(ctx context.Context, input note.MergeInput) (*entity.Note, error)
+	Exists(ctx context.Context, input note.ExistsInput) (*note.ExistsResult, error)
+	AddTags(ctx context.Context, input note.TagsInput) (*entity.Note, error)
+	RemoveTags(ctx context.Context, input note.TagsInput) (*entity.Note, error)
+	Revisions(ctx context.Context, input note.RevisionsInput) ([]entity.NoteRevision, error)
+	RestoreRevision(ctx context.Context, input note.RestoreRevisionInput) (*entity.Note, error)
 }
 
 type SyncService interface {
 	BatchSync(ctx context.Context, input sync.SyncInput) (*sync.SyncResult, error)
+	PhotoManifest(ctx context.Context, input sync.PhotoManifestInput) (*sync.PhotoManifest, error)
+	Changes(ctx context.Context, input sync.ChangesInput) (*sync.Changes, error)
+	UpdateScope(ctx context.Context, input sync.ScopeInput) (*entity.Device, error)
+	SetActiveAuthor(ctx context.Context, input sync.AuthorInput) (*entity.Device, error)
+	ClearActiveAuthor(ctx context.Context, userID uuid.UUID, deviceID string) (*entity.Device, error)
+	SetPushToken(ctx context.Context, input sync.PushTokenInput) error
+	ClearPushToken(ctx context.Context, userID uuid.UUID, deviceID string) error
+	Capabilities() sync.Capabilities
+	ListConflicts(ctx context.Context, userID uuid.UUID) ([]entity.SyncConflict, error)
+	ReplayConflict(ctx context.Context, input sync.ReplayInput) (*entity.Note, error)
 }
 
 type UploadService interface {
 	Upload(ctx context.Context, input upload.UploadInput) (*upload.UploadResult, error)
+	UploadBatch(ctx context.Context, input upload.BatchUploadInput) ([]upload.BatchResult, error)
 	Delete(ctx context.Context, userID, photoID uuid.UUID) error
+	SetShareExclusion(ctx context.Context, userID, photoID uuid.UUID, excluded bool) (*entity.Photo, error)
+	UploadAudio(ctx context.Context, input upload.AudioUploadInput) (*upload.AttachmentResult, error)
+	DeleteAttachment(ctx context.Context, userID, attachmentID uuid.UUID) error
+}
+
+type UsageService interface {
+	GetDeviceUsage(ctx context.Context, input usage.DeviceUsageInput) ([]entity.DeviceUsage, error)
+}
+
+type AccountService interface {
+	Delete(ctx context.Context, userID uuid.UUID) (*entity.AccountDeletion, error)
+}
+
+type StatsService interface {
+	Get(ctx context.Context, userID uuid.UUID) (*entity.UserStats, error)
+}
+
+type AlertService interface {
+	ListAlerts(ctx context.Context, input anomaly.ListInput) ([]entity.SecurityAlert, *pagination.Info, error)
+}
+
+type AuditService interface {
+	List(ctx context.Context, input audit.ListInput) ([]entity.AuditEvent, *pagination.Info, error)
+}
+
+type ClientService interface {
+	Adoption(ctx context.Context, days int) ([]entity.ClientAdoption, error)
+}
+
+type DeprecationService interface {
+	Report(ctx context.Context, days int) ([]entity.DeprecationReport, error)
+}
+
+type KPIService interface {
+	Reports(ctx context.Context, days int) ([]entity.KPIReport, error)
+}
+
+type QualityService interface {
+	GetRules(ctx context.Context, userID uuid.UUID) (*entity.QualityRules, error)
+	UpdateRules(ctx context.Context, input quality.RulesInput) (*entity.QualityRules, error)
+	Report(ctx context.Context, userID uuid.UUID) (*entity.QualityReport, error)
+}
+
+type ShareService interface {
+	Bulk(ctx context.Context, input share.BulkInput) (*share.BulkResult, error)
+	List(ctx context.Context, userID, noteID uuid.UUID) ([]entity.NoteShare, error)
+}
+
+type TeamService interface {
+	Create(ctx context.Context, userID uuid.UUID, name string) (*entity.UserTeam, error)
+	List(ctx context.Context, userID uuid.UUID) ([]entity.UserTeam, error)
+	Members(ctx context.Context, userID, teamID uuid.UUID) ([]entity.TeamMember, error)
+	Invite(ctx context.Context, input team.InviteInput) (*entity.TeamMember, error)
+	Accept(ctx context.Context, userID, teamID uuid.UUID) error
+	UpdateRole(ctx context.Context, input team.RoleInput) (*entity.TeamMember, error)
+	RemoveMember(ctx context.Context, userID, teamID, memberID uuid.UUID) error
+}
+
+type PreferenceService interface {
+	Get(ctx context.Context, userID uuid.UUID) (*entity.User, error)
+	UnitSystem(ctx context.Context, userID uuid.UUID) (string, error)
+	Update(ctx context.Context, input preference.UpdateInput) (*entity.User, error)
+}
+
+type DemoService interface {
+	Credentials() demo.Credentials
+}
+
+type PrivacyService interface {
+	Lint(ctx context.Context, userID, noteID uuid.UUID) ([]entity.PIIFinding, error)
+}
+
+type DocumentService interface {
+	Enable(ctx context.Context, userID, noteID uuid.UUID) (*entity.NoteDocument, error)
+	Disable(ctx context.Context, userID, noteID uuid.UUID) error
+	Get(ctx context.Context, userID, noteID uuid.UUID) (*entity.NoteDocument, error)
+	Apply(ctx context.Context, input realtime.ApplyInput) (int64, error)
+	Watch(noteID uuid.UUID) (<-chan entity.DocumentUpdate, func())
+}
+
+type SemanticService interface {
+	Search(ctx context.Context, input semantic.SearchInput) ([]entity.SearchHit, error)
+	Related(ctx context.Context, input semantic.RelatedInput) ([]entity.SearchHit, error)
+}
+
+type HealthChecker interface {
+	Check(ctx context.Context) health.Report
+}
+
+// KeySet is the public keys access tokens can be verified with, by key id.
+type KeySet interface {
+	PublicKeys() map[string]crypto.PublicKey
+}
+
+type ChangeSubscriber interface {
+	Subscribe(userID, deviceID uuid.UUID) (<-chan entity.ChangeEvent, func())
+}
+
+type PresenceService interface {
+	Open(ctx context.Context, presence entity.Presence) ([]entity.Presence, error)
+	Move(ctx context.Context, presence entity.Presence) error
+	Keep(ctx context.Context, presence entity.Presence) ([]entity.Presence, error)
+	Close(ctx context.Context, presence entity.Presence) error
+	Watch(noteID uuid.UUID) (<-chan entity.PresenceEvent, func())
+}
+
+type MetricsWriter interface {
+	WritePrometheus(w io.Writer) error
+}
+
+type JobScheduler interface {
+	Jobs() []jobs.Status
+	History() []jobs.Run
+	RunNow(name string) error
 }
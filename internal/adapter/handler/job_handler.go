@@ -0,0 +1,62 @@
+package handler
+
+import (
+	_ "embed"
+	"errors"
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/jobs"
+)
+
+//go:embed templates/jobs.html
+var jobsPageSource string
+
+var jobsPage = template.Must(template.New("jobs").Parse(jobsPageSource))
+
+// JobHandler serves the operator dashboard of the background jobs. It renders
+// HTML for browsers rather than JSON and is not part of the public API.
+type JobHandler struct {
+	scheduler JobScheduler
+}
+
+func NewJobHandler(scheduler JobScheduler) *JobHandler {
+	return &JobHandler{scheduler: scheduler}
+}
+
+type jobsPageData struct {
+	Jobs    []jobs.Status
+	History []jobs.Run
+}
+
+// Dashboard shows every job with its last result and the recent run history.
+func (h *JobHandler) Dashboard(c *gin.Context) {
+	c.Render(http.StatusOK, render.HTML{
+		Template: jobsPage,
+		Data: jobsPageData{
+			Jobs:    h.scheduler.Jobs(),
+			History: h.scheduler.History(),
+		},
+	})
+}
+
+// Run starts the job outside its schedule and sends the browser back to the
+// dashboard. A job that is already running is left alone.
+func (h *JobHandler) Run(c *gin.Context) {
+	err := h.scheduler.RunNow(c.Param("name"))
+	if err != nil && !errors.Is(err, domain.ErrJobRunning) {
+		if errors.Is(err, domain.ErrJobNotFound) {
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "job not found")
+			return
+		}
+		httputil.InternalError(c)
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, "/admin/jobs")
+}
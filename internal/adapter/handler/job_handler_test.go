@@ -0,0 +1,91 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/jobs"
+)
+
+func TestJobHandler_Dashboard(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scheduler := mocks.NewMockJobScheduler(ctrl)
+	h := handler.NewJobHandler(scheduler)
+
+	router := setupRouter()
+	router.GET("/admin/jobs", h.Dashboard)
+
+	started := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	failed := jobs.Run{Job: "stats-reconcile", StartedAt: started, FinishedAt: started.Add(time.Second), Err: "connection <refused>"}
+	scheduler.EXPECT().Jobs().Return([]jobs.Status{
+		{Name: "stats-reconcile", Interval: 24 * time.Hour, LastRun: &failed, Failures: 1},
+		{Name: "usage-flush", Interval: 30 * time.Second},
+	})
+	scheduler.EXPECT().History().Return([]jobs.Run{failed})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/jobs", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	body := w.Body.String()
+	assert.Contains(t, body, "usage-flush")
+	assert.Contains(t, body, `action="/admin/jobs/stats-reconcile/run"`)
+	assert.Contains(t, body, "Retry")
+	assert.Contains(t, body, "connection &lt;refused&gt;")
+}
+
+func TestJobHandler_Run(t *testing.T) {
+	t.Run("runs job and redirects to dashboard", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		scheduler := mocks.NewMockJobScheduler(ctrl)
+		h := handler.NewJobHandler(scheduler)
+
+		router := setupRouter()
+		router.POST("/admin/jobs/:name/run", h.Run)
+
+		scheduler.EXPECT().RunNow("usage-flush").Return(nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/jobs/usage-flush/run", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/admin/jobs", w.Header().Get("Location"))
+	})
+
+	t.Run("returns not found for unknown job", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		scheduler := mocks.NewMockJobScheduler(ctrl)
+		h := handler.NewJobHandler(scheduler)
+
+		router := setupRouter()
+		router.POST("/admin/jobs/:name/run", h.Run)
+
+		scheduler.EXPECT().RunNow("missing").Return(domain.ErrJobNotFound)
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/jobs/missing/run", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
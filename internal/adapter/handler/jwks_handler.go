@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+)
+
+// jwksMaxAge is how long verifiers may cache the key set. It should stay
+// well below the time between adding a key and signing with it.
+const jwksMaxAge = "public, max-age=300"
+
+// JWKSHandler publishes the public keys of the asymmetric signing keys, so
+// other services can verify access tokens without the HMAC secrets.
+type JWKSHandler struct {
+	keys KeySet
+}
+
+func NewJWKSHandler(keys KeySet) *JWKSHandler {
+	return &JWKSHandler{keys: keys}
+}
+
+// Keys godoc
+//
+//	@Summary		JSON Web Key Set
+//	@Description	Lists the public keys access tokens are signed with, by kid. Tokens signed with an HMAC secret cannot be verified with it
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	{object}	response.JWKSResponse
+//	@Router			/.well-known/jwks.json [get]
+func (h *JWKSHandler) Keys(c *gin.Context) {
+	c.Header("Cache-Control", jwksMaxAge)
+	c.JSON(http.StatusOK, response.JWKSFromKeys(h.keys.PublicKeys()))
+}
@@ -0,0 +1,54 @@
+package handler_test
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+)
+
+func TestJWKSHandler_Keys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	edPublic, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	keys := mocks.NewMockKeySet(ctrl)
+	keys.EXPECT().PublicKeys().Return(map[string]crypto.PublicKey{"r1": &rsaKey.PublicKey, "e1": edPublic})
+	h := handler.NewJWKSHandler(keys)
+
+	router := setupRouter()
+	router.GET("/.well-known/jwks.json", h.Keys)
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Cache-Control"), "max-age")
+	var resp response.JWKSResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Keys, 2)
+	assert.Equal(t, response.JWK{Kty: "OKP", Kid: "e1", Use: "sig", Alg: "EdDSA", Crv: "Ed25519", X: resp.Keys[0].X}, resp.Keys[0])
+	assert.Len(t, resp.Keys[0].X, 43)
+	assert.Equal(t, "RSA", resp.Keys[1].Kty)
+	assert.Equal(t, "RS256", resp.Keys[1].Alg)
+	assert.Equal(t, "AQAB", resp.Keys[1].E)
+	assert.Len(t, resp.Keys[1].N, 342)
+}
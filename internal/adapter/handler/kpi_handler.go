@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/request"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+const defaultKPIDays = 7
+
+// KPIHandler serves the business KPIs computed by the kpi-compute job to
+// product dashboards, as JSON and in the Prometheus text format. Like the
+// jobs dashboard it is not part of the public API.
+type KPIHandler struct {
+	kpiSvc KPIService
+}
+
+func NewKPIHandler(kpiSvc KPIService) *KPIHandler {
+	return &KPIHandler{kpiSvc: kpiSvc}
+}
+
+// Reports lists the KPIs of the last days days, oldest first.
+func (h *KPIHandler) Reports(c *gin.Context) {
+	var req request.KPIReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+	if req.Days == 0 {
+		req.Days = defaultKPIDays
+	}
+
+	reports, err := h.kpiSvc.Reports(c.Request.Context(), req.Days)
+	if err != nil {
+		httputil.InternalError(c)
+		return
+	}
+	httputil.OK(c, response.KPIReportsFromEntities(reports))
+}
+
+// Metrics exposes today's KPIs as Prometheus gauges. Before the first
+// computation of the day there is nothing to expose.
+func (h *KPIHandler) Metrics(c *gin.Context) {
+	reports, err := h.kpiSvc.Reports(c.Request.Context(), 1)
+	if err != nil {
+		httputil.InternalError(c)
+		return
+	}
+
+	var b strings.Builder
+	for _, r := range reports {
+		writeGauge(&b, "active_users", "Distinct users active today (UTC).", float64(r.ActiveUsers))
+		writeGauge(&b, "notes_created", "Notes created today.", float64(r.NotesCreated))
+		writeGauge(&b, "syncs", "Sync requests today.", float64(r.Syncs))
+		writeGauge(&b, "sync_success_rate", "Fraction of today's syncs that succeeded.", r.SyncSuccessRate())
+		writeGauge(&b, "conflict_rate", "Fraction of the notes pushed today that conflicted.", r.ConflictRate())
+		writeGauge(&b, "photo_uploads", "Photo uploads today.", float64(r.PhotoUploads))
+		writeGauge(&b, "photo_upload_failure_rate", "Fraction of today's photo uploads that failed.", r.PhotoUploadFailureRate())
+		writeGauge(&b, "computed_timestamp_seconds", "When the KPIs were last computed.", float64(r.ComputedAt.Unix()))
+	}
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(b.String()))
+}
+
+func writeGauge(b *strings.Builder, name, help string, value float64) {
+	name = "fieldnotes_kpi_" + name
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s gauge\n%s %s\n", name, help, name, name, strconv.FormatFloat(value, 'f', -1, 64))
+}
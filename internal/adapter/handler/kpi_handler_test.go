@@ -0,0 +1,100 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+)
+
+func TestKPIHandler_Reports(t *testing.T) {
+	t.Run("lists the last week by default", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		kpiSvc := mocks.NewMockKPIService(ctrl)
+		h := handler.NewKPIHandler(kpiSvc)
+
+		router := setupRouter()
+		router.GET("/admin/kpis", h.Reports)
+
+		kpiSvc.EXPECT().Reports(gomock.Any(), 7).Return([]entity.KPIReport{{
+			Day:                 time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+			ActiveUsers:         42,
+			PhotoUploads:        50,
+			PhotoUploadFailures: 5,
+		}}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/kpis", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp response.KPIReportListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Days, 1)
+		assert.Equal(t, "2026-06-01", resp.Days[0].Day)
+		assert.Equal(t, int64(42), resp.Days[0].ActiveUsers)
+		assert.InDelta(t, 0.1, resp.Days[0].PhotoUploadFailureRate, 1e-9)
+		assert.Equal(t, float64(1), resp.Days[0].SyncSuccessRate)
+	})
+
+	t.Run("rejects an out of range period", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		h := handler.NewKPIHandler(mocks.NewMockKPIService(ctrl))
+
+		router := setupRouter()
+		router.GET("/admin/kpis", h.Reports)
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/kpis?days=400", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestKPIHandler_Metrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	kpiSvc := mocks.NewMockKPIService(ctrl)
+	h := handler.NewKPIHandler(kpiSvc)
+
+	router := setupRouter()
+	router.GET("/admin/kpis/metrics", h.Metrics)
+
+	kpiSvc.EXPECT().Reports(gomock.Any(), 1).Return([]entity.KPIReport{{
+		Day:          time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+		ActiveUsers:  1234567,
+		Syncs:        4,
+		SyncFailures: 1,
+		ComputedAt:   time.Unix(1780300800, 0),
+	}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/kpis/metrics", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/plain; version=0.0.4")
+	body := w.Body.String()
+	assert.Contains(t, body, "# TYPE fieldnotes_kpi_active_users gauge\nfieldnotes_kpi_active_users 1234567\n")
+	assert.Contains(t, body, "fieldnotes_kpi_sync_success_rate 0.75\n")
+	assert.Contains(t, body, "fieldnotes_kpi_computed_timestamp_seconds 1780300800\n")
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/request"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/qrcode"
+)
+
+// Defaults of the QR code endpoint.
+const (
+	defaultQRCodeSize  = 256
+	defaultQRCodeLevel = "M"
+)
+
+// LabelHandler serves QR codes for labels that tie physical samples to
+// their note.
+type LabelHandler struct {
+	noteSvc NoteService
+	// linkURL is the deep link a QR code encodes, with an {id} placeholder
+	// for the note ID.
+	linkURL string
+}
+
+func NewLabelHandler(noteSvc NoteService, linkURL string) *LabelHandler {
+	return &LabelHandler{noteSvc: noteSvc, linkURL: linkURL}
+}
+
+// QRCode godoc
+//
+//	@Summary		Note QR code
+//	@Description	Render a QR code of the deep link to the note, for printing on sample labels. Only users who can read the note can get it; scanning it still requires access to the note.
+//	@Tags			notes
+//	@Security		BearerAuth
+//	@Produce		image/png,image/svg+xml
+//	@Param			id		path		string	true	"Note ID"	format(uuid)
+//	@Param			format	query		string	false	"Image format"	Enums(png, svg)	default(png)
+//	@Param			size	query		int		false	"Width and height in pixels, 64 to 2048"	default(256)
+//	@Param			level	query		string	false	"Error correction level"	Enums(L, M, Q, H)	default(M)
+//	@Success		200		{file}		file
+//	@Failure		400		{object}	httputil.ErrorResponse
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Failure		403		{object}	httputil.ErrorResponse
+//	@Failure		404		{object}	httputil.ErrorResponse
+//	@Router			/notes/{id}/qrcode [get]
+func (h *LabelHandler) QRCode(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidID, "invalid note id")
+		return
+	}
+
+	req := request.QRCodeRequest{Size: defaultQRCodeSize, Level: defaultQRCodeLevel}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+	level, _ := qrcode.ParseLevel(req.Level)
+
+	n, err := h.noteSvc.GetByID(c.Request.Context(), httputil.GetUserID(c), noteID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNoteNotFound):
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "note not found")
+		case errors.Is(err, domain.ErrForbidden):
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeForbidden, "access denied")
+		default:
+			httputil.InternalError(c)
+		}
+		return
+	}
+
+	code, err := qrcode.Encode([]byte(strings.ReplaceAll(h.linkURL, "{id}", n.ID.String())), level)
+	if err != nil {
+		httputil.InternalError(c)
+		return
+	}
+
+	name := n.Reference
+	if name == "" {
+		name = n.ID.String()
+	}
+	// The link only depends on the note ID, so the image never changes.
+	c.Header("Cache-Control", "private, max-age=86400")
+
+	if req.Format == "svg" {
+		c.Header("Content-Disposition", `inline; filename="`+name+`.svg"`)
+		c.Data(http.StatusOK, "image/svg+xml", code.SVG(req.Size))
+		return
+	}
+
+	img, err := code.PNG(req.Size)
+	if err != nil {
+		httputil.InternalError(c)
+		return
+	}
+	c.Header("Content-Disposition", `inline; filename="`+name+`.png"`)
+	c.Data(http.StatusOK, "image/png", img)
+}
@@ -0,0 +1,98 @@
+package handler_test
+
+import (
+	"bytes"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+)
+
+func TestLabelHandler_QRCode(t *testing.T) {
+	setup := func(t *testing.T) (*mocks.MockNoteService, *gin.Engine, uuid.UUID) {
+		ctrl := gomock.NewController(t)
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewLabelHandler(noteSvc, "fieldnotes://notes/{id}")
+
+		router := setupRouter()
+		userID := uuid.New()
+		router.GET("/notes/:id/qrcode", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.QRCode(c)
+		})
+		return noteSvc, router, userID
+	}
+
+	t.Run("renders a png by default", func(t *testing.T) {
+		noteSvc, router, userID := setup(t)
+		n := &entity.Note{ID: uuid.New(), Reference: "PLOT-0001"}
+		noteSvc.EXPECT().GetByID(gomock.Any(), userID, n.ID).Return(n, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/notes/"+n.ID.String()+"/qrcode?size=512", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "PLOT-0001.png")
+
+		img, err := png.Decode(bytes.NewReader(w.Body.Bytes()))
+		require.NoError(t, err)
+		assert.LessOrEqual(t, img.Bounds().Dx(), 512)
+		assert.Greater(t, img.Bounds().Dx(), 256)
+	})
+
+	t.Run("renders an svg", func(t *testing.T) {
+		noteSvc, router, _ := setup(t)
+		n := &entity.Note{ID: uuid.New(), Reference: "PLOT-0002"}
+		noteSvc.EXPECT().GetByID(gomock.Any(), gomock.Any(), n.ID).Return(n, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/notes/"+n.ID.String()+"/qrcode?format=svg&level=H", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/svg+xml", w.Header().Get("Content-Type"))
+		assert.True(t, strings.HasPrefix(w.Body.String(), "<svg"))
+	})
+
+	t.Run("rejects invalid parameters", func(t *testing.T) {
+		for _, query := range []string{"format=jpg", "size=10", "size=5000", "level=X"} {
+			_, router, _ := setup(t)
+
+			req := httptest.NewRequest(http.MethodGet, "/notes/"+uuid.NewString()+"/qrcode?"+query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+	})
+
+	t.Run("maps note errors", func(t *testing.T) {
+		for err, status := range map[error]int{
+			domain.ErrNoteNotFound: http.StatusNotFound,
+			domain.ErrForbidden:    http.StatusForbidden,
+		} {
+			noteSvc, router, _ := setup(t)
+			noteSvc.EXPECT().GetByID(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/notes/"+uuid.NewString()+"/qrcode", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, status, w.Code)
+		}
+	})
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+// MetricsHandler exposes the latency histograms of the storage and image
+// operations in the Prometheus text format. Like the KPI metrics it is not
+// part of the public API.
+type MetricsHandler struct {
+	metrics MetricsWriter
+}
+
+func NewMetricsHandler(metrics MetricsWriter) *MetricsHandler {
+	return &MetricsHandler{metrics: metrics}
+}
+
+func (h *MetricsHandler) Metrics(c *gin.Context) {
+	var buf bytes.Buffer
+	if err := h.metrics.WritePrometheus(&buf); err != nil {
+		httputil.InternalError(c)
+		return
+	}
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", buf.Bytes())
+}
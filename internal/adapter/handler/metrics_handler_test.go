@@ -0,0 +1,62 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/observability"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+)
+
+func TestMetricsHandler_Metrics(t *testing.T) {
+	t.Run("writes the latency histograms", func(t *testing.T) {
+		latencies := observability.NewLatencies()
+		latencies.Observe(context.Background(), observability.OpS3PutObject, 300*time.Millisecond)
+		latencies.Observe(context.Background(), observability.OpS3PutObject, 2*time.Second)
+		latencies.OverBudget(observability.OpUpload)
+
+		router := setupRouter()
+		router.GET("/admin/metrics", handler.NewMetricsHandler(latencies).Metrics)
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/metrics", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/plain; version=0.0.4")
+		body := w.Body.String()
+		assert.Contains(t, body, "# TYPE fieldnotes_operation_duration_seconds histogram\n")
+		assert.Contains(t, body, `fieldnotes_operation_duration_seconds_bucket{operation="s3_put_object",le="0.25"} 0`+"\n")
+		assert.Contains(t, body, `fieldnotes_operation_duration_seconds_bucket{operation="s3_put_object",le="0.5"} 1`+"\n")
+		assert.Contains(t, body, `fieldnotes_operation_duration_seconds_bucket{operation="s3_put_object",le="+Inf"} 2`+"\n")
+		assert.Contains(t, body, `fieldnotes_operation_duration_seconds_sum{operation="s3_put_object"} 2.3`+"\n")
+		assert.Contains(t, body, `fieldnotes_operation_over_budget_total{operation="upload"} 1`+"\n")
+	})
+
+	t.Run("returns 500 when the metrics cannot be written", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metrics := mocks.NewMockMetricsWriter(ctrl)
+		metrics.EXPECT().WritePrometheus(gomock.Any()).Return(errors.New("boom"))
+
+		router := setupRouter()
+		router.GET("/admin/metrics", handler.NewMetricsHandler(metrics).Metrics)
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/metrics", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+// Export formats.
+const (
+	exportNDJSON = "ndjson"
+	exportCSV    = "csv"
+	exportGPX    = "gpx"
+)
+
+// noteEncoder writes notes to an export one at a time, so nothing but the
+// current batch is held in memory.
+type noteEncoder interface {
+	ContentType() string
+	Filename() string
+	// Begin writes what precedes the first note.
+	Begin() error
+	Encode(n *response.NoteResponse) error
+	// End writes what follows the last note.
+	End() error
+	// Fail marks an export cut short after it started, where the format
+	// allows it.
+	Fail(e httputil.ErrorResponse)
+}
+
+func newNoteEncoder(format string, w io.Writer) noteEncoder {
+	switch format {
+	case exportCSV:
+		return &csvNoteEncoder{w: csv.NewWriter(w)}
+	case exportGPX:
+		return &gpxNoteEncoder{w: w, enc: xml.NewEncoder(w)}
+	default:
+		return &ndjsonNoteEncoder{enc: json.NewEncoder(w)}
+	}
+}
+
+type ndjsonNoteEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonNoteEncoder) ContentType() string { return "application/x-ndjson" }
+func (e *ndjsonNoteEncoder) Filename() string    { return "notes.ndjson" }
+func (e *ndjsonNoteEncoder) Begin() error        { return nil }
+func (e *ndjsonNoteEncoder) End() error          { return nil }
+
+func (e *ndjsonNoteEncoder) Encode(n *response.NoteResponse) error {
+	return e.enc.Encode(n)
+}
+
+// Fail writes the error as the last line, so clients can tell the export is
+// incomplete.
+func (e *ndjsonNoteEncoder) Fail(resp httputil.ErrorResponse) {
+	_ = e.enc.Encode(resp)
+}
+
+var csvHeader = []string{
+	"id", "number", "reference", "title", "content",
+	"latitude", "longitude", "altitude", "accuracy", "location_generalized",
+	"sensitivity", "source", "tags", "measurements", "photo_urls",
+	"created_at", "updated_at",
+}
+
+// csvNoteEncoder writes one row per note. Lists are joined with "; " and
+// measurements are written as "name=value unit".
+type csvNoteEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvNoteEncoder) ContentType() string { return "text/csv; charset=utf-8" }
+func (e *csvNoteEncoder) Filename() string    { return "notes.csv" }
+
+func (e *csvNoteEncoder) Begin() error {
+	return e.write(csvHeader)
+}
+
+func (e *csvNoteEncoder) Encode(n *response.NoteResponse) error {
+	var lat, lng, alt, acc string
+	if n.Location != nil {
+		lat = formatFloat(n.Location.Latitude)
+		lng = formatFloat(n.Location.Longitude)
+		if n.Location.Altitude != nil {
+			alt = formatFloat(*n.Location.Altitude)
+		}
+		if n.Location.Accuracy != nil {
+			acc = formatFloat(*n.Location.Accuracy)
+		}
+	}
+
+	measurements := make([]string, len(n.Measurements))
+	for i, m := range n.Measurements {
+		measurements[i] = m.Name + "=" + formatFloat(m.Value) + " " + m.Unit
+	}
+	photos := make([]string, len(n.Photos))
+	for i, p := range n.Photos {
+		photos[i] = p.URL
+	}
+
+	return e.write([]string{
+		n.ID.String(), strconv.FormatInt(n.Number, 10), n.Reference, n.Title, n.Content,
+		lat, lng, alt, acc, strconv.FormatBool(n.LocationGeneralized),
+		n.Sensitivity, n.Source, strings.Join(n.Tags, "; "), strings.Join(measurements, "; "), strings.Join(photos, "; "),
+		n.CreatedAt.UTC().Format(time.RFC3339), n.UpdatedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+func (e *csvNoteEncoder) End() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// Fail leaves the file as written: CSV has no way to mark it incomplete.
+func (e *csvNoteEncoder) Fail(httputil.ErrorResponse) {
+	e.w.Flush()
+}
+
+// write writes the record, flushing to the response so rows are not
+// buffered beyond the current batch.
+func (e *csvNoteEncoder) write(record []string) error {
+	for i, field := range record {
+		record[i] = csvSafe(field)
+	}
+	if err := e.w.Write(record); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// csvSafe prefixes fields that spreadsheets would run as formulas with a
+// quote, so an exported note cannot execute anything when opened.
+func csvSafe(field string) string {
+	if field != "" && strings.ContainsRune("=+-@\t\r", rune(field[0])) {
+		if _, err := strconv.ParseFloat(field, 64); err == nil {
+			return field
+		}
+		return "'" + field
+	}
+	return field
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+const gpxHeader = xml.Header + `<gpx version="1.1" creator="field-notes" xmlns="http://www.topografix.com/GPX/1/1">` + "\n"
+
+// gpxNoteEncoder writes a GPX 1.1 waypoint per note with a location. Notes
+// without one have no place in a GPX file and are skipped.
+type gpxNoteEncoder struct {
+	w   io.Writer
+	enc *xml.Encoder
+}
+
+type gpxWaypoint struct {
+	XMLName   xml.Name  `xml:"wpt"`
+	Latitude  float64   `xml:"lat,attr"`
+	Longitude float64   `xml:"lon,attr"`
+	Elevation *float64  `xml:"ele,omitempty"`
+	Time      time.Time `xml:"time"`
+	Name      string    `xml:"name"`
+	Desc      string    `xml:"desc,omitempty"`
+	Links     []gpxLink `xml:"link"`
+}
+
+type gpxLink struct {
+	Href string `xml:"href,attr"`
+	Type string `xml:"type,omitempty"`
+}
+
+func (e *gpxNoteEncoder) ContentType() string { return "application/gpx+xml" }
+func (e *gpxNoteEncoder) Filename() string    { return "notes.gpx" }
+
+func (e *gpxNoteEncoder) Begin() error {
+	_, err := io.WriteString(e.w, gpxHeader)
+	return err
+}
+
+func (e *gpxNoteEncoder) Encode(n *response.NoteResponse) error {
+	if n.Location == nil {
+		return nil
+	}
+
+	name := n.Reference
+	if n.Title != "" {
+		name += " " + n.Title
+	}
+	wpt := gpxWaypoint{
+		Latitude:  n.Location.Latitude,
+		Longitude: n.Location.Longitude,
+		Elevation: n.Location.Altitude,
+		Time:      n.CreatedAt.UTC(),
+		Name:      name,
+		Desc:      n.Content,
+	}
+	for _, p := range n.Photos {
+		wpt.Links = append(wpt.Links, gpxLink{Href: p.URL, Type: p.MimeType})
+	}
+
+	if err := e.enc.Encode(wpt); err != nil {
+		return err
+	}
+	_, err := io.WriteString(e.w, "\n")
+	return err
+}
+
+func (e *gpxNoteEncoder) End() error {
+	_, err := io.WriteString(e.w, "</gpx>\n")
+	return err
+}
+
+// Fail leaves out the closing </gpx>, so readers reject the file instead of
+// taking it for the whole export.
+func (e *gpxNoteEncoder) Fail(httputil.ErrorResponse) {}
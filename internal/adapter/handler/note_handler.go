@@ -1,8 +1,12 @@
 package handler
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -10,31 +14,38 @@ import (
 	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/request"
 	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/apperror"
 	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
 	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/note"
 )
 
 type NoteHandler struct {
 	noteSvc NoteService
+	prefSvc PreferenceService
+	mask    entity.LocationMask
 }
 
-func NewNoteHandler(noteSvc NoteService) *NoteHandler {
-	return &NoteHandler{noteSvc: noteSvc}
+func NewNoteHandler(noteSvc NoteService, prefSvc PreferenceService, mask entity.LocationMask) *NoteHandler {
+	return &NoteHandler{noteSvc: noteSvc, prefSvc: prefSvc, mask: mask}
 }
 
 // Create godoc
 //
 //	@Summary		Create a new note
-//	@Description	Create a new note with optional location
+//	@Description	Create a new note with optional location. Without a title, or with a placeholder such as "Untitled", the title is generated from the content unless auto_title is false
 //	@Tags			notes
 //	@Security		BearerAuth
 //	@Accept			json
 //	@Produce		json
-//	@Param			request	body		request.CreateNoteRequest	true	"Note data"
+//	@Param			request		body		request.CreateNoteRequest	true	"Note data"
+//	@Param			X-Device-ID	header		string						false	"Client device identifier"
+//	@Param			units		query		string						false	"Unit system for measurements, defaults to the user's preference"	Enums(metric, imperial)
 //	@Success		201		{object}	response.NoteResponse
 //	@Failure		400		{object}	httputil.ErrorResponse
 //	@Failure		401		{object}	httputil.ErrorResponse
+//	@Failure		403		{object}	httputil.ErrorResponse
 //	@Router			/notes [post]
 func (h *NoteHandler) Create(c *gin.Context) {
 	var req request.CreateNoteRequest
@@ -49,30 +60,60 @@ func (h *NoteHandler) Create(c *gin.Context) {
 	if req.Latitude != nil && req.Longitude != nil {
 		loc = valueobject.NewLocation(*req.Latitude, *req.Longitude, req.Altitude, req.Accuracy)
 		if !loc.IsValid() {
-			httputil.ErrorWithCode(c, http.StatusBadRequest, "INVALID_LOCATION", "invalid coordinates")
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidLocation, "invalid coordinates")
 			return
 		}
 	}
 
+	measurements, ok := measurementsFromRequest(req.Measurements)
+	if !ok {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidMeasurement, "invalid measurement value")
+		return
+	}
+
 	n, err := h.noteSvc.Create(c.Request.Context(), note.CreateInput{
-		UserID:   userID,
-		Title:    req.Title,
-		Content:  req.Content,
-		Location: loc,
-		ClientID: req.ClientID,
+		UserID:        userID,
+		Title:         req.Title,
+		Content:       req.Content,
+		Location:      loc,
+		Measurements:  measurements,
+		ClientID:      req.ClientID,
+		DeviceID:      httputil.GetDeviceID(c),
+		Sensitivity:   req.Sensitivity,
+		Source:        req.Source,
+		SourceMeta:    req.SourceMeta,
+		TeamID:        optionalUUID(req.TeamID),
+		Color:         req.Color,
+		Icon:          req.Icon,
+		SkipAutoTitle: optedOut(req.AutoTitle),
 	})
 	if err != nil {
-		httputil.InternalError(c)
+		switch {
+		case errors.Is(err, domain.ErrForbidden):
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeForbidden, "not a member of the team")
+		case errors.Is(err, domain.ErrInvalidSensitivity):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "sensitivity must be none, low or high")
+		case errors.Is(err, domain.ErrInvalidNoteColor):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, noteColorMessage)
+		case errors.Is(err, domain.ErrInvalidNoteIcon):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, noteIconMessage)
+		case errors.Is(err, domain.ErrInvalidNoteSource):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "source must be manual or integration:<name>, with source_meta up to 4 KB")
+		default:
+			httputil.InternalError(c)
+		}
 		return
 	}
 
-	httputil.Created(c, response.NoteFromEntity(n))
+	httputil.CountKPI(c, entity.KPINotesCreated, 1)
+	httputil.MarkNotesChanged(c)
+	httputil.Created(c, response.NoteFromEntity(n, noteView(c, h.prefSvc, h.mask, len(n.Measurements) > 0)))
 }
 
 // List godoc
 //
 //	@Summary		List notes
-//	@Description	Get paginated list of notes with optional bounding box filter
+//	@Description	Get paginated list of notes with optional bounding box filter. Pass next_cursor from a previous page as cursor for keyset pagination, which stays fast on deep pages but does not report totals. sort orders by several fields; notes that tie on all of them are ordered by id, so pages never skip or repeat notes. A cursor only continues the sort it came from.
 //	@Tags			notes
 //	@Security		BearerAuth
 //	@Produce		json
@@ -82,9 +123,21 @@ func (h *NoteHandler) Create(c *gin.Context) {
 //	@Param			max_lat		query		number	false	"Maximum latitude for bounding box"
 //	@Param			min_lng		query		number	false	"Minimum longitude for bounding box"
 //	@Param			max_lng		query		number	false	"Maximum longitude for bounding box"
+//	@Param			device_id	query		string	false	"Only notes created or last modified by this device"
+//	@Param			quality		query		string	false	"Only notes with this quality status"	Enums(unchecked, passed, failed)
+//	@Param			number		query		string	false	"Note number (42) or reference (PLOT-0042)"
+//	@Param			tag			query		[]string	false	"Only notes with all of these tags"	collectionFormat(multi)
+//	@Param			color		query		[]string	false	"Only notes with any of these colors"	collectionFormat(multi)
+//	@Param			icon		query		[]string	false	"Only notes with any of these icons"	collectionFormat(multi)
+//	@Param			source		query		string	false	"Only notes from this source: manual, sync, import, integration:<name>, or integration for any integration"
+//	@Param			cursor		query		string	false	"Opaque next_cursor from a previous page; replaces page"
+//	@Param			team_id		query		string	false	"List the notes of this team, by any member, instead of your own"	format(uuid)
+//	@Param			sort		query		string	false	"Comma-separated fields, each prefixed with - for descending: updated_at, created_at, title, number, id"	default(-updated_at)
+//	@Param			units		query		string	false	"Unit system for measurements, defaults to the user's preference"	Enums(metric, imperial)
 //	@Success		200			{object}	response.NotesListResponse
 //	@Failure		400			{object}	httputil.ErrorResponse
 //	@Failure		401			{object}	httputil.ErrorResponse
+//	@Failure		403			{object}	httputil.ErrorResponse
 //	@Router			/notes [get]
 func (h *NoteHandler) List(c *gin.Context) {
 	var req request.ListNotesRequest
@@ -95,32 +148,408 @@ func (h *NoteHandler) List(c *gin.Context) {
 
 	userID := httputil.GetUserID(c)
 
-	var bbox *valueobject.BoundingBox
-	if req.MinLat != nil && req.MaxLat != nil && req.MinLng != nil && req.MaxLng != nil {
-		bbox = valueobject.NewBoundingBox(*req.MinLat, *req.MaxLat, *req.MinLng, *req.MaxLng)
-		if !bbox.IsValid() {
-			httputil.ErrorWithCode(c, http.StatusBadRequest, "INVALID_BBOX", "invalid bounding box")
-			return
-		}
+	bbox, ok := boundingBox(req.MinLat, req.MaxLat, req.MinLng, req.MaxLng)
+	if !ok {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidBBox, "invalid bounding box")
+		return
 	}
 
 	notes, pageInfo, err := h.noteSvc.List(c.Request.Context(), note.ListInput{
-		UserID:      userID,
-		Page:        req.Page,
-		PerPage:     req.PerPage,
-		BoundingBox: bbox,
+		UserID:        userID,
+		Page:          req.Page,
+		PerPage:       req.PerPage,
+		BoundingBox:   bbox,
+		DeviceID:      req.DeviceID,
+		QualityStatus: req.Quality,
+		Number:        req.Number,
+		Tags:          req.Tags,
+		Colors:        req.Colors,
+		Icons:         req.Icons,
+		Cursor:        req.Cursor,
+		Source:        req.Source,
+		TeamID:        optionalUUID(req.TeamID),
+		Sort:          req.Sort,
 	})
 	if err != nil {
-		httputil.InternalError(c)
+		switch {
+		case errors.Is(err, domain.ErrForbidden):
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeForbidden, "not a member of the team")
+		case errors.Is(err, domain.ErrInvalidSort):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidSort,
+				"sort must list updated_at, created_at, title, number or id at most once each, with - for descending")
+		case errors.Is(err, domain.ErrInvalidTag):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "invalid tag")
+		case errors.Is(err, domain.ErrInvalidNoteColor):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, noteColorMessage)
+		case errors.Is(err, domain.ErrInvalidNoteIcon):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, noteIconMessage)
+		case errors.Is(err, domain.ErrInvalidCursor):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidCursor, "invalid cursor")
+		case errors.Is(err, domain.ErrInvalidNoteSource):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "invalid source")
+		default:
+			httputil.InternalError(c)
+		}
 		return
 	}
 
 	httputil.OK(c, response.NotesListResponse{
-		Notes:      response.NotesFromEntities(notes),
+		Notes:      response.NotesFromEntities(notes, noteView(c, h.prefSvc, h.mask, hasMeasurements(notes...))),
 		Pagination: response.PaginationFromInfo(pageInfo),
 	})
 }
 
+// Messages for colors and icons outside the allowed sets, listing them.
+var (
+	noteColorMessage = "color must be one of " + strings.Join(entity.NoteColors, ", ")
+	noteIconMessage  = "icon must be one of " + strings.Join(entity.NoteIcons, ", ")
+)
+
+// optionalUUID parses an ID validated by binding, or returns nil when it is
+// empty.
+func optionalUUID(s string) *uuid.UUID {
+	if s == "" {
+		return nil
+	}
+	id := uuid.MustParse(s)
+	return &id
+}
+
+// optedOut reports whether a feature the client can turn off per request,
+// on unless the flag is sent, was turned off.
+func optedOut(flag *bool) bool {
+	return flag != nil && !*flag
+}
+
+// boundingBox returns the box given by the query, or nil when any of its
+// edges is missing. It reports false when the box is invalid.
+func boundingBox(minLat, maxLat, minLng, maxLng *float64) (*valueobject.BoundingBox, bool) {
+	if minLat == nil || maxLat == nil || minLng == nil || maxLng == nil {
+		return nil, true
+	}
+	bbox := valueobject.NewBoundingBox(*minLat, *maxLat, *minLng, *maxLng)
+	return bbox, bbox.IsValid()
+}
+
+// Coverage godoc
+//
+//	@Summary		Offline map coverage of notes
+//	@Description	Get the Web Mercator tiles at the zoom that hold the caller's notes with a location, with the bounds of the notes, so the app can download just those map areas for offline use. At most 2000 tiles are returned; truncated is set when there are more
+//	@Tags			notes
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			zoom	query		int	false	"Tile zoom level, 1 to 18"	default(12)
+//	@Success		200		{object}	response.CoverageResponse
+//	@Failure		400		{object}	httputil.ErrorResponse
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Router			/notes/coverage [get]
+func (h *NoteHandler) Coverage(c *gin.Context) {
+	var req request.NoteCoverageRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	coverage, err := h.noteSvc.Coverage(c.Request.Context(), httputil.GetUserID(c), req.Zoom)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidZoom) {
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "invalid zoom")
+			return
+		}
+		httputil.InternalError(c)
+		return
+	}
+
+	httputil.OK(c, response.CoverageFromEntity(coverage))
+}
+
+// Nearby godoc
+//
+//	@Summary		List notes near a point
+//	@Description	Get the caller's notes within radius_m meters of the point, closest first, each with its distance in distance_m
+//	@Tags			notes
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			lat			query		number	true	"Latitude of the search point"
+//	@Param			lng			query		number	true	"Longitude of the search point"
+//	@Param			radius_m	query		number	true	"Search radius in meters, up to 50000"
+//	@Param			limit		query		int		false	"Maximum number of notes"	default(20)
+//	@Param			units		query		string	false	"Unit system for measurements, defaults to the user's preference"	Enums(metric, imperial)
+//	@Success		200			{object}	response.NearbyNotesResponse
+//	@Failure		400			{object}	httputil.ErrorResponse
+//	@Failure		401			{object}	httputil.ErrorResponse
+//	@Router			/notes/nearby [get]
+func (h *NoteHandler) Nearby(c *gin.Context) {
+	var req request.NearbyNotesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	notes, err := h.noteSvc.Nearby(c.Request.Context(), note.NearbyInput{
+		UserID:       httputil.GetUserID(c),
+		Latitude:     *req.Latitude,
+		Longitude:    *req.Longitude,
+		RadiusMeters: req.Radius,
+		Limit:        req.Limit,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidLocation) {
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidLocation, "invalid location or radius")
+			return
+		}
+		httputil.InternalError(c)
+		return
+	}
+
+	withMeasurements := false
+	for _, n := range notes {
+		withMeasurements = withMeasurements || hasMeasurements(n.Note)
+	}
+
+	httputil.OK(c, response.NearbyNotesResponse{
+		Notes: response.NearbyNotesFromEntities(notes, noteView(c, h.prefSvc, h.mask, withMeasurements)),
+	})
+}
+
+// Search godoc
+//
+//	@Summary		Search notes
+//	@Description	Full-text search over the caller's notes' title and content. With lat, lng and radius_m, or with a bounding box, only notes in that area match and results are ranked by both relevance and distance to the area's center
+//	@Tags			notes
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			q			query		string	true	"Search text; supports quoted phrases, OR and -word"
+//	@Param			lat			query		number	false	"Latitude of the search point"
+//	@Param			lng			query		number	false	"Longitude of the search point"
+//	@Param			radius_m	query		number	false	"Search radius in meters, up to 50000"
+//	@Param			min_lat		query		number	false	"Minimum latitude for bounding box"
+//	@Param			max_lat		query		number	false	"Maximum latitude for bounding box"
+//	@Param			min_lng		query		number	false	"Minimum longitude for bounding box"
+//	@Param			max_lng		query		number	false	"Maximum longitude for bounding box"
+//	@Param			limit		query		int		false	"Maximum number of notes"	default(20)
+//	@Param			units		query		string	false	"Unit system for measurements, defaults to the user's preference"	Enums(metric, imperial)
+//	@Success		200			{object}	response.SearchNotesResponse
+//	@Failure		400			{object}	httputil.ErrorResponse
+//	@Failure		401			{object}	httputil.ErrorResponse
+//	@Router			/notes/search [get]
+func (h *NoteHandler) Search(c *gin.Context) {
+	var req request.SearchNotesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	input := note.SearchInput{
+		UserID: httputil.GetUserID(c),
+		Query:  req.Query,
+		Limit:  req.Limit,
+	}
+	if req.Latitude != nil || req.Longitude != nil || req.Radius != 0 {
+		if req.Latitude == nil || req.Longitude == nil || req.Radius == 0 {
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidLocation, "lat, lng and radius_m must be given together")
+			return
+		}
+		input.Latitude, input.Longitude, input.RadiusMeters = *req.Latitude, *req.Longitude, req.Radius
+	}
+	if req.MinLat != nil && req.MaxLat != nil && req.MinLng != nil && req.MaxLng != nil {
+		input.BoundingBox = valueobject.NewBoundingBox(*req.MinLat, *req.MaxLat, *req.MinLng, *req.MaxLng)
+	}
+
+	hits, err := h.noteSvc.Search(c.Request.Context(), input)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidSearchQuery):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "q must not be blank")
+		case errors.Is(err, domain.ErrInvalidLocation):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidLocation, "invalid location or radius; use either a radius or a bounding box")
+		case errors.Is(err, domain.ErrInvalidBoundingBox):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidBBox, "invalid bounding box")
+		default:
+			httputil.InternalError(c)
+		}
+		return
+	}
+
+	withMeasurements := false
+	for _, hit := range hits {
+		withMeasurements = withMeasurements || hasMeasurements(hit.Note)
+	}
+
+	httputil.OK(c, response.SearchNotesResponse{
+		Notes: response.SearchHitsFromEntities(hits, noteView(c, h.prefSvc, h.mask, withMeasurements)),
+	})
+}
+
+// exportWriteTimeout is how long writing each batch of an export may take.
+// It replaces the server's write timeout, which would cut off large exports.
+const exportWriteTimeout = 30 * time.Second
+
+// Export godoc
+//
+//	@Summary		Export notes
+//	@Description	Stream the caller's notes, ordered by number, as newline-delimited JSON (with photos and attachments), CSV (one row per note) or GPX (one waypoint per note with a location). The filters are those of the note list plus a creation date range. The response is sent as it is read; if the export fails midway, NDJSON ends with an error object, GPX lacks its closing tag and CSV is cut short
+//	@Tags			notes
+//	@Security		BearerAuth
+//	@Produce		application/x-ndjson,text/csv,application/gpx+xml
+//	@Param			format		query		string		false	"Export format"	Enums(ndjson, csv, gpx)	default(ndjson)
+//	@Param			min_lat		query		number		false	"Bounding box minimum latitude"
+//	@Param			max_lat		query		number		false	"Bounding box maximum latitude"
+//	@Param			min_lng		query		number		false	"Bounding box minimum longitude"
+//	@Param			max_lng		query		number		false	"Bounding box maximum longitude"
+//	@Param			device_id	query		string		false	"Only notes created or last modified by this device"
+//	@Param			quality		query		string		false	"Quality status"	Enums(unchecked, passed, failed)
+//	@Param			tag			query		[]string	false	"Only notes with all of these tags"	collectionFormat(multi)
+//	@Param			color		query		[]string	false	"Only notes with any of these colors"	collectionFormat(multi)
+//	@Param			icon		query		[]string	false	"Only notes with any of these icons"	collectionFormat(multi)
+//	@Param			source		query		string		false	"Note source"
+//	@Param			from		query		string		false	"Only notes created at or after this time (RFC 3339)"
+//	@Param			to			query		string		false	"Only notes created before this time (RFC 3339)"
+//	@Param			units		query		string		false	"Unit system for measurements, defaults to the user's preference"	Enums(metric, imperial)
+//	@Success		200			{object}	response.NoteResponse	"One note per line, for NDJSON"
+//	@Failure		400			{object}	httputil.ErrorResponse
+//	@Failure		401			{object}	httputil.ErrorResponse
+//	@Router			/notes/export [get]
+func (h *NoteHandler) Export(c *gin.Context) {
+	var req request.ExportNotesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	bbox, ok := boundingBox(req.MinLat, req.MaxLat, req.MinLng, req.MaxLng)
+	if !ok {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidBBox, "invalid bounding box")
+		return
+	}
+
+	input := note.ExportInput{
+		UserID:        httputil.GetUserID(c),
+		BoundingBox:   bbox,
+		DeviceID:      req.DeviceID,
+		QualityStatus: req.Quality,
+		Source:        req.Source,
+		Tags:          req.Tags,
+		Colors:        req.Colors,
+		Icons:         req.Icons,
+		From:          req.From,
+		To:            req.To,
+	}
+
+	view := noteView(c, h.prefSvc, h.mask, true)
+	rc := http.NewResponseController(c.Writer)
+	encoder := newNoteEncoder(req.Format, c.Writer)
+	started := false
+	start := func() error {
+		if started {
+			return nil
+		}
+		started = true
+		c.Header("Content-Type", encoder.ContentType())
+		c.Header("Content-Disposition", `attachment; filename="`+encoder.Filename()+`"`)
+		c.Status(http.StatusOK)
+		return encoder.Begin()
+	}
+
+	err := h.noteSvc.Export(c.Request.Context(), input, func(notes []entity.Note) error {
+		if err := start(); err != nil {
+			return err
+		}
+		_ = rc.SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+		for i := range notes {
+			resp := response.NoteFromEntity(&notes[i], view)
+			if err := encoder.Encode(&resp); err != nil {
+				return err
+			}
+		}
+		return rc.Flush()
+	})
+	if err == nil {
+		err = start()
+	}
+	if err == nil {
+		err = encoder.End()
+	}
+	if err != nil {
+		if !started {
+			switch {
+			case errors.Is(err, domain.ErrInvalidTag):
+				httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "invalid tag")
+			case errors.Is(err, domain.ErrInvalidNoteColor):
+				httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, noteColorMessage)
+			case errors.Is(err, domain.ErrInvalidNoteIcon):
+				httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, noteIconMessage)
+			case errors.Is(err, domain.ErrInvalidNoteSource):
+				httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "invalid source")
+			case errors.Is(err, domain.ErrInvalidDateRange):
+				httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "from must be before to")
+			default:
+				httputil.InternalError(c)
+			}
+			return
+		}
+		// The status line is gone; tell the client the export is incomplete.
+		encoder.Fail(httputil.NewErrorResponse(c,
+			apperror.New(http.StatusInternalServerError, httputil.CodeInternalError, "export interrupted")))
+	}
+}
+
+// Import godoc
+//
+//	@Summary		Import notes
+//	@Description	Create notes from a GeoJSON FeatureCollection (Point features) or a CSV file with a header row, to migrate from other tools. Rows are deduplicated by client_id: rows repeating one in the file fail and rows whose client_id was already imported are skipped. Each row's outcome is returned; invalid rows do not stop the others
+//	@Tags			notes
+//	@Security		BearerAuth
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			file	formData	file	true	"GeoJSON or CSV file, up to 10MB and 5000 notes"
+//	@Param			format	query		string	false	"File format, from the file extension when omitted"	Enums(geojson, csv)
+//	@Success		200		{object}	response.ImportResponse
+//	@Failure		400		{object}	httputil.ErrorResponse
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Router			/notes/import [post]
+func (h *NoteHandler) Import(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidFile, "file is required")
+		return
+	}
+	defer file.Close()
+
+	var req request.ImportNotesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+	format := importFormat(req.Format, header.Filename)
+	if format == "" {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidType, "file must be .geojson, .json or .csv, or set format")
+		return
+	}
+
+	rows, err := parseImport(format, file)
+	if err != nil {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidFile, err.Error())
+		return
+	}
+
+	results, err := h.noteSvc.Import(c.Request.Context(), note.ImportInput{
+		UserID:   httputil.GetUserID(c),
+		Format:   format,
+		Filename: header.Filename,
+		Rows:     rows,
+	})
+	if err != nil {
+		httputil.InternalError(c)
+		return
+	}
+
+	httputil.MarkNotesChanged(c)
+	httputil.OK(c, response.ImportFromResults(results))
+}
+
 // Get godoc
 //
 //	@Summary		Get note by ID
@@ -128,17 +557,18 @@ func (h *NoteHandler) List(c *gin.Context) {
 //	@Tags			notes
 //	@Security		BearerAuth
 //	@Produce		json
-//	@Param			id	path		string	true	"Note ID"	format(uuid)
-//	@Success		200	{object}	response.NoteResponse
-//	@Failure		400	{object}	httputil.ErrorResponse
-//	@Failure		401	{object}	httputil.ErrorResponse
-//	@Failure		403	{object}	httputil.ErrorResponse
-//	@Failure		404	{object}	httputil.ErrorResponse
+//	@Param			id		path		string	true	"Note ID"	format(uuid)
+//	@Param			units	query		string	false	"Unit system for measurements, defaults to the user's preference"	Enums(metric, imperial)
+//	@Success		200		{object}	response.NoteResponse
+//	@Failure		400		{object}	httputil.ErrorResponse
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Failure		403		{object}	httputil.ErrorResponse
+//	@Failure		404		{object}	httputil.ErrorResponse
 //	@Router			/notes/{id} [get]
 func (h *NoteHandler) Get(c *gin.Context) {
 	noteID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		httputil.ErrorWithCode(c, http.StatusBadRequest, "INVALID_ID", "invalid note id")
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidID, "invalid note id")
 		return
 	}
 
@@ -148,16 +578,16 @@ func (h *NoteHandler) Get(c *gin.Context) {
 	if err != nil {
 		switch {
 		case errors.Is(err, domain.ErrNoteNotFound):
-			httputil.ErrorWithCode(c, http.StatusNotFound, "NOT_FOUND", "note not found")
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "note not found")
 		case errors.Is(err, domain.ErrForbidden):
-			httputil.ErrorWithCode(c, http.StatusForbidden, "FORBIDDEN", "access denied")
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeForbidden, "access denied")
 		default:
 			httputil.InternalError(c)
 		}
 		return
 	}
 
-	httputil.OK(c, response.NoteFromEntity(n))
+	httputil.OK(c, response.NoteFromEntity(n, noteView(c, h.prefSvc, h.mask, len(n.Measurements) > 0)))
 }
 
 // Update godoc
@@ -170,16 +600,18 @@ func (h *NoteHandler) Get(c *gin.Context) {
 //	@Produce		json
 //	@Param			id		path		string						true	"Note ID"	format(uuid)
 //	@Param			request	body		request.UpdateNoteRequest	true	"Note data to update"
+//	@Param			units	query		string						false	"Unit system for measurements, defaults to the user's preference"	Enums(metric, imperial)
 //	@Success		200		{object}	response.NoteResponse
 //	@Failure		400		{object}	httputil.ErrorResponse
 //	@Failure		401		{object}	httputil.ErrorResponse
 //	@Failure		403		{object}	httputil.ErrorResponse
 //	@Failure		404		{object}	httputil.ErrorResponse
+//	@Failure		409		{object}	httputil.ErrorResponse
 //	@Router			/notes/{id} [put]
 func (h *NoteHandler) Update(c *gin.Context) {
 	noteID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		httputil.ErrorWithCode(c, http.StatusBadRequest, "INVALID_ID", "invalid note id")
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidID, "invalid note id")
 		return
 	}
 
@@ -195,29 +627,50 @@ func (h *NoteHandler) Update(c *gin.Context) {
 	if req.Latitude != nil && req.Longitude != nil {
 		loc = valueobject.NewLocation(*req.Latitude, *req.Longitude, req.Altitude, req.Accuracy)
 		if !loc.IsValid() {
-			httputil.ErrorWithCode(c, http.StatusBadRequest, "INVALID_LOCATION", "invalid coordinates")
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidLocation, "invalid coordinates")
 			return
 		}
 	}
 
+	measurements, ok := measurementsFromRequest(req.Measurements)
+	if !ok {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidMeasurement, "invalid measurement value")
+		return
+	}
+
 	n, err := h.noteSvc.Update(c.Request.Context(), userID, noteID, note.UpdateInput{
-		Title:    req.Title,
-		Content:  req.Content,
-		Location: loc,
+		Title:         req.Title,
+		Content:       req.Content,
+		Location:      loc,
+		Measurements:  measurements,
+		DeviceID:      httputil.GetDeviceID(c),
+		Sensitivity:   req.Sensitivity,
+		Color:         req.Color,
+		Icon:          req.Icon,
+		SkipAutoTitle: optedOut(req.AutoTitle),
 	})
 	if err != nil {
 		switch {
+		case errors.Is(err, domain.ErrInvalidSensitivity):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "sensitivity must be none, low or high")
+		case errors.Is(err, domain.ErrInvalidNoteColor):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, noteColorMessage)
+		case errors.Is(err, domain.ErrInvalidNoteIcon):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, noteIconMessage)
+		case errors.Is(err, domain.ErrNoteCollaborative):
+			httputil.ErrorWithCode(c, http.StatusConflict, httputil.CodeCollaborative, "the content of a collaborative note is changed with updates to its document")
 		case errors.Is(err, domain.ErrNoteNotFound):
-			httputil.ErrorWithCode(c, http.StatusNotFound, "NOT_FOUND", "note not found")
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "note not found")
 		case errors.Is(err, domain.ErrForbidden):
-			httputil.ErrorWithCode(c, http.StatusForbidden, "FORBIDDEN", "access denied")
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeForbidden, "access denied")
 		default:
 			httputil.InternalError(c)
 		}
 		return
 	}
 
-	httputil.OK(c, response.NoteFromEntity(n))
+	httputil.MarkNotesChanged(c)
+	httputil.OK(c, response.NoteFromEntity(n, noteView(c, h.prefSvc, h.mask, len(n.Measurements) > 0)))
 }
 
 // Delete godoc
@@ -236,7 +689,7 @@ func (h *NoteHandler) Update(c *gin.Context) {
 func (h *NoteHandler) Delete(c *gin.Context) {
 	noteID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		httputil.ErrorWithCode(c, http.StatusBadRequest, "INVALID_ID", "invalid note id")
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidID, "invalid note id")
 		return
 	}
 
@@ -245,14 +698,242 @@ func (h *NoteHandler) Delete(c *gin.Context) {
 	if err := h.noteSvc.Delete(c.Request.Context(), userID, noteID); err != nil {
 		switch {
 		case errors.Is(err, domain.ErrNoteNotFound):
-			httputil.ErrorWithCode(c, http.StatusNotFound, "NOT_FOUND", "note not found")
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "note not found")
 		case errors.Is(err, domain.ErrForbidden):
-			httputil.ErrorWithCode(c, http.StatusForbidden, "FORBIDDEN", "access denied")
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeForbidden, "access denied")
 		default:
 			httputil.InternalError(c)
 		}
 		return
 	}
 
+	httputil.MarkNotesChanged(c)
 	httputil.NoContent(c)
 }
+
+// Restore godoc
+//
+//	@Summary		Restore a note
+//	@Description	Undo the deletion of a note deleted within the restore window (30 days)
+//	@Tags			notes
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			id		path		string	true	"Note ID"	format(uuid)
+//	@Param			units	query		string	false	"Unit system for measurements, defaults to the user's preference"	Enums(metric, imperial)
+//	@Success		200		{object}	response.NoteResponse
+//	@Failure		400		{object}	httputil.ErrorResponse
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Failure		403		{object}	httputil.ErrorResponse
+//	@Failure		404		{object}	httputil.ErrorResponse
+//	@Failure		410		{object}	httputil.ErrorResponse
+//	@Router			/notes/{id}/restore [post]
+func (h *NoteHandler) Restore(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidID, "invalid note id")
+		return
+	}
+
+	n, err := h.noteSvc.Restore(c.Request.Context(), httputil.GetUserID(c), noteID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNoteNotFound):
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "note not found")
+		case errors.Is(err, domain.ErrForbidden):
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeForbidden, "access denied")
+		case errors.Is(err, domain.ErrRestoreExpired):
+			httputil.ErrorWithCode(c, http.StatusGone, httputil.CodeRestoreExpired, "restore window expired")
+		default:
+			httputil.InternalError(c)
+		}
+		return
+	}
+
+	httputil.MarkNotesChanged(c)
+	httputil.OK(c, response.NoteFromEntity(n, noteView(c, h.prefSvc, h.mask, len(n.Measurements) > 0)))
+}
+
+// Merge godoc
+//
+//	@Summary		Merge notes
+//	@Description	Merge two or more notes into the first one listed. Photos and measurements are combined and the other notes are deleted, pointing to the survivor in merged_into.
+//	@Tags			notes
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request		body		request.MergeNotesRequest	true	"Notes and merge options"
+//	@Param			X-Device-ID	header		string						false	"Client device identifier"
+//	@Param			units		query		string						false	"Unit system for measurements, defaults to the user's preference"	Enums(metric, imperial)
+//	@Success		200			{object}	response.NoteResponse
+//	@Failure		400			{object}	httputil.ErrorResponse
+//	@Failure		401			{object}	httputil.ErrorResponse
+//	@Failure		403			{object}	httputil.ErrorResponse
+//	@Failure		404			{object}	httputil.ErrorResponse
+//	@Failure		409			{object}	httputil.ErrorResponse
+//	@Router			/notes/merge [post]
+func (h *NoteHandler) Merge(c *gin.Context) {
+	var req request.MergeNotesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	input := note.MergeInput{
+		UserID:   httputil.GetUserID(c),
+		NoteIDs:  make([]uuid.UUID, 0, len(req.NoteIDs)),
+		Content:  req.Content,
+		DeviceID: httputil.GetDeviceID(c),
+	}
+	for _, id := range req.NoteIDs {
+		input.NoteIDs = append(input.NoteIDs, uuid.MustParse(id))
+	}
+	if req.TitleFrom != "" {
+		input.TitleFrom = uuid.MustParse(req.TitleFrom)
+	}
+	if input.Content == "" {
+		input.Content = entity.MergeContentConcatenate
+	}
+
+	n, err := h.noteSvc.Merge(c.Request.Context(), input)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidMerge):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "notes must be distinct, belong to the same owner and include title_from")
+		case errors.Is(err, domain.ErrNoteCollaborative):
+			httputil.ErrorWithCode(c, http.StatusConflict, httputil.CodeCollaborative, "collaborative notes cannot be merged")
+		case errors.Is(err, domain.ErrNoteNotFound):
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "note not found")
+		case errors.Is(err, domain.ErrForbidden):
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeForbidden, "access denied")
+		default:
+			httputil.InternalError(c)
+		}
+		return
+	}
+
+	httputil.MarkNotesChanged(c)
+	httputil.OK(c, response.NoteFromEntity(n, noteView(c, h.prefSvc, h.mask, len(n.Measurements) > 0)))
+}
+
+// Exists godoc
+//
+//	@Summary		Check which notes exist
+//	@Description	Look up up to 500 notes by id and 500 by client_id and get back those the caller has, deleted ones included, with their updated_at, and the ids and client_ids that match no note. Clients rebuilding a damaged local store use it to work out what to upload again before syncing
+//	@Tags			notes
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		request.NotesExistRequest	true	"Notes to look up"
+//	@Success		200		{object}	response.NotesExistResponse
+//	@Failure		400		{object}	httputil.ErrorResponse
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Router			/notes/exists [post]
+func (h *NoteHandler) Exists(c *gin.Context) {
+	var req request.NotesExistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+	if len(req.IDs) == 0 && len(req.ClientIDs) == 0 {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "ids or client_ids is required")
+		return
+	}
+
+	input := note.ExistsInput{
+		UserID:    httputil.GetUserID(c),
+		IDs:       make([]uuid.UUID, 0, len(req.IDs)),
+		ClientIDs: req.ClientIDs,
+	}
+	for _, id := range req.IDs {
+		input.IDs = append(input.IDs, uuid.MustParse(id))
+	}
+
+	result, err := h.noteSvc.Exists(c.Request.Context(), input)
+	if err != nil {
+		httputil.InternalError(c)
+		return
+	}
+
+	httputil.OK(c, response.NotesExistFromResult(result))
+}
+
+// AddTags godoc
+//
+//	@Summary		Tag a note
+//	@Description	Add tags to a note. Tags are lower-cased and may contain letters, digits, '-' and '_'; unknown tags are created.
+//	@Tags			notes
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string					true	"Note ID"	format(uuid)
+//	@Param			request		body		request.NoteTagsRequest	true	"Tags to add"
+//	@Param			X-Device-ID	header		string					false	"Client device identifier"
+//	@Success		200			{object}	response.NoteResponse
+//	@Failure		400			{object}	httputil.ErrorResponse
+//	@Failure		401			{object}	httputil.ErrorResponse
+//	@Failure		403			{object}	httputil.ErrorResponse
+//	@Failure		404			{object}	httputil.ErrorResponse
+//	@Router			/notes/{id}/tags [post]
+func (h *NoteHandler) AddTags(c *gin.Context) {
+	h.changeTags(c, h.noteSvc.AddTags)
+}
+
+// RemoveTags godoc
+//
+//	@Summary		Untag a note
+//	@Description	Remove tags from a note. Tags the note does not have are ignored.
+//	@Tags			notes
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string					true	"Note ID"	format(uuid)
+//	@Param			request		body		request.NoteTagsRequest	true	"Tags to remove"
+//	@Param			X-Device-ID	header		string					false	"Client device identifier"
+//	@Success		200			{object}	response.NoteResponse
+//	@Failure		400			{object}	httputil.ErrorResponse
+//	@Failure		401			{object}	httputil.ErrorResponse
+//	@Failure		403			{object}	httputil.ErrorResponse
+//	@Failure		404			{object}	httputil.ErrorResponse
+//	@Router			/notes/{id}/tags [delete]
+func (h *NoteHandler) RemoveTags(c *gin.Context) {
+	h.changeTags(c, h.noteSvc.RemoveTags)
+}
+
+func (h *NoteHandler) changeTags(c *gin.Context, change func(context.Context, note.TagsInput) (*entity.Note, error)) {
+	noteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidID, "invalid note id")
+		return
+	}
+
+	var req request.NoteTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	n, err := change(c.Request.Context(), note.TagsInput{
+		UserID:   httputil.GetUserID(c),
+		NoteID:   noteID,
+		Tags:     req.Tags,
+		DeviceID: httputil.GetDeviceID(c),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidTag):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "tags may only contain letters, digits, '-' and '_'")
+		case errors.Is(err, domain.ErrTooManyTags):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, fmt.Sprintf("a note can have at most %d tags", entity.MaxNoteTags))
+		case errors.Is(err, domain.ErrNoteNotFound):
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "note not found")
+		case errors.Is(err, domain.ErrForbidden):
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeForbidden, "access denied")
+		default:
+			httputil.InternalError(c)
+		}
+		return
+	}
+
+	httputil.MarkNotesChanged(c)
+	httputil.OK(c, response.NoteFromEntity(n, noteView(c, h.prefSvc, h.mask, len(n.Measurements) > 0)))
+}
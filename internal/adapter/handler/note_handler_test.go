@@ -2,9 +2,13 @@ package handler_test
 
 import (
 	"bytes"
+	"context"
+	"encoding/csv"
 	"encoding/json"
+	"encoding/xml"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,11 +19,13 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
 	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
 	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/note"
 )
 
 func TestNoteHandler_Create(t *testing.T) {
@@ -28,7 +34,7 @@ func TestNoteHandler_Create(t *testing.T) {
 		defer ctrl.Finish()
 
 		noteSvc := mocks.NewMockNoteService(ctrl)
-		h := handler.NewNoteHandler(noteSvc)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
 
 		router := setupRouter()
 		userID := uuid.New()
@@ -64,12 +70,76 @@ func TestNoteHandler_Create(t *testing.T) {
 		assert.Equal(t, "Test content", resp["content"])
 	})
 
+	t.Run("normalizes measurements and returns them in the preferred units", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		prefSvc := mocks.NewMockPreferenceService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, prefSvc, entity.LocationMask{})
+
+		router := setupRouter()
+		userID := uuid.New()
+		router.POST("/notes", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Create(c)
+		})
+
+		noteSvc.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ any, input note.CreateInput) (*entity.Note, error) {
+				require.Len(t, input.Measurements, 1)
+				assert.Equal(t, valueobject.MeasurementLength, input.Measurements[0].Kind)
+				assert.InDelta(t, 3.048, input.Measurements[0].Value, 1e-9)
+				return &entity.Note{ID: uuid.New(), UserID: userID, Title: "Plot", Measurements: input.Measurements}, nil
+			})
+		prefSvc.EXPECT().UnitSystem(gomock.Any(), userID).Return(valueobject.UnitSystemImperial, nil)
+
+		body := `{"title":"Plot","content":"Tree","measurements":[{"name":"height","value":10,"unit":"ft"}]}`
+		req := httptest.NewRequest(http.MethodPost, "/notes", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var resp response.NoteResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Measurements, 1)
+		assert.Equal(t, "ft", resp.Measurements[0].Unit)
+		assert.InDelta(t, 10.0, resp.Measurements[0].Value, 1e-9)
+	})
+
+	t.Run("rejects temperature below absolute zero", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		router.POST("/notes", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.Create(c)
+		})
+
+		body := `{"title":"Plot","content":"Cold","measurements":[{"name":"air","value":-500,"unit":"C"}]}`
+		req := httptest.NewRequest(http.MethodPost, "/notes", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_MEASUREMENT")
+	})
+
 	t.Run("creates note with location", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		noteSvc := mocks.NewMockNoteService(ctrl)
-		h := handler.NewNoteHandler(noteSvc)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
 
 		router := setupRouter()
 		userID := uuid.New()
@@ -112,7 +182,7 @@ func TestNoteHandler_Create(t *testing.T) {
 		defer ctrl.Finish()
 
 		noteSvc := mocks.NewMockNoteService(ctrl)
-		h := handler.NewNoteHandler(noteSvc)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
 
 		router := setupRouter()
 		userID := uuid.New()
@@ -136,7 +206,7 @@ func TestNoteHandler_Create(t *testing.T) {
 		defer ctrl.Finish()
 
 		noteSvc := mocks.NewMockNoteService(ctrl)
-		h := handler.NewNoteHandler(noteSvc)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
 
 		router := setupRouter()
 		userID := uuid.New()
@@ -154,6 +224,95 @@ func TestNoteHandler_Create(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
+
+	t.Run("returns the color and icon and lists the allowed colors", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		router.POST("/notes", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.Create(c)
+		})
+
+		green, bird := "green", "bird"
+		noteSvc.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, input note.CreateInput) (*entity.Note, error) {
+				if input.Color != "green" {
+					return nil, domain.ErrInvalidNoteColor
+				}
+				assert.Equal(t, "bird", input.Icon)
+				return &entity.Note{ID: uuid.New(), Title: "Nest", Color: &green, Icon: &bird}, nil
+			}).Times(2)
+
+		body := `{"title":"Nest","content":"Two eggs","color":"green","icon":"bird"}`
+		req := httptest.NewRequest(http.MethodPost, "/notes", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "green", resp["color"])
+		assert.Equal(t, "bird", resp["icon"])
+
+		body = `{"title":"Nest","content":"Two eggs","color":"chartreuse"}`
+		req = httptest.NewRequest(http.MethodPost, "/notes", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "color must be one of red, orange")
+	})
+
+	t.Run("accepts notes without a title and passes the auto title opt-out", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		router.POST("/notes", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.Create(c)
+		})
+
+		var skipped []bool
+		noteSvc.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, input note.CreateInput) (*entity.Note, error) {
+				skipped = append(skipped, input.SkipAutoTitle)
+				n := &entity.Note{ID: uuid.New(), Content: input.Content}
+				if !input.SkipAutoTitle {
+					n.SetAutoTitle("Two eggs")
+				}
+				return n, nil
+			}).Times(3)
+
+		var titled []any
+		for _, body := range []string{
+			`{"content":"Two eggs"}`,
+			`{"title":"","content":"Two eggs","auto_title":true}`,
+			`{"title":"","content":"Two eggs","auto_title":false}`,
+		} {
+			req := httptest.NewRequest(http.MethodPost, "/notes", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			titled = append(titled, resp["auto_titled"])
+		}
+		assert.Equal(t, []bool{false, false, true}, skipped)
+		assert.Equal(t, []any{true, true, nil}, titled)
+	})
 }
 
 func TestNoteHandler_List(t *testing.T) {
@@ -162,7 +321,7 @@ func TestNoteHandler_List(t *testing.T) {
 		defer ctrl.Finish()
 
 		noteSvc := mocks.NewMockNoteService(ctrl)
-		h := handler.NewNoteHandler(noteSvc)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
 
 		router := setupRouter()
 		userID := uuid.New()
@@ -219,7 +378,7 @@ func TestNoteHandler_List(t *testing.T) {
 		defer ctrl.Finish()
 
 		noteSvc := mocks.NewMockNoteService(ctrl)
-		h := handler.NewNoteHandler(noteSvc)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
 
 		router := setupRouter()
 		userID := uuid.New()
@@ -254,291 +413,1206 @@ func TestNoteHandler_List(t *testing.T) {
 		assert.Equal(t, float64(2), pag["page"])
 		assert.Equal(t, float64(10), pag["per_page"])
 	})
-}
 
-func TestNoteHandler_Get(t *testing.T) {
-	t.Run("gets note successfully", func(t *testing.T) {
+	t.Run("passes cursor and returns next cursor", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		noteSvc := mocks.NewMockNoteService(ctrl)
-		h := handler.NewNoteHandler(noteSvc)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
 
 		router := setupRouter()
-		userID := uuid.New()
-		noteID := uuid.New()
-		router.GET("/notes/:id", func(c *gin.Context) {
-			c.Set("user_id", userID)
-			h.Get(c)
+		router.GET("/notes", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.List(c)
 		})
 
-		noteEntity := &entity.Note{
-			ID:        noteID,
-			UserID:    userID,
-			Title:     "Test Note",
-			Content:   "Test content",
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		}
-
-		noteSvc.EXPECT().GetByID(gomock.Any(), userID, noteID).Return(noteEntity, nil)
+		next := pagination.Cursor{UpdatedAt: time.Now().UTC(), ID: uuid.New()}
+		noteSvc.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ any, input note.ListInput) ([]entity.Note, *pagination.Info, error) {
+				assert.Equal(t, "abc", input.Cursor)
+				return []entity.Note{}, pagination.NewCursorInfo(20, &next, true), nil
+			})
 
-		req := httptest.NewRequest(http.MethodGet, "/notes/"+noteID.String(), nil)
+		req := httptest.NewRequest(http.MethodGet, "/notes?cursor=abc", nil)
 		w := httptest.NewRecorder()
 
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusOK, w.Code)
-
-		var resp map[string]any
-		err := json.Unmarshal(w.Body.Bytes(), &resp)
-		require.NoError(t, err)
-		assert.Equal(t, "Test Note", resp["title"])
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp response.NotesListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, next.Encode(), resp.Pagination.NextCursor)
+		assert.True(t, resp.Pagination.HasNext)
 	})
 
-	t.Run("returns not found for non-existent note", func(t *testing.T) {
+	t.Run("returns bad request for malformed cursor", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		noteSvc := mocks.NewMockNoteService(ctrl)
-		h := handler.NewNoteHandler(noteSvc)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
 
 		router := setupRouter()
-		userID := uuid.New()
-		noteID := uuid.New()
-		router.GET("/notes/:id", func(c *gin.Context) {
-			c.Set("user_id", userID)
-			h.Get(c)
+		router.GET("/notes", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.List(c)
 		})
 
-		noteSvc.EXPECT().GetByID(gomock.Any(), userID, noteID).Return(nil, domain.ErrNoteNotFound)
+		noteSvc.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, nil, domain.ErrInvalidCursor)
 
-		req := httptest.NewRequest(http.MethodGet, "/notes/"+noteID.String(), nil)
+		req := httptest.NewRequest(http.MethodGet, "/notes?cursor=garbage", nil)
 		w := httptest.NewRecorder()
 
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_CURSOR")
 	})
 
-	t.Run("returns forbidden for other user's note", func(t *testing.T) {
+	t.Run("passes the sort and rejects unknown fields", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		noteSvc := mocks.NewMockNoteService(ctrl)
-		h := handler.NewNoteHandler(noteSvc)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
 
 		router := setupRouter()
-		userID := uuid.New()
-		noteID := uuid.New()
-		router.GET("/notes/:id", func(c *gin.Context) {
-			c.Set("user_id", userID)
-			h.Get(c)
+		router.GET("/notes", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.List(c)
 		})
 
-		noteSvc.EXPECT().GetByID(gomock.Any(), userID, noteID).Return(nil, domain.ErrForbidden)
+		noteSvc.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, input note.ListInput) ([]entity.Note, *pagination.Info, error) {
+				assert.Equal(t, "-number,title", input.Sort)
+				return nil, nil, domain.ErrInvalidSort
+			})
 
-		req := httptest.NewRequest(http.MethodGet, "/notes/"+noteID.String(), nil)
+		req := httptest.NewRequest(http.MethodGet, "/notes?sort=-number,title", nil)
 		w := httptest.NewRecorder()
 
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_SORT")
 	})
 
-	t.Run("returns bad request for invalid ID", func(t *testing.T) {
+	t.Run("passes the color and icon filters", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		noteSvc := mocks.NewMockNoteService(ctrl)
-		h := handler.NewNoteHandler(noteSvc)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
 
 		router := setupRouter()
-		userID := uuid.New()
-		router.GET("/notes/:id", func(c *gin.Context) {
-			c.Set("user_id", userID)
-			h.Get(c)
+		router.GET("/notes", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.List(c)
 		})
 
-		req := httptest.NewRequest(http.MethodGet, "/notes/invalid-uuid", nil)
+		noteSvc.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, input note.ListInput) ([]entity.Note, *pagination.Info, error) {
+				assert.Equal(t, []string{"green", "blue"}, input.Colors)
+				assert.Equal(t, []string{"bird"}, input.Icons)
+				return nil, &pagination.Info{Page: 1, PerPage: 20}, nil
+			})
+
+		req := httptest.NewRequest(http.MethodGet, "/notes?color=green&color=blue&icon=bird", nil)
 		w := httptest.NewRecorder()
 
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, http.StatusOK, w.Code)
 	})
 }
 
-func TestNoteHandler_Update(t *testing.T) {
-	t.Run("updates note successfully", func(t *testing.T) {
+func TestNoteHandler_Coverage(t *testing.T) {
+	t.Run("returns tiles with their bounds", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
-		defer ctrl.Finish()
-
 		noteSvc := mocks.NewMockNoteService(ctrl)
-		h := handler.NewNoteHandler(noteSvc)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
 
 		router := setupRouter()
 		userID := uuid.New()
-		noteID := uuid.New()
-		router.PUT("/notes/:id", func(c *gin.Context) {
+		router.GET("/notes/coverage", func(c *gin.Context) {
 			c.Set("user_id", userID)
-			h.Update(c)
+			h.Coverage(c)
 		})
 
-		updatedNote := &entity.Note{
-			ID:        noteID,
-			UserID:    userID,
-			Title:     "Updated Title",
-			Content:   "Updated content",
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		}
-
-		noteSvc.EXPECT().Update(gomock.Any(), userID, noteID, gomock.Any()).Return(updatedNote, nil)
+		lisbon := entity.TileAt(14, 38.7084, -9.1366)
+		noteSvc.EXPECT().Coverage(gomock.Any(), userID, 14).Return(&entity.NoteCoverage{
+			Zoom:   14,
+			Notes:  1,
+			Bounds: valueobject.NewBoundingBox(38.7084, 38.7084, -9.1366, -9.1366),
+			Tiles:  []entity.CoverageTile{{MapTile: lisbon, Notes: 1}},
+		}, nil)
 
-		body := `{"title":"Updated Title","content":"Updated content"}`
-		req := httptest.NewRequest(http.MethodPut, "/notes/"+noteID.String(), bytes.NewBufferString(body))
-		req.Header.Set("Content-Type", "application/json")
+		req := httptest.NewRequest(http.MethodGet, "/notes/coverage?zoom=14", nil)
 		w := httptest.NewRecorder()
-
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp response.CoverageResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Tiles, 1)
+		tile := resp.Tiles[0]
+		assert.Equal(t, 14, tile.Z)
+		assert.True(t, tile.Bounds.MinLat <= 38.7084 && 38.7084 < tile.Bounds.MaxLat, "tile bounds hold the note")
+		assert.True(t, tile.Bounds.MinLng <= -9.1366 && -9.1366 < tile.Bounds.MaxLng, "tile bounds hold the note")
+		assert.InDelta(t, 360.0/(1<<14), tile.Bounds.MaxLng-tile.Bounds.MinLng, 1e-9)
+	})
 
-		var resp map[string]any
-		err := json.Unmarshal(w.Body.Bytes(), &resp)
-		require.NoError(t, err)
-		assert.Equal(t, "Updated Title", resp["title"])
+	t.Run("rejects invalid zoom", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		h := handler.NewNoteHandler(mocks.NewMockNoteService(ctrl), mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		router.GET("/notes/coverage", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.Coverage(c)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/notes/coverage?zoom=25", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
+}
 
-	t.Run("returns not found for non-existent note", func(t *testing.T) {
+func TestNoteHandler_Nearby(t *testing.T) {
+	t.Run("returns notes with distance", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		noteSvc := mocks.NewMockNoteService(ctrl)
-		h := handler.NewNoteHandler(noteSvc)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
 
 		router := setupRouter()
 		userID := uuid.New()
-		noteID := uuid.New()
-		router.PUT("/notes/:id", func(c *gin.Context) {
+		router.GET("/notes/nearby", func(c *gin.Context) {
 			c.Set("user_id", userID)
-			h.Update(c)
+			h.Nearby(c)
 		})
 
-		noteSvc.EXPECT().Update(gomock.Any(), userID, noteID, gomock.Any()).Return(nil, domain.ErrNoteNotFound)
+		noteSvc.EXPECT().Nearby(gomock.Any(), note.NearbyInput{
+			UserID: userID, Latitude: 0, Longitude: -9.14, RadiusMeters: 250, Limit: 5,
+		}).Return([]entity.NearbyNote{
+			{Note: entity.Note{ID: uuid.New(), UserID: userID, Title: "Close"}, Distance: 12.5},
+		}, nil)
 
-		body := `{"title":"Updated Title"}`
-		req := httptest.NewRequest(http.MethodPut, "/notes/"+noteID.String(), bytes.NewBufferString(body))
-		req.Header.Set("Content-Type", "application/json")
+		req := httptest.NewRequest(http.MethodGet, "/notes/nearby?lat=0&lng=-9.14&radius_m=250&limit=5", nil)
 		w := httptest.NewRecorder()
 
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp response.NearbyNotesResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Notes, 1)
+		require.NotNil(t, resp.Notes[0].DistanceMeters)
+		assert.InDelta(t, 12.5, *resp.Notes[0].DistanceMeters, 0.001)
 	})
 
-	t.Run("returns forbidden for other user's note", func(t *testing.T) {
+	t.Run("returns bad request without radius", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		noteSvc := mocks.NewMockNoteService(ctrl)
-		h := handler.NewNoteHandler(noteSvc)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
 
 		router := setupRouter()
-		userID := uuid.New()
-		noteID := uuid.New()
-		router.PUT("/notes/:id", func(c *gin.Context) {
-			c.Set("user_id", userID)
-			h.Update(c)
-		})
+		router.GET("/notes/nearby", h.Nearby)
 
-		noteSvc.EXPECT().Update(gomock.Any(), userID, noteID, gomock.Any()).Return(nil, domain.ErrForbidden)
-
-		body := `{"title":"Updated Title"}`
-		req := httptest.NewRequest(http.MethodPut, "/notes/"+noteID.String(), bytes.NewBufferString(body))
-		req.Header.Set("Content-Type", "application/json")
+		req := httptest.NewRequest(http.MethodGet, "/notes/nearby?lat=38.7&lng=-9.1", nil)
 		w := httptest.NewRecorder()
 
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 }
 
-func TestNoteHandler_Delete(t *testing.T) {
-	t.Run("deletes note successfully", func(t *testing.T) {
+func TestNoteHandler_Search(t *testing.T) {
+	setup := func(t *testing.T) (*mocks.MockNoteService, *gin.Engine, uuid.UUID) {
 		ctrl := gomock.NewController(t)
-		defer ctrl.Finish()
-
 		noteSvc := mocks.NewMockNoteService(ctrl)
-		h := handler.NewNoteHandler(noteSvc)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
 
 		router := setupRouter()
 		userID := uuid.New()
-		noteID := uuid.New()
-		router.DELETE("/notes/:id", func(c *gin.Context) {
+		router.GET("/notes/search", func(c *gin.Context) {
 			c.Set("user_id", userID)
-			h.Delete(c)
+			h.Search(c)
 		})
+		return noteSvc, router, userID
+	}
 
-		noteSvc.EXPECT().Delete(gomock.Any(), userID, noteID).Return(nil)
+	t.Run("searches within a radius", func(t *testing.T) {
+		noteSvc, router, userID := setup(t)
+		distance := 40.0
+
+		noteSvc.EXPECT().Search(gomock.Any(), note.SearchInput{
+			UserID: userID, Query: "oak", Latitude: 38.7, Longitude: -9.1, RadiusMeters: 500,
+		}).Return([]entity.SearchHit{
+			{Note: entity.Note{ID: uuid.New(), UserID: userID, Title: "Oak"}, Score: 0.9, Distance: &distance},
+		}, nil)
 
-		req := httptest.NewRequest(http.MethodDelete, "/notes/"+noteID.String(), nil)
 		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/notes/search?q=oak&lat=38.7&lng=-9.1&radius_m=500", nil))
 
-		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
 
-		assert.Equal(t, http.StatusNoContent, w.Code)
+		var resp response.SearchNotesResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Notes, 1)
+		require.NotNil(t, resp.Notes[0].Score)
+		assert.InDelta(t, 0.9, *resp.Notes[0].Score, 0.001)
+		require.NotNil(t, resp.Notes[0].DistanceMeters)
+		assert.InDelta(t, 40, *resp.Notes[0].DistanceMeters, 0.001)
 	})
 
-	t.Run("returns not found for non-existent note", func(t *testing.T) {
-		ctrl := gomock.NewController(t)
-		defer ctrl.Finish()
+	t.Run("searches within a bounding box", func(t *testing.T) {
+		noteSvc, router, userID := setup(t)
 
-		noteSvc := mocks.NewMockNoteService(ctrl)
-		h := handler.NewNoteHandler(noteSvc)
+		noteSvc.EXPECT().Search(gomock.Any(), note.SearchInput{
+			UserID: userID, Query: "oak", BoundingBox: valueobject.NewBoundingBox(38, 39, -10, -9),
+		}).Return(nil, nil)
 
-		router := setupRouter()
-		userID := uuid.New()
-		noteID := uuid.New()
-		router.DELETE("/notes/:id", func(c *gin.Context) {
-			c.Set("user_id", userID)
-			h.Delete(c)
-		})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/notes/search?q=oak&min_lat=38&max_lat=39&min_lng=-10&max_lng=-9", nil))
 
-		noteSvc.EXPECT().Delete(gomock.Any(), userID, noteID).Return(domain.ErrNoteNotFound)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("requires the query", func(t *testing.T) {
+		_, router, _ := setup(t)
 
-		req := httptest.NewRequest(http.MethodDelete, "/notes/"+noteID.String(), nil)
 		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/notes/search?lat=38.7&lng=-9.1&radius_m=500", nil))
 
-		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
 
-		assert.Equal(t, http.StatusNotFound, w.Code)
+	t.Run("requires a complete point and radius", func(t *testing.T) {
+		_, router, _ := setup(t)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/notes/search?q=oak&lat=38.7&radius_m=500", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_LOCATION")
 	})
+}
 
-	t.Run("returns forbidden for other user's note", func(t *testing.T) {
+func TestNoteHandler_Export(t *testing.T) {
+	setup := func(t *testing.T) (*mocks.MockNoteService, *gin.Engine, uuid.UUID) {
 		ctrl := gomock.NewController(t)
-		defer ctrl.Finish()
-
 		noteSvc := mocks.NewMockNoteService(ctrl)
-		h := handler.NewNoteHandler(noteSvc)
+		prefSvc := mocks.NewMockPreferenceService(ctrl)
+		prefSvc.EXPECT().UnitSystem(gomock.Any(), gomock.Any()).Return("metric", nil).AnyTimes()
+		h := handler.NewNoteHandler(noteSvc, prefSvc, entity.LocationMask{})
 
 		router := setupRouter()
 		userID := uuid.New()
-		noteID := uuid.New()
-		router.DELETE("/notes/:id", func(c *gin.Context) {
+		router.GET("/notes/export", func(c *gin.Context) {
 			c.Set("user_id", userID)
-			h.Delete(c)
+			h.Export(c)
 		})
+		return noteSvc, router, userID
+	}
 
-		noteSvc.EXPECT().Delete(gomock.Any(), userID, noteID).Return(domain.ErrForbidden)
+	t.Run("streams one note per line", func(t *testing.T) {
+		noteSvc, router, userID := setup(t)
+
+		noteSvc.EXPECT().Export(gomock.Any(), note.ExportInput{UserID: userID}, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ note.ExportInput, fn func([]entity.Note) error) error {
+				if err := fn([]entity.Note{{ID: uuid.New(), UserID: userID, Number: 1, Title: "First"}, {ID: uuid.New(), UserID: userID, Number: 2, Title: "Second"}}); err != nil {
+					return err
+				}
+				return fn([]entity.Note{{ID: uuid.New(), UserID: userID, Number: 3, Title: "Third", Photos: []entity.Photo{{ID: uuid.New(), Checksum: "abc"}}}})
+			})
 
-		req := httptest.NewRequest(http.MethodDelete, "/notes/"+noteID.String(), nil)
 		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/notes/export?format=ndjson", nil))
 
-		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+		lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+		require.Len(t, lines, 3)
+		var last response.NoteResponse
+		require.NoError(t, json.Unmarshal([]byte(lines[2]), &last))
+		assert.Equal(t, "Third", last.Title)
+		require.Len(t, last.Photos, 1)
+		assert.Equal(t, "abc", last.Photos[0].Checksum)
+	})
 
-		assert.Equal(t, http.StatusForbidden, w.Code)
+	t.Run("returns an empty export", func(t *testing.T) {
+		noteSvc, router, _ := setup(t)
+
+		noteSvc.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/notes/export", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("returns internal error before the first batch", func(t *testing.T) {
+		noteSvc, router, _ := setup(t)
+
+		noteSvc.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).Return(assert.AnError)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/notes/export", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("ends with an error line when interrupted", func(t *testing.T) {
+		noteSvc, router, userID := setup(t)
+
+		noteSvc.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ note.ExportInput, fn func([]entity.Note) error) error {
+				if err := fn([]entity.Note{{ID: uuid.New(), UserID: userID, Title: "First"}}); err != nil {
+					return err
+				}
+				return assert.AnError
+			})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/notes/export", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+		require.Len(t, lines, 2)
+		assert.Contains(t, lines[1], "INTERNAL_ERROR")
+	})
+
+	t.Run("rejects unknown formats", func(t *testing.T) {
+		_, router, _ := setup(t)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/notes/export?format=xml", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("passes the filters to the service", func(t *testing.T) {
+		noteSvc, router, userID := setup(t)
+
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+		noteSvc.EXPECT().Export(gomock.Any(), note.ExportInput{
+			UserID:        userID,
+			BoundingBox:   valueobject.NewBoundingBox(38, 39, -10, -9),
+			QualityStatus: entity.QualityPassed,
+			Tags:          []string{"soil"},
+			From:          &from,
+			To:            &to,
+		}, gomock.Any()).Return(nil)
+
+		url := "/notes/export?format=csv&min_lat=38&max_lat=39&min_lng=-10&max_lng=-9&quality=passed&tag=soil" +
+			"&from=2024-01-01T00:00:00Z&to=2024-02-01T00:00:00Z"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("rejects an invalid bounding box", func(t *testing.T) {
+		_, router, _ := setup(t)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/notes/export?min_lat=39&max_lat=38&min_lng=-10&max_lng=-9", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_BBOX")
+	})
+
+	t.Run("rejects an empty date range", func(t *testing.T) {
+		noteSvc, router, _ := setup(t)
+
+		noteSvc.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).Return(domain.ErrInvalidDateRange)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/notes/export?from=2024-02-01T00:00:00Z&to=2024-01-01T00:00:00Z", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("writes csv with a header row", func(t *testing.T) {
+		noteSvc, router, userID := setup(t)
+
+		altitude := 12.5
+		noteSvc.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ note.ExportInput, fn func([]entity.Note) error) error {
+				return fn([]entity.Note{
+					{
+						ID: uuid.New(), UserID: userID, Number: 1, Reference: "PLOT-0001", Title: "=HYPERLINK(\"x\")", Content: "line one\nline two",
+						Location: valueobject.NewLocation(38.7223, -9.1393, &altitude, nil), Tags: []string{"oak", "soil"},
+						Measurements: []valueobject.Measurement{{Name: "dbh", Kind: "length", Value: 0.3}},
+						Photos:       []entity.Photo{{URL: "https://cdn/p1.jpg"}, {URL: "https://cdn/p2.jpg"}},
+					},
+					{ID: uuid.New(), UserID: userID, Number: 2, Reference: "PLOT-0002", Title: "No location"},
+				})
+			})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/notes/export?format=csv", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "notes.csv")
+
+		records, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		assert.Equal(t, "id", records[0][0])
+		assert.Equal(t, []string{"1", "PLOT-0001", `'=HYPERLINK("x")`, "line one\nline two", "38.7223", "-9.1393", "12.5", ""},
+			records[1][1:9])
+		assert.Equal(t, "oak; soil", records[1][12])
+		assert.Equal(t, "dbh=0.3 m", records[1][13])
+		assert.Equal(t, "https://cdn/p1.jpg; https://cdn/p2.jpg", records[1][14])
+		assert.Empty(t, records[2][5])
+	})
+
+	t.Run("writes gpx waypoints for located notes", func(t *testing.T) {
+		noteSvc, router, userID := setup(t)
+
+		noteSvc.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ note.ExportInput, fn func([]entity.Note) error) error {
+				return fn([]entity.Note{
+					{
+						ID: uuid.New(), UserID: userID, Reference: "PLOT-0001", Title: "Oak & ash", Content: "desc",
+						Location:  valueobject.NewLocation(38.7223, -9.1393, nil, nil),
+						CreatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+						Photos:    []entity.Photo{{URL: "https://cdn/p1.jpg", MimeType: "image/jpeg"}},
+					},
+					{ID: uuid.New(), UserID: userID, Reference: "PLOT-0002", Title: "No location"},
+				})
+			})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/notes/export?format=gpx", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/gpx+xml", w.Header().Get("Content-Type"))
+
+		var gpx struct {
+			Waypoints []struct {
+				Lat   float64 `xml:"lat,attr"`
+				Lon   float64 `xml:"lon,attr"`
+				Time  string  `xml:"time"`
+				Name  string  `xml:"name"`
+				Links []struct {
+					Href string `xml:"href,attr"`
+				} `xml:"link"`
+			} `xml:"wpt"`
+		}
+		require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &gpx))
+		require.Len(t, gpx.Waypoints, 1)
+		assert.Equal(t, 38.7223, gpx.Waypoints[0].Lat)
+		assert.Equal(t, "PLOT-0001 Oak & ash", gpx.Waypoints[0].Name)
+		assert.Equal(t, "2024-05-01T10:00:00Z", gpx.Waypoints[0].Time)
+		require.Len(t, gpx.Waypoints[0].Links, 1)
+		assert.Equal(t, "https://cdn/p1.jpg", gpx.Waypoints[0].Links[0].Href)
+	})
+
+	t.Run("leaves gpx unterminated when interrupted", func(t *testing.T) {
+		noteSvc, router, userID := setup(t)
+
+		noteSvc.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ note.ExportInput, fn func([]entity.Note) error) error {
+				if err := fn([]entity.Note{{ID: uuid.New(), UserID: userID, Location: valueobject.NewLocation(1, 2, nil, nil)}}); err != nil {
+					return err
+				}
+				return assert.AnError
+			})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/notes/export?format=gpx", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "</gpx>")
+	})
+}
+
+func TestNoteHandler_Get(t *testing.T) {
+	t.Run("gets note successfully", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		userID := uuid.New()
+		noteID := uuid.New()
+		router.GET("/notes/:id", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Get(c)
+		})
+
+		noteEntity := &entity.Note{
+			ID:        noteID,
+			UserID:    userID,
+			Title:     "Test Note",
+			Content:   "Test content",
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+
+		noteSvc.EXPECT().GetByID(gomock.Any(), userID, noteID).Return(noteEntity, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/notes/"+noteID.String(), nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp map[string]any
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		assert.Equal(t, "Test Note", resp["title"])
+	})
+
+	t.Run("generalizes sensitive location for non-owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{HighGrid: 0.1})
+
+		router := setupRouter()
+		viewerID := uuid.New()
+		noteID := uuid.New()
+		router.GET("/notes/:id", func(c *gin.Context) {
+			c.Set("user_id", viewerID)
+			h.Get(c)
+		})
+
+		altitude := 760.0
+		noteEntity := &entity.Note{
+			ID:          noteID,
+			UserID:      uuid.New(),
+			Title:       "Ninho",
+			Sensitivity: entity.SensitivityHigh,
+			Location:    valueobject.NewLocation(-23.55052, -46.63331, &altitude, nil),
+			Photos:      []entity.Photo{{ID: uuid.New(), Location: valueobject.NewLocation(-23.55052, -46.63331, nil, nil)}},
+		}
+
+		noteSvc.EXPECT().GetByID(gomock.Any(), viewerID, noteID).Return(noteEntity, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/notes/"+noteID.String(), nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp response.NoteResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.NotNil(t, resp.Location)
+		assert.True(t, resp.LocationGeneralized)
+		assert.Equal(t, entity.SensitivityHigh, resp.Sensitivity)
+		assert.InDelta(t, -23.55, resp.Location.Latitude, 1e-9)
+		assert.InDelta(t, -46.65, resp.Location.Longitude, 1e-9)
+		assert.Nil(t, resp.Location.Altitude)
+		require.Len(t, resp.Photos, 1)
+		assert.Nil(t, resp.Photos[0].Location)
+	})
+
+	t.Run("returns exact sensitive location to owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		userID := uuid.New()
+		noteID := uuid.New()
+		router.GET("/notes/:id", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Get(c)
+		})
+
+		noteEntity := &entity.Note{
+			ID:          noteID,
+			UserID:      userID,
+			Sensitivity: entity.SensitivityHigh,
+			Location:    valueobject.NewLocation(-23.55052, -46.63331, nil, nil),
+		}
+
+		noteSvc.EXPECT().GetByID(gomock.Any(), userID, noteID).Return(noteEntity, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/notes/"+noteID.String(), nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		var resp response.NoteResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.NotNil(t, resp.Location)
+		assert.False(t, resp.LocationGeneralized)
+		assert.Equal(t, -23.55052, resp.Location.Latitude)
+	})
+
+	t.Run("leaves photos excluded from shares out for non-owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		ownerID := uuid.New()
+		viewerID := uuid.New()
+		noteID := uuid.New()
+		var userID uuid.UUID
+		router.GET("/notes/:id", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Get(c)
+		})
+
+		shared := entity.Photo{ID: uuid.New()}
+		excluded := entity.Photo{ID: uuid.New(), ExcludedFromShares: true}
+		noteEntity := &entity.Note{ID: noteID, UserID: ownerID, Photos: []entity.Photo{shared, excluded}}
+
+		noteSvc.EXPECT().GetByID(gomock.Any(), gomock.Any(), noteID).Return(noteEntity, nil).Times(2)
+
+		get := func(id uuid.UUID) response.NoteResponse {
+			userID = id
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/notes/"+noteID.String(), nil))
+			require.Equal(t, http.StatusOK, w.Code)
+			var resp response.NoteResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			return resp
+		}
+
+		resp := get(viewerID)
+		require.Len(t, resp.Photos, 1)
+		assert.Equal(t, shared.ID, resp.Photos[0].ID)
+
+		resp = get(ownerID)
+		require.Len(t, resp.Photos, 2)
+		assert.True(t, resp.Photos[1].ExcludedFromShares)
+	})
+
+	t.Run("returns not found for non-existent note", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		userID := uuid.New()
+		noteID := uuid.New()
+		router.GET("/notes/:id", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Get(c)
+		})
+
+		noteSvc.EXPECT().GetByID(gomock.Any(), userID, noteID).Return(nil, domain.ErrNoteNotFound)
+
+		req := httptest.NewRequest(http.MethodGet, "/notes/"+noteID.String(), nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("returns forbidden for other user's note", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		userID := uuid.New()
+		noteID := uuid.New()
+		router.GET("/notes/:id", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Get(c)
+		})
+
+		noteSvc.EXPECT().GetByID(gomock.Any(), userID, noteID).Return(nil, domain.ErrForbidden)
+
+		req := httptest.NewRequest(http.MethodGet, "/notes/"+noteID.String(), nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("returns bad request for invalid ID", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		userID := uuid.New()
+		router.GET("/notes/:id", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Get(c)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/notes/invalid-uuid", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestNoteHandler_Update(t *testing.T) {
+	t.Run("updates note successfully", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		userID := uuid.New()
+		noteID := uuid.New()
+		router.PUT("/notes/:id", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Update(c)
+		})
+
+		updatedNote := &entity.Note{
+			ID:        noteID,
+			UserID:    userID,
+			Title:     "Updated Title",
+			Content:   "Updated content",
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+
+		noteSvc.EXPECT().Update(gomock.Any(), userID, noteID, gomock.Any()).Return(updatedNote, nil)
+
+		body := `{"title":"Updated Title","content":"Updated content"}`
+		req := httptest.NewRequest(http.MethodPut, "/notes/"+noteID.String(), bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp map[string]any
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		assert.Equal(t, "Updated Title", resp["title"])
+	})
+
+	t.Run("returns not found for non-existent note", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		userID := uuid.New()
+		noteID := uuid.New()
+		router.PUT("/notes/:id", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Update(c)
+		})
+
+		noteSvc.EXPECT().Update(gomock.Any(), userID, noteID, gomock.Any()).Return(nil, domain.ErrNoteNotFound)
+
+		body := `{"title":"Updated Title"}`
+		req := httptest.NewRequest(http.MethodPut, "/notes/"+noteID.String(), bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("returns conflict for the content of a collaborative note", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		userID := uuid.New()
+		noteID := uuid.New()
+		router.PUT("/notes/:id", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Update(c)
+		})
+
+		noteSvc.EXPECT().Update(gomock.Any(), userID, noteID, gomock.Any()).Return(nil, domain.ErrNoteCollaborative)
+
+		body := `{"content":"an old copy"}`
+		req := httptest.NewRequest(http.MethodPut, "/notes/"+noteID.String(), bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "NOTE_COLLABORATIVE")
+	})
+
+	t.Run("returns forbidden for other user's note", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		userID := uuid.New()
+		noteID := uuid.New()
+		router.PUT("/notes/:id", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Update(c)
+		})
+
+		noteSvc.EXPECT().Update(gomock.Any(), userID, noteID, gomock.Any()).Return(nil, domain.ErrForbidden)
+
+		body := `{"title":"Updated Title"}`
+		req := httptest.NewRequest(http.MethodPut, "/notes/"+noteID.String(), bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestNoteHandler_Delete(t *testing.T) {
+	t.Run("deletes note successfully", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		userID := uuid.New()
+		noteID := uuid.New()
+		router.DELETE("/notes/:id", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Delete(c)
+		})
+
+		noteSvc.EXPECT().Delete(gomock.Any(), userID, noteID).Return(nil)
+
+		req := httptest.NewRequest(http.MethodDelete, "/notes/"+noteID.String(), nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("returns not found for non-existent note", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		userID := uuid.New()
+		noteID := uuid.New()
+		router.DELETE("/notes/:id", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Delete(c)
+		})
+
+		noteSvc.EXPECT().Delete(gomock.Any(), userID, noteID).Return(domain.ErrNoteNotFound)
+
+		req := httptest.NewRequest(http.MethodDelete, "/notes/"+noteID.String(), nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("returns forbidden for other user's note", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		userID := uuid.New()
+		noteID := uuid.New()
+		router.DELETE("/notes/:id", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Delete(c)
+		})
+
+		noteSvc.EXPECT().Delete(gomock.Any(), userID, noteID).Return(domain.ErrForbidden)
+
+		req := httptest.NewRequest(http.MethodDelete, "/notes/"+noteID.String(), nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestNoteHandler_Restore(t *testing.T) {
+	t.Run("restores note", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		userID := uuid.New()
+		noteID := uuid.New()
+		router.POST("/notes/:id/restore", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Restore(c)
+		})
+
+		noteSvc.EXPECT().Restore(gomock.Any(), userID, noteID).Return(&entity.Note{ID: noteID, UserID: userID, Title: "Back"}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/notes/"+noteID.String()+"/restore", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("returns gone after the restore window", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		noteID := uuid.New()
+		router.POST("/notes/:id/restore", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.Restore(c)
+		})
+
+		noteSvc.EXPECT().Restore(gomock.Any(), gomock.Any(), noteID).Return(nil, domain.ErrRestoreExpired)
+
+		req := httptest.NewRequest(http.MethodPost, "/notes/"+noteID.String()+"/restore", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusGone, w.Code)
+	})
+}
+
+func TestNoteHandler_Merge(t *testing.T) {
+	t.Run("merges notes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		userID := uuid.New()
+		router.POST("/notes/merge", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Merge(c)
+		})
+
+		a, b := uuid.New(), uuid.New()
+		noteSvc.EXPECT().Merge(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ any, input note.MergeInput) (*entity.Note, error) {
+				assert.Equal(t, userID, input.UserID)
+				assert.Equal(t, []uuid.UUID{a, b}, input.NoteIDs)
+				assert.Equal(t, entity.MergeContentConcatenate, input.Content)
+				return &entity.Note{ID: a, UserID: userID, Title: "Merged"}, nil
+			})
+
+		body := `{"note_ids":["` + a.String() + `","` + b.String() + `"]}`
+		req := httptest.NewRequest(http.MethodPost, "/notes/merge", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("returns bad request for a single note", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		router.POST("/notes/merge", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.Merge(c)
+		})
+
+		body := `{"note_ids":["` + uuid.New().String() + `"]}`
+		req := httptest.NewRequest(http.MethodPost, "/notes/merge", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestNoteHandler_Exists(t *testing.T) {
+	t.Run("returns found and missing notes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		userID := uuid.New()
+		router.POST("/notes/exists", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Exists(c)
+		})
+
+		found, missing := uuid.New(), uuid.New()
+		noteSvc.EXPECT().Exists(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ any, input note.ExistsInput) (*note.ExistsResult, error) {
+				assert.Equal(t, userID, input.UserID)
+				assert.Equal(t, []uuid.UUID{found, missing}, input.IDs)
+				assert.Equal(t, []string{"local-1"}, input.ClientIDs)
+				return &note.ExistsResult{
+					Found:            []entity.NoteExistence{{ID: found, ClientID: "local-9", UpdatedAt: time.Now()}},
+					MissingIDs:       []uuid.UUID{missing},
+					MissingClientIDs: []string{"local-1"},
+				}, nil
+			})
+
+		body := `{"ids":["` + found.String() + `","` + missing.String() + `"],"client_ids":["local-1"]}`
+		req := httptest.NewRequest(http.MethodPost, "/notes/exists", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp response.NotesExistResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Notes, 1)
+		assert.Equal(t, found, resp.Notes[0].ID)
+		assert.Equal(t, []uuid.UUID{missing}, resp.MissingIDs)
+		assert.Equal(t, []string{"local-1"}, resp.MissingClientIDs)
+	})
+
+	t.Run("returns bad request without ids", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		router.POST("/notes/exists", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.Exists(c)
+		})
+
+		for _, body := range []string{`{}`, `{"ids":["not-a-uuid"]}`} {
+			req := httptest.NewRequest(http.MethodPost, "/notes/exists", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, body)
+		}
+	})
+}
+
+func TestNoteHandler_AddTags(t *testing.T) {
+	t.Run("tags note", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		userID := uuid.New()
+		noteID := uuid.New()
+		router.POST("/notes/:id/tags", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.AddTags(c)
+		})
+
+		noteSvc.EXPECT().AddTags(gomock.Any(), note.TagsInput{UserID: userID, NoteID: noteID, Tags: []string{"wildlife"}}).
+			Return(&entity.Note{ID: noteID, UserID: userID, Tags: []string{"wildlife"}}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/notes/"+noteID.String()+"/tags", bytes.NewBufferString(`{"tags":["wildlife"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp response.NoteResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, []string{"wildlife"}, resp.Tags)
+	})
+
+	t.Run("returns bad request for invalid tag", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		noteID := uuid.New()
+		router.POST("/notes/:id/tags", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.AddTags(c)
+		})
+
+		noteSvc.EXPECT().AddTags(gomock.Any(), gomock.Any()).Return(nil, domain.ErrInvalidTag)
+
+		req := httptest.NewRequest(http.MethodPost, "/notes/"+noteID.String()+"/tags", bytes.NewBufferString(`{"tags":["soil sample"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 }
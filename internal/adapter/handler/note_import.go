@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/note"
+)
+
+// Import formats.
+const (
+	importGeoJSON = "geojson"
+	importCSV     = "csv"
+)
+
+const maxImportSize = 10 << 20 // 10MB
+
+// errTooManyImportRows is returned by the parsers once a file goes past
+// note.MaxImportRows, without reading the rest.
+var errTooManyImportRows = fmt.Errorf("file has more than %d notes", note.MaxImportRows)
+
+// importFormat picks the format from the request or, when omitted, from the
+// file extension.
+func importFormat(format, filename string) string {
+	if format != "" {
+		return format
+	}
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".geojson", ".json":
+		return importGeoJSON
+	case ".csv":
+		return importCSV
+	}
+	return ""
+}
+
+func parseImport(format string, r io.Reader) ([]note.ImportRow, error) {
+	if format == importCSV {
+		return parseImportCSV(r)
+	}
+	return parseImportGeoJSON(r)
+}
+
+type geoJSONFeature struct {
+	ID       any `json:"id"`
+	Geometry *struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	} `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+// parseImportGeoJSON reads a FeatureCollection, or a single Feature, with
+// one note per feature. Point geometries give the location as
+// [longitude, latitude, altitude]; features without a geometry have none.
+// Properties are read as in parseImportCSV; a feature's id is the client ID
+// when the properties have none.
+func parseImportGeoJSON(r io.Reader) ([]note.ImportRow, error) {
+	var doc struct {
+		Type     string            `json:"type"`
+		Features []json.RawMessage `json:"features"`
+	}
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, errors.New("file is not valid JSON")
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, errors.New("file is not a GeoJSON FeatureCollection or Feature")
+	}
+	switch doc.Type {
+	case "FeatureCollection":
+	case "Feature":
+		doc.Features = []json.RawMessage{raw}
+	default:
+		return nil, errors.New("file is not a GeoJSON FeatureCollection or Feature")
+	}
+	if len(doc.Features) > note.MaxImportRows {
+		return nil, errTooManyImportRows
+	}
+
+	rows := make([]note.ImportRow, len(doc.Features))
+	for i, data := range doc.Features {
+		row := &rows[i]
+		row.Row = i + 1
+
+		var f geoJSONFeature
+		if err := json.Unmarshal(data, &f); err != nil {
+			row.Err = errors.New("feature is not valid GeoJSON")
+			continue
+		}
+
+		fields := make(map[string]string, len(f.Properties))
+		for k, v := range f.Properties {
+			switch v := v.(type) {
+			case nil:
+			case string:
+				fields[strings.ToLower(k)] = v
+			case []any:
+				// Tags may be a JSON array.
+				parts := make([]string, 0, len(v))
+				for _, p := range v {
+					parts = append(parts, fmt.Sprint(p))
+				}
+				fields[strings.ToLower(k)] = strings.Join(parts, ";")
+			default:
+				encoded, _ := json.Marshal(v)
+				fields[strings.ToLower(k)] = string(encoded)
+			}
+		}
+		if _, ok := fields["client_id"]; !ok && f.ID != nil {
+			fields["client_id"] = fmt.Sprint(f.ID)
+		}
+		if row.Err = readImportFields(row, fields); row.Err != nil {
+			continue
+		}
+
+		if f.Geometry == nil {
+			continue
+		}
+		var point []float64
+		if f.Geometry.Type != "Point" || json.Unmarshal(f.Geometry.Coordinates, &point) != nil || len(point) < 2 {
+			row.Err = errors.New("geometry must be a Point")
+			continue
+		}
+		row.Longitude = &point[0]
+		row.Latitude = &point[1]
+		if len(point) > 2 {
+			row.Altitude = &point[2]
+		}
+	}
+	return rows, nil
+}
+
+// parseImportCSV reads a CSV file with a header row, one note per row.
+// Columns are matched by name, case-insensitively, and unknown ones are
+// ignored, so the CSV export can be imported back.
+func parseImportCSV(r io.Reader) ([]note.ImportRow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, errors.New("file has no CSV header row")
+	}
+	columns := make([]string, len(header))
+	for i, name := range header {
+		columns[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+	}
+
+	var rows []note.ImportRow
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if len(rows) == note.MaxImportRows {
+			return nil, errTooManyImportRows
+		}
+		row := note.ImportRow{Row: len(rows) + 1}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("reading CSV: %w", err)
+			}
+			// Quoting errors leave the reader in sync, so later rows still load.
+			row.Err = errors.New("row is not valid CSV")
+			rows = append(rows, row)
+			continue
+		}
+
+		fields := make(map[string]string, len(columns))
+		for i, value := range record {
+			if i < len(columns) {
+				fields[columns[i]] = unescapeCSV(value)
+			}
+		}
+		row.Err = readImportFields(&row, fields)
+		if row.Err == nil {
+			row.Latitude, row.Err = importFloat(fields, "latitude", "lat")
+		}
+		if row.Err == nil {
+			row.Longitude, row.Err = importFloat(fields, "longitude", "lng", "lon")
+		}
+		if row.Err == nil {
+			row.Altitude, row.Err = importFloat(fields, "altitude")
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// readImportFields sets the row from the named fields. Title falls back to
+// name and content to description, as other tools export them, and the
+// client ID to id, as the CSV export writes it. Tags are separated by ";"
+// or ",".
+func readImportFields(row *note.ImportRow, fields map[string]string) error {
+	row.ClientID = strings.TrimSpace(firstField(fields, "client_id", "id"))
+	row.Title = strings.TrimSpace(firstField(fields, "title", "name"))
+	row.Content = firstField(fields, "content", "description")
+
+	for _, tag := range strings.FieldsFunc(fields["tags"], func(r rune) bool { return r == ';' || r == ',' }) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			row.Tags = append(row.Tags, tag)
+		}
+	}
+
+	var err error
+	if row.Accuracy, err = importFloat(fields, "accuracy"); err != nil {
+		return err
+	}
+	if v := strings.TrimSpace(fields["created_at"]); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return errors.New("created_at must be an RFC 3339 time")
+		}
+		row.CreatedAt = &t
+	}
+	return nil
+}
+
+func firstField(fields map[string]string, names ...string) string {
+	for _, name := range names {
+		if v := fields[name]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// importFloat parses the first of the named fields that is set.
+func importFloat(fields map[string]string, names ...string) (*float64, error) {
+	v := strings.TrimSpace(firstField(fields, names...))
+	if v == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("%s must be a number", names[0])
+	}
+	return &f, nil
+}
+
+// unescapeCSV drops the quote csvSafe puts in front of values that look
+// like formulas.
+func unescapeCSV(s string) string {
+	if len(s) > 1 && s[0] == '\'' && strings.ContainsRune("=+-@\t\r", rune(s[1])) {
+		return s[1:]
+	}
+	return s
+}
@@ -0,0 +1,145 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/note"
+)
+
+func TestNoteHandler_Import(t *testing.T) {
+	setup := func(t *testing.T) (*mocks.MockNoteService, *gin.Engine, uuid.UUID) {
+		ctrl := gomock.NewController(t)
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		userID := uuid.New()
+		router.POST("/notes/import", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Import(c)
+		})
+		return noteSvc, router, userID
+	}
+
+	// importRows runs an import of the file and returns the rows the
+	// service received.
+	importRows := func(t *testing.T, filename, content string) []note.ImportRow {
+		t.Helper()
+		noteSvc, router, userID := setup(t)
+
+		var rows []note.ImportRow
+		noteSvc.EXPECT().Import(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ any, input note.ImportInput) ([]note.ImportResult, error) {
+				assert.Equal(t, userID, input.UserID)
+				assert.Equal(t, filename, input.Filename)
+				rows = input.Rows
+				return nil, nil
+			})
+
+		req, _ := createMultipartRequest(t, "/notes/import", "file", filename, "application/octet-stream", []byte(content))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		return rows
+	}
+
+	t.Run("reads geojson features", func(t *testing.T) {
+		rows := importRows(t, "plots.geojson", `{
+			"type": "FeatureCollection",
+			"features": [
+				{"type": "Feature", "id": 17, "geometry": {"type": "Point", "coordinates": [-9.14, 38.72, 80]},
+				 "properties": {"name": "Plot 1", "description": "Clay", "tags": ["soil", "plot"], "created_at": "2023-05-01T09:00:00Z"}},
+				{"type": "Feature", "geometry": null, "properties": {"title": "No location", "client_id": "c-2"}},
+				{"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[0, 0], [1, 1]]}, "properties": {"title": "Track"}}
+			]
+		}`)
+
+		require.Len(t, rows, 3)
+		assert.Equal(t, "17", rows[0].ClientID)
+		assert.Equal(t, "Plot 1", rows[0].Title)
+		assert.Equal(t, "Clay", rows[0].Content)
+		assert.Equal(t, []string{"soil", "plot"}, rows[0].Tags)
+		require.NotNil(t, rows[0].Latitude)
+		assert.Equal(t, 38.72, *rows[0].Latitude)
+		assert.Equal(t, -9.14, *rows[0].Longitude)
+		assert.Equal(t, 80.0, *rows[0].Altitude)
+		require.NotNil(t, rows[0].CreatedAt)
+
+		assert.Equal(t, "c-2", rows[1].ClientID)
+		assert.Nil(t, rows[1].Latitude)
+		assert.NoError(t, rows[1].Err)
+
+		assert.EqualError(t, rows[2].Err, "geometry must be a Point")
+		assert.Equal(t, 3, rows[2].Row)
+	})
+
+	t.Run("reads csv rows by column name", func(t *testing.T) {
+		rows := importRows(t, "plots.csv", "Title,Lat,Lng,Tags,content,extra\n"+
+			"Plot 1,38.72,-9.14,soil; plot,'=1+1,ignored\n"+
+			"Plot 2,north,-9.14,,,\n"+
+			"Plot 3,,,,,\n")
+
+		require.Len(t, rows, 3)
+		assert.Equal(t, "Plot 1", rows[0].Title)
+		assert.Equal(t, 38.72, *rows[0].Latitude)
+		assert.Equal(t, []string{"soil", "plot"}, rows[0].Tags)
+		assert.Equal(t, "=1+1", rows[0].Content, "the export's formula guard is undone")
+		assert.EqualError(t, rows[1].Err, "latitude must be a number")
+		assert.Nil(t, rows[2].Latitude)
+		assert.NoError(t, rows[2].Err)
+	})
+
+	t.Run("returns the summary", func(t *testing.T) {
+		noteSvc, router, _ := setup(t)
+		noteID := uuid.New()
+		noteSvc.EXPECT().Import(gomock.Any(), gomock.Any()).Return([]note.ImportResult{
+			{Row: 1, ClientID: "a", Status: note.ImportCreated, NoteID: noteID},
+			{Row: 2, ClientID: "b", Status: note.ImportFailed, Err: assert.AnError},
+		}, nil)
+
+		req, _ := createMultipartRequest(t, "/notes/import?format=csv", "file", "plots.txt", "text/plain", []byte("title\na\nb\n"))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp response.ImportResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 1, resp.Created)
+		assert.Equal(t, 1, resp.Failed)
+		require.Len(t, resp.Rows, 2)
+		assert.Equal(t, noteID, *resp.Rows[0].NoteID)
+		assert.Equal(t, assert.AnError.Error(), resp.Rows[1].Error)
+	})
+
+	t.Run("rejects unreadable files", func(t *testing.T) {
+		for name, tt := range map[string]struct{ filename, content string }{
+			"unknown extension": {"plots.kml", "<kml/>"},
+			"invalid json":      {"plots.geojson", "{"},
+			"not geojson":       {"plots.json", `{"type": "Polygon"}`},
+			"too many rows":     {"plots.csv", "title\n" + strings.Repeat("a\n", note.MaxImportRows+1)},
+		} {
+			_, router, _ := setup(t)
+
+			req, _ := createMultipartRequest(t, "/notes/import", "file", tt.filename, "application/octet-stream", []byte(tt.content))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, name)
+		}
+	})
+}
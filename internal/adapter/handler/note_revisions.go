@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/request"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/note"
+)
+
+// Revisions godoc
+//
+//	@Summary		List a note's revisions
+//	@Description	List the versions of a note that updates replaced, newest first. Every update that changes the title, content, location, measurements, color or icon keeps the version it replaces, sync included, so a version lost to a conflicting device can be found and restored; edits to the content of a collaborative note are not kept. Offloaded content is listed as its excerpt, with content_url. Anyone who can read the note can list them.
+//	@Tags			notes
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			id		path		string	true	"Note ID"	format(uuid)
+//	@Param			before	query		int		false	"List the revisions numbered below this one"
+//	@Param			limit	query		int		false	"Maximum number of revisions (default 20, max 100)"
+//	@Param			units	query		string	false	"Unit system for measurements, defaults to the user's preference"	Enums(metric, imperial)
+//	@Success		200		{object}	response.NoteRevisionsResponse
+//	@Failure		400		{object}	httputil.ErrorResponse
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Failure		403		{object}	httputil.ErrorResponse
+//	@Failure		404		{object}	httputil.ErrorResponse
+//	@Router			/notes/{id}/revisions [get]
+func (h *NoteHandler) Revisions(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidID, "invalid note id")
+		return
+	}
+
+	var req request.ListRevisionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	revisions, err := h.noteSvc.Revisions(c.Request.Context(), note.RevisionsInput{
+		UserID: httputil.GetUserID(c),
+		NoteID: noteID,
+		Before: req.Before,
+		Limit:  req.Limit,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNoteNotFound):
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "note not found")
+		case errors.Is(err, domain.ErrForbidden):
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeForbidden, "access denied")
+		default:
+			httputil.InternalError(c)
+		}
+		return
+	}
+
+	withMeasurements := false
+	for _, r := range revisions {
+		withMeasurements = withMeasurements || len(r.Measurements) > 0
+	}
+	httputil.OK(c, response.NoteRevisionsFromEntities(revisions, noteView(c, h.prefSvc, h.mask, withMeasurements)))
+}
+
+// RestoreRevision godoc
+//
+//	@Summary		Restore a note revision
+//	@Description	Put the title, content, location, measurements, color and icon of a revision back on the note. This is an update like PUT /notes/{id}, with its permissions, so the version it replaces becomes a revision in turn and the restore can be undone. A revision without a location leaves the current one. The content of a collaborative note can only be restored once collaboration is disabled.
+//	@Tags			notes
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			id		path		string	true	"Note ID"	format(uuid)
+//	@Param			rev		path		int		true	"Revision number"
+//	@Param			units	query		string	false	"Unit system for measurements, defaults to the user's preference"	Enums(metric, imperial)
+//	@Success		200		{object}	response.NoteResponse
+//	@Failure		400		{object}	httputil.ErrorResponse
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Failure		403		{object}	httputil.ErrorResponse
+//	@Failure		404		{object}	httputil.ErrorResponse
+//	@Failure		409		{object}	httputil.ErrorResponse
+//	@Router			/notes/{id}/revisions/{rev}/restore [post]
+func (h *NoteHandler) RestoreRevision(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidID, "invalid note id")
+		return
+	}
+	revision, err := strconv.Atoi(c.Param("rev"))
+	if err != nil || revision < 1 {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidID, "invalid revision")
+		return
+	}
+
+	n, err := h.noteSvc.RestoreRevision(c.Request.Context(), note.RestoreRevisionInput{
+		UserID:   httputil.GetUserID(c),
+		NoteID:   noteID,
+		Revision: revision,
+		DeviceID: httputil.GetDeviceID(c),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrRevisionNotFound):
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "revision not found")
+		case errors.Is(err, domain.ErrNoteCollaborative):
+			httputil.ErrorWithCode(c, http.StatusConflict, httputil.CodeCollaborative, "the content of a collaborative note is changed with updates to its document")
+		case errors.Is(err, domain.ErrNoteNotFound):
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "note not found")
+		case errors.Is(err, domain.ErrForbidden):
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeForbidden, "access denied")
+		default:
+			httputil.InternalError(c)
+		}
+		return
+	}
+
+	httputil.MarkNotesChanged(c)
+	httputil.OK(c, response.NoteFromEntity(n, noteView(c, h.prefSvc, h.mask, len(n.Measurements) > 0)))
+}
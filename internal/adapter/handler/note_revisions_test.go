@@ -0,0 +1,145 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/note"
+)
+
+func TestNoteHandler_Revisions(t *testing.T) {
+	setup := func(t *testing.T) (*mocks.MockNoteService, *gin.Engine, uuid.UUID) {
+		ctrl := gomock.NewController(t)
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		userID := uuid.New()
+		router.GET("/notes/:id/revisions", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Revisions(c)
+		})
+		return noteSvc, router, userID
+	}
+
+	t.Run("lists revisions with sensitive locations generalized", func(t *testing.T) {
+		noteSvc, router, userID := setup(t)
+		noteID := uuid.New()
+
+		noteSvc.EXPECT().Revisions(gomock.Any(), note.RevisionsInput{UserID: userID, NoteID: noteID, Before: 4, Limit: 2}).
+			Return([]entity.NoteRevision{{
+				NoteID: noteID, UserID: uuid.New(), Revision: 3, Title: "Ninho", Content: "Two eggs",
+				Sensitivity: entity.SensitivityHigh,
+				Location:    valueobject.NewLocation(-23.55052, -46.63331, nil, nil),
+			}}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/notes/"+noteID.String()+"/revisions?before=4&limit=2", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp response.NoteRevisionsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Revisions, 1)
+		assert.Equal(t, 3, resp.Revisions[0].Revision)
+		assert.Equal(t, "Two eggs", resp.Revisions[0].Content)
+		require.NotNil(t, resp.Revisions[0].Location)
+		assert.True(t, resp.Revisions[0].LocationGeneralized)
+		assert.InDelta(t, -46.65, resp.Revisions[0].Location.Longitude, 1e-9)
+	})
+
+	t.Run("rejects a limit over 100", func(t *testing.T) {
+		_, router, _ := setup(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/notes/"+uuid.NewString()+"/revisions?limit=500", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("returns not found for a missing note", func(t *testing.T) {
+		noteSvc, router, _ := setup(t)
+		noteSvc.EXPECT().Revisions(gomock.Any(), gomock.Any()).Return(nil, domain.ErrNoteNotFound)
+
+		req := httptest.NewRequest(http.MethodGet, "/notes/"+uuid.NewString()+"/revisions", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestNoteHandler_RestoreRevision(t *testing.T) {
+	setup := func(t *testing.T) (*mocks.MockNoteService, *gin.Engine, uuid.UUID) {
+		ctrl := gomock.NewController(t)
+		noteSvc := mocks.NewMockNoteService(ctrl)
+		h := handler.NewNoteHandler(noteSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		userID := uuid.New()
+		router.POST("/notes/:id/revisions/:rev/restore", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.RestoreRevision(c)
+		})
+		return noteSvc, router, userID
+	}
+
+	t.Run("restores the revision", func(t *testing.T) {
+		noteSvc, router, userID := setup(t)
+		noteID := uuid.New()
+
+		noteSvc.EXPECT().RestoreRevision(gomock.Any(), note.RestoreRevisionInput{UserID: userID, NoteID: noteID, Revision: 2}).
+			Return(&entity.Note{ID: noteID, UserID: userID, Title: "Oak survey"}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/notes/"+noteID.String()+"/revisions/2/restore", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp response.NoteResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "Oak survey", resp.Title)
+	})
+
+	t.Run("rejects an invalid revision number", func(t *testing.T) {
+		_, router, _ := setup(t)
+
+		req := httptest.NewRequest(http.MethodPost, "/notes/"+uuid.NewString()+"/revisions/0/restore", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("maps service errors", func(t *testing.T) {
+		for err, status := range map[error]int{
+			domain.ErrRevisionNotFound:  http.StatusNotFound,
+			domain.ErrNoteCollaborative: http.StatusConflict,
+			domain.ErrForbidden:         http.StatusForbidden,
+		} {
+			noteSvc, router, _ := setup(t)
+			noteSvc.EXPECT().RestoreRevision(gomock.Any(), gomock.Any()).Return(nil, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/notes/"+uuid.NewString()+"/revisions/1/restore", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, status, w.Code, err.Error())
+		}
+	})
+}
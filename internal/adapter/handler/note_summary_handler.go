@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/request"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/summary"
+)
+
+type NoteSummaryHandler struct {
+	summarySvc NoteSummaryService
+	mask       entity.LocationMask
+}
+
+func NewNoteSummaryHandler(summarySvc NoteSummaryService, mask entity.LocationMask) *NoteSummaryHandler {
+	return &NoteSummaryHandler{summarySvc: summarySvc, mask: mask}
+}
+
+// List godoc
+//
+//	@Summary		List note summaries
+//	@Description	Get the notes as lists and maps show them: title, excerpt, cover thumbnail, location, color and icon, tags and photo count, without loading the notes themselves. Summaries are kept up to date as notes, tags and photos change. Pages with next_cursor.
+//	@Tags			notes
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			per_page	query		int			false	"Items per page"	default(20)
+//	@Param			min_lat		query		number		false	"Minimum latitude for bounding box"
+//	@Param			max_lat		query		number		false	"Maximum latitude for bounding box"
+//	@Param			min_lng		query		number		false	"Minimum longitude for bounding box"
+//	@Param			max_lng		query		number		false	"Maximum longitude for bounding box"
+//	@Param			tag			query		[]string	false	"Only notes with all of these tags"	collectionFormat(multi)
+//	@Param			color		query		[]string	false	"Only notes with any of these colors"	collectionFormat(multi)
+//	@Param			icon		query		[]string	false	"Only notes with any of these icons"	collectionFormat(multi)
+//	@Param			cursor		query		string		false	"Opaque next_cursor from a previous page"
+//	@Param			team_id		query		string		false	"List the notes of this team, by any member, instead of your own"	format(uuid)
+//	@Success		200			{object}	response.NoteSummariesResponse
+//	@Failure		400			{object}	httputil.ErrorResponse
+//	@Failure		401			{object}	httputil.ErrorResponse
+//	@Failure		403			{object}	httputil.ErrorResponse
+//	@Router			/notes/summaries [get]
+func (h *NoteSummaryHandler) List(c *gin.Context) {
+	var req request.ListNoteSummariesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	bbox, ok := boundingBox(req.MinLat, req.MaxLat, req.MinLng, req.MaxLng)
+	if !ok {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidBBox, "invalid bounding box")
+		return
+	}
+
+	summaries, pageInfo, err := h.summarySvc.List(c.Request.Context(), summary.ListInput{
+		UserID:      httputil.GetUserID(c),
+		PerPage:     req.PerPage,
+		Cursor:      req.Cursor,
+		TeamID:      optionalUUID(req.TeamID),
+		BoundingBox: bbox,
+		Tags:        req.Tags,
+		Colors:      req.Colors,
+		Icons:       req.Icons,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrForbidden):
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeForbidden, "not a member of the team")
+		case errors.Is(err, domain.ErrInvalidTag):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "invalid tag")
+		case errors.Is(err, domain.ErrInvalidNoteColor):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, noteColorMessage)
+		case errors.Is(err, domain.ErrInvalidNoteIcon):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, noteIconMessage)
+		case errors.Is(err, domain.ErrInvalidCursor):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidCursor, "invalid cursor")
+		default:
+			httputil.InternalError(c)
+		}
+		return
+	}
+
+	httputil.OK(c, response.NoteSummariesResponse{
+		Notes:      response.NoteSummariesFromEntities(summaries, response.NoteView{Viewer: httputil.GetUserID(c), Mask: h.mask}),
+		Pagination: response.PaginationFromInfo(pageInfo),
+	})
+}
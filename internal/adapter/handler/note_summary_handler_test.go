@@ -0,0 +1,84 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/summary"
+)
+
+func setupNoteSummaryRouter(t *testing.T) (*mocks.MockNoteSummaryService, *gin.Engine, uuid.UUID) {
+	ctrl := gomock.NewController(t)
+	summarySvc := mocks.NewMockNoteSummaryService(ctrl)
+	h := handler.NewNoteSummaryHandler(summarySvc, entity.LocationMask{})
+
+	router := setupRouter()
+	userID := uuid.New()
+	router.GET("/notes/summaries", func(c *gin.Context) { c.Set("user_id", userID) }, h.List)
+	return summarySvc, router, userID
+}
+
+func TestNoteSummaryHandler_List(t *testing.T) {
+	t.Run("shows team members the shared photo count", func(t *testing.T) {
+		summarySvc, router, userID := setupNoteSummaryRouter(t)
+		teamID := uuid.New()
+		stored := entity.NoteSummary{
+			ID: uuid.New(), UserID: uuid.New(), TeamID: &teamID, Title: "Plot 1",
+			Tags: []string{"soil-sample"}, PhotoCount: 3, SharedPhotoCount: 2,
+		}
+		summarySvc.EXPECT().List(gomock.Any(), summary.ListInput{
+			UserID: userID, PerPage: 10, TeamID: &teamID, Tags: []string{"soil-sample"},
+		}).Return([]entity.NoteSummary{stored}, &pagination.Info{PerPage: 10}, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+			"/notes/summaries?per_page=10&tag=soil-sample&team_id="+teamID.String(), nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp response.NoteSummariesResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Notes, 1)
+		assert.Equal(t, "Plot 1", resp.Notes[0].Title)
+		assert.Equal(t, 2, resp.Notes[0].PhotoCount)
+	})
+
+	t.Run("rejects an invalid bounding box", func(t *testing.T) {
+		_, router, _ := setupNoteSummaryRouter(t)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+			"/notes/summaries?min_lat=10&max_lat=5&min_lng=0&max_lng=1", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("maps service errors", func(t *testing.T) {
+		for err, status := range map[error]int{
+			domain.ErrForbidden:     http.StatusForbidden,
+			domain.ErrInvalidCursor: http.StatusBadRequest,
+			domain.ErrInvalidTag:    http.StatusBadRequest,
+		} {
+			summarySvc, router, _ := setupNoteSummaryRouter(t)
+			summarySvc.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, nil, err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/notes/summaries", nil))
+
+			assert.Equal(t, status, w.Code, err.Error())
+		}
+	})
+}
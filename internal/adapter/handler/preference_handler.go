@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/request"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/preference"
+)
+
+type PreferenceHandler struct {
+	prefSvc PreferenceService
+}
+
+func NewPreferenceHandler(prefSvc PreferenceService) *PreferenceHandler {
+	return &PreferenceHandler{prefSvc: prefSvc}
+}
+
+// Get godoc
+//
+//	@Summary		Get preferences
+//	@Description	Get the current user's preferences
+//	@Tags			preferences
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{object}	response.PreferencesResponse
+//	@Failure		401	{object}	httputil.ErrorResponse
+//	@Router			/me/preferences [get]
+func (h *PreferenceHandler) Get(c *gin.Context) {
+	user, err := h.prefSvc.Get(c.Request.Context(), httputil.GetUserID(c))
+	if err != nil {
+		httputil.InternalError(c)
+		return
+	}
+
+	httputil.OK(c, response.PreferencesFromEntity(user))
+}
+
+// Update godoc
+//
+//	@Summary		Update preferences
+//	@Description	Set the unit system measurements are returned in, the prefix of new note references, sync conflict notices and whether notes are indexed for semantic search
+//	@Tags			preferences
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		request.PreferencesRequest	true	"Preferences"
+//	@Success		200		{object}	response.PreferencesResponse
+//	@Failure		400		{object}	httputil.ErrorResponse
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Router			/me/preferences [put]
+func (h *PreferenceHandler) Update(c *gin.Context) {
+	var req request.PreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	user, err := h.prefSvc.Update(c.Request.Context(), preference.UpdateInput{
+		UserID:              httputil.GetUserID(c),
+		UnitSystem:          req.UnitSystem,
+		NotePrefix:          req.NotePrefix,
+		NotifySyncConflicts: req.NotifySyncConflicts,
+		SemanticSearch:      req.SemanticSearch,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidUnitSystem):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "unit_system must be metric or imperial")
+		case errors.Is(err, domain.ErrInvalidNotePrefix):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "note_prefix must be 1-16 letters or digits")
+		default:
+			httputil.InternalError(c)
+		}
+		return
+	}
+
+	httputil.OK(c, response.PreferencesFromEntity(user))
+}
+
+// unitSystem picks the unit system for measurements in a response: the units
+// query parameter when valid, otherwise the user's preference. The preference
+// is only looked up when the response has measurements to convert.
+func unitSystem(c *gin.Context, prefSvc PreferenceService, hasMeasurements bool) string {
+	if units := c.Query("units"); valueobject.IsUnitSystem(units) {
+		return units
+	}
+	if !hasMeasurements {
+		return valueobject.UnitSystemMetric
+	}
+
+	system, err := prefSvc.UnitSystem(c.Request.Context(), httputil.GetUserID(c))
+	if err != nil {
+		// Measurements are still correct in metric; don't fail the request over display.
+		return valueobject.UnitSystemMetric
+	}
+	return system
+}
+
+// noteView presents notes to the requesting user; see response.NoteView.
+func noteView(c *gin.Context, prefSvc PreferenceService, mask entity.LocationMask, hasMeasurements bool) response.NoteView {
+	return response.NoteView{
+		Units:  unitSystem(c, prefSvc, hasMeasurements),
+		Viewer: httputil.GetUserID(c),
+		Mask:   mask,
+	}
+}
+
+func hasMeasurements(notes ...entity.Note) bool {
+	for _, n := range notes {
+		if len(n.Measurements) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// measurementsFromRequest normalizes request measurements to SI. A nil slice
+// stays nil so updates can tell "unchanged" from "cleared".
+func measurementsFromRequest(reqs []request.MeasurementRequest) ([]valueobject.Measurement, bool) {
+	if reqs == nil {
+		return nil, true
+	}
+
+	measurements := make([]valueobject.Measurement, 0, len(reqs))
+	for _, r := range reqs {
+		m, ok := valueobject.NewMeasurement(r.Name, r.Value, r.Unit)
+		if !ok {
+			return nil, false
+		}
+		measurements = append(measurements, m)
+	}
+	return measurements, true
+}
@@ -0,0 +1,78 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/preference"
+)
+
+func TestPreferenceHandler_Update(t *testing.T) {
+	t.Run("updates preferences", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		prefSvc := mocks.NewMockPreferenceService(ctrl)
+		h := handler.NewPreferenceHandler(prefSvc)
+
+		router := setupRouter()
+		userID := uuid.New()
+		router.PUT("/me/preferences", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Update(c)
+		})
+
+		prefSvc.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ any, input preference.UpdateInput) (*entity.User, error) {
+				assert.Equal(t, userID, input.UserID)
+				return &entity.User{ID: userID, UnitSystem: input.UnitSystem, NotePrefix: "PLOT"}, nil
+			})
+
+		req := httptest.NewRequest(http.MethodPut, "/me/preferences", bytes.NewBufferString(`{"unit_system":"imperial","note_prefix":"plot"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp response.PreferencesResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "imperial", resp.UnitSystem)
+		assert.Equal(t, "PLOT", resp.NotePrefix)
+	})
+
+	t.Run("rejects unknown unit system", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		h := handler.NewPreferenceHandler(mocks.NewMockPreferenceService(ctrl))
+
+		router := setupRouter()
+		router.PUT("/me/preferences", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.Update(c)
+		})
+
+		req := httptest.NewRequest(http.MethodPut, "/me/preferences", bytes.NewBufferString(`{"unit_system":"nautical"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+type PrivacyHandler struct {
+	privacySvc PrivacyService
+}
+
+func NewPrivacyHandler(privacySvc PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{privacySvc: privacySvc}
+}
+
+// Lint godoc
+//
+//	@Summary		Lint note for personal data
+//	@Description	Report emails, phone numbers, typed coordinates and precise locations found in a note, to review before it leaves the account
+//	@Tags			notes
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			id	path		string	true	"Note ID"	format(uuid)
+//	@Success		200	{object}	response.LintResponse
+//	@Failure		400	{object}	httputil.ErrorResponse
+//	@Failure		401	{object}	httputil.ErrorResponse
+//	@Failure		403	{object}	httputil.ErrorResponse
+//	@Failure		404	{object}	httputil.ErrorResponse
+//	@Router			/notes/{id}/lint [get]
+func (h *PrivacyHandler) Lint(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidID, "invalid note id")
+		return
+	}
+
+	findings, err := h.privacySvc.Lint(c.Request.Context(), httputil.GetUserID(c), noteID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNoteNotFound):
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "note not found")
+		case errors.Is(err, domain.ErrForbidden):
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeForbidden, "access denied")
+		default:
+			httputil.InternalError(c)
+		}
+		return
+	}
+
+	httputil.OK(c, response.LintFromFindings(findings))
+}
@@ -0,0 +1,79 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+)
+
+func TestPrivacyHandler_Lint(t *testing.T) {
+	t.Run("returns findings", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		privacySvc := mocks.NewMockPrivacyService(ctrl)
+		h := handler.NewPrivacyHandler(privacySvc)
+
+		router := setupRouter()
+		userID := uuid.New()
+		noteID := uuid.New()
+		router.GET("/notes/:id/lint", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Lint(c)
+		})
+
+		privacySvc.EXPECT().Lint(gomock.Any(), userID, noteID).Return([]entity.PIIFinding{
+			{Kind: entity.PIIEmail, Field: "content", Match: "jane@example.com", Start: 4, End: 20},
+		}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/notes/"+noteID.String()+"/lint", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp response.LintResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.False(t, resp.Clean)
+		require.Len(t, resp.Findings, 1)
+		assert.Equal(t, "email", resp.Findings[0].Kind)
+		assert.Equal(t, 20, resp.Findings[0].End)
+	})
+
+	t.Run("returns forbidden for users who cannot share the note", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		privacySvc := mocks.NewMockPrivacyService(ctrl)
+		h := handler.NewPrivacyHandler(privacySvc)
+
+		router := setupRouter()
+		router.GET("/notes/:id/lint", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.Lint(c)
+		})
+
+		privacySvc.EXPECT().Lint(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, domain.ErrForbidden)
+
+		req := httptest.NewRequest(http.MethodGet, "/notes/"+uuid.NewString()+"/lint", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/request"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/quality"
+)
+
+type QualityHandler struct {
+	qualitySvc QualityService
+}
+
+func NewQualityHandler(qualitySvc QualityService) *QualityHandler {
+	return &QualityHandler{qualitySvc: qualitySvc}
+}
+
+// GetRules godoc
+//
+//	@Summary		Get quality rules
+//	@Description	Get the data quality rules applied to the current user's notes
+//	@Tags			quality
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{object}	response.QualityRulesResponse
+//	@Failure		401	{object}	httputil.ErrorResponse
+//	@Router			/quality/rules [get]
+func (h *QualityHandler) GetRules(c *gin.Context) {
+	rules, err := h.qualitySvc.GetRules(c.Request.Context(), httputil.GetUserID(c))
+	if err != nil {
+		httputil.InternalError(c)
+		return
+	}
+
+	httputil.OK(c, response.QualityRulesFromEntity(rules))
+}
+
+// UpdateRules godoc
+//
+//	@Summary		Set quality rules
+//	@Description	Replace the data quality rules and re-evaluate every note against them
+//	@Tags			quality
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		request.QualityRulesRequest	true	"Quality rules"
+//	@Success		200		{object}	response.QualityRulesResponse
+//	@Failure		400		{object}	httputil.ErrorResponse
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Router			/quality/rules [put]
+func (h *QualityHandler) UpdateRules(c *gin.Context) {
+	var req request.QualityRulesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	input := quality.RulesInput{
+		UserID:           httputil.GetUserID(c),
+		RequirePhoto:     req.RequirePhoto,
+		MinContentLength: req.MinContentLength,
+		MaxAccuracy:      req.MaxAccuracy,
+	}
+	if req.Fence != nil {
+		input.Fence = valueobject.NewBoundingBox(req.Fence.MinLat, req.Fence.MaxLat, req.Fence.MinLng, req.Fence.MaxLng)
+	}
+
+	rules, err := h.qualitySvc.UpdateRules(c.Request.Context(), input)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidBoundingBox) {
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidBBox, "invalid fence")
+			return
+		}
+		httputil.InternalError(c)
+		return
+	}
+
+	httputil.OK(c, response.QualityRulesFromEntity(rules))
+}
+
+// Report godoc
+//
+//	@Summary		Get quality report
+//	@Description	Count notes by quality status and failed rule
+//	@Tags			quality
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{object}	response.QualityReportResponse
+//	@Failure		401	{object}	httputil.ErrorResponse
+//	@Router			/quality/report [get]
+func (h *QualityHandler) Report(c *gin.Context) {
+	report, err := h.qualitySvc.Report(c.Request.Context(), httputil.GetUserID(c))
+	if err != nil {
+		httputil.InternalError(c)
+		return
+	}
+
+	httputil.OK(c, response.QualityReportFromEntity(report))
+}
@@ -0,0 +1,128 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/quality"
+)
+
+func TestQualityHandler_UpdateRules(t *testing.T) {
+	t.Run("updates rules", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		qualitySvc := mocks.NewMockQualityService(ctrl)
+		h := handler.NewQualityHandler(qualitySvc)
+
+		router := setupRouter()
+		userID := uuid.New()
+		router.PUT("/quality/rules", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.UpdateRules(c)
+		})
+
+		qualitySvc.EXPECT().UpdateRules(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ any, input quality.RulesInput) (*entity.QualityRules, error) {
+				assert.Equal(t, userID, input.UserID)
+				require.NotNil(t, input.Fence)
+				return &entity.QualityRules{
+					UserID:           userID,
+					RequirePhoto:     input.RequirePhoto,
+					MinContentLength: input.MinContentLength,
+					Fence:            input.Fence,
+				}, nil
+			})
+
+		body := `{"require_photo":true,"min_content_length":20,"fence":{"min_lat":-24,"max_lat":-23,"min_lng":-47,"max_lng":-46}}`
+		req := httptest.NewRequest(http.MethodPut, "/quality/rules", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp response.QualityRulesResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.True(t, resp.RequirePhoto)
+		assert.Equal(t, 20, resp.MinContentLength)
+		require.NotNil(t, resp.Fence)
+		assert.Equal(t, -24.0, resp.Fence.MinLat)
+	})
+
+	t.Run("returns bad request for invalid fence", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		qualitySvc := mocks.NewMockQualityService(ctrl)
+		h := handler.NewQualityHandler(qualitySvc)
+
+		router := setupRouter()
+		router.PUT("/quality/rules", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.UpdateRules(c)
+		})
+
+		qualitySvc.EXPECT().UpdateRules(gomock.Any(), gomock.Any()).Return(nil, domain.ErrInvalidBoundingBox)
+
+		body := `{"fence":{"min_lat":10,"max_lat":-10,"min_lng":0,"max_lng":1}}`
+		req := httptest.NewRequest(http.MethodPut, "/quality/rules", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestQualityHandler_Report(t *testing.T) {
+	t.Run("returns report", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		qualitySvc := mocks.NewMockQualityService(ctrl)
+		h := handler.NewQualityHandler(qualitySvc)
+
+		router := setupRouter()
+		userID := uuid.New()
+		router.GET("/quality/report", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Report(c)
+		})
+
+		qualitySvc.EXPECT().Report(gomock.Any(), userID).Return(&entity.QualityReport{
+			Total:        5,
+			Passed:       3,
+			Failed:       2,
+			RuleFailures: map[string]int{entity.QualityRuleRequiredPhoto: 2},
+		}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/quality/report", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp response.QualityReportResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 5, resp.Total)
+		assert.Equal(t, 2, resp.RuleFailures[entity.QualityRuleRequiredPhoto])
+	})
+}
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/request"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/apperror"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/realtime"
+)
+
+// documentConn is the collaborative editing side of a /ws connection: the
+// collaborative notes the app has open, whose updates it is sent, and the
+// updates it sends.
+type documentConn struct {
+	documents DocumentService
+	userID    uuid.UUID
+	// deviceID is the device of the token, uuid.Nil when it has none; its
+	// own updates are not sent back to it.
+	deviceID uuid.UUID
+	send     func(response.RealtimeMessage) error
+	updates  chan entity.DocumentUpdate
+
+	mu      sync.Mutex
+	watched map[uuid.UUID]func()
+}
+
+func newDocumentConn(documents DocumentService, userID, deviceID uuid.UUID, send func(response.RealtimeMessage) error) *documentConn {
+	return &documentConn{
+		documents: documents,
+		userID:    userID,
+		deviceID:  deviceID,
+		send:      send,
+		updates:   make(chan entity.DocumentUpdate, maxOpenNotes),
+		watched:   make(map[uuid.UUID]func()),
+	}
+}
+
+// handle acts on a message from the app. Unknown messages are ignored.
+func (d *documentConn) handle(msg request.RealtimeMessage) error {
+	switch msg.Type {
+	case request.RealtimeOpen, request.RealtimeClose, request.RealtimeUpdate:
+	default:
+		return nil
+	}
+
+	noteID, err := uuid.Parse(msg.NoteID)
+	if err != nil {
+		// Open and close are answered by the presence side, when it is on.
+		if msg.Type == request.RealtimeUpdate {
+			return d.fail(nil, apperror.New(http.StatusBadRequest, httputil.CodeInvalidID, "invalid note id"))
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), presenceTimeout)
+	defer cancel()
+
+	switch msg.Type {
+	case request.RealtimeOpen:
+		return d.open(ctx, noteID)
+	case request.RealtimeUpdate:
+		return d.update(ctx, noteID, msg)
+	default:
+		d.close(noteID)
+		return nil
+	}
+}
+
+// open follows the note's updates when it is collaborative. Notes that are
+// not are left alone; the app opens them again once collaboration is on.
+func (d *documentConn) open(ctx context.Context, noteID uuid.UUID) error {
+	d.mu.Lock()
+	_, watching := d.watched[noteID]
+	full := !watching && len(d.watched) >= maxOpenNotes
+	d.mu.Unlock()
+	if full {
+		return nil
+	}
+
+	// Watching before reading the seq, so no update after it is missed.
+	var stop func()
+	if !watching {
+		stop = d.watch(noteID)
+	}
+	doc, err := d.documents.Get(ctx, d.userID, noteID)
+	if err != nil {
+		if stop != nil {
+			stop()
+		}
+		// Notes the user cannot open are answered by the presence side.
+		if errors.Is(err, domain.ErrDocumentNotFound) || errors.Is(err, domain.ErrNoteNotFound) || errors.Is(err, domain.ErrForbidden) {
+			return nil
+		}
+		return d.fail(&noteID, documentError(err))
+	}
+
+	if stop != nil {
+		d.mu.Lock()
+		d.watched[noteID] = stop
+		d.mu.Unlock()
+	}
+	seq := doc.Seq()
+	return d.send(response.RealtimeMessage{Type: response.RealtimeDocument, NoteID: &noteID, Seq: &seq})
+}
+
+func (d *documentConn) update(ctx context.Context, noteID uuid.UUID, msg request.RealtimeMessage) error {
+	seq, err := d.documents.Apply(ctx, realtime.ApplyInput{
+		UserID:   d.userID,
+		DeviceID: d.deviceID,
+		NoteID:   noteID,
+		Ops:      msg.Ops,
+	})
+	if err != nil {
+		return d.fail(&noteID, documentError(err))
+	}
+	return d.send(response.RealtimeMessage{Type: response.RealtimeUpdateAck, NoteID: &noteID, Seq: &seq})
+}
+
+func (d *documentConn) close(noteID uuid.UUID) {
+	d.mu.Lock()
+	stop, ok := d.watched[noteID]
+	delete(d.watched, noteID)
+	d.mu.Unlock()
+	if ok {
+		stop()
+	}
+}
+
+// watch forwards the note's updates to the connection until stopped.
+func (d *documentConn) watch(noteID uuid.UUID) func() {
+	updates, unwatch := d.documents.Watch(noteID)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case update := <-updates:
+				select {
+				case d.updates <- update:
+				case <-done:
+					return
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			unwatch()
+			close(done)
+		})
+	}
+}
+
+// forward sends an update made by another device to a note that is open.
+func (d *documentConn) forward(update entity.DocumentUpdate) error {
+	if d.deviceID != uuid.Nil && update.DeviceID != nil && *update.DeviceID == d.deviceID {
+		return nil
+	}
+	d.mu.Lock()
+	_, ok := d.watched[update.NoteID]
+	d.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	resp := response.DocumentUpdateFromEntity(update)
+	return d.send(response.RealtimeMessage{Type: response.RealtimeDocumentUpdate, NoteID: &update.NoteID, Update: &resp})
+}
+
+// closeAll stops following every note when the connection ends.
+func (d *documentConn) closeAll() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for noteID, stop := range d.watched {
+		stop()
+		delete(d.watched, noteID)
+	}
+}
+
+func (d *documentConn) fail(noteID *uuid.UUID, err *apperror.Error) error {
+	return d.send(response.RealtimeMessage{
+		Type:   response.RealtimeError,
+		NoteID: noteID,
+		Error:  &response.RealtimeErrorMsg{Code: err.Code, Message: err.Message},
+	})
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/net/websocket"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/request"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/apperror"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+const realtimeWriteTimeout = 10 * time.Second
+
+type RealtimeHandler struct {
+	changes   ChangeSubscriber
+	presence  PresenceService
+	documents DocumentService
+	heartbeat time.Duration
+}
+
+// NewRealtimeHandler returns a handler that pings idle connections every
+// heartbeat, so proxies don't close them and dead ones are noticed. With a
+// nil presence the open, move and close messages are ignored, and with nil
+// documents the update messages.
+func NewRealtimeHandler(changes ChangeSubscriber, presence PresenceService, documents DocumentService, heartbeat time.Duration) *RealtimeHandler {
+	return &RealtimeHandler{changes: changes, presence: presence, documents: documents, heartbeat: heartbeat}
+}
+
+// Connect godoc
+//
+//	@Summary		Real-time change notifications
+//	@Description	Open a WebSocket on which the server sends {"type":"changes","since":...} when the user's notes change through another device, by the API or a sync, so the app syncs then instead of polling. The device whose token opened the socket is not told about its own changes. Events that arrive while the app is still reading are merged into one. A {"type":"ping"} keeps the connection open. Changes made while disconnected are not replayed, so sync on reconnect. The app can send {"type":"open","note_id":...} for a note the user can read, {"type":"move","note_id":...,"selection":{"start":0,"end":0}} as its cursor moves, and {"type":"close","note_id":...}; it then gets {"type":"presence"} with the devices that have the note open, again with every ping, and {"type":"presence_changed"} as they come, move or leave. Presence is not stored beyond the connection. Opening a collaborative note also gets {"type":"document","note_id":...,"seq":...} and then {"type":"document_update","update":{...}} for every update other devices make to its document; the app sends its own as {"type":"update","note_id":...,"ops":[...]} and gets {"type":"update_ack","seq":...}. A gap in seq means updates were missed; fetch them from /notes/{id}/collaboration. Other messages are ignored.
+//	@Tags			sync
+//	@Security		BearerAuth
+//	@Success		101	{object}	response.RealtimeMessage
+//	@Failure		401	{object}	httputil.ErrorResponse
+//	@Failure		426	{object}	httputil.ErrorResponse
+//	@Router			/ws [get]
+func (h *RealtimeHandler) Connect(c *gin.Context) {
+	if !strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+		httputil.Fail(c, apperror.New(http.StatusUpgradeRequired, httputil.CodeUpgradeRequired, "websocket upgrade required"))
+		return
+	}
+
+	userID, deviceID := httputil.GetUserID(c), httputil.GetTokenDeviceID(c)
+	events, unsubscribe := h.changes.Subscribe(userID, deviceID)
+	defer unsubscribe()
+
+	// Tokens not bound to a device get an ID per connection, so two
+	// browser tabs show as two viewers.
+	presenceDeviceID := deviceID
+	if presenceDeviceID == uuid.Nil {
+		presenceDeviceID = uuid.New()
+	}
+
+	// Native apps send no Origin and the token already authenticates the
+	// caller, so there is no origin check.
+	server := websocket.Server{Handler: func(ws *websocket.Conn) {
+		h.serve(ws, events, userID, deviceID, presenceDeviceID)
+	}}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *RealtimeHandler) serve(ws *websocket.Conn, events <-chan entity.ChangeEvent, userID, deviceID, presenceDeviceID uuid.UUID) {
+	defer ws.Close()
+
+	// The server's read and write timeouts still apply to the hijacked
+	// connection.
+	_ = ws.SetDeadline(time.Time{})
+
+	// Replies to the app's messages are written from the reading goroutine.
+	var writeMu sync.Mutex
+	send := func(msg response.RealtimeMessage) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		_ = ws.SetWriteDeadline(time.Now().Add(realtimeWriteTimeout))
+		return websocket.JSON.Send(ws, msg)
+	}
+
+	var presence *presenceConn
+	var presenceEvents <-chan entity.PresenceEvent
+	if h.presence != nil {
+		presence = newPresenceConn(h.presence, userID, presenceDeviceID, send)
+		presenceEvents = presence.events
+		defer presence.closeAll()
+	}
+	var documents *documentConn
+	var documentUpdates <-chan entity.DocumentUpdate
+	if h.documents != nil {
+		documents = newDocumentConn(h.documents, userID, deviceID, send)
+		documentUpdates = documents.updates
+		defer documents.closeAll()
+	}
+
+	// Reading notices the client closing the socket.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			var msg request.RealtimeMessage
+			if err := websocket.JSON.Receive(ws, &msg); err != nil {
+				var syntaxErr *json.SyntaxError
+				var typeErr *json.UnmarshalTypeError
+				if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+					continue
+				}
+				return
+			}
+			if presence != nil && presence.handle(msg) != nil {
+				return
+			}
+			if documents != nil && documents.handle(msg) != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		var err error
+		select {
+		case <-closed:
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			err = send(response.RealtimeMessage{Type: response.RealtimeChanges, Since: &event.Since})
+		case event := <-presenceEvents:
+			err = presence.forward(event)
+		case update := <-documentUpdates:
+			err = documents.forward(update)
+		case <-ticker.C:
+			err = send(response.RealtimeMessage{Type: response.RealtimePing})
+			if err == nil && presence != nil {
+				err = presence.keep()
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
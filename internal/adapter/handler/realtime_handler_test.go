@@ -0,0 +1,238 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/net/websocket"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/request"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/crdt"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/realtime"
+)
+
+func setupRealtimeServer(t *testing.T, heartbeat time.Duration) (*mocks.MockChangeSubscriber, *httptest.Server, uuid.UUID, uuid.UUID) {
+	ctrl := gomock.NewController(t)
+	changes := mocks.NewMockChangeSubscriber(ctrl)
+	server, userID, deviceID := serveRealtime(t, handler.NewRealtimeHandler(changes, nil, nil, heartbeat))
+	return changes, server, userID, deviceID
+}
+
+func setupPresenceServer(t *testing.T) (*mocks.MockPresenceService, *httptest.Server, uuid.UUID, uuid.UUID) {
+	ctrl := gomock.NewController(t)
+	changes := mocks.NewMockChangeSubscriber(ctrl)
+	changes.EXPECT().Subscribe(gomock.Any(), gomock.Any()).Return(make(chan entity.ChangeEvent), func() {})
+	presence := mocks.NewMockPresenceService(ctrl)
+	server, userID, deviceID := serveRealtime(t, handler.NewRealtimeHandler(changes, presence, nil, time.Hour))
+	return presence, server, userID, deviceID
+}
+
+func serveRealtime(t *testing.T, h *handler.RealtimeHandler) (*httptest.Server, uuid.UUID, uuid.UUID) {
+
+	router := setupRouter()
+	userID, deviceID := uuid.New(), uuid.New()
+	router.GET("/ws", func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Set("token_device_id", deviceID)
+	}, h.Connect)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server, userID, deviceID
+}
+
+func dialRealtime(t *testing.T, server *httptest.Server) *websocket.Conn {
+	t.Helper()
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", "", server.URL)
+	require.NoError(t, err)
+	t.Cleanup(func() { ws.Close() })
+	require.NoError(t, ws.SetReadDeadline(time.Now().Add(5*time.Second)))
+	return ws
+}
+
+func TestRealtimeHandler_Connect(t *testing.T) {
+	t.Run("sends change events to the device", func(t *testing.T) {
+		changes, server, userID, deviceID := setupRealtimeServer(t, time.Hour)
+		events := make(chan entity.ChangeEvent, 1)
+		changes.EXPECT().Subscribe(userID, deviceID).Return(events, func() {})
+
+		ws := dialRealtime(t, server)
+		since := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+		events <- entity.ChangeEvent{UserID: userID, Since: since}
+
+		var msg response.RealtimeMessage
+		require.NoError(t, websocket.JSON.Receive(ws, &msg))
+		assert.Equal(t, response.RealtimeChanges, msg.Type)
+		require.NotNil(t, msg.Since)
+		assert.True(t, since.Equal(*msg.Since))
+	})
+
+	t.Run("pings idle connections", func(t *testing.T) {
+		changes, server, _, _ := setupRealtimeServer(t, 10*time.Millisecond)
+		changes.EXPECT().Subscribe(gomock.Any(), gomock.Any()).Return(make(chan entity.ChangeEvent), func() {})
+
+		ws := dialRealtime(t, server)
+
+		var msg response.RealtimeMessage
+		require.NoError(t, websocket.JSON.Receive(ws, &msg))
+		assert.Equal(t, response.RealtimePing, msg.Type)
+	})
+
+	t.Run("closes when the subscription ends", func(t *testing.T) {
+		changes, server, _, _ := setupRealtimeServer(t, time.Hour)
+		events := make(chan entity.ChangeEvent)
+		changes.EXPECT().Subscribe(gomock.Any(), gomock.Any()).Return(events, func() {})
+
+		ws := dialRealtime(t, server)
+		close(events)
+
+		var msg response.RealtimeMessage
+		assert.Error(t, websocket.JSON.Receive(ws, &msg))
+	})
+
+	t.Run("rejects plain HTTP requests", func(t *testing.T) {
+		_, server, _, _ := setupRealtimeServer(t, time.Hour)
+
+		resp, err := http.Get(server.URL + "/ws")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusUpgradeRequired, resp.StatusCode)
+	})
+}
+
+func TestRealtimeHandler_Presence(t *testing.T) {
+	noteID := uuid.New()
+	other := entity.Presence{NoteID: noteID, UserID: uuid.New(), DeviceID: uuid.New(), SeenAt: time.Now().UTC()}
+
+	t.Run("shares who has an opened note open", func(t *testing.T) {
+		presence, server, userID, deviceID := setupPresenceServer(t)
+		events := make(chan entity.PresenceEvent, 2)
+		self := entity.Presence{NoteID: noteID, UserID: userID, DeviceID: deviceID, Selection: &entity.Selection{Start: 3, End: 3}}
+
+		presence.EXPECT().Watch(noteID).Return(events, func() {})
+		presence.EXPECT().Open(gomock.Any(), self).Return([]entity.Presence{other, self}, nil)
+		closed := make(chan struct{})
+		presence.EXPECT().Close(gomock.Any(), self).DoAndReturn(func(context.Context, entity.Presence) error {
+			close(closed)
+			return nil
+		})
+
+		ws := dialRealtime(t, server)
+		require.NoError(t, websocket.JSON.Send(ws, request.RealtimeMessage{
+			Type: request.RealtimeOpen, NoteID: noteID.String(), Selection: &request.SelectionRequest{Start: 3, End: 3},
+		}))
+
+		var msg response.RealtimeMessage
+		require.NoError(t, websocket.JSON.Receive(ws, &msg))
+		assert.Equal(t, response.RealtimePresence, msg.Type)
+		assert.Equal(t, noteID, *msg.NoteID)
+		require.Len(t, msg.Viewers, 2)
+		assert.Equal(t, other.UserID, msg.Viewers[0].UserID)
+		assert.Equal(t, &response.SelectionResponse{Start: 3, End: 3}, msg.Viewers[1].Selection)
+
+		// The device's own events are not sent back to it.
+		events <- entity.PresenceEvent{Presence: self}
+		events <- entity.PresenceEvent{Presence: other, Left: true}
+
+		require.NoError(t, websocket.JSON.Receive(ws, &msg))
+		assert.Equal(t, response.RealtimePresenceChanged, msg.Type)
+		assert.Equal(t, other.DeviceID, msg.Viewer.DeviceID)
+		assert.True(t, msg.Left)
+
+		require.NoError(t, websocket.JSON.Send(ws, request.RealtimeMessage{Type: request.RealtimeClose, NoteID: noteID.String()}))
+		select {
+		case <-closed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("note was not closed")
+		}
+	})
+
+	t.Run("reports notes that cannot be opened", func(t *testing.T) {
+		presence, server, _, _ := setupPresenceServer(t)
+		unwatched := false
+		presence.EXPECT().Watch(noteID).Return(make(chan entity.PresenceEvent), func() { unwatched = true })
+		presence.EXPECT().Open(gomock.Any(), gomock.Any()).Return(nil, domain.ErrForbidden)
+
+		ws := dialRealtime(t, server)
+		require.NoError(t, websocket.JSON.Send(ws, request.RealtimeMessage{Type: request.RealtimeOpen, NoteID: noteID.String()}))
+
+		var msg response.RealtimeMessage
+		require.NoError(t, websocket.JSON.Receive(ws, &msg))
+		assert.Equal(t, response.RealtimeError, msg.Type)
+		assert.Equal(t, noteID, *msg.NoteID)
+		assert.Equal(t, httputil.CodeForbidden, msg.Error.Code)
+		assert.True(t, unwatched)
+	})
+
+	t.Run("rejects invalid note ids and selections", func(t *testing.T) {
+		_, server, _, _ := setupPresenceServer(t)
+
+		ws := dialRealtime(t, server)
+		require.NoError(t, websocket.JSON.Send(ws, request.RealtimeMessage{Type: request.RealtimeOpen, NoteID: "not-a-uuid"}))
+		require.NoError(t, websocket.JSON.Send(ws, request.RealtimeMessage{
+			Type: request.RealtimeMove, NoteID: noteID.String(), Selection: &request.SelectionRequest{Start: 5, End: 2},
+		}))
+
+		var msg response.RealtimeMessage
+		require.NoError(t, websocket.JSON.Receive(ws, &msg))
+		assert.Equal(t, httputil.CodeInvalidID, msg.Error.Code)
+		require.NoError(t, websocket.JSON.Receive(ws, &msg))
+		assert.Equal(t, httputil.CodeValidationError, msg.Error.Code)
+	})
+}
+
+func TestRealtimeHandler_Documents(t *testing.T) {
+	noteID := uuid.New()
+	ctrl := gomock.NewController(t)
+	changes := mocks.NewMockChangeSubscriber(ctrl)
+	changes.EXPECT().Subscribe(gomock.Any(), gomock.Any()).Return(make(chan entity.ChangeEvent), func() {})
+	documents := mocks.NewMockDocumentService(ctrl)
+	server, userID, deviceID := serveRealtime(t, handler.NewRealtimeHandler(changes, nil, documents, time.Hour))
+
+	updates := make(chan entity.DocumentUpdate, 2)
+	doc := entity.NewNoteDocument(noteID, crdt.FromText(realtime.ServerReplica, "abc").Snapshot())
+	doc.SnapshotSeq = 4
+	ops := []crdt.Op{{Op: crdt.OpInsert, ID: crdt.ID{Replica: "phone", Seq: 4}, Text: "!"}}
+	documents.EXPECT().Watch(noteID).Return(updates, func() {})
+	documents.EXPECT().Get(gomock.Any(), userID, noteID).Return(doc, nil)
+	documents.EXPECT().Apply(gomock.Any(), realtime.ApplyInput{UserID: userID, DeviceID: deviceID, NoteID: noteID, Ops: ops}).Return(int64(5), nil)
+
+	ws := dialRealtime(t, server)
+	require.NoError(t, websocket.JSON.Send(ws, request.RealtimeMessage{Type: request.RealtimeOpen, NoteID: noteID.String()}))
+
+	var msg response.RealtimeMessage
+	require.NoError(t, websocket.JSON.Receive(ws, &msg))
+	assert.Equal(t, response.RealtimeDocument, msg.Type)
+	assert.Equal(t, int64(4), *msg.Seq)
+
+	require.NoError(t, websocket.JSON.Send(ws, request.RealtimeMessage{Type: request.RealtimeUpdate, NoteID: noteID.String(), Ops: ops}))
+	require.NoError(t, websocket.JSON.Receive(ws, &msg))
+	assert.Equal(t, response.RealtimeUpdateAck, msg.Type)
+	assert.Equal(t, int64(5), *msg.Seq)
+
+	// The device's own update is not sent back to it.
+	otherDevice := uuid.New()
+	updates <- entity.DocumentUpdate{NoteID: noteID, Seq: 5, DeviceID: &deviceID, Ops: ops}
+	updates <- entity.DocumentUpdate{NoteID: noteID, Seq: 6, DeviceID: &otherDevice, Ops: ops}
+
+	require.NoError(t, websocket.JSON.Receive(ws, &msg))
+	assert.Equal(t, response.RealtimeDocumentUpdate, msg.Type)
+	assert.Equal(t, int64(6), msg.Update.Seq)
+	assert.Equal(t, ops, msg.Update.Ops)
+}
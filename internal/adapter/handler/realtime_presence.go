@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/request"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/apperror"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+const (
+	// maxOpenNotes bounds the notes one connection follows.
+	maxOpenNotes = 20
+	// minMoveInterval drops selection moves sent faster than this, which
+	// would otherwise all be relayed to every instance.
+	minMoveInterval = 100 * time.Millisecond
+	presenceTimeout = 5 * time.Second
+)
+
+type openNote struct {
+	presence entity.Presence
+	movedAt  time.Time
+	stop     func()
+}
+
+// presenceConn is the presence side of a /ws connection: the notes the app
+// has open, and the events about them waiting to be sent.
+type presenceConn struct {
+	presence PresenceService
+	userID   uuid.UUID
+	deviceID uuid.UUID
+	send     func(response.RealtimeMessage) error
+	events   chan entity.PresenceEvent
+
+	mu   sync.Mutex
+	open map[uuid.UUID]*openNote
+}
+
+func newPresenceConn(presence PresenceService, userID, deviceID uuid.UUID, send func(response.RealtimeMessage) error) *presenceConn {
+	return &presenceConn{
+		presence: presence,
+		userID:   userID,
+		deviceID: deviceID,
+		send:     send,
+		events:   make(chan entity.PresenceEvent, maxOpenNotes),
+		open:     make(map[uuid.UUID]*openNote),
+	}
+}
+
+// handle acts on a message from the app. Unknown messages are ignored.
+func (p *presenceConn) handle(msg request.RealtimeMessage) error {
+	switch msg.Type {
+	case request.RealtimeOpen, request.RealtimeMove, request.RealtimeClose:
+	default:
+		return nil
+	}
+
+	noteID, err := uuid.Parse(msg.NoteID)
+	if err != nil {
+		return p.fail(nil, apperror.New(http.StatusBadRequest, httputil.CodeInvalidID, "invalid note id"))
+	}
+	presence := entity.Presence{NoteID: noteID, UserID: p.userID, DeviceID: p.deviceID}
+	if s := msg.Selection; s != nil {
+		if s.Start < 0 || s.End < s.Start {
+			return p.fail(&noteID, apperror.New(http.StatusBadRequest, httputil.CodeValidationError, "selection must have 0 <= start <= end"))
+		}
+		presence.Selection = &entity.Selection{Start: s.Start, End: s.End}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), presenceTimeout)
+	defer cancel()
+
+	switch msg.Type {
+	case request.RealtimeOpen:
+		return p.openNote(ctx, presence)
+	case request.RealtimeMove:
+		return p.move(ctx, presence)
+	default:
+		p.closeNote(ctx, noteID)
+		return nil
+	}
+}
+
+func (p *presenceConn) openNote(ctx context.Context, presence entity.Presence) error {
+	p.mu.Lock()
+	_, reopened := p.open[presence.NoteID]
+	full := !reopened && len(p.open) >= maxOpenNotes
+	p.mu.Unlock()
+	if full {
+		return p.fail(&presence.NoteID, apperror.New(http.StatusBadRequest, httputil.CodeValidationError, "too many notes open on this connection"))
+	}
+
+	// Watching before opening, so no event after the snapshot is missed.
+	var stop func()
+	if !reopened {
+		stop = p.watch(presence.NoteID)
+	}
+	viewers, err := p.presence.Open(ctx, presence)
+	if err != nil {
+		if stop != nil {
+			stop()
+		}
+		return p.fail(&presence.NoteID, presenceError(err))
+	}
+
+	p.mu.Lock()
+	if note, ok := p.open[presence.NoteID]; ok {
+		note.presence = presence
+	} else {
+		p.open[presence.NoteID] = &openNote{presence: presence, movedAt: time.Now(), stop: stop}
+	}
+	p.mu.Unlock()
+
+	return p.send(response.RealtimePresenceFromEntities(presence.NoteID, viewers))
+}
+
+func (p *presenceConn) move(ctx context.Context, presence entity.Presence) error {
+	p.mu.Lock()
+	note, ok := p.open[presence.NoteID]
+	if ok && time.Since(note.movedAt) < minMoveInterval {
+		ok = false
+	} else if ok {
+		note.presence = presence
+		note.movedAt = time.Now()
+	}
+	p.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	if err := p.presence.Move(ctx, presence); err != nil {
+		return p.fail(&presence.NoteID, presenceError(err))
+	}
+	return nil
+}
+
+func (p *presenceConn) closeNote(ctx context.Context, noteID uuid.UUID) {
+	p.mu.Lock()
+	note, ok := p.open[noteID]
+	delete(p.open, noteID)
+	p.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	note.stop()
+	_ = p.presence.Close(ctx, note.presence)
+}
+
+// watch forwards the note's events to the connection until stopped.
+func (p *presenceConn) watch(noteID uuid.UUID) func() {
+	events, unwatch := p.presence.Watch(noteID)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case event := <-events:
+				select {
+				case p.events <- event:
+				case <-done:
+					return
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			unwatch()
+			close(done)
+		})
+	}
+}
+
+// forward sends an event from another device about a note that is open.
+func (p *presenceConn) forward(event entity.PresenceEvent) error {
+	if event.UserID == p.userID && event.DeviceID == p.deviceID {
+		return nil
+	}
+	p.mu.Lock()
+	_, ok := p.open[event.NoteID]
+	p.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return p.send(response.RealtimePresenceChangedFromEntity(event))
+}
+
+// keep refreshes the presence on every open note, so it does not expire
+// while the connection lives, and sends who has each open.
+func (p *presenceConn) keep() error {
+	p.mu.Lock()
+	open := make([]entity.Presence, 0, len(p.open))
+	for _, note := range p.open {
+		open = append(open, note.presence)
+	}
+	p.mu.Unlock()
+
+	for _, presence := range open {
+		ctx, cancel := context.WithTimeout(context.Background(), presenceTimeout)
+		viewers, err := p.presence.Keep(ctx, presence)
+		cancel()
+		if err != nil {
+			continue
+		}
+		if err := p.send(response.RealtimePresenceFromEntities(presence.NoteID, viewers)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// closeAll leaves every open note when the connection ends.
+func (p *presenceConn) closeAll() {
+	p.mu.Lock()
+	noteIDs := make([]uuid.UUID, 0, len(p.open))
+	for noteID := range p.open {
+		noteIDs = append(noteIDs, noteID)
+	}
+	p.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), presenceTimeout)
+	defer cancel()
+	for _, noteID := range noteIDs {
+		p.closeNote(ctx, noteID)
+	}
+}
+
+func (p *presenceConn) fail(noteID *uuid.UUID, err *apperror.Error) error {
+	return p.send(response.RealtimeMessage{
+		Type:   response.RealtimeError,
+		NoteID: noteID,
+		Error:  &response.RealtimeErrorMsg{Code: err.Code, Message: err.Message},
+	})
+}
+
+func presenceError(err error) *apperror.Error {
+	switch {
+	case errors.Is(err, domain.ErrNoteNotFound):
+		return apperror.New(http.StatusNotFound, httputil.CodeNotFound, "note not found")
+	case errors.Is(err, domain.ErrForbidden):
+		return apperror.New(http.StatusForbidden, httputil.CodeForbidden, "access denied")
+	default:
+		return apperror.New(http.StatusInternalServerError, httputil.CodeInternalError, "internal server error")
+	}
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/request"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/semantic"
+)
+
+// SemanticHandler serves search by meaning over note embeddings. It is only
+// set up when an embeddings provider is configured.
+type SemanticHandler struct {
+	semantic SemanticService
+	prefSvc  PreferenceService
+	mask     entity.LocationMask
+}
+
+func NewSemanticHandler(semantic SemanticService, prefSvc PreferenceService, mask entity.LocationMask) *SemanticHandler {
+	return &SemanticHandler{semantic: semantic, prefSvc: prefSvc, mask: mask}
+}
+
+// Search godoc
+//
+//	@Summary		Search notes by meaning
+//	@Description	Find the caller's notes whose title and content are closest in meaning to the query, best first, with the cosine similarity as score. The query is sent to the server's embeddings provider. Only for users who turned on semantic_search in their preferences; notes are indexed in the background, so new and edited notes show up after a minute or so
+//	@Tags			notes
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			q		query		string	true	"Search text"
+//	@Param			limit	query		int		false	"Maximum number of notes"	default(20)
+//	@Param			units	query		string	false	"Unit system for measurements, defaults to the user's preference"	Enums(metric, imperial)
+//	@Success		200		{object}	response.SearchNotesResponse
+//	@Failure		400		{object}	httputil.ErrorResponse
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Failure		403		{object}	httputil.ErrorResponse
+//	@Router			/notes/semantic-search [get]
+func (h *SemanticHandler) Search(c *gin.Context) {
+	var req request.SemanticSearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	hits, err := h.semantic.Search(c.Request.Context(), semantic.SearchInput{
+		UserID: httputil.GetUserID(c),
+		Query:  req.Query,
+		Limit:  req.Limit,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidSearchQuery):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "q must not be blank")
+		case errors.Is(err, domain.ErrSemanticSearchOff):
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeSemanticSearchOff, "semantic search is turned off in your preferences")
+		default:
+			httputil.InternalError(c)
+		}
+		return
+	}
+
+	h.respond(c, hits)
+}
+
+// Related godoc
+//
+//	@Summary		List related notes
+//	@Description	List the caller's notes closest in meaning to a note they can read, best first, with the cosine similarity as score. Only for users who turned on semantic_search in their preferences; the list is empty until the note is indexed, and for notes shared by users who have it off
+//	@Tags			notes
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			id		path		string	true	"Note ID"	format(uuid)
+//	@Param			limit	query		int		false	"Maximum number of notes"	default(20)
+//	@Param			units	query		string	false	"Unit system for measurements, defaults to the user's preference"	Enums(metric, imperial)
+//	@Success		200		{object}	response.SearchNotesResponse
+//	@Failure		400		{object}	httputil.ErrorResponse
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Failure		403		{object}	httputil.ErrorResponse
+//	@Failure		404		{object}	httputil.ErrorResponse
+//	@Router			/notes/{id}/related [get]
+func (h *SemanticHandler) Related(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidID, "invalid note id")
+		return
+	}
+
+	var req request.RelatedNotesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	hits, err := h.semantic.Related(c.Request.Context(), semantic.RelatedInput{
+		UserID: httputil.GetUserID(c),
+		NoteID: noteID,
+		Limit:  req.Limit,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrSemanticSearchOff):
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeSemanticSearchOff, "semantic search is turned off in your preferences")
+		case errors.Is(err, domain.ErrNoteNotFound):
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "note not found")
+		case errors.Is(err, domain.ErrForbidden):
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeForbidden, "access denied")
+		default:
+			httputil.InternalError(c)
+		}
+		return
+	}
+
+	h.respond(c, hits)
+}
+
+func (h *SemanticHandler) respond(c *gin.Context, hits []entity.SearchHit) {
+	withMeasurements := false
+	for _, hit := range hits {
+		withMeasurements = withMeasurements || hasMeasurements(hit.Note)
+	}
+
+	httputil.OK(c, response.SearchNotesResponse{
+		Notes: response.SearchHitsFromEntities(hits, noteView(c, h.prefSvc, h.mask, withMeasurements)),
+	})
+}
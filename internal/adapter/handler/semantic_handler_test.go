@@ -0,0 +1,117 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/semantic"
+)
+
+func setupSemanticRouter(t *testing.T) (*mocks.MockSemanticService, *gin.Engine, uuid.UUID) {
+	ctrl := gomock.NewController(t)
+	semanticSvc := mocks.NewMockSemanticService(ctrl)
+	h := handler.NewSemanticHandler(semanticSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+	router := setupRouter()
+	userID := uuid.New()
+	router.GET("/notes/semantic-search", func(c *gin.Context) {
+		c.Set("user_id", userID)
+		h.Search(c)
+	})
+	router.GET("/notes/:id/related", func(c *gin.Context) {
+		c.Set("user_id", userID)
+		h.Related(c)
+	})
+	return semanticSvc, router, userID
+}
+
+func TestSemanticHandler_Search(t *testing.T) {
+	t.Run("returns the closest notes with their score", func(t *testing.T) {
+		semanticSvc, router, userID := setupSemanticRouter(t)
+		noteID := uuid.New()
+
+		semanticSvc.EXPECT().Search(gomock.Any(), semantic.SearchInput{UserID: userID, Query: "big trees", Limit: 5}).
+			Return([]entity.SearchHit{{Note: entity.Note{ID: noteID, UserID: userID, Title: "Oak survey"}, Score: 0.83}}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/notes/semantic-search?q=big+trees&limit=5", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp response.SearchNotesResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Notes, 1)
+		assert.Equal(t, "Oak survey", resp.Notes[0].Title)
+		require.NotNil(t, resp.Notes[0].Score)
+		assert.InDelta(t, 0.83, *resp.Notes[0].Score, 1e-9)
+	})
+
+	t.Run("requires q", func(t *testing.T) {
+		_, router, _ := setupSemanticRouter(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/notes/semantic-search", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("refuses users who did not opt in", func(t *testing.T) {
+		semanticSvc, router, _ := setupSemanticRouter(t)
+		semanticSvc.EXPECT().Search(gomock.Any(), gomock.Any()).Return(nil, domain.ErrSemanticSearchOff)
+
+		req := httptest.NewRequest(http.MethodGet, "/notes/semantic-search?q=trees", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "SEMANTIC_SEARCH_OFF")
+	})
+}
+
+func TestSemanticHandler_Related(t *testing.T) {
+	t.Run("lists related notes", func(t *testing.T) {
+		semanticSvc, router, userID := setupSemanticRouter(t)
+		noteID := uuid.New()
+
+		semanticSvc.EXPECT().Related(gomock.Any(), semantic.RelatedInput{UserID: userID, NoteID: noteID}).
+			Return([]entity.SearchHit{}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/notes/"+noteID.String()+"/related", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.JSONEq(t, `{"notes":[]}`, w.Body.String())
+	})
+
+	t.Run("maps service errors", func(t *testing.T) {
+		for err, status := range map[error]int{
+			domain.ErrNoteNotFound:      http.StatusNotFound,
+			domain.ErrForbidden:         http.StatusForbidden,
+			domain.ErrSemanticSearchOff: http.StatusForbidden,
+		} {
+			semanticSvc, router, _ := setupSemanticRouter(t)
+			semanticSvc.EXPECT().Related(gomock.Any(), gomock.Any()).Return(nil, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/notes/"+uuid.NewString()+"/related", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, status, w.Code, err.Error())
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/request"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/share"
+)
+
+type ShareHandler struct {
+	shareSvc ShareService
+}
+
+func NewShareHandler(shareSvc ShareService) *ShareHandler {
+	return &ShareHandler{shareSvc: shareSvc}
+}
+
+// Bulk godoc
+//
+//	@Summary		Share notes in bulk
+//	@Description	Grant, change or revoke access to several notes in one call. Grants replace existing roles; a user both granted and revoked keeps the grant.
+//	@Tags			shares
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		request.BulkShareRequest	true	"Notes, grants and revokes"
+//	@Success		200		{object}	response.BulkShareResponse
+//	@Failure		400		{object}	httputil.ErrorResponse
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Failure		403		{object}	httputil.ErrorResponse
+//	@Failure		404		{object}	httputil.ErrorResponse
+//	@Router			/shares [post]
+func (h *ShareHandler) Bulk(c *gin.Context) {
+	var req request.BulkShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	input := share.BulkInput{
+		UserID:  httputil.GetUserID(c),
+		NoteIDs: make([]uuid.UUID, 0, len(req.NoteIDs)),
+		Revokes: req.Revokes,
+	}
+	for _, id := range req.NoteIDs {
+		input.NoteIDs = append(input.NoteIDs, uuid.MustParse(id))
+	}
+	for _, g := range req.Grants {
+		input.Grants = append(input.Grants, share.Grant{Email: g.Email, Role: g.Role})
+	}
+
+	result, err := h.shareSvc.Bulk(c.Request.Context(), input)
+	if err != nil {
+		writeShareError(c, err)
+		return
+	}
+
+	httputil.OK(c, response.BulkShareFromResult(result))
+}
+
+// List godoc
+//
+//	@Summary		List note shares
+//	@Description	List the users a note is shared with; only the owner can see them
+//	@Tags			shares
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			id	path		string	true	"Note ID"	format(uuid)
+//	@Success		200	{array}		response.ShareResponse
+//	@Failure		400	{object}	httputil.ErrorResponse
+//	@Failure		401	{object}	httputil.ErrorResponse
+//	@Failure		403	{object}	httputil.ErrorResponse
+//	@Failure		404	{object}	httputil.ErrorResponse
+//	@Router			/notes/{id}/shares [get]
+func (h *ShareHandler) List(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidID, "invalid note id")
+		return
+	}
+
+	shares, err := h.shareSvc.List(c.Request.Context(), httputil.GetUserID(c), noteID)
+	if err != nil {
+		writeShareError(c, err)
+		return
+	}
+
+	httputil.OK(c, response.SharesFromEntities(shares))
+}
+
+func writeShareError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNoteNotFound):
+		httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "note not found")
+	case errors.Is(err, domain.ErrUserNotFound):
+		httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "user not found")
+	case errors.Is(err, domain.ErrForbidden):
+		httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeForbidden, "access denied")
+	case errors.Is(err, domain.ErrShareWithOwner):
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "cannot share a note with its owner")
+	case errors.Is(err, domain.ErrInvalidShareRole):
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "role must be viewer or editor")
+	default:
+		httputil.InternalError(c)
+	}
+}
@@ -0,0 +1,109 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/share"
+)
+
+func TestShareHandler_Bulk(t *testing.T) {
+	t.Run("applies grants and revokes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		shareSvc := mocks.NewMockShareService(ctrl)
+		h := handler.NewShareHandler(shareSvc)
+
+		router := setupRouter()
+		userID := uuid.New()
+		noteID := uuid.New()
+		router.POST("/shares", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Bulk(c)
+		})
+
+		shareSvc.EXPECT().Bulk(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ any, input share.BulkInput) (*share.BulkResult, error) {
+				assert.Equal(t, userID, input.UserID)
+				assert.Equal(t, []uuid.UUID{noteID}, input.NoteIDs)
+				assert.Equal(t, []share.Grant{{Email: "alice@example.com", Role: "editor"}}, input.Grants)
+				assert.Equal(t, []string{"bob@example.com"}, input.Revokes)
+				return &share.BulkResult{Granted: 1, Revoked: 1}, nil
+			})
+
+		body := `{"note_ids":["` + noteID.String() + `"],"grants":[{"email":"alice@example.com","role":"editor"}],"revokes":["bob@example.com"]}`
+		req := httptest.NewRequest(http.MethodPost, "/shares", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp response.BulkShareResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 1, resp.Granted)
+		assert.Equal(t, int64(1), resp.Revoked)
+	})
+
+	t.Run("rejects unknown role", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		h := handler.NewShareHandler(mocks.NewMockShareService(ctrl))
+
+		router := setupRouter()
+		router.POST("/shares", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.Bulk(c)
+		})
+
+		body := `{"note_ids":["` + uuid.NewString() + `"],"grants":[{"email":"alice@example.com","role":"admin"}]}`
+		req := httptest.NewRequest(http.MethodPost, "/shares", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("returns forbidden for notes of another user", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		shareSvc := mocks.NewMockShareService(ctrl)
+		h := handler.NewShareHandler(shareSvc)
+
+		router := setupRouter()
+		router.POST("/shares", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.Bulk(c)
+		})
+
+		shareSvc.EXPECT().Bulk(gomock.Any(), gomock.Any()).Return(nil, domain.ErrForbidden)
+
+		body := `{"note_ids":["` + uuid.NewString() + `"],"revokes":["bob@example.com"]}`
+		req := httptest.NewRequest(http.MethodPost, "/shares", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+type StatsHandler struct {
+	statsSvc StatsService
+}
+
+func NewStatsHandler(statsSvc StatsService) *StatsHandler {
+	return &StatsHandler{statsSvc: statsSvc}
+}
+
+// Get godoc
+//
+//	@Summary		Get account statistics
+//	@Description	Get the current user's note and photo counts and the storage used by their photos
+//	@Tags			stats
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{object}	response.UserStatsResponse
+//	@Failure		401	{object}	httputil.ErrorResponse
+//	@Router			/me/stats [get]
+func (h *StatsHandler) Get(c *gin.Context) {
+	stats, err := h.statsSvc.Get(c.Request.Context(), httputil.GetUserID(c))
+	if err != nil {
+		httputil.InternalError(c)
+		return
+	}
+
+	httputil.OK(c, response.UserStatsFromEntity(stats))
+}
@@ -0,0 +1,56 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+)
+
+func TestStatsHandler_Get(t *testing.T) {
+	t.Run("returns stats", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		statsSvc := mocks.NewMockStatsService(ctrl)
+		h := handler.NewStatsHandler(statsSvc)
+
+		router := setupRouter()
+		userID := uuid.New()
+		router.GET("/me/stats", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Get(c)
+		})
+
+		statsSvc.EXPECT().Get(gomock.Any(), userID).Return(&entity.UserStats{
+			UserID:       userID,
+			NoteCount:    12,
+			PhotoCount:   30,
+			StorageBytes: 1 << 20,
+		}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/me/stats", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp response.UserStatsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, int64(12), resp.NoteCount)
+		assert.Equal(t, int64(30), resp.PhotoCount)
+		assert.Equal(t, int64(1<<20), resp.StorageBytes)
+	})
+}
@@ -2,37 +2,41 @@ package handler
 
 import (
 	"errors"
-
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 
 	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/request"
 	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
 	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
 	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/sync"
 )
 
 type SyncHandler struct {
 	syncSvc SyncService
+	prefSvc PreferenceService
+	mask    entity.LocationMask
 }
 
-func NewSyncHandler(syncSvc SyncService) *SyncHandler {
-	return &SyncHandler{syncSvc: syncSvc}
+func NewSyncHandler(syncSvc SyncService, prefSvc PreferenceService, mask entity.LocationMask) *SyncHandler {
+	return &SyncHandler{syncSvc: syncSvc, prefSvc: prefSvc, mask: mask}
 }
 
 // Sync godoc
 //
 //	@Summary		Sync notes
-//	@Description	Sync notes between client and server using last-write-wins strategy
+//	@Description	Sync notes between client and server using last-write-wins strategy; with conflict_strategy keep_both the losing version is saved as a new note whose ID is returned as copy_id. Photos the device holds can be sent by client_id; each comes back with whether it still needs uploading and to which note. Server notes are paged by limit (at most 1000); while has_more is true the client syncs again with next_page_token as page_token, and new_cursor only advances on the last page
 //	@Tags			sync
 //	@Security		BearerAuth
 //	@Accept			json
 //	@Produce		json
 //	@Param			request	body		request.SyncRequest	true	"Sync data with client notes"
+//	@Param			units	query		string				false	"Unit system for measurements, defaults to the user's preference"	Enums(metric, imperial)
 //	@Success		200		{object}	response.SyncResponse
-//	@Failure		400		{object}	httputil.ErrorResponse	"Device not found or validation error"
+//	@Failure		400		{object}	httputil.ErrorResponse	"Device not found, invalid page token or validation error"
 //	@Failure		401		{object}	httputil.ErrorResponse
 //	@Router			/sync [post]
 func (h *SyncHandler) Sync(c *gin.Context) {
@@ -46,33 +50,291 @@ func (h *SyncHandler) Sync(c *gin.Context) {
 
 	clientNotes := make([]sync.ClientNote, 0, len(req.Notes))
 	for _, n := range req.Notes {
+		measurements, ok := measurementsFromRequest(n.Measurements)
+		if !ok {
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidMeasurement, "invalid measurement value in note "+n.ClientID)
+			return
+		}
+
 		clientNotes = append(clientNotes, sync.ClientNote{
-			ClientID:  n.ClientID,
-			Title:     n.Title,
-			Content:   n.Content,
-			Latitude:  n.Latitude,
-			Longitude: n.Longitude,
-			Altitude:  n.Altitude,
-			Accuracy:  n.Accuracy,
-			UpdatedAt: n.UpdatedAt,
+			ClientID:     n.ClientID,
+			Title:        n.Title,
+			Content:      n.Content,
+			Latitude:     n.Latitude,
+			Longitude:    n.Longitude,
+			Altitude:     n.Altitude,
+			Accuracy:     n.Accuracy,
+			Measurements: measurements,
+			Tags:         n.Tags,
+			Color:        n.Color,
+			Icon:         n.Icon,
+			UpdatedAt:    n.UpdatedAt,
+			FieldsUpdatedAt: entity.NoteFieldTimes{
+				Title:    n.FieldUpdatedAt.Title,
+				Content:  n.FieldUpdatedAt.Content,
+				Location: n.FieldUpdatedAt.Location,
+			},
 			IsDeleted: n.IsDeleted,
 		})
 	}
 
+	clientPhotos := make([]sync.ClientPhoto, 0, len(req.Photos))
+	for _, p := range req.Photos {
+		clientPhotos = append(clientPhotos, sync.ClientPhoto{ClientID: p.ClientID, NoteClientID: p.NoteClientID})
+	}
+
 	result, err := h.syncSvc.BatchSync(c.Request.Context(), sync.SyncInput{
-		UserID:      userID,
-		DeviceID:    req.DeviceID,
-		ClientNotes: clientNotes,
-		SyncCursor:  req.SyncCursor,
+		UserID:           userID,
+		DeviceID:         req.DeviceID,
+		ClientNotes:      clientNotes,
+		ClientPhotos:     clientPhotos,
+		SyncCursor:       req.SyncCursor,
+		ConflictStrategy: req.ConflictStrategy,
+		Limit:            req.Limit,
+		PageToken:        req.PageToken,
+		SkipAutoTitle:    optedOut(req.AutoTitle),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidCursor):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidCursor, "invalid page token")
+		case errors.Is(err, domain.ErrDeviceNotFound):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeDeviceNotFound, "device not registered, please login first")
+		default:
+			httputil.InternalError(c)
+		}
+		return
+	}
+
+	httputil.CountKPI(c, entity.KPINotesPushed, int64(len(req.Notes)))
+	httputil.CountKPI(c, entity.KPINotesCreated, int64(result.Created))
+	httputil.CountKPI(c, entity.KPIConflicts, int64(len(result.Conflicts)))
+	if len(req.Notes) > 0 {
+		httputil.MarkNotesChanged(c)
+	}
+
+	withMeasurements := hasMeasurements(result.ServerNotes...)
+	for _, conflict := range result.Conflicts {
+		if conflict.ServerVersion != nil && len(conflict.ServerVersion.Measurements) > 0 {
+			withMeasurements = true
+		}
+	}
+
+	httputil.OK(c, response.SyncResultToResponse(result, noteView(c, h.prefSvc, h.mask, withMeasurements)))
+}
+
+// PhotoManifest godoc
+//
+//	@Summary		Photo sync manifest
+//	@Description	List photo metadata added or removed since a cursor, so clients can download content lazily
+//	@Tags			sync
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			device_id	query		string	false	"Apply this device's sync scope"
+//	@Param			cursor		query		string	false	"next_cursor of the previous manifest, or an RFC3339 timestamp"
+//	@Param			limit		query		int		false	"Maximum entries"	default(500)
+//	@Success		200			{object}	response.PhotoManifestResponse
+//	@Failure		400			{object}	httputil.ErrorResponse	"Device not found, invalid cursor or validation error"
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Router			/sync/photos/manifest [get]
+func (h *SyncHandler) PhotoManifest(c *gin.Context) {
+	var req request.PhotoManifestRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	manifest, err := h.syncSvc.PhotoManifest(c.Request.Context(), sync.PhotoManifestInput{
+		UserID:   httputil.GetUserID(c),
+		DeviceID: req.DeviceID,
+		Cursor:   req.Cursor,
+		Limit:    req.Limit,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidCursor):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidCursor, "invalid cursor")
+		case errors.Is(err, domain.ErrDeviceNotFound):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeDeviceNotFound, "device not registered, please login first")
+		default:
+			httputil.InternalError(c)
+		}
+		return
+	}
+
+	httputil.OK(c, response.PhotoManifestToResponse(manifest))
+}
+
+// Changes godoc
+//
+//	@Summary		Pull server changes
+//	@Description	Page through notes changed on the server, oldest first, without pushing anything or moving the device's sync cursor
+//	@Tags			sync
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			device_id	query		string	false	"Apply this device's sync scope"
+//	@Param			cursor		query		string	false	"next_cursor of the previous page, or an RFC3339 timestamp"
+//	@Param			limit		query		int		false	"Maximum notes"	default(500)
+//	@Param			units		query		string	false	"Unit system for measurements, defaults to the user's preference"	Enums(metric, imperial)
+//	@Success		200			{object}	response.SyncChangesResponse
+//	@Failure		400			{object}	httputil.ErrorResponse	"Device not found, invalid cursor or validation error"
+//	@Failure		401			{object}	httputil.ErrorResponse
+//	@Router			/sync/changes [get]
+func (h *SyncHandler) Changes(c *gin.Context) {
+	var req request.SyncChangesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	changes, err := h.syncSvc.Changes(c.Request.Context(), sync.ChangesInput{
+		UserID:   httputil.GetUserID(c),
+		DeviceID: req.DeviceID,
+		Cursor:   req.Cursor,
+		Limit:    req.Limit,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidCursor):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidCursor, "invalid cursor")
+		case errors.Is(err, domain.ErrDeviceNotFound):
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeDeviceNotFound, "device not registered, please login first")
+		default:
+			httputil.InternalError(c)
+		}
+		return
+	}
+
+	httputil.OK(c, response.SyncChangesToResponse(changes, noteView(c, h.prefSvc, h.mask, hasMeasurements(changes.Notes...))))
+}
+
+// UpdateScope godoc
+//
+//	@Summary		Set device sync scope
+//	@Description	Limit what a device pulls during sync (notes created since a date, no photos)
+//	@Tags			sync
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		request.SyncScopeRequest	true	"Sync scope"
+//	@Success		200		{object}	response.SyncScopeResponse
+//	@Failure		400		{object}	httputil.ErrorResponse	"Device not found or validation error"
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Router			/sync/scope [put]
+func (h *SyncHandler) UpdateScope(c *gin.Context) {
+	var req request.SyncScopeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	device, err := h.syncSvc.UpdateScope(c.Request.Context(), sync.ScopeInput{
+		UserID:   httputil.GetUserID(c),
+		DeviceID: req.DeviceID,
+		Scope: entity.SyncScope{
+			NotesSince:    req.NotesSince,
+			ExcludePhotos: req.ExcludePhotos,
+		},
 	})
 	if err != nil {
 		if errors.Is(err, domain.ErrDeviceNotFound) {
-			httputil.ErrorWithCode(c, http.StatusBadRequest, "DEVICE_NOT_FOUND", "device not registered, please login first")
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeDeviceNotFound, "device not registered, please login first")
 			return
 		}
 		httputil.InternalError(c)
 		return
 	}
 
-	httputil.OK(c, response.SyncResultToResponse(result))
+	httputil.OK(c, response.SyncScopeFromDevice(device))
+}
+
+// Capabilities godoc
+//
+//	@Summary		Sync capabilities
+//	@Description	Describe the sync features this server supports (batch limits, conflict strategies, cursor formats, photo sync, compression) so clients can negotiate behavior at runtime
+//	@Tags			sync
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{object}	response.SyncCapabilitiesResponse
+//	@Failure		401	{object}	httputil.ErrorResponse
+//	@Router			/sync/capabilities [get]
+func (h *SyncHandler) Capabilities(c *gin.Context) {
+	httputil.OK(c, response.SyncCapabilitiesToResponse(h.syncSvc.Capabilities()))
+}
+
+// Conflicts godoc
+//
+//	@Summary		List sync conflicts
+//	@Description	List the client versions held by the manual conflict strategy that are waiting for review, oldest first. Conflicts are dropped once SYNC_CONFLICT_RETENTION has passed
+//	@Tags			sync
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			units	query		string	false	"Unit system for measurements, defaults to the user's preference"	Enums(metric, imperial)
+//	@Success		200		{object}	response.SyncConflictsResponse
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Router			/sync/conflicts [get]
+func (h *SyncHandler) Conflicts(c *gin.Context) {
+	conflicts, err := h.syncSvc.ListConflicts(c.Request.Context(), httputil.GetUserID(c))
+	if err != nil {
+		httputil.InternalError(c)
+		return
+	}
+
+	withMeasurements := false
+	for _, conflict := range conflicts {
+		if len(conflict.ClientVersion.Measurements) > 0 {
+			withMeasurements = true
+		}
+	}
+
+	httputil.OK(c, response.SyncConflictsToResponse(conflicts, noteView(c, h.prefSvc, h.mask, withMeasurements)))
+}
+
+// ReplayConflict godoc
+//
+//	@Summary		Replay a sync conflict
+//	@Description	Resolve a conflict held by the manual strategy. side client applies the held client version to the note, but only while the note is still the version it conflicted with; side server keeps the note as it is. Returns the note as it stands afterwards
+//	@Tags			sync
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		request.ReplayConflictRequest	true	"Conflict and side to keep"
+//	@Param			units	query		string							false	"Unit system for measurements, defaults to the user's preference"	Enums(metric, imperial)
+//	@Success		200		{object}	response.NoteResponse
+//	@Failure		400		{object}	httputil.ErrorResponse	"Validation error"
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Failure		404		{object}	httputil.ErrorResponse	"Conflict not found or expired"
+//	@Failure		409		{object}	httputil.ErrorResponse	"Conflict already resolved or note changed since the conflict"
+//	@Router			/sync/conflicts/replay [post]
+func (h *SyncHandler) ReplayConflict(c *gin.Context) {
+	var req request.ReplayConflictRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	note, err := h.syncSvc.ReplayConflict(c.Request.Context(), sync.ReplayInput{
+		UserID:     httputil.GetUserID(c),
+		ConflictID: uuid.MustParse(req.ConflictID),
+		Side:       req.Side,
+		DeviceID:   req.DeviceID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrSyncConflictNotFound):
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "sync conflict not found")
+		case errors.Is(err, domain.ErrNoteNotFound):
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "note not found")
+		case errors.Is(err, domain.ErrSyncConflictResolved):
+			httputil.ErrorWithCode(c, http.StatusConflict, httputil.CodeConflictResolved, "sync conflict already resolved")
+		case errors.Is(err, domain.ErrNoteChanged):
+			httputil.ErrorWithCode(c, http.StatusConflict, httputil.CodeNoteChanged, "note changed since the conflict")
+		default:
+			httputil.InternalError(c)
+		}
+		return
+	}
+
+	httputil.MarkNotesChanged(c)
+	httputil.OK(c, response.NoteFromEntity(note, noteView(c, h.prefSvc, h.mask, hasMeasurements(*note))))
 }
@@ -2,6 +2,7 @@ package handler_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -27,7 +28,7 @@ func TestSyncHandler_Sync(t *testing.T) {
 		defer ctrl.Finish()
 
 		syncSvc := mocks.NewMockSyncService(ctrl)
-		h := handler.NewSyncHandler(syncSvc)
+		h := handler.NewSyncHandler(syncSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
 
 		router := setupRouter()
 		userID := uuid.New()
@@ -87,7 +88,7 @@ func TestSyncHandler_Sync(t *testing.T) {
 		defer ctrl.Finish()
 
 		syncSvc := mocks.NewMockSyncService(ctrl)
-		h := handler.NewSyncHandler(syncSvc)
+		h := handler.NewSyncHandler(syncSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
 
 		router := setupRouter()
 		userID := uuid.New()
@@ -153,7 +154,7 @@ func TestSyncHandler_Sync(t *testing.T) {
 		defer ctrl.Finish()
 
 		syncSvc := mocks.NewMockSyncService(ctrl)
-		h := handler.NewSyncHandler(syncSvc)
+		h := handler.NewSyncHandler(syncSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
 
 		router := setupRouter()
 		userID := uuid.New()
@@ -189,7 +190,7 @@ func TestSyncHandler_Sync(t *testing.T) {
 		defer ctrl.Finish()
 
 		syncSvc := mocks.NewMockSyncService(ctrl)
-		h := handler.NewSyncHandler(syncSvc)
+		h := handler.NewSyncHandler(syncSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
 
 		router := setupRouter()
 		userID := uuid.New()
@@ -218,12 +219,106 @@ func TestSyncHandler_Sync(t *testing.T) {
 		assert.Equal(t, "DEVICE_NOT_FOUND", resp["code"])
 	})
 
+	t.Run("passes paging through and reports more pages", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		syncSvc := mocks.NewMockSyncService(ctrl)
+		h := handler.NewSyncHandler(syncSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		router.POST("/sync", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.Sync(c)
+		})
+
+		syncSvc.EXPECT().BatchSync(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, input sync.SyncInput) (*sync.SyncResult, error) {
+				assert.Equal(t, 50, input.Limit)
+				assert.Equal(t, "page-1", input.PageToken)
+				return &sync.SyncResult{HasMore: true, NextPageToken: "page-2"}, nil
+			})
+
+		body := `{"device_id": "device-123", "limit": 50, "page_token": "page-1"}`
+		req := httptest.NewRequest(http.MethodPost, "/sync", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, true, resp["has_more"])
+		assert.Equal(t, "page-2", resp["next_page_token"])
+	})
+
+	t.Run("passes field times through", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		syncSvc := mocks.NewMockSyncService(ctrl)
+		h := handler.NewSyncHandler(syncSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		router.POST("/sync", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.Sync(c)
+		})
+
+		syncSvc.EXPECT().BatchSync(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, input sync.SyncInput) (*sync.SyncResult, error) {
+				require.Len(t, input.ClientNotes, 1)
+				fields := input.ClientNotes[0].FieldsUpdatedAt
+				assert.Equal(t, time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), fields.Title.UTC())
+				assert.True(t, fields.Content.IsZero())
+				return &sync.SyncResult{}, nil
+			})
+
+		body := `{"device_id": "device-123", "notes": [{"client_id": "oak", "title": "Oak", "content": "Three oaks",
+			"updated_at": "2026-03-01T10:00:00Z", "field_updated_at": {"title": "2026-03-01T09:00:00Z"}}]}`
+		req := httptest.NewRequest(http.MethodPost, "/sync", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("returns bad request for invalid page token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		syncSvc := mocks.NewMockSyncService(ctrl)
+		h := handler.NewSyncHandler(syncSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		router.POST("/sync", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.Sync(c)
+		})
+
+		syncSvc.EXPECT().BatchSync(gomock.Any(), gomock.Any()).Return(nil, domain.ErrInvalidCursor)
+
+		body := `{"device_id": "device-123", "page_token": "bogus"}`
+		req := httptest.NewRequest(http.MethodPost, "/sync", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_CURSOR")
+	})
+
 	t.Run("returns validation error for missing device_id", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		syncSvc := mocks.NewMockSyncService(ctrl)
-		h := handler.NewSyncHandler(syncSvc)
+		h := handler.NewSyncHandler(syncSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
 
 		router := setupRouter()
 		userID := uuid.New()
@@ -242,12 +337,35 @@ func TestSyncHandler_Sync(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
+	t.Run("returns validation error for unknown conflict strategy", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		syncSvc := mocks.NewMockSyncService(ctrl)
+		h := handler.NewSyncHandler(syncSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		router.POST("/sync", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.Sync(c)
+		})
+
+		body := `{"device_id": "device-123", "conflict_strategy": "merge"}`
+		req := httptest.NewRequest(http.MethodPost, "/sync", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
 	t.Run("syncs with deleted note", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		syncSvc := mocks.NewMockSyncService(ctrl)
-		h := handler.NewSyncHandler(syncSvc)
+		h := handler.NewSyncHandler(syncSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
 
 		router := setupRouter()
 		userID := uuid.New()
@@ -284,4 +402,356 @@ func TestSyncHandler_Sync(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
+
+	t.Run("returns photo statuses", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		syncSvc := mocks.NewMockSyncService(ctrl)
+		h := handler.NewSyncHandler(syncSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		userID := uuid.New()
+		router.POST("/sync", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Sync(c)
+		})
+
+		noteID := uuid.New()
+		result := &sync.SyncResult{
+			ServerNotes: []entity.Note{},
+			NewCursor:   time.Now().UTC(),
+			Conflicts:   []sync.ConflictInfo{},
+			Photos: []sync.PhotoStatus{
+				{ClientID: "photo-1", Status: sync.PhotoPendingUpload, NoteID: noteID},
+			},
+		}
+
+		syncSvc.EXPECT().BatchSync(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, input sync.SyncInput) (*sync.SyncResult, error) {
+				require.Len(t, input.ClientPhotos, 1)
+				assert.Equal(t, "photo-1", input.ClientPhotos[0].ClientID)
+				assert.Equal(t, "note-1", input.ClientPhotos[0].NoteClientID)
+				return result, nil
+			})
+
+		body := `{
+			"device_id": "device-123",
+			"photos": [{"client_id": "photo-1", "note_client_id": "note-1"}]
+		}`
+		req := httptest.NewRequest(http.MethodPost, "/sync", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp struct {
+			Photos []struct {
+				ClientID      string `json:"client_id"`
+				Status        string `json:"status"`
+				PendingUpload bool   `json:"pending_upload"`
+				NoteID        string `json:"note_id"`
+			} `json:"photos"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Photos, 1)
+		assert.Equal(t, "pending_upload", resp.Photos[0].Status)
+		assert.True(t, resp.Photos[0].PendingUpload)
+		assert.Equal(t, noteID.String(), resp.Photos[0].NoteID)
+	})
+}
+
+func TestSyncHandler_PhotoManifest(t *testing.T) {
+	t.Run("returns manifest", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		syncSvc := mocks.NewMockSyncService(ctrl)
+		h := handler.NewSyncHandler(syncSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		userID := uuid.New()
+		router.GET("/sync/photos/manifest", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.PhotoManifest(c)
+		})
+
+		cursor := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+		photo := entity.Photo{ID: uuid.New(), NoteID: uuid.New(), Size: 1024, Checksum: "abc", CreatedAt: cursor.Add(time.Minute)}
+		syncSvc.EXPECT().PhotoManifest(gomock.Any(), sync.PhotoManifestInput{UserID: userID, Cursor: "2024-01-15T10:00:00Z"}).
+			Return(&sync.PhotoManifest{
+				Added:      []entity.Photo{photo},
+				Removed:    []entity.PhotoTombstone{},
+				NextCursor: "next-page",
+			}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/sync/photos/manifest?cursor=2024-01-15T10:00:00Z", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		added := resp["added"].([]any)
+		require.Len(t, added, 1)
+		assert.Equal(t, "abc", added[0].(map[string]any)["checksum"])
+		assert.Equal(t, "next-page", resp["next_cursor"])
+		assert.Equal(t, false, resp["has_more"])
+	})
+
+	t.Run("returns bad request for invalid cursor", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		syncSvc := mocks.NewMockSyncService(ctrl)
+		h := handler.NewSyncHandler(syncSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		router.GET("/sync/photos/manifest", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.PhotoManifest(c)
+		})
+
+		syncSvc.EXPECT().PhotoManifest(gomock.Any(), gomock.Any()).Return(nil, domain.ErrInvalidCursor)
+
+		req := httptest.NewRequest(http.MethodGet, "/sync/photos/manifest?cursor=bogus", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_CURSOR")
+	})
+}
+
+func TestSyncHandler_Changes(t *testing.T) {
+	t.Run("returns a page of changes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		syncSvc := mocks.NewMockSyncService(ctrl)
+		h := handler.NewSyncHandler(syncSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		userID := uuid.New()
+		router.GET("/sync/changes", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.Changes(c)
+		})
+
+		note := entity.Note{ID: uuid.New(), UserID: userID, Title: "Changed", UpdatedAt: time.Now()}
+		syncSvc.EXPECT().Changes(gomock.Any(), sync.ChangesInput{UserID: userID, Cursor: "abc", Limit: 50}).
+			Return(&sync.Changes{Notes: []entity.Note{note}, NextCursor: "def", HasMore: true}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/sync/changes?cursor=abc&limit=50", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp["notes"].([]any), 1)
+		assert.Equal(t, "def", resp["next_cursor"])
+		assert.Equal(t, true, resp["has_more"])
+	})
+
+	t.Run("returns bad request for invalid cursor", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		syncSvc := mocks.NewMockSyncService(ctrl)
+		h := handler.NewSyncHandler(syncSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		router.GET("/sync/changes", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.Changes(c)
+		})
+
+		syncSvc.EXPECT().Changes(gomock.Any(), gomock.Any()).Return(nil, domain.ErrInvalidCursor)
+
+		req := httptest.NewRequest(http.MethodGet, "/sync/changes?cursor=bogus", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_CURSOR")
+	})
+}
+
+func TestSyncHandler_UpdateScope(t *testing.T) {
+	t.Run("updates scope", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		syncSvc := mocks.NewMockSyncService(ctrl)
+		h := handler.NewSyncHandler(syncSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		userID := uuid.New()
+		router.PUT("/sync/scope", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.UpdateScope(c)
+		})
+
+		syncSvc.EXPECT().UpdateScope(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, input sync.ScopeInput) (*entity.Device, error) {
+				assert.Equal(t, "tablet", input.DeviceID)
+				assert.True(t, input.Scope.ExcludePhotos)
+				require.NotNil(t, input.Scope.NotesSince)
+				return &entity.Device{DeviceID: input.DeviceID, Scope: input.Scope}, nil
+			})
+
+		body := `{"device_id": "tablet", "notes_since": "2024-06-01T00:00:00Z", "exclude_photos": true}`
+		req := httptest.NewRequest(http.MethodPut, "/sync/scope", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, true, resp["exclude_photos"])
+		assert.Equal(t, "2024-06-01T00:00:00Z", resp["notes_since"])
+	})
+
+	t.Run("returns bad request for unknown device", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		syncSvc := mocks.NewMockSyncService(ctrl)
+		h := handler.NewSyncHandler(syncSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		router.PUT("/sync/scope", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.UpdateScope(c)
+		})
+
+		syncSvc.EXPECT().UpdateScope(gomock.Any(), gomock.Any()).Return(nil, domain.ErrDeviceNotFound)
+
+		req := httptest.NewRequest(http.MethodPut, "/sync/scope", bytes.NewBufferString(`{"device_id": "unknown"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestSyncHandler_ReplayConflict(t *testing.T) {
+	setup := func(t *testing.T) (*mocks.MockSyncService, *gin.Engine, uuid.UUID) {
+		ctrl := gomock.NewController(t)
+		syncSvc := mocks.NewMockSyncService(ctrl)
+		h := handler.NewSyncHandler(syncSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+		router := setupRouter()
+		userID := uuid.New()
+		router.POST("/sync/conflicts/replay", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.ReplayConflict(c)
+		})
+		return syncSvc, router, userID
+	}
+	replay := func(router *gin.Engine, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/sync/conflicts/replay", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("returns the note after keeping the client side", func(t *testing.T) {
+		syncSvc, router, userID := setup(t)
+		conflictID := uuid.New()
+
+		syncSvc.EXPECT().ReplayConflict(gomock.Any(), sync.ReplayInput{
+			UserID: userID, ConflictID: conflictID, Side: entity.ConflictSideClient, DeviceID: "desktop",
+		}).Return(&entity.Note{ID: uuid.New(), UserID: userID, Title: "Client Version"}, nil)
+
+		w := replay(router, `{"conflict_id": "`+conflictID.String()+`", "side": "client", "device_id": "desktop"}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Client Version")
+	})
+
+	t.Run("returns conflict when the note changed", func(t *testing.T) {
+		syncSvc, router, _ := setup(t)
+		syncSvc.EXPECT().ReplayConflict(gomock.Any(), gomock.Any()).Return(nil, domain.ErrNoteChanged)
+
+		w := replay(router, `{"conflict_id": "`+uuid.NewString()+`", "side": "client"}`)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "NOTE_CHANGED")
+	})
+
+	t.Run("returns conflict when already resolved", func(t *testing.T) {
+		syncSvc, router, _ := setup(t)
+		syncSvc.EXPECT().ReplayConflict(gomock.Any(), gomock.Any()).Return(nil, domain.ErrSyncConflictResolved)
+
+		w := replay(router, `{"conflict_id": "`+uuid.NewString()+`", "side": "server"}`)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "CONFLICT_RESOLVED")
+	})
+
+	t.Run("returns not found for unknown conflicts", func(t *testing.T) {
+		syncSvc, router, _ := setup(t)
+		syncSvc.EXPECT().ReplayConflict(gomock.Any(), gomock.Any()).Return(nil, domain.ErrSyncConflictNotFound)
+
+		w := replay(router, `{"conflict_id": "`+uuid.NewString()+`", "side": "server"}`)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("returns validation error for unknown side", func(t *testing.T) {
+		_, router, _ := setup(t)
+
+		w := replay(router, `{"conflict_id": "`+uuid.NewString()+`", "side": "both"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestSyncHandler_Capabilities(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	syncSvc := mocks.NewMockSyncService(ctrl)
+	h := handler.NewSyncHandler(syncSvc, mocks.NewMockPreferenceService(ctrl), entity.LocationMask{})
+
+	router := setupRouter()
+	router.GET("/sync/capabilities", h.Capabilities)
+
+	syncSvc.EXPECT().Capabilities().Return(sync.Capabilities{
+		ProtocolVersion:         1,
+		MaxBatchSize:            1000,
+		ConflictStrategies:      []string{sync.StrategyLastWriteWins, sync.StrategyKeepBoth},
+		DefaultConflictStrategy: sync.StrategyLastWriteWins,
+		CursorTypes:             []string{sync.CursorTypeTimestamp},
+		PhotoSync:               true,
+		Compression:             []string{},
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sync/capabilities", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.EqualValues(t, 1000, resp["max_batch_size"])
+	assert.Equal(t, []any{"last_write_wins", "keep_both"}, resp["conflict_strategies"])
+	assert.Equal(t, true, resp["photo_sync"])
+	assert.Equal(t, []any{}, resp["compression"])
 }
@@ -0,0 +1,295 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/request"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/apperror"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/team"
+)
+
+type TeamHandler struct {
+	teamSvc TeamService
+}
+
+func NewTeamHandler(teamSvc TeamService) *TeamHandler {
+	return &TeamHandler{teamSvc: teamSvc}
+}
+
+// Create godoc
+//
+//	@Summary		Create a team
+//	@Description	Create a team for a shared field project; the user becomes its owner
+//	@Tags			teams
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		request.CreateTeamRequest	true	"Team name"
+//	@Success		201		{object}	response.TeamResponse
+//	@Failure		400		{object}	httputil.ErrorResponse
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Router			/teams [post]
+func (h *TeamHandler) Create(c *gin.Context) {
+	var req request.CreateTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	t, err := h.teamSvc.Create(c.Request.Context(), httputil.GetUserID(c), req.Name)
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+
+	httputil.Created(c, response.TeamFromEntity(t))
+}
+
+// List godoc
+//
+//	@Summary		List teams
+//	@Description	List the teams the user belongs to, and those they are invited to with pending set
+//	@Tags			teams
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{array}		response.TeamResponse
+//	@Failure		401	{object}	httputil.ErrorResponse
+//	@Router			/teams [get]
+func (h *TeamHandler) List(c *gin.Context) {
+	teams, err := h.teamSvc.List(c.Request.Context(), httputil.GetUserID(c))
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+
+	httputil.OK(c, response.TeamsFromEntities(teams))
+}
+
+// Members godoc
+//
+//	@Summary		List team members
+//	@Description	List the team's members and pending invitations; only members can see them
+//	@Tags			teams
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			id	path		string	true	"Team ID"	format(uuid)
+//	@Success		200	{array}		response.TeamMemberResponse
+//	@Failure		400	{object}	httputil.ErrorResponse
+//	@Failure		401	{object}	httputil.ErrorResponse
+//	@Failure		403	{object}	httputil.ErrorResponse
+//	@Failure		404	{object}	httputil.ErrorResponse
+//	@Router			/teams/{id}/members [get]
+func (h *TeamHandler) Members(c *gin.Context) {
+	teamID, ok := teamIDParam(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.teamSvc.Members(c.Request.Context(), httputil.GetUserID(c), teamID)
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+
+	httputil.OK(c, response.TeamMembersFromEntities(members))
+}
+
+// Invite godoc
+//
+//	@Summary		Invite a team member
+//	@Description	Invite a user by email as admin, editor or viewer; only the team's owner and admins can invite. The user joins once they accept.
+//	@Tags			teams
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string							true	"Team ID"	format(uuid)
+//	@Param			request	body		request.InviteTeamMemberRequest	true	"Invitee and role"
+//	@Success		201		{object}	response.TeamMemberResponse
+//	@Failure		400		{object}	httputil.ErrorResponse
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Failure		403		{object}	httputil.ErrorResponse
+//	@Failure		404		{object}	httputil.ErrorResponse
+//	@Failure		409		{object}	httputil.ErrorResponse
+//	@Router			/teams/{id}/members [post]
+func (h *TeamHandler) Invite(c *gin.Context) {
+	teamID, ok := teamIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req request.InviteTeamMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	member, err := h.teamSvc.Invite(c.Request.Context(), team.InviteInput{
+		UserID: httputil.GetUserID(c),
+		TeamID: teamID,
+		Email:  req.Email,
+		Role:   req.Role,
+	})
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+
+	httputil.Created(c, response.TeamMemberFromEntity(member))
+}
+
+// Accept godoc
+//
+//	@Summary		Accept a team invitation
+//	@Description	Join a team the user was invited to
+//	@Tags			teams
+//	@Security		BearerAuth
+//	@Param			id	path	string	true	"Team ID"	format(uuid)
+//	@Success		204
+//	@Failure		400	{object}	httputil.ErrorResponse
+//	@Failure		401	{object}	httputil.ErrorResponse
+//	@Failure		404	{object}	httputil.ErrorResponse
+//	@Router			/teams/{id}/accept [post]
+func (h *TeamHandler) Accept(c *gin.Context) {
+	teamID, ok := teamIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.teamSvc.Accept(c.Request.Context(), httputil.GetUserID(c), teamID); err != nil {
+		if errors.Is(err, domain.ErrTeamMemberNotFound) {
+			httputil.Fail(c, apperror.New(http.StatusNotFound, httputil.CodeNotFound, "no pending invitation to this team"))
+			return
+		}
+		writeTeamError(c, err)
+		return
+	}
+
+	httputil.NoContent(c)
+}
+
+// UpdateRole godoc
+//
+//	@Summary		Change a team member's role
+//	@Description	Make a member or invitee an admin, editor or viewer; only the team's owner and admins can change roles, and never the owner's. Editors create and edit the team's notes, viewers only read them.
+//	@Tags			teams
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string							true	"Team ID"	format(uuid)
+//	@Param			user_id	path		string							true	"User ID"	format(uuid)
+//	@Param			request	body		request.UpdateTeamRoleRequest	true	"New role"
+//	@Success		200		{object}	response.TeamMemberResponse
+//	@Failure		400		{object}	httputil.ErrorResponse
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Failure		403		{object}	httputil.ErrorResponse
+//	@Failure		404		{object}	httputil.ErrorResponse
+//	@Router			/teams/{id}/members/{user_id} [patch]
+func (h *TeamHandler) UpdateRole(c *gin.Context) {
+	teamID, ok := teamIDParam(c)
+	if !ok {
+		return
+	}
+	memberID, ok := memberIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req request.UpdateTeamRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	member, err := h.teamSvc.UpdateRole(c.Request.Context(), team.RoleInput{
+		UserID:   httputil.GetUserID(c),
+		TeamID:   teamID,
+		MemberID: memberID,
+		Role:     req.Role,
+	})
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+
+	httputil.OK(c, response.TeamMemberFromEntity(member))
+}
+
+// RemoveMember godoc
+//
+//	@Summary		Remove a team member
+//	@Description	Remove a member or cancel an invitation; only the team's owner and admins can remove others. Pass your own user ID to leave the team or decline an invitation. The owner cannot be removed.
+//	@Tags			teams
+//	@Security		BearerAuth
+//	@Param			id		path	string	true	"Team ID"	format(uuid)
+//	@Param			user_id	path	string	true	"User ID"	format(uuid)
+//	@Success		204
+//	@Failure		400	{object}	httputil.ErrorResponse
+//	@Failure		401	{object}	httputil.ErrorResponse
+//	@Failure		403	{object}	httputil.ErrorResponse
+//	@Failure		404	{object}	httputil.ErrorResponse
+//	@Router			/teams/{id}/members/{user_id} [delete]
+func (h *TeamHandler) RemoveMember(c *gin.Context) {
+	teamID, ok := teamIDParam(c)
+	if !ok {
+		return
+	}
+	memberID, ok := memberIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.teamSvc.RemoveMember(c.Request.Context(), httputil.GetUserID(c), teamID, memberID); err != nil {
+		writeTeamError(c, err)
+		return
+	}
+
+	httputil.NoContent(c)
+}
+
+func teamIDParam(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.Fail(c, apperror.New(http.StatusBadRequest, httputil.CodeInvalidID, "invalid team id"))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func memberIDParam(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		httputil.Fail(c, apperror.New(http.StatusBadRequest, httputil.CodeInvalidID, "invalid user id"))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func writeTeamError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrTeamNotFound):
+		httputil.Fail(c, apperror.New(http.StatusNotFound, httputil.CodeNotFound, "team not found"))
+	case errors.Is(err, domain.ErrTeamMemberNotFound):
+		httputil.Fail(c, apperror.New(http.StatusNotFound, httputil.CodeNotFound, "team member not found"))
+	case errors.Is(err, domain.ErrUserNotFound):
+		httputil.Fail(c, apperror.New(http.StatusNotFound, httputil.CodeNotFound, "user not found"))
+	case errors.Is(err, domain.ErrForbidden):
+		httputil.Fail(c, apperror.New(http.StatusForbidden, httputil.CodeForbidden, "access denied"))
+	case errors.Is(err, domain.ErrAlreadyTeamMember):
+		httputil.Fail(c, apperror.New(http.StatusConflict, httputil.CodeAlreadyMember, "user is already a member or invited"))
+	case errors.Is(err, domain.ErrTeamOwnerCannotLeave):
+		httputil.Fail(c, apperror.New(http.StatusBadRequest, httputil.CodeValidationError, "the team owner cannot leave the team"))
+	case errors.Is(err, domain.ErrInvalidTeamName):
+		httputil.Fail(c, apperror.New(http.StatusBadRequest, httputil.CodeValidationError, "name must not be blank"))
+	case errors.Is(err, domain.ErrInvalidTeamRole):
+		httputil.Fail(c, apperror.New(http.StatusBadRequest, httputil.CodeValidationError, "role must be admin, editor or viewer"))
+	default:
+		httputil.InternalError(c)
+	}
+}
@@ -0,0 +1,180 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/team"
+)
+
+func setupTeamRouter(t *testing.T) (*mocks.MockTeamService, *gin.Engine, uuid.UUID) {
+	ctrl := gomock.NewController(t)
+	teamSvc := mocks.NewMockTeamService(ctrl)
+	h := handler.NewTeamHandler(teamSvc)
+
+	router := setupRouter()
+	userID := uuid.New()
+	auth := func(c *gin.Context) { c.Set("user_id", userID) }
+	router.POST("/teams", auth, h.Create)
+	router.GET("/teams", auth, h.List)
+	router.POST("/teams/:id/members", auth, h.Invite)
+	router.POST("/teams/:id/accept", auth, h.Accept)
+	router.PATCH("/teams/:id/members/:user_id", auth, h.UpdateRole)
+	router.DELETE("/teams/:id/members/:user_id", auth, h.RemoveMember)
+	return teamSvc, router, userID
+}
+
+func TestTeamHandler_Create(t *testing.T) {
+	teamSvc, router, userID := setupTeamRouter(t)
+	created := &entity.UserTeam{
+		Team:       entity.Team{ID: uuid.New(), Name: "River survey"},
+		Membership: entity.TeamMember{Role: entity.TeamRoleOwner, AcceptedAt: new(time.Time)},
+	}
+	teamSvc.EXPECT().Create(gomock.Any(), userID, "River survey").Return(created, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/teams", bytes.NewBufferString(`{"name":"River survey"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	var resp response.TeamResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, created.ID, resp.ID)
+	assert.Equal(t, entity.TeamRoleOwner, resp.Role)
+	assert.False(t, resp.Pending)
+}
+
+func TestTeamHandler_Invite(t *testing.T) {
+	t.Run("invites by email", func(t *testing.T) {
+		teamSvc, router, userID := setupTeamRouter(t)
+		teamID := uuid.New()
+		teamSvc.EXPECT().Invite(gomock.Any(), team.InviteInput{
+			UserID: userID, TeamID: teamID, Email: "colleague@example.com", Role: entity.TeamRoleEditor,
+		}).Return(&entity.TeamMember{UserID: uuid.New(), Email: "colleague@example.com", Role: entity.TeamRoleEditor}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/teams/"+teamID.String()+"/members",
+			bytes.NewBufferString(`{"email":"colleague@example.com","role":"editor"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		var resp response.TeamMemberResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.True(t, resp.Pending)
+	})
+
+	t.Run("maps service errors", func(t *testing.T) {
+		for err, status := range map[error]int{
+			domain.ErrForbidden:         http.StatusForbidden,
+			domain.ErrTeamNotFound:      http.StatusNotFound,
+			domain.ErrUserNotFound:      http.StatusNotFound,
+			domain.ErrAlreadyTeamMember: http.StatusConflict,
+		} {
+			teamSvc, router, _ := setupTeamRouter(t)
+			teamSvc.EXPECT().Invite(gomock.Any(), gomock.Any()).Return(nil, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/teams/"+uuid.NewString()+"/members",
+				bytes.NewBufferString(`{"email":"colleague@example.com","role":"admin"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, status, w.Code, err.Error())
+		}
+	})
+
+	t.Run("rejects the owner role", func(t *testing.T) {
+		_, router, _ := setupTeamRouter(t)
+
+		req := httptest.NewRequest(http.MethodPost, "/teams/"+uuid.NewString()+"/members",
+			bytes.NewBufferString(`{"email":"colleague@example.com","role":"owner"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestTeamHandler_Accept(t *testing.T) {
+	teamSvc, router, userID := setupTeamRouter(t)
+	teamID := uuid.New()
+	teamSvc.EXPECT().Accept(gomock.Any(), userID, teamID).Return(domain.ErrTeamMemberNotFound)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/teams/"+teamID.String()+"/accept", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestTeamHandler_UpdateRole(t *testing.T) {
+	t.Run("changes the role", func(t *testing.T) {
+		teamSvc, router, userID := setupTeamRouter(t)
+		teamID, memberID := uuid.New(), uuid.New()
+		teamSvc.EXPECT().UpdateRole(gomock.Any(), team.RoleInput{
+			UserID: userID, TeamID: teamID, MemberID: memberID, Role: entity.TeamRoleViewer,
+		}).Return(&entity.TeamMember{UserID: memberID, Role: entity.TeamRoleViewer, AcceptedAt: new(time.Time)}, nil)
+
+		req := httptest.NewRequest(http.MethodPatch, "/teams/"+teamID.String()+"/members/"+memberID.String(),
+			bytes.NewBufferString(`{"role":"viewer"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp response.TeamMemberResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, entity.TeamRoleViewer, resp.Role)
+	})
+
+	t.Run("rejects the owner role", func(t *testing.T) {
+		_, router, _ := setupTeamRouter(t)
+
+		req := httptest.NewRequest(http.MethodPatch, "/teams/"+uuid.NewString()+"/members/"+uuid.NewString(),
+			bytes.NewBufferString(`{"role":"owner"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestTeamHandler_RemoveMember(t *testing.T) {
+	t.Run("leaves the team", func(t *testing.T) {
+		teamSvc, router, userID := setupTeamRouter(t)
+		teamID := uuid.New()
+		teamSvc.EXPECT().RemoveMember(gomock.Any(), userID, teamID, userID).Return(nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/teams/"+teamID.String()+"/members/"+userID.String(), nil))
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("rejects an invalid user id", func(t *testing.T) {
+		_, router, _ := setupTeamRouter(t)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/teams/"+uuid.NewString()+"/members/nope", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
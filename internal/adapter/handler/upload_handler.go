@@ -3,17 +3,28 @@ package handler
 import (
 	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/request"
 	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler/dto/response"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
 	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
 	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/upload"
 )
 
-const maxUploadSize = 10 << 20 // 10MB
+const (
+	maxUploadSize      = 10 << 20 // 10MB
+	maxAudioUploadSize = 25 << 20 // 25MB
+	maxBatchUploadSize = 100 << 20
+	maxBatchFiles      = 20
+	// maxClientIDLength matches the client_id columns of notes and photos.
+	maxClientIDLength = 36
+)
 
 type UploadHandler struct {
 	uploadSvc UploadService
@@ -26,23 +37,26 @@ func NewUploadHandler(uploadSvc UploadService) *UploadHandler {
 // Upload godoc
 //
 //	@Summary		Upload image to note
-//	@Description	Upload an image file (JPEG/PNG) to a note
+//	@Description	Upload an image file (JPEG/PNG) to a note. Uploading a file the note already has a photo of returns that photo with duplicate set; compare its SHA-256 with the photos' source_checksum to skip the upload
 //	@Tags			upload
 //	@Security		BearerAuth
 //	@Accept			multipart/form-data
 //	@Produce		json
 //	@Param			note_id	path		string	true	"Note ID"	format(uuid)
-//	@Param			file	formData	file	true	"Image file (max 10MB)"
+//	@Param			file		formData	file	true	"Image file (max 10MB)"
+//	@Param			client_id	formData	string	false	"Device-generated photo ID; repeating an upload with it returns the stored photo"
+//	@Param			set_note_location	formData	bool	false	"Set the note's location from the photo's EXIF GPS position when the note has none"
 //	@Success		201		{object}	response.UploadResponse
 //	@Failure		400		{object}	httputil.ErrorResponse	"Invalid file or note ID"
 //	@Failure		401		{object}	httputil.ErrorResponse
 //	@Failure		403		{object}	httputil.ErrorResponse
 //	@Failure		404		{object}	httputil.ErrorResponse
+//	@Failure		409		{object}	httputil.ErrorResponse	"client_id already used on another note"
 //	@Router			/upload/{note_id} [post]
 func (h *UploadHandler) Upload(c *gin.Context) {
 	noteID, err := uuid.Parse(c.Param("note_id"))
 	if err != nil {
-		httputil.ErrorWithCode(c, http.StatusBadRequest, "INVALID_ID", "invalid note id")
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidID, "invalid note id")
 		return
 	}
 
@@ -50,42 +64,225 @@ func (h *UploadHandler) Upload(c *gin.Context) {
 
 	file, header, err := c.Request.FormFile("file")
 	if err != nil {
-		httputil.ErrorWithCode(c, http.StatusBadRequest, "INVALID_FILE", "file is required")
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidFile, "file is required")
 		return
 	}
 	defer file.Close()
 
 	contentType := header.Header.Get("Content-Type")
 	if !isAllowedImageType(contentType) {
-		httputil.ErrorWithCode(c, http.StatusBadRequest, "INVALID_TYPE", "only jpeg and png images are allowed")
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidType, "only jpeg and png images are allowed")
+		return
+	}
+
+	clientID := c.PostForm("client_id")
+	if len(clientID) > maxClientIDLength {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "client_id is too long")
 		return
 	}
 
+	setNoteLocation := false
+	if value := c.PostForm("set_note_location"); value != "" {
+		if setNoteLocation, err = strconv.ParseBool(value); err != nil {
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "set_note_location must be a boolean")
+			return
+		}
+	}
+
 	userID := httputil.GetUserID(c)
 
 	result, err := h.uploadSvc.Upload(c.Request.Context(), upload.UploadInput{
-		UserID:      userID,
-		NoteID:      noteID,
-		File:        file,
-		Filename:    header.Filename,
-		ContentType: contentType,
-		Size:        header.Size,
+		UserID:          userID,
+		NoteID:          noteID,
+		File:            file,
+		Filename:        header.Filename,
+		ContentType:     contentType,
+		Size:            header.Size,
+		ClientID:        clientID,
+		SetNoteLocation: setNoteLocation,
 	})
 	if err != nil {
 		switch {
 		case errors.Is(err, domain.ErrNoteNotFound):
-			httputil.ErrorWithCode(c, http.StatusNotFound, "NOT_FOUND", "note not found")
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "note not found")
 		case errors.Is(err, domain.ErrForbidden):
-			httputil.ErrorWithCode(c, http.StatusForbidden, "FORBIDDEN", "access denied")
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeForbidden, "access denied")
+		case errors.Is(err, domain.ErrPhotoClientIDInUse):
+			httputil.ErrorWithCode(c, http.StatusConflict, httputil.CodeClientIDInUse, "client_id is already used by a photo of another note")
 		default:
 			httputil.InternalError(c)
 		}
 		return
 	}
 
+	httputil.MarkNotesChanged(c)
 	httputil.Created(c, response.UploadResultToResponse(result))
 }
 
+// UploadBatch godoc
+//
+//	@Summary		Upload several images to a note
+//	@Description	Upload up to 20 image files (JPEG/PNG, 10MB each, 100MB in all) to a note in one request. Each file is stored or fails on its own, as with a single upload, and results lists the outcome of each in the order sent; the request only fails as a whole when the note cannot be found or edited, or the form is malformed
+//	@Tags			upload
+//	@Security		BearerAuth
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			note_id	path		string	true	"Note ID"	format(uuid)
+//	@Param			files		formData	file	true	"Image files, one part each"
+//	@Param			client_ids	formData	[]string	false	"Device-generated photo IDs, one part per file in the same order"	collectionFormat(multi)
+//	@Param			set_note_location	formData	bool	false	"Set the note's location from the EXIF GPS position of the first photo that has one, when the note has none"
+//	@Success		200		{object}	response.BatchUploadResponse
+//	@Failure		400		{object}	httputil.ErrorResponse	"Missing files, too many files or invalid note ID"
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Failure		403		{object}	httputil.ErrorResponse
+//	@Failure		404		{object}	httputil.ErrorResponse
+//	@Failure		413		{object}	httputil.ErrorResponse
+//	@Router			/upload/{note_id}/batch [post]
+func (h *UploadHandler) UploadBatch(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("note_id"))
+	if err != nil {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidID, "invalid note id")
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBatchUploadSize)
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			httputil.ErrorWithCode(c, http.StatusRequestEntityTooLarge, httputil.CodeBodyTooLarge, "batch is larger than 100MB")
+			return
+		}
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidFile, "files are required")
+		return
+	}
+
+	headers := form.File["files"]
+	if len(headers) == 0 {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidFile, "files are required")
+		return
+	}
+	if len(headers) > maxBatchFiles {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "a batch has at most 20 files")
+		return
+	}
+
+	clientIDs := form.Value["client_ids"]
+	if len(clientIDs) > 0 && len(clientIDs) != len(headers) {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "client_ids must have one entry per file")
+		return
+	}
+	for _, id := range clientIDs {
+		if len(id) > maxClientIDLength {
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "client_id is too long")
+			return
+		}
+	}
+
+	setNoteLocation := false
+	if value := c.PostForm("set_note_location"); value != "" {
+		if setNoteLocation, err = strconv.ParseBool(value); err != nil {
+			httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "set_note_location must be a boolean")
+			return
+		}
+	}
+
+	// Files that fail validation are reported without reaching the service;
+	// positions maps the files sent to it back to their place in results.
+	results := make([]response.BatchFileResponse, len(headers))
+	var files []upload.BatchFile
+	var positions []int
+	for i, header := range headers {
+		results[i] = response.BatchFileResponse{Filename: header.Filename}
+		if len(clientIDs) > 0 {
+			results[i].ClientID = clientIDs[i]
+		}
+
+		contentType := header.Header.Get("Content-Type")
+		switch {
+		case !isAllowedImageType(contentType):
+			results[i].Error = &response.BatchFileError{Code: httputil.CodeInvalidType, Message: "only jpeg and png images are allowed"}
+			continue
+		case header.Size > maxUploadSize:
+			results[i].Error = &response.BatchFileError{Code: httputil.CodeValidationError, Message: "file is larger than 10MB"}
+			continue
+		}
+
+		file, err := header.Open()
+		if err != nil {
+			results[i].Error = &response.BatchFileError{Code: httputil.CodeInvalidFile, Message: "file could not be read"}
+			continue
+		}
+		defer file.Close()
+
+		files = append(files, upload.BatchFile{
+			File:        file,
+			Filename:    header.Filename,
+			ContentType: contentType,
+			Size:        header.Size,
+			ClientID:    results[i].ClientID,
+		})
+		positions = append(positions, i)
+	}
+
+	uploaded, err := h.uploadSvc.UploadBatch(c.Request.Context(), upload.BatchUploadInput{
+		UserID:          httputil.GetUserID(c),
+		NoteID:          noteID,
+		Files:           files,
+		SetNoteLocation: setNoteLocation,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNoteNotFound):
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "note not found")
+		case errors.Is(err, domain.ErrForbidden):
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeForbidden, "access denied")
+		default:
+			httputil.InternalError(c)
+		}
+		return
+	}
+
+	for j, r := range uploaded {
+		i := positions[j]
+		if r.Err != nil {
+			results[i].Error = batchFileError(r.Err)
+			continue
+		}
+		resp := response.UploadResultToResponse(r.Result)
+		results[i].Upload = &resp
+	}
+
+	resp := response.BatchUploadResponse{Results: results}
+	for i := range results {
+		if results[i].Error != nil {
+			results[i].Status = response.BatchFileFailed
+			resp.Failed++
+		} else {
+			results[i].Status = response.BatchFileUploaded
+			resp.Uploaded++
+		}
+	}
+
+	if resp.Uploaded > 0 {
+		httputil.MarkNotesChanged(c)
+	}
+	httputil.OK(c, resp)
+}
+
+// batchFileError describes why the upload of one file of a batch failed.
+func batchFileError(err error) *response.BatchFileError {
+	switch {
+	case errors.Is(err, domain.ErrPhotoClientIDInUse):
+		return &response.BatchFileError{Code: httputil.CodeClientIDInUse, Message: "client_id is already used by a photo of another note"}
+	case errors.Is(err, domain.ErrNoteNotFound):
+		return &response.BatchFileError{Code: httputil.CodeNotFound, Message: "note not found"}
+	default:
+		return &response.BatchFileError{Code: httputil.CodeInternalError, Message: "the photo could not be stored; upload it again"}
+	}
+}
+
 // Delete godoc
 //
 //	@Summary		Delete a photo
@@ -102,7 +299,7 @@ func (h *UploadHandler) Upload(c *gin.Context) {
 func (h *UploadHandler) Delete(c *gin.Context) {
 	photoID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		httputil.ErrorWithCode(c, http.StatusBadRequest, "INVALID_ID", "invalid photo id")
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidID, "invalid photo id")
 		return
 	}
 
@@ -111,15 +308,184 @@ func (h *UploadHandler) Delete(c *gin.Context) {
 	if err := h.uploadSvc.Delete(c.Request.Context(), userID, photoID); err != nil {
 		switch {
 		case errors.Is(err, domain.ErrPhotoNotFound):
-			httputil.ErrorWithCode(c, http.StatusNotFound, "NOT_FOUND", "photo not found")
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "photo not found")
+		case errors.Is(err, domain.ErrForbidden):
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeForbidden, "access denied")
+		default:
+			httputil.InternalError(c)
+		}
+		return
+	}
+
+	httputil.MarkNotesChanged(c)
+	httputil.NoContent(c)
+}
+
+// SetSharing godoc
+//
+//	@Summary		Exclude a photo from shares
+//	@Description	Hide a photo from the users its note is shared with, or show it again. Excluded photos are left out of the note for everyone but the owner. Only the owner may change it.
+//	@Tags			upload
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Photo ID"	format(uuid)
+//	@Param			request	body		request.PhotoSharingRequest	true	"Share exclusion"
+//	@Success		200		{object}	response.PhotoResponse
+//	@Failure		400		{object}	httputil.ErrorResponse
+//	@Failure		401		{object}	httputil.ErrorResponse
+//	@Failure		403		{object}	httputil.ErrorResponse
+//	@Failure		404		{object}	httputil.ErrorResponse
+//	@Router			/photos/{id}/sharing [put]
+func (h *UploadHandler) SetSharing(c *gin.Context) {
+	photoID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidID, "invalid photo id")
+		return
+	}
+
+	var req request.PhotoSharingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httputil.ValidationError(c, err)
+		return
+	}
+
+	photo, err := h.uploadSvc.SetShareExclusion(c.Request.Context(), httputil.GetUserID(c), photoID, *req.Excluded)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrPhotoNotFound), errors.Is(err, domain.ErrNoteNotFound):
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "photo not found")
+		case errors.Is(err, domain.ErrForbidden):
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeForbidden, "access denied")
+		default:
+			httputil.InternalError(c)
+		}
+		return
+	}
+
+	httputil.MarkNotesChanged(c)
+	httputil.OK(c, response.PhotoFromEntity(photo))
+}
+
+// UploadAudio godoc
+//
+//	@Summary		Upload audio to note
+//	@Description	Attach a voice memo (M4A/MP3) to a note
+//	@Tags			upload
+//	@Security		BearerAuth
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			note_id		path		string	true	"Note ID"	format(uuid)
+//	@Param			file		formData	file	true	"Audio file (max 25MB)"
+//	@Param			duration_ms	formData	int		true	"Recording length in milliseconds (max 4h)"
+//	@Param			client_id	formData	string	false	"Device-generated attachment ID; repeating an upload with it returns the stored attachment"
+//	@Success		201			{object}	response.AttachmentUploadResponse
+//	@Failure		400			{object}	httputil.ErrorResponse	"Invalid file, duration or note ID"
+//	@Failure		401			{object}	httputil.ErrorResponse
+//	@Failure		403			{object}	httputil.ErrorResponse
+//	@Failure		404			{object}	httputil.ErrorResponse
+//	@Failure		409			{object}	httputil.ErrorResponse	"client_id already used on another note"
+//	@Router			/upload/{note_id}/audio [post]
+func (h *UploadHandler) UploadAudio(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("note_id"))
+	if err != nil {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidID, "invalid note id")
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxAudioUploadSize)
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidFile, "file is required")
+		return
+	}
+	defer file.Close()
+
+	contentType := header.Header.Get("Content-Type")
+	if !entity.IsAudioType(contentType) {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidType, "only m4a and mp3 audio is allowed")
+		return
+	}
+
+	durationMS, err := strconv.ParseInt(c.PostForm("duration_ms"), 10, 64)
+	duration := time.Duration(durationMS) * time.Millisecond
+	if err != nil || durationMS <= 0 || duration > entity.MaxAudioDuration {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "duration_ms must be a positive number of milliseconds up to 4 hours")
+		return
+	}
+
+	clientID := c.PostForm("client_id")
+	if len(clientID) > maxClientIDLength {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeValidationError, "client_id is too long")
+		return
+	}
+
+	userID := httputil.GetUserID(c)
+
+	result, err := h.uploadSvc.UploadAudio(c.Request.Context(), upload.AudioUploadInput{
+		UserID:      userID,
+		NoteID:      noteID,
+		File:        file,
+		Filename:    header.Filename,
+		ContentType: contentType,
+		Size:        header.Size,
+		Duration:    duration,
+		ClientID:    clientID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNoteNotFound):
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "note not found")
+		case errors.Is(err, domain.ErrForbidden):
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeForbidden, "access denied")
+		case errors.Is(err, domain.ErrAttachmentClientIDInUse):
+			httputil.ErrorWithCode(c, http.StatusConflict, httputil.CodeClientIDInUse, "client_id is already used by an attachment of another note")
+		default:
+			httputil.InternalError(c)
+		}
+		return
+	}
+
+	httputil.MarkNotesChanged(c)
+	httputil.Created(c, response.AttachmentResultToResponse(result))
+}
+
+// DeleteAttachment godoc
+//
+//	@Summary		Delete an attachment
+//	@Description	Delete a voice memo or other attachment from a note
+//	@Tags			upload
+//	@Security		BearerAuth
+//	@Param			id	path	string	true	"Attachment ID"	format(uuid)
+//	@Success		204	"No content"
+//	@Failure		400	{object}	httputil.ErrorResponse
+//	@Failure		401	{object}	httputil.ErrorResponse
+//	@Failure		403	{object}	httputil.ErrorResponse
+//	@Failure		404	{object}	httputil.ErrorResponse
+//	@Router			/attachments/{id} [delete]
+func (h *UploadHandler) DeleteAttachment(c *gin.Context) {
+	attachmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.ErrorWithCode(c, http.StatusBadRequest, httputil.CodeInvalidID, "invalid attachment id")
+		return
+	}
+
+	userID := httputil.GetUserID(c)
+
+	if err := h.uploadSvc.DeleteAttachment(c.Request.Context(), userID, attachmentID); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrAttachmentNotFound), errors.Is(err, domain.ErrNoteNotFound):
+			httputil.ErrorWithCode(c, http.StatusNotFound, httputil.CodeNotFound, "attachment not found")
 		case errors.Is(err, domain.ErrForbidden):
-			httputil.ErrorWithCode(c, http.StatusForbidden, "FORBIDDEN", "access denied")
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeForbidden, "access denied")
 		default:
 			httputil.InternalError(c)
 		}
 		return
 	}
 
+	httputil.MarkNotesChanged(c)
 	httputil.NoContent(c)
 }
 
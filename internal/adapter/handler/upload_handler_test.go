@@ -2,6 +2,7 @@ package handler_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"mime/multipart"
@@ -207,6 +208,195 @@ func TestUploadHandler_Upload(t *testing.T) {
 
 		assert.Equal(t, http.StatusForbidden, w.Code)
 	})
+
+	t.Run("returns conflict for a client id used on another note", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		uploadSvc := mocks.NewMockUploadService(ctrl)
+		h := handler.NewUploadHandler(uploadSvc)
+
+		router := setupRouter()
+		noteID := uuid.New()
+		router.POST("/notes/:note_id/upload", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.Upload(c)
+		})
+
+		uploadSvc.EXPECT().Upload(gomock.Any(), gomock.Any()).Return(nil, domain.ErrPhotoClientIDInUse)
+
+		fileContent := []byte{0xFF, 0xD8, 0xFF, 0xE0}
+		req, _ := createMultipartRequest(t, "/notes/"+noteID.String()+"/upload", "file", "test.jpg", "image/jpeg", fileContent)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "CLIENT_ID_IN_USE")
+	})
+
+	t.Run("marks a duplicate upload", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		uploadSvc := mocks.NewMockUploadService(ctrl)
+		h := handler.NewUploadHandler(uploadSvc)
+
+		router := setupRouter()
+		noteID := uuid.New()
+		router.POST("/notes/:note_id/upload", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.Upload(c)
+		})
+
+		uploadSvc.EXPECT().Upload(gomock.Any(), gomock.Any()).Return(&upload.UploadResult{
+			Photo:     &entity.Photo{ID: uuid.New(), NoteID: noteID, SourceChecksum: "9f86d081"},
+			Duplicate: true,
+		}, nil)
+
+		fileContent := []byte{0xFF, 0xD8, 0xFF, 0xE0}
+		req, _ := createMultipartRequest(t, "/notes/"+noteID.String()+"/upload", "file", "test.jpg", "image/jpeg", fileContent)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var resp struct {
+			Duplicate bool `json:"duplicate"`
+			Photo     struct {
+				SourceChecksum string `json:"source_checksum"`
+			} `json:"photo"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.True(t, resp.Duplicate)
+		assert.Equal(t, "9f86d081", resp.Photo.SourceChecksum)
+	})
+}
+
+// batchFile is one file part of a batch upload request.
+type batchFile struct {
+	name        string
+	contentType string
+}
+
+func createBatchRequest(t *testing.T, url string, files []batchFile, clientIDs ...string) *http.Request {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for _, f := range files {
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="files"; filename="%s"`, f.name))
+		h.Set("Content-Type", f.contentType)
+		part, err := writer.CreatePart(h)
+		require.NoError(t, err)
+		_, err = part.Write([]byte{0xFF, 0xD8, 0xFF, 0xE0})
+		require.NoError(t, err)
+	}
+	for _, id := range clientIDs {
+		require.NoError(t, writer.WriteField("client_ids", id))
+	}
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, url, body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestUploadHandler_UploadBatch(t *testing.T) {
+	setup := func(t *testing.T) (*mocks.MockUploadService, *gin.Engine) {
+		ctrl := gomock.NewController(t)
+		uploadSvc := mocks.NewMockUploadService(ctrl)
+		h := handler.NewUploadHandler(uploadSvc)
+
+		router := setupRouter()
+		router.POST("/upload/:note_id/batch", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.UploadBatch(c)
+		})
+		return uploadSvc, router
+	}
+
+	t.Run("reports each file", func(t *testing.T) {
+		uploadSvc, router := setup(t)
+		noteID := uuid.New()
+
+		uploadSvc.EXPECT().UploadBatch(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input upload.BatchUploadInput) ([]upload.BatchResult, error) {
+			require.Len(t, input.Files, 2)
+			assert.Equal(t, "a.jpg", input.Files[0].Filename)
+			assert.Equal(t, "c", input.Files[1].ClientID)
+			return []upload.BatchResult{
+				{Result: &upload.UploadResult{Photo: &entity.Photo{ID: uuid.New(), NoteID: noteID, ClientID: "a"}}},
+				{Err: domain.ErrPhotoClientIDInUse},
+			}, nil
+		})
+
+		req := createBatchRequest(t, "/upload/"+noteID.String()+"/batch", []batchFile{
+			{"a.jpg", "image/jpeg"}, {"b.gif", "image/gif"}, {"c.png", "image/png"},
+		}, "a", "b", "c")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp struct {
+			Results []struct {
+				Filename string `json:"filename"`
+				ClientID string `json:"client_id"`
+				Status   string `json:"status"`
+				Error    *struct {
+					Code string `json:"code"`
+				} `json:"error"`
+			} `json:"results"`
+			Uploaded int `json:"uploaded"`
+			Failed   int `json:"failed"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Results, 3)
+		assert.Equal(t, "uploaded", resp.Results[0].Status)
+		assert.Equal(t, "b.gif", resp.Results[1].Filename)
+		assert.Equal(t, "INVALID_TYPE", resp.Results[1].Error.Code)
+		assert.Equal(t, "c", resp.Results[2].ClientID)
+		assert.Equal(t, "CLIENT_ID_IN_USE", resp.Results[2].Error.Code)
+		assert.Equal(t, 1, resp.Uploaded)
+		assert.Equal(t, 2, resp.Failed)
+	})
+
+	t.Run("requires one client id per file", func(t *testing.T) {
+		_, router := setup(t)
+
+		req := createBatchRequest(t, "/upload/"+uuid.NewString()+"/batch", []batchFile{
+			{"a.jpg", "image/jpeg"}, {"b.jpg", "image/jpeg"},
+		}, "a")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("requires files", func(t *testing.T) {
+		_, router := setup(t)
+
+		req := createBatchRequest(t, "/upload/"+uuid.NewString()+"/batch", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("returns not found for a missing note", func(t *testing.T) {
+		uploadSvc, router := setup(t)
+		uploadSvc.EXPECT().UploadBatch(gomock.Any(), gomock.Any()).Return(nil, domain.ErrNoteNotFound)
+
+		req := createBatchRequest(t, "/upload/"+uuid.NewString()+"/batch", []batchFile{{"a.jpg", "image/jpeg"}})
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
 }
 
 func TestUploadHandler_Delete(t *testing.T) {
@@ -312,3 +502,226 @@ func TestUploadHandler_Delete(t *testing.T) {
 		assert.Equal(t, http.StatusForbidden, w.Code)
 	})
 }
+
+func TestUploadHandler_SetSharing(t *testing.T) {
+	setup := func(t *testing.T) (*mocks.MockUploadService, *gin.Engine, uuid.UUID) {
+		ctrl := gomock.NewController(t)
+		uploadSvc := mocks.NewMockUploadService(ctrl)
+		h := handler.NewUploadHandler(uploadSvc)
+
+		router := setupRouter()
+		userID := uuid.New()
+		router.PUT("/photos/:id/sharing", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.SetSharing(c)
+		})
+		return uploadSvc, router, userID
+	}
+
+	put := func(router *gin.Engine, photoID, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/photos/"+photoID+"/sharing", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("excludes photo from shares", func(t *testing.T) {
+		uploadSvc, router, userID := setup(t)
+		photoID := uuid.New()
+
+		uploadSvc.EXPECT().SetShareExclusion(gomock.Any(), userID, photoID, true).
+			Return(&entity.Photo{ID: photoID, ExcludedFromShares: true}, nil)
+
+		w := put(router, photoID.String(), `{"excluded":true}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, true, resp["excluded_from_shares"])
+	})
+
+	t.Run("includes photo again", func(t *testing.T) {
+		uploadSvc, router, userID := setup(t)
+		photoID := uuid.New()
+
+		uploadSvc.EXPECT().SetShareExclusion(gomock.Any(), userID, photoID, false).
+			Return(&entity.Photo{ID: photoID}, nil)
+
+		w := put(router, photoID.String(), `{"excluded":false}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("requires excluded", func(t *testing.T) {
+		_, router, _ := setup(t)
+
+		w := put(router, uuid.NewString(), `{}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("returns forbidden for non-owner", func(t *testing.T) {
+		uploadSvc, router, _ := setup(t)
+
+		uploadSvc.EXPECT().SetShareExclusion(gomock.Any(), gomock.Any(), gomock.Any(), true).Return(nil, domain.ErrForbidden)
+
+		w := put(router, uuid.NewString(), `{"excluded":true}`)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func createAudioRequest(t *testing.T, url, contentType string, fields map[string]string) *http.Request {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", `form-data; name="file"; filename="memo.m4a"`)
+	h.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(h)
+	require.NoError(t, err)
+	_, err = part.Write([]byte("fake audio"))
+	require.NoError(t, err)
+
+	for name, value := range fields {
+		require.NoError(t, writer.WriteField(name, value))
+	}
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, url, body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestUploadHandler_UploadAudio(t *testing.T) {
+	setup := func(t *testing.T) (*mocks.MockUploadService, *gin.Engine, uuid.UUID) {
+		ctrl := gomock.NewController(t)
+		uploadSvc := mocks.NewMockUploadService(ctrl)
+		h := handler.NewUploadHandler(uploadSvc)
+
+		router := setupRouter()
+		userID := uuid.New()
+		router.POST("/upload/:note_id/audio", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.UploadAudio(c)
+		})
+		return uploadSvc, router, userID
+	}
+
+	t.Run("uploads audio successfully", func(t *testing.T) {
+		uploadSvc, router, userID := setup(t)
+		noteID := uuid.New()
+
+		uploadSvc.EXPECT().UploadAudio(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, input upload.AudioUploadInput) (*upload.AttachmentResult, error) {
+				assert.Equal(t, userID, input.UserID)
+				assert.Equal(t, noteID, input.NoteID)
+				assert.Equal(t, 42*time.Second, input.Duration)
+				assert.Equal(t, "memo-1", input.ClientID)
+				return &upload.AttachmentResult{Attachment: &entity.Attachment{
+					ID: uuid.New(), NoteID: noteID, Kind: entity.AttachmentKindAudio, MimeType: "audio/m4a", Duration: input.Duration,
+				}}, nil
+			})
+
+		req := createAudioRequest(t, "/upload/"+noteID.String()+"/audio", "audio/m4a", map[string]string{"duration_ms": "42000", "client_id": "memo-1"})
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var resp struct {
+			Attachment struct {
+				Kind       string `json:"kind"`
+				DurationMS int64  `json:"duration_ms"`
+			} `json:"attachment"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "audio", resp.Attachment.Kind)
+		assert.EqualValues(t, 42000, resp.Attachment.DurationMS)
+	})
+
+	t.Run("rejects other content types", func(t *testing.T) {
+		_, router, _ := setup(t)
+
+		req := createAudioRequest(t, "/upload/"+uuid.NewString()+"/audio", "audio/wav", map[string]string{"duration_ms": "1000"})
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_TYPE")
+	})
+
+	t.Run("requires a valid duration", func(t *testing.T) {
+		_, router, _ := setup(t)
+
+		for _, duration := range []string{"", "abc", "0", "-5", "14400001"} {
+			req := createAudioRequest(t, "/upload/"+uuid.NewString()+"/audio", "audio/mpeg", map[string]string{"duration_ms": duration})
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, duration)
+			assert.Contains(t, w.Body.String(), "VALIDATION_ERROR", duration)
+		}
+	})
+
+	t.Run("returns conflict for a client id used on another note", func(t *testing.T) {
+		uploadSvc, router, _ := setup(t)
+
+		uploadSvc.EXPECT().UploadAudio(gomock.Any(), gomock.Any()).Return(nil, domain.ErrAttachmentClientIDInUse)
+
+		req := createAudioRequest(t, "/upload/"+uuid.NewString()+"/audio", "audio/m4a", map[string]string{"duration_ms": "1000", "client_id": "memo-1"})
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestUploadHandler_DeleteAttachment(t *testing.T) {
+	t.Run("deletes attachment successfully", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		uploadSvc := mocks.NewMockUploadService(ctrl)
+		h := handler.NewUploadHandler(uploadSvc)
+
+		router := setupRouter()
+		userID := uuid.New()
+		attachmentID := uuid.New()
+		router.DELETE("/attachments/:id", func(c *gin.Context) {
+			c.Set("user_id", userID)
+			h.DeleteAttachment(c)
+		})
+
+		uploadSvc.EXPECT().DeleteAttachment(gomock.Any(), userID, attachmentID).Return(nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/attachments/"+attachmentID.String(), nil))
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("returns not found for missing attachment", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		uploadSvc := mocks.NewMockUploadService(ctrl)
+		h := handler.NewUploadHandler(uploadSvc)
+
+		router := setupRouter()
+		router.DELETE("/attachments/:id", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			h.DeleteAttachment(c)
+		})
+
+		uploadSvc.EXPECT().DeleteAttachment(gomock.Any(), gomock.Any(), gomock.Any()).Return(domain.ErrAttachmentNotFound)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/attachments/"+uuid.NewString(), nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
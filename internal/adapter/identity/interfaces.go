@@ -0,0 +1,27 @@
+package identity
+
+import (
+	"context"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+// IDTokenClaims holds the verified identity returned by an OpenID Connect provider.
+type IDTokenClaims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Nonce         string
+}
+
+type OIDCProvider interface {
+	AuthCodeURL(ctx context.Context, org *entity.Organization, state, nonce string) (string, error)
+	Exchange(ctx context.Context, org *entity.Organization, code string) (*IDTokenClaims, error)
+}
+
+// SocialVerifier verifies ID tokens a social identity provider issued to
+// the apps through its native sign-in SDK.
+type SocialVerifier interface {
+	Verify(ctx context.Context, idToken string) (*IDTokenClaims, error)
+}
@@ -0,0 +1,16 @@
+package mail
+
+import "context"
+
+//go:generate mockgen -source=interfaces.go -destination=../../mocks/mail_mocks.go -package=mocks
+
+// Message is a plain-text email to a single recipient.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
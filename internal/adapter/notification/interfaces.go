@@ -0,0 +1,24 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+//go:generate mockgen -source=interfaces.go -destination=../../mocks/notification_mocks.go -package=mocks
+
+type Notifier interface {
+	// SyncConflict tells the user that a sync kept the server version of
+	// notes they had edited on a device.
+	SyncConflict(ctx context.Context, notice entity.SyncConflictNotice) error
+	// SecurityAlert tells the user about suspicious activity on their account.
+	SecurityAlert(ctx context.Context, notice entity.SecurityAlertNotice) error
+}
+
+// Pusher wakes a device's app with a silent push so it syncs without waiting
+// for its next poll. It returns domain.ErrPushTokenInvalid when the push
+// service no longer accepts the device's token.
+type Pusher interface {
+	SilentPush(ctx context.Context, device entity.Device) error
+}
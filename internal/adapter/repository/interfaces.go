@@ -8,6 +8,7 @@ import (
 
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/crdt"
 	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
 )
 
@@ -17,33 +18,198 @@ type UserRepository interface {
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
 	Update(ctx context.Context, user *entity.User) error
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
+	GetTokenVersion(ctx context.Context, id uuid.UUID) (int, error)
+	// BumpTokenVersion invalidates every access token issued to the user.
+	BumpTokenVersion(ctx context.Context, id uuid.UUID) error
 }
 
 type NoteRepository interface {
+	// Create assigns the note's number and reference before inserting it.
 	Create(ctx context.Context, note *entity.Note) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.Note, error)
 	GetByClientID(ctx context.Context, userID uuid.UUID, clientID string) (*entity.Note, error)
 	List(ctx context.Context, userID uuid.UUID, params NoteListParams) ([]entity.Note, *pagination.Info, error)
+	// Nearby returns the user's notes within the radius, closest first.
+	Nearby(ctx context.Context, userID uuid.UUID, params NoteNearbyParams) ([]entity.NearbyNote, error)
+	// Coverage returns the Web Mercator tiles at the zoom holding the user's
+	// notes with a location, up to limit of them, and the notes' bounds.
+	Coverage(ctx context.Context, userID uuid.UUID, zoom, limit int) (*entity.NoteCoverage, error)
+	// Search returns the user's notes matching the text query, optionally
+	// restricted to a radius or bounding box, best match first.
+	Search(ctx context.Context, userID uuid.UUID, params NoteSearchParams) ([]entity.SearchHit, error)
+	// Export calls fn with the user's notes matching the filters of params,
+	// in batches of up to batchSize ordered by number, all read from one
+	// snapshot. It stops at the first error fn returns.
+	Export(ctx context.Context, userID uuid.UUID, params NoteListParams, batchSize int, fn func([]entity.Note) error) error
 	Update(ctx context.Context, note *entity.Note) error
-	SoftDelete(ctx context.Context, id uuid.UUID) error
+	// SoftDelete and UpdateQuality take the note owner's userID, which
+	// selects the partition the note is in.
+	SoftDelete(ctx context.Context, userID, id uuid.UUID) error
+	// Merge saves the survivor and, in the same transaction, moves the
+	// photos and attachments of the merged notes to it and deletes them.
+	Merge(ctx context.Context, survivor *entity.Note, mergedIDs []uuid.UUID) error
+	// Purge hard-deletes every note of the user and restarts its numbering.
+	Purge(ctx context.Context, userID uuid.UUID) error
+	// PurgeDeleted hard-deletes up to limit notes soft-deleted before the
+	// given time and returns how many it deleted. Their storage objects are
+	// queued as storage orphans.
+	PurgeDeleted(ctx context.Context, before time.Time, limit int) (int64, error)
+	UpdateQuality(ctx context.Context, userID, id uuid.UUID, result entity.QualityResult) error
+	GetQualityReport(ctx context.Context, userID uuid.UUID) (*entity.QualityReport, error)
 
 	// Sync operations
-	GetModifiedSince(ctx context.Context, userID uuid.UUID, since time.Time, limit int) ([]entity.Note, error)
+	// GetModifiedAfter pages through changed notes in ascending (updated_at, id)
+	// order, starting after the cursor, so ties on updated_at are never skipped.
+	// Besides the user's own notes it returns those of the teams they are a
+	// member of.
+	GetModifiedAfter(ctx context.Context, userID uuid.UUID, after pagination.Cursor, limit int, scope entity.SyncScope) ([]entity.Note, error)
+	// GetByClientIDs returns the user's notes, deleted ones included, with any
+	// of the client IDs.
+	GetByClientIDs(ctx context.Context, userID uuid.UUID, clientIDs []string) ([]entity.Note, error)
+	// Existing returns the version of each of the user's notes, deleted ones
+	// included, with any of the IDs or client IDs, without reading the notes.
+	Existing(ctx context.Context, userID uuid.UUID, ids []uuid.UUID, clientIDs []string) ([]entity.NoteExistence, error)
+	// BatchUpsert sets each note's number and reference, keeping the stored
+	// ones for client IDs that already exist. Tags are replaced only for
+	// notes whose Tags is non-nil.
 	BatchUpsert(ctx context.Context, notes []entity.Note) error
+	// SetTags replaces the note's tags and bumps its updated_at.
+	SetTags(ctx context.Context, note *entity.Note) error
+
+	// Revisions returns up to limit of the note's revisions numbered below
+	// before, or its latest ones when before is 0, newest first. Offloaded
+	// content is left as its excerpt.
+	Revisions(ctx context.Context, noteID uuid.UUID, before, limit int) ([]entity.NoteRevision, error)
+	// Revision returns one revision of the note with its full content.
+	Revision(ctx context.Context, noteID uuid.UUID, revision int) (*entity.NoteRevision, error)
+}
+
+type NoteNearbyParams struct {
+	Latitude     float64
+	Longitude    float64
+	RadiusMeters float64
+	Limit        int
+}
+
+// NoteSearchParams restricts a text search to RadiusMeters around the point
+// when RadiusMeters is positive, or else to BoundingBox when it is set.
+type NoteSearchParams struct {
+	Query        string
+	Latitude     float64
+	Longitude    float64
+	RadiusMeters float64
+	BoundingBox  *valueobject.BoundingBox
+	Limit        int
 }
 
 type NoteListParams struct {
-	Pagination     pagination.Params
-	BoundingBox    *valueobject.BoundingBox
+	Pagination pagination.Params
+	// TeamID lists the team's notes, by any member, instead of the user's.
+	TeamID        *uuid.UUID
+	BoundingBox   *valueobject.BoundingBox
+	DeviceID      string
+	QualityStatus string
+	// Source keeps notes with this source, or from any integration when it
+	// is entity.NoteSourceIntegration.
+	Source    string
+	Number    int64
+	Reference string
+	// Tags keeps only notes carrying every one of these tags.
+	Tags []string
+	// Colors and Icons keep only notes with any one of these colors and
+	// any one of these icons.
+	Colors []string
+	Icons  []string
+	// CreatedFrom and CreatedTo, when set, keep notes created in
+	// [CreatedFrom, CreatedTo).
+	CreatedFrom    *time.Time
+	CreatedTo      *time.Time
 	IncludeDeleted bool
+	// Sort orders the notes by these entity.NoteSortFields, then by id
+	// unless it is one of them. Empty sorts by updated_at, newest first.
+	Sort []pagination.SortKey
+}
+
+// NoteSummaryRepository reads the note_summaries projection, which the
+// database keeps in step with every note, tag and photo write.
+type NoteSummaryRepository interface {
+	List(ctx context.Context, userID uuid.UUID, params NoteSummaryListParams) ([]entity.NoteSummary, *pagination.Info, error)
+	// Rebuild recomputes every summary, fixes those that drifted from the
+	// notes and returns how many it changed.
+	Rebuild(ctx context.Context) (int64, error)
+}
+
+// NoteSummaryListParams pages by cursor only, so lists never count rows.
+type NoteSummaryListParams struct {
+	PerPage int
+	After   *pagination.Cursor
+	// TeamID lists the team's notes, by any member, instead of the user's.
+	TeamID      *uuid.UUID
+	BoundingBox *valueobject.BoundingBox
+	// Tags keeps only notes carrying every one of these tags.
+	Tags []string
+	// Colors and Icons keep only notes with any one of these colors and
+	// any one of these icons.
+	Colors []string
+	Icons  []string
+}
+
+type QualityRuleRepository interface {
+	// GetByUserID returns the user's rules, or an empty rule set when none are configured.
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*entity.QualityRules, error)
+	Upsert(ctx context.Context, rules *entity.QualityRules) error
+}
+
+type ShareRepository interface {
+	// Apply grants and revokes shares in one transaction. Grants replace the
+	// role of existing shares; revokes remove every share of the given users
+	// on the given notes.
+	Apply(ctx context.Context, grants []entity.NoteShare, noteIDs, revokeUserIDs []uuid.UUID) (revoked int64, err error)
+	ListByNoteID(ctx context.Context, noteID uuid.UUID) ([]entity.NoteShare, error)
+	// GetRole returns the user's share role on the note, or "" when it is not shared with them.
+	GetRole(ctx context.Context, noteID, userID uuid.UUID) (string, error)
 }
 
 type PhotoRepository interface {
 	Create(ctx context.Context, photo *entity.Photo) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.Photo, error)
 	GetByNoteID(ctx context.Context, noteID uuid.UUID) ([]entity.Photo, error)
-	Delete(ctx context.Context, id uuid.UUID) error
+	// GetByNoteIDs returns the photos of all the notes, ordered by note and
+	// creation time.
+	GetByNoteIDs(ctx context.Context, noteIDs []uuid.UUID) ([]entity.Photo, error)
+	// SetExcludedFromShares hides the photo from, or shows it again to,
+	// users the note is shared with. It and Delete take the note owner's
+	// userID, which selects the partition the photo is in.
+	SetExcludedFromShares(ctx context.Context, userID, id uuid.UUID, excluded bool) error
+	Delete(ctx context.Context, userID, id uuid.UUID) error
 	DeleteByNoteID(ctx context.Context, noteID uuid.UUID) error
+
+	// Sync operations
+	// GetCreatedAfter and GetDeletedAfter page through added photos and
+	// tombstones in ascending (time, id) order, starting after the cursor.
+	GetCreatedAfter(ctx context.Context, userID uuid.UUID, after pagination.Cursor, limit int) ([]entity.Photo, error)
+	GetDeletedAfter(ctx context.Context, userID uuid.UUID, after pagination.Cursor, limit int) ([]entity.PhotoTombstone, error)
+	// GetByClientID looks up a photo on userID's notes by the ID the device
+	// uploaded it with.
+	GetByClientID(ctx context.Context, userID uuid.UUID, clientID string) (*entity.Photo, error)
+	GetByClientIDs(ctx context.Context, userID uuid.UUID, clientIDs []string) ([]entity.Photo, error)
+	// GetBySourceChecksum looks up the photo of a note of userID that was
+	// uploaded from the file with the given SHA-256.
+	GetBySourceChecksum(ctx context.Context, userID, noteID uuid.UUID, checksum string) (*entity.Photo, error)
+	GetDeletedByClientIDs(ctx context.Context, userID uuid.UUID, clientIDs []string) ([]entity.PhotoTombstone, error)
+}
+
+// AttachmentRepository stores a note's non-image media. Notes read through
+// NoteRepository already carry their attachments.
+type AttachmentRepository interface {
+	// Create and Delete also bump the note's updated_at, so sync clients
+	// pull the note again with its current attachments.
+	Create(ctx context.Context, attachment *entity.Attachment) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Attachment, error)
+	// GetByClientID looks up an attachment on userID's notes by the ID the
+	// device uploaded it with.
+	GetByClientID(ctx context.Context, userID uuid.UUID, clientID string) (*entity.Attachment, error)
+	Delete(ctx context.Context, id uuid.UUID) error
 }
 
 type DeviceRepository interface {
@@ -52,6 +218,119 @@ type DeviceRepository interface {
 	GetByUserAndDeviceID(ctx context.Context, userID uuid.UUID, deviceID string) (*entity.Device, error)
 	Update(ctx context.Context, device *entity.Device) error
 	Upsert(ctx context.Context, device *entity.Device) error
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]entity.Device, error)
+	// RecordAccess stores where and when the device last authenticated.
+	RecordAccess(ctx context.Context, id uuid.UUID, access entity.DeviceAccess) error
+	// SetPushToken stores the device's push token, taking it from any other
+	// device that holds it.
+	SetPushToken(ctx context.Context, device *entity.Device) error
+}
+
+// UserStatsRepository reads per-user counters that the database maintains
+// on every note and photo write.
+type UserStatsRepository interface {
+	Get(ctx context.Context, userID uuid.UUID) (*entity.UserStats, error)
+	// Reconcile fixes counters that no longer match a full recount and
+	// returns them.
+	Reconcile(ctx context.Context) ([]entity.StatsDrift, error)
+}
+
+type DeviceUsageRepository interface {
+	// Increment adds the given byte counts to the stored daily totals.
+	Increment(ctx context.Context, usage []entity.DeviceUsage) error
+	ListByDevice(ctx context.Context, deviceID uuid.UUID, from, to time.Time) ([]entity.DeviceUsage, error)
+	// ListAbove returns the devices whose combined traffic on day exceeds minBytes.
+	ListAbove(ctx context.Context, day time.Time, minBytes int64) ([]entity.DeviceUsage, error)
+}
+
+// ClientUsageRepository keeps the daily request counts per client version.
+type ClientUsageRepository interface {
+	// Increment adds the given counts to the stored daily totals.
+	Increment(ctx context.Context, usage []entity.ClientUsage) error
+	// List returns the daily totals of the days from from to to, inclusive.
+	List(ctx context.Context, from, to time.Time) ([]entity.ClientUsage, error)
+	DeleteBefore(ctx context.Context, day time.Time) (int64, error)
+}
+
+// DeprecationUsageRepository keeps the daily request counts per deprecated
+// endpoint or field and app version.
+type DeprecationUsageRepository interface {
+	// Increment adds the given counts to the stored daily totals.
+	Increment(ctx context.Context, usage []entity.DeprecationUsage) error
+	// List returns the daily totals of the days from from to to, inclusive.
+	List(ctx context.Context, from, to time.Time) ([]entity.DeprecationUsage, error)
+	DeleteBefore(ctx context.Context, day time.Time) (int64, error)
+}
+
+// KPIRepository keeps the daily KPI event counts and the reports computed
+// from them.
+type KPIRepository interface {
+	// IncrementEvents adds the counts to the stored totals of the day.
+	IncrementEvents(ctx context.Context, day time.Time, counts map[string]int64) error
+	// Events returns the event totals of the day.
+	Events(ctx context.Context, day time.Time) (map[string]int64, error)
+	// ActiveUsers counts the users whose devices used the API on the day.
+	ActiveUsers(ctx context.Context, day time.Time) (int64, error)
+	// SaveReport stores the report, replacing the one of the same day.
+	SaveReport(ctx context.Context, report entity.KPIReport) error
+	// ListReports returns the reports of the days from from to to,
+	// inclusive, oldest first.
+	ListReports(ctx context.Context, from, to time.Time) ([]entity.KPIReport, error)
+}
+
+// AuthEventRepository keeps the login and refresh history that anomaly
+// detection runs over.
+type AuthEventRepository interface {
+	Create(ctx context.Context, event *entity.AuthEvent) error
+	// ListSince returns the events at or after since, ordered by user and time.
+	ListSince(ctx context.Context, since time.Time) ([]entity.AuthEvent, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type SecurityAlertRepository interface {
+	// Create stores the alert unless one with the same user, kind and
+	// fingerprint exists, and reports whether it was stored.
+	Create(ctx context.Context, alert *entity.SecurityAlert) (bool, error)
+	// ListForAdmin returns, newest first, the alerts about members of the
+	// organizations adminID administers.
+	ListForAdmin(ctx context.Context, adminID uuid.UUID, params AlertListParams) ([]entity.SecurityAlert, *pagination.Info, error)
+}
+
+type AlertListParams struct {
+	Pagination pagination.Params
+	Kind       string
+}
+
+// AuditEventRepository keeps the audit log of each user's account.
+type AuditEventRepository interface {
+	Create(ctx context.Context, event *entity.AuditEvent) error
+	// ListByUser returns the user's events, newest first.
+	ListByUser(ctx context.Context, userID uuid.UUID, params AuditListParams) ([]entity.AuditEvent, *pagination.Info, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type AuditListParams struct {
+	Pagination pagination.Params
+	Action     string
+}
+
+// NoteDocumentRepository keeps the documents of collaborative notes.
+type NoteDocumentRepository interface {
+	// Create fails with ErrDocumentExists when the note already has one.
+	Create(ctx context.Context, doc *entity.NoteDocument) error
+	// Get returns the document with the updates since its snapshot.
+	Get(ctx context.Context, noteID uuid.UUID) (*entity.NoteDocument, error)
+	// Append calls fn with the document locked against other appends and
+	// stores the update fn returns, numbered after the last one. A nil
+	// update stores nothing.
+	Append(ctx context.Context, noteID uuid.UUID, fn func(*entity.NoteDocument) (*entity.DocumentUpdate, error)) (*entity.DocumentUpdate, error)
+	// Compact replaces the snapshot with one taken after update seq and
+	// drops the updates it contains, unless the stored snapshot is as new.
+	Compact(ctx context.Context, noteID uuid.UUID, snapshot crdt.Snapshot, seq int64) error
+	// ListCompactable returns the notes whose documents have at least
+	// minUpdates updates past their snapshot, the longest logs first.
+	ListCompactable(ctx context.Context, minUpdates, limit int) ([]uuid.UUID, error)
+	Delete(ctx context.Context, noteID uuid.UUID) error
 }
 
 type RefreshTokenRepository interface {
@@ -59,6 +338,139 @@ type RefreshTokenRepository interface {
 	GetByToken(ctx context.Context, token string) (*entity.RefreshToken, error)
 	RevokeByUserID(ctx context.Context, userID uuid.UUID) error
 	RevokeByDeviceID(ctx context.Context, deviceID uuid.UUID) error
+	// RevokeExcess revokes the user's active tokens on the platform except
+	// the newest keep, ending the oldest sessions first.
+	RevokeExcess(ctx context.Context, userID uuid.UUID, platform string, keep int) error
+	// RevokeOthers revokes the user's active tokens except keepID and returns
+	// how many it revoked. It returns ErrTokenInvalid, revoking nothing, if
+	// keepID is not an active token of the user.
+	RevokeOthers(ctx context.Context, userID, keepID uuid.UUID) (int, error)
 	Revoke(ctx context.Context, id uuid.UUID) error
-	DeleteExpired(ctx context.Context) error
+	// DeleteExpired removes expired and revoked tokens and returns how many
+	// it removed.
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// AccountDeletionRepository tracks account deletions from the request to the
+// purge, and keeps them afterwards as the audit trail.
+type AccountDeletionRepository interface {
+	// Create locks the account and records the deletion in one transaction:
+	// the user can no longer log in and their access and refresh tokens are
+	// revoked. It returns ErrUserNotFound if the account is already locked.
+	Create(ctx context.Context, deletion *entity.AccountDeletion) error
+	// ListPending returns up to limit deletions requested before the given
+	// time and not yet purged, oldest first.
+	ListPending(ctx context.Context, before time.Time, limit int) ([]entity.AccountDeletion, error)
+	// ObjectKeys returns the storage keys of every photo, thumbnail,
+	// attachment and offloaded note content of the user.
+	ObjectKeys(ctx context.Context, userID uuid.UUID) ([]string, error)
+	// Purge deletes the user, and with them their notes, photos, devices and
+	// tokens, and marks the deletion purged.
+	Purge(ctx context.Context, deletion *entity.AccountDeletion) error
+}
+
+// StorageOrphanRepository queues the storage objects left behind by
+// hard-deleted notes until they are removed from storage.
+type StorageOrphanRepository interface {
+	// List returns up to limit orphaned keys, oldest first.
+	List(ctx context.Context, limit int) ([]string, error)
+	// Remove drops keys whose objects were deleted from the queue.
+	Remove(ctx context.Context, keys []string) error
+}
+
+// NoteEmbeddingRepository stores the embeddings of notes of users who turned
+// semantic search on. Only vectors of the same model are compared.
+type NoteEmbeddingRepository interface {
+	// Available reports whether the note_embeddings table exists, which it
+	// only does where pgvector was installed when migrations ran.
+	Available(ctx context.Context) (bool, error)
+	// Pending returns up to limit live notes of opted-in users that have no
+	// embedding of model for their current version, least recently updated
+	// first. Notes without a title or content are left out.
+	Pending(ctx context.Context, model string, limit int) ([]entity.Note, error)
+	// Save inserts or replaces the embeddings of their notes.
+	Save(ctx context.Context, embeddings []entity.NoteEmbedding) error
+	// Search returns up to limit of the user's live notes closest to vector,
+	// with the cosine similarity as score.
+	Search(ctx context.Context, userID uuid.UUID, model string, vector []float32, limit int) ([]entity.SearchHit, error)
+	// Related is Search with the note's own embedding, leaving the note out.
+	// It returns nothing while the note has no embedding.
+	Related(ctx context.Context, userID, noteID uuid.UUID, model string, limit int) ([]entity.SearchHit, error)
+	// Prune deletes the embeddings of deleted notes and of users who turned
+	// semantic search off, and returns how many it deleted.
+	Prune(ctx context.Context) (int64, error)
+}
+
+type SyncConflictRepository interface {
+	// Save stores a pending conflict. A pending conflict of the same note
+	// and device is replaced, keeping its ID, which is set on conflict.
+	Save(ctx context.Context, conflict *entity.SyncConflict) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.SyncConflict, error)
+	// ListPending returns the user's pending conflicts that have not
+	// expired by now, oldest first.
+	ListPending(ctx context.Context, userID uuid.UUID, now time.Time) ([]entity.SyncConflict, error)
+	// Resolve stores the conflict's resolution. It returns
+	// ErrSyncConflictResolved if the conflict was already resolved.
+	Resolve(ctx context.Context, conflict *entity.SyncConflict) error
+	// DeleteExpired removes conflicts, pending or resolved, that expired
+	// before the given time.
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+type PasswordResetTokenRepository interface {
+	Create(ctx context.Context, token *entity.PasswordResetToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error)
+	// Consume marks the token and every other unused token of its user as
+	// used, so a link works once and a reset voids the links sent before it.
+	// It returns ErrResetTokenInvalid if the token was already used.
+	Consume(ctx context.Context, id uuid.UUID) error
+}
+
+// AuthProviderRepository links users to their social identity provider
+// accounts.
+type AuthProviderRepository interface {
+	// GetBySubject returns domain.ErrProviderNotLinked when no user is
+	// linked to the provider account.
+	GetBySubject(ctx context.Context, provider, subject string) (*entity.AuthProviderLink, error)
+	// Create links the provider account, leaving an existing link to it
+	// untouched.
+	Create(ctx context.Context, link *entity.AuthProviderLink) error
+}
+
+type OrganizationRepository interface {
+	GetBySlug(ctx context.Context, slug string) (*entity.Organization, error)
+	GetByDomain(ctx context.Context, domain string) (*entity.Organization, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]entity.Organization, error)
+	UpsertMember(ctx context.Context, membership *entity.OrgMembership) error
+	// UpdateSSO stores the organization's identity provider settings; the
+	// client secret is encrypted at rest.
+	UpdateSSO(ctx context.Context, org *entity.Organization) error
+	IsAdmin(ctx context.Context, orgID, userID uuid.UUID) (bool, error)
+	// IsAdminOver reports whether adminID is an admin of an organization memberID belongs to.
+	IsAdminOver(ctx context.Context, adminID, memberID uuid.UUID) (bool, error)
+}
+
+type TeamRepository interface {
+	// Create stores the team with its owner's membership.
+	Create(ctx context.Context, team *entity.Team, owner *entity.TeamMember) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Team, error)
+	// ListByUserID returns the teams the user belongs to or is invited to.
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]entity.UserTeam, error)
+	ListMembers(ctx context.Context, teamID uuid.UUID) ([]entity.TeamMember, error)
+	// GetMember returns domain.ErrTeamMemberNotFound when the user is
+	// neither a member nor invited.
+	GetMember(ctx context.Context, teamID, userID uuid.UUID) (*entity.TeamMember, error)
+	// AddMember returns domain.ErrAlreadyTeamMember when the user is a
+	// member or already invited.
+	AddMember(ctx context.Context, member *entity.TeamMember) error
+	// Accept accepts the user's pending invitation; it returns
+	// domain.ErrTeamMemberNotFound when there is none.
+	Accept(ctx context.Context, teamID, userID uuid.UUID, at time.Time) error
+	// UpdateRole returns domain.ErrTeamMemberNotFound when the user is
+	// neither a member nor invited.
+	UpdateRole(ctx context.Context, teamID, userID uuid.UUID, role string) error
+	RemoveMember(ctx context.Context, teamID, userID uuid.UUID) error
+	// GetRole returns the user's role in the team, or "" when they are not a
+	// member or have not accepted the invitation.
+	GetRole(ctx context.Context, teamID, userID uuid.UUID) (string, error)
 }
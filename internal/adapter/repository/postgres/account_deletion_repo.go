@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+type AccountDeletionRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewAccountDeletionRepo(pool *pgxpool.Pool) *AccountDeletionRepo {
+	return &AccountDeletionRepo{pool: pool}
+}
+
+func (r *AccountDeletionRepo) Create(ctx context.Context, deletion *entity.AccountDeletion) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Bumping the token version rejects the access tokens already issued;
+	// deleted_at keeps the user from logging in again.
+	result, err := tx.Exec(ctx, `
+		UPDATE users
+		SET deleted_at = $2, token_version = token_version + 1, updated_at = $2
+		WHERE id = $1 AND deleted_at IS NULL
+	`, deletion.UserID, deletion.RequestedAt)
+	if err != nil {
+		return fmt.Errorf("locking user: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE refresh_tokens
+		SET revoked_at = $2
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`, deletion.UserID, deletion.RequestedAt); err != nil {
+		return fmt.Errorf("revoking refresh tokens: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO account_deletions (id, user_id, requested_at)
+		VALUES ($1, $2, $3)
+	`, deletion.ID, deletion.UserID, deletion.RequestedAt); err != nil {
+		return fmt.Errorf("inserting account deletion: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *AccountDeletionRepo) ListPending(ctx context.Context, before time.Time, limit int) ([]entity.AccountDeletion, error) {
+	query := `
+		SELECT id, user_id, requested_at
+		FROM account_deletions
+		WHERE purged_at IS NULL AND requested_at < $1
+		ORDER BY requested_at
+		LIMIT $2
+	`
+	rows, err := r.pool.Query(ctx, query, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying pending account deletions: %w", err)
+	}
+	defer rows.Close()
+
+	var deletions []entity.AccountDeletion
+	for rows.Next() {
+		var d entity.AccountDeletion
+		if err := rows.Scan(&d.ID, &d.UserID, &d.RequestedAt); err != nil {
+			return nil, fmt.Errorf("scanning account deletion: %w", err)
+		}
+		deletions = append(deletions, d)
+	}
+	return deletions, rows.Err()
+}
+
+func (r *AccountDeletionRepo) ObjectKeys(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	query := `
+		SELECT key FROM photos WHERE user_id = $1
+		UNION ALL
+		SELECT t.value->>'key' FROM photos p, jsonb_each(p.thumbnails) t WHERE p.user_id = $1
+		UNION ALL
+		SELECT key FROM attachments WHERE user_id = $1
+		UNION ALL
+		SELECT content_key FROM notes WHERE user_id = $1 AND content_key IS NOT NULL
+		-- A revision and its note share the object while the content
+		-- is the same, so the keys are deduplicated.
+		UNION
+		SELECT content_key FROM note_revisions WHERE user_id = $1 AND content_key IS NOT NULL
+	`
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("querying object keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("scanning object key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *AccountDeletionRepo) Purge(ctx context.Context, deletion *entity.AccountDeletion) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Everything the user owns references users(id) ON DELETE CASCADE.
+	if _, err := tx.Exec(ctx, `DELETE FROM users WHERE id = $1`, deletion.UserID); err != nil {
+		return fmt.Errorf("deleting user: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE account_deletions
+		SET purged_at = $2, objects_deleted = $3
+		WHERE id = $1
+	`, deletion.ID, deletion.PurgedAt, deletion.ObjectsDeleted); err != nil {
+		return fmt.Errorf("marking account deletion purged: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository/postgres"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+func TestIntegrationAccountDeletionRepo(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewAccountDeletionRepo(db.Pool)
+	userRepo := postgres.NewUserRepo(db.Pool)
+	noteRepo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
+	photoRepo := postgres.NewPhotoRepo(db.Pool, nil)
+	attachmentRepo := postgres.NewAttachmentRepo(db.Pool)
+	tokenRepo := postgres.NewRefreshTokenRepo(db.Pool, nil)
+	ctx := context.Background()
+
+	db.Truncate(t, "account_deletions", "users")
+	user, device := createTestUserAndDevice(t, db)
+	token := entity.NewRefreshToken(user.ID, device.ID, "refresh-token", time.Now().Add(time.Hour))
+	require.NoError(t, tokenRepo.Create(ctx, token))
+
+	n := entity.NewNote(user.ID, "Plot 4", "", nil, "")
+	require.NoError(t, noteRepo.Create(ctx, n))
+	photo := entity.NewPhoto(n.ID, n.UserID, "http://storage/photo.jpg", "notes/photo.jpg", "image/jpeg", 1024, 800, 600)
+	photo.Thumbnails = map[string]entity.PhotoThumbnail{
+		entity.ThumbnailSmall: {Key: "notes/photo_small.jpg", URL: "http://storage/photo_small.jpg", Width: 160, Height: 120},
+	}
+	require.NoError(t, photoRepo.Create(ctx, photo))
+	require.NoError(t, attachmentRepo.Create(ctx, entity.NewAttachment(n.ID, entity.AttachmentKindAudio, "http://storage/memo.m4a", "notes/memo.m4a", "audio/m4a", 2048, time.Second)))
+
+	deletion := entity.NewAccountDeletion(user.ID)
+
+	t.Run("locks the account and revokes its tokens", func(t *testing.T) {
+		require.NoError(t, repo.Create(ctx, deletion))
+
+		_, err := userRepo.GetByEmail(ctx, user.Email)
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+		version, err := userRepo.GetTokenVersion(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, user.TokenVersion+1, version)
+
+		stored, err := tokenRepo.GetByToken(ctx, "refresh-token")
+		require.NoError(t, err)
+		assert.True(t, stored.IsRevoked())
+	})
+
+	t.Run("rejects a second request", func(t *testing.T) {
+		err := repo.Create(ctx, entity.NewAccountDeletion(user.ID))
+
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("lists deletions once they are due", func(t *testing.T) {
+		pending, err := repo.ListPending(ctx, deletion.RequestedAt, 10)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+
+		pending, err = repo.ListPending(ctx, time.Now().Add(time.Minute), 10)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, user.ID, pending[0].UserID)
+	})
+
+	t.Run("returns every stored file", func(t *testing.T) {
+		keys, err := repo.ObjectKeys(ctx, user.ID)
+
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"notes/photo.jpg", "notes/photo_small.jpg", "notes/memo.m4a"}, keys)
+	})
+
+	t.Run("purges the account and keeps the record", func(t *testing.T) {
+		deletion.MarkPurged(3)
+		require.NoError(t, repo.Purge(ctx, deletion))
+
+		var users, notes, photos int
+		require.NoError(t, db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM users WHERE id = $1`, user.ID).Scan(&users))
+		require.NoError(t, db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM notes WHERE user_id = $1`, user.ID).Scan(&notes))
+		require.NoError(t, db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM photos WHERE user_id = $1`, user.ID).Scan(&photos))
+		assert.Zero(t, users)
+		assert.Zero(t, notes)
+		assert.Zero(t, photos)
+
+		var objects int
+		require.NoError(t, db.Pool.QueryRow(ctx, `SELECT objects_deleted FROM account_deletions WHERE id = $1 AND purged_at IS NOT NULL`, deletion.ID).Scan(&objects))
+		assert.Equal(t, 3, objects)
+
+		pending, err := repo.ListPending(ctx, time.Now().Add(time.Minute), 10)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+	})
+}
@@ -0,0 +1,179 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+type AttachmentRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewAttachmentRepo(pool *pgxpool.Pool) *AttachmentRepo {
+	return &AttachmentRepo{pool: pool}
+}
+
+// touchNoteQuery bumps the note's updated_at so sync clients pull it again
+// with its current attachments.
+const touchNoteQuery = `UPDATE notes SET updated_at = NOW() WHERE id = $1`
+
+func (r *AttachmentRepo) Create(ctx context.Context, attachment *entity.Attachment) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// user_id is the note owner's, as for photos; it is NULL when the note is
+	// gone and the insert fails on the NOT NULL constraint.
+	query := `
+		INSERT INTO attachments (id, note_id, user_id, kind, url, key, mime_type, size, duration_ms, checksum, client_id, created_at)
+		VALUES ($1, $2, (SELECT user_id FROM notes WHERE id = $2), $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+	_, err = tx.Exec(ctx, query,
+		attachment.ID, attachment.NoteID, attachment.Kind, attachment.URL, attachment.Key,
+		attachment.MimeType, attachment.Size, attachment.Duration.Milliseconds(),
+		nullableString(attachment.Checksum), nullableString(attachment.ClientID), attachment.CreatedAt,
+	)
+	if err != nil {
+		if hasCode(err, codeNotNullViolation) {
+			return domain.ErrNoteNotFound
+		}
+		if hasCode(err, codeUniqueViolation) {
+			return domain.ErrAttachmentAlreadyExists
+		}
+		return fmt.Errorf("inserting attachment: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, touchNoteQuery, attachment.NoteID); err != nil {
+		return fmt.Errorf("touching note: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *AttachmentRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.Attachment, error) {
+	query := `
+		SELECT ` + attachmentColumns + `
+		FROM attachments
+		WHERE id = $1
+	`
+	attachment, err := scanAttachmentRow(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrAttachmentNotFound
+		}
+		return nil, fmt.Errorf("querying attachment: %w", err)
+	}
+	return attachment, nil
+}
+
+func (r *AttachmentRepo) GetByClientID(ctx context.Context, userID uuid.UUID, clientID string) (*entity.Attachment, error) {
+	query := `
+		SELECT ` + attachmentColumns + `
+		FROM attachments
+		WHERE user_id = $1 AND client_id = $2
+	`
+	attachment, err := scanAttachmentRow(r.pool.QueryRow(ctx, query, userID, clientID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrAttachmentNotFound
+		}
+		return nil, fmt.Errorf("querying attachment by client id: %w", err)
+	}
+	return attachment, nil
+}
+
+func (r *AttachmentRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var noteID uuid.UUID
+	err = tx.QueryRow(ctx, `DELETE FROM attachments WHERE id = $1 RETURNING note_id`, id).Scan(&noteID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrAttachmentNotFound
+		}
+		return fmt.Errorf("deleting attachment: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, touchNoteQuery, noteID); err != nil {
+		return fmt.Errorf("touching note: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+const attachmentColumns = `id, note_id, kind, url, key, mime_type, size, duration_ms, checksum, client_id, created_at`
+
+// scanAttachmentRow scans a row selected with attachmentColumns.
+func scanAttachmentRow(row pgx.Row) (*entity.Attachment, error) {
+	var attachment entity.Attachment
+	var durationMs int64
+	var checksum, clientID *string
+
+	if err := row.Scan(
+		&attachment.ID, &attachment.NoteID, &attachment.Kind, &attachment.URL, &attachment.Key,
+		&attachment.MimeType, &attachment.Size, &durationMs, &checksum, &clientID, &attachment.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	attachment.Duration = time.Duration(durationMs) * time.Millisecond
+	if checksum != nil {
+		attachment.Checksum = *checksum
+	}
+	if clientID != nil {
+		attachment.ClientID = *clientID
+	}
+	return &attachment, nil
+}
+
+// attachmentRow is the JSON shape of an attachment aggregated into a note row
+// by noteColumns.
+type attachmentRow struct {
+	ID         uuid.UUID `json:"id"`
+	Kind       string    `json:"kind"`
+	URL        string    `json:"url"`
+	Key        string    `json:"key"`
+	MimeType   string    `json:"mime_type"`
+	Size       int64     `json:"size"`
+	DurationMs int64     `json:"duration_ms"`
+	Checksum   string    `json:"checksum"`
+	ClientID   string    `json:"client_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (a attachmentRow) toEntity(noteID uuid.UUID) entity.Attachment {
+	return entity.Attachment{
+		ID:        a.ID,
+		NoteID:    noteID,
+		Kind:      a.Kind,
+		URL:       a.URL,
+		Key:       a.Key,
+		MimeType:  a.MimeType,
+		Size:      a.Size,
+		Duration:  time.Duration(a.DurationMs) * time.Millisecond,
+		Checksum:  a.Checksum,
+		ClientID:  a.ClientID,
+		CreatedAt: a.CreatedAt,
+	}
+}
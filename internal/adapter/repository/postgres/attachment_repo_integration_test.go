@@ -0,0 +1,80 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository/postgres"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+func TestIntegrationAttachmentRepo(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewAttachmentRepo(db.Pool)
+	noteRepo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
+	ctx := context.Background()
+
+	newMemo := func(noteID uuid.UUID) *entity.Attachment {
+		return entity.NewAttachment(noteID, entity.AttachmentKindAudio, "http://storage/memo.m4a", "notes/123/memo.m4a", "audio/m4a", 2048, 42*time.Second)
+	}
+
+	t.Run("creates an attachment and returns it with the note", func(t *testing.T) {
+		db.Truncate(t, "attachments", "notes", "users")
+		_, note := createTestUserAndNote(t, db)
+
+		memo := newMemo(note.ID)
+		memo.ClientID = "memo-1"
+		require.NoError(t, repo.Create(ctx, memo))
+
+		got, err := noteRepo.GetByID(ctx, note.ID)
+		require.NoError(t, err)
+		require.Len(t, got.Attachments, 1)
+		assert.Equal(t, memo.ID, got.Attachments[0].ID)
+		assert.Equal(t, 42*time.Second, got.Attachments[0].Duration)
+		assert.True(t, got.UpdatedAt.After(note.UpdatedAt))
+	})
+
+	t.Run("finds an attachment by client id", func(t *testing.T) {
+		db.Truncate(t, "attachments", "notes", "users")
+		user, note := createTestUserAndNote(t, db)
+
+		memo := newMemo(note.ID)
+		memo.ClientID = "memo-1"
+		require.NoError(t, repo.Create(ctx, memo))
+
+		got, err := repo.GetByClientID(ctx, user.ID, "memo-1")
+		require.NoError(t, err)
+		assert.Equal(t, memo.ID, got.ID)
+
+		duplicate := newMemo(note.ID)
+		duplicate.ClientID = "memo-1"
+		assert.ErrorIs(t, repo.Create(ctx, duplicate), domain.ErrAttachmentAlreadyExists)
+	})
+
+	t.Run("returns not found for a missing note", func(t *testing.T) {
+		db.Truncate(t, "attachments", "notes", "users")
+
+		assert.ErrorIs(t, repo.Create(ctx, newMemo(uuid.New())), domain.ErrNoteNotFound)
+	})
+
+	t.Run("deletes an attachment", func(t *testing.T) {
+		db.Truncate(t, "attachments", "notes", "users")
+		_, note := createTestUserAndNote(t, db)
+
+		memo := newMemo(note.ID)
+		require.NoError(t, repo.Create(ctx, memo))
+		require.NoError(t, repo.Delete(ctx, memo.ID))
+
+		_, err := repo.GetByID(ctx, memo.ID)
+		assert.ErrorIs(t, err, domain.ErrAttachmentNotFound)
+		assert.ErrorIs(t, repo.Delete(ctx, memo.ID), domain.ErrAttachmentNotFound)
+	})
+}
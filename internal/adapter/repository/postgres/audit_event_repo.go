@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+)
+
+type AuditEventRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuditEventRepo(pool *pgxpool.Pool) *AuditEventRepo {
+	return &AuditEventRepo{pool: pool}
+}
+
+func (r *AuditEventRepo) Create(ctx context.Context, event *entity.AuditEvent) error {
+	query := `
+		INSERT INTO audit_events (id, user_id, device_id, action, target, ip, user_agent, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := r.pool.Exec(ctx, query,
+		event.ID, event.UserID, event.DeviceID, event.Action, event.Target, event.IP, event.UserAgent, event.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("inserting audit event: %w", err)
+	}
+	return nil
+}
+
+func (r *AuditEventRepo) ListByUser(ctx context.Context, userID uuid.UUID, params repository.AuditListParams) ([]entity.AuditEvent, *pagination.Info, error) {
+	from := `
+		FROM audit_events
+		WHERE user_id = $1 AND ($2::text = '' OR action = $2)
+	`
+	args := []any{userID, params.Action}
+
+	var total int
+	if err := r.pool.QueryRow(ctx, "SELECT COUNT(*) "+from, args...).Scan(&total); err != nil {
+		return nil, nil, fmt.Errorf("counting audit events: %w", err)
+	}
+
+	query := `
+		SELECT id, user_id, device_id, action, target, ip, user_agent, created_at
+	` + from + `
+		ORDER BY created_at DESC, id
+		LIMIT $3 OFFSET $4
+	`
+	rows, err := r.pool.Query(ctx, query, append(args, params.Pagination.Limit(), params.Pagination.Offset())...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("querying audit events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []entity.AuditEvent
+	for rows.Next() {
+		var e entity.AuditEvent
+		if err := rows.Scan(&e.ID, &e.UserID, &e.DeviceID, &e.Action, &e.Target, &e.IP, &e.UserAgent, &e.CreatedAt); err != nil {
+			return nil, nil, fmt.Errorf("scanning audit event: %w", err)
+		}
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("iterating audit events: %w", err)
+	}
+
+	return events, pagination.NewInfo(params.Pagination.Page, params.Pagination.PerPage, total), nil
+}
+
+func (r *AuditEventRepo) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM audit_events WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("deleting audit events: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
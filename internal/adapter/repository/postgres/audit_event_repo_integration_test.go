@@ -0,0 +1,59 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository/postgres"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+)
+
+func TestIntegrationAuditEventRepo(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewAuditEventRepo(db.Pool)
+	ctx := context.Background()
+
+	db.Truncate(t, "audit_events", "devices", "users")
+	user, device := createTestUserAndDevice(t, db)
+	other := createTestUser(t, db)
+
+	now := time.Now().UTC()
+	old := entity.NewAuditEvent(user.ID, entity.AuditLogin, "")
+	old.DeviceID = &device.ID
+	old.IP = "203.0.113.7"
+	old.CreatedAt = now.Add(-48 * time.Hour)
+	deleted := entity.NewAuditEvent(user.ID, entity.AuditNoteDeleted, "note-1")
+	deleted.UserAgent = "FieldNotes/2.4.0"
+	for _, e := range []*entity.AuditEvent{old, deleted, entity.NewAuditEvent(other.ID, entity.AuditLogin, "")} {
+		require.NoError(t, repo.Create(ctx, e))
+	}
+
+	events, info, err := repo.ListByUser(ctx, user.ID, repository.AuditListParams{Pagination: pagination.NewParams(1, 20)})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, 2, info.TotalItems)
+	assert.Equal(t, deleted.ID, events[0].ID)
+	assert.Equal(t, "note-1", events[0].Target)
+	assert.Equal(t, "FieldNotes/2.4.0", events[0].UserAgent)
+	assert.Nil(t, events[0].DeviceID)
+	require.NotNil(t, events[1].DeviceID)
+	assert.Equal(t, device.ID, *events[1].DeviceID)
+	assert.Equal(t, "203.0.113.7", events[1].IP)
+
+	events, _, err = repo.ListByUser(ctx, user.ID, repository.AuditListParams{Pagination: pagination.NewParams(1, 20), Action: entity.AuditLogin})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, old.ID, events[0].ID)
+
+	n, err := repo.DeleteBefore(ctx, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+}
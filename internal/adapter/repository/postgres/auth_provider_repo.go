@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+type AuthProviderRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuthProviderRepo(pool *pgxpool.Pool) *AuthProviderRepo {
+	return &AuthProviderRepo{pool: pool}
+}
+
+func (r *AuthProviderRepo) GetBySubject(ctx context.Context, provider, subject string) (*entity.AuthProviderLink, error) {
+	query := `
+		SELECT id, user_id, provider, subject, email, created_at
+		FROM auth_providers
+		WHERE provider = $1 AND subject = $2
+	`
+	var link entity.AuthProviderLink
+	var email *string
+	err := r.pool.QueryRow(ctx, query, provider, subject).Scan(
+		&link.ID, &link.UserID, &link.Provider, &link.Subject, &email, &link.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrProviderNotLinked
+		}
+		return nil, fmt.Errorf("querying auth provider: %w", err)
+	}
+	if email != nil {
+		link.Email = *email
+	}
+	return &link, nil
+}
+
+func (r *AuthProviderRepo) Create(ctx context.Context, link *entity.AuthProviderLink) error {
+	query := `
+		INSERT INTO auth_providers (id, user_id, provider, subject, email, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (provider, subject) DO NOTHING
+	`
+	_, err := r.pool.Exec(ctx, query,
+		link.ID, link.UserID, link.Provider, link.Subject, nullableString(link.Email), link.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("creating auth provider link: %w", err)
+	}
+	return nil
+}
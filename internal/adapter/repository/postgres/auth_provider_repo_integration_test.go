@@ -0,0 +1,47 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository/postgres"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+func TestIntegrationAuthProviderRepo(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewAuthProviderRepo(db.Pool)
+	ctx := context.Background()
+
+	db.Truncate(t, "auth_providers", "users")
+	user := createTestUser(t, db)
+
+	t.Run("returns not linked for an unknown subject", func(t *testing.T) {
+		_, err := repo.GetBySubject(ctx, entity.AuthProviderGoogle, "missing")
+		assert.ErrorIs(t, err, domain.ErrProviderNotLinked)
+	})
+
+	t.Run("links the subject once", func(t *testing.T) {
+		require.NoError(t, repo.Create(ctx, entity.NewAuthProviderLink(user.ID, entity.AuthProviderGoogle, "google-1", user.Email)))
+
+		other := entity.NewUser("other@example.com", "", "Other User")
+		require.NoError(t, postgres.NewUserRepo(db.Pool).Create(ctx, other))
+		require.NoError(t, repo.Create(ctx, entity.NewAuthProviderLink(other.ID, entity.AuthProviderGoogle, "google-1", other.Email)))
+
+		link, err := repo.GetBySubject(ctx, entity.AuthProviderGoogle, "google-1")
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, link.UserID)
+		assert.Equal(t, user.Email, link.Email)
+	})
+
+	t.Run("keeps subjects of different providers apart", func(t *testing.T) {
+		_, err := repo.GetBySubject(ctx, entity.AuthProviderApple, "google-1")
+		assert.ErrorIs(t, err, domain.ErrProviderNotLinked)
+	})
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+type ClientUsageRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewClientUsageRepo(pool *pgxpool.Pool) *ClientUsageRepo {
+	return &ClientUsageRepo{pool: pool}
+}
+
+func (r *ClientUsageRepo) Increment(ctx context.Context, usage []entity.ClientUsage) error {
+	if len(usage) == 0 {
+		return nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO client_usage (day, platform, app_version, os, requests, devices)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (day, platform, app_version, os)
+		DO UPDATE SET
+			requests = client_usage.requests + EXCLUDED.requests,
+			devices = client_usage.devices + EXCLUDED.devices
+	`
+	for _, u := range usage {
+		if _, err := tx.Exec(ctx, query, entity.UsageDay(u.Day), u.Platform, u.AppVersion, u.OS, u.Requests, u.Devices); err != nil {
+			return fmt.Errorf("incrementing client usage: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ClientUsageRepo) List(ctx context.Context, from, to time.Time) ([]entity.ClientUsage, error) {
+	query := `
+		SELECT day, platform, app_version, os, requests, devices
+		FROM client_usage
+		WHERE day >= $1 AND day <= $2
+		ORDER BY day, platform, app_version, os
+	`
+	rows, err := r.pool.Query(ctx, query, entity.UsageDay(from), entity.UsageDay(to))
+	if err != nil {
+		return nil, fmt.Errorf("querying client usage: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []entity.ClientUsage
+	for rows.Next() {
+		var u entity.ClientUsage
+		if err := rows.Scan(&u.Day, &u.Platform, &u.AppVersion, &u.OS, &u.Requests, &u.Devices); err != nil {
+			return nil, fmt.Errorf("scanning client usage: %w", err)
+		}
+		usage = append(usage, u)
+	}
+	return usage, rows.Err()
+}
+
+func (r *ClientUsageRepo) DeleteBefore(ctx context.Context, day time.Time) (int64, error) {
+	result, err := r.pool.Exec(ctx, `DELETE FROM client_usage WHERE day < $1`, entity.UsageDay(day))
+	if err != nil {
+		return 0, fmt.Errorf("deleting client usage: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+type DeprecationUsageRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewDeprecationUsageRepo(pool *pgxpool.Pool) *DeprecationUsageRepo {
+	return &DeprecationUsageRepo{pool: pool}
+}
+
+func (r *DeprecationUsageRepo) Increment(ctx context.Context, usage []entity.DeprecationUsage) error {
+	if len(usage) == 0 {
+		return nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO deprecation_usage (day, name, platform, app_version, requests)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (day, name, platform, app_version)
+		DO UPDATE SET requests = deprecation_usage.requests + EXCLUDED.requests
+	`
+	for _, u := range usage {
+		if _, err := tx.Exec(ctx, query, entity.UsageDay(u.Day), u.Name, u.Platform, u.AppVersion, u.Requests); err != nil {
+			return fmt.Errorf("incrementing deprecation usage: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DeprecationUsageRepo) List(ctx context.Context, from, to time.Time) ([]entity.DeprecationUsage, error) {
+	query := `
+		SELECT day, name, platform, app_version, requests
+		FROM deprecation_usage
+		WHERE day >= $1 AND day <= $2
+		ORDER BY day, name, platform, app_version
+	`
+	rows, err := r.pool.Query(ctx, query, entity.UsageDay(from), entity.UsageDay(to))
+	if err != nil {
+		return nil, fmt.Errorf("querying deprecation usage: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []entity.DeprecationUsage
+	for rows.Next() {
+		var u entity.DeprecationUsage
+		if err := rows.Scan(&u.Day, &u.Name, &u.Platform, &u.AppVersion, &u.Requests); err != nil {
+			return nil, fmt.Errorf("scanning deprecation usage: %w", err)
+		}
+		usage = append(usage, u)
+	}
+	return usage, rows.Err()
+}
+
+func (r *DeprecationUsageRepo) DeleteBefore(ctx context.Context, day time.Time) (int64, error) {
+	result, err := r.pool.Exec(ctx, `DELETE FROM deprecation_usage WHERE day < $1`, entity.UsageDay(day))
+	if err != nil {
+		return 0, fmt.Errorf("deleting deprecation usage: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -31,6 +32,9 @@ func (r *DeviceRepo) Create(ctx context.Context, device *entity.Device) error {
 		device.Name, device.SyncCursor, device.CreatedAt, device.UpdatedAt,
 	)
 	if err != nil {
+		if hasCode(err, codeUniqueViolation) {
+			return domain.ErrDeviceAlreadyExists
+		}
 		return fmt.Errorf("inserting device: %w", err)
 	}
 	return nil
@@ -38,52 +42,59 @@ func (r *DeviceRepo) Create(ctx context.Context, device *entity.Device) error {
 
 func (r *DeviceRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.Device, error) {
 	query := `
-		SELECT id, user_id, device_id, platform, name, sync_cursor, created_at, updated_at
+		SELECT ` + deviceColumns + `
 		FROM devices
 		WHERE id = $1
 	`
-	var device entity.Device
-	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&device.ID, &device.UserID, &device.DeviceID, &device.Platform,
-		&device.Name, &device.SyncCursor, &device.CreatedAt, &device.UpdatedAt,
-	)
+	device, err := scanDeviceRow(r.pool.QueryRow(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrDeviceNotFound
 		}
 		return nil, fmt.Errorf("querying device: %w", err)
 	}
-	return &device, nil
+
+	if device.Cursors, err = r.getCursors(ctx, device.ID); err != nil {
+		return nil, err
+	}
+	return device, nil
 }
 
+const deviceByUserQuery = `SELECT ` + deviceColumns + ` FROM devices WHERE user_id = $1 AND device_id = $2`
+
 func (r *DeviceRepo) GetByUserAndDeviceID(ctx context.Context, userID uuid.UUID, deviceID string) (*entity.Device, error) {
-	query := `
-		SELECT id, user_id, device_id, platform, name, sync_cursor, created_at, updated_at
-		FROM devices
-		WHERE user_id = $1 AND device_id = $2
-	`
-	var device entity.Device
-	err := r.pool.QueryRow(ctx, query, userID, deviceID).Scan(
-		&device.ID, &device.UserID, &device.DeviceID, &device.Platform,
-		&device.Name, &device.SyncCursor, &device.CreatedAt, &device.UpdatedAt,
-	)
+	device, err := scanDeviceRow(r.pool.QueryRow(ctx, deviceByUserQuery, userID, deviceID))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrDeviceNotFound
 		}
 		return nil, fmt.Errorf("querying device: %w", err)
 	}
-	return &device, nil
+
+	if device.Cursors, err = r.getCursors(ctx, device.ID); err != nil {
+		return nil, err
+	}
+	return device, nil
 }
 
 func (r *DeviceRepo) Update(ctx context.Context, device *entity.Device) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
 		UPDATE devices
-		SET platform = $2, name = $3, sync_cursor = $4, updated_at = $5
+		SET platform = $2, name = $3, sync_cursor = $4,
+			scope_notes_since = $5, scope_exclude_photos = $6,
+			active_author = $7, active_author_since = $8, updated_at = $9
 		WHERE id = $1
 	`
-	result, err := r.pool.Exec(ctx, query,
-		device.ID, device.Platform, device.Name, device.SyncCursor, device.UpdatedAt,
+	result, err := tx.Exec(ctx, query,
+		device.ID, device.Platform, device.Name, device.SyncCursor,
+		device.Scope.NotesSince, device.Scope.ExcludePhotos,
+		nullableString(device.ActiveAuthor), device.ActiveAuthorSince, device.UpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("updating device: %w", err)
@@ -91,9 +102,170 @@ func (r *DeviceRepo) Update(ctx context.Context, device *entity.Device) error {
 	if result.RowsAffected() == 0 {
 		return domain.ErrDeviceNotFound
 	}
+
+	cursorQuery := `
+		INSERT INTO device_cursors (device_id, stream, cursor, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (device_id, stream)
+		DO UPDATE SET cursor = EXCLUDED.cursor, updated_at = EXCLUDED.updated_at
+	`
+	for stream, cursor := range device.Cursors {
+		if _, err := tx.Exec(ctx, cursorQuery, device.ID, stream, cursor, device.UpdatedAt); err != nil {
+			return fmt.Errorf("upserting device cursor: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+const deviceColumns = `id, user_id, device_id, platform, name, sync_cursor,
+			   scope_notes_since, scope_exclude_photos, active_author, active_author_since,
+			   push_token, push_token_updated_at, last_ip, last_city, last_country, last_access_at, created_at, updated_at`
+
+// scanDeviceRow scans a row selected with deviceColumns. Cursors are loaded separately.
+func scanDeviceRow(row pgx.Row) (*entity.Device, error) {
+	var device entity.Device
+	var author, pushToken, ip, city, country *string
+	var accessAt *time.Time
+	if err := row.Scan(
+		&device.ID, &device.UserID, &device.DeviceID, &device.Platform,
+		&device.Name, &device.SyncCursor,
+		&device.Scope.NotesSince, &device.Scope.ExcludePhotos, &author, &device.ActiveAuthorSince,
+		&pushToken, &device.PushTokenUpdatedAt, &ip, &city, &country, &accessAt,
+		&device.CreatedAt, &device.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if author != nil {
+		device.ActiveAuthor = *author
+	}
+	if pushToken != nil {
+		device.PushToken = *pushToken
+	}
+	if accessAt != nil {
+		device.LastAccess = &entity.DeviceAccess{At: *accessAt}
+		if ip != nil {
+			device.LastAccess.IP = *ip
+		}
+		if city != nil {
+			device.LastAccess.City = *city
+		}
+		if country != nil {
+			device.LastAccess.Country = *country
+		}
+	}
+	return &device, nil
+}
+
+// ListByUserID returns the user's devices, most recently used first.
+func (r *DeviceRepo) ListByUserID(ctx context.Context, userID uuid.UUID) ([]entity.Device, error) {
+	query := `
+		SELECT ` + deviceColumns + `
+		FROM devices
+		WHERE user_id = $1
+		ORDER BY COALESCE(last_access_at, updated_at) DESC
+	`
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("querying devices: %w", err)
+	}
+	defer rows.Close()
+
+	var devices []entity.Device
+	for rows.Next() {
+		device, err := scanDeviceRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning device: %w", err)
+		}
+		devices = append(devices, *device)
+	}
+	return devices, rows.Err()
+}
+
+func (r *DeviceRepo) RecordAccess(ctx context.Context, id uuid.UUID, access entity.DeviceAccess) error {
+	query := `
+		UPDATE devices
+		SET last_ip = $2, last_city = $3, last_country = $4, last_access_at = $5
+		WHERE id = $1
+	`
+	result, err := r.pool.Exec(ctx, query, id,
+		nullableString(access.IP), nullableString(access.City), nullableString(access.Country), access.At,
+	)
+	if err != nil {
+		return fmt.Errorf("recording device access: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrDeviceNotFound
+	}
+	return nil
+}
+
+// SetPushToken stores the device's push token. A token identifies one app
+// install, so it is first taken from any other device row that still holds
+// it, as happens when an app is reinstalled and registers a new device ID.
+func (r *DeviceRepo) SetPushToken(ctx context.Context, device *entity.Device) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if device.PushToken != "" {
+		release := `
+			UPDATE devices SET push_token = NULL, push_token_updated_at = NULL
+			WHERE push_token = $1 AND id <> $2
+		`
+		if _, err := tx.Exec(ctx, release, device.PushToken, device.ID); err != nil {
+			return fmt.Errorf("releasing push token: %w", err)
+		}
+	}
+
+	query := `
+		UPDATE devices
+		SET push_token = $2, push_token_updated_at = $3, updated_at = $4
+		WHERE id = $1
+	`
+	result, err := tx.Exec(ctx, query,
+		device.ID, nullableString(device.PushToken), device.PushTokenUpdatedAt, device.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("updating push token: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrDeviceNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
 	return nil
 }
 
+func (r *DeviceRepo) getCursors(ctx context.Context, deviceID uuid.UUID) (map[string]time.Time, error) {
+	query := `SELECT stream, cursor FROM device_cursors WHERE device_id = $1`
+	rows, err := r.pool.Query(ctx, query, deviceID)
+	if err != nil {
+		return nil, fmt.Errorf("querying device cursors: %w", err)
+	}
+	defer rows.Close()
+
+	cursors := make(map[string]time.Time)
+	for rows.Next() {
+		var stream string
+		var cursor time.Time
+		if err := rows.Scan(&stream, &cursor); err != nil {
+			return nil, fmt.Errorf("scanning device cursor: %w", err)
+		}
+		cursors[stream] = cursor
+	}
+
+	return cursors, rows.Err()
+}
+
 func (r *DeviceRepo) Upsert(ctx context.Context, device *entity.Device) error {
 	query := `
 		INSERT INTO devices (id, user_id, device_id, platform, name, sync_cursor, created_at, updated_at)
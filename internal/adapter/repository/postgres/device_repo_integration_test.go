@@ -43,7 +43,7 @@ func TestIntegrationDeviceRepo_Create(t *testing.T) {
 		device2 := entity.NewDevice(user.ID, "same-device", "ios", "Device 2")
 		err = repo.Create(ctx, device2)
 
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, domain.ErrDeviceAlreadyExists)
 	})
 }
 
@@ -140,6 +140,48 @@ func TestIntegrationDeviceRepo_Update(t *testing.T) {
 		require.NoError(t, err)
 		assert.WithinDuration(t, newCursor, found.SyncCursor, time.Second)
 	})
+
+	t.Run("persists named cursors independently", func(t *testing.T) {
+		db.Truncate(t, "devices", "users")
+		user := createTestUser(t, db)
+
+		device := entity.NewDevice(user.ID, "device-123", "ios", "iPhone 15")
+		err := repo.Create(ctx, device)
+		require.NoError(t, err)
+
+		notesCursor := time.Now()
+		photosCursor := time.Now().Add(-1 * time.Hour)
+		device.UpdateCursor(entity.CursorNotes, notesCursor)
+		device.UpdateCursor("photos", photosCursor)
+		err = repo.Update(ctx, device)
+		require.NoError(t, err)
+
+		found, err := repo.GetByID(ctx, device.ID)
+		require.NoError(t, err)
+		assert.WithinDuration(t, notesCursor, found.Cursor(entity.CursorNotes), time.Second)
+		assert.WithinDuration(t, photosCursor, found.Cursor("photos"), time.Second)
+		assert.WithinDuration(t, notesCursor, found.SyncCursor, time.Second)
+	})
+
+	t.Run("persists sync scope", func(t *testing.T) {
+		db.Truncate(t, "devices", "users")
+		user := createTestUser(t, db)
+
+		device := entity.NewDevice(user.ID, "tablet-1", "android", "Field Tablet")
+		err := repo.Create(ctx, device)
+		require.NoError(t, err)
+
+		notesSince := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+		device.SetScope(entity.SyncScope{NotesSince: &notesSince, ExcludePhotos: true})
+		err = repo.Update(ctx, device)
+		require.NoError(t, err)
+
+		found, err := repo.GetByUserAndDeviceID(ctx, user.ID, "tablet-1")
+		require.NoError(t, err)
+		require.NotNil(t, found.Scope.NotesSince)
+		assert.True(t, notesSince.Equal(*found.Scope.NotesSince))
+		assert.True(t, found.Scope.ExcludePhotos)
+	})
 }
 
 func TestIntegrationDeviceRepo_Upsert(t *testing.T) {
@@ -179,3 +221,106 @@ func TestIntegrationDeviceRepo_Upsert(t *testing.T) {
 		assert.Equal(t, "iPhone 15 Pro", found.Name)
 	})
 }
+
+func TestIntegrationDeviceRepo_ActiveAuthor(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewDeviceRepo(db.Pool)
+	noteRepo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
+	ctx := context.Background()
+
+	db.Truncate(t, "notes", "devices", "users")
+	user := createTestUser(t, db)
+	device := entity.NewDevice(user.ID, "kit-3", "android", "Shared tablet")
+	require.NoError(t, repo.Create(ctx, device))
+
+	device.SetActiveAuthor("Ana Silva")
+	require.NoError(t, repo.Update(ctx, device))
+
+	stored, err := repo.GetByUserAndDeviceID(ctx, user.ID, "kit-3")
+	require.NoError(t, err)
+	assert.Equal(t, "Ana Silva", stored.ActiveAuthor)
+	require.NotNil(t, stored.ActiveAuthorSince)
+
+	t.Run("stamps notes the device creates", func(t *testing.T) {
+		note := entity.NewNote(user.ID, "Plot 1", "Clay", nil, "plot-1")
+		note.CreatedByDevice = "kit-3"
+		require.NoError(t, noteRepo.Create(ctx, note))
+		assert.Equal(t, "Ana Silva", note.Author)
+
+		synced := *entity.NewNote(user.ID, "Plot 2", "Sand", nil, "plot-2")
+		synced.CreatedByDevice = "kit-3"
+		require.NoError(t, noteRepo.BatchUpsert(ctx, []entity.Note{synced}))
+
+		found, err := noteRepo.GetByClientID(ctx, user.ID, "plot-2")
+		require.NoError(t, err)
+		assert.Equal(t, "Ana Silva", found.Author)
+	})
+
+	t.Run("keeps the author of existing notes after checking out", func(t *testing.T) {
+		stored.SetActiveAuthor("")
+		require.NoError(t, repo.Update(ctx, stored))
+
+		note := entity.NewNote(user.ID, "Plot 3", "Loam", nil, "plot-3")
+		note.CreatedByDevice = "kit-3"
+		require.NoError(t, noteRepo.Create(ctx, note))
+		assert.Empty(t, note.Author)
+
+		found, err := noteRepo.GetByClientID(ctx, user.ID, "plot-1")
+		require.NoError(t, err)
+		assert.Equal(t, "Ana Silva", found.Author)
+	})
+}
+
+func TestIntegrationDeviceRepo_SetPushToken(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewDeviceRepo(db.Pool)
+	ctx := context.Background()
+
+	db.Truncate(t, "devices", "users")
+	user := createTestUser(t, db)
+	old := entity.NewDevice(user.ID, "install-1", "ios", "iPhone")
+	reinstalled := entity.NewDevice(user.ID, "install-2", "ios", "iPhone")
+	require.NoError(t, repo.Create(ctx, old))
+	require.NoError(t, repo.Create(ctx, reinstalled))
+
+	old.SetPushToken("apns-token")
+	require.NoError(t, repo.SetPushToken(ctx, old))
+
+	stored, err := repo.GetByID(ctx, old.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "apns-token", stored.PushToken)
+	require.NotNil(t, stored.PushTokenUpdatedAt)
+
+	t.Run("moves the token to the device that registers it", func(t *testing.T) {
+		reinstalled.SetPushToken("apns-token")
+		require.NoError(t, repo.SetPushToken(ctx, reinstalled))
+
+		devices, err := repo.ListByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		tokens := map[string]string{}
+		for _, d := range devices {
+			tokens[d.DeviceID] = d.PushToken
+		}
+		assert.Equal(t, map[string]string{"install-1": "", "install-2": "apns-token"}, tokens)
+	})
+
+	t.Run("clears the token", func(t *testing.T) {
+		reinstalled.SetPushToken("")
+		require.NoError(t, repo.SetPushToken(ctx, reinstalled))
+
+		stored, err := repo.GetByID(ctx, reinstalled.ID)
+		require.NoError(t, err)
+		assert.Empty(t, stored.PushToken)
+		assert.Nil(t, stored.PushTokenUpdatedAt)
+	})
+
+	t.Run("reports a missing device", func(t *testing.T) {
+		missing := entity.NewDevice(user.ID, "gone", "android", "Pixel")
+		missing.SetPushToken("fcm-token")
+		assert.ErrorIs(t, repo.SetPushToken(ctx, missing), domain.ErrDeviceNotFound)
+	})
+}
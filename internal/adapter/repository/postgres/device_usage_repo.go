@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+type DeviceUsageRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewDeviceUsageRepo(pool *pgxpool.Pool) *DeviceUsageRepo {
+	return &DeviceUsageRepo{pool: pool}
+}
+
+func (r *DeviceUsageRepo) Increment(ctx context.Context, usage []entity.DeviceUsage) error {
+	if len(usage) == 0 {
+		return nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO device_usage (device_id, day, bytes_in, bytes_out, updated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (device_id, day)
+		DO UPDATE SET
+			bytes_in = device_usage.bytes_in + EXCLUDED.bytes_in,
+			bytes_out = device_usage.bytes_out + EXCLUDED.bytes_out,
+			updated_at = NOW()
+	`
+	for _, u := range usage {
+		if _, err := tx.Exec(ctx, query, u.DeviceID, entity.UsageDay(u.Day), u.BytesIn, u.BytesOut); err != nil {
+			return fmt.Errorf("incrementing device usage: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DeviceUsageRepo) ListByDevice(ctx context.Context, deviceID uuid.UUID, from, to time.Time) ([]entity.DeviceUsage, error) {
+	query := `
+		SELECT device_id, day, bytes_in, bytes_out
+		FROM device_usage
+		WHERE device_id = $1 AND day >= $2 AND day <= $3
+		ORDER BY day ASC
+	`
+	rows, err := r.pool.Query(ctx, query, deviceID, entity.UsageDay(from), entity.UsageDay(to))
+	if err != nil {
+		return nil, fmt.Errorf("querying device usage: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []entity.DeviceUsage
+	for rows.Next() {
+		var u entity.DeviceUsage
+		if err := rows.Scan(&u.DeviceID, &u.Day, &u.BytesIn, &u.BytesOut); err != nil {
+			return nil, fmt.Errorf("scanning device usage: %w", err)
+		}
+		usage = append(usage, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating device usage: %w", err)
+	}
+
+	return usage, nil
+}
+
+func (r *DeviceUsageRepo) ListAbove(ctx context.Context, day time.Time, minBytes int64) ([]entity.DeviceUsage, error) {
+	query := `
+		SELECT device_id, day, bytes_in, bytes_out
+		FROM device_usage
+		WHERE day = $1 AND bytes_in + bytes_out > $2
+	`
+	rows, err := r.pool.Query(ctx, query, entity.UsageDay(day), minBytes)
+	if err != nil {
+		return nil, fmt.Errorf("querying device usage: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []entity.DeviceUsage
+	for rows.Next() {
+		var u entity.DeviceUsage
+		if err := rows.Scan(&u.DeviceID, &u.Day, &u.BytesIn, &u.BytesOut); err != nil {
+			return nil, fmt.Errorf("scanning device usage: %w", err)
+		}
+		usage = append(usage, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating device usage: %w", err)
+	}
+
+	return usage, nil
+}
@@ -0,0 +1,19 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Postgres error codes the repositories translate into domain errors.
+const (
+	codeNotNullViolation = "23502"
+	codeUniqueViolation  = "23505"
+)
+
+// hasCode reports whether err is a Postgres error with the given SQLSTATE.
+func hasCode(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
@@ -0,0 +1,42 @@
+package postgres
+
+// HotQueries returns the statements run by most requests: the token version
+// check of every authenticated request and the user, note and device
+// lookups behind auth, note reads and sync. Preparing them ahead of time
+// spares the first requests on a new connection the parse and plan.
+func HotQueries() []string {
+	return []string{tokenVersionQuery, userByIDQuery, noteByIDQuery, deviceByUserQuery}
+}
+
+// IndexUse is an index a repository query depends on: its table and the
+// leading columns the query filters or sorts on.
+type IndexUse struct {
+	Table   string
+	Columns []string
+	Query   string
+}
+
+// RequiredIndexes returns the indexes the repositories' frequent queries
+// rely on, for checking a database before deploys. Dropping one turns its
+// query into a sequential scan.
+func RequiredIndexes() []IndexUse {
+	return []IndexUse{
+		{Table: "users", Columns: []string{"email"}, Query: "login by email"},
+		{Table: "notes", Columns: []string{"user_id", "updated_at"}, Query: "sync changes and note lists"},
+		{Table: "notes", Columns: []string{"user_id", "client_id"}, Query: "sync upsert by client ID"},
+		{Table: "notes", Columns: []string{"user_id", "number"}, Query: "note numbering"},
+		{Table: "photos", Columns: []string{"note_id"}, Query: "photos of a note"},
+		{Table: "attachments", Columns: []string{"note_id"}, Query: "attachments of a note"},
+		{Table: "devices", Columns: []string{"user_id", "device_id"}, Query: "device lookup on every sync"},
+		{Table: "refresh_tokens", Columns: []string{"token"}, Query: "token refresh"},
+		{Table: "password_reset_tokens", Columns: []string{"token_hash"}, Query: "password reset"},
+		{Table: "note_shares", Columns: []string{"user_id"}, Query: "notes shared with a user"},
+		{Table: "auth_events", Columns: []string{"created_at"}, Query: "anomaly analysis window"},
+		{Table: "audit_events", Columns: []string{"user_id", "created_at"}, Query: "account activity of a user"},
+		{Table: "auth_providers", Columns: []string{"provider", "subject"}, Query: "social login"},
+		{Table: "sync_conflicts", Columns: []string{"user_id", "created_at"}, Query: "pending conflicts of a user"},
+		{Table: "client_usage", Columns: []string{"day"}, Query: "client adoption report"},
+		{Table: "deprecation_usage", Columns: []string{"day"}, Query: "deprecation report"},
+		{Table: "device_usage", Columns: []string{"day"}, Query: "daily active users"},
+	}
+}
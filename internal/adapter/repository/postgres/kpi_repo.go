@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+type KPIRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewKPIRepo(pool *pgxpool.Pool) *KPIRepo {
+	return &KPIRepo{pool: pool}
+}
+
+func (r *KPIRepo) IncrementEvents(ctx context.Context, day time.Time, counts map[string]int64) error {
+	if len(counts) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(counts))
+	values := make([]int64, 0, len(counts))
+	for name, count := range counts {
+		names = append(names, name)
+		values = append(values, count)
+	}
+
+	query := `
+		INSERT INTO kpi_events (day, name, count)
+		SELECT $1, name, count FROM unnest($2::text[], $3::bigint[]) AS e(name, count)
+		ON CONFLICT (day, name)
+		DO UPDATE SET count = kpi_events.count + EXCLUDED.count
+	`
+	if _, err := r.pool.Exec(ctx, query, entity.UsageDay(day), names, values); err != nil {
+		return fmt.Errorf("incrementing kpi events: %w", err)
+	}
+	return nil
+}
+
+func (r *KPIRepo) Events(ctx context.Context, day time.Time) (map[string]int64, error) {
+	rows, err := r.pool.Query(ctx, `SELECT name, count FROM kpi_events WHERE day = $1`, entity.UsageDay(day))
+	if err != nil {
+		return nil, fmt.Errorf("querying kpi events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make(map[string]int64)
+	for rows.Next() {
+		var name string
+		var count int64
+		if err := rows.Scan(&name, &count); err != nil {
+			return nil, fmt.Errorf("scanning kpi event: %w", err)
+		}
+		events[name] = count
+	}
+	return events, rows.Err()
+}
+
+func (r *KPIRepo) ActiveUsers(ctx context.Context, day time.Time) (int64, error) {
+	query := `
+		SELECT COUNT(DISTINCT d.user_id)
+		FROM device_usage u
+		JOIN devices d ON d.id = u.device_id
+		WHERE u.day = $1
+	`
+	var count int64
+	if err := r.pool.QueryRow(ctx, query, entity.UsageDay(day)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting active users: %w", err)
+	}
+	return count, nil
+}
+
+func (r *KPIRepo) SaveReport(ctx context.Context, report entity.KPIReport) error {
+	query := `
+		INSERT INTO daily_kpis (day, active_users, notes_created, syncs, sync_failures, notes_pushed,
+								conflicts, photo_uploads, photo_upload_failures, computed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (day)
+		DO UPDATE SET
+			active_users = EXCLUDED.active_users,
+			notes_created = EXCLUDED.notes_created,
+			syncs = EXCLUDED.syncs,
+			sync_failures = EXCLUDED.sync_failures,
+			notes_pushed = EXCLUDED.notes_pushed,
+			conflicts = EXCLUDED.conflicts,
+			photo_uploads = EXCLUDED.photo_uploads,
+			photo_upload_failures = EXCLUDED.photo_upload_failures,
+			computed_at = EXCLUDED.computed_at
+	`
+	_, err := r.pool.Exec(ctx, query,
+		entity.UsageDay(report.Day), report.ActiveUsers, report.NotesCreated, report.Syncs, report.SyncFailures,
+		report.NotesPushed, report.Conflicts, report.PhotoUploads, report.PhotoUploadFailures, report.ComputedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("saving kpi report: %w", err)
+	}
+	return nil
+}
+
+func (r *KPIRepo) ListReports(ctx context.Context, from, to time.Time) ([]entity.KPIReport, error) {
+	query := `
+		SELECT day, active_users, notes_created, syncs, sync_failures, notes_pushed,
+			   conflicts, photo_uploads, photo_upload_failures, computed_at
+		FROM daily_kpis
+		WHERE day >= $1 AND day <= $2
+		ORDER BY day
+	`
+	rows, err := r.pool.Query(ctx, query, entity.UsageDay(from), entity.UsageDay(to))
+	if err != nil {
+		return nil, fmt.Errorf("querying kpi reports: %w", err)
+	}
+	defer rows.Close()
+
+	var reports []entity.KPIReport
+	for rows.Next() {
+		var k entity.KPIReport
+		if err := rows.Scan(
+			&k.Day, &k.ActiveUsers, &k.NotesCreated, &k.Syncs, &k.SyncFailures, &k.NotesPushed,
+			&k.Conflicts, &k.PhotoUploads, &k.PhotoUploadFailures, &k.ComputedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scanning kpi report: %w", err)
+		}
+		reports = append(reports, k)
+	}
+	return reports, rows.Err()
+}
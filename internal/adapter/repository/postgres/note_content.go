@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/storage"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+// contentExcerptLength is how many characters of offloaded content stay in
+// the row, for lists, sync and search.
+const contentExcerptLength = 500
+
+// ContentOffload moves note content larger than Threshold bytes to Storage,
+// keeping an excerpt and the object's key and URL in the row. A Threshold of
+// zero stops offloading new content but still resolves content offloaded
+// before.
+type ContentOffload struct {
+	Storage   storage.ImageStorage
+	Threshold int
+}
+
+// noteContent is what a note's content columns hold after a write.
+type noteContent struct {
+	content string
+	key     *string
+	url     *string
+	// uploaded is set when the write put a new object in storage, which
+	// must be removed again if the row is not written.
+	uploaded bool
+}
+
+// uploadedKey returns the object uploaded for the write, if any.
+func (c noteContent) uploadedKey() string {
+	if !c.uploaded {
+		return ""
+	}
+	return *c.key
+}
+
+// prepareContent returns the content columns for the note stored in row
+// rowID, uploading its content when it is over the threshold. Content is
+// stored under a key derived from its hash, so saving unchanged content over
+// previousKey uploads nothing.
+func (r *NoteRepo) prepareContent(ctx context.Context, note *entity.Note, rowID uuid.UUID, previousKey string) (noteContent, error) {
+	if err := r.resolveContent(ctx, note); err != nil {
+		return noteContent{}, err
+	}
+	if r.offload == nil || r.offload.Threshold <= 0 || len(note.Content) <= r.offload.Threshold {
+		note.ContentKey, note.ContentURL = "", ""
+		return noteContent{content: note.Content}, nil
+	}
+
+	sum := sha256.Sum256([]byte(note.Content))
+	key := fmt.Sprintf("notes/%s/content-%s.txt", rowID, hex.EncodeToString(sum[:8]))
+	url := r.offload.Storage.GetURL(key)
+
+	uploaded := false
+	if key != previousKey {
+		data := []byte(note.Content)
+		if _, err := r.offload.Storage.Upload(ctx, key, bytes.NewReader(data), "text/plain; charset=utf-8", int64(len(data))); err != nil {
+			return noteContent{}, fmt.Errorf("uploading note content: %w", err)
+		}
+		uploaded = true
+	}
+
+	note.ContentKey = key
+	note.ContentURL = url
+	return noteContent{content: contentExcerpt(note.Content), key: &key, url: &url, uploaded: uploaded}, nil
+}
+
+// resolveContent replaces an excerpt read from the row with the full content.
+func (r *NoteRepo) resolveContent(ctx context.Context, note *entity.Note) error {
+	if !note.ContentExcerpt {
+		return nil
+	}
+	content, err := r.downloadContent(ctx, note.ContentKey)
+	if err != nil {
+		return fmt.Errorf("resolving content of note %s: %w", note.ID, err)
+	}
+	note.Content = content
+	note.ContentExcerpt = false
+	return nil
+}
+
+// downloadContent reads the content object stored under key.
+func (r *NoteRepo) downloadContent(ctx context.Context, key string) (string, error) {
+	if r.offload == nil {
+		return "", errors.New("no content storage")
+	}
+	data, err := r.offload.Storage.Download(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("downloading note content: %w", err)
+	}
+	return string(data), nil
+}
+
+// rowQuerier is a pool or a transaction.
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// storedContent returns the ID of the row selected by query and the key of
+// the content object it points to, or "" when its content is in the row.
+// A missing row is returned as pgx.ErrNoRows.
+func storedContent(ctx context.Context, db rowQuerier, query string, args ...any) (uuid.UUID, string, error) {
+	var id uuid.UUID
+	var key *string
+	if err := db.QueryRow(ctx, query, args...).Scan(&id, &key); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, "", err
+		}
+		return uuid.Nil, "", fmt.Errorf("querying note content: %w", err)
+	}
+	if key == nil {
+		return id, "", nil
+	}
+	return id, *key, nil
+}
+
+// removeContent deletes content objects no longer referenced by any row.
+// Failures only leave an orphaned object behind, so they are ignored.
+func (r *NoteRepo) removeContent(ctx context.Context, keys ...string) {
+	if r.offload == nil {
+		return
+	}
+	for _, key := range keys {
+		if key != "" {
+			_ = r.offload.Storage.Delete(ctx, key)
+		}
+	}
+}
+
+// contentExcerpt cuts content to contentExcerptLength characters.
+func contentExcerpt(content string) string {
+	if utf8.RuneCountInString(content) <= contentExcerptLength {
+		return content
+	}
+	return string([]rune(content)[:contentExcerptLength])
+}
@@ -0,0 +1,183 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/crdt"
+)
+
+type NoteDocumentRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewNoteDocumentRepo(pool *pgxpool.Pool) *NoteDocumentRepo {
+	return &NoteDocumentRepo{pool: pool}
+}
+
+func (r *NoteDocumentRepo) Create(ctx context.Context, doc *entity.NoteDocument) error {
+	query := `
+		INSERT INTO note_documents (note_id, snapshot, snapshot_seq, seq, created_at, updated_at)
+		VALUES ($1, $2, $3, $3, $4, $5)
+	`
+	_, err := r.pool.Exec(ctx, query, doc.NoteID, doc.Snapshot, doc.SnapshotSeq, doc.CreatedAt, doc.UpdatedAt)
+	if err != nil {
+		if hasCode(err, codeUniqueViolation) {
+			return domain.ErrDocumentExists
+		}
+		return fmt.Errorf("inserting note document: %w", err)
+	}
+	return nil
+}
+
+func (r *NoteDocumentRepo) Get(ctx context.Context, noteID uuid.UUID) (*entity.NoteDocument, error) {
+	return getNoteDocument(ctx, r.pool, noteID, "")
+}
+
+// Append runs fn with the document locked, so updates to a note are
+// numbered and applied one at a time across instances.
+func (r *NoteDocumentRepo) Append(ctx context.Context, noteID uuid.UUID, fn func(*entity.NoteDocument) (*entity.DocumentUpdate, error)) (*entity.DocumentUpdate, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	doc, err := getNoteDocument(ctx, tx, noteID, "FOR UPDATE")
+	if err != nil {
+		return nil, err
+	}
+	update, err := fn(doc)
+	if err != nil || update == nil {
+		return nil, err
+	}
+
+	update.NoteID = noteID
+	update.Seq = doc.Seq() + 1
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO note_document_updates (note_id, seq, user_id, device_id, ops, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, noteID, update.Seq, update.UserID, update.DeviceID, update.Ops, update.CreatedAt); err != nil {
+		return nil, fmt.Errorf("inserting document update: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE note_documents SET seq = $2, updated_at = $3 WHERE note_id = $1
+	`, noteID, update.Seq, update.CreatedAt); err != nil {
+		return nil, fmt.Errorf("updating note document: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+	return update, nil
+}
+
+// Compact ignores snapshots older than the stored one, so two instances
+// compacting the same document cannot move it back.
+func (r *NoteDocumentRepo) Compact(ctx context.Context, noteID uuid.UUID, snapshot crdt.Snapshot, seq int64) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `
+		UPDATE note_documents SET snapshot = $2, snapshot_seq = $3
+		WHERE note_id = $1 AND snapshot_seq < $3 AND seq >= $3
+	`, noteID, snapshot, seq)
+	if err != nil {
+		return fmt.Errorf("updating document snapshot: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM note_document_updates WHERE note_id = $1 AND seq <= $2
+	`, noteID, seq); err != nil {
+		return fmt.Errorf("deleting compacted updates: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *NoteDocumentRepo) ListCompactable(ctx context.Context, minUpdates, limit int) ([]uuid.UUID, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT note_id FROM note_documents
+		WHERE seq - snapshot_seq >= $1
+		ORDER BY seq - snapshot_seq DESC, note_id
+		LIMIT $2
+	`, minUpdates, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying compactable documents: %w", err)
+	}
+	noteIDs, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, fmt.Errorf("scanning compactable documents: %w", err)
+	}
+	return noteIDs, nil
+}
+
+func (r *NoteDocumentRepo) Delete(ctx context.Context, noteID uuid.UUID) error {
+	result, err := r.pool.Exec(ctx, `DELETE FROM note_documents WHERE note_id = $1`, noteID)
+	if err != nil {
+		return fmt.Errorf("deleting note document: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrDocumentNotFound
+	}
+	return nil
+}
+
+// documentQuerier is a pool or a transaction.
+type documentQuerier interface {
+	rowQuerier
+	noteQuerier
+}
+
+// getNoteDocument reads the document and the updates since its snapshot,
+// adding lock to the document's select.
+func getNoteDocument(ctx context.Context, db documentQuerier, noteID uuid.UUID, lock string) (*entity.NoteDocument, error) {
+	doc := entity.NoteDocument{NoteID: noteID}
+	err := db.QueryRow(ctx, `
+		SELECT snapshot, snapshot_seq, created_at, updated_at FROM note_documents WHERE note_id = $1 `+lock,
+		noteID,
+	).Scan(&doc.Snapshot, &doc.SnapshotSeq, &doc.CreatedAt, &doc.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrDocumentNotFound
+		}
+		return nil, fmt.Errorf("querying note document: %w", err)
+	}
+
+	rows, err := db.Query(ctx, `
+		SELECT seq, user_id, device_id, ops, created_at FROM note_document_updates
+		WHERE note_id = $1 AND seq > $2
+		ORDER BY seq
+	`, noteID, doc.SnapshotSeq)
+	if err != nil {
+		return nil, fmt.Errorf("querying document updates: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		u := entity.DocumentUpdate{NoteID: noteID}
+		if err := rows.Scan(&u.Seq, &u.UserID, &u.DeviceID, &u.Ops, &u.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning document update: %w", err)
+		}
+		doc.Updates = append(doc.Updates, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating document updates: %w", err)
+	}
+	return &doc, nil
+}
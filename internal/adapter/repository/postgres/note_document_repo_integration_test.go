@@ -0,0 +1,91 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository/postgres"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/crdt"
+)
+
+func TestIntegrationNoteDocumentRepo(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	noteRepo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
+	repo := postgres.NewNoteDocumentRepo(db.Pool)
+	ctx := context.Background()
+
+	db.Truncate(t, "note_document_updates", "note_documents", "notes", "users")
+	user := createTestUser(t, db)
+	note := entity.NewNote(user.ID, "Transect", "abc", nil, "client-doc")
+	require.NoError(t, noteRepo.Create(ctx, note))
+
+	state := crdt.FromText("server", note.Content)
+	require.NoError(t, repo.Create(ctx, entity.NewNoteDocument(note.ID, state.Snapshot())))
+	require.ErrorIs(t, repo.Create(ctx, entity.NewNoteDocument(note.ID, state.Snapshot())), domain.ErrDocumentExists)
+
+	stored, err := noteRepo.GetByID(ctx, note.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.Collaborative)
+
+	// Two updates, then one that fn rejects and is not stored.
+	for _, text := range []string{"x", "y"} {
+		op, err := state.Insert("client", state.Len(), text)
+		require.NoError(t, err)
+		update, err := repo.Append(ctx, note.ID, func(doc *entity.NoteDocument) (*entity.DocumentUpdate, error) {
+			return &entity.DocumentUpdate{UserID: &user.ID, Ops: []crdt.Op{op}, CreatedAt: time.Now().UTC()}, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(state.Text())-3), update.Seq)
+	}
+	rejected := errors.New("rejected")
+	_, err = repo.Append(ctx, note.ID, func(*entity.NoteDocument) (*entity.DocumentUpdate, error) {
+		return nil, rejected
+	})
+	require.ErrorIs(t, err, rejected)
+
+	doc, err := repo.Get(ctx, note.ID)
+	require.NoError(t, err)
+	require.Len(t, doc.Updates, 2)
+	assert.Equal(t, int64(2), doc.Seq())
+	assert.Equal(t, user.ID, *doc.Updates[0].UserID)
+	rebuilt, err := doc.State()
+	require.NoError(t, err)
+	assert.Equal(t, "abcxy", rebuilt.Text())
+
+	compactable, err := repo.ListCompactable(ctx, 2, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{note.ID}, compactable)
+
+	require.NoError(t, repo.Compact(ctx, note.ID, rebuilt.Snapshot(), 2))
+	// An older snapshot arriving late leaves the new one in place.
+	require.NoError(t, repo.Compact(ctx, note.ID, state.Snapshot(), 1))
+
+	doc, err = repo.Get(ctx, note.ID)
+	require.NoError(t, err)
+	assert.Empty(t, doc.Updates)
+	assert.Equal(t, int64(2), doc.Seq())
+	rebuilt, err = doc.State()
+	require.NoError(t, err)
+	assert.Equal(t, "abcxy", rebuilt.Text())
+
+	compactable, err = repo.ListCompactable(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Empty(t, compactable)
+
+	// Hard-deleting the note removes its document.
+	_, err = db.Pool.Exec(ctx, `DELETE FROM notes WHERE id = $1`, note.ID)
+	require.NoError(t, err)
+	_, err = repo.Get(ctx, note.ID)
+	require.ErrorIs(t, err, domain.ErrDocumentNotFound)
+	require.ErrorIs(t, repo.Delete(ctx, note.ID), domain.ErrDocumentNotFound)
+}
@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+// NoteEmbeddingRepo stores note embeddings in the pgvector table
+// note_embeddings. There is no Go type for vector here; vectors are sent in
+// their text form, '[0.1,0.2,...]', and cast in SQL.
+type NoteEmbeddingRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewNoteEmbeddingRepo(pool *pgxpool.Pool) *NoteEmbeddingRepo {
+	return &NoteEmbeddingRepo{pool: pool}
+}
+
+func (r *NoteEmbeddingRepo) Available(ctx context.Context) (bool, error) {
+	var ok bool
+	if err := r.pool.QueryRow(ctx, `SELECT to_regclass('note_embeddings') IS NOT NULL`).Scan(&ok); err != nil {
+		return false, fmt.Errorf("checking note_embeddings: %w", err)
+	}
+	return ok, nil
+}
+
+// Pending embeds offloaded content from the excerpt kept in the row.
+func (r *NoteEmbeddingRepo) Pending(ctx context.Context, model string, limit int) ([]entity.Note, error) {
+	query := `
+		SELECT ` + noteColumns + `
+		FROM notes
+		WHERE deleted_at IS NULL
+		  AND (title <> '' OR content <> '')
+		  AND user_id IN (SELECT id FROM users WHERE semantic_search)
+		  AND NOT EXISTS (
+			  SELECT 1 FROM note_embeddings e
+			  WHERE e.note_id = notes.id AND e.user_id = notes.user_id
+			    AND e.model = $1 AND e.note_updated_at >= notes.updated_at)
+		ORDER BY updated_at, id
+		LIMIT $2
+	`
+	return queryNotes(ctx, r.pool, query, model, limit)
+}
+
+func (r *NoteEmbeddingRepo) Save(ctx context.Context, embeddings []entity.NoteEmbedding) error {
+	if len(embeddings) == 0 {
+		return nil
+	}
+
+	noteIDs := make([]uuid.UUID, len(embeddings))
+	userIDs := make([]uuid.UUID, len(embeddings))
+	models := make([]string, len(embeddings))
+	vectors := make([]string, len(embeddings))
+	updatedAt := make([]time.Time, len(embeddings))
+	for i, e := range embeddings {
+		noteIDs[i], userIDs[i], models[i], updatedAt[i] = e.NoteID, e.UserID, e.Model, e.NoteUpdatedAt
+		vectors[i] = vectorLiteral(e.Vector)
+	}
+
+	query := `
+		INSERT INTO note_embeddings (note_id, user_id, model, embedding, note_updated_at)
+		SELECT note_id, user_id, model, embedding::vector, note_updated_at
+		FROM unnest($1::uuid[], $2::uuid[], $3::text[], $4::text[], $5::timestamptz[])
+			AS e(note_id, user_id, model, embedding, note_updated_at)
+		ON CONFLICT (note_id) DO UPDATE SET
+			user_id = EXCLUDED.user_id, model = EXCLUDED.model, embedding = EXCLUDED.embedding,
+			note_updated_at = EXCLUDED.note_updated_at, created_at = NOW()
+	`
+	if _, err := r.pool.Exec(ctx, query, noteIDs, userIDs, models, vectors, updatedAt); err != nil {
+		return fmt.Errorf("saving note embeddings: %w", err)
+	}
+	return nil
+}
+
+func (r *NoteEmbeddingRepo) Search(ctx context.Context, userID uuid.UUID, model string, vector []float32, limit int) ([]entity.SearchHit, error) {
+	query := `
+		SELECT ` + noteColumns + `, 1 - e.dist AS score, NULL::double precision AS distance
+		FROM notes
+		JOIN (SELECT note_id, embedding <=> $3::vector AS dist
+			  FROM note_embeddings
+			  WHERE user_id = $1 AND model = $2) e ON e.note_id = notes.id
+		WHERE notes.user_id = $1 AND notes.deleted_at IS NULL
+		ORDER BY e.dist, notes.id
+		LIMIT $4
+	`
+	return r.queryHits(ctx, query, userID, model, vectorLiteral(vector), limit)
+}
+
+func (r *NoteEmbeddingRepo) Related(ctx context.Context, userID, noteID uuid.UUID, model string, limit int) ([]entity.SearchHit, error) {
+	query := `
+		SELECT ` + noteColumns + `, 1 - e.dist AS score, NULL::double precision AS distance
+		FROM notes
+		JOIN (SELECT ne.note_id, ne.embedding <=> src.embedding AS dist
+			  FROM note_embeddings ne
+			  JOIN note_embeddings src ON src.note_id = $2 AND src.model = $3
+			  WHERE ne.user_id = $1 AND ne.model = $3 AND ne.note_id <> $2) e ON e.note_id = notes.id
+		WHERE notes.user_id = $1 AND notes.deleted_at IS NULL
+		ORDER BY e.dist, notes.id
+		LIMIT $4
+	`
+	return r.queryHits(ctx, query, userID, noteID, model, limit)
+}
+
+func (r *NoteEmbeddingRepo) queryHits(ctx context.Context, query string, args ...any) ([]entity.SearchHit, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("searching note embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []entity.SearchHit
+	for rows.Next() {
+		var hit entity.SearchHit
+		note, err := scanNoteRow(rows, &hit.Score, &hit.Distance)
+		if err != nil {
+			return nil, fmt.Errorf("scanning note: %w", err)
+		}
+		hit.Note = *note
+		hits = append(hits, hit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating notes: %w", err)
+	}
+
+	return hits, nil
+}
+
+// Prune also catches notes that moved to another user, whose new owner's
+// choice then applies.
+func (r *NoteEmbeddingRepo) Prune(ctx context.Context) (int64, error) {
+	query := `
+		DELETE FROM note_embeddings e
+		WHERE NOT EXISTS (SELECT 1 FROM users u WHERE u.id = e.user_id AND u.semantic_search)
+		   OR NOT EXISTS (SELECT 1 FROM notes n
+						  WHERE n.id = e.note_id AND n.user_id = e.user_id AND n.deleted_at IS NULL)
+	`
+	result, err := r.pool.Exec(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("pruning note embeddings: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// vectorLiteral formats v as pgvector's text input.
+func vectorLiteral(v []float32) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, x := range v {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(float64(x), 'g', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
@@ -0,0 +1,88 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository/postgres"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+func TestIntegrationNoteEmbeddingRepo(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewNoteEmbeddingRepo(db.Pool)
+	noteRepo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
+	userRepo := postgres.NewUserRepo(db.Pool)
+	ctx := context.Background()
+
+	// The PostGIS test image ships without pgvector, which leaves the table
+	// out.
+	available, err := repo.Available(ctx)
+	require.NoError(t, err)
+	if !available {
+		t.Skip("pgvector is not installed")
+	}
+
+	const model = "test-model"
+
+	t.Run("embeds opted-in notes and finds the closest", func(t *testing.T) {
+		db.Truncate(t, "note_embeddings", "notes", "users")
+		user := createTestUser(t, db)
+
+		oak := entity.NewNote(user.ID, "Oak survey", "Three oaks", nil, "")
+		nest := entity.NewNote(user.ID, "Ninho", "Two eggs", nil, "")
+		require.NoError(t, noteRepo.Create(ctx, oak))
+		require.NoError(t, noteRepo.Create(ctx, nest))
+
+		pending, err := repo.Pending(ctx, model, 10)
+		require.NoError(t, err)
+		assert.Empty(t, pending, "notes of users who did not opt in")
+
+		user.SetSemanticSearch(true)
+		require.NoError(t, userRepo.Update(ctx, user))
+
+		pending, err = repo.Pending(ctx, model, 10)
+		require.NoError(t, err)
+		require.Len(t, pending, 2)
+
+		require.NoError(t, repo.Save(ctx, []entity.NoteEmbedding{
+			{NoteID: oak.ID, UserID: user.ID, Model: model, Vector: []float32{1, 0, 0}, NoteUpdatedAt: oak.UpdatedAt},
+			{NoteID: nest.ID, UserID: user.ID, Model: model, Vector: []float32{0, 1, 0}, NoteUpdatedAt: nest.UpdatedAt},
+		}))
+
+		pending, err = repo.Pending(ctx, model, 10)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+
+		hits, err := repo.Search(ctx, user.ID, model, []float32{0.9, 0.1, 0}, 10)
+		require.NoError(t, err)
+		require.Len(t, hits, 2)
+		assert.Equal(t, oak.ID, hits[0].Note.ID)
+		assert.Greater(t, hits[0].Score, hits[1].Score)
+
+		related, err := repo.Related(ctx, user.ID, oak.ID, model, 10)
+		require.NoError(t, err)
+		require.Len(t, related, 1)
+		assert.Equal(t, nest.ID, related[0].Note.ID)
+	})
+
+	t.Run("prunes embeddings of users who opted out", func(t *testing.T) {
+		db.Truncate(t, "note_embeddings", "notes", "users")
+		user := createTestUser(t, db)
+		note := entity.NewNote(user.ID, "Oak survey", "Three oaks", nil, "")
+		require.NoError(t, noteRepo.Create(ctx, note))
+
+		require.NoError(t, repo.Save(ctx, []entity.NoteEmbedding{
+			{NoteID: note.ID, UserID: user.ID, Model: model, Vector: []float32{1, 0}, NoteUpdatedAt: note.UpdatedAt},
+		}))
+
+		pruned, err := repo.Prune(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), pruned)
+	})
+}
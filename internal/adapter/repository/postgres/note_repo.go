@@ -15,21 +15,62 @@ import (
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/clock"
 	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
 )
 
 type NoteRepo struct {
-	pool *pgxpool.Pool
+	pool    *pgxpool.Pool
+	reads   *ReadRouter
+	offload *ContentOffload
+	clock   clock.Clock
 }
 
-func NewNoteRepo(pool *pgxpool.Pool) *NoteRepo {
-	return &NoteRepo{pool: pool}
+// NewNoteRepo returns a repository that writes to pool. List reads go through
+// reads when it is set, so they can be served by a replica. Large content is
+// moved to object storage when offload is set; see ContentOffload. clk stamps
+// deletions; nil means the system clock.
+func NewNoteRepo(pool *pgxpool.Pool, reads *ReadRouter, offload *ContentOffload, clk clock.Clock) *NoteRepo {
+	if clk == nil {
+		clk = clock.System{}
+	}
+	return &NoteRepo{pool: pool, reads: reads, offload: offload, clock: clk}
+}
+
+// reader returns the pool for reads that tolerate replica lag.
+func (r *NoteRepo) reader(ctx context.Context) *pgxpool.Pool {
+	if r.reads == nil {
+		return r.pool
+	}
+	return r.reads.Reader(ctx)
 }
 
+// Create inserts the note and assigns it the next number in the owner's
+// sequence.
 func (r *NoteRepo) Create(ctx context.Context, note *entity.Note) error {
+	content, err := r.prepareContent(ctx, note, note.ID, "")
+	if err != nil {
+		return err
+	}
+
+	if err := r.insertNote(ctx, note, content); err != nil {
+		r.removeContent(ctx, content.uploadedKey())
+		return err
+	}
+	return nil
+}
+
+func (r *NoteRepo) insertNote(ctx context.Context, note *entity.Note, content noteContent) error {
 	query := `
-		INSERT INTO notes (id, user_id, title, content, location, altitude, accuracy, client_id, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, ST_SetSRID(ST_MakePoint($5, $6), 4326)::geography, $7, $8, $9, $10, $11)
+		INSERT INTO notes (id, user_id, number, reference, title, content, location, altitude, accuracy, client_id,
+						   created_by_device, last_modified_by_device,
+						   quality_status, quality_passed, quality_failed, quality_checked_at,
+						   measurements, sensitivity, created_at, updated_at, content_key, content_url,
+						   source, source_meta, team_id, author, color, icon, auto_titled, field_updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, ST_SetSRID(ST_MakePoint($7, $8), 4326)::geography, $9, $10, $11, $12, $13,
+				$14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26,
+				` + deviceAuthor + `, $27, $28, $29, $30)
+		RETURNING author
 	`
 	var lng, lat *float64
 	var altitude, accuracy *float64
@@ -41,33 +82,83 @@ func (r *NoteRepo) Create(ctx context.Context, note *entity.Note) error {
 		accuracy = note.Location.Accuracy
 	}
 
-	_, err := r.pool.Exec(ctx, query,
-		note.ID, note.UserID, note.Title, note.Content,
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := reserveNoteNumber(ctx, tx, note); err != nil {
+		return err
+	}
+
+	var author *string
+	err = tx.QueryRow(ctx, query,
+		note.ID, note.UserID, note.Number, note.Reference, note.Title, content.content,
 		lng, lat, altitude, accuracy,
-		nullableString(note.ClientID), note.CreatedAt, note.UpdatedAt,
-	)
+		nullableString(note.ClientID), nullableString(note.CreatedByDevice), nullableString(note.LastModifiedByDevice),
+		qualityStatus(note.Quality), qualityRules(note.Quality.Passed), qualityRules(note.Quality.Failed), note.Quality.CheckedAt,
+		measurementRows(note.Measurements), sensitivity(note.Sensitivity), note.CreatedAt, note.UpdatedAt,
+		content.key, content.url, noteSource(note.Source, entity.NoteSourceManual), note.SourceMeta, note.TeamID,
+		noteStyle(note.Color), noteStyle(note.Icon), note.AutoTitled, note.FieldTimes(),
+	).Scan(&author)
 	if err != nil {
+		if hasCode(err, codeUniqueViolation) {
+			return domain.ErrNoteAlreadyExists
+		}
 		return fmt.Errorf("inserting note: %w", err)
 	}
+	if author != nil {
+		note.Author = *author
+	}
+
+	if err := replaceTags(ctx, tx, note); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
 	return nil
 }
 
-func (r *NoteRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.Note, error) {
+// deviceAuthor stamps a new note with the active author of the device that
+// created it, $12 of the insert, when one is set.
+const deviceAuthor = `(SELECT active_author FROM devices WHERE user_id = $2 AND device_id = $12)`
+
+// reserveNoteNumber takes the next number from the owner's sequence. Numbers
+// are never reused, so deleted notes and rolled back inserts leave gaps.
+func reserveNoteNumber(ctx context.Context, tx pgx.Tx, note *entity.Note) error {
 	query := `
-		SELECT id, user_id, title, content,
-			   ST_Y(location::geometry) as lat, ST_X(location::geometry) as lng,
-			   altitude, accuracy, client_id, created_at, updated_at, deleted_at
-		FROM notes
+		UPDATE users
+		SET last_note_number = last_note_number + 1
 		WHERE id = $1
+		RETURNING note_prefix, last_note_number
 	`
-	return r.scanNote(ctx, query, id)
+	var prefix string
+	var number int64
+	if err := tx.QueryRow(ctx, query, note.UserID).Scan(&prefix, &number); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
+		return fmt.Errorf("reserving note number: %w", err)
+	}
+
+	note.AssignNumber(prefix, number)
+	return nil
+}
+
+// noteByIDQuery looks the note up in every partition, since callers only
+// have its ID.
+const noteByIDQuery = `SELECT ` + noteColumns + ` FROM notes WHERE id = $1`
+
+func (r *NoteRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.Note, error) {
+	return r.scanNote(ctx, noteByIDQuery, id)
 }
 
 func (r *NoteRepo) GetByClientID(ctx context.Context, userID uuid.UUID, clientID string) (*entity.Note, error) {
 	query := `
-		SELECT id, user_id, title, content,
-			   ST_Y(location::geometry) as lat, ST_X(location::geometry) as lng,
-			   altitude, accuracy, client_id, created_at, updated_at, deleted_at
+		SELECT ` + noteColumns + `
 		FROM notes
 		WHERE user_id = $1 AND client_id = $2
 	`
@@ -75,45 +166,136 @@ func (r *NoteRepo) GetByClientID(ctx context.Context, userID uuid.UUID, clientID
 }
 
 func (r *NoteRepo) scanNote(ctx context.Context, query string, args ...any) (*entity.Note, error) {
-	var note entity.Note
-	var lat, lng, altitude, accuracy *float64
-	var clientID *string
-
-	err := r.pool.QueryRow(ctx, query, args...).Scan(
-		&note.ID, &note.UserID, &note.Title, &note.Content,
-		&lat, &lng, &altitude, &accuracy,
-		&clientID, &note.CreatedAt, &note.UpdatedAt, &note.DeletedAt,
-	)
+	note, err := scanNoteRow(r.pool.QueryRow(ctx, query, args...))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrNoteNotFound
 		}
 		return nil, fmt.Errorf("querying note: %w", err)
 	}
+	// Single notes carry their full content; lists keep the excerpt.
+	if err := r.resolveContent(ctx, note); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
 
-	if lat != nil && lng != nil {
-		note.Location = valueobject.NewLocation(*lat, *lng, altitude, accuracy)
+func (r *NoteRepo) List(ctx context.Context, userID uuid.UUID, params repository.NoteListParams) ([]entity.Note, *pagination.Info, error) {
+	conditions, args := noteListConditions(userID, params)
+	argNum := len(args) + 1
+
+	db := r.reader(ctx)
+
+	if after := params.Pagination.After; after != nil {
+		return r.listAfter(ctx, db, conditions, args, after, params)
 	}
-	if clientID != nil {
-		note.ClientID = *clientID
+
+	whereClause := strings.Join(conditions, " AND ")
+
+	// Count total
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM notes WHERE %s", whereClause)
+	var total int
+	if err := db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, nil, fmt.Errorf("counting notes: %w", err)
 	}
 
-	return &note, nil
+	// Get notes
+	query := fmt.Sprintf(`
+		SELECT `+noteColumns+`
+		FROM notes
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, whereClause, orderByClause(noteOrder(params.Sort)), argNum, argNum+1)
+	args = append(args, params.Pagination.Limit(), params.Pagination.Offset())
+
+	notes, err := queryNotes(ctx, db, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pageInfo := pagination.NewInfo(params.Pagination.Page, params.Pagination.PerPage, total)
+	if pageInfo.HasNext && len(notes) > 0 {
+		// Lets clients switch to cursor pagination after any offset page.
+		pageInfo.NextCursor = noteCursor(&notes[len(notes)-1], params.Sort).Encode()
+	}
+	return notes, pageInfo, nil
 }
 
-func (r *NoteRepo) List(ctx context.Context, userID uuid.UUID, params repository.NoteListParams) ([]entity.Note, *pagination.Info, error) {
+// noteListConditions returns the WHERE conditions, and their arguments,
+// selecting the user's notes, or the team's when params.TeamID is set, that
+// match the filters of params.
+func noteListConditions(userID uuid.UUID, params repository.NoteListParams) ([]string, []any) {
 	var conditions []string
 	var args []any
 	argNum := 1
 
-	conditions = append(conditions, fmt.Sprintf("user_id = $%d", argNum))
-	args = append(args, userID)
+	if params.TeamID != nil {
+		conditions = append(conditions, fmt.Sprintf("team_id = $%d", argNum))
+		args = append(args, *params.TeamID)
+	} else {
+		conditions = append(conditions, fmt.Sprintf("user_id = $%d", argNum))
+		args = append(args, userID)
+	}
 	argNum++
 
 	if !params.IncludeDeleted {
 		conditions = append(conditions, "deleted_at IS NULL")
 	}
 
+	if params.DeviceID != "" {
+		conditions = append(conditions, fmt.Sprintf("(created_by_device = $%d OR last_modified_by_device = $%d)", argNum, argNum))
+		args = append(args, params.DeviceID)
+		argNum++
+	}
+
+	if params.QualityStatus != "" {
+		conditions = append(conditions, fmt.Sprintf("quality_status = $%d", argNum))
+		args = append(args, params.QualityStatus)
+		argNum++
+	}
+
+	if params.Source == entity.NoteSourceIntegration {
+		conditions = append(conditions, "source LIKE 'integration:%'")
+	} else if params.Source != "" {
+		conditions = append(conditions, fmt.Sprintf("source = $%d", argNum))
+		args = append(args, params.Source)
+		argNum++
+	}
+
+	if params.Number > 0 {
+		conditions = append(conditions, fmt.Sprintf("number = $%d", argNum))
+		args = append(args, params.Number)
+		argNum++
+	}
+
+	if params.Reference != "" {
+		conditions = append(conditions, fmt.Sprintf("reference = $%d", argNum))
+		args = append(args, params.Reference)
+		argNum++
+	}
+
+	for _, tag := range params.Tags {
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM note_tags nt JOIN tags t ON t.id = nt.tag_id
+			WHERE nt.note_id = notes.id AND t.name = $%d
+		)`, argNum))
+		args = append(args, tag)
+		argNum++
+	}
+
+	if len(params.Colors) > 0 {
+		conditions = append(conditions, fmt.Sprintf("color = ANY($%d)", argNum))
+		args = append(args, params.Colors)
+		argNum++
+	}
+
+	if len(params.Icons) > 0 {
+		conditions = append(conditions, fmt.Sprintf("icon = ANY($%d)", argNum))
+		args = append(args, params.Icons)
+		argNum++
+	}
+
 	if params.BoundingBox != nil {
 		bb := params.BoundingBox
 		conditions = append(conditions, fmt.Sprintf(`
@@ -126,72 +308,313 @@ func (r *NoteRepo) List(ctx context.Context, userID uuid.UUID, params repository
 		argNum += 4
 	}
 
-	whereClause := strings.Join(conditions, " AND ")
+	if params.CreatedFrom != nil {
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", argNum))
+		args = append(args, *params.CreatedFrom)
+		argNum++
+	}
 
-	// Count total
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM notes WHERE %s", whereClause)
-	var total int
-	if err := r.pool.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
-		return nil, nil, fmt.Errorf("counting notes: %w", err)
+	if params.CreatedTo != nil {
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", argNum))
+		args = append(args, *params.CreatedTo)
 	}
 
-	// Get notes
+	return conditions, args
+}
+
+// listAfter returns the page of notes after the cursor. It seeks on the
+// sort keys and id instead of counting and skipping rows, so deep pages
+// cost the same as the first one.
+func (r *NoteRepo) listAfter(ctx context.Context, db *pgxpool.Pool, conditions []string, args []any, after *pagination.Cursor, params repository.NoteListParams) ([]entity.Note, *pagination.Info, error) {
+	perPage := params.Pagination.PerPage
+	condition, values, err := afterCursor(params.Sort, after, len(args)+1)
+	if err != nil {
+		return nil, nil, err
+	}
+	conditions = append(conditions, condition)
+	args = append(args, values...)
+
+	// One extra row tells whether another page follows.
 	query := fmt.Sprintf(`
-		SELECT id, user_id, title, content,
-			   ST_Y(location::geometry) as lat, ST_X(location::geometry) as lng,
-			   altitude, accuracy, client_id, created_at, updated_at, deleted_at
+		SELECT `+noteColumns+`
 		FROM notes
 		WHERE %s
-		ORDER BY updated_at DESC
-		LIMIT $%d OFFSET $%d
-	`, whereClause, argNum, argNum+1)
-	args = append(args, params.Pagination.Limit(), params.Pagination.Offset())
+		ORDER BY %s
+		LIMIT $%d
+	`, strings.Join(conditions, " AND "), orderByClause(noteOrder(params.Sort)), len(args)+1)
+	args = append(args, perPage+1)
+
+	notes, err := queryNotes(ctx, db, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var next *pagination.Cursor
+	if len(notes) > perPage {
+		notes = notes[:perPage]
+		cursor := noteCursor(&notes[len(notes)-1], params.Sort)
+		next = &cursor
+	}
+
+	return notes, pagination.NewCursorInfo(perPage, next, true), nil
+}
+
+// noteQuerier is a pool or a transaction.
+type noteQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
 
-	rows, err := r.pool.Query(ctx, query, args...)
+func queryNotes(ctx context.Context, db noteQuerier, query string, args ...any) ([]entity.Note, error) {
+	rows, err := db.Query(ctx, query, args...)
 	if err != nil {
-		return nil, nil, fmt.Errorf("querying notes: %w", err)
+		return nil, fmt.Errorf("querying notes: %w", err)
 	}
 	defer rows.Close()
 
 	var notes []entity.Note
 	for rows.Next() {
-		var note entity.Note
-		var lat, lng, altitude, accuracy *float64
-		var clientID *string
+		note, err := scanNoteRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning note: %w", err)
+		}
+		notes = append(notes, *note)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating notes: %w", err)
+	}
+
+	return notes, nil
+}
+
+// Nearby uses ST_DWithin so the search can use the GiST index on location,
+// then sorts the matches by their exact distance.
+func (r *NoteRepo) Nearby(ctx context.Context, userID uuid.UUID, params repository.NoteNearbyParams) ([]entity.NearbyNote, error) {
+	query := `
+		SELECT ` + noteColumns + `,
+			   ST_Distance(location, ST_SetSRID(ST_MakePoint($2, $3), 4326)::geography) AS distance
+		FROM notes
+		WHERE user_id = $1 AND deleted_at IS NULL
+		  AND ST_DWithin(location, ST_SetSRID(ST_MakePoint($2, $3), 4326)::geography, $4)
+		ORDER BY distance, id
+		LIMIT $5
+	`
+	rows, err := r.reader(ctx).Query(ctx, query, userID, params.Longitude, params.Latitude, params.RadiusMeters, params.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying nearby notes: %w", err)
+	}
+	defer rows.Close()
 
-		if err := rows.Scan(
-			&note.ID, &note.UserID, &note.Title, &note.Content,
-			&lat, &lng, &altitude, &accuracy,
-			&clientID, &note.CreatedAt, &note.UpdatedAt, &note.DeletedAt,
-		); err != nil {
-			return nil, nil, fmt.Errorf("scanning note: %w", err)
+	var notes []entity.NearbyNote
+	for rows.Next() {
+		var distance float64
+		note, err := scanNoteRow(rows, &distance)
+		if err != nil {
+			return nil, fmt.Errorf("scanning note: %w", err)
 		}
+		notes = append(notes, entity.NearbyNote{Note: *note, Distance: distance})
+	}
 
-		if lat != nil && lng != nil {
-			note.Location = valueobject.NewLocation(*lat, *lng, altitude, accuracy)
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating notes: %w", err)
+	}
+
+	return notes, nil
+}
+
+// Coverage groups the user's notes with a location into Web Mercator tiles
+// in SQL, with the tile formula of entity.TileAt, and reads their extent
+// with ST_Extent, so no note rows leave the database.
+func (r *NoteRepo) Coverage(ctx context.Context, userID uuid.UUID, zoom, limit int) (*entity.NoteCoverage, error) {
+	coverage := &entity.NoteCoverage{Zoom: zoom}
+
+	var minLat, maxLat, minLng, maxLng *float64
+	err := r.reader(ctx).QueryRow(ctx, `
+		SELECT notes, ST_YMin(extent), ST_YMax(extent), ST_XMin(extent), ST_XMax(extent)
+		FROM (
+			SELECT count(*) AS notes, ST_Extent(location::geometry) AS extent
+			FROM notes
+			WHERE user_id = $1 AND deleted_at IS NULL AND location IS NOT NULL
+		) e
+	`, userID).Scan(&coverage.Notes, &minLat, &maxLat, &minLng, &maxLng)
+	if err != nil {
+		return nil, fmt.Errorf("querying note extent: %w", err)
+	}
+	if coverage.Notes == 0 {
+		return coverage, nil
+	}
+	coverage.Bounds = valueobject.NewBoundingBox(*minLat, *maxLat, *minLng, *maxLng)
+
+	rows, err := r.reader(ctx).Query(ctx, `
+		WITH located AS (
+			SELECT ST_X(location::geometry) AS lng,
+				   radians(LEAST(GREATEST(ST_Y(location::geometry), -$4::float8), $4::float8)) AS lat
+			FROM notes
+			WHERE user_id = $1 AND deleted_at IS NULL AND location IS NOT NULL
+		)
+		SELECT LEAST(floor((lng + 180) / 360 * 2 ^ $2::int)::int, (2 ^ $2::int)::int - 1) AS x,
+			   LEAST(GREATEST(floor((1 - ln(tan(lat) + 1 / cos(lat)) / pi()) / 2 * 2 ^ $2::int)::int, 0), (2 ^ $2::int)::int - 1) AS y,
+			   count(*)
+		FROM located
+		GROUP BY 1, 2
+		ORDER BY 1, 2
+		LIMIT $3
+	`, userID, zoom, limit+1, entity.MaxMercatorLatitude)
+	if err != nil {
+		return nil, fmt.Errorf("querying note tiles: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		tile := entity.CoverageTile{MapTile: entity.MapTile{Z: zoom}}
+		if err := rows.Scan(&tile.X, &tile.Y, &tile.Notes); err != nil {
+			return nil, fmt.Errorf("scanning tile: %w", err)
 		}
-		if clientID != nil {
-			note.ClientID = *clientID
+		coverage.Tiles = append(coverage.Tiles, tile)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating tiles: %w", err)
+	}
+
+	if len(coverage.Tiles) > limit {
+		coverage.Tiles = coverage.Tiles[:limit]
+		coverage.Truncated = true
+	}
+	return coverage, nil
+}
+
+// searchTextWeight is the share of a search score that comes from text
+// relevance when the search has an area; the rest comes from proximity to
+// its center.
+const searchTextWeight = 0.7
+
+// Search matches the query against search_vector (GIN) and the area against
+// location (GiST) in one statement. ts_rank_cd with normalization 32 maps
+// relevance into [0, 1); proximity is 1 at the center of the area and 0 at
+// its edge (the radius, or the distance from the box center to a corner).
+func (r *NoteRepo) Search(ctx context.Context, userID uuid.UUID, params repository.NoteSearchParams) ([]entity.SearchHit, error) {
+	conditions := []string{
+		"user_id = $1",
+		"deleted_at IS NULL",
+		"search_vector @@ websearch_to_tsquery('simple', $2)",
+	}
+	args := []any{userID, params.Query}
+	rank := "ts_rank_cd(search_vector, websearch_to_tsquery('simple', $2), 32)"
+
+	var center, extent string
+	switch {
+	case params.RadiusMeters > 0:
+		center = "ST_SetSRID(ST_MakePoint($3, $4), 4326)::geography"
+		extent = "$5"
+		conditions = append(conditions, "ST_DWithin(location, "+center+", $5)")
+		args = append(args, params.Longitude, params.Latitude, params.RadiusMeters)
+	case params.BoundingBox != nil:
+		bb := params.BoundingBox
+		envelope := "ST_MakeEnvelope($3, $4, $5, $6, 4326)"
+		center = "ST_Centroid(" + envelope + ")::geography"
+		extent = "ST_Distance(" + center + ", ST_SetSRID(ST_MakePoint($5, $6), 4326)::geography)"
+		conditions = append(conditions, "ST_Intersects(location, "+envelope+"::geography)")
+		args = append(args, bb.MinLng, bb.MinLat, bb.MaxLng, bb.MaxLat)
+	}
+
+	distance, score := "NULL::double precision", rank
+	if center != "" {
+		distance = "ST_Distance(location, " + center + ")"
+		score = fmt.Sprintf("%.2f * %s + %.2f * (1 - LEAST(%s / GREATEST(%s, 1), 1))",
+			searchTextWeight, rank, 1-searchTextWeight, distance, extent)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT `+noteColumns+`, %s AS score, %s AS distance
+		FROM notes
+		WHERE %s
+		ORDER BY score DESC, id
+		LIMIT $%d
+	`, score, distance, strings.Join(conditions, " AND "), len(args)+1)
+	args = append(args, params.Limit)
+
+	rows, err := r.reader(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("searching notes: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []entity.SearchHit
+	for rows.Next() {
+		var hit entity.SearchHit
+		note, err := scanNoteRow(rows, &hit.Score, &hit.Distance)
+		if err != nil {
+			return nil, fmt.Errorf("scanning note: %w", err)
 		}
-		notes = append(notes, note)
+		hit.Note = *note
+		hits = append(hits, hit)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, nil, fmt.Errorf("iterating notes: %w", err)
+		return nil, fmt.Errorf("iterating notes: %w", err)
 	}
 
-	pageInfo := pagination.NewInfo(params.Pagination.Page, params.Pagination.PerPage, total)
-	return notes, pageInfo, nil
+	return hits, nil
 }
 
-func (r *NoteRepo) Update(ctx context.Context, note *entity.Note) error {
-	query := `
-		UPDATE notes
-		SET title = $2, content = $3,
-			location = ST_SetSRID(ST_MakePoint($4, $5), 4326)::geography,
-			altitude = $6, accuracy = $7, updated_at = $8, deleted_at = $9
-		WHERE id = $1
-	`
+// Export reads the notes matching params in a read-only repeatable read
+// transaction, so the export is one consistent snapshot. It seeks on number
+// batch by batch, so only one batch is held in memory at a time.
+// Pagination in params is ignored.
+func (r *NoteRepo) Export(ctx context.Context, userID uuid.UUID, params repository.NoteListParams, batchSize int, fn func([]entity.Note) error) error {
+	tx, err := r.reader(ctx).BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	conditions, args := noteListConditions(userID, params)
+	argNum := len(args) + 1
+	query := fmt.Sprintf(`
+		SELECT `+noteColumns+`
+		FROM notes
+		WHERE %s AND number > $%d
+		ORDER BY number
+		LIMIT $%d
+	`, strings.Join(conditions, " AND "), argNum, argNum+1)
+
+	var after int64
+	for {
+		notes, err := queryNotes(ctx, tx, query, append(args, after, batchSize)...)
+		if err != nil {
+			return err
+		}
+		if len(notes) == 0 {
+			return nil
+		}
+		after = notes[len(notes)-1].Number
+		if err := fn(notes); err != nil {
+			return err
+		}
+		if len(notes) < batchSize {
+			return nil
+		}
+	}
+}
+
+const updateNoteQuery = `
+	UPDATE notes
+	SET title = $2, content = $3,
+		location = ST_SetSRID(ST_MakePoint($4, $5), 4326)::geography,
+		altitude = $6, accuracy = $7, last_modified_by_device = $8,
+		quality_status = $9, quality_passed = $10, quality_failed = $11, quality_checked_at = $12,
+		measurements = $13, sensitivity = $14, updated_at = $15, deleted_at = $16,
+		content_key = $17, content_url = $18, color = $19, icon = $20, auto_titled = $21,
+		field_updated_at = $22
+	WHERE id = $1 AND user_id = $23
+`
+
+// storedContentByIDQuery selects what storedContent reads by note ID and
+// owner.
+const storedContentByIDQuery = `SELECT id, content_key FROM notes WHERE id = $1 AND user_id = $2`
+
+// updateNoteArgs returns the arguments for updateNoteQuery.
+func updateNoteArgs(note *entity.Note, content noteContent) []any {
 	var lng, lat *float64
 	var altitude, accuracy *float64
 
@@ -202,27 +625,181 @@ func (r *NoteRepo) Update(ctx context.Context, note *entity.Note) error {
 		accuracy = note.Location.Accuracy
 	}
 
-	result, err := r.pool.Exec(ctx, query,
-		note.ID, note.Title, note.Content,
+	return []any{
+		note.ID, note.Title, content.content,
 		lng, lat, altitude, accuracy,
-		note.UpdatedAt, note.DeletedAt,
-	)
+		nullableString(note.LastModifiedByDevice),
+		qualityStatus(note.Quality), qualityRules(note.Quality.Passed), qualityRules(note.Quality.Failed), note.Quality.CheckedAt,
+		measurementRows(note.Measurements), sensitivity(note.Sensitivity), note.UpdatedAt, note.DeletedAt,
+		content.key, content.url, noteStyle(note.Color), noteStyle(note.Icon), note.AutoTitled, note.FieldTimes(),
+		note.UserID,
+	}
+}
+
+func (r *NoteRepo) Update(ctx context.Context, note *entity.Note) error {
+	_, previousKey, err := storedContent(ctx, r.pool, storedContentByIDQuery, note.ID, note.UserID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrNoteNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	content, err := r.prepareContent(ctx, note, note.ID, previousKey)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.pool.Exec(ctx, updateNoteQuery, updateNoteArgs(note, content)...)
+	if err != nil {
+		r.removeContent(ctx, content.uploadedKey())
+		return fmt.Errorf("updating note: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		r.removeContent(ctx, content.uploadedKey())
+		return domain.ErrNoteNotFound
+	}
+	// The object the old content was in stays: the revision kept of the
+	// old version points to it.
+	return nil
+}
+
+// SetTags replaces the note's tags and stores its updated_at and
+// last_modified_by_device, so devices pull the change on their next sync.
+func (r *NoteRepo) SetTags(ctx context.Context, note *entity.Note) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE notes SET last_modified_by_device = $2, updated_at = $3 WHERE id = $1 AND user_id = $4`
+	result, err := tx.Exec(ctx, query, note.ID, nullableString(note.LastModifiedByDevice), note.UpdatedAt, note.UserID)
+	if err != nil {
+		return fmt.Errorf("updating note: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrNoteNotFound
+	}
+
+	if err := replaceTags(ctx, tx, note); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+// replaceTags makes note.Tags the note's full tag set, creating tags missing
+// from the owner's vocabulary. Tags belong to the note's owner, so tags added
+// by a collaborator end up in the owner's list.
+func replaceTags(ctx context.Context, tx pgx.Tx, note *entity.Note) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM note_tags WHERE note_id = $1`, note.ID); err != nil {
+		return fmt.Errorf("clearing note tags: %w", err)
+	}
+	if len(note.Tags) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO tags (user_id, name)
+		SELECT $1, unnest($2::text[])
+		ON CONFLICT (user_id, name) DO NOTHING
+	`
+	if _, err := tx.Exec(ctx, query, note.UserID, note.Tags); err != nil {
+		return fmt.Errorf("creating tags: %w", err)
+	}
+
+	query = `
+		INSERT INTO note_tags (note_id, tag_id)
+		SELECT $1, id FROM tags WHERE user_id = $2 AND name = ANY($3)
+	`
+	if _, err := tx.Exec(ctx, query, note.ID, note.UserID, note.Tags); err != nil {
+		return fmt.Errorf("tagging note: %w", err)
+	}
+	return nil
+}
+
+// Merge saves the surviving note with its tags, moves the photos of the
+// merged notes to it and deletes the merged notes, recording where they went.
+func (r *NoteRepo) Merge(ctx context.Context, survivor *entity.Note, mergedIDs []uuid.UUID) error {
+	_, previousKey, err := storedContent(ctx, r.pool, storedContentByIDQuery, survivor.ID, survivor.UserID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrNoteNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	content, err := r.prepareContent(ctx, survivor, survivor.ID, previousKey)
+	if err != nil {
+		return err
+	}
+
+	if err := r.merge(ctx, survivor, content, mergedIDs); err != nil {
+		r.removeContent(ctx, content.uploadedKey())
+		return err
+	}
+	return nil
+}
+
+func (r *NoteRepo) merge(ctx context.Context, survivor *entity.Note, content noteContent, mergedIDs []uuid.UUID) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, updateNoteQuery, updateNoteArgs(survivor, content)...)
 	if err != nil {
 		return fmt.Errorf("updating note: %w", err)
 	}
 	if result.RowsAffected() == 0 {
 		return domain.ErrNoteNotFound
 	}
+
+	// Merged notes have the survivor's owner, so their photos are in its
+	// partition.
+	if _, err := tx.Exec(ctx, `UPDATE photos SET note_id = $1 WHERE user_id = $3 AND note_id = ANY($2)`, survivor.ID, mergedIDs, survivor.UserID); err != nil {
+		return fmt.Errorf("moving photos: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE attachments SET note_id = $1 WHERE note_id = ANY($2)`, survivor.ID, mergedIDs); err != nil {
+		return fmt.Errorf("moving attachments: %w", err)
+	}
+
+	if err := replaceTags(ctx, tx, survivor); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE notes
+		SET deleted_at = $2, updated_at = $2, merged_into = $1
+		WHERE id = ANY($3) AND user_id = $4 AND deleted_at IS NULL
+	`
+	result, err = tx.Exec(ctx, query, survivor.ID, survivor.UpdatedAt, mergedIDs, survivor.UserID)
+	if err != nil {
+		return fmt.Errorf("deleting merged notes: %w", err)
+	}
+	if result.RowsAffected() != int64(len(mergedIDs)) {
+		return domain.ErrNoteNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
 	return nil
 }
 
-func (r *NoteRepo) SoftDelete(ctx context.Context, id uuid.UUID) error {
+func (r *NoteRepo) SoftDelete(ctx context.Context, userID, id uuid.UUID) error {
 	query := `
 		UPDATE notes
-		SET deleted_at = NOW(), updated_at = NOW()
-		WHERE id = $1 AND deleted_at IS NULL
+		SET deleted_at = $2, updated_at = $2
+		WHERE id = $1 AND user_id = $3 AND deleted_at IS NULL
 	`
-	result, err := r.pool.Exec(ctx, query, id)
+	result, err := r.pool.Exec(ctx, query, id, r.clock.Now(), userID)
 	if err != nil {
 		return fmt.Errorf("soft deleting note: %w", err)
 	}
@@ -232,46 +809,169 @@ func (r *NoteRepo) SoftDelete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-func (r *NoteRepo) GetModifiedSince(ctx context.Context, userID uuid.UUID, since time.Time, limit int) ([]entity.Note, error) {
+// Purge hard-deletes every note of the user with its photos, tags and
+// shares, and restarts the user's note numbering.
+func (r *NoteRepo) Purge(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// notes_cascade_delete removes the photos, tags and shares of every
+	// deleted note in one pass per statement.
+	rows, err := tx.Query(ctx, `DELETE FROM notes WHERE user_id = $1 RETURNING content_key`, userID)
+	if err != nil {
+		return fmt.Errorf("deleting notes: %w", err)
+	}
+	contentKeys, err := pgx.CollectRows(rows, pgx.RowTo[*string])
+	if err != nil {
+		return fmt.Errorf("deleting notes: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE users SET last_note_number = 0 WHERE id = $1`, userID); err != nil {
+		return fmt.Errorf("resetting note numbers: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	for _, key := range contentKeys {
+		if key != nil {
+			r.removeContent(ctx, *key)
+		}
+	}
+	return nil
+}
+
+func (r *NoteRepo) PurgeDeleted(ctx context.Context, before time.Time, limit int) (int64, error) {
+	// notes_cascade_delete removes the photos, attachments, tags and shares
+	// and queues their storage objects in storage_orphans.
 	query := `
-		SELECT id, user_id, title, content,
-			   ST_Y(location::geometry) as lat, ST_X(location::geometry) as lng,
-			   altitude, accuracy, client_id, created_at, updated_at, deleted_at
+		DELETE FROM notes
+		WHERE (id, user_id) IN (
+			SELECT id, user_id FROM notes
+			WHERE deleted_at < $1
+			LIMIT $2
+		)
+	`
+	result, err := r.pool.Exec(ctx, query, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("purging deleted notes: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+func (r *NoteRepo) UpdateQuality(ctx context.Context, userID, id uuid.UUID, result entity.QualityResult) error {
+	query := `
+		UPDATE notes
+		SET quality_status = $2, quality_passed = $3, quality_failed = $4, quality_checked_at = $5
+		WHERE id = $1 AND user_id = $6
+	`
+	res, err := r.pool.Exec(ctx, query, id,
+		qualityStatus(result), qualityRules(result.Passed), qualityRules(result.Failed), result.CheckedAt, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating note quality: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrNoteNotFound
+	}
+	return nil
+}
+
+func (r *NoteRepo) GetQualityReport(ctx context.Context, userID uuid.UUID) (*entity.QualityReport, error) {
+	report := &entity.QualityReport{RuleFailures: make(map[string]int)}
+
+	countQuery := `
+		SELECT COUNT(*),
+			   COUNT(*) FILTER (WHERE quality_status = 'unchecked'),
+			   COUNT(*) FILTER (WHERE quality_status = 'passed'),
+			   COUNT(*) FILTER (WHERE quality_status = 'failed')
 		FROM notes
-		WHERE user_id = $1 AND updated_at > $2
-		ORDER BY updated_at ASC
-		LIMIT $3
+		WHERE user_id = $1 AND deleted_at IS NULL
 	`
-	rows, err := r.pool.Query(ctx, query, userID, since, limit)
+	if err := r.pool.QueryRow(ctx, countQuery, userID).Scan(
+		&report.Total, &report.Unchecked, &report.Passed, &report.Failed,
+	); err != nil {
+		return nil, fmt.Errorf("counting note quality: %w", err)
+	}
+
+	rulesQuery := `
+		SELECT rule, COUNT(*)
+		FROM notes, unnest(quality_failed) AS rule
+		WHERE user_id = $1 AND deleted_at IS NULL
+		GROUP BY rule
+	`
+	rows, err := r.pool.Query(ctx, rulesQuery, userID)
 	if err != nil {
-		return nil, fmt.Errorf("querying modified notes: %w", err)
+		return nil, fmt.Errorf("querying rule failures: %w", err)
 	}
 	defer rows.Close()
 
-	var notes []entity.Note
 	for rows.Next() {
-		var note entity.Note
-		var lat, lng, altitude, accuracy *float64
-		var clientID *string
-
-		if err := rows.Scan(
-			&note.ID, &note.UserID, &note.Title, &note.Content,
-			&lat, &lng, &altitude, &accuracy,
-			&clientID, &note.CreatedAt, &note.UpdatedAt, &note.DeletedAt,
-		); err != nil {
-			return nil, fmt.Errorf("scanning note: %w", err)
+		var rule string
+		var count int
+		if err := rows.Scan(&rule, &count); err != nil {
+			return nil, fmt.Errorf("scanning rule failures: %w", err)
 		}
+		report.RuleFailures[rule] = count
+	}
+
+	return report, rows.Err()
+}
+
+func (r *NoteRepo) GetModifiedAfter(ctx context.Context, userID uuid.UUID, after pagination.Cursor, limit int, scope entity.SyncScope) ([]entity.Note, error) {
+	query := `
+		SELECT ` + noteColumns + `
+		FROM notes
+		WHERE (user_id = $1 OR team_id IN (
+				SELECT team_id FROM team_members WHERE user_id = $1 AND accepted_at IS NOT NULL))
+		  AND (updated_at, id) > ($2, $3)
+		  AND ($5::timestamptz IS NULL OR created_at >= $5)
+		ORDER BY updated_at ASC, id ASC
+		LIMIT $4
+	`
+	return queryNotes(ctx, r.pool, query, userID, after.UpdatedAt, after.ID, limit, scope.NotesSince)
+}
+
+func (r *NoteRepo) GetByClientIDs(ctx context.Context, userID uuid.UUID, clientIDs []string) ([]entity.Note, error) {
+	query := `
+		SELECT ` + noteColumns + `
+		FROM notes
+		WHERE user_id = $1 AND client_id = ANY($2)
+	`
+	return queryNotes(ctx, r.pool, query, userID, clientIDs)
+}
+
+func (r *NoteRepo) Existing(ctx context.Context, userID uuid.UUID, ids []uuid.UUID, clientIDs []string) ([]entity.NoteExistence, error) {
+	query := `
+		SELECT id, client_id, updated_at, deleted_at
+		FROM notes
+		WHERE user_id = $1 AND (id = ANY($2) OR client_id = ANY($3))
+	`
+	rows, err := r.pool.Query(ctx, query, userID, ids, clientIDs)
+	if err != nil {
+		return nil, fmt.Errorf("querying existing notes: %w", err)
+	}
+	defer rows.Close()
 
-		if lat != nil && lng != nil {
-			note.Location = valueobject.NewLocation(*lat, *lng, altitude, accuracy)
+	var existing []entity.NoteExistence
+	for rows.Next() {
+		var e entity.NoteExistence
+		var clientID *string
+		if err := rows.Scan(&e.ID, &clientID, &e.UpdatedAt, &e.DeletedAt); err != nil {
+			return nil, fmt.Errorf("scanning existing note: %w", err)
 		}
 		if clientID != nil {
-			note.ClientID = *clientID
+			e.ClientID = *clientID
 		}
-		notes = append(notes, note)
+		existing = append(existing, e)
 	}
-
-	return notes, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating existing notes: %w", err)
+	}
+	return existing, nil
 }
 
 func (r *NoteRepo) BatchUpsert(ctx context.Context, notes []entity.Note) error {
@@ -285,7 +985,39 @@ func (r *NoteRepo) BatchUpsert(ctx context.Context, notes []entity.Note) error {
 	}
 	defer tx.Rollback(ctx)
 
-	for _, note := range notes {
+	// Objects uploaded for the batch are removed again unless it commits;
+	// those of skipped notes are removed once it has. Replaced objects stay
+	// with the revisions of the versions they held.
+	var uploaded, unreferenced []string
+	committed := false
+	defer func() {
+		if !committed {
+			r.removeContent(ctx, uploaded...)
+		}
+	}()
+
+	for i := range notes {
+		note := &notes[i]
+		if err := keepOrReserveNumber(ctx, tx, note); err != nil {
+			return err
+		}
+
+		rowID, previousKey, err := storedContent(ctx, tx,
+			`SELECT id, content_key FROM notes WHERE user_id = $1 AND client_id = $2`, note.UserID, note.ClientID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			rowID = note.ID
+		} else if err != nil {
+			return err
+		}
+
+		content, err := r.prepareContent(ctx, note, rowID, previousKey)
+		if err != nil {
+			return err
+		}
+		if key := content.uploadedKey(); key != "" {
+			uploaded = append(uploaded, key)
+		}
+
 		var lng, lat *float64
 		var altitude, accuracy *float64
 
@@ -297,8 +1029,15 @@ func (r *NoteRepo) BatchUpsert(ctx context.Context, notes []entity.Note) error {
 		}
 
 		query := `
-			INSERT INTO notes (id, user_id, title, content, location, altitude, accuracy, client_id, created_at, updated_at, deleted_at)
-			VALUES ($1, $2, $3, $4, ST_SetSRID(ST_MakePoint($5, $6), 4326)::geography, $7, $8, $9, $10, $11, $12)
+			INSERT INTO notes (id, user_id, number, reference, title, content, location, altitude, accuracy, client_id,
+							   created_by_device, last_modified_by_device,
+							   quality_status, quality_passed, quality_failed, quality_checked_at,
+							   measurements, created_at, updated_at, deleted_at, content_key, content_url,
+							   source, source_meta, synced_by_device, synced_updated_at, author, color, icon, auto_titled,
+							   field_updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, ST_SetSRID(ST_MakePoint($7, $8), 4326)::geography, $9, $10, $11, $12, $13,
+					$14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27,
+					` + deviceAuthor + `, NULLIF($28::text, ''), NULLIF($29::text, ''), $30, $31)
 			ON CONFLICT (user_id, client_id)
 			DO UPDATE SET
 				title = EXCLUDED.title,
@@ -306,27 +1045,220 @@ func (r *NoteRepo) BatchUpsert(ctx context.Context, notes []entity.Note) error {
 				location = EXCLUDED.location,
 				altitude = EXCLUDED.altitude,
 				accuracy = EXCLUDED.accuracy,
+				last_modified_by_device = EXCLUDED.last_modified_by_device,
+				quality_status = EXCLUDED.quality_status,
+				quality_passed = EXCLUDED.quality_passed,
+				quality_failed = EXCLUDED.quality_failed,
+				quality_checked_at = EXCLUDED.quality_checked_at,
+				measurements = EXCLUDED.measurements,
 				updated_at = EXCLUDED.updated_at,
-				deleted_at = EXCLUDED.deleted_at
+				deleted_at = EXCLUDED.deleted_at,
+				content_key = EXCLUDED.content_key,
+				content_url = EXCLUDED.content_url,
+				synced_by_device = EXCLUDED.synced_by_device,
+				synced_updated_at = EXCLUDED.synced_updated_at,
+				-- Clients that predate colors and icons send neither;
+				-- keep what is stored.
+				color = CASE WHEN $28::text IS NULL THEN notes.color ELSE EXCLUDED.color END,
+				icon = CASE WHEN $29::text IS NULL THEN notes.icon ELSE EXCLUDED.icon END,
+				auto_titled = EXCLUDED.auto_titled,
+				field_updated_at = EXCLUDED.field_updated_at
 			WHERE notes.updated_at < EXCLUDED.updated_at
+			RETURNING id, author
 		`
-		_, err := tx.Exec(ctx, query,
-			note.ID, note.UserID, note.Title, note.Content,
+		var syncedBy *uuid.UUID
+		var syncedAt *time.Time
+		if note.SyncedFrom != nil {
+			syncedBy, syncedAt = &note.SyncedFrom.DeviceID, &note.SyncedFrom.UpdatedAt
+		}
+
+		var author *string
+		err = tx.QueryRow(ctx, query,
+			note.ID, note.UserID, note.Number, note.Reference, note.Title, content.content,
 			lng, lat, altitude, accuracy,
-			nullableString(note.ClientID), note.CreatedAt, note.UpdatedAt, note.DeletedAt,
-		)
+			nullableString(note.ClientID), nullableString(note.CreatedByDevice), nullableString(note.LastModifiedByDevice),
+			qualityStatus(note.Quality), qualityRules(note.Quality.Passed), qualityRules(note.Quality.Failed), note.Quality.CheckedAt,
+			measurementRows(note.Measurements), note.CreatedAt, note.UpdatedAt, note.DeletedAt,
+			content.key, content.url, noteSource(note.Source, entity.NoteSourceSync), note.SourceMeta,
+			syncedBy, syncedAt, note.Color, note.Icon, note.AutoTitled, note.FieldTimes(),
+		).Scan(&note.ID, &author)
+		if errors.Is(err, pgx.ErrNoRows) {
+			// The stored version is newer; its tags and content stay too.
+			unreferenced = append(unreferenced, content.uploadedKey())
+			continue
+		}
 		if err != nil {
 			return fmt.Errorf("upserting note: %w", err)
 		}
+		if author != nil {
+			note.Author = *author
+		}
+
+		// Clients that predate tags send none; keep what is stored.
+		if note.Tags != nil {
+			if err := replaceTags(ctx, tx, note); err != nil {
+				return err
+			}
+		}
 	}
 
 	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("committing transaction: %w", err)
 	}
+	committed = true
 
+	r.removeContent(ctx, unreferenced...)
+	return nil
+}
+
+// keepOrReserveNumber gives a synced note the number already stored for its
+// client ID, so every device sees the same number, or reserves a new one.
+func keepOrReserveNumber(ctx context.Context, tx pgx.Tx, note *entity.Note) error {
+	query := `SELECT number, reference FROM notes WHERE user_id = $1 AND client_id = $2`
+	err := tx.QueryRow(ctx, query, note.UserID, note.ClientID).Scan(&note.Number, &note.Reference)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return reserveNoteNumber(ctx, tx, note)
+	}
+	if err != nil {
+		return fmt.Errorf("querying note number: %w", err)
+	}
 	return nil
 }
 
+const noteColumns = `id, user_id, number, reference, title, content, content_key, content_url,
+			   ST_Y(location::geometry) as lat, ST_X(location::geometry) as lng,
+			   altitude, accuracy, client_id, created_by_device, last_modified_by_device,
+			   quality_status, quality_passed, quality_failed, quality_checked_at,
+			   measurements, sensitivity, merged_into, team_id, author, created_at, updated_at, deleted_at,
+			   source, source_meta, synced_by_device, synced_updated_at, color, icon, auto_titled, field_updated_at,
+			   EXISTS(SELECT 1 FROM note_documents d WHERE d.note_id = notes.id) AS collaborative,
+			   COALESCE((SELECT array_agg(t.name ORDER BY t.name)
+						 FROM note_tags nt JOIN tags t ON t.id = nt.tag_id
+						 WHERE nt.note_id = notes.id), '{}') AS tags,
+			   COALESCE((SELECT jsonb_agg(jsonb_build_object(
+							'id', a.id, 'kind', a.kind, 'url', a.url, 'key', a.key, 'mime_type', a.mime_type,
+							'size', a.size, 'duration_ms', a.duration_ms, 'checksum', COALESCE(a.checksum, ''),
+							'client_id', COALESCE(a.client_id, ''), 'created_at', a.created_at)
+							ORDER BY a.created_at, a.id)
+						 FROM attachments a
+						 WHERE a.user_id = notes.user_id AND a.note_id = notes.id), '[]') AS attachments`
+
+// scanNoteRow scans a row selected with noteColumns, followed by any extra
+// columns into extra.
+func scanNoteRow(row pgx.Row, extra ...any) (*entity.Note, error) {
+	var note entity.Note
+	var lat, lng, altitude, accuracy *float64
+	var contentKey, contentURL, clientID, createdBy, modifiedBy, author *string
+	var measurements []measurementRow
+	var attachments []attachmentRow
+	var syncedBy *uuid.UUID
+	var syncedAt *time.Time
+
+	dest := []any{
+		&note.ID, &note.UserID, &note.Number, &note.Reference, &note.Title, &note.Content, &contentKey, &contentURL,
+		&lat, &lng, &altitude, &accuracy,
+		&clientID, &createdBy, &modifiedBy,
+		&note.Quality.Status, &note.Quality.Passed, &note.Quality.Failed, &note.Quality.CheckedAt,
+		&measurements, &note.Sensitivity, &note.MergedInto, &note.TeamID, &author, &note.CreatedAt, &note.UpdatedAt, &note.DeletedAt,
+		&note.Source, &note.SourceMeta, &syncedBy, &syncedAt, &note.Color, &note.Icon, &note.AutoTitled, &note.FieldsUpdatedAt,
+		&note.Collaborative,
+		&note.Tags, &attachments,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	if lat != nil && lng != nil {
+		note.Location = valueobject.NewLocation(*lat, *lng, altitude, accuracy)
+	}
+	if contentKey != nil {
+		note.ContentKey = *contentKey
+		note.ContentExcerpt = true
+	}
+	if contentURL != nil {
+		note.ContentURL = *contentURL
+	}
+	if clientID != nil {
+		note.ClientID = *clientID
+	}
+	if createdBy != nil {
+		note.CreatedByDevice = *createdBy
+	}
+	if modifiedBy != nil {
+		note.LastModifiedByDevice = *modifiedBy
+	}
+	if author != nil {
+		note.Author = *author
+	}
+	if syncedBy != nil && syncedAt != nil {
+		note.SyncedFrom = &entity.NoteSyncOrigin{DeviceID: *syncedBy, UpdatedAt: *syncedAt}
+	}
+	for _, m := range measurements {
+		note.Measurements = append(note.Measurements, valueobject.Measurement{Name: m.Name, Kind: m.Kind, Value: m.Value})
+	}
+	for _, a := range attachments {
+		note.Attachments = append(note.Attachments, a.toEntity(note.ID))
+	}
+
+	return &note, nil
+}
+
+// measurementRow is the JSON shape of a measurement in notes.measurements.
+// Values are in SI units.
+type measurementRow struct {
+	Name  string  `json:"name"`
+	Kind  string  `json:"kind"`
+	Value float64 `json:"value"`
+}
+
+func measurementRows(measurements []valueobject.Measurement) []measurementRow {
+	rows := make([]measurementRow, 0, len(measurements))
+	for _, m := range measurements {
+		rows = append(rows, measurementRow{Name: m.Name, Kind: m.Kind, Value: m.Value})
+	}
+	return rows
+}
+
+func qualityStatus(result entity.QualityResult) string {
+	if result.Status == "" {
+		return entity.QualityUnchecked
+	}
+	return result.Status
+}
+
+// noteStyle stores an empty color or icon as none.
+func noteStyle(name *string) *string {
+	if name == nil {
+		return nil
+	}
+	return nullableString(*name)
+}
+
+// sensitivity stores notes built without a level as not sensitive.
+func sensitivity(level string) string {
+	if level == "" {
+		return entity.SensitivityNone
+	}
+	return level
+}
+
+// noteSource stores notes built without a source as coming from fallback,
+// the path writing them.
+func noteSource(source, fallback string) string {
+	if source == "" {
+		return fallback
+	}
+	return source
+}
+
+// qualityRules keeps rule lists non-nil for the NOT NULL array columns.
+func qualityRules(rules []string) []string {
+	if rules == nil {
+		return []string{}
+	}
+	return rules
+}
+
 func nullableString(s string) *string {
 	if s == "" {
 		return nil
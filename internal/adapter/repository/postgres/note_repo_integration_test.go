@@ -2,6 +2,10 @@ package postgres_test
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -30,7 +34,7 @@ func TestIntegrationNoteRepo_Create(t *testing.T) {
 	db := SetupTestDB(t)
 	defer db.Cleanup(t)
 
-	repo := postgres.NewNoteRepo(db.Pool)
+	repo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
 	ctx := context.Background()
 
 	t.Run("creates note successfully", func(t *testing.T) {
@@ -45,6 +49,16 @@ func TestIntegrationNoteRepo_Create(t *testing.T) {
 		assert.NotEmpty(t, note.ID)
 	})
 
+	t.Run("fails with duplicate client_id for same user", func(t *testing.T) {
+		db.Truncate(t, "notes", "users")
+		user := createTestUser(t, db)
+
+		require.NoError(t, repo.Create(ctx, entity.NewNote(user.ID, "First", "Content", nil, "client-123")))
+		err := repo.Create(ctx, entity.NewNote(user.ID, "Second", "Content", nil, "client-123"))
+
+		assert.ErrorIs(t, err, domain.ErrNoteAlreadyExists)
+	})
+
 	t.Run("creates note with location", func(t *testing.T) {
 		db.Truncate(t, "notes", "users")
 		user := createTestUser(t, db)
@@ -61,13 +75,31 @@ func TestIntegrationNoteRepo_Create(t *testing.T) {
 		assert.Equal(t, 37.7749, found.Location.Latitude)
 		assert.Equal(t, -122.4194, found.Location.Longitude)
 	})
+
+	t.Run("assigns sequential numbers per user", func(t *testing.T) {
+		db.Truncate(t, "notes", "users")
+		user := createTestUser(t, db)
+
+		first := entity.NewNote(user.ID, "First", "Content", nil, "")
+		second := entity.NewNote(user.ID, "Second", "Content", nil, "")
+		require.NoError(t, repo.Create(ctx, first))
+		require.NoError(t, repo.Create(ctx, second))
+
+		assert.Equal(t, int64(1), first.Number)
+		assert.Equal(t, "NOTE-0001", first.Reference)
+		assert.Equal(t, int64(2), second.Number)
+
+		found, err := repo.GetByID(ctx, second.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "NOTE-0002", found.Reference)
+	})
 }
 
 func TestIntegrationNoteRepo_GetByID(t *testing.T) {
 	db := SetupTestDB(t)
 	defer db.Cleanup(t)
 
-	repo := postgres.NewNoteRepo(db.Pool)
+	repo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
 	ctx := context.Background()
 
 	t.Run("returns note by ID", func(t *testing.T) {
@@ -85,6 +117,27 @@ func TestIntegrationNoteRepo_GetByID(t *testing.T) {
 		assert.Equal(t, "Test Note", found.Title)
 	})
 
+	t.Run("keeps field times", func(t *testing.T) {
+		db.Truncate(t, "notes", "users")
+		user := createTestUser(t, db)
+
+		note := entity.NewNote(user.ID, "Oak", "Three oaks", nil, "")
+		require.NoError(t, repo.Create(ctx, note))
+
+		found, err := repo.GetByID(ctx, note.ID)
+		require.NoError(t, err)
+		assert.True(t, found.FieldsUpdatedAt.Title.Equal(note.UpdatedAt), "unset times are stored as updated_at")
+
+		later := found.UpdatedAt.Add(time.Minute)
+		found.UpdateAt("Oak", "Four oaks", nil, later)
+		require.NoError(t, repo.Update(ctx, found))
+
+		found, err = repo.GetByID(ctx, note.ID)
+		require.NoError(t, err)
+		assert.True(t, found.FieldsUpdatedAt.Title.Equal(note.UpdatedAt))
+		assert.True(t, found.FieldsUpdatedAt.Content.Equal(later))
+	})
+
 	t.Run("returns not found error", func(t *testing.T) {
 		db.Truncate(t, "notes", "users")
 
@@ -99,7 +152,7 @@ func TestIntegrationNoteRepo_GetByClientID(t *testing.T) {
 	db := SetupTestDB(t)
 	defer db.Cleanup(t)
 
-	repo := postgres.NewNoteRepo(db.Pool)
+	repo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
 	ctx := context.Background()
 
 	t.Run("returns note by client ID", func(t *testing.T) {
@@ -131,11 +184,70 @@ func TestIntegrationNoteRepo_GetByClientID(t *testing.T) {
 	})
 }
 
+func TestIntegrationNoteRepo_GetByClientIDs(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
+	ctx := context.Background()
+
+	t.Run("returns matching notes including deleted ones", func(t *testing.T) {
+		db.Truncate(t, "notes", "users")
+		user := createTestUser(t, db)
+
+		kept := entity.NewNote(user.ID, "Kept", "Content", nil, "kept")
+		require.NoError(t, repo.Create(ctx, kept))
+		deleted := entity.NewNote(user.ID, "Deleted", "Content", nil, "deleted")
+		require.NoError(t, repo.Create(ctx, deleted))
+		require.NoError(t, repo.SoftDelete(ctx, deleted.UserID, deleted.ID))
+		other := entity.NewNote(user.ID, "Other", "Content", nil, "other")
+		require.NoError(t, repo.Create(ctx, other))
+
+		notes, err := repo.GetByClientIDs(ctx, user.ID, []string{"kept", "deleted", "missing"})
+
+		require.NoError(t, err)
+		assert.Len(t, notes, 2)
+	})
+}
+
+func TestIntegrationNoteRepo_Existing(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
+	ctx := context.Background()
+
+	db.Truncate(t, "notes", "users")
+	user := createTestUser(t, db)
+	stranger := entity.NewUser("stranger@example.com", "hashedpassword", "Stranger")
+	require.NoError(t, postgres.NewUserRepo(db.Pool).Create(ctx, stranger))
+
+	byID := entity.NewNote(user.ID, "By ID", "Content", nil, "")
+	require.NoError(t, repo.Create(ctx, byID))
+	deleted := entity.NewNote(user.ID, "Deleted", "Content", nil, "deleted")
+	require.NoError(t, repo.Create(ctx, deleted))
+	require.NoError(t, repo.SoftDelete(ctx, deleted.UserID, deleted.ID))
+	foreign := entity.NewNote(stranger.ID, "Foreign", "Content", nil, "foreign")
+	require.NoError(t, repo.Create(ctx, foreign))
+
+	existing, err := repo.Existing(ctx, user.ID, []uuid.UUID{byID.ID, foreign.ID, uuid.New()}, []string{"deleted", "foreign", "missing"})
+
+	require.NoError(t, err)
+	require.Len(t, existing, 2)
+	got := map[uuid.UUID]entity.NoteExistence{}
+	for _, e := range existing {
+		got[e.ID] = e
+	}
+	assert.Nil(t, got[byID.ID].DeletedAt)
+	assert.Equal(t, "deleted", got[deleted.ID].ClientID)
+	assert.NotNil(t, got[deleted.ID].DeletedAt)
+}
+
 func TestIntegrationNoteRepo_List(t *testing.T) {
 	db := SetupTestDB(t)
 	defer db.Cleanup(t)
 
-	repo := postgres.NewNoteRepo(db.Pool)
+	repo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
 	ctx := context.Background()
 
 	t.Run("lists notes with pagination", func(t *testing.T) {
@@ -169,7 +281,7 @@ func TestIntegrationNoteRepo_List(t *testing.T) {
 		note2 := entity.NewNote(user.ID, "Deleted Note", "Content", nil, "")
 		err = repo.Create(ctx, note2)
 		require.NoError(t, err)
-		err = repo.SoftDelete(ctx, note2.ID)
+		err = repo.SoftDelete(ctx, note2.UserID, note2.ID)
 		require.NoError(t, err)
 
 		notes, info, err := repo.List(ctx, user.ID, repository.NoteListParams{
@@ -209,13 +321,394 @@ func TestIntegrationNoteRepo_List(t *testing.T) {
 		assert.Len(t, notes, 1)
 		assert.Equal(t, "SF Note", notes[0].Title)
 	})
+
+	t.Run("filters by device", func(t *testing.T) {
+		db.Truncate(t, "notes", "users")
+		user := createTestUser(t, db)
+
+		phoneNote := entity.NewNote(user.ID, "Phone Note", "Content", nil, "phone-1")
+		phoneNote.SetOriginDevice("phone")
+		require.NoError(t, repo.Create(ctx, phoneNote))
+
+		tabletNote := entity.NewNote(user.ID, "Tablet Note", "Content", nil, "tablet-1")
+		tabletNote.SetOriginDevice("tablet")
+		require.NoError(t, repo.Create(ctx, tabletNote))
+
+		tabletNote.MarkModifiedBy("phone")
+		require.NoError(t, repo.Update(ctx, tabletNote))
+
+		notes, _, err := repo.List(ctx, user.ID, repository.NoteListParams{
+			Pagination: pagination.Params{Page: 1, PerPage: 10},
+			DeviceID:   "phone",
+		})
+		require.NoError(t, err)
+		assert.Len(t, notes, 2)
+
+		notes, _, err = repo.List(ctx, user.ID, repository.NoteListParams{
+			Pagination: pagination.Params{Page: 1, PerPage: 10},
+			DeviceID:   "tablet",
+		})
+		require.NoError(t, err)
+		require.Len(t, notes, 1)
+		assert.Equal(t, "tablet", notes[0].CreatedByDevice)
+		assert.Equal(t, "phone", notes[0].LastModifiedByDevice)
+	})
+
+	t.Run("filters by quality status", func(t *testing.T) {
+		db.Truncate(t, "notes", "users")
+		user := createTestUser(t, db)
+
+		passing := entity.NewNote(user.ID, "Complete", "Content", nil, "")
+		require.NoError(t, repo.Create(ctx, passing))
+		require.NoError(t, repo.Create(ctx, entity.NewNote(user.ID, "Unchecked", "Content", nil, "")))
+
+		rules := entity.QualityRules{MinContentLength: 3}
+		require.NoError(t, repo.UpdateQuality(ctx, passing.UserID, passing.ID, rules.Evaluate(passing, 0)))
+
+		notes, _, err := repo.List(ctx, user.ID, repository.NoteListParams{
+			Pagination:    pagination.Params{Page: 1, PerPage: 10},
+			QualityStatus: entity.QualityPassed,
+		})
+		require.NoError(t, err)
+		require.Len(t, notes, 1)
+		assert.Equal(t, passing.ID, notes[0].ID)
+		assert.Equal(t, []string{entity.QualityRuleMinContentLength}, notes[0].Quality.Passed)
+		assert.NotNil(t, notes[0].Quality.CheckedAt)
+	})
+
+	t.Run("filters by number and reference", func(t *testing.T) {
+		db.Truncate(t, "notes", "users")
+		user := createTestUser(t, db)
+
+		require.NoError(t, repo.Create(ctx, entity.NewNote(user.ID, "First", "Content", nil, "")))
+		second := entity.NewNote(user.ID, "Second", "Content", nil, "")
+		require.NoError(t, repo.Create(ctx, second))
+
+		notes, _, err := repo.List(ctx, user.ID, repository.NoteListParams{
+			Pagination: pagination.Params{Page: 1, PerPage: 10},
+			Number:     2,
+		})
+		require.NoError(t, err)
+		require.Len(t, notes, 1)
+		assert.Equal(t, second.ID, notes[0].ID)
+
+		notes, _, err = repo.List(ctx, user.ID, repository.NoteListParams{
+			Pagination: pagination.Params{Page: 1, PerPage: 10},
+			Reference:  "NOTE-0002",
+		})
+		require.NoError(t, err)
+		require.Len(t, notes, 1)
+		assert.Equal(t, second.ID, notes[0].ID)
+	})
+
+	t.Run("filters by source", func(t *testing.T) {
+		db.Truncate(t, "notes", "users")
+		user := createTestUser(t, db)
+
+		require.NoError(t, repo.Create(ctx, entity.NewNote(user.ID, "By hand", "Content", nil, "")))
+		integrated := entity.NewNote(user.ID, "Rainfall", "12 mm", nil, "")
+		integrated.Source = entity.IntegrationSource("weather-station")
+		integrated.SourceMeta = map[string]any{"station": "WS-12"}
+		require.NoError(t, repo.Create(ctx, integrated))
+		synced := entity.NewNote(user.ID, "Synced", "Content", nil, "client-1")
+		synced.Source = entity.NoteSourceSync
+		require.NoError(t, repo.BatchUpsert(ctx, []entity.Note{*synced}))
+
+		notes, _, err := repo.List(ctx, user.ID, repository.NoteListParams{
+			Pagination: pagination.Params{Page: 1, PerPage: 10},
+			Source:     entity.NoteSourceIntegration,
+		})
+		require.NoError(t, err)
+		require.Len(t, notes, 1)
+		assert.Equal(t, "integration:weather-station", notes[0].Source)
+		assert.Equal(t, map[string]any{"station": "WS-12"}, notes[0].SourceMeta)
+
+		notes, _, err = repo.List(ctx, user.ID, repository.NoteListParams{
+			Pagination: pagination.Params{Page: 1, PerPage: 10},
+			Source:     entity.NoteSourceSync,
+		})
+		require.NoError(t, err)
+		require.Len(t, notes, 1)
+		assert.Equal(t, "Synced", notes[0].Title)
+
+		notes, _, err = repo.List(ctx, user.ID, repository.NoteListParams{
+			Pagination: pagination.Params{Page: 1, PerPage: 10},
+			Source:     entity.NoteSourceManual,
+		})
+		require.NoError(t, err)
+		require.Len(t, notes, 1)
+		assert.Equal(t, "By hand", notes[0].Title)
+	})
+}
+
+func TestIntegrationNoteRepo_Nearby(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
+	ctx := context.Background()
+
+	db.Truncate(t, "notes", "users")
+	user := createTestUser(t, db)
+
+	// Around Praça do Comércio, Lisbon: ~110 m and ~330 m north, and Porto.
+	for _, n := range []struct {
+		title    string
+		lat, lng float64
+	}{
+		{"far", 38.7104, -9.1366},
+		{"close", 38.7084, -9.1366},
+		{"porto", 41.1579, -8.6291},
+	} {
+		require.NoError(t, repo.Create(ctx, entity.NewNote(user.ID, n.title, "", valueobject.NewLocation(n.lat, n.lng, nil, nil), "")))
+	}
+	require.NoError(t, repo.Create(ctx, entity.NewNote(user.ID, "no location", "", nil, "")))
+
+	notes, err := repo.Nearby(ctx, user.ID, repository.NoteNearbyParams{
+		Latitude: 38.7074, Longitude: -9.1366, RadiusMeters: 1000, Limit: 10,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, notes, 2)
+	assert.Equal(t, "close", notes[0].Note.Title)
+	assert.InDelta(t, 111, notes[0].Distance, 5)
+	assert.Equal(t, "far", notes[1].Note.Title)
+	assert.InDelta(t, 333, notes[1].Distance, 5)
+}
+
+func TestIntegrationNoteRepo_Coverage(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
+	ctx := context.Background()
+
+	db.Truncate(t, "notes", "users")
+	user := createTestUser(t, db)
+
+	t.Run("no located notes", func(t *testing.T) {
+		coverage, err := repo.Coverage(ctx, user.ID, 12, 10)
+
+		require.NoError(t, err)
+		assert.Zero(t, coverage.Notes)
+		assert.Nil(t, coverage.Bounds)
+		assert.Empty(t, coverage.Tiles)
+	})
+
+	// Two notes in the same tile in Lisbon, one in Porto, one past the
+	// Mercator limit and one on the antimeridian.
+	points := [][2]float64{{38.7084, -9.1366}, {38.7104, -9.1366}, {41.1579, -8.6291}, {89.9, 0}, {-10, 180}}
+	for _, p := range points {
+		require.NoError(t, repo.Create(ctx, entity.NewNote(user.ID, "n", "", valueobject.NewLocation(p[0], p[1], nil, nil), "")))
+	}
+	require.NoError(t, repo.Create(ctx, entity.NewNote(user.ID, "no location", "", nil, "")))
+
+	coverage, err := repo.Coverage(ctx, user.ID, 12, 10)
+
+	require.NoError(t, err)
+	assert.Equal(t, 5, coverage.Notes)
+	require.NotNil(t, coverage.Bounds)
+	assert.InDelta(t, -10, coverage.Bounds.MinLat, 1e-9)
+	assert.InDelta(t, 89.9, coverage.Bounds.MaxLat, 1e-9)
+	assert.False(t, coverage.Truncated)
+
+	want := map[entity.MapTile]int{}
+	for _, p := range points {
+		want[entity.TileAt(12, p[0], p[1])]++
+	}
+	got := map[entity.MapTile]int{}
+	for _, tile := range coverage.Tiles {
+		got[tile.MapTile] = tile.Notes
+	}
+	assert.Equal(t, want, got, "SQL tiles match entity.TileAt")
+
+	coverage, err = repo.Coverage(ctx, user.ID, 12, 2)
+
+	require.NoError(t, err)
+	assert.Len(t, coverage.Tiles, 2)
+	assert.True(t, coverage.Truncated)
+}
+
+func TestIntegrationNoteRepo_Export(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
+	ctx := context.Background()
+
+	db.Truncate(t, "notes", "users")
+	user := createTestUser(t, db)
+	other := createTestUser(t, db)
+
+	var deleted *entity.Note
+	for i := range 5 {
+		n := entity.NewNote(user.ID, fmt.Sprintf("note %d", i), "", nil, "")
+		require.NoError(t, repo.Create(ctx, n))
+		if i == 2 {
+			deleted = n
+		}
+	}
+	require.NoError(t, repo.SoftDelete(ctx, deleted.UserID, deleted.ID))
+	require.NoError(t, repo.Create(ctx, entity.NewNote(other.ID, "someone else's", "", nil, "")))
+
+	t.Run("streams live notes in batches by number", func(t *testing.T) {
+		var batches [][]string
+		err := repo.Export(ctx, user.ID, repository.NoteListParams{}, 2, func(notes []entity.Note) error {
+			var titles []string
+			for _, n := range notes {
+				titles = append(titles, n.Title)
+			}
+			batches = append(batches, titles)
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"note 0", "note 1"}, {"note 3", "note 4"}}, batches)
+	})
+
+	t.Run("stops at the first callback error", func(t *testing.T) {
+		calls := 0
+		err := repo.Export(ctx, user.ID, repository.NoteListParams{}, 1, func([]entity.Note) error {
+			calls++
+			return assert.AnError
+		})
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("applies list filters and the creation range", func(t *testing.T) {
+		db.Truncate(t, "notes", "users")
+		owner := createTestUser(t, db)
+
+		lisbon := valueobject.NewLocation(38.7223, -9.1393, nil, nil)
+		porto := valueobject.NewLocation(41.1579, -8.6291, nil, nil)
+		old := entity.NewNote(owner.ID, "old lisbon", "", lisbon, "")
+		old.CreatedAt = time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+		recent := entity.NewNote(owner.ID, "recent lisbon", "", lisbon, "")
+		recent.CreatedAt = time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+		far := entity.NewNote(owner.ID, "recent porto", "", porto, "")
+		far.CreatedAt = time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)
+		for _, n := range []*entity.Note{old, recent, far} {
+			require.NoError(t, repo.Create(ctx, n))
+		}
+
+		from := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+		params := repository.NoteListParams{
+			BoundingBox: valueobject.NewBoundingBox(38, 39, -10, -9),
+			CreatedFrom: &from,
+		}
+		var titles []string
+		err := repo.Export(ctx, owner.ID, params, 10, func(notes []entity.Note) error {
+			for _, n := range notes {
+				titles = append(titles, n.Title)
+			}
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"recent lisbon"}, titles)
+	})
+}
+
+func TestIntegrationNoteRepo_Search(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
+	ctx := context.Background()
+
+	db.Truncate(t, "notes", "users")
+	user := createTestUser(t, db)
+
+	for _, n := range []struct {
+		title, content string
+		lat, lng       float64
+	}{
+		{"oak far", "", 38.7104, -9.1366},
+		{"oak close", "", 38.7084, -9.1366},
+		{"pine", "an oak in the content", 38.7080, -9.1366},
+		{"oak porto", "", 41.1579, -8.6291},
+		{"birch", "", 38.7075, -9.1366},
+	} {
+		require.NoError(t, repo.Create(ctx, entity.NewNote(user.ID, n.title, n.content, valueobject.NewLocation(n.lat, n.lng, nil, nil), "")))
+	}
+
+	titles := func(hits []entity.SearchHit) []string {
+		var out []string
+		for _, h := range hits {
+			out = append(out, h.Note.Title)
+		}
+		return out
+	}
+
+	t.Run("matches text without an area", func(t *testing.T) {
+		hits, err := repo.Search(ctx, user.ID, repository.NoteSearchParams{Query: "oak", Limit: 10})
+
+		require.NoError(t, err)
+		assert.Len(t, hits, 4)
+		assert.Nil(t, hits[0].Distance)
+		// Title matches outrank content matches.
+		assert.Equal(t, "pine", hits[len(hits)-1].Note.Title)
+	})
+
+	t.Run("limits to a radius and ranks closer notes first", func(t *testing.T) {
+		hits, err := repo.Search(ctx, user.ID, repository.NoteSearchParams{
+			Query: "oak", Latitude: 38.7074, Longitude: -9.1366, RadiusMeters: 1000, Limit: 10,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"oak close", "oak far", "pine"}, titles(hits))
+		require.NotNil(t, hits[0].Distance)
+		assert.InDelta(t, 111, *hits[0].Distance, 5)
+	})
+
+	t.Run("limits to a bounding box", func(t *testing.T) {
+		hits, err := repo.Search(ctx, user.ID, repository.NoteSearchParams{
+			Query:       "oak",
+			BoundingBox: valueobject.NewBoundingBox(41, 42, -9, -8),
+			Limit:       10,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"oak porto"}, titles(hits))
+	})
+}
+
+func TestIntegrationNoteRepo_GetQualityReport(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
+	ctx := context.Background()
+
+	t.Run("counts notes by status and failed rule", func(t *testing.T) {
+		db.Truncate(t, "notes", "users")
+		user := createTestUser(t, db)
+		rules := entity.QualityRules{RequirePhoto: true, MinContentLength: 10}
+
+		for _, content := range []string{"short", "long enough content", "tiny"} {
+			note := entity.NewNote(user.ID, "Note", content, nil, "")
+			note.Quality = rules.Evaluate(note, 0)
+			require.NoError(t, repo.Create(ctx, note))
+		}
+		require.NoError(t, repo.Create(ctx, entity.NewNote(user.ID, "Note", "Content", nil, "")))
+
+		report, err := repo.GetQualityReport(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 4, report.Total)
+		assert.Equal(t, 1, report.Unchecked)
+		assert.Equal(t, 3, report.Failed)
+		assert.Equal(t, 3, report.RuleFailures[entity.QualityRuleRequiredPhoto])
+		assert.Equal(t, 2, report.RuleFailures[entity.QualityRuleMinContentLength])
+	})
 }
 
 func TestIntegrationNoteRepo_Update(t *testing.T) {
 	db := SetupTestDB(t)
 	defer db.Cleanup(t)
 
-	repo := postgres.NewNoteRepo(db.Pool)
+	repo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
 	ctx := context.Background()
 
 	t.Run("updates note successfully", func(t *testing.T) {
@@ -238,11 +731,100 @@ func TestIntegrationNoteRepo_Update(t *testing.T) {
 	})
 }
 
+func TestIntegrationNoteRepo_Revisions(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
+	ctx := context.Background()
+
+	t.Run("keeps the version each update replaces", func(t *testing.T) {
+		db.Truncate(t, "notes", "users")
+		user := createTestUser(t, db)
+
+		note := entity.NewNote(user.ID, "Oak survey", "Three oaks", valueobject.NewLocation(-22.9, -43.2, nil, nil), "")
+		require.NoError(t, repo.Create(ctx, note))
+
+		note.Update("Oak survey", "Three oaks by the creek", note.Location)
+		note.MarkModifiedBy("device-a")
+		require.NoError(t, repo.Update(ctx, note))
+
+		note.Update("Oaks", "Four oaks", nil)
+		require.NoError(t, repo.Update(ctx, note))
+
+		revisions, err := repo.Revisions(ctx, note.ID, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, revisions, 2)
+		assert.Equal(t, 2, revisions[0].Revision)
+		assert.Equal(t, "Three oaks by the creek", revisions[0].Content)
+		assert.Equal(t, "device-a", revisions[0].LastModifiedByDevice)
+		assert.Equal(t, 1, revisions[1].Revision)
+		assert.Equal(t, "Three oaks", revisions[1].Content)
+		require.NotNil(t, revisions[1].Location)
+		assert.InDelta(t, -22.9, revisions[1].Location.Latitude, 1e-9)
+
+		older, err := repo.Revisions(ctx, note.ID, 2, 10)
+		require.NoError(t, err)
+		require.Len(t, older, 1)
+		assert.Equal(t, 1, older[0].Revision)
+	})
+
+	t.Run("skips writes that change nothing the user wrote", func(t *testing.T) {
+		db.Truncate(t, "notes", "users")
+		user := createTestUser(t, db)
+
+		note := entity.NewNote(user.ID, "Plot 7", "Soil sample", nil, "")
+		require.NoError(t, repo.Create(ctx, note))
+
+		note.Tags = []string{"soil"}
+		require.NoError(t, repo.SetTags(ctx, note))
+		require.NoError(t, repo.SoftDelete(ctx, note.UserID, note.ID))
+
+		revisions, err := repo.Revisions(ctx, note.ID, 0, 10)
+		require.NoError(t, err)
+		assert.Empty(t, revisions)
+	})
+
+	t.Run("records sync upserts", func(t *testing.T) {
+		db.Truncate(t, "notes", "users")
+		user := createTestUser(t, db)
+
+		require.NoError(t, repo.BatchUpsert(ctx, []entity.Note{*entity.NewNote(user.ID, "Nest", "Two eggs", nil, "nest-1")}))
+		later := *entity.NewNote(user.ID, "Nest", "Three eggs", nil, "nest-1")
+		later.UpdatedAt = time.Now().Add(time.Hour)
+		require.NoError(t, repo.BatchUpsert(ctx, []entity.Note{later}))
+
+		rev, err := repo.Revision(ctx, later.ID, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "Two eggs", rev.Content)
+
+		_, err = repo.Revision(ctx, later.ID, 2)
+		assert.ErrorIs(t, err, domain.ErrRevisionNotFound)
+	})
+
+	t.Run("deletes the revisions with the note", func(t *testing.T) {
+		db.Truncate(t, "notes", "users")
+		user := createTestUser(t, db)
+
+		note := entity.NewNote(user.ID, "Plot 7", "Soil sample", nil, "")
+		require.NoError(t, repo.Create(ctx, note))
+		note.Update("Plot 7", "Clay soil", nil)
+		require.NoError(t, repo.Update(ctx, note))
+
+		_, err := db.Pool.Exec(ctx, `DELETE FROM notes WHERE id = $1`, note.ID)
+		require.NoError(t, err)
+
+		revisions, err := repo.Revisions(ctx, note.ID, 0, 10)
+		require.NoError(t, err)
+		assert.Empty(t, revisions)
+	})
+}
+
 func TestIntegrationNoteRepo_SoftDelete(t *testing.T) {
 	db := SetupTestDB(t)
 	defer db.Cleanup(t)
 
-	repo := postgres.NewNoteRepo(db.Pool)
+	repo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
 	ctx := context.Background()
 
 	t.Run("soft deletes note", func(t *testing.T) {
@@ -253,22 +835,95 @@ func TestIntegrationNoteRepo_SoftDelete(t *testing.T) {
 		err := repo.Create(ctx, note)
 		require.NoError(t, err)
 
-		err = repo.SoftDelete(ctx, note.ID)
+		err = repo.SoftDelete(ctx, note.UserID, note.ID)
 		require.NoError(t, err)
 
 		found, err := repo.GetByID(ctx, note.ID)
 		require.NoError(t, err)
 		assert.NotNil(t, found.DeletedAt)
 	})
+
+	t.Run("only deletes the note of its owner", func(t *testing.T) {
+		db.Truncate(t, "notes", "users")
+		user := createTestUser(t, db)
+
+		note := entity.NewNote(user.ID, "Test Note", "Content", nil, "")
+		require.NoError(t, repo.Create(ctx, note))
+
+		err := repo.SoftDelete(ctx, uuid.New(), note.ID)
+		assert.ErrorIs(t, err, domain.ErrNoteNotFound)
+	})
 }
 
-func TestIntegrationNoteRepo_GetModifiedSince(t *testing.T) {
+func TestIntegrationNoteRepo_Merge(t *testing.T) {
 	db := SetupTestDB(t)
 	defer db.Cleanup(t)
 
-	repo := postgres.NewNoteRepo(db.Pool)
+	repo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
+	photoRepo := postgres.NewPhotoRepo(db.Pool, nil)
 	ctx := context.Background()
 
+	t.Run("moves photos and deletes merged notes", func(t *testing.T) {
+		db.Truncate(t, "photos", "notes", "users")
+		user := createTestUser(t, db)
+
+		survivor := entity.NewNote(user.ID, "Survivor", "A", nil, "")
+		require.NoError(t, repo.Create(ctx, survivor))
+		merged := entity.NewNote(user.ID, "Merged", "B", nil, "")
+		require.NoError(t, repo.Create(ctx, merged))
+		photo := entity.NewPhoto(merged.ID, merged.UserID, "http://storage/photo.jpg", "notes/123/photo.jpg", "image/jpeg", 1024, 800, 600)
+		require.NoError(t, photoRepo.Create(ctx, photo))
+
+		survivor.Merge([]entity.Note{*merged}, entity.MergeSpec{Content: entity.MergeContentConcatenate})
+		require.NoError(t, repo.Merge(ctx, survivor, []uuid.UUID{merged.ID}))
+
+		found, err := repo.GetByID(ctx, survivor.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "A\n\nB", found.Content)
+
+		tombstone, err := repo.GetByID(ctx, merged.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, tombstone.DeletedAt)
+		require.NotNil(t, tombstone.MergedInto)
+		assert.Equal(t, survivor.ID, *tombstone.MergedInto)
+
+		photos, err := photoRepo.GetByNoteID(ctx, survivor.ID)
+		require.NoError(t, err)
+		assert.Len(t, photos, 1)
+	})
+}
+
+func TestIntegrationNoteRepo_GetModifiedAfter(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
+	ctx := context.Background()
+
+	t.Run("does not skip notes sharing updated_at across pages", func(t *testing.T) {
+		db.Truncate(t, "notes", "users")
+		user := createTestUser(t, db)
+
+		at := time.Now().Add(-1 * time.Hour).UTC().Truncate(time.Microsecond)
+		for i := range 3 {
+			note := entity.NewNote(user.ID, fmt.Sprintf("Note %d", i), "Content", nil, fmt.Sprintf("tie-%d", i))
+			note.UpdatedAt = at
+			require.NoError(t, repo.Create(ctx, note))
+		}
+
+		first, err := repo.GetModifiedAfter(ctx, user.ID, pagination.Cursor{}, 2, entity.SyncScope{})
+		require.NoError(t, err)
+		require.Len(t, first, 2)
+
+		last := first[len(first)-1]
+		second, err := repo.GetModifiedAfter(ctx, user.ID, pagination.Cursor{UpdatedAt: last.UpdatedAt, ID: last.ID}, 2, entity.SyncScope{})
+		require.NoError(t, err)
+		require.Len(t, second, 1)
+
+		seen := map[uuid.UUID]bool{first[0].ID: true, first[1].ID: true, second[0].ID: true}
+		assert.Len(t, seen, 3)
+	})
+
 	t.Run("returns notes modified since timestamp", func(t *testing.T) {
 		db.Truncate(t, "notes", "users")
 		user := createTestUser(t, db)
@@ -279,7 +934,7 @@ func TestIntegrationNoteRepo_GetModifiedSince(t *testing.T) {
 		err := repo.Create(ctx, note)
 		require.NoError(t, err)
 
-		notes, err := repo.GetModifiedSince(ctx, user.ID, since, 100)
+		notes, err := repo.GetModifiedAfter(ctx, user.ID, pagination.Cursor{UpdatedAt: since}, 100, entity.SyncScope{})
 
 		require.NoError(t, err)
 		assert.Len(t, notes, 1)
@@ -295,22 +950,43 @@ func TestIntegrationNoteRepo_GetModifiedSince(t *testing.T) {
 		note := entity.NewNote(user.ID, "Deleted Note", "Content", nil, "deleted-1")
 		err := repo.Create(ctx, note)
 		require.NoError(t, err)
-		err = repo.SoftDelete(ctx, note.ID)
+		err = repo.SoftDelete(ctx, note.UserID, note.ID)
 		require.NoError(t, err)
 
-		notes, err := repo.GetModifiedSince(ctx, user.ID, since, 100)
+		notes, err := repo.GetModifiedAfter(ctx, user.ID, pagination.Cursor{UpdatedAt: since}, 100, entity.SyncScope{})
 
 		require.NoError(t, err)
 		assert.Len(t, notes, 1)
 		assert.NotNil(t, notes[0].DeletedAt)
 	})
+
+	t.Run("skips notes created before scope start", func(t *testing.T) {
+		db.Truncate(t, "notes", "users")
+		user := createTestUser(t, db)
+
+		since := time.Now().Add(-48 * time.Hour)
+
+		old := entity.NewNote(user.ID, "Old Note", "Content", nil, "old-1")
+		old.CreatedAt = time.Now().Add(-24 * time.Hour).UTC()
+		require.NoError(t, repo.Create(ctx, old))
+
+		recent := entity.NewNote(user.ID, "Recent Note", "Content", nil, "recent-1")
+		require.NoError(t, repo.Create(ctx, recent))
+
+		scopeStart := time.Now().Add(-1 * time.Hour)
+		notes, err := repo.GetModifiedAfter(ctx, user.ID, pagination.Cursor{UpdatedAt: since}, 100, entity.SyncScope{NotesSince: &scopeStart})
+
+		require.NoError(t, err)
+		require.Len(t, notes, 1)
+		assert.Equal(t, "Recent Note", notes[0].Title)
+	})
 }
 
 func TestIntegrationNoteRepo_BatchUpsert(t *testing.T) {
 	db := SetupTestDB(t)
 	defer db.Cleanup(t)
 
-	repo := postgres.NewNoteRepo(db.Pool)
+	repo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
 	ctx := context.Background()
 
 	t.Run("inserts new notes", func(t *testing.T) {
@@ -334,6 +1010,26 @@ func TestIntegrationNoteRepo_BatchUpsert(t *testing.T) {
 		assert.Equal(t, "Note 2", found2.Title)
 	})
 
+	t.Run("records the device sync that wrote the note", func(t *testing.T) {
+		db.Truncate(t, "notes", "users")
+		user := createTestUser(t, db)
+		deviceID := uuid.New()
+
+		note := *entity.NewNote(user.ID, "Synced", "Content", nil, "synced-1")
+		note.MarkSyncedFrom(deviceID)
+		require.NoError(t, repo.BatchUpsert(ctx, []entity.Note{note}))
+
+		found, err := repo.GetByClientID(ctx, user.ID, "synced-1")
+		require.NoError(t, err)
+		assert.True(t, found.PushedBy(deviceID))
+
+		require.NoError(t, repo.SoftDelete(ctx, found.UserID, found.ID))
+		deleted, err := repo.GetModifiedAfter(ctx, user.ID, pagination.Cursor{}, 10, entity.SyncScope{})
+		require.NoError(t, err)
+		require.Len(t, deleted, 1)
+		assert.False(t, deleted[0].PushedBy(deviceID), "later writes are not the device's")
+	})
+
 	t.Run("updates existing notes with newer timestamp", func(t *testing.T) {
 		db.Truncate(t, "notes", "users")
 		user := createTestUser(t, db)
@@ -353,5 +1049,374 @@ func TestIntegrationNoteRepo_BatchUpsert(t *testing.T) {
 		found, err := repo.GetByClientID(ctx, user.ID, "upsert-1")
 		require.NoError(t, err)
 		assert.Equal(t, "Updated", found.Title)
+		assert.Equal(t, note.Number, found.Number)
+	})
+
+	t.Run("keeps the stored number for existing client IDs", func(t *testing.T) {
+		db.Truncate(t, "notes", "users")
+		user := createTestUser(t, db)
+
+		notes := []entity.Note{*entity.NewNote(user.ID, "Note", "Content", nil, "numbered-1")}
+		require.NoError(t, repo.BatchUpsert(ctx, notes))
+		assert.Equal(t, int64(1), notes[0].Number)
+
+		again := []entity.Note{
+			*entity.NewNote(user.ID, "Note", "Edited on another device", nil, "numbered-1"),
+			*entity.NewNote(user.ID, "Other", "Content", nil, "numbered-2"),
+		}
+		again[0].UpdatedAt = time.Now().Add(time.Hour)
+		require.NoError(t, repo.BatchUpsert(ctx, again))
+
+		assert.Equal(t, int64(1), again[0].Number)
+		assert.Equal(t, "NOTE-0001", again[0].Reference)
+		assert.Equal(t, int64(2), again[1].Number)
+	})
+}
+
+func TestIntegrationNoteRepo_Tags(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
+	ctx := context.Background()
+
+	db.Truncate(t, "note_tags", "tags", "notes", "users")
+	user := createTestUser(t, db)
+
+	soil := entity.NewNote(user.ID, "Soil", "", nil, "tag-1")
+	soil.Tags = []string{"soil-sample"}
+	require.NoError(t, repo.Create(ctx, soil))
+	both := entity.NewNote(user.ID, "Both", "", nil, "tag-2")
+	both.Tags = []string{"soil-sample", "wildlife"}
+	require.NoError(t, repo.Create(ctx, both))
+
+	found, err := repo.GetByID(ctx, both.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"soil-sample", "wildlife"}, found.Tags)
+
+	notes, _, err := repo.List(ctx, user.ID, repository.NoteListParams{
+		Pagination: pagination.Params{Page: 1, PerPage: 10},
+		Tags:       []string{"wildlife"},
+	})
+	require.NoError(t, err)
+	require.Len(t, notes, 1)
+	assert.Equal(t, both.ID, notes[0].ID)
+
+	soil.Tags = nil
+	require.NoError(t, repo.SetTags(ctx, soil))
+	found, err = repo.GetByID(ctx, soil.ID)
+	require.NoError(t, err)
+	assert.Empty(t, found.Tags)
+}
+
+func TestIntegrationNoteRepo_ListCursor(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
+	ctx := context.Background()
+
+	db.Truncate(t, "notes", "users")
+	user := createTestUser(t, db)
+
+	for i := range 5 {
+		require.NoError(t, repo.Create(ctx, entity.NewNote(user.ID, "Note", "", nil, "cursor-"+strconv.Itoa(i))))
+	}
+
+	first, info, err := repo.List(ctx, user.ID, repository.NoteListParams{
+		Pagination: pagination.Params{Page: 1, PerPage: 2},
+	})
+	require.NoError(t, err)
+	require.Len(t, first, 2)
+	require.NotEmpty(t, info.NextCursor)
+
+	seen := map[uuid.UUID]bool{first[0].ID: true, first[1].ID: true}
+	token := info.NextCursor
+	for token != "" {
+		after, err := pagination.DecodeCursor(token)
+		require.NoError(t, err)
+
+		page, info, err := repo.List(ctx, user.ID, repository.NoteListParams{
+			Pagination: pagination.Params{PerPage: 2, After: after},
+		})
+		require.NoError(t, err)
+		for _, n := range page {
+			assert.False(t, seen[n.ID], "note returned twice")
+			seen[n.ID] = true
+		}
+		token = info.NextCursor
+	}
+
+	assert.Len(t, seen, 5)
+
+	t.Run("pages a mixed-direction sort without skipping ties", func(t *testing.T) {
+		sort := []pagination.SortKey{{Field: entity.NoteSortTitle}, {Field: entity.NoteSortNumber, Desc: true}}
+		var got []uuid.UUID
+		params := repository.NoteListParams{Pagination: pagination.Params{Page: 1, PerPage: 2}, Sort: sort}
+		for {
+			page, info, err := repo.List(ctx, user.ID, params)
+			require.NoError(t, err)
+			for _, n := range page {
+				got = append(got, n.ID)
+			}
+			if info.NextCursor == "" {
+				break
+			}
+			after, err := pagination.DecodeCursor(info.NextCursor)
+			require.NoError(t, err)
+			params.Pagination = pagination.Params{PerPage: 2, After: after}
+		}
+
+		all, _, err := repo.List(ctx, user.ID, repository.NoteListParams{
+			Pagination: pagination.Params{Page: 1, PerPage: 10}, Sort: sort,
+		})
+		require.NoError(t, err)
+		require.Len(t, all, 5)
+		for i := 1; i < len(all); i++ {
+			assert.Greater(t, all[i-1].Number, all[i].Number)
+		}
+		want := make([]uuid.UUID, len(all))
+		for i, n := range all {
+			want[i] = n.ID
+		}
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("rejects a cursor from another sort", func(t *testing.T) {
+		after, err := pagination.DecodeCursor(info.NextCursor)
+		require.NoError(t, err)
+
+		_, _, err = repo.List(ctx, user.ID, repository.NoteListParams{
+			Pagination: pagination.Params{PerPage: 2, After: after},
+			Sort:       []pagination.SortKey{{Field: entity.NoteSortTitle}},
+		})
+		assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+	})
+}
+
+func TestIntegrationNoteRepo_DeleteCascades(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
+	photoRepo := postgres.NewPhotoRepo(db.Pool, nil)
+	ctx := context.Background()
+
+	t.Run("removing a user's notes removes their photos and tags", func(t *testing.T) {
+		db.Truncate(t, "note_tags", "tags", "photos", "notes", "users")
+		user, note := createTestUserAndNote(t, db)
+
+		note.Tags = []string{"wildlife"}
+		require.NoError(t, repo.SetTags(ctx, note))
+		photo := entity.NewPhoto(note.ID, note.UserID, "http://storage/photo.jpg", "notes/1/photo.jpg", "image/jpeg", 1024, 800, 600)
+		require.NoError(t, photoRepo.Create(ctx, photo))
+
+		require.NoError(t, repo.Purge(ctx, user.ID))
+
+		photos, err := photoRepo.GetByNoteID(ctx, note.ID)
+		require.NoError(t, err)
+		assert.Empty(t, photos)
+		var links int
+		require.NoError(t, db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM note_tags`).Scan(&links))
+		assert.Zero(t, links)
+	})
+
+	t.Run("merged notes lose their link when the survivor is deleted", func(t *testing.T) {
+		db.Truncate(t, "photos", "notes", "users")
+		user, survivor := createTestUserAndNote(t, db)
+		merged := entity.NewNote(user.ID, "Merged", "", nil, "")
+		require.NoError(t, repo.Create(ctx, merged))
+		require.NoError(t, repo.Merge(ctx, survivor, []uuid.UUID{merged.ID}))
+
+		_, err := db.Pool.Exec(ctx, `DELETE FROM notes WHERE id = $1`, survivor.ID)
+		require.NoError(t, err)
+
+		var mergedInto *uuid.UUID
+		require.NoError(t, db.Pool.QueryRow(ctx, `SELECT merged_into FROM notes WHERE id = $1`, merged.ID).Scan(&mergedInto))
+		assert.Nil(t, mergedInto)
+	})
+}
+
+func TestIntegrationNoteRepo_PurgeDeleted(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
+	photoRepo := postgres.NewPhotoRepo(db.Pool, nil)
+	orphanRepo := postgres.NewStorageOrphanRepo(db.Pool)
+	ctx := context.Background()
+
+	t.Run("purges notes deleted before the cutoff and queues their objects", func(t *testing.T) {
+		db.Truncate(t, "storage_orphans", "photo_tombstones", "photos", "notes", "users")
+		user, old := createTestUserAndNote(t, db)
+		recent := entity.NewNote(user.ID, "Recent", "", nil, "")
+		require.NoError(t, repo.Create(ctx, recent))
+		photo := entity.NewPhoto(old.ID, old.UserID, "http://storage/old.jpg", "notes/old.jpg", "image/jpeg", 1024, 800, 600)
+		require.NoError(t, photoRepo.Create(ctx, photo))
+
+		require.NoError(t, repo.SoftDelete(ctx, old.UserID, old.ID))
+		require.NoError(t, repo.SoftDelete(ctx, recent.UserID, recent.ID))
+		_, err := db.Pool.Exec(ctx, `UPDATE notes SET deleted_at = NOW() - INTERVAL '60 days' WHERE id = $1`, old.ID)
+		require.NoError(t, err)
+
+		purged, err := repo.PurgeDeleted(ctx, time.Now().Add(-30*24*time.Hour), 100)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
+
+		_, err = repo.GetByID(ctx, old.ID)
+		assert.ErrorIs(t, err, domain.ErrNoteNotFound)
+		var remaining int
+		require.NoError(t, db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM notes WHERE id = $1`, recent.ID).Scan(&remaining))
+		assert.Equal(t, 1, remaining)
+
+		keys, err := orphanRepo.List(ctx, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"notes/old.jpg"}, keys)
+	})
+}
+
+// memoryStorage keeps objects in memory for content offload tests.
+type memoryStorage struct {
+	objects map[string][]byte
+}
+
+func (s *memoryStorage) Upload(_ context.Context, key string, reader io.Reader, _ string, _ int64) (string, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	s.objects[key] = data
+	return "", nil
+}
+
+func (s *memoryStorage) Download(_ context.Context, key string) ([]byte, error) {
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, fmt.Errorf("no object %s", key)
+	}
+	return data, nil
+}
+
+func (s *memoryStorage) Delete(_ context.Context, key string) error {
+	delete(s.objects, key)
+	return nil
+}
+
+func (s *memoryStorage) GetURL(key string) string {
+	return "https://storage.example.com/" + key
+}
+
+func (s *memoryStorage) GetSignedURL(key string, _ time.Duration) (string, error) {
+	return s.GetURL(key), nil
+}
+
+func TestIntegrationNoteRepo_ContentOffload(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	store := &memoryStorage{objects: make(map[string][]byte)}
+	repo := postgres.NewNoteRepo(db.Pool, nil, &postgres.ContentOffload{Storage: store, Threshold: 1000}, nil)
+	ctx := context.Background()
+
+	db.Truncate(t, "notes", "users")
+	user := createTestUser(t, db)
+	long := strings.Repeat("transect ", 500)
+
+	note := entity.NewNote(user.ID, "Long log", long, nil, "long-1")
+	require.NoError(t, repo.Create(ctx, note))
+	require.NoError(t, repo.Create(ctx, entity.NewNote(user.ID, "Short", "short", nil, "short-1")))
+
+	t.Run("stores long content as an object with an excerpt in the row", func(t *testing.T) {
+		var content string
+		var key *string
+		require.NoError(t, db.Pool.QueryRow(ctx, `SELECT content, content_key FROM notes WHERE id = $1`, note.ID).Scan(&content, &key))
+
+		require.NotNil(t, key)
+		assert.Equal(t, []byte(long), store.objects[*key])
+		assert.Len(t, []rune(content), 500)
+		assert.Len(t, store.objects, 1)
+	})
+
+	t.Run("resolves the full content of single notes", func(t *testing.T) {
+		found, err := repo.GetByID(ctx, note.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, long, found.Content)
+		assert.False(t, found.ContentExcerpt)
+		assert.Equal(t, store.GetURL(found.ContentKey), found.ContentURL)
+	})
+
+	t.Run("lists keep the excerpt and the url", func(t *testing.T) {
+		notes, _, err := repo.List(ctx, user.ID, repository.NoteListParams{Pagination: pagination.Params{Page: 1, PerPage: 10}})
+
+		require.NoError(t, err)
+		require.Len(t, notes, 2)
+		for _, n := range notes {
+			if n.ID == note.ID {
+				assert.True(t, n.ContentExcerpt)
+				assert.NotEmpty(t, n.ContentURL)
+				assert.Len(t, []rune(n.Content), 500)
+			} else {
+				assert.False(t, n.ContentExcerpt)
+				assert.Empty(t, n.ContentURL)
+			}
+		}
+	})
+
+	t.Run("keeps the replaced object for the revision", func(t *testing.T) {
+		found, err := repo.GetByID(ctx, note.ID)
+		require.NoError(t, err)
+		oldKey := found.ContentKey
+
+		found.Update(found.Title, long+"plot 7", nil)
+		require.NoError(t, repo.Update(ctx, found))
+
+		assert.NotEqual(t, oldKey, found.ContentKey)
+		assert.Contains(t, store.objects, oldKey)
+		assert.Len(t, store.objects, 2)
+
+		rev, err := repo.Revision(ctx, note.ID, 1)
+		require.NoError(t, err)
+		assert.Equal(t, oldKey, rev.ContentKey)
+		assert.Equal(t, long, rev.Content)
+		assert.False(t, rev.ContentExcerpt)
+	})
+
+	t.Run("keeps the object of an excerpt synced back", func(t *testing.T) {
+		listed, err := repo.GetByClientIDs(ctx, user.ID, []string{"long-1"})
+		require.NoError(t, err)
+		require.Len(t, listed, 1)
+
+		synced := listed[0]
+		synced.UpdatedAt = time.Now().Add(time.Hour)
+		require.NoError(t, repo.BatchUpsert(ctx, []entity.Note{synced}))
+
+		found, err := repo.GetByID(ctx, note.ID)
+		require.NoError(t, err)
+		assert.Equal(t, long+"plot 7", found.Content)
+		assert.Len(t, store.objects, 2)
+	})
+
+	t.Run("moves shortened content back into the row", func(t *testing.T) {
+		found, err := repo.GetByID(ctx, note.ID)
+		require.NoError(t, err)
+
+		found.Update(found.Title, "summary only", nil)
+		require.NoError(t, repo.Update(ctx, found))
+
+		found, err = repo.GetByID(ctx, note.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "summary only", found.Content)
+		assert.Empty(t, found.ContentKey)
+		assert.Len(t, store.objects, 2)
+	})
+
+	t.Run("queues the objects of revisions when the note is deleted", func(t *testing.T) {
+		_, err := db.Pool.Exec(ctx, `DELETE FROM notes WHERE id = $1`, note.ID)
+		require.NoError(t, err)
+
+		var queued int
+		require.NoError(t, db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM storage_orphans`).Scan(&queued))
+		assert.Equal(t, 2, queued)
 	})
 }
@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+)
+
+// note_revisions is written by the note_revisions_note_update trigger on
+// every update of a note, so the repository only reads it.
+
+const noteRevisionColumns = `note_id, user_id, revision, title, content, content_key, content_url,
+			   ST_Y(location::geometry) as lat, ST_X(location::geometry) as lng,
+			   altitude, accuracy, measurements, color, icon, last_modified_by_device,
+			   updated_at, created_at`
+
+func (r *NoteRepo) Revisions(ctx context.Context, noteID uuid.UUID, before, limit int) ([]entity.NoteRevision, error) {
+	query := `
+		SELECT ` + noteRevisionColumns + `
+		FROM note_revisions
+		WHERE note_id = $1 AND ($2 = 0 OR revision < $2)
+		ORDER BY revision DESC
+		LIMIT $3
+	`
+	rows, err := r.pool.Query(ctx, query, noteID, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying note revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []entity.NoteRevision{}
+	for rows.Next() {
+		rev, err := scanNoteRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning note revision: %w", err)
+		}
+		revisions = append(revisions, *rev)
+	}
+	return revisions, rows.Err()
+}
+
+func (r *NoteRepo) Revision(ctx context.Context, noteID uuid.UUID, revision int) (*entity.NoteRevision, error) {
+	query := `SELECT ` + noteRevisionColumns + ` FROM note_revisions WHERE note_id = $1 AND revision = $2`
+	rev, err := scanNoteRevision(r.pool.QueryRow(ctx, query, noteID, revision))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrRevisionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("querying note revision: %w", err)
+	}
+
+	if rev.ContentExcerpt {
+		content, err := r.downloadContent(ctx, rev.ContentKey)
+		if err != nil {
+			return nil, fmt.Errorf("resolving content of revision %d of note %s: %w", revision, noteID, err)
+		}
+		rev.Content = content
+		rev.ContentExcerpt = false
+	}
+	return rev, nil
+}
+
+func scanNoteRevision(row pgx.Row) (*entity.NoteRevision, error) {
+	var rev entity.NoteRevision
+	var lat, lng, altitude, accuracy *float64
+	var contentKey, contentURL, modifiedBy *string
+	var measurements []measurementRow
+
+	err := row.Scan(
+		&rev.NoteID, &rev.UserID, &rev.Revision, &rev.Title, &rev.Content, &contentKey, &contentURL,
+		&lat, &lng, &altitude, &accuracy, &measurements, &rev.Color, &rev.Icon, &modifiedBy,
+		&rev.UpdatedAt, &rev.ReplacedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if lat != nil && lng != nil {
+		rev.Location = valueobject.NewLocation(*lat, *lng, altitude, accuracy)
+	}
+	if contentKey != nil {
+		rev.ContentKey = *contentKey
+		rev.ContentExcerpt = true
+	}
+	if contentURL != nil {
+		rev.ContentURL = *contentURL
+	}
+	if modifiedBy != nil {
+		rev.LastModifiedByDevice = *modifiedBy
+	}
+	for _, m := range measurements {
+		rev.Measurements = append(rev.Measurements, valueobject.Measurement{Name: m.Name, Kind: m.Kind, Value: m.Value})
+	}
+	return &rev, nil
+}
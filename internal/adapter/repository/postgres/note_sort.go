@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+)
+
+// noteSortColumn formats a note's value of one sort field for a cursor, and
+// parses it back into a query argument. Fields are named after their columns.
+type noteSortColumn struct {
+	value func(n *entity.Note) string
+	parse func(s string) (any, error)
+}
+
+var noteSortColumns = map[string]noteSortColumn{
+	entity.NoteSortUpdatedAt: {
+		value: func(n *entity.Note) string { return n.UpdatedAt.UTC().Format(time.RFC3339Nano) },
+		parse: parseSortTime,
+	},
+	entity.NoteSortCreatedAt: {
+		value: func(n *entity.Note) string { return n.CreatedAt.UTC().Format(time.RFC3339Nano) },
+		parse: parseSortTime,
+	},
+	entity.NoteSortTitle: {
+		value: func(n *entity.Note) string { return n.Title },
+		parse: func(s string) (any, error) { return s, nil },
+	},
+	entity.NoteSortNumber: {
+		value: func(n *entity.Note) string { return strconv.FormatInt(n.Number, 10) },
+		parse: func(s string) (any, error) { return strconv.ParseInt(s, 10, 64) },
+	},
+	entity.NoteSortID: {
+		value: func(n *entity.Note) string { return n.ID.String() },
+		parse: func(s string) (any, error) { return uuid.Parse(s) },
+	},
+}
+
+func parseSortTime(s string) (any, error) {
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+// noteOrder is the full order of a note listing: the requested keys, then id
+// as the tiebreaker unless it is one of them, so notes that tie on every key
+// still have a fixed place between pages. Without keys notes are listed by
+// updated_at, newest first.
+func noteOrder(keys []pagination.SortKey) []pagination.SortKey {
+	if len(keys) == 0 {
+		keys = []pagination.SortKey{{Field: entity.NoteSortUpdatedAt, Desc: true}}
+	}
+	order := make([]pagination.SortKey, 0, len(keys)+1)
+	order = append(order, keys...)
+	for _, k := range keys {
+		if k.Field == entity.NoteSortID {
+			return order
+		}
+	}
+	return append(order, pagination.SortKey{Field: entity.NoteSortID, Desc: true})
+}
+
+func orderByClause(order []pagination.SortKey) string {
+	terms := make([]string, len(order))
+	for i, k := range order {
+		terms[i] = k.Field + " ASC"
+		if k.Desc {
+			terms[i] = k.Field + " DESC"
+		}
+	}
+	return strings.Join(terms, ", ")
+}
+
+// noteCursor returns the cursor that continues a listing after the note.
+// Listings in the default order keep the cursor without values, so cursors
+// handed out before sorting existed still work.
+func noteCursor(note *entity.Note, keys []pagination.SortKey) pagination.Cursor {
+	cursor := pagination.Cursor{UpdatedAt: note.UpdatedAt, ID: note.ID}
+	if len(keys) > 0 {
+		for _, k := range noteOrder(keys) {
+			cursor.Values = append(cursor.Values, noteSortColumns[k.Field].value(note))
+		}
+	}
+	return cursor
+}
+
+// afterCursor returns the condition keeping the notes that sort after the
+// cursor, with its arguments numbered from argNum. It reports
+// domain.ErrInvalidCursor when the cursor came from a different sort.
+func afterCursor(keys []pagination.SortKey, after *pagination.Cursor, argNum int) (string, []any, error) {
+	order := noteOrder(keys)
+
+	var values []any
+	if len(keys) == 0 {
+		if len(after.Values) > 0 {
+			return "", nil, domain.ErrInvalidCursor
+		}
+		values = []any{after.UpdatedAt, after.ID}
+	} else {
+		if len(after.Values) != len(order) {
+			return "", nil, domain.ErrInvalidCursor
+		}
+		for i, k := range order {
+			v, err := noteSortColumns[k.Field].parse(after.Values[i])
+			if err != nil {
+				return "", nil, domain.ErrInvalidCursor
+			}
+			values = append(values, v)
+		}
+	}
+
+	// A row comparison seeks on an index when every key runs the same way.
+	if sameDirection(order) {
+		fields := make([]string, len(order))
+		params := make([]string, len(order))
+		for i, k := range order {
+			fields[i] = k.Field
+			params[i] = fmt.Sprintf("$%d", argNum+i)
+		}
+		return fmt.Sprintf("(%s) %s (%s)", strings.Join(fields, ", "), comparison(order[0]), strings.Join(params, ", ")), values, nil
+	}
+
+	// Otherwise a note comes after the cursor when it ties on the first
+	// keys and sorts after it on the next one.
+	alternatives := make([]string, len(order))
+	for i, k := range order {
+		terms := make([]string, 0, i+1)
+		for j := range i {
+			terms = append(terms, fmt.Sprintf("%s = $%d", order[j].Field, argNum+j))
+		}
+		terms = append(terms, fmt.Sprintf("%s %s $%d", k.Field, comparison(k), argNum+i))
+		alternatives[i] = "(" + strings.Join(terms, " AND ") + ")"
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")", values, nil
+}
+
+func sameDirection(order []pagination.SortKey) bool {
+	for _, k := range order[1:] {
+		if k.Desc != order[0].Desc {
+			return false
+		}
+	}
+	return true
+}
+
+func comparison(k pagination.SortKey) string {
+	if k.Desc {
+		return "<"
+	}
+	return ">"
+}
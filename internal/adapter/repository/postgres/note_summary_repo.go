@@ -0,0 +1,194 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+)
+
+// NoteSummaryRepo reads the note_summaries projection maintained by triggers
+// on notes, note_tags and photos.
+type NoteSummaryRepo struct {
+	pool  *pgxpool.Pool
+	reads *ReadRouter
+}
+
+// NewNoteSummaryRepo returns a repository that lists through reads when it
+// is set, like NoteRepo.
+func NewNoteSummaryRepo(pool *pgxpool.Pool, reads *ReadRouter) *NoteSummaryRepo {
+	return &NoteSummaryRepo{pool: pool, reads: reads}
+}
+
+const noteSummaryColumns = `note_id, user_id, team_id, title, excerpt, cover_url,
+			   ST_Y(location::geometry) AS lat, ST_X(location::geometry) AS lng,
+			   sensitivity, tags, photo_count, shared_photo_count, created_at, updated_at,
+			   COALESCE(color, '') AS color, COALESCE(icon, '') AS icon`
+
+// List returns a page of the user's summaries, or the team's, newest first.
+// Paging is by cursor on (updated_at, note_id), which the projection indexes.
+func (r *NoteSummaryRepo) List(ctx context.Context, userID uuid.UUID, params repository.NoteSummaryListParams) ([]entity.NoteSummary, *pagination.Info, error) {
+	var conditions []string
+	var args []any
+	argNum := 1
+
+	if params.TeamID != nil {
+		conditions = append(conditions, fmt.Sprintf("team_id = $%d", argNum))
+		args = append(args, *params.TeamID)
+	} else {
+		conditions = append(conditions, fmt.Sprintf("user_id = $%d", argNum))
+		args = append(args, userID)
+	}
+	argNum++
+
+	if len(params.Tags) > 0 {
+		conditions = append(conditions, fmt.Sprintf("tags @> $%d", argNum))
+		args = append(args, params.Tags)
+		argNum++
+	}
+
+	if len(params.Colors) > 0 {
+		conditions = append(conditions, fmt.Sprintf("color = ANY($%d)", argNum))
+		args = append(args, params.Colors)
+		argNum++
+	}
+
+	if len(params.Icons) > 0 {
+		conditions = append(conditions, fmt.Sprintf("icon = ANY($%d)", argNum))
+		args = append(args, params.Icons)
+		argNum++
+	}
+
+	if bb := params.BoundingBox; bb != nil {
+		conditions = append(conditions, fmt.Sprintf(
+			"ST_Intersects(location, ST_MakeEnvelope($%d, $%d, $%d, $%d, 4326)::geography)",
+			argNum, argNum+1, argNum+2, argNum+3))
+		args = append(args, bb.MinLng, bb.MinLat, bb.MaxLng, bb.MaxLat)
+		argNum += 4
+	}
+
+	if after := params.After; after != nil {
+		conditions = append(conditions, fmt.Sprintf("(updated_at, note_id) < ($%d, $%d)", argNum, argNum+1))
+		args = append(args, after.UpdatedAt, after.ID)
+		argNum += 2
+	}
+
+	// One extra row tells whether another page follows.
+	query := fmt.Sprintf(`
+		SELECT `+noteSummaryColumns+`
+		FROM note_summaries
+		WHERE %s
+		ORDER BY updated_at DESC, note_id DESC
+		LIMIT $%d
+	`, strings.Join(conditions, " AND "), argNum)
+	args = append(args, params.PerPage+1)
+
+	db := r.pool
+	if r.reads != nil {
+		db = r.reads.Reader(ctx)
+	}
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("querying note summaries: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []entity.NoteSummary
+	for rows.Next() {
+		s, err := scanNoteSummary(rows)
+		if err != nil {
+			return nil, nil, fmt.Errorf("scanning note summary: %w", err)
+		}
+		summaries = append(summaries, *s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("iterating note summaries: %w", err)
+	}
+
+	var next *pagination.Cursor
+	if len(summaries) > params.PerPage {
+		summaries = summaries[:params.PerPage]
+		last := summaries[len(summaries)-1]
+		next = &pagination.Cursor{UpdatedAt: last.UpdatedAt, ID: last.ID}
+	}
+	return summaries, pagination.NewCursorInfo(params.PerPage, next, params.After != nil), nil
+}
+
+func scanNoteSummary(row pgx.Row) (*entity.NoteSummary, error) {
+	var s entity.NoteSummary
+	var coverURL *string
+	var lat, lng *float64
+	if err := row.Scan(
+		&s.ID, &s.UserID, &s.TeamID, &s.Title, &s.Excerpt, &coverURL, &lat, &lng,
+		&s.Sensitivity, &s.Tags, &s.PhotoCount, &s.SharedPhotoCount, &s.CreatedAt, &s.UpdatedAt,
+		&s.Color, &s.Icon,
+	); err != nil {
+		return nil, err
+	}
+	if coverURL != nil {
+		s.CoverURL = *coverURL
+	}
+	if lat != nil && lng != nil {
+		s.Location = valueobject.NewLocation(*lat, *lng, nil, nil)
+	}
+	return &s, nil
+}
+
+// Rebuild recomputes every summary from note_summary_rows, the view the
+// triggers use, and writes only those that differ, so it can run while the
+// API serves lists. Writes committed while it runs are refreshed by their
+// own triggers.
+func (r *NoteSummaryRepo) Rebuild(ctx context.Context) (int64, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	upsert := `
+		INSERT INTO note_summaries AS s
+		SELECT * FROM note_summary_rows
+		ON CONFLICT (note_id) DO UPDATE SET
+			user_id = EXCLUDED.user_id,
+			team_id = EXCLUDED.team_id,
+			title = EXCLUDED.title,
+			excerpt = EXCLUDED.excerpt,
+			cover_url = EXCLUDED.cover_url,
+			location = EXCLUDED.location,
+			sensitivity = EXCLUDED.sensitivity,
+			tags = EXCLUDED.tags,
+			photo_count = EXCLUDED.photo_count,
+			shared_photo_count = EXCLUDED.shared_photo_count,
+			created_at = EXCLUDED.created_at,
+			updated_at = EXCLUDED.updated_at,
+			color = EXCLUDED.color,
+			icon = EXCLUDED.icon
+		WHERE (s.*) IS DISTINCT FROM (EXCLUDED.*)
+	`
+	written, err := tx.Exec(ctx, upsert)
+	if err != nil {
+		return 0, fmt.Errorf("rebuilding note summaries: %w", err)
+	}
+
+	stale := `
+		DELETE FROM note_summaries s
+		WHERE NOT EXISTS (SELECT 1 FROM note_summary_rows r WHERE r.note_id = s.note_id)
+	`
+	removed, err := tx.Exec(ctx, stale)
+	if err != nil {
+		return 0, fmt.Errorf("removing stale note summaries: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("committing transaction: %w", err)
+	}
+	return written.RowsAffected() + removed.RowsAffected(), nil
+}
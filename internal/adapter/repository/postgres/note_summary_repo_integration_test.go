@@ -0,0 +1,102 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository/postgres"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+)
+
+func TestIntegrationNoteSummaryRepo(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewNoteSummaryRepo(db.Pool, nil)
+	noteRepo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
+	photoRepo := postgres.NewPhotoRepo(db.Pool, nil)
+	ctx := context.Background()
+
+	t.Run("follows writes to notes, tags and photos", func(t *testing.T) {
+		db.Truncate(t, "photos", "note_tags", "notes", "users")
+		user := createTestUser(t, db)
+
+		note := entity.NewNote(user.ID, "Plot 1", "Clay, dark brown", valueobject.NewLocation(-23.5, -46.6, nil, nil), "")
+		require.NoError(t, noteRepo.Create(ctx, note))
+		note.Tags = []string{"soil-sample"}
+		require.NoError(t, noteRepo.SetTags(ctx, note))
+		hidden := entity.NewPhoto(note.ID, note.UserID, "http://storage/hidden.jpg", "notes/1/hidden.jpg", "image/jpeg", 1024, 800, 600)
+		require.NoError(t, photoRepo.Create(ctx, hidden))
+		require.NoError(t, photoRepo.SetExcludedFromShares(ctx, hidden.UserID, hidden.ID, true))
+		cover := entity.NewPhoto(note.ID, note.UserID, "http://storage/cover.jpg", "notes/1/cover.jpg", "image/jpeg", 1024, 800, 600)
+		require.NoError(t, photoRepo.Create(ctx, cover))
+
+		summaries, _, err := repo.List(ctx, user.ID, repository.NoteSummaryListParams{PerPage: 20, Tags: []string{"soil-sample"}})
+		require.NoError(t, err)
+		require.Len(t, summaries, 1)
+		assert.Equal(t, "Plot 1", summaries[0].Title)
+		assert.Equal(t, "Clay, dark brown", summaries[0].Excerpt)
+		assert.Equal(t, "http://storage/cover.jpg", summaries[0].CoverURL)
+		assert.Equal(t, []string{"soil-sample"}, summaries[0].Tags)
+		assert.Equal(t, 2, summaries[0].PhotoCount)
+		assert.Equal(t, 1, summaries[0].SharedPhotoCount)
+		require.NotNil(t, summaries[0].Location)
+		assert.InDelta(t, -23.5, summaries[0].Location.Latitude, 1e-9)
+
+		summaries, _, err = repo.List(ctx, user.ID, repository.NoteSummaryListParams{
+			PerPage:     20,
+			BoundingBox: valueobject.NewBoundingBox(0, 10, 0, 10),
+		})
+		require.NoError(t, err)
+		assert.Empty(t, summaries)
+
+		require.NoError(t, noteRepo.SoftDelete(ctx, note.UserID, note.ID))
+
+		summaries, _, err = repo.List(ctx, user.ID, repository.NoteSummaryListParams{PerPage: 20})
+		require.NoError(t, err)
+		assert.Empty(t, summaries)
+	})
+
+	t.Run("pages by cursor", func(t *testing.T) {
+		db.Truncate(t, "photos", "note_tags", "notes", "users")
+		user := createTestUser(t, db)
+		for _, title := range []string{"First", "Second", "Third"} {
+			require.NoError(t, noteRepo.Create(ctx, entity.NewNote(user.ID, title, "Content", nil, "")))
+		}
+
+		first, pageInfo, err := repo.List(ctx, user.ID, repository.NoteSummaryListParams{PerPage: 2})
+		require.NoError(t, err)
+		require.Len(t, first, 2)
+		after, err := pagination.DecodeCursor(pageInfo.NextCursor)
+		require.NoError(t, err)
+
+		rest, _, err := repo.List(ctx, user.ID, repository.NoteSummaryListParams{PerPage: 2, After: after})
+		require.NoError(t, err)
+		require.Len(t, rest, 1)
+		assert.NotContains(t, []string{first[0].Title, first[1].Title}, rest[0].Title)
+	})
+
+	t.Run("rebuild fixes drifted summaries", func(t *testing.T) {
+		db.Truncate(t, "photos", "note_tags", "notes", "users")
+		user := createTestUser(t, db)
+		note := entity.NewNote(user.ID, "Plot 1", "Content", nil, "")
+		require.NoError(t, noteRepo.Create(ctx, note))
+		_, err := db.Pool.Exec(ctx, `UPDATE note_summaries SET title = 'Stale' WHERE note_id = $1`, note.ID)
+		require.NoError(t, err)
+
+		fixed, err := repo.Rebuild(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), fixed)
+
+		summaries, _, err := repo.List(ctx, user.ID, repository.NoteSummaryListParams{PerPage: 20})
+		require.NoError(t, err)
+		require.Len(t, summaries, 1)
+		assert.Equal(t, "Plot 1", summaries[0].Title)
+	})
+}
@@ -0,0 +1,172 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+// SecretSealer encrypts the OIDC client secrets stored with organizations.
+type SecretSealer interface {
+	Seal(plaintext string) (string, error)
+	Open(stored string) (string, error)
+}
+
+type OrganizationRepo struct {
+	pool    *pgxpool.Pool
+	secrets SecretSealer
+}
+
+func NewOrganizationRepo(pool *pgxpool.Pool, secrets SecretSealer) *OrganizationRepo {
+	return &OrganizationRepo{pool: pool, secrets: secrets}
+}
+
+func (r *OrganizationRepo) GetBySlug(ctx context.Context, slug string) (*entity.Organization, error) {
+	query := `
+		SELECT id, slug, name, domain, oidc_issuer, oidc_client_id, oidc_client_secret,
+			   sso_enforced, created_at, updated_at
+		FROM organizations
+		WHERE slug = $1
+	`
+	return r.scanOrganization(ctx, query, slug)
+}
+
+func (r *OrganizationRepo) GetByDomain(ctx context.Context, domainName string) (*entity.Organization, error) {
+	query := `
+		SELECT id, slug, name, domain, oidc_issuer, oidc_client_id, oidc_client_secret,
+			   sso_enforced, created_at, updated_at
+		FROM organizations
+		WHERE LOWER(domain) = LOWER($1)
+	`
+	return r.scanOrganization(ctx, query, domainName)
+}
+
+func (r *OrganizationRepo) scanOrganization(ctx context.Context, query string, args ...any) (*entity.Organization, error) {
+	var org entity.Organization
+	err := r.pool.QueryRow(ctx, query, args...).Scan(
+		&org.ID, &org.Slug, &org.Name, &org.Domain, &org.OIDCIssuer, &org.OIDCClientID,
+		&org.OIDCClientSecret, &org.SSOEnforced, &org.CreatedAt, &org.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrOrgNotFound
+		}
+		return nil, fmt.Errorf("querying organization: %w", err)
+	}
+	if err := r.openSecret(&org); err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+func (r *OrganizationRepo) openSecret(org *entity.Organization) error {
+	secret, err := r.secrets.Open(org.OIDCClientSecret)
+	if err != nil {
+		return fmt.Errorf("opening client secret of organization %s: %w", org.Slug, err)
+	}
+	org.OIDCClientSecret = secret
+	return nil
+}
+
+func (r *OrganizationRepo) UpdateSSO(ctx context.Context, org *entity.Organization) error {
+	secret, err := r.secrets.Seal(org.OIDCClientSecret)
+	if err != nil {
+		return fmt.Errorf("sealing client secret: %w", err)
+	}
+
+	query := `
+		UPDATE organizations
+		SET oidc_issuer = $2, oidc_client_id = $3, oidc_client_secret = $4, sso_enforced = $5, updated_at = $6
+		WHERE id = $1
+	`
+	tag, err := r.pool.Exec(ctx, query, org.ID, org.OIDCIssuer, org.OIDCClientID, secret, org.SSOEnforced, org.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("updating organization sso: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrOrgNotFound
+	}
+	return nil
+}
+
+func (r *OrganizationRepo) ListByUserID(ctx context.Context, userID uuid.UUID) ([]entity.Organization, error) {
+	query := `
+		SELECT o.id, o.slug, o.name, o.domain, o.oidc_issuer, o.oidc_client_id, o.oidc_client_secret,
+			   o.sso_enforced, o.created_at, o.updated_at
+		FROM organizations o
+		JOIN org_members m ON m.org_id = o.id
+		WHERE m.user_id = $1
+		ORDER BY o.name ASC
+	`
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("querying organizations: %w", err)
+	}
+	defer rows.Close()
+
+	var orgs []entity.Organization
+	for rows.Next() {
+		var org entity.Organization
+		if err := rows.Scan(
+			&org.ID, &org.Slug, &org.Name, &org.Domain, &org.OIDCIssuer, &org.OIDCClientID,
+			&org.OIDCClientSecret, &org.SSOEnforced, &org.CreatedAt, &org.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scanning organization: %w", err)
+		}
+		if err := r.openSecret(&org); err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, org)
+	}
+
+	return orgs, rows.Err()
+}
+
+func (r *OrganizationRepo) UpsertMember(ctx context.Context, membership *entity.OrgMembership) error {
+	query := `
+		INSERT INTO org_members (org_id, user_id, role, external_subject, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (org_id, user_id)
+		DO UPDATE SET external_subject = EXCLUDED.external_subject
+	`
+	_, err := r.pool.Exec(ctx, query,
+		membership.OrgID, membership.UserID, membership.Role,
+		nullableString(membership.ExternalSubject), membership.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("upserting org member: %w", err)
+	}
+	return nil
+}
+
+func (r *OrganizationRepo) IsAdminOver(ctx context.Context, adminID, memberID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1
+			FROM org_members a
+			JOIN org_members m ON m.org_id = a.org_id
+			WHERE a.user_id = $1 AND a.role = $3 AND m.user_id = $2
+		)
+	`
+	var isAdmin bool
+	if err := r.pool.QueryRow(ctx, query, adminID, memberID, entity.OrgRoleAdmin).Scan(&isAdmin); err != nil {
+		return false, fmt.Errorf("checking org admin: %w", err)
+	}
+	return isAdmin, nil
+}
+
+func (r *OrganizationRepo) IsAdmin(ctx context.Context, orgID, userID uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM org_members WHERE org_id = $1 AND user_id = $2 AND role = $3)`
+	var isAdmin bool
+	if err := r.pool.QueryRow(ctx, query, orgID, userID, entity.OrgRoleAdmin).Scan(&isAdmin); err != nil {
+		return false, fmt.Errorf("checking org admin: %w", err)
+	}
+	return isAdmin, nil
+}
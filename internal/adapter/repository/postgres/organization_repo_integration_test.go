@@ -0,0 +1,83 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository/postgres"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+func TestIntegrationOrganizationRepo_UpdateSSO(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewOrganizationRepo(db.Pool, newTestSecretBox(t))
+	ctx := context.Background()
+
+	db.Truncate(t, "org_members", "organizations", "users")
+	orgID := uuid.New()
+	_, err := db.Pool.Exec(ctx, `
+		INSERT INTO organizations (id, slug, name, domain, oidc_issuer, oidc_client_id, oidc_client_secret)
+		VALUES ($1, 'acme', 'Acme', 'example.com', 'https://idp.example.com', 'client', 'legacy-secret')
+	`, orgID)
+	require.NoError(t, err)
+
+	t.Run("reads secrets stored before encryption", func(t *testing.T) {
+		org, err := repo.GetBySlug(ctx, "acme")
+
+		require.NoError(t, err)
+		assert.Equal(t, "legacy-secret", org.OIDCClientSecret)
+	})
+
+	t.Run("stores the client secret encrypted", func(t *testing.T) {
+		org, err := repo.GetBySlug(ctx, "acme")
+		require.NoError(t, err)
+		org.OIDCIssuer = "https://login.example.com"
+		org.OIDCClientSecret = "new-secret"
+		org.UpdatedAt = time.Now().UTC()
+
+		require.NoError(t, repo.UpdateSSO(ctx, org))
+
+		var stored string
+		require.NoError(t, db.Pool.QueryRow(ctx, `SELECT oidc_client_secret FROM organizations WHERE id = $1`, orgID).Scan(&stored))
+		assert.NotContains(t, stored, "new-secret")
+
+		got, err := repo.GetBySlug(ctx, "acme")
+		require.NoError(t, err)
+		assert.Equal(t, "https://login.example.com", got.OIDCIssuer)
+		assert.Equal(t, "new-secret", got.OIDCClientSecret)
+	})
+
+	t.Run("unknown organization", func(t *testing.T) {
+		err := repo.UpdateSSO(ctx, &entity.Organization{ID: uuid.New()})
+
+		assert.ErrorIs(t, err, domain.ErrOrgNotFound)
+	})
+
+	t.Run("is admin", func(t *testing.T) {
+		userRepo := postgres.NewUserRepo(db.Pool)
+		admin := entity.NewUser("admin@example.com", "hashedpassword", "Admin")
+		require.NoError(t, userRepo.Create(ctx, admin))
+		member := createTestUser(t, db)
+
+		adminMembership := entity.NewOrgMembership(orgID, admin.ID, "")
+		adminMembership.Role = entity.OrgRoleAdmin
+		require.NoError(t, repo.UpsertMember(ctx, adminMembership))
+		require.NoError(t, repo.UpsertMember(ctx, entity.NewOrgMembership(orgID, member.ID, "")))
+
+		isAdmin, err := repo.IsAdmin(ctx, orgID, admin.ID)
+		require.NoError(t, err)
+		assert.True(t, isAdmin)
+
+		isAdmin, err = repo.IsAdmin(ctx, orgID, member.ID)
+		require.NoError(t, err)
+		assert.False(t, isAdmin)
+	})
+}
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+type PasswordResetTokenRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewPasswordResetTokenRepo(pool *pgxpool.Pool) *PasswordResetTokenRepo {
+	return &PasswordResetTokenRepo{pool: pool}
+}
+
+func (r *PasswordResetTokenRepo) Create(ctx context.Context, token *entity.PasswordResetToken) error {
+	query := `
+		INSERT INTO password_reset_tokens (id, user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := r.pool.Exec(ctx, query, token.ID, token.UserID, token.TokenHash, token.ExpiresAt, token.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("inserting password reset token: %w", err)
+	}
+	return nil
+}
+
+func (r *PasswordResetTokenRepo) GetByHash(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error) {
+	query := `
+		SELECT id, user_id, token_hash, expires_at, created_at, used_at
+		FROM password_reset_tokens
+		WHERE token_hash = $1
+	`
+	var t entity.PasswordResetToken
+	err := r.pool.QueryRow(ctx, query, tokenHash).Scan(
+		&t.ID, &t.UserID, &t.TokenHash, &t.ExpiresAt, &t.CreatedAt, &t.UsedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrResetTokenInvalid
+		}
+		return nil, fmt.Errorf("querying password reset token: %w", err)
+	}
+	return &t, nil
+}
+
+func (r *PasswordResetTokenRepo) Consume(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE password_reset_tokens
+		SET used_at = NOW()
+		WHERE used_at IS NULL AND user_id = (
+			SELECT user_id FROM password_reset_tokens WHERE id = $1 AND used_at IS NULL
+		)
+	`
+	result, err := r.pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("consuming password reset tokens: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrResetTokenInvalid
+	}
+	return nil
+}
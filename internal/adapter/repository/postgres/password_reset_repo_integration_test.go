@@ -0,0 +1,49 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository/postgres"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+func TestIntegrationPasswordResetTokenRepo_Consume(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewPasswordResetTokenRepo(db.Pool)
+	userRepo := postgres.NewUserRepo(db.Pool)
+	ctx := context.Background()
+
+	t.Run("consumes every pending token of the user once", func(t *testing.T) {
+		db.Truncate(t, "password_reset_tokens", "users")
+
+		user := entity.NewUser("reset@example.com", "hashedpassword", "Reset User")
+		require.NoError(t, userRepo.Create(ctx, user))
+
+		first := entity.NewPasswordResetToken(user.ID, "hash-1", time.Now().Add(time.Hour))
+		second := entity.NewPasswordResetToken(user.ID, "hash-2", time.Now().Add(time.Hour))
+		require.NoError(t, repo.Create(ctx, first))
+		require.NoError(t, repo.Create(ctx, second))
+
+		require.NoError(t, repo.Consume(ctx, second.ID))
+
+		found, err := repo.GetByHash(ctx, "hash-1")
+		require.NoError(t, err)
+		assert.NotNil(t, found.UsedAt)
+		assert.False(t, found.IsValid(time.Now()))
+
+		assert.ErrorIs(t, repo.Consume(ctx, second.ID), domain.ErrResetTokenInvalid)
+	})
+
+	t.Run("returns error for unknown hash", func(t *testing.T) {
+		_, err := repo.GetByHash(ctx, "missing")
+		assert.ErrorIs(t, err, domain.ErrResetTokenInvalid)
+	})
+}
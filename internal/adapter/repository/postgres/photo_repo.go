@@ -11,84 +11,134 @@ import (
 
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/clock"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
 )
 
 type PhotoRepo struct {
-	pool *pgxpool.Pool
+	pool  *pgxpool.Pool
+	clock clock.Clock
 }
 
-func NewPhotoRepo(pool *pgxpool.Pool) *PhotoRepo {
-	return &PhotoRepo{pool: pool}
+// NewPhotoRepo returns a repository that stamps photo tombstones with clk;
+// nil means the system clock.
+func NewPhotoRepo(pool *pgxpool.Pool, clk clock.Clock) *PhotoRepo {
+	if clk == nil {
+		clk = clock.System{}
+	}
+	return &PhotoRepo{pool: pool, clock: clk}
 }
 
 func (r *PhotoRepo) Create(ctx context.Context, photo *entity.Photo) error {
+	// photos is partitioned by the note owner's user_id. The owner is read
+	// back from the note, in its own partition, so it is NULL when the note
+	// is gone or is not the owner's, and the insert fails on the NOT NULL
+	// constraint.
 	query := `
-		INSERT INTO photos (id, note_id, url, key, mime_type, size, width, height, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO photos (id, note_id, user_id, url, key, mime_type, size, width, height, checksum, source_checksum, client_id, encryption,
+			thumbnails, taken_at, location, created_at)
+		VALUES ($1, $2, (SELECT user_id FROM notes WHERE id = $2 AND user_id = $18), $3, $4, $5, $6, $7, $8, $9, $10, $11, $12,
+			$13, $14, ST_SetSRID(ST_MakePoint($15, $16), 4326)::geography, $17)
 	`
+	var lng, lat *float64
+	if photo.Location != nil {
+		lng, lat = &photo.Location.Longitude, &photo.Location.Latitude
+	}
 	_, err := r.pool.Exec(ctx, query,
 		photo.ID, photo.NoteID, photo.URL, photo.Key,
-		photo.MimeType, photo.Size, photo.Width, photo.Height, photo.CreatedAt,
+		photo.MimeType, photo.Size, photo.Width, photo.Height,
+		nullableString(photo.Checksum), nullableString(photo.SourceChecksum), nullableString(photo.ClientID), nullableString(photo.Encryption),
+		thumbnailRows(photo.Thumbnails),
+		photo.TakenAt, lng, lat, photo.CreatedAt, photo.UserID,
 	)
 	if err != nil {
+		if hasCode(err, codeNotNullViolation) {
+			return domain.ErrNoteNotFound
+		}
+		if hasCode(err, codeUniqueViolation) {
+			return domain.ErrPhotoAlreadyExists
+		}
 		return fmt.Errorf("inserting photo: %w", err)
 	}
 	return nil
 }
 
+// GetByID looks the photo up in every partition, since callers only have
+// its ID.
 func (r *PhotoRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.Photo, error) {
 	query := `
-		SELECT id, note_id, url, key, mime_type, size, width, height, created_at
+		SELECT ` + photoColumns + `
 		FROM photos
 		WHERE id = $1
 	`
-	var photo entity.Photo
-	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&photo.ID, &photo.NoteID, &photo.URL, &photo.Key,
-		&photo.MimeType, &photo.Size, &photo.Width, &photo.Height, &photo.CreatedAt,
-	)
+	photo, err := scanPhotoRow(r.pool.QueryRow(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrPhotoNotFound
 		}
 		return nil, fmt.Errorf("querying photo: %w", err)
 	}
-	return &photo, nil
+	return photo, nil
 }
 
 func (r *PhotoRepo) GetByNoteID(ctx context.Context, noteID uuid.UUID) ([]entity.Photo, error) {
 	query := `
-		SELECT id, note_id, url, key, mime_type, size, width, height, created_at
+		SELECT ` + photoColumns + `
 		FROM photos
 		WHERE note_id = $1
 		ORDER BY created_at ASC
 	`
-	rows, err := r.pool.Query(ctx, query, noteID)
+	return r.queryPhotos(ctx, query, noteID)
+}
+
+func (r *PhotoRepo) GetByNoteIDs(ctx context.Context, noteIDs []uuid.UUID) ([]entity.Photo, error) {
+	query := `
+		SELECT ` + photoColumns + `
+		FROM photos
+		WHERE note_id = ANY($1)
+		ORDER BY note_id, created_at ASC
+	`
+	return r.queryPhotos(ctx, query, noteIDs)
+}
+
+func (r *PhotoRepo) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	tx, err := r.pool.Begin(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("querying photos: %w", err)
+		return fmt.Errorf("beginning transaction: %w", err)
 	}
-	defer rows.Close()
+	defer tx.Rollback(ctx)
 
-	var photos []entity.Photo
-	for rows.Next() {
-		var photo entity.Photo
-		if err := rows.Scan(
-			&photo.ID, &photo.NoteID, &photo.URL, &photo.Key,
-			&photo.MimeType, &photo.Size, &photo.Width, &photo.Height, &photo.CreatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("scanning photo: %w", err)
-		}
-		photos = append(photos, photo)
+	tombstone := `
+		INSERT INTO photo_tombstones (photo_id, note_id, user_id, client_id, deleted_at)
+		SELECT id, note_id, user_id, client_id, $2
+		FROM photos
+		WHERE id = $1 AND user_id = $3
+		ON CONFLICT (photo_id) DO NOTHING
+	`
+	if _, err := tx.Exec(ctx, tombstone, id, r.clock.Now(), userID); err != nil {
+		return fmt.Errorf("recording photo tombstone: %w", err)
 	}
 
-	return photos, rows.Err()
+	result, err := tx.Exec(ctx, `DELETE FROM photos WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("deleting photo: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrPhotoNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
 }
 
-func (r *PhotoRepo) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM photos WHERE id = $1`
-	result, err := r.pool.Exec(ctx, query, id)
+func (r *PhotoRepo) SetExcludedFromShares(ctx context.Context, userID, id uuid.UUID, excluded bool) error {
+	query := `UPDATE photos SET excluded_from_shares = $2 WHERE id = $1 AND user_id = $3`
+	result, err := r.pool.Exec(ctx, query, id, excluded, userID)
 	if err != nil {
-		return fmt.Errorf("deleting photo: %w", err)
+		return fmt.Errorf("updating photo share exclusion: %w", err)
 	}
 	if result.RowsAffected() == 0 {
 		return domain.ErrPhotoNotFound
@@ -97,10 +147,208 @@ func (r *PhotoRepo) Delete(ctx context.Context, id uuid.UUID) error {
 }
 
 func (r *PhotoRepo) DeleteByNoteID(ctx context.Context, noteID uuid.UUID) error {
-	query := `DELETE FROM photos WHERE note_id = $1`
-	_, err := r.pool.Exec(ctx, query, noteID)
+	tx, err := r.pool.Begin(ctx)
 	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tombstone := `
+		INSERT INTO photo_tombstones (photo_id, note_id, user_id, client_id, deleted_at)
+		SELECT id, note_id, user_id, client_id, $2
+		FROM photos
+		WHERE note_id = $1
+		ON CONFLICT (photo_id) DO NOTHING
+	`
+	if _, err := tx.Exec(ctx, tombstone, noteID, r.clock.Now()); err != nil {
+		return fmt.Errorf("recording photo tombstones: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM photos WHERE note_id = $1`, noteID); err != nil {
 		return fmt.Errorf("deleting photos by note: %w", err)
 	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
 	return nil
 }
+
+func (r *PhotoRepo) GetCreatedAfter(ctx context.Context, userID uuid.UUID, after pagination.Cursor, limit int) ([]entity.Photo, error) {
+	query := `
+		SELECT ` + photoColumns + `
+		FROM photos
+		WHERE user_id = $1 AND (created_at, id) > ($2, $3)
+		ORDER BY created_at ASC, id ASC
+		LIMIT $4
+	`
+	return r.queryPhotos(ctx, query, userID, after.UpdatedAt, after.ID, limit)
+}
+
+func (r *PhotoRepo) GetDeletedAfter(ctx context.Context, userID uuid.UUID, after pagination.Cursor, limit int) ([]entity.PhotoTombstone, error) {
+	query := `
+		SELECT photo_id, note_id, client_id, deleted_at
+		FROM photo_tombstones
+		WHERE user_id = $1 AND (deleted_at, photo_id) > ($2, $3)
+		ORDER BY deleted_at ASC, photo_id ASC
+		LIMIT $4
+	`
+	return r.queryTombstones(ctx, query, userID, after.UpdatedAt, after.ID, limit)
+}
+
+func (r *PhotoRepo) GetByClientID(ctx context.Context, userID uuid.UUID, clientID string) (*entity.Photo, error) {
+	query := `
+		SELECT ` + photoColumns + `
+		FROM photos
+		WHERE user_id = $1 AND client_id = $2
+	`
+	photo, err := scanPhotoRow(r.pool.QueryRow(ctx, query, userID, clientID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrPhotoNotFound
+		}
+		return nil, fmt.Errorf("querying photo: %w", err)
+	}
+	return photo, nil
+}
+
+func (r *PhotoRepo) GetBySourceChecksum(ctx context.Context, userID, noteID uuid.UUID, checksum string) (*entity.Photo, error) {
+	query := `
+		SELECT ` + photoColumns + `
+		FROM photos
+		WHERE user_id = $1 AND note_id = $2 AND source_checksum = $3
+	`
+	photo, err := scanPhotoRow(r.pool.QueryRow(ctx, query, userID, noteID, checksum))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrPhotoNotFound
+		}
+		return nil, fmt.Errorf("querying photo: %w", err)
+	}
+	return photo, nil
+}
+
+func (r *PhotoRepo) GetByClientIDs(ctx context.Context, userID uuid.UUID, clientIDs []string) ([]entity.Photo, error) {
+	query := `
+		SELECT ` + photoColumns + `
+		FROM photos
+		WHERE user_id = $1 AND client_id = ANY($2)
+	`
+	return r.queryPhotos(ctx, query, userID, clientIDs)
+}
+
+func (r *PhotoRepo) GetDeletedByClientIDs(ctx context.Context, userID uuid.UUID, clientIDs []string) ([]entity.PhotoTombstone, error) {
+	query := `
+		SELECT photo_id, note_id, client_id, deleted_at
+		FROM photo_tombstones
+		WHERE user_id = $1 AND client_id = ANY($2)
+	`
+	return r.queryTombstones(ctx, query, userID, clientIDs)
+}
+
+func (r *PhotoRepo) queryTombstones(ctx context.Context, query string, args ...any) ([]entity.PhotoTombstone, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying photo tombstones: %w", err)
+	}
+	defer rows.Close()
+
+	var tombstones []entity.PhotoTombstone
+	for rows.Next() {
+		var t entity.PhotoTombstone
+		var clientID *string
+		if err := rows.Scan(&t.PhotoID, &t.NoteID, &clientID, &t.DeletedAt); err != nil {
+			return nil, fmt.Errorf("scanning photo tombstone: %w", err)
+		}
+		if clientID != nil {
+			t.ClientID = *clientID
+		}
+		tombstones = append(tombstones, t)
+	}
+
+	return tombstones, rows.Err()
+}
+
+func (r *PhotoRepo) queryPhotos(ctx context.Context, query string, args ...any) ([]entity.Photo, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying photos: %w", err)
+	}
+	defer rows.Close()
+
+	var photos []entity.Photo
+	for rows.Next() {
+		photo, err := scanPhotoRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning photo: %w", err)
+		}
+		photos = append(photos, *photo)
+	}
+
+	return photos, rows.Err()
+}
+
+const photoColumns = `id, note_id, user_id, url, key, mime_type, size, width, height, checksum, source_checksum, client_id, encryption, thumbnails,
+	taken_at, ST_Y(location::geometry), ST_X(location::geometry), excluded_from_shares, created_at`
+
+// scanPhotoRow scans a row selected with photoColumns.
+func scanPhotoRow(row pgx.Row) (*entity.Photo, error) {
+	var photo entity.Photo
+	var width, height *int
+	var checksum, sourceChecksum, clientID, encryption *string
+	var thumbnails map[string]thumbnailRow
+	var lat, lng *float64
+
+	if err := row.Scan(
+		&photo.ID, &photo.NoteID, &photo.UserID, &photo.URL, &photo.Key,
+		&photo.MimeType, &photo.Size, &width, &height, &checksum, &sourceChecksum, &clientID, &encryption, &thumbnails,
+		&photo.TakenAt, &lat, &lng, &photo.ExcludedFromShares, &photo.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if width != nil {
+		photo.Width = *width
+	}
+	if height != nil {
+		photo.Height = *height
+	}
+	if checksum != nil {
+		photo.Checksum = *checksum
+	}
+	if sourceChecksum != nil {
+		photo.SourceChecksum = *sourceChecksum
+	}
+	if clientID != nil {
+		photo.ClientID = *clientID
+	}
+	if encryption != nil {
+		photo.Encryption = *encryption
+	}
+	if lat != nil && lng != nil {
+		photo.Location = valueobject.NewLocation(*lat, *lng, nil, nil)
+	}
+	if len(thumbnails) > 0 {
+		photo.Thumbnails = make(map[string]entity.PhotoThumbnail, len(thumbnails))
+		for size, t := range thumbnails {
+			photo.Thumbnails[size] = entity.PhotoThumbnail{Key: t.Key, URL: t.URL, Width: t.Width, Height: t.Height}
+		}
+	}
+
+	return &photo, nil
+}
+
+type thumbnailRow struct {
+	Key    string `json:"key"`
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+func thumbnailRows(thumbnails map[string]entity.PhotoThumbnail) map[string]thumbnailRow {
+	rows := make(map[string]thumbnailRow, len(thumbnails))
+	for size, t := range thumbnails {
+		rows[size] = thumbnailRow{Key: t.Key, URL: t.URL, Width: t.Width, Height: t.Height}
+	}
+	return rows
+}
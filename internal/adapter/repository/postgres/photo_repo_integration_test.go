@@ -2,7 +2,9 @@ package postgres_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -11,12 +13,14 @@ import (
 	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository/postgres"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
 )
 
 func createTestUserAndNote(t *testing.T, db *TestDB) (*entity.User, *entity.Note) {
 	t.Helper()
 	userRepo := postgres.NewUserRepo(db.Pool)
-	noteRepo := postgres.NewNoteRepo(db.Pool)
+	noteRepo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
 	ctx := context.Background()
 
 	user := entity.NewUser("test@example.com", "hashedpassword", "Test User")
@@ -34,18 +38,52 @@ func TestIntegrationPhotoRepo_Create(t *testing.T) {
 	db := SetupTestDB(t)
 	defer db.Cleanup(t)
 
-	repo := postgres.NewPhotoRepo(db.Pool)
+	repo := postgres.NewPhotoRepo(db.Pool, nil)
 	ctx := context.Background()
 
 	t.Run("creates photo successfully", func(t *testing.T) {
 		db.Truncate(t, "photos", "notes", "users")
 		_, note := createTestUserAndNote(t, db)
 
-		photo := entity.NewPhoto(note.ID, "http://storage/photo.jpg", "notes/123/photo.jpg", "image/jpeg", 1024, 800, 600)
+		photo := entity.NewPhoto(note.ID, note.UserID, "http://storage/photo.jpg", "notes/123/photo.jpg", "image/jpeg", 1024, 800, 600)
 		err := repo.Create(ctx, photo)
 
 		require.NoError(t, err)
 		assert.NotEmpty(t, photo.ID)
+
+		found, err := repo.GetByID(ctx, photo.ID)
+		require.NoError(t, err)
+		assert.Equal(t, note.UserID, found.UserID)
+	})
+
+	t.Run("finds a photo by source checksum on its note only", func(t *testing.T) {
+		db.Truncate(t, "photos", "notes", "users")
+		user, note := createTestUserAndNote(t, db)
+
+		photo := entity.NewPhoto(note.ID, note.UserID, "http://storage/photo.jpg", "notes/123/photo.jpg", "image/jpeg", 1024, 800, 600)
+		photo.SourceChecksum = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+		require.NoError(t, repo.Create(ctx, photo))
+
+		found, err := repo.GetBySourceChecksum(ctx, user.ID, note.ID, photo.SourceChecksum)
+		require.NoError(t, err)
+		assert.Equal(t, photo.ID, found.ID)
+		assert.Equal(t, photo.SourceChecksum, found.SourceChecksum)
+
+		_, err = repo.GetBySourceChecksum(ctx, user.ID, uuid.New(), photo.SourceChecksum)
+		assert.ErrorIs(t, err, domain.ErrPhotoNotFound)
+
+		again := entity.NewPhoto(note.ID, note.UserID, "http://storage/again.jpg", "notes/123/again.jpg", "image/jpeg", 1024, 800, 600)
+		again.SourceChecksum = photo.SourceChecksum
+		assert.ErrorIs(t, repo.Create(ctx, again), domain.ErrPhotoAlreadyExists)
+	})
+
+	t.Run("returns not found for a missing note", func(t *testing.T) {
+		db.Truncate(t, "photos", "notes", "users")
+
+		photo := entity.NewPhoto(uuid.New(), uuid.New(), "http://storage/photo.jpg", "notes/123/photo.jpg", "image/jpeg", 1024, 800, 600)
+		err := repo.Create(ctx, photo)
+
+		assert.ErrorIs(t, err, domain.ErrNoteNotFound)
 	})
 }
 
@@ -53,14 +91,14 @@ func TestIntegrationPhotoRepo_GetByID(t *testing.T) {
 	db := SetupTestDB(t)
 	defer db.Cleanup(t)
 
-	repo := postgres.NewPhotoRepo(db.Pool)
+	repo := postgres.NewPhotoRepo(db.Pool, nil)
 	ctx := context.Background()
 
 	t.Run("returns photo by ID", func(t *testing.T) {
 		db.Truncate(t, "photos", "notes", "users")
 		_, note := createTestUserAndNote(t, db)
 
-		photo := entity.NewPhoto(note.ID, "http://storage/photo.jpg", "notes/123/photo.jpg", "image/jpeg", 1024, 800, 600)
+		photo := entity.NewPhoto(note.ID, note.UserID, "http://storage/photo.jpg", "notes/123/photo.jpg", "image/jpeg", 1024, 800, 600)
 		err := repo.Create(ctx, photo)
 		require.NoError(t, err)
 
@@ -70,6 +108,57 @@ func TestIntegrationPhotoRepo_GetByID(t *testing.T) {
 		assert.Equal(t, photo.ID, found.ID)
 		assert.Equal(t, "http://storage/photo.jpg", found.URL)
 		assert.Equal(t, int64(1024), found.Size)
+		assert.Empty(t, found.Encryption)
+	})
+
+	t.Run("returns encryption status", func(t *testing.T) {
+		db.Truncate(t, "photos", "notes", "users")
+		_, note := createTestUserAndNote(t, db)
+
+		photo := entity.NewPhoto(note.ID, note.UserID, "http://storage/photo.jpg", "notes/123/photo.jpg", "image/jpeg", 1024, 800, 600)
+		photo.Encryption = "aws:kms"
+		require.NoError(t, repo.Create(ctx, photo))
+
+		found, err := repo.GetByID(ctx, photo.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, "aws:kms", found.Encryption)
+	})
+
+	t.Run("returns thumbnails", func(t *testing.T) {
+		db.Truncate(t, "photos", "notes", "users")
+		_, note := createTestUserAndNote(t, db)
+
+		photo := entity.NewPhoto(note.ID, note.UserID, "http://storage/photo.jpg", "notes/123/photo.jpg", "image/jpeg", 1024, 800, 600)
+		photo.Thumbnails = map[string]entity.PhotoThumbnail{
+			entity.ThumbnailSmall: {Key: "notes/123/photo_small.jpg", URL: "http://storage/photo_small.jpg", Width: 256, Height: 192},
+		}
+		require.NoError(t, repo.Create(ctx, photo))
+
+		found, err := repo.GetByID(ctx, photo.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, photo.Thumbnails, found.Thumbnails)
+	})
+
+	t.Run("returns exif capture time and position", func(t *testing.T) {
+		db.Truncate(t, "photos", "notes", "users")
+		_, note := createTestUserAndNote(t, db)
+
+		takenAt := time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC)
+		photo := entity.NewPhoto(note.ID, note.UserID, "http://storage/photo.jpg", "notes/123/photo.jpg", "image/jpeg", 1024, 800, 600)
+		photo.TakenAt = &takenAt
+		photo.Location = valueobject.NewLocation(38.7083, -9.1376, nil, nil)
+		require.NoError(t, repo.Create(ctx, photo))
+
+		found, err := repo.GetByID(ctx, photo.ID)
+
+		require.NoError(t, err)
+		require.NotNil(t, found.TakenAt)
+		assert.True(t, takenAt.Equal(*found.TakenAt))
+		require.NotNil(t, found.Location)
+		assert.InDelta(t, 38.7083, found.Location.Latitude, 1e-6)
+		assert.InDelta(t, -9.1376, found.Location.Longitude, 1e-6)
 	})
 
 	t.Run("returns not found error", func(t *testing.T) {
@@ -82,22 +171,54 @@ func TestIntegrationPhotoRepo_GetByID(t *testing.T) {
 	})
 }
 
+func TestIntegrationPhotoRepo_SetExcludedFromShares(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewPhotoRepo(db.Pool, nil)
+	ctx := context.Background()
+
+	t.Run("excludes and includes photo", func(t *testing.T) {
+		db.Truncate(t, "photos", "notes", "users")
+		_, note := createTestUserAndNote(t, db)
+
+		photo := entity.NewPhoto(note.ID, note.UserID, "http://storage/photo.jpg", "notes/123/photo.jpg", "image/jpeg", 1024, 800, 600)
+		require.NoError(t, repo.Create(ctx, photo))
+
+		require.NoError(t, repo.SetExcludedFromShares(ctx, photo.UserID, photo.ID, true))
+		found, err := repo.GetByID(ctx, photo.ID)
+		require.NoError(t, err)
+		assert.True(t, found.ExcludedFromShares)
+
+		require.NoError(t, repo.SetExcludedFromShares(ctx, photo.UserID, photo.ID, false))
+		found, err = repo.GetByID(ctx, photo.ID)
+		require.NoError(t, err)
+		assert.False(t, found.ExcludedFromShares)
+	})
+
+	t.Run("returns not found error", func(t *testing.T) {
+		err := repo.SetExcludedFromShares(ctx, uuid.New(), uuid.New(), true)
+
+		assert.ErrorIs(t, err, domain.ErrPhotoNotFound)
+	})
+}
+
 func TestIntegrationPhotoRepo_GetByNoteID(t *testing.T) {
 	db := SetupTestDB(t)
 	defer db.Cleanup(t)
 
-	repo := postgres.NewPhotoRepo(db.Pool)
+	repo := postgres.NewPhotoRepo(db.Pool, nil)
 	ctx := context.Background()
 
 	t.Run("returns all photos for note", func(t *testing.T) {
 		db.Truncate(t, "photos", "notes", "users")
 		_, note := createTestUserAndNote(t, db)
 
-		photo1 := entity.NewPhoto(note.ID, "http://storage/photo1.jpg", "notes/123/photo1.jpg", "image/jpeg", 1024, 800, 600)
+		photo1 := entity.NewPhoto(note.ID, note.UserID, "http://storage/photo1.jpg", "notes/123/photo1.jpg", "image/jpeg", 1024, 800, 600)
 		err := repo.Create(ctx, photo1)
 		require.NoError(t, err)
 
-		photo2 := entity.NewPhoto(note.ID, "http://storage/photo2.jpg", "notes/123/photo2.jpg", "image/jpeg", 2048, 1920, 1080)
+		photo2 := entity.NewPhoto(note.ID, note.UserID, "http://storage/photo2.jpg", "notes/123/photo2.jpg", "image/jpeg", 2048, 1920, 1080)
 		err = repo.Create(ctx, photo2)
 		require.NoError(t, err)
 
@@ -120,7 +241,7 @@ func TestIntegrationPhotoRepo_GetByNoteID(t *testing.T) {
 	t.Run("does not return photos from other notes", func(t *testing.T) {
 		db.Truncate(t, "photos", "notes", "users")
 		userRepo := postgres.NewUserRepo(db.Pool)
-		noteRepo := postgres.NewNoteRepo(db.Pool)
+		noteRepo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
 
 		user := entity.NewUser("test@example.com", "hashedpassword", "Test User")
 		err := userRepo.Create(ctx, user)
@@ -134,7 +255,7 @@ func TestIntegrationPhotoRepo_GetByNoteID(t *testing.T) {
 		err = noteRepo.Create(ctx, note2)
 		require.NoError(t, err)
 
-		photo := entity.NewPhoto(note1.ID, "http://storage/photo.jpg", "notes/123/photo.jpg", "image/jpeg", 1024, 800, 600)
+		photo := entity.NewPhoto(note1.ID, note1.UserID, "http://storage/photo.jpg", "notes/123/photo.jpg", "image/jpeg", 1024, 800, 600)
 		err = repo.Create(ctx, photo)
 		require.NoError(t, err)
 
@@ -143,24 +264,48 @@ func TestIntegrationPhotoRepo_GetByNoteID(t *testing.T) {
 		require.NoError(t, err)
 		assert.Empty(t, photos)
 	})
+
+	t.Run("returns the photos of several notes at once", func(t *testing.T) {
+		db.Truncate(t, "photos", "notes", "users")
+		user, note1 := createTestUserAndNote(t, db)
+		noteRepo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
+
+		note2 := entity.NewNote(user.ID, "Note 2", "Content", nil, "n2")
+		require.NoError(t, noteRepo.Create(ctx, note2))
+		note3 := entity.NewNote(user.ID, "Note 3", "Content", nil, "n3")
+		require.NoError(t, noteRepo.Create(ctx, note3))
+
+		for _, noteID := range []uuid.UUID{note1.ID, note2.ID, note3.ID} {
+			photo := entity.NewPhoto(noteID, user.ID, "http://storage/photo.jpg", "notes/"+noteID.String()+"/photo.jpg", "image/jpeg", 1024, 800, 600)
+			require.NoError(t, repo.Create(ctx, photo))
+		}
+
+		photos, err := repo.GetByNoteIDs(ctx, []uuid.UUID{note1.ID, note2.ID})
+
+		require.NoError(t, err)
+		require.Len(t, photos, 2)
+		for _, p := range photos {
+			assert.NotEqual(t, note3.ID, p.NoteID)
+		}
+	})
 }
 
 func TestIntegrationPhotoRepo_Delete(t *testing.T) {
 	db := SetupTestDB(t)
 	defer db.Cleanup(t)
 
-	repo := postgres.NewPhotoRepo(db.Pool)
+	repo := postgres.NewPhotoRepo(db.Pool, nil)
 	ctx := context.Background()
 
 	t.Run("deletes photo successfully", func(t *testing.T) {
 		db.Truncate(t, "photos", "notes", "users")
 		_, note := createTestUserAndNote(t, db)
 
-		photo := entity.NewPhoto(note.ID, "http://storage/photo.jpg", "notes/123/photo.jpg", "image/jpeg", 1024, 800, 600)
+		photo := entity.NewPhoto(note.ID, note.UserID, "http://storage/photo.jpg", "notes/123/photo.jpg", "image/jpeg", 1024, 800, 600)
 		err := repo.Create(ctx, photo)
 		require.NoError(t, err)
 
-		err = repo.Delete(ctx, photo.ID)
+		err = repo.Delete(ctx, photo.UserID, photo.ID)
 		require.NoError(t, err)
 
 		found, err := repo.GetByID(ctx, photo.ID)
@@ -168,3 +313,72 @@ func TestIntegrationPhotoRepo_Delete(t *testing.T) {
 		assert.ErrorIs(t, err, domain.ErrPhotoNotFound)
 	})
 }
+
+func TestIntegrationPhotoRepo_SyncManifest(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewPhotoRepo(db.Pool, nil)
+	ctx := context.Background()
+
+	t.Run("returns created photos and tombstones since cursor", func(t *testing.T) {
+		db.Truncate(t, "photo_tombstones", "photos", "notes", "users")
+		user, note := createTestUserAndNote(t, db)
+		since := time.Now().UTC().Add(-time.Minute)
+
+		kept := entity.NewPhoto(note.ID, note.UserID, "http://storage/a.jpg", "notes/a.jpg", "image/jpeg", 1024, 800, 600)
+		kept.Checksum = "abc123"
+		require.NoError(t, repo.Create(ctx, kept))
+
+		removed := entity.NewPhoto(note.ID, note.UserID, "http://storage/b.jpg", "notes/b.jpg", "image/jpeg", 2048, 800, 600)
+		require.NoError(t, repo.Create(ctx, removed))
+		require.NoError(t, repo.Delete(ctx, removed.UserID, removed.ID))
+
+		added, err := repo.GetCreatedAfter(ctx, user.ID, pagination.Cursor{UpdatedAt: since}, 10)
+		require.NoError(t, err)
+		require.Len(t, added, 1)
+		assert.Equal(t, kept.ID, added[0].ID)
+		assert.Equal(t, "abc123", added[0].Checksum)
+
+		tombstones, err := repo.GetDeletedAfter(ctx, user.ID, pagination.Cursor{UpdatedAt: since}, 10)
+		require.NoError(t, err)
+		require.Len(t, tombstones, 1)
+		assert.Equal(t, removed.ID, tombstones[0].PhotoID)
+		assert.Equal(t, note.ID, tombstones[0].NoteID)
+	})
+
+	t.Run("pages tombstones sharing a timestamp without skipping", func(t *testing.T) {
+		db.Truncate(t, "photo_tombstones", "photos", "notes", "users")
+		user, note := createTestUserAndNote(t, db)
+
+		for i := range 3 {
+			photo := entity.NewPhoto(note.ID, note.UserID, "http://storage/p.jpg", fmt.Sprintf("notes/%d.jpg", i), "image/jpeg", 1024, 800, 600)
+			require.NoError(t, repo.Create(ctx, photo))
+		}
+		require.NoError(t, repo.DeleteByNoteID(ctx, note.ID))
+
+		first, err := repo.GetDeletedAfter(ctx, user.ID, pagination.Cursor{}, 2)
+		require.NoError(t, err)
+		require.Len(t, first, 2)
+
+		last := first[len(first)-1]
+		second, err := repo.GetDeletedAfter(ctx, user.ID, pagination.Cursor{UpdatedAt: last.DeletedAt, ID: last.PhotoID}, 2)
+		require.NoError(t, err)
+		require.Len(t, second, 1)
+		assert.NotContains(t, []uuid.UUID{first[0].PhotoID, first[1].PhotoID}, second[0].PhotoID)
+	})
+
+	t.Run("records tombstones for photos of hard-deleted notes", func(t *testing.T) {
+		db.Truncate(t, "photo_tombstones", "photos", "notes", "users")
+		user, note := createTestUserAndNote(t, db)
+
+		photo := entity.NewPhoto(note.ID, note.UserID, "http://storage/p.jpg", "notes/p.jpg", "image/jpeg", 1024, 800, 600)
+		require.NoError(t, repo.Create(ctx, photo))
+		require.NoError(t, postgres.NewNoteRepo(db.Pool, nil, nil, nil).Purge(ctx, user.ID))
+
+		tombstones, err := repo.GetDeletedAfter(ctx, user.ID, pagination.Cursor{}, 10)
+		require.NoError(t, err)
+		require.Len(t, tombstones, 1)
+		assert.Equal(t, photo.ID, tombstones[0].PhotoID)
+	})
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+)
+
+type QualityRuleRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewQualityRuleRepo(pool *pgxpool.Pool) *QualityRuleRepo {
+	return &QualityRuleRepo{pool: pool}
+}
+
+func (r *QualityRuleRepo) GetByUserID(ctx context.Context, userID uuid.UUID) (*entity.QualityRules, error) {
+	query := `
+		SELECT require_photo, min_content_length,
+			   fence_min_lat, fence_max_lat, fence_min_lng, fence_max_lng,
+			   max_accuracy, updated_at
+		FROM quality_rules
+		WHERE user_id = $1
+	`
+	rules := entity.QualityRules{UserID: userID}
+	var minLat, maxLat, minLng, maxLng *float64
+
+	err := r.pool.QueryRow(ctx, query, userID).Scan(
+		&rules.RequirePhoto, &rules.MinContentLength,
+		&minLat, &maxLat, &minLng, &maxLng,
+		&rules.MaxAccuracy, &rules.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &rules, nil
+		}
+		return nil, fmt.Errorf("querying quality rules: %w", err)
+	}
+
+	if minLat != nil && maxLat != nil && minLng != nil && maxLng != nil {
+		rules.Fence = valueobject.NewBoundingBox(*minLat, *maxLat, *minLng, *maxLng)
+	}
+
+	return &rules, nil
+}
+
+func (r *QualityRuleRepo) Upsert(ctx context.Context, rules *entity.QualityRules) error {
+	query := `
+		INSERT INTO quality_rules (user_id, require_photo, min_content_length,
+								   fence_min_lat, fence_max_lat, fence_min_lng, fence_max_lng,
+								   max_accuracy, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (user_id)
+		DO UPDATE SET
+			require_photo = EXCLUDED.require_photo,
+			min_content_length = EXCLUDED.min_content_length,
+			fence_min_lat = EXCLUDED.fence_min_lat,
+			fence_max_lat = EXCLUDED.fence_max_lat,
+			fence_min_lng = EXCLUDED.fence_min_lng,
+			fence_max_lng = EXCLUDED.fence_max_lng,
+			max_accuracy = EXCLUDED.max_accuracy,
+			updated_at = EXCLUDED.updated_at
+	`
+	var minLat, maxLat, minLng, maxLng *float64
+	if rules.Fence != nil {
+		minLat, maxLat = &rules.Fence.MinLat, &rules.Fence.MaxLat
+		minLng, maxLng = &rules.Fence.MinLng, &rules.Fence.MaxLng
+	}
+
+	_, err := r.pool.Exec(ctx, query,
+		rules.UserID, rules.RequirePhoto, rules.MinContentLength,
+		minLat, maxLat, minLng, maxLng,
+		rules.MaxAccuracy, rules.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("upserting quality rules: %w", err)
+	}
+	return nil
+}
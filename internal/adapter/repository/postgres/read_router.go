@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/consistency"
+)
+
+// ReadRouter chooses between the primary and an optional read replica.
+type ReadRouter struct {
+	primary *pgxpool.Pool
+	replica *pgxpool.Pool
+}
+
+// NewReadRouter returns a router over primary and replica. A nil replica
+// sends every read to the primary.
+func NewReadRouter(primary, replica *pgxpool.Pool) *ReadRouter {
+	return &ReadRouter{primary: primary, replica: replica}
+}
+
+// Version returns the user's current write version as seen by the primary.
+func (r *ReadRouter) Version(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var version int64
+	err := r.primary.QueryRow(ctx, `SELECT write_version FROM users WHERE id = $1`, userID).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("getting write version: %w", err)
+	}
+	return version, nil
+}
+
+// Reader returns the replica when ctx allows replica reads and the replica
+// has caught up with the version the caller asked for, and the primary
+// otherwise. Errors reading the replica's version also fall back to the
+// primary.
+func (r *ReadRouter) Reader(ctx context.Context) *pgxpool.Pool {
+	read, ok := consistency.ReplicaReadFrom(ctx)
+	if !ok || r.replica == nil {
+		return r.primary
+	}
+	if read.MinVersion == 0 {
+		return r.replica
+	}
+
+	var version int64
+	err := r.replica.QueryRow(ctx, `SELECT write_version FROM users WHERE id = $1`, read.UserID).Scan(&version)
+	if err != nil || version < read.MinVersion {
+		return r.primary
+	}
+	return r.replica
+}
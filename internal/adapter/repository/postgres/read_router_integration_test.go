@@ -0,0 +1,71 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository/postgres"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/consistency"
+)
+
+func TestIntegrationReadRouter(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	ctx := context.Background()
+
+	// A second pool on the same database stands in for the replica; it is
+	// always caught up, so only the requested version decides the routing.
+	replica, err := pgxpool.New(ctx, db.Pool.Config().ConnString())
+	require.NoError(t, err)
+	defer replica.Close()
+
+	router := postgres.NewReadRouter(db.Pool, replica)
+	userRepo := postgres.NewUserRepo(db.Pool)
+	noteRepo := postgres.NewNoteRepo(db.Pool, router, nil, nil)
+
+	db.Truncate(t, "notes", "users")
+	user := entity.NewUser("versions@example.com", "hashedpassword", "Versions")
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	before, err := router.Version(ctx, user.ID)
+	require.NoError(t, err)
+
+	require.NoError(t, noteRepo.Create(ctx, entity.NewNote(user.ID, "Note", "Content", nil, "")))
+
+	after, err := router.Version(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Greater(t, after, before)
+
+	t.Run("uses the primary without the replica read mark", func(t *testing.T) {
+		assert.Same(t, db.Pool, router.Reader(ctx))
+	})
+
+	t.Run("uses the replica once it has the requested version", func(t *testing.T) {
+		readCtx := consistency.WithReplicaRead(ctx, consistency.ReplicaRead{UserID: user.ID, MinVersion: after})
+		assert.Same(t, replica, router.Reader(readCtx))
+	})
+
+	t.Run("falls back to the primary when the replica is behind", func(t *testing.T) {
+		readCtx := consistency.WithReplicaRead(ctx, consistency.ReplicaRead{UserID: user.ID, MinVersion: after + 1})
+		assert.Same(t, db.Pool, router.Reader(readCtx))
+	})
+
+	t.Run("bumps the version once per transaction", func(t *testing.T) {
+		notes := make([]entity.Note, 0, 5)
+		for range 5 {
+			notes = append(notes, *entity.NewNote(user.ID, "Batch", "Content", nil, uuid.NewString()))
+		}
+		require.NoError(t, noteRepo.BatchUpsert(ctx, notes))
+
+		batched, err := router.Version(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, after+1, batched)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -11,23 +12,33 @@ import (
 
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/clock"
 )
 
 type RefreshTokenRepo struct {
-	pool *pgxpool.Pool
+	pool  *pgxpool.Pool
+	clock clock.Clock
 }
 
-func NewRefreshTokenRepo(pool *pgxpool.Pool) *RefreshTokenRepo {
-	return &RefreshTokenRepo{pool: pool}
+// NewRefreshTokenRepo returns a repository that revokes and expires tokens by
+// clk; nil means the system clock.
+func NewRefreshTokenRepo(pool *pgxpool.Pool, clk clock.Clock) *RefreshTokenRepo {
+	if clk == nil {
+		clk = clock.System{}
+	}
+	return &RefreshTokenRepo{pool: pool, clock: clk}
 }
 
 func (r *RefreshTokenRepo) Create(ctx context.Context, token *entity.RefreshToken) error {
 	query := `
-		INSERT INTO refresh_tokens (id, user_id, device_id, token, expires_at, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO refresh_tokens (id, user_id, device_id, token, expires_at, created_at,
+		                            platform, access_ttl_seconds, refresh_ttl_seconds, max_sessions)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 	_, err := r.pool.Exec(ctx, query,
 		token.ID, token.UserID, token.DeviceID, token.Token, token.ExpiresAt, token.CreatedAt,
+		nullableString(token.Platform), int(token.Policy.AccessTTL.Seconds()), int(token.Policy.RefreshTTL.Seconds()),
+		token.Policy.MaxSessions,
 	)
 	if err != nil {
 		return fmt.Errorf("inserting refresh token: %w", err)
@@ -37,13 +48,17 @@ func (r *RefreshTokenRepo) Create(ctx context.Context, token *entity.RefreshToke
 
 func (r *RefreshTokenRepo) GetByToken(ctx context.Context, token string) (*entity.RefreshToken, error) {
 	query := `
-		SELECT id, user_id, device_id, token, expires_at, created_at, revoked_at
+		SELECT id, user_id, device_id, token, expires_at, created_at, revoked_at,
+		       platform, access_ttl_seconds, refresh_ttl_seconds, max_sessions
 		FROM refresh_tokens
 		WHERE token = $1
 	`
 	var rt entity.RefreshToken
+	var platform *string
+	var accessTTL, refreshTTL, maxSessions *int
 	err := r.pool.QueryRow(ctx, query, token).Scan(
 		&rt.ID, &rt.UserID, &rt.DeviceID, &rt.Token, &rt.ExpiresAt, &rt.CreatedAt, &rt.RevokedAt,
+		&platform, &accessTTL, &refreshTTL, &maxSessions,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -51,16 +66,47 @@ func (r *RefreshTokenRepo) GetByToken(ctx context.Context, token string) (*entit
 		}
 		return nil, fmt.Errorf("querying refresh token: %w", err)
 	}
+
+	if platform != nil {
+		rt.Platform = *platform
+	}
+	if accessTTL != nil {
+		rt.Policy.AccessTTL = time.Duration(*accessTTL) * time.Second
+	}
+	if refreshTTL != nil {
+		rt.Policy.RefreshTTL = time.Duration(*refreshTTL) * time.Second
+	}
+	if maxSessions != nil {
+		rt.Policy.MaxSessions = *maxSessions
+	}
 	return &rt, nil
 }
 
+func (r *RefreshTokenRepo) RevokeExcess(ctx context.Context, userID uuid.UUID, platform string, keep int) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = $4
+		WHERE id IN (
+			SELECT id FROM refresh_tokens
+			WHERE user_id = $1 AND platform = $2 AND revoked_at IS NULL AND expires_at > $4
+			ORDER BY created_at DESC
+			OFFSET $3
+		)
+	`
+	_, err := r.pool.Exec(ctx, query, userID, platform, keep, r.clock.Now())
+	if err != nil {
+		return fmt.Errorf("revoking excess sessions: %w", err)
+	}
+	return nil
+}
+
 func (r *RefreshTokenRepo) RevokeByUserID(ctx context.Context, userID uuid.UUID) error {
 	query := `
 		UPDATE refresh_tokens
-		SET revoked_at = NOW()
+		SET revoked_at = $2
 		WHERE user_id = $1 AND revoked_at IS NULL
 	`
-	_, err := r.pool.Exec(ctx, query, userID)
+	_, err := r.pool.Exec(ctx, query, userID, r.clock.Now())
 	if err != nil {
 		return fmt.Errorf("revoking tokens by user: %w", err)
 	}
@@ -70,23 +116,48 @@ func (r *RefreshTokenRepo) RevokeByUserID(ctx context.Context, userID uuid.UUID)
 func (r *RefreshTokenRepo) RevokeByDeviceID(ctx context.Context, deviceID uuid.UUID) error {
 	query := `
 		UPDATE refresh_tokens
-		SET revoked_at = NOW()
+		SET revoked_at = $2
 		WHERE device_id = $1 AND revoked_at IS NULL
 	`
-	_, err := r.pool.Exec(ctx, query, deviceID)
+	_, err := r.pool.Exec(ctx, query, deviceID, r.clock.Now())
 	if err != nil {
 		return fmt.Errorf("revoking tokens by device: %w", err)
 	}
 	return nil
 }
 
+func (r *RefreshTokenRepo) RevokeOthers(ctx context.Context, userID, keepID uuid.UUID) (int, error) {
+	query := `
+		WITH current AS (
+			SELECT id FROM refresh_tokens
+			WHERE id = $2 AND user_id = $1 AND revoked_at IS NULL AND expires_at > $3
+		), revoked AS (
+			UPDATE refresh_tokens
+			SET revoked_at = $3
+			WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL
+			  AND EXISTS (SELECT 1 FROM current)
+			RETURNING id
+		)
+		SELECT EXISTS (SELECT 1 FROM current), (SELECT COUNT(*) FROM revoked)
+	`
+	var active bool
+	var revoked int
+	if err := r.pool.QueryRow(ctx, query, userID, keepID, r.clock.Now()).Scan(&active, &revoked); err != nil {
+		return 0, fmt.Errorf("revoking other tokens: %w", err)
+	}
+	if !active {
+		return 0, domain.ErrTokenInvalid
+	}
+	return revoked, nil
+}
+
 func (r *RefreshTokenRepo) Revoke(ctx context.Context, id uuid.UUID) error {
 	query := `
 		UPDATE refresh_tokens
-		SET revoked_at = NOW()
+		SET revoked_at = $2
 		WHERE id = $1 AND revoked_at IS NULL
 	`
-	result, err := r.pool.Exec(ctx, query, id)
+	result, err := r.pool.Exec(ctx, query, id, r.clock.Now())
 	if err != nil {
 		return fmt.Errorf("revoking token: %w", err)
 	}
@@ -96,11 +167,11 @@ func (r *RefreshTokenRepo) Revoke(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-func (r *RefreshTokenRepo) DeleteExpired(ctx context.Context) error {
-	query := `DELETE FROM refresh_tokens WHERE expires_at < NOW() OR revoked_at IS NOT NULL`
-	_, err := r.pool.Exec(ctx, query)
+func (r *RefreshTokenRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	query := `DELETE FROM refresh_tokens WHERE expires_at < $1 OR revoked_at IS NOT NULL`
+	result, err := r.pool.Exec(ctx, query, r.clock.Now())
 	if err != nil {
-		return fmt.Errorf("deleting expired tokens: %w", err)
+		return 0, fmt.Errorf("deleting expired tokens: %w", err)
 	}
-	return nil
+	return result.RowsAffected(), nil
 }
@@ -35,7 +35,7 @@ func TestIntegrationRefreshTokenRepo_Create(t *testing.T) {
 	db := SetupTestDB(t)
 	defer db.Cleanup(t)
 
-	repo := postgres.NewRefreshTokenRepo(db.Pool)
+	repo := postgres.NewRefreshTokenRepo(db.Pool, nil)
 	ctx := context.Background()
 
 	t.Run("creates refresh token successfully", func(t *testing.T) {
@@ -54,7 +54,7 @@ func TestIntegrationRefreshTokenRepo_GetByToken(t *testing.T) {
 	db := SetupTestDB(t)
 	defer db.Cleanup(t)
 
-	repo := postgres.NewRefreshTokenRepo(db.Pool)
+	repo := postgres.NewRefreshTokenRepo(db.Pool, nil)
 	ctx := context.Background()
 
 	t.Run("returns token by value", func(t *testing.T) {
@@ -87,7 +87,7 @@ func TestIntegrationRefreshTokenRepo_Revoke(t *testing.T) {
 	db := SetupTestDB(t)
 	defer db.Cleanup(t)
 
-	repo := postgres.NewRefreshTokenRepo(db.Pool)
+	repo := postgres.NewRefreshTokenRepo(db.Pool, nil)
 	ctx := context.Background()
 
 	t.Run("revokes token successfully", func(t *testing.T) {
@@ -111,7 +111,7 @@ func TestIntegrationRefreshTokenRepo_RevokeByUserID(t *testing.T) {
 	db := SetupTestDB(t)
 	defer db.Cleanup(t)
 
-	repo := postgres.NewRefreshTokenRepo(db.Pool)
+	repo := postgres.NewRefreshTokenRepo(db.Pool, nil)
 	ctx := context.Background()
 
 	t.Run("revokes all tokens for user", func(t *testing.T) {
@@ -174,7 +174,7 @@ func TestIntegrationRefreshTokenRepo_RevokeByDeviceID(t *testing.T) {
 	db := SetupTestDB(t)
 	defer db.Cleanup(t)
 
-	repo := postgres.NewRefreshTokenRepo(db.Pool)
+	repo := postgres.NewRefreshTokenRepo(db.Pool, nil)
 	ctx := context.Background()
 
 	t.Run("revokes all tokens for device", func(t *testing.T) {
@@ -204,7 +204,7 @@ func TestIntegrationRefreshTokenRepo_DeleteExpired(t *testing.T) {
 	db := SetupTestDB(t)
 	defer db.Cleanup(t)
 
-	repo := postgres.NewRefreshTokenRepo(db.Pool)
+	repo := postgres.NewRefreshTokenRepo(db.Pool, nil)
 	ctx := context.Background()
 
 	t.Run("deletes expired tokens", func(t *testing.T) {
@@ -228,8 +228,9 @@ func TestIntegrationRefreshTokenRepo_DeleteExpired(t *testing.T) {
 		err = repo.Create(ctx, validToken)
 		require.NoError(t, err)
 
-		err = repo.DeleteExpired(ctx)
+		deleted, err := repo.DeleteExpired(ctx)
 		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
 
 		// Expired token should be gone
 		found, err := repo.GetByToken(ctx, "expired-token")
@@ -242,3 +243,90 @@ func TestIntegrationRefreshTokenRepo_DeleteExpired(t *testing.T) {
 		assert.NotNil(t, found)
 	})
 }
+
+func TestIntegrationRefreshTokenRepo_RevokeExcess(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewRefreshTokenRepo(db.Pool, nil)
+	ctx := context.Background()
+
+	t.Run("keeps the newest sessions on the platform", func(t *testing.T) {
+		db.Truncate(t, "refresh_tokens", "devices", "users")
+		user, device := createTestUserAndDevice(t, db)
+		policy := entity.SessionPolicy{AccessTTL: 5 * time.Minute, RefreshTTL: 12 * time.Hour, MaxSessions: 2}
+
+		for i, value := range []string{"web-1", "web-2", "web-3"} {
+			token := entity.NewRefreshToken(user.ID, device.ID, value, time.Now().Add(time.Hour))
+			token.CreatedAt = time.Now().Add(time.Duration(i) * time.Second)
+			token.Platform = entity.PlatformWeb
+			token.Policy = policy
+			require.NoError(t, repo.Create(ctx, token))
+		}
+		mobile := entity.NewRefreshToken(user.ID, device.ID, "ios-1", time.Now().Add(time.Hour))
+		mobile.Platform = entity.PlatformIOS
+		require.NoError(t, repo.Create(ctx, mobile))
+
+		require.NoError(t, repo.RevokeExcess(ctx, user.ID, entity.PlatformWeb, 2))
+
+		oldest, err := repo.GetByToken(ctx, "web-1")
+		require.NoError(t, err)
+		assert.True(t, oldest.IsRevoked())
+		assert.Equal(t, policy, oldest.Policy)
+
+		for _, value := range []string{"web-2", "web-3", "ios-1"} {
+			token, err := repo.GetByToken(ctx, value)
+			require.NoError(t, err)
+			assert.False(t, token.IsRevoked(), value)
+		}
+	})
+}
+
+func TestIntegrationRefreshTokenRepo_RevokeOthers(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewRefreshTokenRepo(db.Pool, nil)
+	ctx := context.Background()
+
+	t.Run("keeps only the current session", func(t *testing.T) {
+		db.Truncate(t, "refresh_tokens", "devices", "users")
+		user, device := createTestUserAndDevice(t, db)
+
+		current := entity.NewRefreshToken(user.ID, device.ID, "current", time.Now().Add(time.Hour))
+		require.NoError(t, repo.Create(ctx, current))
+		for _, value := range []string{"other-1", "other-2"} {
+			require.NoError(t, repo.Create(ctx, entity.NewRefreshToken(user.ID, device.ID, value, time.Now().Add(time.Hour))))
+		}
+
+		revoked, err := repo.RevokeOthers(ctx, user.ID, current.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, revoked)
+
+		token, err := repo.GetByToken(ctx, "current")
+		require.NoError(t, err)
+		assert.False(t, token.IsRevoked())
+
+		// Repeating the request is harmless.
+		revoked, err = repo.RevokeOthers(ctx, user.ID, current.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, revoked)
+	})
+
+	t.Run("revoked session cannot end the others", func(t *testing.T) {
+		db.Truncate(t, "refresh_tokens", "devices", "users")
+		user, device := createTestUserAndDevice(t, db)
+
+		ended := entity.NewRefreshToken(user.ID, device.ID, "ended", time.Now().Add(time.Hour))
+		require.NoError(t, repo.Create(ctx, ended))
+		require.NoError(t, repo.Revoke(ctx, ended.ID))
+		require.NoError(t, repo.Create(ctx, entity.NewRefreshToken(user.ID, device.ID, "active", time.Now().Add(time.Hour))))
+
+		_, err := repo.RevokeOthers(ctx, user.ID, ended.ID)
+		assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+
+		token, err := repo.GetByToken(ctx, "active")
+		require.NoError(t, err)
+		assert.False(t, token.IsRevoked())
+	})
+}
@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+)
+
+type AuthEventRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuthEventRepo(pool *pgxpool.Pool) *AuthEventRepo {
+	return &AuthEventRepo{pool: pool}
+}
+
+func (r *AuthEventRepo) Create(ctx context.Context, event *entity.AuthEvent) error {
+	var lat, lng *float64
+	if event.Location != nil {
+		lat, lng = &event.Location.Latitude, &event.Location.Longitude
+	}
+
+	query := `
+		INSERT INTO auth_events (id, user_id, device_id, kind, ip, city, country, latitude, longitude, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err := r.pool.Exec(ctx, query,
+		event.ID, event.UserID, event.DeviceID, event.Kind, event.IP,
+		nullableString(event.City), nullableString(event.Country), lat, lng, event.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("inserting auth event: %w", err)
+	}
+	return nil
+}
+
+func (r *AuthEventRepo) ListSince(ctx context.Context, since time.Time) ([]entity.AuthEvent, error) {
+	query := `
+		SELECT id, user_id, device_id, kind, ip, COALESCE(city, ''), COALESCE(country, ''),
+		       latitude, longitude, created_at
+		FROM auth_events
+		WHERE created_at >= $1
+		ORDER BY user_id, created_at
+	`
+	rows, err := r.pool.Query(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("querying auth events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []entity.AuthEvent
+	for rows.Next() {
+		var e entity.AuthEvent
+		var lat, lng *float64
+		if err := rows.Scan(
+			&e.ID, &e.UserID, &e.DeviceID, &e.Kind, &e.IP, &e.City, &e.Country,
+			&lat, &lng, &e.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scanning auth event: %w", err)
+		}
+		if lat != nil && lng != nil {
+			e.Location = valueobject.NewLocation(*lat, *lng, nil, nil)
+		}
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating auth events: %w", err)
+	}
+
+	return events, nil
+}
+
+func (r *AuthEventRepo) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM auth_events WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("deleting auth events: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+type SecurityAlertRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewSecurityAlertRepo(pool *pgxpool.Pool) *SecurityAlertRepo {
+	return &SecurityAlertRepo{pool: pool}
+}
+
+func (r *SecurityAlertRepo) Create(ctx context.Context, alert *entity.SecurityAlert) (bool, error) {
+	query := `
+		INSERT INTO security_alerts (id, user_id, device_id, kind, fingerprint, detail, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id, kind, fingerprint) DO NOTHING
+	`
+	tag, err := r.pool.Exec(ctx, query,
+		alert.ID, alert.UserID, alert.DeviceID, alert.Kind, alert.Fingerprint, alert.Detail, alert.CreatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("inserting security alert: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *SecurityAlertRepo) ListForAdmin(ctx context.Context, adminID uuid.UUID, params repository.AlertListParams) ([]entity.SecurityAlert, *pagination.Info, error) {
+	// An admin of several organizations sharing a member must see each alert once.
+	from := `
+		FROM security_alerts s
+		WHERE EXISTS (
+			SELECT 1
+			FROM org_members a
+			JOIN org_members m ON m.org_id = a.org_id
+			WHERE a.user_id = $1 AND a.role = $2 AND m.user_id = s.user_id
+		)
+		AND ($3::text = '' OR s.kind = $3)
+	`
+	args := []any{adminID, entity.OrgRoleAdmin, params.Kind}
+
+	var total int
+	if err := r.pool.QueryRow(ctx, "SELECT COUNT(*) "+from, args...).Scan(&total); err != nil {
+		return nil, nil, fmt.Errorf("counting security alerts: %w", err)
+	}
+
+	query := `
+		SELECT s.id, s.user_id, s.device_id, s.kind, s.fingerprint, s.detail, s.created_at
+	` + from + `
+		ORDER BY s.created_at DESC
+		LIMIT $4 OFFSET $5
+	`
+	rows, err := r.pool.Query(ctx, query, append(args, params.Pagination.Limit(), params.Pagination.Offset())...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("querying security alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []entity.SecurityAlert
+	for rows.Next() {
+		var a entity.SecurityAlert
+		if err := rows.Scan(&a.ID, &a.UserID, &a.DeviceID, &a.Kind, &a.Fingerprint, &a.Detail, &a.CreatedAt); err != nil {
+			return nil, nil, fmt.Errorf("scanning security alert: %w", err)
+		}
+		alerts = append(alerts, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("iterating security alerts: %w", err)
+	}
+
+	return alerts, pagination.NewInfo(params.Pagination.Page, params.Pagination.PerPage, total), nil
+}
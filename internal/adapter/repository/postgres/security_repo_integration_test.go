@@ -0,0 +1,100 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository/postgres"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+)
+
+func TestIntegrationAuthEventRepo(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewAuthEventRepo(db.Pool)
+	ctx := context.Background()
+
+	db.Truncate(t, "auth_events", "devices", "users")
+	user, device := createTestUserAndDevice(t, db)
+
+	now := time.Now().UTC()
+	old := entity.NewAuthEvent(user.ID, device.ID, entity.AuthEventLogin, entity.DeviceAccess{IP: "203.0.113.7", At: now.Add(-48 * time.Hour)}, nil)
+	recent := entity.NewAuthEvent(user.ID, device.ID, entity.AuthEventRefresh, entity.DeviceAccess{
+		IP: "203.0.113.8", City: "Lisbon", Country: "PT", At: now,
+	}, valueobject.NewLocation(38.72, -9.14, nil, nil))
+	require.NoError(t, repo.Create(ctx, old))
+	require.NoError(t, repo.Create(ctx, recent))
+
+	events, err := repo.ListSince(ctx, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, recent.ID, events[0].ID)
+	assert.Equal(t, "Lisbon", events[0].City)
+	require.NotNil(t, events[0].Location)
+	assert.InDelta(t, 38.72, events[0].Location.Latitude, 0.0001)
+
+	deleted, err := repo.DeleteBefore(ctx, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+}
+
+func TestIntegrationSecurityAlertRepo(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewSecurityAlertRepo(db.Pool)
+	userRepo := postgres.NewUserRepo(db.Pool)
+	orgRepo := postgres.NewOrganizationRepo(db.Pool, newTestSecretBox(t))
+	ctx := context.Background()
+
+	db.Truncate(t, "security_alerts", "org_members", "organizations", "users")
+	member := createTestUser(t, db)
+	admin := entity.NewUser("admin@example.com", "hashedpassword", "Admin")
+	require.NoError(t, userRepo.Create(ctx, admin))
+
+	orgID := uuid.New()
+	_, err := db.Pool.Exec(ctx, `
+		INSERT INTO organizations (id, slug, name, domain, oidc_issuer, oidc_client_id, oidc_client_secret)
+		VALUES ($1, 'acme', 'Acme', 'example.com', 'https://idp.example.com', 'client', 'secret')
+	`, orgID)
+	require.NoError(t, err)
+	require.NoError(t, orgRepo.UpsertMember(ctx, entity.NewOrgMembership(orgID, member.ID, "")))
+	adminMembership := entity.NewOrgMembership(orgID, admin.ID, "")
+	adminMembership.Role = entity.OrgRoleAdmin
+	require.NoError(t, orgRepo.UpsertMember(ctx, adminMembership))
+
+	alert := entity.NewSecurityAlert(member.ID, nil, entity.AlertRefreshStorm, "device/window", "too many refreshes")
+	created, err := repo.Create(ctx, alert)
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	duplicate := entity.NewSecurityAlert(member.ID, nil, entity.AlertRefreshStorm, "device/window", "too many refreshes")
+	created, err = repo.Create(ctx, duplicate)
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	params := repository.AlertListParams{Pagination: pagination.NewParams(1, 20)}
+	alerts, info, err := repo.ListForAdmin(ctx, admin.ID, params)
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, alert.ID, alerts[0].ID)
+	assert.Equal(t, 1, info.TotalItems)
+
+	alerts, _, err = repo.ListForAdmin(ctx, member.ID, params)
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+
+	params.Kind = entity.AlertImpossibleTravel
+	alerts, _, err = repo.ListForAdmin(ctx, admin.ID, params)
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+}
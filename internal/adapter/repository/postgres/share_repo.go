@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+type ShareRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewShareRepo(pool *pgxpool.Pool) *ShareRepo {
+	return &ShareRepo{pool: pool}
+}
+
+func (r *ShareRepo) Apply(ctx context.Context, grants []entity.NoteShare, noteIDs, revokeUserIDs []uuid.UUID) (int64, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var revoked int64
+	if len(noteIDs) > 0 && len(revokeUserIDs) > 0 {
+		result, err := tx.Exec(ctx,
+			`DELETE FROM note_shares WHERE note_id = ANY($1) AND user_id = ANY($2)`,
+			noteIDs, revokeUserIDs,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("revoking shares: %w", err)
+		}
+		revoked = result.RowsAffected()
+	}
+
+	query := `
+		INSERT INTO note_shares (note_id, user_id, role, granted_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (note_id, user_id)
+		DO UPDATE SET role = EXCLUDED.role, granted_by = EXCLUDED.granted_by, updated_at = EXCLUDED.updated_at
+	`
+	for _, s := range grants {
+		if _, err := tx.Exec(ctx, query, s.NoteID, s.UserID, s.Role, s.GrantedBy, s.CreatedAt, s.UpdatedAt); err != nil {
+			return 0, fmt.Errorf("granting share: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return revoked, nil
+}
+
+func (r *ShareRepo) ListByNoteID(ctx context.Context, noteID uuid.UUID) ([]entity.NoteShare, error) {
+	query := `
+		SELECT note_id, user_id, role, granted_by, created_at, updated_at
+		FROM note_shares
+		WHERE note_id = $1
+		ORDER BY created_at ASC
+	`
+	rows, err := r.pool.Query(ctx, query, noteID)
+	if err != nil {
+		return nil, fmt.Errorf("querying shares: %w", err)
+	}
+	defer rows.Close()
+
+	var shares []entity.NoteShare
+	for rows.Next() {
+		var s entity.NoteShare
+		if err := rows.Scan(&s.NoteID, &s.UserID, &s.Role, &s.GrantedBy, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scanning share: %w", err)
+		}
+		shares = append(shares, s)
+	}
+
+	return shares, rows.Err()
+}
+
+func (r *ShareRepo) GetRole(ctx context.Context, noteID, userID uuid.UUID) (string, error) {
+	query := `SELECT role FROM note_shares WHERE note_id = $1 AND user_id = $2`
+
+	var role string
+	err := r.pool.QueryRow(ctx, query, noteID, userID).Scan(&role)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("querying share role: %w", err)
+	}
+	return role, nil
+}
@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type StorageOrphanRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewStorageOrphanRepo(pool *pgxpool.Pool) *StorageOrphanRepo {
+	return &StorageOrphanRepo{pool: pool}
+}
+
+func (r *StorageOrphanRepo) List(ctx context.Context, limit int) ([]string, error) {
+	rows, err := r.pool.Query(ctx, `SELECT key FROM storage_orphans ORDER BY created_at, key LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying storage orphans: %w", err)
+	}
+	keys, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("scanning storage orphans: %w", err)
+	}
+	return keys, nil
+}
+
+func (r *StorageOrphanRepo) Remove(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	if _, err := r.pool.Exec(ctx, `DELETE FROM storage_orphans WHERE key = ANY($1)`, keys); err != nil {
+		return fmt.Errorf("removing storage orphans: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository/postgres"
+)
+
+func TestIntegrationStorageOrphanRepo(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewStorageOrphanRepo(db.Pool)
+	ctx := context.Background()
+
+	t.Run("lists oldest first and removes deleted keys", func(t *testing.T) {
+		db.Truncate(t, "storage_orphans")
+		_, err := db.Pool.Exec(ctx, `
+			INSERT INTO storage_orphans (key, created_at) VALUES
+				('notes/b.jpg', NOW() - INTERVAL '1 hour'),
+				('notes/a.jpg', NOW() - INTERVAL '2 hours'),
+				('notes/c.jpg', NOW())
+		`)
+		require.NoError(t, err)
+
+		keys, err := repo.List(ctx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"notes/a.jpg", "notes/b.jpg"}, keys)
+
+		require.NoError(t, repo.Remove(ctx, keys))
+
+		keys, err = repo.List(ctx, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"notes/c.jpg"}, keys)
+	})
+}
@@ -0,0 +1,190 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+)
+
+type SyncConflictRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewSyncConflictRepo(pool *pgxpool.Pool) *SyncConflictRepo {
+	return &SyncConflictRepo{pool: pool}
+}
+
+func (r *SyncConflictRepo) Save(ctx context.Context, conflict *entity.SyncConflict) error {
+	query := `
+		INSERT INTO sync_conflicts (id, user_id, note_id, client_id, device_id, client_version,
+									server_updated_at, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (note_id, device_id) WHERE resolved_at IS NULL
+		DO UPDATE SET
+			client_version = EXCLUDED.client_version,
+			server_updated_at = EXCLUDED.server_updated_at,
+			created_at = EXCLUDED.created_at,
+			expires_at = EXCLUDED.expires_at
+		RETURNING id
+	`
+	err := r.pool.QueryRow(ctx, query,
+		conflict.ID, conflict.UserID, conflict.NoteID, conflict.ClientID, conflict.DeviceID,
+		newConflictVersionRow(&conflict.ClientVersion),
+		conflict.ServerUpdatedAt, conflict.CreatedAt, conflict.ExpiresAt,
+	).Scan(&conflict.ID)
+	if err != nil {
+		return fmt.Errorf("saving sync conflict: %w", err)
+	}
+	return nil
+}
+
+func (r *SyncConflictRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.SyncConflict, error) {
+	query := `SELECT ` + syncConflictColumns + ` FROM sync_conflicts WHERE id = $1`
+	conflict, err := scanSyncConflict(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrSyncConflictNotFound
+		}
+		return nil, fmt.Errorf("querying sync conflict: %w", err)
+	}
+	return conflict, nil
+}
+
+func (r *SyncConflictRepo) ListPending(ctx context.Context, userID uuid.UUID, now time.Time) ([]entity.SyncConflict, error) {
+	query := `
+		SELECT ` + syncConflictColumns + `
+		FROM sync_conflicts
+		WHERE user_id = $1 AND resolved_at IS NULL AND expires_at > $2
+		ORDER BY created_at, id
+	`
+	rows, err := r.pool.Query(ctx, query, userID, now)
+	if err != nil {
+		return nil, fmt.Errorf("querying sync conflicts: %w", err)
+	}
+	defer rows.Close()
+
+	var conflicts []entity.SyncConflict
+	for rows.Next() {
+		conflict, err := scanSyncConflict(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning sync conflict: %w", err)
+		}
+		conflicts = append(conflicts, *conflict)
+	}
+	return conflicts, rows.Err()
+}
+
+func (r *SyncConflictRepo) Resolve(ctx context.Context, conflict *entity.SyncConflict) error {
+	query := `
+		UPDATE sync_conflicts
+		SET resolution = $2, resolved_at = $3
+		WHERE id = $1 AND resolved_at IS NULL
+	`
+	result, err := r.pool.Exec(ctx, query, conflict.ID, conflict.Resolution, conflict.ResolvedAt)
+	if err != nil {
+		return fmt.Errorf("resolving sync conflict: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrSyncConflictResolved
+	}
+	return nil
+}
+
+func (r *SyncConflictRepo) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.pool.Exec(ctx, `DELETE FROM sync_conflicts WHERE expires_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("deleting expired sync conflicts: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+const syncConflictColumns = `id, user_id, note_id, client_id, device_id, client_version,
+	server_updated_at, created_at, expires_at, resolution, resolved_at`
+
+func scanSyncConflict(row pgx.Row) (*entity.SyncConflict, error) {
+	var conflict entity.SyncConflict
+	var version conflictVersionRow
+	var resolution *string
+	if err := row.Scan(
+		&conflict.ID, &conflict.UserID, &conflict.NoteID, &conflict.ClientID, &conflict.DeviceID, &version,
+		&conflict.ServerUpdatedAt, &conflict.CreatedAt, &conflict.ExpiresAt, &resolution, &conflict.ResolvedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if resolution != nil {
+		conflict.Resolution = *resolution
+	}
+	conflict.ClientVersion = version.toEntity(&conflict)
+	return &conflict, nil
+}
+
+// conflictVersionRow is the JSON shape of sync_conflicts.client_version.
+type conflictVersionRow struct {
+	Title        string           `json:"title"`
+	Content      string           `json:"content"`
+	Latitude     *float64         `json:"latitude,omitempty"`
+	Longitude    *float64         `json:"longitude,omitempty"`
+	Altitude     *float64         `json:"altitude,omitempty"`
+	Accuracy     *float64         `json:"accuracy,omitempty"`
+	Measurements []measurementRow `json:"measurements"`
+	// Tags is null when the client left the note's tags unchanged.
+	Tags      []string  `json:"tags"`
+	Deleted   bool      `json:"deleted"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Color and Icon are absent when the client left them unchanged.
+	Color *string `json:"color,omitempty"`
+	Icon  *string `json:"icon,omitempty"`
+}
+
+func newConflictVersionRow(note *entity.Note) conflictVersionRow {
+	row := conflictVersionRow{
+		Title:        note.Title,
+		Content:      note.Content,
+		Measurements: measurementRows(note.Measurements),
+		Tags:         note.Tags,
+		Deleted:      note.IsDeleted(),
+		UpdatedAt:    note.UpdatedAt,
+		Color:        note.Color,
+		Icon:         note.Icon,
+	}
+	if loc := note.Location; loc != nil {
+		row.Latitude, row.Longitude = &loc.Latitude, &loc.Longitude
+		row.Altitude, row.Accuracy = loc.Altitude, loc.Accuracy
+	}
+	return row
+}
+
+func (v conflictVersionRow) toEntity(conflict *entity.SyncConflict) entity.Note {
+	note := entity.Note{
+		ID:        conflict.NoteID,
+		UserID:    conflict.UserID,
+		ClientID:  conflict.ClientID,
+		Title:     v.Title,
+		Content:   v.Content,
+		Tags:      v.Tags,
+		UpdatedAt: v.UpdatedAt,
+		Color:     v.Color,
+		Icon:      v.Icon,
+	}
+	if v.Latitude != nil && v.Longitude != nil {
+		note.Location = valueobject.NewLocation(*v.Latitude, *v.Longitude, v.Altitude, v.Accuracy)
+	}
+	for _, m := range v.Measurements {
+		note.Measurements = append(note.Measurements, valueobject.Measurement{Name: m.Name, Kind: m.Kind, Value: m.Value})
+	}
+	if v.Deleted {
+		deletedAt := v.UpdatedAt
+		note.DeletedAt = &deletedAt
+	}
+	return note
+}
@@ -0,0 +1,86 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository/postgres"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+)
+
+func TestIntegrationSyncConflictRepo(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewSyncConflictRepo(db.Pool)
+	noteRepo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
+	ctx := context.Background()
+
+	db.Truncate(t, "sync_conflicts", "notes", "users")
+	user := createTestUser(t, db)
+	note := entity.NewNote(user.ID, "Server Version", "Edited on the web", nil, "conflict-note")
+	require.NoError(t, noteRepo.Create(ctx, note))
+
+	clientVersion := *entity.NewNote(user.ID, "Client Version", "Edited offline", valueobject.NewLocation(38.7, -9.1, nil, nil), "conflict-note")
+	clientVersion.Tags = []string{"plot-7"}
+	conflict := entity.NewSyncConflict(note, clientVersion, "tablet", time.Hour)
+
+	t.Run("saves and reads the client version", func(t *testing.T) {
+		require.NoError(t, repo.Save(ctx, conflict))
+
+		got, err := repo.GetByID(ctx, conflict.ID)
+		require.NoError(t, err)
+		assert.Equal(t, note.ID, got.NoteID)
+		assert.Equal(t, "Client Version", got.ClientVersion.Title)
+		assert.Equal(t, []string{"plot-7"}, got.ClientVersion.Tags)
+		require.NotNil(t, got.ClientVersion.Location)
+		assert.InDelta(t, 38.7, got.ClientVersion.Location.Latitude, 1e-9)
+		assert.True(t, got.IsPending())
+	})
+
+	t.Run("replaces the pending conflict of the same device", func(t *testing.T) {
+		clientVersion.Title = "Client Version 2"
+		again := entity.NewSyncConflict(note, clientVersion, "tablet", time.Hour)
+		require.NoError(t, repo.Save(ctx, again))
+
+		assert.Equal(t, conflict.ID, again.ID)
+		pending, err := repo.ListPending(ctx, user.ID, time.Now())
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, "Client Version 2", pending[0].ClientVersion.Title)
+	})
+
+	t.Run("resolves once", func(t *testing.T) {
+		conflict.Resolve(entity.ConflictSideServer)
+		require.NoError(t, repo.Resolve(ctx, conflict))
+
+		assert.ErrorIs(t, repo.Resolve(ctx, conflict), domain.ErrSyncConflictResolved)
+		pending, err := repo.ListPending(ctx, user.ID, time.Now())
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+	})
+
+	t.Run("deletes expired conflicts", func(t *testing.T) {
+		deleted, err := repo.DeleteExpired(ctx, time.Now().Add(2*time.Hour))
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, deleted)
+
+		_, err = repo.GetByID(ctx, conflict.ID)
+		assert.ErrorIs(t, err, domain.ErrSyncConflictNotFound)
+	})
+
+	t.Run("goes with its hard-deleted note", func(t *testing.T) {
+		pending := entity.NewSyncConflict(note, clientVersion, "phone", time.Hour)
+		require.NoError(t, repo.Save(ctx, pending))
+		require.NoError(t, noteRepo.Purge(ctx, user.ID))
+
+		_, err := repo.GetByID(ctx, pending.ID)
+		assert.ErrorIs(t, err, domain.ErrSyncConflictNotFound)
+	})
+}
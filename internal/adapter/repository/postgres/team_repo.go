@@ -0,0 +1,218 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+type TeamRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewTeamRepo(pool *pgxpool.Pool) *TeamRepo {
+	return &TeamRepo{pool: pool}
+}
+
+func (r *TeamRepo) Create(ctx context.Context, team *entity.Team, owner *entity.TeamMember) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO teams (id, name, created_by, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)`,
+		team.ID, team.Name, team.CreatedBy, team.CreatedAt, team.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("inserting team: %w", err)
+	}
+
+	if err := insertTeamMember(ctx, tx, owner); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *TeamRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.Team, error) {
+	query := `SELECT id, name, created_by, created_at, updated_at FROM teams WHERE id = $1`
+
+	var team entity.Team
+	var createdBy *uuid.UUID
+	err := r.pool.QueryRow(ctx, query, id).Scan(&team.ID, &team.Name, &createdBy, &team.CreatedAt, &team.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrTeamNotFound
+		}
+		return nil, fmt.Errorf("querying team: %w", err)
+	}
+	if createdBy != nil {
+		team.CreatedBy = *createdBy
+	}
+	return &team, nil
+}
+
+func (r *TeamRepo) ListByUserID(ctx context.Context, userID uuid.UUID) ([]entity.UserTeam, error) {
+	query := `
+		SELECT t.id, t.name, t.created_by, t.created_at, t.updated_at,
+			   m.team_id, m.user_id, m.role, m.invited_by, m.created_at, m.accepted_at
+		FROM teams t
+		JOIN team_members m ON m.team_id = t.id
+		WHERE m.user_id = $1
+		ORDER BY t.name ASC, t.id ASC
+	`
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("querying teams: %w", err)
+	}
+	defer rows.Close()
+
+	var teams []entity.UserTeam
+	for rows.Next() {
+		var t entity.UserTeam
+		var createdBy *uuid.UUID
+		m := &t.Membership
+		if err := rows.Scan(
+			&t.ID, &t.Name, &createdBy, &t.CreatedAt, &t.UpdatedAt,
+			&m.TeamID, &m.UserID, &m.Role, &m.InvitedBy, &m.CreatedAt, &m.AcceptedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scanning team: %w", err)
+		}
+		if createdBy != nil {
+			t.CreatedBy = *createdBy
+		}
+		teams = append(teams, t)
+	}
+
+	return teams, rows.Err()
+}
+
+func (r *TeamRepo) ListMembers(ctx context.Context, teamID uuid.UUID) ([]entity.TeamMember, error) {
+	query := `
+		SELECT m.team_id, m.user_id, u.email, m.role, m.invited_by, m.created_at, m.accepted_at
+		FROM team_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.team_id = $1
+		ORDER BY m.created_at ASC, u.email ASC
+	`
+	rows, err := r.pool.Query(ctx, query, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("querying team members: %w", err)
+	}
+	defer rows.Close()
+
+	var members []entity.TeamMember
+	for rows.Next() {
+		var m entity.TeamMember
+		if err := rows.Scan(&m.TeamID, &m.UserID, &m.Email, &m.Role, &m.InvitedBy, &m.CreatedAt, &m.AcceptedAt); err != nil {
+			return nil, fmt.Errorf("scanning team member: %w", err)
+		}
+		members = append(members, m)
+	}
+
+	return members, rows.Err()
+}
+
+func (r *TeamRepo) GetMember(ctx context.Context, teamID, userID uuid.UUID) (*entity.TeamMember, error) {
+	query := `
+		SELECT team_id, user_id, role, invited_by, created_at, accepted_at
+		FROM team_members
+		WHERE team_id = $1 AND user_id = $2
+	`
+	var m entity.TeamMember
+	err := r.pool.QueryRow(ctx, query, teamID, userID).Scan(&m.TeamID, &m.UserID, &m.Role, &m.InvitedBy, &m.CreatedAt, &m.AcceptedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrTeamMemberNotFound
+		}
+		return nil, fmt.Errorf("querying team member: %w", err)
+	}
+	return &m, nil
+}
+
+func (r *TeamRepo) AddMember(ctx context.Context, member *entity.TeamMember) error {
+	return insertTeamMember(ctx, r.pool, member)
+}
+
+// execer is the pool or a transaction.
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+func insertTeamMember(ctx context.Context, db execer, m *entity.TeamMember) error {
+	_, err := db.Exec(ctx, `
+		INSERT INTO team_members (team_id, user_id, role, invited_by, created_at, accepted_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, m.TeamID, m.UserID, m.Role, m.InvitedBy, m.CreatedAt, m.AcceptedAt)
+	if err != nil {
+		if hasCode(err, codeUniqueViolation) {
+			return domain.ErrAlreadyTeamMember
+		}
+		return fmt.Errorf("inserting team member: %w", err)
+	}
+	return nil
+}
+
+func (r *TeamRepo) Accept(ctx context.Context, teamID, userID uuid.UUID, at time.Time) error {
+	result, err := r.pool.Exec(ctx, `
+		UPDATE team_members SET accepted_at = $3
+		WHERE team_id = $1 AND user_id = $2 AND accepted_at IS NULL
+	`, teamID, userID, at)
+	if err != nil {
+		return fmt.Errorf("accepting team invite: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrTeamMemberNotFound
+	}
+	return nil
+}
+
+func (r *TeamRepo) UpdateRole(ctx context.Context, teamID, userID uuid.UUID, role string) error {
+	result, err := r.pool.Exec(ctx, `UPDATE team_members SET role = $3 WHERE team_id = $1 AND user_id = $2`, teamID, userID, role)
+	if err != nil {
+		return fmt.Errorf("updating team role: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrTeamMemberNotFound
+	}
+	return nil
+}
+
+func (r *TeamRepo) RemoveMember(ctx context.Context, teamID, userID uuid.UUID) error {
+	result, err := r.pool.Exec(ctx, `DELETE FROM team_members WHERE team_id = $1 AND user_id = $2`, teamID, userID)
+	if err != nil {
+		return fmt.Errorf("removing team member: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrTeamMemberNotFound
+	}
+	return nil
+}
+
+func (r *TeamRepo) GetRole(ctx context.Context, teamID, userID uuid.UUID) (string, error) {
+	query := `SELECT role FROM team_members WHERE team_id = $1 AND user_id = $2 AND accepted_at IS NOT NULL`
+
+	var role string
+	err := r.pool.QueryRow(ctx, query, teamID, userID).Scan(&role)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("querying team role: %w", err)
+	}
+	return role, nil
+}
@@ -0,0 +1,111 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository/postgres"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+)
+
+func TestIntegrationTeamRepo(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	teamRepo := postgres.NewTeamRepo(db.Pool)
+	noteRepo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
+	userRepo := postgres.NewUserRepo(db.Pool)
+	ctx := context.Background()
+
+	db.Truncate(t, "notes", "team_members", "teams", "users")
+	owner := entity.NewUser("owner@example.com", "hashedpassword", "Owner")
+	colleague := entity.NewUser("colleague@example.com", "hashedpassword", "Colleague")
+	require.NoError(t, userRepo.Create(ctx, owner))
+	require.NoError(t, userRepo.Create(ctx, colleague))
+
+	team := entity.NewTeam("River survey", owner.ID)
+	require.NoError(t, teamRepo.Create(ctx, team, &entity.TeamMember{
+		TeamID: team.ID, UserID: owner.ID, Role: entity.TeamRoleOwner, CreatedAt: team.CreatedAt, AcceptedAt: &team.CreatedAt,
+	}))
+
+	note := entity.NewNote(owner.ID, "Plot 1", "Clay", nil, "plot-1")
+	note.TeamID = &team.ID
+	require.NoError(t, noteRepo.Create(ctx, note))
+
+	t.Run("invitations grant no role until accepted", func(t *testing.T) {
+		require.NoError(t, teamRepo.AddMember(ctx, entity.NewTeamInvite(team.ID, colleague.ID, entity.TeamRoleEditor, owner.ID)))
+		assert.ErrorIs(t, teamRepo.AddMember(ctx, entity.NewTeamInvite(team.ID, colleague.ID, entity.TeamRoleAdmin, owner.ID)), domain.ErrAlreadyTeamMember)
+
+		role, err := teamRepo.GetRole(ctx, team.ID, colleague.ID)
+		require.NoError(t, err)
+		assert.Empty(t, role)
+
+		changed, err := noteRepo.GetModifiedAfter(ctx, colleague.ID, pagination.Cursor{}, 10, entity.SyncScope{})
+		require.NoError(t, err)
+		assert.Empty(t, changed)
+
+		require.NoError(t, teamRepo.Accept(ctx, team.ID, colleague.ID, time.Now().UTC()))
+		assert.ErrorIs(t, teamRepo.Accept(ctx, team.ID, colleague.ID, time.Now().UTC()), domain.ErrTeamMemberNotFound)
+
+		role, err = teamRepo.GetRole(ctx, team.ID, colleague.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entity.TeamRoleEditor, role)
+	})
+
+	t.Run("members sync and list the team's notes", func(t *testing.T) {
+		changed, err := noteRepo.GetModifiedAfter(ctx, colleague.ID, pagination.Cursor{}, 10, entity.SyncScope{})
+		require.NoError(t, err)
+		require.Len(t, changed, 1)
+		assert.Equal(t, note.ID, changed[0].ID)
+		assert.Equal(t, &team.ID, changed[0].TeamID)
+
+		listed, _, err := noteRepo.List(ctx, colleague.ID, repository.NoteListParams{
+			Pagination: pagination.NewParams(1, 20),
+			TeamID:     &team.ID,
+		})
+		require.NoError(t, err)
+		require.Len(t, listed, 1)
+		assert.Equal(t, note.ID, listed[0].ID)
+	})
+
+	t.Run("lists teams and members", func(t *testing.T) {
+		teams, err := teamRepo.ListByUserID(ctx, colleague.ID)
+		require.NoError(t, err)
+		require.Len(t, teams, 1)
+		assert.Equal(t, "River survey", teams[0].Name)
+		assert.Equal(t, owner.ID, teams[0].CreatedBy)
+		assert.False(t, teams[0].Membership.Pending())
+
+		members, err := teamRepo.ListMembers(ctx, team.ID)
+		require.NoError(t, err)
+		require.Len(t, members, 2)
+		assert.Equal(t, "owner@example.com", members[0].Email)
+		assert.Equal(t, owner.ID, *members[1].InvitedBy)
+	})
+
+	t.Run("changes a member's role", func(t *testing.T) {
+		require.NoError(t, teamRepo.UpdateRole(ctx, team.ID, colleague.ID, entity.TeamRoleViewer))
+		assert.ErrorIs(t, teamRepo.UpdateRole(ctx, team.ID, uuid.New(), entity.TeamRoleViewer), domain.ErrTeamMemberNotFound)
+
+		role, err := teamRepo.GetRole(ctx, team.ID, colleague.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entity.TeamRoleViewer, role)
+	})
+
+	t.Run("removed members lose the team's notes", func(t *testing.T) {
+		require.NoError(t, teamRepo.RemoveMember(ctx, team.ID, colleague.ID))
+		assert.ErrorIs(t, teamRepo.RemoveMember(ctx, team.ID, colleague.ID), domain.ErrTeamMemberNotFound)
+
+		changed, err := noteRepo.GetModifiedAfter(ctx, colleague.ID, pagination.Cursor{}, 10, entity.SyncScope{})
+		require.NoError(t, err)
+		assert.Empty(t, changed)
+	})
+}
@@ -3,8 +3,6 @@ package postgres_test
 import (
 	"context"
 	"fmt"
-	"path/filepath"
-	"runtime"
 	"testing"
 	"time"
 
@@ -13,9 +11,22 @@ import (
 	"github.com/testcontainers/testcontainers-go/modules/postgres"
 	"github.com/testcontainers/testcontainers-go/wait"
 
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/auth"
 	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/database"
+	"github.com/marcos-nsantos/field-notes-backend/migrations"
 )
 
+const testSecretKey = "IxhGeSNi1MOzqY+dTltMC9dLkiPjhwbGZM9zN+e1sNg="
+
+func newTestSecretBox(t *testing.T) *auth.SecretBox {
+	t.Helper()
+	box, err := auth.NewSecretBox(testSecretKey)
+	if err != nil {
+		t.Fatalf("creating secret box: %v", err)
+	}
+	return box
+}
+
 type TestDB struct {
 	Pool      *pgxpool.Pool
 	Container testcontainers.Container
@@ -62,8 +73,7 @@ func SetupTestDB(t *testing.T) *TestDB {
 		t.Fatalf("failed to create pool: %v", err)
 	}
 
-	migrationsPath := getMigrationsPath()
-	if err := database.RunMigrations(ctx, pool, migrationsPath); err != nil {
+	if err := database.RunMigrations(ctx, pool, migrations.FS); err != nil {
 		t.Fatalf("failed to run migrations: %v", err)
 	}
 
@@ -95,10 +105,3 @@ func (db *TestDB) Truncate(t *testing.T, tables ...string) {
 		}
 	}
 }
-
-// getMigrationsPath returns the absolute path to the migrations directory
-func getMigrationsPath() string {
-	_, filename, _, _ := runtime.Caller(0)
-	repoDir := filepath.Dir(filename)
-	return filepath.Join(repoDir, "..", "..", "..", "..", "migrations")
-}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
 )
 
 type UserRepo struct {
@@ -23,27 +24,32 @@ func NewUserRepo(pool *pgxpool.Pool) *UserRepo {
 
 func (r *UserRepo) Create(ctx context.Context, user *entity.User) error {
 	query := `
-		INSERT INTO users (id, email, password_hash, name, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO users (id, email, password_hash, name, unit_system, note_prefix, notify_sync_conflicts, semantic_search, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 	_, err := r.pool.Exec(ctx, query,
-		user.ID, user.Email, user.PasswordHash, user.Name, user.CreatedAt, user.UpdatedAt,
+		user.ID, user.Email, user.PasswordHash, user.Name, unitSystem(user.UnitSystem), notePrefix(user.NotePrefix), user.NotifySyncConflicts,
+		user.SemanticSearch, user.CreatedAt, user.UpdatedAt,
 	)
 	if err != nil {
+		if hasCode(err, codeUniqueViolation) {
+			return domain.ErrUserAlreadyExists
+		}
 		return fmt.Errorf("inserting user: %w", err)
 	}
 	return nil
 }
 
+const userByIDQuery = `
+	SELECT id, email, password_hash, name, unit_system, note_prefix, notify_sync_conflicts, semantic_search, token_version, created_at, updated_at
+	FROM users
+	WHERE id = $1 AND deleted_at IS NULL
+`
+
 func (r *UserRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
-	query := `
-		SELECT id, email, password_hash, name, created_at, updated_at
-		FROM users
-		WHERE id = $1
-	`
 	var user entity.User
-	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&user.ID, &user.Email, &user.PasswordHash, &user.Name, &user.CreatedAt, &user.UpdatedAt,
+	err := r.pool.QueryRow(ctx, userByIDQuery, id).Scan(
+		&user.ID, &user.Email, &user.PasswordHash, &user.Name, &user.UnitSystem, &user.NotePrefix, &user.NotifySyncConflicts, &user.SemanticSearch, &user.TokenVersion, &user.CreatedAt, &user.UpdatedAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -56,13 +62,13 @@ func (r *UserRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.User, err
 
 func (r *UserRepo) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
 	query := `
-		SELECT id, email, password_hash, name, created_at, updated_at
+		SELECT id, email, password_hash, name, unit_system, note_prefix, notify_sync_conflicts, semantic_search, token_version, created_at, updated_at
 		FROM users
-		WHERE email = $1
+		WHERE email = $1 AND deleted_at IS NULL
 	`
 	var user entity.User
 	err := r.pool.QueryRow(ctx, query, email).Scan(
-		&user.ID, &user.Email, &user.PasswordHash, &user.Name, &user.CreatedAt, &user.UpdatedAt,
+		&user.ID, &user.Email, &user.PasswordHash, &user.Name, &user.UnitSystem, &user.NotePrefix, &user.NotifySyncConflicts, &user.SemanticSearch, &user.TokenVersion, &user.CreatedAt, &user.UpdatedAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -76,11 +82,13 @@ func (r *UserRepo) GetByEmail(ctx context.Context, email string) (*entity.User,
 func (r *UserRepo) Update(ctx context.Context, user *entity.User) error {
 	query := `
 		UPDATE users
-		SET email = $2, password_hash = $3, name = $4, updated_at = $5
+		SET email = $2, password_hash = $3, name = $4, unit_system = $5, note_prefix = $6, notify_sync_conflicts = $7,
+			semantic_search = $8, updated_at = $9
 		WHERE id = $1
 	`
 	result, err := r.pool.Exec(ctx, query,
-		user.ID, user.Email, user.PasswordHash, user.Name, user.UpdatedAt,
+		user.ID, user.Email, user.PasswordHash, user.Name, unitSystem(user.UnitSystem), notePrefix(user.NotePrefix), user.NotifySyncConflicts,
+		user.SemanticSearch, user.UpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("updating user: %w", err)
@@ -100,3 +108,45 @@ func (r *UserRepo) ExistsByEmail(ctx context.Context, email string) (bool, error
 	}
 	return exists, nil
 }
+
+const tokenVersionQuery = `SELECT token_version FROM users WHERE id = $1`
+
+// GetTokenVersion returns the version access tokens must carry to be
+// accepted for the user.
+func (r *UserRepo) GetTokenVersion(ctx context.Context, id uuid.UUID) (int, error) {
+	var version int
+	err := r.pool.QueryRow(ctx, tokenVersionQuery, id).Scan(&version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domain.ErrUserNotFound
+		}
+		return 0, fmt.Errorf("querying token version: %w", err)
+	}
+	return version, nil
+}
+
+// BumpTokenVersion invalidates every access token issued to the user so far.
+func (r *UserRepo) BumpTokenVersion(ctx context.Context, id uuid.UUID) error {
+	result, err := r.pool.Exec(ctx, `UPDATE users SET token_version = token_version + 1 WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("bumping token version: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func unitSystem(system string) string {
+	if system == "" {
+		return valueobject.UnitSystemMetric
+	}
+	return system
+}
+
+func notePrefix(prefix string) string {
+	if prefix == "" {
+		return entity.DefaultNotePrefix
+	}
+	return prefix
+}
@@ -2,8 +2,11 @@ package postgres_test
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -39,7 +42,34 @@ func TestIntegrationUserRepo_Create(t *testing.T) {
 		user2 := entity.NewUser("duplicate@example.com", "hashedpassword", "User 2")
 		err = repo.Create(ctx, user2)
 
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, domain.ErrUserAlreadyExists)
+	})
+
+	t.Run("only one of concurrent registrations with the same email succeeds", func(t *testing.T) {
+		db.Truncate(t, "users")
+
+		const attempts = 8
+		errs := make(chan error, attempts)
+		var wg sync.WaitGroup
+		for i := range attempts {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- repo.Create(ctx, entity.NewUser("race@example.com", "hashedpassword", fmt.Sprintf("User %d", i)))
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		created := 0
+		for err := range errs {
+			if err == nil {
+				created++
+				continue
+			}
+			assert.ErrorIs(t, err, domain.ErrUserAlreadyExists)
+		}
+		assert.Equal(t, 1, created)
 	})
 }
 
@@ -137,3 +167,46 @@ func TestIntegrationUserRepo_ExistsByEmail(t *testing.T) {
 		assert.False(t, exists)
 	})
 }
+
+func TestIntegrationUserRepo_GetTokenVersion(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewUserRepo(db.Pool)
+	ctx := context.Background()
+
+	t.Run("starts at zero", func(t *testing.T) {
+		db.Truncate(t, "users")
+
+		user := entity.NewUser("version@example.com", "hashedpassword", "Test User")
+		require.NoError(t, repo.Create(ctx, user))
+
+		version, err := repo.GetTokenVersion(ctx, user.ID)
+
+		require.NoError(t, err)
+		assert.Zero(t, version)
+	})
+
+	t.Run("increases on bump", func(t *testing.T) {
+		db.Truncate(t, "users")
+
+		user := entity.NewUser("bump@example.com", "hashedpassword", "Test User")
+		require.NoError(t, repo.Create(ctx, user))
+		require.NoError(t, repo.BumpTokenVersion(ctx, user.ID))
+
+		version, err := repo.GetTokenVersion(ctx, user.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, version)
+	})
+
+	t.Run("returns error for unknown user", func(t *testing.T) {
+		db.Truncate(t, "users")
+
+		_, err := repo.GetTokenVersion(ctx, uuid.New())
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+		err = repo.BumpTokenVersion(ctx, uuid.New())
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+}
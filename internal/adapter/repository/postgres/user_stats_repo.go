@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+// UserStatsRepo reads the counters maintained by the user_stats triggers.
+type UserStatsRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewUserStatsRepo(pool *pgxpool.Pool) *UserStatsRepo {
+	return &UserStatsRepo{pool: pool}
+}
+
+func (r *UserStatsRepo) Get(ctx context.Context, userID uuid.UUID) (*entity.UserStats, error) {
+	query := `
+		SELECT user_id, note_count, photo_count, storage_bytes, updated_at
+		FROM user_stats
+		WHERE user_id = $1
+	`
+	var stats entity.UserStats
+	err := r.pool.QueryRow(ctx, query, userID).Scan(
+		&stats.UserID, &stats.NoteCount, &stats.PhotoCount, &stats.StorageBytes, &stats.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Only users deleted mid-request lack a row; report them as empty.
+		return &entity.UserStats{UserID: userID, UpdatedAt: time.Now().UTC()}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("querying user stats: %w", err)
+	}
+	return &stats, nil
+}
+
+// Reconcile recounts every user's notes and photos, overwrites counters that
+// drifted and returns them. Writes committed while it runs can make a
+// counter look drifted; the next run corrects it.
+func (r *UserStatsRepo) Reconcile(ctx context.Context) ([]entity.StatsDrift, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `INSERT INTO user_stats (user_id) SELECT id FROM users ON CONFLICT DO NOTHING`); err != nil {
+		return nil, fmt.Errorf("creating missing user stats: %w", err)
+	}
+
+	query := `
+		WITH actual AS (
+			SELECT u.id AS user_id,
+				   (SELECT COUNT(*) FROM notes n WHERE n.user_id = u.id AND n.deleted_at IS NULL) AS note_count,
+				   (SELECT COUNT(*) FROM photos p JOIN notes n ON n.id = p.note_id WHERE n.user_id = u.id) AS photo_count,
+				   (SELECT COALESCE(SUM(p.size), 0) FROM photos p JOIN notes n ON n.id = p.note_id WHERE n.user_id = u.id) AS storage_bytes
+			FROM users u
+		),
+		drift AS (
+			SELECT s.user_id, s.note_count, s.photo_count, s.storage_bytes,
+				   a.note_count AS actual_notes, a.photo_count AS actual_photos, a.storage_bytes AS actual_bytes
+			FROM user_stats s
+			JOIN actual a ON a.user_id = s.user_id
+			WHERE (s.note_count, s.photo_count, s.storage_bytes) IS DISTINCT FROM (a.note_count, a.photo_count, a.storage_bytes)
+		)
+		UPDATE user_stats s
+		SET note_count = d.actual_notes, photo_count = d.actual_photos, storage_bytes = d.actual_bytes, updated_at = NOW()
+		FROM drift d
+		WHERE s.user_id = d.user_id
+		RETURNING d.user_id, d.note_count, d.photo_count, d.storage_bytes,
+				  d.actual_notes, d.actual_photos, d.actual_bytes, s.updated_at
+	`
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("reconciling user stats: %w", err)
+	}
+	defer rows.Close()
+
+	var drifts []entity.StatsDrift
+	for rows.Next() {
+		var d entity.StatsDrift
+		if err := rows.Scan(
+			&d.Stored.UserID, &d.Stored.NoteCount, &d.Stored.PhotoCount, &d.Stored.StorageBytes,
+			&d.Actual.NoteCount, &d.Actual.PhotoCount, &d.Actual.StorageBytes, &d.Actual.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scanning stats drift: %w", err)
+		}
+		d.Actual.UserID = d.Stored.UserID
+		drifts = append(drifts, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating stats drift: %w", err)
+	}
+	rows.Close()
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+	return drifts, nil
+}
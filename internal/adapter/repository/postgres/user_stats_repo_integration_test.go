@@ -0,0 +1,69 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository/postgres"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+func TestIntegrationUserStatsRepo_Triggers(t *testing.T) {
+	db := SetupTestDB(t)
+	defer db.Cleanup(t)
+
+	repo := postgres.NewUserStatsRepo(db.Pool)
+	noteRepo := postgres.NewNoteRepo(db.Pool, nil, nil, nil)
+	photoRepo := postgres.NewPhotoRepo(db.Pool, nil)
+	ctx := context.Background()
+
+	t.Run("counts notes and photos on write", func(t *testing.T) {
+		db.Truncate(t, "photos", "notes", "users")
+		user := createTestUser(t, db)
+
+		kept := entity.NewNote(user.ID, "Kept", "Content", nil, "")
+		require.NoError(t, noteRepo.Create(ctx, kept))
+		deleted := entity.NewNote(user.ID, "Deleted", "Content", nil, "")
+		require.NoError(t, noteRepo.Create(ctx, deleted))
+		photo := entity.NewPhoto(kept.ID, kept.UserID, "http://storage/photo.jpg", "notes/123/photo.jpg", "image/jpeg", 1024, 800, 600)
+		require.NoError(t, photoRepo.Create(ctx, photo))
+		require.NoError(t, noteRepo.SoftDelete(ctx, deleted.UserID, deleted.ID))
+
+		stats, err := repo.Get(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), stats.NoteCount)
+		assert.Equal(t, int64(1), stats.PhotoCount)
+		assert.Equal(t, int64(1024), stats.StorageBytes)
+
+		require.NoError(t, noteRepo.Purge(ctx, user.ID))
+
+		stats, err = repo.Get(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Zero(t, stats.NoteCount)
+		assert.Zero(t, stats.PhotoCount)
+		assert.Zero(t, stats.StorageBytes)
+	})
+
+	t.Run("reconcile fixes drifted counters", func(t *testing.T) {
+		db.Truncate(t, "photos", "notes", "users")
+		user := createTestUser(t, db)
+
+		note := entity.NewNote(user.ID, "Note", "Content", nil, "")
+		require.NoError(t, noteRepo.Create(ctx, note))
+		_, err := db.Pool.Exec(ctx, `UPDATE user_stats SET note_count = 5 WHERE user_id = $1`, user.ID)
+		require.NoError(t, err)
+
+		drifts, err := repo.Reconcile(ctx)
+		require.NoError(t, err)
+		require.Len(t, drifts, 1)
+		assert.Equal(t, int64(5), drifts[0].Stored.NoteCount)
+		assert.Equal(t, int64(1), drifts[0].Actual.NoteCount)
+
+		stats, err := repo.Get(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), stats.NoteCount)
+	})
+}
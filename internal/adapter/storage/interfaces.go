@@ -4,15 +4,43 @@ import (
 	"context"
 	"io"
 	"time"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
 )
 
 type ImageStorage interface {
-	Upload(ctx context.Context, key string, reader io.Reader, contentType string, size int64) error
+	// Upload stores the object and returns the server-side encryption applied
+	// to it ("AES256", "aws:kms", ...), or "" when the object is unencrypted.
+	Upload(ctx context.Context, key string, reader io.Reader, contentType string, size int64) (string, error)
 	GetURL(key string) string
 	GetSignedURL(key string, expiry time.Duration) (string, error)
 	Delete(ctx context.Context, key string) error
+	// Download returns the whole object.
+	Download(ctx context.Context, key string) ([]byte, error)
 }
 
 type ImageProcessor interface {
-	Process(reader io.Reader) (io.Reader, int64, int, int, error)
+	Process(ctx context.Context, reader io.Reader) (io.Reader, int64, int, int, error)
+	// Thumbnails returns JPEG variants of the image for each thumbnail size,
+	// or none when the image cannot be decoded.
+	Thumbnails(ctx context.Context, reader io.Reader) ([]Thumbnail, error)
+	// Metadata returns the capture time and position recorded in the
+	// image's EXIF data; fields the image does not carry are left unset.
+	Metadata(data []byte) ImageMetadata
+}
+
+// ImageMetadata is what a photo's EXIF data says about where and when it was
+// taken.
+type ImageMetadata struct {
+	TakenAt  *time.Time
+	Location *valueobject.Location
+}
+
+// Thumbnail is an encoded JPEG variant of an image.
+type Thumbnail struct {
+	// Size is entity.ThumbnailSmall or entity.ThumbnailMedium.
+	Size   string
+	Data   []byte
+	Width  int
+	Height int
 }
@@ -0,0 +1,28 @@
+package domain
+
+import "github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+
+// TeamNoteAccess returns the access a team role grants to the team's notes:
+// owner access for those who manage the team, editor or viewer for the
+// others, and "" for users outside the team.
+func TeamNoteAccess(role string) string {
+	switch {
+	case entity.ManagesTeam(role):
+		return entity.AccessOwner
+	case role == entity.TeamRoleEditor:
+		return entity.ShareRoleEditor
+	case role == entity.TeamRoleViewer:
+		return entity.ShareRoleViewer
+	default:
+		return ""
+	}
+}
+
+// CanChangeTeamRole reports whether a member with actorRole may give the
+// role newRole to a member who has memberRole. Only the team's owner and
+// admins change roles, nobody changes the owner's, and nobody becomes owner.
+func CanChangeTeamRole(actorRole, memberRole, newRole string) bool {
+	return entity.ManagesTeam(actorRole) &&
+		memberRole != entity.TeamRoleOwner &&
+		entity.IsTeamInviteRole(newRole)
+}
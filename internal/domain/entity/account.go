@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountDeletion is a user's request to delete their account. The account
+// is locked when it is requested and purged later in the background; the
+// record outlives the account as the audit trail of its deletion.
+type AccountDeletion struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	RequestedAt time.Time
+	// PurgedAt is set once the account's data and stored files are gone.
+	PurgedAt *time.Time
+	// ObjectsDeleted is the number of stored files the purge removed.
+	ObjectsDeleted int
+}
+
+func NewAccountDeletion(userID uuid.UUID) *AccountDeletion {
+	return &AccountDeletion{
+		ID:          uuid.New(),
+		UserID:      userID,
+		RequestedAt: time.Now().UTC(),
+	}
+}
+
+func (d *AccountDeletion) MarkPurged(objects int) {
+	now := time.Now().UTC()
+	d.PurgedAt = &now
+	d.ObjectsDeleted = objects
+}
@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Attachment kinds. Photos keep their own type; attachments hold the other
+// media a note can carry.
+const AttachmentKindAudio = "audio"
+
+// MaxAudioDuration bounds the duration a voice memo may declare.
+const MaxAudioDuration = 4 * time.Hour
+
+// Attachment is a non-image file stored with a note, such as a voice memo.
+type Attachment struct {
+	ID       uuid.UUID
+	NoteID   uuid.UUID
+	Kind     string
+	URL      string
+	Key      string
+	MimeType string
+	Size     int64
+	// Duration is the playback length reported by the client.
+	Duration time.Duration
+	Checksum string
+	// ClientID is the device-generated ID the attachment was uploaded with,
+	// if any.
+	ClientID  string
+	CreatedAt time.Time
+}
+
+func NewAttachment(noteID uuid.UUID, kind, url, key, mimeType string, size int64, duration time.Duration) *Attachment {
+	return &Attachment{
+		ID:        uuid.New(),
+		NoteID:    noteID,
+		Kind:      kind,
+		URL:       url,
+		Key:       key,
+		MimeType:  mimeType,
+		Size:      size,
+		Duration:  duration,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// IsAudioType reports whether contentType is an accepted audio format.
+func IsAudioType(contentType string) bool {
+	switch contentType {
+	case "audio/m4a", "audio/x-m4a", "audio/mp4", "audio/mpeg":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Actions recorded in the audit log.
+const (
+	AuditAccountCreated  = "account_created"
+	AuditLogin           = "login"
+	AuditLoginFailed     = "login_failed"
+	AuditLoginLocked     = "login_locked"
+	AuditLogout          = "logout"
+	AuditTokenRefresh    = "token_refresh"
+	AuditSessionsRevoked = "sessions_revoked"
+	AuditPasswordReset   = "password_reset"
+	AuditSSOSettings     = "sso_settings_changed"
+	AuditNoteDeleted     = "note_deleted"
+	AuditAccountDeletion = "account_deletion_requested"
+)
+
+// AuditEvent is a security-relevant action on a user's account, kept so the
+// user can review who did what from where. Target identifies what the action
+// was applied to, such as the deleted note or the logged out device, and is
+// empty when that is the account itself.
+type AuditEvent struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	DeviceID  *uuid.UUID
+	Action    string
+	Target    string
+	IP        string
+	UserAgent string
+	CreatedAt time.Time
+}
+
+func NewAuditEvent(userID uuid.UUID, action, target string) *AuditEvent {
+	return &AuditEvent{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Action:    action,
+		Target:    target,
+		CreatedAt: time.Now().UTC(),
+	}
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Social identity providers users can sign in with.
+const (
+	AuthProviderGoogle = "google"
+	AuthProviderApple  = "apple"
+)
+
+// AuthProviderLink ties a user to their account at a social identity
+// provider, identified by the provider's stable subject. The email is the
+// one the provider reported when the link was made.
+type AuthProviderLink struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Provider  string
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+}
+
+func NewAuthProviderLink(userID uuid.UUID, provider, subject, email string) *AuthProviderLink {
+	return &AuthProviderLink{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Provider:  provider,
+		Subject:   subject,
+		Email:     email,
+		CreatedAt: time.Now().UTC(),
+	}
+}
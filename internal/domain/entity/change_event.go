@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChangeEvent tells a user's devices that their notes changed on the server
+// after Since, so they sync instead of polling.
+type ChangeEvent struct {
+	UserID uuid.UUID
+	// DeviceID is the registered device that made the change; it already
+	// has it and is not told. uuid.Nil when the request was not made with
+	// a device-bound token.
+	DeviceID uuid.UUID
+	Since    time.Time
+}
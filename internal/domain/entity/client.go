@@ -0,0 +1,108 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ClientUnknown stands for a client value that was missing or not
+// recognized.
+const ClientUnknown = "unknown"
+
+// ClientInfo is the app, its version and the operating system a request
+// came from. It is coarse on purpose: the OS keeps only its name and major
+// version, and unrecognized values collapse to ClientUnknown, so requests
+// can be aggregated without identifying anyone and made-up headers can't
+// grow the analytics without bound.
+type ClientInfo struct {
+	Platform   string
+	AppVersion string
+	OS         string
+}
+
+var (
+	// appUserAgent matches the apps' User-Agent, for example
+	// "FieldNotes/2.3.1 (iOS 17.4.1; iPhone15,2)".
+	appUserAgent      = regexp.MustCompile(`^FieldNotes/(\S+)(?: \(([^;)]*))?`)
+	appVersionPattern = regexp.MustCompile(`^\d{1,4}(\.\d{1,4}){0,3}$`)
+	osPattern         = regexp.MustCompile(`^([A-Za-z]+(?: OS)?)[ /_]?(\d{1,3})?`)
+)
+
+// osPlatforms maps the operating systems the analytics keep to the platform
+// an app on them reports.
+var osPlatforms = map[string]string{
+	"ios":     PlatformIOS,
+	"ipados":  PlatformIOS,
+	"android": PlatformAndroid,
+	"macos":   "",
+	"windows": "",
+	"linux":   "",
+}
+
+// ParseClientInfo reads the client from the X-App-Platform and
+// X-App-Version headers, falling back to the apps' User-Agent. Browsers are
+// reported as the web platform.
+func ParseClientInfo(userAgent, appVersion, platform string) ClientInfo {
+	info := ClientInfo{Platform: ClientUnknown, AppVersion: ClientUnknown, OS: ClientUnknown}
+
+	if m := appUserAgent.FindStringSubmatch(userAgent); m != nil {
+		if appVersion == "" {
+			appVersion = m[1]
+		}
+		info.OS = normalizeOS(m[2])
+		if p, ok := osPlatforms[strings.ToLower(strings.Fields(info.OS)[0])]; ok && p != "" && platform == "" {
+			platform = p
+		}
+	} else if strings.HasPrefix(userAgent, "Mozilla/") && platform == "" {
+		platform = PlatformWeb
+	}
+
+	if p, ok := NormalizePlatform(platform); ok {
+		info.Platform = p
+	}
+	if appVersionPattern.MatchString(appVersion) {
+		info.AppVersion = appVersion
+	}
+	return info
+}
+
+// normalizeOS reduces "iOS 17.4.1" to "iOS 17", and any OS it doesn't
+// know to ClientUnknown.
+func normalizeOS(os string) string {
+	m := osPattern.FindStringSubmatch(strings.TrimSpace(os))
+	if m == nil {
+		return ClientUnknown
+	}
+	if _, ok := osPlatforms[strings.ToLower(m[1])]; !ok {
+		return ClientUnknown
+	}
+	if m[2] == "" {
+		return m[1]
+	}
+	return m[1] + " " + m[2]
+}
+
+// ClientUsage counts the requests one client version made on a UTC day,
+// and the distinct devices that made them.
+type ClientUsage struct {
+	Day time.Time
+	ClientInfo
+	Requests int64
+	Devices  int64
+}
+
+// ClientAdoption is how much one app version of a platform was used over a
+// period. Share is its fraction of the platform's requests; Devices is its
+// busiest day's device count, since devices can't be summed across days.
+type ClientAdoption struct {
+	Platform   string
+	AppVersion string
+	Requests   int64
+	Share      float64
+	Devices    int64
+	FirstSeen  time.Time
+	LastSeen   time.Time
+	// OS counts the requests per operating system.
+	OS map[string]int64
+}
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"math"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+)
+
+// MaxMercatorLatitude is the latitude where Web Mercator tiles end; notes
+// closer to the poles fall in the first or last row.
+const MaxMercatorLatitude = 85.05112878
+
+// MapTile is a Web Mercator (slippy map) tile: z is the zoom, x counts
+// columns east from 180°W and y rows south from MaxMercatorLatitude.
+type MapTile struct {
+	Z int
+	X int
+	Y int
+}
+
+// TileAt returns the tile at the zoom that holds the point.
+func TileAt(zoom int, lat, lng float64) MapTile {
+	n := math.Exp2(float64(zoom))
+	lat = max(-MaxMercatorLatitude, min(MaxMercatorLatitude, lat)) * math.Pi / 180
+	x := int((lng + 180) / 360 * n)
+	y := int((1 - math.Log(math.Tan(lat)+1/math.Cos(lat))/math.Pi) / 2 * n)
+	last := int(n) - 1
+	return MapTile{Z: zoom, X: min(x, last), Y: max(0, min(y, last))}
+}
+
+// Bounds returns the area the tile covers.
+func (t MapTile) Bounds() valueobject.BoundingBox {
+	n := math.Exp2(float64(t.Z))
+	lat := func(y int) float64 {
+		return math.Atan(math.Sinh(math.Pi*(1-2*float64(y)/n))) * 180 / math.Pi
+	}
+	return valueobject.BoundingBox{
+		MinLat: lat(t.Y + 1),
+		MaxLat: lat(t.Y),
+		MinLng: float64(t.X)/n*360 - 180,
+		MaxLng: float64(t.X+1)/n*360 - 180,
+	}
+}
+
+// CoverageTile is a map tile holding some of a user's notes.
+type CoverageTile struct {
+	MapTile
+	Notes int
+}
+
+// NoteCoverage is the map area a user's notes with a location span, for
+// clients to download offline maps of.
+type NoteCoverage struct {
+	Zoom int
+	// Bounds encloses every note; nil when the user has none with a location.
+	Bounds *valueobject.BoundingBox
+	Notes  int
+	// Tiles are ordered by x, then y. Truncated is set when there were more
+	// than the request allowed.
+	Tiles     []CoverageTile
+	Truncated bool
+}
@@ -0,0 +1,60 @@
+package entity
+
+import "time"
+
+// Deprecation marks an endpoint, or a request field of one, that clients
+// should stop using. Requests that use it are answered with the Deprecation
+// and Sunset headers and counted per client version, so it can be removed
+// once the app versions still using it are gone.
+type Deprecation struct {
+	Method string
+	// Route is the route path as registered, such as /api/v1/notes/:id.
+	Route string
+	// Field names the deprecated request field; empty deprecates the
+	// endpoint. Handlers report its use with httputil.UseDeprecatedField.
+	Field string
+	Since time.Time
+	// Sunset is when it stops working; zero when no date is set.
+	Sunset time.Time
+	// Link points to the migration guide, if any.
+	Link string
+}
+
+// Name identifies the deprecation in usage counts and reports, as
+// "POST /api/v1/sync" or "POST /api/v1/sync notes[].tags".
+func (d Deprecation) Name() string {
+	name := d.Method + " " + d.Route
+	if d.Field != "" {
+		name += " " + d.Field
+	}
+	return name
+}
+
+// DeprecationUsage counts the requests one app version made to a
+// deprecated endpoint or field on a UTC day.
+type DeprecationUsage struct {
+	Day        time.Time
+	Name       string
+	Platform   string
+	AppVersion string
+	Requests   int64
+}
+
+// DeprecatedClient is an app version that still used a deprecation over a
+// period.
+type DeprecatedClient struct {
+	Platform   string
+	AppVersion string
+	Requests   int64
+	LastSeen   time.Time
+}
+
+// DeprecationReport is how much a deprecation was still used over a period,
+// by the app versions that used it.
+type DeprecationReport struct {
+	Deprecation
+	Requests int64
+	// LastSeen is zero when nothing used it in the period.
+	LastSeen time.Time
+	Clients  []DeprecatedClient
+}
@@ -1,11 +1,40 @@
 package entity
 
 import (
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
 )
 
+// CursorNotes is the sync stream of notes. Each stream has its own cursor per
+// device in device_cursors; notes is the only stream synced by cursor so far.
+const CursorNotes = "notes"
+
+// Platforms a device can register with. Each deployment enables a subset.
+const (
+	PlatformIOS     = "ios"
+	PlatformAndroid = "android"
+	PlatformWeb     = "web"
+	PlatformCLI     = "cli"
+)
+
+// MaxDeviceAuthorLength is the active author size in characters.
+const MaxDeviceAuthorLength = 64
+
+// Platforms lists every platform the API knows about.
+var Platforms = []string{PlatformIOS, PlatformAndroid, PlatformWeb, PlatformCLI}
+
+// NormalizePlatform trims and lowercases a client-reported platform, and
+// reports whether the result is one of Platforms.
+func NormalizePlatform(platform string) (string, bool) {
+	platform = strings.ToLower(strings.TrimSpace(platform))
+	return platform, slices.Contains(Platforms, platform)
+}
+
 type Device struct {
 	ID         uuid.UUID
 	UserID     uuid.UUID
@@ -13,10 +42,51 @@ type Device struct {
 	Platform   string
 	Name       string
 	SyncCursor time.Time
+	Cursors    map[string]time.Time
+	Scope      SyncScope
+	// ActiveAuthor is the person currently using a shared device, stamped
+	// onto the notes it creates; empty when none is set.
+	ActiveAuthor      string
+	ActiveAuthorSince *time.Time
+	// PushToken is the FCM or APNs token the device's app registered for
+	// silent pushes; empty when it has none.
+	PushToken          string
+	PushTokenUpdatedAt *time.Time
+	// LastAccess is nil until the device logs in or refreshes its token.
+	LastAccess *DeviceAccess
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
 }
 
+// DeviceAccess records where and when a device last logged in or refreshed
+// its token. City and Country come from GeoIP and are empty when unknown.
+type DeviceAccess struct {
+	IP      string
+	City    string
+	Country string
+	At      time.Time
+}
+
+// GeoLocation is the approximate place an IP address is registered to.
+// Coordinates is nil when the provider does not report them.
+type GeoLocation struct {
+	City        string
+	Country     string
+	Coordinates *valueobject.Location
+}
+
+// SyncScope narrows what a device pulls during sync. The zero value syncs everything.
+type SyncScope struct {
+	// NotesSince skips notes created before this time.
+	NotesSince    *time.Time
+	ExcludePhotos bool
+}
+
+// IncludesNote reports whether the note falls within the scope.
+func (s SyncScope) IncludesNote(note *Note) bool {
+	return s.NotesSince == nil || !note.CreatedAt.Before(*s.NotesSince)
+}
+
 func NewDevice(userID uuid.UUID, deviceID, platform, name string) *Device {
 	now := time.Now().UTC()
 	return &Device{
@@ -26,12 +96,70 @@ func NewDevice(userID uuid.UUID, deviceID, platform, name string) *Device {
 		Platform:   platform,
 		Name:       name,
 		SyncCursor: time.Time{},
+		Cursors:    make(map[string]time.Time),
 		CreatedAt:  now,
 		UpdatedAt:  now,
 	}
 }
 
+func (d *Device) SetScope(scope SyncScope) {
+	d.Scope = scope
+	d.UpdatedAt = time.Now().UTC()
+}
+
+// SetActiveAuthor records who is using the device from now on. An empty
+// name clears it.
+func (d *Device) SetActiveAuthor(name string) {
+	now := time.Now().UTC()
+	d.ActiveAuthor = name
+	d.ActiveAuthorSince = &now
+	if name == "" {
+		d.ActiveAuthorSince = nil
+	}
+	d.UpdatedAt = now
+}
+
+// SupportsPush reports whether the device's platform receives pushes:
+// APNs for ios, FCM for android.
+func (d *Device) SupportsPush() bool {
+	return d.Platform == PlatformIOS || d.Platform == PlatformAndroid
+}
+
+// SetPushToken records the token the device's app registered for pushes. An
+// empty token clears it.
+func (d *Device) SetPushToken(token string) {
+	now := time.Now().UTC()
+	d.PushToken = token
+	d.PushTokenUpdatedAt = &now
+	if token == "" {
+		d.PushTokenUpdatedAt = nil
+	}
+	d.UpdatedAt = now
+}
+
 func (d *Device) UpdateSyncCursor(cursor time.Time) {
-	d.SyncCursor = cursor
+	d.UpdateCursor(CursorNotes, cursor)
+}
+
+// Cursor returns the position of the given stream. The notes stream falls back
+// to the legacy single sync cursor for devices that predate named cursors.
+func (d *Device) Cursor(stream string) time.Time {
+	if c, ok := d.Cursors[stream]; ok {
+		return c
+	}
+	if stream == CursorNotes {
+		return d.SyncCursor
+	}
+	return time.Time{}
+}
+
+func (d *Device) UpdateCursor(stream string, cursor time.Time) {
+	if d.Cursors == nil {
+		d.Cursors = make(map[string]time.Time)
+	}
+	d.Cursors[stream] = cursor
+	if stream == CursorNotes {
+		d.SyncCursor = cursor
+	}
 	d.UpdatedAt = time.Now().UTC()
 }
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeviceUsage holds the bytes a device sent to (BytesIn) and received from
+// (BytesOut) the API on a given UTC day.
+type DeviceUsage struct {
+	DeviceID uuid.UUID
+	Day      time.Time
+	BytesIn  int64
+	BytesOut int64
+}
+
+// UsageDay truncates t to the UTC day it falls on.
+func UsageDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
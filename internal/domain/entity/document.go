@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/crdt"
+)
+
+// NoteDocument is the content of a collaborative note as a CRDT document,
+// so co-editors merge their changes instead of the last save winning. It is
+// a snapshot of the document after update SnapshotSeq, and the updates
+// applied since, in order.
+type NoteDocument struct {
+	NoteID      uuid.UUID
+	Snapshot    crdt.Snapshot
+	SnapshotSeq int64
+	Updates     []DocumentUpdate
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// DocumentUpdate is one batch of operations applied to a note's document.
+// UserID and DeviceID are nil once the author's account or device is gone.
+type DocumentUpdate struct {
+	NoteID    uuid.UUID
+	Seq       int64
+	UserID    *uuid.UUID
+	DeviceID  *uuid.UUID
+	Ops       []crdt.Op
+	CreatedAt time.Time
+}
+
+func NewNoteDocument(noteID uuid.UUID, snapshot crdt.Snapshot) *NoteDocument {
+	now := time.Now().UTC()
+	return &NoteDocument{NoteID: noteID, Snapshot: snapshot, CreatedAt: now, UpdatedAt: now}
+}
+
+// Seq is the number of the last update applied to the document.
+func (d *NoteDocument) Seq() int64 {
+	if n := len(d.Updates); n > 0 {
+		return d.Updates[n-1].Seq
+	}
+	return d.SnapshotSeq
+}
+
+// State rebuilds the document from the snapshot and the updates.
+func (d *NoteDocument) State() (*crdt.Doc, error) {
+	doc, err := crdt.FromSnapshot(d.Snapshot)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range d.Updates {
+		if _, err := doc.Apply(u.Ops); err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+// UpdatesSince returns the updates after update seq, and false when the log
+// no longer holds all of them and the whole document must be sent instead.
+func (d *NoteDocument) UpdatesSince(seq int64) ([]DocumentUpdate, bool) {
+	if seq < d.SnapshotSeq || seq > d.Seq() {
+		return nil, false
+	}
+	return d.Updates[seq-d.SnapshotSeq:], true
+}
@@ -0,0 +1,68 @@
+package entity
+
+import "time"
+
+// Product events counted for the KPIs.
+const (
+	KPINotesCreated        = "notes_created"
+	KPISyncs               = "syncs"
+	KPISyncFailures        = "sync_failures"
+	KPINotesPushed         = "notes_pushed"
+	KPIConflicts           = "conflicts"
+	KPIPhotoUploads        = "photo_uploads"
+	KPIPhotoUploadFailures = "photo_upload_failures"
+)
+
+// KPIReport is the business KPIs of one UTC day.
+type KPIReport struct {
+	Day         time.Time
+	ActiveUsers int64
+	// The counts of the KPI events of the day.
+	NotesCreated        int64
+	Syncs               int64
+	SyncFailures        int64
+	NotesPushed         int64
+	Conflicts           int64
+	PhotoUploads        int64
+	PhotoUploadFailures int64
+	ComputedAt          time.Time
+}
+
+// NewKPIReport builds the report of day from the counts of its events.
+func NewKPIReport(day time.Time, activeUsers int64, events map[string]int64) KPIReport {
+	return KPIReport{
+		Day:                 UsageDay(day),
+		ActiveUsers:         activeUsers,
+		NotesCreated:        events[KPINotesCreated],
+		Syncs:               events[KPISyncs],
+		SyncFailures:        events[KPISyncFailures],
+		NotesPushed:         events[KPINotesPushed],
+		Conflicts:           events[KPIConflicts],
+		PhotoUploads:        events[KPIPhotoUploads],
+		PhotoUploadFailures: events[KPIPhotoUploadFailures],
+	}
+}
+
+// SyncSuccessRate is the fraction of syncs that succeeded, 1 when there
+// were none.
+func (r KPIReport) SyncSuccessRate() float64 {
+	return 1 - ratio(r.SyncFailures, r.Syncs)
+}
+
+// ConflictRate is the fraction of the notes pushed by devices that
+// conflicted with the server's version.
+func (r KPIReport) ConflictRate() float64 {
+	return ratio(r.Conflicts, r.NotesPushed)
+}
+
+// PhotoUploadFailureRate is the fraction of photo uploads that failed.
+func (r KPIReport) PhotoUploadFailureRate() float64 {
+	return ratio(r.PhotoUploadFailures, r.PhotoUploads)
+}
+
+func ratio(n, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
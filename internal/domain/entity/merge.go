@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// How the content of merged notes is combined.
+const (
+	MergeContentConcatenate = "concatenate"
+	MergeContentKeep        = "keep"
+)
+
+// MergeSpec says which fields survive when notes are merged.
+type MergeSpec struct {
+	// TitleFrom is the note whose title is kept; the zero ID keeps the
+	// surviving note's title.
+	TitleFrom uuid.UUID
+	// Content is MergeContentConcatenate or MergeContentKeep.
+	Content string
+}
+
+func IsMergeContent(mode string) bool {
+	return mode == MergeContentConcatenate || mode == MergeContentKeep
+}
+
+// Merge folds the other notes into n. Measurements are unioned by name with
+// n's values winning, tags are unioned, a missing location, color or icon is
+// taken from the first note that has one and the strictest sensitivity is
+// kept. Photos are
+// moved by the repository.
+func (n *Note) Merge(others []Note, spec MergeSpec) {
+	contents := []string{n.Content}
+	seen := make(map[string]bool, len(n.Measurements))
+	for _, m := range n.Measurements {
+		seen[m.Name] = true
+	}
+
+	for _, o := range others {
+		if o.ID == spec.TitleFrom {
+			n.Title = o.Title
+		}
+		if spec.Content == MergeContentConcatenate && strings.TrimSpace(o.Content) != "" {
+			contents = append(contents, o.Content)
+		}
+		if n.Location == nil {
+			n.Location = o.Location
+		}
+		if n.Color == nil {
+			n.Color = o.Color
+		}
+		if n.Icon == nil {
+			n.Icon = o.Icon
+		}
+		for _, m := range o.Measurements {
+			if !seen[m.Name] {
+				seen[m.Name] = true
+				n.Measurements = append(n.Measurements, m)
+			}
+		}
+		n.AddTags(o.Tags)
+		if sensitivityRank[o.Sensitivity] > sensitivityRank[n.Sensitivity] {
+			n.Sensitivity = o.Sensitivity
+		}
+	}
+
+	n.Content = strings.Join(contents, "\n\n")
+	n.UpdatedAt = time.Now().UTC()
+}
+
+var sensitivityRank = map[string]int{
+	SensitivityNone: 0,
+	SensitivityLow:  1,
+	SensitivityHigh: 2,
+}
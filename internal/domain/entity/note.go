@@ -1,56 +1,278 @@
 package entity
 
 import (
+	"fmt"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
 )
 
+const (
+	// MaxNoteContentLength is the content size in characters kept on save.
+	MaxNoteContentLength = 100_000
+	// LowAccuracyThreshold is the accuracy radius in meters above which a
+	// location is flagged as imprecise.
+	LowAccuracyThreshold = 100.0
+	// NoteRestoreWindow is how long a deleted note can still be restored.
+	NoteRestoreWindow = 30 * 24 * time.Hour
+	// DefaultNotePrefix prefixes note references until the user picks another.
+	DefaultNotePrefix = "NOTE"
+)
+
+// Fields note listings can be sorted by. NoteSortID is the tiebreaker that
+// keeps the order stable when the other fields tie.
+const (
+	NoteSortUpdatedAt = "updated_at"
+	NoteSortCreatedAt = "created_at"
+	NoteSortTitle     = "title"
+	NoteSortNumber    = "number"
+	NoteSortID        = "id"
+)
+
+var NoteSortFields = []string{NoteSortUpdatedAt, NoteSortCreatedAt, NoteSortTitle, NoteSortNumber, NoteSortID}
+
 type Note struct {
+	ID                   uuid.UUID
+	UserID               uuid.UUID
+	Number               int64
+	Reference            string
+	Title                string
+	Content              string
+	Location             *valueobject.Location
+	Measurements         []valueobject.Measurement
+	Photos               []Photo
+	Attachments          []Attachment
+	ClientID             string
+	CreatedByDevice      string
+	LastModifiedByDevice string
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	DeletedAt            *time.Time
+	Quality              QualityResult
+	// Sensitivity is one of the Sensitivity* levels; see LocationMask.
+	Sensitivity string
+	// MergedInto is set on notes deleted by a merge to the surviving note.
+	MergedInto *uuid.UUID
+	// TeamID is set on notes owned by a team; UserID is then the member who
+	// created the note.
+	TeamID *uuid.UUID
+	// Author is the active author of the device that created the note, when
+	// one was set; see Device.ActiveAuthor. It does not change afterwards.
+	Author string
+	// SyncedFrom is the device sync that last wrote the note, nil when the
+	// last write came through the API or an import; see PushedBy.
+	SyncedFrom *NoteSyncOrigin
+	// Tags are the note's normalized tag names, sorted.
+	Tags []string
+	// Color and Icon are names from NoteColors and NoteIcons, nil when the
+	// note has none.
+	Color *string
+	Icon  *string
+	// ContentKey is the storage object holding the full content when it is
+	// too large to keep in the row, and ContentURL where clients fetch it.
+	ContentKey string
+	ContentURL string
+	// ContentExcerpt is set when Content holds only the start of the
+	// content at ContentKey, as notes read in bulk do.
+	ContentExcerpt bool
+	// Source is how the note was created, one of the NoteSource* values or
+	// an IntegrationSource, and SourceMeta optional details such as the
+	// imported file. Neither changes after creation.
+	Source     string
+	SourceMeta map[string]any
+	// Collaborative is set when the content is edited through a NoteDocument
+	// rather than replaced on every save.
+	Collaborative bool
+	// AutoTitled is set while the title is one the server generated from
+	// the content. Such a title follows the content until the user sets
+	// one.
+	AutoTitled bool
+	// FieldsUpdatedAt are when the title, content and location last
+	// changed; see FieldTimes.
+	FieldsUpdatedAt NoteFieldTimes
+
+	// Warnings collects non-fatal issues found while saving; not persisted.
+	Warnings []valueobject.Warning
+}
+
+// NoteFieldTimes are when each of the fields sync merges separately last
+// changed. A zero time stands for the note's UpdatedAt.
+type NoteFieldTimes struct {
+	Title    time.Time `json:"title,omitzero"`
+	Content  time.Time `json:"content,omitzero"`
+	Location time.Time `json:"location,omitzero"`
+}
+
+func (t NoteFieldTimes) IsZero() bool {
+	return t.Title.IsZero() && t.Content.IsZero() && t.Location.IsZero()
+}
+
+// NearbyNote is a note found by a radius search, with its distance in meters
+// from the search point.
+type NearbyNote struct {
+	Note     Note
+	Distance float64
+}
+
+// SearchHit is a note matched by a text search. Score blends text relevance
+// with proximity and orders the results; Distance, in meters from the center
+// of the search area, is set only when the search had one.
+type SearchHit struct {
+	Note     Note
+	Score    float64
+	Distance *float64
+}
+
+// NoteExistence is where a stored note stands, for clients reconciling
+// their local copy against the server: the version it is at and whether it
+// was deleted.
+type NoteExistence struct {
 	ID        uuid.UUID
-	UserID    uuid.UUID
-	Title     string
-	Content   string
-	Location  *valueobject.Location
-	Photos    []Photo
 	ClientID  string
-	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt *time.Time
 }
 
 func NewNote(userID uuid.UUID, title, content string, loc *valueobject.Location, clientID string) *Note {
-	now := time.Now().UTC()
+	return NewNoteAt(userID, title, content, loc, clientID, time.Now().UTC())
+}
+
+// NewNoteAt is NewNote created at the given time, for callers that read the
+// time from a clock.
+func NewNoteAt(userID uuid.UUID, title, content string, loc *valueobject.Location, clientID string, now time.Time) *Note {
 	return &Note{
-		ID:        uuid.New(),
-		UserID:    userID,
-		Title:     title,
-		Content:   content,
-		Location:  loc,
-		ClientID:  clientID,
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:          uuid.New(),
+		UserID:      userID,
+		Title:       title,
+		Content:     content,
+		Location:    loc,
+		ClientID:    clientID,
+		Quality:     QualityResult{Status: QualityUnchecked},
+		Sensitivity: SensitivityNone,
+		Source:      NoteSourceManual,
+		CreatedAt:   now,
+		UpdatedAt:   now,
 	}
 }
 
 func (n *Note) Update(title, content string, loc *valueobject.Location) {
+	n.UpdateAt(title, content, loc, time.Now().UTC())
+}
+
+// UpdateAt is Update stamped with the given time, which the fields that
+// changed also take.
+func (n *Note) UpdateAt(title, content string, loc *valueobject.Location, at time.Time) {
+	n.FieldsUpdatedAt = n.FieldTimes()
+	if title != n.Title {
+		n.AutoTitled = false
+		n.FieldsUpdatedAt.Title = at
+	}
+	if content != n.Content {
+		n.FieldsUpdatedAt.Content = at
+	}
+	if !loc.Equal(n.Location) {
+		n.FieldsUpdatedAt.Location = at
+	}
 	n.Title = title
 	n.Content = content
 	n.Location = loc
-	n.UpdatedAt = time.Now().UTC()
+	n.UpdatedAt = at
+}
+
+// FieldTimes returns FieldsUpdatedAt with UpdatedAt for the times it lacks.
+func (n *Note) FieldTimes() NoteFieldTimes {
+	t := n.FieldsUpdatedAt
+	for _, f := range []*time.Time{&t.Title, &t.Content, &t.Location} {
+		if f.IsZero() {
+			*f = n.UpdatedAt
+		}
+	}
+	return t
 }
 
-func (n *Note) SoftDelete() {
-	now := time.Now().UTC()
+// AssignNumber gives the note its per-user sequence number and the
+// human-friendly reference built from it, e.g. PLOT-0042.
+func (n *Note) AssignNumber(prefix string, number int64) {
+	n.Number = number
+	n.Reference = fmt.Sprintf("%s-%04d", prefix, number)
+}
+
+// SetOriginDevice records the client device that created the note.
+func (n *Note) SetOriginDevice(deviceID string) {
+	n.CreatedByDevice = deviceID
+	n.LastModifiedByDevice = deviceID
+}
+
+// MarkModifiedBy records the client device that last changed the note.
+// An empty device ID keeps the previous value.
+func (n *Note) MarkModifiedBy(deviceID string) {
+	if deviceID != "" {
+		n.LastModifiedByDevice = deviceID
+	}
+}
+
+// NoteSyncOrigin is the device (devices.id) whose sync wrote a note and the
+// UpdatedAt it wrote.
+type NoteSyncOrigin struct {
+	DeviceID  uuid.UUID
+	UpdatedAt time.Time
+}
+
+// MarkSyncedFrom records that the note is being written by the device's sync.
+func (n *Note) MarkSyncedFrom(deviceID uuid.UUID) {
+	n.SyncedFrom = &NoteSyncOrigin{DeviceID: deviceID, UpdatedAt: n.UpdatedAt}
+}
+
+// PushedBy reports whether the note is still as the device's sync wrote it.
+// Every other write moves UpdatedAt, so the device already has this version.
+func (n *Note) PushedBy(deviceID uuid.UUID) bool {
+	return n.SyncedFrom != nil && n.SyncedFrom.DeviceID == deviceID && n.SyncedFrom.UpdatedAt.Equal(n.UpdatedAt)
+}
+
+// Sanitize fixes what can be fixed without rejecting the note and records a
+// warning for each issue.
+func (n *Note) Sanitize() {
+	if utf8.RuneCountInString(n.Content) > MaxNoteContentLength {
+		n.Content = string([]rune(n.Content)[:MaxNoteContentLength])
+		n.Warnings = append(n.Warnings, valueobject.NewWarning(
+			valueobject.WarningContentTruncated, "content",
+			fmt.Sprintf("content truncated to %d characters", MaxNoteContentLength),
+		))
+	}
+
+	if n.Location != nil && n.Location.Accuracy != nil && *n.Location.Accuracy > LowAccuracyThreshold {
+		n.Warnings = append(n.Warnings, valueobject.NewWarning(
+			valueobject.WarningLowAccuracy, "accuracy",
+			fmt.Sprintf("location accuracy %.0fm is worse than %.0fm", *n.Location.Accuracy, LowAccuracyThreshold),
+		))
+	}
+
+	n.sanitizeTags()
+	n.sanitizeStyle()
+}
+
+func (n *Note) SoftDelete(now time.Time) {
 	n.DeletedAt = &now
 	n.UpdatedAt = now
 }
 
-func (n *Note) Restore() {
+func (n *Note) Restore(now time.Time) {
 	n.DeletedAt = nil
-	n.UpdatedAt = time.Now().UTC()
+	n.UpdatedAt = now
+}
+
+// CanRestore reports whether a deleted note is still within the restore window.
+func (n *Note) CanRestore(now time.Time) bool {
+	return n.DeletedAt != nil && now.Sub(*n.DeletedAt) <= NoteRestoreWindow
+}
+
+// IsSensitive reports whether the note's location is generalized for
+// anyone but the owner.
+func (n *Note) IsSensitive() bool {
+	return n.Sensitivity == SensitivityLow || n.Sensitivity == SensitivityHigh
 }
 
 func (n *Note) IsDeleted() bool {
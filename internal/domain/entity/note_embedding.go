@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NoteEmbedding is the vector an embeddings model computed from a note's
+// title and content, for semantic search. NoteUpdatedAt is the version of
+// the note it was computed from; later edits are embedded again.
+type NoteEmbedding struct {
+	NoteID        uuid.UUID
+	UserID        uuid.UUID
+	Model         string
+	Vector        []float32
+	NoteUpdatedAt time.Time
+}
@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+)
+
+// NoteRevision is a version of a note that an update replaced. The database
+// keeps one for every update that changes what the user wrote, sync
+// included, so a version lost to a last-write-wins sync can be restored.
+type NoteRevision struct {
+	NoteID uuid.UUID
+	UserID uuid.UUID
+	// Revision numbers the note's versions from 1, oldest first.
+	Revision int
+	Title    string
+	Content  string
+	// ContentKey, ContentURL and ContentExcerpt describe offloaded content
+	// as they do on Note.
+	ContentKey     string
+	ContentURL     string
+	ContentExcerpt bool
+	Location       *valueobject.Location
+	Measurements   []valueobject.Measurement
+	Color          *string
+	Icon           *string
+	// Sensitivity is the note's current level, which decides how the
+	// revision's location is shown.
+	Sensitivity          string
+	LastModifiedByDevice string
+	// UpdatedAt is when the version was saved and ReplacedAt when the
+	// update that replaced it was.
+	UpdatedAt  time.Time
+	ReplacedAt time.Time
+}
+
+// AsNote returns the fields of the revision that location masking needs.
+func (r *NoteRevision) AsNote() *Note {
+	return &Note{ID: r.NoteID, UserID: r.UserID, Location: r.Location, Sensitivity: r.Sensitivity}
+}
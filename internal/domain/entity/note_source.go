@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Sources a note can come from, recorded when it is created so users can
+// tell observations entered by hand from bulk imports when auditing data.
+const (
+	NoteSourceManual = "manual"
+	NoteSourceSync   = "sync"
+	NoteSourceImport = "import"
+	// NoteSourceIntegration is the filter matching every integration;
+	// notes carry IntegrationSource(name).
+	NoteSourceIntegration = "integration"
+)
+
+// MaxNoteSourceMetaSize caps the encoded size in bytes of a note's source
+// metadata.
+const MaxNoteSourceMetaSize = 4096
+
+var integrationNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,39}$`)
+
+// IntegrationSource returns the source of notes created by the named
+// integration, e.g. "integration:weather-station".
+func IntegrationSource(name string) string {
+	return NoteSourceIntegration + ":" + name
+}
+
+// IsNoteSource reports whether source is a source a note can carry.
+func IsNoteSource(source string) bool {
+	switch source {
+	case NoteSourceManual, NoteSourceSync, NoteSourceImport:
+		return true
+	}
+	name, ok := strings.CutPrefix(source, NoteSourceIntegration+":")
+	return ok && integrationNamePattern.MatchString(name)
+}
+
+// IsIntegrationSource reports whether the note came from an integration.
+func IsIntegrationSource(source string) bool {
+	return strings.HasPrefix(source, NoteSourceIntegration+":")
+}
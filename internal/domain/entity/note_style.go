@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+)
+
+// NoteColors is the palette notes can be colored from. Clients map each
+// name to their own shade, so the names are all the API knows.
+var NoteColors = []string{"red", "orange", "yellow", "green", "teal", "blue", "purple", "pink", "brown", "gray"}
+
+// NoteIcons are the icons notes can be marked with on maps.
+var NoteIcons = []string{"pin", "flag", "star", "camera", "tree", "flower", "bird", "water", "mountain", "tent", "warning", "info"}
+
+// NormalizeNoteStyle returns the canonical form of a color or icon name:
+// trimmed and lower case.
+func NormalizeNoteStyle(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func IsNoteColor(color string) bool {
+	return slices.Contains(NoteColors, color)
+}
+
+func IsNoteIcon(icon string) bool {
+	return slices.Contains(NoteIcons, icon)
+}
+
+// NormalizeNoteColors normalizes every name and returns them sorted without
+// duplicates. ok is false if any name is not in NoteColors.
+func NormalizeNoteColors(names []string) ([]string, bool) {
+	return normalizeNoteStyles(names, IsNoteColor)
+}
+
+// NormalizeNoteIcons is NormalizeNoteColors for NoteIcons.
+func NormalizeNoteIcons(names []string) ([]string, bool) {
+	return normalizeNoteStyles(names, IsNoteIcon)
+}
+
+func normalizeNoteStyles(names []string, valid func(string) bool) ([]string, bool) {
+	if len(names) == 0 {
+		return nil, true
+	}
+	styles := make([]string, 0, len(names))
+	for _, name := range names {
+		style := NormalizeNoteStyle(name)
+		if !valid(style) {
+			return nil, false
+		}
+		styles = append(styles, style)
+	}
+	slices.Sort(styles)
+	return slices.Compact(styles), true
+}
+
+// sanitizeStyle normalizes the note's color and icon. Nil stays nil: synced
+// notes use nil to leave the stored color or icon alone, and an empty name
+// clears it. Names outside NoteColors and NoteIcons are dropped to nil with
+// a warning.
+func (n *Note) sanitizeStyle() {
+	n.Color = n.sanitizeStyleField(n.Color, "color", IsNoteColor)
+	n.Icon = n.sanitizeStyleField(n.Icon, "icon", IsNoteIcon)
+}
+
+func (n *Note) sanitizeStyleField(value *string, field string, valid func(string) bool) *string {
+	if value == nil {
+		return nil
+	}
+	name := NormalizeNoteStyle(*value)
+	if name != "" && !valid(name) {
+		n.Warnings = append(n.Warnings, valueobject.NewWarning(
+			valueobject.WarningStyleDropped, field, fmt.Sprintf("%s %q is not one of the allowed values", field, *value),
+		))
+		return nil
+	}
+	return &name
+}
@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+)
+
+// NoteSummary is what note lists and maps show of a note, read from the
+// note_summaries projection instead of the notes and their photos and tags.
+type NoteSummary struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+	TeamID *uuid.UUID
+	Title  string
+	// Excerpt is the start of the note's content.
+	Excerpt string
+	// CoverURL is the thumbnail of the note's first photo that every user
+	// with access can see; empty when there is none.
+	CoverURL    string
+	Location    *valueobject.Location
+	Sensitivity string
+	Tags        []string
+	PhotoCount  int
+	// Color and Icon are empty when the note has none.
+	Color string
+	Icon  string
+	// SharedPhotoCount leaves out photos the owner keeps out of shares.
+	SharedPhotoCount int
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// PhotoCountFor returns how many of the note's photos the viewer can see.
+func (s *NoteSummary) PhotoCountFor(viewer uuid.UUID) int {
+	if s.UserID == viewer {
+		return s.PhotoCount
+	}
+	return s.SharedPhotoCount
+}
+
+// AsNote returns the fields of the summary that location masking needs.
+func (s *NoteSummary) AsNote() *Note {
+	return &Note{ID: s.ID, UserID: s.UserID, Location: s.Location, Sensitivity: s.Sensitivity}
+}
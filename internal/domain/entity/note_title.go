@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MaxAutoTitleLength is the longest title generated from content, in
+// characters.
+const MaxAutoTitleLength = 80
+
+// placeholderTitles are the titles capture apps fill in when the user gives
+// none, in lower case. A note titled with one of them gets a title from its
+// content like one without a title.
+var placeholderTitles = map[string]bool{
+	"untitled":      true,
+	"untitled note": true,
+	"new note":      true,
+	"voice note":    true,
+	"voice memo":    true,
+	"sem título":    true,
+	"sem titulo":    true,
+	"nova nota":     true,
+	"nota de voz":   true,
+}
+
+// IsPlaceholderTitle reports whether title is empty or one of the
+// placeholders capture apps fill in.
+func IsPlaceholderTitle(title string) bool {
+	title = strings.ToLower(strings.Join(strings.Fields(title), " "))
+	return title == "" || placeholderTitles[title]
+}
+
+// TitleFromContent returns a title for content: its first sentence, on its
+// first line that has text, without Markdown heading or list markers and
+// cut at a word to MaxAutoTitleLength. It returns "" when content has no
+// text.
+func TitleFromContent(content string) string {
+	var line string
+	for l := range strings.Lines(content) {
+		l = strings.TrimLeft(strings.TrimSpace(l), "#>-*• ")
+		if l != "" {
+			line = l
+			break
+		}
+	}
+	if i := sentenceEnd(line); i > 0 {
+		line = line[:i]
+	}
+	return ClampTitle(strings.Join(strings.Fields(line), " "))
+}
+
+// sentenceEnd returns the index just past the punctuation ending the first
+// sentence of line, or 0 when line is one sentence.
+func sentenceEnd(line string) int {
+	runes := []rune(line)
+	for i, r := range runes {
+		if (r == '.' || r == '!' || r == '?') && i+1 < len(runes) && unicode.IsSpace(runes[i+1]) {
+			return len(string(runes[:i+1]))
+		}
+	}
+	return 0
+}
+
+// ClampTitle cuts title to MaxAutoTitleLength characters, at the last word
+// that fits and with an ellipsis, when it is longer.
+func ClampTitle(title string) string {
+	runes := []rune(strings.TrimSpace(title))
+	if len(runes) <= MaxAutoTitleLength {
+		return string(runes)
+	}
+	cut := string(runes[:MaxAutoTitleLength-1])
+	if i := strings.LastIndexFunc(cut, unicode.IsSpace); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRightFunc(cut, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsPunct(r) }) + "…"
+}
+
+// SetAutoTitle gives the note a title generated from its content. An empty
+// title leaves the note as it is.
+func (n *Note) SetAutoTitle(title string) {
+	if title == "" {
+		return
+	}
+	n.Title = title
+	n.AutoTitled = true
+}
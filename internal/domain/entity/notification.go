@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SyncConflictNotice summarizes the client edits a sync discarded because the
+// server held a newer version.
+type SyncConflictNotice struct {
+	UserID     uuid.UUID
+	Email      string
+	DeviceID   string
+	Notes      []DiscardedEdit
+	OccurredAt time.Time
+}
+
+// DiscardedEdit is a client version of a note that lost to the server version.
+type DiscardedEdit struct {
+	NoteID          uuid.UUID
+	ClientID        string
+	Reference       string
+	Title           string
+	ClientTitle     string
+	ClientUpdatedAt time.Time
+	ServerUpdatedAt time.Time
+}
+
+// SecurityAlertNotice tells a user about suspicious activity on their account.
+type SecurityAlertNotice struct {
+	UserID uuid.UUID
+	Email  string
+	Alert  SecurityAlert
+}
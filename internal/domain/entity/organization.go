@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	OrgRoleMember = "member"
+	OrgRoleAdmin  = "admin"
+)
+
+type Organization struct {
+	ID               uuid.UUID
+	Slug             string
+	Name             string
+	Domain           string
+	OIDCIssuer       string
+	OIDCClientID     string
+	OIDCClientSecret string
+	SSOEnforced      bool
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// OwnsEmail reports whether the email address belongs to the organization's domain.
+func (o *Organization) OwnsEmail(email string) bool {
+	return strings.EqualFold(EmailDomain(email), o.Domain)
+}
+
+type OrgMembership struct {
+	OrgID           uuid.UUID
+	UserID          uuid.UUID
+	Role            string
+	ExternalSubject string
+	CreatedAt       time.Time
+}
+
+func NewOrgMembership(orgID, userID uuid.UUID, externalSubject string) *OrgMembership {
+	return &OrgMembership{
+		OrgID:           orgID,
+		UserID:          userID,
+		Role:            OrgRoleMember,
+		ExternalSubject: externalSubject,
+		CreatedAt:       time.Now().UTC(),
+	}
+}
+
+func EmailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(email[at+1:])
+}
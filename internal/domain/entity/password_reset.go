@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasswordResetToken lets a user set a new password without the old one.
+// Only a hash of the token is stored; the token itself is sent by email.
+type PasswordResetToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    *time.Time
+}
+
+func NewPasswordResetToken(userID uuid.UUID, tokenHash string, expiresAt time.Time) *PasswordResetToken {
+	return &PasswordResetToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// IsValid reports whether the token is unused and not expired at now.
+func (t *PasswordResetToken) IsValid(now time.Time) bool {
+	return t.UsedAt == nil && t.ExpiresAt.After(now)
+}
@@ -4,24 +4,89 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
 )
 
 type Photo struct {
-	ID        uuid.UUID
+	ID     uuid.UUID
+	NoteID uuid.UUID
+	// UserID is the owner of the photo's note, whoever uploaded it.
+	UserID   uuid.UUID
+	URL      string
+	Key      string
+	MimeType string
+	Size     int64
+	Width    int
+	Height   int
+	// Checksum is the SHA-256 of the stored image, for clients to verify
+	// their cached copy.
+	Checksum string
+	// SourceChecksum is the SHA-256 of the file as uploaded, before it was
+	// resized; clients compare it with their local file to skip uploading it
+	// again. Empty for photos uploaded before it was recorded.
+	SourceChecksum string
+	// ClientID is the device-generated ID the photo was uploaded with, if any.
+	ClientID string
+	// Encryption is the server-side encryption the storage applied ("AES256",
+	// "aws:kms"); empty when unencrypted or uploaded before it was recorded.
+	Encryption string
+	// Thumbnails are the smaller variants generated on upload, keyed by
+	// ThumbnailSmall and ThumbnailMedium. Photos uploaded before thumbnails
+	// were generated, or whose image could not be decoded, have none.
+	Thumbnails map[string]PhotoThumbnail
+	// TakenAt and Location come from the image's EXIF data; they are nil
+	// when the image does not carry them.
+	TakenAt  *time.Time
+	Location *valueobject.Location
+	// ExcludedFromShares keeps the photo from users the note is shared
+	// with, for images showing bystanders or sensitive equipment.
+	ExcludedFromShares bool
+	CreatedAt          time.Time
+}
+
+// Thumbnail sizes generated for uploaded photos.
+const (
+	ThumbnailSmall  = "small"
+	ThumbnailMedium = "medium"
+)
+
+// PhotoThumbnail is a downscaled JPEG copy of a photo.
+type PhotoThumbnail struct {
+	Key    string
+	URL    string
+	Width  int
+	Height int
+}
+
+// ObjectKeys returns the storage keys of the photo and its thumbnails.
+func (p *Photo) ObjectKeys() []string {
+	keys := []string{p.Key}
+	for _, t := range p.Thumbnails {
+		keys = append(keys, t.Key)
+	}
+	return keys
+}
+
+// VisibleTo reports whether the viewer may see the photo of a note owned by
+// owner. Excluded photos are only visible to the owner.
+func (p *Photo) VisibleTo(owner, viewer uuid.UUID) bool {
+	return !p.ExcludedFromShares || owner == viewer
+}
+
+// PhotoTombstone records a deleted photo so sync clients can drop their copy.
+type PhotoTombstone struct {
+	PhotoID   uuid.UUID
 	NoteID    uuid.UUID
-	URL       string
-	Key       string
-	MimeType  string
-	Size      int64
-	Width     int
-	Height    int
-	CreatedAt time.Time
+	ClientID  string
+	DeletedAt time.Time
 }
 
-func NewPhoto(noteID uuid.UUID, url, key, mimeType string, size int64, width, height int) *Photo {
+func NewPhoto(noteID, userID uuid.UUID, url, key, mimeType string, size int64, width, height int) *Photo {
 	return &Photo{
 		ID:        uuid.New(),
 		NoteID:    noteID,
+		UserID:    userID,
 		URL:       url,
 		Key:       key,
 		MimeType:  mimeType,
@@ -0,0 +1,126 @@
+package entity
+
+import (
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PII detector names, used in findings and to configure the scanner.
+const (
+	PIIEmail           = "email"
+	PIIPhone           = "phone"
+	PIICoordinates     = "coordinates"
+	PIIPreciseLocation = "precise_location"
+)
+
+// Phone numbers have between 9 and 15 digits (E.164); shorter digit runs are
+// usually dates, counts or measurements.
+const (
+	minPhoneDigits = 9
+	maxPhoneDigits = 15
+)
+
+var (
+	emailPattern = regexp.MustCompile(`[\p{L}\p{N}._%+-]+@[\p{L}\p{N}.-]+\.\p{L}{2,}`)
+	phonePattern = regexp.MustCompile(`\+?\(?\d[\d\s().-]{6,}\d`)
+	// coordinatePattern matches "lat, lng" pairs with at least four decimals,
+	// about 10 m of precision.
+	coordinatePattern = regexp.MustCompile(`-?\d{1,2}\.\d{4,}\s*[,;]\s*-?\d{1,3}\.\d{4,}`)
+)
+
+// PIIFinding is a piece of sensitive data found in a note. Start and End are
+// character offsets into the field; they are zero for the location field.
+type PIIFinding struct {
+	Kind  string
+	Field string
+	Match string
+	Start int
+	End   int
+}
+
+// PIIScanner looks for sensitive data that should not leave the account
+// unnoticed. The zero value detects nothing.
+type PIIScanner struct {
+	Detectors []string
+	// PreciseAccuracy is the accuracy radius in meters at or below which the
+	// note's own location is reported by the precise_location detector.
+	PreciseAccuracy float64
+}
+
+func IsPIIDetector(name string) bool {
+	switch name {
+	case PIIEmail, PIIPhone, PIICoordinates, PIIPreciseLocation:
+		return true
+	}
+	return false
+}
+
+func (s PIIScanner) enabled(detector string) bool {
+	for _, d := range s.Detectors {
+		if d == detector {
+			return true
+		}
+	}
+	return false
+}
+
+// Scan returns the findings in the note's title, content and location, in
+// that order.
+func (s PIIScanner) Scan(note *Note) []PIIFinding {
+	findings := []PIIFinding{}
+	for _, field := range []struct{ name, text string }{
+		{"title", note.Title},
+		{"content", note.Content},
+	} {
+		if s.enabled(PIIEmail) {
+			findings = append(findings, matches(PIIEmail, field.name, field.text, emailPattern, nil)...)
+		}
+		if s.enabled(PIIPhone) {
+			findings = append(findings, matches(PIIPhone, field.name, field.text, phonePattern, isPhoneNumber)...)
+		}
+		if s.enabled(PIICoordinates) {
+			findings = append(findings, matches(PIICoordinates, field.name, field.text, coordinatePattern, nil)...)
+		}
+	}
+
+	if s.enabled(PIIPreciseLocation) && note.Location != nil &&
+		(note.Location.Accuracy == nil || *note.Location.Accuracy <= s.PreciseAccuracy) {
+		findings = append(findings, PIIFinding{Kind: PIIPreciseLocation, Field: "location"})
+	}
+
+	return findings
+}
+
+func matches(kind, field, text string, pattern *regexp.Regexp, accept func(string) bool) []PIIFinding {
+	var findings []PIIFinding
+	for _, loc := range pattern.FindAllStringIndex(text, -1) {
+		match := text[loc[0]:loc[1]]
+		if accept != nil && !accept(match) {
+			continue
+		}
+		start := utf8.RuneCountInString(text[:loc[0]])
+		findings = append(findings, PIIFinding{
+			Kind:  kind,
+			Field: field,
+			Match: match,
+			Start: start,
+			End:   start + utf8.RuneCountInString(match),
+		})
+	}
+	return findings
+}
+
+func isPhoneNumber(s string) bool {
+	digits := 0
+	for _, r := range s {
+		if unicode.IsDigit(r) {
+			digits++
+		}
+	}
+	// Decimal numbers such as coordinates are not phone numbers.
+	if coordinatePattern.MatchString(s) {
+		return false
+	}
+	return digits >= minPhoneDigits && digits <= maxPhoneDigits
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Presence is a device that has a note open, so the others looking at a
+// shared note see who is there. It is ephemeral: it is only kept while the
+// device's connection keeps refreshing it.
+type Presence struct {
+	NoteID uuid.UUID
+	UserID uuid.UUID
+	// DeviceID is the device of the connection, or an ID of the connection
+	// when its token is not bound to a device.
+	DeviceID uuid.UUID
+	// Selection is the device's cursor or selection in the content, nil
+	// until the app sends one.
+	Selection *Selection
+	SeenAt    time.Time
+}
+
+// Selection is a range of the note content, in characters. A cursor is an
+// empty range.
+type Selection struct {
+	Start int
+	End   int
+}
+
+// PresenceEvent tells the devices that have a note open that another device
+// opened it or moved its selection, or, when Left is set, closed it.
+type PresenceEvent struct {
+	Presence
+	Left bool
+}
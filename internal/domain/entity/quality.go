@@ -0,0 +1,99 @@
+package entity
+
+import (
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+)
+
+// Rule names reported in quality results.
+const (
+	QualityRuleRequiredPhoto     = "required_photo"
+	QualityRuleMinContentLength  = "min_content_length"
+	QualityRuleWithinFence       = "within_fence"
+	QualityRuleAccuracyThreshold = "accuracy_threshold"
+)
+
+const (
+	QualityUnchecked = "unchecked"
+	QualityPassed    = "passed"
+	QualityFailed    = "failed"
+)
+
+// QualityRules are the completeness checks a user applies to their notes.
+// The zero value has no rules enabled.
+type QualityRules struct {
+	UserID           uuid.UUID
+	RequirePhoto     bool
+	MinContentLength int
+	Fence            *valueobject.BoundingBox
+	// MaxAccuracy is the worst accepted location accuracy radius in meters.
+	MaxAccuracy *float64
+	UpdatedAt   time.Time
+}
+
+// QualityResult is the outcome of evaluating the rules against one note.
+type QualityResult struct {
+	Status    string
+	Passed    []string
+	Failed    []string
+	CheckedAt *time.Time
+}
+
+// QualityReport summarizes the quality status of a user's notes.
+type QualityReport struct {
+	Total     int
+	Unchecked int
+	Passed    int
+	Failed    int
+	// RuleFailures counts failing notes per rule name.
+	RuleFailures map[string]int
+}
+
+func (r *QualityRules) HasRules() bool {
+	return r.RequirePhoto || r.MinContentLength > 0 || r.Fence != nil || r.MaxAccuracy != nil
+}
+
+// Evaluate checks the note against every enabled rule. Notes without a
+// location fail the fence and accuracy rules.
+func (r *QualityRules) Evaluate(note *Note, photoCount int) QualityResult {
+	if !r.HasRules() {
+		return QualityResult{Status: QualityUnchecked}
+	}
+
+	result := QualityResult{Passed: []string{}, Failed: []string{}}
+	check := func(rule string, ok bool) {
+		if ok {
+			result.Passed = append(result.Passed, rule)
+		} else {
+			result.Failed = append(result.Failed, rule)
+		}
+	}
+
+	if r.RequirePhoto {
+		check(QualityRuleRequiredPhoto, photoCount > 0)
+	}
+	if r.MinContentLength > 0 {
+		check(QualityRuleMinContentLength, utf8.RuneCountInString(note.Content) >= r.MinContentLength)
+	}
+	if r.Fence != nil {
+		check(QualityRuleWithinFence, note.Location != nil &&
+			r.Fence.Contains(note.Location.Latitude, note.Location.Longitude))
+	}
+	if r.MaxAccuracy != nil {
+		check(QualityRuleAccuracyThreshold, note.Location != nil && note.Location.Accuracy != nil &&
+			*note.Location.Accuracy <= *r.MaxAccuracy)
+	}
+
+	result.Status = QualityPassed
+	if len(result.Failed) > 0 {
+		result.Status = QualityFailed
+	}
+	now := time.Now().UTC()
+	result.CheckedAt = &now
+
+	return result
+}
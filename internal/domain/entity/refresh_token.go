@@ -14,30 +14,47 @@ type RefreshToken struct {
 	ExpiresAt time.Time
 	CreatedAt time.Time
 	RevokedAt *time.Time
+	// Platform and Policy record the session policy the token was issued
+	// under. Both are empty on tokens issued before policies were recorded.
+	Platform string
+	Policy   SessionPolicy
+}
+
+// SessionPolicy sets the token lifetimes and the number of concurrent
+// sessions a user may hold on one device platform. MaxSessions 0 is unlimited.
+type SessionPolicy struct {
+	AccessTTL   time.Duration
+	RefreshTTL  time.Duration
+	MaxSessions int
 }
 
 func NewRefreshToken(userID, deviceID uuid.UUID, token string, expiresAt time.Time) *RefreshToken {
+	return NewRefreshTokenAt(userID, deviceID, token, expiresAt, time.Now().UTC())
+}
+
+// NewRefreshTokenAt is NewRefreshToken issued at the given time, for callers
+// that read the time from a clock.
+func NewRefreshTokenAt(userID, deviceID uuid.UUID, token string, expiresAt, now time.Time) *RefreshToken {
 	return &RefreshToken{
 		ID:        uuid.New(),
 		UserID:    userID,
 		DeviceID:  deviceID,
 		Token:     token,
 		ExpiresAt: expiresAt,
-		CreatedAt: time.Now().UTC(),
+		CreatedAt: now,
 	}
 }
 
-func (rt *RefreshToken) Revoke() {
-	now := time.Now().UTC()
+func (rt *RefreshToken) Revoke(now time.Time) {
 	rt.RevokedAt = &now
 }
 
-func (rt *RefreshToken) IsValid() bool {
-	return rt.RevokedAt == nil && rt.ExpiresAt.After(time.Now().UTC())
+func (rt *RefreshToken) IsValid(now time.Time) bool {
+	return rt.RevokedAt == nil && rt.ExpiresAt.After(now)
 }
 
-func (rt *RefreshToken) IsExpired() bool {
-	return rt.ExpiresAt.Before(time.Now().UTC())
+func (rt *RefreshToken) IsExpired(now time.Time) bool {
+	return rt.ExpiresAt.Before(now)
 }
 
 func (rt *RefreshToken) IsRevoked() bool {
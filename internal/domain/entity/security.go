@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+)
+
+const (
+	AuthEventLogin   = "login"
+	AuthEventRefresh = "refresh"
+)
+
+// AuthEvent is one login or token refresh, kept for anomaly detection.
+// Location is nil when the address could not be placed.
+type AuthEvent struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	DeviceID  uuid.UUID
+	Kind      string
+	IP        string
+	City      string
+	Country   string
+	Location  *valueobject.Location
+	CreatedAt time.Time
+}
+
+func NewAuthEvent(userID, deviceID uuid.UUID, kind string, access DeviceAccess, location *valueobject.Location) *AuthEvent {
+	return &AuthEvent{
+		ID:        uuid.New(),
+		UserID:    userID,
+		DeviceID:  deviceID,
+		Kind:      kind,
+		IP:        access.IP,
+		City:      access.City,
+		Country:   access.Country,
+		Location:  location,
+		CreatedAt: access.At,
+	}
+}
+
+// Place is the event's city and country for display, or its IP when unknown.
+func (e *AuthEvent) Place() string {
+	switch {
+	case e.City != "" && e.Country != "":
+		return e.City + ", " + e.Country
+	case e.Country != "":
+		return e.Country
+	default:
+		return e.IP
+	}
+}
+
+const (
+	AlertImpossibleTravel = "impossible_travel"
+	AlertSyncVolume       = "sync_volume"
+	AlertRefreshStorm     = "refresh_storm"
+)
+
+// SecurityAlert flags suspicious account activity. Fingerprint identifies the
+// occurrence, such as the event or the day that triggered it, so repeated
+// analyzer runs over the same data raise it only once.
+type SecurityAlert struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	DeviceID    *uuid.UUID
+	Kind        string
+	Fingerprint string
+	Detail      string
+	CreatedAt   time.Time
+}
+
+func NewSecurityAlert(userID uuid.UUID, deviceID *uuid.UUID, kind, fingerprint, detail string) *SecurityAlert {
+	return &SecurityAlert{
+		ID:          uuid.New(),
+		UserID:      userID,
+		DeviceID:    deviceID,
+		Kind:        kind,
+		Fingerprint: fingerprint,
+		Detail:      detail,
+		CreatedAt:   time.Now().UTC(),
+	}
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+)
+
+// Sensitivity levels for notes at protected locations, such as nests or
+// rare plants. Anyone but the owner sees a generalized location.
+const (
+	SensitivityNone = "none"
+	SensitivityLow  = "low"
+	SensitivityHigh = "high"
+)
+
+// Default grid sizes in degrees, about 1 km and 10 km at the equator.
+const (
+	DefaultLowSensitivityGrid  = 0.01
+	DefaultHighSensitivityGrid = 0.1
+)
+
+func IsSensitivity(level string) bool {
+	switch level {
+	case SensitivityNone, SensitivityLow, SensitivityHigh:
+		return true
+	default:
+		return false
+	}
+}
+
+// LocationMask generalizes the location of sensitive notes for viewers other
+// than the owner. Grid sizes are in degrees; zero uses the defaults.
+type LocationMask struct {
+	LowGrid  float64
+	HighGrid float64
+}
+
+// Location returns the note's location as the viewer may see it and whether
+// it was generalized.
+func (m LocationMask) Location(n *Note, viewer uuid.UUID) (*valueobject.Location, bool) {
+	if n.Location == nil || n.UserID == viewer {
+		return n.Location, false
+	}
+
+	switch n.Sensitivity {
+	case SensitivityLow:
+		return n.Location.Generalize(orDefault(m.LowGrid, DefaultLowSensitivityGrid)), true
+	case SensitivityHigh:
+		return n.Location.Generalize(orDefault(m.HighGrid, DefaultHighSensitivityGrid)), true
+	default:
+		return n.Location, false
+	}
+}
+
+func orDefault(v, def float64) float64 {
+	if v > 0 {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Roles a note can be shared with.
+const (
+	ShareRoleViewer = "viewer"
+	ShareRoleEditor = "editor"
+)
+
+// AccessOwner is the access level of the note's owner; it is never stored as
+// a share role.
+const AccessOwner = "owner"
+
+type NoteShare struct {
+	NoteID    uuid.UUID
+	UserID    uuid.UUID
+	Role      string
+	GrantedBy uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewNoteShare(noteID, userID uuid.UUID, role string, grantedBy uuid.UUID) *NoteShare {
+	now := time.Now().UTC()
+	return &NoteShare{
+		NoteID:    noteID,
+		UserID:    userID,
+		Role:      role,
+		GrantedBy: grantedBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func IsShareRole(role string) bool {
+	return role == ShareRoleViewer || role == ShareRoleEditor
+}
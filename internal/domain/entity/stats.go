@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserStats are per-user counters kept up to date by database triggers.
+// NoteCount excludes deleted notes; photos of deleted notes still count
+// because their files are still stored.
+type UserStats struct {
+	UserID       uuid.UUID
+	NoteCount    int64
+	PhotoCount   int64
+	StorageBytes int64
+	UpdatedAt    time.Time
+}
+
+// StatsDrift is a user whose stored counters differed from a full recount.
+type StatsDrift struct {
+	Stored UserStats
+	Actual UserStats
+}
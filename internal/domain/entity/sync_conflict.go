@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Sides of a sync conflict a user can keep.
+const (
+	ConflictSideClient = "client"
+	ConflictSideServer = "server"
+)
+
+// SyncConflict is a client version of a note held back by the manual
+// conflict strategy until the user keeps one side, or until it expires.
+type SyncConflict struct {
+	ID       uuid.UUID
+	UserID   uuid.UUID
+	NoteID   uuid.UUID
+	ClientID string
+	DeviceID string
+	// ClientVersion is the note as the device sent it; only its title,
+	// content, location, measurements, tags, deletion and UpdatedAt are set.
+	ClientVersion Note
+	// ServerUpdatedAt is the version of the stored note the client version
+	// conflicted with. Keeping the client side is refused once the stored
+	// note has moved past it.
+	ServerUpdatedAt time.Time
+	CreatedAt       time.Time
+	ExpiresAt       time.Time
+	// Resolution is the side kept, empty while the conflict is pending.
+	Resolution string
+	ResolvedAt *time.Time
+}
+
+func NewSyncConflict(server *Note, client Note, deviceID string, retention time.Duration) *SyncConflict {
+	now := time.Now().UTC()
+	return &SyncConflict{
+		ID:              uuid.New(),
+		UserID:          server.UserID,
+		NoteID:          server.ID,
+		ClientID:        server.ClientID,
+		DeviceID:        deviceID,
+		ClientVersion:   client,
+		ServerUpdatedAt: server.UpdatedAt,
+		CreatedAt:       now,
+		ExpiresAt:       now.Add(retention),
+	}
+}
+
+func (c *SyncConflict) IsPending() bool {
+	return c.ResolvedAt == nil
+}
+
+func (c *SyncConflict) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
+
+// Resolve records the side the user kept.
+func (c *SyncConflict) Resolve(side string) {
+	now := time.Now().UTC()
+	c.Resolution = side
+	c.ResolvedAt = &now
+}
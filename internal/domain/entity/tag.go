@@ -0,0 +1,95 @@
+package entity
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+)
+
+const (
+	// MaxTagLength is the longest tag name, in characters.
+	MaxTagLength = 50
+	// MaxNoteTags caps how many tags a single note can carry.
+	MaxNoteTags = 20
+)
+
+// NormalizeTag returns the canonical form of a tag name: trimmed and lower
+// case. Names may contain letters, digits, '-' and '_'. ok is false when
+// the name is empty, too long or has other characters.
+func NormalizeTag(name string) (string, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || utf8.RuneCountInString(name) > MaxTagLength {
+		return "", false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return "", false
+		}
+	}
+	return name, true
+}
+
+// NormalizeTags normalizes every name and returns them sorted without
+// duplicates. ok is false if any name is invalid.
+func NormalizeTags(names []string) ([]string, bool) {
+	tags := make([]string, 0, len(names))
+	for _, name := range names {
+		tag, ok := NormalizeTag(name)
+		if !ok {
+			return nil, false
+		}
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags), true
+}
+
+// AddTags labels the note with the given normalized tags, keeping Tags
+// sorted and unique.
+func (n *Note) AddTags(tags []string) {
+	n.Tags = append(n.Tags, tags...)
+	slices.Sort(n.Tags)
+	n.Tags = slices.Compact(n.Tags)
+}
+
+// RemoveTags drops the given normalized tags from the note.
+func (n *Note) RemoveTags(tags []string) {
+	n.Tags = slices.DeleteFunc(n.Tags, func(t string) bool {
+		return slices.Contains(tags, t)
+	})
+}
+
+// sanitizeTags normalizes the note's tags, dropping invalid names and any
+// beyond MaxNoteTags with a warning. Nil tags stay nil: synced notes use nil
+// to leave the stored tags alone.
+func (n *Note) sanitizeTags() {
+	if n.Tags == nil {
+		return
+	}
+
+	tags := make([]string, 0, len(n.Tags))
+	for _, name := range n.Tags {
+		tag, ok := NormalizeTag(name)
+		if !ok {
+			n.Warnings = append(n.Warnings, valueobject.NewWarning(
+				valueobject.WarningTagDropped, "tags", fmt.Sprintf("tag %q is not a valid tag name", name),
+			))
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+	tags = slices.Compact(tags)
+
+	if len(tags) > MaxNoteTags {
+		n.Warnings = append(n.Warnings, valueobject.NewWarning(
+			valueobject.WarningTagDropped, "tags", fmt.Sprintf("a note can have at most %d tags; the rest were dropped", MaxNoteTags),
+		))
+		tags = tags[:MaxNoteTags]
+	}
+	n.Tags = tags
+}
@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Team roles. The owner created the team; owners and admins manage its
+// members and have owner access to its notes. Editors can create and edit
+// the team's notes, viewers only read them.
+const (
+	TeamRoleOwner  = "owner"
+	TeamRoleAdmin  = "admin"
+	TeamRoleEditor = "editor"
+	TeamRoleViewer = "viewer"
+)
+
+// MaxTeamNameLength is the team name size in characters.
+const MaxTeamNameLength = 100
+
+// Team is a group of users sharing field projects: notes created for the
+// team are visible to all its members.
+type Team struct {
+	ID        uuid.UUID
+	Name      string
+	CreatedBy uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewTeam(name string, createdBy uuid.UUID) *Team {
+	now := time.Now().UTC()
+	return &Team{
+		ID:        uuid.New(),
+		Name:      name,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// TeamMember is a user invited to a team. The invitation is pending until
+// the user accepts it; only then does the role grant access.
+type TeamMember struct {
+	TeamID uuid.UUID
+	UserID uuid.UUID
+	// Email is filled in when members are listed.
+	Email      string
+	Role       string
+	InvitedBy  *uuid.UUID
+	CreatedAt  time.Time
+	AcceptedAt *time.Time
+}
+
+// NewTeamInvite returns a pending membership of the user in the team.
+func NewTeamInvite(teamID, userID uuid.UUID, role string, invitedBy uuid.UUID) *TeamMember {
+	return &TeamMember{
+		TeamID:    teamID,
+		UserID:    userID,
+		Role:      role,
+		InvitedBy: &invitedBy,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+func (m *TeamMember) Pending() bool {
+	return m.AcceptedAt == nil
+}
+
+// UserTeam is a team as one of its members, or invitees, sees it.
+type UserTeam struct {
+	Team
+	Membership TeamMember
+}
+
+// IsTeamInviteRole reports whether members can be invited with, or given,
+// the role; a team has one owner, its creator.
+func IsTeamInviteRole(role string) bool {
+	return role == TeamRoleAdmin || role == TeamRoleEditor || role == TeamRoleViewer
+}
+
+// ManagesTeam reports whether the role can manage the team's members and
+// has owner access to its notes.
+func ManagesTeam(role string) bool {
+	return role == TeamRoleOwner || role == TeamRoleAdmin
+}
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
 )
 
 type User struct {
@@ -11,6 +13,18 @@ type User struct {
 	Email        string
 	PasswordHash string
 	Name         string
+	UnitSystem   string
+	NotePrefix   string
+	// NotifySyncConflicts sends a notification when a sync discards one of
+	// the user's edits.
+	NotifySyncConflicts bool
+	// SemanticSearch lets the user's notes be embedded for semantic search;
+	// off by default, since it sends their content to the embeddings
+	// provider.
+	SemanticSearch bool
+	// TokenVersion is the generation of the user's access tokens; tokens
+	// issued under an older version are rejected.
+	TokenVersion int
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 }
@@ -18,11 +32,36 @@ type User struct {
 func NewUser(email, passwordHash, name string) *User {
 	now := time.Now().UTC()
 	return &User{
-		ID:           uuid.New(),
-		Email:        email,
-		PasswordHash: passwordHash,
-		Name:         name,
-		CreatedAt:    now,
-		UpdatedAt:    now,
+		ID:                  uuid.New(),
+		Email:               email,
+		PasswordHash:        passwordHash,
+		Name:                name,
+		UnitSystem:          valueobject.UnitSystemMetric,
+		NotePrefix:          DefaultNotePrefix,
+		NotifySyncConflicts: true,
+		CreatedAt:           now,
+		UpdatedAt:           now,
 	}
 }
+
+func (u *User) SetUnitSystem(system string) {
+	u.UnitSystem = system
+	u.UpdatedAt = time.Now().UTC()
+}
+
+// SetNotePrefix changes the prefix of references given to new notes.
+// Existing notes keep the reference they were assigned.
+func (u *User) SetNotePrefix(prefix string) {
+	u.NotePrefix = prefix
+	u.UpdatedAt = time.Now().UTC()
+}
+
+func (u *User) SetNotifySyncConflicts(notify bool) {
+	u.NotifySyncConflicts = notify
+	u.UpdatedAt = time.Now().UTC()
+}
+
+func (u *User) SetSemanticSearch(enabled bool) {
+	u.SemanticSearch = enabled
+	u.UpdatedAt = time.Now().UTC()
+}
@@ -3,17 +3,74 @@ package domain
 import "errors"
 
 var (
-	ErrUserNotFound       = errors.New("user not found")
-	ErrUserAlreadyExists  = errors.New("user already exists")
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrNoteNotFound       = errors.New("note not found")
-	ErrPhotoNotFound      = errors.New("photo not found")
-	ErrUnauthorized       = errors.New("unauthorized")
-	ErrForbidden          = errors.New("forbidden")
-	ErrTokenExpired       = errors.New("token expired")
-	ErrTokenInvalid       = errors.New("token invalid")
-	ErrTokenRevoked       = errors.New("token revoked")
-	ErrDeviceNotFound     = errors.New("device not found")
-	ErrInvalidBoundingBox = errors.New("invalid bounding box")
-	ErrInvalidLocation    = errors.New("invalid location")
+	ErrUserNotFound            = errors.New("user not found")
+	ErrUserAlreadyExists       = errors.New("user already exists")
+	ErrInvalidCredentials      = errors.New("invalid credentials")
+	ErrNoteNotFound            = errors.New("note not found")
+	ErrNoteAlreadyExists       = errors.New("note already exists")
+	ErrRestoreExpired          = errors.New("restore window expired")
+	ErrPhotoNotFound           = errors.New("photo not found")
+	ErrPhotoAlreadyExists      = errors.New("photo already exists")
+	ErrPhotoClientIDInUse      = errors.New("photo client id used on another note")
+	ErrAttachmentNotFound      = errors.New("attachment not found")
+	ErrAttachmentAlreadyExists = errors.New("attachment already exists")
+	ErrAttachmentClientIDInUse = errors.New("attachment client id used on another note")
+	ErrUnauthorized            = errors.New("unauthorized")
+	ErrForbidden               = errors.New("forbidden")
+	ErrTokenExpired            = errors.New("token expired")
+	ErrTokenInvalid            = errors.New("token invalid")
+	ErrTokenRevoked            = errors.New("token revoked")
+	ErrResetTokenInvalid       = errors.New("password reset token invalid")
+	ErrDeviceNotFound          = errors.New("device not found")
+	ErrDeviceAlreadyExists     = errors.New("device already exists")
+	ErrInvalidBoundingBox      = errors.New("invalid bounding box")
+	ErrInvalidLocation         = errors.New("invalid location")
+	ErrInvalidSearchQuery      = errors.New("invalid search query")
+	ErrInvalidZoom             = errors.New("invalid zoom")
+	ErrOrgNotFound             = errors.New("organization not found")
+	ErrSSORequired             = errors.New("sso login required")
+	ErrSSOFailed               = errors.New("sso login failed")
+	ErrInvalidIssuer           = errors.New("invalid oidc issuer")
+	ErrUnknownProvider         = errors.New("unknown identity provider")
+	ErrSocialLoginFailed       = errors.New("social login failed")
+	ErrProviderNotLinked       = errors.New("identity provider account not linked")
+	ErrInvalidUnitSystem       = errors.New("invalid unit system")
+	ErrInvalidNotePrefix       = errors.New("invalid note prefix")
+	ErrInvalidShareRole        = errors.New("invalid share role")
+	ErrShareWithOwner          = errors.New("cannot share a note with its owner")
+	ErrInvalidSensitivity      = errors.New("invalid sensitivity")
+	ErrInvalidNoteSource       = errors.New("invalid note source")
+	ErrInvalidMerge            = errors.New("invalid merge")
+	ErrInvalidTag              = errors.New("invalid tag")
+	ErrInvalidNoteColor        = errors.New("invalid note color")
+	ErrInvalidNoteIcon         = errors.New("invalid note icon")
+	ErrTooManyTags             = errors.New("too many tags")
+	ErrInvalidCursor           = errors.New("invalid cursor")
+	ErrInvalidSort             = errors.New("invalid sort")
+	ErrInvalidDateRange        = errors.New("invalid date range")
+	ErrInvalidTitle            = errors.New("invalid title")
+	ErrInvalidClientID         = errors.New("invalid client id")
+	ErrDuplicateClientID       = errors.New("duplicate client id")
+	ErrJobNotFound             = errors.New("job not found")
+	ErrJobRunning              = errors.New("job already running")
+	ErrUnsupportedPlatform     = errors.New("unsupported platform")
+	ErrSyncConflictNotFound    = errors.New("sync conflict not found")
+	ErrSyncConflictResolved    = errors.New("sync conflict already resolved")
+	ErrNoteChanged             = errors.New("note changed since the conflict")
+	ErrTeamNotFound            = errors.New("team not found")
+	ErrInvalidTeamName         = errors.New("invalid team name")
+	ErrTeamMemberNotFound      = errors.New("team member not found")
+	ErrAlreadyTeamMember       = errors.New("already a team member")
+	ErrInvalidTeamRole         = errors.New("invalid team role")
+	ErrTeamOwnerCannotLeave    = errors.New("team owner cannot leave the team")
+	ErrInvalidDeviceAuthor     = errors.New("invalid device author")
+	ErrPushNotSupported        = errors.New("push not supported on the device platform")
+	ErrPushTokenInvalid        = errors.New("push token no longer valid")
+	ErrDocumentNotFound        = errors.New("note is not collaborative")
+	ErrDocumentExists          = errors.New("note is already collaborative")
+	ErrInvalidDocumentUpdate   = errors.New("invalid document update")
+	ErrNoteCollaborative       = errors.New("note content is edited collaboratively")
+	ErrLoginLocked             = errors.New("too many failed logins")
+	ErrRevisionNotFound        = errors.New("note revision not found")
+	ErrSemanticSearchOff       = errors.New("semantic search is turned off")
 )
@@ -1,5 +1,7 @@
 package valueobject
 
+import "math"
+
 type Location struct {
 	Latitude  float64
 	Longitude float64
@@ -20,3 +22,56 @@ func (l *Location) IsValid() bool {
 	return l.Latitude >= -90 && l.Latitude <= 90 &&
 		l.Longitude >= -180 && l.Longitude <= 180
 }
+
+// Equal reports whether l and o are the same position with the same
+// altitude and accuracy. Two nil locations are equal.
+func (l *Location) Equal(o *Location) bool {
+	if l == nil || o == nil {
+		return l == o
+	}
+	return l.Latitude == o.Latitude && l.Longitude == o.Longitude &&
+		sameFloat(l.Altitude, o.Altitude) && sameFloat(l.Accuracy, o.Accuracy)
+}
+
+func sameFloat(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// metersPerDegree is the length of one degree of latitude, close enough for
+// reporting the size of a generalized cell.
+const metersPerDegree = 111_320.0
+
+// Generalize snaps the location to the center of its cell in a grid of the
+// given size in degrees. Altitude is dropped and accuracy becomes the cell
+// size in meters, so clients draw the area rather than a point.
+func (l *Location) Generalize(grid float64) *Location {
+	accuracy := math.Round(grid * metersPerDegree)
+	return &Location{
+		Latitude:  snapToCell(l.Latitude, grid, 90),
+		Longitude: snapToCell(l.Longitude, grid, 180),
+		Accuracy:  &accuracy,
+	}
+}
+
+// earthRadiusKm is the mean radius used for great-circle distances.
+const earthRadiusKm = 6371.0
+
+// DistanceKm is the great-circle distance to o, ignoring altitude.
+func (l *Location) DistanceKm(o *Location) float64 {
+	lat1 := l.Latitude * math.Pi / 180
+	lat2 := o.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLng := (o.Longitude - l.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+func snapToCell(v, grid, limit float64) float64 {
+	center := math.Floor(v/grid)*grid + grid/2
+	return math.Max(-limit, math.Min(limit, center))
+}
@@ -0,0 +1,114 @@
+package valueobject
+
+const (
+	MeasurementLength      = "length"
+	MeasurementMass        = "mass"
+	MeasurementTemperature = "temperature"
+)
+
+// Unit systems used to present measurements.
+const (
+	UnitSystemMetric   = "metric"
+	UnitSystemImperial = "imperial"
+)
+
+// Measurement is a named physical quantity. Value is always stored in the SI
+// unit of its kind: meters, kilograms or kelvin.
+type Measurement struct {
+	Name  string
+	Kind  string
+	Value float64
+}
+
+type unit struct {
+	kind   string
+	toSI   func(float64) float64
+	fromSI func(float64) float64
+}
+
+func linear(kind string, factor float64) unit {
+	return unit{
+		kind:   kind,
+		toSI:   func(v float64) float64 { return v * factor },
+		fromSI: func(v float64) float64 { return v / factor },
+	}
+}
+
+var units = map[string]unit{
+	"m":  linear(MeasurementLength, 1),
+	"cm": linear(MeasurementLength, 0.01),
+	"mm": linear(MeasurementLength, 0.001),
+	"km": linear(MeasurementLength, 1000),
+	"in": linear(MeasurementLength, 0.0254),
+	"ft": linear(MeasurementLength, 0.3048),
+	"yd": linear(MeasurementLength, 0.9144),
+	"mi": linear(MeasurementLength, 1609.344),
+	"kg": linear(MeasurementMass, 1),
+	"g":  linear(MeasurementMass, 0.001),
+	"mg": linear(MeasurementMass, 0.000001),
+	"lb": linear(MeasurementMass, 0.45359237),
+	"oz": linear(MeasurementMass, 0.028349523125),
+	"K":  linear(MeasurementTemperature, 1),
+	"C": {
+		kind:   MeasurementTemperature,
+		toSI:   func(v float64) float64 { return v + 273.15 },
+		fromSI: func(v float64) float64 { return v - 273.15 },
+	},
+	"F": {
+		kind:   MeasurementTemperature,
+		toSI:   func(v float64) float64 { return (v-32)*5/9 + 273.15 },
+		fromSI: func(v float64) float64 { return (v-273.15)*9/5 + 32 },
+	},
+}
+
+var displayUnits = map[string]map[string]string{
+	UnitSystemMetric: {
+		MeasurementLength:      "m",
+		MeasurementMass:        "kg",
+		MeasurementTemperature: "C",
+	},
+	UnitSystemImperial: {
+		MeasurementLength:      "ft",
+		MeasurementMass:        "lb",
+		MeasurementTemperature: "F",
+	},
+}
+
+// NewMeasurement normalizes a value given in unit to SI. It returns false for
+// unknown units and for values that are not physically possible.
+func NewMeasurement(name string, value float64, unitName string) (Measurement, bool) {
+	u, ok := units[unitName]
+	if !ok {
+		return Measurement{}, false
+	}
+
+	m := Measurement{Name: name, Kind: u.kind, Value: u.toSI(value)}
+	return m, m.IsValid()
+}
+
+// IsValid reports whether the SI value is possible for its kind: lengths and
+// masses are not negative and temperatures are not below absolute zero.
+func (m Measurement) IsValid() bool {
+	switch m.Kind {
+	case MeasurementLength, MeasurementMass, MeasurementTemperature:
+		return m.Value >= 0
+	default:
+		return false
+	}
+}
+
+// In converts the measurement to the display unit of the given unit system,
+// falling back to metric for unknown systems.
+func (m Measurement) In(system string) (float64, string) {
+	names, ok := displayUnits[system]
+	if !ok {
+		names = displayUnits[UnitSystemMetric]
+	}
+	name := names[m.Kind]
+	return units[name].fromSI(m.Value), name
+}
+
+func IsUnitSystem(system string) bool {
+	_, ok := displayUnits[system]
+	return ok
+}
@@ -0,0 +1,24 @@
+package valueobject
+
+// Warning codes for non-fatal data quality issues. The request still succeeds.
+const (
+	WarningLowAccuracy      = "LOW_LOCATION_ACCURACY"
+	WarningFutureTimestamp  = "FUTURE_TIMESTAMP_CLAMPED"
+	WarningContentTruncated = "CONTENT_TRUNCATED"
+	WarningTagDropped       = "TAG_DROPPED"
+	WarningStyleDropped     = "STYLE_DROPPED"
+	// WarningContentKept is given when the content of a collaborative note
+	// was left as the server has it, since it only changes through updates
+	// to the note's document.
+	WarningContentKept = "COLLABORATIVE_CONTENT_KEPT"
+)
+
+type Warning struct {
+	Code    string
+	Field   string
+	Message string
+}
+
+func NewWarning(code, field, message string) Warning {
+	return Warning{Code: code, Field: field, Message: message}
+}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"crypto"
 	"crypto/rand"
 	"encoding/base64"
 	"fmt"
@@ -13,27 +14,77 @@ import (
 )
 
 type JWTService struct {
-	secretKey      []byte
+	keyring        *Keyring
 	accessTokenTTL time.Duration
 }
 
 type Claims struct {
 	UserID string `json:"user_id"`
+	// SessionID is the ID of the refresh token issued alongside the access
+	// token, tying the token to the session that obtained it.
+	SessionID string `json:"sid,omitempty"`
+	// DeviceID is the ID of the registered device the session belongs to.
+	DeviceID string `json:"did,omitempty"`
+	// TokenVersion is the user's token version when the token was issued.
+	TokenVersion int `json:"ver"`
 	jwt.RegisteredClaims
 }
 
+// AccessToken is the identity carried by a valid access token.
+type AccessToken struct {
+	UserID uuid.UUID
+	// SessionID is uuid.Nil for tokens issued before sessions were bound.
+	SessionID uuid.UUID
+	// DeviceID is uuid.Nil for tokens issued before devices were bound.
+	DeviceID uuid.UUID
+	// TokenVersion is zero for tokens issued before versions were tracked,
+	// which matches every user that has not invalidated their tokens since.
+	TokenVersion int
+}
+
+// NewJWTService signs and verifies tokens with a single secret, and no kid.
 func NewJWTService(secretKey string, accessTokenTTL time.Duration) *JWTService {
 	return &JWTService{
-		secretKey:      []byte(secretKey),
+		keyring: &Keyring{keys: map[string]signingKey{
+			"": {method: jwt.SigningMethodHS256, private: []byte(secretKey), public: []byte(secretKey)},
+		}},
 		accessTokenTTL: accessTokenTTL,
 	}
 }
 
-func (s *JWTService) GenerateAccessToken(userID uuid.UUID) (string, time.Time, error) {
-	expiresAt := time.Now().UTC().Add(s.accessTokenTTL)
+// NewJWTServiceWithKeyring signs tokens with the current key of keyring and
+// verifies them with any of its keys.
+func NewJWTServiceWithKeyring(keyring *Keyring, accessTokenTTL time.Duration) *JWTService {
+	return &JWTService{
+		keyring:        keyring,
+		accessTokenTTL: accessTokenTTL,
+	}
+}
+
+// PublicKeys returns the public keys access tokens can be verified with, by
+// key id; see Keyring.PublicKeys.
+func (s *JWTService) PublicKeys() map[string]crypto.PublicKey {
+	return s.keyring.PublicKeys()
+}
+
+// AccessTokenTTL is the lifetime of access tokens issued without a ttl.
+func (s *JWTService) AccessTokenTTL() time.Duration {
+	return s.accessTokenTTL
+}
+
+// GenerateAccessToken signs an access token carrying access valid for ttl,
+// or for AccessTokenTTL when ttl is zero.
+func (s *JWTService) GenerateAccessToken(access AccessToken, ttl time.Duration) (string, time.Time, error) {
+	if ttl <= 0 {
+		ttl = s.accessTokenTTL
+	}
+	expiresAt := time.Now().UTC().Add(ttl)
 
 	claims := Claims{
-		UserID: userID.String(),
+		UserID:       access.UserID.String(),
+		SessionID:    access.SessionID.String(),
+		DeviceID:     access.DeviceID.String(),
+		TokenVersion: access.TokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
@@ -42,8 +93,7 @@ func (s *JWTService) GenerateAccessToken(userID uuid.UUID) (string, time.Time, e
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenStr, err := token.SignedString(s.secretKey)
+	tokenStr, err := s.keyring.sign(claims)
 	if err != nil {
 		return "", time.Time{}, fmt.Errorf("signing token: %w", err)
 	}
@@ -51,28 +101,34 @@ func (s *JWTService) GenerateAccessToken(userID uuid.UUID) (string, time.Time, e
 	return tokenStr, expiresAt, nil
 }
 
-func (s *JWTService) ValidateAccessToken(tokenStr string) (uuid.UUID, error) {
-	token, err := jwt.ParseWithClaims(tokenStr, &Claims{}, func(token *jwt.Token) (any, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return s.secretKey, nil
-	})
+func (s *JWTService) ValidateAccessToken(tokenStr string) (*AccessToken, error) {
+	token, err := jwt.ParseWithClaims(tokenStr, &Claims{}, s.keyring.verificationKey)
 	if err != nil {
-		return uuid.Nil, domain.ErrTokenInvalid
+		return nil, domain.ErrTokenInvalid
 	}
 
 	claims, ok := token.Claims.(*Claims)
 	if !ok || !token.Valid {
-		return uuid.Nil, domain.ErrTokenInvalid
+		return nil, domain.ErrTokenInvalid
 	}
 
 	userID, err := uuid.Parse(claims.UserID)
 	if err != nil {
-		return uuid.Nil, domain.ErrTokenInvalid
+		return nil, domain.ErrTokenInvalid
 	}
 
-	return userID, nil
+	access := &AccessToken{UserID: userID, TokenVersion: claims.TokenVersion}
+	if claims.SessionID != "" {
+		if access.SessionID, err = uuid.Parse(claims.SessionID); err != nil {
+			return nil, domain.ErrTokenInvalid
+		}
+	}
+	if claims.DeviceID != "" {
+		if access.DeviceID, err = uuid.Parse(claims.DeviceID); err != nil {
+			return nil, domain.ErrTokenInvalid
+		}
+	}
+	return access, nil
 }
 
 func (s *JWTService) GenerateRefreshToken() (string, error) {
@@ -82,3 +138,44 @@ func (s *JWTService) GenerateRefreshToken() (string, error) {
 	}
 	return base64.URLEncoding.EncodeToString(b), nil
 }
+
+const ssoStateAudience = "sso-state"
+
+// SSOState carries the login context across the identity provider redirect.
+type SSOState struct {
+	OrgSlug    string `json:"org"`
+	DeviceID   string `json:"device_id"`
+	DeviceName string `json:"device_name,omitempty"`
+	Platform   string `json:"platform"`
+	Nonce      string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+func (s *JWTService) GenerateSSOState(state SSOState, ttl time.Duration) (string, error) {
+	now := time.Now().UTC()
+	state.RegisteredClaims = jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		IssuedAt:  jwt.NewNumericDate(now),
+		Issuer:    "field-notes",
+		Audience:  jwt.ClaimStrings{ssoStateAudience},
+	}
+
+	tokenStr, err := s.keyring.sign(state)
+	if err != nil {
+		return "", fmt.Errorf("signing sso state: %w", err)
+	}
+	return tokenStr, nil
+}
+
+func (s *JWTService) ValidateSSOState(stateStr string) (*SSOState, error) {
+	token, err := jwt.ParseWithClaims(stateStr, &SSOState{}, s.keyring.verificationKey, jwt.WithAudience(ssoStateAudience))
+	if err != nil {
+		return nil, domain.ErrTokenInvalid
+	}
+
+	state, ok := token.Claims.(*SSOState)
+	if !ok || !token.Valid {
+		return nil, domain.ErrTokenInvalid
+	}
+	return state, nil
+}
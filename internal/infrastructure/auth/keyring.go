@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSABits is the smallest RSA key accepted for signing.
+const minRSABits = 2048
+
+// Keyring holds the keys tokens are signed with, by key id. New tokens are
+// signed with the current key and name it in their kid header; a token is
+// verified with the key it names, so tokens signed with an older key stay
+// valid for as long as that key is kept.
+//
+// Keys are HMAC secrets, or RSA (RS256) and Ed25519 (EdDSA) private keys
+// whose public halves other services can verify tokens with; see
+// PublicKeys. A token must use the algorithm of the key it names.
+//
+// The key with the empty id is the one from before keys had ids: tokens it
+// signs carry no kid, and tokens without a kid are verified with it.
+type Keyring struct {
+	keys    map[string]signingKey
+	current string
+}
+
+type signingKey struct {
+	method jwt.SigningMethod
+	// private signs and public verifies; both are the secret for HMAC.
+	private any
+	public  any
+}
+
+// NewKeyring returns a keyring of the HMAC secrets and the PEM-encoded
+// private keys, by key id, signing with the key current.
+func NewKeyring(secrets map[string]string, current string, privateKeys map[string][]byte) (*Keyring, error) {
+	k := &Keyring{keys: make(map[string]signingKey, len(secrets)+len(privateKeys)), current: current}
+	for id, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		k.keys[id] = signingKey{method: jwt.SigningMethodHS256, private: []byte(secret), public: []byte(secret)}
+	}
+	for id, data := range privateKeys {
+		if id == "" {
+			return nil, errors.New("private keys need a key id")
+		}
+		if _, ok := k.keys[id]; ok {
+			return nil, fmt.Errorf("key %q is both a secret and a private key", id)
+		}
+		key, err := parsePrivateKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		k.keys[id] = key
+	}
+	if _, ok := k.keys[current]; !ok {
+		if current == "" {
+			return nil, errors.New("no key to sign tokens with")
+		}
+		return nil, fmt.Errorf("signing key %q is not in the keyring", current)
+	}
+	return k, nil
+}
+
+// parsePrivateKey reads a PKCS #8 or PKCS #1 PEM private key.
+func parsePrivateKey(data []byte) (signingKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return signingKey{}, errors.New("no PEM data")
+	}
+	var parsed any
+	var err error
+	if block.Type == "RSA PRIVATE KEY" {
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	} else {
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return signingKey{}, fmt.Errorf("parsing private key: %w", err)
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		if key.N.BitLen() < minRSABits {
+			return signingKey{}, fmt.Errorf("RSA keys must have at least %d bits", minRSABits)
+		}
+		return signingKey{method: jwt.SigningMethodRS256, private: key, public: &key.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return signingKey{method: jwt.SigningMethodEdDSA, private: key, public: key.Public()}, nil
+	default:
+		return signingKey{}, fmt.Errorf("unsupported key type %T; use RSA or Ed25519", parsed)
+	}
+}
+
+// sign signs claims with the current key.
+func (k *Keyring) sign(claims jwt.Claims) (string, error) {
+	key := k.keys[k.current]
+	token := jwt.NewWithClaims(key.method, claims)
+	if k.current != "" {
+		token.Header["kid"] = k.current
+	}
+	return token.SignedString(key.private)
+}
+
+// verificationKey returns the key token names, for jwt.Parse.
+func (k *Keyring) verificationKey(token *jwt.Token) (any, error) {
+	var kid string
+	if v, ok := token.Header["kid"]; ok {
+		if kid, ok = v.(string); !ok || kid == "" {
+			return nil, errors.New("invalid kid")
+		}
+	}
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+	// Otherwise a token could name an RSA key and be signed with HMAC,
+	// using the published public key as the secret.
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.public, nil
+}
+
+// PublicKeys returns the public keys of the keyring's private keys, by key
+// id, for other services to verify tokens with. HMAC secrets are left out.
+func (k *Keyring) PublicKeys() map[string]crypto.PublicKey {
+	keys := make(map[string]crypto.PublicKey)
+	for id, key := range k.keys {
+		if key.method != jwt.SigningMethodHS256 {
+			keys[id] = key.public
+		}
+	}
+	return keys
+}
@@ -0,0 +1,158 @@
+package auth_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/auth"
+)
+
+func TestKeyring_Rotation(t *testing.T) {
+	access := auth.AccessToken{UserID: uuid.New()}
+	service := func(t *testing.T, keys map[string]string, current string) *auth.JWTService {
+		t.Helper()
+		keyring, err := auth.NewKeyring(keys, current, nil)
+		require.NoError(t, err)
+		return auth.NewJWTServiceWithKeyring(keyring, time.Minute)
+	}
+	sign := func(t *testing.T, svc *auth.JWTService) string {
+		t.Helper()
+		token, _, err := svc.GenerateAccessToken(access, 0)
+		require.NoError(t, err)
+		return token
+	}
+
+	legacy := sign(t, auth.NewJWTService("old-secret", time.Minute))
+	beforeRotation := sign(t, service(t, map[string]string{"": "old-secret", "k1": "first"}, "k1"))
+
+	// k2 is added and signs new tokens; k1 and the legacy secret still verify.
+	rotated := service(t, map[string]string{"": "old-secret", "k1": "first", "k2": "second"}, "k2")
+	afterRotation := sign(t, rotated)
+	for _, token := range []string{legacy, beforeRotation, afterRotation} {
+		got, err := rotated.ValidateAccessToken(token)
+		require.NoError(t, err)
+		assert.Equal(t, access.UserID, got.UserID)
+	}
+
+	// Once retired, the tokens of the removed keys are rejected.
+	retired := service(t, map[string]string{"k2": "second"}, "k2")
+	_, err := retired.ValidateAccessToken(afterRotation)
+	assert.NoError(t, err)
+	for _, token := range []string{legacy, beforeRotation} {
+		_, err := retired.ValidateAccessToken(token)
+		assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+	}
+
+	// A kid naming a key with another secret does not verify.
+	forged := sign(t, service(t, map[string]string{"k2": "guessed"}, "k2"))
+	_, err = retired.ValidateAccessToken(forged)
+	assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+}
+
+func TestNewKeyring(t *testing.T) {
+	_, err := auth.NewKeyring(map[string]string{"k1": "first"}, "k2", nil)
+	assert.Error(t, err)
+
+	_, err = auth.NewKeyring(map[string]string{"k1": "first"}, "", nil)
+	assert.Error(t, err)
+
+	_, err = auth.NewKeyring(map[string]string{"k1": ""}, "k1", nil)
+	assert.Error(t, err)
+
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	for name, keys := range map[string]map[string][]byte{
+		"short RSA key":             {"r1": pemKey(t, small)},
+		"unsupported key type":      {"r1": pemKey(t, ecKey)},
+		"not PEM":                   {"r1": []byte("not a key")},
+		"private key without an id": {"": pemKey(t, edKey)},
+		"id taken by a secret":      {"k1": pemKey(t, edKey)},
+	} {
+		_, err := auth.NewKeyring(map[string]string{"k1": "first"}, "k1", keys)
+		assert.Error(t, err, name)
+	}
+}
+
+func pemKey(t *testing.T, key any) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func TestKeyring_PrivateKeys(t *testing.T) {
+	access := auth.AccessToken{UserID: uuid.New()}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	privateKeys := map[string][]byte{"r1": pemKey(t, rsaKey), "e1": pemKey(t, edKey)}
+
+	service := func(t *testing.T, current string) *auth.JWTService {
+		t.Helper()
+		keyring, err := auth.NewKeyring(map[string]string{"k1": "first"}, current, privateKeys)
+		require.NoError(t, err)
+		return auth.NewJWTServiceWithKeyring(keyring, time.Minute)
+	}
+	sign := func(t *testing.T, svc *auth.JWTService) string {
+		t.Helper()
+		token, _, err := svc.GenerateAccessToken(access, 0)
+		require.NoError(t, err)
+		return token
+	}
+
+	// Moving from the secret to the private keys keeps every token valid.
+	tokens := map[string]string{"k1": sign(t, service(t, "k1")), "r1": sign(t, service(t, "r1")), "e1": sign(t, service(t, "e1"))}
+	svc := service(t, "e1")
+	for kid, token := range tokens {
+		got, err := svc.ValidateAccessToken(token)
+		require.NoError(t, err, kid)
+		assert.Equal(t, access.UserID, got.UserID)
+	}
+
+	// Another service verifies them with the public keys alone.
+	public := svc.PublicKeys()
+	assert.Len(t, public, 2, "secrets are not published")
+	for kid, alg := range map[string]string{"r1": "RS256", "e1": "EdDSA"} {
+		parsed, err := jwt.Parse(tokens[kid], func(token *jwt.Token) (any, error) {
+			return public[token.Header["kid"].(string)], nil
+		}, jwt.WithValidMethods([]string{alg}))
+		require.NoError(t, err, kid)
+		assert.True(t, parsed.Valid)
+	}
+
+	// A token naming the RSA key but signed with HMAC over its public key
+	// is rejected, as is one signed with another RSA key.
+	der, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+	confused := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": access.UserID.String(), "exp": time.Now().Add(time.Minute).Unix()})
+	confused.Header["kid"] = "r1"
+	forged, err := confused.SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	require.NoError(t, err)
+	_, err = svc.ValidateAccessToken(forged)
+	assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	other, err := auth.NewKeyring(nil, "r1", map[string][]byte{"r1": pemKey(t, otherKey)})
+	require.NoError(t, err)
+	_, err = svc.ValidateAccessToken(sign(t, auth.NewJWTServiceWithKeyring(other, time.Minute)))
+	assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+}
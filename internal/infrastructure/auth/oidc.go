@@ -0,0 +1,313 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/identity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+const oidcDiscoveryPath = "/.well-known/openid-configuration"
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type idTokenClaims struct {
+	Email         string    `json:"email"`
+	EmailVerified claimBool `json:"email_verified"`
+	Name          string    `json:"name"`
+	Nonce         string    `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+// claimBool is a boolean claim some providers, Apple among them, send as
+// the string "true" or "false".
+type claimBool bool
+
+func (b *claimBool) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = claimBool(s == "true")
+		return nil
+	}
+	var v bool
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*b = claimBool(v)
+	return nil
+}
+
+// OIDCClient implements the authorization code flow against per-organization
+// OpenID Connect providers. Discovery documents and signing keys are cached per issuer.
+type OIDCClient struct {
+	httpClient      *http.Client
+	callbackBaseURL string
+	// keyRefreshInterval is the least time between two signing key fetches
+	// for one issuer, so tokens with made-up key IDs can't make every
+	// callback hit the provider.
+	keyRefreshInterval time.Duration
+
+	mu          sync.RWMutex
+	discovery   map[string]*oidcDiscovery
+	keys        map[string]map[string]*rsa.PublicKey
+	keysFetched map[string]time.Time
+}
+
+func NewOIDCClient(callbackBaseURL string, timeout, keyRefreshInterval time.Duration) *OIDCClient {
+	return &OIDCClient{
+		httpClient:         &http.Client{Timeout: timeout},
+		callbackBaseURL:    strings.TrimRight(callbackBaseURL, "/"),
+		keyRefreshInterval: keyRefreshInterval,
+		discovery:          make(map[string]*oidcDiscovery),
+		keys:               make(map[string]map[string]*rsa.PublicKey),
+		keysFetched:        make(map[string]time.Time),
+	}
+}
+
+func (c *OIDCClient) AuthCodeURL(ctx context.Context, org *entity.Organization, state, nonce string) (string, error) {
+	disc, err := c.discover(ctx, org.OIDCIssuer)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{
+		"response_type": {"code"},
+		"client_id":     {org.OIDCClientID},
+		"redirect_uri":  {c.redirectURL(org)},
+		"scope":         {"openid email profile"},
+		"state":         {state},
+		"nonce":         {nonce},
+	}
+
+	sep := "?"
+	if strings.Contains(disc.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return disc.AuthorizationEndpoint + sep + params.Encode(), nil
+}
+
+func (c *OIDCClient) Exchange(ctx context.Context, org *entity.Organization, code string) (*identity.IDTokenClaims, error) {
+	disc, err := c.discover(ctx, org.OIDCIssuer)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {c.redirectURL(org)},
+		"client_id":     {org.OIDCClientID},
+		"client_secret": {org.OIDCClientSecret},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, disc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("building token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("exchanging code: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint returned %d: %w", resp.StatusCode, domain.ErrSSOFailed)
+	}
+
+	var tokenResp struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return nil, fmt.Errorf("decoding token response: %w", err)
+	}
+	if tokenResp.IDToken == "" {
+		return nil, fmt.Errorf("token response without id_token: %w", domain.ErrSSOFailed)
+	}
+
+	return c.verifyIDToken(ctx, org, tokenResp.IDToken)
+}
+
+func (c *OIDCClient) verifyIDToken(ctx context.Context, org *entity.Organization, rawToken string) (*identity.IDTokenClaims, error) {
+	claims, err := c.parseIDToken(ctx, org.OIDCIssuer, rawToken, jwt.WithIssuer(org.OIDCIssuer), jwt.WithAudience(org.OIDCClientID))
+	if err != nil {
+		return nil, fmt.Errorf("verifying id token: %v: %w", err, domain.ErrSSOFailed)
+	}
+	return claims.identity(), nil
+}
+
+// parseIDToken verifies rawToken's signature with the keys of issuer and
+// checks its expiry and the extra opts.
+func (c *OIDCClient) parseIDToken(ctx context.Context, issuer, rawToken string, opts ...jwt.ParserOption) (*idTokenClaims, error) {
+	opts = append(opts,
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512"}),
+		jwt.WithExpirationRequired(),
+	)
+	token, err := jwt.ParseWithClaims(rawToken, &idTokenClaims{}, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return c.signingKey(ctx, issuer, kid)
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*idTokenClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+func (c *idTokenClaims) identity() *identity.IDTokenClaims {
+	return &identity.IDTokenClaims{
+		Subject:       c.Subject,
+		Email:         c.Email,
+		EmailVerified: bool(c.EmailVerified),
+		Name:          c.Name,
+		Nonce:         c.Nonce,
+	}
+}
+
+func (c *OIDCClient) redirectURL(org *entity.Organization) string {
+	return fmt.Sprintf("%s/api/v1/auth/sso/%s/callback", c.callbackBaseURL, org.Slug)
+}
+
+func (c *OIDCClient) discover(ctx context.Context, issuer string) (*oidcDiscovery, error) {
+	c.mu.RLock()
+	disc, ok := c.discovery[issuer]
+	c.mu.RUnlock()
+	if ok {
+		return disc, nil
+	}
+
+	disc = &oidcDiscovery{}
+	if err := c.getJSON(ctx, strings.TrimRight(issuer, "/")+oidcDiscoveryPath, disc); err != nil {
+		return nil, fmt.Errorf("fetching discovery document: %w", err)
+	}
+	if disc.Issuer != issuer {
+		return nil, fmt.Errorf("discovery issuer mismatch %q: %w", disc.Issuer, domain.ErrSSOFailed)
+	}
+
+	c.mu.Lock()
+	c.discovery[issuer] = disc
+	c.mu.Unlock()
+
+	return disc, nil
+}
+
+func (c *OIDCClient) signingKey(ctx context.Context, issuer, kid string) (*rsa.PublicKey, error) {
+	c.mu.Lock()
+	key, ok := c.keys[issuer][kid]
+	if ok {
+		c.mu.Unlock()
+		return key, nil
+	}
+
+	// Unknown kid: the provider may have rotated keys, so refresh the set,
+	// but at most once per keyRefreshInterval.
+	if fetched, ok := c.keysFetched[issuer]; ok && time.Since(fetched) < c.keyRefreshInterval {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	c.keysFetched[issuer] = time.Now()
+	c.mu.Unlock()
+
+	keys, err := c.fetchKeys(ctx, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok = keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+func (c *OIDCClient) fetchKeys(ctx context.Context, issuer string) (map[string]*rsa.PublicKey, error) {
+	disc, err := c.discover(ctx, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := c.getJSON(ctx, disc.JWKSURI, &jwks); err != nil {
+		return nil, fmt.Errorf("fetching jwks: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	c.mu.Lock()
+	c.keys[issuer] = keys
+	c.mu.Unlock()
+
+	return keys, nil
+}
+
+func (c *OIDCClient) getJSON(ctx context.Context, endpoint string, dest any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("requesting %s: %w", endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %d", endpoint, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(dest); err != nil {
+		return fmt.Errorf("decoding %s: %w", endpoint, err)
+	}
+	return nil
+}
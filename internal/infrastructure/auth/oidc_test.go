@@ -0,0 +1,213 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/auth"
+)
+
+// fakeIdP is an OpenID Connect provider whose token endpoint returns
+// whatever ID token the test set last.
+type fakeIdP struct {
+	t   *testing.T
+	srv *httptest.Server
+
+	mu      sync.Mutex
+	keys    map[string]*rsa.PrivateKey
+	idToken string
+
+	jwksFetches atomic.Int32
+}
+
+func newFakeIdP(t *testing.T) *fakeIdP {
+	t.Helper()
+	idp := &fakeIdP{t: t, keys: make(map[string]*rsa.PrivateKey)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		idp.writeJSON(w, map[string]string{
+			"issuer":                 idp.srv.URL,
+			"authorization_endpoint": idp.srv.URL + "/authorize",
+			"token_endpoint":         idp.srv.URL + "/token",
+			"jwks_uri":               idp.srv.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, _ *http.Request) {
+		idp.jwksFetches.Add(1)
+		idp.mu.Lock()
+		defer idp.mu.Unlock()
+		keys := make([]map[string]string, 0, len(idp.keys))
+		for kid, key := range idp.keys {
+			keys = append(keys, map[string]string{
+				"kty": "RSA",
+				"kid": kid,
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		idp.writeJSON(w, map[string]any{"keys": keys})
+	})
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, _ *http.Request) {
+		idp.mu.Lock()
+		defer idp.mu.Unlock()
+		idp.writeJSON(w, map[string]string{"id_token": idp.idToken})
+	})
+	idp.srv = httptest.NewServer(mux)
+	t.Cleanup(idp.srv.Close)
+	return idp
+}
+
+func (idp *fakeIdP) writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	require.NoError(idp.t, json.NewEncoder(w).Encode(v))
+}
+
+// rotate publishes a new signing key under kid and drops the previous ones.
+func (idp *fakeIdP) rotate(kid string) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(idp.t, err)
+	idp.mu.Lock()
+	idp.keys = map[string]*rsa.PrivateKey{kid: key}
+	idp.mu.Unlock()
+	return key
+}
+
+func (idp *fakeIdP) issue(token string) {
+	idp.mu.Lock()
+	idp.idToken = token
+	idp.mu.Unlock()
+}
+
+func (idp *fakeIdP) org() *entity.Organization {
+	return &entity.Organization{Slug: "acme", OIDCIssuer: idp.srv.URL, OIDCClientID: "field-notes"}
+}
+
+func (idp *fakeIdP) claims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":   idp.srv.URL,
+		"aud":   "field-notes",
+		"sub":   "user-1",
+		"email": "ana@acme.test",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"iat":   time.Now().Unix(),
+	}
+}
+
+func sign(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestOIDCClient_Exchange(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("accepts a valid id token", func(t *testing.T) {
+		idp := newFakeIdP(t)
+		key := idp.rotate("k1")
+		idp.issue(sign(t, key, "k1", idp.claims()))
+		client := auth.NewOIDCClient("http://localhost", 5*time.Second, time.Minute)
+
+		claims, err := client.Exchange(ctx, idp.org(), "code")
+
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", claims.Subject)
+		assert.Equal(t, "ana@acme.test", claims.Email)
+	})
+
+	t.Run("rejects another audience", func(t *testing.T) {
+		idp := newFakeIdP(t)
+		key := idp.rotate("k1")
+		claims := idp.claims()
+		claims["aud"] = "other-app"
+		idp.issue(sign(t, key, "k1", claims))
+		client := auth.NewOIDCClient("http://localhost", 5*time.Second, time.Minute)
+
+		_, err := client.Exchange(ctx, idp.org(), "code")
+
+		assert.ErrorIs(t, err, domain.ErrSSOFailed)
+	})
+
+	t.Run("rejects an expired token", func(t *testing.T) {
+		idp := newFakeIdP(t)
+		key := idp.rotate("k1")
+		claims := idp.claims()
+		claims["exp"] = time.Now().Add(-time.Minute).Unix()
+		idp.issue(sign(t, key, "k1", claims))
+		client := auth.NewOIDCClient("http://localhost", 5*time.Second, time.Minute)
+
+		_, err := client.Exchange(ctx, idp.org(), "code")
+
+		assert.ErrorIs(t, err, domain.ErrSSOFailed)
+	})
+
+	t.Run("rejects alg none", func(t *testing.T) {
+		idp := newFakeIdP(t)
+		idp.rotate("k1")
+		token := jwt.NewWithClaims(jwt.SigningMethodNone, idp.claims())
+		token.Header["kid"] = "k1"
+		unsigned, err := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+		require.NoError(t, err)
+		idp.issue(unsigned)
+		client := auth.NewOIDCClient("http://localhost", 5*time.Second, time.Minute)
+
+		_, err = client.Exchange(ctx, idp.org(), "code")
+
+		assert.ErrorIs(t, err, domain.ErrSSOFailed)
+	})
+
+	t.Run("picks up rotated keys", func(t *testing.T) {
+		idp := newFakeIdP(t)
+		key := idp.rotate("k1")
+		idp.issue(sign(t, key, "k1", idp.claims()))
+		client := auth.NewOIDCClient("http://localhost", 5*time.Second, 0)
+
+		_, err := client.Exchange(ctx, idp.org(), "code")
+		require.NoError(t, err)
+
+		key = idp.rotate("k2")
+		idp.issue(sign(t, key, "k2", idp.claims()))
+
+		_, err = client.Exchange(ctx, idp.org(), "code")
+
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, idp.jwksFetches.Load())
+	})
+
+	t.Run("refetches keys at most once per interval for unknown key IDs", func(t *testing.T) {
+		idp := newFakeIdP(t)
+		key := idp.rotate("k1")
+		idp.issue(sign(t, key, "k1", idp.claims()))
+		client := auth.NewOIDCClient("http://localhost", 5*time.Second, time.Minute)
+
+		_, err := client.Exchange(ctx, idp.org(), "code")
+		require.NoError(t, err)
+
+		for _, kid := range []string{"bogus-1", "bogus-2", "bogus-3"} {
+			idp.issue(sign(t, key, kid, idp.claims()))
+			_, err := client.Exchange(ctx, idp.org(), "code")
+			assert.ErrorIs(t, err, domain.ErrSSOFailed)
+		}
+
+		assert.EqualValues(t, 1, idp.jwksFetches.Load())
+	})
+}
@@ -0,0 +1,52 @@
+package auth_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/auth"
+)
+
+const testSecretKey = "IxhGeSNi1MOzqY+dTltMC9dLkiPjhwbGZM9zN+e1sNg="
+
+func TestSecretBox(t *testing.T) {
+	box, err := auth.NewSecretBox(testSecretKey)
+	require.NoError(t, err)
+
+	t.Run("round trips a sealed secret", func(t *testing.T) {
+		sealed, err := box.Seal("client-secret")
+		require.NoError(t, err)
+		assert.NotContains(t, sealed, "client-secret")
+
+		opened, err := box.Open(sealed)
+
+		require.NoError(t, err)
+		assert.Equal(t, "client-secret", opened)
+	})
+
+	t.Run("returns unsealed values unchanged", func(t *testing.T) {
+		opened, err := box.Open("legacy-secret")
+
+		require.NoError(t, err)
+		assert.Equal(t, "legacy-secret", opened)
+	})
+
+	t.Run("rejects secrets sealed with another key", func(t *testing.T) {
+		other, err := auth.NewSecretBox("AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=")
+		require.NoError(t, err)
+		sealed, err := other.Seal("client-secret")
+		require.NoError(t, err)
+
+		_, err = box.Open(sealed)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects short keys", func(t *testing.T) {
+		_, err := auth.NewSecretBox("c2hvcnQ=")
+
+		assert.Error(t, err)
+	})
+}
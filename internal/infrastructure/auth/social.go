@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/identity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+)
+
+const (
+	GoogleIssuer = "https://accounts.google.com"
+	AppleIssuer  = "https://appleid.apple.com"
+)
+
+// SocialVerifier verifies ID tokens that a social identity provider issued
+// to one of the apps' client IDs, with the signing keys the provider
+// publishes through OpenID Connect discovery.
+type SocialVerifier struct {
+	client *OIDCClient
+	issuer string
+	// issuers are the iss values the provider signs tokens with.
+	issuers   []string
+	clientIDs []string
+}
+
+func NewSocialVerifier(client *OIDCClient, issuer string, clientIDs []string) *SocialVerifier {
+	return &SocialVerifier{client: client, issuer: issuer, issuers: []string{issuer}, clientIDs: clientIDs}
+}
+
+func NewGoogleVerifier(client *OIDCClient, clientIDs []string) *SocialVerifier {
+	v := NewSocialVerifier(client, GoogleIssuer, clientIDs)
+	// Google still issues some tokens without the scheme.
+	v.issuers = append(v.issuers, "accounts.google.com")
+	return v
+}
+
+func NewAppleVerifier(client *OIDCClient, clientIDs []string) *SocialVerifier {
+	return NewSocialVerifier(client, AppleIssuer, clientIDs)
+}
+
+func (v *SocialVerifier) Verify(ctx context.Context, idToken string) (*identity.IDTokenClaims, error) {
+	if len(v.clientIDs) == 0 {
+		return nil, fmt.Errorf("no client ids configured: %w", domain.ErrSocialLoginFailed)
+	}
+
+	claims, err := v.client.parseIDToken(ctx, v.issuer, idToken, jwt.WithAudience(v.clientIDs...))
+	if err != nil {
+		return nil, fmt.Errorf("verifying id token: %v: %w", err, domain.ErrSocialLoginFailed)
+	}
+	if !slices.Contains(v.issuers, claims.Issuer) {
+		return nil, fmt.Errorf("unexpected issuer %q: %w", claims.Issuer, domain.ErrSocialLoginFailed)
+	}
+	return claims.identity(), nil
+}
@@ -0,0 +1,56 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/auth"
+)
+
+func TestSocialVerifier_Verify(t *testing.T) {
+	ctx := context.Background()
+	client := auth.NewOIDCClient("http://localhost", 5*time.Second, time.Minute)
+
+	t.Run("accepts a token issued to any of the client ids", func(t *testing.T) {
+		idp := newFakeIdP(t)
+		key := idp.rotate("k1")
+		claims := idp.claims()
+		claims["aud"] = "app.fieldnotes.ios"
+		// Apple sends email_verified as a string.
+		claims["email_verified"] = "true"
+		verifier := auth.NewSocialVerifier(client, idp.srv.URL, []string{"app.fieldnotes.android", "app.fieldnotes.ios"})
+
+		got, err := verifier.Verify(ctx, sign(t, key, "k1", claims))
+
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", got.Subject)
+		assert.True(t, got.EmailVerified)
+	})
+
+	t.Run("rejects a token issued to another app", func(t *testing.T) {
+		idp := newFakeIdP(t)
+		key := idp.rotate("k1")
+		verifier := auth.NewSocialVerifier(client, idp.srv.URL, []string{"app.fieldnotes.ios"})
+
+		_, err := verifier.Verify(ctx, sign(t, key, "k1", idp.claims()))
+
+		assert.ErrorIs(t, err, domain.ErrSocialLoginFailed)
+	})
+
+	t.Run("rejects a token from another issuer", func(t *testing.T) {
+		idp := newFakeIdP(t)
+		key := idp.rotate("k1")
+		claims := idp.claims()
+		claims["iss"] = "https://evil.example"
+		verifier := auth.NewSocialVerifier(client, idp.srv.URL, []string{"field-notes"})
+
+		_, err := verifier.Verify(ctx, sign(t, key, "k1", claims))
+
+		assert.ErrorIs(t, err, domain.ErrSocialLoginFailed)
+	})
+}
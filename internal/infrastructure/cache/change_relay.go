@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+const changeChannel = "fieldnotes:changes"
+
+// ChangeRelay carries change events between API instances over Redis
+// pub/sub. Events are not stored: an instance that is not listening when
+// one is published misses it, and its devices catch up on their next sync.
+type ChangeRelay struct {
+	client *redis.Client
+}
+
+func NewChangeRelay(client *redis.Client) *ChangeRelay {
+	return &ChangeRelay{client: client}
+}
+
+type changeMessage struct {
+	UserID   uuid.UUID `json:"user_id"`
+	DeviceID uuid.UUID `json:"device_id"`
+	Since    time.Time `json:"since"`
+}
+
+func (r *ChangeRelay) Publish(ctx context.Context, event entity.ChangeEvent) error {
+	payload, err := json.Marshal(changeMessage(event))
+	if err != nil {
+		return fmt.Errorf("encoding change event: %w", err)
+	}
+	if err := r.client.Publish(ctx, changeChannel, payload).Err(); err != nil {
+		return fmt.Errorf("publishing change event: %w", err)
+	}
+	return nil
+}
+
+func (r *ChangeRelay) Listen(ctx context.Context, deliver func(entity.ChangeEvent)) error {
+	sub := r.client.Subscribe(ctx, changeChannel)
+	defer sub.Close()
+
+	// Wait for the subscription so a Redis outage at startup is reported.
+	if _, err := sub.Receive(ctx); err != nil {
+		return fmt.Errorf("subscribing to change events: %w", err)
+	}
+
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			var m changeMessage
+			if err := json.Unmarshal([]byte(msg.Payload), &m); err != nil {
+				continue
+			}
+			deliver(entity.ChangeEvent(m))
+		}
+	}
+}
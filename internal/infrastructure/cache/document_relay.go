@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/crdt"
+)
+
+const documentChannel = "fieldnotes:documents"
+
+// DocumentRelay carries updates to collaborative notes between API
+// instances over Redis pub/sub. The updates are already stored; a missed
+// one is fetched by the app when it sees a gap in the seq numbers.
+type DocumentRelay struct {
+	client *redis.Client
+}
+
+func NewDocumentRelay(client *redis.Client) *DocumentRelay {
+	return &DocumentRelay{client: client}
+}
+
+type documentMessage struct {
+	NoteID    uuid.UUID  `json:"note_id"`
+	Seq       int64      `json:"seq"`
+	UserID    *uuid.UUID `json:"user_id,omitempty"`
+	DeviceID  *uuid.UUID `json:"device_id,omitempty"`
+	Ops       []crdt.Op  `json:"ops"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (r *DocumentRelay) Publish(ctx context.Context, update entity.DocumentUpdate) error {
+	payload, err := json.Marshal(documentMessage{
+		NoteID:    update.NoteID,
+		Seq:       update.Seq,
+		UserID:    update.UserID,
+		DeviceID:  update.DeviceID,
+		Ops:       update.Ops,
+		CreatedAt: update.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("encoding document update: %w", err)
+	}
+	if err := r.client.Publish(ctx, documentChannel, payload).Err(); err != nil {
+		return fmt.Errorf("publishing document update: %w", err)
+	}
+	return nil
+}
+
+func (r *DocumentRelay) Listen(ctx context.Context, deliver func(entity.DocumentUpdate)) error {
+	sub := r.client.Subscribe(ctx, documentChannel)
+	defer sub.Close()
+
+	// Wait for the subscription so a Redis outage at startup is reported.
+	if _, err := sub.Receive(ctx); err != nil {
+		return fmt.Errorf("subscribing to document updates: %w", err)
+	}
+
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			var m documentMessage
+			if err := json.Unmarshal([]byte(msg.Payload), &m); err != nil {
+				continue
+			}
+			deliver(entity.DocumentUpdate{
+				NoteID:    m.NoteID,
+				Seq:       m.Seq,
+				UserID:    m.UserID,
+				DeviceID:  m.DeviceID,
+				Ops:       m.Ops,
+				CreatedAt: m.CreatedAt,
+			})
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisLoginAttemptStore keeps failed login counts and locks in Redis, so
+// every instance locks the same logins.
+type RedisLoginAttemptStore struct {
+	client *redis.Client
+}
+
+func NewRedisLoginAttemptStore(client *redis.Client) *RedisLoginAttemptStore {
+	return &RedisLoginAttemptStore{client: client}
+}
+
+func loginFailuresKey(key string) string {
+	return "fieldnotes:login:failures:" + key
+}
+
+func loginLockKey(key string) string {
+	return "fieldnotes:login:lock:" + key
+}
+
+func (s *RedisLoginAttemptStore) Fail(ctx context.Context, key string, window time.Duration) (int, error) {
+	pipe := s.client.TxPipeline()
+	incr := pipe.Incr(ctx, loginFailuresKey(key))
+	pipe.PExpire(ctx, loginFailuresKey(key), window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return int(incr.Val()), nil
+}
+
+func (s *RedisLoginAttemptStore) Lock(ctx context.Context, key string, d time.Duration) error {
+	return s.client.Set(ctx, loginLockKey(key), 1, d).Err()
+}
+
+func (s *RedisLoginAttemptStore) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := s.client.PTTL(ctx, loginLockKey(key)).Result()
+	if err != nil {
+		return 0, err
+	}
+	// Negative when the key does not exist or has no expiry.
+	return max(ttl, 0), nil
+}
+
+func (s *RedisLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	return s.client.Del(ctx, loginFailuresKey(key), loginLockKey(key)).Err()
+}
+
+type loginAttempts struct {
+	failures    int
+	expiresAt   time.Time
+	lockedUntil time.Time
+}
+
+// MemoryLoginAttemptStore keeps failed login counts per process, for
+// deployments without Redis. Expired entries are dropped as new failures
+// come in.
+type MemoryLoginAttemptStore struct {
+	mu      sync.Mutex
+	entries map[string]*loginAttempts
+	swept   time.Time
+}
+
+func NewMemoryLoginAttemptStore() *MemoryLoginAttemptStore {
+	return &MemoryLoginAttemptStore{entries: make(map[string]*loginAttempts)}
+}
+
+func (s *MemoryLoginAttemptStore) Fail(_ context.Context, key string, window time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+	e := s.entries[key]
+	if e == nil {
+		e = &loginAttempts{}
+		s.entries[key] = e
+	}
+	if !now.Before(e.expiresAt) {
+		e.failures = 0
+	}
+	e.failures++
+	e.expiresAt = now.Add(window)
+	return e.failures, nil
+}
+
+func (s *MemoryLoginAttemptStore) Lock(_ context.Context, key string, d time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := s.entries[key]
+	if e == nil {
+		e = &loginAttempts{}
+		s.entries[key] = e
+	}
+	e.lockedUntil = time.Now().Add(d)
+	return nil
+}
+
+func (s *MemoryLoginAttemptStore) LockedFor(_ context.Context, key string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := s.entries[key]
+	if e == nil {
+		return 0, nil
+	}
+	return max(time.Until(e.lockedUntil), 0), nil
+}
+
+func (s *MemoryLoginAttemptStore) Reset(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// sweep drops the entries with neither failures nor a lock left, at most
+// once a minute.
+func (s *MemoryLoginAttemptStore) sweep(now time.Time) {
+	if now.Sub(s.swept) < time.Minute {
+		return
+	}
+	s.swept = now
+	for key, e := range s.entries {
+		if !now.Before(e.expiresAt) && !now.Before(e.lockedUntil) {
+			delete(s.entries, key)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+const presenceChannel = "fieldnotes:presence"
+
+// PresenceRelay carries presence events between API instances over Redis
+// pub/sub. Like change events they are not stored; a missed one is caught
+// up by the next presence snapshot.
+type PresenceRelay struct {
+	client *redis.Client
+}
+
+func NewPresenceRelay(client *redis.Client) *PresenceRelay {
+	return &PresenceRelay{client: client}
+}
+
+type presenceMessage struct {
+	NoteID    uuid.UUID         `json:"note_id"`
+	UserID    uuid.UUID         `json:"user_id"`
+	DeviceID  uuid.UUID         `json:"device_id"`
+	Selection *entity.Selection `json:"selection,omitempty"`
+	SeenAt    time.Time         `json:"seen_at"`
+	Left      bool              `json:"left,omitempty"`
+}
+
+func (r *PresenceRelay) Publish(ctx context.Context, event entity.PresenceEvent) error {
+	payload, err := json.Marshal(presenceMessage{
+		NoteID:    event.NoteID,
+		UserID:    event.UserID,
+		DeviceID:  event.DeviceID,
+		Selection: event.Selection,
+		SeenAt:    event.SeenAt,
+		Left:      event.Left,
+	})
+	if err != nil {
+		return fmt.Errorf("encoding presence event: %w", err)
+	}
+	if err := r.client.Publish(ctx, presenceChannel, payload).Err(); err != nil {
+		return fmt.Errorf("publishing presence event: %w", err)
+	}
+	return nil
+}
+
+func (r *PresenceRelay) Listen(ctx context.Context, deliver func(entity.PresenceEvent)) error {
+	sub := r.client.Subscribe(ctx, presenceChannel)
+	defer sub.Close()
+
+	// Wait for the subscription so a Redis outage at startup is reported.
+	if _, err := sub.Receive(ctx); err != nil {
+		return fmt.Errorf("subscribing to presence events: %w", err)
+	}
+
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			var m presenceMessage
+			if err := json.Unmarshal([]byte(msg.Payload), &m); err != nil {
+				continue
+			}
+			deliver(entity.PresenceEvent{
+				Presence: entity.Presence{
+					NoteID:    m.NoteID,
+					UserID:    m.UserID,
+					DeviceID:  m.DeviceID,
+					Selection: m.Selection,
+					SeenAt:    m.SeenAt,
+				},
+				Left: m.Left,
+			})
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+type presenceEntry struct {
+	UserID    uuid.UUID         `json:"user_id"`
+	DeviceID  uuid.UUID         `json:"device_id"`
+	Selection *entity.Selection `json:"selection,omitempty"`
+	SeenAt    time.Time         `json:"seen_at"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+func presenceField(userID, deviceID uuid.UUID) string {
+	return userID.String() + ":" + deviceID.String()
+}
+
+// RedisPresenceStore keeps each note's presence in a hash of its devices,
+// shared by every instance. The hash expires once no device refreshes it;
+// entries of devices that went away without closing the note are skipped
+// and removed when listed.
+type RedisPresenceStore struct {
+	client *redis.Client
+}
+
+func NewRedisPresenceStore(client *redis.Client) *RedisPresenceStore {
+	return &RedisPresenceStore{client: client}
+}
+
+func presenceKey(noteID uuid.UUID) string {
+	return "fieldnotes:presence:" + noteID.String()
+}
+
+func (s *RedisPresenceStore) Set(ctx context.Context, p entity.Presence, ttl time.Duration) error {
+	payload, err := json.Marshal(presenceEntry{
+		UserID:    p.UserID,
+		DeviceID:  p.DeviceID,
+		Selection: p.Selection,
+		SeenAt:    p.SeenAt,
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return fmt.Errorf("encoding presence: %w", err)
+	}
+
+	key := presenceKey(p.NoteID)
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, key, presenceField(p.UserID, p.DeviceID), payload)
+	pipe.Expire(ctx, key, ttl)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (s *RedisPresenceStore) Remove(ctx context.Context, noteID, userID, deviceID uuid.UUID) error {
+	return s.client.HDel(ctx, presenceKey(noteID), presenceField(userID, deviceID)).Err()
+}
+
+func (s *RedisPresenceStore) List(ctx context.Context, noteID uuid.UUID) ([]entity.Presence, error) {
+	key := presenceKey(noteID)
+	fields, err := s.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	presence := make([]entity.Presence, 0, len(fields))
+	var expired []string
+	for field, payload := range fields {
+		var e presenceEntry
+		if err := json.Unmarshal([]byte(payload), &e); err != nil || !now.Before(e.ExpiresAt) {
+			expired = append(expired, field)
+			continue
+		}
+		presence = append(presence, entity.Presence{
+			NoteID:    noteID,
+			UserID:    e.UserID,
+			DeviceID:  e.DeviceID,
+			Selection: e.Selection,
+			SeenAt:    e.SeenAt,
+		})
+	}
+	if len(expired) > 0 {
+		_ = s.client.HDel(ctx, key, expired...).Err()
+	}
+	sortPresence(presence)
+	return presence, nil
+}
+
+// MemoryPresenceStore keeps presence per process, for deployments without
+// Redis, where a single instance serves every connection.
+type MemoryPresenceStore struct {
+	mu    sync.Mutex
+	notes map[uuid.UUID]map[string]presenceEntry
+}
+
+func NewMemoryPresenceStore() *MemoryPresenceStore {
+	return &MemoryPresenceStore{notes: make(map[uuid.UUID]map[string]presenceEntry)}
+}
+
+func (s *MemoryPresenceStore) Set(_ context.Context, p entity.Presence, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.notes[p.NoteID] == nil {
+		s.notes[p.NoteID] = make(map[string]presenceEntry)
+	}
+	s.notes[p.NoteID][presenceField(p.UserID, p.DeviceID)] = presenceEntry{
+		UserID:    p.UserID,
+		DeviceID:  p.DeviceID,
+		Selection: p.Selection,
+		SeenAt:    p.SeenAt,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	return nil
+}
+
+func (s *MemoryPresenceStore) Remove(_ context.Context, noteID, userID, deviceID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.notes[noteID], presenceField(userID, deviceID))
+	if len(s.notes[noteID]) == 0 {
+		delete(s.notes, noteID)
+	}
+	return nil
+}
+
+func (s *MemoryPresenceStore) List(_ context.Context, noteID uuid.UUID) ([]entity.Presence, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	presence := make([]entity.Presence, 0, len(s.notes[noteID]))
+	for field, e := range s.notes[noteID] {
+		if !now.Before(e.ExpiresAt) {
+			delete(s.notes[noteID], field)
+			continue
+		}
+		presence = append(presence, entity.Presence{
+			NoteID:    noteID,
+			UserID:    e.UserID,
+			DeviceID:  e.DeviceID,
+			Selection: e.Selection,
+			SeenAt:    e.SeenAt,
+		})
+	}
+	if len(s.notes[noteID]) == 0 {
+		delete(s.notes, noteID)
+	}
+	sortPresence(presence)
+	return presence, nil
+}
+
+// sortPresence orders the devices by when they were last seen, then by
+// user and device, so listings are stable.
+func sortPresence(presence []entity.Presence) {
+	slices.SortFunc(presence, func(a, b entity.Presence) int {
+		return cmp.Or(
+			a.SeenAt.Compare(b.SeenAt),
+			strings.Compare(a.UserID.String(), b.UserID.String()),
+			strings.Compare(a.DeviceID.String(), b.DeviceID.String()),
+		)
+	})
+}
@@ -1,20 +1,60 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/kelseyhightower/envconfig"
 )
 
 type Config struct {
-	Server    ServerConfig
-	Database  DatabaseConfig
-	Redis     RedisConfig
-	JWT       JWTConfig
-	S3        S3Config
-	Log       LogConfig
-	RateLimit RateLimitConfig
+	// File is the overrides file the config was loaded with, if any.
+	File string `ignored:"true"`
+
+	Server       ServerConfig
+	Database     DatabaseConfig
+	Redis        RedisConfig
+	JWT          JWTConfig
+	Device       DeviceConfig
+	S3           S3Config
+	Log          LogConfig
+	RateLimit    RateLimitConfig
+	UploadLimit  UploadLimitConfig
+	Latency      LatencyConfig
+	Idempotency  IdempotencyConfig
+	SSO          SSOConfig
+	OAuth        OAuthConfig
+	Usage        UsageConfig
+	Telemetry    TelemetryConfig
+	KPI          KPIConfig
+	Realtime     RealtimeConfig
+	Demo         DemoConfig
+	Notification NotificationConfig
+	Push         PushConfig
+	PII          PIIConfig
+	Sensitive    SensitiveConfig
+	Label        LabelConfig
+	Stats        StatsConfig
+	Summary      SummaryConfig
+	GeoIP        GeoIPConfig
+	Anomaly      AnomalyConfig
+	Mail         MailConfig
+	Reset        PasswordResetConfig
+	Jobs         JobsConfig
+	Account      AccountConfig
+	Cleanup      CleanupConfig
+	Note         NoteConfig
+	Sync         SyncConfig
+	Audit        AuditConfig
+	Collab       CollaborationConfig
+	Security     SecurityConfig
+	Semantic     SemanticConfig
 }
 
 type ServerConfig struct {
@@ -22,19 +62,60 @@ type ServerConfig struct {
 	ReadTimeout     time.Duration `envconfig:"SERVER_READ_TIMEOUT" default:"10s"`
 	WriteTimeout    time.Duration `envconfig:"SERVER_WRITE_TIMEOUT" default:"30s"`
 	ShutdownTimeout time.Duration `envconfig:"SERVER_SHUTDOWN_TIMEOUT" default:"10s"`
-	Environment     string        `envconfig:"ENVIRONMENT" default:"development"`
+	// HandlerTimeout bounds most handlers; LongHandlerTimeout bounds sync
+	// and uploads. Zero disables the timeout.
+	HandlerTimeout     time.Duration `envconfig:"SERVER_HANDLER_TIMEOUT" default:"5s"`
+	LongHandlerTimeout time.Duration `envconfig:"SERVER_LONG_HANDLER_TIMEOUT" default:"30s"`
+	// ReadinessTimeout bounds the dependency checks of /health/ready, below
+	// the probe timeout of the orchestrator.
+	ReadinessTimeout time.Duration `envconfig:"SERVER_READINESS_TIMEOUT" default:"2s"`
+	Environment      Profile       `envconfig:"ENVIRONMENT" default:"development"`
+	// MaxBodyBytes bounds request bodies and MaxSyncBodyBytes those of sync;
+	// uploads and imports keep their own limits. Zero disables the limit.
+	MaxBodyBytes     int64 `envconfig:"SERVER_MAX_BODY_BYTES" default:"1048576"`
+	MaxSyncBodyBytes int64 `envconfig:"SERVER_MAX_SYNC_BODY_BYTES" default:"10485760"`
+	// ReadHeaderTimeout bounds reading the request headers, so clients that
+	// send them slowly cannot hold connections open.
+	ReadHeaderTimeout time.Duration `envconfig:"SERVER_READ_HEADER_TIMEOUT" default:"5s"`
+	// CORSAllowedOrigins are the browser origins allowed to call the API;
+	// "*" allows any and none leaves cross-origin requests blocked.
+	CORSAllowedOrigins []string `envconfig:"CORS_ALLOWED_ORIGINS" default:"*"`
+	// SwaggerExposure is who is served the API documentation at /swagger:
+	// "public" anyone, "operators" only the operators of JobsConfig, "off"
+	// no one.
+	SwaggerExposure string `envconfig:"SWAGGER_EXPOSURE" default:"public"`
 }
 
 type DatabaseConfig struct {
 	Host            string        `envconfig:"DB_HOST" default:"localhost"`
 	Port            int           `envconfig:"DB_PORT" default:"5432"`
 	User            string        `envconfig:"DB_USER" required:"true"`
-	Password        string        `envconfig:"DB_PASSWORD" required:"true"`
+	Password        string        `envconfig:"DB_PASSWORD" required:"true" secret:"true"`
 	Name            string        `envconfig:"DB_NAME" required:"true"`
 	SSLMode         string        `envconfig:"DB_SSL_MODE" default:"disable"`
 	MaxOpenConns    int           `envconfig:"DB_MAX_OPEN_CONNS" default:"25"`
 	MaxIdleConns    int           `envconfig:"DB_MAX_IDLE_CONNS" default:"5"`
 	ConnMaxLifetime time.Duration `envconfig:"DB_CONN_MAX_LIFETIME" default:"5m"`
+	// PrePing checks every connection with a round trip before handing it
+	// out, instead of only those idle for more than a second. It costs a
+	// round trip per query but never serves a connection dropped by a
+	// failover or a proxy.
+	PrePing bool `envconfig:"DB_PRE_PING" default:"false"`
+	// Warmup opens MaxIdleConns connections and prepares the hot queries on
+	// them before the server starts accepting requests.
+	Warmup bool `envconfig:"DB_WARMUP" default:"true"`
+	// MigrationsPath overrides the migrations embedded in the binary, for
+	// iterating on migrations during development.
+	MigrationsPath string `envconfig:"DB_MIGRATIONS_PATH"`
+	// ReplicaHost is a streaming replica of the primary, reached with the same
+	// port and credentials. Note listings are read from it when set.
+	ReplicaHost string `envconfig:"DB_REPLICA_HOST"`
+}
+
+// Replica returns the config for connecting to ReplicaHost.
+func (c DatabaseConfig) Replica() DatabaseConfig {
+	c.Host = c.ReplicaHost
+	return c
 }
 
 func (c DatabaseConfig) DSN() string {
@@ -45,9 +126,49 @@ func (c DatabaseConfig) DSN() string {
 }
 
 type JWTConfig struct {
-	SecretKey       string        `envconfig:"JWT_SECRET_KEY" required:"true"`
+	// SecretKey signs tokens while SigningKeyID is empty, and verifies the
+	// tokens without a kid. Once no token it signed is still in use, it can
+	// be dropped in favour of Keys.
+	SecretKey       string        `envconfig:"JWT_SECRET_KEY" secret:"true"`
 	AccessTokenTTL  time.Duration `envconfig:"JWT_ACCESS_TOKEN_TTL" default:"15m"`
 	RefreshTokenTTL time.Duration `envconfig:"JWT_REFRESH_TOKEN_TTL" default:"720h"`
+	// TokenVersionCacheTTL is how long each instance trusts a user's token
+	// version, and so how long a revoked access token may still be accepted.
+	TokenVersionCacheTTL time.Duration `envconfig:"JWT_TOKEN_VERSION_CACHE_TTL" default:"5s"`
+	// Keys are secrets by key id, verifying the tokens that name them in
+	// their kid. SigningKeyID picks the one new tokens are signed with. To
+	// rotate, add a key everywhere, then sign with it, then remove the old
+	// key once the tokens it signed have expired.
+	Keys         map[string]string `envconfig:"JWT_KEYS" secret:"true"`
+	SigningKeyID string            `envconfig:"JWT_SIGNING_KEY_ID"`
+	// PrivateKeyFiles are paths of PEM-encoded RSA or Ed25519 private keys
+	// by key id. They rotate the same way as Keys, and their public keys
+	// are published at /.well-known/jwks.json so other services can verify
+	// tokens without a shared secret.
+	PrivateKeyFiles map[string]string `envconfig:"JWT_PRIVATE_KEY_FILES"`
+}
+
+// Keyring returns the secrets by key id, with SecretKey under the empty id.
+func (c JWTConfig) Keyring() map[string]string {
+	keys := make(map[string]string, len(c.Keys)+1)
+	maps.Copy(keys, c.Keys)
+	if c.SecretKey != "" {
+		keys[""] = c.SecretKey
+	}
+	return keys
+}
+
+type DeviceConfig struct {
+	// Platforms are the device platforms allowed to log in: any of ios,
+	// android, web and cli.
+	Platforms []string `envconfig:"DEVICE_PLATFORMS" default:"ios,android,web"`
+	// AccessTTL, RefreshTTL and MaxSessions set the session policy per
+	// platform, as platform:value lists such as web:12h,ios:2160h. Platforms
+	// not listed use the JWT lifetimes and have no session limit; a
+	// MaxSessions of 0 is also unlimited.
+	AccessTTL   map[string]time.Duration `envconfig:"DEVICE_ACCESS_TTL"`
+	RefreshTTL  map[string]time.Duration `envconfig:"DEVICE_REFRESH_TTL"`
+	MaxSessions map[string]int           `envconfig:"DEVICE_MAX_SESSIONS"`
 }
 
 type S3Config struct {
@@ -55,9 +176,18 @@ type S3Config struct {
 	Region          string `envconfig:"S3_REGION" default:"us-east-1"`
 	Bucket          string `envconfig:"S3_BUCKET" required:"true"`
 	AccessKeyID     string `envconfig:"S3_ACCESS_KEY_ID" required:"true"`
-	SecretAccessKey string `envconfig:"S3_SECRET_ACCESS_KEY" required:"true"`
+	SecretAccessKey string `envconfig:"S3_SECRET_ACCESS_KEY" required:"true" secret:"true"`
 	UsePathStyle    bool   `envconfig:"S3_USE_PATH_STYLE" default:"false"`
 	PublicURL       string `envconfig:"S3_PUBLIC_URL"`
+	// SSE is the server-side encryption requested on every upload: "AES256"
+	// (SSE-S3), "aws:kms" (SSE-KMS) or empty to leave it to the bucket default.
+	SSE string `envconfig:"S3_SSE"`
+	// KMSKeyID is the KMS key ARN used with SSE "aws:kms". Empty uses the
+	// account's AWS managed key.
+	KMSKeyID string `envconfig:"S3_KMS_KEY_ID"`
+	// VerifyBucket checks at startup that the bucket encrypts by default and
+	// has ACLs disabled, and refuses to start otherwise.
+	VerifyBucket bool `envconfig:"S3_VERIFY_BUCKET" default:"false"`
 }
 
 type LogConfig struct {
@@ -66,27 +196,392 @@ type LogConfig struct {
 }
 
 type RedisConfig struct {
-	Host     string `envconfig:"REDIS_HOST" default:"localhost"`
+	Host     string `envconfig:"REDIS_HOST"`
 	Port     int    `envconfig:"REDIS_PORT" default:"6379"`
-	Password string `envconfig:"REDIS_PASSWORD" default:""`
+	Password string `envconfig:"REDIS_PASSWORD" default:"" secret:"true"`
 	DB       int    `envconfig:"REDIS_DB" default:"0"`
 }
 
+// Enabled reports whether a Redis host is configured. Without one the cache
+// and rate limiter run in-process.
+func (c RedisConfig) Enabled() bool {
+	return c.Host != ""
+}
+
 func (c RedisConfig) Addr() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
 
 type RateLimitConfig struct {
-	Enabled         bool          `envconfig:"RATE_LIMIT_ENABLED" default:"true"`
-	RequestsPerMin  int           `envconfig:"RATE_LIMIT_REQUESTS_PER_MIN" default:"100"`
-	BurstSize       int           `envconfig:"RATE_LIMIT_BURST_SIZE" default:"10"`
-	CleanupInterval time.Duration `envconfig:"RATE_LIMIT_CLEANUP_INTERVAL" default:"1m"`
+	Enabled         bool              `envconfig:"RATE_LIMIT_ENABLED" default:"true"`
+	RequestsPerMin  int               `envconfig:"RATE_LIMIT_REQUESTS_PER_MIN" default:"100"`
+	BurstSize       int               `envconfig:"RATE_LIMIT_BURST_SIZE" default:"10"`
+	CleanupInterval time.Duration     `envconfig:"RATE_LIMIT_CLEANUP_INTERVAL" default:"1m"`
+	ExemptCIDRs     []string          `envconfig:"RATE_LIMIT_EXEMPT_CIDRS"`
+	ExemptAPIKeys   map[string]string `envconfig:"RATE_LIMIT_EXEMPT_API_KEYS" secret:"true"`
+	// Fallback is what happens while Redis is unreachable: memory limits
+	// each instance with a token bucket, open lets every request through
+	// and closed rejects them.
+	Fallback string `envconfig:"RATE_LIMIT_FALLBACK" default:"memory"`
+}
+
+// UploadLimitConfig caps photo uploads per user, on top of RateLimitConfig.
+type UploadLimitConfig struct {
+	Enabled       bool          `envconfig:"UPLOAD_LIMIT_ENABLED" default:"true"`
+	MaxConcurrent int           `envconfig:"UPLOAD_MAX_CONCURRENT" default:"3"`
+	PerMinute     int           `envconfig:"UPLOAD_MAX_PER_MINUTE" default:"30"`
+	SlotTTL       time.Duration `envconfig:"UPLOAD_SLOT_TTL" default:"5m"`
+	RetryAfter    time.Duration `envconfig:"UPLOAD_RETRY_AFTER" default:"5s"`
+}
+
+// LatencyConfig sets the latency budgets past which slow requests are logged
+// with the time spent in each storage and image operation.
+type LatencyConfig struct {
+	// UploadBudget applies to photo and audio uploads; zero disables it.
+	UploadBudget time.Duration `envconfig:"UPLOAD_LATENCY_BUDGET" default:"10s"`
+}
+
+// IdempotencyConfig controls the replay of writes retried with the same
+// Idempotency-Key.
+type IdempotencyConfig struct {
+	Enabled bool `envconfig:"IDEMPOTENCY_ENABLED" default:"true"`
+	// TTL is how long a response is kept for replay.
+	TTL time.Duration `envconfig:"IDEMPOTENCY_TTL" default:"24h"`
+	// LockTTL bounds how long a request holds its key while it runs, so a
+	// crashed instance cannot block retries for the whole TTL.
+	LockTTL time.Duration `envconfig:"IDEMPOTENCY_LOCK_TTL" default:"1m"`
+	// MaxResponseBytes is the largest response kept; larger ones are not
+	// replayed.
+	MaxResponseBytes int `envconfig:"IDEMPOTENCY_MAX_RESPONSE_BYTES" default:"1048576"`
+}
+
+type SSOConfig struct {
+	CallbackBaseURL string        `envconfig:"SSO_CALLBACK_BASE_URL" default:"http://localhost:8080"`
+	HTTPTimeout     time.Duration `envconfig:"SSO_HTTP_TIMEOUT" default:"10s"`
+	// KeyRefreshInterval limits how often the signing keys of one provider are
+	// refetched when an ID token names a key that is not cached.
+	KeyRefreshInterval time.Duration `envconfig:"SSO_KEY_REFRESH_INTERVAL" default:"1m"`
+	// SecretKey is the base64-encoded 32-byte key that encrypts organization
+	// client secrets in the database.
+	SecretKey string `envconfig:"SSO_SECRET_KEY" required:"true" secret:"true"`
+}
+
+// OAuthConfig lists the client IDs of the apps allowed to sign in with each
+// social identity provider. A provider without client IDs is disabled.
+type OAuthConfig struct {
+	GoogleClientIDs []string `envconfig:"OAUTH_GOOGLE_CLIENT_IDS"`
+	// AppleClientIDs are the apps' bundle IDs, and the Services ID for web
+	// sign-in.
+	AppleClientIDs []string `envconfig:"OAUTH_APPLE_CLIENT_IDS"`
+}
+
+type UsageConfig struct {
+	FlushInterval time.Duration `envconfig:"USAGE_FLUSH_INTERVAL" default:"30s"`
+}
+
+// TelemetryConfig controls the per-version request counts behind the
+// operators' adoption report.
+type TelemetryConfig struct {
+	Enabled       bool          `envconfig:"TELEMETRY_ENABLED" default:"true"`
+	FlushInterval time.Duration `envconfig:"TELEMETRY_FLUSH_INTERVAL" default:"1m"`
+	// Retention is how long the daily counts are kept; zero keeps them.
+	Retention time.Duration `envconfig:"TELEMETRY_RETENTION" default:"2160h"`
+}
+
+// KPIConfig controls the business KPIs behind /admin/kpis. Interval is how
+// often the event counts are flushed and the day's KPIs recomputed.
+type KPIConfig struct {
+	Enabled  bool          `envconfig:"KPI_ENABLED" default:"true"`
+	Interval time.Duration `envconfig:"KPI_INTERVAL" default:"5m"`
+}
+
+// RealtimeConfig controls the /ws change feed. Heartbeat is how often idle
+// connections are pinged.
+type RealtimeConfig struct {
+	Enabled   bool          `envconfig:"REALTIME_ENABLED" default:"true"`
+	Heartbeat time.Duration `envconfig:"REALTIME_HEARTBEAT" default:"30s"`
+	// Presence shares who has a note open. A device's presence is refreshed
+	// with every heartbeat and dropped PresenceTTL after the last one, so
+	// it must be longer than Heartbeat.
+	Presence    bool          `envconfig:"REALTIME_PRESENCE_ENABLED" default:"true"`
+	PresenceTTL time.Duration `envconfig:"REALTIME_PRESENCE_TTL" default:"90s"`
+}
+
+type StatsConfig struct {
+	// ReconcileInterval is how often user stats are recounted to fix drift.
+	ReconcileInterval time.Duration `envconfig:"STATS_RECONCILE_INTERVAL" default:"24h"`
+}
+
+type SummaryConfig struct {
+	// RebuildInterval is how often the note summaries are recomputed to fix
+	// drift.
+	RebuildInterval time.Duration `envconfig:"NOTE_SUMMARIES_REBUILD_INTERVAL" default:"24h"`
+}
+
+// AccountConfig schedules the purge of deleted accounts. Delay gives
+// requests already in flight when the account was locked time to finish, so
+// none of them stores a file after the purge has listed them.
+type AccountConfig struct {
+	PurgeInterval time.Duration `envconfig:"ACCOUNT_PURGE_INTERVAL" default:"10m"`
+	PurgeDelay    time.Duration `envconfig:"ACCOUNT_PURGE_DELAY" default:"1h"`
+	PurgeBatch    int           `envconfig:"ACCOUNT_PURGE_BATCH" default:"20"`
+}
+
+// CleanupConfig schedules the token-cleanup, note-purge and storage-gc jobs.
+// Notes deleted more than NoteRetention ago are purged; a retention shorter
+// than the 30-day restore window is raised to it.
+type CleanupConfig struct {
+	Interval      time.Duration `envconfig:"CLEANUP_INTERVAL" default:"1h"`
+	NoteRetention time.Duration `envconfig:"CLEANUP_NOTE_RETENTION" default:"2160h"`
+	Batch         int           `envconfig:"CLEANUP_BATCH" default:"500"`
+}
+
+// NoteConfig sets when note content is moved out of the database. Content
+// longer than ContentOffloadThreshold bytes is stored in the S3 bucket with
+// only an excerpt in the row; 0 keeps all new content in the database.
+type NoteConfig struct {
+	ContentOffloadThreshold int `envconfig:"NOTE_CONTENT_OFFLOAD_THRESHOLD" default:"32768"`
+}
+
+// SyncConfig sets how long conflicts held by the manual conflict strategy
+// wait for the user to replay a side before they are pruned.
+type SyncConfig struct {
+	ConflictRetention     time.Duration `envconfig:"SYNC_CONFLICT_RETENTION" default:"720h"`
+	ConflictPruneInterval time.Duration `envconfig:"SYNC_CONFLICT_PRUNE_INTERVAL" default:"1h"`
+}
+
+type JobsConfig struct {
+	// DashboardPassword enables the /admin/jobs dashboard behind basic auth
+	// as user "ops". Empty leaves the dashboard off.
+	DashboardPassword string `envconfig:"JOBS_DASHBOARD_PASSWORD" secret:"true"`
+	HistorySize       int    `envconfig:"JOBS_HISTORY_SIZE" default:"100"`
+	// AdminUserIDs are the users whose access tokens open the operator
+	// pages too, besides the "ops" password.
+	AdminUserIDs []uuid.UUID `envconfig:"OPS_ADMIN_USER_IDS"`
+}
+
+type DemoConfig struct {
+	Enabled       bool          `envconfig:"DEMO_ENABLED" default:"false"`
+	Email         string        `envconfig:"DEMO_EMAIL" default:"demo@fieldnotes.app"`
+	Password      string        `envconfig:"DEMO_PASSWORD" default:"demo1234"`
+	ResetInterval time.Duration `envconfig:"DEMO_RESET_INTERVAL" default:"24h"`
+}
+
+type NotificationConfig struct {
+	// WebhookURL receives notifications as JSON; notifications are off when empty.
+	WebhookURL    string        `envconfig:"NOTIFICATION_WEBHOOK_URL"`
+	WebhookSecret string        `envconfig:"NOTIFICATION_WEBHOOK_SECRET" secret:"true"`
+	AppURL        string        `envconfig:"NOTIFICATION_APP_URL"`
+	Timeout       time.Duration `envconfig:"NOTIFICATION_TIMEOUT" default:"5s"`
+}
+
+// PushConfig sets up the silent pushes that wake a user's other devices after
+// a sync. APNs is on when APNsKeyFile is set, FCM when FCMCredentialsFile is.
+type PushConfig struct {
+	// APNsKeyFile is the .p8 token-based auth key from the Apple developer account.
+	APNsKeyFile string `envconfig:"PUSH_APNS_KEY_FILE"`
+	APNsKeyID   string `envconfig:"PUSH_APNS_KEY_ID"`
+	APNsTeamID  string `envconfig:"PUSH_APNS_TEAM_ID"`
+	// APNsTopic is the iOS app's bundle ID.
+	APNsTopic   string `envconfig:"PUSH_APNS_TOPIC"`
+	APNsSandbox bool   `envconfig:"PUSH_APNS_SANDBOX" default:"false"`
+	// FCMCredentialsFile is a Firebase service account JSON key.
+	FCMCredentialsFile string        `envconfig:"PUSH_FCM_CREDENTIALS_FILE"`
+	Timeout            time.Duration `envconfig:"PUSH_TIMEOUT" default:"5s"`
 }
 
+type PIIConfig struct {
+	// Detectors run when linting notes: email, phone, coordinates and precise_location.
+	Detectors       []string `envconfig:"PII_DETECTORS" default:"email,phone,coordinates"`
+	PreciseAccuracy float64  `envconfig:"PII_PRECISE_ACCURACY" default:"100"`
+}
+
+// SensitiveConfig sets the grid, in degrees, that sensitive note locations
+// are snapped to for anyone but the owner.
+type SensitiveConfig struct {
+	LowGrid  float64 `envconfig:"SENSITIVE_LOW_GRID" default:"0.01"`
+	HighGrid float64 `envconfig:"SENSITIVE_HIGH_GRID" default:"0.1"`
+}
+
+// LabelConfig sets the deep link that note QR codes encode, with an {id}
+// placeholder for the note ID.
+type LabelConfig struct {
+	LinkURL string `envconfig:"LABEL_LINK_URL" default:"fieldnotes://notes/{id}"`
+}
+
+type MailConfig struct {
+	// SMTPHost relays outgoing email; password reset is off when empty.
+	SMTPHost     string `envconfig:"MAIL_SMTP_HOST"`
+	SMTPPort     int    `envconfig:"MAIL_SMTP_PORT" default:"587"`
+	SMTPUsername string `envconfig:"MAIL_SMTP_USERNAME"`
+	SMTPPassword string `envconfig:"MAIL_SMTP_PASSWORD" secret:"true"`
+	From         string `envconfig:"MAIL_FROM" default:"no-reply@localhost"`
+}
+
+type PasswordResetConfig struct {
+	TokenTTL time.Duration `envconfig:"PASSWORD_RESET_TOKEN_TTL" default:"1h"`
+	// URL is the app page that completes a reset; the emailed link adds the
+	// token as a query parameter.
+	URL string `envconfig:"PASSWORD_RESET_URL" default:"http://localhost:3000/reset-password"`
+}
+
+type GeoIPConfig struct {
+	// APIURL is a JSON lookup endpoint with an {ip} placeholder; lookups are off when empty.
+	APIURL  string        `envconfig:"GEOIP_API_URL"`
+	Timeout time.Duration `envconfig:"GEOIP_TIMEOUT" default:"2s"`
+}
+
+// AnomalyConfig tunes the background analyzer that flags impossible travel,
+// token refresh storms and unusual sync volumes. A zero threshold disables
+// its check.
+type AnomalyConfig struct {
+	Interval time.Duration `envconfig:"ANOMALY_INTERVAL" default:"5m"`
+	// Lookback is how far back each run reads auth events; keep it above Interval.
+	Lookback          time.Duration `envconfig:"ANOMALY_LOOKBACK" default:"1h"`
+	MaxTravelSpeed    float64       `envconfig:"ANOMALY_MAX_TRAVEL_SPEED" default:"1000"`
+	MinTravelDistance float64       `envconfig:"ANOMALY_MIN_TRAVEL_DISTANCE" default:"200"`
+	RefreshLimit      int           `envconfig:"ANOMALY_REFRESH_LIMIT" default:"30"`
+	RefreshWindow     time.Duration `envconfig:"ANOMALY_REFRESH_WINDOW" default:"10m"`
+	DailySyncBytes    int64         `envconfig:"ANOMALY_DAILY_SYNC_BYTES" default:"1073741824"`
+	// NotifyUsers sends each new alert to its user through the notification webhook.
+	NotifyUsers    bool          `envconfig:"ANOMALY_NOTIFY_USERS" default:"false"`
+	EventRetention time.Duration `envconfig:"ANOMALY_EVENT_RETENTION" default:"720h"`
+}
+
+// AuditConfig sets how long the account activity users can review is kept.
+type AuditConfig struct {
+	Retention time.Duration `envconfig:"AUDIT_RETENTION" default:"8760h"`
+}
+
+// SecurityConfig locks password logins after repeated failures; see
+// auth.LockoutConfig. Locks are kept in Redis when it is configured.
+type SecurityConfig struct {
+	LockoutEnabled     bool          `envconfig:"LOGIN_LOCKOUT_ENABLED" default:"true"`
+	LockoutThreshold   int           `envconfig:"LOGIN_LOCKOUT_THRESHOLD" default:"5"`
+	LockoutIPThreshold int           `envconfig:"LOGIN_LOCKOUT_IP_THRESHOLD" default:"50"`
+	LockoutWindow      time.Duration `envconfig:"LOGIN_LOCKOUT_WINDOW" default:"1h"`
+	LockoutDuration    time.Duration `envconfig:"LOGIN_LOCKOUT_DURATION" default:"1m"`
+	LockoutMaxDuration time.Duration `envconfig:"LOGIN_LOCKOUT_MAX_DURATION" default:"1h"`
+}
+
+type CollaborationConfig struct {
+	Enabled bool `envconfig:"COLLAB_ENABLED" default:"true"`
+	// Documents with at least CompactThreshold updates in their log have it
+	// folded into their snapshot every CompactInterval, CompactBatch at a
+	// time.
+	CompactInterval  time.Duration `envconfig:"COLLAB_COMPACT_INTERVAL" default:"10m"`
+	CompactThreshold int           `envconfig:"COLLAB_COMPACT_THRESHOLD" default:"200"`
+	CompactBatch     int           `envconfig:"COLLAB_COMPACT_BATCH" default:"100"`
+}
+
+// SemanticConfig sets up semantic search. It is off unless APIURL names an
+// OpenAI-compatible embeddings endpoint, and then only for users who turn it
+// on; their notes are embedded Batch at a time every Interval.
+type SemanticConfig struct {
+	APIURL   string        `envconfig:"EMBEDDINGS_API_URL"`
+	APIKey   string        `envconfig:"EMBEDDINGS_API_KEY" secret:"true"`
+	Model    string        `envconfig:"EMBEDDINGS_MODEL" default:"text-embedding-3-small"`
+	Timeout  time.Duration `envconfig:"EMBEDDINGS_TIMEOUT" default:"10s"`
+	Interval time.Duration `envconfig:"EMBEDDINGS_INTERVAL" default:"1m"`
+	Batch    int           `envconfig:"EMBEDDINGS_BATCH" default:"50"`
+}
+
+// Load reads the config in layers: each setting comes from the overrides
+// file named by CONFIG_FILE, else from its environment variable, else from
+// the preset of the profile in ENVIRONMENT, else from its default.
 func Load() (*Config, error) {
+	path := os.Getenv(FileEnv)
+	overrides, err := readOverrides(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+
+	profile := ProfileDevelopment
+	if env, ok := overrides["ENVIRONMENT"]; ok {
+		profile = Profile(env)
+	} else if env, ok := os.LookupEnv("ENVIRONMENT"); ok {
+		profile = Profile(env)
+	}
+	preset, ok := presets[profile]
+	if !ok {
+		return nil, fmt.Errorf("loading config: ENVIRONMENT must be one of %s, got %q", strings.Join(profileNames(), ", "), profile)
+	}
+
+	layered := make(map[string]string, len(preset)+len(overrides))
+	for key, value := range preset {
+		if _, set := os.LookupEnv(key); !set {
+			layered[key] = value
+		}
+	}
+	maps.Copy(layered, overrides)
+
 	var cfg Config
-	if err := envconfig.Process("", &cfg); err != nil {
+	err = withEnv(layered, func() error {
+		return envconfig.Process("", &cfg)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	cfg.File = path
+	if err := cfg.validate(); err != nil {
 		return nil, fmt.Errorf("loading config: %w", err)
 	}
 	return &cfg, nil
 }
+
+// validate rejects settings that load but cannot work together.
+func (c *Config) validate() error {
+	for platform, n := range c.Device.MaxSessions {
+		if n < 0 {
+			return fmt.Errorf("DEVICE_MAX_SESSIONS for %s must not be negative", platform)
+		}
+	}
+	if c.Realtime.Presence && c.Realtime.PresenceTTL <= c.Realtime.Heartbeat {
+		return errors.New("REALTIME_PRESENCE_TTL must be longer than REALTIME_HEARTBEAT")
+	}
+	if c.Security.LockoutEnabled && c.Security.LockoutWindow < c.Security.LockoutMaxDuration {
+		return errors.New("LOGIN_LOCKOUT_WINDOW must not be shorter than LOGIN_LOCKOUT_MAX_DURATION")
+	}
+	if slices.Contains(c.Server.CORSAllowedOrigins, "*") && len(c.Server.CORSAllowedOrigins) > 1 {
+		return errors.New(`CORS_ALLOWED_ORIGINS must be either "*" or a list of origins`)
+	}
+	for id := range c.JWT.PrivateKeyFiles {
+		if id == "" {
+			return errors.New("JWT_PRIVATE_KEY_FILES needs a key id for every file")
+		}
+		if _, ok := c.JWT.Keys[id]; ok {
+			return fmt.Errorf("key %q is in both JWT_KEYS and JWT_PRIVATE_KEY_FILES", id)
+		}
+	}
+	_, secret := c.JWT.Keyring()[c.JWT.SigningKeyID]
+	if _, private := c.JWT.PrivateKeyFiles[c.JWT.SigningKeyID]; !secret && !private {
+		if c.JWT.SigningKeyID == "" {
+			return errors.New("JWT_SECRET_KEY or JWT_SIGNING_KEY_ID is required")
+		}
+		return fmt.Errorf("JWT_SIGNING_KEY_ID %q is not in JWT_KEYS or JWT_PRIVATE_KEY_FILES", c.JWT.SigningKeyID)
+	}
+	if c.Semantic.APIURL != "" {
+		if c.Semantic.Model == "" {
+			return errors.New("EMBEDDINGS_MODEL is required with EMBEDDINGS_API_URL")
+		}
+		if c.Semantic.Interval <= 0 || c.Semantic.Batch <= 0 {
+			return errors.New("EMBEDDINGS_INTERVAL and EMBEDDINGS_BATCH must be positive")
+		}
+	}
+	switch c.Server.SwaggerExposure {
+	case "public", "operators", "off":
+	default:
+		return fmt.Errorf("SWAGGER_EXPOSURE must be public, operators or off, got %q", c.Server.SwaggerExposure)
+	}
+	return nil
+}
+
+// LoadDatabase loads only the DB_* settings, for tools that need nothing
+// else.
+func LoadDatabase() (*DatabaseConfig, error) {
+	var cfg DatabaseConfig
+	if err := envconfig.Process("", &cfg); err != nil {
+		return nil, fmt.Errorf("loading database config: %w", err)
+	}
+	return &cfg, nil
+}
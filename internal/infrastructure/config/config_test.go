@@ -0,0 +1,185 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/config"
+)
+
+func setRequired(t *testing.T) {
+	t.Helper()
+	for key, value := range map[string]string{
+		"DB_USER":              "fieldnotes",
+		"DB_PASSWORD":          "db-secret",
+		"DB_NAME":              "fieldnotes",
+		"JWT_SECRET_KEY":       "jwt-secret",
+		"S3_BUCKET":            "photos",
+		"S3_ACCESS_KEY_ID":     "access",
+		"S3_SECRET_ACCESS_KEY": "s3-secret",
+		"SSO_SECRET_KEY":       "sso-secret",
+	} {
+		t.Setenv(key, value)
+	}
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_Profiles(t *testing.T) {
+	tests := []struct {
+		profile      string
+		logLevel     string
+		swagger      string
+		corsOrigins  []string
+		requestsPerM int
+	}{
+		{"development", "debug", "public", []string{"*"}, 1000},
+		{"staging", "info", "operators", []string{"*"}, 100},
+		{"production", "info", "off", []string{}, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			setRequired(t)
+			t.Setenv("ENVIRONMENT", tt.profile)
+
+			cfg, err := config.Load()
+			require.NoError(t, err)
+			assert.Equal(t, config.Profile(tt.profile), cfg.Server.Environment)
+			assert.Equal(t, tt.logLevel, cfg.Log.Level)
+			assert.Equal(t, tt.swagger, cfg.Server.SwaggerExposure)
+			assert.Equal(t, tt.corsOrigins, cfg.Server.CORSAllowedOrigins)
+			assert.Equal(t, tt.requestsPerM, cfg.RateLimit.RequestsPerMin)
+		})
+	}
+
+	t.Run("environment variables win over the preset", func(t *testing.T) {
+		setRequired(t)
+		t.Setenv("ENVIRONMENT", "production")
+		t.Setenv("SWAGGER_EXPOSURE", "operators")
+		t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com")
+
+		cfg, err := config.Load()
+		require.NoError(t, err)
+		assert.Equal(t, "operators", cfg.Server.SwaggerExposure)
+		assert.Equal(t, []string{"https://app.example.com"}, cfg.Server.CORSAllowedOrigins)
+
+		_, set := os.LookupEnv("LOG_FORMAT")
+		assert.False(t, set, "presets must not leak into the environment")
+	})
+
+	t.Run("rejects an unknown profile", func(t *testing.T) {
+		setRequired(t)
+		t.Setenv("ENVIRONMENT", "prod")
+
+		_, err := config.Load()
+		assert.ErrorContains(t, err, `got "prod"`)
+	})
+}
+
+func TestLoad_File(t *testing.T) {
+	t.Run("overrides the environment", func(t *testing.T) {
+		setRequired(t)
+		t.Setenv("LOG_LEVEL", "warn")
+		t.Setenv("CONFIG_FILE", writeFile(t, `
+ENVIRONMENT: production
+LOG_LEVEL: error
+CORS_ALLOWED_ORIGINS:
+  - https://app.example.com
+  - https://admin.example.com
+DEVICE_MAX_SESSIONS:
+  ios: 2
+  web: 5
+REALTIME_HEARTBEAT: 10s
+`))
+
+		cfg, err := config.Load()
+		require.NoError(t, err)
+		assert.Equal(t, config.ProfileProduction, cfg.Server.Environment)
+		assert.Equal(t, "off", cfg.Server.SwaggerExposure)
+		assert.Equal(t, "error", cfg.Log.Level)
+		assert.Equal(t, []string{"https://app.example.com", "https://admin.example.com"}, cfg.Server.CORSAllowedOrigins)
+		assert.Equal(t, map[string]int{"ios": 2, "web": 5}, cfg.Device.MaxSessions)
+		assert.Equal(t, 10*time.Second, cfg.Realtime.Heartbeat)
+		assert.Equal(t, "warn", os.Getenv("LOG_LEVEL"))
+	})
+
+	t.Run("rejects unknown settings", func(t *testing.T) {
+		setRequired(t)
+		t.Setenv("CONFIG_FILE", writeFile(t, "LOG_LEVLE: debug\n"))
+
+		_, err := config.Load()
+		assert.ErrorContains(t, err, "unknown setting LOG_LEVLE")
+	})
+
+	t.Run("fails when the file is missing", func(t *testing.T) {
+		setRequired(t)
+		t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.yaml"))
+
+		_, err := config.Load()
+		assert.Error(t, err)
+	})
+}
+
+func TestLoad_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		err  string
+	}{
+		{"negative session limit", map[string]string{"DEVICE_MAX_SESSIONS": "ios:-1"}, "DEVICE_MAX_SESSIONS"},
+		{"presence outlived by the heartbeat", map[string]string{"REALTIME_HEARTBEAT": "2m"}, "REALTIME_PRESENCE_TTL"},
+		{"lockout window shorter than the lock", map[string]string{"LOGIN_LOCKOUT_WINDOW": "10m"}, "LOGIN_LOCKOUT_WINDOW"},
+		{"any origin mixed with origins", map[string]string{"CORS_ALLOWED_ORIGINS": "*,https://app.example.com"}, "CORS_ALLOWED_ORIGINS"},
+		{"unknown swagger exposure", map[string]string{"SWAGGER_EXPOSURE": "private"}, "SWAGGER_EXPOSURE"},
+		{"admin that is not a user id", map[string]string{"OPS_ADMIN_USER_IDS": "ana@example.com"}, "OPS_ADMIN_USER_IDS"},
+		{"no key to sign tokens with", map[string]string{"JWT_SECRET_KEY": "", "JWT_KEYS": "k1:first"}, "JWT_SECRET_KEY or JWT_SIGNING_KEY_ID"},
+		{"signing key missing from the keys", map[string]string{"JWT_KEYS": "k1:first", "JWT_SIGNING_KEY_ID": "k2"}, "JWT_SIGNING_KEY_ID"},
+		{"key id both a secret and a private key", map[string]string{"JWT_KEYS": "k1:first", "JWT_PRIVATE_KEY_FILES": "k1:/etc/keys/k1.pem"}, "both JWT_KEYS and JWT_PRIVATE_KEY_FILES"},
+		{"embeddings without a batch", map[string]string{"EMBEDDINGS_API_URL": "http://localhost:11434/v1/embeddings", "EMBEDDINGS_BATCH": "0"}, "EMBEDDINGS_BATCH"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequired(t)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			_, err := config.Load()
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestConfig_Settings(t *testing.T) {
+	setRequired(t)
+	t.Setenv("REDIS_PASSWORD", "")
+	t.Setenv("RATE_LIMIT_EXEMPT_API_KEYS", "monitor:key-1")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	settings := cfg.Settings()
+
+	assert.Equal(t, "[redacted]", settings["JWT_SECRET_KEY"])
+	assert.Equal(t, "[redacted]", settings["DB_PASSWORD"])
+	assert.Equal(t, "[redacted]", settings["RATE_LIMIT_EXEMPT_API_KEYS"])
+	assert.Equal(t, "", settings["JWT_KEYS"])
+	assert.Equal(t, "", settings["REDIS_PASSWORD"])
+	assert.Equal(t, "fieldnotes", settings["DB_USER"])
+	assert.Equal(t, "development", settings["ENVIRONMENT"])
+	assert.Equal(t, "30s", settings["REALTIME_HEARTBEAT"])
+	for _, value := range settings {
+		assert.NotContains(t, value, "secret")
+	}
+}
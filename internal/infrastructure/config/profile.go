@@ -0,0 +1,211 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the environment variable with the path of the overrides
+// file.
+const FileEnv = "CONFIG_FILE"
+
+// Profile is the environment the API runs in, chosen with ENVIRONMENT. It
+// picks the defaults of the settings that should differ between a laptop
+// and a public deployment.
+type Profile string
+
+const (
+	ProfileDevelopment Profile = "development"
+	ProfileStaging     Profile = "staging"
+	ProfileProduction  Profile = "production"
+)
+
+// presets are the defaults each profile gives, by environment variable.
+// They only apply to variables that are not set.
+var presets = map[Profile]map[string]string{
+	ProfileDevelopment: {
+		"LOG_LEVEL":                   "debug",
+		"SWAGGER_EXPOSURE":            "public",
+		"CORS_ALLOWED_ORIGINS":        "*",
+		"RATE_LIMIT_REQUESTS_PER_MIN": "1000",
+	},
+	ProfileStaging: {
+		"LOG_LEVEL":            "info",
+		"LOG_FORMAT":           "json",
+		"SWAGGER_EXPOSURE":     "operators",
+		"CORS_ALLOWED_ORIGINS": "*",
+		"RATE_LIMIT_ENABLED":   "true",
+	},
+	ProfileProduction: {
+		"LOG_LEVEL":        "info",
+		"LOG_FORMAT":       "json",
+		"SWAGGER_EXPOSURE": "off",
+		// Browsers are kept out until the origins of the web apps are listed.
+		"CORS_ALLOWED_ORIGINS": "",
+		"RATE_LIMIT_ENABLED":   "true",
+	},
+}
+
+func profileNames() []string {
+	names := make([]string, 0, len(presets))
+	for p := range presets {
+		names = append(names, string(p))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// readOverrides reads the overrides file at path into environment variable
+// values. The file is YAML keyed by environment variable name; lists are
+// joined with commas and maps written as key:value pairs, the way envconfig
+// reads them. An empty path reads nothing.
+func readOverrides(path string) (map[string]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", FileEnv, err)
+	}
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	known := settingKeys()
+	overrides := make(map[string]string, len(doc))
+	for key, value := range doc {
+		if _, ok := known[key]; !ok {
+			return nil, fmt.Errorf("%s: unknown setting %s", path, key)
+		}
+		s, err := overrideValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", path, key, err)
+		}
+		overrides[key] = s
+	}
+	return overrides, nil
+}
+
+func overrideValue(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			s, err := overrideScalar(item)
+			if err != nil {
+				return "", err
+			}
+			parts[i] = s
+		}
+		return strings.Join(parts, ","), nil
+	case map[string]any:
+		parts := make([]string, 0, len(v))
+		for k, item := range v {
+			s, err := overrideScalar(item)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, k+":"+s)
+		}
+		slices.Sort(parts)
+		return strings.Join(parts, ","), nil
+	default:
+		return overrideScalar(v)
+	}
+}
+
+func overrideScalar(value any) (string, error) {
+	switch value.(type) {
+	case []any, map[string]any:
+		return "", fmt.Errorf("nested values are not supported")
+	}
+	return fmt.Sprint(value), nil
+}
+
+// withEnv runs fn with the environment variables in values set, and puts
+// the environment back afterwards.
+func withEnv(values map[string]string, fn func() error) error {
+	for key, value := range values {
+		previous, set := os.LookupEnv(key)
+		if err := os.Setenv(key, value); err != nil {
+			return err
+		}
+		if set {
+			defer os.Setenv(key, previous)
+		} else {
+			defer os.Unsetenv(key)
+		}
+	}
+	return fn()
+}
+
+// settingKeys returns the environment variable of every setting, mapped to
+// whether it holds a secret.
+func settingKeys() map[string]bool {
+	keys := make(map[string]bool)
+	walkSettings(reflect.ValueOf(Config{}), func(key string, secret bool, _ reflect.Value) {
+		keys[key] = secret
+	})
+	return keys
+}
+
+// Settings returns every setting by environment variable, as the API runs
+// with it. Secrets that are set read "[redacted]", so the result can be
+// logged.
+func (c *Config) Settings() map[string]string {
+	settings := make(map[string]string)
+	walkSettings(reflect.ValueOf(*c), func(key string, secret bool, v reflect.Value) {
+		if secret && !v.IsZero() {
+			settings[key] = "[redacted]"
+			return
+		}
+		settings[key] = settingString(v)
+	})
+	return settings
+}
+
+func settingString(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Slice:
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		return strings.Join(parts, ",")
+	case reflect.Map:
+		parts := make([]string, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			parts = append(parts, fmt.Sprintf("%v:%v", iter.Key().Interface(), iter.Value().Interface()))
+		}
+		slices.Sort(parts)
+		return strings.Join(parts, ",")
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+func walkSettings(v reflect.Value, fn func(key string, secret bool, v reflect.Value)) {
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Tag.Get("ignored") == "true" {
+			continue
+		}
+		key := field.Tag.Get("envconfig")
+		if key == "" && field.Type.Kind() == reflect.Struct {
+			walkSettings(v.Field(i), fn)
+			continue
+		}
+		fn(key, field.Tag.Get("secret") == "true", v.Field(i))
+	}
+}
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// HasIndex reports whether table has an index whose leading key columns
+// are columns, in order, so queries filtering or sorting on them can use it.
+// Expression columns never match.
+func HasIndex(ctx context.Context, pool *pgxpool.Pool, table string, columns []string) (bool, error) {
+	query := `
+		SELECT array_agg(COALESCE(a.attname, '') ORDER BY k.ord)
+		FROM pg_index i
+		JOIN pg_class t ON t.oid = i.indrelid
+		CROSS JOIN LATERAL unnest(i.indkey) WITH ORDINALITY AS k(attnum, ord)
+		LEFT JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+		WHERE t.relname = $1 AND t.relnamespace = 'public'::regnamespace
+		  AND k.ord <= i.indnkeyatts
+		GROUP BY i.indexrelid
+	`
+	rows, err := pool.Query(ctx, query, table)
+	if err != nil {
+		return false, fmt.Errorf("querying indexes of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var keys []string
+		if err := rows.Scan(&keys); err != nil {
+			return false, fmt.Errorf("scanning index of %s: %w", table, err)
+		}
+		if len(keys) >= len(columns) && slices.Equal(keys[:len(columns)], columns) {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+// MigratedVersion returns the version golang-migrate recorded in
+// schema_migrations, and false when nothing was recorded. A migration that
+// failed halfway is returned as an error.
+func MigratedVersion(ctx context.Context, pool *pgxpool.Pool) (int, bool, error) {
+	var exists bool
+	if err := pool.QueryRow(ctx, `SELECT to_regclass('public.schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return 0, false, fmt.Errorf("looking up schema_migrations: %w", err)
+	}
+	if !exists {
+		return 0, false, nil
+	}
+
+	var version int64
+	var dirty bool
+	err := pool.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("reading schema_migrations: %w", err)
+	}
+	if dirty {
+		return 0, false, fmt.Errorf("migration %d failed halfway and must be fixed by hand", version)
+	}
+	return int(version), true, nil
+}
@@ -0,0 +1,288 @@
+package database
+
+import (
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Rules reported by LintMigrations.
+const (
+	// RuleNotNull flags columns made NOT NULL on an existing table: adding
+	// one without a default fails on a table with rows, and SET NOT NULL
+	// scans the whole table under an exclusive lock.
+	RuleNotNull = "not-null"
+	// RuleIndexNotConcurrent flags indexes built on an existing table without
+	// CONCURRENTLY, which blocks writes to it for the whole build.
+	RuleIndexNotConcurrent = "index-not-concurrent"
+	// RuleConcurrentInTransaction flags CONCURRENTLY in a migration with
+	// other statements. RunMigrations runs each file as one transaction,
+	// where Postgres refuses it.
+	RuleConcurrentInTransaction = "concurrent-in-transaction"
+	// RuleColumnType flags column type changes, which usually rewrite the
+	// table under an exclusive lock.
+	RuleColumnType = "column-type"
+)
+
+// lintIgnore in a comment right before a statement silences the named rules
+// for it, for operations known to be safe, e.g.
+// "-- schemacheck:ignore not-null (table is always small)".
+const lintIgnore = "schemacheck:ignore"
+
+// MigrationIssue is an unsafe operation found in a migration.
+type MigrationIssue struct {
+	File    string
+	Line    int
+	Rule    string
+	Message string
+}
+
+func (i MigrationIssue) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s", i.File, i.Line, i.Rule, i.Message)
+}
+
+// MigrationVersion returns the number a migration file name starts with.
+func MigrationVersion(name string) (int, bool) {
+	prefix, _, ok := strings.Cut(name, "_")
+	if !ok {
+		return 0, false
+	}
+	version, err := strconv.Atoi(prefix)
+	if err != nil {
+		return 0, false
+	}
+	return version, true
+}
+
+// LintMigrations checks the .up.sql migrations of fsys newer than since for
+// operations that lock or fail on tables already holding data. Tables
+// created by the same migration are empty and not checked.
+func LintMigrations(fsys fs.FS, since int) ([]MigrationIssue, error) {
+	files, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("reading migrations directory: %w", err)
+	}
+
+	var names []string
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".up.sql") {
+			continue
+		}
+		if version, ok := MigrationVersion(f.Name()); ok && version > since {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var issues []MigrationIssue
+	for _, name := range names {
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("reading migration file %s: %w", name, err)
+		}
+		issues = append(issues, lintMigration(name, string(content))...)
+	}
+	return issues, nil
+}
+
+var (
+	createTablePattern = regexp.MustCompile(`^CREATE (?:UNLOGGED )?TABLE (?:IF NOT EXISTS )?([\w."]+)`)
+	createIndexPattern = regexp.MustCompile(`^CREATE (?:UNIQUE )?INDEX (CONCURRENTLY )?(?:IF NOT EXISTS )?(?:[\w"]+ )?ON (?:ONLY )?([\w."]+)`)
+	alterTablePattern  = regexp.MustCompile(`^ALTER TABLE (?:IF EXISTS )?(?:ONLY )?([\w."]+) (.*)$`)
+	addColumnPattern   = regexp.MustCompile(`^ADD (?:COLUMN )?(?:IF NOT EXISTS )?([\w"]+)`)
+	alterColumnPattern = regexp.MustCompile(`^ALTER (?:COLUMN )?([\w"]+) (SET NOT NULL|(?:SET DATA )?TYPE)\b`)
+)
+
+// tableConstraints are the words that follow ADD when a constraint, not a
+// column, is added.
+var tableConstraints = map[string]bool{
+	"CONSTRAINT": true, "PRIMARY": true, "UNIQUE": true, "CHECK": true, "FOREIGN": true, "EXCLUDE": true,
+}
+
+func lintMigration(file, content string) []MigrationIssue {
+	stmts := splitStatements(content)
+	created := make(map[string]bool)
+
+	var issues []MigrationIssue
+	report := func(s statement, rule, format string, args ...any) {
+		if !s.ignores(rule) {
+			issues = append(issues, MigrationIssue{File: file, Line: s.line, Rule: rule, Message: fmt.Sprintf(format, args...)})
+		}
+	}
+
+	for _, s := range stmts {
+		text := s.normalized()
+
+		if m := createTablePattern.FindStringSubmatch(text); m != nil {
+			created[tableName(m[1])] = true
+			continue
+		}
+
+		if m := createIndexPattern.FindStringSubmatch(text); m != nil {
+			table := tableName(m[2])
+			switch {
+			case m[1] != "" && len(stmts) > 1:
+				report(s, RuleConcurrentInTransaction, "CREATE INDEX CONCURRENTLY on %s must be the only statement of its migration", table)
+			case m[1] == "" && !created[table]:
+				report(s, RuleIndexNotConcurrent, "index on existing table %s blocks writes while it builds; use CREATE INDEX CONCURRENTLY in a migration of its own", table)
+			}
+			continue
+		}
+
+		m := alterTablePattern.FindStringSubmatch(text)
+		if m == nil || created[tableName(m[1])] {
+			continue
+		}
+		table := tableName(m[1])
+		for _, action := range splitTopLevel(m[2], ',') {
+			if c := addColumnPattern.FindStringSubmatch(action); c != nil && !tableConstraints[c[1]] {
+				if strings.Contains(action, " NOT NULL") && !strings.Contains(action, " DEFAULT ") {
+					report(s, RuleNotNull, "column %s.%s is added NOT NULL without a default and fails if the table has rows", table, strings.ToLower(unquote(c[1])))
+				}
+				continue
+			}
+			if c := alterColumnPattern.FindStringSubmatch(action); c != nil {
+				if c[2] == "SET NOT NULL" {
+					report(s, RuleNotNull, "SET NOT NULL on %s.%s scans the table under an exclusive lock; add a NOT VALID check constraint and validate it first", table, strings.ToLower(unquote(c[1])))
+				} else {
+					report(s, RuleColumnType, "changing the type of %s.%s can rewrite the table under an exclusive lock", table, strings.ToLower(unquote(c[1])))
+				}
+			}
+		}
+	}
+	return issues
+}
+
+type statement struct {
+	// text is the statement without comments.
+	text string
+	// comments are the comments that precede it.
+	comments []string
+	line     int
+}
+
+// normalized returns the statement upper-cased with whitespace collapsed,
+// for matching. Identifiers are matched upper-cased too.
+func (s statement) normalized() string {
+	return strings.ToUpper(strings.Join(strings.Fields(s.text), " "))
+}
+
+func (s statement) ignores(rule string) bool {
+	for _, c := range s.comments {
+		_, rest, ok := strings.Cut(c, lintIgnore)
+		if !ok {
+			continue
+		}
+		for _, r := range strings.Fields(rest) {
+			if r == rule {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// splitStatements splits SQL on semicolons outside quotes, dollar-quoted
+// bodies and comments.
+func splitStatements(sql string) []statement {
+	var stmts []statement
+	var cur strings.Builder
+	var comments []string
+	line, start := 1, 0
+
+	// write appends to the current statement, which starts at the first
+	// text written to it.
+	write := func(text string) {
+		if cur.Len() == 0 {
+			if strings.TrimSpace(text) == "" {
+				line += strings.Count(text, "\n")
+				return
+			}
+			start = line
+		}
+		cur.WriteString(text)
+		line += strings.Count(text, "\n")
+	}
+	flush := func() {
+		if text := strings.TrimSpace(cur.String()); text != "" {
+			stmts = append(stmts, statement{text: text, comments: comments, line: start})
+			comments = nil
+		}
+		cur.Reset()
+	}
+
+	for i := 0; i < len(sql); {
+		rest := sql[i:]
+		n := 1
+		switch {
+		case strings.HasPrefix(rest, "--"):
+			n = strings.IndexByte(rest, '\n')
+			if n < 0 {
+				n = len(rest)
+			}
+			comments = append(comments, rest[:n])
+		case rest[0] == '\'':
+			if end := strings.IndexByte(rest[1:], '\''); end >= 0 {
+				n = end + 2
+			} else {
+				n = len(rest)
+			}
+			write(rest[:n])
+		case rest[0] == '$' && dollarTag(rest) != "":
+			tag := dollarTag(rest)
+			if end := strings.Index(rest[len(tag):], tag); end >= 0 {
+				n = len(tag) + end + len(tag)
+			} else {
+				n = len(rest)
+			}
+			write(rest[:n])
+		case rest[0] == ';':
+			flush()
+		default:
+			write(rest[:1])
+		}
+		i += n
+	}
+	flush()
+	return stmts
+}
+
+var dollarTagPattern = regexp.MustCompile(`^\$[A-Za-z_]*\$`)
+
+// dollarTag returns the $tag$ opening a dollar-quoted string at the start
+// of s, or "" when there is none.
+func dollarTag(s string) string {
+	return dollarTagPattern.FindString(s)
+}
+
+// splitTopLevel splits s on sep outside parentheses.
+func splitTopLevel(s string, sep byte) []string {
+	var parts []string
+	depth, last := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case sep:
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(s[last:i]))
+				last = i + 1
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(s[last:]))
+}
+
+// tableName drops the public schema and quotes from a table reference.
+func tableName(ref string) string {
+	return strings.ToLower(strings.TrimPrefix(unquote(ref), "PUBLIC."))
+}
+
+func unquote(ident string) string {
+	return strings.ReplaceAll(ident, `"`, "")
+}
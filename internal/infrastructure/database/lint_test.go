@@ -0,0 +1,111 @@
+package database_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/database"
+)
+
+func TestLintMigrations(t *testing.T) {
+	lint := func(t *testing.T, files map[string]string, since int) []database.MigrationIssue {
+		t.Helper()
+		fsys := fstest.MapFS{}
+		for name, content := range files {
+			fsys[name] = &fstest.MapFile{Data: []byte(content)}
+		}
+		issues, err := database.LintMigrations(fsys, since)
+		require.NoError(t, err)
+		return issues
+	}
+	rules := func(issues []database.MigrationIssue) []string {
+		var r []string
+		for _, i := range issues {
+			r = append(r, i.Rule)
+		}
+		return r
+	}
+
+	t.Run("flags unsafe changes to existing tables", func(t *testing.T) {
+		issues := lint(t, map[string]string{
+			"000005_unsafe.up.sql": `-- Adds things.
+ALTER TABLE notes ADD COLUMN score INT NOT NULL;
+
+CREATE INDEX idx_notes_score ON notes(score);
+ALTER TABLE notes
+    ALTER COLUMN title TYPE TEXT,
+    ALTER COLUMN score SET NOT NULL;
+`,
+		}, 0)
+
+		require.Len(t, issues, 4)
+		assert.Equal(t, []string{
+			database.RuleNotNull, database.RuleIndexNotConcurrent, database.RuleColumnType, database.RuleNotNull,
+		}, rules(issues))
+		assert.Equal(t, "000005_unsafe.up.sql", issues[0].File)
+		assert.Equal(t, 2, issues[0].Line)
+		assert.Equal(t, 4, issues[1].Line)
+		assert.Equal(t, 5, issues[2].Line)
+		assert.Contains(t, issues[2].Message, "notes.title")
+	})
+
+	t.Run("accepts safe changes", func(t *testing.T) {
+		issues := lint(t, map[string]string{
+			"000006_safe.up.sql": `
+CREATE TABLE tags (id UUID PRIMARY KEY, name TEXT NOT NULL);
+CREATE INDEX idx_tags_name ON tags(name);
+ALTER TABLE tags ALTER COLUMN name TYPE VARCHAR(50);
+ALTER TABLE notes ADD COLUMN pinned BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE notes ADD CONSTRAINT notes_pinned_check CHECK (pinned IS NOT NULL) NOT VALID;
+ALTER TABLE notes ADD PRIMARY KEY (id, user_id);
+-- A semicolon in a body; ALTER TABLE notes ALTER COLUMN title TYPE TEXT;
+CREATE FUNCTION f() RETURNS void AS $$
+BEGIN
+    ALTER TABLE notes ALTER COLUMN title TYPE TEXT;
+END $$ LANGUAGE plpgsql;
+`,
+			"000007_concurrent.up.sql": `CREATE INDEX CONCURRENTLY idx_notes_title ON notes(title);`,
+		}, 0)
+
+		assert.Empty(t, issues)
+	})
+
+	t.Run("requires concurrent indexes to be alone in their migration", func(t *testing.T) {
+		issues := lint(t, map[string]string{
+			"000008_concurrent.up.sql": `
+ALTER TABLE notes ADD COLUMN pinned BOOLEAN;
+CREATE INDEX CONCURRENTLY idx_notes_pinned ON notes(pinned);
+`,
+		}, 0)
+
+		assert.Equal(t, []string{database.RuleConcurrentInTransaction}, rules(issues))
+	})
+
+	t.Run("honours ignore comments", func(t *testing.T) {
+		issues := lint(t, map[string]string{
+			"000009_small.up.sql": `
+-- The table never holds more than a few rows.
+-- schemacheck:ignore index-not-concurrent
+CREATE INDEX idx_settings_key ON settings(key);
+CREATE INDEX idx_settings_value ON settings(value);
+`,
+		}, 0)
+
+		require.Len(t, issues, 1)
+		assert.Equal(t, 5, issues[0].Line)
+	})
+
+	t.Run("only checks migrations after since", func(t *testing.T) {
+		issues := lint(t, map[string]string{
+			"000001_old.up.sql":   `CREATE INDEX idx_notes_a ON notes(a);`,
+			"000002_new.up.sql":   `CREATE INDEX idx_notes_b ON notes(b);`,
+			"000002_new.down.sql": `ALTER TABLE notes ALTER COLUMN b TYPE TEXT;`,
+		}, 1)
+
+		require.Len(t, issues, 1)
+		assert.Equal(t, "000002_new.up.sql", issues[0].File)
+	})
+}
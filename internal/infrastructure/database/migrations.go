@@ -3,17 +3,17 @@ package database
 import (
 	"context"
 	"fmt"
-	"os"
-	"path/filepath"
+	"io/fs"
 	"sort"
 	"strings"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// RunMigrations executes all .up.sql migrations from the specified directory
-func RunMigrations(ctx context.Context, pool *pgxpool.Pool, migrationsPath string) error {
-	files, err := os.ReadDir(migrationsPath)
+// RunMigrations executes all .up.sql migrations found at the root of fsys,
+// usually migrations.FS or os.DirFS of an override directory.
+func RunMigrations(ctx context.Context, pool *pgxpool.Pool, fsys fs.FS) error {
+	files, err := fs.ReadDir(fsys, ".")
 	if err != nil {
 		return fmt.Errorf("reading migrations directory: %w", err)
 	}
@@ -28,8 +28,7 @@ func RunMigrations(ctx context.Context, pool *pgxpool.Pool, migrationsPath strin
 	sort.Strings(upFiles)
 
 	for _, filename := range upFiles {
-		path := filepath.Join(migrationsPath, filename)
-		content, err := os.ReadFile(path)
+		content, err := fs.ReadFile(fsys, filename)
 		if err != nil {
 			return fmt.Errorf("reading migration file %s: %w", filename, err)
 		}
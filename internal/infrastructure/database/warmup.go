@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Warmup opens conns connections in pool and prepares queries on each of
+// them, so requests arriving right after startup neither wait for a
+// connection to be established nor for their statements to be planned.
+// Queries run through the pool with the same SQL text use the prepared
+// statement. Connections opened later prepare statements on first use.
+func Warmup(ctx context.Context, pool *pgxpool.Pool, conns int, queries []string) error {
+	acquired := make([]*pgxpool.Conn, 0, conns)
+	defer func() {
+		for _, conn := range acquired {
+			conn.Release()
+		}
+	}()
+
+	// Holding every connection until the end makes each Acquire open a new
+	// one instead of reusing the last.
+	for range conns {
+		conn, err := pool.Acquire(ctx)
+		if err != nil {
+			return fmt.Errorf("acquiring connection: %w", err)
+		}
+		acquired = append(acquired, conn)
+
+		for _, query := range queries {
+			if _, err := conn.Conn().Prepare(ctx, query, query); err != nil {
+				return fmt.Errorf("preparing statement: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type embedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// embedResponse covers the fields of the OpenAI embeddings API that the
+// compatible servers (Ollama, LocalAI, vLLM and others) send too.
+type embedResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// HTTPProvider computes embeddings with an OpenAI-compatible API, such as
+// https://api.openai.com/v1/embeddings or a self-hosted Ollama at
+// http://localhost:11434/v1/embeddings.
+type HTTPProvider struct {
+	httpClient *http.Client
+	url        string
+	apiKey     string
+	model      string
+}
+
+func NewHTTPProvider(url, apiKey, model string, timeout time.Duration) *HTTPProvider {
+	return &HTTPProvider{
+		httpClient: &http.Client{Timeout: timeout},
+		url:        url,
+		apiKey:     apiKey,
+		model:      model,
+	}
+}
+
+func (p *HTTPProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	body, err := json.Marshal(embedRequest{Model: p.model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("encoding embeddings request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating embeddings request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("querying embeddings provider: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var out embedResponse
+	decodeErr := json.NewDecoder(resp.Body).Decode(&out)
+	if resp.StatusCode != http.StatusOK {
+		if decodeErr == nil && out.Error != nil {
+			return nil, fmt.Errorf("embeddings provider returned status %d: %s", resp.StatusCode, out.Error.Message)
+		}
+		return nil, fmt.Errorf("embeddings provider returned status %d", resp.StatusCode)
+	}
+	if decodeErr != nil {
+		return nil, fmt.Errorf("decoding embeddings response: %w", decodeErr)
+	}
+	if len(out.Data) != len(texts) {
+		return nil, fmt.Errorf("embeddings provider returned %d vectors for %d texts", len(out.Data), len(texts))
+	}
+
+	// Providers may send the vectors out of order; index says which text
+	// each belongs to.
+	vectors := make([][]float32, len(texts))
+	for _, d := range out.Data {
+		if d.Index < 0 || d.Index >= len(texts) || vectors[d.Index] != nil {
+			return nil, fmt.Errorf("embeddings provider returned an unexpected index %d", d.Index)
+		}
+		if len(d.Embedding) == 0 {
+			return nil, fmt.Errorf("embeddings provider returned an empty vector for text %d", d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	return vectors, nil
+}
@@ -0,0 +1,62 @@
+package embedding_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/embedding"
+)
+
+func TestHTTPProvider_Embed(t *testing.T) {
+	t.Run("sends the model and texts and orders the vectors by index", func(t *testing.T) {
+		var got struct {
+			Model string   `json:"model"`
+			Input []string `json:"input"`
+		}
+		var auth string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			auth = r.Header.Get("Authorization")
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+			_, _ = w.Write([]byte(`{"data":[{"index":1,"embedding":[0.3,0.4]},{"index":0,"embedding":[0.1,0.2]}]}`))
+		}))
+		t.Cleanup(srv.Close)
+
+		provider := embedding.NewHTTPProvider(srv.URL, "sk-test", "text-embedding-3-small", time.Second)
+		vectors, err := provider.Embed(t.Context(), []string{"Oak survey", "Ninho"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer sk-test", auth)
+		assert.Equal(t, "text-embedding-3-small", got.Model)
+		assert.Equal(t, []string{"Oak survey", "Ninho"}, got.Input)
+		assert.Equal(t, [][]float32{{0.1, 0.2}, {0.3, 0.4}}, vectors)
+	})
+
+	t.Run("reports the provider's error", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"message":"invalid api key"}}`))
+		}))
+		t.Cleanup(srv.Close)
+
+		_, err := embedding.NewHTTPProvider(srv.URL, "", "m", time.Second).Embed(t.Context(), []string{"a"})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid api key")
+	})
+
+	t.Run("rejects a response missing vectors", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"data":[{"index":0,"embedding":[0.1]}]}`))
+		}))
+		t.Cleanup(srv.Close)
+
+		_, err := embedding.NewHTTPProvider(srv.URL, "", "m", time.Second).Embed(t.Context(), []string{"a", "b"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,80 @@
+package geoip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+)
+
+// IPPlaceholder is replaced with the looked up address in the provider URL.
+const IPPlaceholder = "{ip}"
+
+// lookupResponse covers the common fields of ipapi.co-style JSON APIs.
+type lookupResponse struct {
+	City        string   `json:"city"`
+	CountryCode string   `json:"country_code"`
+	Country     string   `json:"country"`
+	Latitude    *float64 `json:"latitude"`
+	Longitude   *float64 `json:"longitude"`
+	Error       bool     `json:"error"`
+	Reason      string   `json:"reason"`
+}
+
+// HTTPResolver looks addresses up in a JSON API, such as
+// https://ipapi.co/{ip}/json/.
+type HTTPResolver struct {
+	httpClient  *http.Client
+	urlTemplate string
+}
+
+func NewHTTPResolver(urlTemplate string, timeout time.Duration) *HTTPResolver {
+	return &HTTPResolver{
+		httpClient:  &http.Client{Timeout: timeout},
+		urlTemplate: urlTemplate,
+	}
+}
+
+func (r *HTTPResolver) Lookup(ctx context.Context, ip string) (*entity.GeoLocation, error) {
+	endpoint := strings.ReplaceAll(r.urlTemplate, IPPlaceholder, url.PathEscape(ip))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating geoip request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("querying geoip provider: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geoip provider returned status %d", resp.StatusCode)
+	}
+
+	var body lookupResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decoding geoip response: %w", err)
+	}
+	if body.Error {
+		return nil, fmt.Errorf("geoip provider error: %s", body.Reason)
+	}
+
+	// Prefer the ISO code; some providers only send the country name.
+	country := body.CountryCode
+	if country == "" {
+		country = body.Country
+	}
+	loc := &entity.GeoLocation{City: body.City, Country: country}
+	if body.Latitude != nil && body.Longitude != nil {
+		loc.Coordinates = valueobject.NewLocation(*body.Latitude, *body.Longitude, nil, nil)
+	}
+	return loc, nil
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/audit"
+)
+
+// AuditClient records the client address and user agent in the request
+// context for the audit events the request causes. After RequireAuth it also
+// records the device the access token was issued to, so it runs again there.
+func AuditClient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := audit.WithClient(c.Request.Context(), audit.Client{
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+			DeviceID:  httputil.GetTokenDeviceID(c),
+		})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
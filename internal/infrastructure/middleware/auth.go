@@ -1,52 +1,125 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
 	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/auth"
 	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
 )
 
 const (
 	UserIDKey    = "user_id"
-	BearerPrefix = "Bearer "
+	SessionIDKey = "session_id"
+	// TokenDeviceIDKey holds the registered device the access token was
+	// issued to, unlike the client-supplied X-Device-ID header.
+	TokenDeviceIDKey = "token_device_id"
+	BearerPrefix     = "Bearer "
 )
 
+// TokenVersionSource returns the token version a user's access tokens must
+// carry to be accepted.
+type TokenVersionSource interface {
+	GetTokenVersion(ctx context.Context, userID uuid.UUID) (int, error)
+}
+
 type AuthMiddleware struct {
-	jwtSvc *auth.JWTService
+	jwtSvc   *auth.JWTService
+	versions TokenVersionSource
 }
 
-func NewAuthMiddleware(jwtSvc *auth.JWTService) *AuthMiddleware {
-	return &AuthMiddleware{jwtSvc: jwtSvc}
+func NewAuthMiddleware(jwtSvc *auth.JWTService, versions TokenVersionSource) *AuthMiddleware {
+	return &AuthMiddleware{jwtSvc: jwtSvc, versions: versions}
 }
 
 func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			httputil.Error(c, http.StatusUnauthorized, "authorization header required")
-			c.Abort()
-			return
+		if m.authenticate(c) {
+			c.Next()
 		}
+	}
+}
 
-		if !strings.HasPrefix(authHeader, BearerPrefix) {
-			httputil.Error(c, http.StatusUnauthorized, "invalid authorization format")
-			c.Abort()
+// RequireOperator lets through the operators: clients with the "ops"
+// password over basic auth, and users in admins with their access token.
+// Either is off when empty.
+func (m *AuthMiddleware) RequireOperator(password string, admins []uuid.UUID) gin.HandlerFunc {
+	var basic gin.HandlerFunc
+	if password != "" {
+		basic = gin.BasicAuth(gin.Accounts{"ops": password})
+	}
+	return func(c *gin.Context) {
+		if len(admins) > 0 && strings.HasPrefix(c.GetHeader("Authorization"), BearerPrefix) {
+			if !m.authenticate(c) {
+				return
+			}
+			if !slices.Contains(admins, c.MustGet(UserIDKey).(uuid.UUID)) {
+				httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeForbidden, "operators only")
+				c.Abort()
+				return
+			}
+			c.Next()
 			return
 		}
-
-		token := strings.TrimPrefix(authHeader, BearerPrefix)
-		userID, err := m.jwtSvc.ValidateAccessToken(token)
-		if err != nil {
-			httputil.Error(c, http.StatusUnauthorized, "invalid or expired token")
+		if basic == nil {
+			httputil.ErrorWithCode(c, http.StatusUnauthorized, httputil.CodeUnauthorized, "authorization header required")
 			c.Abort()
 			return
 		}
+		basic(c)
+	}
+}
+
+// authenticate checks the access token of the request and keeps its user,
+// session and device on the context. It answers the request and returns
+// false when the token is missing or not valid.
+func (m *AuthMiddleware) authenticate(c *gin.Context) bool {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		httputil.ErrorWithCode(c, http.StatusUnauthorized, httputil.CodeUnauthorized, "authorization header required")
+		c.Abort()
+		return false
+	}
+
+	if !strings.HasPrefix(authHeader, BearerPrefix) {
+		httputil.ErrorWithCode(c, http.StatusUnauthorized, httputil.CodeUnauthorized, "invalid authorization format")
+		c.Abort()
+		return false
+	}
 
-		c.Set(UserIDKey, userID)
-		c.Next()
+	token := strings.TrimPrefix(authHeader, BearerPrefix)
+	access, err := m.jwtSvc.ValidateAccessToken(token)
+	if err != nil {
+		httputil.ErrorWithCode(c, http.StatusUnauthorized, httputil.CodeUnauthorized, "invalid or expired token")
+		c.Abort()
+		return false
 	}
+
+	version, err := m.versions.GetTokenVersion(c.Request.Context(), access.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			httputil.ErrorWithCode(c, http.StatusUnauthorized, httputil.CodeUnauthorized, "invalid or expired token")
+		} else {
+			httputil.InternalError(c)
+		}
+		c.Abort()
+		return false
+	}
+	if access.TokenVersion < version {
+		httputil.ErrorWithCode(c, http.StatusUnauthorized, httputil.CodeUnauthorized, "token has been revoked")
+		c.Abort()
+		return false
+	}
+
+	c.Set(UserIDKey, access.UserID)
+	c.Set(SessionIDKey, access.SessionID)
+	c.Set(TokenDeviceIDKey, access.DeviceID)
+	return true
 }
@@ -0,0 +1,85 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/auth"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/middleware"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+type fakeTokenVersions map[uuid.UUID]int
+
+func (f fakeTokenVersions) GetTokenVersion(_ context.Context, userID uuid.UUID) (int, error) {
+	version, ok := f[userID]
+	if !ok {
+		return 0, domain.ErrUserNotFound
+	}
+	return version, nil
+}
+
+func TestAuthMiddleware_RequireAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtSvc := auth.NewJWTService("test-secret", 15*time.Minute)
+	userID := uuid.New()
+	sessionID := uuid.New()
+	deviceID := uuid.New()
+	versions := fakeTokenVersions{userID: 2}
+
+	router := gin.New()
+	router.GET("/me", middleware.NewAuthMiddleware(jwtSvc, versions).RequireAuth(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"user_id":    httputil.GetUserID(c),
+			"session_id": httputil.GetSessionID(c),
+			"device_id":  httputil.GetTokenDeviceID(c),
+		})
+	})
+
+	request := func(t *testing.T, access auth.AccessToken) *httptest.ResponseRecorder {
+		t.Helper()
+		token, _, err := jwtSvc.GenerateAccessToken(access, 0)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("exposes the token identity", func(t *testing.T) {
+		w := request(t, auth.AccessToken{UserID: userID, SessionID: sessionID, DeviceID: deviceID, TokenVersion: 2})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), sessionID.String())
+		assert.Contains(t, w.Body.String(), deviceID.String())
+	})
+
+	t.Run("rejects tokens older than the user's version", func(t *testing.T) {
+		w := request(t, auth.AccessToken{UserID: userID, SessionID: sessionID, DeviceID: deviceID, TokenVersion: 1})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("rejects tokens of unknown users", func(t *testing.T) {
+		w := request(t, auth.AccessToken{UserID: uuid.New(), TokenVersion: 0})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("requires a bearer token", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/me", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/apperror"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+// RouteBodyLimit is the body size limit, in bytes, of the routes whose path
+// starts with Prefix. A zero Limit leaves those routes to bound their own
+// bodies, as uploads do.
+type RouteBodyLimit struct {
+	Prefix string
+	Limit  int64
+}
+
+// BodyLimit bounds the request body to the route's limit: the first entry of
+// routes matching the route path, or def. A declared Content-Length over the
+// limit is refused with a 413 before the handler runs; a longer body sent
+// without one fails to bind, which httputil.ValidationError reports as 413.
+func BodyLimit(def int64, routes []RouteBodyLimit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := routeBodyLimit(c.FullPath(), def, routes)
+		if limit <= 0 || c.Request.Body == nil {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > limit {
+			// The connection is closed so the client stops sending the body.
+			c.Header("Connection", "close")
+			httputil.Abort(c, apperror.New(http.StatusRequestEntityTooLarge, httputil.CodeBodyTooLarge,
+				"request body is too large"))
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
+
+func routeBodyLimit(path string, def int64, routes []RouteBodyLimit) int64 {
+	for _, route := range routes {
+		if strings.HasPrefix(path, route.Prefix) {
+			return route.Limit
+		}
+	}
+	return def
+}
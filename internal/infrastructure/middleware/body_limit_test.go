@@ -0,0 +1,74 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/middleware"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+func TestBodyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := func(c *gin.Context) {
+		var body struct {
+			Content string `json:"content"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			httputil.ValidationError(c, err)
+			return
+		}
+		c.Status(http.StatusCreated)
+	}
+
+	router := gin.New()
+	router.Use(middleware.BodyLimit(64, []middleware.RouteBodyLimit{
+		{Prefix: "/sync", Limit: 1024},
+		{Prefix: "/upload", Limit: 0},
+	}))
+	router.POST("/notes", handler)
+	router.POST("/sync", handler)
+	router.POST("/upload", handler)
+
+	post := func(path string, size int, chunked bool) *httptest.ResponseRecorder {
+		body := `{"content":"` + strings.Repeat("a", size) + `"}`
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if chunked {
+			req.ContentLength = -1
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("accepts bodies within the limit", func(t *testing.T) {
+		assert.Equal(t, http.StatusCreated, post("/notes", 10, false).Code)
+	})
+
+	t.Run("refuses a declared length over the limit", func(t *testing.T) {
+		w := post("/notes", 100, false)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Contains(t, w.Body.String(), httputil.CodeBodyTooLarge)
+		assert.Equal(t, "close", w.Header().Get("Connection"))
+	})
+
+	t.Run("refuses an undeclared body once it passes the limit", func(t *testing.T) {
+		w := post("/notes", 100, true)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Contains(t, w.Body.String(), httputil.CodeBodyTooLarge)
+	})
+
+	t.Run("applies the route's limit", func(t *testing.T) {
+		assert.Equal(t, http.StatusCreated, post("/sync", 500, false).Code)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, post("/sync", 2000, false).Code)
+		assert.Equal(t, http.StatusCreated, post("/upload", 5000, false).Code)
+	})
+}
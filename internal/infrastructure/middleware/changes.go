@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+type ChangePublisher interface {
+	Publish(ctx context.Context, event entity.ChangeEvent) error
+}
+
+// ChangeFeed publishes a change event for the user when a handler marked
+// the request with httputil.MarkNotesChanged and it succeeded. The event
+// covers changes since the request started, so devices that synced before
+// it began know they have something to pull.
+func ChangeFeed(publisher ChangePublisher, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Note timestamps are stored with microsecond precision.
+		since := time.Now().UTC().Truncate(time.Microsecond)
+
+		c.Next()
+
+		if !httputil.NotesChanged(c) {
+			return
+		}
+		if status := c.Writer.Status(); status < 200 || status >= 300 {
+			return
+		}
+		userID, ok := c.Get(UserIDKey)
+		if !ok {
+			return
+		}
+
+		event := entity.ChangeEvent{
+			UserID:   userID.(uuid.UUID),
+			DeviceID: httputil.GetTokenDeviceID(c),
+			Since:    since,
+		}
+		if err := publisher.Publish(context.WithoutCancel(c.Request.Context()), event); err != nil {
+			logger.Warn("failed to publish change event", zap.Error(err), zap.String("request_id", c.GetString(RequestIDKey)))
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/middleware"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+type changePublisher struct {
+	events []entity.ChangeEvent
+}
+
+func (p *changePublisher) Publish(_ context.Context, event entity.ChangeEvent) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func TestChangeFeed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID, deviceID := uuid.New(), uuid.New()
+
+	setup := func(status int, mark bool) (*gin.Engine, *changePublisher) {
+		publisher := &changePublisher{}
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			c.Set(middleware.UserIDKey, userID)
+			c.Set(middleware.TokenDeviceIDKey, deviceID)
+		})
+		router.Use(middleware.ChangeFeed(publisher, zap.NewNop()))
+		router.POST("/notes", func(c *gin.Context) {
+			if mark {
+				httputil.MarkNotesChanged(c)
+			}
+			c.Status(status)
+		})
+		return router, publisher
+	}
+
+	t.Run("publishes marked changes from the token's device", func(t *testing.T) {
+		router, publisher := setup(http.StatusCreated, true)
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/notes", nil))
+
+		require.Len(t, publisher.events, 1)
+		assert.Equal(t, userID, publisher.events[0].UserID)
+		assert.Equal(t, deviceID, publisher.events[0].DeviceID)
+		assert.False(t, publisher.events[0].Since.IsZero())
+	})
+
+	t.Run("skips unmarked and failed requests", func(t *testing.T) {
+		for _, tc := range []struct {
+			status int
+			mark   bool
+		}{{http.StatusOK, false}, {http.StatusInternalServerError, true}} {
+			router, publisher := setup(tc.status, tc.mark)
+
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/notes", nil))
+
+			assert.Empty(t, publisher.events)
+		}
+	})
+}
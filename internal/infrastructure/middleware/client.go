@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+// Headers the apps send to identify themselves when their User-Agent can't
+// be set, as in browsers.
+const (
+	AppVersionHeader  = "X-App-Version"
+	AppPlatformHeader = "X-App-Platform"
+)
+
+type ClientRecorder interface {
+	Record(client entity.ClientInfo, deviceID string)
+}
+
+// ClientTelemetry counts each request under the client it came from.
+func ClientTelemetry(recorder ClientRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client := entity.ParseClientInfo(c.Request.UserAgent(), c.GetHeader(AppVersionHeader), c.GetHeader(AppPlatformHeader))
+		recorder.Record(client, c.GetHeader(DeviceIDHeader))
+		c.Next()
+	}
+}
@@ -0,0 +1,87 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/middleware"
+)
+
+type recordedClient struct {
+	client   entity.ClientInfo
+	deviceID string
+}
+
+type clientRecorder struct {
+	records []recordedClient
+}
+
+func (r *clientRecorder) Record(client entity.ClientInfo, deviceID string) {
+	r.records = append(r.records, recordedClient{client: client, deviceID: deviceID})
+}
+
+func TestClientTelemetry(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		userAgent string
+		headers   map[string]string
+		want      entity.ClientInfo
+	}{
+		{
+			name:      "parses the app user agent",
+			userAgent: "FieldNotes/2.3.1 (iOS 17.4.1; iPhone15,2)",
+			want:      entity.ClientInfo{Platform: entity.PlatformIOS, AppVersion: "2.3.1", OS: "iOS 17"},
+		},
+		{
+			name:      "maps Android to its platform",
+			userAgent: "FieldNotes/2.2.0 (Android 14; Pixel 8)",
+			want:      entity.ClientInfo{Platform: entity.PlatformAndroid, AppVersion: "2.2.0", OS: "Android 14"},
+		},
+		{
+			name:      "prefers the app headers",
+			userAgent: "Mozilla/5.0 (X11; Linux x86_64)",
+			headers:   map[string]string{middleware.AppVersionHeader: "2.4.0", middleware.AppPlatformHeader: "web"},
+			want:      entity.ClientInfo{Platform: entity.PlatformWeb, AppVersion: "2.4.0", OS: entity.ClientUnknown},
+		},
+		{
+			name:      "reports browsers as web",
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_4)",
+			want:      entity.ClientInfo{Platform: entity.PlatformWeb, AppVersion: entity.ClientUnknown, OS: entity.ClientUnknown},
+		},
+		{
+			name:      "collapses unrecognized values",
+			userAgent: "FieldNotes/not-a-version (PlanOS 9)",
+			headers:   map[string]string{middleware.AppPlatformHeader: "toaster"},
+			want:      entity.ClientInfo{Platform: entity.ClientUnknown, AppVersion: entity.ClientUnknown, OS: entity.ClientUnknown},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &clientRecorder{}
+			router := gin.New()
+			router.Use(middleware.ClientTelemetry(recorder))
+			router.GET("/notes", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/notes", nil)
+			req.Header.Set("User-Agent", tt.userAgent)
+			req.Header.Set(middleware.DeviceIDHeader, "device-1")
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			require.Len(t, recorder.records, 1)
+			assert.Equal(t, tt.want, recorder.records[0].client)
+			assert.Equal(t, "device-1", recorder.records[0].deviceID)
+		})
+	}
+}
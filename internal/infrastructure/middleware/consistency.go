@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/consistency"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+const (
+	VersionHeader    = "X-Version"
+	MinVersionHeader = "X-Min-Version"
+)
+
+type VersionSource interface {
+	Version(ctx context.Context, userID uuid.UUID) (int64, error)
+}
+
+// ReadYourWrites returns the user's write version in X-Version on successful
+// writes and lets reads go to a replica, which must have caught up with the
+// X-Min-Version the client sends. A malformed X-Min-Version keeps the read on
+// the primary. It must run after RequireAuth.
+func ReadYourWrites(versions VersionSource) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := httputil.GetUserID(c)
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead:
+			var minVersion int64
+			if header := c.GetHeader(MinVersionHeader); header != "" {
+				v, err := strconv.ParseInt(header, 10, 64)
+				if err != nil || v < 0 {
+					c.Next()
+					return
+				}
+				minVersion = v
+			}
+			ctx := consistency.WithReplicaRead(c.Request.Context(), consistency.ReplicaRead{
+				UserID:     userID,
+				MinVersion: minVersion,
+			})
+			c.Request = c.Request.WithContext(ctx)
+		case http.MethodOptions:
+		default:
+			c.Writer = &versionWriter{ResponseWriter: c.Writer, ctx: c.Request.Context(), userID: userID, versions: versions}
+		}
+
+		c.Next()
+	}
+}
+
+// versionWriter sets X-Version just before the status line is sent, since
+// headers can't be added once the handler has written the body.
+type versionWriter struct {
+	gin.ResponseWriter
+	ctx      context.Context
+	userID   uuid.UUID
+	versions VersionSource
+	stamped  bool
+}
+
+func (w *versionWriter) stamp(status int) {
+	if w.stamped || w.ResponseWriter.Written() {
+		return
+	}
+	w.stamped = true
+	if status >= http.StatusBadRequest {
+		return
+	}
+	version, err := w.versions.Version(w.ctx, w.userID)
+	if err != nil {
+		return
+	}
+	w.Header().Set(VersionHeader, strconv.FormatInt(version, 10))
+}
+
+func (w *versionWriter) WriteHeader(code int) {
+	w.stamp(code)
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *versionWriter) WriteHeaderNow() {
+	w.stamp(w.Status())
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *versionWriter) Write(data []byte) (int, error) {
+	w.stamp(w.Status())
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *versionWriter) WriteString(s string) (int, error) {
+	w.stamp(w.Status())
+	return w.ResponseWriter.WriteString(s)
+}
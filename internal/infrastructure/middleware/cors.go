@@ -1,15 +1,27 @@
 package middleware
 
 import (
+	"slices"
+
 	"github.com/gin-gonic/gin"
 )
 
-func CORS() gin.HandlerFunc {
+// CORS lets browsers on origins call the API; "*" allows any origin. Other
+// origins get no CORS headers, so browsers keep them from reading responses.
+func CORS(origins []string) gin.HandlerFunc {
+	anyOrigin := slices.Contains(origins, "*")
 	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
+		if anyOrigin {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else if len(origins) > 0 {
+			c.Header("Vary", "Origin")
+			if origin := c.GetHeader("Origin"); origin != "" && slices.Contains(origins, origin) {
+				c.Header("Access-Control-Allow-Origin", origin)
+			}
+		}
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-Request-ID")
-		c.Header("Access-Control-Expose-Headers", "X-Request-ID")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-Request-ID, X-Device-ID, X-Min-Version, Idempotency-Key")
+		c.Header("Access-Control-Expose-Headers", "X-Request-ID, X-Version, Idempotent-Replayed, Deprecation, Sunset, Link")
 		c.Header("Access-Control-Max-Age", "86400")
 
 		if c.Request.Method == "OPTIONS" {
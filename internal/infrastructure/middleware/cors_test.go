@@ -0,0 +1,44 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/middleware"
+)
+
+func TestCORS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		origins []string
+		origin  string
+		allowed string
+	}{
+		{"any origin", []string{"*"}, "https://elsewhere.example.com", "*"},
+		{"listed origin", []string{"https://app.example.com"}, "https://app.example.com", "https://app.example.com"},
+		{"unlisted origin", []string{"https://app.example.com"}, "https://elsewhere.example.com", ""},
+		{"no origins", nil, "https://app.example.com", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(middleware.CORS(tt.origins))
+			router.GET("/notes", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/notes", nil)
+			req.Header.Set("Origin", tt.origin)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.allowed, w.Header().Get("Access-Control-Allow-Origin"))
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+// DemoReadOnly rejects every request from the demo account that could change
+// data. It must run after RequireAuth.
+func DemoReadOnly(demoUserID uuid.UUID) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		if httputil.GetUserID(c) == demoUserID {
+			httputil.ErrorWithCode(c, http.StatusForbidden, httputil.CodeDemoReadOnly, "the demo account is read-only")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
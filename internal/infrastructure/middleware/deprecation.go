@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+type DeprecationRecorder interface {
+	RecordDeprecated(name string, client entity.ClientInfo)
+}
+
+type routeKey struct {
+	method string
+	route  string
+}
+
+type routeDeprecations struct {
+	endpoint *entity.Deprecation
+	fields   map[string]entity.Deprecation
+}
+
+// Deprecated answers requests that use a deprecated endpoint, or a
+// deprecated field reported by the handler with httputil.UseDeprecatedField,
+// with the Deprecation (RFC 9745) and Sunset (RFC 8594) headers and a Link
+// to the migration guide, and counts the use under the client it came from.
+// A nil recorder only sets the headers.
+func Deprecated(deprecations []entity.Deprecation, recorder DeprecationRecorder) gin.HandlerFunc {
+	routes := make(map[routeKey]*routeDeprecations)
+	for _, d := range deprecations {
+		key := routeKey{method: d.Method, route: d.Route}
+		r, ok := routes[key]
+		if !ok {
+			r = &routeDeprecations{fields: make(map[string]entity.Deprecation)}
+			routes[key] = r
+		}
+		if d.Field == "" {
+			r.endpoint = &d
+		} else {
+			r.fields[d.Field] = d
+		}
+	}
+
+	return func(c *gin.Context) {
+		r, ok := routes[routeKey{method: c.Request.Method, route: c.FullPath()}]
+		if !ok {
+			c.Next()
+			return
+		}
+
+		used := make(map[string]bool)
+		use := func(d entity.Deprecation) {
+			name := d.Name()
+			if used[name] {
+				return
+			}
+			used[name] = true
+			setDeprecationHeaders(c.Writer.Header(), d)
+			if recorder != nil {
+				client := entity.ParseClientInfo(c.Request.UserAgent(), c.GetHeader(AppVersionHeader), c.GetHeader(AppPlatformHeader))
+				recorder.RecordDeprecated(name, client)
+			}
+		}
+
+		if r.endpoint != nil {
+			use(*r.endpoint)
+		}
+		if len(r.fields) > 0 {
+			httputil.OnDeprecatedField(c, func(field string) {
+				if d, ok := r.fields[field]; ok {
+					use(d)
+				}
+			})
+		}
+		c.Next()
+	}
+}
+
+// setDeprecationHeaders adds d to the headers. When a request uses several
+// deprecations, the earliest dates are kept and every link is listed.
+func setDeprecationHeaders(h http.Header, d entity.Deprecation) {
+	current, err := strconv.ParseInt(strings.TrimPrefix(h.Get("Deprecation"), "@"), 10, 64)
+	if err != nil || d.Since.Unix() < current {
+		h.Set("Deprecation", "@"+strconv.FormatInt(d.Since.Unix(), 10))
+	}
+	if !d.Sunset.IsZero() {
+		if current, err := http.ParseTime(h.Get("Sunset")); err != nil || d.Sunset.Before(current) {
+			h.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+		}
+	}
+	if d.Link != "" {
+		h.Add("Link", "<"+d.Link+`>; rel="deprecation"`)
+	}
+}
@@ -0,0 +1,103 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/middleware"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+type deprecationUses struct {
+	names   []string
+	clients []entity.ClientInfo
+}
+
+func (u *deprecationUses) RecordDeprecated(name string, client entity.ClientInfo) {
+	u.names = append(u.names, name)
+	u.clients = append(u.clients, client)
+}
+
+func TestDeprecated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	since := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, 5, 1, 0, 0, 0, 0, time.UTC)
+	deprecations := []entity.Deprecation{
+		{Method: http.MethodGet, Route: "/notes/:id/legacy", Since: since, Sunset: sunset, Link: "https://docs.example.com/legacy"},
+		{Method: http.MethodPost, Route: "/sync", Field: "tags", Since: since.AddDate(0, 1, 0)},
+		{Method: http.MethodPost, Route: "/sync", Field: "altitude", Since: since, Sunset: sunset},
+	}
+
+	setup := func() (*gin.Engine, *deprecationUses) {
+		uses := &deprecationUses{}
+		router := gin.New()
+		router.Use(middleware.Deprecated(deprecations, uses))
+		ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+		router.GET("/notes/:id/legacy", ok)
+		router.GET("/notes/:id", ok)
+		router.POST("/sync", func(c *gin.Context) {
+			for _, field := range c.QueryArray("field") {
+				httputil.UseDeprecatedField(c, field)
+			}
+			c.Status(http.StatusOK)
+		})
+		return router, uses
+	}
+
+	serve := func(router *gin.Engine, method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("User-Agent", "FieldNotes/2.3.1 (iOS 17.4.1; iPhone15,2)")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("flags deprecated endpoints and counts their use", func(t *testing.T) {
+		router, uses := setup()
+
+		w := serve(router, http.MethodGet, "/notes/1/legacy")
+
+		assert.Equal(t, "@1793491200", w.Header().Get("Deprecation"))
+		assert.Equal(t, "Sat, 01 May 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+		assert.Equal(t, `<https://docs.example.com/legacy>; rel="deprecation"`, w.Header().Get("Link"))
+		assert.Equal(t, []string{"GET /notes/:id/legacy"}, uses.names)
+		assert.Equal(t, entity.PlatformIOS, uses.clients[0].Platform)
+		assert.Equal(t, "2.3.1", uses.clients[0].AppVersion)
+	})
+
+	t.Run("leaves other routes alone", func(t *testing.T) {
+		router, uses := setup()
+
+		w := serve(router, http.MethodGet, "/notes/1")
+
+		assert.Empty(t, w.Header().Get("Deprecation"))
+		assert.Empty(t, uses.names)
+	})
+
+	t.Run("flags deprecated fields the handler reports", func(t *testing.T) {
+		router, uses := setup()
+
+		w := serve(router, http.MethodPost, "/sync?field=tags&field=altitude&field=tags&field=title")
+
+		// The earliest dates of the fields used are kept.
+		assert.Equal(t, "@1793491200", w.Header().Get("Deprecation"))
+		assert.Equal(t, "Sat, 01 May 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+		assert.Equal(t, []string{"POST /sync tags", "POST /sync altitude"}, uses.names)
+	})
+
+	t.Run("flags nothing when no deprecated field is used", func(t *testing.T) {
+		router, uses := setup()
+
+		w := serve(router, http.MethodPost, "/sync")
+
+		assert.Empty(t, w.Header().Get("Deprecation"))
+		assert.Empty(t, uses.names)
+	})
+}
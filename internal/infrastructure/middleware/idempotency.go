@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/config"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/apperror"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayHeader marks responses replayed from an earlier request.
+	IdempotentReplayHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// replayedHeaders are the response headers kept with a response; the rest,
+// such as X-Request-ID, belong to the request that produced it.
+var replayedHeaders = []string{"Content-Type", "Content-Disposition", "Location"}
+
+// Idempotency replays the response of a write retried with the same
+// Idempotency-Key instead of running it again, so clients on flaky networks
+// can retry any write, uploads included, without creating duplicates.
+type Idempotency struct {
+	store IdempotencyStore
+	cfg   config.IdempotencyConfig
+}
+
+func NewIdempotency(store IdempotencyStore, cfg config.IdempotencyConfig) *Idempotency {
+	return &Idempotency{store: store, cfg: cfg}
+}
+
+// Handle must run after authentication; keys are scoped to the user. Reads
+// and requests without the header pass through.
+func (i *Idempotency) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader(IdempotencyKeyHeader)
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			header = ""
+		}
+		if header == "" {
+			c.Next()
+			return
+		}
+		if len(header) > maxIdempotencyKeyLength {
+			httputil.Abort(c, apperror.New(http.StatusBadRequest, httputil.CodeValidationError,
+				"Idempotency-Key must be at most 255 characters"))
+			return
+		}
+
+		ctx := c.Request.Context()
+		key := "idempotency:" + httputil.GetUserID(c).String() + ":" + header
+		request := c.Request.Method + " " + c.Request.URL.Path
+
+		claimed, stored, err := i.store.Claim(ctx, key, i.cfg.LockTTL)
+		if err != nil {
+			// Store errors let the request run, as the rate limiter does.
+			c.Next()
+			return
+		}
+		if !claimed {
+			i.answerRetry(c, request, stored)
+			return
+		}
+
+		// Settled on a fresh context so a cancelled request still frees its key.
+		ctx = context.WithoutCancel(ctx)
+		settled := false
+		defer func() {
+			if !settled {
+				_ = i.store.Release(ctx, key)
+			}
+		}()
+
+		w := &recordingWriter{ResponseWriter: c.Writer, limit: i.cfg.MaxResponseBytes}
+		c.Writer = w
+		c.Next()
+
+		// Failures the client should retry, and responses too large to
+		// keep, run again on the next attempt.
+		status := w.Status()
+		if !w.answered || status >= http.StatusInternalServerError || status == http.StatusTooManyRequests || w.overflow {
+			return
+		}
+
+		resp := StoredResponse{Request: request, Status: status, Header: http.Header{}, Body: w.body.Bytes()}
+		for _, name := range replayedHeaders {
+			if v := w.Header().Values(name); len(v) > 0 {
+				resp.Header[name] = v
+			}
+		}
+		settled = i.store.Save(ctx, key, resp, i.cfg.TTL) == nil
+	}
+}
+
+func (i *Idempotency) answerRetry(c *gin.Context, request string, stored *StoredResponse) {
+	switch {
+	case stored == nil:
+		c.Header("Retry-After", "1")
+		httputil.Abort(c, apperror.New(http.StatusConflict, httputil.CodeIdempotencyBusy,
+			"a request with this Idempotency-Key is still running"))
+	case stored.Request != request:
+		httputil.Abort(c, apperror.New(http.StatusUnprocessableEntity, httputil.CodeIdempotencyMismatch,
+			"this Idempotency-Key was used for "+stored.Request))
+	default:
+		for name, v := range stored.Header {
+			c.Writer.Header()[name] = v
+		}
+		c.Header(IdempotentReplayHeader, "true")
+		c.Status(stored.Status)
+		_, _ = c.Writer.Write(stored.Body)
+		c.Abort()
+	}
+}
+
+// recordingWriter keeps a copy of the body, up to limit bytes, as it is
+// written. answered is set once the handler sets a status or writes, which
+// a handler that timed out never does.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	limit    int
+	overflow bool
+	answered bool
+}
+
+func (w *recordingWriter) WriteHeader(code int) {
+	w.answered = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *recordingWriter) keep(data []byte) {
+	w.answered = true
+	if w.overflow || w.body.Len()+len(data) > w.limit {
+		w.overflow = true
+		w.body.Reset()
+		return
+	}
+	w.body.Write(data)
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.keep(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.keep([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// StoredResponse is a response kept for replay under an idempotency key.
+type StoredResponse struct {
+	// Request is the method and path the key was first used for.
+	Request string      `json:"request"`
+	Status  int         `json:"status"`
+	Header  http.Header `json:"header,omitempty"`
+	Body    []byte      `json:"body,omitempty"`
+}
+
+// IdempotencyStore keeps the responses of requests sent with an
+// Idempotency-Key.
+type IdempotencyStore interface {
+	// Claim takes key for a request for ttl. It reports false when the key
+	// is taken, with the stored response, or nil while the request that
+	// holds it is still running.
+	Claim(ctx context.Context, key string, ttl time.Duration) (bool, *StoredResponse, error)
+	// Save stores the response under a claimed key for ttl.
+	Save(ctx context.Context, key string, resp StoredResponse, ttl time.Duration) error
+	// Release gives up a claim without a response, so a retry runs again.
+	Release(ctx context.Context, key string) error
+}
+
+// NewIdempotencyStore returns a Redis backed store shared by every instance,
+// or an in-process one when client is nil.
+func NewIdempotencyStore(client *redis.Client, cleanupInterval time.Duration) IdempotencyStore {
+	if client == nil {
+		return NewMemoryIdempotencyStore(cleanupInterval)
+	}
+	return NewRedisIdempotencyStore(client)
+}
+
+type RedisIdempotencyStore struct {
+	client *redis.Client
+}
+
+func NewRedisIdempotencyStore(client *redis.Client) *RedisIdempotencyStore {
+	return &RedisIdempotencyStore{client: client}
+}
+
+// claimKey sets an empty value, the running marker, unless the key exists,
+// and returns the value it found.
+var claimKey = redis.NewScript(`
+	local stored = redis.call("GET", KEYS[1])
+	if stored then
+		return stored
+	end
+	redis.call("SET", KEYS[1], "", "PX", ARGV[1])
+	return false
+`)
+
+func (s *RedisIdempotencyStore) Claim(ctx context.Context, key string, ttl time.Duration) (bool, *StoredResponse, error) {
+	stored, err := claimKey.Run(ctx, s.client, []string{key}, ttl.Milliseconds()).Text()
+	if errors.Is(err, redis.Nil) {
+		return true, nil, nil
+	}
+	if err != nil {
+		return false, nil, err
+	}
+	if stored == "" {
+		return false, nil, nil
+	}
+
+	var resp StoredResponse
+	if err := json.Unmarshal([]byte(stored), &resp); err != nil {
+		return false, nil, fmt.Errorf("decoding stored response: %w", err)
+	}
+	return false, &resp, nil
+}
+
+func (s *RedisIdempotencyStore) Save(ctx context.Context, key string, resp StoredResponse, ttl time.Duration) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("encoding stored response: %w", err)
+	}
+	return s.client.Set(ctx, key, data, ttl).Err()
+}
+
+func (s *RedisIdempotencyStore) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, key).Err()
+}
+
+type idempotencyEntry struct {
+	resp      *StoredResponse
+	expiresAt time.Time
+}
+
+// MemoryIdempotencyStore keeps responses per process. With several
+// instances, a retry that reaches another one runs again.
+type MemoryIdempotencyStore struct {
+	mu              sync.Mutex
+	entries         map[string]idempotencyEntry
+	cleanupInterval time.Duration
+	lastCleanup     time.Time
+}
+
+func NewMemoryIdempotencyStore(cleanupInterval time.Duration) *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		entries:         make(map[string]idempotencyEntry),
+		cleanupInterval: cleanupInterval,
+	}
+}
+
+func (s *MemoryIdempotencyStore) Claim(_ context.Context, key string, ttl time.Duration) (bool, *StoredResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastCleanup) >= s.cleanupInterval {
+		for k, e := range s.entries {
+			if !now.Before(e.expiresAt) {
+				delete(s.entries, k)
+			}
+		}
+		s.lastCleanup = now
+	}
+
+	if e, ok := s.entries[key]; ok && now.Before(e.expiresAt) {
+		return false, e.resp, nil
+	}
+	s.entries[key] = idempotencyEntry{expiresAt: now.Add(ttl)}
+	return true, nil, nil
+}
+
+func (s *MemoryIdempotencyStore) Save(_ context.Context, key string, resp StoredResponse, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = idempotencyEntry{resp: &resp, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
@@ -0,0 +1,151 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/config"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/middleware"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+func setupIdempotencyRouter(store middleware.IdempotencyStore, userID uuid.UUID, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	idempotency := middleware.NewIdempotency(store, config.IdempotencyConfig{
+		TTL: time.Hour, LockTTL: time.Minute, MaxResponseBytes: 1024,
+	})
+	router := gin.New()
+	router.Use(func(c *gin.Context) { c.Set("user_id", userID) }, idempotency.Handle())
+	router.POST("/notes", handler)
+	router.POST("/notes/:id/restore", handler)
+	return router
+}
+
+func post(router *gin.Engine, path, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	if key != "" {
+		req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency(t *testing.T) {
+	t.Run("replays the response of a retried write", func(t *testing.T) {
+		runs := 0
+		router := setupIdempotencyRouter(middleware.NewMemoryIdempotencyStore(time.Minute), uuid.New(), func(c *gin.Context) {
+			runs++
+			c.Header("Location", "/notes/1")
+			c.JSON(http.StatusCreated, gin.H{"run": runs})
+		})
+
+		first := post(router, "/notes", "key-1")
+		retry := post(router, "/notes", "key-1")
+
+		assert.Equal(t, 1, runs)
+		assert.Equal(t, http.StatusCreated, retry.Code)
+		assert.JSONEq(t, first.Body.String(), retry.Body.String())
+		assert.Equal(t, "/notes/1", retry.Header().Get("Location"))
+		assert.Equal(t, "true", retry.Header().Get(middleware.IdempotentReplayHeader))
+		assert.Empty(t, first.Header().Get(middleware.IdempotentReplayHeader))
+	})
+
+	t.Run("replays responses without a body", func(t *testing.T) {
+		runs := 0
+		router := setupIdempotencyRouter(middleware.NewMemoryIdempotencyStore(time.Minute), uuid.New(), func(c *gin.Context) {
+			runs++
+			c.Status(http.StatusNoContent)
+		})
+
+		post(router, "/notes", "key-1")
+
+		assert.Equal(t, http.StatusNoContent, post(router, "/notes", "key-1").Code)
+		assert.Equal(t, 1, runs)
+	})
+
+	t.Run("runs writes without a key or with another one", func(t *testing.T) {
+		runs := 0
+		router := setupIdempotencyRouter(middleware.NewMemoryIdempotencyStore(time.Minute), uuid.New(), func(c *gin.Context) {
+			runs++
+			c.Status(http.StatusCreated)
+		})
+
+		post(router, "/notes", "")
+		post(router, "/notes", "")
+		post(router, "/notes", "key-1")
+		post(router, "/notes", "key-2")
+
+		assert.Equal(t, 4, runs)
+	})
+
+	t.Run("scopes keys to the user", func(t *testing.T) {
+		store := middleware.NewMemoryIdempotencyStore(time.Minute)
+		runs := 0
+		handler := func(c *gin.Context) {
+			runs++
+			c.Status(http.StatusCreated)
+		}
+
+		post(setupIdempotencyRouter(store, uuid.New(), handler), "/notes", "key-1")
+		post(setupIdempotencyRouter(store, uuid.New(), handler), "/notes", "key-1")
+
+		assert.Equal(t, 2, runs)
+	})
+
+	t.Run("runs failed writes again", func(t *testing.T) {
+		runs := 0
+		router := setupIdempotencyRouter(middleware.NewMemoryIdempotencyStore(time.Minute), uuid.New(), func(c *gin.Context) {
+			runs++
+			if runs == 1 {
+				httputil.InternalError(c)
+				return
+			}
+			c.Status(http.StatusCreated)
+		})
+
+		assert.Equal(t, http.StatusInternalServerError, post(router, "/notes", "key-1").Code)
+		assert.Equal(t, http.StatusCreated, post(router, "/notes", "key-1").Code)
+		assert.Equal(t, 2, runs)
+	})
+
+	t.Run("rejects a key reused for another request", func(t *testing.T) {
+		router := setupIdempotencyRouter(middleware.NewMemoryIdempotencyStore(time.Minute), uuid.New(), func(c *gin.Context) {
+			c.Status(http.StatusCreated)
+		})
+
+		post(router, "/notes", "key-1")
+		w := post(router, "/notes/1/restore", "key-1")
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), httputil.CodeIdempotencyMismatch)
+	})
+
+	t.Run("rejects a retry while the first request runs", func(t *testing.T) {
+		started := make(chan struct{})
+		finish := make(chan struct{})
+		router := setupIdempotencyRouter(middleware.NewMemoryIdempotencyStore(time.Minute), uuid.New(), func(c *gin.Context) {
+			close(started)
+			<-finish
+			c.Status(http.StatusCreated)
+		})
+
+		done := make(chan int)
+		go func() { done <- post(router, "/notes", "key-1").Code }()
+		<-started
+
+		w := post(router, "/notes", "key-1")
+		close(finish)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "1", w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), httputil.CodeIdempotencyBusy)
+		assert.Equal(t, http.StatusCreated, <-done)
+	})
+}
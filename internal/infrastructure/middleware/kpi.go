@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+type KPIRecorder interface {
+	Record(event string, n int64)
+}
+
+// KPIOutcome names the events counted for every request to a route and for
+// those that fail.
+type KPIOutcome struct {
+	Total   string
+	Failure string
+}
+
+// KPI records the KPI events handlers count on each request and, for the
+// routes in outcomes keyed by "METHOD /path", one total event per request
+// and a failure event when the response is not 2xx.
+func KPI(recorder KPIRecorder, outcomes map[string]KPIOutcome) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		for event, n := range httputil.KPICounts(c) {
+			recorder.Record(event, n)
+		}
+
+		outcome, ok := outcomes[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			return
+		}
+		recorder.Record(outcome.Total, 1)
+		if status := c.Writer.Status(); status < 200 || status >= 300 {
+			recorder.Record(outcome.Failure, 1)
+		}
+	}
+}
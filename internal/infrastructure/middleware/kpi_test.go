@@ -0,0 +1,66 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/middleware"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+type kpiRecorder struct {
+	events map[string]int64
+}
+
+func (r *kpiRecorder) Record(event string, n int64) {
+	r.events[event] += n
+}
+
+func TestKPI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	setup := func(status int) (*gin.Engine, *kpiRecorder) {
+		recorder := &kpiRecorder{events: make(map[string]int64)}
+		router := gin.New()
+		router.Use(middleware.KPI(recorder, map[string]middleware.KPIOutcome{
+			"POST /sync": {Total: "syncs", Failure: "sync_failures"},
+		}))
+		router.POST("/sync", func(c *gin.Context) {
+			httputil.CountKPI(c, "notes_pushed", 3)
+			c.Status(status)
+		})
+		router.GET("/sync", func(c *gin.Context) {
+			c.Status(status)
+		})
+		return router, recorder
+	}
+
+	t.Run("records handler counts and a successful outcome", func(t *testing.T) {
+		router, recorder := setup(http.StatusOK)
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/sync", nil))
+
+		assert.Equal(t, map[string]int64{"notes_pushed": 3, "syncs": 1}, recorder.events)
+	})
+
+	t.Run("records a failure for non-2xx responses", func(t *testing.T) {
+		router, recorder := setup(http.StatusInternalServerError)
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/sync", nil))
+
+		assert.Equal(t, int64(1), recorder.events["syncs"])
+		assert.Equal(t, int64(1), recorder.events["sync_failures"])
+	})
+
+	t.Run("ignores routes without an outcome", func(t *testing.T) {
+		router, recorder := setup(http.StatusInternalServerError)
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/sync", nil))
+
+		assert.Empty(t, recorder.events)
+	})
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/observability"
+)
+
+// LatencyBudget traces the request, so the storage and image operations it
+// runs are collected, and records its duration as op. When it takes longer
+// than budget it is counted and logged with the time spent in each
+// operation. A zero budget only records.
+func LatencyBudget(op string, budget time.Duration, latencies *observability.Latencies, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		parent := c.Request.Context()
+		ctx, trace := observability.WithTrace(parent)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		elapsed := time.Since(start)
+		// Recorded outside the trace, which lists only the inner operations.
+		latencies.Observe(parent, op, elapsed)
+		if budget <= 0 || elapsed <= budget {
+			return
+		}
+
+		latencies.OverBudget(op)
+		fields := []zap.Field{
+			zap.String("operation", op),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status", c.Writer.Status()),
+			zap.Int64("bytes_in", c.Request.ContentLength),
+			zap.Duration("latency", elapsed),
+			zap.Duration("budget", budget),
+			trace.Field(),
+		}
+		if requestID := c.GetString(RequestIDKey); requestID != "" {
+			fields = append(fields, zap.String("request_id", requestID))
+		}
+		if userID, exists := c.Get(UserIDKey); exists {
+			fields = append(fields, zap.String("user_id", userID.(uuid.UUID).String()))
+		}
+		logger.Warn("request over latency budget", fields...)
+	}
+}
@@ -0,0 +1,84 @@
+package middleware_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/middleware"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/observability"
+)
+
+func TestLatencyBudget(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	setup := func(budget time.Duration) (*gin.Engine, *observability.Latencies, *observer.ObservedLogs) {
+		core, logs := observer.New(zapcore.WarnLevel)
+		latencies := observability.NewLatencies()
+		router := gin.New()
+		router.Use(middleware.LatencyBudget(observability.OpUpload, budget, latencies, zap.New(core)))
+		router.POST("/upload/photo", func(c *gin.Context) {
+			latencies.Observe(c.Request.Context(), observability.OpImageDecode, 20*time.Millisecond)
+			latencies.Observe(c.Request.Context(), observability.OpS3PutObject, 30*time.Millisecond)
+			time.Sleep(5 * time.Millisecond)
+			c.Status(http.StatusCreated)
+		})
+		return router, latencies, logs
+	}
+
+	upload := func(router *gin.Engine) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/upload/photo", nil))
+		require.Equal(t, http.StatusCreated, w.Code)
+	}
+
+	metrics := func(latencies *observability.Latencies) string {
+		var buf bytes.Buffer
+		require.NoError(t, latencies.WritePrometheus(&buf))
+		return buf.String()
+	}
+
+	t.Run("logs slow requests with their operations", func(t *testing.T) {
+		router, latencies, logs := setup(time.Millisecond)
+
+		upload(router)
+
+		entries := logs.All()
+		require.Len(t, entries, 1)
+		fields := entries[0].ContextMap()
+		assert.Equal(t, "/upload/photo", fields["path"])
+		assert.Equal(t, map[string]any{
+			observability.OpImageDecode: 20 * time.Millisecond,
+			observability.OpS3PutObject: 30 * time.Millisecond,
+		}, fields["spans"])
+		assert.Contains(t, metrics(latencies), `fieldnotes_operation_over_budget_total{operation="upload"} 1`)
+		assert.Contains(t, metrics(latencies), `fieldnotes_operation_duration_seconds_count{operation="upload"} 1`)
+	})
+
+	t.Run("only records requests within the budget", func(t *testing.T) {
+		router, latencies, logs := setup(time.Minute)
+
+		upload(router)
+
+		assert.Zero(t, logs.Len())
+		assert.NotContains(t, metrics(latencies), "over_budget_total{")
+		assert.Contains(t, metrics(latencies), `fieldnotes_operation_duration_seconds_count{operation="upload"} 1`)
+	})
+
+	t.Run("never logs without a budget", func(t *testing.T) {
+		router, _, logs := setup(0)
+
+		upload(router)
+
+		assert.Zero(t, logs.Len())
+	})
+}
@@ -37,6 +37,10 @@ func Logger(logger *zap.Logger) gin.HandlerFunc {
 			fields = append(fields, zap.String("user_id", userID.(uuid.UUID).String()))
 		}
 
+		if exemption := c.GetString(RateLimitExemptionKey); exemption != "" {
+			fields = append(fields, zap.String("rate_limit_exemption", exemption))
+		}
+
 		if len(c.Errors) > 0 {
 			fields = append(fields, zap.String("errors", c.Errors.String()))
 		}
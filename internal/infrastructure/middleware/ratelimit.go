@@ -2,39 +2,129 @@ package middleware
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
 	"net/http"
+	"net/netip"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/redis/go-redis/v9"
 
 	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/config"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/apperror"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
 )
 
+const (
+	APIKeyHeader = "X-API-Key"
+
+	// RateLimitExemptionKey holds the name of the exemption that let a request
+	// bypass rate limiting, for request logs.
+	RateLimitExemptionKey = "rate_limit_exemption"
+)
+
+// What the rate limiter does with requests while its store is unreachable.
+const (
+	// RateLimitFallbackMemory limits each instance on its own with a token
+	// bucket of RATE_LIMIT_BURST_SIZE requests.
+	RateLimitFallbackMemory = "memory"
+	// RateLimitFallbackOpen lets every request through.
+	RateLimitFallbackOpen = "open"
+	// RateLimitFallbackClosed rejects every request.
+	RateLimitFallbackClosed = "closed"
+)
+
+type exemptAPIKey struct {
+	name string
+	key  []byte
+}
+
 type RateLimiter struct {
-	client         *redis.Client
+	store          RateLimitStore
 	requestsPerMin int
 	windowSize     time.Duration
+	exemptNets     []netip.Prefix
+	exemptKeys     []exemptAPIKey
+	fallback       string
+	// bucket limits requests when fallback is RateLimitFallbackMemory.
+	bucket *TokenBucket
 }
 
-func NewRateLimiter(client *redis.Client, cfg config.RateLimitConfig) *RateLimiter {
-	return &RateLimiter{
-		client:         client,
+func NewRateLimiter(store RateLimitStore, cfg config.RateLimitConfig) (*RateLimiter, error) {
+	rl := &RateLimiter{
+		store:          store,
 		requestsPerMin: cfg.RequestsPerMin,
 		windowSize:     time.Minute,
+		fallback:       cfg.Fallback,
+	}
+
+	switch cfg.Fallback {
+	case RateLimitFallbackMemory:
+		rl.bucket = NewTokenBucket(cfg.RequestsPerMin, cfg.BurstSize, cfg.CleanupInterval)
+	case RateLimitFallbackOpen, RateLimitFallbackClosed:
+	default:
+		return nil, fmt.Errorf("unknown rate limit fallback %q", cfg.Fallback)
+	}
+
+	for _, cidr := range cfg.ExemptCIDRs {
+		prefix, err := parsePrefix(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, fmt.Errorf("parsing rate limit exemption %q: %w", cidr, err)
+		}
+		rl.exemptNets = append(rl.exemptNets, prefix)
+	}
+
+	for name, key := range cfg.ExemptAPIKeys {
+		if key == "" {
+			return nil, fmt.Errorf("rate limit exemption %q has an empty api key", name)
+		}
+		rl.exemptKeys = append(rl.exemptKeys, exemptAPIKey{name: name, key: []byte(key)})
+	}
+
+	return rl, nil
+}
+
+// parsePrefix accepts both CIDR notation and bare addresses.
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		return prefix.Masked(), err
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
 	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
 }
 
 func (rl *RateLimiter) Limit() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := c.Request.Context()
+
+		if name, ok := rl.exemption(c); ok {
+			c.Set(RateLimitExemptionKey, name)
+			// Audit counter is best effort; a Redis hiccup must not block the caller.
+			_ = rl.store.CountExemption(ctx, name)
+			c.Next()
+			return
+		}
+
 		key := fmt.Sprintf("ratelimit:%s", c.ClientIP())
 
 		allowed, remaining, err := rl.isAllowed(ctx, key)
 		if err != nil {
-			c.Next()
-			return
+			switch rl.fallback {
+			case RateLimitFallbackOpen:
+				c.Next()
+				return
+			case RateLimitFallbackClosed:
+				c.Header("Retry-After", "60")
+				httputil.Abort(c, apperror.New(http.StatusServiceUnavailable, httputil.CodeRateLimitDown,
+					"rate limiting is unavailable, please try again later"))
+				return
+			}
+			allowed, remaining = rl.bucket.Allow(key)
 		}
 
 		c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", rl.requestsPerMin))
@@ -42,10 +132,8 @@ func (rl *RateLimiter) Limit() gin.HandlerFunc {
 
 		if !allowed {
 			c.Header("Retry-After", "60")
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"code":    "RATE_LIMITED",
-				"message": "too many requests, please try again later",
-			})
+			httputil.Abort(c, apperror.New(http.StatusTooManyRequests, httputil.CodeRateLimited,
+				"too many requests, please try again later"))
 			return
 		}
 
@@ -53,28 +141,38 @@ func (rl *RateLimiter) Limit() gin.HandlerFunc {
 	}
 }
 
-func (rl *RateLimiter) isAllowed(ctx context.Context, key string) (bool, int, error) {
-	now := time.Now().UnixMilli()
-	windowStart := now - rl.windowSize.Milliseconds()
-
-	pipe := rl.client.Pipeline()
-
-	pipe.ZRemRangeByScore(ctx, key, "0", fmt.Sprintf("%d", windowStart))
-
-	pipe.ZAdd(ctx, key, redis.Z{
-		Score:  float64(now),
-		Member: now,
-	})
+// exemption reports the name of the exemption matching the request, if any.
+// API keys are checked first so internal jobs are attributed by name.
+func (rl *RateLimiter) exemption(c *gin.Context) (string, bool) {
+	if apiKey := c.GetHeader(APIKeyHeader); apiKey != "" {
+		for _, k := range rl.exemptKeys {
+			if subtle.ConstantTimeCompare([]byte(apiKey), k.key) == 1 {
+				return "key:" + k.name, true
+			}
+		}
+	}
 
-	countCmd := pipe.ZCard(ctx, key)
+	if len(rl.exemptNets) > 0 {
+		addr, err := netip.ParseAddr(c.ClientIP())
+		if err == nil {
+			addr = addr.Unmap()
+			for _, prefix := range rl.exemptNets {
+				if prefix.Contains(addr) {
+					return "cidr:" + prefix.String(), true
+				}
+			}
+		}
+	}
 
-	pipe.Expire(ctx, key, rl.windowSize)
+	return "", false
+}
 
-	if _, err := pipe.Exec(ctx); err != nil {
+func (rl *RateLimiter) isAllowed(ctx context.Context, key string) (bool, int, error) {
+	count, err := rl.store.Hit(ctx, key, rl.windowSize)
+	if err != nil {
 		return true, rl.requestsPerMin, err
 	}
 
-	count := int(countCmd.Val())
 	remaining := rl.requestsPerMin - count
 	if remaining < 0 {
 		remaining = 0
@@ -0,0 +1,196 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// exemptionCountersKey is a Redis hash of exemption name to bypassed requests.
+const exemptionCountersKey = "ratelimit:exemptions"
+
+// RateLimitStore keeps the sliding window of requests per client.
+type RateLimitStore interface {
+	// Hit records a request for key and returns how many requests it made
+	// within the window, this one included.
+	Hit(ctx context.Context, key string, window time.Duration) (int, error)
+	// CountExemption records a request that bypassed limiting, for auditing.
+	CountExemption(ctx context.Context, name string) error
+}
+
+// UploadLimitStore counts a user's uploads per window and in flight.
+type UploadLimitStore interface {
+	// Hit records an upload attempt, as RateLimitStore.Hit does.
+	Hit(ctx context.Context, key string, window time.Duration) (int, error)
+	// Acquire takes an upload slot and returns how many are held, this one
+	// included. Slots expire after ttl so a crashed instance cannot leak them.
+	Acquire(ctx context.Context, key string, ttl time.Duration) (int, error)
+	// Release gives back a slot taken by Acquire.
+	Release(ctx context.Context, key string) error
+}
+
+// NewRateLimitStore returns a Redis backed store shared by every instance, or
+// an in-process one when client is nil.
+func NewRateLimitStore(client *redis.Client, cleanupInterval time.Duration) RateLimitStore {
+	if client == nil {
+		return NewMemoryRateLimitStore(cleanupInterval)
+	}
+	return NewRedisRateLimitStore(client)
+}
+
+// NewUploadLimitStore returns a Redis backed store shared by every instance,
+// or an in-process one when client is nil.
+func NewUploadLimitStore(client *redis.Client, cleanupInterval time.Duration) UploadLimitStore {
+	if client == nil {
+		return NewMemoryRateLimitStore(cleanupInterval)
+	}
+	return NewRedisRateLimitStore(client)
+}
+
+type RedisRateLimitStore struct {
+	client *redis.Client
+}
+
+func NewRedisRateLimitStore(client *redis.Client) *RedisRateLimitStore {
+	return &RedisRateLimitStore{client: client}
+}
+
+func (s *RedisRateLimitStore) Hit(ctx context.Context, key string, window time.Duration) (int, error) {
+	now := time.Now().UnixMilli()
+	windowStart := now - window.Milliseconds()
+
+	pipe := s.client.Pipeline()
+
+	pipe.ZRemRangeByScore(ctx, key, "0", fmt.Sprintf("%d", windowStart))
+
+	pipe.ZAdd(ctx, key, redis.Z{
+		Score:  float64(now),
+		Member: now,
+	})
+
+	countCmd := pipe.ZCard(ctx, key)
+
+	pipe.Expire(ctx, key, window)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	return int(countCmd.Val()), nil
+}
+
+func (s *RedisRateLimitStore) CountExemption(ctx context.Context, name string) error {
+	return s.client.HIncrBy(ctx, exemptionCountersKey, name, 1).Err()
+}
+
+func (s *RedisRateLimitStore) Acquire(ctx context.Context, key string, ttl time.Duration) (int, error) {
+	return acquireSlot.Run(ctx, s.client, []string{key}, ttl.Milliseconds()).Int()
+}
+
+func (s *RedisRateLimitStore) Release(ctx context.Context, key string) error {
+	// Never go below zero if the key expired while the upload was running.
+	return releaseSlot.Run(ctx, s.client, []string{key}).Err()
+}
+
+// acquireSlot sets the TTL only when the counter is created, so a steady
+// stream of uploads cannot keep slots leaked by a crashed instance alive.
+var acquireSlot = redis.NewScript(`
+	local active = redis.call("INCR", KEYS[1])
+	if active == 1 then
+		redis.call("PEXPIRE", KEYS[1], ARGV[1])
+	end
+	return active
+`)
+
+var releaseSlot = redis.NewScript(`
+	if tonumber(redis.call("GET", KEYS[1]) or "0") > 0 then
+		return redis.call("DECR", KEYS[1])
+	end
+	return 0
+`)
+
+// MemoryRateLimitStore limits per process. With several instances behind a
+// load balancer each one enforces the limit on its own.
+type MemoryRateLimitStore struct {
+	mu              sync.Mutex
+	hits            map[string][]time.Time
+	exemptions      map[string]int64
+	active          map[string]int
+	cleanupInterval time.Duration
+	lastCleanup     time.Time
+}
+
+func NewMemoryRateLimitStore(cleanupInterval time.Duration) *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		hits:            make(map[string][]time.Time),
+		exemptions:      make(map[string]int64),
+		active:          make(map[string]int),
+		cleanupInterval: cleanupInterval,
+	}
+}
+
+func (s *MemoryRateLimitStore) Hit(_ context.Context, key string, window time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	windowStart := now.Add(-window)
+
+	hits := append(inWindow(s.hits[key], windowStart), now)
+	s.hits[key] = hits
+
+	// Clients that went quiet would otherwise keep their entry forever.
+	if now.Sub(s.lastCleanup) >= s.cleanupInterval {
+		for k, v := range s.hits {
+			if v = inWindow(v, windowStart); len(v) == 0 {
+				delete(s.hits, k)
+			} else {
+				s.hits[k] = v
+			}
+		}
+		s.lastCleanup = now
+	}
+
+	return len(hits), nil
+}
+
+func (s *MemoryRateLimitStore) CountExemption(_ context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.exemptions[name]++
+	return nil
+}
+
+// Acquire ignores ttl: slots held in process die with it.
+func (s *MemoryRateLimitStore) Acquire(_ context.Context, key string, _ time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.active[key]++
+	return s.active[key], nil
+}
+
+func (s *MemoryRateLimitStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.active[key] <= 1 {
+		delete(s.active, key)
+	} else {
+		s.active[key]--
+	}
+	return nil
+}
+
+// inWindow drops the hits older than windowStart; hits are in order.
+func inWindow(hits []time.Time, windowStart time.Time) []time.Time {
+	i := 0
+	for i < len(hits) && !hits[i].After(windowStart) {
+		i++
+	}
+	return hits[i:]
+}
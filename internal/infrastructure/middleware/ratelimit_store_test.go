@@ -0,0 +1,166 @@
+package middleware_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/middleware"
+)
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("counts hits within the window", func(t *testing.T) {
+		store := middleware.NewMemoryRateLimitStore(time.Minute)
+
+		for want := 1; want <= 3; want++ {
+			got, err := store.Hit(ctx, "user", time.Minute)
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		}
+
+		other, err := store.Hit(ctx, "other", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, 1, other)
+	})
+
+	t.Run("forgets hits older than the window", func(t *testing.T) {
+		store := middleware.NewMemoryRateLimitStore(time.Minute)
+
+		_, err := store.Hit(ctx, "user", 10*time.Millisecond)
+		require.NoError(t, err)
+		time.Sleep(20 * time.Millisecond)
+
+		got, err := store.Hit(ctx, "user", 10*time.Millisecond)
+		require.NoError(t, err)
+		assert.Equal(t, 1, got)
+	})
+
+	t.Run("acquires and releases slots", func(t *testing.T) {
+		store := middleware.NewMemoryRateLimitStore(time.Minute)
+
+		first, err := store.Acquire(ctx, "slots", time.Minute)
+		require.NoError(t, err)
+		second, err := store.Acquire(ctx, "slots", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, 1, first)
+		assert.Equal(t, 2, second)
+
+		require.NoError(t, store.Release(ctx, "slots"))
+		require.NoError(t, store.Release(ctx, "slots"))
+		require.NoError(t, store.Release(ctx, "slots"))
+
+		again, err := store.Acquire(ctx, "slots", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, 1, again)
+	})
+}
+
+func setupTestRedis(t *testing.T) *redis.Client {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "redis:7-alpine",
+			ExposedPorts: []string{"6379/tcp"},
+			WaitingFor:   wait.ForLog("Ready to accept connections").WithStartupTimeout(30 * time.Second),
+		},
+		Started: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to start redis container: %v", err)
+	}
+	t.Cleanup(func() { _ = container.Terminate(ctx) })
+
+	endpoint, err := container.Endpoint(ctx, "")
+	if err != nil {
+		t.Fatalf("failed to get redis endpoint: %v", err)
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: endpoint})
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestIntegrationRedisRateLimitStore(t *testing.T) {
+	client := setupTestRedis(t)
+	store := middleware.NewRedisRateLimitStore(client)
+	ctx := context.Background()
+
+	t.Run("counts hits within the window", func(t *testing.T) {
+		for want := 1; want <= 3; want++ {
+			got, err := store.Hit(ctx, "rate:user", time.Minute)
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		}
+	})
+
+	t.Run("sets the slot ttl only when the counter is created", func(t *testing.T) {
+		_, err := store.Acquire(ctx, "slots:ttl", time.Hour)
+		require.NoError(t, err)
+		_, err = store.Acquire(ctx, "slots:ttl", time.Second)
+		require.NoError(t, err)
+
+		ttl, err := client.PTTL(ctx, "slots:ttl").Result()
+		require.NoError(t, err)
+		assert.Greater(t, ttl, time.Minute)
+	})
+
+	t.Run("acquires and releases slots without going below zero", func(t *testing.T) {
+		first, err := store.Acquire(ctx, "slots:user", time.Minute)
+		require.NoError(t, err)
+		second, err := store.Acquire(ctx, "slots:user", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, 1, first)
+		assert.Equal(t, 2, second)
+
+		require.NoError(t, store.Release(ctx, "slots:user"))
+		require.NoError(t, store.Release(ctx, "slots:user"))
+		require.NoError(t, store.Release(ctx, "slots:user"))
+
+		again, err := store.Acquire(ctx, "slots:user", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, 1, again)
+	})
+}
+
+func TestIntegrationRedisIdempotencyStore(t *testing.T) {
+	client := setupTestRedis(t)
+	store := middleware.NewRedisIdempotencyStore(client)
+	ctx := context.Background()
+
+	claimed, stored, err := store.Claim(ctx, "idempotency:key", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	assert.Nil(t, stored)
+
+	claimed, stored, err = store.Claim(ctx, "idempotency:key", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, claimed, "running requests keep their key")
+	assert.Nil(t, stored)
+
+	resp := middleware.StoredResponse{Request: "POST /notes", Status: 201, Body: []byte(`{"id":1}`)}
+	require.NoError(t, store.Save(ctx, "idempotency:key", resp, time.Hour))
+
+	claimed, stored, err = store.Claim(ctx, "idempotency:key", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+	assert.Equal(t, &resp, stored)
+
+	require.NoError(t, store.Release(ctx, "idempotency:key"))
+	claimed, _, err = store.Claim(ctx, "idempotency:key", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+}
@@ -0,0 +1,116 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/config"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/middleware"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+// downStore fails every call, as the Redis store does during an outage.
+type downStore struct{}
+
+func (downStore) Hit(context.Context, string, time.Duration) (int, error) {
+	return 0, errors.New("connection refused")
+}
+
+func (downStore) CountExemption(context.Context, string) error {
+	return errors.New("connection refused")
+}
+
+func setupRateLimitRouter(t *testing.T, cfg config.RateLimitConfig) *gin.Engine {
+	t.Helper()
+	limiter, err := middleware.NewRateLimiter(downStore{}, cfg)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/", limiter.Limit(), func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func get(router *gin.Engine) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	return w
+}
+
+func TestRateLimiter_Fallback(t *testing.T) {
+	cfg := config.RateLimitConfig{RequestsPerMin: 60, BurstSize: 2, CleanupInterval: time.Minute}
+
+	t.Run("limits in memory while the store is down", func(t *testing.T) {
+		cfg.Fallback = middleware.RateLimitFallbackMemory
+		router := setupRateLimitRouter(t, cfg)
+
+		assert.Equal(t, http.StatusOK, get(router).Code)
+		assert.Equal(t, http.StatusOK, get(router).Code)
+
+		w := get(router)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Contains(t, w.Body.String(), httputil.CodeRateLimited)
+	})
+
+	t.Run("lets requests through when open", func(t *testing.T) {
+		cfg.Fallback = middleware.RateLimitFallbackOpen
+		router := setupRateLimitRouter(t, cfg)
+
+		for range 5 {
+			assert.Equal(t, http.StatusOK, get(router).Code)
+		}
+	})
+
+	t.Run("rejects requests when closed", func(t *testing.T) {
+		cfg.Fallback = middleware.RateLimitFallbackClosed
+		router := setupRateLimitRouter(t, cfg)
+
+		w := get(router)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "60", w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), httputil.CodeRateLimitDown)
+	})
+
+	t.Run("rejects unknown fallbacks", func(t *testing.T) {
+		cfg.Fallback = "redis"
+		_, err := middleware.NewRateLimiter(downStore{}, cfg)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestTokenBucket(t *testing.T) {
+	t.Run("refills over time", func(t *testing.T) {
+		tb := middleware.NewTokenBucket(6000, 1, time.Minute)
+
+		allowed, _ := tb.Allow("client")
+		assert.True(t, allowed)
+		allowed, _ = tb.Allow("client")
+		assert.False(t, allowed)
+
+		time.Sleep(20 * time.Millisecond)
+
+		allowed, _ = tb.Allow("client")
+		assert.True(t, allowed)
+	})
+
+	t.Run("keeps a bucket per key", func(t *testing.T) {
+		tb := middleware.NewTokenBucket(1, 1, time.Minute)
+
+		tb.Allow("first")
+		allowed, remaining := tb.Allow("second")
+
+		assert.True(t, allowed)
+		assert.Zero(t, remaining)
+	})
+}
@@ -6,6 +6,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/apperror"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
 )
 
 func Recovery(logger *zap.Logger) gin.HandlerFunc {
@@ -18,10 +21,7 @@ func Recovery(logger *zap.Logger) gin.HandlerFunc {
 					zap.String("request_id", c.GetString(RequestIDKey)),
 				)
 
-				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-					"error":      "internal server error",
-					"request_id": c.GetString(RequestIDKey),
-				})
+				httputil.Abort(c, apperror.New(http.StatusInternalServerError, httputil.CodeInternalError, "internal server error"))
 			}
 		}()
 		c.Next()
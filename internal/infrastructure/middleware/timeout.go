@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+// RouteTimeout is the handler timeout of the routes whose path starts with
+// Prefix. A zero Timeout leaves those routes without one.
+type RouteTimeout struct {
+	Prefix  string
+	Timeout time.Duration
+}
+
+// Timeout cancels the request context once the handler has run for the
+// route's timeout: the first entry of routes matching the route path, or
+// def. Queries and storage calls then fail fast, and a handler that has not
+// written a response by the time it returns gets a 504. Handlers that report
+// the failure through httputil.InternalError answer 504 as well.
+func Timeout(def time.Duration, routes []RouteTimeout) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := routeTimeout(c.FullPath(), def, routes)
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			httputil.ErrorWithCode(c, http.StatusGatewayTimeout, httputil.CodeTimeout, "request timed out")
+			c.Abort()
+		}
+	}
+}
+
+func routeTimeout(path string, def time.Duration, routes []RouteTimeout) time.Duration {
+	for _, route := range routes {
+		if strings.HasPrefix(path, route.Prefix) {
+			return route.Timeout
+		}
+	}
+	return def
+}
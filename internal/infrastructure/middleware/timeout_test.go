@@ -0,0 +1,77 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/middleware"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+func TestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// waitForCancel blocks like a slow query until the request context ends.
+	waitForCancel := func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+		case <-time.After(time.Second):
+			c.Status(http.StatusOK)
+		}
+	}
+
+	setup := func(handler gin.HandlerFunc) *gin.Engine {
+		router := gin.New()
+		router.Use(middleware.Timeout(20*time.Millisecond, []middleware.RouteTimeout{
+			{Prefix: "/export", Timeout: 0},
+			{Prefix: "/sync", Timeout: time.Second},
+		}))
+		router.GET("/notes", handler)
+		router.GET("/export", handler)
+		router.GET("/sync/changes", handler)
+		return router
+	}
+
+	get := func(router *gin.Engine, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	t.Run("answers 504 when the handler writes nothing", func(t *testing.T) {
+		w := get(setup(waitForCancel), "/notes")
+
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+		assert.Contains(t, w.Body.String(), httputil.CodeTimeout)
+	})
+
+	t.Run("turns internal errors into 504", func(t *testing.T) {
+		w := get(setup(func(c *gin.Context) {
+			waitForCancel(c)
+			httputil.InternalError(c)
+		}), "/notes")
+
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+		assert.Contains(t, w.Body.String(), httputil.CodeTimeout)
+	})
+
+	t.Run("applies the route timeout", func(t *testing.T) {
+		slow := func(c *gin.Context) {
+			time.Sleep(50 * time.Millisecond)
+			if c.Request.Context().Err() != nil {
+				httputil.InternalError(c)
+				return
+			}
+			c.Status(http.StatusOK)
+		}
+
+		assert.Equal(t, http.StatusGatewayTimeout, get(setup(slow), "/notes").Code)
+		assert.Equal(t, http.StatusOK, get(setup(slow), "/sync/changes").Code)
+		assert.Equal(t, http.StatusOK, get(setup(slow), "/export").Code)
+	})
+}
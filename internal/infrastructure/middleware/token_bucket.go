@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+)
+
+// TokenBucket limits each key in process: a bucket holds up to burst
+// tokens, refills at perMin tokens a minute and every request takes one. It
+// needs no shared state, so the rate limiter falls back to it while its
+// store is unreachable.
+type TokenBucket struct {
+	mu              sync.Mutex
+	buckets         map[string]*bucket
+	perSecond       float64
+	burst           float64
+	cleanupInterval time.Duration
+	lastCleanup     time.Time
+}
+
+type bucket struct {
+	tokens float64
+	filled time.Time
+}
+
+func NewTokenBucket(perMin, burst int, cleanupInterval time.Duration) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		buckets:         make(map[string]*bucket),
+		perSecond:       float64(perMin) / 60,
+		burst:           float64(burst),
+		cleanupInterval: cleanupInterval,
+	}
+}
+
+// Allow takes a token from key's bucket and returns whether there was one
+// and how many are left.
+func (tb *TokenBucket) Allow(key string) (bool, int) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	now := time.Now()
+	b, ok := tb.buckets[key]
+	if !ok {
+		b = &bucket{tokens: tb.burst, filled: now}
+		tb.buckets[key] = b
+	} else {
+		b.tokens = tb.refill(b, now)
+		b.filled = now
+	}
+
+	// A full bucket is the same as no bucket, so idle clients are dropped.
+	if now.Sub(tb.lastCleanup) >= tb.cleanupInterval {
+		for k, other := range tb.buckets {
+			if other != b && tb.refill(other, now) >= tb.burst {
+				delete(tb.buckets, k)
+			}
+		}
+		tb.lastCleanup = now
+	}
+
+	if b.tokens < 1 {
+		return false, 0
+	}
+	b.tokens--
+	return true, int(b.tokens)
+}
+
+func (tb *TokenBucket) refill(b *bucket, now time.Time) float64 {
+	return min(tb.burst, b.tokens+now.Sub(b.filled).Seconds()*tb.perSecond)
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxCachedTokenVersions bounds the cache; once full, it is cleared rather
+// than tracking recency, since a miss only costs one query.
+const maxCachedTokenVersions = 10000
+
+type cachedTokenVersion struct {
+	version   int
+	expiresAt time.Time
+}
+
+// TokenVersionCache keeps users' token versions for ttl so the auth
+// middleware does not query the database on every request. A version bumped
+// elsewhere is seen once the cached entry expires.
+type TokenVersionCache struct {
+	source TokenVersionSource
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	entries map[uuid.UUID]cachedTokenVersion
+}
+
+func NewTokenVersionCache(source TokenVersionSource, ttl time.Duration) *TokenVersionCache {
+	return &TokenVersionCache{
+		source:  source,
+		ttl:     ttl,
+		entries: make(map[uuid.UUID]cachedTokenVersion),
+	}
+}
+
+func (c *TokenVersionCache) GetTokenVersion(ctx context.Context, userID uuid.UUID) (int, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[userID]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.version, nil
+	}
+
+	version, err := c.source.GetTokenVersion(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	if len(c.entries) >= maxCachedTokenVersions {
+		clear(c.entries)
+	}
+	c.entries[userID] = cachedTokenVersion{version: version, expiresAt: now.Add(c.ttl)}
+	c.mu.Unlock()
+
+	return version, nil
+}
@@ -0,0 +1,62 @@
+package middleware_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/middleware"
+)
+
+type countingTokenVersions struct {
+	fakeTokenVersions
+	calls int
+}
+
+func (c *countingTokenVersions) GetTokenVersion(ctx context.Context, userID uuid.UUID) (int, error) {
+	c.calls++
+	return c.fakeTokenVersions.GetTokenVersion(ctx, userID)
+}
+
+func TestTokenVersionCache(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("serves cached versions until they expire", func(t *testing.T) {
+		source := &countingTokenVersions{fakeTokenVersions: fakeTokenVersions{userID: 1}}
+		cache := middleware.NewTokenVersionCache(source, 20*time.Millisecond)
+
+		version, err := cache.GetTokenVersion(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, version)
+
+		source.fakeTokenVersions[userID] = 2
+		version, err = cache.GetTokenVersion(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, version)
+		assert.Equal(t, 1, source.calls)
+
+		time.Sleep(30 * time.Millisecond)
+
+		version, err = cache.GetTokenVersion(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, version)
+		assert.Equal(t, 2, source.calls)
+	})
+
+	t.Run("does not cache errors", func(t *testing.T) {
+		source := &countingTokenVersions{fakeTokenVersions: fakeTokenVersions{}}
+		cache := middleware.NewTokenVersionCache(source, time.Minute)
+
+		_, err := cache.GetTokenVersion(ctx, userID)
+		require.Error(t, err)
+		_, err = cache.GetTokenVersion(ctx, userID)
+		require.Error(t, err)
+
+		assert.Equal(t, 2, source.calls)
+	})
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/config"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/apperror"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+// UploadLimiter caps how many uploads a user may run at once and start per
+// minute. It is independent of the generic rate limiter: a client stuck in a
+// retry loop can stay under the request limit and still saturate the image
+// pipeline, which is far more expensive per request.
+type UploadLimiter struct {
+	store         UploadLimitStore
+	maxConcurrent int
+	perMinute     int
+	slotTTL       time.Duration
+	retryAfter    time.Duration
+}
+
+func NewUploadLimiter(store UploadLimitStore, cfg config.UploadLimitConfig) *UploadLimiter {
+	return &UploadLimiter{
+		store:         store,
+		maxConcurrent: cfg.MaxConcurrent,
+		perMinute:     cfg.PerMinute,
+		slotTTL:       cfg.SlotTTL,
+		retryAfter:    cfg.RetryAfter,
+	}
+}
+
+// Limit must run after authentication; uploads are counted per user.
+func (ul *UploadLimiter) Limit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		userID := httputil.GetUserID(c).String()
+
+		// Store errors let the upload through, as the rate limiter does.
+		count, err := ul.store.Hit(ctx, "uploads:rate:"+userID, time.Minute)
+		if err == nil && count > ul.perMinute {
+			ul.reject(c, time.Minute, fmt.Sprintf("at most %d uploads per minute", ul.perMinute))
+			return
+		}
+
+		slotKey := "uploads:active:" + userID
+		active, err := ul.store.Acquire(ctx, slotKey, ul.slotTTL)
+		if err != nil {
+			c.Next()
+			return
+		}
+		// Released on a fresh context so a cancelled request still frees its slot.
+		defer func() { _ = ul.store.Release(context.WithoutCancel(ctx), slotKey) }()
+
+		if active > ul.maxConcurrent {
+			ul.reject(c, ul.retryAfter, fmt.Sprintf("at most %d uploads at a time", ul.maxConcurrent))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func (ul *UploadLimiter) reject(c *gin.Context, retryAfter time.Duration, reason string) {
+	c.Header("Retry-After", fmt.Sprintf("%d", int(retryAfter.Seconds())))
+	httputil.Abort(c, apperror.New(http.StatusTooManyRequests, httputil.CodeTooManyUploads, "too many uploads, "+reason))
+}
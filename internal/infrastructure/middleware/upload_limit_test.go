@@ -0,0 +1,91 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/config"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/middleware"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
+)
+
+func setupUploadRouter(limiter *middleware.UploadLimiter, userID uuid.UUID, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/upload", func(c *gin.Context) {
+		c.Set("user_id", userID)
+	}, limiter.Limit(), handler)
+	return router
+}
+
+func upload(router *gin.Engine) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/upload", nil))
+	return w
+}
+
+func TestUploadLimiter(t *testing.T) {
+	cfg := config.UploadLimitConfig{MaxConcurrent: 1, PerMinute: 2, SlotTTL: time.Minute, RetryAfter: 5 * time.Second}
+	ok := func(c *gin.Context) { c.Status(http.StatusCreated) }
+
+	t.Run("caps uploads per minute", func(t *testing.T) {
+		limiter := middleware.NewUploadLimiter(middleware.NewMemoryRateLimitStore(time.Minute), cfg)
+		router := setupUploadRouter(limiter, uuid.New(), ok)
+
+		assert.Equal(t, http.StatusCreated, upload(router).Code)
+		assert.Equal(t, http.StatusCreated, upload(router).Code)
+
+		w := upload(router)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "60", w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), httputil.CodeTooManyUploads)
+	})
+
+	t.Run("counts each user separately", func(t *testing.T) {
+		store := middleware.NewMemoryRateLimitStore(time.Minute)
+		limiter := middleware.NewUploadLimiter(store, cfg)
+		first := setupUploadRouter(limiter, uuid.New(), ok)
+		second := setupUploadRouter(limiter, uuid.New(), ok)
+
+		upload(first)
+		upload(first)
+
+		assert.Equal(t, http.StatusTooManyRequests, upload(first).Code)
+		assert.Equal(t, http.StatusCreated, upload(second).Code)
+	})
+
+	t.Run("caps concurrent uploads and releases the slot when done", func(t *testing.T) {
+		limiter := middleware.NewUploadLimiter(middleware.NewMemoryRateLimitStore(time.Minute), config.UploadLimitConfig{
+			MaxConcurrent: 1, PerMinute: 10, SlotTTL: time.Minute, RetryAfter: 5 * time.Second,
+		})
+		started := make(chan struct{})
+		finish := make(chan struct{})
+		userID := uuid.New()
+		slow := setupUploadRouter(limiter, userID, func(c *gin.Context) {
+			close(started)
+			<-finish
+			c.Status(http.StatusCreated)
+		})
+		fast := setupUploadRouter(limiter, userID, ok)
+
+		done := make(chan int)
+		go func() { done <- upload(slow).Code }()
+		<-started
+
+		w := upload(fast)
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "5", w.Header().Get("Retry-After"))
+
+		close(finish)
+		assert.Equal(t, http.StatusCreated, <-done)
+
+		assert.Equal(t, http.StatusCreated, upload(fast).Code)
+	})
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const DeviceIDHeader = "X-Device-ID"
+
+type UsageRecorder interface {
+	Record(userID uuid.UUID, deviceID string, bytesIn, bytesOut int64)
+}
+
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// DataUsage counts request and response body bytes of authenticated requests
+// that carry an X-Device-ID header and hands them to the recorder.
+func DataUsage(recorder UsageRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		deviceID := c.GetHeader(DeviceIDHeader)
+		if deviceID == "" {
+			c.Next()
+			return
+		}
+
+		var body *countingReader
+		if c.Request.Body != nil {
+			body = &countingReader{ReadCloser: c.Request.Body}
+			c.Request.Body = body
+		}
+
+		c.Next()
+
+		userID, ok := c.Get(UserIDKey)
+		if !ok {
+			return
+		}
+
+		var bytesIn int64
+		if body != nil {
+			bytesIn = body.n
+		}
+		bytesOut := int64(max(c.Writer.Size(), 0))
+
+		recorder.Record(userID.(uuid.UUID), deviceID, bytesIn, bytesOut)
+	}
+}
@@ -0,0 +1,137 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+const (
+	APNsProductionURL = "https://api.push.apple.com"
+	APNsSandboxURL    = "https://api.sandbox.push.apple.com"
+
+	// apnsTokenLifetime renews the provider token before Apple's one hour
+	// limit; renewing more often than every 20 minutes is rejected.
+	apnsTokenLifetime = 50 * time.Minute
+)
+
+var silentAPNsPayload = []byte(`{"aps":{"content-available":1}}`)
+
+type APNsConfig struct {
+	// KeyPEM is the .p8 token-based auth key.
+	KeyPEM []byte
+	KeyID  string
+	TeamID string
+	// Topic is the app's bundle ID.
+	Topic string
+	// BaseURL is APNsProductionURL or APNsSandboxURL.
+	BaseURL string
+}
+
+// APNsPusher sends background pushes to iOS devices through Apple's provider
+// API, signing in with a token made from the team's auth key.
+type APNsPusher struct {
+	httpClient *http.Client
+	baseURL    string
+	key        *ecdsa.PrivateKey
+	keyID      string
+	teamID     string
+	topic      string
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+func NewAPNsPusher(cfg APNsConfig, timeout time.Duration) (*APNsPusher, error) {
+	key, err := jwt.ParseECPrivateKeyFromPEM(cfg.KeyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("parsing apns key: %w", err)
+	}
+	return &APNsPusher{
+		httpClient: &http.Client{Timeout: timeout},
+		baseURL:    strings.TrimRight(cfg.BaseURL, "/"),
+		key:        key,
+		keyID:      cfg.KeyID,
+		teamID:     cfg.TeamID,
+		topic:      cfg.Topic,
+	}, nil
+}
+
+func (p *APNsPusher) SilentPush(ctx context.Context, device entity.Device) error {
+	token, err := p.providerToken()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		p.baseURL+"/3/device/"+device.PushToken, bytes.NewReader(silentAPNsPayload))
+	if err != nil {
+		return fmt.Errorf("building apns request: %w", err)
+	}
+	req.Header.Set("Authorization", "bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("apns-push-type", "background")
+	// Background pushes must use priority 5.
+	req.Header.Set("apns-priority", "5")
+	req.Header.Set("apns-topic", p.topic)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending apns push: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 4<<10)).Decode(&body)
+	switch {
+	case resp.StatusCode == http.StatusGone,
+		body.Reason == "BadDeviceToken", body.Reason == "DeviceTokenNotForTopic":
+		return domain.ErrPushTokenInvalid
+	default:
+		return fmt.Errorf("apns returned %d: %s", resp.StatusCode, body.Reason)
+	}
+}
+
+// providerToken returns the cached provider token, signing a new one when it
+// is about to expire.
+func (p *APNsPusher) providerToken() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if p.token != "" && now.Sub(p.issuedAt) < apnsTokenLifetime {
+		return p.token, nil
+	}
+
+	t := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.RegisteredClaims{
+		Issuer:   p.teamID,
+		IssuedAt: jwt.NewNumericDate(now),
+	})
+	t.Header["kid"] = p.keyID
+	signed, err := t.SignedString(p.key)
+	if err != nil {
+		return "", fmt.Errorf("signing apns token: %w", err)
+	}
+
+	p.token, p.issuedAt = signed, now
+	return signed, nil
+}
@@ -0,0 +1,190 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+const (
+	FCMURL = "https://fcm.googleapis.com"
+
+	fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+)
+
+type fcmServiceAccount struct {
+	ProjectID   string `json:"project_id"`
+	PrivateKey  string `json:"private_key"`
+	ClientEmail string `json:"client_email"`
+	TokenURI    string `json:"token_uri"`
+}
+
+type fcmMessage struct {
+	Message struct {
+		Token   string            `json:"token"`
+		Data    map[string]string `json:"data"`
+		Android struct {
+			Priority string `json:"priority"`
+		} `json:"android"`
+	} `json:"message"`
+}
+
+type fcmError struct {
+	Error struct {
+		Status  string `json:"status"`
+		Details []struct {
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+// FCMPusher sends data-only messages to Android devices through the Firebase
+// Cloud Messaging HTTP v1 API, signing in as a service account.
+type FCMPusher struct {
+	httpClient *http.Client
+	baseURL    string
+	account    fcmServiceAccount
+	key        *rsa.PrivateKey
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewFCMPusher reads a Firebase service account JSON key. baseURL is FCMURL
+// outside tests.
+func NewFCMPusher(credentialsJSON []byte, baseURL string, timeout time.Duration) (*FCMPusher, error) {
+	var account fcmServiceAccount
+	if err := json.Unmarshal(credentialsJSON, &account); err != nil {
+		return nil, fmt.Errorf("parsing fcm credentials: %w", err)
+	}
+	if account.ProjectID == "" || account.ClientEmail == "" || account.TokenURI == "" {
+		return nil, errors.New("fcm credentials need project_id, client_email and token_uri")
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(account.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("parsing fcm private key: %w", err)
+	}
+	return &FCMPusher{
+		httpClient: &http.Client{Timeout: timeout},
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		account:    account,
+		key:        key,
+	}, nil
+}
+
+func (p *FCMPusher) SilentPush(ctx context.Context, device entity.Device) error {
+	token, err := p.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	var msg fcmMessage
+	msg.Message.Token = device.PushToken
+	msg.Message.Data = map[string]string{"type": "sync"}
+	msg.Message.Android.Priority = "high"
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("encoding fcm message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		p.baseURL+"/v1/projects/"+p.account.ProjectID+"/messages:send", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("building fcm request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending fcm message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var fail fcmError
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 4<<10)).Decode(&fail)
+	if resp.StatusCode == http.StatusNotFound {
+		return domain.ErrPushTokenInvalid
+	}
+	for _, d := range fail.Error.Details {
+		if d.ErrorCode == "UNREGISTERED" {
+			return domain.ErrPushTokenInvalid
+		}
+	}
+	return fmt.Errorf("fcm returned %d: %s", resp.StatusCode, fail.Error.Status)
+}
+
+// accessToken returns the cached OAuth token, exchanging a signed assertion
+// for a new one when it is about to expire.
+func (p *FCMPusher) accessToken(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if p.token != "" && now.Before(p.expiresAt) {
+		return p.token, nil
+	}
+
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   p.account.ClientEmail,
+		"scope": fcmScope,
+		"aud":   p.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(p.key)
+	if err != nil {
+		return "", fmt.Errorf("signing fcm assertion: %w", err)
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("building fcm token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("requesting fcm token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fcm token endpoint returned %d", resp.StatusCode)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("decoding fcm token: %w", err)
+	}
+
+	// Renew a minute early so a token never expires in flight.
+	p.token = body.AccessToken
+	p.expiresAt = now.Add(time.Duration(body.ExpiresIn)*time.Second - time.Minute)
+	return p.token, nil
+}
@@ -0,0 +1,31 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+// PushDispatcher sends each push through the service of the device's
+// platform: APNs for ios, FCM for android. Devices of a platform whose
+// service is not configured are skipped.
+type PushDispatcher struct {
+	apns *APNsPusher
+	fcm  *FCMPusher
+}
+
+// NewPushDispatcher routes pushes to apns and fcm; either may be nil.
+func NewPushDispatcher(apns *APNsPusher, fcm *FCMPusher) *PushDispatcher {
+	return &PushDispatcher{apns: apns, fcm: fcm}
+}
+
+func (d *PushDispatcher) SilentPush(ctx context.Context, device entity.Device) error {
+	switch {
+	case device.Platform == entity.PlatformIOS && d.apns != nil:
+		return d.apns.SilentPush(ctx, device)
+	case device.Platform == entity.PlatformAndroid && d.fcm != nil:
+		return d.fcm.SilentPush(ctx, device)
+	default:
+		return nil
+	}
+}
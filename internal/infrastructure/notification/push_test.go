@@ -0,0 +1,166 @@
+package notification_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/notification"
+)
+
+func newAPNsPusher(t *testing.T, handler http.HandlerFunc) (*notification.APNsPusher, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	pusher, err := notification.NewAPNsPusher(notification.APNsConfig{
+		KeyPEM:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}),
+		KeyID:   "KEY123",
+		TeamID:  "TEAM123",
+		Topic:   "com.example.fieldnotes",
+		BaseURL: srv.URL,
+	}, time.Second)
+	require.NoError(t, err)
+	return pusher, key
+}
+
+func TestAPNsPusher_SilentPush(t *testing.T) {
+	device := entity.Device{Platform: entity.PlatformIOS, PushToken: "abc123"}
+
+	t.Run("sends a background push signed with the team key", func(t *testing.T) {
+		var got *http.Request
+		var body []byte
+		pusher, key := newAPNsPusher(t, func(w http.ResponseWriter, r *http.Request) {
+			got = r
+			body, _ = io.ReadAll(r.Body)
+		})
+
+		require.NoError(t, pusher.SilentPush(t.Context(), device))
+
+		assert.Equal(t, "/3/device/abc123", got.URL.Path)
+		assert.Equal(t, "background", got.Header.Get("apns-push-type"))
+		assert.Equal(t, "5", got.Header.Get("apns-priority"))
+		assert.Equal(t, "com.example.fieldnotes", got.Header.Get("apns-topic"))
+		assert.JSONEq(t, `{"aps":{"content-available":1}}`, string(body))
+
+		token, err := jwt.Parse(strings.TrimPrefix(got.Header.Get("Authorization"), "bearer "),
+			func(*jwt.Token) (any, error) { return &key.PublicKey, nil })
+		require.NoError(t, err)
+		assert.Equal(t, "KEY123", token.Header["kid"])
+		issuer, _ := token.Claims.GetIssuer()
+		assert.Equal(t, "TEAM123", issuer)
+	})
+
+	t.Run("reports unregistered tokens", func(t *testing.T) {
+		for status, reason := range map[int]string{
+			http.StatusGone:       "Unregistered",
+			http.StatusBadRequest: "BadDeviceToken",
+		} {
+			pusher, _ := newAPNsPusher(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(status)
+				_, _ = w.Write([]byte(`{"reason":"` + reason + `"}`))
+			})
+
+			assert.ErrorIs(t, pusher.SilentPush(t.Context(), device), domain.ErrPushTokenInvalid, reason)
+		}
+	})
+
+	t.Run("keeps the token on other failures", func(t *testing.T) {
+		pusher, _ := newAPNsPusher(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"reason":"TooManyRequests"}`))
+		})
+
+		err := pusher.SilentPush(t.Context(), device)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, domain.ErrPushTokenInvalid)
+	})
+}
+
+func TestFCMPusher_SilentPush(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	device := entity.Device{Platform: entity.PlatformAndroid, PushToken: "fcm-token"}
+
+	newPusher := func(t *testing.T, send http.HandlerFunc) (*notification.FCMPusher, *int) {
+		tokenRequests := 0
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+			tokenRequests++
+			require.NoError(t, r.ParseForm())
+			_, err := jwt.Parse(r.PostForm.Get("assertion"),
+				func(*jwt.Token) (any, error) { return &key.PublicKey, nil })
+			assert.NoError(t, err)
+			_, _ = w.Write([]byte(`{"access_token":"oauth-token","expires_in":3600}`))
+		})
+		mux.HandleFunc("POST /v1/projects/field-notes/messages:send", send)
+		srv := httptest.NewServer(mux)
+		t.Cleanup(srv.Close)
+
+		credentials, err := json.Marshal(map[string]string{
+			"project_id":   "field-notes",
+			"client_email": "push@field-notes.iam.gserviceaccount.com",
+			"private_key":  string(keyPEM),
+			"token_uri":    srv.URL + "/token",
+		})
+		require.NoError(t, err)
+		pusher, err := notification.NewFCMPusher(credentials, srv.URL, time.Second)
+		require.NoError(t, err)
+		return pusher, &tokenRequests
+	}
+
+	t.Run("sends a data message with a cached access token", func(t *testing.T) {
+		var msg map[string]any
+		pusher, tokenRequests := newPusher(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer oauth-token", r.Header.Get("Authorization"))
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		})
+
+		require.NoError(t, pusher.SilentPush(t.Context(), device))
+		require.NoError(t, pusher.SilentPush(t.Context(), device))
+
+		assert.Equal(t, 1, *tokenRequests)
+		assert.Equal(t, map[string]any{
+			"token":   "fcm-token",
+			"data":    map[string]any{"type": "sync"},
+			"android": map[string]any{"priority": "high"},
+		}, msg["message"])
+	})
+
+	t.Run("reports unregistered tokens", func(t *testing.T) {
+		pusher, _ := newPusher(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"status":"NOT_FOUND","details":[{"errorCode":"UNREGISTERED"}]}}`))
+		})
+
+		assert.ErrorIs(t, pusher.SilentPush(t.Context(), device), domain.ErrPushTokenInvalid)
+	})
+}
+
+func TestPushDispatcher_SkipsUnconfiguredPlatforms(t *testing.T) {
+	dispatcher := notification.NewPushDispatcher(nil, nil)
+
+	assert.NoError(t, dispatcher.SilentPush(t.Context(), entity.Device{Platform: entity.PlatformIOS, PushToken: "abc"}))
+	assert.NoError(t, dispatcher.SilentPush(t.Context(), entity.Device{Platform: entity.PlatformAndroid, PushToken: "abc"}))
+}
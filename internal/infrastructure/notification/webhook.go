@@ -0,0 +1,152 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+const (
+	// SignatureHeader carries the hex HMAC-SHA256 of the body, keyed with the
+	// webhook secret, so receivers can verify the sender.
+	SignatureHeader = "X-Field-Notes-Signature"
+
+	eventSyncConflict  = "sync.conflict"
+	eventSecurityAlert = "security.alert"
+)
+
+type webhookPayload struct {
+	Event      string           `json:"event"`
+	UserID     uuid.UUID        `json:"user_id"`
+	Email      string           `json:"email"`
+	DeviceID   string           `json:"device_id"`
+	OccurredAt time.Time        `json:"occurred_at"`
+	Notes      []discardedEntry `json:"notes"`
+}
+
+type discardedEntry struct {
+	NoteID          uuid.UUID `json:"note_id"`
+	ClientID        string    `json:"client_id"`
+	Reference       string    `json:"reference"`
+	Title           string    `json:"title"`
+	ClientTitle     string    `json:"client_title"`
+	ClientUpdatedAt time.Time `json:"client_updated_at"`
+	ServerUpdatedAt time.Time `json:"server_updated_at"`
+	URL             string    `json:"url,omitempty"`
+}
+
+type alertPayload struct {
+	Event      string     `json:"event"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Email      string     `json:"email"`
+	OccurredAt time.Time  `json:"occurred_at"`
+	AlertID    uuid.UUID  `json:"alert_id"`
+	Kind       string     `json:"kind"`
+	Detail     string     `json:"detail"`
+	DeviceID   *uuid.UUID `json:"device_id,omitempty"`
+}
+
+// WebhookNotifier posts notifications as JSON to a single endpoint, which is
+// responsible for delivering them by push or email.
+type WebhookNotifier struct {
+	httpClient *http.Client
+	url        string
+	secret     []byte
+	appURL     string
+}
+
+func NewWebhookNotifier(url, secret, appURL string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		httpClient: &http.Client{Timeout: timeout},
+		url:        url,
+		secret:     []byte(secret),
+		appURL:     strings.TrimRight(appURL, "/"),
+	}
+}
+
+func (n *WebhookNotifier) SyncConflict(ctx context.Context, notice entity.SyncConflictNotice) error {
+	payload := webhookPayload{
+		Event:      eventSyncConflict,
+		UserID:     notice.UserID,
+		Email:      notice.Email,
+		DeviceID:   notice.DeviceID,
+		OccurredAt: notice.OccurredAt,
+		Notes:      make([]discardedEntry, len(notice.Notes)),
+	}
+	for i, d := range notice.Notes {
+		payload.Notes[i] = discardedEntry{
+			NoteID:          d.NoteID,
+			ClientID:        d.ClientID,
+			Reference:       d.Reference,
+			Title:           d.Title,
+			ClientTitle:     d.ClientTitle,
+			ClientUpdatedAt: d.ClientUpdatedAt,
+			ServerUpdatedAt: d.ServerUpdatedAt,
+			URL:             n.noteURL(d.NoteID),
+		}
+	}
+
+	return n.post(ctx, payload)
+}
+
+func (n *WebhookNotifier) SecurityAlert(ctx context.Context, notice entity.SecurityAlertNotice) error {
+	return n.post(ctx, alertPayload{
+		Event:      eventSecurityAlert,
+		UserID:     notice.UserID,
+		Email:      notice.Email,
+		OccurredAt: notice.Alert.CreatedAt,
+		AlertID:    notice.Alert.ID,
+		Kind:       notice.Alert.Kind,
+		Detail:     notice.Alert.Detail,
+		DeviceID:   notice.Alert.DeviceID,
+	})
+}
+
+// noteURL links to the note in the web app, where the kept version can be
+// compared with the user's copy. It is empty when no app URL is configured.
+func (n *WebhookNotifier) noteURL(id uuid.UUID) string {
+	if n.appURL == "" {
+		return ""
+	}
+	return n.appURL + "/notes/" + id.String()
+}
+
+func (n *WebhookNotifier) post(ctx context.Context, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encoding webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(n.secret) > 0 {
+		mac := hmac.New(sha256.New, n.secret)
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
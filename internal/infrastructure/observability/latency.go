@@ -0,0 +1,138 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Names of the operations whose latency is recorded.
+const (
+	OpS3PutObject    = "s3_put_object"
+	OpS3GetObject    = "s3_get_object"
+	OpS3DeleteObject = "s3_delete_object"
+	OpS3Presign      = "s3_presign"
+	OpImageDecode    = "image_decode"
+	OpImageResize    = "image_resize"
+	OpImageEncode    = "image_encode"
+	OpThumbnail      = "image_thumbnail"
+	OpUpload         = "upload"
+)
+
+// latencyBuckets are the histogram upper bounds in seconds, from a fast
+// presign to an upload on a slow link.
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+type histogram struct {
+	counts []int64
+	count  int64
+	sum    float64
+}
+
+// Latencies keeps a histogram of the durations of each operation, and counts
+// the operations that went over their latency budget. A nil *Latencies
+// records nothing, so instrumented components work without one.
+type Latencies struct {
+	mu         sync.Mutex
+	histograms map[string]*histogram
+	overBudget map[string]int64
+}
+
+func NewLatencies() *Latencies {
+	return &Latencies{
+		histograms: make(map[string]*histogram),
+		overBudget: make(map[string]int64),
+	}
+}
+
+// Observe records that op took d, and adds it to the trace of ctx if any.
+func (l *Latencies) Observe(ctx context.Context, op string, d time.Duration) {
+	if l == nil {
+		return
+	}
+	TraceFrom(ctx).add(op, d)
+
+	seconds := d.Seconds()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	h, ok := l.histograms[op]
+	if !ok {
+		h = &histogram{counts: make([]int64, len(latencyBuckets))}
+		l.histograms[op] = h
+	}
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// Since observes the time elapsed since start, for use with defer.
+func (l *Latencies) Since(ctx context.Context, op string, start time.Time) {
+	l.Observe(ctx, op, time.Since(start))
+}
+
+// OverBudget counts an op that took longer than its budget.
+func (l *Latencies) OverBudget(op string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.overBudget[op]++
+}
+
+// WritePrometheus writes the histograms and budget counters in the
+// Prometheus text format.
+func (l *Latencies) WritePrometheus(w io.Writer) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	const duration = "fieldnotes_operation_duration_seconds"
+	if _, err := fmt.Fprintf(w, "# HELP %s Time taken by storage and image operations.\n# TYPE %s histogram\n", duration, duration); err != nil {
+		return err
+	}
+	for _, op := range sortedKeys(l.histograms) {
+		h := l.histograms[op]
+		for i, bound := range latencyBuckets {
+			if _, err := fmt.Fprintf(w, "%s_bucket{operation=%q,le=%q} %d\n", duration, op, formatFloat(bound), h.counts[i]); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s_bucket{operation=%q,le=\"+Inf\"} %d\n%s_sum{operation=%q} %s\n%s_count{operation=%q} %d\n",
+			duration, op, h.count, duration, op, formatFloat(h.sum), duration, op, h.count); err != nil {
+			return err
+		}
+	}
+
+	const over = "fieldnotes_operation_over_budget_total"
+	if _, err := fmt.Fprintf(w, "# HELP %s Operations that took longer than their latency budget.\n# TYPE %s counter\n", over, over); err != nil {
+		return err
+	}
+	for _, op := range sortedKeys(l.overBudget) {
+		if _, err := fmt.Fprintf(w, "%s{operation=%q} %d\n", over, op, l.overBudget[op]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,69 @@
+package observability
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type traceKey struct{}
+
+// Span is one timed operation of a request.
+type Span struct {
+	Operation string
+	Duration  time.Duration
+}
+
+// Trace collects the operations timed while serving one request, so a slow
+// request can be broken down in its log line. A nil *Trace ignores them.
+type Trace struct {
+	mu    sync.Mutex
+	spans []Span
+}
+
+// WithTrace returns a context whose timed operations are collected in the
+// returned trace.
+func WithTrace(ctx context.Context) (context.Context, *Trace) {
+	t := &Trace{}
+	return context.WithValue(ctx, traceKey{}, t), t
+}
+
+// TraceFrom returns the trace of ctx, or nil when it has none.
+func TraceFrom(ctx context.Context) *Trace {
+	t, _ := ctx.Value(traceKey{}).(*Trace)
+	return t
+}
+
+func (t *Trace) add(op string, d time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans = append(t.spans, Span{Operation: op, Duration: d})
+}
+
+// Spans returns the operations in the order they finished.
+func (t *Trace) Spans() []Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Span(nil), t.spans...)
+}
+
+// Field returns the time spent in each operation, summed over its spans, as
+// a log field.
+func (t *Trace) Field() zap.Field {
+	return zap.Object("spans", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		totals := make(map[string]time.Duration)
+		for _, span := range t.Spans() {
+			totals[span.Operation] += span.Duration
+		}
+		for _, op := range sortedKeys(totals) {
+			enc.AddDuration(op, totals[op])
+		}
+		return nil
+	}))
+}
@@ -0,0 +1,21 @@
+package server
+
+import "github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+
+// Deprecations returns the API endpoints and request fields clients should
+// stop using. Deprecating one only takes an entry here, with a Field when a
+// handler reports the field through httputil.UseDeprecatedField; it stays
+// listed, with its use per app version in /admin/deprecations, until the
+// code behind it is removed. For example:
+//
+//	{
+//		Method: http.MethodPost,
+//		Route:  "/api/v1/sync",
+//		Field:  "notes[].tags",
+//		Since:  time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+//		Sunset: time.Date(2027, 5, 1, 0, 0, 0, 0, time.UTC),
+//		Link:   "https://docs.fieldnotes.app/migrations/sync-tags",
+//	}
+func Deprecations() []entity.Deprecation {
+	return []entity.Deprecation{}
+}
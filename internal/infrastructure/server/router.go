@@ -1,39 +1,168 @@
 package server
 
 import (
+	"net/http"
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"go.uber.org/zap"
 
 	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
 	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/middleware"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/observability"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/apperror"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/httputil"
 )
 
 type Router struct {
-	engine          *gin.Engine
-	authHandler     *handler.AuthHandler
-	noteHandler     *handler.NoteHandler
-	syncHandler     *handler.SyncHandler
-	uploadHandler   *handler.UploadHandler
-	authMiddleware  *middleware.AuthMiddleware
-	rateLimiter     *middleware.RateLimiter
-	rateLimitEnable bool
-	logger          *zap.Logger
+	engine            *gin.Engine
+	authHandler       *handler.AuthHandler
+	noteHandler       *handler.NoteHandler
+	syncHandler       *handler.SyncHandler
+	uploadHandler     *handler.UploadHandler
+	deviceHandler     *handler.DeviceHandler
+	qualityHandler    *handler.QualityHandler
+	preferenceHandler *handler.PreferenceHandler
+	shareHandler      *handler.ShareHandler
+	teamHandler       *handler.TeamHandler
+	privacyHandler    *handler.PrivacyHandler
+	labelHandler      *handler.LabelHandler
+	summaryHandler    *handler.NoteSummaryHandler
+	statsHandler      *handler.StatsHandler
+	accountHandler    *handler.AccountHandler
+	alertHandler      *handler.AlertHandler
+	demoHandler       *handler.DemoHandler
+	jobHandler        *handler.JobHandler
+	clientHandler     *handler.ClientHandler
+	kpiHandler        *handler.KPIHandler
+	metricsHandler    *handler.MetricsHandler
+	realtimeHandler   *handler.RealtimeHandler
+	healthHandler     *handler.HealthHandler
+	errorHandler      *handler.ErrorCatalogHandler
+	authMiddleware    *middleware.AuthMiddleware
+	usageRecorder     middleware.UsageRecorder
+	clientRecorder    middleware.ClientRecorder
+	kpiRecorder       middleware.KPIRecorder
+	changePublisher   middleware.ChangePublisher
+	rateLimiter       *middleware.RateLimiter
+	rateLimitEnable   bool
+	uploadLimiter     *middleware.UploadLimiter
+	latencies         *observability.Latencies
+	uploadBudget      time.Duration
+	idempotency       *middleware.Idempotency
+	versions          middleware.VersionSource
+	passwordReset     bool
+	jobsPassword      string
+	demoUserID        uuid.UUID
+	handlerTimeout    time.Duration
+	longTimeout       time.Duration
+	maxBodyBytes      int64
+	maxSyncBodyBytes  int64
+	logger            *zap.Logger
+	// deprecations are answered with deprecation headers and counted by
+	// deprecationRecorder.
+	deprecations        []entity.Deprecation
+	deprecationRecorder middleware.DeprecationRecorder
+	deprecationHandler  *handler.DeprecationHandler
+	// auditHandler lists the account activity the services record.
+	auditHandler *handler.AuditHandler
+	// documentHandler serves collaborative editing of notes.
+	documentHandler *handler.DocumentHandler
+	// semanticHandler serves search over note embeddings.
+	semanticHandler *handler.SemanticHandler
+	corsOrigins     []string
+	swaggerExposure Exposure
+	adminUserIDs    []uuid.UUID
+	jwksHandler     *handler.JWKSHandler
 }
 
 type RouterConfig struct {
-	AuthHandler     *handler.AuthHandler
-	NoteHandler     *handler.NoteHandler
-	SyncHandler     *handler.SyncHandler
-	UploadHandler   *handler.UploadHandler
-	AuthMiddleware  *middleware.AuthMiddleware
-	RateLimiter     *middleware.RateLimiter
-	RateLimitEnable bool
-	Logger          *zap.Logger
-	Environment     string
+	AuthHandler       *handler.AuthHandler
+	NoteHandler       *handler.NoteHandler
+	SyncHandler       *handler.SyncHandler
+	UploadHandler     *handler.UploadHandler
+	DeviceHandler     *handler.DeviceHandler
+	QualityHandler    *handler.QualityHandler
+	PreferenceHandler *handler.PreferenceHandler
+	ShareHandler      *handler.ShareHandler
+	TeamHandler       *handler.TeamHandler
+	PrivacyHandler    *handler.PrivacyHandler
+	LabelHandler      *handler.LabelHandler
+	SummaryHandler    *handler.NoteSummaryHandler
+	StatsHandler      *handler.StatsHandler
+	AccountHandler    *handler.AccountHandler
+	AlertHandler      *handler.AlertHandler
+	DemoHandler       *handler.DemoHandler
+	JobHandler        *handler.JobHandler
+	ClientHandler     *handler.ClientHandler
+	KPIHandler        *handler.KPIHandler
+	MetricsHandler    *handler.MetricsHandler
+	RealtimeHandler   *handler.RealtimeHandler
+	HealthHandler     *handler.HealthHandler
+	AuthMiddleware    *middleware.AuthMiddleware
+	UsageRecorder     middleware.UsageRecorder
+	ClientRecorder    middleware.ClientRecorder
+	KPIRecorder       middleware.KPIRecorder
+	ChangePublisher   middleware.ChangePublisher
+	RateLimiter       *middleware.RateLimiter
+	RateLimitEnable   bool
+	UploadLimiter     *middleware.UploadLimiter
+	Latencies         *observability.Latencies
+	UploadBudget      time.Duration
+	Idempotency       *middleware.Idempotency
+	Versions          middleware.VersionSource
+	PasswordReset     bool
+	JobsPassword      string
+	DemoUserID        uuid.UUID
+	// HandlerTimeout bounds each handler, and LongTimeout the sync and
+	// upload handlers. Zero leaves them unbounded.
+	HandlerTimeout time.Duration
+	LongTimeout    time.Duration
+	Logger         *zap.Logger
+	Environment    string
+	// MaxBodyBytes bounds request bodies, and MaxSyncBodyBytes those of
+	// sync. Zero leaves them unbounded.
+	MaxBodyBytes     int64
+	MaxSyncBodyBytes int64
+	// Deprecations are answered with deprecation headers and, with a
+	// DeprecationRecorder, counted per app version.
+	Deprecations        []entity.Deprecation
+	DeprecationRecorder middleware.DeprecationRecorder
+	DeprecationHandler  *handler.DeprecationHandler
+	// AuditHandler serves /me/audit; without it the route is not registered.
+	AuditHandler *handler.AuditHandler
+	// DocumentHandler serves /notes/{id}/collaboration; without it the
+	// routes are not registered.
+	DocumentHandler *handler.DocumentHandler
+	// SemanticHandler serves /notes/semantic-search and /notes/{id}/related;
+	// without it the routes are not registered.
+	SemanticHandler *handler.SemanticHandler
+	// CORSOrigins are the browser origins allowed to call the API; "*"
+	// allows any.
+	CORSOrigins []string
+	// SwaggerExposure is who is served the API documentation at /swagger.
+	SwaggerExposure Exposure
+	// AdminUserIDs are the users whose access tokens open the operator
+	// routes, besides JobsPassword.
+	AdminUserIDs []uuid.UUID
+	// JWKSHandler serves /.well-known/jwks.json; without it the route is
+	// not registered.
+	JWKSHandler *handler.JWKSHandler
 }
 
+// Exposure is who an operational route is served to.
+type Exposure string
+
+const (
+	ExposurePublic    Exposure = "public"
+	ExposureOperators Exposure = "operators"
+	ExposureOff       Exposure = "off"
+)
+
 func NewRouter(cfg RouterConfig) *Router {
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -42,15 +171,62 @@ func NewRouter(cfg RouterConfig) *Router {
 	engine := gin.New()
 
 	r := &Router{
-		engine:          engine,
-		authHandler:     cfg.AuthHandler,
-		noteHandler:     cfg.NoteHandler,
-		syncHandler:     cfg.SyncHandler,
-		uploadHandler:   cfg.UploadHandler,
-		authMiddleware:  cfg.AuthMiddleware,
-		rateLimiter:     cfg.RateLimiter,
-		rateLimitEnable: cfg.RateLimitEnable,
-		logger:          cfg.Logger,
+		engine:            engine,
+		authHandler:       cfg.AuthHandler,
+		noteHandler:       cfg.NoteHandler,
+		syncHandler:       cfg.SyncHandler,
+		uploadHandler:     cfg.UploadHandler,
+		deviceHandler:     cfg.DeviceHandler,
+		qualityHandler:    cfg.QualityHandler,
+		preferenceHandler: cfg.PreferenceHandler,
+		shareHandler:      cfg.ShareHandler,
+		teamHandler:       cfg.TeamHandler,
+		privacyHandler:    cfg.PrivacyHandler,
+		labelHandler:      cfg.LabelHandler,
+		summaryHandler:    cfg.SummaryHandler,
+		statsHandler:      cfg.StatsHandler,
+		accountHandler:    cfg.AccountHandler,
+		alertHandler:      cfg.AlertHandler,
+		demoHandler:       cfg.DemoHandler,
+		jobHandler:        cfg.JobHandler,
+		clientHandler:     cfg.ClientHandler,
+		kpiHandler:        cfg.KPIHandler,
+		metricsHandler:    cfg.MetricsHandler,
+		realtimeHandler:   cfg.RealtimeHandler,
+		healthHandler:     cfg.HealthHandler,
+		errorHandler:      handler.NewErrorCatalogHandler(),
+		authMiddleware:    cfg.AuthMiddleware,
+		usageRecorder:     cfg.UsageRecorder,
+		clientRecorder:    cfg.ClientRecorder,
+		kpiRecorder:       cfg.KPIRecorder,
+		changePublisher:   cfg.ChangePublisher,
+		rateLimiter:       cfg.RateLimiter,
+		rateLimitEnable:   cfg.RateLimitEnable,
+		uploadLimiter:     cfg.UploadLimiter,
+		latencies:         cfg.Latencies,
+		uploadBudget:      cfg.UploadBudget,
+		idempotency:       cfg.Idempotency,
+		versions:          cfg.Versions,
+		passwordReset:     cfg.PasswordReset,
+		jobsPassword:      cfg.JobsPassword,
+		demoUserID:        cfg.DemoUserID,
+		handlerTimeout:    cfg.HandlerTimeout,
+		longTimeout:       cfg.LongTimeout,
+		maxBodyBytes:      cfg.MaxBodyBytes,
+		maxSyncBodyBytes:  cfg.MaxSyncBodyBytes,
+		logger:            cfg.Logger,
+
+		deprecations:        cfg.Deprecations,
+		deprecationRecorder: cfg.DeprecationRecorder,
+		deprecationHandler:  cfg.DeprecationHandler,
+
+		auditHandler:    cfg.AuditHandler,
+		documentHandler: cfg.DocumentHandler,
+		semanticHandler: cfg.SemanticHandler,
+		corsOrigins:     cfg.CORSOrigins,
+		swaggerExposure: cfg.SwaggerExposure,
+		adminUserIDs:    cfg.AdminUserIDs,
+		jwksHandler:     cfg.JWKSHandler,
 	}
 
 	r.setupMiddleware()
@@ -63,57 +239,280 @@ func (r *Router) setupMiddleware() {
 	r.engine.Use(middleware.Recovery(r.logger))
 	r.engine.Use(middleware.RequestID())
 	r.engine.Use(middleware.Logger(r.logger))
-	r.engine.Use(middleware.CORS())
+	r.engine.Use(middleware.CORS(r.corsOrigins))
+	r.engine.Use(middleware.Timeout(r.handlerTimeout, []middleware.RouteTimeout{
+		// The export streams for as long as the account takes to read.
+		{Prefix: "/api/v1/notes/export", Timeout: 0},
+		{Prefix: "/api/v1/notes/import", Timeout: r.longTimeout},
+		{Prefix: "/api/v1/sync", Timeout: r.longTimeout},
+		{Prefix: "/api/v1/upload", Timeout: r.longTimeout},
+		{Prefix: "/api/v1/ws", Timeout: 0},
+	}))
+	r.engine.Use(middleware.BodyLimit(r.maxBodyBytes, []middleware.RouteBodyLimit{
+		// Imports and uploads check their own, larger limits.
+		{Prefix: "/api/v1/notes/import", Limit: 0},
+		{Prefix: "/api/v1/sync", Limit: r.maxSyncBodyBytes},
+		{Prefix: "/api/v1/upload", Limit: 0},
+	}))
+
+	if r.usageRecorder != nil {
+		r.engine.Use(middleware.DataUsage(r.usageRecorder))
+	}
 
 	if r.rateLimitEnable && r.rateLimiter != nil {
 		r.engine.Use(r.rateLimiter.Limit())
 	}
 }
 
+// requireAuth authenticates the request and, in demo mode, blocks writes from
+// the demo account. With a read replica it also tracks the user's write
+// version, and it replays writes retried with the same Idempotency-Key.
+// Audit events of authenticated requests record the token's device.
+func (r *Router) requireAuth() gin.HandlersChain {
+	chain := gin.HandlersChain{r.authMiddleware.RequireAuth(), middleware.AuditClient()}
+	if r.demoUserID != uuid.Nil {
+		chain = append(chain, middleware.DemoReadOnly(r.demoUserID))
+	}
+	if r.versions != nil {
+		chain = append(chain, middleware.ReadYourWrites(r.versions))
+	}
+	if r.idempotency != nil {
+		chain = append(chain, r.idempotency.Handle())
+	}
+	return chain
+}
+
+// operational returns the group to serve operational routes under path to
+// exposure, or nil when they are not to be served: when off, or when for
+// operators and neither the ops password nor admin users are configured.
+// Debugging and operator routes are registered through it, so that they
+// follow the same policy.
+func (r *Router) operational(path string, exposure Exposure) *gin.RouterGroup {
+	switch exposure {
+	case ExposurePublic:
+		return r.engine.Group(path)
+	case ExposureOperators:
+		if r.jobsPassword == "" && len(r.adminUserIDs) == 0 {
+			return nil
+		}
+		return r.engine.Group(path, r.authMiddleware.RequireOperator(r.jobsPassword, r.adminUserIDs))
+	default:
+		return nil
+	}
+}
+
 func (r *Router) setupRoutes() {
+	r.engine.NoRoute(func(c *gin.Context) {
+		httputil.Fail(c, apperror.New(http.StatusNotFound, httputil.CodeNotFound, "route not found"))
+	})
+
 	r.engine.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
+	if r.healthHandler != nil {
+		r.engine.GET("/health/live", r.healthHandler.Live)
+		r.engine.GET("/health/ready", r.healthHandler.Ready)
+	}
+	if r.jwksHandler != nil {
+		r.engine.GET("/.well-known/jwks.json", r.jwksHandler.Keys)
+	}
 
 	// Swagger documentation
-	r.engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	if docs := r.operational("/swagger", r.swaggerExposure); docs != nil {
+		docs.GET("/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	}
+
+	// Operator pages, outside the API
+	if ops := r.operational("/admin", ExposureOperators); ops != nil {
+		if r.jobHandler != nil {
+			ops.GET("/jobs", r.jobHandler.Dashboard)
+			ops.POST("/jobs/:name/run", r.jobHandler.Run)
+		}
+		if r.clientHandler != nil {
+			ops.GET("/clients", r.clientHandler.Adoption)
+		}
+		if r.kpiHandler != nil {
+			ops.GET("/kpis", r.kpiHandler.Reports)
+			ops.GET("/kpis/metrics", r.kpiHandler.Metrics)
+		}
+		if r.metricsHandler != nil {
+			ops.GET("/metrics", r.metricsHandler.Metrics)
+		}
+		if r.deprecationHandler != nil {
+			ops.GET("/deprecations", r.deprecationHandler.Report)
+			ops.GET("/deprecations/metrics", r.deprecationHandler.Metrics)
+		}
+	}
 
 	api := r.engine.Group("/api/v1")
+	api.Use(middleware.AuditClient())
+	if r.clientRecorder != nil {
+		api.Use(middleware.ClientTelemetry(r.clientRecorder))
+	}
+	if len(r.deprecations) > 0 {
+		api.Use(middleware.Deprecated(r.deprecations, r.deprecationRecorder))
+	}
+	if r.kpiRecorder != nil {
+		api.Use(middleware.KPI(r.kpiRecorder, map[string]middleware.KPIOutcome{
+			"POST /api/v1/sync":            {Total: entity.KPISyncs, Failure: entity.KPISyncFailures},
+			"POST /api/v1/upload/:note_id": {Total: entity.KPIPhotoUploads, Failure: entity.KPIPhotoUploadFailures},
+		}))
+	}
+	if r.changePublisher != nil {
+		api.Use(middleware.ChangeFeed(r.changePublisher, r.logger))
+	}
 	{
+		api.GET("/errors", r.errorHandler.List)
+
+		if r.realtimeHandler != nil {
+			ws := api.Group("/ws")
+			ws.Use(r.requireAuth()...)
+			ws.GET("", r.realtimeHandler.Connect)
+		}
+
 		auth := api.Group("/auth")
 		{
 			auth.POST("/register", r.authHandler.Register)
 			auth.POST("/login", r.authHandler.Login)
 			auth.POST("/refresh", r.authHandler.Refresh)
 			auth.POST("/logout", r.authMiddleware.RequireAuth(), r.authHandler.Logout)
+			if r.passwordReset {
+				auth.POST("/forgot-password", r.authHandler.ForgotPassword)
+				auth.POST("/reset-password", r.authHandler.ResetPassword)
+			}
+			auth.GET("/sso/:org", r.authHandler.SSOLogin)
+			auth.GET("/sso/:org/callback", r.authHandler.SSOCallback)
+			auth.POST("/oauth/:provider", r.authHandler.SocialLogin)
+			if r.demoHandler != nil {
+				auth.GET("/demo", r.demoHandler.Credentials)
+			}
 		}
 
 		notes := api.Group("/notes")
-		notes.Use(r.authMiddleware.RequireAuth())
+		notes.Use(r.requireAuth()...)
 		{
 			notes.POST("", r.noteHandler.Create)
 			notes.GET("", r.noteHandler.List)
+			notes.GET("/summaries", r.summaryHandler.List)
+			notes.GET("/nearby", r.noteHandler.Nearby)
+			notes.GET("/coverage", r.noteHandler.Coverage)
+			notes.GET("/search", r.noteHandler.Search)
+			notes.GET("/export", r.noteHandler.Export)
+			notes.POST("/import", r.noteHandler.Import)
 			notes.GET("/:id", r.noteHandler.Get)
 			notes.PUT("/:id", r.noteHandler.Update)
 			notes.DELETE("/:id", r.noteHandler.Delete)
+			notes.POST("/:id/restore", r.noteHandler.Restore)
+			notes.GET("/:id/revisions", r.noteHandler.Revisions)
+			notes.POST("/:id/revisions/:rev/restore", r.noteHandler.RestoreRevision)
+			notes.POST("/merge", r.noteHandler.Merge)
+			notes.POST("/exists", r.noteHandler.Exists)
+			notes.POST("/:id/tags", r.noteHandler.AddTags)
+			notes.DELETE("/:id/tags", r.noteHandler.RemoveTags)
+			notes.GET("/:id/shares", r.shareHandler.List)
+			notes.GET("/:id/lint", r.privacyHandler.Lint)
+			notes.GET("/:id/qrcode", r.labelHandler.QRCode)
+			if r.documentHandler != nil {
+				notes.POST("/:id/collaboration", r.documentHandler.Enable)
+				notes.GET("/:id/collaboration", r.documentHandler.Get)
+				notes.DELETE("/:id/collaboration", r.documentHandler.Disable)
+				notes.POST("/:id/collaboration/updates", r.documentHandler.Update)
+			}
+			if r.semanticHandler != nil {
+				notes.GET("/semantic-search", r.semanticHandler.Search)
+				notes.GET("/:id/related", r.semanticHandler.Related)
+			}
 		}
 
 		sync := api.Group("/sync")
-		sync.Use(r.authMiddleware.RequireAuth())
+		sync.Use(r.requireAuth()...)
 		{
 			sync.POST("", r.syncHandler.Sync)
+			sync.GET("/capabilities", r.syncHandler.Capabilities)
+			sync.GET("/changes", r.syncHandler.Changes)
+			sync.GET("/photos/manifest", r.syncHandler.PhotoManifest)
+			sync.PUT("/scope", r.syncHandler.UpdateScope)
+			sync.GET("/conflicts", r.syncHandler.Conflicts)
+			sync.POST("/conflicts/replay", r.syncHandler.ReplayConflict)
 		}
 
 		upload := api.Group("/upload")
-		upload.Use(r.authMiddleware.RequireAuth())
+		if r.latencies != nil {
+			upload.Use(middleware.LatencyBudget(observability.OpUpload, r.uploadBudget, r.latencies, r.logger))
+		}
+		upload.Use(r.requireAuth()...)
+		if r.uploadLimiter != nil {
+			upload.Use(r.uploadLimiter.Limit())
+		}
 		{
 			upload.POST("/:note_id", r.uploadHandler.Upload)
+			upload.POST("/:note_id/audio", r.uploadHandler.UploadAudio)
+			upload.POST("/:note_id/batch", r.uploadHandler.UploadBatch)
 		}
 
 		photos := api.Group("/photos")
-		photos.Use(r.authMiddleware.RequireAuth())
+		photos.Use(r.requireAuth()...)
 		{
 			photos.DELETE("/:id", r.uploadHandler.Delete)
+			photos.PUT("/:id/sharing", r.uploadHandler.SetSharing)
+		}
+
+		attachments := api.Group("/attachments")
+		attachments.Use(r.requireAuth()...)
+		{
+			attachments.DELETE("/:id", r.uploadHandler.DeleteAttachment)
+		}
+
+		shares := api.Group("/shares")
+		shares.Use(r.requireAuth()...)
+		{
+			shares.POST("", r.shareHandler.Bulk)
+		}
+
+		teams := api.Group("/teams")
+		teams.Use(r.requireAuth()...)
+		{
+			teams.POST("", r.teamHandler.Create)
+			teams.GET("", r.teamHandler.List)
+			teams.GET("/:id/members", r.teamHandler.Members)
+			teams.POST("/:id/members", r.teamHandler.Invite)
+			teams.PATCH("/:id/members/:user_id", r.teamHandler.UpdateRole)
+			teams.DELETE("/:id/members/:user_id", r.teamHandler.RemoveMember)
+			teams.POST("/:id/accept", r.teamHandler.Accept)
+		}
+
+		quality := api.Group("/quality")
+		quality.Use(r.requireAuth()...)
+		{
+			quality.GET("/rules", r.qualityHandler.GetRules)
+			quality.PUT("/rules", r.qualityHandler.UpdateRules)
+			quality.GET("/report", r.qualityHandler.Report)
+		}
+
+		me := api.Group("/me")
+		me.Use(r.requireAuth()...)
+		{
+			me.DELETE("", r.accountHandler.Delete)
+			me.GET("/devices/:id/usage", r.deviceHandler.Usage)
+			me.POST("/devices/:id/author", r.deviceHandler.SetAuthor)
+			me.DELETE("/devices/:id/author", r.deviceHandler.ClearAuthor)
+			me.PUT("/devices/:id/push-token", r.deviceHandler.SetPushToken)
+			me.DELETE("/devices/:id/push-token", r.deviceHandler.ClearPushToken)
+			me.GET("/preferences", r.preferenceHandler.Get)
+			me.PUT("/preferences", r.preferenceHandler.Update)
+			me.GET("/stats", r.statsHandler.Get)
+			me.GET("/sessions", r.authHandler.Sessions)
+			me.POST("/sessions/revoke-others", r.authHandler.RevokeOtherSessions)
+			if r.auditHandler != nil {
+				me.GET("/audit", r.auditHandler.List)
+			}
+		}
+
+		admin := api.Group("/admin")
+		admin.Use(r.requireAuth()...)
+		{
+			admin.GET("/alerts", r.alertHandler.List)
+			admin.PUT("/orgs/:slug/sso", r.authHandler.UpdateSSOSettings)
 		}
 	}
 }
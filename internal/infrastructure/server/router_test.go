@@ -0,0 +1,161 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/auth"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/middleware"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/server"
+)
+
+// TestRouter_ErrorShape sends every API route a request it must reject,
+// unauthenticated and with an empty body, and checks each error has the
+// same shape. Handlers are nil: protected routes stop at authentication,
+// public ones at validation or, past it, in the recovery middleware.
+func TestRouter_ErrorShape(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := server.NewRouter(server.RouterConfig{
+		AuthMiddleware: middleware.NewAuthMiddleware(auth.NewJWTService("secret", time.Minute), nil),
+		PasswordReset:  true,
+		Logger:         zap.NewNop(),
+	})
+	engine := router.Engine()
+
+	// Routes that succeed without a user.
+	public := map[string]bool{
+		"GET /api/v1/errors": true,
+	}
+
+	requests := []*http.Request{httptest.NewRequest(http.MethodGet, "/api/v1/no-such-route", nil)}
+	for _, route := range engine.Routes() {
+		if !strings.HasPrefix(route.Path, "/api/v1") || public[route.Method+" "+route.Path] {
+			continue
+		}
+		var path []string
+		for _, part := range strings.Split(route.Path, "/") {
+			switch {
+			case strings.HasPrefix(part, ":"):
+				part = uuid.NewString()
+			case strings.HasPrefix(part, "*"):
+				part = "x"
+			}
+			path = append(path, part)
+		}
+		req := httptest.NewRequest(route.Method, strings.Join(path, "/"), strings.NewReader("{}"))
+		req.Header.Set("Content-Type", "application/json")
+		requests = append(requests, req)
+	}
+	require.Greater(t, len(requests), 50)
+
+	for _, req := range requests {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+
+		route := req.Method + " " + req.URL.Path
+		require.GreaterOrEqual(t, w.Code, http.StatusBadRequest, route)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json", route)
+
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), route)
+		assert.NotEmpty(t, body["code"], route)
+		assert.NotEmpty(t, body["message"], route)
+		assert.NotEmpty(t, body["request_id"], route)
+		assert.Equal(t, body["message"], body["error"], route)
+		for key := range body {
+			assert.Contains(t, []string{"code", "message", "request_id", "details", "error"}, key, route)
+		}
+	}
+}
+
+func TestRouter_ValidationDetails(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := server.NewRouter(server.RouterConfig{
+		AuthMiddleware: middleware.NewAuthMiddleware(auth.NewJWTService("secret", time.Minute), nil),
+		Logger:         zap.NewNop(),
+	}).Engine()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader(`{"email": "not-an-email"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var body struct {
+		Code    string `json:"code"`
+		Details []struct {
+			Field string `json:"field"`
+			Rule  string `json:"rule"`
+		} `json:"details"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "VALIDATION_ERROR", body.Code)
+	require.NotEmpty(t, body.Details)
+	assert.Equal(t, "email", body.Details[0].Field, "fields are named by their json tag")
+}
+
+type tokenVersions struct{}
+
+func (tokenVersions) GetTokenVersion(context.Context, uuid.UUID) (int, error) {
+	return 0, nil
+}
+
+func TestRouter_OperationalExposure(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtSvc := auth.NewJWTService("secret", time.Minute)
+	admin, user := uuid.New(), uuid.New()
+	token := func(userID uuid.UUID) string {
+		t.Helper()
+		tok, _, err := jwtSvc.GenerateAccessToken(auth.AccessToken{UserID: userID}, time.Minute)
+		require.NoError(t, err)
+		return "Bearer " + tok
+	}
+
+	tests := []struct {
+		name     string
+		exposure server.Exposure
+		password string
+		admins   []uuid.UUID
+		auth     func(*http.Request)
+		status   int
+	}{
+		{"public", server.ExposurePublic, "", nil, func(*http.Request) {}, http.StatusOK},
+		{"off", server.ExposureOff, "ops-password", nil, func(*http.Request) {}, http.StatusNotFound},
+		{"operators without credentials configured", server.ExposureOperators, "", nil, func(*http.Request) {}, http.StatusNotFound},
+		{"operators, anonymous", server.ExposureOperators, "ops-password", []uuid.UUID{admin}, func(*http.Request) {}, http.StatusUnauthorized},
+		{"operators, ops password", server.ExposureOperators, "ops-password", nil, func(r *http.Request) { r.SetBasicAuth("ops", "ops-password") }, http.StatusOK},
+		{"operators, wrong password", server.ExposureOperators, "ops-password", nil, func(r *http.Request) { r.SetBasicAuth("ops", "guess") }, http.StatusUnauthorized},
+		{"operators, admin token", server.ExposureOperators, "", []uuid.UUID{admin}, func(r *http.Request) { r.Header.Set("Authorization", token(admin)) }, http.StatusOK},
+		{"operators, other user's token", server.ExposureOperators, "", []uuid.UUID{admin}, func(r *http.Request) { r.Header.Set("Authorization", token(user)) }, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := server.NewRouter(server.RouterConfig{
+				AuthMiddleware:  middleware.NewAuthMiddleware(jwtSvc, tokenVersions{}),
+				Logger:          zap.NewNop(),
+				JobsPassword:    tt.password,
+				AdminUserIDs:    tt.admins,
+				SwaggerExposure: tt.exposure,
+			}).Engine()
+
+			req := httptest.NewRequest(http.MethodGet, "/swagger/index.html", nil)
+			tt.auth(req)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
@@ -15,21 +15,23 @@ type Server struct {
 }
 
 type ServerConfig struct {
-	Port            int
-	ReadTimeout     time.Duration
-	WriteTimeout    time.Duration
-	ShutdownTimeout time.Duration
-	Handler         http.Handler
-	Logger          *zap.Logger
+	Port              int
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	ShutdownTimeout   time.Duration
+	Handler           http.Handler
+	Logger            *zap.Logger
 }
 
 func NewServer(cfg ServerConfig) *Server {
 	return &Server{
 		httpServer: &http.Server{
-			Addr:         fmt.Sprintf(":%d", cfg.Port),
-			Handler:      cfg.Handler,
-			ReadTimeout:  cfg.ReadTimeout,
-			WriteTimeout: cfg.WriteTimeout,
+			Addr:              fmt.Sprintf(":%d", cfg.Port),
+			Handler:           cfg.Handler,
+			ReadTimeout:       cfg.ReadTimeout,
+			ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+			WriteTimeout:      cfg.WriteTimeout,
 		},
 		logger: cfg.Logger,
 	}
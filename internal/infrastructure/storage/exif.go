@@ -0,0 +1,215 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"time"
+
+	adapterstorage "github.com/marcos-nsantos/field-notes-backend/internal/adapter/storage"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+)
+
+// EXIF tags read from a JPEG's APP1 segment. Only the capture time and GPS
+// position are extracted; everything else is ignored.
+const (
+	tagExifIFD            = 0x8769
+	tagGPSIFD             = 0x8825
+	tagDateTimeOriginal   = 0x9003
+	tagOffsetTimeOriginal = 0x9011
+	tagGPSLatitudeRef     = 0x0001
+	tagGPSLatitude        = 0x0002
+	tagGPSLongitudeRef    = 0x0003
+	tagGPSLongitude       = 0x0004
+
+	exifTypeASCII    = 2
+	exifTypeRational = 5
+
+	exifDateLayout = "2006:01:02 15:04:05"
+)
+
+// readExif returns the capture time and position recorded in a JPEG's EXIF
+// data. Missing or malformed fields are left unset.
+func readExif(data []byte) adapterstorage.ImageMetadata {
+	var meta adapterstorage.ImageMetadata
+
+	tiff, ok := exifSegment(data)
+	if !ok {
+		return meta
+	}
+
+	var order binary.ByteOrder
+	switch {
+	case bytes.HasPrefix(tiff, []byte("II*\x00")):
+		order = binary.LittleEndian
+	case bytes.HasPrefix(tiff, []byte("MM\x00*")):
+		order = binary.BigEndian
+	default:
+		return meta
+	}
+
+	r := &exifReader{data: tiff, order: order}
+	ifd0 := r.entries(order.Uint32(tiff[4:8]))
+
+	if entry, ok := ifd0[tagExifIFD]; ok {
+		exif := r.entries(r.long(entry))
+		meta.TakenAt = takenAt(r.ascii(exif[tagDateTimeOriginal]), r.ascii(exif[tagOffsetTimeOriginal]))
+	}
+
+	if entry, ok := ifd0[tagGPSIFD]; ok {
+		gps := r.entries(r.long(entry))
+		lat, latOK := r.degrees(gps[tagGPSLatitude])
+		lng, lngOK := r.degrees(gps[tagGPSLongitude])
+		if latOK && lngOK {
+			if r.ascii(gps[tagGPSLatitudeRef]) == "S" {
+				lat = -lat
+			}
+			if r.ascii(gps[tagGPSLongitudeRef]) == "W" {
+				lng = -lng
+			}
+			if loc := valueobject.NewLocation(lat, lng, nil, nil); loc.IsValid() && (lat != 0 || lng != 0) {
+				meta.Location = loc
+			}
+		}
+	}
+
+	return meta
+}
+
+// exifSegment returns the TIFF structure inside the JPEG's Exif APP1 segment.
+func exifSegment(data []byte) ([]byte, bool) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, false
+	}
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return nil, false
+		}
+		marker := data[pos+1]
+		// Start of scan: image data follows and there are no more headers.
+		if marker == 0xDA || marker == 0xD9 {
+			return nil, false
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return nil, false
+		}
+		segment := data[pos+4 : end]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) && len(segment) >= 14 {
+			return segment[6:], true
+		}
+		pos = end
+	}
+	return nil, false
+}
+
+// takenAt parses DateTimeOriginal. Without an OffsetTimeOriginal the camera's
+// time zone is unknown and the time is taken as UTC.
+func takenAt(value, offset string) *time.Time {
+	if value == "" {
+		return nil
+	}
+
+	loc := time.UTC
+	if offset != "" {
+		if t, err := time.Parse("-07:00", offset); err == nil {
+			_, seconds := t.Zone()
+			loc = time.FixedZone(offset, seconds)
+		}
+	}
+
+	t, err := time.ParseInLocation(exifDateLayout, value, loc)
+	if err != nil {
+		return nil
+	}
+	t = t.UTC()
+	return &t
+}
+
+type exifEntry struct {
+	typ   uint16
+	count uint32
+	// value holds the raw four value bytes: the value itself when it fits,
+	// otherwise the offset to it.
+	value []byte
+}
+
+type exifReader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+// entries reads the IFD at offset, keyed by tag. An offset outside the data
+// yields no entries.
+func (r *exifReader) entries(offset uint32) map[uint16]exifEntry {
+	entries := make(map[uint16]exifEntry)
+	if int64(offset)+2 > int64(len(r.data)) {
+		return entries
+	}
+
+	count := int(r.order.Uint16(r.data[offset:]))
+	start := int(offset) + 2
+	for i := range count {
+		pos := start + i*12
+		if pos+12 > len(r.data) {
+			break
+		}
+		entries[r.order.Uint16(r.data[pos:])] = exifEntry{
+			typ:   r.order.Uint16(r.data[pos+2:]),
+			count: r.order.Uint32(r.data[pos+4:]),
+			value: r.data[pos+8 : pos+12],
+		}
+	}
+	return entries
+}
+
+func (r *exifReader) long(e exifEntry) uint32 {
+	if e.value == nil {
+		return 0
+	}
+	return r.order.Uint32(e.value)
+}
+
+// bytes returns the n bytes of the entry's value, inline or at its offset.
+func (r *exifReader) bytes(e exifEntry, n int) []byte {
+	if n <= 4 {
+		return e.value[:n]
+	}
+	offset := int64(r.order.Uint32(e.value))
+	if offset+int64(n) > int64(len(r.data)) {
+		return nil
+	}
+	return r.data[offset : offset+int64(n)]
+}
+
+func (r *exifReader) ascii(e exifEntry) string {
+	if e.typ != exifTypeASCII || e.count == 0 || e.count > 64 {
+		return ""
+	}
+	return strings.TrimRight(string(r.bytes(e, int(e.count))), "\x00 ")
+}
+
+// degrees reads a GPS coordinate stored as degrees, minutes and seconds.
+func (r *exifReader) degrees(e exifEntry) (float64, bool) {
+	if e.typ != exifTypeRational || e.count != 3 {
+		return 0, false
+	}
+	raw := r.bytes(e, 24)
+	if raw == nil {
+		return 0, false
+	}
+
+	var parts [3]float64
+	for i := range parts {
+		num := r.order.Uint32(raw[i*8:])
+		den := r.order.Uint32(raw[i*8+4:])
+		if den == 0 {
+			return 0, false
+		}
+		parts[i] = float64(num) / float64(den)
+	}
+	return parts[0] + parts[1]/60 + parts[2]/3600, true
+}
@@ -2,42 +2,67 @@ package storage
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"image"
 	"image/jpeg"
 	"image/png"
 	"io"
+	"time"
 
 	"github.com/disintegration/imaging"
+
+	adapterstorage "github.com/marcos-nsantos/field-notes-backend/internal/adapter/storage"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/observability"
 )
 
 const (
 	MaxImageWidth  = 2048
 	MaxImageHeight = 2048
 	JPEGQuality    = 85
+
+	// Thumbnails fit within a square of these sides.
+	SmallThumbnailSize  = 256
+	MediumThumbnailSize = 1024
+	ThumbnailQuality    = 80
 )
 
+var thumbnailSizes = []struct {
+	name string
+	side int
+}{
+	{entity.ThumbnailSmall, SmallThumbnailSize},
+	{entity.ThumbnailMedium, MediumThumbnailSize},
+}
+
 type ImageProcessorImpl struct {
 	maxWidth  int
 	maxHeight int
 	quality   int
+	latencies *observability.Latencies
 }
 
-func NewImageProcessor() *ImageProcessorImpl {
+// NewImageProcessor records how long decoding, resizing and encoding take in
+// latencies, which may be nil.
+func NewImageProcessor(latencies *observability.Latencies) *ImageProcessorImpl {
 	return &ImageProcessorImpl{
 		maxWidth:  MaxImageWidth,
 		maxHeight: MaxImageHeight,
 		quality:   JPEGQuality,
+		latencies: latencies,
 	}
 }
 
-func (p *ImageProcessorImpl) Process(reader io.Reader) (io.Reader, int64, int, int, error) {
+func (p *ImageProcessorImpl) Process(ctx context.Context, reader io.Reader) (io.Reader, int64, int, int, error) {
 	data, err := io.ReadAll(reader)
 	if err != nil {
 		return nil, 0, 0, 0, fmt.Errorf("reading image: %w", err)
 	}
 
+	start := time.Now()
 	img, format, err := image.Decode(bytes.NewReader(data))
+	p.latencies.Since(ctx, observability.OpImageDecode, start)
 	if err != nil {
 		return bytes.NewReader(data), int64(len(data)), 0, 0, nil
 	}
@@ -53,7 +78,9 @@ func (p *ImageProcessorImpl) Process(reader io.Reader) (io.Reader, int64, int, i
 	}
 
 	if needsResize {
+		start := time.Now()
 		img = imaging.Fit(img, p.maxWidth, p.maxHeight, imaging.Lanczos)
+		p.latencies.Since(ctx, observability.OpImageResize, start)
 		bounds = img.Bounds()
 		width = bounds.Dx()
 		height = bounds.Dy()
@@ -61,6 +88,7 @@ func (p *ImageProcessorImpl) Process(reader io.Reader) (io.Reader, int64, int, i
 
 	var buf bytes.Buffer
 
+	defer p.latencies.Since(ctx, observability.OpImageEncode, time.Now())
 	switch format {
 	case "png":
 		if err := png.Encode(&buf, img); err != nil {
@@ -74,3 +102,38 @@ func (p *ImageProcessorImpl) Process(reader io.Reader) (io.Reader, int64, int, i
 
 	return bytes.NewReader(buf.Bytes()), int64(buf.Len()), width, height, nil
 }
+
+func (p *ImageProcessorImpl) Thumbnails(ctx context.Context, reader io.Reader) ([]adapterstorage.Thumbnail, error) {
+	start := time.Now()
+	img, _, err := image.Decode(reader)
+	p.latencies.Since(ctx, observability.OpImageDecode, start)
+	if err != nil {
+		return nil, nil
+	}
+
+	defer p.latencies.Since(ctx, observability.OpThumbnail, time.Now())
+	thumbnails := make([]adapterstorage.Thumbnail, 0, len(thumbnailSizes))
+	for _, size := range thumbnailSizes {
+		thumb := imaging.Fit(img, size.side, size.side, imaging.Lanczos)
+
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: ThumbnailQuality}); err != nil {
+			return nil, fmt.Errorf("encoding %s thumbnail: %w", size.name, err)
+		}
+
+		bounds := thumb.Bounds()
+		thumbnails = append(thumbnails, adapterstorage.Thumbnail{
+			Size:   size.name,
+			Data:   buf.Bytes(),
+			Width:  bounds.Dx(),
+			Height: bounds.Dy(),
+		})
+	}
+	return thumbnails, nil
+}
+
+// Metadata reads EXIF data from JPEG images. Process re-encodes resized
+// images without it, so this must be given the original upload.
+func (p *ImageProcessorImpl) Metadata(data []byte) adapterstorage.ImageMetadata {
+	return readExif(data)
+}
@@ -0,0 +1,164 @@
+package storage_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/storage"
+)
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := range width {
+		img.Set(x, x*height/width, color.RGBA{R: 255, A: 255})
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestImageProcessor_Thumbnails(t *testing.T) {
+	p := storage.NewImageProcessor(nil)
+
+	t.Run("fits each size keeping the aspect ratio", func(t *testing.T) {
+		thumbs, err := p.Thumbnails(t.Context(), bytes.NewReader(encodePNG(t, 2000, 1000)))
+
+		require.NoError(t, err)
+		require.Len(t, thumbs, 2)
+		assert.Equal(t, entity.ThumbnailSmall, thumbs[0].Size)
+		assert.Equal(t, 256, thumbs[0].Width)
+		assert.Equal(t, 128, thumbs[0].Height)
+		assert.Equal(t, entity.ThumbnailMedium, thumbs[1].Size)
+		assert.Equal(t, 1024, thumbs[1].Width)
+
+		_, format, err := image.Decode(bytes.NewReader(thumbs[0].Data))
+		require.NoError(t, err)
+		assert.Equal(t, "jpeg", format)
+	})
+
+	t.Run("does not upscale small images", func(t *testing.T) {
+		thumbs, err := p.Thumbnails(t.Context(), bytes.NewReader(encodePNG(t, 100, 50)))
+
+		require.NoError(t, err)
+		require.Len(t, thumbs, 2)
+		assert.Equal(t, 100, thumbs[1].Width)
+	})
+
+	t.Run("returns none for data that is not an image", func(t *testing.T) {
+		thumbs, err := p.Thumbnails(t.Context(), bytes.NewReader([]byte("not an image")))
+
+		require.NoError(t, err)
+		assert.Empty(t, thumbs)
+	})
+}
+
+// exifJPEG returns a JPEG header carrying EXIF DateTimeOriginal (with the
+// offset, if any) and a GPS position given as degrees, minutes and seconds.
+func exifJPEG(order binary.ByteOrder, taken, offset, latRef string, lat [3]float64, lngRef string, lng [3]float64) []byte {
+	tiff := make([]byte, 256)
+	if order == binary.LittleEndian {
+		copy(tiff, "II*\x00")
+	} else {
+		copy(tiff, "MM\x00*")
+	}
+	order.PutUint32(tiff[4:], 8)
+
+	entry := func(pos int, tag, typ uint16, count, value uint32) {
+		order.PutUint16(tiff[pos:], tag)
+		order.PutUint16(tiff[pos+2:], typ)
+		order.PutUint32(tiff[pos+4:], count)
+		order.PutUint32(tiff[pos+8:], value)
+	}
+	inline := func(pos int, tag uint16, s string) {
+		entry(pos, tag, 2, uint32(len(s)+1), 0)
+		copy(tiff[pos+8:], s)
+	}
+	rationals := func(at int, dms [3]float64) {
+		for i, v := range dms {
+			order.PutUint32(tiff[at+i*8:], uint32(v*100))
+			order.PutUint32(tiff[at+i*8+4:], 100)
+		}
+	}
+
+	// IFD0 at 8: pointers to the Exif IFD (38) and the GPS IFD (68).
+	order.PutUint16(tiff[8:], 2)
+	entry(10, 0x8769, 4, 1, 38)
+	entry(22, 0x8825, 4, 1, 68)
+
+	// Exif IFD at 38: DateTimeOriginal at 122, OffsetTimeOriginal at 142.
+	order.PutUint16(tiff[38:], 2)
+	entry(40, 0x9003, 2, 20, 122)
+	entry(52, 0x9011, 2, 7, 142)
+	copy(tiff[122:], taken)
+	copy(tiff[142:], offset)
+
+	// GPS IFD at 68: latitude at 150, longitude at 174.
+	order.PutUint16(tiff[68:], 4)
+	inline(70, 0x0001, latRef)
+	entry(82, 0x0002, 5, 3, 150)
+	inline(94, 0x0003, lngRef)
+	entry(106, 0x0004, 5, 3, 174)
+	rationals(150, lat)
+	rationals(174, lng)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	jpeg := []byte{0xFF, 0xD8, 0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(jpeg[4:], uint16(len(segment)+2))
+	jpeg = append(jpeg, segment...)
+	return append(jpeg, 0xFF, 0xDA, 0, 2)
+}
+
+func TestImageProcessor_Metadata(t *testing.T) {
+	p := storage.NewImageProcessor(nil)
+
+	t.Run("reads the capture time and position", func(t *testing.T) {
+		for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+			data := exifJPEG(order, "2026:05:01 10:30:00", "+01:00", "N", [3]float64{38, 42, 30}, "W", [3]float64{9, 8, 15.5})
+
+			meta := p.Metadata(data)
+
+			require.NotNil(t, meta.TakenAt, order)
+			assert.Equal(t, time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC), *meta.TakenAt)
+			require.NotNil(t, meta.Location, order)
+			assert.InDelta(t, 38.708333, meta.Location.Latitude, 1e-6)
+			assert.InDelta(t, -9.137639, meta.Location.Longitude, 1e-6)
+		}
+	})
+
+	t.Run("takes times without an offset as UTC", func(t *testing.T) {
+		data := exifJPEG(binary.LittleEndian, "2026:05:01 10:30:00", "", "S", [3]float64{33, 52, 0}, "E", [3]float64{151, 12, 0})
+
+		meta := p.Metadata(data)
+
+		require.NotNil(t, meta.TakenAt)
+		assert.Equal(t, time.Date(2026, 5, 1, 10, 30, 0, 0, time.UTC), *meta.TakenAt)
+		assert.InDelta(t, -33.866667, meta.Location.Latitude, 1e-6)
+		assert.InDelta(t, 151.2, meta.Location.Longitude, 1e-6)
+	})
+
+	t.Run("ignores images without exif", func(t *testing.T) {
+		meta := p.Metadata(encodePNG(t, 10, 10))
+
+		assert.Nil(t, meta.TakenAt)
+		assert.Nil(t, meta.Location)
+	})
+
+	t.Run("ignores truncated exif", func(t *testing.T) {
+		data := exifJPEG(binary.LittleEndian, "2026:05:01 10:30:00", "", "N", [3]float64{38, 42, 30}, "W", [3]float64{9, 8, 15})
+
+		meta := p.Metadata(data[:40])
+
+		assert.Nil(t, meta.TakenAt)
+		assert.Nil(t, meta.Location)
+	})
+}
@@ -9,8 +9,10 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 
 	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/config"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/observability"
 )
 
 type S3Storage struct {
@@ -18,9 +20,24 @@ type S3Storage struct {
 	presigner *s3.PresignClient
 	bucket    string
 	publicURL string
+	sse       types.ServerSideEncryption
+	kmsKeyID  string
+	latencies *observability.Latencies
 }
 
-func NewS3Storage(cfg config.S3Config) (*S3Storage, error) {
+// NewS3Storage records the latency of each S3 call in latencies, which may
+// be nil.
+func NewS3Storage(cfg config.S3Config, latencies *observability.Latencies) (*S3Storage, error) {
+	sse := types.ServerSideEncryption(cfg.SSE)
+	switch sse {
+	case "", types.ServerSideEncryptionAes256, types.ServerSideEncryptionAwsKms:
+	default:
+		return nil, fmt.Errorf("unsupported S3_SSE %q: use AES256 or aws:kms", cfg.SSE)
+	}
+	if cfg.KMSKeyID != "" && sse != types.ServerSideEncryptionAwsKms {
+		return nil, fmt.Errorf("S3_KMS_KEY_ID requires S3_SSE=aws:kms")
+	}
+
 	opts := []func(*s3.Options){
 		func(o *s3.Options) {
 			o.Region = cfg.Region
@@ -47,21 +64,102 @@ func NewS3Storage(cfg config.S3Config) (*S3Storage, error) {
 		presigner: presigner,
 		bucket:    cfg.Bucket,
 		publicURL: cfg.PublicURL,
+		sse:       sse,
+		kmsKeyID:  cfg.KMSKeyID,
+		latencies: latencies,
 	}, nil
 }
 
-func (s *S3Storage) Upload(ctx context.Context, key string, reader io.Reader, contentType string, size int64) error {
-	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+// VerifyBucket checks that the bucket encrypts objects by default, with the
+// configured KMS key when there is one, and that ACLs are disabled so every
+// object is owned by the bucket owner.
+func (s *S3Storage) VerifyBucket(ctx context.Context) error {
+	enc, err := s.client.GetBucketEncryption(ctx, &s3.GetBucketEncryptionInput{
+		Bucket: aws.String(s.bucket),
+	})
+	if err != nil {
+		return fmt.Errorf("reading encryption of bucket %s: %w", s.bucket, err)
+	}
+	if !s.encryptsByDefault(enc.ServerSideEncryptionConfiguration) {
+		return fmt.Errorf("bucket %s does not encrypt objects by default as required by S3_SSE=%q", s.bucket, s.sse)
+	}
+
+	own, err := s.client.GetBucketOwnershipControls(ctx, &s3.GetBucketOwnershipControlsInput{
+		Bucket: aws.String(s.bucket),
+	})
+	if err != nil {
+		return fmt.Errorf("reading ownership controls of bucket %s: %w", s.bucket, err)
+	}
+	if !ownerEnforced(own.OwnershipControls) {
+		return fmt.Errorf("bucket %s must use object ownership %s", s.bucket, types.ObjectOwnershipBucketOwnerEnforced)
+	}
+	return nil
+}
+
+// Ping checks that the bucket is reachable with the configured credentials.
+func (s *S3Storage) Ping(ctx context.Context) error {
+	_, err := s.client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(s.bucket),
+	})
+	if err != nil {
+		return fmt.Errorf("reaching bucket %s: %w", s.bucket, err)
+	}
+	return nil
+}
+
+func (s *S3Storage) encryptsByDefault(cfg *types.ServerSideEncryptionConfiguration) bool {
+	if cfg == nil {
+		return false
+	}
+	for _, rule := range cfg.Rules {
+		def := rule.ApplyServerSideEncryptionByDefault
+		if def == nil || def.SSEAlgorithm == "" {
+			continue
+		}
+		if s.sse == types.ServerSideEncryptionAwsKms && def.SSEAlgorithm != types.ServerSideEncryptionAwsKms {
+			continue
+		}
+		if s.kmsKeyID != "" && aws.ToString(def.KMSMasterKeyID) != s.kmsKeyID {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func ownerEnforced(controls *types.OwnershipControls) bool {
+	if controls == nil {
+		return false
+	}
+	for _, rule := range controls.Rules {
+		if rule.ObjectOwnership == types.ObjectOwnershipBucketOwnerEnforced {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *S3Storage) Upload(ctx context.Context, key string, reader io.Reader, contentType string, size int64) (string, error) {
+	input := &s3.PutObjectInput{
 		Bucket:        aws.String(s.bucket),
 		Key:           aws.String(key),
 		Body:          reader,
 		ContentType:   aws.String(contentType),
 		ContentLength: aws.Int64(size),
-	})
+	}
+	if s.sse != "" {
+		input.ServerSideEncryption = s.sse
+	}
+	if s.kmsKeyID != "" {
+		input.SSEKMSKeyId = aws.String(s.kmsKeyID)
+	}
+
+	defer s.latencies.Since(ctx, observability.OpS3PutObject, time.Now())
+	out, err := s.client.PutObject(ctx, input)
 	if err != nil {
-		return fmt.Errorf("uploading to s3: %w", err)
+		return "", fmt.Errorf("uploading to s3: %w", err)
 	}
-	return nil
+	return string(out.ServerSideEncryption), nil
 }
 
 func (s *S3Storage) GetURL(key string) string {
@@ -72,6 +170,7 @@ func (s *S3Storage) GetURL(key string) string {
 }
 
 func (s *S3Storage) GetSignedURL(key string, expiry time.Duration) (string, error) {
+	defer s.latencies.Since(context.Background(), observability.OpS3Presign, time.Now())
 	presignResult, err := s.presigner.PresignGetObject(context.Background(), &s3.GetObjectInput{
 		Bucket: aws.String(s.bucket),
 		Key:    aws.String(key),
@@ -85,6 +184,7 @@ func (s *S3Storage) GetSignedURL(key string, expiry time.Duration) (string, erro
 }
 
 func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	defer s.latencies.Since(ctx, observability.OpS3DeleteObject, time.Now())
 	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(s.bucket),
 		Key:    aws.String(key),
@@ -94,3 +194,21 @@ func (s *S3Storage) Delete(ctx context.Context, key string) error {
 	}
 	return nil
 }
+
+func (s *S3Storage) Download(ctx context.Context, key string) ([]byte, error) {
+	defer s.latencies.Since(ctx, observability.OpS3GetObject, time.Now())
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("downloading from s3: %w", err)
+	}
+	defer out.Body.Close()
+
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading s3 object: %w", err)
+	}
+	return data, nil
+}
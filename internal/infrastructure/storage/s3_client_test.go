@@ -0,0 +1,118 @@
+package storage_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/config"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/storage"
+)
+
+const (
+	kmsEncryption = `<ServerSideEncryptionConfiguration><Rule><ApplyServerSideEncryptionByDefault>` +
+		`<SSEAlgorithm>aws:kms</SSEAlgorithm><KMSMasterKeyID>arn:aws:kms:key/notes</KMSMasterKeyID>` +
+		`</ApplyServerSideEncryptionByDefault></Rule></ServerSideEncryptionConfiguration>`
+	ownerEnforced  = `<OwnershipControls><Rule><ObjectOwnership>BucketOwnerEnforced</ObjectOwnership></Rule></OwnershipControls>`
+	ownerPreferred = `<OwnershipControls><Rule><ObjectOwnership>BucketOwnerPreferred</ObjectOwnership></Rule></OwnershipControls>`
+	noEncryption   = `<Error><Code>ServerSideEncryptionConfigurationNotFoundError</Code><Message>not found</Message></Error>`
+)
+
+// fakeS3 answers the bucket encryption and ownership calls. An empty body
+// answers 404 with noEncryption.
+func fakeS3(t *testing.T, encryption, ownership string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		query := r.URL.Query()
+		switch {
+		case query.Has("encryption") && encryption == "":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(noEncryption))
+		case query.Has("encryption"):
+			_, _ = w.Write([]byte(encryption))
+		case query.Has("ownershipControls"):
+			_, _ = w.Write([]byte(ownership))
+		default:
+			t.Errorf("unexpected S3 request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestStorage(t *testing.T, endpoint, sse, kmsKeyID string) *storage.S3Storage {
+	t.Helper()
+	s, err := storage.NewS3Storage(config.S3Config{
+		Endpoint:        endpoint,
+		Region:          "us-east-1",
+		Bucket:          "notes",
+		AccessKeyID:     "key",
+		SecretAccessKey: "secret",
+		UsePathStyle:    true,
+		SSE:             sse,
+		KMSKeyID:        kmsKeyID,
+	}, nil)
+	require.NoError(t, err)
+	return s
+}
+
+func TestS3Storage_VerifyBucket(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("accepts an encrypted bucket with ACLs disabled", func(t *testing.T) {
+		srv := fakeS3(t, kmsEncryption, ownerEnforced)
+		s := newTestStorage(t, srv.URL, "aws:kms", "arn:aws:kms:key/notes")
+
+		assert.NoError(t, s.VerifyBucket(ctx))
+	})
+
+	t.Run("fails without default encryption", func(t *testing.T) {
+		srv := fakeS3(t, "", ownerEnforced)
+		s := newTestStorage(t, srv.URL, "AES256", "")
+
+		err := s.VerifyBucket(ctx)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "reading encryption of bucket notes")
+	})
+
+	t.Run("fails when the default uses another KMS key", func(t *testing.T) {
+		srv := fakeS3(t, kmsEncryption, ownerEnforced)
+		s := newTestStorage(t, srv.URL, "aws:kms", "arn:aws:kms:key/other")
+
+		err := s.VerifyBucket(ctx)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not encrypt objects by default")
+	})
+
+	t.Run("fails when ACLs are enabled", func(t *testing.T) {
+		srv := fakeS3(t, kmsEncryption, ownerPreferred)
+		s := newTestStorage(t, srv.URL, "aws:kms", "")
+
+		err := s.VerifyBucket(ctx)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "BucketOwnerEnforced")
+	})
+}
+
+func TestNewS3Storage(t *testing.T) {
+	t.Run("rejects unknown encryption", func(t *testing.T) {
+		_, err := storage.NewS3Storage(config.S3Config{Bucket: "notes", SSE: "rot13"}, nil)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("requires aws:kms for a KMS key", func(t *testing.T) {
+		_, err := storage.NewS3Storage(config.S3Config{Bucket: "notes", SSE: "AES256", KMSKeyID: "arn:aws:kms:key/notes"}, nil)
+
+		assert.Error(t, err)
+	})
+}
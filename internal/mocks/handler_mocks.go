@@ -11,15 +11,30 @@ package mocks
 
 import (
 	context "context"
+	crypto "crypto"
+	io "io"
 	reflect "reflect"
 
 	uuid "github.com/google/uuid"
 	entity "github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
 	pagination "github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+	anomaly "github.com/marcos-nsantos/field-notes-backend/internal/usecase/anomaly"
+	audit "github.com/marcos-nsantos/field-notes-backend/internal/usecase/audit"
 	auth "github.com/marcos-nsantos/field-notes-backend/internal/usecase/auth"
+	demo "github.com/marcos-nsantos/field-notes-backend/internal/usecase/demo"
+	health "github.com/marcos-nsantos/field-notes-backend/internal/usecase/health"
+	jobs "github.com/marcos-nsantos/field-notes-backend/internal/usecase/jobs"
 	note "github.com/marcos-nsantos/field-notes-backend/internal/usecase/note"
+	preference "github.com/marcos-nsantos/field-notes-backend/internal/usecase/preference"
+	quality "github.com/marcos-nsantos/field-notes-backend/internal/usecase/quality"
+	realtime "github.com/marcos-nsantos/field-notes-backend/internal/usecase/realtime"
+	semantic "github.com/marcos-nsantos/field-notes-backend/internal/usecase/semantic"
+	share "github.com/marcos-nsantos/field-notes-backend/internal/usecase/share"
+	summary "github.com/marcos-nsantos/field-notes-backend/internal/usecase/summary"
 	sync "github.com/marcos-nsantos/field-notes-backend/internal/usecase/sync"
+	team "github.com/marcos-nsantos/field-notes-backend/internal/usecase/team"
 	upload "github.com/marcos-nsantos/field-notes-backend/internal/usecase/upload"
+	usage "github.com/marcos-nsantos/field-notes-backend/internal/usecase/usage"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -47,6 +62,36 @@ func (m *MockAuthService) EXPECT() *MockAuthServiceMockRecorder {
 	return m.recorder
 }
 
+// CompleteSSO mocks base method.
+func (m *MockAuthService) CompleteSSO(ctx context.Context, input auth.SSOCallbackInput) (*auth.TokenPair, *entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteSSO", ctx, input)
+	ret0, _ := ret[0].(*auth.TokenPair)
+	ret1, _ := ret[1].(*entity.User)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CompleteSSO indicates an expected call of CompleteSSO.
+func (mr *MockAuthServiceMockRecorder) CompleteSSO(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteSSO", reflect.TypeOf((*MockAuthService)(nil).CompleteSSO), ctx, input)
+}
+
+// ForgotPassword mocks base method.
+func (m *MockAuthService) ForgotPassword(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForgotPassword", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForgotPassword indicates an expected call of ForgotPassword.
+func (mr *MockAuthServiceMockRecorder) ForgotPassword(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPassword", reflect.TypeOf((*MockAuthService)(nil).ForgotPassword), ctx, email)
+}
+
 // Login mocks base method.
 func (m *MockAuthService) Login(ctx context.Context, input auth.LoginInput) (*auth.TokenPair, *entity.User, error) {
 	m.ctrl.T.Helper()
@@ -78,18 +123,18 @@ func (mr *MockAuthServiceMockRecorder) Logout(ctx, userID any) *gomock.Call {
 }
 
 // Refresh mocks base method.
-func (m *MockAuthService) Refresh(ctx context.Context, refreshToken string) (*auth.TokenPair, error) {
+func (m *MockAuthService) Refresh(ctx context.Context, refreshToken, ip string) (*auth.TokenPair, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Refresh", ctx, refreshToken)
+	ret := m.ctrl.Call(m, "Refresh", ctx, refreshToken, ip)
 	ret0, _ := ret[0].(*auth.TokenPair)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Refresh indicates an expected call of Refresh.
-func (mr *MockAuthServiceMockRecorder) Refresh(ctx, refreshToken any) *gomock.Call {
+func (mr *MockAuthServiceMockRecorder) Refresh(ctx, refreshToken, ip any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockAuthService)(nil).Refresh), ctx, refreshToken)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockAuthService)(nil).Refresh), ctx, refreshToken, ip)
 }
 
 // Register mocks base method.
@@ -107,6 +152,136 @@ func (mr *MockAuthServiceMockRecorder) Register(ctx, input any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockAuthService)(nil).Register), ctx, input)
 }
 
+// ResetPassword mocks base method.
+func (m *MockAuthService) ResetPassword(ctx context.Context, input auth.ResetPasswordInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockAuthServiceMockRecorder) ResetPassword(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockAuthService)(nil).ResetPassword), ctx, input)
+}
+
+// RevokeOtherSessions mocks base method.
+func (m *MockAuthService) RevokeOtherSessions(ctx context.Context, userID, sessionID uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOtherSessions", ctx, userID, sessionID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeOtherSessions indicates an expected call of RevokeOtherSessions.
+func (mr *MockAuthServiceMockRecorder) RevokeOtherSessions(ctx, userID, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOtherSessions", reflect.TypeOf((*MockAuthService)(nil).RevokeOtherSessions), ctx, userID, sessionID)
+}
+
+// Sessions mocks base method.
+func (m *MockAuthService) Sessions(ctx context.Context, userID uuid.UUID) ([]entity.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sessions", ctx, userID)
+	ret0, _ := ret[0].([]entity.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sessions indicates an expected call of Sessions.
+func (mr *MockAuthServiceMockRecorder) Sessions(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sessions", reflect.TypeOf((*MockAuthService)(nil).Sessions), ctx, userID)
+}
+
+// SocialLogin mocks base method.
+func (m *MockAuthService) SocialLogin(ctx context.Context, input auth.SocialLoginInput) (*auth.TokenPair, *entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SocialLogin", ctx, input)
+	ret0, _ := ret[0].(*auth.TokenPair)
+	ret1, _ := ret[1].(*entity.User)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SocialLogin indicates an expected call of SocialLogin.
+func (mr *MockAuthServiceMockRecorder) SocialLogin(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SocialLogin", reflect.TypeOf((*MockAuthService)(nil).SocialLogin), ctx, input)
+}
+
+// StartSSO mocks base method.
+func (m *MockAuthService) StartSSO(ctx context.Context, input auth.SSOStartInput) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartSSO", ctx, input)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartSSO indicates an expected call of StartSSO.
+func (mr *MockAuthServiceMockRecorder) StartSSO(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartSSO", reflect.TypeOf((*MockAuthService)(nil).StartSSO), ctx, input)
+}
+
+// UpdateSSOSettings mocks base method.
+func (m *MockAuthService) UpdateSSOSettings(ctx context.Context, input auth.SSOSettingsInput) (*entity.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSSOSettings", ctx, input)
+	ret0, _ := ret[0].(*entity.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSSOSettings indicates an expected call of UpdateSSOSettings.
+func (mr *MockAuthServiceMockRecorder) UpdateSSOSettings(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSSOSettings", reflect.TypeOf((*MockAuthService)(nil).UpdateSSOSettings), ctx, input)
+}
+
+// MockNoteSummaryService is a mock of NoteSummaryService interface.
+type MockNoteSummaryService struct {
+	ctrl     *gomock.Controller
+	recorder *MockNoteSummaryServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockNoteSummaryServiceMockRecorder is the mock recorder for MockNoteSummaryService.
+type MockNoteSummaryServiceMockRecorder struct {
+	mock *MockNoteSummaryService
+}
+
+// NewMockNoteSummaryService creates a new mock instance.
+func NewMockNoteSummaryService(ctrl *gomock.Controller) *MockNoteSummaryService {
+	mock := &MockNoteSummaryService{ctrl: ctrl}
+	mock.recorder = &MockNoteSummaryServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNoteSummaryService) EXPECT() *MockNoteSummaryServiceMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockNoteSummaryService) List(ctx context.Context, input summary.ListInput) ([]entity.NoteSummary, *pagination.Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, input)
+	ret0, _ := ret[0].([]entity.NoteSummary)
+	ret1, _ := ret[1].(*pagination.Info)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockNoteSummaryServiceMockRecorder) List(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockNoteSummaryService)(nil).List), ctx, input)
+}
+
 // MockNoteService is a mock of NoteService interface.
 type MockNoteService struct {
 	ctrl     *gomock.Controller
@@ -131,6 +306,36 @@ func (m *MockNoteService) EXPECT() *MockNoteServiceMockRecorder {
 	return m.recorder
 }
 
+// AddTags mocks base method.
+func (m *MockNoteService) AddTags(ctx context.Context, input note.TagsInput) (*entity.Note, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTags", ctx, input)
+	ret0, _ := ret[0].(*entity.Note)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddTags indicates an expected call of AddTags.
+func (mr *MockNoteServiceMockRecorder) AddTags(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTags", reflect.TypeOf((*MockNoteService)(nil).AddTags), ctx, input)
+}
+
+// Coverage mocks base method.
+func (m *MockNoteService) Coverage(ctx context.Context, userID uuid.UUID, zoom int) (*entity.NoteCoverage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Coverage", ctx, userID, zoom)
+	ret0, _ := ret[0].(*entity.NoteCoverage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Coverage indicates an expected call of Coverage.
+func (mr *MockNoteServiceMockRecorder) Coverage(ctx, userID, zoom any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Coverage", reflect.TypeOf((*MockNoteService)(nil).Coverage), ctx, userID, zoom)
+}
+
 // Create mocks base method.
 func (m *MockNoteService) Create(ctx context.Context, input note.CreateInput) (*entity.Note, error) {
 	m.ctrl.T.Helper()
@@ -160,6 +365,35 @@ func (mr *MockNoteServiceMockRecorder) Delete(ctx, userID, noteID any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockNoteService)(nil).Delete), ctx, userID, noteID)
 }
 
+// Exists mocks base method.
+func (m *MockNoteService) Exists(ctx context.Context, input note.ExistsInput) (*note.ExistsResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", ctx, input)
+	ret0, _ := ret[0].(*note.ExistsResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockNoteServiceMockRecorder) Exists(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockNoteService)(nil).Exists), ctx, input)
+}
+
+// Export mocks base method.
+func (m *MockNoteService) Export(ctx context.Context, input note.ExportInput, fn func([]entity.Note) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, input, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockNoteServiceMockRecorder) Export(ctx, input, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockNoteService)(nil).Export), ctx, input, fn)
+}
+
 // GetByID mocks base method.
 func (m *MockNoteService) GetByID(ctx context.Context, userID, noteID uuid.UUID) (*entity.Note, error) {
 	m.ctrl.T.Helper()
@@ -175,6 +409,21 @@ func (mr *MockNoteServiceMockRecorder) GetByID(ctx, userID, noteID any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockNoteService)(nil).GetByID), ctx, userID, noteID)
 }
 
+// Import mocks base method.
+func (m *MockNoteService) Import(ctx context.Context, input note.ImportInput) ([]note.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, input)
+	ret0, _ := ret[0].([]note.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockNoteServiceMockRecorder) Import(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockNoteService)(nil).Import), ctx, input)
+}
+
 // List mocks base method.
 func (m *MockNoteService) List(ctx context.Context, input note.ListInput) ([]entity.Note, *pagination.Info, error) {
 	m.ctrl.T.Helper()
@@ -191,6 +440,111 @@ func (mr *MockNoteServiceMockRecorder) List(ctx, input any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockNoteService)(nil).List), ctx, input)
 }
 
+// Merge mocks base method.
+func (m *MockNoteService) Merge(ctx context.Context, input note.MergeInput) (*entity.Note, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, input)
+	ret0, _ := ret[0].(*entity.Note)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockNoteServiceMockRecorder) Merge(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockNoteService)(nil).Merge), ctx, input)
+}
+
+// Nearby mocks base method.
+func (m *MockNoteService) Nearby(ctx context.Context, input note.NearbyInput) ([]entity.NearbyNote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Nearby", ctx, input)
+	ret0, _ := ret[0].([]entity.NearbyNote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Nearby indicates an expected call of Nearby.
+func (mr *MockNoteServiceMockRecorder) Nearby(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Nearby", reflect.TypeOf((*MockNoteService)(nil).Nearby), ctx, input)
+}
+
+// RemoveTags mocks base method.
+func (m *MockNoteService) RemoveTags(ctx context.Context, input note.TagsInput) (*entity.Note, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveTags", ctx, input)
+	ret0, _ := ret[0].(*entity.Note)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveTags indicates an expected call of RemoveTags.
+func (mr *MockNoteServiceMockRecorder) RemoveTags(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTags", reflect.TypeOf((*MockNoteService)(nil).RemoveTags), ctx, input)
+}
+
+// Restore mocks base method.
+func (m *MockNoteService) Restore(ctx context.Context, userID, noteID uuid.UUID) (*entity.Note, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, userID, noteID)
+	ret0, _ := ret[0].(*entity.Note)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockNoteServiceMockRecorder) Restore(ctx, userID, noteID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockNoteService)(nil).Restore), ctx, userID, noteID)
+}
+
+// RestoreRevision mocks base method.
+func (m *MockNoteService) RestoreRevision(ctx context.Context, input note.RestoreRevisionInput) (*entity.Note, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreRevision", ctx, input)
+	ret0, _ := ret[0].(*entity.Note)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreRevision indicates an expected call of RestoreRevision.
+func (mr *MockNoteServiceMockRecorder) RestoreRevision(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRevision", reflect.TypeOf((*MockNoteService)(nil).RestoreRevision), ctx, input)
+}
+
+// Revisions mocks base method.
+func (m *MockNoteService) Revisions(ctx context.Context, input note.RevisionsInput) ([]entity.NoteRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revisions", ctx, input)
+	ret0, _ := ret[0].([]entity.NoteRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revisions indicates an expected call of Revisions.
+func (mr *MockNoteServiceMockRecorder) Revisions(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revisions", reflect.TypeOf((*MockNoteService)(nil).Revisions), ctx, input)
+}
+
+// Search mocks base method.
+func (m *MockNoteService) Search(ctx context.Context, input note.SearchInput) ([]entity.SearchHit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, input)
+	ret0, _ := ret[0].([]entity.SearchHit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockNoteServiceMockRecorder) Search(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockNoteService)(nil).Search), ctx, input)
+}
+
 // Update mocks base method.
 func (m *MockNoteService) Update(ctx context.Context, userID, noteID uuid.UUID, input note.UpdateInput) (*entity.Note, error) {
 	m.ctrl.T.Helper()
@@ -245,55 +599,1439 @@ func (mr *MockSyncServiceMockRecorder) BatchSync(ctx, input any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchSync", reflect.TypeOf((*MockSyncService)(nil).BatchSync), ctx, input)
 }
 
-// MockUploadService is a mock of UploadService interface.
-type MockUploadService struct {
-	ctrl     *gomock.Controller
-	recorder *MockUploadServiceMockRecorder
-	isgomock struct{}
+// Capabilities mocks base method.
+func (m *MockSyncService) Capabilities() sync.Capabilities {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capabilities")
+	ret0, _ := ret[0].(sync.Capabilities)
+	return ret0
 }
 
-// MockUploadServiceMockRecorder is the mock recorder for MockUploadService.
-type MockUploadServiceMockRecorder struct {
-	mock *MockUploadService
+// Capabilities indicates an expected call of Capabilities.
+func (mr *MockSyncServiceMockRecorder) Capabilities() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capabilities", reflect.TypeOf((*MockSyncService)(nil).Capabilities))
 }
 
-// NewMockUploadService creates a new mock instance.
-func NewMockUploadService(ctrl *gomock.Controller) *MockUploadService {
-	mock := &MockUploadService{ctrl: ctrl}
-	mock.recorder = &MockUploadServiceMockRecorder{mock}
-	return mock
+// Changes mocks base method.
+func (m *MockSyncService) Changes(ctx context.Context, input sync.ChangesInput) (*sync.Changes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Changes", ctx, input)
+	ret0, _ := ret[0].(*sync.Changes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockUploadService) EXPECT() *MockUploadServiceMockRecorder {
-	return m.recorder
+// Changes indicates an expected call of Changes.
+func (mr *MockSyncServiceMockRecorder) Changes(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Changes", reflect.TypeOf((*MockSyncService)(nil).Changes), ctx, input)
 }
 
-// Delete mocks base method.
-func (m *MockUploadService) Delete(ctx context.Context, userID, photoID uuid.UUID) error {
+// ClearActiveAuthor mocks base method.
+func (m *MockSyncService) ClearActiveAuthor(ctx context.Context, userID uuid.UUID, deviceID string) (*entity.Device, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, userID, photoID)
+	ret := m.ctrl.Call(m, "ClearActiveAuthor", ctx, userID, deviceID)
+	ret0, _ := ret[0].(*entity.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClearActiveAuthor indicates an expected call of ClearActiveAuthor.
+func (mr *MockSyncServiceMockRecorder) ClearActiveAuthor(ctx, userID, deviceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearActiveAuthor", reflect.TypeOf((*MockSyncService)(nil).ClearActiveAuthor), ctx, userID, deviceID)
+}
+
+// ClearPushToken mocks base method.
+func (m *MockSyncService) ClearPushToken(ctx context.Context, userID uuid.UUID, deviceID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearPushToken", ctx, userID, deviceID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Delete indicates an expected call of Delete.
-func (mr *MockUploadServiceMockRecorder) Delete(ctx, userID, photoID any) *gomock.Call {
+// ClearPushToken indicates an expected call of ClearPushToken.
+func (mr *MockSyncServiceMockRecorder) ClearPushToken(ctx, userID, deviceID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUploadService)(nil).Delete), ctx, userID, photoID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearPushToken", reflect.TypeOf((*MockSyncService)(nil).ClearPushToken), ctx, userID, deviceID)
 }
 
-// Upload mocks base method.
-func (m *MockUploadService) Upload(ctx context.Context, input upload.UploadInput) (*upload.UploadResult, error) {
+// ListConflicts mocks base method.
+func (m *MockSyncService) ListConflicts(ctx context.Context, userID uuid.UUID) ([]entity.SyncConflict, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Upload", ctx, input)
-	ret0, _ := ret[0].(*upload.UploadResult)
+	ret := m.ctrl.Call(m, "ListConflicts", ctx, userID)
+	ret0, _ := ret[0].([]entity.SyncConflict)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Upload indicates an expected call of Upload.
-func (mr *MockUploadServiceMockRecorder) Upload(ctx, input any) *gomock.Call {
+// ListConflicts indicates an expected call of ListConflicts.
+func (mr *MockSyncServiceMockRecorder) ListConflicts(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockUploadService)(nil).Upload), ctx, input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListConflicts", reflect.TypeOf((*MockSyncService)(nil).ListConflicts), ctx, userID)
+}
+
+// PhotoManifest mocks base method.
+func (m *MockSyncService) PhotoManifest(ctx context.Context, input sync.PhotoManifestInput) (*sync.PhotoManifest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PhotoManifest", ctx, input)
+	ret0, _ := ret[0].(*sync.PhotoManifest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PhotoManifest indicates an expected call of PhotoManifest.
+func (mr *MockSyncServiceMockRecorder) PhotoManifest(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PhotoManifest", reflect.TypeOf((*MockSyncService)(nil).PhotoManifest), ctx, input)
+}
+
+// ReplayConflict mocks base method.
+func (m *MockSyncService) ReplayConflict(ctx context.Context, input sync.ReplayInput) (*entity.Note, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayConflict", ctx, input)
+	ret0, _ := ret[0].(*entity.Note)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplayConflict indicates an expected call of ReplayConflict.
+func (mr *MockSyncServiceMockRecorder) ReplayConflict(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayConflict", reflect.TypeOf((*MockSyncService)(nil).ReplayConflict), ctx, input)
+}
+
+// SetActiveAuthor mocks base method.
+func (m *MockSyncService) SetActiveAuthor(ctx context.Context, input sync.AuthorInput) (*entity.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetActiveAuthor", ctx, input)
+	ret0, _ := ret[0].(*entity.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetActiveAuthor indicates an expected call of SetActiveAuthor.
+func (mr *MockSyncServiceMockRecorder) SetActiveAuthor(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetActiveAuthor", reflect.TypeOf((*MockSyncService)(nil).SetActiveAuthor), ctx, input)
+}
+
+// SetPushToken mocks base method.
+func (m *MockSyncService) SetPushToken(ctx context.Context, input sync.PushTokenInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPushToken", ctx, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPushToken indicates an expected call of SetPushToken.
+func (mr *MockSyncServiceMockRecorder) SetPushToken(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPushToken", reflect.TypeOf((*MockSyncService)(nil).SetPushToken), ctx, input)
+}
+
+// UpdateScope mocks base method.
+func (m *MockSyncService) UpdateScope(ctx context.Context, input sync.ScopeInput) (*entity.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScope", ctx, input)
+	ret0, _ := ret[0].(*entity.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateScope indicates an expected call of UpdateScope.
+func (mr *MockSyncServiceMockRecorder) UpdateScope(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScope", reflect.TypeOf((*MockSyncService)(nil).UpdateScope), ctx, input)
+}
+
+// MockUploadService is a mock of UploadService interface.
+type MockUploadService struct {
+	ctrl     *gomock.Controller
+	recorder *MockUploadServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockUploadServiceMockRecorder is the mock recorder for MockUploadService.
+type MockUploadServiceMockRecorder struct {
+	mock *MockUploadService
+}
+
+// NewMockUploadService creates a new mock instance.
+func NewMockUploadService(ctrl *gomock.Controller) *MockUploadService {
+	mock := &MockUploadService{ctrl: ctrl}
+	mock.recorder = &MockUploadServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUploadService) EXPECT() *MockUploadServiceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockUploadService) Delete(ctx context.Context, userID, photoID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, photoID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockUploadServiceMockRecorder) Delete(ctx, userID, photoID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUploadService)(nil).Delete), ctx, userID, photoID)
+}
+
+// DeleteAttachment mocks base method.
+func (m *MockUploadService) DeleteAttachment(ctx context.Context, userID, attachmentID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAttachment", ctx, userID, attachmentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAttachment indicates an expected call of DeleteAttachment.
+func (mr *MockUploadServiceMockRecorder) DeleteAttachment(ctx, userID, attachmentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAttachment", reflect.TypeOf((*MockUploadService)(nil).DeleteAttachment), ctx, userID, attachmentID)
+}
+
+// SetShareExclusion mocks base method.
+func (m *MockUploadService) SetShareExclusion(ctx context.Context, userID, photoID uuid.UUID, excluded bool) (*entity.Photo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetShareExclusion", ctx, userID, photoID, excluded)
+	ret0, _ := ret[0].(*entity.Photo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetShareExclusion indicates an expected call of SetShareExclusion.
+func (mr *MockUploadServiceMockRecorder) SetShareExclusion(ctx, userID, photoID, excluded any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetShareExclusion", reflect.TypeOf((*MockUploadService)(nil).SetShareExclusion), ctx, userID, photoID, excluded)
+}
+
+// Upload mocks base method.
+func (m *MockUploadService) Upload(ctx context.Context, input upload.UploadInput) (*upload.UploadResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upload", ctx, input)
+	ret0, _ := ret[0].(*upload.UploadResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upload indicates an expected call of Upload.
+func (mr *MockUploadServiceMockRecorder) Upload(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockUploadService)(nil).Upload), ctx, input)
+}
+
+// UploadAudio mocks base method.
+func (m *MockUploadService) UploadAudio(ctx context.Context, input upload.AudioUploadInput) (*upload.AttachmentResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadAudio", ctx, input)
+	ret0, _ := ret[0].(*upload.AttachmentResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadAudio indicates an expected call of UploadAudio.
+func (mr *MockUploadServiceMockRecorder) UploadAudio(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadAudio", reflect.TypeOf((*MockUploadService)(nil).UploadAudio), ctx, input)
+}
+
+// UploadBatch mocks base method.
+func (m *MockUploadService) UploadBatch(ctx context.Context, input upload.BatchUploadInput) ([]upload.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadBatch", ctx, input)
+	ret0, _ := ret[0].([]upload.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadBatch indicates an expected call of UploadBatch.
+func (mr *MockUploadServiceMockRecorder) UploadBatch(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadBatch", reflect.TypeOf((*MockUploadService)(nil).UploadBatch), ctx, input)
+}
+
+// MockUsageService is a mock of UsageService interface.
+type MockUsageService struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsageServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockUsageServiceMockRecorder is the mock recorder for MockUsageService.
+type MockUsageServiceMockRecorder struct {
+	mock *MockUsageService
+}
+
+// NewMockUsageService creates a new mock instance.
+func NewMockUsageService(ctrl *gomock.Controller) *MockUsageService {
+	mock := &MockUsageService{ctrl: ctrl}
+	mock.recorder = &MockUsageServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsageService) EXPECT() *MockUsageServiceMockRecorder {
+	return m.recorder
+}
+
+// GetDeviceUsage mocks base method.
+func (m *MockUsageService) GetDeviceUsage(ctx context.Context, input usage.DeviceUsageInput) ([]entity.DeviceUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeviceUsage", ctx, input)
+	ret0, _ := ret[0].([]entity.DeviceUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeviceUsage indicates an expected call of GetDeviceUsage.
+func (mr *MockUsageServiceMockRecorder) GetDeviceUsage(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeviceUsage", reflect.TypeOf((*MockUsageService)(nil).GetDeviceUsage), ctx, input)
+}
+
+// MockAccountService is a mock of AccountService interface.
+type MockAccountService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockAccountServiceMockRecorder is the mock recorder for MockAccountService.
+type MockAccountServiceMockRecorder struct {
+	mock *MockAccountService
+}
+
+// NewMockAccountService creates a new mock instance.
+func NewMockAccountService(ctrl *gomock.Controller) *MockAccountService {
+	mock := &MockAccountService{ctrl: ctrl}
+	mock.recorder = &MockAccountServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountService) EXPECT() *MockAccountServiceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockAccountService) Delete(ctx context.Context, userID uuid.UUID) (*entity.AccountDeletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID)
+	ret0, _ := ret[0].(*entity.AccountDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAccountServiceMockRecorder) Delete(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAccountService)(nil).Delete), ctx, userID)
+}
+
+// MockStatsService is a mock of StatsService interface.
+type MockStatsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatsServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockStatsServiceMockRecorder is the mock recorder for MockStatsService.
+type MockStatsServiceMockRecorder struct {
+	mock *MockStatsService
+}
+
+// NewMockStatsService creates a new mock instance.
+func NewMockStatsService(ctrl *gomock.Controller) *MockStatsService {
+	mock := &MockStatsService{ctrl: ctrl}
+	mock.recorder = &MockStatsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatsService) EXPECT() *MockStatsServiceMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockStatsService) Get(ctx context.Context, userID uuid.UUID) (*entity.UserStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID)
+	ret0, _ := ret[0].(*entity.UserStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockStatsServiceMockRecorder) Get(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockStatsService)(nil).Get), ctx, userID)
+}
+
+// MockAlertService is a mock of AlertService interface.
+type MockAlertService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAlertServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockAlertServiceMockRecorder is the mock recorder for MockAlertService.
+type MockAlertServiceMockRecorder struct {
+	mock *MockAlertService
+}
+
+// NewMockAlertService creates a new mock instance.
+func NewMockAlertService(ctrl *gomock.Controller) *MockAlertService {
+	mock := &MockAlertService{ctrl: ctrl}
+	mock.recorder = &MockAlertServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAlertService) EXPECT() *MockAlertServiceMockRecorder {
+	return m.recorder
+}
+
+// ListAlerts mocks base method.
+func (m *MockAlertService) ListAlerts(ctx context.Context, input anomaly.ListInput) ([]entity.SecurityAlert, *pagination.Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAlerts", ctx, input)
+	ret0, _ := ret[0].([]entity.SecurityAlert)
+	ret1, _ := ret[1].(*pagination.Info)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAlerts indicates an expected call of ListAlerts.
+func (mr *MockAlertServiceMockRecorder) ListAlerts(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAlerts", reflect.TypeOf((*MockAlertService)(nil).ListAlerts), ctx, input)
+}
+
+// MockAuditService is a mock of AuditService interface.
+type MockAuditService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditServiceMockRecorder is the mock recorder for MockAuditService.
+type MockAuditServiceMockRecorder struct {
+	mock *MockAuditService
+}
+
+// NewMockAuditService creates a new mock instance.
+func NewMockAuditService(ctrl *gomock.Controller) *MockAuditService {
+	mock := &MockAuditService{ctrl: ctrl}
+	mock.recorder = &MockAuditServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditService) EXPECT() *MockAuditServiceMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockAuditService) List(ctx context.Context, input audit.ListInput) ([]entity.AuditEvent, *pagination.Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, input)
+	ret0, _ := ret[0].([]entity.AuditEvent)
+	ret1, _ := ret[1].(*pagination.Info)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockAuditServiceMockRecorder) List(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAuditService)(nil).List), ctx, input)
+}
+
+// MockClientService is a mock of ClientService interface.
+type MockClientService struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockClientServiceMockRecorder is the mock recorder for MockClientService.
+type MockClientServiceMockRecorder struct {
+	mock *MockClientService
+}
+
+// NewMockClientService creates a new mock instance.
+func NewMockClientService(ctrl *gomock.Controller) *MockClientService {
+	mock := &MockClientService{ctrl: ctrl}
+	mock.recorder = &MockClientServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClientService) EXPECT() *MockClientServiceMockRecorder {
+	return m.recorder
+}
+
+// Adoption mocks base method.
+func (m *MockClientService) Adoption(ctx context.Context, days int) ([]entity.ClientAdoption, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Adoption", ctx, days)
+	ret0, _ := ret[0].([]entity.ClientAdoption)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Adoption indicates an expected call of Adoption.
+func (mr *MockClientServiceMockRecorder) Adoption(ctx, days any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Adoption", reflect.TypeOf((*MockClientService)(nil).Adoption), ctx, days)
+}
+
+// MockDeprecationService is a mock of DeprecationService interface.
+type MockDeprecationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeprecationServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockDeprecationServiceMockRecorder is the mock recorder for MockDeprecationService.
+type MockDeprecationServiceMockRecorder struct {
+	mock *MockDeprecationService
+}
+
+// NewMockDeprecationService creates a new mock instance.
+func NewMockDeprecationService(ctrl *gomock.Controller) *MockDeprecationService {
+	mock := &MockDeprecationService{ctrl: ctrl}
+	mock.recorder = &MockDeprecationServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeprecationService) EXPECT() *MockDeprecationServiceMockRecorder {
+	return m.recorder
+}
+
+// Report mocks base method.
+func (m *MockDeprecationService) Report(ctx context.Context, days int) ([]entity.DeprecationReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Report", ctx, days)
+	ret0, _ := ret[0].([]entity.DeprecationReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Report indicates an expected call of Report.
+func (mr *MockDeprecationServiceMockRecorder) Report(ctx, days any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockDeprecationService)(nil).Report), ctx, days)
+}
+
+// MockKPIService is a mock of KPIService interface.
+type MockKPIService struct {
+	ctrl     *gomock.Controller
+	recorder *MockKPIServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockKPIServiceMockRecorder is the mock recorder for MockKPIService.
+type MockKPIServiceMockRecorder struct {
+	mock *MockKPIService
+}
+
+// NewMockKPIService creates a new mock instance.
+func NewMockKPIService(ctrl *gomock.Controller) *MockKPIService {
+	mock := &MockKPIService{ctrl: ctrl}
+	mock.recorder = &MockKPIServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKPIService) EXPECT() *MockKPIServiceMockRecorder {
+	return m.recorder
+}
+
+// Reports mocks base method.
+func (m *MockKPIService) Reports(ctx context.Context, days int) ([]entity.KPIReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reports", ctx, days)
+	ret0, _ := ret[0].([]entity.KPIReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reports indicates an expected call of Reports.
+func (mr *MockKPIServiceMockRecorder) Reports(ctx, days any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reports", reflect.TypeOf((*MockKPIService)(nil).Reports), ctx, days)
+}
+
+// MockQualityService is a mock of QualityService interface.
+type MockQualityService struct {
+	ctrl     *gomock.Controller
+	recorder *MockQualityServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockQualityServiceMockRecorder is the mock recorder for MockQualityService.
+type MockQualityServiceMockRecorder struct {
+	mock *MockQualityService
+}
+
+// NewMockQualityService creates a new mock instance.
+func NewMockQualityService(ctrl *gomock.Controller) *MockQualityService {
+	mock := &MockQualityService{ctrl: ctrl}
+	mock.recorder = &MockQualityServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQualityService) EXPECT() *MockQualityServiceMockRecorder {
+	return m.recorder
+}
+
+// GetRules mocks base method.
+func (m *MockQualityService) GetRules(ctx context.Context, userID uuid.UUID) (*entity.QualityRules, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRules", ctx, userID)
+	ret0, _ := ret[0].(*entity.QualityRules)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRules indicates an expected call of GetRules.
+func (mr *MockQualityServiceMockRecorder) GetRules(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRules", reflect.TypeOf((*MockQualityService)(nil).GetRules), ctx, userID)
+}
+
+// Report mocks base method.
+func (m *MockQualityService) Report(ctx context.Context, userID uuid.UUID) (*entity.QualityReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Report", ctx, userID)
+	ret0, _ := ret[0].(*entity.QualityReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Report indicates an expected call of Report.
+func (mr *MockQualityServiceMockRecorder) Report(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockQualityService)(nil).Report), ctx, userID)
+}
+
+// UpdateRules mocks base method.
+func (m *MockQualityService) UpdateRules(ctx context.Context, input quality.RulesInput) (*entity.QualityRules, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRules", ctx, input)
+	ret0, _ := ret[0].(*entity.QualityRules)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRules indicates an expected call of UpdateRules.
+func (mr *MockQualityServiceMockRecorder) UpdateRules(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRules", reflect.TypeOf((*MockQualityService)(nil).UpdateRules), ctx, input)
+}
+
+// MockShareService is a mock of ShareService interface.
+type MockShareService struct {
+	ctrl     *gomock.Controller
+	recorder *MockShareServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockShareServiceMockRecorder is the mock recorder for MockShareService.
+type MockShareServiceMockRecorder struct {
+	mock *MockShareService
+}
+
+// NewMockShareService creates a new mock instance.
+func NewMockShareService(ctrl *gomock.Controller) *MockShareService {
+	mock := &MockShareService{ctrl: ctrl}
+	mock.recorder = &MockShareServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShareService) EXPECT() *MockShareServiceMockRecorder {
+	return m.recorder
+}
+
+// Bulk mocks base method.
+func (m *MockShareService) Bulk(ctx context.Context, input share.BulkInput) (*share.BulkResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Bulk", ctx, input)
+	ret0, _ := ret[0].(*share.BulkResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Bulk indicates an expected call of Bulk.
+func (mr *MockShareServiceMockRecorder) Bulk(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Bulk", reflect.TypeOf((*MockShareService)(nil).Bulk), ctx, input)
+}
+
+// List mocks base method.
+func (m *MockShareService) List(ctx context.Context, userID, noteID uuid.UUID) ([]entity.NoteShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID, noteID)
+	ret0, _ := ret[0].([]entity.NoteShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockShareServiceMockRecorder) List(ctx, userID, noteID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockShareService)(nil).List), ctx, userID, noteID)
+}
+
+// MockTeamService is a mock of TeamService interface.
+type MockTeamService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTeamServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockTeamServiceMockRecorder is the mock recorder for MockTeamService.
+type MockTeamServiceMockRecorder struct {
+	mock *MockTeamService
+}
+
+// NewMockTeamService creates a new mock instance.
+func NewMockTeamService(ctrl *gomock.Controller) *MockTeamService {
+	mock := &MockTeamService{ctrl: ctrl}
+	mock.recorder = &MockTeamServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTeamService) EXPECT() *MockTeamServiceMockRecorder {
+	return m.recorder
+}
+
+// Accept mocks base method.
+func (m *MockTeamService) Accept(ctx context.Context, userID, teamID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Accept", ctx, userID, teamID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Accept indicates an expected call of Accept.
+func (mr *MockTeamServiceMockRecorder) Accept(ctx, userID, teamID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Accept", reflect.TypeOf((*MockTeamService)(nil).Accept), ctx, userID, teamID)
+}
+
+// Create mocks base method.
+func (m *MockTeamService) Create(ctx context.Context, userID uuid.UUID, name string) (*entity.UserTeam, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, name)
+	ret0, _ := ret[0].(*entity.UserTeam)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTeamServiceMockRecorder) Create(ctx, userID, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTeamService)(nil).Create), ctx, userID, name)
+}
+
+// Invite mocks base method.
+func (m *MockTeamService) Invite(ctx context.Context, input team.InviteInput) (*entity.TeamMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Invite", ctx, input)
+	ret0, _ := ret[0].(*entity.TeamMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Invite indicates an expected call of Invite.
+func (mr *MockTeamServiceMockRecorder) Invite(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Invite", reflect.TypeOf((*MockTeamService)(nil).Invite), ctx, input)
+}
+
+// List mocks base method.
+func (m *MockTeamService) List(ctx context.Context, userID uuid.UUID) ([]entity.UserTeam, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID)
+	ret0, _ := ret[0].([]entity.UserTeam)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTeamServiceMockRecorder) List(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTeamService)(nil).List), ctx, userID)
+}
+
+// Members mocks base method.
+func (m *MockTeamService) Members(ctx context.Context, userID, teamID uuid.UUID) ([]entity.TeamMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Members", ctx, userID, teamID)
+	ret0, _ := ret[0].([]entity.TeamMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Members indicates an expected call of Members.
+func (mr *MockTeamServiceMockRecorder) Members(ctx, userID, teamID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Members", reflect.TypeOf((*MockTeamService)(nil).Members), ctx, userID, teamID)
+}
+
+// RemoveMember mocks base method.
+func (m *MockTeamService) RemoveMember(ctx context.Context, userID, teamID, memberID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, userID, teamID, memberID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockTeamServiceMockRecorder) RemoveMember(ctx, userID, teamID, memberID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockTeamService)(nil).RemoveMember), ctx, userID, teamID, memberID)
+}
+
+// UpdateRole mocks base method.
+func (m *MockTeamService) UpdateRole(ctx context.Context, input team.RoleInput) (*entity.TeamMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", ctx, input)
+	ret0, _ := ret[0].(*entity.TeamMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockTeamServiceMockRecorder) UpdateRole(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockTeamService)(nil).UpdateRole), ctx, input)
+}
+
+// MockPreferenceService is a mock of PreferenceService interface.
+type MockPreferenceService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPreferenceServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockPreferenceServiceMockRecorder is the mock recorder for MockPreferenceService.
+type MockPreferenceServiceMockRecorder struct {
+	mock *MockPreferenceService
+}
+
+// NewMockPreferenceService creates a new mock instance.
+func NewMockPreferenceService(ctrl *gomock.Controller) *MockPreferenceService {
+	mock := &MockPreferenceService{ctrl: ctrl}
+	mock.recorder = &MockPreferenceServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPreferenceService) EXPECT() *MockPreferenceServiceMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockPreferenceService) Get(ctx context.Context, userID uuid.UUID) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockPreferenceServiceMockRecorder) Get(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPreferenceService)(nil).Get), ctx, userID)
+}
+
+// UnitSystem mocks base method.
+func (m *MockPreferenceService) UnitSystem(ctx context.Context, userID uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnitSystem", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnitSystem indicates an expected call of UnitSystem.
+func (mr *MockPreferenceServiceMockRecorder) UnitSystem(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnitSystem", reflect.TypeOf((*MockPreferenceService)(nil).UnitSystem), ctx, userID)
+}
+
+// Update mocks base method.
+func (m *MockPreferenceService) Update(ctx context.Context, input preference.UpdateInput) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, input)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPreferenceServiceMockRecorder) Update(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPreferenceService)(nil).Update), ctx, input)
+}
+
+// MockDemoService is a mock of DemoService interface.
+type MockDemoService struct {
+	ctrl     *gomock.Controller
+	recorder *MockDemoServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockDemoServiceMockRecorder is the mock recorder for MockDemoService.
+type MockDemoServiceMockRecorder struct {
+	mock *MockDemoService
+}
+
+// NewMockDemoService creates a new mock instance.
+func NewMockDemoService(ctrl *gomock.Controller) *MockDemoService {
+	mock := &MockDemoService{ctrl: ctrl}
+	mock.recorder = &MockDemoServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDemoService) EXPECT() *MockDemoServiceMockRecorder {
+	return m.recorder
+}
+
+// Credentials mocks base method.
+func (m *MockDemoService) Credentials() demo.Credentials {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Credentials")
+	ret0, _ := ret[0].(demo.Credentials)
+	return ret0
+}
+
+// Credentials indicates an expected call of Credentials.
+func (mr *MockDemoServiceMockRecorder) Credentials() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Credentials", reflect.TypeOf((*MockDemoService)(nil).Credentials))
+}
+
+// MockPrivacyService is a mock of PrivacyService interface.
+type MockPrivacyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPrivacyServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockPrivacyServiceMockRecorder is the mock recorder for MockPrivacyService.
+type MockPrivacyServiceMockRecorder struct {
+	mock *MockPrivacyService
+}
+
+// NewMockPrivacyService creates a new mock instance.
+func NewMockPrivacyService(ctrl *gomock.Controller) *MockPrivacyService {
+	mock := &MockPrivacyService{ctrl: ctrl}
+	mock.recorder = &MockPrivacyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPrivacyService) EXPECT() *MockPrivacyServiceMockRecorder {
+	return m.recorder
+}
+
+// Lint mocks base method.
+func (m *MockPrivacyService) Lint(ctx context.Context, userID, noteID uuid.UUID) ([]entity.PIIFinding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lint", ctx, userID, noteID)
+	ret0, _ := ret[0].([]entity.PIIFinding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lint indicates an expected call of Lint.
+func (mr *MockPrivacyServiceMockRecorder) Lint(ctx, userID, noteID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lint", reflect.TypeOf((*MockPrivacyService)(nil).Lint), ctx, userID, noteID)
+}
+
+// MockDocumentService is a mock of DocumentService interface.
+type MockDocumentService struct {
+	ctrl     *gomock.Controller
+	recorder *MockDocumentServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockDocumentServiceMockRecorder is the mock recorder for MockDocumentService.
+type MockDocumentServiceMockRecorder struct {
+	mock *MockDocumentService
+}
+
+// NewMockDocumentService creates a new mock instance.
+func NewMockDocumentService(ctrl *gomock.Controller) *MockDocumentService {
+	mock := &MockDocumentService{ctrl: ctrl}
+	mock.recorder = &MockDocumentServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDocumentService) EXPECT() *MockDocumentServiceMockRecorder {
+	return m.recorder
+}
+
+// Apply mocks base method.
+func (m *MockDocumentService) Apply(ctx context.Context, input realtime.ApplyInput) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Apply", ctx, input)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Apply indicates an expected call of Apply.
+func (mr *MockDocumentServiceMockRecorder) Apply(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockDocumentService)(nil).Apply), ctx, input)
+}
+
+// Disable mocks base method.
+func (m *MockDocumentService) Disable(ctx context.Context, userID, noteID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Disable", ctx, userID, noteID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Disable indicates an expected call of Disable.
+func (mr *MockDocumentServiceMockRecorder) Disable(ctx, userID, noteID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disable", reflect.TypeOf((*MockDocumentService)(nil).Disable), ctx, userID, noteID)
+}
+
+// Enable mocks base method.
+func (m *MockDocumentService) Enable(ctx context.Context, userID, noteID uuid.UUID) (*entity.NoteDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enable", ctx, userID, noteID)
+	ret0, _ := ret[0].(*entity.NoteDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Enable indicates an expected call of Enable.
+func (mr *MockDocumentServiceMockRecorder) Enable(ctx, userID, noteID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enable", reflect.TypeOf((*MockDocumentService)(nil).Enable), ctx, userID, noteID)
+}
+
+// Get mocks base method.
+func (m *MockDocumentService) Get(ctx context.Context, userID, noteID uuid.UUID) (*entity.NoteDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID, noteID)
+	ret0, _ := ret[0].(*entity.NoteDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockDocumentServiceMockRecorder) Get(ctx, userID, noteID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDocumentService)(nil).Get), ctx, userID, noteID)
+}
+
+// Watch mocks base method.
+func (m *MockDocumentService) Watch(noteID uuid.UUID) (<-chan entity.DocumentUpdate, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Watch", noteID)
+	ret0, _ := ret[0].(<-chan entity.DocumentUpdate)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockDocumentServiceMockRecorder) Watch(noteID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockDocumentService)(nil).Watch), noteID)
+}
+
+// MockSemanticService is a mock of SemanticService interface.
+type MockSemanticService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSemanticServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockSemanticServiceMockRecorder is the mock recorder for MockSemanticService.
+type MockSemanticServiceMockRecorder struct {
+	mock *MockSemanticService
+}
+
+// NewMockSemanticService creates a new mock instance.
+func NewMockSemanticService(ctrl *gomock.Controller) *MockSemanticService {
+	mock := &MockSemanticService{ctrl: ctrl}
+	mock.recorder = &MockSemanticServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSemanticService) EXPECT() *MockSemanticServiceMockRecorder {
+	return m.recorder
+}
+
+// Related mocks base method.
+func (m *MockSemanticService) Related(ctx context.Context, input semantic.RelatedInput) ([]entity.SearchHit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Related", ctx, input)
+	ret0, _ := ret[0].([]entity.SearchHit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Related indicates an expected call of Related.
+func (mr *MockSemanticServiceMockRecorder) Related(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Related", reflect.TypeOf((*MockSemanticService)(nil).Related), ctx, input)
+}
+
+// Search mocks base method.
+func (m *MockSemanticService) Search(ctx context.Context, input semantic.SearchInput) ([]entity.SearchHit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, input)
+	ret0, _ := ret[0].([]entity.SearchHit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockSemanticServiceMockRecorder) Search(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSemanticService)(nil).Search), ctx, input)
+}
+
+// MockHealthChecker is a mock of HealthChecker interface.
+type MockHealthChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthCheckerMockRecorder
+	isgomock struct{}
+}
+
+// MockHealthCheckerMockRecorder is the mock recorder for MockHealthChecker.
+type MockHealthCheckerMockRecorder struct {
+	mock *MockHealthChecker
+}
+
+// NewMockHealthChecker creates a new mock instance.
+func NewMockHealthChecker(ctrl *gomock.Controller) *MockHealthChecker {
+	mock := &MockHealthChecker{ctrl: ctrl}
+	mock.recorder = &MockHealthCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealthChecker) EXPECT() *MockHealthCheckerMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockHealthChecker) Check(ctx context.Context) health.Report {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx)
+	ret0, _ := ret[0].(health.Report)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockHealthCheckerMockRecorder) Check(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockHealthChecker)(nil).Check), ctx)
+}
+
+// MockKeySet is a mock of KeySet interface.
+type MockKeySet struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeySetMockRecorder
+	isgomock struct{}
+}
+
+// MockKeySetMockRecorder is the mock recorder for MockKeySet.
+type MockKeySetMockRecorder struct {
+	mock *MockKeySet
+}
+
+// NewMockKeySet creates a new mock instance.
+func NewMockKeySet(ctrl *gomock.Controller) *MockKeySet {
+	mock := &MockKeySet{ctrl: ctrl}
+	mock.recorder = &MockKeySetMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeySet) EXPECT() *MockKeySetMockRecorder {
+	return m.recorder
+}
+
+// PublicKeys mocks base method.
+func (m *MockKeySet) PublicKeys() map[string]crypto.PublicKey {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublicKeys")
+	ret0, _ := ret[0].(map[string]crypto.PublicKey)
+	return ret0
+}
+
+// PublicKeys indicates an expected call of PublicKeys.
+func (mr *MockKeySetMockRecorder) PublicKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublicKeys", reflect.TypeOf((*MockKeySet)(nil).PublicKeys))
+}
+
+// MockChangeSubscriber is a mock of ChangeSubscriber interface.
+type MockChangeSubscriber struct {
+	ctrl     *gomock.Controller
+	recorder *MockChangeSubscriberMockRecorder
+	isgomock struct{}
+}
+
+// MockChangeSubscriberMockRecorder is the mock recorder for MockChangeSubscriber.
+type MockChangeSubscriberMockRecorder struct {
+	mock *MockChangeSubscriber
+}
+
+// NewMockChangeSubscriber creates a new mock instance.
+func NewMockChangeSubscriber(ctrl *gomock.Controller) *MockChangeSubscriber {
+	mock := &MockChangeSubscriber{ctrl: ctrl}
+	mock.recorder = &MockChangeSubscriberMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChangeSubscriber) EXPECT() *MockChangeSubscriberMockRecorder {
+	return m.recorder
+}
+
+// Subscribe mocks base method.
+func (m *MockChangeSubscriber) Subscribe(userID, deviceID uuid.UUID) (<-chan entity.ChangeEvent, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", userID, deviceID)
+	ret0, _ := ret[0].(<-chan entity.ChangeEvent)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockChangeSubscriberMockRecorder) Subscribe(userID, deviceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockChangeSubscriber)(nil).Subscribe), userID, deviceID)
+}
+
+// MockPresenceService is a mock of PresenceService interface.
+type MockPresenceService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPresenceServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockPresenceServiceMockRecorder is the mock recorder for MockPresenceService.
+type MockPresenceServiceMockRecorder struct {
+	mock *MockPresenceService
+}
+
+// NewMockPresenceService creates a new mock instance.
+func NewMockPresenceService(ctrl *gomock.Controller) *MockPresenceService {
+	mock := &MockPresenceService{ctrl: ctrl}
+	mock.recorder = &MockPresenceServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPresenceService) EXPECT() *MockPresenceServiceMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockPresenceService) Close(ctx context.Context, presence entity.Presence) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", ctx, presence)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockPresenceServiceMockRecorder) Close(ctx, presence any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockPresenceService)(nil).Close), ctx, presence)
+}
+
+// Keep mocks base method.
+func (m *MockPresenceService) Keep(ctx context.Context, presence entity.Presence) ([]entity.Presence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Keep", ctx, presence)
+	ret0, _ := ret[0].([]entity.Presence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Keep indicates an expected call of Keep.
+func (mr *MockPresenceServiceMockRecorder) Keep(ctx, presence any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keep", reflect.TypeOf((*MockPresenceService)(nil).Keep), ctx, presence)
+}
+
+// Move mocks base method.
+func (m *MockPresenceService) Move(ctx context.Context, presence entity.Presence) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", ctx, presence)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockPresenceServiceMockRecorder) Move(ctx, presence any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockPresenceService)(nil).Move), ctx, presence)
+}
+
+// Open mocks base method.
+func (m *MockPresenceService) Open(ctx context.Context, presence entity.Presence) ([]entity.Presence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", ctx, presence)
+	ret0, _ := ret[0].([]entity.Presence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockPresenceServiceMockRecorder) Open(ctx, presence any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockPresenceService)(nil).Open), ctx, presence)
+}
+
+// Watch mocks base method.
+func (m *MockPresenceService) Watch(noteID uuid.UUID) (<-chan entity.PresenceEvent, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Watch", noteID)
+	ret0, _ := ret[0].(<-chan entity.PresenceEvent)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockPresenceServiceMockRecorder) Watch(noteID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockPresenceService)(nil).Watch), noteID)
+}
+
+// MockMetricsWriter is a mock of MetricsWriter interface.
+type MockMetricsWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetricsWriterMockRecorder
+	isgomock struct{}
+}
+
+// MockMetricsWriterMockRecorder is the mock recorder for MockMetricsWriter.
+type MockMetricsWriterMockRecorder struct {
+	mock *MockMetricsWriter
+}
+
+// NewMockMetricsWriter creates a new mock instance.
+func NewMockMetricsWriter(ctrl *gomock.Controller) *MockMetricsWriter {
+	mock := &MockMetricsWriter{ctrl: ctrl}
+	mock.recorder = &MockMetricsWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetricsWriter) EXPECT() *MockMetricsWriterMockRecorder {
+	return m.recorder
+}
+
+// WritePrometheus mocks base method.
+func (m *MockMetricsWriter) WritePrometheus(w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WritePrometheus", w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WritePrometheus indicates an expected call of WritePrometheus.
+func (mr *MockMetricsWriterMockRecorder) WritePrometheus(w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WritePrometheus", reflect.TypeOf((*MockMetricsWriter)(nil).WritePrometheus), w)
+}
+
+// MockJobScheduler is a mock of JobScheduler interface.
+type MockJobScheduler struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobSchedulerMockRecorder
+	isgomock struct{}
+}
+
+// MockJobSchedulerMockRecorder is the mock recorder for MockJobScheduler.
+type MockJobSchedulerMockRecorder struct {
+	mock *MockJobScheduler
+}
+
+// NewMockJobScheduler creates a new mock instance.
+func NewMockJobScheduler(ctrl *gomock.Controller) *MockJobScheduler {
+	mock := &MockJobScheduler{ctrl: ctrl}
+	mock.recorder = &MockJobSchedulerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobScheduler) EXPECT() *MockJobSchedulerMockRecorder {
+	return m.recorder
+}
+
+// History mocks base method.
+func (m *MockJobScheduler) History() []jobs.Run {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History")
+	ret0, _ := ret[0].([]jobs.Run)
+	return ret0
+}
+
+// History indicates an expected call of History.
+func (mr *MockJobSchedulerMockRecorder) History() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockJobScheduler)(nil).History))
+}
+
+// Jobs mocks base method.
+func (m *MockJobScheduler) Jobs() []jobs.Status {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Jobs")
+	ret0, _ := ret[0].([]jobs.Status)
+	return ret0
+}
+
+// Jobs indicates an expected call of Jobs.
+func (mr *MockJobSchedulerMockRecorder) Jobs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Jobs", reflect.TypeOf((*MockJobScheduler)(nil).Jobs))
+}
+
+// RunNow mocks base method.
+func (m *MockJobScheduler) RunNow(name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunNow", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RunNow indicates an expected call of RunNow.
+func (mr *MockJobSchedulerMockRecorder) RunNow(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunNow", reflect.TypeOf((*MockJobScheduler)(nil).RunNow), name)
 }
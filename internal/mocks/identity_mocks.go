@@ -0,0 +1,112 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/adapter/identity/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/adapter/identity/interfaces.go -destination=internal/mocks/identity_mocks.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	identity "github.com/marcos-nsantos/field-notes-backend/internal/adapter/identity"
+	entity "github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOIDCProvider is a mock of OIDCProvider interface.
+type MockOIDCProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockOIDCProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockOIDCProviderMockRecorder is the mock recorder for MockOIDCProvider.
+type MockOIDCProviderMockRecorder struct {
+	mock *MockOIDCProvider
+}
+
+// NewMockOIDCProvider creates a new mock instance.
+func NewMockOIDCProvider(ctrl *gomock.Controller) *MockOIDCProvider {
+	mock := &MockOIDCProvider{ctrl: ctrl}
+	mock.recorder = &MockOIDCProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOIDCProvider) EXPECT() *MockOIDCProviderMockRecorder {
+	return m.recorder
+}
+
+// AuthCodeURL mocks base method.
+func (m *MockOIDCProvider) AuthCodeURL(ctx context.Context, org *entity.Organization, state, nonce string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthCodeURL", ctx, org, state, nonce)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthCodeURL indicates an expected call of AuthCodeURL.
+func (mr *MockOIDCProviderMockRecorder) AuthCodeURL(ctx, org, state, nonce any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthCodeURL", reflect.TypeOf((*MockOIDCProvider)(nil).AuthCodeURL), ctx, org, state, nonce)
+}
+
+// Exchange mocks base method.
+func (m *MockOIDCProvider) Exchange(ctx context.Context, org *entity.Organization, code string) (*identity.IDTokenClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exchange", ctx, org, code)
+	ret0, _ := ret[0].(*identity.IDTokenClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exchange indicates an expected call of Exchange.
+func (mr *MockOIDCProviderMockRecorder) Exchange(ctx, org, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exchange", reflect.TypeOf((*MockOIDCProvider)(nil).Exchange), ctx, org, code)
+}
+
+// MockSocialVerifier is a mock of SocialVerifier interface.
+type MockSocialVerifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockSocialVerifierMockRecorder
+	isgomock struct{}
+}
+
+// MockSocialVerifierMockRecorder is the mock recorder for MockSocialVerifier.
+type MockSocialVerifierMockRecorder struct {
+	mock *MockSocialVerifier
+}
+
+// NewMockSocialVerifier creates a new mock instance.
+func NewMockSocialVerifier(ctrl *gomock.Controller) *MockSocialVerifier {
+	mock := &MockSocialVerifier{ctrl: ctrl}
+	mock.recorder = &MockSocialVerifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSocialVerifier) EXPECT() *MockSocialVerifierMockRecorder {
+	return m.recorder
+}
+
+// Verify mocks base method.
+func (m *MockSocialVerifier) Verify(ctx context.Context, idToken string) (*identity.IDTokenClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx, idToken)
+	ret0, _ := ret[0].(*identity.IDTokenClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockSocialVerifierMockRecorder) Verify(ctx, idToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockSocialVerifier)(nil).Verify), ctx, idToken)
+}
@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interfaces.go -destination=../../mocks/notification_mocks.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockNotifier is a mock of Notifier interface.
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+	isgomock struct{}
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier.
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance.
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// SecurityAlert mocks base method.
+func (m *MockNotifier) SecurityAlert(ctx context.Context, notice entity.SecurityAlertNotice) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SecurityAlert", ctx, notice)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SecurityAlert indicates an expected call of SecurityAlert.
+func (mr *MockNotifierMockRecorder) SecurityAlert(ctx, notice any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SecurityAlert", reflect.TypeOf((*MockNotifier)(nil).SecurityAlert), ctx, notice)
+}
+
+// SyncConflict mocks base method.
+func (m *MockNotifier) SyncConflict(ctx context.Context, notice entity.SyncConflictNotice) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncConflict", ctx, notice)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncConflict indicates an expected call of SyncConflict.
+func (mr *MockNotifierMockRecorder) SyncConflict(ctx, notice any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncConflict", reflect.TypeOf((*MockNotifier)(nil).SyncConflict), ctx, notice)
+}
+
+// MockPusher is a mock of Pusher interface.
+type MockPusher struct {
+	ctrl     *gomock.Controller
+	recorder *MockPusherMockRecorder
+	isgomock struct{}
+}
+
+// MockPusherMockRecorder is the mock recorder for MockPusher.
+type MockPusherMockRecorder struct {
+	mock *MockPusher
+}
+
+// NewMockPusher creates a new mock instance.
+func NewMockPusher(ctrl *gomock.Controller) *MockPusher {
+	mock := &MockPusher{ctrl: ctrl}
+	mock.recorder = &MockPusherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPusher) EXPECT() *MockPusherMockRecorder {
+	return m.recorder
+}
+
+// SilentPush mocks base method.
+func (m *MockPusher) SilentPush(ctx context.Context, device entity.Device) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SilentPush", ctx, device)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SilentPush indicates an expected call of SilentPush.
+func (mr *MockPusherMockRecorder) SilentPush(ctx, device any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SilentPush", reflect.TypeOf((*MockPusher)(nil).SilentPush), ctx, device)
+}
@@ -17,6 +17,7 @@ import (
 	uuid "github.com/google/uuid"
 	repository "github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
 	entity "github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	crdt "github.com/marcos-nsantos/field-notes-backend/internal/pkg/crdt"
 	pagination "github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -45,6 +46,20 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 	return m.recorder
 }
 
+// BumpTokenVersion mocks base method.
+func (m *MockUserRepository) BumpTokenVersion(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BumpTokenVersion", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BumpTokenVersion indicates an expected call of BumpTokenVersion.
+func (mr *MockUserRepositoryMockRecorder) BumpTokenVersion(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BumpTokenVersion", reflect.TypeOf((*MockUserRepository)(nil).BumpTokenVersion), ctx, id)
+}
+
 // Create mocks base method.
 func (m *MockUserRepository) Create(ctx context.Context, user *entity.User) error {
 	m.ctrl.T.Helper()
@@ -104,6 +119,21 @@ func (mr *MockUserRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUserRepository)(nil).GetByID), ctx, id)
 }
 
+// GetTokenVersion mocks base method.
+func (m *MockUserRepository) GetTokenVersion(ctx context.Context, id uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenVersion", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenVersion indicates an expected call of GetTokenVersion.
+func (mr *MockUserRepositoryMockRecorder) GetTokenVersion(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenVersion", reflect.TypeOf((*MockUserRepository)(nil).GetTokenVersion), ctx, id)
+}
+
 // Update mocks base method.
 func (m *MockUserRepository) Update(ctx context.Context, user *entity.User) error {
 	m.ctrl.T.Helper()
@@ -156,6 +186,21 @@ func (mr *MockNoteRepositoryMockRecorder) BatchUpsert(ctx, notes any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchUpsert", reflect.TypeOf((*MockNoteRepository)(nil).BatchUpsert), ctx, notes)
 }
 
+// Coverage mocks base method.
+func (m *MockNoteRepository) Coverage(ctx context.Context, userID uuid.UUID, zoom, limit int) (*entity.NoteCoverage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Coverage", ctx, userID, zoom, limit)
+	ret0, _ := ret[0].(*entity.NoteCoverage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Coverage indicates an expected call of Coverage.
+func (mr *MockNoteRepositoryMockRecorder) Coverage(ctx, userID, zoom, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Coverage", reflect.TypeOf((*MockNoteRepository)(nil).Coverage), ctx, userID, zoom, limit)
+}
+
 // Create mocks base method.
 func (m *MockNoteRepository) Create(ctx context.Context, note *entity.Note) error {
 	m.ctrl.T.Helper()
@@ -170,6 +215,35 @@ func (mr *MockNoteRepositoryMockRecorder) Create(ctx, note any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockNoteRepository)(nil).Create), ctx, note)
 }
 
+// Existing mocks base method.
+func (m *MockNoteRepository) Existing(ctx context.Context, userID uuid.UUID, ids []uuid.UUID, clientIDs []string) ([]entity.NoteExistence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Existing", ctx, userID, ids, clientIDs)
+	ret0, _ := ret[0].([]entity.NoteExistence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Existing indicates an expected call of Existing.
+func (mr *MockNoteRepositoryMockRecorder) Existing(ctx, userID, ids, clientIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Existing", reflect.TypeOf((*MockNoteRepository)(nil).Existing), ctx, userID, ids, clientIDs)
+}
+
+// Export mocks base method.
+func (m *MockNoteRepository) Export(ctx context.Context, userID uuid.UUID, params repository.NoteListParams, batchSize int, fn func([]entity.Note) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, userID, params, batchSize, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockNoteRepositoryMockRecorder) Export(ctx, userID, params, batchSize, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockNoteRepository)(nil).Export), ctx, userID, params, batchSize, fn)
+}
+
 // GetByClientID mocks base method.
 func (m *MockNoteRepository) GetByClientID(ctx context.Context, userID uuid.UUID, clientID string) (*entity.Note, error) {
 	m.ctrl.T.Helper()
@@ -185,6 +259,21 @@ func (mr *MockNoteRepositoryMockRecorder) GetByClientID(ctx, userID, clientID an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByClientID", reflect.TypeOf((*MockNoteRepository)(nil).GetByClientID), ctx, userID, clientID)
 }
 
+// GetByClientIDs mocks base method.
+func (m *MockNoteRepository) GetByClientIDs(ctx context.Context, userID uuid.UUID, clientIDs []string) ([]entity.Note, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByClientIDs", ctx, userID, clientIDs)
+	ret0, _ := ret[0].([]entity.Note)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByClientIDs indicates an expected call of GetByClientIDs.
+func (mr *MockNoteRepositoryMockRecorder) GetByClientIDs(ctx, userID, clientIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByClientIDs", reflect.TypeOf((*MockNoteRepository)(nil).GetByClientIDs), ctx, userID, clientIDs)
+}
+
 // GetByID mocks base method.
 func (m *MockNoteRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Note, error) {
 	m.ctrl.T.Helper()
@@ -200,19 +289,34 @@ func (mr *MockNoteRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockNoteRepository)(nil).GetByID), ctx, id)
 }
 
-// GetModifiedSince mocks base method.
-func (m *MockNoteRepository) GetModifiedSince(ctx context.Context, userID uuid.UUID, since time.Time, limit int) ([]entity.Note, error) {
+// GetModifiedAfter mocks base method.
+func (m *MockNoteRepository) GetModifiedAfter(ctx context.Context, userID uuid.UUID, after pagination.Cursor, limit int, scope entity.SyncScope) ([]entity.Note, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetModifiedSince", ctx, userID, since, limit)
+	ret := m.ctrl.Call(m, "GetModifiedAfter", ctx, userID, after, limit, scope)
 	ret0, _ := ret[0].([]entity.Note)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetModifiedSince indicates an expected call of GetModifiedSince.
-func (mr *MockNoteRepositoryMockRecorder) GetModifiedSince(ctx, userID, since, limit any) *gomock.Call {
+// GetModifiedAfter indicates an expected call of GetModifiedAfter.
+func (mr *MockNoteRepositoryMockRecorder) GetModifiedAfter(ctx, userID, after, limit, scope any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModifiedAfter", reflect.TypeOf((*MockNoteRepository)(nil).GetModifiedAfter), ctx, userID, after, limit, scope)
+}
+
+// GetQualityReport mocks base method.
+func (m *MockNoteRepository) GetQualityReport(ctx context.Context, userID uuid.UUID) (*entity.QualityReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQualityReport", ctx, userID)
+	ret0, _ := ret[0].(*entity.QualityReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQualityReport indicates an expected call of GetQualityReport.
+func (mr *MockNoteRepositoryMockRecorder) GetQualityReport(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModifiedSince", reflect.TypeOf((*MockNoteRepository)(nil).GetModifiedSince), ctx, userID, since, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQualityReport", reflect.TypeOf((*MockNoteRepository)(nil).GetQualityReport), ctx, userID)
 }
 
 // List mocks base method.
@@ -231,331 +335,2332 @@ func (mr *MockNoteRepositoryMockRecorder) List(ctx, userID, params any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockNoteRepository)(nil).List), ctx, userID, params)
 }
 
-// SoftDelete mocks base method.
-func (m *MockNoteRepository) SoftDelete(ctx context.Context, id uuid.UUID) error {
+// Merge mocks base method.
+func (m *MockNoteRepository) Merge(ctx context.Context, survivor *entity.Note, mergedIDs []uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SoftDelete", ctx, id)
+	ret := m.ctrl.Call(m, "Merge", ctx, survivor, mergedIDs)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SoftDelete indicates an expected call of SoftDelete.
-func (mr *MockNoteRepositoryMockRecorder) SoftDelete(ctx, id any) *gomock.Call {
+// Merge indicates an expected call of Merge.
+func (mr *MockNoteRepositoryMockRecorder) Merge(ctx, survivor, mergedIDs any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDelete", reflect.TypeOf((*MockNoteRepository)(nil).SoftDelete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockNoteRepository)(nil).Merge), ctx, survivor, mergedIDs)
 }
 
-// Update mocks base method.
-func (m *MockNoteRepository) Update(ctx context.Context, note *entity.Note) error {
+// Nearby mocks base method.
+func (m *MockNoteRepository) Nearby(ctx context.Context, userID uuid.UUID, params repository.NoteNearbyParams) ([]entity.NearbyNote, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, note)
+	ret := m.ctrl.Call(m, "Nearby", ctx, userID, params)
+	ret0, _ := ret[0].([]entity.NearbyNote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Nearby indicates an expected call of Nearby.
+func (mr *MockNoteRepositoryMockRecorder) Nearby(ctx, userID, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Nearby", reflect.TypeOf((*MockNoteRepository)(nil).Nearby), ctx, userID, params)
+}
+
+// Purge mocks base method.
+func (m *MockNoteRepository) Purge(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, userID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Update indicates an expected call of Update.
-func (mr *MockNoteRepositoryMockRecorder) Update(ctx, note any) *gomock.Call {
+// Purge indicates an expected call of Purge.
+func (mr *MockNoteRepositoryMockRecorder) Purge(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockNoteRepository)(nil).Update), ctx, note)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockNoteRepository)(nil).Purge), ctx, userID)
 }
 
-// MockPhotoRepository is a mock of PhotoRepository interface.
-type MockPhotoRepository struct {
-	ctrl     *gomock.Controller
-	recorder *MockPhotoRepositoryMockRecorder
-	isgomock struct{}
+// PurgeDeleted mocks base method.
+func (m *MockNoteRepository) PurgeDeleted(ctx context.Context, before time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", ctx, before, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// MockPhotoRepositoryMockRecorder is the mock recorder for MockPhotoRepository.
-type MockPhotoRepositoryMockRecorder struct {
-	mock *MockPhotoRepository
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockNoteRepositoryMockRecorder) PurgeDeleted(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockNoteRepository)(nil).PurgeDeleted), ctx, before, limit)
 }
 
-// NewMockPhotoRepository creates a new mock instance.
-func NewMockPhotoRepository(ctrl *gomock.Controller) *MockPhotoRepository {
-	mock := &MockPhotoRepository{ctrl: ctrl}
-	mock.recorder = &MockPhotoRepositoryMockRecorder{mock}
-	return mock
+// Revision mocks base method.
+func (m *MockNoteRepository) Revision(ctx context.Context, noteID uuid.UUID, revision int) (*entity.NoteRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revision", ctx, noteID, revision)
+	ret0, _ := ret[0].(*entity.NoteRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockPhotoRepository) EXPECT() *MockPhotoRepositoryMockRecorder {
-	return m.recorder
+// Revision indicates an expected call of Revision.
+func (mr *MockNoteRepositoryMockRecorder) Revision(ctx, noteID, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revision", reflect.TypeOf((*MockNoteRepository)(nil).Revision), ctx, noteID, revision)
 }
 
-// Create mocks base method.
-func (m *MockPhotoRepository) Create(ctx context.Context, photo *entity.Photo) error {
+// Revisions mocks base method.
+func (m *MockNoteRepository) Revisions(ctx context.Context, noteID uuid.UUID, before, limit int) ([]entity.NoteRevision, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, photo)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "Revisions", ctx, noteID, before, limit)
+	ret0, _ := ret[0].([]entity.NoteRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Create indicates an expected call of Create.
-func (mr *MockPhotoRepositoryMockRecorder) Create(ctx, photo any) *gomock.Call {
+// Revisions indicates an expected call of Revisions.
+func (mr *MockNoteRepositoryMockRecorder) Revisions(ctx, noteID, before, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPhotoRepository)(nil).Create), ctx, photo)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revisions", reflect.TypeOf((*MockNoteRepository)(nil).Revisions), ctx, noteID, before, limit)
 }
 
-// Delete mocks base method.
-func (m *MockPhotoRepository) Delete(ctx context.Context, id uuid.UUID) error {
+// Search mocks base method.
+func (m *MockNoteRepository) Search(ctx context.Context, userID uuid.UUID, params repository.NoteSearchParams) ([]entity.SearchHit, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret := m.ctrl.Call(m, "Search", ctx, userID, params)
+	ret0, _ := ret[0].([]entity.SearchHit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockNoteRepositoryMockRecorder) Search(ctx, userID, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockNoteRepository)(nil).Search), ctx, userID, params)
+}
+
+// SetTags mocks base method.
+func (m *MockNoteRepository) SetTags(ctx context.Context, note *entity.Note) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTags", ctx, note)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Delete indicates an expected call of Delete.
-func (mr *MockPhotoRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+// SetTags indicates an expected call of SetTags.
+func (mr *MockNoteRepositoryMockRecorder) SetTags(ctx, note any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPhotoRepository)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTags", reflect.TypeOf((*MockNoteRepository)(nil).SetTags), ctx, note)
 }
 
-// DeleteByNoteID mocks base method.
-func (m *MockPhotoRepository) DeleteByNoteID(ctx context.Context, noteID uuid.UUID) error {
+// SoftDelete mocks base method.
+func (m *MockNoteRepository) SoftDelete(ctx context.Context, userID, id uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteByNoteID", ctx, noteID)
+	ret := m.ctrl.Call(m, "SoftDelete", ctx, userID, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteByNoteID indicates an expected call of DeleteByNoteID.
-func (mr *MockPhotoRepositoryMockRecorder) DeleteByNoteID(ctx, noteID any) *gomock.Call {
+// SoftDelete indicates an expected call of SoftDelete.
+func (mr *MockNoteRepositoryMockRecorder) SoftDelete(ctx, userID, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByNoteID", reflect.TypeOf((*MockPhotoRepository)(nil).DeleteByNoteID), ctx, noteID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDelete", reflect.TypeOf((*MockNoteRepository)(nil).SoftDelete), ctx, userID, id)
 }
 
-// GetByID mocks base method.
-func (m *MockPhotoRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Photo, error) {
+// Update mocks base method.
+func (m *MockNoteRepository) Update(ctx context.Context, note *entity.Note) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", ctx, id)
-	ret0, _ := ret[0].(*entity.Photo)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "Update", ctx, note)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetByID indicates an expected call of GetByID.
-func (mr *MockPhotoRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+// Update indicates an expected call of Update.
+func (mr *MockNoteRepositoryMockRecorder) Update(ctx, note any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockPhotoRepository)(nil).GetByID), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockNoteRepository)(nil).Update), ctx, note)
 }
 
-// GetByNoteID mocks base method.
-func (m *MockPhotoRepository) GetByNoteID(ctx context.Context, noteID uuid.UUID) ([]entity.Photo, error) {
+// UpdateQuality mocks base method.
+func (m *MockNoteRepository) UpdateQuality(ctx context.Context, userID, id uuid.UUID, result entity.QualityResult) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByNoteID", ctx, noteID)
-	ret0, _ := ret[0].([]entity.Photo)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "UpdateQuality", ctx, userID, id, result)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetByNoteID indicates an expected call of GetByNoteID.
-func (mr *MockPhotoRepositoryMockRecorder) GetByNoteID(ctx, noteID any) *gomock.Call {
+// UpdateQuality indicates an expected call of UpdateQuality.
+func (mr *MockNoteRepositoryMockRecorder) UpdateQuality(ctx, userID, id, result any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByNoteID", reflect.TypeOf((*MockPhotoRepository)(nil).GetByNoteID), ctx, noteID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateQuality", reflect.TypeOf((*MockNoteRepository)(nil).UpdateQuality), ctx, userID, id, result)
 }
 
-// MockDeviceRepository is a mock of DeviceRepository interface.
-type MockDeviceRepository struct {
+// MockNoteSummaryRepository is a mock of NoteSummaryRepository interface.
+type MockNoteSummaryRepository struct {
 	ctrl     *gomock.Controller
-	recorder *MockDeviceRepositoryMockRecorder
+	recorder *MockNoteSummaryRepositoryMockRecorder
 	isgomock struct{}
 }
 
-// MockDeviceRepositoryMockRecorder is the mock recorder for MockDeviceRepository.
-type MockDeviceRepositoryMockRecorder struct {
-	mock *MockDeviceRepository
+// MockNoteSummaryRepositoryMockRecorder is the mock recorder for MockNoteSummaryRepository.
+type MockNoteSummaryRepositoryMockRecorder struct {
+	mock *MockNoteSummaryRepository
 }
 
-// NewMockDeviceRepository creates a new mock instance.
-func NewMockDeviceRepository(ctrl *gomock.Controller) *MockDeviceRepository {
-	mock := &MockDeviceRepository{ctrl: ctrl}
-	mock.recorder = &MockDeviceRepositoryMockRecorder{mock}
+// NewMockNoteSummaryRepository creates a new mock instance.
+func NewMockNoteSummaryRepository(ctrl *gomock.Controller) *MockNoteSummaryRepository {
+	mock := &MockNoteSummaryRepository{ctrl: ctrl}
+	mock.recorder = &MockNoteSummaryRepositoryMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockDeviceRepository) EXPECT() *MockDeviceRepositoryMockRecorder {
+func (m *MockNoteSummaryRepository) EXPECT() *MockNoteSummaryRepositoryMockRecorder {
 	return m.recorder
 }
 
-// Create mocks base method.
-func (m *MockDeviceRepository) Create(ctx context.Context, device *entity.Device) error {
+// List mocks base method.
+func (m *MockNoteSummaryRepository) List(ctx context.Context, userID uuid.UUID, params repository.NoteSummaryListParams) ([]entity.NoteSummary, *pagination.Info, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, device)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "List", ctx, userID, params)
+	ret0, _ := ret[0].([]entity.NoteSummary)
+	ret1, _ := ret[1].(*pagination.Info)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
-// Create indicates an expected call of Create.
-func (mr *MockDeviceRepositoryMockRecorder) Create(ctx, device any) *gomock.Call {
+// List indicates an expected call of List.
+func (mr *MockNoteSummaryRepositoryMockRecorder) List(ctx, userID, params any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDeviceRepository)(nil).Create), ctx, device)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockNoteSummaryRepository)(nil).List), ctx, userID, params)
 }
 
-// GetByID mocks base method.
-func (m *MockDeviceRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Device, error) {
+// Rebuild mocks base method.
+func (m *MockNoteSummaryRepository) Rebuild(ctx context.Context) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", ctx, id)
-	ret0, _ := ret[0].(*entity.Device)
+	ret := m.ctrl.Call(m, "Rebuild", ctx)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetByID indicates an expected call of GetByID.
-func (mr *MockDeviceRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+// Rebuild indicates an expected call of Rebuild.
+func (mr *MockNoteSummaryRepositoryMockRecorder) Rebuild(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockDeviceRepository)(nil).GetByID), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rebuild", reflect.TypeOf((*MockNoteSummaryRepository)(nil).Rebuild), ctx)
 }
 
-// GetByUserAndDeviceID mocks base method.
-func (m *MockDeviceRepository) GetByUserAndDeviceID(ctx context.Context, userID uuid.UUID, deviceID string) (*entity.Device, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByUserAndDeviceID", ctx, userID, deviceID)
-	ret0, _ := ret[0].(*entity.Device)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+// MockQualityRuleRepository is a mock of QualityRuleRepository interface.
+type MockQualityRuleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockQualityRuleRepositoryMockRecorder
+	isgomock struct{}
 }
 
-// GetByUserAndDeviceID indicates an expected call of GetByUserAndDeviceID.
-func (mr *MockDeviceRepositoryMockRecorder) GetByUserAndDeviceID(ctx, userID, deviceID any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserAndDeviceID", reflect.TypeOf((*MockDeviceRepository)(nil).GetByUserAndDeviceID), ctx, userID, deviceID)
+// MockQualityRuleRepositoryMockRecorder is the mock recorder for MockQualityRuleRepository.
+type MockQualityRuleRepositoryMockRecorder struct {
+	mock *MockQualityRuleRepository
 }
 
-// Update mocks base method.
-func (m *MockDeviceRepository) Update(ctx context.Context, device *entity.Device) error {
+// NewMockQualityRuleRepository creates a new mock instance.
+func NewMockQualityRuleRepository(ctrl *gomock.Controller) *MockQualityRuleRepository {
+	mock := &MockQualityRuleRepository{ctrl: ctrl}
+	mock.recorder = &MockQualityRuleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQualityRuleRepository) EXPECT() *MockQualityRuleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetByUserID mocks base method.
+func (m *MockQualityRuleRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*entity.QualityRules, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, device)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "GetByUserID", ctx, userID)
+	ret0, _ := ret[0].(*entity.QualityRules)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Update indicates an expected call of Update.
-func (mr *MockDeviceRepositoryMockRecorder) Update(ctx, device any) *gomock.Call {
+// GetByUserID indicates an expected call of GetByUserID.
+func (mr *MockQualityRuleRepositoryMockRecorder) GetByUserID(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDeviceRepository)(nil).Update), ctx, device)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockQualityRuleRepository)(nil).GetByUserID), ctx, userID)
 }
 
 // Upsert mocks base method.
-func (m *MockDeviceRepository) Upsert(ctx context.Context, device *entity.Device) error {
+func (m *MockQualityRuleRepository) Upsert(ctx context.Context, rules *entity.QualityRules) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Upsert", ctx, device)
+	ret := m.ctrl.Call(m, "Upsert", ctx, rules)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Upsert indicates an expected call of Upsert.
-func (mr *MockDeviceRepositoryMockRecorder) Upsert(ctx, device any) *gomock.Call {
+func (mr *MockQualityRuleRepositoryMockRecorder) Upsert(ctx, rules any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockDeviceRepository)(nil).Upsert), ctx, device)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockQualityRuleRepository)(nil).Upsert), ctx, rules)
 }
 
-// MockRefreshTokenRepository is a mock of RefreshTokenRepository interface.
-type MockRefreshTokenRepository struct {
+// MockShareRepository is a mock of ShareRepository interface.
+type MockShareRepository struct {
 	ctrl     *gomock.Controller
-	recorder *MockRefreshTokenRepositoryMockRecorder
+	recorder *MockShareRepositoryMockRecorder
 	isgomock struct{}
 }
 
-// MockRefreshTokenRepositoryMockRecorder is the mock recorder for MockRefreshTokenRepository.
-type MockRefreshTokenRepositoryMockRecorder struct {
-	mock *MockRefreshTokenRepository
+// MockShareRepositoryMockRecorder is the mock recorder for MockShareRepository.
+type MockShareRepositoryMockRecorder struct {
+	mock *MockShareRepository
 }
 
-// NewMockRefreshTokenRepository creates a new mock instance.
-func NewMockRefreshTokenRepository(ctrl *gomock.Controller) *MockRefreshTokenRepository {
-	mock := &MockRefreshTokenRepository{ctrl: ctrl}
-	mock.recorder = &MockRefreshTokenRepositoryMockRecorder{mock}
+// NewMockShareRepository creates a new mock instance.
+func NewMockShareRepository(ctrl *gomock.Controller) *MockShareRepository {
+	mock := &MockShareRepository{ctrl: ctrl}
+	mock.recorder = &MockShareRepositoryMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockRefreshTokenRepository) EXPECT() *MockRefreshTokenRepositoryMockRecorder {
+func (m *MockShareRepository) EXPECT() *MockShareRepositoryMockRecorder {
 	return m.recorder
 }
 
-// Create mocks base method.
-func (m *MockRefreshTokenRepository) Create(ctx context.Context, token *entity.RefreshToken) error {
+// Apply mocks base method.
+func (m *MockShareRepository) Apply(ctx context.Context, grants []entity.NoteShare, noteIDs, revokeUserIDs []uuid.UUID) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, token)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "Apply", ctx, grants, noteIDs, revokeUserIDs)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Create indicates an expected call of Create.
-func (mr *MockRefreshTokenRepositoryMockRecorder) Create(ctx, token any) *gomock.Call {
+// Apply indicates an expected call of Apply.
+func (mr *MockShareRepositoryMockRecorder) Apply(ctx, grants, noteIDs, revokeUserIDs any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRefreshTokenRepository)(nil).Create), ctx, token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockShareRepository)(nil).Apply), ctx, grants, noteIDs, revokeUserIDs)
 }
 
-// DeleteExpired mocks base method.
-func (m *MockRefreshTokenRepository) DeleteExpired(ctx context.Context) error {
+// GetRole mocks base method.
+func (m *MockShareRepository) GetRole(ctx context.Context, noteID, userID uuid.UUID) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteExpired", ctx)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "GetRole", ctx, noteID, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteExpired indicates an expected call of DeleteExpired.
-func (mr *MockRefreshTokenRepositoryMockRecorder) DeleteExpired(ctx any) *gomock.Call {
+// GetRole indicates an expected call of GetRole.
+func (mr *MockShareRepositoryMockRecorder) GetRole(ctx, noteID, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockRefreshTokenRepository)(nil).DeleteExpired), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRole", reflect.TypeOf((*MockShareRepository)(nil).GetRole), ctx, noteID, userID)
 }
 
-// GetByToken mocks base method.
-func (m *MockRefreshTokenRepository) GetByToken(ctx context.Context, token string) (*entity.RefreshToken, error) {
+// ListByNoteID mocks base method.
+func (m *MockShareRepository) ListByNoteID(ctx context.Context, noteID uuid.UUID) ([]entity.NoteShare, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByToken", ctx, token)
-	ret0, _ := ret[0].(*entity.RefreshToken)
+	ret := m.ctrl.Call(m, "ListByNoteID", ctx, noteID)
+	ret0, _ := ret[0].([]entity.NoteShare)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetByToken indicates an expected call of GetByToken.
-func (mr *MockRefreshTokenRepositoryMockRecorder) GetByToken(ctx, token any) *gomock.Call {
+// ListByNoteID indicates an expected call of ListByNoteID.
+func (mr *MockShareRepositoryMockRecorder) ListByNoteID(ctx, noteID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByNoteID", reflect.TypeOf((*MockShareRepository)(nil).ListByNoteID), ctx, noteID)
+}
+
+// MockPhotoRepository is a mock of PhotoRepository interface.
+type MockPhotoRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPhotoRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPhotoRepositoryMockRecorder is the mock recorder for MockPhotoRepository.
+type MockPhotoRepositoryMockRecorder struct {
+	mock *MockPhotoRepository
+}
+
+// NewMockPhotoRepository creates a new mock instance.
+func NewMockPhotoRepository(ctrl *gomock.Controller) *MockPhotoRepository {
+	mock := &MockPhotoRepository{ctrl: ctrl}
+	mock.recorder = &MockPhotoRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPhotoRepository) EXPECT() *MockPhotoRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPhotoRepository) Create(ctx context.Context, photo *entity.Photo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, photo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPhotoRepositoryMockRecorder) Create(ctx, photo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPhotoRepository)(nil).Create), ctx, photo)
+}
+
+// Delete mocks base method.
+func (m *MockPhotoRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPhotoRepositoryMockRecorder) Delete(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPhotoRepository)(nil).Delete), ctx, userID, id)
+}
+
+// DeleteByNoteID mocks base method.
+func (m *MockPhotoRepository) DeleteByNoteID(ctx context.Context, noteID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByNoteID", ctx, noteID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByNoteID indicates an expected call of DeleteByNoteID.
+func (mr *MockPhotoRepositoryMockRecorder) DeleteByNoteID(ctx, noteID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByNoteID", reflect.TypeOf((*MockPhotoRepository)(nil).DeleteByNoteID), ctx, noteID)
+}
+
+// GetByClientID mocks base method.
+func (m *MockPhotoRepository) GetByClientID(ctx context.Context, userID uuid.UUID, clientID string) (*entity.Photo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByClientID", ctx, userID, clientID)
+	ret0, _ := ret[0].(*entity.Photo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByClientID indicates an expected call of GetByClientID.
+func (mr *MockPhotoRepositoryMockRecorder) GetByClientID(ctx, userID, clientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByClientID", reflect.TypeOf((*MockPhotoRepository)(nil).GetByClientID), ctx, userID, clientID)
+}
+
+// GetByClientIDs mocks base method.
+func (m *MockPhotoRepository) GetByClientIDs(ctx context.Context, userID uuid.UUID, clientIDs []string) ([]entity.Photo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByClientIDs", ctx, userID, clientIDs)
+	ret0, _ := ret[0].([]entity.Photo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByClientIDs indicates an expected call of GetByClientIDs.
+func (mr *MockPhotoRepositoryMockRecorder) GetByClientIDs(ctx, userID, clientIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByClientIDs", reflect.TypeOf((*MockPhotoRepository)(nil).GetByClientIDs), ctx, userID, clientIDs)
+}
+
+// GetByID mocks base method.
+func (m *MockPhotoRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Photo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Photo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockPhotoRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockPhotoRepository)(nil).GetByID), ctx, id)
+}
+
+// GetByNoteID mocks base method.
+func (m *MockPhotoRepository) GetByNoteID(ctx context.Context, noteID uuid.UUID) ([]entity.Photo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByNoteID", ctx, noteID)
+	ret0, _ := ret[0].([]entity.Photo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByNoteID indicates an expected call of GetByNoteID.
+func (mr *MockPhotoRepositoryMockRecorder) GetByNoteID(ctx, noteID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByNoteID", reflect.TypeOf((*MockPhotoRepository)(nil).GetByNoteID), ctx, noteID)
+}
+
+// GetByNoteIDs mocks base method.
+func (m *MockPhotoRepository) GetByNoteIDs(ctx context.Context, noteIDs []uuid.UUID) ([]entity.Photo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByNoteIDs", ctx, noteIDs)
+	ret0, _ := ret[0].([]entity.Photo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByNoteIDs indicates an expected call of GetByNoteIDs.
+func (mr *MockPhotoRepositoryMockRecorder) GetByNoteIDs(ctx, noteIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByNoteIDs", reflect.TypeOf((*MockPhotoRepository)(nil).GetByNoteIDs), ctx, noteIDs)
+}
+
+// GetBySourceChecksum mocks base method.
+func (m *MockPhotoRepository) GetBySourceChecksum(ctx context.Context, userID, noteID uuid.UUID, checksum string) (*entity.Photo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBySourceChecksum", ctx, userID, noteID, checksum)
+	ret0, _ := ret[0].(*entity.Photo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBySourceChecksum indicates an expected call of GetBySourceChecksum.
+func (mr *MockPhotoRepositoryMockRecorder) GetBySourceChecksum(ctx, userID, noteID, checksum any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySourceChecksum", reflect.TypeOf((*MockPhotoRepository)(nil).GetBySourceChecksum), ctx, userID, noteID, checksum)
+}
+
+// GetCreatedAfter mocks base method.
+func (m *MockPhotoRepository) GetCreatedAfter(ctx context.Context, userID uuid.UUID, after pagination.Cursor, limit int) ([]entity.Photo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCreatedAfter", ctx, userID, after, limit)
+	ret0, _ := ret[0].([]entity.Photo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCreatedAfter indicates an expected call of GetCreatedAfter.
+func (mr *MockPhotoRepositoryMockRecorder) GetCreatedAfter(ctx, userID, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCreatedAfter", reflect.TypeOf((*MockPhotoRepository)(nil).GetCreatedAfter), ctx, userID, after, limit)
+}
+
+// GetDeletedAfter mocks base method.
+func (m *MockPhotoRepository) GetDeletedAfter(ctx context.Context, userID uuid.UUID, after pagination.Cursor, limit int) ([]entity.PhotoTombstone, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedAfter", ctx, userID, after, limit)
+	ret0, _ := ret[0].([]entity.PhotoTombstone)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedAfter indicates an expected call of GetDeletedAfter.
+func (mr *MockPhotoRepositoryMockRecorder) GetDeletedAfter(ctx, userID, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedAfter", reflect.TypeOf((*MockPhotoRepository)(nil).GetDeletedAfter), ctx, userID, after, limit)
+}
+
+// GetDeletedByClientIDs mocks base method.
+func (m *MockPhotoRepository) GetDeletedByClientIDs(ctx context.Context, userID uuid.UUID, clientIDs []string) ([]entity.PhotoTombstone, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedByClientIDs", ctx, userID, clientIDs)
+	ret0, _ := ret[0].([]entity.PhotoTombstone)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedByClientIDs indicates an expected call of GetDeletedByClientIDs.
+func (mr *MockPhotoRepositoryMockRecorder) GetDeletedByClientIDs(ctx, userID, clientIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedByClientIDs", reflect.TypeOf((*MockPhotoRepository)(nil).GetDeletedByClientIDs), ctx, userID, clientIDs)
+}
+
+// SetExcludedFromShares mocks base method.
+func (m *MockPhotoRepository) SetExcludedFromShares(ctx context.Context, userID, id uuid.UUID, excluded bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetExcludedFromShares", ctx, userID, id, excluded)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetExcludedFromShares indicates an expected call of SetExcludedFromShares.
+func (mr *MockPhotoRepositoryMockRecorder) SetExcludedFromShares(ctx, userID, id, excluded any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetExcludedFromShares", reflect.TypeOf((*MockPhotoRepository)(nil).SetExcludedFromShares), ctx, userID, id, excluded)
+}
+
+// MockAttachmentRepository is a mock of AttachmentRepository interface.
+type MockAttachmentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttachmentRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAttachmentRepositoryMockRecorder is the mock recorder for MockAttachmentRepository.
+type MockAttachmentRepositoryMockRecorder struct {
+	mock *MockAttachmentRepository
+}
+
+// NewMockAttachmentRepository creates a new mock instance.
+func NewMockAttachmentRepository(ctrl *gomock.Controller) *MockAttachmentRepository {
+	mock := &MockAttachmentRepository{ctrl: ctrl}
+	mock.recorder = &MockAttachmentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttachmentRepository) EXPECT() *MockAttachmentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAttachmentRepository) Create(ctx context.Context, attachment *entity.Attachment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, attachment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAttachmentRepositoryMockRecorder) Create(ctx, attachment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAttachmentRepository)(nil).Create), ctx, attachment)
+}
+
+// Delete mocks base method.
+func (m *MockAttachmentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAttachmentRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAttachmentRepository)(nil).Delete), ctx, id)
+}
+
+// GetByClientID mocks base method.
+func (m *MockAttachmentRepository) GetByClientID(ctx context.Context, userID uuid.UUID, clientID string) (*entity.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByClientID", ctx, userID, clientID)
+	ret0, _ := ret[0].(*entity.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByClientID indicates an expected call of GetByClientID.
+func (mr *MockAttachmentRepositoryMockRecorder) GetByClientID(ctx, userID, clientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByClientID", reflect.TypeOf((*MockAttachmentRepository)(nil).GetByClientID), ctx, userID, clientID)
+}
+
+// GetByID mocks base method.
+func (m *MockAttachmentRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockAttachmentRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAttachmentRepository)(nil).GetByID), ctx, id)
+}
+
+// MockDeviceRepository is a mock of DeviceRepository interface.
+type MockDeviceRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeviceRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockDeviceRepositoryMockRecorder is the mock recorder for MockDeviceRepository.
+type MockDeviceRepositoryMockRecorder struct {
+	mock *MockDeviceRepository
+}
+
+// NewMockDeviceRepository creates a new mock instance.
+func NewMockDeviceRepository(ctrl *gomock.Controller) *MockDeviceRepository {
+	mock := &MockDeviceRepository{ctrl: ctrl}
+	mock.recorder = &MockDeviceRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeviceRepository) EXPECT() *MockDeviceRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDeviceRepository) Create(ctx context.Context, device *entity.Device) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, device)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDeviceRepositoryMockRecorder) Create(ctx, device any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDeviceRepository)(nil).Create), ctx, device)
+}
+
+// GetByID mocks base method.
+func (m *MockDeviceRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockDeviceRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockDeviceRepository)(nil).GetByID), ctx, id)
+}
+
+// GetByUserAndDeviceID mocks base method.
+func (m *MockDeviceRepository) GetByUserAndDeviceID(ctx context.Context, userID uuid.UUID, deviceID string) (*entity.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserAndDeviceID", ctx, userID, deviceID)
+	ret0, _ := ret[0].(*entity.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUserAndDeviceID indicates an expected call of GetByUserAndDeviceID.
+func (mr *MockDeviceRepositoryMockRecorder) GetByUserAndDeviceID(ctx, userID, deviceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserAndDeviceID", reflect.TypeOf((*MockDeviceRepository)(nil).GetByUserAndDeviceID), ctx, userID, deviceID)
+}
+
+// ListByUserID mocks base method.
+func (m *MockDeviceRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]entity.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUserID", ctx, userID)
+	ret0, _ := ret[0].([]entity.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUserID indicates an expected call of ListByUserID.
+func (mr *MockDeviceRepositoryMockRecorder) ListByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUserID", reflect.TypeOf((*MockDeviceRepository)(nil).ListByUserID), ctx, userID)
+}
+
+// RecordAccess mocks base method.
+func (m *MockDeviceRepository) RecordAccess(ctx context.Context, id uuid.UUID, access entity.DeviceAccess) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordAccess", ctx, id, access)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordAccess indicates an expected call of RecordAccess.
+func (mr *MockDeviceRepositoryMockRecorder) RecordAccess(ctx, id, access any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAccess", reflect.TypeOf((*MockDeviceRepository)(nil).RecordAccess), ctx, id, access)
+}
+
+// SetPushToken mocks base method.
+func (m *MockDeviceRepository) SetPushToken(ctx context.Context, device *entity.Device) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPushToken", ctx, device)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPushToken indicates an expected call of SetPushToken.
+func (mr *MockDeviceRepositoryMockRecorder) SetPushToken(ctx, device any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPushToken", reflect.TypeOf((*MockDeviceRepository)(nil).SetPushToken), ctx, device)
+}
+
+// Update mocks base method.
+func (m *MockDeviceRepository) Update(ctx context.Context, device *entity.Device) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, device)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDeviceRepositoryMockRecorder) Update(ctx, device any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDeviceRepository)(nil).Update), ctx, device)
+}
+
+// Upsert mocks base method.
+func (m *MockDeviceRepository) Upsert(ctx context.Context, device *entity.Device) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, device)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockDeviceRepositoryMockRecorder) Upsert(ctx, device any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockDeviceRepository)(nil).Upsert), ctx, device)
+}
+
+// MockUserStatsRepository is a mock of UserStatsRepository interface.
+type MockUserStatsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserStatsRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockUserStatsRepositoryMockRecorder is the mock recorder for MockUserStatsRepository.
+type MockUserStatsRepositoryMockRecorder struct {
+	mock *MockUserStatsRepository
+}
+
+// NewMockUserStatsRepository creates a new mock instance.
+func NewMockUserStatsRepository(ctrl *gomock.Controller) *MockUserStatsRepository {
+	mock := &MockUserStatsRepository{ctrl: ctrl}
+	mock.recorder = &MockUserStatsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserStatsRepository) EXPECT() *MockUserStatsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockUserStatsRepository) Get(ctx context.Context, userID uuid.UUID) (*entity.UserStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID)
+	ret0, _ := ret[0].(*entity.UserStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockUserStatsRepositoryMockRecorder) Get(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUserStatsRepository)(nil).Get), ctx, userID)
+}
+
+// Reconcile mocks base method.
+func (m *MockUserStatsRepository) Reconcile(ctx context.Context) ([]entity.StatsDrift, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reconcile", ctx)
+	ret0, _ := ret[0].([]entity.StatsDrift)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reconcile indicates an expected call of Reconcile.
+func (mr *MockUserStatsRepositoryMockRecorder) Reconcile(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconcile", reflect.TypeOf((*MockUserStatsRepository)(nil).Reconcile), ctx)
+}
+
+// MockDeviceUsageRepository is a mock of DeviceUsageRepository interface.
+type MockDeviceUsageRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeviceUsageRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockDeviceUsageRepositoryMockRecorder is the mock recorder for MockDeviceUsageRepository.
+type MockDeviceUsageRepositoryMockRecorder struct {
+	mock *MockDeviceUsageRepository
+}
+
+// NewMockDeviceUsageRepository creates a new mock instance.
+func NewMockDeviceUsageRepository(ctrl *gomock.Controller) *MockDeviceUsageRepository {
+	mock := &MockDeviceUsageRepository{ctrl: ctrl}
+	mock.recorder = &MockDeviceUsageRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeviceUsageRepository) EXPECT() *MockDeviceUsageRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Increment mocks base method.
+func (m *MockDeviceUsageRepository) Increment(ctx context.Context, usage []entity.DeviceUsage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Increment", ctx, usage)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Increment indicates an expected call of Increment.
+func (mr *MockDeviceUsageRepositoryMockRecorder) Increment(ctx, usage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Increment", reflect.TypeOf((*MockDeviceUsageRepository)(nil).Increment), ctx, usage)
+}
+
+// ListAbove mocks base method.
+func (m *MockDeviceUsageRepository) ListAbove(ctx context.Context, day time.Time, minBytes int64) ([]entity.DeviceUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAbove", ctx, day, minBytes)
+	ret0, _ := ret[0].([]entity.DeviceUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAbove indicates an expected call of ListAbove.
+func (mr *MockDeviceUsageRepositoryMockRecorder) ListAbove(ctx, day, minBytes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAbove", reflect.TypeOf((*MockDeviceUsageRepository)(nil).ListAbove), ctx, day, minBytes)
+}
+
+// ListByDevice mocks base method.
+func (m *MockDeviceUsageRepository) ListByDevice(ctx context.Context, deviceID uuid.UUID, from, to time.Time) ([]entity.DeviceUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByDevice", ctx, deviceID, from, to)
+	ret0, _ := ret[0].([]entity.DeviceUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByDevice indicates an expected call of ListByDevice.
+func (mr *MockDeviceUsageRepositoryMockRecorder) ListByDevice(ctx, deviceID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByDevice", reflect.TypeOf((*MockDeviceUsageRepository)(nil).ListByDevice), ctx, deviceID, from, to)
+}
+
+// MockClientUsageRepository is a mock of ClientUsageRepository interface.
+type MockClientUsageRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientUsageRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockClientUsageRepositoryMockRecorder is the mock recorder for MockClientUsageRepository.
+type MockClientUsageRepositoryMockRecorder struct {
+	mock *MockClientUsageRepository
+}
+
+// NewMockClientUsageRepository creates a new mock instance.
+func NewMockClientUsageRepository(ctrl *gomock.Controller) *MockClientUsageRepository {
+	mock := &MockClientUsageRepository{ctrl: ctrl}
+	mock.recorder = &MockClientUsageRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClientUsageRepository) EXPECT() *MockClientUsageRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteBefore mocks base method.
+func (m *MockClientUsageRepository) DeleteBefore(ctx context.Context, day time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBefore", ctx, day)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteBefore indicates an expected call of DeleteBefore.
+func (mr *MockClientUsageRepositoryMockRecorder) DeleteBefore(ctx, day any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBefore", reflect.TypeOf((*MockClientUsageRepository)(nil).DeleteBefore), ctx, day)
+}
+
+// Increment mocks base method.
+func (m *MockClientUsageRepository) Increment(ctx context.Context, usage []entity.ClientUsage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Increment", ctx, usage)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Increment indicates an expected call of Increment.
+func (mr *MockClientUsageRepositoryMockRecorder) Increment(ctx, usage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Increment", reflect.TypeOf((*MockClientUsageRepository)(nil).Increment), ctx, usage)
+}
+
+// List mocks base method.
+func (m *MockClientUsageRepository) List(ctx context.Context, from, to time.Time) ([]entity.ClientUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, from, to)
+	ret0, _ := ret[0].([]entity.ClientUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockClientUsageRepositoryMockRecorder) List(ctx, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockClientUsageRepository)(nil).List), ctx, from, to)
+}
+
+// MockDeprecationUsageRepository is a mock of DeprecationUsageRepository interface.
+type MockDeprecationUsageRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeprecationUsageRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockDeprecationUsageRepositoryMockRecorder is the mock recorder for MockDeprecationUsageRepository.
+type MockDeprecationUsageRepositoryMockRecorder struct {
+	mock *MockDeprecationUsageRepository
+}
+
+// NewMockDeprecationUsageRepository creates a new mock instance.
+func NewMockDeprecationUsageRepository(ctrl *gomock.Controller) *MockDeprecationUsageRepository {
+	mock := &MockDeprecationUsageRepository{ctrl: ctrl}
+	mock.recorder = &MockDeprecationUsageRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeprecationUsageRepository) EXPECT() *MockDeprecationUsageRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteBefore mocks base method.
+func (m *MockDeprecationUsageRepository) DeleteBefore(ctx context.Context, day time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBefore", ctx, day)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteBefore indicates an expected call of DeleteBefore.
+func (mr *MockDeprecationUsageRepositoryMockRecorder) DeleteBefore(ctx, day any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBefore", reflect.TypeOf((*MockDeprecationUsageRepository)(nil).DeleteBefore), ctx, day)
+}
+
+// Increment mocks base method.
+func (m *MockDeprecationUsageRepository) Increment(ctx context.Context, usage []entity.DeprecationUsage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Increment", ctx, usage)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Increment indicates an expected call of Increment.
+func (mr *MockDeprecationUsageRepositoryMockRecorder) Increment(ctx, usage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Increment", reflect.TypeOf((*MockDeprecationUsageRepository)(nil).Increment), ctx, usage)
+}
+
+// List mocks base method.
+func (m *MockDeprecationUsageRepository) List(ctx context.Context, from, to time.Time) ([]entity.DeprecationUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, from, to)
+	ret0, _ := ret[0].([]entity.DeprecationUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockDeprecationUsageRepositoryMockRecorder) List(ctx, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockDeprecationUsageRepository)(nil).List), ctx, from, to)
+}
+
+// MockKPIRepository is a mock of KPIRepository interface.
+type MockKPIRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockKPIRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockKPIRepositoryMockRecorder is the mock recorder for MockKPIRepository.
+type MockKPIRepositoryMockRecorder struct {
+	mock *MockKPIRepository
+}
+
+// NewMockKPIRepository creates a new mock instance.
+func NewMockKPIRepository(ctrl *gomock.Controller) *MockKPIRepository {
+	mock := &MockKPIRepository{ctrl: ctrl}
+	mock.recorder = &MockKPIRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKPIRepository) EXPECT() *MockKPIRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ActiveUsers mocks base method.
+func (m *MockKPIRepository) ActiveUsers(ctx context.Context, day time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActiveUsers", ctx, day)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActiveUsers indicates an expected call of ActiveUsers.
+func (mr *MockKPIRepositoryMockRecorder) ActiveUsers(ctx, day any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActiveUsers", reflect.TypeOf((*MockKPIRepository)(nil).ActiveUsers), ctx, day)
+}
+
+// Events mocks base method.
+func (m *MockKPIRepository) Events(ctx context.Context, day time.Time) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Events", ctx, day)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Events indicates an expected call of Events.
+func (mr *MockKPIRepositoryMockRecorder) Events(ctx, day any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Events", reflect.TypeOf((*MockKPIRepository)(nil).Events), ctx, day)
+}
+
+// IncrementEvents mocks base method.
+func (m *MockKPIRepository) IncrementEvents(ctx context.Context, day time.Time, counts map[string]int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementEvents", ctx, day, counts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementEvents indicates an expected call of IncrementEvents.
+func (mr *MockKPIRepositoryMockRecorder) IncrementEvents(ctx, day, counts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementEvents", reflect.TypeOf((*MockKPIRepository)(nil).IncrementEvents), ctx, day, counts)
+}
+
+// ListReports mocks base method.
+func (m *MockKPIRepository) ListReports(ctx context.Context, from, to time.Time) ([]entity.KPIReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReports", ctx, from, to)
+	ret0, _ := ret[0].([]entity.KPIReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReports indicates an expected call of ListReports.
+func (mr *MockKPIRepositoryMockRecorder) ListReports(ctx, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReports", reflect.TypeOf((*MockKPIRepository)(nil).ListReports), ctx, from, to)
+}
+
+// SaveReport mocks base method.
+func (m *MockKPIRepository) SaveReport(ctx context.Context, report entity.KPIReport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveReport", ctx, report)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveReport indicates an expected call of SaveReport.
+func (mr *MockKPIRepositoryMockRecorder) SaveReport(ctx, report any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveReport", reflect.TypeOf((*MockKPIRepository)(nil).SaveReport), ctx, report)
+}
+
+// MockAuthEventRepository is a mock of AuthEventRepository interface.
+type MockAuthEventRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthEventRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAuthEventRepositoryMockRecorder is the mock recorder for MockAuthEventRepository.
+type MockAuthEventRepositoryMockRecorder struct {
+	mock *MockAuthEventRepository
+}
+
+// NewMockAuthEventRepository creates a new mock instance.
+func NewMockAuthEventRepository(ctrl *gomock.Controller) *MockAuthEventRepository {
+	mock := &MockAuthEventRepository{ctrl: ctrl}
+	mock.recorder = &MockAuthEventRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthEventRepository) EXPECT() *MockAuthEventRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAuthEventRepository) Create(ctx context.Context, event *entity.AuthEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAuthEventRepositoryMockRecorder) Create(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuthEventRepository)(nil).Create), ctx, event)
+}
+
+// DeleteBefore mocks base method.
+func (m *MockAuthEventRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBefore", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteBefore indicates an expected call of DeleteBefore.
+func (mr *MockAuthEventRepositoryMockRecorder) DeleteBefore(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBefore", reflect.TypeOf((*MockAuthEventRepository)(nil).DeleteBefore), ctx, before)
+}
+
+// ListSince mocks base method.
+func (m *MockAuthEventRepository) ListSince(ctx context.Context, since time.Time) ([]entity.AuthEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSince", ctx, since)
+	ret0, _ := ret[0].([]entity.AuthEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSince indicates an expected call of ListSince.
+func (mr *MockAuthEventRepositoryMockRecorder) ListSince(ctx, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSince", reflect.TypeOf((*MockAuthEventRepository)(nil).ListSince), ctx, since)
+}
+
+// MockSecurityAlertRepository is a mock of SecurityAlertRepository interface.
+type MockSecurityAlertRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSecurityAlertRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSecurityAlertRepositoryMockRecorder is the mock recorder for MockSecurityAlertRepository.
+type MockSecurityAlertRepositoryMockRecorder struct {
+	mock *MockSecurityAlertRepository
+}
+
+// NewMockSecurityAlertRepository creates a new mock instance.
+func NewMockSecurityAlertRepository(ctrl *gomock.Controller) *MockSecurityAlertRepository {
+	mock := &MockSecurityAlertRepository{ctrl: ctrl}
+	mock.recorder = &MockSecurityAlertRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSecurityAlertRepository) EXPECT() *MockSecurityAlertRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSecurityAlertRepository) Create(ctx context.Context, alert *entity.SecurityAlert) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, alert)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSecurityAlertRepositoryMockRecorder) Create(ctx, alert any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSecurityAlertRepository)(nil).Create), ctx, alert)
+}
+
+// ListForAdmin mocks base method.
+func (m *MockSecurityAlertRepository) ListForAdmin(ctx context.Context, adminID uuid.UUID, params repository.AlertListParams) ([]entity.SecurityAlert, *pagination.Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListForAdmin", ctx, adminID, params)
+	ret0, _ := ret[0].([]entity.SecurityAlert)
+	ret1, _ := ret[1].(*pagination.Info)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListForAdmin indicates an expected call of ListForAdmin.
+func (mr *MockSecurityAlertRepositoryMockRecorder) ListForAdmin(ctx, adminID, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListForAdmin", reflect.TypeOf((*MockSecurityAlertRepository)(nil).ListForAdmin), ctx, adminID, params)
+}
+
+// MockAuditEventRepository is a mock of AuditEventRepository interface.
+type MockAuditEventRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditEventRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditEventRepositoryMockRecorder is the mock recorder for MockAuditEventRepository.
+type MockAuditEventRepositoryMockRecorder struct {
+	mock *MockAuditEventRepository
+}
+
+// NewMockAuditEventRepository creates a new mock instance.
+func NewMockAuditEventRepository(ctrl *gomock.Controller) *MockAuditEventRepository {
+	mock := &MockAuditEventRepository{ctrl: ctrl}
+	mock.recorder = &MockAuditEventRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditEventRepository) EXPECT() *MockAuditEventRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAuditEventRepository) Create(ctx context.Context, event *entity.AuditEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAuditEventRepositoryMockRecorder) Create(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuditEventRepository)(nil).Create), ctx, event)
+}
+
+// DeleteBefore mocks base method.
+func (m *MockAuditEventRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBefore", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteBefore indicates an expected call of DeleteBefore.
+func (mr *MockAuditEventRepositoryMockRecorder) DeleteBefore(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBefore", reflect.TypeOf((*MockAuditEventRepository)(nil).DeleteBefore), ctx, before)
+}
+
+// ListByUser mocks base method.
+func (m *MockAuditEventRepository) ListByUser(ctx context.Context, userID uuid.UUID, params repository.AuditListParams) ([]entity.AuditEvent, *pagination.Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID, params)
+	ret0, _ := ret[0].([]entity.AuditEvent)
+	ret1, _ := ret[1].(*pagination.Info)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockAuditEventRepositoryMockRecorder) ListByUser(ctx, userID, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockAuditEventRepository)(nil).ListByUser), ctx, userID, params)
+}
+
+// MockNoteDocumentRepository is a mock of NoteDocumentRepository interface.
+type MockNoteDocumentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockNoteDocumentRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockNoteDocumentRepositoryMockRecorder is the mock recorder for MockNoteDocumentRepository.
+type MockNoteDocumentRepositoryMockRecorder struct {
+	mock *MockNoteDocumentRepository
+}
+
+// NewMockNoteDocumentRepository creates a new mock instance.
+func NewMockNoteDocumentRepository(ctrl *gomock.Controller) *MockNoteDocumentRepository {
+	mock := &MockNoteDocumentRepository{ctrl: ctrl}
+	mock.recorder = &MockNoteDocumentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNoteDocumentRepository) EXPECT() *MockNoteDocumentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Append mocks base method.
+func (m *MockNoteDocumentRepository) Append(ctx context.Context, noteID uuid.UUID, fn func(*entity.NoteDocument) (*entity.DocumentUpdate, error)) (*entity.DocumentUpdate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Append", ctx, noteID, fn)
+	ret0, _ := ret[0].(*entity.DocumentUpdate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Append indicates an expected call of Append.
+func (mr *MockNoteDocumentRepositoryMockRecorder) Append(ctx, noteID, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Append", reflect.TypeOf((*MockNoteDocumentRepository)(nil).Append), ctx, noteID, fn)
+}
+
+// Compact mocks base method.
+func (m *MockNoteDocumentRepository) Compact(ctx context.Context, noteID uuid.UUID, snapshot crdt.Snapshot, seq int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Compact", ctx, noteID, snapshot, seq)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Compact indicates an expected call of Compact.
+func (mr *MockNoteDocumentRepositoryMockRecorder) Compact(ctx, noteID, snapshot, seq any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compact", reflect.TypeOf((*MockNoteDocumentRepository)(nil).Compact), ctx, noteID, snapshot, seq)
+}
+
+// Create mocks base method.
+func (m *MockNoteDocumentRepository) Create(ctx context.Context, doc *entity.NoteDocument) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, doc)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockNoteDocumentRepositoryMockRecorder) Create(ctx, doc any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockNoteDocumentRepository)(nil).Create), ctx, doc)
+}
+
+// Delete mocks base method.
+func (m *MockNoteDocumentRepository) Delete(ctx context.Context, noteID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, noteID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockNoteDocumentRepositoryMockRecorder) Delete(ctx, noteID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockNoteDocumentRepository)(nil).Delete), ctx, noteID)
+}
+
+// Get mocks base method.
+func (m *MockNoteDocumentRepository) Get(ctx context.Context, noteID uuid.UUID) (*entity.NoteDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, noteID)
+	ret0, _ := ret[0].(*entity.NoteDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockNoteDocumentRepositoryMockRecorder) Get(ctx, noteID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockNoteDocumentRepository)(nil).Get), ctx, noteID)
+}
+
+// ListCompactable mocks base method.
+func (m *MockNoteDocumentRepository) ListCompactable(ctx context.Context, minUpdates, limit int) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCompactable", ctx, minUpdates, limit)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCompactable indicates an expected call of ListCompactable.
+func (mr *MockNoteDocumentRepositoryMockRecorder) ListCompactable(ctx, minUpdates, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCompactable", reflect.TypeOf((*MockNoteDocumentRepository)(nil).ListCompactable), ctx, minUpdates, limit)
+}
+
+// MockRefreshTokenRepository is a mock of RefreshTokenRepository interface.
+type MockRefreshTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefreshTokenRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRefreshTokenRepositoryMockRecorder is the mock recorder for MockRefreshTokenRepository.
+type MockRefreshTokenRepositoryMockRecorder struct {
+	mock *MockRefreshTokenRepository
+}
+
+// NewMockRefreshTokenRepository creates a new mock instance.
+func NewMockRefreshTokenRepository(ctrl *gomock.Controller) *MockRefreshTokenRepository {
+	mock := &MockRefreshTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockRefreshTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRefreshTokenRepository) EXPECT() *MockRefreshTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRefreshTokenRepository) Create(ctx context.Context, token *entity.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRefreshTokenRepositoryMockRecorder) Create(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRefreshTokenRepository)(nil).Create), ctx, token)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockRefreshTokenRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockRefreshTokenRepositoryMockRecorder) DeleteExpired(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockRefreshTokenRepository)(nil).DeleteExpired), ctx)
+}
+
+// GetByToken mocks base method.
+func (m *MockRefreshTokenRepository) GetByToken(ctx context.Context, token string) (*entity.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByToken", ctx, token)
+	ret0, _ := ret[0].(*entity.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByToken indicates an expected call of GetByToken.
+func (mr *MockRefreshTokenRepositoryMockRecorder) GetByToken(ctx, token any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByToken", reflect.TypeOf((*MockRefreshTokenRepository)(nil).GetByToken), ctx, token)
 }
 
-// Revoke mocks base method.
-func (m *MockRefreshTokenRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+// Revoke mocks base method.
+func (m *MockRefreshTokenRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockRefreshTokenRepositoryMockRecorder) Revoke(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockRefreshTokenRepository)(nil).Revoke), ctx, id)
+}
+
+// RevokeByDeviceID mocks base method.
+func (m *MockRefreshTokenRepository) RevokeByDeviceID(ctx context.Context, deviceID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeByDeviceID", ctx, deviceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeByDeviceID indicates an expected call of RevokeByDeviceID.
+func (mr *MockRefreshTokenRepositoryMockRecorder) RevokeByDeviceID(ctx, deviceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeByDeviceID", reflect.TypeOf((*MockRefreshTokenRepository)(nil).RevokeByDeviceID), ctx, deviceID)
+}
+
+// RevokeByUserID mocks base method.
+func (m *MockRefreshTokenRepository) RevokeByUserID(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeByUserID", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeByUserID indicates an expected call of RevokeByUserID.
+func (mr *MockRefreshTokenRepositoryMockRecorder) RevokeByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeByUserID", reflect.TypeOf((*MockRefreshTokenRepository)(nil).RevokeByUserID), ctx, userID)
+}
+
+// RevokeExcess mocks base method.
+func (m *MockRefreshTokenRepository) RevokeExcess(ctx context.Context, userID uuid.UUID, platform string, keep int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeExcess", ctx, userID, platform, keep)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeExcess indicates an expected call of RevokeExcess.
+func (mr *MockRefreshTokenRepositoryMockRecorder) RevokeExcess(ctx, userID, platform, keep any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeExcess", reflect.TypeOf((*MockRefreshTokenRepository)(nil).RevokeExcess), ctx, userID, platform, keep)
+}
+
+// RevokeOthers mocks base method.
+func (m *MockRefreshTokenRepository) RevokeOthers(ctx context.Context, userID, keepID uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOthers", ctx, userID, keepID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeOthers indicates an expected call of RevokeOthers.
+func (mr *MockRefreshTokenRepositoryMockRecorder) RevokeOthers(ctx, userID, keepID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOthers", reflect.TypeOf((*MockRefreshTokenRepository)(nil).RevokeOthers), ctx, userID, keepID)
+}
+
+// MockAccountDeletionRepository is a mock of AccountDeletionRepository interface.
+type MockAccountDeletionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountDeletionRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAccountDeletionRepositoryMockRecorder is the mock recorder for MockAccountDeletionRepository.
+type MockAccountDeletionRepositoryMockRecorder struct {
+	mock *MockAccountDeletionRepository
+}
+
+// NewMockAccountDeletionRepository creates a new mock instance.
+func NewMockAccountDeletionRepository(ctrl *gomock.Controller) *MockAccountDeletionRepository {
+	mock := &MockAccountDeletionRepository{ctrl: ctrl}
+	mock.recorder = &MockAccountDeletionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountDeletionRepository) EXPECT() *MockAccountDeletionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAccountDeletionRepository) Create(ctx context.Context, deletion *entity.AccountDeletion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, deletion)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAccountDeletionRepositoryMockRecorder) Create(ctx, deletion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAccountDeletionRepository)(nil).Create), ctx, deletion)
+}
+
+// ListPending mocks base method.
+func (m *MockAccountDeletionRepository) ListPending(ctx context.Context, before time.Time, limit int) ([]entity.AccountDeletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPending", ctx, before, limit)
+	ret0, _ := ret[0].([]entity.AccountDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPending indicates an expected call of ListPending.
+func (mr *MockAccountDeletionRepositoryMockRecorder) ListPending(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPending", reflect.TypeOf((*MockAccountDeletionRepository)(nil).ListPending), ctx, before, limit)
+}
+
+// ObjectKeys mocks base method.
+func (m *MockAccountDeletionRepository) ObjectKeys(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ObjectKeys", ctx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ObjectKeys indicates an expected call of ObjectKeys.
+func (mr *MockAccountDeletionRepositoryMockRecorder) ObjectKeys(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObjectKeys", reflect.TypeOf((*MockAccountDeletionRepository)(nil).ObjectKeys), ctx, userID)
+}
+
+// Purge mocks base method.
+func (m *MockAccountDeletionRepository) Purge(ctx context.Context, deletion *entity.AccountDeletion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, deletion)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockAccountDeletionRepositoryMockRecorder) Purge(ctx, deletion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockAccountDeletionRepository)(nil).Purge), ctx, deletion)
+}
+
+// MockStorageOrphanRepository is a mock of StorageOrphanRepository interface.
+type MockStorageOrphanRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStorageOrphanRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockStorageOrphanRepositoryMockRecorder is the mock recorder for MockStorageOrphanRepository.
+type MockStorageOrphanRepositoryMockRecorder struct {
+	mock *MockStorageOrphanRepository
+}
+
+// NewMockStorageOrphanRepository creates a new mock instance.
+func NewMockStorageOrphanRepository(ctrl *gomock.Controller) *MockStorageOrphanRepository {
+	mock := &MockStorageOrphanRepository{ctrl: ctrl}
+	mock.recorder = &MockStorageOrphanRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStorageOrphanRepository) EXPECT() *MockStorageOrphanRepositoryMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockStorageOrphanRepository) List(ctx context.Context, limit int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockStorageOrphanRepositoryMockRecorder) List(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockStorageOrphanRepository)(nil).List), ctx, limit)
+}
+
+// Remove mocks base method.
+func (m *MockStorageOrphanRepository) Remove(ctx context.Context, keys []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", ctx, keys)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockStorageOrphanRepositoryMockRecorder) Remove(ctx, keys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockStorageOrphanRepository)(nil).Remove), ctx, keys)
+}
+
+// MockNoteEmbeddingRepository is a mock of NoteEmbeddingRepository interface.
+type MockNoteEmbeddingRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockNoteEmbeddingRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockNoteEmbeddingRepositoryMockRecorder is the mock recorder for MockNoteEmbeddingRepository.
+type MockNoteEmbeddingRepositoryMockRecorder struct {
+	mock *MockNoteEmbeddingRepository
+}
+
+// NewMockNoteEmbeddingRepository creates a new mock instance.
+func NewMockNoteEmbeddingRepository(ctrl *gomock.Controller) *MockNoteEmbeddingRepository {
+	mock := &MockNoteEmbeddingRepository{ctrl: ctrl}
+	mock.recorder = &MockNoteEmbeddingRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNoteEmbeddingRepository) EXPECT() *MockNoteEmbeddingRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Available mocks base method.
+func (m *MockNoteEmbeddingRepository) Available(ctx context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Available", ctx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Available indicates an expected call of Available.
+func (mr *MockNoteEmbeddingRepositoryMockRecorder) Available(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Available", reflect.TypeOf((*MockNoteEmbeddingRepository)(nil).Available), ctx)
+}
+
+// Pending mocks base method.
+func (m *MockNoteEmbeddingRepository) Pending(ctx context.Context, model string, limit int) ([]entity.Note, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pending", ctx, model, limit)
+	ret0, _ := ret[0].([]entity.Note)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Pending indicates an expected call of Pending.
+func (mr *MockNoteEmbeddingRepositoryMockRecorder) Pending(ctx, model, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pending", reflect.TypeOf((*MockNoteEmbeddingRepository)(nil).Pending), ctx, model, limit)
+}
+
+// Prune mocks base method.
+func (m *MockNoteEmbeddingRepository) Prune(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prune", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Prune indicates an expected call of Prune.
+func (mr *MockNoteEmbeddingRepositoryMockRecorder) Prune(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prune", reflect.TypeOf((*MockNoteEmbeddingRepository)(nil).Prune), ctx)
+}
+
+// Related mocks base method.
+func (m *MockNoteEmbeddingRepository) Related(ctx context.Context, userID, noteID uuid.UUID, model string, limit int) ([]entity.SearchHit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Related", ctx, userID, noteID, model, limit)
+	ret0, _ := ret[0].([]entity.SearchHit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Related indicates an expected call of Related.
+func (mr *MockNoteEmbeddingRepositoryMockRecorder) Related(ctx, userID, noteID, model, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Related", reflect.TypeOf((*MockNoteEmbeddingRepository)(nil).Related), ctx, userID, noteID, model, limit)
+}
+
+// Save mocks base method.
+func (m *MockNoteEmbeddingRepository) Save(ctx context.Context, embeddings []entity.NoteEmbedding) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, embeddings)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockNoteEmbeddingRepositoryMockRecorder) Save(ctx, embeddings any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockNoteEmbeddingRepository)(nil).Save), ctx, embeddings)
+}
+
+// Search mocks base method.
+func (m *MockNoteEmbeddingRepository) Search(ctx context.Context, userID uuid.UUID, model string, vector []float32, limit int) ([]entity.SearchHit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, userID, model, vector, limit)
+	ret0, _ := ret[0].([]entity.SearchHit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockNoteEmbeddingRepositoryMockRecorder) Search(ctx, userID, model, vector, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockNoteEmbeddingRepository)(nil).Search), ctx, userID, model, vector, limit)
+}
+
+// MockSyncConflictRepository is a mock of SyncConflictRepository interface.
+type MockSyncConflictRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSyncConflictRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSyncConflictRepositoryMockRecorder is the mock recorder for MockSyncConflictRepository.
+type MockSyncConflictRepositoryMockRecorder struct {
+	mock *MockSyncConflictRepository
+}
+
+// NewMockSyncConflictRepository creates a new mock instance.
+func NewMockSyncConflictRepository(ctrl *gomock.Controller) *MockSyncConflictRepository {
+	mock := &MockSyncConflictRepository{ctrl: ctrl}
+	mock.recorder = &MockSyncConflictRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSyncConflictRepository) EXPECT() *MockSyncConflictRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteExpired mocks base method.
+func (m *MockSyncConflictRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockSyncConflictRepositoryMockRecorder) DeleteExpired(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockSyncConflictRepository)(nil).DeleteExpired), ctx, before)
+}
+
+// GetByID mocks base method.
+func (m *MockSyncConflictRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.SyncConflict, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*entity.SyncConflict)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockSyncConflictRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockSyncConflictRepository)(nil).GetByID), ctx, id)
+}
+
+// ListPending mocks base method.
+func (m *MockSyncConflictRepository) ListPending(ctx context.Context, userID uuid.UUID, now time.Time) ([]entity.SyncConflict, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPending", ctx, userID, now)
+	ret0, _ := ret[0].([]entity.SyncConflict)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPending indicates an expected call of ListPending.
+func (mr *MockSyncConflictRepositoryMockRecorder) ListPending(ctx, userID, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPending", reflect.TypeOf((*MockSyncConflictRepository)(nil).ListPending), ctx, userID, now)
+}
+
+// Resolve mocks base method.
+func (m *MockSyncConflictRepository) Resolve(ctx context.Context, conflict *entity.SyncConflict) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Revoke", ctx, id)
+	ret := m.ctrl.Call(m, "Resolve", ctx, conflict)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Revoke indicates an expected call of Revoke.
-func (mr *MockRefreshTokenRepositoryMockRecorder) Revoke(ctx, id any) *gomock.Call {
+// Resolve indicates an expected call of Resolve.
+func (mr *MockSyncConflictRepositoryMockRecorder) Resolve(ctx, conflict any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockRefreshTokenRepository)(nil).Revoke), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockSyncConflictRepository)(nil).Resolve), ctx, conflict)
 }
 
-// RevokeByDeviceID mocks base method.
-func (m *MockRefreshTokenRepository) RevokeByDeviceID(ctx context.Context, deviceID uuid.UUID) error {
+// Save mocks base method.
+func (m *MockSyncConflictRepository) Save(ctx context.Context, conflict *entity.SyncConflict) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RevokeByDeviceID", ctx, deviceID)
+	ret := m.ctrl.Call(m, "Save", ctx, conflict)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// RevokeByDeviceID indicates an expected call of RevokeByDeviceID.
-func (mr *MockRefreshTokenRepositoryMockRecorder) RevokeByDeviceID(ctx, deviceID any) *gomock.Call {
+// Save indicates an expected call of Save.
+func (mr *MockSyncConflictRepositoryMockRecorder) Save(ctx, conflict any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeByDeviceID", reflect.TypeOf((*MockRefreshTokenRepository)(nil).RevokeByDeviceID), ctx, deviceID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockSyncConflictRepository)(nil).Save), ctx, conflict)
 }
 
-// RevokeByUserID mocks base method.
-func (m *MockRefreshTokenRepository) RevokeByUserID(ctx context.Context, userID uuid.UUID) error {
+// MockPasswordResetTokenRepository is a mock of PasswordResetTokenRepository interface.
+type MockPasswordResetTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordResetTokenRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPasswordResetTokenRepositoryMockRecorder is the mock recorder for MockPasswordResetTokenRepository.
+type MockPasswordResetTokenRepositoryMockRecorder struct {
+	mock *MockPasswordResetTokenRepository
+}
+
+// NewMockPasswordResetTokenRepository creates a new mock instance.
+func NewMockPasswordResetTokenRepository(ctrl *gomock.Controller) *MockPasswordResetTokenRepository {
+	mock := &MockPasswordResetTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockPasswordResetTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordResetTokenRepository) EXPECT() *MockPasswordResetTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockPasswordResetTokenRepository) Consume(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RevokeByUserID", ctx, userID)
+	ret := m.ctrl.Call(m, "Consume", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// RevokeByUserID indicates an expected call of RevokeByUserID.
-func (mr *MockRefreshTokenRepositoryMockRecorder) RevokeByUserID(ctx, userID any) *gomock.Call {
+// Consume indicates an expected call of Consume.
+func (mr *MockPasswordResetTokenRepositoryMockRecorder) Consume(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeByUserID", reflect.TypeOf((*MockRefreshTokenRepository)(nil).RevokeByUserID), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockPasswordResetTokenRepository)(nil).Consume), ctx, id)
+}
+
+// Create mocks base method.
+func (m *MockPasswordResetTokenRepository) Create(ctx context.Context, token *entity.PasswordResetToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPasswordResetTokenRepositoryMockRecorder) Create(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPasswordResetTokenRepository)(nil).Create), ctx, token)
+}
+
+// GetByHash mocks base method.
+func (m *MockPasswordResetTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByHash", ctx, tokenHash)
+	ret0, _ := ret[0].(*entity.PasswordResetToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByHash indicates an expected call of GetByHash.
+func (mr *MockPasswordResetTokenRepositoryMockRecorder) GetByHash(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByHash", reflect.TypeOf((*MockPasswordResetTokenRepository)(nil).GetByHash), ctx, tokenHash)
+}
+
+// MockAuthProviderRepository is a mock of AuthProviderRepository interface.
+type MockAuthProviderRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthProviderRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAuthProviderRepositoryMockRecorder is the mock recorder for MockAuthProviderRepository.
+type MockAuthProviderRepositoryMockRecorder struct {
+	mock *MockAuthProviderRepository
+}
+
+// NewMockAuthProviderRepository creates a new mock instance.
+func NewMockAuthProviderRepository(ctrl *gomock.Controller) *MockAuthProviderRepository {
+	mock := &MockAuthProviderRepository{ctrl: ctrl}
+	mock.recorder = &MockAuthProviderRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthProviderRepository) EXPECT() *MockAuthProviderRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAuthProviderRepository) Create(ctx context.Context, link *entity.AuthProviderLink) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, link)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAuthProviderRepositoryMockRecorder) Create(ctx, link any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuthProviderRepository)(nil).Create), ctx, link)
+}
+
+// GetBySubject mocks base method.
+func (m *MockAuthProviderRepository) GetBySubject(ctx context.Context, provider, subject string) (*entity.AuthProviderLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBySubject", ctx, provider, subject)
+	ret0, _ := ret[0].(*entity.AuthProviderLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBySubject indicates an expected call of GetBySubject.
+func (mr *MockAuthProviderRepositoryMockRecorder) GetBySubject(ctx, provider, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySubject", reflect.TypeOf((*MockAuthProviderRepository)(nil).GetBySubject), ctx, provider, subject)
+}
+
+// MockOrganizationRepository is a mock of OrganizationRepository interface.
+type MockOrganizationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrganizationRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockOrganizationRepositoryMockRecorder is the mock recorder for MockOrganizationRepository.
+type MockOrganizationRepositoryMockRecorder struct {
+	mock *MockOrganizationRepository
+}
+
+// NewMockOrganizationRepository creates a new mock instance.
+func NewMockOrganizationRepository(ctrl *gomock.Controller) *MockOrganizationRepository {
+	mock := &MockOrganizationRepository{ctrl: ctrl}
+	mock.recorder = &MockOrganizationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrganizationRepository) EXPECT() *MockOrganizationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetByDomain mocks base method.
+func (m *MockOrganizationRepository) GetByDomain(ctx context.Context, domain string) (*entity.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByDomain", ctx, domain)
+	ret0, _ := ret[0].(*entity.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByDomain indicates an expected call of GetByDomain.
+func (mr *MockOrganizationRepositoryMockRecorder) GetByDomain(ctx, domain any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByDomain", reflect.TypeOf((*MockOrganizationRepository)(nil).GetByDomain), ctx, domain)
+}
+
+// GetBySlug mocks base method.
+func (m *MockOrganizationRepository) GetBySlug(ctx context.Context, slug string) (*entity.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBySlug", ctx, slug)
+	ret0, _ := ret[0].(*entity.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBySlug indicates an expected call of GetBySlug.
+func (mr *MockOrganizationRepositoryMockRecorder) GetBySlug(ctx, slug any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySlug", reflect.TypeOf((*MockOrganizationRepository)(nil).GetBySlug), ctx, slug)
+}
+
+// IsAdmin mocks base method.
+func (m *MockOrganizationRepository) IsAdmin(ctx context.Context, orgID, userID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAdmin", ctx, orgID, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsAdmin indicates an expected call of IsAdmin.
+func (mr *MockOrganizationRepositoryMockRecorder) IsAdmin(ctx, orgID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAdmin", reflect.TypeOf((*MockOrganizationRepository)(nil).IsAdmin), ctx, orgID, userID)
+}
+
+// IsAdminOver mocks base method.
+func (m *MockOrganizationRepository) IsAdminOver(ctx context.Context, adminID, memberID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAdminOver", ctx, adminID, memberID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsAdminOver indicates an expected call of IsAdminOver.
+func (mr *MockOrganizationRepositoryMockRecorder) IsAdminOver(ctx, adminID, memberID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAdminOver", reflect.TypeOf((*MockOrganizationRepository)(nil).IsAdminOver), ctx, adminID, memberID)
+}
+
+// ListByUserID mocks base method.
+func (m *MockOrganizationRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]entity.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUserID", ctx, userID)
+	ret0, _ := ret[0].([]entity.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUserID indicates an expected call of ListByUserID.
+func (mr *MockOrganizationRepositoryMockRecorder) ListByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUserID", reflect.TypeOf((*MockOrganizationRepository)(nil).ListByUserID), ctx, userID)
+}
+
+// UpdateSSO mocks base method.
+func (m *MockOrganizationRepository) UpdateSSO(ctx context.Context, org *entity.Organization) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSSO", ctx, org)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSSO indicates an expected call of UpdateSSO.
+func (mr *MockOrganizationRepositoryMockRecorder) UpdateSSO(ctx, org any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSSO", reflect.TypeOf((*MockOrganizationRepository)(nil).UpdateSSO), ctx, org)
+}
+
+// UpsertMember mocks base method.
+func (m *MockOrganizationRepository) UpsertMember(ctx context.Context, membership *entity.OrgMembership) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertMember", ctx, membership)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertMember indicates an expected call of UpsertMember.
+func (mr *MockOrganizationRepositoryMockRecorder) UpsertMember(ctx, membership any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertMember", reflect.TypeOf((*MockOrganizationRepository)(nil).UpsertMember), ctx, membership)
+}
+
+// MockTeamRepository is a mock of TeamRepository interface.
+type MockTeamRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTeamRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockTeamRepositoryMockRecorder is the mock recorder for MockTeamRepository.
+type MockTeamRepositoryMockRecorder struct {
+	mock *MockTeamRepository
+}
+
+// NewMockTeamRepository creates a new mock instance.
+func NewMockTeamRepository(ctrl *gomock.Controller) *MockTeamRepository {
+	mock := &MockTeamRepository{ctrl: ctrl}
+	mock.recorder = &MockTeamRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTeamRepository) EXPECT() *MockTeamRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Accept mocks base method.
+func (m *MockTeamRepository) Accept(ctx context.Context, teamID, userID uuid.UUID, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Accept", ctx, teamID, userID, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Accept indicates an expected call of Accept.
+func (mr *MockTeamRepositoryMockRecorder) Accept(ctx, teamID, userID, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Accept", reflect.TypeOf((*MockTeamRepository)(nil).Accept), ctx, teamID, userID, at)
+}
+
+// AddMember mocks base method.
+func (m *MockTeamRepository) AddMember(ctx context.Context, member *entity.TeamMember) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMember", ctx, member)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMember indicates an expected call of AddMember.
+func (mr *MockTeamRepositoryMockRecorder) AddMember(ctx, member any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMember", reflect.TypeOf((*MockTeamRepository)(nil).AddMember), ctx, member)
+}
+
+// Create mocks base method.
+func (m *MockTeamRepository) Create(ctx context.Context, team *entity.Team, owner *entity.TeamMember) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, team, owner)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTeamRepositoryMockRecorder) Create(ctx, team, owner any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTeamRepository)(nil).Create), ctx, team, owner)
+}
+
+// GetByID mocks base method.
+func (m *MockTeamRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockTeamRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTeamRepository)(nil).GetByID), ctx, id)
+}
+
+// GetMember mocks base method.
+func (m *MockTeamRepository) GetMember(ctx context.Context, teamID, userID uuid.UUID) (*entity.TeamMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMember", ctx, teamID, userID)
+	ret0, _ := ret[0].(*entity.TeamMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMember indicates an expected call of GetMember.
+func (mr *MockTeamRepositoryMockRecorder) GetMember(ctx, teamID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMember", reflect.TypeOf((*MockTeamRepository)(nil).GetMember), ctx, teamID, userID)
+}
+
+// GetRole mocks base method.
+func (m *MockTeamRepository) GetRole(ctx context.Context, teamID, userID uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRole", ctx, teamID, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRole indicates an expected call of GetRole.
+func (mr *MockTeamRepositoryMockRecorder) GetRole(ctx, teamID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRole", reflect.TypeOf((*MockTeamRepository)(nil).GetRole), ctx, teamID, userID)
+}
+
+// ListByUserID mocks base method.
+func (m *MockTeamRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]entity.UserTeam, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUserID", ctx, userID)
+	ret0, _ := ret[0].([]entity.UserTeam)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUserID indicates an expected call of ListByUserID.
+func (mr *MockTeamRepositoryMockRecorder) ListByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUserID", reflect.TypeOf((*MockTeamRepository)(nil).ListByUserID), ctx, userID)
+}
+
+// ListMembers mocks base method.
+func (m *MockTeamRepository) ListMembers(ctx context.Context, teamID uuid.UUID) ([]entity.TeamMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMembers", ctx, teamID)
+	ret0, _ := ret[0].([]entity.TeamMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMembers indicates an expected call of ListMembers.
+func (mr *MockTeamRepositoryMockRecorder) ListMembers(ctx, teamID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMembers", reflect.TypeOf((*MockTeamRepository)(nil).ListMembers), ctx, teamID)
+}
+
+// RemoveMember mocks base method.
+func (m *MockTeamRepository) RemoveMember(ctx context.Context, teamID, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, teamID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockTeamRepositoryMockRecorder) RemoveMember(ctx, teamID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockTeamRepository)(nil).RemoveMember), ctx, teamID, userID)
+}
+
+// UpdateRole mocks base method.
+func (m *MockTeamRepository) UpdateRole(ctx context.Context, teamID, userID uuid.UUID, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", ctx, teamID, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockTeamRepositoryMockRecorder) UpdateRole(ctx, teamID, userID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockTeamRepository)(nil).UpdateRole), ctx, teamID, userID, role)
 }
@@ -15,6 +15,7 @@ import (
 	reflect "reflect"
 	time "time"
 
+	storage "github.com/marcos-nsantos/field-notes-backend/internal/adapter/storage"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -56,6 +57,21 @@ func (mr *MockImageStorageMockRecorder) Delete(ctx, key any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockImageStorage)(nil).Delete), ctx, key)
 }
 
+// Download mocks base method.
+func (m *MockImageStorage) Download(ctx context.Context, key string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Download", ctx, key)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Download indicates an expected call of Download.
+func (mr *MockImageStorageMockRecorder) Download(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Download", reflect.TypeOf((*MockImageStorage)(nil).Download), ctx, key)
+}
+
 // GetSignedURL mocks base method.
 func (m *MockImageStorage) GetSignedURL(key string, expiry time.Duration) (string, error) {
 	m.ctrl.T.Helper()
@@ -86,11 +102,12 @@ func (mr *MockImageStorageMockRecorder) GetURL(key any) *gomock.Call {
 }
 
 // Upload mocks base method.
-func (m *MockImageStorage) Upload(ctx context.Context, key string, reader io.Reader, contentType string, size int64) error {
+func (m *MockImageStorage) Upload(ctx context.Context, key string, reader io.Reader, contentType string, size int64) (string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Upload", ctx, key, reader, contentType, size)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Upload indicates an expected call of Upload.
@@ -123,10 +140,24 @@ func (m *MockImageProcessor) EXPECT() *MockImageProcessorMockRecorder {
 	return m.recorder
 }
 
+// Metadata mocks base method.
+func (m *MockImageProcessor) Metadata(data []byte) storage.ImageMetadata {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Metadata", data)
+	ret0, _ := ret[0].(storage.ImageMetadata)
+	return ret0
+}
+
+// Metadata indicates an expected call of Metadata.
+func (mr *MockImageProcessorMockRecorder) Metadata(data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Metadata", reflect.TypeOf((*MockImageProcessor)(nil).Metadata), data)
+}
+
 // Process mocks base method.
-func (m *MockImageProcessor) Process(reader io.Reader) (io.Reader, int64, int, int, error) {
+func (m *MockImageProcessor) Process(ctx context.Context, reader io.Reader) (io.Reader, int64, int, int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Process", reader)
+	ret := m.ctrl.Call(m, "Process", ctx, reader)
 	ret0, _ := ret[0].(io.Reader)
 	ret1, _ := ret[1].(int64)
 	ret2, _ := ret[2].(int)
@@ -136,7 +167,22 @@ func (m *MockImageProcessor) Process(reader io.Reader) (io.Reader, int64, int, i
 }
 
 // Process indicates an expected call of Process.
-func (mr *MockImageProcessorMockRecorder) Process(reader any) *gomock.Call {
+func (mr *MockImageProcessorMockRecorder) Process(ctx, reader any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Process", reflect.TypeOf((*MockImageProcessor)(nil).Process), ctx, reader)
+}
+
+// Thumbnails mocks base method.
+func (m *MockImageProcessor) Thumbnails(ctx context.Context, reader io.Reader) ([]storage.Thumbnail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Thumbnails", ctx, reader)
+	ret0, _ := ret[0].([]storage.Thumbnail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Thumbnails indicates an expected call of Thumbnails.
+func (mr *MockImageProcessorMockRecorder) Thumbnails(ctx, reader any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Process", reflect.TypeOf((*MockImageProcessor)(nil).Process), reader)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Thumbnails", reflect.TypeOf((*MockImageProcessor)(nil).Thumbnails), ctx, reader)
 }
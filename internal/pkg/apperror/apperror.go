@@ -0,0 +1,28 @@
+// Package apperror defines the errors the API reports to clients: an HTTP
+// status, a machine-readable code from the httputil catalog, a message for
+// people and optional structured details. httputil.Fail writes them as the
+// one error body every route returns.
+package apperror
+
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+	// Details is encoded as is, e.g. the fields that failed validation.
+	Details any
+}
+
+func New(status int, code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// WithDetails returns a copy of the error carrying details.
+func (e *Error) WithDetails(details any) *Error {
+	c := *e
+	c.Details = details
+	return &c
+}
@@ -0,0 +1,32 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/clock"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.FixedZone("BRT", -3*3600))
+	c := clock.NewFake(start)
+
+	assert.Equal(t, time.UTC, c.Now().Location())
+	assert.True(t, c.Now().Equal(start))
+
+	c.Advance(90 * time.Minute)
+	assert.True(t, c.Now().Equal(start.Add(90*time.Minute)))
+
+	c.Set(start)
+	assert.True(t, c.Now().Equal(start))
+}
+
+func TestSystem(t *testing.T) {
+	before := time.Now()
+	now := clock.System{}.Now()
+
+	assert.Equal(t, time.UTC, now.Location())
+	assert.False(t, now.Before(before))
+}
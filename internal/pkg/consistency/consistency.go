@@ -0,0 +1,27 @@
+package consistency
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type ctxKey struct{}
+
+// ReplicaRead allows a read to be served by a replica once the replica has
+// applied the user's writes up to MinVersion. Zero accepts any replica state.
+type ReplicaRead struct {
+	UserID     uuid.UUID
+	MinVersion int64
+}
+
+// WithReplicaRead marks ctx as allowing replica reads. Reads in a context
+// without the mark always go to the primary.
+func WithReplicaRead(ctx context.Context, read ReplicaRead) context.Context {
+	return context.WithValue(ctx, ctxKey{}, read)
+}
+
+func ReplicaReadFrom(ctx context.Context) (ReplicaRead, bool) {
+	read, ok := ctx.Value(ctxKey{}).(ReplicaRead)
+	return read, ok
+}
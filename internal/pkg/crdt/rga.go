@@ -0,0 +1,293 @@
+// Package crdt implements a replicated growable array (RGA) of characters,
+// the text CRDT behind collaborative notes. Replicas that apply the same
+// operations end with the same text, whatever order concurrent operations
+// arrive in, so co-editors never overwrite each other's changes.
+//
+// Every character has an ID made of the replica that inserted it and a
+// sequence number that works as a Lamport clock: a replica numbers each new
+// character above every ID it has seen. Deleted characters stay in the
+// document as tombstones, since operations still in flight may refer to
+// them.
+package crdt
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+const (
+	OpInsert = "insert"
+	OpDelete = "delete"
+
+	// MaxReplicaLength bounds replica names.
+	MaxReplicaLength = 64
+)
+
+// ErrInvalidOp is wrapped by the errors of operations a document rejects.
+var ErrInvalidOp = errors.New("invalid operation")
+
+// ID identifies a character. IDs are ordered by Seq and then by Replica.
+type ID struct {
+	Replica string `json:"r"`
+	Seq     int64  `json:"s"`
+}
+
+func (id ID) less(other ID) bool {
+	if id.Seq != other.Seq {
+		return id.Seq < other.Seq
+	}
+	return id.Replica < other.Replica
+}
+
+// offset returns the ID of the n-th character of a run starting at id.
+func (id ID) offset(n int) ID {
+	return ID{Replica: id.Replica, Seq: id.Seq + int64(n)}
+}
+
+func (id ID) String() string {
+	return fmt.Sprintf("%s:%d", id.Replica, id.Seq)
+}
+
+// Op is one change to a document. An insert adds Text after the character
+// After, or at the start when After is nil; its characters get the IDs
+// ID.Seq, ID.Seq+1 and so on of ID's replica, each following the one
+// before. ID.Seq must be above After.Seq. A delete removes the Count
+// characters, 1 when zero, with IDs from ID on.
+type Op struct {
+	Op    string `json:"op"`
+	ID    ID     `json:"id"`
+	After *ID    `json:"after,omitempty"`
+	Text  string `json:"text,omitempty"`
+	Count int    `json:"count,omitempty"`
+}
+
+// length is the number of characters the op inserts or deletes.
+func (op Op) length() int {
+	if op.Op == OpInsert {
+		return utf8.RuneCountInString(op.Text)
+	}
+	return max(op.Count, 1)
+}
+
+type node struct {
+	id      ID
+	char    rune
+	deleted bool
+	next    *node
+}
+
+// Doc is a document replica. It is not safe for concurrent use.
+type Doc struct {
+	head   node
+	nodes  map[ID]*node
+	maxSeq int64
+	length int
+}
+
+func New() *Doc {
+	return &Doc{nodes: make(map[ID]*node)}
+}
+
+// FromText returns a document holding text, inserted by replica as one run
+// numbered from 1.
+func FromText(replica, text string) *Doc {
+	d := New()
+	if text != "" {
+		_, _ = d.Apply([]Op{{Op: OpInsert, ID: ID{Replica: replica, Seq: 1}, Text: text}})
+	}
+	return d
+}
+
+// Text returns the document without its deleted characters.
+func (d *Doc) Text() string {
+	buf := make([]rune, 0, d.length)
+	for n := d.head.next; n != nil; n = n.next {
+		if !n.deleted {
+			buf = append(buf, n.char)
+		}
+	}
+	return string(buf)
+}
+
+// Len is the number of characters in Text.
+func (d *Doc) Len() int {
+	return d.length
+}
+
+// MaxSeq is the highest sequence number in the document. A replica joining
+// the document numbers its characters above it.
+func (d *Doc) MaxSeq() int64 {
+	return d.maxSeq
+}
+
+// Apply applies ops in order and reports whether any changed the document.
+// Inserts whose first character is already in the document were applied
+// before and are skipped, so a retried update is harmless. Ops are checked
+// before any is applied: if one is invalid, the document is left unchanged
+// and the error wraps ErrInvalidOp.
+func (d *Doc) Apply(ops []Op) (bool, error) {
+	if err := d.check(ops); err != nil {
+		return false, err
+	}
+
+	changed := false
+	for _, op := range ops {
+		switch op.Op {
+		case OpInsert:
+			if _, seen := d.nodes[op.ID]; seen {
+				continue
+			}
+			prev := &d.head
+			if op.After != nil {
+				prev = d.nodes[*op.After]
+			}
+			i := 0
+			for _, char := range op.Text {
+				prev = d.integrate(prev, op.ID.offset(i), char)
+				i++
+			}
+			changed = true
+		case OpDelete:
+			for i := range op.length() {
+				if n := d.nodes[op.ID.offset(i)]; !n.deleted {
+					n.deleted = true
+					d.length--
+					changed = true
+				}
+			}
+		}
+	}
+	return changed, nil
+}
+
+// integrate inserts the character after prev. Characters already after prev
+// with higher IDs were inserted concurrently, or later after them, and stay
+// in front, which puts concurrent inserts at the same place in the same
+// order on every replica.
+func (d *Doc) integrate(prev *node, id ID, char rune) *node {
+	for prev.next != nil && id.less(prev.next.id) {
+		prev = prev.next
+	}
+	n := &node{id: id, char: char, next: prev.next}
+	prev.next = n
+	d.nodes[id] = n
+	d.maxSeq = max(d.maxSeq, id.Seq)
+	d.length++
+	return n
+}
+
+// check validates ops against the document and the characters inserted by
+// the ops before them.
+func (d *Doc) check(ops []Op) error {
+	inserted := make(map[ID]bool)
+	exists := func(id ID) bool {
+		_, ok := d.nodes[id]
+		return ok || inserted[id]
+	}
+
+	for i, op := range ops {
+		fail := func(format string, args ...any) error {
+			return fmt.Errorf("%w: op %d: %s", ErrInvalidOp, i, fmt.Sprintf(format, args...))
+		}
+		if op.ID.Replica == "" || len(op.ID.Replica) > MaxReplicaLength {
+			return fail("replica must have 1 to %d bytes", MaxReplicaLength)
+		}
+		if op.ID.Seq < 1 {
+			return fail("seq must be positive")
+		}
+
+		switch op.Op {
+		case OpInsert:
+			if op.Text == "" || !utf8.ValidString(op.Text) {
+				return fail("insert needs valid UTF-8 text")
+			}
+			if _, seen := d.nodes[op.ID]; seen {
+				continue
+			}
+			if op.After != nil {
+				if !exists(*op.After) {
+					return fail("character %s is unknown", op.After)
+				}
+				if op.ID.Seq <= op.After.Seq {
+					return fail("seq %d must be above the seq of the character it follows", op.ID.Seq)
+				}
+			}
+			for j := range op.length() {
+				id := op.ID.offset(j)
+				if exists(id) {
+					return fail("character %s already exists", id)
+				}
+				inserted[id] = true
+			}
+		case OpDelete:
+			if op.Count < 0 {
+				return fail("count must not be negative")
+			}
+			for j := range op.length() {
+				if id := op.ID.offset(j); !exists(id) {
+					return fail("character %s is unknown", id)
+				}
+			}
+		default:
+			return fail("unknown op %q", op.Op)
+		}
+	}
+	return nil
+}
+
+// Insert inserts text at the character position pos as replica, and
+// returns the op to send to other replicas.
+func (d *Doc) Insert(replica string, pos int, text string) (Op, error) {
+	if pos < 0 || pos > d.length {
+		return Op{}, fmt.Errorf("%w: position %d is out of range", ErrInvalidOp, pos)
+	}
+	op := Op{Op: OpInsert, ID: ID{Replica: replica, Seq: d.maxSeq + 1}, Text: text}
+	if pos > 0 {
+		after := d.visible(pos - 1).id
+		op.After = &after
+	}
+	if _, err := d.Apply([]Op{op}); err != nil {
+		return Op{}, err
+	}
+	return op, nil
+}
+
+// Delete deletes count characters from the character position pos, and
+// returns the ops to send to other replicas.
+func (d *Doc) Delete(pos, count int) ([]Op, error) {
+	if pos < 0 || count < 1 || pos+count > d.length {
+		return nil, fmt.Errorf("%w: range %d+%d is out of range", ErrInvalidOp, pos, count)
+	}
+	var ops []Op
+	n := d.visible(pos)
+	for range count {
+		for n.deleted {
+			n = n.next
+		}
+		if last := len(ops) - 1; last >= 0 && ops[last].ID.offset(ops[last].length()) == n.id {
+			ops[last].Count = ops[last].length() + 1
+		} else {
+			ops = append(ops, Op{Op: OpDelete, ID: n.id})
+		}
+		n = n.next
+	}
+	if _, err := d.Apply(ops); err != nil {
+		return nil, err
+	}
+	return ops, nil
+}
+
+// visible returns the node of the character at position pos of Text.
+func (d *Doc) visible(pos int) *node {
+	for n := d.head.next; n != nil; n = n.next {
+		if n.deleted {
+			continue
+		}
+		if pos == 0 {
+			return n
+		}
+		pos--
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package crdt_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/crdt"
+)
+
+// replicate returns a copy of doc, as another replica would load it.
+func replicate(t *testing.T, doc *crdt.Doc) *crdt.Doc {
+	t.Helper()
+	copied, err := crdt.FromSnapshot(doc.Snapshot())
+	require.NoError(t, err)
+	return copied
+}
+
+func apply(t *testing.T, doc *crdt.Doc, ops ...crdt.Op) {
+	t.Helper()
+	_, err := doc.Apply(ops)
+	require.NoError(t, err)
+}
+
+func TestDoc_LocalEdits(t *testing.T) {
+	doc := crdt.FromText("server", "field notes")
+
+	_, err := doc.Insert("a", 0, "My ")
+	require.NoError(t, err)
+	_, err = doc.Delete(3, 6)
+	require.NoError(t, err)
+	_, err = doc.Insert("a", doc.Len(), " ✓")
+	require.NoError(t, err)
+
+	assert.Equal(t, "My notes ✓", doc.Text())
+	assert.Equal(t, 10, doc.Len())
+}
+
+func TestDoc_ConcurrentEditsConverge(t *testing.T) {
+	base := crdt.FromText("server", "hello world")
+	alice, bob := replicate(t, base), replicate(t, base)
+
+	a1, err := alice.Insert("alice", 5, ",")
+	require.NoError(t, err)
+	a2, err := alice.Delete(7, 5)
+	require.NoError(t, err)
+	a3, err := alice.Insert("alice", alice.Len(), "there")
+	require.NoError(t, err)
+	b1, err := bob.Insert("bob", 5, "!")
+	require.NoError(t, err)
+	b2, err := bob.Insert("bob", bob.Len(), " again")
+	require.NoError(t, err)
+
+	aliceOps := append(append([]crdt.Op{a1}, a2...), a3)
+	bobOps := []crdt.Op{b1, b2}
+	apply(t, alice, bobOps...)
+	apply(t, bob, aliceOps...)
+
+	// A third replica receives everything in yet another order.
+	carol := replicate(t, base)
+	apply(t, carol, b2)
+	apply(t, carol, aliceOps...)
+	apply(t, carol, b1)
+
+	assert.Equal(t, alice.Text(), bob.Text())
+	assert.Equal(t, alice.Text(), carol.Text())
+	assert.Equal(t, "hello!, there again", alice.Text())
+}
+
+func TestDoc_ConcurrentInsertsAtSamePosition(t *testing.T) {
+	base := crdt.FromText("server", "ab")
+	x, y := replicate(t, base), replicate(t, base)
+
+	opX, err := x.Insert("x", 1, "123")
+	require.NoError(t, err)
+	opY, err := y.Insert("y", 1, "XYZ")
+	require.NoError(t, err)
+
+	apply(t, x, opY)
+	apply(t, y, opX)
+
+	assert.Equal(t, x.Text(), y.Text())
+	assert.Equal(t, "aXYZ123b", x.Text(), "runs do not interleave")
+}
+
+func TestDoc_ApplyIsIdempotent(t *testing.T) {
+	doc := crdt.FromText("server", "abc")
+	remote := replicate(t, doc)
+	ins, err := remote.Insert("r", 1, "x")
+	require.NoError(t, err)
+	del, err := remote.Delete(0, 1)
+	require.NoError(t, err)
+	ops := append([]crdt.Op{ins}, del...)
+
+	changed, err := doc.Apply(ops)
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	changed, err = doc.Apply(ops)
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, "xbc", doc.Text())
+}
+
+func TestDoc_ApplyRejectsInvalidOps(t *testing.T) {
+	unknown := crdt.ID{Replica: "ghost", Seq: 9}
+	first := crdt.ID{Replica: "server", Seq: 1}
+
+	tests := []struct {
+		name string
+		ops  []crdt.Op
+	}{
+		{"unknown op", []crdt.Op{{Op: "move", ID: crdt.ID{Replica: "a", Seq: 5}}}},
+		{"empty replica", []crdt.Op{{Op: crdt.OpInsert, ID: crdt.ID{Seq: 5}, Text: "x"}}},
+		{"zero seq", []crdt.Op{{Op: crdt.OpInsert, ID: crdt.ID{Replica: "a"}, Text: "x"}}},
+		{"empty insert", []crdt.Op{{Op: crdt.OpInsert, ID: crdt.ID{Replica: "a", Seq: 5}}}},
+		{"insert after unknown character", []crdt.Op{{Op: crdt.OpInsert, ID: crdt.ID{Replica: "a", Seq: 10}, After: &unknown, Text: "x"}}},
+		{"seq not above reference", []crdt.Op{{Op: crdt.OpInsert, ID: crdt.ID{Replica: "a", Seq: 1}, After: &first, Text: "x"}}},
+		{
+			"run overlapping inserted characters",
+			[]crdt.Op{
+				{Op: crdt.OpInsert, ID: crdt.ID{Replica: "a", Seq: 5}, After: &first, Text: "x"},
+				{Op: crdt.OpInsert, ID: crdt.ID{Replica: "a", Seq: 4}, After: &first, Text: "xy"},
+			},
+		},
+		{"delete unknown character", []crdt.Op{{Op: crdt.OpDelete, ID: unknown}}},
+		{"delete past the run", []crdt.Op{{Op: crdt.OpDelete, ID: first, Count: 4}}},
+		{
+			"valid op followed by an invalid one",
+			[]crdt.Op{
+				{Op: crdt.OpDelete, ID: first},
+				{Op: crdt.OpDelete, ID: unknown},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := crdt.FromText("server", "abc")
+
+			changed, err := doc.Apply(tt.ops)
+
+			require.ErrorIs(t, err, crdt.ErrInvalidOp)
+			assert.False(t, changed)
+			assert.Equal(t, "abc", doc.Text())
+		})
+	}
+}
+
+func TestDoc_ApplyWithinBatch(t *testing.T) {
+	doc := crdt.FromText("server", "ab")
+	first := crdt.ID{Replica: "server", Seq: 1}
+	inserted := crdt.ID{Replica: "a", Seq: 3}
+
+	apply(t, doc,
+		crdt.Op{Op: crdt.OpInsert, ID: inserted, After: &first, Text: "xy"},
+		crdt.Op{Op: crdt.OpInsert, ID: crdt.ID{Replica: "a", Seq: 5}, After: &crdt.ID{Replica: "a", Seq: 4}, Text: "z"},
+		crdt.Op{Op: crdt.OpDelete, ID: inserted},
+	)
+
+	assert.Equal(t, "ayzb", doc.Text())
+}
+
+func TestSnapshot_RoundTrip(t *testing.T) {
+	doc := crdt.FromText("server", "one two three")
+	_, err := doc.Delete(4, 4)
+	require.NoError(t, err)
+	_, err = doc.Insert("a", 4, "2 ")
+	require.NoError(t, err)
+	inFlight := replicate(t, doc)
+	late, err := inFlight.Delete(0, 1)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(doc.Snapshot())
+	require.NoError(t, err)
+	var snap crdt.Snapshot
+	require.NoError(t, json.Unmarshal(data, &snap))
+	restored, err := crdt.FromSnapshot(snap)
+	require.NoError(t, err)
+
+	assert.Equal(t, "one 2 three", restored.Text())
+	assert.Equal(t, doc.Len(), restored.Len())
+	assert.Equal(t, doc.MaxSeq(), restored.MaxSeq())
+	assert.Len(t, snap, 4, "live and deleted stretches are stored as runs")
+
+	// Tombstones survive, so ops made before the snapshot still apply.
+	apply(t, restored, late...)
+	apply(t, doc, late...)
+	assert.Equal(t, doc.Text(), restored.Text())
+}
+
+func TestFromSnapshot_RejectsMalformedRuns(t *testing.T) {
+	tests := []struct {
+		name string
+		snap crdt.Snapshot
+	}{
+		{"run without text", crdt.Snapshot{{ID: crdt.ID{Replica: "a", Seq: 1}}}},
+		{"run with text and tombstones", crdt.Snapshot{{ID: crdt.ID{Replica: "a", Seq: 1}, Text: "x", Deleted: 1}}},
+		{"run without replica", crdt.Snapshot{{ID: crdt.ID{Seq: 1}, Text: "x"}}},
+		{"repeated character", crdt.Snapshot{{ID: crdt.ID{Replica: "a", Seq: 1}, Text: "xy"}, {ID: crdt.ID{Replica: "a", Seq: 2}, Text: "z"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := crdt.FromSnapshot(tt.snap)
+
+			require.ErrorIs(t, err, crdt.ErrInvalidOp)
+		})
+	}
+}
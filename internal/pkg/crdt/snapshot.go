@@ -0,0 +1,92 @@
+package crdt
+
+import "fmt"
+
+// Run is a stretch of characters, in document order, inserted by one
+// replica with consecutive sequence numbers from ID on. A run is either
+// Text, or Deleted tombstones whose text is no longer kept.
+type Run struct {
+	ID      ID     `json:"id"`
+	Text    string `json:"t,omitempty"`
+	Deleted int    `json:"d,omitempty"`
+}
+
+// Snapshot is the full state of a document, tombstones included, so that
+// operations made against any earlier state still apply to it.
+type Snapshot []Run
+
+// Snapshot returns the state of the document.
+func (d *Doc) Snapshot() Snapshot {
+	snap := Snapshot{}
+	var text []rune
+	flush := func() {
+		if len(text) > 0 {
+			snap[len(snap)-1].Text = string(text)
+			text = text[:0]
+		}
+	}
+
+	for n := d.head.next; n != nil; n = n.next {
+		if len(snap) > 0 {
+			last := &snap[len(snap)-1]
+			if last.ID.offset(last.Deleted+len(text)) == n.id && (last.Deleted > 0) == n.deleted {
+				if n.deleted {
+					last.Deleted++
+				} else {
+					text = append(text, n.char)
+				}
+				continue
+			}
+		}
+		flush()
+		run := Run{ID: n.id}
+		if n.deleted {
+			run.Deleted = 1
+		} else {
+			text = append(text, n.char)
+		}
+		snap = append(snap, run)
+	}
+	flush()
+	return snap
+}
+
+// FromSnapshot rebuilds a document from its snapshot.
+func FromSnapshot(snap Snapshot) (*Doc, error) {
+	d := New()
+	tail := &d.head
+	add := func(id ID, char rune, deleted bool) error {
+		if _, seen := d.nodes[id]; seen {
+			return fmt.Errorf("%w: character %s appears twice", ErrInvalidOp, id)
+		}
+		n := &node{id: id, char: char, deleted: deleted}
+		tail.next = n
+		tail = n
+		d.nodes[id] = n
+		d.maxSeq = max(d.maxSeq, id.Seq)
+		if !deleted {
+			d.length++
+		}
+		return nil
+	}
+
+	for _, run := range snap {
+		if run.ID.Replica == "" || run.ID.Seq < 1 || run.Deleted < 0 || (run.Deleted > 0) == (run.Text != "") {
+			return nil, fmt.Errorf("%w: malformed run at %s", ErrInvalidOp, run.ID)
+		}
+		i := 0
+		for range run.Deleted {
+			if err := add(run.ID.offset(i), 0, true); err != nil {
+				return nil, err
+			}
+			i++
+		}
+		for _, char := range run.Text {
+			if err := add(run.ID.offset(i), char, false); err != nil {
+				return nil, err
+			}
+			i++
+		}
+	}
+	return d, nil
+}
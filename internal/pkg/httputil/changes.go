@@ -0,0 +1,17 @@
+package httputil
+
+import "github.com/gin-gonic/gin"
+
+const notesChangedKey = "notes_changed"
+
+// MarkNotesChanged records that the request changed the user's notes, so
+// the change feed tells the user's other devices once the handler returns.
+func MarkNotesChanged(c *gin.Context) {
+	c.Set(notesChangedKey, true)
+}
+
+// NotesChanged reports whether a handler marked the request as changing
+// the user's notes.
+func NotesChanged(c *gin.Context) bool {
+	return c.GetBool(notesChangedKey)
+}
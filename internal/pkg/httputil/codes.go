@@ -0,0 +1,115 @@
+package httputil
+
+import (
+	"net/http"
+	"slices"
+)
+
+// Machine-readable error codes returned in ErrorResponse.Code. Every code must
+// be listed in errorCatalog so clients can discover it via GET /api/v1/errors.
+const (
+	CodeValidationError     = "VALIDATION_ERROR"
+	CodeInternalError       = "INTERNAL_ERROR"
+	CodeUnauthorized        = "UNAUTHORIZED"
+	CodeInvalidCredentials  = "INVALID_CREDENTIALS"
+	CodeUserExists          = "USER_EXISTS"
+	CodeTokenExpired        = "TOKEN_EXPIRED"
+	CodeTokenInvalid        = "TOKEN_INVALID"
+	CodeTokenRevoked        = "TOKEN_REVOKED"
+	CodeResetTokenInvalid   = "RESET_TOKEN_INVALID"
+	CodeSSORequired         = "SSO_REQUIRED"
+	CodeSSOFailed           = "SSO_FAILED"
+	CodeUnknownProvider     = "UNKNOWN_PROVIDER"
+	CodeSocialLoginFailed   = "SOCIAL_LOGIN_FAILED"
+	CodeForbidden           = "FORBIDDEN"
+	CodeDemoReadOnly        = "DEMO_READ_ONLY"
+	CodeNotFound            = "NOT_FOUND"
+	CodeRestoreExpired      = "RESTORE_EXPIRED"
+	CodeInvalidID           = "INVALID_ID"
+	CodeInvalidLocation     = "INVALID_LOCATION"
+	CodeInvalidMeasurement  = "INVALID_MEASUREMENT"
+	CodeInvalidBBox         = "INVALID_BBOX"
+	CodeInvalidRange        = "INVALID_RANGE"
+	CodeInvalidCursor       = "INVALID_CURSOR"
+	CodeInvalidSort         = "INVALID_SORT"
+	CodeInvalidFile         = "INVALID_FILE"
+	CodeInvalidType         = "INVALID_TYPE"
+	CodeDeviceNotFound      = "DEVICE_NOT_FOUND"
+	CodeRateLimited         = "RATE_LIMITED"
+	CodeRateLimitDown       = "RATE_LIMIT_UNAVAILABLE"
+	CodeTooManyUploads      = "TOO_MANY_UPLOADS"
+	CodeClientIDInUse       = "CLIENT_ID_IN_USE"
+	CodeUnsupportedPlatform = "UNSUPPORTED_PLATFORM"
+	CodeConflictResolved    = "CONFLICT_RESOLVED"
+	CodeNoteChanged         = "NOTE_CHANGED"
+	CodeTimeout             = "TIMEOUT"
+	CodeAlreadyMember       = "ALREADY_MEMBER"
+	CodeUpgradeRequired     = "UPGRADE_REQUIRED"
+	CodeIdempotencyBusy     = "IDEMPOTENCY_KEY_IN_USE"
+	CodeIdempotencyMismatch = "IDEMPOTENCY_KEY_REUSED"
+	CodeBodyTooLarge        = "BODY_TOO_LARGE"
+	CodeNotCollaborative    = "NOTE_NOT_COLLABORATIVE"
+	CodeCollaborative       = "NOTE_COLLABORATIVE"
+	CodeInvalidUpdate       = "INVALID_DOCUMENT_UPDATE"
+	CodeLoginLocked         = "LOGIN_LOCKED"
+	CodeSemanticSearchOff   = "SEMANTIC_SEARCH_OFF"
+)
+
+type ErrorCodeInfo struct {
+	Code        string
+	Status      int
+	Description string
+}
+
+var errorCatalog = []ErrorCodeInfo{
+	{CodeValidationError, http.StatusBadRequest, "Request body or query parameters failed validation; details lists the offending fields and rules"},
+	{CodeInternalError, http.StatusInternalServerError, "Unexpected server error; retry later and report the request_id if it persists"},
+	{CodeTimeout, http.StatusGatewayTimeout, "The request took longer than the route allows; retry later"},
+	{CodeUnauthorized, http.StatusUnauthorized, "Missing, malformed or expired access token"},
+	{CodeInvalidCredentials, http.StatusUnauthorized, "Email or password is wrong"},
+	{CodeUserExists, http.StatusConflict, "An account with this email already exists"},
+	{CodeTokenExpired, http.StatusUnauthorized, "Refresh token expired; log in again"},
+	{CodeTokenInvalid, http.StatusUnauthorized, "Refresh token is unknown or malformed"},
+	{CodeTokenRevoked, http.StatusUnauthorized, "Refresh token was revoked by logout or a newer login on the device"},
+	{CodeResetTokenInvalid, http.StatusBadRequest, "Password reset link is unknown, expired or already used; ask for a new one"},
+	{CodeUnsupportedPlatform, http.StatusBadRequest, "The device platform is unknown, not enabled on this server, or cannot receive pushes"},
+	{CodeSSORequired, http.StatusForbidden, "The email belongs to an organization that requires SSO login"},
+	{CodeSSOFailed, http.StatusUnauthorized, "The identity provider response could not be verified"},
+	{CodeUnknownProvider, http.StatusNotFound, "Sign-in with this identity provider is not enabled on this server"},
+	{CodeSocialLoginFailed, http.StatusUnauthorized, "The ID token could not be verified, or it has no verified email to create the account with"},
+	{CodeForbidden, http.StatusForbidden, "The resource belongs to another user"},
+	{CodeDemoReadOnly, http.StatusForbidden, "The demo account is read-only; create an account to save changes"},
+	{CodeNotFound, http.StatusNotFound, "The requested resource does not exist or was deleted"},
+	{CodeRestoreExpired, http.StatusGone, "The note was deleted longer ago than the restore window and can no longer be restored"},
+	{CodeInvalidID, http.StatusBadRequest, "A path identifier is not a valid UUID"},
+	{CodeInvalidLocation, http.StatusBadRequest, "Latitude or longitude is out of range"},
+	{CodeInvalidMeasurement, http.StatusBadRequest, "A measurement value is impossible for its unit, such as a negative length or a temperature below absolute zero"},
+	{CodeInvalidBBox, http.StatusBadRequest, "Bounding box corners are out of range or inverted"},
+	{CodeInvalidRange, http.StatusBadRequest, "Date range start is after its end"},
+	{CodeInvalidCursor, http.StatusBadRequest, "The pagination cursor is malformed; restart from the first page"},
+	{CodeInvalidSort, http.StatusBadRequest, "The sort names an unknown field or repeats one"},
+	{CodeBodyTooLarge, http.StatusRequestEntityTooLarge, "The request body is larger than the route accepts; split sync pushes into smaller batches"},
+	{CodeInvalidFile, http.StatusBadRequest, "Multipart upload is missing the file field"},
+	{CodeInvalidType, http.StatusBadRequest, "Uploaded file type is not supported"},
+	{CodeUpgradeRequired, http.StatusUpgradeRequired, "The endpoint only accepts WebSocket connections"},
+	{CodeDeviceNotFound, http.StatusBadRequest, "The device is not registered for this user; log in from the device first"},
+	{CodeRateLimited, http.StatusTooManyRequests, "Too many requests; wait for Retry-After seconds"},
+	{CodeRateLimitDown, http.StatusServiceUnavailable, "The rate limiter cannot reach its store and this server rejects requests until it recovers; wait for Retry-After seconds"},
+	{CodeTooManyUploads, http.StatusTooManyRequests, "Too many photo uploads running or started in the last minute; wait for Retry-After seconds"},
+	{CodeClientIDInUse, http.StatusConflict, "The photo client_id was already uploaded to another note; generate a new one"},
+	{CodeConflictResolved, http.StatusConflict, "The sync conflict was already resolved"},
+	{CodeNoteChanged, http.StatusConflict, "The note changed after the sync conflict was recorded; review the current version before keeping the client side"},
+	{CodeIdempotencyBusy, http.StatusConflict, "A request with the same Idempotency-Key is still running; retry after Retry-After seconds to get its response"},
+	{CodeIdempotencyMismatch, http.StatusUnprocessableEntity, "The Idempotency-Key was already used for a different method or path; generate a new key for each request"},
+	{CodeAlreadyMember, http.StatusConflict, "The user is already a member of the team or has a pending invitation"},
+	{CodeNotCollaborative, http.StatusConflict, "The note is not in collaborative mode; the owner enables it at /notes/{id}/collaboration"},
+	{CodeCollaborative, http.StatusConflict, "The note's content is edited collaboratively; send document updates instead of the whole content"},
+	{CodeInvalidUpdate, http.StatusBadRequest, "The document update refers to unknown characters, reuses an ID or is otherwise malformed; fetch the document again"},
+	{CodeLoginLocked, http.StatusTooManyRequests, "Too many failed logins for this email from this address; wait for Retry-After seconds"},
+	{CodeSemanticSearchOff, http.StatusForbidden, "Semantic search is off for the account; turn on semantic_search in the preferences and wait for the notes to be indexed"},
+}
+
+// ErrorCatalog returns every error code the API can return.
+func ErrorCatalog() []ErrorCodeInfo {
+	return slices.Clone(errorCatalog)
+}
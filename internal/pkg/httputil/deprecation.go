@@ -0,0 +1,20 @@
+package httputil
+
+import "github.com/gin-gonic/gin"
+
+const deprecatedFieldKey = "deprecated_field"
+
+// UseDeprecatedField reports that the request sent the deprecated request
+// field field, so the response carries its deprecation headers and the use
+// is counted. Call it before writing the response. Fields that are not
+// registered as deprecated on the route are ignored.
+func UseDeprecatedField(c *gin.Context, field string) {
+	if mark, ok := c.Get(deprecatedFieldKey); ok {
+		mark.(func(string))(field)
+	}
+}
+
+// OnDeprecatedField sets what UseDeprecatedField does for the request.
+func OnDeprecatedField(c *gin.Context, mark func(field string)) {
+	c.Set(deprecatedFieldKey, mark)
+}
@@ -0,0 +1,24 @@
+package httputil
+
+import "github.com/gin-gonic/gin"
+
+const kpiCountsKey = "kpi_counts"
+
+// CountKPI adds n occurrences of a KPI event to the request, recorded by the
+// KPI middleware once the handler returns.
+func CountKPI(c *gin.Context, event string, n int64) {
+	counts, _ := c.Get(kpiCountsKey)
+	m, ok := counts.(map[string]int64)
+	if !ok {
+		m = make(map[string]int64)
+		c.Set(kpiCountsKey, m)
+	}
+	m[event] += n
+}
+
+// KPICounts returns the KPI events handlers counted on the request.
+func KPICounts(c *gin.Context) map[string]int64 {
+	counts, _ := c.Get(kpiCountsKey)
+	m, _ := counts.(map[string]int64)
+	return m
+}
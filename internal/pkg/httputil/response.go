@@ -1,16 +1,28 @@
 package httputil
 
 import (
+	"context"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/apperror"
 )
 
+// ErrorResponse is the body of every error the API returns.
 type ErrorResponse struct {
-	Error     string `json:"error"`
-	Code      string `json:"code,omitempty"`
-	RequestID string `json:"request_id,omitempty"`
+	Code      string `json:"code" example:"NOT_FOUND"`
+	Message   string `json:"message" example:"note not found"`
+	RequestID string `json:"request_id,omitempty" example:"5f0c2b8e-3c1a-4d7e-9a51-0f6a2d1e7c44"`
+	// Details adds structured information for some codes, such as the
+	// fields that failed validation.
+	Details any `json:"details,omitempty" swaggertype:"object"`
+	// Error repeats Message for clients written before it existed.
+	//
+	// Deprecated: read Message.
+	Error string `json:"error" example:"note not found"`
 }
 
 func OK(c *gin.Context, data any) {
@@ -25,35 +37,60 @@ func NoContent(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
-func Error(c *gin.Context, status int, message string) {
-	c.JSON(status, ErrorResponse{
-		Error:     message,
+// NewErrorResponse builds the body for err, for responses that cannot go
+// through Fail, such as a stream that already sent its status.
+func NewErrorResponse(c *gin.Context, err *apperror.Error) ErrorResponse {
+	return ErrorResponse{
+		Code:      err.Code,
+		Message:   err.Message,
 		RequestID: GetRequestID(c),
-	})
+		Details:   err.Details,
+		Error:     err.Message,
+	}
+}
+
+// Fail writes err as the response.
+func Fail(c *gin.Context, err *apperror.Error) {
+	c.JSON(err.Status, NewErrorResponse(c, err))
+}
+
+// Abort writes err as the response and stops the handler chain, for
+// middleware.
+func Abort(c *gin.Context, err *apperror.Error) {
+	c.AbortWithStatusJSON(err.Status, NewErrorResponse(c, err))
 }
 
+// ErrorWithCode writes an error response.
+//
+// Deprecated: use Fail with an apperror.Error.
 func ErrorWithCode(c *gin.Context, status int, code, message string) {
-	c.JSON(status, ErrorResponse{
-		Error:     message,
-		Code:      code,
-		RequestID: GetRequestID(c),
-	})
+	Fail(c, apperror.New(status, code, message))
 }
 
+// ValidationError reports a request that failed binding. Failed validation
+// rules are listed in the details, and a body cut off at its size limit is
+// reported as too large.
 func ValidationError(c *gin.Context, err error) {
-	c.JSON(http.StatusBadRequest, ErrorResponse{
-		Error:     err.Error(),
-		Code:      "VALIDATION_ERROR",
-		RequestID: GetRequestID(c),
-	})
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		Fail(c, apperror.New(http.StatusRequestEntityTooLarge, CodeBodyTooLarge, "request body is too large"))
+		return
+	}
+	appErr := apperror.New(http.StatusBadRequest, CodeValidationError, err.Error())
+	if fields := FieldErrors(err); len(fields) > 0 {
+		appErr = appErr.WithDetails(fields)
+	}
+	Fail(c, appErr)
 }
 
+// InternalError reports an unexpected failure. A failure caused by the
+// request running out of its time reports a timeout instead.
 func InternalError(c *gin.Context) {
-	c.JSON(http.StatusInternalServerError, ErrorResponse{
-		Error:     "internal server error",
-		Code:      "INTERNAL_ERROR",
-		RequestID: GetRequestID(c),
-	})
+	if errors.Is(c.Request.Context().Err(), context.DeadlineExceeded) {
+		Fail(c, apperror.New(http.StatusGatewayTimeout, CodeTimeout, "request timed out"))
+		return
+	}
+	Fail(c, apperror.New(http.StatusInternalServerError, CodeInternalError, "internal server error"))
 }
 
 func GetUserID(c *gin.Context) uuid.UUID {
@@ -63,6 +100,29 @@ func GetUserID(c *gin.Context) uuid.UUID {
 	return uuid.Nil
 }
 
+// GetSessionID returns the session the access token was issued for, or
+// uuid.Nil if the token is not bound to one.
+func GetSessionID(c *gin.Context) uuid.UUID {
+	if id, exists := c.Get("session_id"); exists {
+		return id.(uuid.UUID)
+	}
+	return uuid.Nil
+}
+
+// GetTokenDeviceID returns the registered device the access token was issued
+// to, or uuid.Nil if the token is not bound to one.
+func GetTokenDeviceID(c *gin.Context) uuid.UUID {
+	if id, exists := c.Get("token_device_id"); exists {
+		return id.(uuid.UUID)
+	}
+	return uuid.Nil
+}
+
+// GetDeviceID returns the client device identifier sent in the X-Device-ID header.
+func GetDeviceID(c *gin.Context) string {
+	return c.GetHeader("X-Device-ID")
+}
+
 func GetRequestID(c *gin.Context) string {
 	if id, exists := c.Get("request_id"); exists {
 		return id.(string)
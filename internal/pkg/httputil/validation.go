@@ -0,0 +1,52 @@
+package httputil
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError is a validation rule a request field failed.
+type FieldError struct {
+	Field string `json:"field" example:"title"`
+	Rule  string `json:"rule" example:"max"`
+	Param string `json:"param,omitempty" example:"255"`
+}
+
+// FieldErrors lists the rules that failed in a binding error, or nil when
+// the error is not a validation failure, such as malformed JSON.
+func FieldErrors(err error) []FieldError {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return nil
+	}
+	fields := make([]FieldError, len(errs))
+	for i, fe := range errs {
+		fields[i] = FieldError{Field: fe.Field(), Rule: fe.Tag(), Param: fe.Param()}
+	}
+	return fields
+}
+
+// Validation errors name fields as clients send them: by their json, form
+// or uri tag rather than the Go field name.
+func init() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		for _, key := range []string{"json", "form", "uri"} {
+			name, _, _ := strings.Cut(f.Tag.Get(key), ",")
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return f.Name
+	})
+}
@@ -0,0 +1,66 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks a position in a list ordered by updated_at and id, both
+// descending. The next page holds the items that sort after it.
+type Cursor struct {
+	UpdatedAt time.Time
+	ID        uuid.UUID
+	// Values are the item's value for each SortKey of a list sorted
+	// another way, formatted by the list; empty for the default order.
+	Values []string
+}
+
+// Encode returns the cursor as an opaque URL-safe token.
+func (c Cursor) Encode() string {
+	raw := c.UpdatedAt.UTC().Format(time.RFC3339Nano) + "," + c.ID.String()
+	if len(c.Values) > 0 {
+		values, _ := json.Marshal(c.Values)
+		raw += "," + base64.RawURLEncoding.EncodeToString(values)
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a token returned by Cursor.Encode.
+func DecodeCursor(token string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parts := strings.SplitN(string(raw), ",", 3)
+	if len(parts) < 2 {
+		return nil, ErrInvalidCursor
+	}
+	at, id := parts[0], parts[1]
+
+	updatedAt, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	cursor := &Cursor{UpdatedAt: updatedAt, ID: parsedID}
+	if len(parts) == 3 {
+		values, err := base64.RawURLEncoding.DecodeString(parts[2])
+		if err != nil || json.Unmarshal(values, &cursor.Values) != nil || len(cursor.Values) == 0 {
+			return nil, ErrInvalidCursor
+		}
+	}
+	return cursor, nil
+}
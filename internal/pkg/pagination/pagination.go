@@ -9,6 +9,9 @@ const (
 type Params struct {
 	Page    int
 	PerPage int
+	// After switches to keyset pagination: when set, Page is ignored and
+	// the list continues after this position.
+	After *Cursor
 }
 
 func NewParams(page, perPage int) Params {
@@ -36,12 +39,13 @@ func (p Params) Limit() int {
 }
 
 type Info struct {
-	Page       int  `json:"page"`
-	PerPage    int  `json:"per_page"`
-	TotalItems int  `json:"total_items"`
-	TotalPages int  `json:"total_pages"`
-	HasNext    bool `json:"has_next"`
-	HasPrev    bool `json:"has_prev"`
+	Page       int    `json:"page"`
+	PerPage    int    `json:"per_page"`
+	TotalItems int    `json:"total_items"`
+	TotalPages int    `json:"total_pages"`
+	HasNext    bool   `json:"has_next"`
+	HasPrev    bool   `json:"has_prev"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 func NewInfo(page, perPage, totalItems int) *Info {
@@ -62,3 +66,17 @@ func NewInfo(page, perPage, totalItems int) *Info {
 		HasPrev:    page > 1,
 	}
 }
+
+// NewCursorInfo describes a keyset page. Totals are not counted, which is
+// what keeps deep pages cheap; next is nil on the last page.
+func NewCursorInfo(perPage int, next *Cursor, hasPrev bool) *Info {
+	info := &Info{
+		PerPage: perPage,
+		HasNext: next != nil,
+		HasPrev: hasPrev,
+	}
+	if next != nil {
+		info.NextCursor = next.Encode()
+	}
+	return info
+}
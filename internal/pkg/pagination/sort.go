@@ -0,0 +1,38 @@
+package pagination
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+var ErrInvalidSort = errors.New("invalid sort")
+
+// SortKey orders a list by one field.
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSort reads a comma-separated list of fields, each prefixed with - to
+// sort it in descending order, such as "-updated_at,title". Every field must
+// be one of allowed and appear once. An empty string returns no keys, which
+// leaves the list in its default order.
+func ParseSort(s string, allowed []string) ([]SortKey, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	var keys []SortKey
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		key := SortKey{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if !slices.Contains(allowed, key.Field) || seen[key.Field] {
+			return nil, ErrInvalidSort
+		}
+		seen[key.Field] = true
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
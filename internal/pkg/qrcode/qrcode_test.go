@@ -0,0 +1,292 @@
+package qrcode_test
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/qrcode"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name    string
+		length  int
+		level   qrcode.Level
+		version int
+		// blocks are the data codewords of each error correction block.
+		blocks []int
+		ecc    int
+	}{
+		{"version 1-M", 14, qrcode.LevelM, 1, []int{16}, 10},
+		{"version 5-Q with two block sizes", 55, qrcode.LevelQ, 5, []int{15, 15, 16, 16}, 18},
+		{"version 7-L with version information", 150, qrcode.LevelL, 7, []int{78, 78}, 20},
+		{"version 10-H", 100, qrcode.LevelH, 10, []int{15, 15, 15, 15, 15, 15, 16, 16}, 28},
+		{"version 32-M with uneven alignment spacing", 1500, qrcode.LevelM, 32, append(repeat(10, 46), repeat(23, 47)...), 28},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := []byte(strings.Repeat("fieldnotes://notes/0123456789abcdef", 50)[:tt.length])
+
+			code, err := qrcode.Encode(data, tt.level)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.version*4+17, code.Size())
+			assert.Equal(t, data, decode(t, code, tt.level, tt.blocks, tt.ecc))
+		})
+	}
+}
+
+func TestEncode_Capacity(t *testing.T) {
+	for _, tt := range []struct {
+		level    qrcode.Level
+		capacity int
+	}{
+		{qrcode.LevelL, 17},
+		{qrcode.LevelH, 7},
+	} {
+		code, err := qrcode.Encode(make([]byte, tt.capacity), tt.level)
+		require.NoError(t, err)
+		assert.Equal(t, 21, code.Size(), "fits version 1")
+
+		code, err = qrcode.Encode(make([]byte, tt.capacity+1), tt.level)
+		require.NoError(t, err)
+		assert.Equal(t, 25, code.Size(), "needs version 2")
+	}
+
+	_, err := qrcode.Encode(make([]byte, 2953), qrcode.LevelL)
+	require.NoError(t, err)
+
+	_, err = qrcode.Encode(make([]byte, 2954), qrcode.LevelL)
+	assert.ErrorIs(t, err, qrcode.ErrTooLong)
+}
+
+func TestCode_Render(t *testing.T) {
+	code, err := qrcode.Encode([]byte("PLOT-0001"), qrcode.LevelM)
+	require.NoError(t, err)
+
+	t.Run("png fits whole pixels per module", func(t *testing.T) {
+		data, err := code.PNG(256)
+		require.NoError(t, err)
+
+		img, err := png.Decode(bytes.NewReader(data))
+		require.NoError(t, err)
+		// 21 modules plus two 4-module quiet zones, 8 pixels each.
+		assert.Equal(t, 29*8, img.Bounds().Dx())
+
+		r, _, _, _ := img.At(4*8, 4*8).RGBA()
+		assert.Zero(t, r, "finder pattern corner is dark")
+		r, _, _, _ = img.At(0, 0).RGBA()
+		assert.NotZero(t, r, "quiet zone is light")
+	})
+
+	t.Run("svg", func(t *testing.T) {
+		svg := string(code.SVG(300))
+
+		assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="300" height="300" viewBox="0 0 29 29"`))
+		// The top row of the top-left finder pattern is one 7-module run.
+		assert.Contains(t, svg, "M4 4h7v1h-7z")
+	})
+}
+
+// decode reads the symbol back, checking the format information and the
+// Reed-Solomon codewords of every block along the way.
+func decode(t *testing.T, code *qrcode.Code, level qrcode.Level, blocks []int, ecc int) []byte {
+	t.Helper()
+	size := code.Size()
+	version := (size - 17) / 4
+
+	format := 0
+	formatAt := [][2]int{{8, 0}, {8, 1}, {8, 2}, {8, 3}, {8, 4}, {8, 5}, {8, 7}, {8, 8}, {7, 8}, {5, 8}, {4, 8}, {3, 8}, {2, 8}, {1, 8}, {0, 8}}
+	for i, p := range formatAt {
+		if code.Dark(p[0], p[1]) {
+			format |= 1 << i
+		}
+	}
+	format ^= 0x5412
+	rem := format
+	for i := 14; i >= 10; i-- {
+		if rem>>i&1 == 1 {
+			rem ^= 0x537 << (i - 10)
+		}
+	}
+	require.Zero(t, rem, "format information is a BCH codeword")
+	require.Equal(t, []int{1, 0, 3, 2}[level], format>>13, "format information carries the level")
+	mask := format >> 10 & 7
+
+	if version >= 7 {
+		info := 0
+		for i := range 18 {
+			if code.Dark(size-11+i%3, i/3) {
+				info |= 1 << i
+			}
+		}
+		require.Equal(t, version, info>>12)
+	}
+
+	align := alignmentCenters(version)
+	isFunction := func(x, y int) bool {
+		switch {
+		case x == 6 || y == 6,
+			x <= 8 && y <= 8,
+			x >= size-8 && y <= 8,
+			x <= 8 && y >= size-8:
+			return true
+		case version >= 7 && ((x < 6 && y >= size-11 && y < size-8) || (y < 6 && x >= size-11 && x < size-8)):
+			return true
+		}
+		for i, ax := range align {
+			for j, ay := range align {
+				corner := (i == 0 && j == 0) || (i == 0 && j == len(align)-1) || (i == len(align)-1 && j == 0)
+				if !corner && abs(x-ax) <= 2 && abs(y-ay) <= 2 {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	masked := func(x, y int) bool {
+		return [8]bool{
+			(x+y)%2 == 0, y%2 == 0, x%3 == 0, (x+y)%3 == 0,
+			(x/3+y/2)%2 == 0, x*y%2+x*y%3 == 0, (x*y%2+x*y%3)%2 == 0, ((x+y)%2+x*y%3)%2 == 0,
+		}[mask]
+	}
+
+	var bits []bool
+	for right := size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := range size {
+			for j := range 2 {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = size - 1 - vert
+				}
+				if !isFunction(x, y) {
+					bits = append(bits, code.Dark(x, y) != masked(x, y))
+				}
+			}
+		}
+	}
+	codewords := make([]byte, len(bits)/8)
+	for i := range codewords {
+		for _, b := range bits[i*8 : i*8+8] {
+			codewords[i] <<= 1
+			if b {
+				codewords[i] |= 1
+			}
+		}
+	}
+
+	// Deinterleave: data codewords column by column, then error correction.
+	maxData := blocks[len(blocks)-1]
+	split := make([][]byte, len(blocks))
+	k := 0
+	for i := range maxData {
+		for j, n := range blocks {
+			if i < n {
+				split[j] = append(split[j], codewords[k])
+				k++
+			}
+		}
+	}
+	for range ecc {
+		for j := range blocks {
+			split[j] = append(split[j], codewords[k])
+			k++
+		}
+	}
+
+	var data []byte
+	for j, block := range split {
+		for i := range ecc {
+			require.Zero(t, evalPoly(block, gfPow(i)), "block %d syndrome %d", j, i)
+		}
+		data = append(data, block[:blocks[j]]...)
+	}
+
+	r := bitReader{data: data}
+	require.Equal(t, 0x4, r.read(4), "byte mode")
+	count := r.read(8)
+	if version > 9 {
+		count = count<<8 | r.read(8)
+	}
+	out := make([]byte, count)
+	for i := range out {
+		out[i] = byte(r.read(8))
+	}
+	return out
+}
+
+func alignmentCenters(version int) []int {
+	// Table E.1 of the standard, for the versions tested.
+	return map[int][]int{1: nil, 5: {6, 30}, 7: {6, 22, 38}, 10: {6, 28, 50}, 32: {6, 34, 60, 86, 112, 138}}[version]
+}
+
+func repeat(n, v int) []int {
+	out := make([]int, n)
+	for i := range out {
+		out[i] = v
+	}
+	return out
+}
+
+type bitReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *bitReader) read(n int) int {
+	v := 0
+	for range n {
+		v = v<<1 | int(r.data[r.pos/8]>>(7-r.pos%8)&1)
+		r.pos++
+	}
+	return v
+}
+
+// evalPoly evaluates the codeword polynomial, highest power first, at x.
+func evalPoly(poly []byte, x byte) byte {
+	var y byte
+	for _, c := range poly {
+		y = gfMul(y, x) ^ c
+	}
+	return y
+}
+
+func gfPow(n int) byte {
+	x := byte(1)
+	for range n {
+		x = gfMul(x, 2)
+	}
+	return x
+}
+
+func gfMul(a, b byte) byte {
+	var p byte
+	for b > 0 {
+		if b&1 == 1 {
+			p ^= a
+		}
+		carry := a&0x80 != 0
+		a <<= 1
+		if carry {
+			a ^= 0x1D
+		}
+		b >>= 1
+	}
+	return p
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,72 @@
+package qrcode
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// quietZone is the light border, in modules, that readers need around the
+// symbol.
+const quietZone = 4
+
+// modulePixels returns the whole number of pixels per module that fits the
+// symbol and its quiet zone in size pixels, at least 1.
+func (c *Code) modulePixels(size int) int {
+	return max(1, size/(c.size+2*quietZone))
+}
+
+// PNG renders the symbol with its quiet zone as a black and white PNG at
+// most size pixels wide, and at least one pixel per module. Modules are
+// whole pixels so the edges stay sharp when printed.
+func (c *Code) PNG(size int) ([]byte, error) {
+	scale := c.modulePixels(size)
+	width := (c.size + 2*quietZone) * scale
+
+	img := image.NewPaletted(image.Rect(0, 0, width, width), color.Palette{color.White, color.Black})
+	for y := range c.size {
+		for x := range c.size {
+			if !c.modules[y][x] {
+				continue
+			}
+			for dy := range scale {
+				row := img.Pix[((y+quietZone)*scale+dy)*img.Stride:]
+				for dx := range scale {
+					row[(x+quietZone)*scale+dx] = 1
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("encoding png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// SVG renders the symbol with its quiet zone as an SVG of size pixels,
+// drawing each row's runs of dark modules as one path.
+func (c *Code) SVG(size int) []byte {
+	dim := c.size + 2*quietZone
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size, dim, dim)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, dim, dim)
+	for y := range c.size {
+		for x := 0; x < c.size; x++ {
+			if !c.modules[y][x] {
+				continue
+			}
+			start := x
+			for x < c.size && c.modules[y][x] {
+				x++
+			}
+			fmt.Fprintf(&buf, "M%d %dh%dv1h-%dz", start+quietZone, y+quietZone, x-start, x-start)
+		}
+	}
+	buf.WriteString(`"/></svg>`)
+	return buf.Bytes()
+}
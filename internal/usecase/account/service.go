@@ -0,0 +1,81 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/storage"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/audit"
+)
+
+type Service struct {
+	deletionRepo repository.AccountDeletionRepository
+	storage      storage.ImageStorage
+	// purgeDelay is how long a locked account waits before it is purged.
+	purgeDelay    time.Duration
+	auditRecorder *audit.Recorder
+}
+
+func NewService(deletionRepo repository.AccountDeletionRepository, imageStorage storage.ImageStorage, purgeDelay time.Duration, auditRecorder *audit.Recorder) *Service {
+	return &Service{
+		deletionRepo:  deletionRepo,
+		storage:       imageStorage,
+		purgeDelay:    purgeDelay,
+		auditRecorder: auditRecorder,
+	}
+}
+
+// Delete locks the user's account, revokes their sessions and schedules the
+// purge of everything they stored.
+func (s *Service) Delete(ctx context.Context, userID uuid.UUID) (*entity.AccountDeletion, error) {
+	deletion := entity.NewAccountDeletion(userID)
+	if err := s.deletionRepo.Create(ctx, deletion); err != nil {
+		return nil, err
+	}
+	s.auditRecorder.Record(ctx, entity.NewAuditEvent(userID, entity.AuditAccountDeletion, ""))
+	return deletion, nil
+}
+
+// Purge purges up to limit accounts whose deletion is due and returns the
+// ones it finished. An account that fails is left for the next run; the
+// others are still purged.
+func (s *Service) Purge(ctx context.Context, limit int) ([]entity.AccountDeletion, error) {
+	deletions, err := s.deletionRepo.ListPending(ctx, time.Now().UTC().Add(-s.purgeDelay), limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing pending account deletions: %w", err)
+	}
+
+	var purged []entity.AccountDeletion
+	var errs []error
+	for _, d := range deletions {
+		if err := s.purge(ctx, &d); err != nil {
+			errs = append(errs, fmt.Errorf("purging account %s: %w", d.UserID, err))
+			continue
+		}
+		purged = append(purged, d)
+	}
+	return purged, errors.Join(errs...)
+}
+
+// purge removes the stored files before the rows that point to them, so a
+// failure leaves the account to be retried rather than files nobody tracks.
+func (s *Service) purge(ctx context.Context, deletion *entity.AccountDeletion) error {
+	keys, err := s.deletionRepo.ObjectKeys(ctx, deletion.UserID)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			return fmt.Errorf("deleting %s from storage: %w", key, err)
+		}
+	}
+
+	deletion.MarkPurged(len(keys))
+	return s.deletionRepo.Purge(ctx, deletion)
+}
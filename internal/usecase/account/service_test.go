@@ -0,0 +1,117 @@
+package account_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/account"
+)
+
+func TestService_Delete(t *testing.T) {
+	t.Run("locks the account", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		deletionRepo := mocks.NewMockAccountDeletionRepository(ctrl)
+		svc := account.NewService(deletionRepo, mocks.NewMockImageStorage(ctrl), time.Hour, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+
+		deletionRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, d *entity.AccountDeletion) error {
+			assert.Equal(t, userID, d.UserID)
+			assert.Nil(t, d.PurgedAt)
+			return nil
+		})
+
+		deletion, err := svc.Delete(ctx, userID)
+
+		require.NoError(t, err)
+		assert.Equal(t, userID, deletion.UserID)
+	})
+
+	t.Run("account already locked", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		deletionRepo := mocks.NewMockAccountDeletionRepository(ctrl)
+		svc := account.NewService(deletionRepo, mocks.NewMockImageStorage(ctrl), time.Hour, nil)
+
+		deletionRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(domain.ErrUserNotFound)
+
+		_, err := svc.Delete(context.Background(), uuid.New())
+
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+}
+
+func TestService_Purge(t *testing.T) {
+	t.Run("deletes stored files before the account", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		deletionRepo := mocks.NewMockAccountDeletionRepository(ctrl)
+		storage := mocks.NewMockImageStorage(ctrl)
+		svc := account.NewService(deletionRepo, storage, time.Hour, nil)
+
+		ctx := context.Background()
+		pending := entity.AccountDeletion{ID: uuid.New(), UserID: uuid.New()}
+
+		deletionRepo.EXPECT().ListPending(ctx, gomock.Any(), 10).DoAndReturn(
+			func(_ context.Context, before time.Time, _ int) ([]entity.AccountDeletion, error) {
+				assert.WithinDuration(t, time.Now().Add(-time.Hour), before, time.Minute)
+				return []entity.AccountDeletion{pending}, nil
+			})
+		deletionRepo.EXPECT().ObjectKeys(ctx, pending.UserID).Return([]string{"photo.jpg", "photo_small.jpg", "memo.m4a"}, nil)
+		gomock.InOrder(
+			storage.EXPECT().Delete(ctx, "photo.jpg").Return(nil),
+			storage.EXPECT().Delete(ctx, "photo_small.jpg").Return(nil),
+			storage.EXPECT().Delete(ctx, "memo.m4a").Return(nil),
+			deletionRepo.EXPECT().Purge(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, d *entity.AccountDeletion) error {
+				assert.NotNil(t, d.PurgedAt)
+				assert.Equal(t, 3, d.ObjectsDeleted)
+				return nil
+			}),
+		)
+
+		purged, err := svc.Purge(ctx, 10)
+
+		require.NoError(t, err)
+		require.Len(t, purged, 1)
+		assert.Equal(t, pending.UserID, purged[0].UserID)
+	})
+
+	t.Run("leaves an account whose files could not be deleted for the next run", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		deletionRepo := mocks.NewMockAccountDeletionRepository(ctrl)
+		storage := mocks.NewMockImageStorage(ctrl)
+		svc := account.NewService(deletionRepo, storage, time.Hour, nil)
+
+		ctx := context.Background()
+		failing := entity.AccountDeletion{ID: uuid.New(), UserID: uuid.New()}
+		empty := entity.AccountDeletion{ID: uuid.New(), UserID: uuid.New()}
+
+		deletionRepo.EXPECT().ListPending(ctx, gomock.Any(), 10).Return([]entity.AccountDeletion{failing, empty}, nil)
+		deletionRepo.EXPECT().ObjectKeys(ctx, failing.UserID).Return([]string{"photo.jpg"}, nil)
+		storage.EXPECT().Delete(ctx, "photo.jpg").Return(assert.AnError)
+		deletionRepo.EXPECT().ObjectKeys(ctx, empty.UserID).Return(nil, nil)
+		deletionRepo.EXPECT().Purge(ctx, gomock.Any()).Return(nil)
+
+		purged, err := svc.Purge(ctx, 10)
+
+		assert.ErrorIs(t, err, assert.AnError)
+		require.Len(t, purged, 1)
+		assert.Equal(t, empty.UserID, purged[0].UserID)
+	})
+}
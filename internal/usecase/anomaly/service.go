@@ -0,0 +1,237 @@
+package anomaly
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/notification"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+)
+
+// Thresholds decide what the analyzer flags. A zero value disables the
+// corresponding check.
+type Thresholds struct {
+	// MaxTravelSpeed is the fastest plausible trip between two logins, in km/h.
+	MaxTravelSpeed float64
+	// MinTravelDistance ignores jumps shorter than this many km, which GeoIP
+	// imprecision alone can produce.
+	MinTravelDistance float64
+	// RefreshLimit is how many token refreshes a device may make in one
+	// RefreshWindow.
+	RefreshLimit  int
+	RefreshWindow time.Duration
+	// DailySyncBytes is the traffic a device may move in a UTC day.
+	DailySyncBytes int64
+}
+
+type Service struct {
+	eventRepo  repository.AuthEventRepository
+	alertRepo  repository.SecurityAlertRepository
+	usageRepo  repository.DeviceUsageRepository
+	deviceRepo repository.DeviceRepository
+	userRepo   repository.UserRepository
+	notifier   notification.Notifier
+	thresholds Thresholds
+}
+
+// NewService creates the anomaly analyzer. notifier may be nil, in which case
+// alerts are only stored for admins.
+func NewService(
+	eventRepo repository.AuthEventRepository,
+	alertRepo repository.SecurityAlertRepository,
+	usageRepo repository.DeviceUsageRepository,
+	deviceRepo repository.DeviceRepository,
+	userRepo repository.UserRepository,
+	notifier notification.Notifier,
+	thresholds Thresholds,
+) *Service {
+	return &Service{
+		eventRepo:  eventRepo,
+		alertRepo:  alertRepo,
+		usageRepo:  usageRepo,
+		deviceRepo: deviceRepo,
+		userRepo:   userRepo,
+		notifier:   notifier,
+		thresholds: thresholds,
+	}
+}
+
+// Analyze checks the auth events at or after since and the device traffic of
+// now's day. Alerts already raised by an earlier run are skipped, so runs may
+// overlap; the new ones are stored, sent to their users and returned.
+func (s *Service) Analyze(ctx context.Context, since, now time.Time) ([]entity.SecurityAlert, error) {
+	events, err := s.eventRepo.ListSince(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("listing auth events: %w", err)
+	}
+
+	candidates := append(s.impossibleTravel(events), s.refreshStorms(events)...)
+
+	heavy, err := s.syncVolume(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	candidates = append(candidates, heavy...)
+
+	var raised []entity.SecurityAlert
+	for i := range candidates {
+		created, err := s.alertRepo.Create(ctx, &candidates[i])
+		if err != nil {
+			return nil, fmt.Errorf("storing security alert: %w", err)
+		}
+		if created {
+			raised = append(raised, candidates[i])
+			s.notify(ctx, candidates[i])
+		}
+	}
+	return raised, nil
+}
+
+// impossibleTravel flags consecutive logins or refreshes of a user whose
+// locations are farther apart than anyone could travel in the time between
+// them. events must be ordered by user and time.
+func (s *Service) impossibleTravel(events []entity.AuthEvent) []entity.SecurityAlert {
+	if s.thresholds.MaxTravelSpeed <= 0 {
+		return nil
+	}
+
+	var alerts []entity.SecurityAlert
+	var prev *entity.AuthEvent
+	for i := range events {
+		cur := &events[i]
+		if cur.Location == nil {
+			continue
+		}
+		if prev == nil || prev.UserID != cur.UserID {
+			prev = cur
+			continue
+		}
+
+		distance := prev.Location.DistanceKm(cur.Location)
+		elapsed := cur.CreatedAt.Sub(prev.CreatedAt)
+		if distance >= s.thresholds.MinTravelDistance &&
+			(elapsed <= 0 || distance/elapsed.Hours() > s.thresholds.MaxTravelSpeed) {
+			detail := fmt.Sprintf("%s from %s, %.0f km from the %s from %s %s earlier",
+				cur.Kind, cur.Place(), distance, prev.Kind, prev.Place(), elapsed.Round(time.Second))
+			deviceID := cur.DeviceID
+			alerts = append(alerts, *entity.NewSecurityAlert(cur.UserID, &deviceID, entity.AlertImpossibleTravel, cur.ID.String(), detail))
+		}
+		prev = cur
+	}
+	return alerts
+}
+
+// refreshStorms flags devices that refreshed their token more than
+// RefreshLimit times within one RefreshWindow, a sign of a leaked refresh
+// token being replayed or a client stuck in a loop.
+func (s *Service) refreshStorms(events []entity.AuthEvent) []entity.SecurityAlert {
+	if s.thresholds.RefreshLimit <= 0 || s.thresholds.RefreshWindow <= 0 {
+		return nil
+	}
+
+	type bucket struct {
+		deviceID uuid.UUID
+		start    time.Time
+	}
+	counts := make(map[bucket]int)
+
+	var alerts []entity.SecurityAlert
+	for _, e := range events {
+		if e.Kind != entity.AuthEventRefresh {
+			continue
+		}
+		b := bucket{deviceID: e.DeviceID, start: e.CreatedAt.Truncate(s.thresholds.RefreshWindow)}
+		counts[b]++
+		if counts[b] != s.thresholds.RefreshLimit+1 {
+			continue
+		}
+
+		detail := fmt.Sprintf("more than %d token refreshes between %s and %s",
+			s.thresholds.RefreshLimit, b.start.UTC().Format(time.RFC3339), b.start.Add(s.thresholds.RefreshWindow).UTC().Format(time.RFC3339))
+		fingerprint := e.DeviceID.String() + "/" + b.start.UTC().Format(time.RFC3339)
+		deviceID := e.DeviceID
+		alerts = append(alerts, *entity.NewSecurityAlert(e.UserID, &deviceID, entity.AlertRefreshStorm, fingerprint, detail))
+	}
+	return alerts
+}
+
+// syncVolume flags devices whose traffic on now's day is above DailySyncBytes.
+func (s *Service) syncVolume(ctx context.Context, now time.Time) ([]entity.SecurityAlert, error) {
+	if s.thresholds.DailySyncBytes <= 0 {
+		return nil, nil
+	}
+
+	usage, err := s.usageRepo.ListAbove(ctx, now, s.thresholds.DailySyncBytes)
+	if err != nil {
+		return nil, fmt.Errorf("listing device usage: %w", err)
+	}
+
+	var alerts []entity.SecurityAlert
+	for _, u := range usage {
+		device, err := s.deviceRepo.GetByID(ctx, u.DeviceID)
+		if err != nil {
+			return nil, fmt.Errorf("getting device: %w", err)
+		}
+
+		day := entity.UsageDay(u.Day).Format("2006-01-02")
+		detail := fmt.Sprintf("%d bytes transferred on %s, above the daily threshold of %d bytes",
+			u.BytesIn+u.BytesOut, day, s.thresholds.DailySyncBytes)
+		deviceID := device.ID
+		alerts = append(alerts, *entity.NewSecurityAlert(device.UserID, &deviceID, entity.AlertSyncVolume, device.ID.String()+"/"+day, detail))
+	}
+	return alerts, nil
+}
+
+// notify emails the user through the notifier. Delivery is best effort: the
+// alert is already stored for admins either way.
+func (s *Service) notify(ctx context.Context, alert entity.SecurityAlert) {
+	if s.notifier == nil {
+		return
+	}
+
+	user, err := s.userRepo.GetByID(ctx, alert.UserID)
+	if err != nil {
+		return
+	}
+
+	_ = s.notifier.SecurityAlert(ctx, entity.SecurityAlertNotice{
+		UserID: user.ID,
+		Email:  user.Email,
+		Alert:  alert,
+	})
+}
+
+// Prune deletes auth events older than before, which no analysis window
+// reaches anymore, and returns how many were removed.
+func (s *Service) Prune(ctx context.Context, before time.Time) (int64, error) {
+	n, err := s.eventRepo.DeleteBefore(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("pruning auth events: %w", err)
+	}
+	return n, nil
+}
+
+type ListInput struct {
+	AdminID uuid.UUID
+	Kind    string
+	Page    int
+	PerPage int
+}
+
+// ListAlerts returns the alerts about members of the organizations the
+// caller administers. Users who administer none get an empty list.
+func (s *Service) ListAlerts(ctx context.Context, input ListInput) ([]entity.SecurityAlert, *pagination.Info, error) {
+	alerts, pageInfo, err := s.alertRepo.ListForAdmin(ctx, input.AdminID, repository.AlertListParams{
+		Pagination: pagination.NewParams(input.Page, input.PerPage),
+		Kind:       input.Kind,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing security alerts: %w", err)
+	}
+	return alerts, pageInfo, nil
+}
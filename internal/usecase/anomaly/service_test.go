@@ -0,0 +1,182 @@
+package anomaly_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/anomaly"
+)
+
+var thresholds = anomaly.Thresholds{
+	MaxTravelSpeed:    1000,
+	MinTravelDistance: 200,
+	RefreshLimit:      3,
+	RefreshWindow:     10 * time.Minute,
+}
+
+var (
+	lisbon = valueobject.NewLocation(38.72, -9.14, nil, nil)
+	porto  = valueobject.NewLocation(41.15, -8.61, nil, nil)
+	tokyo  = valueobject.NewLocation(35.68, 139.69, nil, nil)
+)
+
+func authEvent(userID, deviceID uuid.UUID, kind string, at time.Time, loc *valueobject.Location) entity.AuthEvent {
+	return entity.AuthEvent{
+		ID:        uuid.New(),
+		UserID:    userID,
+		DeviceID:  deviceID,
+		Kind:      kind,
+		IP:        "203.0.113.7",
+		Location:  loc,
+		CreatedAt: at,
+	}
+}
+
+func TestService_Analyze(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	since := now.Add(-time.Hour)
+
+	t.Run("flags impossible travel", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		eventRepo := mocks.NewMockAuthEventRepository(ctrl)
+		alertRepo := mocks.NewMockSecurityAlertRepository(ctrl)
+		svc := anomaly.NewService(eventRepo, alertRepo, nil, nil, nil, nil, thresholds)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		tokyoLogin := authEvent(userID, uuid.New(), entity.AuthEventLogin, now.Add(-20*time.Minute), tokyo)
+		eventRepo.EXPECT().ListSince(ctx, since).Return([]entity.AuthEvent{
+			authEvent(userID, uuid.New(), entity.AuthEventLogin, now.Add(-40*time.Minute), lisbon),
+			tokyoLogin,
+		}, nil)
+		alertRepo.EXPECT().Create(ctx, gomock.Any()).Return(true, nil)
+
+		alerts, err := svc.Analyze(ctx, since, now)
+
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		assert.Equal(t, entity.AlertImpossibleTravel, alerts[0].Kind)
+		assert.Equal(t, userID, alerts[0].UserID)
+		assert.Equal(t, tokyoLogin.ID.String(), alerts[0].Fingerprint)
+	})
+
+	t.Run("plausible trips are not flagged", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		eventRepo := mocks.NewMockAuthEventRepository(ctrl)
+		svc := anomaly.NewService(eventRepo, nil, nil, nil, nil, nil, thresholds)
+
+		ctx := context.Background()
+		userID, otherID := uuid.New(), uuid.New()
+		eventRepo.EXPECT().ListSince(ctx, since).Return([]entity.AuthEvent{
+			// About 275 km in 50 minutes, well below airliner speed.
+			authEvent(userID, uuid.New(), entity.AuthEventLogin, now.Add(-55*time.Minute), lisbon),
+			authEvent(userID, uuid.New(), entity.AuthEventLogin, now.Add(-5*time.Minute), porto),
+			// Different users are never compared.
+			authEvent(otherID, uuid.New(), entity.AuthEventLogin, now.Add(-4*time.Minute), tokyo),
+		}, nil)
+
+		alerts, err := svc.Analyze(ctx, since, now)
+
+		require.NoError(t, err)
+		assert.Empty(t, alerts)
+	})
+
+	t.Run("flags refresh storms once per window", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		eventRepo := mocks.NewMockAuthEventRepository(ctrl)
+		alertRepo := mocks.NewMockSecurityAlertRepository(ctrl)
+		svc := anomaly.NewService(eventRepo, alertRepo, nil, nil, nil, nil, thresholds)
+
+		ctx := context.Background()
+		userID, deviceID := uuid.New(), uuid.New()
+		var events []entity.AuthEvent
+		for i := 0; i < 6; i++ {
+			events = append(events, authEvent(userID, deviceID, entity.AuthEventRefresh, now.Add(-9*time.Minute+time.Duration(i)*time.Minute), nil))
+		}
+		eventRepo.EXPECT().ListSince(ctx, since).Return(events, nil)
+		alertRepo.EXPECT().Create(ctx, gomock.Any()).Return(true, nil)
+
+		alerts, err := svc.Analyze(ctx, since, now)
+
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		assert.Equal(t, entity.AlertRefreshStorm, alerts[0].Kind)
+		require.NotNil(t, alerts[0].DeviceID)
+		assert.Equal(t, deviceID, *alerts[0].DeviceID)
+	})
+
+	t.Run("flags heavy sync volume and notifies the user", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		eventRepo := mocks.NewMockAuthEventRepository(ctrl)
+		alertRepo := mocks.NewMockSecurityAlertRepository(ctrl)
+		usageRepo := mocks.NewMockDeviceUsageRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		notifier := mocks.NewMockNotifier(ctrl)
+		svc := anomaly.NewService(eventRepo, alertRepo, usageRepo, deviceRepo, userRepo, notifier, anomaly.Thresholds{DailySyncBytes: 1000})
+
+		ctx := context.Background()
+		user := &entity.User{ID: uuid.New(), Email: "field@example.com"}
+		device := &entity.Device{ID: uuid.New(), UserID: user.ID}
+
+		eventRepo.EXPECT().ListSince(ctx, since).Return(nil, nil)
+		usageRepo.EXPECT().ListAbove(ctx, now, int64(1000)).Return([]entity.DeviceUsage{
+			{DeviceID: device.ID, Day: now, BytesIn: 800, BytesOut: 900},
+		}, nil)
+		deviceRepo.EXPECT().GetByID(ctx, device.ID).Return(device, nil)
+		alertRepo.EXPECT().Create(ctx, gomock.Any()).Return(true, nil)
+		userRepo.EXPECT().GetByID(ctx, user.ID).Return(user, nil)
+		notifier.EXPECT().SecurityAlert(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, notice entity.SecurityAlertNotice) error {
+				assert.Equal(t, "field@example.com", notice.Email)
+				assert.Equal(t, entity.AlertSyncVolume, notice.Alert.Kind)
+				return nil
+			})
+
+		alerts, err := svc.Analyze(ctx, since, now)
+
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		assert.Equal(t, device.ID.String()+"/2026-03-10", alerts[0].Fingerprint)
+	})
+
+	t.Run("alerts raised by an earlier run are not sent again", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		eventRepo := mocks.NewMockAuthEventRepository(ctrl)
+		alertRepo := mocks.NewMockSecurityAlertRepository(ctrl)
+		notifier := mocks.NewMockNotifier(ctrl)
+		svc := anomaly.NewService(eventRepo, alertRepo, nil, nil, nil, notifier, thresholds)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		eventRepo.EXPECT().ListSince(ctx, since).Return([]entity.AuthEvent{
+			authEvent(userID, uuid.New(), entity.AuthEventLogin, now.Add(-40*time.Minute), lisbon),
+			authEvent(userID, uuid.New(), entity.AuthEventRefresh, now.Add(-20*time.Minute), tokyo),
+		}, nil)
+		alertRepo.EXPECT().Create(ctx, gomock.Any()).Return(false, nil)
+
+		alerts, err := svc.Analyze(ctx, since, now)
+
+		require.NoError(t, err)
+		assert.Empty(t, alerts)
+	})
+}
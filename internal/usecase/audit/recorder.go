@@ -0,0 +1,106 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+)
+
+// maxUserAgentLength is the user_agent column width.
+const maxUserAgentLength = 255
+
+type clientKey struct{}
+
+// Client is where a request came from, as recorded on its audit events.
+// DeviceID is the registered device the access token was issued to, or
+// uuid.Nil when the request is not authenticated.
+type Client struct {
+	IP        string
+	UserAgent string
+	DeviceID  uuid.UUID
+}
+
+// WithClient returns a context whose audit events are recorded as coming
+// from client.
+func WithClient(ctx context.Context, client Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+func ClientFrom(ctx context.Context) (Client, bool) {
+	client, ok := ctx.Value(clientKey{}).(Client)
+	return client, ok
+}
+
+// Recorder keeps the audit log of security-relevant account activity. A nil
+// *Recorder records nothing, so services work without one.
+type Recorder struct {
+	repo repository.AuditEventRepository
+}
+
+func NewRecorder(repo repository.AuditEventRepository) *Recorder {
+	return &Recorder{repo: repo}
+}
+
+// Record stores event, filling its address, user agent and, unless set,
+// device from the client of ctx. It is best effort: the action it records
+// has already happened, so a failed write must not fail it.
+func (r *Recorder) Record(ctx context.Context, event *entity.AuditEvent) {
+	if r == nil {
+		return
+	}
+	if client, ok := ClientFrom(ctx); ok {
+		event.IP = client.IP
+		event.UserAgent = truncate(client.UserAgent, maxUserAgentLength)
+		if event.DeviceID == nil && client.DeviceID != uuid.Nil {
+			event.DeviceID = &client.DeviceID
+		}
+	}
+	// Recorded even when the request that caused it is cancelled.
+	_ = r.repo.Create(context.WithoutCancel(ctx), event)
+}
+
+type ListInput struct {
+	UserID  uuid.UUID
+	Action  string
+	Page    int
+	PerPage int
+}
+
+// List returns the user's audit events, newest first.
+func (r *Recorder) List(ctx context.Context, input ListInput) ([]entity.AuditEvent, *pagination.Info, error) {
+	events, pageInfo, err := r.repo.ListByUser(ctx, input.UserID, repository.AuditListParams{
+		Pagination: pagination.NewParams(input.Page, input.PerPage),
+		Action:     input.Action,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing audit events: %w", err)
+	}
+	return events, pageInfo, nil
+}
+
+// Prune deletes the events recorded before before.
+func (r *Recorder) Prune(ctx context.Context, before time.Time) (int64, error) {
+	n, err := r.repo.DeleteBefore(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("pruning audit events: %w", err)
+	}
+	return n, nil
+}
+
+// truncate cuts s to at most n bytes without splitting a UTF-8 sequence.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,150 @@
+package audit_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/audit"
+)
+
+func TestRecorder_Record(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("fills the client of the request", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockAuditEventRepository(ctrl)
+		recorder := audit.NewRecorder(repo)
+
+		deviceID := uuid.New()
+		ctx := audit.WithClient(context.Background(), audit.Client{
+			IP: "203.0.113.7", UserAgent: "FieldNotes/2.4.0", DeviceID: deviceID,
+		})
+
+		var stored *entity.AuditEvent
+		repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *entity.AuditEvent) error {
+			stored = e
+			return nil
+		})
+
+		recorder.Record(ctx, entity.NewAuditEvent(userID, entity.AuditNoteDeleted, "note-1"))
+
+		require.NotNil(t, stored)
+		assert.Equal(t, "203.0.113.7", stored.IP)
+		assert.Equal(t, "FieldNotes/2.4.0", stored.UserAgent)
+		require.NotNil(t, stored.DeviceID)
+		assert.Equal(t, deviceID, *stored.DeviceID)
+	})
+
+	t.Run("keeps a device set on the event", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockAuditEventRepository(ctrl)
+		recorder := audit.NewRecorder(repo)
+
+		loginDevice := uuid.New()
+		ctx := audit.WithClient(context.Background(), audit.Client{IP: "203.0.113.7"})
+		event := entity.NewAuditEvent(userID, entity.AuditLogin, "password")
+		event.DeviceID = &loginDevice
+
+		repo.EXPECT().Create(gomock.Any(), event).Return(nil)
+
+		recorder.Record(ctx, event)
+
+		assert.Equal(t, loginDevice, *event.DeviceID)
+	})
+
+	t.Run("cuts long user agents", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockAuditEventRepository(ctrl)
+		recorder := audit.NewRecorder(repo)
+
+		ctx := audit.WithClient(context.Background(), audit.Client{UserAgent: strings.Repeat("é", 200)})
+		event := entity.NewAuditEvent(userID, entity.AuditLogout, "")
+
+		repo.EXPECT().Create(gomock.Any(), event).Return(nil)
+
+		recorder.Record(ctx, event)
+
+		assert.Len(t, event.UserAgent, 254)
+	})
+
+	t.Run("records after the request is cancelled and ignores failures", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockAuditEventRepository(ctrl)
+		recorder := audit.NewRecorder(repo)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, _ *entity.AuditEvent) error {
+			assert.NoError(t, ctx.Err())
+			return errors.New("db down")
+		})
+
+		recorder.Record(ctx, entity.NewAuditEvent(userID, entity.AuditLogout, ""))
+	})
+
+	t.Run("nil recorder records nothing", func(t *testing.T) {
+		var recorder *audit.Recorder
+		recorder.Record(context.Background(), entity.NewAuditEvent(userID, entity.AuditLogout, ""))
+	})
+}
+
+func TestRecorder_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuditEventRepository(ctrl)
+	recorder := audit.NewRecorder(repo)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	event := entity.NewAuditEvent(userID, entity.AuditLogin, "password")
+
+	repo.EXPECT().ListByUser(ctx, userID, repository.AuditListParams{
+		Pagination: pagination.NewParams(0, 0),
+		Action:     entity.AuditLogin,
+	}).Return([]entity.AuditEvent{*event}, pagination.NewInfo(1, 20, 1), nil)
+
+	events, info, err := recorder.List(ctx, audit.ListInput{UserID: userID, Action: entity.AuditLogin})
+
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, event.ID, events[0].ID)
+	assert.Equal(t, 1, info.TotalItems)
+}
+
+func TestRecorder_Prune(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuditEventRepository(ctrl)
+	recorder := audit.NewRecorder(repo)
+
+	before := time.Now().UTC().Add(-365 * 24 * time.Hour)
+	repo.EXPECT().DeleteBefore(gomock.Any(), before).Return(int64(3), nil)
+
+	n, err := recorder.Prune(context.Background(), before)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+}
@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+)
+
+// LoginAttemptStore counts failed password logins and keeps the locks they
+// lead to. Keys are opaque to the store.
+type LoginAttemptStore interface {
+	// Fail counts a failure under key and returns the failures counted since
+	// the last reset. The count is forgotten window after the last failure.
+	Fail(ctx context.Context, key string, window time.Duration) (int, error)
+	// Lock refuses logins under key for d.
+	Lock(ctx context.Context, key string, d time.Duration) error
+	// LockedFor returns how long the lock on key still lasts; zero when
+	// there is none.
+	LockedFor(ctx context.Context, key string) (time.Duration, error)
+	// Reset forgets the failures and the lock of key.
+	Reset(ctx context.Context, key string) error
+}
+
+// LockoutConfig sets when password logins are locked. Threshold failures
+// for one email from one address lock that email for that address, and
+// IPThreshold failures from one address for any email lock the address;
+// zero turns either off. The first lock lasts Duration and each further
+// failure doubles it, up to MaxDuration. Failures are forgotten Window
+// after the last one, which should not be shorter than MaxDuration.
+type LockoutConfig struct {
+	Threshold   int
+	IPThreshold int
+	Window      time.Duration
+	Duration    time.Duration
+	MaxDuration time.Duration
+}
+
+// Lockout slows down password guessing by locking logins after repeated
+// failures. It fails open: while its store is unreachable logins are not
+// locked.
+type Lockout struct {
+	store LoginAttemptStore
+	cfg   LockoutConfig
+}
+
+func NewLockout(store LoginAttemptStore, cfg LockoutConfig) *Lockout {
+	return &Lockout{store: store, cfg: cfg}
+}
+
+// LockedError is returned by Login while the email cannot be tried from the
+// client's address.
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return domain.ErrLoginLocked.Error()
+}
+
+func (e *LockedError) Unwrap() error {
+	return domain.ErrLoginLocked
+}
+
+type lockoutKey struct {
+	key       string
+	threshold int
+}
+
+func (l *Lockout) keys(email, ip string) []lockoutKey {
+	email = strings.ToLower(strings.TrimSpace(email))
+	keys := []lockoutKey{{key: "account:" + email + "|" + ip, threshold: l.cfg.Threshold}}
+	if ip != "" && l.cfg.IPThreshold > 0 {
+		keys = append(keys, lockoutKey{key: "ip:" + ip, threshold: l.cfg.IPThreshold})
+	}
+	return keys
+}
+
+// lockedFor returns how long until the email can be tried from ip again.
+func (l *Lockout) lockedFor(ctx context.Context, email, ip string) time.Duration {
+	if l == nil {
+		return 0
+	}
+	var wait time.Duration
+	for _, k := range l.keys(email, ip) {
+		if d, err := l.store.LockedFor(ctx, k.key); err == nil {
+			wait = max(wait, d)
+		}
+	}
+	return wait
+}
+
+// fail counts a failed login and returns how long the lock it started
+// lasts, zero when it started none.
+func (l *Lockout) fail(ctx context.Context, email, ip string) time.Duration {
+	if l == nil {
+		return 0
+	}
+	var locked time.Duration
+	for _, k := range l.keys(email, ip) {
+		n, err := l.store.Fail(ctx, k.key, l.cfg.Window)
+		if err != nil || k.threshold <= 0 || n < k.threshold {
+			continue
+		}
+		d := l.duration(n - k.threshold)
+		if err := l.store.Lock(ctx, k.key, d); err == nil {
+			locked = max(locked, d)
+		}
+	}
+	return locked
+}
+
+// reset forgets the failures of the email from ip after a successful login.
+// The address's own count is left alone, so an attacker cannot clear it by
+// logging in to an account of their own.
+func (l *Lockout) reset(ctx context.Context, email, ip string) {
+	if l == nil {
+		return
+	}
+	_ = l.store.Reset(ctx, l.keys(email, ip)[0].key)
+}
+
+// duration is the lock after extra failures beyond the threshold.
+func (l *Lockout) duration(extra int) time.Duration {
+	d := l.cfg.Duration
+	for range extra {
+		if d >= l.cfg.MaxDuration/2 {
+			return l.cfg.MaxDuration
+		}
+		d *= 2
+	}
+	return min(d, l.cfg.MaxDuration)
+}
@@ -0,0 +1,154 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/auth"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/cache"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/audit"
+	authUC "github.com/marcos-nsantos/field-notes-backend/internal/usecase/auth"
+)
+
+// unlockedStore counts failures but never reports a lock, standing in for
+// locks that have run out.
+type unlockedStore struct {
+	*cache.MemoryLoginAttemptStore
+}
+
+func (unlockedStore) LockedFor(context.Context, string) (time.Duration, error) {
+	return 0, nil
+}
+
+func TestService_LoginLockout(t *testing.T) {
+	ctx := context.Background()
+	cfg := authUC.LockoutConfig{Threshold: 3, IPThreshold: 5, Window: time.Hour, Duration: time.Minute, MaxDuration: 4 * time.Minute}
+	passwordHasher := auth.NewPasswordHasher(4)
+	hash, _ := passwordHasher.Hash("correctpassword")
+
+	login := func(svc *authUC.Service, email, password, ip string) error {
+		_, _, err := svc.Login(ctx, authUC.LoginInput{Email: email, Password: password, Platform: "ios", IP: ip})
+		return err
+	}
+	retryAfter := func(t *testing.T, err error) time.Duration {
+		t.Helper()
+		var locked *authUC.LockedError
+		require.True(t, errors.As(err, &locked), "got %v", err)
+		assert.ErrorIs(t, err, domain.ErrLoginLocked)
+		return locked.RetryAfter
+	}
+
+	t.Run("locks the email for the address after repeated wrong passwords", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		auditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		lockout := authUC.NewLockout(cache.NewMemoryLoginAttemptStore(), cfg)
+		svc := authUC.NewService(userRepo, nil, nil, nil, nil, passwordHasher, nil, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, audit.NewRecorder(auditRepo), lockout, nil)
+
+		user := &entity.User{ID: uuid.New(), Email: "ana@example.com", PasswordHash: hash}
+		userRepo.EXPECT().GetByEmail(ctx, gomock.Any()).Return(user, nil).Times(4)
+		var actions []string
+		auditRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, event *entity.AuditEvent) error {
+			actions = append(actions, event.Action+" "+event.Target)
+			return nil
+		}).AnyTimes()
+
+		assert.ErrorIs(t, login(svc, "ana@example.com", "guess1", "203.0.113.7"), domain.ErrInvalidCredentials)
+		assert.ErrorIs(t, login(svc, "ana@example.com", "guess2", "203.0.113.7"), domain.ErrInvalidCredentials)
+		assert.Equal(t, time.Minute, retryAfter(t, login(svc, "Ana@Example.com", "guess3", "203.0.113.7")))
+
+		// Locked even with the right password, without checking it.
+		wait := retryAfter(t, login(svc, "ana@example.com", "correctpassword", "203.0.113.7"))
+		assert.Greater(t, wait, 50*time.Second)
+
+		// Other addresses can still try.
+		assert.ErrorIs(t, login(svc, "ana@example.com", "guess4", "198.51.100.2"), domain.ErrInvalidCredentials)
+
+		assert.Equal(t, []string{
+			"login_failed password", "login_failed password", "login_failed password", "login_locked 1m0s", "login_failed password",
+		}, actions)
+	})
+
+	t.Run("counts unknown emails the same", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		lockout := authUC.NewLockout(cache.NewMemoryLoginAttemptStore(), cfg)
+		svc := authUC.NewService(userRepo, nil, nil, nil, nil, passwordHasher, nil, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, lockout, nil)
+
+		userRepo.EXPECT().GetByEmail(ctx, "nobody@example.com").Return(nil, domain.ErrUserNotFound).Times(3)
+
+		assert.ErrorIs(t, login(svc, "nobody@example.com", "guess", "203.0.113.7"), domain.ErrInvalidCredentials)
+		assert.ErrorIs(t, login(svc, "nobody@example.com", "guess", "203.0.113.7"), domain.ErrInvalidCredentials)
+		assert.Equal(t, time.Minute, retryAfter(t, login(svc, "nobody@example.com", "guess", "203.0.113.7")))
+	})
+
+	t.Run("doubles the lock with each further failure", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		lockout := authUC.NewLockout(unlockedStore{cache.NewMemoryLoginAttemptStore()}, authUC.LockoutConfig{Threshold: 3, Window: time.Hour, Duration: time.Minute, MaxDuration: 4 * time.Minute})
+		svc := authUC.NewService(userRepo, nil, nil, nil, nil, passwordHasher, nil, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, lockout, nil)
+
+		userRepo.EXPECT().GetByEmail(ctx, gomock.Any()).Return(nil, domain.ErrUserNotFound).AnyTimes()
+
+		var locks []time.Duration
+		for range 7 {
+			if err := login(svc, "ana@example.com", "guess", ""); !errors.Is(err, domain.ErrInvalidCredentials) {
+				locks = append(locks, retryAfter(t, err))
+			}
+		}
+		assert.Equal(t, []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 4 * time.Minute, 4 * time.Minute}, locks)
+	})
+
+	t.Run("locks an address trying many emails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		lockout := authUC.NewLockout(cache.NewMemoryLoginAttemptStore(), cfg)
+		svc := authUC.NewService(userRepo, nil, nil, nil, nil, passwordHasher, nil, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, lockout, nil)
+
+		userRepo.EXPECT().GetByEmail(ctx, gomock.Any()).Return(nil, domain.ErrUserNotFound).Times(5)
+
+		for _, email := range []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com"} {
+			assert.ErrorIs(t, login(svc, email, "guess", "203.0.113.7"), domain.ErrInvalidCredentials)
+		}
+		assert.Equal(t, time.Minute, retryAfter(t, login(svc, "e@example.com", "guess", "203.0.113.7")))
+		retryAfter(t, login(svc, "f@example.com", "guess", "203.0.113.7"))
+	})
+
+	t.Run("a successful login forgets the failures", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		refreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+		orgRepo := mocks.NewMockOrganizationRepository(ctrl)
+		lockout := authUC.NewLockout(cache.NewMemoryLoginAttemptStore(), cfg)
+		svc := authUC.NewService(userRepo, deviceRepo, refreshTokenRepo, orgRepo, auth.NewJWTService("test-secret", 15*time.Minute), passwordHasher, nil, nil, nil, nil, nil, 24*time.Hour, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, lockout, nil)
+
+		user := &entity.User{ID: uuid.New(), Email: "ana@example.com", PasswordHash: hash}
+		device := &entity.Device{ID: uuid.New(), UserID: user.ID, DeviceID: "device-123"}
+		userRepo.EXPECT().GetByEmail(ctx, "ana@example.com").Return(user, nil).Times(5)
+		orgRepo.EXPECT().GetByDomain(ctx, "example.com").Return(nil, domain.ErrOrgNotFound)
+		orgRepo.EXPECT().ListByUserID(ctx, user.ID).Return(nil, nil)
+		deviceRepo.EXPECT().Upsert(ctx, gomock.Any()).Return(nil)
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, user.ID, "device-123").Return(device, nil)
+		refreshTokenRepo.EXPECT().RevokeByDeviceID(ctx, device.ID).Return(nil)
+		refreshTokenRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+		assert.ErrorIs(t, login(svc, "ana@example.com", "guess1", ""), domain.ErrInvalidCredentials)
+		assert.ErrorIs(t, login(svc, "ana@example.com", "guess2", ""), domain.ErrInvalidCredentials)
+		_, _, err := svc.Login(ctx, authUC.LoginInput{Email: "ana@example.com", Password: "correctpassword", DeviceID: "device-123", Platform: "ios"})
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, login(svc, "ana@example.com", "guess3", ""), domain.ErrInvalidCredentials)
+		assert.ErrorIs(t, login(svc, "ana@example.com", "guess4", ""), domain.ErrInvalidCredentials)
+	})
+}
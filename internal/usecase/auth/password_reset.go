@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/mail"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+// PasswordResetConfig controls the links sent by ForgotPassword.
+type PasswordResetConfig struct {
+	TokenTTL time.Duration
+	// URL is the app page that completes the reset; the token is added as
+	// the token query parameter.
+	URL string
+}
+
+// ForgotPassword emails the user a link to set a new password. It returns
+// nil for unknown emails and SSO accounts alike, so the response does not
+// reveal which emails have an account.
+func (s *Service) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil
+		}
+		return fmt.Errorf("getting user: %w", err)
+	}
+
+	// Accounts that must log in through their identity provider have no
+	// password to reset.
+	if err := s.ensurePasswordLoginAllowed(ctx, user.Email, user.ID); err != nil {
+		if errors.Is(err, domain.ErrSSORequired) {
+			return nil
+		}
+		return err
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Errorf("generating reset token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	resetToken := entity.NewPasswordResetToken(user.ID, hashResetToken(token), s.clock.Now().Add(s.reset.TokenTTL))
+	if err := s.resetTokenRepo.Create(ctx, resetToken); err != nil {
+		return fmt.Errorf("creating reset token: %w", err)
+	}
+
+	msg := mail.Message{
+		To:      user.Email,
+		Subject: "Reset your Field Notes password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse this link to choose a new password. It expires in %s and works once:\n\n%s\n\nIf you did not ask to reset your password, you can ignore this email.\n",
+			user.Name, s.reset.TokenTTL, s.resetLink(token),
+		),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("sending reset email: %w", err)
+	}
+
+	return nil
+}
+
+type ResetPasswordInput struct {
+	Token    string
+	Password string
+}
+
+// ResetPassword sets a new password using a token from ForgotPassword. The
+// user is logged out of every device.
+func (s *Service) ResetPassword(ctx context.Context, input ResetPasswordInput) error {
+	resetToken, err := s.resetTokenRepo.GetByHash(ctx, hashResetToken(input.Token))
+	if err != nil {
+		return err
+	}
+	if !resetToken.IsValid(s.clock.Now()) {
+		return domain.ErrResetTokenInvalid
+	}
+
+	user, err := s.userRepo.GetByID(ctx, resetToken.UserID)
+	if err != nil {
+		return fmt.Errorf("getting user: %w", err)
+	}
+
+	// The organization may have enforced SSO after the link was sent.
+	if err := s.ensurePasswordLoginAllowed(ctx, user.Email, user.ID); err != nil {
+		return err
+	}
+
+	hash, err := s.passwordHasher.Hash(input.Password)
+	if err != nil {
+		return fmt.Errorf("hashing password: %w", err)
+	}
+
+	if err := s.resetTokenRepo.Consume(ctx, resetToken.ID); err != nil {
+		return err
+	}
+
+	user.PasswordHash = hash
+	user.UpdatedAt = s.clock.Now()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return fmt.Errorf("updating user: %w", err)
+	}
+
+	if err := s.userRepo.BumpTokenVersion(ctx, user.ID); err != nil {
+		return fmt.Errorf("invalidating access tokens: %w", err)
+	}
+
+	if err := s.refreshTokenRepo.RevokeByUserID(ctx, user.ID); err != nil {
+		return fmt.Errorf("revoking tokens: %w", err)
+	}
+
+	s.auditRecorder.Record(ctx, entity.NewAuditEvent(user.ID, entity.AuditPasswordReset, ""))
+	return nil
+}
+
+func (s *Service) resetLink(token string) string {
+	u, err := url.Parse(s.reset.URL)
+	if err != nil {
+		return s.reset.URL + "?token=" + url.QueryEscape(token)
+	}
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// hashResetToken is what gets stored, so a leaked table cannot be used to
+// reset passwords. The token is random, so a plain hash is enough.
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,145 @@
+package auth_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/mail"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/auth"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	authUC "github.com/marcos-nsantos/field-notes-backend/internal/usecase/auth"
+)
+
+var resetConfig = authUC.PasswordResetConfig{TokenTTL: time.Hour, URL: "https://app.example.com/reset"}
+
+func TestService_ForgotPassword(t *testing.T) {
+	t.Run("emails a reset link", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		orgRepo := mocks.NewMockOrganizationRepository(ctrl)
+		resetRepo := mocks.NewMockPasswordResetTokenRepository(ctrl)
+		mailer := mocks.NewMockMailer(ctrl)
+		svc := authUC.NewService(userRepo, nil, nil, orgRepo, nil, nil, nil, nil, nil, resetRepo, mailer, 0, resetConfig, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
+
+		ctx := context.Background()
+		user := entity.NewUser("ana@example.com", "hash", "Ana")
+
+		userRepo.EXPECT().GetByEmail(ctx, user.Email).Return(user, nil)
+		orgRepo.EXPECT().GetByDomain(ctx, "example.com").Return(nil, domain.ErrOrgNotFound)
+		orgRepo.EXPECT().ListByUserID(ctx, user.ID).Return(nil, nil)
+
+		var stored *entity.PasswordResetToken
+		resetRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, token *entity.PasswordResetToken) error {
+			stored = token
+			return nil
+		})
+		mailer.EXPECT().Send(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, msg mail.Message) error {
+			assert.Equal(t, user.Email, msg.To)
+			assert.Contains(t, msg.Body, "https://app.example.com/reset?token=")
+
+			link := msg.Body[strings.Index(msg.Body, "https://"):]
+			link = link[:strings.Index(link, "\n")]
+			parsed, err := url.Parse(link)
+			require.NoError(t, err)
+			token := parsed.Query().Get("token")
+			require.NotEmpty(t, token)
+			assert.NotEqual(t, token, stored.TokenHash, "only the hash is stored")
+			return nil
+		})
+
+		require.NoError(t, svc.ForgotPassword(ctx, user.Email))
+		assert.Equal(t, user.ID, stored.UserID)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), stored.ExpiresAt, time.Minute)
+	})
+
+	t.Run("does not reveal unknown emails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		svc := authUC.NewService(userRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, 0, resetConfig, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
+
+		ctx := context.Background()
+		userRepo.EXPECT().GetByEmail(ctx, "nobody@example.com").Return(nil, domain.ErrUserNotFound)
+
+		assert.NoError(t, svc.ForgotPassword(ctx, "nobody@example.com"))
+	})
+
+	t.Run("sends nothing to accounts that must use sso", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		orgRepo := mocks.NewMockOrganizationRepository(ctrl)
+		svc := authUC.NewService(userRepo, nil, nil, orgRepo, nil, nil, nil, nil, nil, nil, nil, 0, resetConfig, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
+
+		ctx := context.Background()
+		user := entity.NewUser("ana@acme.com", "hash", "Ana")
+		userRepo.EXPECT().GetByEmail(ctx, user.Email).Return(user, nil)
+		orgRepo.EXPECT().GetByDomain(ctx, "acme.com").Return(&entity.Organization{SSOEnforced: true}, nil)
+
+		assert.NoError(t, svc.ForgotPassword(ctx, user.Email))
+	})
+}
+
+func TestService_ResetPassword(t *testing.T) {
+	t.Run("sets the new password and logs out every device", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		orgRepo := mocks.NewMockOrganizationRepository(ctrl)
+		refreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+		resetRepo := mocks.NewMockPasswordResetTokenRepository(ctrl)
+		passwordHasher := auth.NewPasswordHasher(4)
+		svc := authUC.NewService(userRepo, nil, refreshTokenRepo, orgRepo, nil, passwordHasher, nil, nil, nil, resetRepo, nil, 0, resetConfig, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
+
+		ctx := context.Background()
+		user := entity.NewUser("ana@example.com", "old-hash", "Ana")
+		token := entity.NewPasswordResetToken(user.ID, "hash", time.Now().Add(time.Hour))
+
+		resetRepo.EXPECT().GetByHash(ctx, gomock.Any()).Return(token, nil)
+		userRepo.EXPECT().GetByID(ctx, user.ID).Return(user, nil)
+		orgRepo.EXPECT().GetByDomain(ctx, "example.com").Return(nil, domain.ErrOrgNotFound)
+		orgRepo.EXPECT().ListByUserID(ctx, user.ID).Return(nil, nil)
+		resetRepo.EXPECT().Consume(ctx, token.ID).Return(nil)
+		userRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, u *entity.User) error {
+			assert.NoError(t, passwordHasher.Compare(u.PasswordHash, "new-password"))
+			return nil
+		})
+		userRepo.EXPECT().BumpTokenVersion(ctx, user.ID).Return(nil)
+		refreshTokenRepo.EXPECT().RevokeByUserID(ctx, user.ID).Return(nil)
+
+		err := svc.ResetPassword(ctx, authUC.ResetPasswordInput{Token: "token", Password: "new-password"})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects expired token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		resetRepo := mocks.NewMockPasswordResetTokenRepository(ctrl)
+		svc := authUC.NewService(nil, nil, nil, nil, nil, nil, nil, nil, nil, resetRepo, nil, 0, resetConfig, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
+
+		ctx := context.Background()
+		token := entity.NewPasswordResetToken(uuid.New(), "hash", time.Now().Add(-time.Minute))
+		resetRepo.EXPECT().GetByHash(ctx, gomock.Any()).Return(token, nil)
+
+		err := svc.ResetPassword(ctx, authUC.ResetPasswordInput{Token: "token", Password: "new-password"})
+
+		assert.ErrorIs(t, err, domain.ErrResetTokenInvalid)
+	})
+}
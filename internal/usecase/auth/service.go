@@ -3,40 +3,96 @@ package auth
 import (
 	"context"
 	"fmt"
+	"net/netip"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/geoip"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/identity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/mail"
 	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
 	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/auth"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/clock"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/audit"
 )
 
 type Service struct {
 	userRepo         repository.UserRepository
 	deviceRepo       repository.DeviceRepository
 	refreshTokenRepo repository.RefreshTokenRepository
+	orgRepo          repository.OrganizationRepository
 	jwtSvc           *auth.JWTService
 	passwordHasher   *auth.PasswordHasher
+	oidcProvider     identity.OIDCProvider
+	geoResolver      geoip.Resolver
+	authEventRepo    repository.AuthEventRepository
+	resetTokenRepo   repository.PasswordResetTokenRepository
+	mailer           mail.Mailer
 	refreshTokenTTL  time.Duration
+	reset            PasswordResetConfig
+	session          SessionConfig
+	authProviderRepo repository.AuthProviderRepository
+	// socialVerifiers are keyed by provider name; social login is off for
+	// providers without one.
+	socialVerifiers map[string]identity.SocialVerifier
+	auditRecorder   *audit.Recorder
+	// lockout locks password logins after repeated failures; nil leaves
+	// them unlocked.
+	lockout *Lockout
+	clock   clock.Clock
 }
 
+// NewService creates the auth service. clk issues and expires refresh and
+// password reset tokens; nil means the system clock.
 func NewService(
 	userRepo repository.UserRepository,
 	deviceRepo repository.DeviceRepository,
 	refreshTokenRepo repository.RefreshTokenRepository,
+	orgRepo repository.OrganizationRepository,
 	jwtSvc *auth.JWTService,
 	passwordHasher *auth.PasswordHasher,
+	oidcProvider identity.OIDCProvider,
+	geoResolver geoip.Resolver,
+	authEventRepo repository.AuthEventRepository,
+	resetTokenRepo repository.PasswordResetTokenRepository,
+	mailer mail.Mailer,
 	refreshTokenTTL time.Duration,
+	reset PasswordResetConfig,
+	session SessionConfig,
+	authProviderRepo repository.AuthProviderRepository,
+	socialVerifiers map[string]identity.SocialVerifier,
+	auditRecorder *audit.Recorder,
+	lockout *Lockout,
+	clk clock.Clock,
 ) *Service {
+	if clk == nil {
+		clk = clock.System{}
+	}
 	return &Service{
 		userRepo:         userRepo,
 		deviceRepo:       deviceRepo,
 		refreshTokenRepo: refreshTokenRepo,
+		orgRepo:          orgRepo,
 		jwtSvc:           jwtSvc,
 		passwordHasher:   passwordHasher,
+		oidcProvider:     oidcProvider,
+		geoResolver:      geoResolver,
+		authEventRepo:    authEventRepo,
+		resetTokenRepo:   resetTokenRepo,
+		mailer:           mailer,
 		refreshTokenTTL:  refreshTokenTTL,
+		reset:            reset,
+		session:          session,
+		authProviderRepo: authProviderRepo,
+		socialVerifiers:  socialVerifiers,
+		auditRecorder:    auditRecorder,
+		lockout:          lockout,
+		clock:            clk,
 	}
 }
 
@@ -61,6 +117,10 @@ func (s *Service) Register(ctx context.Context, input RegisterInput) (*entity.Us
 		return nil, domain.ErrUserAlreadyExists
 	}
 
+	if err := s.ensurePasswordLoginAllowed(ctx, input.Email, uuid.Nil); err != nil {
+		return nil, err
+	}
+
 	hash, err := s.passwordHasher.Hash(input.Password)
 	if err != nil {
 		return nil, fmt.Errorf("hashing password: %w", err)
@@ -71,6 +131,7 @@ func (s *Service) Register(ctx context.Context, input RegisterInput) (*entity.Us
 		return nil, fmt.Errorf("creating user: %w", err)
 	}
 
+	s.auditRecorder.Record(ctx, entity.NewAuditEvent(user.ID, entity.AuditAccountCreated, ""))
 	return user, nil
 }
 
@@ -80,41 +141,119 @@ type LoginInput struct {
 	DeviceID   string
 	DeviceName string
 	Platform   string
+	// IP is the client address, recorded on the device with its location.
+	IP string
 }
 
 func (s *Service) Login(ctx context.Context, input LoginInput) (*TokenPair, *entity.User, error) {
+	platform, err := s.platform(input.Platform)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if wait := s.lockout.lockedFor(ctx, input.Email, input.IP); wait > 0 {
+		return nil, nil, &LockedError{RetryAfter: wait}
+	}
+
+	// Failures count the same whether or not the email has an account, so
+	// the lock does not tell which emails do.
 	user, err := s.userRepo.GetByEmail(ctx, input.Email)
 	if err != nil {
+		if locked := s.lockout.fail(ctx, input.Email, input.IP); locked > 0 {
+			return nil, nil, &LockedError{RetryAfter: locked}
+		}
 		return nil, nil, domain.ErrInvalidCredentials
 	}
 
 	if err := s.passwordHasher.Compare(user.PasswordHash, input.Password); err != nil {
+		s.auditRecorder.Record(ctx, entity.NewAuditEvent(user.ID, entity.AuditLoginFailed, LoginPassword))
+		if locked := s.lockout.fail(ctx, input.Email, input.IP); locked > 0 {
+			s.auditRecorder.Record(ctx, entity.NewAuditEvent(user.ID, entity.AuditLoginLocked, locked.String()))
+			return nil, nil, &LockedError{RetryAfter: locked}
+		}
 		return nil, nil, domain.ErrInvalidCredentials
 	}
+	s.lockout.reset(ctx, input.Email, input.IP)
 
-	device := entity.NewDevice(user.ID, input.DeviceID, input.Platform, input.DeviceName)
+	if err := s.ensurePasswordLoginAllowed(ctx, user.Email, user.ID); err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := s.startSession(ctx, user, LoginPassword, input.DeviceID, platform, input.DeviceName, input.IP)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tokens, user, nil
+}
+
+// Login methods, recorded as the target of login audit events. SSO logins
+// record "sso:" and the organization, social logins the provider name.
+const (
+	LoginPassword = "password"
+	LoginSSO      = "sso:"
+)
+
+// startSession registers the device and issues a fresh token pair for it,
+// revoking any tokens the device held before. method is how the user
+// authenticated.
+func (s *Service) startSession(ctx context.Context, user *entity.User, method, deviceID, platform, deviceName, ip string) (*TokenPair, error) {
+	device := entity.NewDevice(user.ID, deviceID, platform, deviceName)
 	if err := s.deviceRepo.Upsert(ctx, device); err != nil {
-		return nil, nil, fmt.Errorf("upserting device: %w", err)
+		return nil, fmt.Errorf("upserting device: %w", err)
 	}
 
-	device, err = s.deviceRepo.GetByUserAndDeviceID(ctx, user.ID, input.DeviceID)
+	device, err := s.deviceRepo.GetByUserAndDeviceID(ctx, user.ID, deviceID)
 	if err != nil {
-		return nil, nil, fmt.Errorf("getting device: %w", err)
+		return nil, fmt.Errorf("getting device: %w", err)
 	}
 
 	if err := s.refreshTokenRepo.RevokeByDeviceID(ctx, device.ID); err != nil {
-		return nil, nil, fmt.Errorf("revoking old tokens: %w", err)
+		return nil, fmt.Errorf("revoking old tokens: %w", err)
 	}
 
-	tokens, err := s.generateTokenPair(ctx, user.ID, device.ID)
+	tokens, err := s.generateTokenPair(ctx, user.ID, device.ID, user.TokenVersion, platform, s.sessionPolicy(platform))
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	return tokens, user, nil
+	s.recordAccess(ctx, user.ID, device.ID, entity.AuthEventLogin, ip)
+	s.recordDevice(ctx, user.ID, device.ID, entity.AuditLogin, method)
+	return tokens, nil
 }
 
-func (s *Service) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+// recordAccess stores the client address and its approximate location on the
+// device and in the auth event history used for anomaly detection. It is best
+// effort: a failed lookup or write must not fail a login that already issued
+// tokens, so errors only leave the location empty or the history incomplete.
+func (s *Service) recordAccess(ctx context.Context, userID, deviceID uuid.UUID, kind, ip string) {
+	if ip == "" {
+		return
+	}
+
+	access := entity.DeviceAccess{IP: ip, At: s.clock.Now()}
+	var coordinates *valueobject.Location
+	if addr, err := netip.ParseAddr(ip); s.geoResolver != nil && err == nil && addr.IsGlobalUnicast() && !addr.IsPrivate() {
+		if loc, err := s.geoResolver.Lookup(ctx, ip); err == nil {
+			access.City = loc.City
+			access.Country = loc.Country
+			coordinates = loc.Coordinates
+		}
+	}
+
+	_ = s.deviceRepo.RecordAccess(ctx, deviceID, access)
+	_ = s.authEventRepo.Create(ctx, entity.NewAuthEvent(userID, deviceID, kind, access, coordinates))
+}
+
+// recordDevice records an audit event of the given device, which for logins
+// and refreshes is not yet the one of the request's access token.
+func (s *Service) recordDevice(ctx context.Context, userID, deviceID uuid.UUID, action, target string) {
+	event := entity.NewAuditEvent(userID, action, target)
+	event.DeviceID = &deviceID
+	s.auditRecorder.Record(ctx, event)
+}
+
+func (s *Service) Refresh(ctx context.Context, refreshToken, ip string) (*TokenPair, error) {
 	rt, err := s.refreshTokenRepo.GetByToken(ctx, refreshToken)
 	if err != nil {
 		return nil, domain.ErrTokenInvalid
@@ -124,7 +263,7 @@ func (s *Service) Refresh(ctx context.Context, refreshToken string) (*TokenPair,
 		return nil, domain.ErrTokenRevoked
 	}
 
-	if rt.IsExpired() {
+	if rt.IsExpired(s.clock.Now()) {
 		return nil, domain.ErrTokenExpired
 	}
 
@@ -132,55 +271,123 @@ func (s *Service) Refresh(ctx context.Context, refreshToken string) (*TokenPair,
 		return nil, fmt.Errorf("revoking old token: %w", err)
 	}
 
-	tokens, err := s.generateTokenPair(ctx, rt.UserID, rt.DeviceID)
+	tokenVersion, err := s.userRepo.GetTokenVersion(ctx, rt.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("getting token version: %w", err)
+	}
+
+	tokens, err := s.generateTokenPair(ctx, rt.UserID, rt.DeviceID, tokenVersion, rt.Platform, s.issuedPolicy(rt))
 	if err != nil {
 		return nil, err
 	}
 
+	s.recordAccess(ctx, rt.UserID, rt.DeviceID, entity.AuthEventRefresh, ip)
+	s.recordDevice(ctx, rt.UserID, rt.DeviceID, entity.AuditTokenRefresh, "")
 	return tokens, nil
 }
 
+// Sessions lists the user's devices with where and when each last
+// authenticated.
+func (s *Service) Sessions(ctx context.Context, userID uuid.UUID) ([]entity.Device, error) {
+	devices, err := s.deviceRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("listing devices: %w", err)
+	}
+	return devices, nil
+}
+
+// Logout ends every session of the user. Their access tokens stop being
+// accepted too, within the token version cache TTL.
 func (s *Service) Logout(ctx context.Context, userID uuid.UUID) error {
+	if err := s.userRepo.BumpTokenVersion(ctx, userID); err != nil {
+		return fmt.Errorf("invalidating access tokens: %w", err)
+	}
 	if err := s.refreshTokenRepo.RevokeByUserID(ctx, userID); err != nil {
 		return fmt.Errorf("revoking tokens: %w", err)
 	}
+	s.auditRecorder.Record(ctx, entity.NewAuditEvent(userID, entity.AuditLogout, ""))
 	return nil
 }
 
+// LogoutDevice ends the sessions of one of the user's devices. Token
+// versions are per user, so the access tokens of the other devices stop
+// being accepted as well; those devices get new ones with their refresh
+// tokens, which stay valid.
 func (s *Service) LogoutDevice(ctx context.Context, userID uuid.UUID, deviceID string) error {
 	device, err := s.deviceRepo.GetByUserAndDeviceID(ctx, userID, deviceID)
 	if err != nil {
 		return fmt.Errorf("getting device: %w", err)
 	}
 
+	if err := s.userRepo.BumpTokenVersion(ctx, userID); err != nil {
+		return fmt.Errorf("invalidating access tokens: %w", err)
+	}
+
 	if err := s.refreshTokenRepo.RevokeByDeviceID(ctx, device.ID); err != nil {
 		return fmt.Errorf("revoking tokens: %w", err)
 	}
+	s.recordDevice(ctx, userID, device.ID, entity.AuditLogout, "")
 	return nil
 }
 
-func (s *Service) generateTokenPair(ctx context.Context, userID, deviceID uuid.UUID) (*TokenPair, error) {
-	accessToken, expiresAt, err := s.jwtSvc.GenerateAccessToken(userID)
+// RevokeOtherSessions ends every session of the user except the current one
+// and returns how many were ended. It fails with ErrTokenInvalid when the
+// current session has itself been revoked or expired, so a token from an
+// ended session cannot be used to lock out the sessions that remain.
+func (s *Service) RevokeOtherSessions(ctx context.Context, userID, sessionID uuid.UUID) (int, error) {
+	if sessionID == uuid.Nil {
+		return 0, domain.ErrTokenInvalid
+	}
+
+	revoked, err := s.refreshTokenRepo.RevokeOthers(ctx, userID, sessionID)
 	if err != nil {
-		return nil, fmt.Errorf("generating access token: %w", err)
+		return 0, fmt.Errorf("revoking other sessions: %w", err)
 	}
+	s.auditRecorder.Record(ctx, entity.NewAuditEvent(userID, entity.AuditSessionsRevoked, strconv.Itoa(revoked)))
+	return revoked, nil
+}
 
+// generateTokenPair issues tokens under the given session policy and, if the
+// policy limits sessions, ends the user's oldest sessions on the platform
+// beyond it. The access token carries tokenVersion, the user's current token
+// version.
+func (s *Service) generateTokenPair(ctx context.Context, userID, deviceID uuid.UUID, tokenVersion int, platform string, policy entity.SessionPolicy) (*TokenPair, error) {
 	refreshTokenStr, err := s.jwtSvc.GenerateRefreshToken()
 	if err != nil {
 		return nil, fmt.Errorf("generating refresh token: %w", err)
 	}
 
-	rt := entity.NewRefreshToken(
+	now := s.clock.Now()
+	rt := entity.NewRefreshTokenAt(
 		userID,
 		deviceID,
 		refreshTokenStr,
-		time.Now().UTC().Add(s.refreshTokenTTL),
+		now.Add(policy.RefreshTTL),
+		now,
 	)
+	rt.Platform = platform
+	rt.Policy = policy
+
+	accessToken, expiresAt, err := s.jwtSvc.GenerateAccessToken(auth.AccessToken{
+		UserID:       userID,
+		SessionID:    rt.ID,
+		DeviceID:     deviceID,
+		TokenVersion: tokenVersion,
+	}, policy.AccessTTL)
+	if err != nil {
+		return nil, fmt.Errorf("generating access token: %w", err)
+	}
 
 	if err := s.refreshTokenRepo.Create(ctx, rt); err != nil {
 		return nil, fmt.Errorf("storing refresh token: %w", err)
 	}
 
+	if policy.MaxSessions > 0 && platform != "" {
+		if err := s.refreshTokenRepo.RevokeExcess(ctx, userID, platform, policy.MaxSessions); err != nil {
+			return nil, err
+		}
+	}
+
 	return &TokenPair{
 		AccessToken:  accessToken,
 		RefreshToken: refreshTokenStr,
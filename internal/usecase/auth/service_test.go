@@ -13,8 +13,11 @@ import (
 
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
 	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/auth"
 	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/clock"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/audit"
 	authUC "github.com/marcos-nsantos/field-notes-backend/internal/usecase/auth"
 )
 
@@ -26,13 +29,15 @@ func TestService_Register(t *testing.T) {
 		userRepo := mocks.NewMockUserRepository(ctrl)
 		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
 		refreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+		orgRepo := mocks.NewMockOrganizationRepository(ctrl)
 		jwtSvc := auth.NewJWTService("test-secret", 15*time.Minute)
 		passwordHasher := auth.NewPasswordHasher(4)
 
-		svc := authUC.NewService(userRepo, deviceRepo, refreshTokenRepo, jwtSvc, passwordHasher, 24*time.Hour)
+		svc := authUC.NewService(userRepo, deviceRepo, refreshTokenRepo, orgRepo, jwtSvc, passwordHasher, nil, nil, nil, nil, nil, 24*time.Hour, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
 
 		ctx := context.Background()
 		userRepo.EXPECT().ExistsByEmail(ctx, "test@example.com").Return(false, nil)
+		orgRepo.EXPECT().GetByDomain(ctx, "example.com").Return(nil, domain.ErrOrgNotFound)
 		userRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
 
 		user, err := svc.Register(ctx, authUC.RegisterInput{
@@ -52,7 +57,7 @@ func TestService_Register(t *testing.T) {
 		defer ctrl.Finish()
 
 		userRepo := mocks.NewMockUserRepository(ctrl)
-		svc := authUC.NewService(userRepo, nil, nil, nil, nil, 0)
+		svc := authUC.NewService(userRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
 
 		ctx := context.Background()
 		userRepo.EXPECT().ExistsByEmail(ctx, "existing@example.com").Return(true, nil)
@@ -66,6 +71,30 @@ func TestService_Register(t *testing.T) {
 		assert.Nil(t, user)
 		assert.ErrorIs(t, err, domain.ErrUserAlreadyExists)
 	})
+
+	t.Run("email registered concurrently after the check", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		orgRepo := mocks.NewMockOrganizationRepository(ctrl)
+		passwordHasher := auth.NewPasswordHasher(4)
+		svc := authUC.NewService(userRepo, nil, nil, orgRepo, nil, passwordHasher, nil, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
+
+		ctx := context.Background()
+		userRepo.EXPECT().ExistsByEmail(ctx, "race@example.com").Return(false, nil)
+		orgRepo.EXPECT().GetByDomain(ctx, "example.com").Return(nil, domain.ErrOrgNotFound)
+		userRepo.EXPECT().Create(ctx, gomock.Any()).Return(domain.ErrUserAlreadyExists)
+
+		user, err := svc.Register(ctx, authUC.RegisterInput{
+			Email:    "race@example.com",
+			Password: "password123",
+			Name:     "Test User",
+		})
+
+		assert.Nil(t, user)
+		assert.ErrorIs(t, err, domain.ErrUserAlreadyExists)
+	})
 }
 
 func TestService_Login(t *testing.T) {
@@ -76,10 +105,11 @@ func TestService_Login(t *testing.T) {
 		userRepo := mocks.NewMockUserRepository(ctrl)
 		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
 		refreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+		orgRepo := mocks.NewMockOrganizationRepository(ctrl)
 		jwtSvc := auth.NewJWTService("test-secret", 15*time.Minute)
 		passwordHasher := auth.NewPasswordHasher(4)
 
-		svc := authUC.NewService(userRepo, deviceRepo, refreshTokenRepo, jwtSvc, passwordHasher, 24*time.Hour)
+		svc := authUC.NewService(userRepo, deviceRepo, refreshTokenRepo, orgRepo, jwtSvc, passwordHasher, nil, nil, nil, nil, nil, 24*time.Hour, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
 
 		ctx := context.Background()
 		hashedPassword, _ := passwordHasher.Hash("password123")
@@ -100,6 +130,8 @@ func TestService_Login(t *testing.T) {
 		}
 
 		userRepo.EXPECT().GetByEmail(ctx, "test@example.com").Return(user, nil)
+		orgRepo.EXPECT().GetByDomain(ctx, "example.com").Return(nil, domain.ErrOrgNotFound)
+		orgRepo.EXPECT().ListByUserID(ctx, userID).Return(nil, nil)
 		deviceRepo.EXPECT().Upsert(ctx, gomock.Any()).Return(nil)
 		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(device, nil)
 		refreshTokenRepo.EXPECT().RevokeByDeviceID(ctx, deviceID).Return(nil)
@@ -119,12 +151,116 @@ func TestService_Login(t *testing.T) {
 		assert.Equal(t, userID, returnedUser.ID)
 	})
 
+	t.Run("records access with geoip location", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		refreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+		orgRepo := mocks.NewMockOrganizationRepository(ctrl)
+		geoResolver := mocks.NewMockResolver(ctrl)
+		authEventRepo := mocks.NewMockAuthEventRepository(ctrl)
+		passwordHasher := auth.NewPasswordHasher(4)
+
+		svc := authUC.NewService(userRepo, deviceRepo, refreshTokenRepo, orgRepo, auth.NewJWTService("test-secret", 15*time.Minute), passwordHasher, nil, geoResolver, authEventRepo, nil, nil, 24*time.Hour, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
+
+		ctx := context.Background()
+		hashedPassword, _ := passwordHasher.Hash("password123")
+		user := &entity.User{ID: uuid.New(), Email: "test@example.com", PasswordHash: hashedPassword}
+		device := &entity.Device{ID: uuid.New(), UserID: user.ID, DeviceID: "device-123"}
+
+		userRepo.EXPECT().GetByEmail(ctx, "test@example.com").Return(user, nil)
+		orgRepo.EXPECT().GetByDomain(ctx, "example.com").Return(nil, domain.ErrOrgNotFound)
+		orgRepo.EXPECT().ListByUserID(ctx, user.ID).Return(nil, nil)
+		deviceRepo.EXPECT().Upsert(ctx, gomock.Any()).Return(nil)
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, user.ID, "device-123").Return(device, nil)
+		refreshTokenRepo.EXPECT().RevokeByDeviceID(ctx, device.ID).Return(nil)
+		refreshTokenRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+		geoResolver.EXPECT().Lookup(ctx, "203.0.113.7").Return(&entity.GeoLocation{
+			City:        "Lisbon",
+			Country:     "PT",
+			Coordinates: valueobject.NewLocation(38.72, -9.14, nil, nil),
+		}, nil)
+		deviceRepo.EXPECT().RecordAccess(ctx, device.ID, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ uuid.UUID, access entity.DeviceAccess) error {
+				assert.Equal(t, "203.0.113.7", access.IP)
+				assert.Equal(t, "Lisbon", access.City)
+				assert.Equal(t, "PT", access.Country)
+				assert.False(t, access.At.IsZero())
+				return nil
+			})
+		authEventRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, event *entity.AuthEvent) error {
+				assert.Equal(t, entity.AuthEventLogin, event.Kind)
+				assert.Equal(t, user.ID, event.UserID)
+				require.NotNil(t, event.Location)
+				assert.InDelta(t, 38.72, event.Location.Latitude, 0.001)
+				return nil
+			})
+
+		_, _, err := svc.Login(ctx, authUC.LoginInput{
+			Email:    "test@example.com",
+			Password: "password123",
+			DeviceID: "device-123",
+			Platform: "ios",
+			IP:       "203.0.113.7",
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("private address is recorded without lookup", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		refreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+		orgRepo := mocks.NewMockOrganizationRepository(ctrl)
+		geoResolver := mocks.NewMockResolver(ctrl)
+		authEventRepo := mocks.NewMockAuthEventRepository(ctrl)
+		passwordHasher := auth.NewPasswordHasher(4)
+
+		svc := authUC.NewService(userRepo, deviceRepo, refreshTokenRepo, orgRepo, auth.NewJWTService("test-secret", 15*time.Minute), passwordHasher, nil, geoResolver, authEventRepo, nil, nil, 24*time.Hour, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
+
+		ctx := context.Background()
+		hashedPassword, _ := passwordHasher.Hash("password123")
+		user := &entity.User{ID: uuid.New(), Email: "test@example.com", PasswordHash: hashedPassword}
+		device := &entity.Device{ID: uuid.New(), UserID: user.ID, DeviceID: "device-123"}
+
+		userRepo.EXPECT().GetByEmail(ctx, "test@example.com").Return(user, nil)
+		orgRepo.EXPECT().GetByDomain(ctx, "example.com").Return(nil, domain.ErrOrgNotFound)
+		orgRepo.EXPECT().ListByUserID(ctx, user.ID).Return(nil, nil)
+		deviceRepo.EXPECT().Upsert(ctx, gomock.Any()).Return(nil)
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, user.ID, "device-123").Return(device, nil)
+		refreshTokenRepo.EXPECT().RevokeByDeviceID(ctx, device.ID).Return(nil)
+		refreshTokenRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+		deviceRepo.EXPECT().RecordAccess(ctx, device.ID, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ uuid.UUID, access entity.DeviceAccess) error {
+				assert.Equal(t, "192.168.1.20", access.IP)
+				assert.Empty(t, access.City)
+				return nil
+			})
+		authEventRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+		_, _, err := svc.Login(ctx, authUC.LoginInput{
+			Email:    "test@example.com",
+			Password: "password123",
+			DeviceID: "device-123",
+			Platform: "ios",
+			IP:       "192.168.1.20",
+		})
+
+		require.NoError(t, err)
+	})
+
 	t.Run("invalid email", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		userRepo := mocks.NewMockUserRepository(ctrl)
-		svc := authUC.NewService(userRepo, nil, nil, nil, nil, 0)
+		svc := authUC.NewService(userRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
 
 		ctx := context.Background()
 		userRepo.EXPECT().GetByEmail(ctx, "notfound@example.com").Return(nil, domain.ErrUserNotFound)
@@ -132,6 +268,7 @@ func TestService_Login(t *testing.T) {
 		tokens, user, err := svc.Login(ctx, authUC.LoginInput{
 			Email:    "notfound@example.com",
 			Password: "password123",
+			Platform: "ios",
 		})
 
 		assert.Nil(t, tokens)
@@ -144,10 +281,11 @@ func TestService_Login(t *testing.T) {
 		defer ctrl.Finish()
 
 		userRepo := mocks.NewMockUserRepository(ctrl)
+		auditRepo := mocks.NewMockAuditEventRepository(ctrl)
 		passwordHasher := auth.NewPasswordHasher(4)
-		svc := authUC.NewService(userRepo, nil, nil, nil, passwordHasher, 0)
+		svc := authUC.NewService(userRepo, nil, nil, nil, nil, passwordHasher, nil, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, audit.NewRecorder(auditRepo), nil, nil)
 
-		ctx := context.Background()
+		ctx := audit.WithClient(context.Background(), audit.Client{IP: "203.0.113.7", UserAgent: "FieldNotes/2.4.0"})
 		hashedPassword, _ := passwordHasher.Hash("correctpassword")
 		user := &entity.User{
 			ID:           uuid.New(),
@@ -156,16 +294,161 @@ func TestService_Login(t *testing.T) {
 		}
 
 		userRepo.EXPECT().GetByEmail(ctx, "test@example.com").Return(user, nil)
+		auditRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, event *entity.AuditEvent) error {
+			assert.Equal(t, user.ID, event.UserID)
+			assert.Equal(t, entity.AuditLoginFailed, event.Action)
+			assert.Equal(t, authUC.LoginPassword, event.Target)
+			assert.Equal(t, "203.0.113.7", event.IP)
+			assert.Nil(t, event.DeviceID)
+			return nil
+		})
 
 		tokens, returnedUser, err := svc.Login(ctx, authUC.LoginInput{
 			Email:    "test@example.com",
 			Password: "wrongpassword",
+			Platform: "ios",
 		})
 
 		assert.Nil(t, tokens)
 		assert.Nil(t, returnedUser)
 		assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
 	})
+
+	t.Run("sso required for enforcing organization member", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		orgRepo := mocks.NewMockOrganizationRepository(ctrl)
+		passwordHasher := auth.NewPasswordHasher(4)
+		svc := authUC.NewService(userRepo, nil, nil, orgRepo, nil, passwordHasher, nil, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
+
+		ctx := context.Background()
+		hashedPassword, _ := passwordHasher.Hash("password123")
+		user := &entity.User{
+			ID:           uuid.New(),
+			Email:        "field@acme.org",
+			PasswordHash: hashedPassword,
+		}
+
+		userRepo.EXPECT().GetByEmail(ctx, "field@acme.org").Return(user, nil)
+		orgRepo.EXPECT().GetByDomain(ctx, "acme.org").Return(&entity.Organization{
+			Slug:        "acme",
+			Domain:      "acme.org",
+			SSOEnforced: true,
+		}, nil)
+
+		tokens, returnedUser, err := svc.Login(ctx, authUC.LoginInput{
+			Email:    "field@acme.org",
+			Password: "password123",
+			Platform: "ios",
+		})
+
+		assert.Nil(t, tokens)
+		assert.Nil(t, returnedUser)
+		assert.ErrorIs(t, err, domain.ErrSSORequired)
+	})
+
+	t.Run("normalizes platform", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		refreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+		orgRepo := mocks.NewMockOrganizationRepository(ctrl)
+		jwtSvc := auth.NewJWTService("test-secret", 15*time.Minute)
+		passwordHasher := auth.NewPasswordHasher(4)
+
+		svc := authUC.NewService(userRepo, deviceRepo, refreshTokenRepo, orgRepo, jwtSvc, passwordHasher, nil, nil, nil, nil, nil, 24*time.Hour, authUC.PasswordResetConfig{}, authUC.SessionConfig{Platforms: []string{"ios", "cli"}}, nil, nil, nil, nil, nil)
+
+		ctx := context.Background()
+		hashedPassword, _ := passwordHasher.Hash("password123")
+		user := &entity.User{ID: uuid.New(), Email: "test@example.com", PasswordHash: hashedPassword}
+		device := &entity.Device{ID: uuid.New(), UserID: user.ID, DeviceID: "laptop"}
+
+		userRepo.EXPECT().GetByEmail(ctx, "test@example.com").Return(user, nil)
+		orgRepo.EXPECT().GetByDomain(ctx, "example.com").Return(nil, domain.ErrOrgNotFound)
+		orgRepo.EXPECT().ListByUserID(ctx, user.ID).Return(nil, nil)
+		deviceRepo.EXPECT().Upsert(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, d *entity.Device) error {
+			assert.Equal(t, entity.PlatformCLI, d.Platform)
+			return nil
+		})
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, user.ID, "laptop").Return(device, nil)
+		refreshTokenRepo.EXPECT().RevokeByDeviceID(ctx, device.ID).Return(nil)
+		refreshTokenRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+		_, _, err := svc.Login(ctx, authUC.LoginInput{
+			Email:    "test@example.com",
+			Password: "password123",
+			DeviceID: "laptop",
+			Platform: " CLI ",
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("applies the platform session policy", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		refreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+		orgRepo := mocks.NewMockOrganizationRepository(ctrl)
+		jwtSvc := auth.NewJWTService("test-secret", 15*time.Minute)
+		passwordHasher := auth.NewPasswordHasher(4)
+		session := authUC.SessionConfig{Policies: map[string]entity.SessionPolicy{
+			entity.PlatformWeb: {AccessTTL: 5 * time.Minute, MaxSessions: 2},
+		}}
+
+		svc := authUC.NewService(userRepo, deviceRepo, refreshTokenRepo, orgRepo, jwtSvc, passwordHasher, nil, nil, nil, nil, nil, 24*time.Hour, authUC.PasswordResetConfig{}, session, nil, nil, nil, nil, nil)
+
+		ctx := context.Background()
+		hashedPassword, _ := passwordHasher.Hash("password123")
+		user := &entity.User{ID: uuid.New(), Email: "test@example.com", PasswordHash: hashedPassword}
+		device := &entity.Device{ID: uuid.New(), UserID: user.ID, DeviceID: "browser"}
+
+		userRepo.EXPECT().GetByEmail(ctx, "test@example.com").Return(user, nil)
+		orgRepo.EXPECT().GetByDomain(ctx, "example.com").Return(nil, domain.ErrOrgNotFound)
+		orgRepo.EXPECT().ListByUserID(ctx, user.ID).Return(nil, nil)
+		deviceRepo.EXPECT().Upsert(ctx, gomock.Any()).Return(nil)
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, user.ID, "browser").Return(device, nil)
+		refreshTokenRepo.EXPECT().RevokeByDeviceID(ctx, device.ID).Return(nil)
+		refreshTokenRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, token *entity.RefreshToken) error {
+			assert.Equal(t, entity.PlatformWeb, token.Platform)
+			assert.Equal(t, entity.SessionPolicy{AccessTTL: 5 * time.Minute, RefreshTTL: 24 * time.Hour, MaxSessions: 2}, token.Policy)
+			return nil
+		})
+		refreshTokenRepo.EXPECT().RevokeExcess(ctx, user.ID, entity.PlatformWeb, 2).Return(nil)
+
+		tokens, _, err := svc.Login(ctx, authUC.LoginInput{
+			Email:    "test@example.com",
+			Password: "password123",
+			DeviceID: "browser",
+			Platform: "web",
+		})
+
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(5*time.Minute), tokens.ExpiresAt, time.Minute)
+	})
+
+	t.Run("rejects platform not enabled", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc := authUC.NewService(mocks.NewMockUserRepository(ctrl), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{Platforms: []string{"ios", "android"}}, nil, nil, nil, nil, nil)
+
+		for _, platform := range []string{"web", "windows", ""} {
+			_, _, err := svc.Login(context.Background(), authUC.LoginInput{
+				Email:    "test@example.com",
+				Password: "password123",
+				Platform: platform,
+			})
+
+			assert.ErrorIs(t, err, domain.ErrUnsupportedPlatform, platform)
+		}
+	})
 }
 
 func TestService_Refresh(t *testing.T) {
@@ -173,10 +456,11 @@ func TestService_Refresh(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
+		userRepo := mocks.NewMockUserRepository(ctrl)
 		refreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
 		jwtSvc := auth.NewJWTService("test-secret", 15*time.Minute)
 
-		svc := authUC.NewService(nil, nil, refreshTokenRepo, jwtSvc, nil, 24*time.Hour)
+		svc := authUC.NewService(userRepo, nil, refreshTokenRepo, nil, jwtSvc, nil, nil, nil, nil, nil, nil, 24*time.Hour, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
 
 		ctx := context.Background()
 		userID := uuid.New()
@@ -194,13 +478,108 @@ func TestService_Refresh(t *testing.T) {
 
 		refreshTokenRepo.EXPECT().GetByToken(ctx, "valid-refresh-token").Return(rt, nil)
 		refreshTokenRepo.EXPECT().Revoke(ctx, tokenID).Return(nil)
-		refreshTokenRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+		userRepo.EXPECT().GetTokenVersion(ctx, userID).Return(3, nil)
+		var created *entity.RefreshToken
+		refreshTokenRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, token *entity.RefreshToken) error {
+			created = token
+			return nil
+		})
 
-		tokens, err := svc.Refresh(ctx, "valid-refresh-token")
+		tokens, err := svc.Refresh(ctx, "valid-refresh-token", "")
 
 		require.NoError(t, err)
 		assert.NotEmpty(t, tokens.AccessToken)
 		assert.NotEmpty(t, tokens.RefreshToken)
+
+		access, err := jwtSvc.ValidateAccessToken(tokens.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, userID, access.UserID)
+		assert.Equal(t, created.ID, access.SessionID)
+		assert.Equal(t, deviceID, access.DeviceID)
+		assert.Equal(t, 3, access.TokenVersion)
+	})
+
+	t.Run("keeps the policy the token was issued under", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		refreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+		jwtSvc := auth.NewJWTService("test-secret", 15*time.Minute)
+		session := authUC.SessionConfig{Policies: map[string]entity.SessionPolicy{
+			entity.PlatformWeb: {AccessTTL: 5 * time.Minute, RefreshTTL: 12 * time.Hour, MaxSessions: 3},
+		}}
+
+		svc := authUC.NewService(userRepo, nil, refreshTokenRepo, nil, jwtSvc, nil, nil, nil, nil, nil, nil, 24*time.Hour, authUC.PasswordResetConfig{}, session, nil, nil, nil, nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		issued := entity.SessionPolicy{AccessTTL: time.Minute, RefreshTTL: 2 * time.Hour, MaxSessions: 1}
+		rt := &entity.RefreshToken{
+			ID:        uuid.New(),
+			UserID:    userID,
+			DeviceID:  uuid.New(),
+			Token:     "web-token",
+			ExpiresAt: time.Now().Add(time.Hour),
+			Platform:  entity.PlatformWeb,
+			Policy:    issued,
+		}
+
+		refreshTokenRepo.EXPECT().GetByToken(ctx, "web-token").Return(rt, nil)
+		refreshTokenRepo.EXPECT().Revoke(ctx, rt.ID).Return(nil)
+		userRepo.EXPECT().GetTokenVersion(ctx, userID).Return(0, nil)
+		refreshTokenRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, token *entity.RefreshToken) error {
+			assert.Equal(t, issued, token.Policy)
+			assert.WithinDuration(t, time.Now().Add(2*time.Hour), token.ExpiresAt, time.Minute)
+			return nil
+		})
+		refreshTokenRepo.EXPECT().RevokeExcess(ctx, userID, entity.PlatformWeb, 1).Return(nil)
+
+		tokens, err := svc.Refresh(ctx, "web-token", "")
+
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), tokens.ExpiresAt, 10*time.Second)
+	})
+
+	t.Run("uses the platform policy for tokens issued without one", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		refreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+		jwtSvc := auth.NewJWTService("test-secret", 15*time.Minute)
+		session := authUC.SessionConfig{Policies: map[string]entity.SessionPolicy{
+			entity.PlatformWeb: {AccessTTL: 5 * time.Minute, RefreshTTL: 12 * time.Hour, MaxSessions: 3},
+		}}
+
+		svc := authUC.NewService(userRepo, nil, refreshTokenRepo, nil, jwtSvc, nil, nil, nil, nil, nil, nil, 24*time.Hour, authUC.PasswordResetConfig{}, session, nil, nil, nil, nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		rt := &entity.RefreshToken{
+			ID:        uuid.New(),
+			UserID:    userID,
+			DeviceID:  uuid.New(),
+			Token:     "web-token",
+			ExpiresAt: time.Now().Add(time.Hour),
+			Platform:  entity.PlatformWeb,
+		}
+
+		refreshTokenRepo.EXPECT().GetByToken(ctx, "web-token").Return(rt, nil)
+		refreshTokenRepo.EXPECT().Revoke(ctx, rt.ID).Return(nil)
+		userRepo.EXPECT().GetTokenVersion(ctx, userID).Return(0, nil)
+		refreshTokenRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, token *entity.RefreshToken) error {
+			assert.Equal(t, entity.PlatformWeb, token.Platform)
+			assert.Equal(t, 12*time.Hour, token.Policy.RefreshTTL)
+			assert.WithinDuration(t, time.Now().Add(12*time.Hour), token.ExpiresAt, time.Minute)
+			return nil
+		})
+		refreshTokenRepo.EXPECT().RevokeExcess(ctx, userID, entity.PlatformWeb, 3).Return(nil)
+
+		tokens, err := svc.Refresh(ctx, "web-token", "")
+
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(5*time.Minute), tokens.ExpiresAt, time.Minute)
 	})
 
 	t.Run("expired token", func(t *testing.T) {
@@ -208,7 +587,7 @@ func TestService_Refresh(t *testing.T) {
 		defer ctrl.Finish()
 
 		refreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
-		svc := authUC.NewService(nil, nil, refreshTokenRepo, nil, nil, 0)
+		svc := authUC.NewService(nil, nil, refreshTokenRepo, nil, nil, nil, nil, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
 
 		ctx := context.Background()
 		rt := &entity.RefreshToken{
@@ -218,7 +597,26 @@ func TestService_Refresh(t *testing.T) {
 
 		refreshTokenRepo.EXPECT().GetByToken(ctx, "expired-token").Return(rt, nil)
 
-		tokens, err := svc.Refresh(ctx, "expired-token")
+		tokens, err := svc.Refresh(ctx, "expired-token", "")
+
+		assert.Nil(t, tokens)
+		assert.ErrorIs(t, err, domain.ErrTokenExpired)
+	})
+
+	t.Run("expires once the clock passes its expiry", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		refreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+		clk := clock.NewFake(time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC))
+		svc := authUC.NewService(nil, nil, refreshTokenRepo, nil, nil, nil, nil, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, clk)
+
+		ctx := context.Background()
+		rt := entity.NewRefreshTokenAt(uuid.New(), uuid.New(), "token", clk.Now().Add(time.Hour), clk.Now())
+		refreshTokenRepo.EXPECT().GetByToken(ctx, "token").Return(rt, nil)
+
+		clk.Advance(time.Hour + time.Second)
+		tokens, err := svc.Refresh(ctx, "token", "")
 
 		assert.Nil(t, tokens)
 		assert.ErrorIs(t, err, domain.ErrTokenExpired)
@@ -229,7 +627,7 @@ func TestService_Refresh(t *testing.T) {
 		defer ctrl.Finish()
 
 		refreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
-		svc := authUC.NewService(nil, nil, refreshTokenRepo, nil, nil, 0)
+		svc := authUC.NewService(nil, nil, refreshTokenRepo, nil, nil, nil, nil, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
 
 		ctx := context.Background()
 		revokedAt := time.Now()
@@ -241,7 +639,7 @@ func TestService_Refresh(t *testing.T) {
 
 		refreshTokenRepo.EXPECT().GetByToken(ctx, "revoked-token").Return(rt, nil)
 
-		tokens, err := svc.Refresh(ctx, "revoked-token")
+		tokens, err := svc.Refresh(ctx, "revoked-token", "")
 
 		assert.Nil(t, tokens)
 		assert.ErrorIs(t, err, domain.ErrTokenRevoked)
@@ -252,12 +650,12 @@ func TestService_Refresh(t *testing.T) {
 		defer ctrl.Finish()
 
 		refreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
-		svc := authUC.NewService(nil, nil, refreshTokenRepo, nil, nil, 0)
+		svc := authUC.NewService(nil, nil, refreshTokenRepo, nil, nil, nil, nil, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
 
 		ctx := context.Background()
 		refreshTokenRepo.EXPECT().GetByToken(ctx, "invalid-token").Return(nil, errors.New("not found"))
 
-		tokens, err := svc.Refresh(ctx, "invalid-token")
+		tokens, err := svc.Refresh(ctx, "invalid-token", "")
 
 		assert.Nil(t, tokens)
 		assert.ErrorIs(t, err, domain.ErrTokenInvalid)
@@ -269,15 +667,83 @@ func TestService_Logout(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
+		userRepo := mocks.NewMockUserRepository(ctrl)
 		refreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
-		svc := authUC.NewService(nil, nil, refreshTokenRepo, nil, nil, 0)
+		svc := authUC.NewService(userRepo, nil, refreshTokenRepo, nil, nil, nil, nil, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
 
 		ctx := context.Background()
 		userID := uuid.New()
-		refreshTokenRepo.EXPECT().RevokeByUserID(ctx, userID).Return(nil)
+		gomock.InOrder(
+			userRepo.EXPECT().BumpTokenVersion(ctx, userID).Return(nil),
+			refreshTokenRepo.EXPECT().RevokeByUserID(ctx, userID).Return(nil),
+		)
 
 		err := svc.Logout(ctx, userID)
 
 		require.NoError(t, err)
 	})
+
+	t.Run("keeps the sessions when access tokens cannot be invalidated", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		refreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+		svc := authUC.NewService(userRepo, nil, refreshTokenRepo, nil, nil, nil, nil, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		userRepo.EXPECT().BumpTokenVersion(ctx, userID).Return(errors.New("db down"))
+
+		err := svc.Logout(ctx, userID)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestService_RevokeOtherSessions(t *testing.T) {
+	t.Run("revokes all but the current session", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		refreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+		svc := authUC.NewService(nil, nil, refreshTokenRepo, nil, nil, nil, nil, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		sessionID := uuid.New()
+		refreshTokenRepo.EXPECT().RevokeOthers(ctx, userID, sessionID).Return(3, nil)
+
+		revoked, err := svc.RevokeOtherSessions(ctx, userID, sessionID)
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, revoked)
+	})
+
+	t.Run("token without session", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		refreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+		svc := authUC.NewService(nil, nil, refreshTokenRepo, nil, nil, nil, nil, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
+
+		_, err := svc.RevokeOtherSessions(context.Background(), uuid.New(), uuid.Nil)
+
+		assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+	})
+
+	t.Run("current session no longer active", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		refreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+		svc := authUC.NewService(nil, nil, refreshTokenRepo, nil, nil, nil, nil, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
+
+		ctx := context.Background()
+		refreshTokenRepo.EXPECT().RevokeOthers(ctx, gomock.Any(), gomock.Any()).Return(0, domain.ErrTokenInvalid)
+
+		_, err := svc.RevokeOtherSessions(ctx, uuid.New(), uuid.New())
+
+		assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+	})
 }
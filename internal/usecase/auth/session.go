@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+// SessionConfig controls which device platforms may log in and the session
+// policy each one gets.
+type SessionConfig struct {
+	// Platforms enabled on this deployment; nil enables every known platform.
+	Platforms []string
+	// Policies override the default token lifetimes and add session limits
+	// per platform. Zero lifetimes keep the defaults.
+	Policies map[string]entity.SessionPolicy
+}
+
+// NewSessionConfig builds a SessionConfig from per-platform settings keyed by
+// platform name, rejecting names that are not known platforms.
+func NewSessionConfig(platforms []string, accessTTL, refreshTTL map[string]time.Duration, maxSessions map[string]int) (SessionConfig, error) {
+	cfg := SessionConfig{Policies: make(map[string]entity.SessionPolicy)}
+
+	for _, p := range platforms {
+		platform, ok := entity.NormalizePlatform(p)
+		if !ok {
+			return SessionConfig{}, fmt.Errorf("unknown platform %q", p)
+		}
+		cfg.Platforms = append(cfg.Platforms, platform)
+	}
+
+	policy := func(p string) (string, entity.SessionPolicy, error) {
+		platform, ok := entity.NormalizePlatform(p)
+		if !ok {
+			return "", entity.SessionPolicy{}, fmt.Errorf("unknown platform %q", p)
+		}
+		return platform, cfg.Policies[platform], nil
+	}
+	for p, ttl := range accessTTL {
+		platform, pol, err := policy(p)
+		if err != nil {
+			return SessionConfig{}, err
+		}
+		pol.AccessTTL = ttl
+		cfg.Policies[platform] = pol
+	}
+	for p, ttl := range refreshTTL {
+		platform, pol, err := policy(p)
+		if err != nil {
+			return SessionConfig{}, err
+		}
+		pol.RefreshTTL = ttl
+		cfg.Policies[platform] = pol
+	}
+	for p, n := range maxSessions {
+		platform, pol, err := policy(p)
+		if err != nil {
+			return SessionConfig{}, err
+		}
+		pol.MaxSessions = n
+		cfg.Policies[platform] = pol
+	}
+
+	return cfg, nil
+}
+
+// platform normalizes a client-reported platform and checks it is enabled on
+// this deployment.
+func (s *Service) platform(platform string) (string, error) {
+	platform, ok := entity.NormalizePlatform(platform)
+	if !ok || (s.session.Platforms != nil && !slices.Contains(s.session.Platforms, platform)) {
+		return "", domain.ErrUnsupportedPlatform
+	}
+	return platform, nil
+}
+
+// sessionPolicy returns the policy for the platform with unset lifetimes
+// filled from the server defaults. Tokens issued before platforms were
+// recorded have an empty platform and get the defaults.
+func (s *Service) sessionPolicy(platform string) entity.SessionPolicy {
+	policy := s.session.Policies[platform]
+	if policy.AccessTTL <= 0 {
+		policy.AccessTTL = s.jwtSvc.AccessTokenTTL()
+	}
+	if policy.RefreshTTL <= 0 {
+		policy.RefreshTTL = s.refreshTokenTTL
+	}
+	return policy
+}
+
+// issuedPolicy returns the policy a refresh token was issued under, so a
+// refreshed session keeps it even if the platform's policy has changed since.
+// Tokens issued before policies were recorded get the current policy.
+func (s *Service) issuedPolicy(rt *entity.RefreshToken) entity.SessionPolicy {
+	if rt.Policy.AccessTTL <= 0 || rt.Policy.RefreshTTL <= 0 {
+		return s.sessionPolicy(rt.Platform)
+	}
+	return rt.Policy
+}
@@ -0,0 +1,36 @@
+package auth_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	authUC "github.com/marcos-nsantos/field-notes-backend/internal/usecase/auth"
+)
+
+func TestNewSessionConfig(t *testing.T) {
+	t.Run("merges settings per platform", func(t *testing.T) {
+		cfg, err := authUC.NewSessionConfig(
+			[]string{"IOS", "web"},
+			map[string]time.Duration{"web": 5 * time.Minute},
+			map[string]time.Duration{"web": 12 * time.Hour, "ios": 2160 * time.Hour},
+			map[string]int{"Web": 3},
+		)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ios", "web"}, cfg.Platforms)
+		assert.Equal(t, map[string]entity.SessionPolicy{
+			"web": {AccessTTL: 5 * time.Minute, RefreshTTL: 12 * time.Hour, MaxSessions: 3},
+			"ios": {RefreshTTL: 2160 * time.Hour},
+		}, cfg.Policies)
+	})
+
+	t.Run("rejects unknown platforms", func(t *testing.T) {
+		_, err := authUC.NewSessionConfig([]string{"ios"}, nil, nil, map[string]int{"windows": 1})
+
+		assert.ErrorContains(t, err, "windows")
+	})
+}
@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+type SocialLoginInput struct {
+	// Provider is entity.AuthProviderGoogle or entity.AuthProviderApple.
+	Provider string
+	IDToken  string
+	// Nonce, when set, must match the token's nonce claim. Apple puts there
+	// the value the app passed to the sign-in request, usually a hash of
+	// the nonce it generated.
+	Nonce string
+	// Name is used for new accounts when the token carries none, as with
+	// Apple, which only hands the name to the app on the first sign-in.
+	Name       string
+	DeviceID   string
+	DeviceName string
+	Platform   string
+	IP         string
+}
+
+// SocialLogin signs in with an ID token from a social identity provider.
+// The provider account is matched by its subject; the first sign-in links
+// it to the user with the same verified email, creating one if needed.
+func (s *Service) SocialLogin(ctx context.Context, input SocialLoginInput) (*TokenPair, *entity.User, error) {
+	platform, err := s.platform(input.Platform)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	verifier, ok := s.socialVerifiers[input.Provider]
+	if !ok || s.authProviderRepo == nil {
+		return nil, nil, domain.ErrUnknownProvider
+	}
+
+	claims, err := verifier.Verify(ctx, input.IDToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	if claims.Subject == "" || (input.Nonce != "" && claims.Nonce != input.Nonce) {
+		return nil, nil, domain.ErrSocialLoginFailed
+	}
+
+	user, err := s.socialUser(ctx, input, claims.Subject, claims.Email, claims.EmailVerified, claims.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Members of organizations enforcing SSO must sign in through it.
+	if err := s.ensurePasswordLoginAllowed(ctx, user.Email, user.ID); err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := s.startSession(ctx, user, input.Provider, input.DeviceID, platform, input.DeviceName, input.IP)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tokens, user, nil
+}
+
+// socialUser returns the user linked to the provider account, linking it
+// on first sign-in.
+func (s *Service) socialUser(ctx context.Context, input SocialLoginInput, subject, email string, emailVerified bool, name string) (*entity.User, error) {
+	link, err := s.authProviderRepo.GetBySubject(ctx, input.Provider, subject)
+	if err == nil {
+		user, err := s.userRepo.GetByID(ctx, link.UserID)
+		if errors.Is(err, domain.ErrUserNotFound) {
+			// The account was deleted and is waiting to be purged.
+			return nil, domain.ErrSocialLoginFailed
+		}
+		if err != nil {
+			return nil, fmt.Errorf("getting user: %w", err)
+		}
+		return user, nil
+	}
+	if !errors.Is(err, domain.ErrProviderNotLinked) {
+		return nil, fmt.Errorf("getting auth provider: %w", err)
+	}
+
+	// Linking by email is only safe when the provider vouches for it.
+	if email == "" || !emailVerified {
+		return nil, domain.ErrSocialLoginFailed
+	}
+	if name == "" {
+		name = input.Name
+	}
+
+	user, err := s.provisionSSOUser(ctx, email, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authProviderRepo.Create(ctx, entity.NewAuthProviderLink(user.ID, input.Provider, subject, email)); err != nil {
+		return nil, fmt.Errorf("linking auth provider: %w", err)
+	}
+	return user, nil
+}
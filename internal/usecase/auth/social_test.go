@@ -0,0 +1,151 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/identity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/auth"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	authUC "github.com/marcos-nsantos/field-notes-backend/internal/usecase/auth"
+)
+
+func TestService_SocialLogin(t *testing.T) {
+	input := authUC.SocialLoginInput{
+		Provider: entity.AuthProviderApple,
+		IDToken:  "id-token",
+		Nonce:    "nonce-1",
+		Name:     "Park Ranger",
+		DeviceID: "device-123",
+		Platform: "ios",
+	}
+	claims := &identity.IDTokenClaims{
+		Subject:       "apple-user-1",
+		Email:         "ranger@example.com",
+		EmailVerified: true,
+		Nonce:         "nonce-1",
+	}
+
+	type deps struct {
+		userRepo         *mocks.MockUserRepository
+		deviceRepo       *mocks.MockDeviceRepository
+		refreshTokenRepo *mocks.MockRefreshTokenRepository
+		orgRepo          *mocks.MockOrganizationRepository
+		authProviderRepo *mocks.MockAuthProviderRepository
+		verifier         *mocks.MockSocialVerifier
+	}
+	setup := func(t *testing.T) (*authUC.Service, deps) {
+		ctrl := gomock.NewController(t)
+		d := deps{
+			userRepo:         mocks.NewMockUserRepository(ctrl),
+			deviceRepo:       mocks.NewMockDeviceRepository(ctrl),
+			refreshTokenRepo: mocks.NewMockRefreshTokenRepository(ctrl),
+			orgRepo:          mocks.NewMockOrganizationRepository(ctrl),
+			authProviderRepo: mocks.NewMockAuthProviderRepository(ctrl),
+			verifier:         mocks.NewMockSocialVerifier(ctrl),
+		}
+		jwtSvc := auth.NewJWTService("test-secret", 15*time.Minute)
+		svc := authUC.NewService(d.userRepo, d.deviceRepo, d.refreshTokenRepo, d.orgRepo, jwtSvc, nil, nil, nil, nil, nil, nil, 24*time.Hour,
+			authUC.PasswordResetConfig{}, authUC.SessionConfig{}, d.authProviderRepo,
+			map[string]identity.SocialVerifier{entity.AuthProviderApple: d.verifier}, nil, nil, nil)
+		return svc, d
+	}
+	expectSession := func(ctx context.Context, d deps) {
+		deviceID := uuid.New()
+		d.orgRepo.EXPECT().GetByDomain(ctx, "example.com").Return(nil, domain.ErrOrgNotFound)
+		d.orgRepo.EXPECT().ListByUserID(ctx, gomock.Any()).Return(nil, nil)
+		d.deviceRepo.EXPECT().Upsert(ctx, gomock.Any()).Return(nil)
+		d.deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, gomock.Any(), "device-123").
+			Return(&entity.Device{ID: deviceID, DeviceID: "device-123"}, nil)
+		d.refreshTokenRepo.EXPECT().RevokeByDeviceID(ctx, deviceID).Return(nil)
+		d.refreshTokenRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	}
+
+	t.Run("creates and links a user on first sign-in", func(t *testing.T) {
+		svc, d := setup(t)
+		ctx := context.Background()
+
+		d.verifier.EXPECT().Verify(ctx, "id-token").Return(claims, nil)
+		d.authProviderRepo.EXPECT().GetBySubject(ctx, entity.AuthProviderApple, "apple-user-1").Return(nil, domain.ErrProviderNotLinked)
+		d.userRepo.EXPECT().GetByEmail(ctx, "ranger@example.com").Return(nil, domain.ErrUserNotFound)
+		d.userRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+		d.authProviderRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, link *entity.AuthProviderLink) error {
+			assert.Equal(t, entity.AuthProviderApple, link.Provider)
+			assert.Equal(t, "apple-user-1", link.Subject)
+			return nil
+		})
+		expectSession(ctx, d)
+
+		tokens, user, err := svc.SocialLogin(ctx, input)
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, tokens.AccessToken)
+		assert.Equal(t, "ranger@example.com", user.Email)
+		assert.Equal(t, "Park Ranger", user.Name)
+	})
+
+	t.Run("signs in the linked user", func(t *testing.T) {
+		svc, d := setup(t)
+		ctx := context.Background()
+		user := &entity.User{ID: uuid.New(), Email: "ranger@example.com"}
+
+		// The email at the provider may have changed since the link.
+		changed := *claims
+		changed.Email = "relay@privaterelay.appleid.com"
+		d.verifier.EXPECT().Verify(ctx, "id-token").Return(&changed, nil)
+		d.authProviderRepo.EXPECT().GetBySubject(ctx, entity.AuthProviderApple, "apple-user-1").
+			Return(&entity.AuthProviderLink{UserID: user.ID}, nil)
+		d.userRepo.EXPECT().GetByID(ctx, user.ID).Return(user, nil)
+		expectSession(ctx, d)
+
+		_, got, err := svc.SocialLogin(ctx, input)
+
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, got.ID)
+	})
+
+	t.Run("rejects an unverified email", func(t *testing.T) {
+		svc, d := setup(t)
+		ctx := context.Background()
+
+		unverified := *claims
+		unverified.EmailVerified = false
+		d.verifier.EXPECT().Verify(ctx, "id-token").Return(&unverified, nil)
+		d.authProviderRepo.EXPECT().GetBySubject(ctx, entity.AuthProviderApple, "apple-user-1").Return(nil, domain.ErrProviderNotLinked)
+
+		_, _, err := svc.SocialLogin(ctx, input)
+
+		assert.ErrorIs(t, err, domain.ErrSocialLoginFailed)
+	})
+
+	t.Run("rejects a nonce mismatch", func(t *testing.T) {
+		svc, d := setup(t)
+		ctx := context.Background()
+
+		d.verifier.EXPECT().Verify(ctx, "id-token").Return(claims, nil)
+
+		other := input
+		other.Nonce = "nonce-2"
+		_, _, err := svc.SocialLogin(ctx, other)
+
+		assert.ErrorIs(t, err, domain.ErrSocialLoginFailed)
+	})
+
+	t.Run("rejects a provider without a verifier", func(t *testing.T) {
+		svc, _ := setup(t)
+
+		other := input
+		other.Provider = entity.AuthProviderGoogle
+		_, _, err := svc.SocialLogin(context.Background(), other)
+
+		assert.ErrorIs(t, err, domain.ErrUnknownProvider)
+	})
+}
@@ -0,0 +1,219 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/auth"
+)
+
+const ssoStateTTL = 10 * time.Minute
+
+type SSOStartInput struct {
+	OrgSlug    string
+	DeviceID   string
+	DeviceName string
+	Platform   string
+}
+
+// StartSSO returns the identity provider URL the client must visit to log in
+// to the organization.
+func (s *Service) StartSSO(ctx context.Context, input SSOStartInput) (string, error) {
+	platform, err := s.platform(input.Platform)
+	if err != nil {
+		return "", err
+	}
+
+	org, err := s.orgRepo.GetBySlug(ctx, input.OrgSlug)
+	if err != nil {
+		return "", err
+	}
+
+	nonce, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	state, err := s.jwtSvc.GenerateSSOState(auth.SSOState{
+		OrgSlug:    org.Slug,
+		DeviceID:   input.DeviceID,
+		DeviceName: input.DeviceName,
+		Platform:   platform,
+		Nonce:      nonce,
+	}, ssoStateTTL)
+	if err != nil {
+		return "", fmt.Errorf("generating sso state: %w", err)
+	}
+
+	authURL, err := s.oidcProvider.AuthCodeURL(ctx, org, state, nonce)
+	if err != nil {
+		return "", fmt.Errorf("building authorization url: %w", err)
+	}
+
+	return authURL, nil
+}
+
+type SSOCallbackInput struct {
+	OrgSlug string
+	Code    string
+	State   string
+	IP      string
+}
+
+// CompleteSSO exchanges the authorization code, provisions the user just-in-time
+// on first login and issues tokens for the device that started the flow.
+func (s *Service) CompleteSSO(ctx context.Context, input SSOCallbackInput) (*TokenPair, *entity.User, error) {
+	state, err := s.jwtSvc.ValidateSSOState(input.State)
+	if err != nil || state.OrgSlug != input.OrgSlug {
+		return nil, nil, domain.ErrSSOFailed
+	}
+
+	org, err := s.orgRepo.GetBySlug(ctx, input.OrgSlug)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	claims, err := s.oidcProvider.Exchange(ctx, org, input.Code)
+	if err != nil {
+		return nil, nil, fmt.Errorf("exchanging code: %w", err)
+	}
+
+	if claims.Nonce != state.Nonce || claims.Email == "" || !claims.EmailVerified || !org.OwnsEmail(claims.Email) {
+		return nil, nil, domain.ErrSSOFailed
+	}
+
+	user, err := s.provisionSSOUser(ctx, claims.Email, claims.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.orgRepo.UpsertMember(ctx, entity.NewOrgMembership(org.ID, user.ID, claims.Subject)); err != nil {
+		return nil, nil, fmt.Errorf("adding org member: %w", err)
+	}
+
+	tokens, err := s.startSession(ctx, user, LoginSSO+org.Slug, state.DeviceID, state.Platform, state.DeviceName, input.IP)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tokens, user, nil
+}
+
+func (s *Service) provisionSSOUser(ctx context.Context, email, name string) (*entity.User, error) {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, domain.ErrUserNotFound) {
+		return nil, fmt.Errorf("getting user: %w", err)
+	}
+
+	if name == "" {
+		name = email
+	}
+
+	// SSO users have no local password; an empty hash never matches in Compare.
+	user = entity.NewUser(email, "", name)
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		// A concurrent first login created the user between the lookup and
+		// the insert.
+		if errors.Is(err, domain.ErrUserAlreadyExists) {
+			return s.userRepo.GetByEmail(ctx, email)
+		}
+		return nil, fmt.Errorf("creating user: %w", err)
+	}
+
+	s.auditRecorder.Record(ctx, entity.NewAuditEvent(user.ID, entity.AuditAccountCreated, ""))
+	return user, nil
+}
+
+// ensurePasswordLoginAllowed rejects password-based access for members of
+// organizations that enforce SSO, and for emails on an enforcing org's domain.
+func (s *Service) ensurePasswordLoginAllowed(ctx context.Context, email string, userID uuid.UUID) error {
+	org, err := s.orgRepo.GetByDomain(ctx, entity.EmailDomain(email))
+	switch {
+	case err == nil && org.SSOEnforced:
+		return domain.ErrSSORequired
+	case err != nil && !errors.Is(err, domain.ErrOrgNotFound):
+		return fmt.Errorf("getting organization: %w", err)
+	}
+
+	if userID == uuid.Nil {
+		return nil
+	}
+
+	orgs, err := s.orgRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("listing organizations: %w", err)
+	}
+	for _, o := range orgs {
+		if o.SSOEnforced {
+			return domain.ErrSSORequired
+		}
+	}
+
+	return nil
+}
+
+type SSOSettingsInput struct {
+	UserID   uuid.UUID
+	OrgSlug  string
+	Issuer   string
+	ClientID string
+	// ClientSecret replaces the stored secret; empty keeps it.
+	ClientSecret string
+	SSOEnforced  bool
+}
+
+// UpdateSSOSettings changes the organization's identity provider settings.
+// Only admins of the organization may change them.
+func (s *Service) UpdateSSOSettings(ctx context.Context, input SSOSettingsInput) (*entity.Organization, error) {
+	issuer, err := url.Parse(input.Issuer)
+	if err != nil || issuer.Scheme != "https" || issuer.Host == "" {
+		return nil, domain.ErrInvalidIssuer
+	}
+
+	org, err := s.orgRepo.GetBySlug(ctx, input.OrgSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	isAdmin, err := s.orgRepo.IsAdmin(ctx, org.ID, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin {
+		return nil, domain.ErrForbidden
+	}
+
+	org.OIDCIssuer = input.Issuer
+	org.OIDCClientID = input.ClientID
+	if input.ClientSecret != "" {
+		org.OIDCClientSecret = input.ClientSecret
+	}
+	org.SSOEnforced = input.SSOEnforced
+	org.UpdatedAt = s.clock.Now()
+
+	if err := s.orgRepo.UpdateSSO(ctx, org); err != nil {
+		return nil, fmt.Errorf("updating sso settings: %w", err)
+	}
+	s.auditRecorder.Record(ctx, entity.NewAuditEvent(input.UserID, entity.AuditSSOSettings, org.Slug))
+	return org, nil
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating random bytes: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,228 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/identity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/auth"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	authUC "github.com/marcos-nsantos/field-notes-backend/internal/usecase/auth"
+)
+
+func TestService_StartSSO(t *testing.T) {
+	t.Run("returns identity provider url", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		orgRepo := mocks.NewMockOrganizationRepository(ctrl)
+		oidcProvider := mocks.NewMockOIDCProvider(ctrl)
+		jwtSvc := auth.NewJWTService("test-secret", 15*time.Minute)
+		svc := authUC.NewService(nil, nil, nil, orgRepo, jwtSvc, nil, oidcProvider, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
+
+		ctx := context.Background()
+		org := &entity.Organization{ID: uuid.New(), Slug: "acme", Domain: "acme.org"}
+
+		orgRepo.EXPECT().GetBySlug(ctx, "acme").Return(org, nil)
+		oidcProvider.EXPECT().AuthCodeURL(ctx, org, gomock.Any(), gomock.Any()).
+			Return("https://idp.acme.org/authorize?state=abc", nil)
+
+		authURL, err := svc.StartSSO(ctx, authUC.SSOStartInput{
+			OrgSlug:  "acme",
+			DeviceID: "device-123",
+			Platform: "ios",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "https://idp.acme.org/authorize?state=abc", authURL)
+	})
+
+	t.Run("unknown organization", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		orgRepo := mocks.NewMockOrganizationRepository(ctrl)
+		svc := authUC.NewService(nil, nil, nil, orgRepo, nil, nil, nil, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
+
+		ctx := context.Background()
+		orgRepo.EXPECT().GetBySlug(ctx, "missing").Return(nil, domain.ErrOrgNotFound)
+
+		authURL, err := svc.StartSSO(ctx, authUC.SSOStartInput{OrgSlug: "missing", Platform: "ios"})
+
+		assert.Empty(t, authURL)
+		assert.ErrorIs(t, err, domain.ErrOrgNotFound)
+	})
+}
+
+func TestService_CompleteSSO(t *testing.T) {
+	newState := func(t *testing.T, jwtSvc *auth.JWTService, nonce string) string {
+		t.Helper()
+		state, err := jwtSvc.GenerateSSOState(auth.SSOState{
+			OrgSlug:  "acme",
+			DeviceID: "device-123",
+			Platform: "android",
+			Nonce:    nonce,
+		}, time.Minute)
+		require.NoError(t, err)
+		return state
+	}
+
+	t.Run("provisions user on first login", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		refreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+		orgRepo := mocks.NewMockOrganizationRepository(ctrl)
+		oidcProvider := mocks.NewMockOIDCProvider(ctrl)
+		jwtSvc := auth.NewJWTService("test-secret", 15*time.Minute)
+		svc := authUC.NewService(userRepo, deviceRepo, refreshTokenRepo, orgRepo, jwtSvc, nil, oidcProvider, nil, nil, nil, nil, 24*time.Hour, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
+
+		ctx := context.Background()
+		org := &entity.Organization{ID: uuid.New(), Slug: "acme", Domain: "acme.org", SSOEnforced: true}
+		deviceID := uuid.New()
+
+		orgRepo.EXPECT().GetBySlug(ctx, "acme").Return(org, nil)
+		oidcProvider.EXPECT().Exchange(ctx, org, "auth-code").Return(&identity.IDTokenClaims{
+			Subject:       "idp-user-1",
+			Email:         "ranger@acme.org",
+			EmailVerified: true,
+			Name:          "Park Ranger",
+			Nonce:         "nonce-1",
+		}, nil)
+		userRepo.EXPECT().GetByEmail(ctx, "ranger@acme.org").Return(nil, domain.ErrUserNotFound)
+		userRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+		orgRepo.EXPECT().UpsertMember(ctx, gomock.Any()).Return(nil)
+		deviceRepo.EXPECT().Upsert(ctx, gomock.Any()).Return(nil)
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, gomock.Any(), "device-123").
+			Return(&entity.Device{ID: deviceID, DeviceID: "device-123"}, nil)
+		refreshTokenRepo.EXPECT().RevokeByDeviceID(ctx, deviceID).Return(nil)
+		refreshTokenRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+		tokens, user, err := svc.CompleteSSO(ctx, authUC.SSOCallbackInput{
+			OrgSlug: "acme",
+			Code:    "auth-code",
+			State:   newState(t, jwtSvc, "nonce-1"),
+		})
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, tokens.AccessToken)
+		assert.Equal(t, "ranger@acme.org", user.Email)
+		assert.Equal(t, "Park Ranger", user.Name)
+	})
+
+	t.Run("rejects nonce mismatch", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		orgRepo := mocks.NewMockOrganizationRepository(ctrl)
+		oidcProvider := mocks.NewMockOIDCProvider(ctrl)
+		jwtSvc := auth.NewJWTService("test-secret", 15*time.Minute)
+		svc := authUC.NewService(nil, nil, nil, orgRepo, jwtSvc, nil, oidcProvider, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
+
+		ctx := context.Background()
+		org := &entity.Organization{ID: uuid.New(), Slug: "acme", Domain: "acme.org"}
+
+		orgRepo.EXPECT().GetBySlug(ctx, "acme").Return(org, nil)
+		oidcProvider.EXPECT().Exchange(ctx, org, "auth-code").Return(&identity.IDTokenClaims{
+			Email:         "ranger@acme.org",
+			EmailVerified: true,
+			Nonce:         "other-nonce",
+		}, nil)
+
+		tokens, user, err := svc.CompleteSSO(ctx, authUC.SSOCallbackInput{
+			OrgSlug: "acme",
+			Code:    "auth-code",
+			State:   newState(t, jwtSvc, "nonce-1"),
+		})
+
+		assert.Nil(t, tokens)
+		assert.Nil(t, user)
+		assert.ErrorIs(t, err, domain.ErrSSOFailed)
+	})
+
+	t.Run("rejects state issued for another organization", func(t *testing.T) {
+		jwtSvc := auth.NewJWTService("test-secret", 15*time.Minute)
+		svc := authUC.NewService(nil, nil, nil, nil, jwtSvc, nil, nil, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
+
+		tokens, user, err := svc.CompleteSSO(context.Background(), authUC.SSOCallbackInput{
+			OrgSlug: "other-org",
+			Code:    "auth-code",
+			State:   newState(t, jwtSvc, "nonce-1"),
+		})
+
+		assert.Nil(t, tokens)
+		assert.Nil(t, user)
+		assert.ErrorIs(t, err, domain.ErrSSOFailed)
+	})
+}
+
+func TestService_UpdateSSOSettings(t *testing.T) {
+	input := authUC.SSOSettingsInput{
+		UserID:      uuid.New(),
+		OrgSlug:     "acme",
+		Issuer:      "https://login.acme.org",
+		ClientID:    "new-client",
+		SSOEnforced: true,
+	}
+
+	t.Run("admin updates settings and keeps the secret when none is given", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		orgRepo := mocks.NewMockOrganizationRepository(ctrl)
+		svc := authUC.NewService(nil, nil, nil, orgRepo, nil, nil, nil, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
+
+		ctx := context.Background()
+		org := &entity.Organization{ID: uuid.New(), Slug: "acme", OIDCIssuer: "https://idp.acme.org", OIDCClientSecret: "secret"}
+
+		orgRepo.EXPECT().GetBySlug(ctx, "acme").Return(org, nil)
+		orgRepo.EXPECT().IsAdmin(ctx, org.ID, input.UserID).Return(true, nil)
+		orgRepo.EXPECT().UpdateSSO(ctx, org).Return(nil)
+
+		got, err := svc.UpdateSSOSettings(ctx, input)
+
+		require.NoError(t, err)
+		assert.Equal(t, "https://login.acme.org", got.OIDCIssuer)
+		assert.Equal(t, "new-client", got.OIDCClientID)
+		assert.Equal(t, "secret", got.OIDCClientSecret)
+		assert.True(t, got.SSOEnforced)
+	})
+
+	t.Run("rejects members who are not admins", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		orgRepo := mocks.NewMockOrganizationRepository(ctrl)
+		svc := authUC.NewService(nil, nil, nil, orgRepo, nil, nil, nil, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
+
+		ctx := context.Background()
+		org := &entity.Organization{ID: uuid.New(), Slug: "acme"}
+
+		orgRepo.EXPECT().GetBySlug(ctx, "acme").Return(org, nil)
+		orgRepo.EXPECT().IsAdmin(ctx, org.ID, input.UserID).Return(false, nil)
+
+		_, err := svc.UpdateSSOSettings(ctx, input)
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("rejects an issuer that is not https", func(t *testing.T) {
+		svc := authUC.NewService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, 0, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, nil, nil, nil)
+		bad := input
+		bad.Issuer = "http://login.acme.org"
+
+		_, err := svc.UpdateSSOSettings(context.Background(), bad)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidIssuer)
+	})
+}
@@ -0,0 +1,119 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+// Authorizer decides what a user may do with a note, so usecases don't
+// compare owner IDs themselves.
+type Authorizer struct {
+	shareRepo repository.ShareRepository
+	orgRepo   repository.OrganizationRepository
+	teamRepo  repository.TeamRepository
+}
+
+func NewAuthorizer(
+	shareRepo repository.ShareRepository,
+	orgRepo repository.OrganizationRepository,
+	teamRepo repository.TeamRepository,
+) *Authorizer {
+	return &Authorizer{
+		shareRepo: shareRepo,
+		orgRepo:   orgRepo,
+		teamRepo:  teamRepo,
+	}
+}
+
+// Can reports whether the subject may perform the action on the note.
+func (a *Authorizer) Can(ctx context.Context, subject uuid.UUID, action Action, note *entity.Note) (bool, error) {
+	access, err := a.NoteAccess(ctx, subject, note)
+	if err != nil {
+		return false, err
+	}
+	return Allowed(access, action), nil
+}
+
+// Authorize is Can for callers that stop on denial: it returns
+// domain.ErrForbidden when the action is not allowed.
+func (a *Authorizer) Authorize(ctx context.Context, subject uuid.UUID, action Action, note *entity.Note) error {
+	ok, err := a.Can(ctx, subject, action, note)
+	if err != nil {
+		return fmt.Errorf("authorizing %s: %w", action, err)
+	}
+	if !ok {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+// NoteAccess returns the user's access to the note, resolved in order: the
+// owner, the user's role in the team owning the note, an explicit share on
+// the note, then an admin of an organization the owner belongs to, who can
+// view. It returns "" when the user has no access.
+//
+// On team notes the owner is the member who created the note; the others
+// get the access of their team role, see domain.TeamNoteAccess.
+func (a *Authorizer) NoteAccess(ctx context.Context, userID uuid.UUID, note *entity.Note) (string, error) {
+	if note.UserID == userID {
+		return entity.AccessOwner, nil
+	}
+
+	if note.TeamID != nil {
+		role, err := a.TeamRole(ctx, userID, *note.TeamID)
+		if err != nil {
+			return "", err
+		}
+		if access := domain.TeamNoteAccess(role); access != "" {
+			return access, nil
+		}
+	}
+
+	role, err := a.shareRepo.GetRole(ctx, note.ID, userID)
+	if err != nil {
+		return "", fmt.Errorf("getting share role: %w", err)
+	}
+	if role != "" {
+		return role, nil
+	}
+
+	isAdmin, err := a.orgRepo.IsAdminOver(ctx, userID, note.UserID)
+	if err != nil {
+		return "", fmt.Errorf("checking org role: %w", err)
+	}
+	if isAdmin {
+		return entity.ShareRoleViewer, nil
+	}
+
+	return "", nil
+}
+
+// TeamRole returns the user's role in the team, or "" when they are not a
+// member. Invitations grant no role until accepted.
+func (a *Authorizer) TeamRole(ctx context.Context, userID, teamID uuid.UUID) (string, error) {
+	role, err := a.teamRepo.GetRole(ctx, teamID, userID)
+	if err != nil {
+		return "", fmt.Errorf("getting team role: %w", err)
+	}
+	return role, nil
+}
+
+// AuthorizeTeam returns domain.ErrForbidden unless the user's team role
+// allows the action on the team's notes: reading them for every member,
+// creating and editing them for editors and up.
+func (a *Authorizer) AuthorizeTeam(ctx context.Context, userID, teamID uuid.UUID, action Action) error {
+	role, err := a.TeamRole(ctx, userID, teamID)
+	if err != nil {
+		return err
+	}
+	if !Allowed(domain.TeamNoteAccess(role), action) {
+		return domain.ErrForbidden
+	}
+	return nil
+}
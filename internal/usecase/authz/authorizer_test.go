@@ -0,0 +1,171 @@
+package authz_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/authz"
+)
+
+func TestAuthorizer_NoteAccess(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("owner has owner access without lookups", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		a := authz.NewAuthorizer(mocks.NewMockShareRepository(ctrl), mocks.NewMockOrganizationRepository(ctrl), nil)
+		ownerID := uuid.New()
+
+		access, err := a.NoteAccess(ctx, ownerID, &entity.Note{ID: uuid.New(), UserID: ownerID})
+
+		require.NoError(t, err)
+		assert.Equal(t, entity.AccessOwner, access)
+	})
+
+	t.Run("explicit share wins over org role", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		shareRepo := mocks.NewMockShareRepository(ctrl)
+		a := authz.NewAuthorizer(shareRepo, mocks.NewMockOrganizationRepository(ctrl), nil)
+		userID := uuid.New()
+		n := &entity.Note{ID: uuid.New(), UserID: uuid.New()}
+
+		shareRepo.EXPECT().GetRole(ctx, n.ID, userID).Return(entity.ShareRoleEditor, nil)
+
+		access, err := a.NoteAccess(ctx, userID, n)
+
+		require.NoError(t, err)
+		assert.Equal(t, entity.ShareRoleEditor, access)
+	})
+
+	t.Run("org admin of the owner can view", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		shareRepo := mocks.NewMockShareRepository(ctrl)
+		orgRepo := mocks.NewMockOrganizationRepository(ctrl)
+		a := authz.NewAuthorizer(shareRepo, orgRepo, nil)
+		adminID := uuid.New()
+		n := &entity.Note{ID: uuid.New(), UserID: uuid.New()}
+
+		shareRepo.EXPECT().GetRole(ctx, n.ID, adminID).Return("", nil)
+		orgRepo.EXPECT().IsAdminOver(ctx, adminID, n.UserID).Return(true, nil)
+
+		access, err := a.NoteAccess(ctx, adminID, n)
+
+		require.NoError(t, err)
+		assert.Equal(t, entity.ShareRoleViewer, access)
+	})
+
+	t.Run("stranger has no access", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		shareRepo := mocks.NewMockShareRepository(ctrl)
+		orgRepo := mocks.NewMockOrganizationRepository(ctrl)
+		a := authz.NewAuthorizer(shareRepo, orgRepo, nil)
+		userID := uuid.New()
+		n := &entity.Note{ID: uuid.New(), UserID: uuid.New()}
+
+		shareRepo.EXPECT().GetRole(ctx, n.ID, userID).Return("", nil)
+		orgRepo.EXPECT().IsAdminOver(ctx, userID, n.UserID).Return(false, nil)
+
+		access, err := a.NoteAccess(ctx, userID, n)
+
+		require.NoError(t, err)
+		assert.False(t, authz.Allowed(access, authz.ActionRead))
+	})
+
+	t.Run("team role grants access to team notes", func(t *testing.T) {
+		for role, want := range map[string]string{
+			entity.TeamRoleOwner:  entity.AccessOwner,
+			entity.TeamRoleAdmin:  entity.AccessOwner,
+			entity.TeamRoleEditor: entity.ShareRoleEditor,
+			entity.TeamRoleViewer: entity.ShareRoleViewer,
+		} {
+			ctrl := gomock.NewController(t)
+			teamRepo := mocks.NewMockTeamRepository(ctrl)
+			a := authz.NewAuthorizer(nil, nil, teamRepo)
+			userID, teamID := uuid.New(), uuid.New()
+			n := &entity.Note{ID: uuid.New(), UserID: uuid.New(), TeamID: &teamID}
+
+			teamRepo.EXPECT().GetRole(ctx, teamID, userID).Return(role, nil)
+
+			access, err := a.NoteAccess(ctx, userID, n)
+
+			require.NoError(t, err)
+			assert.Equal(t, want, access, role)
+		}
+	})
+
+	t.Run("non-members of the team fall back to shares", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		shareRepo := mocks.NewMockShareRepository(ctrl)
+		teamRepo := mocks.NewMockTeamRepository(ctrl)
+		a := authz.NewAuthorizer(shareRepo, nil, teamRepo)
+		userID, teamID := uuid.New(), uuid.New()
+		n := &entity.Note{ID: uuid.New(), UserID: uuid.New(), TeamID: &teamID}
+
+		teamRepo.EXPECT().GetRole(ctx, teamID, userID).Return("", nil)
+		shareRepo.EXPECT().GetRole(ctx, n.ID, userID).Return(entity.ShareRoleViewer, nil)
+
+		access, err := a.NoteAccess(ctx, userID, n)
+
+		require.NoError(t, err)
+		assert.Equal(t, entity.ShareRoleViewer, access)
+	})
+}
+
+func TestAuthorizer_Authorize(t *testing.T) {
+	t.Run("returns forbidden when the policy denies the action", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		shareRepo := mocks.NewMockShareRepository(ctrl)
+		a := authz.NewAuthorizer(shareRepo, mocks.NewMockOrganizationRepository(ctrl), nil)
+		ctx := context.Background()
+		viewerID := uuid.New()
+		n := &entity.Note{ID: uuid.New(), UserID: uuid.New()}
+
+		shareRepo.EXPECT().GetRole(ctx, n.ID, viewerID).Return(entity.ShareRoleViewer, nil).Times(2)
+
+		assert.NoError(t, a.Authorize(ctx, viewerID, authz.ActionRead, n))
+		assert.ErrorIs(t, a.Authorize(ctx, viewerID, authz.ActionEdit, n), domain.ErrForbidden)
+	})
+}
+
+func TestAllowed(t *testing.T) {
+	tests := []struct {
+		access string
+		action authz.Action
+		want   bool
+	}{
+		{entity.AccessOwner, authz.ActionShare, true},
+		{entity.AccessOwner, authz.ActionDelete, true},
+		{entity.ShareRoleEditor, authz.ActionEdit, true},
+		{entity.ShareRoleEditor, authz.ActionDelete, false},
+		{entity.ShareRoleEditor, authz.ActionShare, false},
+		{entity.ShareRoleViewer, authz.ActionRead, true},
+		{entity.ShareRoleViewer, authz.ActionEdit, false},
+		{"", authz.ActionRead, false},
+		{entity.AccessOwner, authz.Action("unknown"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.access+"/"+string(tt.action), func(t *testing.T) {
+			assert.Equal(t, tt.want, authz.Allowed(tt.access, tt.action))
+		})
+	}
+}
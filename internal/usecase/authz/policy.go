@@ -0,0 +1,38 @@
+package authz
+
+import "github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+
+type Action string
+
+const (
+	ActionRead Action = "read"
+	// ActionEdit covers changing the note's fields and its photos.
+	ActionEdit    Action = "edit"
+	ActionDelete  Action = "delete"
+	ActionRestore Action = "restore"
+	ActionShare   Action = "share"
+	// ActionProtect covers changing the note's sensitivity and, for sensitive
+	// notes, its location, which other users only see generalized.
+	ActionProtect Action = "protect"
+)
+
+// policy lists the access levels allowed to perform each action. Actions
+// that are not listed are denied.
+var policy = map[Action][]string{
+	ActionRead:    {entity.AccessOwner, entity.ShareRoleEditor, entity.ShareRoleViewer},
+	ActionEdit:    {entity.AccessOwner, entity.ShareRoleEditor},
+	ActionDelete:  {entity.AccessOwner},
+	ActionRestore: {entity.AccessOwner},
+	ActionShare:   {entity.AccessOwner},
+	ActionProtect: {entity.AccessOwner},
+}
+
+// Allowed reports whether the access level permits the action.
+func Allowed(access string, action Action) bool {
+	for _, a := range policy[action] {
+		if a == access {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package autotitle
+
+import (
+	"context"
+	"strings"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+// Summarizer suggests a title for note content, as a language model would.
+// It bounds its own calls: notes are saved only once it has answered.
+type Summarizer interface {
+	Title(ctx context.Context, content string) (string, error)
+}
+
+// Titler titles the notes that arrive without a title, or with one of the
+// placeholders capture apps fill in, from their content. It asks the
+// summarizer when there is one and falls back to the first sentence of the
+// content when the summarizer fails or has no suggestion. A nil *Titler
+// titles nothing, so services work without one.
+type Titler struct {
+	summarizer Summarizer
+}
+
+// NewTitler returns a titler asking summarizer, which may be nil.
+func NewTitler(summarizer Summarizer) *Titler {
+	return &Titler{summarizer: summarizer}
+}
+
+// Title gives a new note a title from its content when it needs one.
+func (t *Titler) Title(ctx context.Context, note *entity.Note) {
+	if t == nil || !entity.IsPlaceholderTitle(note.Title) {
+		return
+	}
+	note.SetAutoTitle(t.generate(ctx, note.Content))
+}
+
+// Retitle is Title for an edited note whose content was previous. A title
+// generated before is generated again when the content changed.
+func (t *Titler) Retitle(ctx context.Context, note *entity.Note, previous string) {
+	if t == nil {
+		return
+	}
+	if !entity.IsPlaceholderTitle(note.Title) && !(note.AutoTitled && note.Content != previous) {
+		return
+	}
+	note.SetAutoTitle(t.generate(ctx, note.Content))
+}
+
+func (t *Titler) generate(ctx context.Context, content string) string {
+	if t.summarizer != nil && strings.TrimSpace(content) != "" {
+		if suggested, err := t.summarizer.Title(ctx, content); err == nil {
+			title := entity.ClampTitle(strings.Join(strings.Fields(suggested), " "))
+			if !entity.IsPlaceholderTitle(title) {
+				return title
+			}
+		}
+	}
+	return entity.TitleFromContent(content)
+}
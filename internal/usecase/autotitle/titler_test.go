@@ -0,0 +1,92 @@
+package autotitle_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/autotitle"
+)
+
+type summarizerFunc func(ctx context.Context, content string) (string, error)
+
+func (f summarizerFunc) Title(ctx context.Context, content string) (string, error) {
+	return f(ctx, content)
+}
+
+func TestTitler_Title(t *testing.T) {
+	ctx := context.Background()
+	titler := autotitle.NewTitler(nil)
+
+	tests := []struct {
+		name    string
+		title   string
+		content string
+		want    string
+	}{
+		{"first sentence", "", "Heron nest by the creek. Two eggs, one hatched.", "Heron nest by the creek."},
+		{"first line with text", "  ", "\n\n## Plot 7\nSoil is dry", "Plot 7"},
+		{"placeholder in any case", "UNTITLED", "- Fence down near the gate", "Fence down near the gate"},
+		{"decimal points are not sentence ends", "nota de voz", "pH 6.5 at the spring", "pH 6.5 at the spring"},
+		{"long content cut at a word", "", strings.Repeat("lichen ", 20), "lichen lichen lichen lichen lichen lichen lichen lichen lichen lichen lichen…"},
+		{"a title that is not a placeholder", "Heron", "Nest by the creek", "Heron"},
+		{"no text to title from", "", " \n ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			note := &entity.Note{Title: tt.title, Content: tt.content}
+			titler.Title(ctx, note)
+			assert.Equal(t, tt.want, note.Title)
+			assert.Equal(t, tt.want != "" && tt.want != tt.title, note.AutoTitled)
+			assert.LessOrEqual(t, len([]rune(note.Title)), entity.MaxAutoTitleLength)
+		})
+	}
+
+	t.Run("asks the summarizer first", func(t *testing.T) {
+		titler := autotitle.NewTitler(summarizerFunc(func(context.Context, string) (string, error) {
+			return "  Heron\nnest ", nil
+		}))
+		note := &entity.Note{Content: "Saw a heron nest by the creek."}
+		titler.Title(ctx, note)
+		assert.Equal(t, "Heron nest", note.Title)
+	})
+
+	t.Run("falls back to the content when the summarizer fails", func(t *testing.T) {
+		for _, s := range []summarizerFunc{
+			func(context.Context, string) (string, error) { return "", errors.New("unavailable") },
+			func(context.Context, string) (string, error) { return "Untitled", nil },
+		} {
+			note := &entity.Note{Content: "Heron nest"}
+			autotitle.NewTitler(s).Title(ctx, note)
+			assert.Equal(t, "Heron nest", note.Title)
+		}
+	})
+
+	t.Run("a nil titler titles nothing", func(t *testing.T) {
+		var titler *autotitle.Titler
+		note := &entity.Note{Content: "Heron nest"}
+		titler.Title(ctx, note)
+		titler.Retitle(ctx, note, "")
+		assert.Empty(t, note.Title)
+	})
+}
+
+func TestTitler_Retitle(t *testing.T) {
+	ctx := context.Background()
+	titler := autotitle.NewTitler(nil)
+
+	note := &entity.Note{Title: "Heron nest", Content: "Heron nest, two eggs", AutoTitled: true}
+	titler.Retitle(ctx, note, "Heron nest, two eggs")
+	assert.Equal(t, "Heron nest", note.Title, "unchanged content keeps the title")
+
+	titler.Retitle(ctx, note, "Heron nest")
+	assert.Equal(t, "Heron nest, two eggs", note.Title)
+
+	note = &entity.Note{Title: "Herons", Content: "Heron nest, two eggs"}
+	titler.Retitle(ctx, note, "Heron nest")
+	assert.Equal(t, "Herons", note.Title, "a title the user set stays")
+}
@@ -0,0 +1,90 @@
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/storage"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+// Service removes what the API leaves behind: expired sessions, notes
+// deleted longer ago than the retention and the storage objects of notes
+// that no longer exist.
+type Service struct {
+	tokenRepo  repository.RefreshTokenRepository
+	noteRepo   repository.NoteRepository
+	orphanRepo repository.StorageOrphanRepository
+	storage    storage.ImageStorage
+	// noteRetention is how long soft-deleted notes are kept. It is never
+	// shorter than the restore window.
+	noteRetention time.Duration
+	batchSize     int
+}
+
+func NewService(tokenRepo repository.RefreshTokenRepository, noteRepo repository.NoteRepository, orphanRepo repository.StorageOrphanRepository, imageStorage storage.ImageStorage, noteRetention time.Duration, batchSize int) *Service {
+	return &Service{
+		tokenRepo:     tokenRepo,
+		noteRepo:      noteRepo,
+		orphanRepo:    orphanRepo,
+		storage:       imageStorage,
+		noteRetention: max(noteRetention, entity.NoteRestoreWindow),
+		batchSize:     max(batchSize, 1),
+	}
+}
+
+// DeleteExpiredTokens removes expired and revoked refresh tokens.
+func (s *Service) DeleteExpiredTokens(ctx context.Context) (int64, error) {
+	deleted, err := s.tokenRepo.DeleteExpired(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("deleting expired tokens: %w", err)
+	}
+	return deleted, nil
+}
+
+// PurgeDeletedNotes hard-deletes, in batches, the notes soft-deleted more
+// than the retention before now. Devices that sync after that never see
+// them deleted.
+func (s *Service) PurgeDeletedNotes(ctx context.Context, now time.Time) (int64, error) {
+	before := now.Add(-s.noteRetention)
+	var total int64
+	for {
+		purged, err := s.noteRepo.PurgeDeleted(ctx, before, s.batchSize)
+		total += purged
+		if err != nil {
+			return total, fmt.Errorf("purging deleted notes: %w", err)
+		}
+		if purged < int64(s.batchSize) {
+			return total, nil
+		}
+	}
+}
+
+// CollectOrphans deletes one batch of orphaned objects from storage and
+// returns how many it deleted. Objects that fail stay queued for the next
+// run; the others are still removed.
+func (s *Service) CollectOrphans(ctx context.Context) (int, error) {
+	keys, err := s.orphanRepo.List(ctx, s.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("listing storage orphans: %w", err)
+	}
+
+	var deleted []string
+	var errs []error
+	for _, key := range keys {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			errs = append(errs, fmt.Errorf("deleting %s from storage: %w", key, err))
+			continue
+		}
+		deleted = append(deleted, key)
+	}
+
+	if err := s.orphanRepo.Remove(ctx, deleted); err != nil {
+		errs = append(errs, fmt.Errorf("removing storage orphans: %w", err))
+		return 0, errors.Join(errs...)
+	}
+	return len(deleted), errors.Join(errs...)
+}
@@ -0,0 +1,96 @@
+package cleanup_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/cleanup"
+)
+
+func TestService_PurgeDeletedNotes(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("purges in batches until one is not full", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := cleanup.NewService(nil, noteRepo, nil, nil, 90*24*time.Hour, 2)
+
+		ctx := context.Background()
+		before := now.Add(-90 * 24 * time.Hour)
+		gomock.InOrder(
+			noteRepo.EXPECT().PurgeDeleted(ctx, before, 2).Return(int64(2), nil),
+			noteRepo.EXPECT().PurgeDeleted(ctx, before, 2).Return(int64(1), nil),
+		)
+
+		purged, err := svc.PurgeDeletedNotes(ctx, now)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), purged)
+	})
+
+	t.Run("never purges notes that can still be restored", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := cleanup.NewService(nil, noteRepo, nil, nil, time.Hour, 100)
+
+		noteRepo.EXPECT().PurgeDeleted(gomock.Any(), now.Add(-entity.NoteRestoreWindow), 100).Return(int64(0), nil)
+
+		_, err := svc.PurgeDeletedNotes(context.Background(), now)
+
+		require.NoError(t, err)
+	})
+}
+
+func TestService_CollectOrphans(t *testing.T) {
+	t.Run("deletes objects and dequeues them", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		orphanRepo := mocks.NewMockStorageOrphanRepository(ctrl)
+		storage := mocks.NewMockImageStorage(ctrl)
+		svc := cleanup.NewService(nil, nil, orphanRepo, storage, 0, 100)
+
+		ctx := context.Background()
+		orphanRepo.EXPECT().List(ctx, 100).Return([]string{"notes/a.jpg", "notes/b.m4a"}, nil)
+		storage.EXPECT().Delete(ctx, "notes/a.jpg").Return(nil)
+		storage.EXPECT().Delete(ctx, "notes/b.m4a").Return(nil)
+		orphanRepo.EXPECT().Remove(ctx, []string{"notes/a.jpg", "notes/b.m4a"}).Return(nil)
+
+		deleted, err := svc.CollectOrphans(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, deleted)
+	})
+
+	t.Run("keeps objects that failed to delete queued", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		orphanRepo := mocks.NewMockStorageOrphanRepository(ctrl)
+		storage := mocks.NewMockImageStorage(ctrl)
+		svc := cleanup.NewService(nil, nil, orphanRepo, storage, 0, 100)
+
+		ctx := context.Background()
+		orphanRepo.EXPECT().List(ctx, 100).Return([]string{"notes/a.jpg", "notes/b.jpg"}, nil)
+		storage.EXPECT().Delete(ctx, "notes/a.jpg").Return(errors.New("s3 unavailable"))
+		storage.EXPECT().Delete(ctx, "notes/b.jpg").Return(nil)
+		orphanRepo.EXPECT().Remove(ctx, []string{"notes/b.jpg"}).Return(nil)
+
+		deleted, err := svc.CollectOrphans(ctx)
+
+		assert.ErrorContains(t, err, "notes/a.jpg")
+		assert.Equal(t, 1, deleted)
+	})
+}
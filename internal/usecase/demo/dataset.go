@@ -0,0 +1,59 @@
+package demo
+
+import "github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+
+type sampleNote struct {
+	title        string
+	content      string
+	lat, lng     float64
+	accuracy     float64
+	measurements []valueobject.Measurement
+}
+
+func (n sampleNote) location() *valueobject.Location {
+	accuracy := n.accuracy
+	return valueobject.NewLocation(n.lat, n.lng, nil, &accuracy)
+}
+
+// sampleNotes is the dataset the demo account is reset to.
+var sampleNotes = []sampleNote{
+	{
+		title:    "Araucária no trilho do mirante",
+		content:  "Exemplar adulto junto ao marco 3 do trilho. Copa em bom estado, sem sinais de pragas.",
+		lat:      -25.4284,
+		lng:      -49.2733,
+		accuracy: 8,
+		measurements: []valueobject.Measurement{
+			{Name: "altura", Kind: valueobject.MeasurementLength, Value: 21.5},
+			{Name: "perímetro do tronco", Kind: valueobject.MeasurementLength, Value: 2.35},
+		},
+	},
+	{
+		title:    "Nascente do córrego",
+		content:  "Água límpida, fluxo contínuo. Margem esquerda com erosão recente após as chuvas.",
+		lat:      -25.4312,
+		lng:      -49.2698,
+		accuracy: 12,
+		measurements: []valueobject.Measurement{
+			{Name: "temperatura da água", Kind: valueobject.MeasurementTemperature, Value: 291.35},
+		},
+	},
+	{
+		title:    "Parcela de amostragem P-07",
+		content:  "Recolhidas amostras de solo a 20 cm de profundidade em cinco pontos da parcela.",
+		lat:      -25.4351,
+		lng:      -49.2765,
+		accuracy: 5,
+		measurements: []valueobject.Measurement{
+			{Name: "massa da amostra", Kind: valueobject.MeasurementMass, Value: 1.2},
+			{Name: "profundidade", Kind: valueobject.MeasurementLength, Value: 0.2},
+		},
+	},
+	{
+		title:    "Avistamento de gralha-azul",
+		content:  "Grupo de quatro indivíduos a alimentar-se de pinhões perto da estrada de acesso.",
+		lat:      -25.4297,
+		lng:      -49.2801,
+		accuracy: 25,
+	},
+}
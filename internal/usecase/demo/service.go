@@ -0,0 +1,95 @@
+package demo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/auth"
+)
+
+const (
+	demoName   = "Demo"
+	demoPrefix = "DEMO"
+)
+
+type Credentials struct {
+	Email    string
+	Password string
+}
+
+type Service struct {
+	userRepo       repository.UserRepository
+	noteRepo       repository.NoteRepository
+	passwordHasher *auth.PasswordHasher
+	credentials    Credentials
+}
+
+func NewService(
+	userRepo repository.UserRepository,
+	noteRepo repository.NoteRepository,
+	passwordHasher *auth.PasswordHasher,
+	credentials Credentials,
+) *Service {
+	return &Service{
+		userRepo:       userRepo,
+		noteRepo:       noteRepo,
+		passwordHasher: passwordHasher,
+		credentials:    credentials,
+	}
+}
+
+// Credentials returns the login advertised for the demo account.
+func (s *Service) Credentials() Credentials {
+	return s.credentials
+}
+
+// Reset creates the demo account if needed, restores its password and
+// preferences, and replaces its notes with the sample dataset. It returns the
+// account ID so writes from it can be blocked.
+func (s *Service) Reset(ctx context.Context) (uuid.UUID, error) {
+	hash, err := s.passwordHasher.Hash(s.credentials.Password)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("hashing password: %w", err)
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, s.credentials.Email)
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		user = entity.NewUser(s.credentials.Email, hash, demoName)
+		user.NotePrefix = demoPrefix
+		if err := s.userRepo.Create(ctx, user); err != nil {
+			return uuid.Nil, fmt.Errorf("creating demo user: %w", err)
+		}
+	case err != nil:
+		return uuid.Nil, fmt.Errorf("getting demo user: %w", err)
+	default:
+		user.PasswordHash = hash
+		user.Name = demoName
+		user.SetUnitSystem(valueobject.UnitSystemMetric)
+		user.SetNotePrefix(demoPrefix)
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return uuid.Nil, fmt.Errorf("updating demo user: %w", err)
+		}
+	}
+
+	if err := s.noteRepo.Purge(ctx, user.ID); err != nil {
+		return uuid.Nil, fmt.Errorf("purging demo notes: %w", err)
+	}
+
+	for _, sample := range sampleNotes {
+		note := entity.NewNote(user.ID, sample.title, sample.content, sample.location(), "")
+		note.Measurements = sample.measurements
+		if err := s.noteRepo.Create(ctx, note); err != nil {
+			return uuid.Nil, fmt.Errorf("creating demo note: %w", err)
+		}
+	}
+
+	return user.ID, nil
+}
@@ -0,0 +1,101 @@
+package demo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/auth"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/demo"
+)
+
+var credentials = demo.Credentials{Email: "demo@fieldnotes.app", Password: "demo1234"}
+
+func TestService_Reset(t *testing.T) {
+	t.Run("creates demo user and seeds notes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		hasher := auth.NewPasswordHasher(4)
+		svc := demo.NewService(userRepo, noteRepo, hasher, credentials)
+
+		ctx := context.Background()
+		var created *entity.User
+
+		userRepo.EXPECT().GetByEmail(ctx, credentials.Email).Return(nil, domain.ErrUserNotFound)
+		userRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, u *entity.User) error {
+			created = u
+			return nil
+		})
+		noteRepo.EXPECT().Purge(ctx, gomock.Any()).Return(nil)
+		noteRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, n *entity.Note) error {
+			assert.Equal(t, created.ID, n.UserID)
+			assert.NotNil(t, n.Location)
+			return nil
+		}).MinTimes(1)
+
+		userID, err := svc.Reset(ctx)
+
+		require.NoError(t, err)
+		require.NotNil(t, created)
+		assert.Equal(t, created.ID, userID)
+		assert.Equal(t, "DEMO", created.NotePrefix)
+		assert.NoError(t, hasher.Compare(created.PasswordHash, credentials.Password))
+	})
+
+	t.Run("restores existing demo user and replaces notes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		hasher := auth.NewPasswordHasher(4)
+		svc := demo.NewService(userRepo, noteRepo, hasher, credentials)
+
+		ctx := context.Background()
+		existing := &entity.User{ID: uuid.New(), Email: credentials.Email, PasswordHash: "stale", UnitSystem: "imperial", NotePrefix: "X"}
+
+		userRepo.EXPECT().GetByEmail(ctx, credentials.Email).Return(existing, nil)
+		userRepo.EXPECT().Update(ctx, existing).Return(nil)
+		noteRepo.EXPECT().Purge(ctx, existing.ID).Return(nil)
+		noteRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil).MinTimes(1)
+
+		userID, err := svc.Reset(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, existing.ID, userID)
+		assert.Equal(t, "metric", existing.UnitSystem)
+		assert.Equal(t, "DEMO", existing.NotePrefix)
+		assert.NoError(t, hasher.Compare(existing.PasswordHash, credentials.Password))
+	})
+
+	t.Run("returns error when purge fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := demo.NewService(userRepo, noteRepo, auth.NewPasswordHasher(4), credentials)
+
+		ctx := context.Background()
+		existing := &entity.User{ID: uuid.New(), Email: credentials.Email}
+
+		userRepo.EXPECT().GetByEmail(ctx, credentials.Email).Return(existing, nil)
+		userRepo.EXPECT().Update(ctx, existing).Return(nil)
+		noteRepo.EXPECT().Purge(ctx, existing.ID).Return(assert.AnError)
+
+		userID, err := svc.Reset(ctx)
+
+		assert.Error(t, err)
+		assert.Equal(t, uuid.Nil, userID)
+	})
+}
@@ -0,0 +1,84 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Check reports whether a dependency is reachable. A returned error marks
+// it as down.
+type Check func(ctx context.Context) error
+
+// Status is the result of checking one dependency.
+type Status struct {
+	Name    string
+	Err     error
+	Latency time.Duration
+}
+
+func (s Status) Up() bool {
+	return s.Err == nil
+}
+
+// Report is the result of checking every dependency, in the order they were
+// added.
+type Report struct {
+	Checks []Status
+}
+
+// Ready reports whether every dependency is up.
+func (r Report) Ready() bool {
+	for _, s := range r.Checks {
+		if !s.Up() {
+			return false
+		}
+	}
+	return true
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker runs the dependency checks behind the readiness probe. Checks run
+// concurrently, each bounded by the timeout, so one hanging dependency
+// can't hold the probe past it.
+type Checker struct {
+	timeout time.Duration
+	checks  []namedCheck
+}
+
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Add registers a dependency. It is not safe to call once the checker is
+// serving probes.
+func (c *Checker) Add(name string, check Check) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+func (c *Checker) Check(ctx context.Context) Report {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	statuses := make([]Status, len(c.checks))
+	var wg sync.WaitGroup
+	for i, nc := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			err := nc.check(ctx)
+			statuses[i] = Status{Name: nc.name, Err: err, Latency: time.Since(start)}
+		}()
+	}
+	wg.Wait()
+
+	return Report{Checks: statuses}
+}
@@ -0,0 +1,56 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/health"
+)
+
+func TestChecker_Check(t *testing.T) {
+	t.Run("ready when every dependency is up", func(t *testing.T) {
+		c := health.NewChecker(time.Second)
+		c.Add("postgres", func(context.Context) error { return nil })
+		c.Add("s3", func(context.Context) error { return nil })
+
+		report := c.Check(context.Background())
+
+		assert.True(t, report.Ready())
+		require.Len(t, report.Checks, 2)
+		assert.Equal(t, "postgres", report.Checks[0].Name)
+		assert.Equal(t, "s3", report.Checks[1].Name)
+	})
+
+	t.Run("not ready when a dependency is down", func(t *testing.T) {
+		refused := errors.New("connection refused")
+		c := health.NewChecker(time.Second)
+		c.Add("postgres", func(context.Context) error { return nil })
+		c.Add("redis", func(context.Context) error { return refused })
+
+		report := c.Check(context.Background())
+
+		assert.False(t, report.Ready())
+		assert.True(t, report.Checks[0].Up())
+		assert.ErrorIs(t, report.Checks[1].Err, refused)
+	})
+
+	t.Run("bounds a hanging dependency by the timeout", func(t *testing.T) {
+		c := health.NewChecker(20 * time.Millisecond)
+		c.Add("s3", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		start := time.Now()
+		report := c.Check(context.Background())
+
+		assert.Less(t, time.Since(start), time.Second)
+		assert.False(t, report.Ready())
+		assert.ErrorIs(t, report.Checks[0].Err, context.DeadlineExceeded)
+	})
+}
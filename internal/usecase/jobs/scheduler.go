@@ -0,0 +1,198 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+)
+
+// Func is the work of one job run. A returned error marks the run as failed.
+type Func func(ctx context.Context) error
+
+// Run is one finished execution of a job.
+type Run struct {
+	Job        string
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Manual     bool
+	Err        string
+}
+
+func (r Run) Failed() bool {
+	return r.Err != ""
+}
+
+func (r Run) Duration() time.Duration {
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// Status is the state of a job at the time it was read.
+type Status struct {
+	Name     string
+	Interval time.Duration
+	Running  bool
+	LastRun  *Run
+	NextRun  time.Time
+	// Failures is the number of consecutive failed runs, reset by a
+	// successful one.
+	Failures int
+}
+
+type job struct {
+	name     string
+	interval time.Duration
+	fn       Func
+
+	running  bool
+	lastRun  *Run
+	nextRun  time.Time
+	failures int
+}
+
+// Scheduler runs the periodic maintenance jobs and keeps the history of
+// their recent runs in memory. A job never overlaps with itself: a tick that
+// finds the previous run still going is skipped.
+type Scheduler struct {
+	historySize int
+
+	mu      sync.Mutex
+	ctx     context.Context
+	jobs    []*job
+	history []Run
+}
+
+func NewScheduler(historySize int) *Scheduler {
+	return &Scheduler{historySize: historySize, ctx: context.Background()}
+}
+
+// Add registers a job. It must be called before Start.
+func (s *Scheduler) Add(name string, interval time.Duration, fn Func) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, &job{name: name, interval: interval, fn: fn})
+}
+
+// Start runs every job on its interval until ctx is done. Manual runs also
+// use ctx.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	s.ctx = ctx
+	jobs := s.jobs
+	for _, j := range jobs {
+		j.nextRun = time.Now().Add(j.interval)
+	}
+	s.mu.Unlock()
+
+	for _, j := range jobs {
+		go s.loop(ctx, j)
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.mu.Lock()
+			j.nextRun = time.Now().Add(j.interval)
+			started := s.begin(j)
+			s.mu.Unlock()
+			if started {
+				s.run(ctx, j, false)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// RunNow starts a run of the named job in the background, outside its
+// schedule.
+func (s *Scheduler) RunNow(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, j := range s.jobs {
+		if j.name != name {
+			continue
+		}
+		if !s.begin(j) {
+			return domain.ErrJobRunning
+		}
+		go s.run(s.ctx, j, true)
+		return nil
+	}
+	return domain.ErrJobNotFound
+}
+
+// begin marks j as running unless it already is. s.mu must be held.
+func (s *Scheduler) begin(j *job) bool {
+	if j.running {
+		return false
+	}
+	j.running = true
+	return true
+}
+
+func (s *Scheduler) run(ctx context.Context, j *job, manual bool) {
+	run := Run{Job: j.name, StartedAt: time.Now(), Manual: manual}
+	err := j.fn(ctx)
+	run.FinishedAt = time.Now()
+	if err != nil {
+		run.Err = err.Error()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j.running = false
+	j.lastRun = &run
+	if run.Failed() {
+		j.failures++
+	} else {
+		j.failures = 0
+	}
+
+	s.history = append(s.history, run)
+	if len(s.history) > s.historySize {
+		s.history = s.history[len(s.history)-s.historySize:]
+	}
+}
+
+// Jobs returns the status of every job in the order they were added.
+func (s *Scheduler) Jobs() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]Status, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		status := Status{
+			Name:     j.name,
+			Interval: j.interval,
+			Running:  j.running,
+			NextRun:  j.nextRun,
+			Failures: j.failures,
+		}
+		if j.lastRun != nil {
+			last := *j.lastRun
+			status.LastRun = &last
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// History returns the recent runs of all jobs, newest first.
+func (s *Scheduler) History() []Run {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	runs := make([]Run, len(s.history))
+	for i, run := range s.history {
+		runs[len(runs)-1-i] = run
+	}
+	return runs
+}
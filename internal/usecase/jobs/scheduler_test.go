@@ -0,0 +1,125 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/jobs"
+)
+
+// waitIdle waits for the manual run of the only job to finish.
+func waitIdle(t *testing.T, s *jobs.Scheduler) jobs.Status {
+	t.Helper()
+	var status jobs.Status
+	require.Eventually(t, func() bool {
+		status = s.Jobs()[0]
+		return !status.Running && status.LastRun != nil
+	}, time.Second, time.Millisecond)
+	return status
+}
+
+func TestScheduler_RunNow(t *testing.T) {
+	t.Run("records successful run", func(t *testing.T) {
+		s := jobs.NewScheduler(10)
+		s.Add("flush", time.Hour, func(context.Context) error { return nil })
+
+		require.NoError(t, s.RunNow("flush"))
+		status := waitIdle(t, s)
+
+		assert.False(t, status.LastRun.Failed())
+		assert.True(t, status.LastRun.Manual)
+		assert.Zero(t, status.Failures)
+		require.Len(t, s.History(), 1)
+		assert.Equal(t, "flush", s.History()[0].Job)
+	})
+
+	t.Run("records failure and counts consecutive ones", func(t *testing.T) {
+		s := jobs.NewScheduler(10)
+		s.Add("reconcile", time.Hour, func(context.Context) error { return errors.New("db down") })
+
+		require.NoError(t, s.RunNow("reconcile"))
+		waitIdle(t, s)
+		require.NoError(t, s.RunNow("reconcile"))
+		require.Eventually(t, func() bool { return len(s.History()) == 2 }, time.Second, time.Millisecond)
+
+		status := s.Jobs()[0]
+		assert.Equal(t, 2, status.Failures)
+		assert.Equal(t, "db down", status.LastRun.Err)
+	})
+
+	t.Run("does not overlap a running job", func(t *testing.T) {
+		release := make(chan struct{})
+		s := jobs.NewScheduler(10)
+		s.Add("slow", time.Hour, func(context.Context) error {
+			<-release
+			return nil
+		})
+
+		require.NoError(t, s.RunNow("slow"))
+		assert.ErrorIs(t, s.RunNow("slow"), domain.ErrJobRunning)
+
+		close(release)
+		waitIdle(t, s)
+	})
+
+	t.Run("returns error for unknown job", func(t *testing.T) {
+		s := jobs.NewScheduler(10)
+
+		assert.ErrorIs(t, s.RunNow("missing"), domain.ErrJobNotFound)
+	})
+}
+
+func TestScheduler_History(t *testing.T) {
+	s := jobs.NewScheduler(2)
+	calls := 0
+	s.Add("job", time.Hour, func(context.Context) error {
+		calls++
+		if calls == 3 {
+			return errors.New("third")
+		}
+		return nil
+	})
+
+	for i := range 3 {
+		require.NoError(t, s.RunNow("job"))
+		require.Eventually(t, func() bool {
+			status := s.Jobs()[0]
+			return !status.Running && len(s.History()) == min(i+1, 2)
+		}, time.Second, time.Millisecond)
+	}
+
+	history := s.History()
+	require.Len(t, history, 2, "keeps only the newest runs")
+	assert.Equal(t, "third", history[0].Err, "newest first")
+	assert.False(t, history[1].Failed())
+}
+
+func TestScheduler_Start(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ran := make(chan struct{}, 1)
+	s := jobs.NewScheduler(10)
+	s.Add("tick", 10*time.Millisecond, func(context.Context) error {
+		select {
+		case ran <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+
+	s.Start(ctx)
+
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("job did not run on schedule")
+	}
+	assert.False(t, s.Jobs()[0].NextRun.IsZero())
+}
@@ -0,0 +1,117 @@
+package kpi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+type eventKey struct {
+	day  time.Time
+	name string
+}
+
+// Service counts the KPI events in memory, persists them on Flush and
+// computes the daily reports from the totals of every instance.
+type Service struct {
+	kpiRepo repository.KPIRepository
+
+	mu      sync.Mutex
+	pending map[eventKey]int64
+}
+
+func NewService(kpiRepo repository.KPIRepository) *Service {
+	return &Service{
+		kpiRepo: kpiRepo,
+		pending: make(map[eventKey]int64),
+	}
+}
+
+// Record counts n occurrences of the event today.
+func (s *Service) Record(event string, n int64) {
+	if n <= 0 {
+		return
+	}
+	key := eventKey{day: entity.UsageDay(time.Now()), name: event}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending[key] += n
+}
+
+// Flush writes the accumulated counts. On a write failure the counts not
+// written are kept for the next flush.
+func (s *Service) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	batch := s.pending
+	s.pending = make(map[eventKey]int64)
+	s.mu.Unlock()
+
+	days := make(map[time.Time]map[string]int64)
+	for key, n := range batch {
+		if days[key.day] == nil {
+			days[key.day] = make(map[string]int64)
+		}
+		days[key.day][key.name] = n
+	}
+
+	for day, counts := range days {
+		if err := s.kpiRepo.IncrementEvents(ctx, day, counts); err != nil {
+			s.requeue(days)
+			return fmt.Errorf("persisting kpi events: %w", err)
+		}
+		delete(days, day)
+	}
+	return nil
+}
+
+func (s *Service) requeue(days map[time.Time]map[string]int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for day, counts := range days {
+		for name, n := range counts {
+			s.pending[eventKey{day: day, name: name}] += n
+		}
+	}
+}
+
+// Compute computes and stores the report of the day now falls on, and of
+// the day before so its last hours are counted once it has ended.
+func (s *Service) Compute(ctx context.Context, now time.Time) (*entity.KPIReport, error) {
+	today := entity.UsageDay(now)
+
+	var report entity.KPIReport
+	for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+		events, err := s.kpiRepo.Events(ctx, day)
+		if err != nil {
+			return nil, fmt.Errorf("reading kpi events: %w", err)
+		}
+		activeUsers, err := s.kpiRepo.ActiveUsers(ctx, day)
+		if err != nil {
+			return nil, fmt.Errorf("counting active users: %w", err)
+		}
+
+		report = entity.NewKPIReport(day, activeUsers, events)
+		report.ComputedAt = now.UTC()
+		if err := s.kpiRepo.SaveReport(ctx, report); err != nil {
+			return nil, fmt.Errorf("saving kpi report: %w", err)
+		}
+	}
+	return &report, nil
+}
+
+// Reports returns the computed reports of the last days days, today
+// included, oldest first. Days never computed are missing.
+func (s *Service) Reports(ctx context.Context, days int) ([]entity.KPIReport, error) {
+	to := entity.UsageDay(time.Now())
+	reports, err := s.kpiRepo.ListReports(ctx, to.AddDate(0, 0, -(days-1)), to)
+	if err != nil {
+		return nil, fmt.Errorf("listing kpi reports: %w", err)
+	}
+	return reports, nil
+}
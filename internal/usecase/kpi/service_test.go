@@ -0,0 +1,96 @@
+package kpi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/kpi"
+)
+
+func TestService_Flush(t *testing.T) {
+	t.Run("writes the counts of the day", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		kpiRepo := mocks.NewMockKPIRepository(ctrl)
+		svc := kpi.NewService(kpiRepo)
+
+		ctx := context.Background()
+		svc.Record(entity.KPISyncs, 1)
+		svc.Record(entity.KPISyncs, 1)
+		svc.Record(entity.KPINotesPushed, 5)
+		svc.Record(entity.KPIConflicts, 0)
+
+		kpiRepo.EXPECT().IncrementEvents(ctx, entity.UsageDay(time.Now()), map[string]int64{
+			entity.KPISyncs:       2,
+			entity.KPINotesPushed: 5,
+		}).Return(nil)
+		require.NoError(t, svc.Flush(ctx))
+
+		// Nothing left to write.
+		require.NoError(t, svc.Flush(ctx))
+	})
+
+	t.Run("keeps counts for the next flush when the write fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		kpiRepo := mocks.NewMockKPIRepository(ctrl)
+		svc := kpi.NewService(kpiRepo)
+
+		ctx := context.Background()
+		svc.Record(entity.KPIPhotoUploads, 1)
+
+		kpiRepo.EXPECT().IncrementEvents(ctx, gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+		require.Error(t, svc.Flush(ctx))
+
+		svc.Record(entity.KPIPhotoUploads, 1)
+		kpiRepo.EXPECT().IncrementEvents(ctx, gomock.Any(), map[string]int64{entity.KPIPhotoUploads: 2}).Return(nil)
+		require.NoError(t, svc.Flush(ctx))
+	})
+}
+
+func TestService_Compute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	kpiRepo := mocks.NewMockKPIRepository(ctrl)
+	svc := kpi.NewService(kpiRepo)
+
+	ctx := context.Background()
+	now := time.Date(2026, 6, 2, 0, 3, 0, 0, time.UTC)
+	yesterday := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	today := time.Date(2026, 6, 2, 0, 0, 0, 0, time.UTC)
+
+	kpiRepo.EXPECT().Events(ctx, yesterday).Return(map[string]int64{
+		entity.KPISyncs: 200, entity.KPISyncFailures: 10, entity.KPINotesPushed: 400, entity.KPIConflicts: 8,
+	}, nil)
+	kpiRepo.EXPECT().ActiveUsers(ctx, yesterday).Return(int64(42), nil)
+	kpiRepo.EXPECT().SaveReport(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, r entity.KPIReport) error {
+		assert.Equal(t, yesterday, r.Day)
+		assert.Equal(t, int64(42), r.ActiveUsers)
+		assert.InDelta(t, 0.95, r.SyncSuccessRate(), 1e-9)
+		assert.InDelta(t, 0.02, r.ConflictRate(), 1e-9)
+		return nil
+	})
+	kpiRepo.EXPECT().Events(ctx, today).Return(map[string]int64{}, nil)
+	kpiRepo.EXPECT().ActiveUsers(ctx, today).Return(int64(3), nil)
+	kpiRepo.EXPECT().SaveReport(ctx, gomock.Any()).Return(nil)
+
+	report, err := svc.Compute(ctx, now)
+
+	require.NoError(t, err)
+	assert.Equal(t, today, report.Day)
+	assert.Equal(t, int64(3), report.ActiveUsers)
+	assert.Equal(t, float64(1), report.SyncSuccessRate())
+	assert.Zero(t, report.PhotoUploadFailureRate())
+	assert.Equal(t, now, report.ComputedAt)
+}
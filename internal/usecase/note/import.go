@@ -0,0 +1,204 @@
+package note
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+)
+
+const (
+	// MaxImportRows caps the notes of a single import.
+	MaxImportRows = 5000
+	// importBatchSize is how many notes each upsert transaction saves.
+	importBatchSize = 500
+	// maxTitleLength and maxClientIDLength match the notes table columns.
+	maxTitleLength    = 255
+	maxClientIDLength = 36
+)
+
+// Import row outcomes.
+const (
+	ImportCreated = "created"
+	ImportSkipped = "skipped"
+	ImportFailed  = "failed"
+)
+
+// ImportRow is one note read from an imported file.
+type ImportRow struct {
+	// Row is the row's position in the file, starting at 1, for the summary.
+	Row       int
+	ClientID  string
+	Title     string
+	Content   string
+	Latitude  *float64
+	Longitude *float64
+	Altitude  *float64
+	Accuracy  *float64
+	Tags      []string
+	// CreatedAt keeps the note's original creation time; now when nil.
+	CreatedAt *time.Time
+	// Err is set when the row could not be read from the file.
+	Err error
+}
+
+type ImportInput struct {
+	UserID uuid.UUID
+	// Format and Filename are recorded in each note's source_meta.
+	Format   string
+	Filename string
+	Rows     []ImportRow
+}
+
+// ImportResult is the outcome of one row: the note created, a row skipped
+// because its client ID was imported before, or the reason it failed.
+type ImportResult struct {
+	Row      int
+	ClientID string
+	Status   string
+	NoteID   uuid.UUID
+	Err      error
+}
+
+// Import creates a note with source import for each valid row, in batches.
+// Rows are deduplicated by client ID: later rows repeating one in the file
+// fail, and rows whose client ID the user already has are skipped, so
+// importing the same file again creates nothing. Rows without a client ID
+// get a new one. The results follow the order of the rows; an error is
+// returned only when saving fails, after the batches before it were saved.
+func (s *Service) Import(ctx context.Context, input ImportInput) ([]ImportResult, error) {
+	results := make([]ImportResult, len(input.Rows))
+	seen := make(map[string]bool, len(input.Rows))
+	var clientIDs []string
+	for i, row := range input.Rows {
+		results[i] = ImportResult{Row: row.Row, ClientID: row.ClientID}
+		switch {
+		case row.Err != nil:
+			results[i].Err = row.Err
+		case row.ClientID == "":
+			results[i].ClientID = uuid.NewString()
+		case seen[row.ClientID]:
+			results[i].Err = domain.ErrDuplicateClientID
+		case utf8.RuneCountInString(row.ClientID) > maxClientIDLength:
+			results[i].Err = domain.ErrInvalidClientID
+		default:
+			clientIDs = append(clientIDs, row.ClientID)
+		}
+		seen[row.ClientID] = true
+	}
+
+	if len(clientIDs) > 0 {
+		existing, err := s.noteRepo.GetByClientIDs(ctx, input.UserID, clientIDs)
+		if err != nil {
+			return nil, fmt.Errorf("getting notes by client id: %w", err)
+		}
+		imported := make(map[string]uuid.UUID, len(existing))
+		for _, n := range existing {
+			imported[n.ClientID] = n.ID
+		}
+		for i := range results {
+			if id, ok := imported[results[i].ClientID]; ok && results[i].Err == nil {
+				results[i].Status = ImportSkipped
+				results[i].NoteID = id
+			}
+		}
+	}
+
+	rules, err := s.ruleRepo.GetByUserID(ctx, input.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("getting quality rules: %w", err)
+	}
+
+	meta := map[string]any{"format": input.Format}
+	if input.Filename != "" {
+		meta["filename"] = input.Filename
+	}
+
+	var batch []entity.Note
+	var batchRows []int
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := s.noteRepo.BatchUpsert(ctx, batch); err != nil {
+			return fmt.Errorf("importing notes: %w", err)
+		}
+		for j, i := range batchRows {
+			results[i].Status = ImportCreated
+			results[i].NoteID = batch[j].ID
+		}
+		batch, batchRows = nil, nil
+		return nil
+	}
+
+	for i, row := range input.Rows {
+		if results[i].Status == ImportSkipped {
+			continue
+		}
+		if results[i].Err == nil {
+			var n *entity.Note
+			n, results[i].Err = importedNote(input.UserID, results[i].ClientID, row)
+			if results[i].Err == nil {
+				n.SourceMeta = meta
+				n.Quality = rules.Evaluate(n, 0)
+				batch = append(batch, *n)
+				batchRows = append(batchRows, i)
+			}
+		}
+		if results[i].Err != nil {
+			results[i].Status = ImportFailed
+			continue
+		}
+		if len(batch) == importBatchSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// importedNote validates the row and builds its note.
+func importedNote(userID uuid.UUID, clientID string, row ImportRow) (*entity.Note, error) {
+	if row.Title == "" || utf8.RuneCountInString(row.Title) > maxTitleLength {
+		return nil, domain.ErrInvalidTitle
+	}
+
+	var loc *valueobject.Location
+	if row.Latitude != nil || row.Longitude != nil {
+		if row.Latitude == nil || row.Longitude == nil {
+			return nil, domain.ErrInvalidLocation
+		}
+		loc = valueobject.NewLocation(*row.Latitude, *row.Longitude, row.Altitude, row.Accuracy)
+		if !loc.IsValid() || (row.Accuracy != nil && *row.Accuracy < 0) {
+			return nil, domain.ErrInvalidLocation
+		}
+	}
+
+	n := entity.NewNote(userID, row.Title, row.Content, loc, clientID)
+	n.Source = entity.NoteSourceImport
+	if row.CreatedAt != nil {
+		n.CreatedAt = row.CreatedAt.UTC()
+	}
+	if len(row.Tags) > 0 {
+		tags, ok := entity.NormalizeTags(row.Tags)
+		if !ok {
+			return nil, domain.ErrInvalidTag
+		}
+		if len(tags) > entity.MaxNoteTags {
+			return nil, domain.ErrTooManyTags
+		}
+		n.Tags = tags
+	}
+	n.Sanitize()
+	return n, nil
+}
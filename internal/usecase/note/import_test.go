@@ -0,0 +1,130 @@
+package note_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/note"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestService_Import(t *testing.T) {
+	setup := func(t *testing.T) (*note.Service, *mocks.MockNoteRepository, *mocks.MockQualityRuleRepository) {
+		ctrl := gomock.NewController(t)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, mocks.NewMockPhotoRepository(ctrl), ruleRepo, ownerOnly(ctrl), nil, nil, nil)
+		return svc, noteRepo, ruleRepo
+	}
+
+	t.Run("creates valid rows and reports the others", func(t *testing.T) {
+		svc, noteRepo, ruleRepo := setup(t)
+		ctx := context.Background()
+		userID := uuid.New()
+		existingID := uuid.New()
+		createdAt := time.Date(2023, 5, 1, 9, 0, 0, 0, time.UTC)
+
+		noteRepo.EXPECT().GetByClientIDs(ctx, userID, []string{"a", "b"}).
+			Return([]entity.Note{{ID: existingID, ClientID: "b"}}, nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil)
+
+		var saved []entity.Note
+		noteRepo.EXPECT().BatchUpsert(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, notes []entity.Note) error {
+			saved = append(saved, notes...)
+			return nil
+		})
+
+		results, err := svc.Import(ctx, note.ImportInput{
+			UserID:   userID,
+			Format:   "csv",
+			Filename: "plots.csv",
+			Rows: []note.ImportRow{
+				{Row: 1, ClientID: "a", Title: "Plot A", Latitude: ptr(38.7), Longitude: ptr(-9.1), Tags: []string{"Soil"}, CreatedAt: &createdAt},
+				{Row: 2, ClientID: "b", Title: "Plot B"},
+				{Row: 3, ClientID: "a", Title: "Plot A again"},
+				{Row: 4, Title: "Bad latitude", Latitude: ptr(91.0), Longitude: ptr(0.0)},
+				{Row: 5, Title: "Only latitude", Latitude: ptr(10.0)},
+				{Row: 6, Title: ""},
+				{Row: 7, Err: errors.New("latitude must be a number")},
+				{Row: 8, Title: "No client id", Tags: []string{"bad tag!"}},
+				{Row: 9, Title: "No location"},
+			},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, results, 9)
+
+		assert.Equal(t, note.ImportCreated, results[0].Status)
+		assert.Equal(t, note.ImportSkipped, results[1].Status)
+		assert.Equal(t, existingID, results[1].NoteID)
+		assert.ErrorIs(t, results[2].Err, domain.ErrDuplicateClientID)
+		assert.ErrorIs(t, results[3].Err, domain.ErrInvalidLocation)
+		assert.ErrorIs(t, results[4].Err, domain.ErrInvalidLocation)
+		assert.ErrorIs(t, results[5].Err, domain.ErrInvalidTitle)
+		assert.EqualError(t, results[6].Err, "latitude must be a number")
+		assert.ErrorIs(t, results[7].Err, domain.ErrInvalidTag)
+		for _, r := range results[2:8] {
+			assert.Equal(t, note.ImportFailed, r.Status, "row %d", r.Row)
+		}
+		assert.Equal(t, note.ImportCreated, results[8].Status)
+		assert.NotEmpty(t, results[8].ClientID, "rows without a client id get one")
+
+		require.Len(t, saved, 2)
+		assert.Equal(t, results[0].NoteID, saved[0].ID)
+		assert.Equal(t, entity.NoteSourceImport, saved[0].Source)
+		assert.Equal(t, map[string]any{"format": "csv", "filename": "plots.csv"}, saved[0].SourceMeta)
+		assert.Equal(t, []string{"soil"}, saved[0].Tags)
+		assert.Equal(t, createdAt, saved[0].CreatedAt)
+		require.NotNil(t, saved[0].Location)
+		assert.Equal(t, -9.1, saved[0].Location.Longitude)
+		assert.Nil(t, saved[1].Location)
+	})
+
+	t.Run("saves in batches", func(t *testing.T) {
+		svc, noteRepo, ruleRepo := setup(t)
+		ctx := context.Background()
+
+		rows := make([]note.ImportRow, 1200)
+		for i := range rows {
+			rows[i] = note.ImportRow{Row: i + 1, Title: "Plot"}
+		}
+
+		ruleRepo.EXPECT().GetByUserID(ctx, gomock.Any()).Return(&entity.QualityRules{}, nil)
+		var sizes []int
+		noteRepo.EXPECT().BatchUpsert(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, notes []entity.Note) error {
+			sizes = append(sizes, len(notes))
+			return nil
+		}).Times(3)
+
+		results, err := svc.Import(ctx, note.ImportInput{UserID: uuid.New(), Format: "geojson", Rows: rows})
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{500, 500, 200}, sizes)
+		assert.Equal(t, note.ImportCreated, results[1199].Status)
+	})
+
+	t.Run("returns save errors", func(t *testing.T) {
+		svc, noteRepo, ruleRepo := setup(t)
+		ctx := context.Background()
+
+		ruleRepo.EXPECT().GetByUserID(ctx, gomock.Any()).Return(&entity.QualityRules{}, nil)
+		noteRepo.EXPECT().BatchUpsert(ctx, gomock.Any()).Return(errors.New("db down"))
+
+		_, err := svc.Import(ctx, note.ImportInput{UserID: uuid.New(), Rows: []note.ImportRow{{Row: 1, Title: "Plot"}}})
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,111 @@
+package note
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/authz"
+)
+
+const (
+	defaultRevisionsLimit = 20
+	maxRevisionsLimit     = 100
+)
+
+type RevisionsInput struct {
+	UserID uuid.UUID
+	NoteID uuid.UUID
+	// Before lists the revisions numbered below it; 0 lists the latest.
+	Before int
+	// Limit defaults to 20 and is capped at 100.
+	Limit int
+}
+
+// Revisions returns the versions of the note that updates replaced, newest
+// first, to anyone who can read the note.
+func (s *Service) Revisions(ctx context.Context, input RevisionsInput) ([]entity.NoteRevision, error) {
+	note, err := s.noteRepo.GetByID(ctx, input.NoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizer.Authorize(ctx, input.UserID, authz.ActionRead, note); err != nil {
+		return nil, err
+	}
+
+	if note.IsDeleted() {
+		return nil, domain.ErrNoteNotFound
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultRevisionsLimit
+	}
+	limit = min(limit, maxRevisionsLimit)
+
+	revisions, err := s.noteRepo.Revisions(ctx, note.ID, max(input.Before, 0), limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing revisions: %w", err)
+	}
+	for i := range revisions {
+		revisions[i].Sensitivity = note.Sensitivity
+	}
+	return revisions, nil
+}
+
+type RestoreRevisionInput struct {
+	UserID   uuid.UUID
+	NoteID   uuid.UUID
+	Revision int
+	DeviceID string
+}
+
+// RestoreRevision puts the title, content, location, measurements, color
+// and icon of a revision back on the note. It goes through Update, so the
+// same permissions apply and the version it replaces is kept as a revision
+// in turn. A revision without a location leaves the current one.
+func (s *Service) RestoreRevision(ctx context.Context, input RestoreRevisionInput) (*entity.Note, error) {
+	note, err := s.noteRepo.GetByID(ctx, input.NoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizer.Authorize(ctx, input.UserID, authz.ActionEdit, note); err != nil {
+		return nil, err
+	}
+
+	rev, err := s.noteRepo.Revision(ctx, note.ID, input.Revision)
+	if err != nil {
+		return nil, err
+	}
+
+	update := UpdateInput{
+		Title:         &rev.Title,
+		Content:       &rev.Content,
+		Measurements:  append([]valueobject.Measurement{}, rev.Measurements...),
+		Color:         styleOrEmpty(rev.Color),
+		Icon:          styleOrEmpty(rev.Icon),
+		DeviceID:      input.DeviceID,
+		SkipAutoTitle: true,
+	}
+	// Only a move needs the permission to move a sensitive note.
+	if rev.Location != nil && !rev.Location.Equal(note.Location) {
+		update.Location = rev.Location
+	}
+	return s.Update(ctx, input.UserID, note.ID, update)
+}
+
+// styleOrEmpty returns a color or icon for UpdateInput, where empty takes
+// it off.
+func styleOrEmpty(name *string) *string {
+	if name == nil {
+		empty := ""
+		return &empty
+	}
+	return name
+}
@@ -0,0 +1,142 @@
+package note_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/note"
+)
+
+func TestService_Revisions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("lists the revisions with the note's sensitivity", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := note.NewService(noteRepo, nil, nil, ownerOnly(ctrl), nil, nil, nil)
+
+		userID := uuid.New()
+		n := &entity.Note{ID: uuid.New(), UserID: userID, Sensitivity: entity.SensitivityHigh}
+		noteRepo.EXPECT().GetByID(ctx, n.ID).Return(n, nil)
+		noteRepo.EXPECT().Revisions(ctx, n.ID, 5, 20).Return([]entity.NoteRevision{
+			{NoteID: n.ID, Revision: 4, Title: "Before"},
+			{NoteID: n.ID, Revision: 3, Title: "Earlier"},
+		}, nil)
+
+		revisions, err := svc.Revisions(ctx, note.RevisionsInput{UserID: userID, NoteID: n.ID, Before: 5})
+
+		require.NoError(t, err)
+		require.Len(t, revisions, 2)
+		assert.Equal(t, 4, revisions[0].Revision)
+		assert.Equal(t, entity.SensitivityHigh, revisions[1].Sensitivity)
+	})
+
+	t.Run("caps the limit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := note.NewService(noteRepo, nil, nil, ownerOnly(ctrl), nil, nil, nil)
+
+		userID := uuid.New()
+		n := &entity.Note{ID: uuid.New(), UserID: userID}
+		noteRepo.EXPECT().GetByID(ctx, n.ID).Return(n, nil)
+		noteRepo.EXPECT().Revisions(ctx, n.ID, 0, 100).Return(nil, nil)
+
+		_, err := svc.Revisions(ctx, note.RevisionsInput{UserID: userID, NoteID: n.ID, Limit: 500})
+		require.NoError(t, err)
+	})
+
+	t.Run("returns forbidden to users without access", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := note.NewService(noteRepo, nil, nil, ownerOnly(ctrl), nil, nil, nil)
+
+		n := &entity.Note{ID: uuid.New(), UserID: uuid.New()}
+		noteRepo.EXPECT().GetByID(ctx, n.ID).Return(n, nil)
+
+		_, err := svc.Revisions(ctx, note.RevisionsInput{UserID: uuid.New(), NoteID: n.ID})
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+}
+
+func TestService_RestoreRevision(t *testing.T) {
+	ctx := context.Background()
+	green := "green"
+
+	t.Run("puts the revision's fields back through an update", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, ruleRepo, ownerOnly(ctrl), nil, nil, nil)
+
+		userID := uuid.New()
+		noteID := uuid.New()
+		current := func() *entity.Note {
+			return &entity.Note{
+				ID: noteID, UserID: userID, Title: "Synced over", Content: "Lost edit", Color: &green,
+				Location:     valueobject.NewLocation(-23.5, -46.6, nil, nil),
+				Measurements: []valueobject.Measurement{{Name: "dbh", Kind: "length", Value: 0.3}},
+			}
+		}
+		noteRepo.EXPECT().GetByID(ctx, noteID).DoAndReturn(func(context.Context, uuid.UUID) (*entity.Note, error) {
+			return current(), nil
+		}).Times(2)
+		noteRepo.EXPECT().Revision(ctx, noteID, 2).Return(&entity.NoteRevision{
+			NoteID: noteID, UserID: userID, Revision: 2, Title: "Oak survey", Content: "Three oaks by the creek",
+			Location: valueobject.NewLocation(-22.9, -43.2, nil, nil),
+		}, nil)
+		photoRepo.EXPECT().GetByNoteID(ctx, noteID).Return(nil, nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil)
+		var saved *entity.Note
+		noteRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, n *entity.Note) error {
+			saved = n
+			return nil
+		})
+
+		result, err := svc.RestoreRevision(ctx, note.RestoreRevisionInput{UserID: userID, NoteID: noteID, Revision: 2, DeviceID: "device-a"})
+
+		require.NoError(t, err)
+		require.NotNil(t, saved)
+		assert.Equal(t, "Oak survey", result.Title)
+		assert.Equal(t, "Three oaks by the creek", result.Content)
+		assert.InDelta(t, -22.9, result.Location.Latitude, 1e-9)
+		assert.Empty(t, result.Measurements)
+		assert.Nil(t, result.Color)
+		assert.Equal(t, "device-a", result.LastModifiedByDevice)
+	})
+
+	t.Run("returns the missing revision", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := note.NewService(noteRepo, nil, nil, ownerOnly(ctrl), nil, nil, nil)
+
+		userID := uuid.New()
+		n := &entity.Note{ID: uuid.New(), UserID: userID}
+		noteRepo.EXPECT().GetByID(ctx, n.ID).Return(n, nil)
+		noteRepo.EXPECT().Revision(ctx, n.ID, 9).Return(nil, domain.ErrRevisionNotFound)
+
+		_, err := svc.RestoreRevision(ctx, note.RestoreRevisionInput{UserID: userID, NoteID: n.ID, Revision: 9})
+		assert.ErrorIs(t, err, domain.ErrRevisionNotFound)
+	})
+
+	t.Run("checks access before reading the revision", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := note.NewService(noteRepo, nil, nil, ownerOnly(ctrl), nil, nil, nil)
+
+		n := &entity.Note{ID: uuid.New(), UserID: uuid.New()}
+		noteRepo.EXPECT().GetByID(ctx, n.ID).Return(n, nil)
+
+		_, err := svc.RestoreRevision(ctx, note.RestoreRevisionInput{UserID: uuid.New(), NoteID: n.ID, Revision: 1})
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+}
@@ -2,7 +2,12 @@ package note
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -10,30 +15,81 @@ import (
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/clock"
 	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/audit"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/authz"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/autotitle"
 )
 
 type Service struct {
-	noteRepo  repository.NoteRepository
-	photoRepo repository.PhotoRepository
+	noteRepo      repository.NoteRepository
+	photoRepo     repository.PhotoRepository
+	ruleRepo      repository.QualityRuleRepository
+	authorizer    *authz.Authorizer
+	auditRecorder *audit.Recorder
+	titler        *autotitle.Titler
+	clock         clock.Clock
 }
 
-func NewService(noteRepo repository.NoteRepository, photoRepo repository.PhotoRepository) *Service {
+// NewService creates the note service. titler may be nil, in which case
+// notes keep the titles they are given. clk stamps created, updated and
+// restored notes; nil means the system clock.
+func NewService(
+	noteRepo repository.NoteRepository,
+	photoRepo repository.PhotoRepository,
+	ruleRepo repository.QualityRuleRepository,
+	authorizer *authz.Authorizer,
+	auditRecorder *audit.Recorder,
+	titler *autotitle.Titler,
+	clk clock.Clock,
+) *Service {
+	if clk == nil {
+		clk = clock.System{}
+	}
 	return &Service{
-		noteRepo:  noteRepo,
-		photoRepo: photoRepo,
+		noteRepo:      noteRepo,
+		photoRepo:     photoRepo,
+		ruleRepo:      ruleRepo,
+		authorizer:    authorizer,
+		auditRecorder: auditRecorder,
+		titler:        titler,
+		clock:         clk,
 	}
 }
 
 type CreateInput struct {
-	UserID   uuid.UUID
-	Title    string
-	Content  string
-	Location *valueobject.Location
-	ClientID string
+	UserID       uuid.UUID
+	Title        string
+	Content      string
+	Location     *valueobject.Location
+	Measurements []valueobject.Measurement
+	ClientID     string
+	DeviceID     string
+	// Sensitivity defaults to entity.SensitivityNone when empty.
+	Sensitivity string
+	// Source is entity.NoteSourceManual, the default, or the
+	// entity.IntegrationSource of the integration creating the note.
+	Source     string
+	SourceMeta map[string]any
+	// TeamID creates the note for a team the user is a member of.
+	TeamID *uuid.UUID
+	// Color and Icon are names from entity.NoteColors and entity.NoteIcons;
+	// empty leaves the note without.
+	Color string
+	Icon  string
+	// SkipAutoTitle keeps an empty or placeholder title rather than
+	// generating one from the content.
+	SkipAutoTitle bool
 }
 
 func (s *Service) Create(ctx context.Context, input CreateInput) (*entity.Note, error) {
+	if input.TeamID != nil {
+		if err := s.authorizer.AuthorizeTeam(ctx, input.UserID, *input.TeamID, authz.ActionEdit); err != nil {
+			return nil, err
+		}
+	}
+
 	if input.ClientID != "" {
 		existing, err := s.noteRepo.GetByClientID(ctx, input.UserID, input.ClientID)
 		if err == nil && existing != nil {
@@ -41,27 +97,183 @@ func (s *Service) Create(ctx context.Context, input CreateInput) (*entity.Note,
 		}
 	}
 
-	note := entity.NewNote(input.UserID, input.Title, input.Content, input.Location, input.ClientID)
+	note := entity.NewNoteAt(input.UserID, input.Title, input.Content, input.Location, input.ClientID, s.clock.Now())
+	note.Measurements = input.Measurements
+	note.TeamID = input.TeamID
+	if input.Sensitivity != "" {
+		if !entity.IsSensitivity(input.Sensitivity) {
+			return nil, domain.ErrInvalidSensitivity
+		}
+		note.Sensitivity = input.Sensitivity
+	}
+	if err := setSource(note, input.Source, input.SourceMeta); err != nil {
+		return nil, err
+	}
+	if err := setStyle(note, &input.Color, &input.Icon); err != nil {
+		return nil, err
+	}
+	if !input.SkipAutoTitle {
+		s.titler.Title(ctx, note)
+	}
+	note.SetOriginDevice(input.DeviceID)
+	note.Sanitize()
+
+	if err := s.evaluateQuality(ctx, note); err != nil {
+		return nil, err
+	}
 
 	if err := s.noteRepo.Create(ctx, note); err != nil {
+		// A retry of the same create raced this one past the client_id
+		// lookup; return the note it stored.
+		if errors.Is(err, domain.ErrNoteAlreadyExists) && input.ClientID != "" {
+			return s.noteRepo.GetByClientID(ctx, input.UserID, input.ClientID)
+		}
 		return nil, fmt.Errorf("creating note: %w", err)
 	}
 
 	return note, nil
 }
 
+// setSource records where a note created through the API came from. Sync
+// and import set their own sources, so clients can't claim them.
+func setSource(note *entity.Note, source string, meta map[string]any) error {
+	if source != "" {
+		if source != entity.NoteSourceManual && !(entity.IsIntegrationSource(source) && entity.IsNoteSource(source)) {
+			return domain.ErrInvalidNoteSource
+		}
+		note.Source = source
+	}
+	if len(meta) > 0 {
+		encoded, err := json.Marshal(meta)
+		if err != nil || len(encoded) > entity.MaxNoteSourceMetaSize {
+			return domain.ErrInvalidNoteSource
+		}
+		note.SourceMeta = meta
+	}
+	return nil
+}
+
+// setStyle sets the note's color and icon, leaving those that are nil alone.
+// An empty name takes the color or icon off.
+func setStyle(note *entity.Note, color, icon *string) error {
+	if color != nil {
+		name := entity.NormalizeNoteStyle(*color)
+		if name != "" && !entity.IsNoteColor(name) {
+			return domain.ErrInvalidNoteColor
+		}
+		note.Color = styleName(name)
+	}
+	if icon != nil {
+		name := entity.NormalizeNoteStyle(*icon)
+		if name != "" && !entity.IsNoteIcon(name) {
+			return domain.ErrInvalidNoteIcon
+		}
+		note.Icon = styleName(name)
+	}
+	return nil
+}
+
+func styleName(name string) *string {
+	if name == "" {
+		return nil
+	}
+	return &name
+}
+
+// normalizeStyleFilters validates the colors and icons a listing is
+// filtered by.
+func normalizeStyleFilters(colors, icons []string) ([]string, []string, error) {
+	colors, ok := entity.NormalizeNoteColors(colors)
+	if !ok {
+		return nil, nil, domain.ErrInvalidNoteColor
+	}
+	icons, ok = entity.NormalizeNoteIcons(icons)
+	if !ok {
+		return nil, nil, domain.ErrInvalidNoteIcon
+	}
+	return colors, icons, nil
+}
+
 type ListInput struct {
-	UserID      uuid.UUID
-	Page        int
-	PerPage     int
-	BoundingBox *valueobject.BoundingBox
+	UserID        uuid.UUID
+	Page          int
+	PerPage       int
+	BoundingBox   *valueobject.BoundingBox
+	DeviceID      string
+	QualityStatus string
+	// Source keeps notes with this source; entity.NoteSourceIntegration
+	// matches every integration.
+	Source string
+	// Number matches a note by its number ("42") or full reference ("PLOT-0042").
+	Number string
+	// Tags keeps only notes carrying all of these tags.
+	Tags []string
+	// Colors and Icons keep only notes with any one of these colors and
+	// any one of these icons.
+	Colors []string
+	Icons  []string
+	// Cursor continues a listing from a previous page's next_cursor; when
+	// set, Page is ignored.
+	Cursor string
+	// TeamID lists the notes of a team the user is a member of instead of
+	// the user's own.
+	TeamID *uuid.UUID
+	// Sort is a pagination.ParseSort list of entity.NoteSortFields; empty
+	// lists the most recently updated notes first.
+	Sort string
 }
 
 func (s *Service) List(ctx context.Context, input ListInput) ([]entity.Note, *pagination.Info, error) {
+	tags, ok := entity.NormalizeTags(input.Tags)
+	if !ok {
+		return nil, nil, domain.ErrInvalidTag
+	}
+	colors, icons, err := normalizeStyleFilters(input.Colors, input.Icons)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pageParams := pagination.NewParams(input.Page, input.PerPage)
+	if input.Cursor != "" {
+		after, err := pagination.DecodeCursor(input.Cursor)
+		if err != nil {
+			return nil, nil, domain.ErrInvalidCursor
+		}
+		pageParams.After = after
+	}
+
+	if input.Source != "" && input.Source != entity.NoteSourceIntegration && !entity.IsNoteSource(input.Source) {
+		return nil, nil, domain.ErrInvalidNoteSource
+	}
+
+	sort, err := pagination.ParseSort(input.Sort, entity.NoteSortFields)
+	if err != nil {
+		return nil, nil, domain.ErrInvalidSort
+	}
+
+	if input.TeamID != nil {
+		if err := s.authorizer.AuthorizeTeam(ctx, input.UserID, *input.TeamID, authz.ActionRead); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	params := repository.NoteListParams{
-		Pagination:     pagination.NewParams(input.Page, input.PerPage),
+		Pagination:     pageParams,
+		TeamID:         input.TeamID,
+		Tags:           tags,
+		Colors:         colors,
+		Icons:          icons,
 		BoundingBox:    input.BoundingBox,
+		DeviceID:       input.DeviceID,
+		QualityStatus:  input.QualityStatus,
+		Source:         input.Source,
 		IncludeDeleted: false,
+		Sort:           sort,
+	}
+	if n, err := strconv.ParseInt(input.Number, 10, 64); err == nil {
+		params.Number = n
+	} else if input.Number != "" {
+		params.Reference = strings.ToUpper(input.Number)
 	}
 
 	notes, pageInfo, err := s.noteRepo.List(ctx, input.UserID, params)
@@ -69,15 +281,254 @@ func (s *Service) List(ctx context.Context, input ListInput) ([]entity.Note, *pa
 		return nil, nil, fmt.Errorf("listing notes: %w", err)
 	}
 
+	ids := make([]uuid.UUID, len(notes))
+	for i := range notes {
+		ids[i] = notes[i].ID
+	}
+	photos, err := s.photosByNote(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range notes {
+		notes[i].Photos = photos[notes[i].ID]
+	}
+
+	return notes, pageInfo, nil
+}
+
+// photosByNote loads the photos of several notes in one query.
+func (s *Service) photosByNote(ctx context.Context, noteIDs []uuid.UUID) (map[uuid.UUID][]entity.Photo, error) {
+	if len(noteIDs) == 0 {
+		return nil, nil
+	}
+
+	photos, err := s.photoRepo.GetByNoteIDs(ctx, noteIDs)
+	if err != nil {
+		return nil, fmt.Errorf("loading photos: %w", err)
+	}
+
+	byNote := make(map[uuid.UUID][]entity.Photo, len(noteIDs))
+	for _, p := range photos {
+		byNote[p.NoteID] = append(byNote[p.NoteID], p)
+	}
+	return byNote, nil
+}
+
+const (
+	// MaxNearbyRadius bounds radius searches in meters, so one request can't
+	// walk the whole location index.
+	MaxNearbyRadius    = 50_000.0
+	defaultNearbyLimit = 20
+	maxNearbyLimit     = 100
+)
+
+type NearbyInput struct {
+	UserID       uuid.UUID
+	Latitude     float64
+	Longitude    float64
+	RadiusMeters float64
+	// Limit defaults to 20 and is capped at 100.
+	Limit int
+}
+
+// Nearby returns the user's notes within RadiusMeters of the point, closest
+// first.
+func (s *Service) Nearby(ctx context.Context, input NearbyInput) ([]entity.NearbyNote, error) {
+	center := valueobject.NewLocation(input.Latitude, input.Longitude, nil, nil)
+	if !center.IsValid() || input.RadiusMeters <= 0 || input.RadiusMeters > MaxNearbyRadius {
+		return nil, domain.ErrInvalidLocation
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultNearbyLimit
+	}
+	limit = min(limit, maxNearbyLimit)
+
+	notes, err := s.noteRepo.Nearby(ctx, input.UserID, repository.NoteNearbyParams{
+		Latitude:     input.Latitude,
+		Longitude:    input.Longitude,
+		RadiusMeters: input.RadiusMeters,
+		Limit:        limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("finding nearby notes: %w", err)
+	}
+
+	ids := make([]uuid.UUID, len(notes))
 	for i := range notes {
-		photos, err := s.photoRepo.GetByNoteID(ctx, notes[i].ID)
+		ids[i] = notes[i].Note.ID
+	}
+	photos, err := s.photosByNote(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range notes {
+		notes[i].Note.Photos = photos[notes[i].Note.ID]
+	}
+
+	return notes, nil
+}
+
+const (
+	// DefaultCoverageZoom shows roads and trails, enough to find a site.
+	DefaultCoverageZoom = 12
+	MaxCoverageZoom     = 18
+	// maxCoverageTiles keeps offline downloads to a size phones can hold;
+	// past it the coverage is truncated and a lower zoom should be asked.
+	maxCoverageTiles = 2000
+)
+
+// Coverage returns the map tiles at the zoom, DefaultCoverageZoom when 0,
+// that hold the user's notes, so clients can download just those areas for
+// offline use.
+func (s *Service) Coverage(ctx context.Context, userID uuid.UUID, zoom int) (*entity.NoteCoverage, error) {
+	if zoom == 0 {
+		zoom = DefaultCoverageZoom
+	}
+	if zoom < 1 || zoom > MaxCoverageZoom {
+		return nil, domain.ErrInvalidZoom
+	}
+
+	coverage, err := s.noteRepo.Coverage(ctx, userID, zoom, maxCoverageTiles)
+	if err != nil {
+		return nil, fmt.Errorf("computing note coverage: %w", err)
+	}
+	return coverage, nil
+}
+
+// exportBatchSize is how many notes an export reads, and hands to the caller,
+// at a time.
+const exportBatchSize = 500
+
+// ExportInput filters an export as ListInput filters a listing.
+type ExportInput struct {
+	UserID        uuid.UUID
+	BoundingBox   *valueobject.BoundingBox
+	DeviceID      string
+	QualityStatus string
+	Source        string
+	Tags          []string
+	Colors        []string
+	Icons         []string
+	// From and To, when set, keep notes created in [From, To).
+	From *time.Time
+	To   *time.Time
+}
+
+// Export calls fn with the user's notes matching the input, with their
+// photos, in batches ordered by number. Memory use does not grow with the
+// size of the account.
+func (s *Service) Export(ctx context.Context, input ExportInput, fn func([]entity.Note) error) error {
+	tags, ok := entity.NormalizeTags(input.Tags)
+	if !ok {
+		return domain.ErrInvalidTag
+	}
+	colors, icons, err := normalizeStyleFilters(input.Colors, input.Icons)
+	if err != nil {
+		return err
+	}
+	if input.Source != "" && input.Source != entity.NoteSourceIntegration && !entity.IsNoteSource(input.Source) {
+		return domain.ErrInvalidNoteSource
+	}
+	if input.From != nil && input.To != nil && !input.From.Before(*input.To) {
+		return domain.ErrInvalidDateRange
+	}
+
+	params := repository.NoteListParams{
+		BoundingBox:   input.BoundingBox,
+		DeviceID:      input.DeviceID,
+		QualityStatus: input.QualityStatus,
+		Source:        input.Source,
+		Tags:          tags,
+		Colors:        colors,
+		Icons:         icons,
+		CreatedFrom:   input.From,
+		CreatedTo:     input.To,
+	}
+	return s.noteRepo.Export(ctx, input.UserID, params, exportBatchSize, func(notes []entity.Note) error {
+		ids := make([]uuid.UUID, len(notes))
+		for i := range notes {
+			ids[i] = notes[i].ID
+		}
+		photos, err := s.photosByNote(ctx, ids)
 		if err != nil {
-			return nil, nil, fmt.Errorf("loading photos: %w", err)
+			return err
 		}
-		notes[i].Photos = photos
+		for i := range notes {
+			notes[i].Photos = photos[notes[i].ID]
+		}
+		return fn(notes)
+	})
+}
+
+// maxSearchQueryLength bounds the text of a search query in bytes.
+const maxSearchQueryLength = 200
+
+type SearchInput struct {
+	UserID uuid.UUID
+	Query  string
+	// A positive RadiusMeters restricts the search to that radius around
+	// Latitude and Longitude; otherwise BoundingBox, when set, restricts it
+	// to the box. Setting both is invalid.
+	Latitude     float64
+	Longitude    float64
+	RadiusMeters float64
+	BoundingBox  *valueobject.BoundingBox
+	// Limit defaults to 20 and is capped at 100.
+	Limit int
+}
+
+// Search returns the user's notes matching the text query, best first. With
+// an area, results are limited to it and ranked by both text relevance and
+// closeness to its center.
+func (s *Service) Search(ctx context.Context, input SearchInput) ([]entity.SearchHit, error) {
+	query := strings.TrimSpace(input.Query)
+	if query == "" || len(query) > maxSearchQueryLength {
+		return nil, domain.ErrInvalidSearchQuery
 	}
 
-	return notes, pageInfo, nil
+	if input.RadiusMeters != 0 {
+		center := valueobject.NewLocation(input.Latitude, input.Longitude, nil, nil)
+		if input.BoundingBox != nil || !center.IsValid() || input.RadiusMeters < 0 || input.RadiusMeters > MaxNearbyRadius {
+			return nil, domain.ErrInvalidLocation
+		}
+	}
+	if input.BoundingBox != nil && !input.BoundingBox.IsValid() {
+		return nil, domain.ErrInvalidBoundingBox
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultNearbyLimit
+	}
+	limit = min(limit, maxNearbyLimit)
+
+	hits, err := s.noteRepo.Search(ctx, input.UserID, repository.NoteSearchParams{
+		Query:        query,
+		Latitude:     input.Latitude,
+		Longitude:    input.Longitude,
+		RadiusMeters: input.RadiusMeters,
+		BoundingBox:  input.BoundingBox,
+		Limit:        limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("searching notes: %w", err)
+	}
+
+	ids := make([]uuid.UUID, len(hits))
+	for i := range hits {
+		ids[i] = hits[i].Note.ID
+	}
+	photos, err := s.photosByNote(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range hits {
+		hits[i].Note.Photos = photos[hits[i].Note.ID]
+	}
+
+	return hits, nil
 }
 
 func (s *Service) GetByID(ctx context.Context, userID, noteID uuid.UUID) (*entity.Note, error) {
@@ -86,8 +537,8 @@ func (s *Service) GetByID(ctx context.Context, userID, noteID uuid.UUID) (*entit
 		return nil, err
 	}
 
-	if note.UserID != userID {
-		return nil, domain.ErrForbidden
+	if err := s.authorizer.Authorize(ctx, userID, authz.ActionRead, note); err != nil {
+		return nil, err
 	}
 
 	if note.IsDeleted() {
@@ -107,6 +558,17 @@ type UpdateInput struct {
 	Title    *string
 	Content  *string
 	Location *valueobject.Location
+	// Measurements replaces the note's measurements when non-nil.
+	Measurements []valueobject.Measurement
+	DeviceID     string
+	// Sensitivity changes the note's level when non-nil; owner only.
+	Sensitivity *string
+	// Color and Icon change the note's color and icon when non-nil; empty
+	// takes them off.
+	Color *string
+	Icon  *string
+	// SkipAutoTitle keeps the title as given; see CreateInput.
+	SkipAutoTitle bool
 }
 
 func (s *Service) Update(ctx context.Context, userID, noteID uuid.UUID, input UpdateInput) (*entity.Note, error) {
@@ -115,14 +577,23 @@ func (s *Service) Update(ctx context.Context, userID, noteID uuid.UUID, input Up
 		return nil, err
 	}
 
-	if note.UserID != userID {
-		return nil, domain.ErrForbidden
+	if err := s.authorizer.Authorize(ctx, userID, authz.ActionEdit, note); err != nil {
+		return nil, err
 	}
 
 	if note.IsDeleted() {
 		return nil, domain.ErrNoteNotFound
 	}
 
+	if err := s.authorizeProtection(ctx, userID, note, input); err != nil {
+		return nil, err
+	}
+
+	// A collaborative note's content only changes through its document.
+	if note.Collaborative && input.Content != nil && *input.Content != note.Content {
+		return nil, domain.ErrNoteCollaborative
+	}
+
 	title := note.Title
 	content := note.Content
 	location := note.Location
@@ -137,12 +608,90 @@ func (s *Service) Update(ctx context.Context, userID, noteID uuid.UUID, input Up
 		location = input.Location
 	}
 
-	note.Update(title, content, location)
+	previous := note.Content
+	note.UpdateAt(title, content, location, s.clock.Now())
+	if input.Measurements != nil {
+		note.Measurements = input.Measurements
+	}
+	if input.Sensitivity != nil {
+		note.Sensitivity = *input.Sensitivity
+	}
+	if err := setStyle(note, input.Color, input.Icon); err != nil {
+		return nil, err
+	}
+	if !input.SkipAutoTitle {
+		s.titler.Retitle(ctx, note, previous)
+	}
+	note.MarkModifiedBy(input.DeviceID)
+	note.Sanitize()
+
+	photos, err := s.photoRepo.GetByNoteID(ctx, noteID)
+	if err != nil {
+		return nil, fmt.Errorf("loading photos: %w", err)
+	}
+	note.Photos = photos
+
+	if err := s.evaluateQuality(ctx, note); err != nil {
+		return nil, err
+	}
 
 	if err := s.noteRepo.Update(ctx, note); err != nil {
 		return nil, fmt.Errorf("updating note: %w", err)
 	}
 
+	return note, nil
+}
+
+// authorizeProtection lets only the owner change the sensitivity or move a
+// sensitive note. Editors only ever see the generalized location, so a
+// location from them would overwrite the exact one with a guess.
+func (s *Service) authorizeProtection(ctx context.Context, userID uuid.UUID, note *entity.Note, input UpdateInput) error {
+	if input.Sensitivity != nil && !entity.IsSensitivity(*input.Sensitivity) {
+		return domain.ErrInvalidSensitivity
+	}
+	changesLevel := input.Sensitivity != nil && *input.Sensitivity != note.Sensitivity
+	movesSensitive := input.Location != nil && note.IsSensitive()
+	if !changesLevel && !movesSensitive {
+		return nil
+	}
+	return s.authorizer.Authorize(ctx, userID, authz.ActionProtect, note)
+}
+
+// evaluateQuality checks the note against the owner's quality rules. The
+// note's photos must already be loaded.
+func (s *Service) evaluateQuality(ctx context.Context, note *entity.Note) error {
+	rules, err := s.ruleRepo.GetByUserID(ctx, note.UserID)
+	if err != nil {
+		return fmt.Errorf("getting quality rules: %w", err)
+	}
+	note.Quality = rules.Evaluate(note, len(note.Photos))
+	return nil
+}
+
+// Restore undeletes a note deleted within the restore window. Restoring bumps
+// updated_at, so devices that synced the tombstone pull the note back.
+// Restoring a note that is not deleted returns it unchanged.
+func (s *Service) Restore(ctx context.Context, userID, noteID uuid.UUID) (*entity.Note, error) {
+	note, err := s.noteRepo.GetByID(ctx, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizer.Authorize(ctx, userID, authz.ActionRestore, note); err != nil {
+		return nil, err
+	}
+
+	if note.IsDeleted() {
+		if !note.CanRestore(s.clock.Now()) {
+			return nil, domain.ErrRestoreExpired
+		}
+
+		note.Restore(s.clock.Now())
+		if err := s.noteRepo.Update(ctx, note); err != nil {
+			return nil, fmt.Errorf("restoring note: %w", err)
+		}
+	}
+
 	photos, err := s.photoRepo.GetByNoteID(ctx, noteID)
 	if err != nil {
 		return nil, fmt.Errorf("loading photos: %w", err)
@@ -152,19 +701,222 @@ func (s *Service) Update(ctx context.Context, userID, noteID uuid.UUID, input Up
 	return note, nil
 }
 
+type ExistsInput struct {
+	UserID    uuid.UUID
+	IDs       []uuid.UUID
+	ClientIDs []string
+}
+
+// ExistsResult splits the notes asked about into those the user has, deleted
+// ones included, and the IDs and client IDs that match none.
+type ExistsResult struct {
+	Found            []entity.NoteExistence
+	MissingIDs       []uuid.UUID
+	MissingClientIDs []string
+}
+
+// Exists reports which of the user's notes exist and at which version, so a
+// client recovering its local store can work out what to upload again
+// without sending every note through sync.
+func (s *Service) Exists(ctx context.Context, input ExistsInput) (*ExistsResult, error) {
+	result := &ExistsResult{}
+	if len(input.IDs) == 0 && len(input.ClientIDs) == 0 {
+		return result, nil
+	}
+
+	found, err := s.noteRepo.Existing(ctx, input.UserID, input.IDs, input.ClientIDs)
+	if err != nil {
+		return nil, fmt.Errorf("checking notes: %w", err)
+	}
+	result.Found = found
+
+	ids := make(map[uuid.UUID]bool, len(found))
+	clientIDs := make(map[string]bool, len(found))
+	for _, n := range found {
+		ids[n.ID] = true
+		clientIDs[n.ClientID] = true
+	}
+	for _, id := range input.IDs {
+		if !ids[id] {
+			result.MissingIDs = append(result.MissingIDs, id)
+		}
+	}
+	for _, id := range input.ClientIDs {
+		if !clientIDs[id] {
+			result.MissingClientIDs = append(result.MissingClientIDs, id)
+		}
+	}
+	return result, nil
+}
+
+type MergeInput struct {
+	UserID uuid.UUID
+	// NoteIDs lists the notes to merge; the first one survives.
+	NoteIDs   []uuid.UUID
+	TitleFrom uuid.UUID
+	Content   string
+	DeviceID  string
+}
+
+// Merge folds the other notes into the first one and deletes them. All notes
+// must belong to the same owner, who must be allowed to delete the merged
+// ones. The merged notes keep a pointer to the survivor so devices that
+// synced them can follow it.
+func (s *Service) Merge(ctx context.Context, input MergeInput) (*entity.Note, error) {
+	if err := validateMerge(input); err != nil {
+		return nil, err
+	}
+
+	notes := make([]entity.Note, 0, len(input.NoteIDs))
+	for i, id := range input.NoteIDs {
+		n, err := s.noteRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		action := authz.ActionDelete
+		if i == 0 {
+			action = authz.ActionEdit
+		}
+		if err := s.authorizer.Authorize(ctx, input.UserID, action, n); err != nil {
+			return nil, err
+		}
+		if n.IsDeleted() {
+			return nil, domain.ErrNoteNotFound
+		}
+		if len(notes) > 0 && n.UserID != notes[0].UserID {
+			return nil, domain.ErrInvalidMerge
+		}
+		if n.Collaborative {
+			return nil, domain.ErrNoteCollaborative
+		}
+		notes = append(notes, *n)
+	}
+
+	survivor := &notes[0]
+	survivor.Merge(notes[1:], entity.MergeSpec{TitleFrom: input.TitleFrom, Content: input.Content})
+	survivor.MarkModifiedBy(input.DeviceID)
+	survivor.Sanitize()
+
+	for _, n := range notes {
+		photos, err := s.photoRepo.GetByNoteID(ctx, n.ID)
+		if err != nil {
+			return nil, fmt.Errorf("loading photos: %w", err)
+		}
+		survivor.Photos = append(survivor.Photos, photos...)
+	}
+	for _, n := range notes[1:] {
+		survivor.Attachments = append(survivor.Attachments, n.Attachments...)
+	}
+
+	if err := s.evaluateQuality(ctx, survivor); err != nil {
+		return nil, err
+	}
+
+	if err := s.noteRepo.Merge(ctx, survivor, input.NoteIDs[1:]); err != nil {
+		return nil, fmt.Errorf("merging notes: %w", err)
+	}
+
+	return survivor, nil
+}
+
+func validateMerge(input MergeInput) error {
+	if len(input.NoteIDs) < 2 || !entity.IsMergeContent(input.Content) {
+		return domain.ErrInvalidMerge
+	}
+
+	seen := make(map[uuid.UUID]bool, len(input.NoteIDs))
+	for _, id := range input.NoteIDs {
+		if seen[id] {
+			return domain.ErrInvalidMerge
+		}
+		seen[id] = true
+	}
+	if input.TitleFrom != uuid.Nil && !seen[input.TitleFrom] {
+		return domain.ErrInvalidMerge
+	}
+	return nil
+}
+
+type TagsInput struct {
+	UserID   uuid.UUID
+	NoteID   uuid.UUID
+	Tags     []string
+	DeviceID string
+}
+
+// AddTags labels the note with the given tags. Anyone who may edit the note
+// may tag it; new tags are created in the owner's vocabulary.
+func (s *Service) AddTags(ctx context.Context, input TagsInput) (*entity.Note, error) {
+	return s.changeTags(ctx, input, func(n *entity.Note, tags []string) error {
+		n.AddTags(tags)
+		if len(n.Tags) > entity.MaxNoteTags {
+			return domain.ErrTooManyTags
+		}
+		return nil
+	})
+}
+
+// RemoveTags takes the given tags off the note. Tags the note does not carry
+// are ignored.
+func (s *Service) RemoveTags(ctx context.Context, input TagsInput) (*entity.Note, error) {
+	return s.changeTags(ctx, input, func(n *entity.Note, tags []string) error {
+		n.RemoveTags(tags)
+		return nil
+	})
+}
+
+func (s *Service) changeTags(ctx context.Context, input TagsInput, apply func(*entity.Note, []string) error) (*entity.Note, error) {
+	tags, ok := entity.NormalizeTags(input.Tags)
+	if !ok || len(tags) == 0 {
+		return nil, domain.ErrInvalidTag
+	}
+
+	note, err := s.noteRepo.GetByID(ctx, input.NoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizer.Authorize(ctx, input.UserID, authz.ActionEdit, note); err != nil {
+		return nil, err
+	}
+
+	if note.IsDeleted() {
+		return nil, domain.ErrNoteNotFound
+	}
+
+	if err := apply(note, tags); err != nil {
+		return nil, err
+	}
+	note.MarkModifiedBy(input.DeviceID)
+	note.UpdatedAt = s.clock.Now()
+
+	if err := s.noteRepo.SetTags(ctx, note); err != nil {
+		return nil, fmt.Errorf("saving tags: %w", err)
+	}
+
+	photos, err := s.photoRepo.GetByNoteID(ctx, note.ID)
+	if err != nil {
+		return nil, fmt.Errorf("loading photos: %w", err)
+	}
+	note.Photos = photos
+
+	return note, nil
+}
+
 func (s *Service) Delete(ctx context.Context, userID, noteID uuid.UUID) error {
 	note, err := s.noteRepo.GetByID(ctx, noteID)
 	if err != nil {
 		return err
 	}
 
-	if note.UserID != userID {
-		return domain.ErrForbidden
+	if err := s.authorizer.Authorize(ctx, userID, authz.ActionDelete, note); err != nil {
+		return err
 	}
 
-	if err := s.noteRepo.SoftDelete(ctx, noteID); err != nil {
+	if err := s.noteRepo.SoftDelete(ctx, note.UserID, noteID); err != nil {
 		return fmt.Errorf("deleting note: %w", err)
 	}
 
+	s.auditRecorder.Record(ctx, entity.NewAuditEvent(userID, entity.AuditNoteDeleted, noteID.String()))
 	return nil
 }
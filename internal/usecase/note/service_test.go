@@ -2,6 +2,7 @@ package note_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -10,14 +11,28 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
 	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/clock"
 	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/audit"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/authz"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/autotitle"
 	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/note"
 )
 
+// ownerOnly returns an authorizer for notes that are not shared with anyone.
+func ownerOnly(ctrl *gomock.Controller) *authz.Authorizer {
+	shareRepo := mocks.NewMockShareRepository(ctrl)
+	shareRepo.EXPECT().GetRole(gomock.Any(), gomock.Any(), gomock.Any()).Return("", nil).AnyTimes()
+	orgRepo := mocks.NewMockOrganizationRepository(ctrl)
+	orgRepo.EXPECT().IsAdminOver(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+	return authz.NewAuthorizer(shareRepo, orgRepo, nil)
+}
+
 func TestService_Create(t *testing.T) {
 	t.Run("creates note successfully", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
@@ -25,13 +40,15 @@ func TestService_Create(t *testing.T) {
 
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
 		photoRepo := mocks.NewMockPhotoRepository(ctrl)
-		svc := note.NewService(noteRepo, photoRepo)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, ruleRepo, ownerOnly(ctrl), nil, nil, nil)
 
 		ctx := context.Background()
 		userID := uuid.New()
 		loc := valueobject.NewLocation(37.7749, -122.4194, nil, nil)
 
 		noteRepo.EXPECT().GetByClientID(ctx, userID, "client-123").Return(nil, domain.ErrNoteNotFound)
+		ruleRepo.EXPECT().GetByUserID(ctx, gomock.Any()).Return(&entity.QualityRules{}, nil)
 		noteRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
 
 		n, err := svc.Create(ctx, note.CreateInput{
@@ -40,6 +57,7 @@ func TestService_Create(t *testing.T) {
 			Content:  "Test content",
 			Location: loc,
 			ClientID: "client-123",
+			DeviceID: "device-123",
 		})
 
 		require.NoError(t, err)
@@ -47,6 +65,38 @@ func TestService_Create(t *testing.T) {
 		assert.Equal(t, "Test content", n.Content)
 		assert.Equal(t, userID, n.UserID)
 		assert.Equal(t, loc.Latitude, n.Location.Latitude)
+		assert.Equal(t, "device-123", n.CreatedByDevice)
+		assert.Equal(t, "device-123", n.LastModifiedByDevice)
+	})
+
+	t.Run("creates team notes only for editors", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		teamRepo := mocks.NewMockTeamRepository(ctrl)
+		svc := note.NewService(noteRepo, nil, ruleRepo, authz.NewAuthorizer(nil, nil, teamRepo), nil, nil, nil)
+
+		ctx := context.Background()
+		memberID, viewerID, strangerID, teamID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+		teamRepo.EXPECT().GetRole(ctx, teamID, memberID).Return(entity.TeamRoleEditor, nil)
+		teamRepo.EXPECT().GetRole(ctx, teamID, viewerID).Return(entity.TeamRoleViewer, nil)
+		teamRepo.EXPECT().GetRole(ctx, teamID, strangerID).Return("", nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, memberID).Return(&entity.QualityRules{}, nil)
+		noteRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+		n, err := svc.Create(ctx, note.CreateInput{UserID: memberID, Title: "Plot", Content: "Clay", TeamID: &teamID})
+		require.NoError(t, err)
+		assert.Equal(t, memberID, n.UserID)
+		assert.Equal(t, &teamID, n.TeamID)
+
+		_, err = svc.Create(ctx, note.CreateInput{UserID: viewerID, Title: "Plot", Content: "Clay", TeamID: &teamID})
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+
+		_, err = svc.Create(ctx, note.CreateInput{UserID: strangerID, Title: "Plot", Content: "Clay", TeamID: &teamID})
+		assert.ErrorIs(t, err, domain.ErrForbidden)
 	})
 
 	t.Run("returns existing note with same client_id (idempotent)", func(t *testing.T) {
@@ -55,7 +105,8 @@ func TestService_Create(t *testing.T) {
 
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
 		photoRepo := mocks.NewMockPhotoRepository(ctrl)
-		svc := note.NewService(noteRepo, photoRepo)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, ruleRepo, ownerOnly(ctrl), nil, nil, nil)
 
 		ctx := context.Background()
 		userID := uuid.New()
@@ -81,17 +132,108 @@ func TestService_Create(t *testing.T) {
 		assert.Equal(t, "Existing Note", n.Title)
 	})
 
+	t.Run("returns the note a concurrent create stored with the same client_id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, ruleRepo, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		stored := &entity.Note{ID: uuid.New(), UserID: userID, Title: "Stored", ClientID: "client-123"}
+
+		gomock.InOrder(
+			noteRepo.EXPECT().GetByClientID(ctx, userID, "client-123").Return(nil, domain.ErrNoteNotFound),
+			noteRepo.EXPECT().GetByClientID(ctx, userID, "client-123").Return(stored, nil),
+		)
+		ruleRepo.EXPECT().GetByUserID(ctx, gomock.Any()).Return(&entity.QualityRules{}, nil)
+		noteRepo.EXPECT().Create(ctx, gomock.Any()).Return(domain.ErrNoteAlreadyExists)
+
+		n, err := svc.Create(ctx, note.CreateInput{
+			UserID:   userID,
+			Title:    "Retry",
+			Content:  "Content",
+			ClientID: "client-123",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, stored.ID, n.ID)
+	})
+
+	t.Run("returns warnings for truncated content and low accuracy", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, ruleRepo, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		accuracy := 500.0
+		loc := valueobject.NewLocation(37.7749, -122.4194, nil, &accuracy)
+
+		ruleRepo.EXPECT().GetByUserID(ctx, gomock.Any()).Return(&entity.QualityRules{}, nil)
+		noteRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+		n, err := svc.Create(ctx, note.CreateInput{
+			UserID:   uuid.New(),
+			Title:    "Long Note",
+			Content:  strings.Repeat("a", entity.MaxNoteContentLength+10),
+			Location: loc,
+		})
+
+		require.NoError(t, err)
+		assert.Len(t, n.Content, entity.MaxNoteContentLength)
+		require.Len(t, n.Warnings, 2)
+		assert.Equal(t, valueobject.WarningContentTruncated, n.Warnings[0].Code)
+		assert.Equal(t, valueobject.WarningLowAccuracy, n.Warnings[1].Code)
+	})
+
+	t.Run("records failed quality rules", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, ruleRepo, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		rules := &entity.QualityRules{UserID: userID, RequirePhoto: true, MinContentLength: 5}
+
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(rules, nil)
+		noteRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+		n, err := svc.Create(ctx, note.CreateInput{
+			UserID:  userID,
+			Title:   "Plot 7",
+			Content: "Observed two nests",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, entity.QualityFailed, n.Quality.Status)
+		assert.Equal(t, []string{entity.QualityRuleMinContentLength}, n.Quality.Passed)
+		assert.Equal(t, []string{entity.QualityRuleRequiredPhoto}, n.Quality.Failed)
+	})
+
 	t.Run("creates note without client_id", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
 		photoRepo := mocks.NewMockPhotoRepository(ctrl)
-		svc := note.NewService(noteRepo, photoRepo)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, ruleRepo, ownerOnly(ctrl), nil, nil, nil)
 
 		ctx := context.Background()
 		userID := uuid.New()
 
+		ruleRepo.EXPECT().GetByUserID(ctx, gomock.Any()).Return(&entity.QualityRules{}, nil)
 		noteRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
 
 		n, err := svc.Create(ctx, note.CreateInput{
@@ -103,6 +245,110 @@ func TestService_Create(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, "Test Note", n.Title)
 		assert.Empty(t, n.ClientID)
+		assert.Equal(t, entity.NoteSourceManual, n.Source)
+	})
+
+	t.Run("records the integration that created the note", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, mocks.NewMockPhotoRepository(ctrl), ruleRepo, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		meta := map[string]any{"station": "WS-12"}
+
+		ruleRepo.EXPECT().GetByUserID(ctx, gomock.Any()).Return(&entity.QualityRules{}, nil)
+		noteRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+		n, err := svc.Create(ctx, note.CreateInput{
+			UserID:     uuid.New(),
+			Title:      "Rainfall",
+			Content:    "12 mm",
+			Source:     entity.IntegrationSource("weather-station"),
+			SourceMeta: meta,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "integration:weather-station", n.Source)
+		assert.Equal(t, meta, n.SourceMeta)
+	})
+
+	t.Run("rejects sources set by other paths and oversized metadata", func(t *testing.T) {
+		svc := note.NewService(nil, nil, nil, nil, nil, nil, nil)
+		ctx := context.Background()
+
+		for _, input := range []note.CreateInput{
+			{Source: entity.NoteSourceSync},
+			{Source: entity.NoteSourceImport},
+			{Source: "integration:Not Valid"},
+			{SourceMeta: map[string]any{"blob": strings.Repeat("x", entity.MaxNoteSourceMetaSize)}},
+		} {
+			input.UserID, input.Title, input.Content = uuid.New(), "Title", "Content"
+
+			_, err := svc.Create(ctx, input)
+
+			assert.ErrorIs(t, err, domain.ErrInvalidNoteSource)
+		}
+	})
+
+	t.Run("sets a color and icon from the allowed sets", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, nil, ruleRepo, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil)
+		noteRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+		n, err := svc.Create(ctx, note.CreateInput{UserID: userID, Title: "Nest", Content: "Two eggs", Color: " Green", Icon: "bird"})
+		require.NoError(t, err)
+		require.NotNil(t, n.Color)
+		require.NotNil(t, n.Icon)
+		assert.Equal(t, "green", *n.Color)
+		assert.Equal(t, "bird", *n.Icon)
+
+		_, err = svc.Create(ctx, note.CreateInput{UserID: userID, Title: "Nest", Content: "Two eggs", Color: "chartreuse"})
+		assert.ErrorIs(t, err, domain.ErrInvalidNoteColor)
+
+		_, err = svc.Create(ctx, note.CreateInput{UserID: userID, Title: "Nest", Content: "Two eggs", Icon: "dragon"})
+		assert.ErrorIs(t, err, domain.ErrInvalidNoteIcon)
+	})
+
+	t.Run("titles notes without a title from their content", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, nil, ruleRepo, ownerOnly(ctrl), nil, autotitle.NewTitler(nil), nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil).Times(3)
+		noteRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(3)
+
+		n, err := svc.Create(ctx, note.CreateInput{UserID: userID, Title: "Voice note", Content: "\nHeron nest by the creek. Two eggs."})
+		require.NoError(t, err)
+		assert.Equal(t, "Heron nest by the creek.", n.Title)
+		assert.True(t, n.AutoTitled)
+
+		n, err = svc.Create(ctx, note.CreateInput{UserID: userID, Title: "Heron", Content: "Nest by the creek"})
+		require.NoError(t, err)
+		assert.Equal(t, "Heron", n.Title)
+		assert.False(t, n.AutoTitled)
+
+		n, err = svc.Create(ctx, note.CreateInput{UserID: userID, Content: "Nest by the creek", SkipAutoTitle: true})
+		require.NoError(t, err)
+		assert.Empty(t, n.Title)
+		assert.False(t, n.AutoTitled)
 	})
 }
 
@@ -113,7 +359,8 @@ func TestService_List(t *testing.T) {
 
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
 		photoRepo := mocks.NewMockPhotoRepository(ctrl)
-		svc := note.NewService(noteRepo, photoRepo)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, ruleRepo, ownerOnly(ctrl), nil, nil, nil)
 
 		ctx := context.Background()
 		userID := uuid.New()
@@ -125,7 +372,7 @@ func TestService_List(t *testing.T) {
 		pageInfo := &pagination.Info{Page: 1, PerPage: 20, TotalItems: 1, TotalPages: 1}
 
 		noteRepo.EXPECT().List(ctx, userID, gomock.Any()).Return(notes, pageInfo, nil)
-		photoRepo.EXPECT().GetByNoteID(ctx, noteID).Return([]entity.Photo{}, nil)
+		photoRepo.EXPECT().GetByNoteIDs(ctx, []uuid.UUID{noteID}).Return([]entity.Photo{}, nil)
 
 		result, info, err := svc.List(ctx, note.ListInput{
 			UserID:  userID,
@@ -139,13 +386,68 @@ func TestService_List(t *testing.T) {
 		assert.Equal(t, 1, info.TotalItems)
 	})
 
+	t.Run("lists a team's notes for its members", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		teamRepo := mocks.NewMockTeamRepository(ctrl)
+		svc := note.NewService(noteRepo, nil, nil, authz.NewAuthorizer(nil, nil, teamRepo), nil, nil, nil)
+
+		ctx := context.Background()
+		memberID, strangerID, teamID := uuid.New(), uuid.New(), uuid.New()
+
+		teamRepo.EXPECT().GetRole(ctx, teamID, memberID).Return(entity.TeamRoleViewer, nil)
+		teamRepo.EXPECT().GetRole(ctx, teamID, strangerID).Return("", nil)
+		noteRepo.EXPECT().List(ctx, memberID, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ uuid.UUID, params repository.NoteListParams) ([]entity.Note, *pagination.Info, error) {
+				assert.Equal(t, &teamID, params.TeamID)
+				return nil, &pagination.Info{}, nil
+			})
+
+		_, _, err := svc.List(ctx, note.ListInput{UserID: memberID, TeamID: &teamID})
+		require.NoError(t, err)
+
+		_, _, err = svc.List(ctx, note.ListInput{UserID: strangerID, TeamID: &teamID})
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("parses the sort into the listing params", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := note.NewService(noteRepo, nil, nil, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+
+		noteRepo.EXPECT().List(ctx, userID, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ uuid.UUID, params repository.NoteListParams) ([]entity.Note, *pagination.Info, error) {
+				assert.Equal(t, []pagination.SortKey{
+					{Field: entity.NoteSortNumber, Desc: true},
+					{Field: entity.NoteSortTitle},
+				}, params.Sort)
+				return nil, &pagination.Info{}, nil
+			})
+
+		_, _, err := svc.List(ctx, note.ListInput{UserID: userID, Sort: "-number,title"})
+		require.NoError(t, err)
+
+		for _, bad := range []string{"content", "title,-title", "-", "title,"} {
+			_, _, err = svc.List(ctx, note.ListInput{UserID: userID, Sort: bad})
+			assert.ErrorIs(t, err, domain.ErrInvalidSort, bad)
+		}
+	})
+
 	t.Run("lists notes with bounding box filter", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
 		photoRepo := mocks.NewMockPhotoRepository(ctrl)
-		svc := note.NewService(noteRepo, photoRepo)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, ruleRepo, ownerOnly(ctrl), nil, nil, nil)
 
 		ctx := context.Background()
 		userID := uuid.New()
@@ -158,7 +460,7 @@ func TestService_List(t *testing.T) {
 		pageInfo := &pagination.Info{Page: 1, PerPage: 20, TotalItems: 1, TotalPages: 1}
 
 		noteRepo.EXPECT().List(ctx, userID, gomock.Any()).Return(notes, pageInfo, nil)
-		photoRepo.EXPECT().GetByNoteID(ctx, noteID).Return([]entity.Photo{}, nil)
+		photoRepo.EXPECT().GetByNoteIDs(ctx, []uuid.UUID{noteID}).Return([]entity.Photo{}, nil)
 
 		result, _, err := svc.List(ctx, note.ListInput{
 			UserID:      userID,
@@ -170,189 +472,773 @@ func TestService_List(t *testing.T) {
 		require.NoError(t, err)
 		assert.Len(t, result, 1)
 	})
-}
 
-func TestService_GetByID(t *testing.T) {
-	t.Run("returns note for owner", func(t *testing.T) {
+	t.Run("filters by number or reference", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
-		photoRepo := mocks.NewMockPhotoRepository(ctrl)
-		svc := note.NewService(noteRepo, photoRepo)
+		svc := note.NewService(noteRepo, mocks.NewMockPhotoRepository(ctrl), nil, ownerOnly(ctrl), nil, nil, nil)
 
 		ctx := context.Background()
 		userID := uuid.New()
-		noteID := uuid.New()
-		n := &entity.Note{ID: noteID, UserID: userID, Title: "Test Note"}
-
-		noteRepo.EXPECT().GetByID(ctx, noteID).Return(n, nil)
-		photoRepo.EXPECT().GetByNoteID(ctx, noteID).Return([]entity.Photo{}, nil)
-
-		result, err := svc.GetByID(ctx, userID, noteID)
+		pageInfo := &pagination.Info{Page: 1, PerPage: 20}
+
+		noteRepo.EXPECT().List(ctx, userID, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ uuid.UUID, params repository.NoteListParams) ([]entity.Note, *pagination.Info, error) {
+				assert.Equal(t, int64(42), params.Number)
+				assert.Empty(t, params.Reference)
+				return nil, pageInfo, nil
+			})
+		noteRepo.EXPECT().List(ctx, userID, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ uuid.UUID, params repository.NoteListParams) ([]entity.Note, *pagination.Info, error) {
+				assert.Zero(t, params.Number)
+				assert.Equal(t, "PLOT-0042", params.Reference)
+				return nil, pageInfo, nil
+			})
+
+		_, _, err := svc.List(ctx, note.ListInput{UserID: userID, Number: "42"})
+		require.NoError(t, err)
 
+		_, _, err = svc.List(ctx, note.ListInput{UserID: userID, Number: "plot-0042"})
 		require.NoError(t, err)
-		assert.Equal(t, noteID, result.ID)
 	})
 
-	t.Run("returns forbidden for non-owner", func(t *testing.T) {
+	t.Run("filters by source", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
-		photoRepo := mocks.NewMockPhotoRepository(ctrl)
-		svc := note.NewService(noteRepo, photoRepo)
+		svc := note.NewService(noteRepo, mocks.NewMockPhotoRepository(ctrl), nil, ownerOnly(ctrl), nil, nil, nil)
 
 		ctx := context.Background()
-		ownerID := uuid.New()
-		otherUserID := uuid.New()
-		noteID := uuid.New()
-		n := &entity.Note{ID: noteID, UserID: ownerID, Title: "Test Note"}
+		userID := uuid.New()
 
-		noteRepo.EXPECT().GetByID(ctx, noteID).Return(n, nil)
+		noteRepo.EXPECT().List(ctx, userID, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ uuid.UUID, params repository.NoteListParams) ([]entity.Note, *pagination.Info, error) {
+				assert.Equal(t, entity.NoteSourceIntegration, params.Source)
+				return nil, &pagination.Info{Page: 1, PerPage: 20}, nil
+			})
 
-		result, err := svc.GetByID(ctx, otherUserID, noteID)
+		_, _, err := svc.List(ctx, note.ListInput{UserID: userID, Source: entity.NoteSourceIntegration})
+		require.NoError(t, err)
 
-		assert.Nil(t, result)
-		assert.ErrorIs(t, err, domain.ErrForbidden)
+		_, _, err = svc.List(ctx, note.ListInput{UserID: userID, Source: "spreadsheet"})
+		assert.ErrorIs(t, err, domain.ErrInvalidNoteSource)
 	})
 
-	t.Run("returns not found", func(t *testing.T) {
+	t.Run("continues after a cursor", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
-		photoRepo := mocks.NewMockPhotoRepository(ctrl)
-		svc := note.NewService(noteRepo, photoRepo)
+		svc := note.NewService(noteRepo, mocks.NewMockPhotoRepository(ctrl), nil, ownerOnly(ctrl), nil, nil, nil)
 
 		ctx := context.Background()
 		userID := uuid.New()
-		noteID := uuid.New()
+		cursor := pagination.Cursor{UpdatedAt: time.Date(2024, 5, 1, 10, 0, 0, 123000, time.UTC), ID: uuid.New()}
 
-		noteRepo.EXPECT().GetByID(ctx, noteID).Return(nil, domain.ErrNoteNotFound)
+		noteRepo.EXPECT().List(ctx, userID, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ uuid.UUID, params repository.NoteListParams) ([]entity.Note, *pagination.Info, error) {
+				require.NotNil(t, params.Pagination.After)
+				assert.True(t, cursor.UpdatedAt.Equal(params.Pagination.After.UpdatedAt))
+				assert.Equal(t, cursor.ID, params.Pagination.After.ID)
+				return nil, pagination.NewCursorInfo(params.Pagination.PerPage, nil, true), nil
+			})
 
-		result, err := svc.GetByID(ctx, userID, noteID)
+		_, info, err := svc.List(ctx, note.ListInput{UserID: userID, Cursor: cursor.Encode()})
 
-		assert.Nil(t, result)
-		assert.ErrorIs(t, err, domain.ErrNoteNotFound)
+		require.NoError(t, err)
+		assert.False(t, info.HasNext)
+		assert.Empty(t, info.NextCursor)
 	})
 
-	t.Run("returns not found for deleted note", func(t *testing.T) {
+	t.Run("rejects malformed cursor", func(t *testing.T) {
+		svc := note.NewService(nil, nil, nil, nil, nil, nil, nil)
+
+		_, _, err := svc.List(context.Background(), note.ListInput{UserID: uuid.New(), Cursor: "not-a-cursor"})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+	})
+
+	t.Run("filters by color and icon", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
-		photoRepo := mocks.NewMockPhotoRepository(ctrl)
-		svc := note.NewService(noteRepo, photoRepo)
+		svc := note.NewService(noteRepo, nil, nil, ownerOnly(ctrl), nil, nil, nil)
 
 		ctx := context.Background()
 		userID := uuid.New()
-		noteID := uuid.New()
-		deletedAt := time.Now()
-		n := &entity.Note{ID: noteID, UserID: userID, DeletedAt: &deletedAt}
 
-		noteRepo.EXPECT().GetByID(ctx, noteID).Return(n, nil)
+		noteRepo.EXPECT().List(ctx, userID, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ uuid.UUID, params repository.NoteListParams) ([]entity.Note, *pagination.Info, error) {
+				assert.Equal(t, []string{"green", "red"}, params.Colors)
+				assert.Equal(t, []string{"bird"}, params.Icons)
+				return nil, &pagination.Info{}, nil
+			})
 
-		result, err := svc.GetByID(ctx, userID, noteID)
+		_, _, err := svc.List(ctx, note.ListInput{UserID: userID, Colors: []string{"red", "Green", "red"}, Icons: []string{"bird"}})
+		require.NoError(t, err)
 
-		assert.Nil(t, result)
-		assert.ErrorIs(t, err, domain.ErrNoteNotFound)
+		_, _, err = svc.List(ctx, note.ListInput{UserID: userID, Colors: []string{"chartreuse"}})
+		assert.ErrorIs(t, err, domain.ErrInvalidNoteColor)
+
+		_, _, err = svc.List(ctx, note.ListInput{UserID: userID, Icons: []string{""}})
+		assert.ErrorIs(t, err, domain.ErrInvalidNoteIcon)
 	})
 }
 
-func TestService_Update(t *testing.T) {
-	t.Run("updates note successfully", func(t *testing.T) {
+func TestService_Nearby(t *testing.T) {
+	t.Run("returns notes with distances and photos", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
 		photoRepo := mocks.NewMockPhotoRepository(ctrl)
-		svc := note.NewService(noteRepo, photoRepo)
+		svc := note.NewService(noteRepo, photoRepo, nil, ownerOnly(ctrl), nil, nil, nil)
 
 		ctx := context.Background()
 		userID := uuid.New()
 		noteID := uuid.New()
-		n := &entity.Note{ID: noteID, UserID: userID, Title: "Old Title", Content: "Old Content"}
+		otherID := uuid.New()
 
-		noteRepo.EXPECT().GetByID(ctx, noteID).Return(n, nil)
-		noteRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
-		photoRepo.EXPECT().GetByNoteID(ctx, noteID).Return([]entity.Photo{}, nil)
+		noteRepo.EXPECT().Nearby(ctx, userID, repository.NoteNearbyParams{
+			Latitude: 38.72, Longitude: -9.14, RadiusMeters: 500, Limit: 20,
+		}).Return([]entity.NearbyNote{
+			{Note: entity.Note{ID: noteID, UserID: userID}, Distance: 42},
+			{Note: entity.Note{ID: otherID, UserID: userID}, Distance: 90},
+		}, nil)
+		photoRepo.EXPECT().GetByNoteIDs(ctx, []uuid.UUID{noteID, otherID}).Return([]entity.Photo{
+			{ID: uuid.New(), NoteID: noteID},
+			{ID: uuid.New(), NoteID: noteID},
+		}, nil)
 
-		newTitle := "New Title"
-		newContent := "New Content"
-		result, err := svc.Update(ctx, userID, noteID, note.UpdateInput{
-			Title:   &newTitle,
-			Content: &newContent,
-		})
+		result, err := svc.Nearby(ctx, note.NearbyInput{UserID: userID, Latitude: 38.72, Longitude: -9.14, RadiusMeters: 500})
 
 		require.NoError(t, err)
-		assert.Equal(t, "New Title", result.Title)
-		assert.Equal(t, "New Content", result.Content)
+		require.Len(t, result, 2)
+		assert.InDelta(t, 42, result[0].Distance, 0.001)
+		assert.Len(t, result[0].Note.Photos, 2)
+		assert.Empty(t, result[1].Note.Photos)
 	})
 
-	t.Run("returns forbidden for non-owner", func(t *testing.T) {
+	t.Run("caps the limit", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
-		photoRepo := mocks.NewMockPhotoRepository(ctrl)
-		svc := note.NewService(noteRepo, photoRepo)
+		svc := note.NewService(noteRepo, mocks.NewMockPhotoRepository(ctrl), nil, ownerOnly(ctrl), nil, nil, nil)
 
 		ctx := context.Background()
-		ownerID := uuid.New()
-		otherUserID := uuid.New()
-		noteID := uuid.New()
-		n := &entity.Note{ID: noteID, UserID: ownerID}
+		noteRepo.EXPECT().Nearby(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ uuid.UUID, params repository.NoteNearbyParams) ([]entity.NearbyNote, error) {
+				assert.Equal(t, 100, params.Limit)
+				return nil, nil
+			})
 
-		noteRepo.EXPECT().GetByID(ctx, noteID).Return(n, nil)
+		_, err := svc.Nearby(ctx, note.NearbyInput{UserID: uuid.New(), RadiusMeters: 100, Limit: 1000})
 
-		newTitle := "New Title"
-		result, err := svc.Update(ctx, otherUserID, noteID, note.UpdateInput{Title: &newTitle})
+		require.NoError(t, err)
+	})
 
-		assert.Nil(t, result)
-		assert.ErrorIs(t, err, domain.ErrForbidden)
+	t.Run("rejects invalid point or radius", func(t *testing.T) {
+		svc := note.NewService(nil, nil, nil, nil, nil, nil, nil)
+		ctx := context.Background()
+
+		for _, input := range []note.NearbyInput{
+			{Latitude: 91, RadiusMeters: 100},
+			{Longitude: -181, RadiusMeters: 100},
+			{RadiusMeters: 0},
+			{RadiusMeters: note.MaxNearbyRadius + 1},
+		} {
+			_, err := svc.Nearby(ctx, input)
+			assert.ErrorIs(t, err, domain.ErrInvalidLocation)
+		}
 	})
+}
 
-	t.Run("returns not found for deleted note", func(t *testing.T) {
+func TestService_Search(t *testing.T) {
+	t.Run("returns hits with photos", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
 		photoRepo := mocks.NewMockPhotoRepository(ctrl)
-		svc := note.NewService(noteRepo, photoRepo)
+		svc := note.NewService(noteRepo, photoRepo, nil, ownerOnly(ctrl), nil, nil, nil)
 
 		ctx := context.Background()
 		userID := uuid.New()
 		noteID := uuid.New()
-		deletedAt := time.Now()
-		n := &entity.Note{ID: noteID, UserID: userID, DeletedAt: &deletedAt}
+		bbox := valueobject.NewBoundingBox(38, 39, -10, -9)
 
-		noteRepo.EXPECT().GetByID(ctx, noteID).Return(n, nil)
+		noteRepo.EXPECT().Search(ctx, userID, repository.NoteSearchParams{
+			Query: "oak", BoundingBox: bbox, Limit: 20,
+		}).Return([]entity.SearchHit{{Note: entity.Note{ID: noteID, UserID: userID}, Score: 0.8}}, nil)
+		photoRepo.EXPECT().GetByNoteIDs(ctx, []uuid.UUID{noteID}).Return([]entity.Photo{{ID: uuid.New(), NoteID: noteID}}, nil)
 
-		newTitle := "New Title"
-		result, err := svc.Update(ctx, userID, noteID, note.UpdateInput{Title: &newTitle})
+		hits, err := svc.Search(ctx, note.SearchInput{UserID: userID, Query: "  oak ", BoundingBox: bbox})
 
-		assert.Nil(t, result)
-		assert.ErrorIs(t, err, domain.ErrNoteNotFound)
+		require.NoError(t, err)
+		require.Len(t, hits, 1)
+		assert.Len(t, hits[0].Note.Photos, 1)
+	})
+
+	t.Run("rejects a blank query", func(t *testing.T) {
+		svc := note.NewService(nil, nil, nil, nil, nil, nil, nil)
+
+		_, err := svc.Search(context.Background(), note.SearchInput{Query: "   "})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidSearchQuery)
+	})
+
+	t.Run("rejects an invalid area", func(t *testing.T) {
+		svc := note.NewService(nil, nil, nil, nil, nil, nil, nil)
+		ctx := context.Background()
+
+		for _, input := range []note.SearchInput{
+			{Query: "oak", Latitude: 91, RadiusMeters: 100},
+			{Query: "oak", RadiusMeters: note.MaxNearbyRadius + 1},
+			{Query: "oak", RadiusMeters: 100, BoundingBox: valueobject.NewBoundingBox(38, 39, -10, -9)},
+		} {
+			_, err := svc.Search(ctx, input)
+			assert.ErrorIs(t, err, domain.ErrInvalidLocation)
+		}
+
+		_, err := svc.Search(ctx, note.SearchInput{Query: "oak", BoundingBox: valueobject.NewBoundingBox(39, 38, -10, -9)})
+		assert.ErrorIs(t, err, domain.ErrInvalidBoundingBox)
 	})
 }
 
-func TestService_Delete(t *testing.T) {
-	t.Run("soft deletes note successfully", func(t *testing.T) {
+func TestService_Export(t *testing.T) {
+	t.Run("attaches photos to each batch", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
 		photoRepo := mocks.NewMockPhotoRepository(ctrl)
-		svc := note.NewService(noteRepo, photoRepo)
+		svc := note.NewService(noteRepo, photoRepo, nil, ownerOnly(ctrl), nil, nil, nil)
 
 		ctx := context.Background()
 		userID := uuid.New()
-		noteID := uuid.New()
-		n := &entity.Note{ID: noteID, UserID: userID}
+		first, second := uuid.New(), uuid.New()
+
+		noteRepo.EXPECT().Export(ctx, userID, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ uuid.UUID, _ repository.NoteListParams, _ int, fn func([]entity.Note) error) error {
+				if err := fn([]entity.Note{{ID: first, UserID: userID}}); err != nil {
+					return err
+				}
+				return fn([]entity.Note{{ID: second, UserID: userID}})
+			})
+		photoRepo.EXPECT().GetByNoteIDs(ctx, []uuid.UUID{first}).Return([]entity.Photo{{ID: uuid.New(), NoteID: first}}, nil)
+		photoRepo.EXPECT().GetByNoteIDs(ctx, []uuid.UUID{second}).Return(nil, nil)
+
+		var got []entity.Note
+		err := svc.Export(ctx, note.ExportInput{UserID: userID}, func(notes []entity.Note) error {
+			got = append(got, notes...)
+			return nil
+		})
 
-		noteRepo.EXPECT().GetByID(ctx, noteID).Return(n, nil)
-		noteRepo.EXPECT().SoftDelete(ctx, noteID).Return(nil)
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Len(t, got[0].Photos, 1)
+		assert.Empty(t, got[1].Photos)
+	})
+
+	t.Run("stops when loading photos fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, nil, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		noteRepo.EXPECT().Export(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ uuid.UUID, _ repository.NoteListParams, _ int, fn func([]entity.Note) error) error {
+				return fn([]entity.Note{{ID: uuid.New()}})
+			})
+		photoRepo.EXPECT().GetByNoteIDs(ctx, gomock.Any()).Return(nil, assert.AnError)
+
+		err := svc.Export(ctx, note.ExportInput{UserID: uuid.New()}, func([]entity.Note) error {
+			t.Fatal("callback should not run")
+			return nil
+		})
+
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("passes the filters to the repository", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := note.NewService(noteRepo, nil, nil, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := from.AddDate(0, 1, 0)
+		bbox := valueobject.NewBoundingBox(38, 39, -10, -9)
+
+		noteRepo.EXPECT().Export(ctx, gomock.Any(), repository.NoteListParams{
+			BoundingBox: bbox,
+			Source:      entity.NoteSourceImport,
+			Tags:        []string{"soil"},
+			CreatedFrom: &from,
+			CreatedTo:   &to,
+		}, gomock.Any(), gomock.Any()).Return(nil)
+
+		err := svc.Export(ctx, note.ExportInput{
+			UserID:      uuid.New(),
+			BoundingBox: bbox,
+			Source:      entity.NoteSourceImport,
+			Tags:        []string{"Soil"},
+			From:        &from,
+			To:          &to,
+		}, func([]entity.Note) error { return nil })
+
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects an empty date range", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc := note.NewService(mocks.NewMockNoteRepository(ctrl), nil, nil, ownerOnly(ctrl), nil, nil, nil)
+
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		err := svc.Export(context.Background(), note.ExportInput{From: &from, To: &from}, func([]entity.Note) error { return nil })
+
+		assert.ErrorIs(t, err, domain.ErrInvalidDateRange)
+	})
+}
+
+func TestService_GetByID(t *testing.T) {
+	t.Run("returns note for owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, ruleRepo, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		noteID := uuid.New()
+		n := &entity.Note{ID: noteID, UserID: userID, Title: "Test Note"}
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(n, nil)
+		photoRepo.EXPECT().GetByNoteID(ctx, noteID).Return([]entity.Photo{}, nil)
+
+		result, err := svc.GetByID(ctx, userID, noteID)
+
+		require.NoError(t, err)
+		assert.Equal(t, noteID, result.ID)
+	})
+
+	t.Run("returns note shared with the user", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		shareRepo := mocks.NewMockShareRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, nil, authz.NewAuthorizer(shareRepo, nil, nil), nil, nil, nil)
+
+		ctx := context.Background()
+		viewerID := uuid.New()
+		noteID := uuid.New()
+		n := &entity.Note{ID: noteID, UserID: uuid.New(), Title: "Shared"}
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(n, nil)
+		shareRepo.EXPECT().GetRole(ctx, noteID, viewerID).Return(entity.ShareRoleViewer, nil)
+		photoRepo.EXPECT().GetByNoteID(ctx, noteID).Return([]entity.Photo{}, nil)
+
+		result, err := svc.GetByID(ctx, viewerID, noteID)
+
+		require.NoError(t, err)
+		assert.Equal(t, noteID, result.ID)
+	})
+
+	t.Run("returns forbidden for non-owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, ruleRepo, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		ownerID := uuid.New()
+		otherUserID := uuid.New()
+		noteID := uuid.New()
+		n := &entity.Note{ID: noteID, UserID: ownerID, Title: "Test Note"}
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(n, nil)
+
+		result, err := svc.GetByID(ctx, otherUserID, noteID)
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("returns not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, ruleRepo, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		noteID := uuid.New()
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(nil, domain.ErrNoteNotFound)
+
+		result, err := svc.GetByID(ctx, userID, noteID)
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrNoteNotFound)
+	})
+
+	t.Run("returns not found for deleted note", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, ruleRepo, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		noteID := uuid.New()
+		deletedAt := time.Now()
+		n := &entity.Note{ID: noteID, UserID: userID, DeletedAt: &deletedAt}
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(n, nil)
+
+		result, err := svc.GetByID(ctx, userID, noteID)
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrNoteNotFound)
+	})
+}
+
+func TestService_Update(t *testing.T) {
+	t.Run("updates note successfully", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, ruleRepo, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		noteID := uuid.New()
+		n := &entity.Note{
+			ID: noteID, UserID: userID, Title: "Old Title", Content: "Old Content",
+			CreatedByDevice: "device-a", LastModifiedByDevice: "device-a",
+		}
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(n, nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil)
+		noteRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+		photoRepo.EXPECT().GetByNoteID(ctx, noteID).Return([]entity.Photo{}, nil)
+
+		newTitle := "New Title"
+		newContent := "New Content"
+		result, err := svc.Update(ctx, userID, noteID, note.UpdateInput{
+			Title:    &newTitle,
+			Content:  &newContent,
+			DeviceID: "device-b",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "New Title", result.Title)
+		assert.Equal(t, "New Content", result.Content)
+		assert.Equal(t, "device-a", result.CreatedByDevice)
+		assert.Equal(t, "device-b", result.LastModifiedByDevice)
+	})
+
+	t.Run("stamps the fields that changed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+		svc := note.NewService(noteRepo, photoRepo, ruleRepo, ownerOnly(ctrl), nil, nil, clock.NewFake(now))
+
+		ctx := context.Background()
+		userID := uuid.New()
+		created := now.Add(-time.Hour)
+		n := &entity.Note{
+			ID: uuid.New(), UserID: userID, Title: "Oak", Content: "Three oaks",
+			UpdatedAt: created, FieldsUpdatedAt: entity.NoteFieldTimes{Title: created.Add(-time.Hour)},
+		}
+
+		noteRepo.EXPECT().GetByID(ctx, n.ID).Return(n, nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil)
+		noteRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+		photoRepo.EXPECT().GetByNoteID(ctx, n.ID).Return([]entity.Photo{}, nil)
+
+		content := "Four oaks"
+		result, err := svc.Update(ctx, userID, n.ID, note.UpdateInput{Content: &content})
+
+		require.NoError(t, err)
+		assert.Equal(t, now, result.UpdatedAt)
+		assert.Equal(t, entity.NoteFieldTimes{
+			Title: created.Add(-time.Hour), Content: now, Location: created,
+		}, result.FieldsUpdatedAt)
+	})
+
+	t.Run("returns forbidden for non-owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, ruleRepo, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		ownerID := uuid.New()
+		otherUserID := uuid.New()
+		noteID := uuid.New()
+		n := &entity.Note{ID: noteID, UserID: ownerID}
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(n, nil)
+
+		newTitle := "New Title"
+		result, err := svc.Update(ctx, otherUserID, noteID, note.UpdateInput{Title: &newTitle})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("returns not found for deleted note", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, ruleRepo, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		noteID := uuid.New()
+		deletedAt := time.Now()
+		n := &entity.Note{ID: noteID, UserID: userID, DeletedAt: &deletedAt}
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(n, nil)
+
+		newTitle := "New Title"
+		result, err := svc.Update(ctx, userID, noteID, note.UpdateInput{Title: &newTitle})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrNoteNotFound)
+	})
+
+	t.Run("keeps the content of a collaborative note", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, ruleRepo, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		noteID := uuid.New()
+		n := &entity.Note{ID: noteID, UserID: userID, Title: "Transect", Content: "merged edits", Collaborative: true}
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(n, nil)
+
+		stale := "an old copy"
+		result, err := svc.Update(ctx, userID, noteID, note.UpdateInput{Content: &stale})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrNoteCollaborative)
+	})
+
+	t.Run("owner changes sensitivity", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, ruleRepo, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		noteID := uuid.New()
+		n := &entity.Note{ID: noteID, UserID: userID, Sensitivity: entity.SensitivityNone}
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(n, nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil)
+		noteRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+		photoRepo.EXPECT().GetByNoteID(ctx, noteID).Return([]entity.Photo{}, nil)
+
+		level := entity.SensitivityHigh
+		result, err := svc.Update(ctx, userID, noteID, note.UpdateInput{Sensitivity: &level})
+
+		require.NoError(t, err)
+		assert.Equal(t, entity.SensitivityHigh, result.Sensitivity)
+	})
+
+	t.Run("editor cannot change sensitivity", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		shareRepo := mocks.NewMockShareRepository(ctrl)
+		svc := note.NewService(noteRepo, nil, nil, authz.NewAuthorizer(shareRepo, nil, nil), nil, nil, nil)
+
+		ctx := context.Background()
+		editorID := uuid.New()
+		noteID := uuid.New()
+		n := &entity.Note{ID: noteID, UserID: uuid.New(), Sensitivity: entity.SensitivityHigh}
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(n, nil)
+		shareRepo.EXPECT().GetRole(ctx, noteID, editorID).Return(entity.ShareRoleEditor, nil).Times(2)
+
+		level := entity.SensitivityNone
+		result, err := svc.Update(ctx, editorID, noteID, note.UpdateInput{Sensitivity: &level})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("editor cannot move a sensitive note", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		shareRepo := mocks.NewMockShareRepository(ctrl)
+		svc := note.NewService(noteRepo, nil, nil, authz.NewAuthorizer(shareRepo, nil, nil), nil, nil, nil)
+
+		ctx := context.Background()
+		editorID := uuid.New()
+		noteID := uuid.New()
+		n := &entity.Note{
+			ID: noteID, UserID: uuid.New(), Sensitivity: entity.SensitivityLow,
+			Location: valueobject.NewLocation(-23.55052, -46.63331, nil, nil),
+		}
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(n, nil)
+		shareRepo.EXPECT().GetRole(ctx, noteID, editorID).Return(entity.ShareRoleEditor, nil).Times(2)
+
+		result, err := svc.Update(ctx, editorID, noteID, note.UpdateInput{
+			Location: valueobject.NewLocation(-23.555, -46.635, nil, nil),
+		})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("changes and takes off the color and icon", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, ruleRepo, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		userID, noteID := uuid.New(), uuid.New()
+		red, pin := "red", "pin"
+		n := &entity.Note{ID: noteID, UserID: userID, Title: "Plot", Color: &red, Icon: &pin}
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(n, nil).Times(2)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil)
+		noteRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+		photoRepo.EXPECT().GetByNoteID(ctx, noteID).Return(nil, nil)
+
+		blue, none := "blue", ""
+		result, err := svc.Update(ctx, userID, noteID, note.UpdateInput{Color: &blue, Icon: &none})
+		require.NoError(t, err)
+		require.NotNil(t, result.Color)
+		assert.Equal(t, "blue", *result.Color)
+		assert.Nil(t, result.Icon)
+
+		bad := "dragon"
+		_, err = svc.Update(ctx, userID, noteID, note.UpdateInput{Icon: &bad})
+		assert.ErrorIs(t, err, domain.ErrInvalidNoteIcon)
+	})
+
+	t.Run("a generated title follows the content until one is set", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, ruleRepo, ownerOnly(ctrl), nil, autotitle.NewTitler(nil), nil)
+
+		ctx := context.Background()
+		userID, noteID := uuid.New(), uuid.New()
+		n := &entity.Note{ID: noteID, UserID: userID, Title: "Heron nest", Content: "Heron nest", AutoTitled: true}
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(n, nil).Times(3)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil).Times(3)
+		noteRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil).Times(3)
+		photoRepo.EXPECT().GetByNoteID(ctx, noteID).Return(nil, nil).Times(3)
+
+		content := "Egret nest, empty"
+		result, err := svc.Update(ctx, userID, noteID, note.UpdateInput{Content: &content})
+		require.NoError(t, err)
+		assert.Equal(t, "Egret nest, empty", result.Title)
+		assert.True(t, result.AutoTitled)
+
+		content = "Egret nest, one egg"
+		result, err = svc.Update(ctx, userID, noteID, note.UpdateInput{Content: &content, SkipAutoTitle: true})
+		require.NoError(t, err)
+		assert.Equal(t, "Egret nest, empty", result.Title)
+
+		title := "Egrets"
+		result, err = svc.Update(ctx, userID, noteID, note.UpdateInput{Title: &title, Content: &content})
+		require.NoError(t, err)
+		assert.Equal(t, "Egrets", result.Title)
+		assert.False(t, result.AutoTitled)
+	})
+}
+
+func TestService_Delete(t *testing.T) {
+	t.Run("soft deletes note successfully", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		auditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, ruleRepo, ownerOnly(ctrl), audit.NewRecorder(auditRepo), nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		noteID := uuid.New()
+		n := &entity.Note{ID: noteID, UserID: userID}
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(n, nil)
+		noteRepo.EXPECT().SoftDelete(ctx, userID, noteID).Return(nil)
+		auditRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, event *entity.AuditEvent) error {
+			assert.Equal(t, userID, event.UserID)
+			assert.Equal(t, entity.AuditNoteDeleted, event.Action)
+			assert.Equal(t, noteID.String(), event.Target)
+			return nil
+		})
 
 		err := svc.Delete(ctx, userID, noteID)
 
@@ -365,7 +1251,8 @@ func TestService_Delete(t *testing.T) {
 
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
 		photoRepo := mocks.NewMockPhotoRepository(ctrl)
-		svc := note.NewService(noteRepo, photoRepo)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, ruleRepo, ownerOnly(ctrl), nil, nil, nil)
 
 		ctx := context.Background()
 		ownerID := uuid.New()
@@ -380,3 +1267,370 @@ func TestService_Delete(t *testing.T) {
 		assert.ErrorIs(t, err, domain.ErrForbidden)
 	})
 }
+
+func TestService_Restore(t *testing.T) {
+	t.Run("restores note deleted within the window", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, nil, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		noteID := uuid.New()
+		deletedAt := time.Now().Add(-24 * time.Hour)
+		n := &entity.Note{ID: noteID, UserID: userID, DeletedAt: &deletedAt, UpdatedAt: deletedAt}
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(n, nil)
+		noteRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, updated *entity.Note) error {
+				assert.Nil(t, updated.DeletedAt)
+				assert.True(t, updated.UpdatedAt.After(deletedAt))
+				return nil
+			})
+		photoRepo.EXPECT().GetByNoteID(ctx, noteID).Return([]entity.Photo{}, nil)
+
+		result, err := svc.Restore(ctx, userID, noteID)
+
+		require.NoError(t, err)
+		assert.False(t, result.IsDeleted())
+	})
+
+	t.Run("returns restore expired after the window", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := note.NewService(noteRepo, nil, nil, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		noteID := uuid.New()
+		deletedAt := time.Now().Add(-entity.NoteRestoreWindow - time.Hour)
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(&entity.Note{ID: noteID, UserID: userID, DeletedAt: &deletedAt}, nil)
+
+		result, err := svc.Restore(ctx, userID, noteID)
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrRestoreExpired)
+	})
+
+	t.Run("returns forbidden for non-owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := note.NewService(noteRepo, nil, nil, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		noteID := uuid.New()
+		deletedAt := time.Now()
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(&entity.Note{ID: noteID, UserID: uuid.New(), DeletedAt: &deletedAt}, nil)
+
+		result, err := svc.Restore(ctx, uuid.New(), noteID)
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("returns forbidden for editors the note is shared with", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		shareRepo := mocks.NewMockShareRepository(ctrl)
+		orgRepo := mocks.NewMockOrganizationRepository(ctrl)
+		svc := note.NewService(noteRepo, nil, nil, authz.NewAuthorizer(shareRepo, orgRepo, nil), nil, nil, nil)
+
+		ctx := context.Background()
+		editorID := uuid.New()
+		noteID := uuid.New()
+		deletedAt := time.Now()
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(&entity.Note{ID: noteID, UserID: uuid.New(), DeletedAt: &deletedAt}, nil)
+		shareRepo.EXPECT().GetRole(ctx, noteID, editorID).Return(entity.ShareRoleEditor, nil)
+		orgRepo.EXPECT().IsAdminOver(ctx, editorID, gomock.Any()).Return(false, nil).AnyTimes()
+
+		result, err := svc.Restore(ctx, editorID, noteID)
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+}
+
+func TestService_Exists(t *testing.T) {
+	t.Run("splits found notes from missing ids", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := note.NewService(noteRepo, nil, nil, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		byID, byClientID, missing := uuid.New(), uuid.New(), uuid.New()
+		deletedAt := time.Now()
+		found := []entity.NoteExistence{
+			{ID: byID, ClientID: "local-1", UpdatedAt: time.Now()},
+			{ID: byClientID, ClientID: "local-2", UpdatedAt: time.Now(), DeletedAt: &deletedAt},
+		}
+
+		noteRepo.EXPECT().Existing(ctx, userID, []uuid.UUID{byID, missing}, []string{"local-2", "local-3"}).Return(found, nil)
+
+		result, err := svc.Exists(ctx, note.ExistsInput{
+			UserID:    userID,
+			IDs:       []uuid.UUID{byID, missing},
+			ClientIDs: []string{"local-2", "local-3"},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, found, result.Found)
+		assert.Equal(t, []uuid.UUID{missing}, result.MissingIDs)
+		assert.Equal(t, []string{"local-3"}, result.MissingClientIDs)
+	})
+
+	t.Run("skips the lookup without ids", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc := note.NewService(mocks.NewMockNoteRepository(ctrl), nil, nil, ownerOnly(ctrl), nil, nil, nil)
+
+		result, err := svc.Exists(context.Background(), note.ExistsInput{UserID: uuid.New()})
+
+		require.NoError(t, err)
+		assert.Empty(t, result.Found)
+	})
+}
+
+func TestService_Merge(t *testing.T) {
+	t.Run("merges notes into the first one", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, ruleRepo, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		a := &entity.Note{
+			ID: uuid.New(), UserID: userID, Title: "Plot 3", Content: "Oak",
+			Measurements: []valueobject.Measurement{{Name: "dbh", Kind: valueobject.MeasurementLength, Value: 0.3}},
+		}
+		b := &entity.Note{
+			ID: uuid.New(), UserID: userID, Title: "Plot 3 (north)", Content: "Lichen on bark",
+			Location:     valueobject.NewLocation(-23.5, -46.6, nil, nil),
+			Sensitivity:  entity.SensitivityLow,
+			Measurements: []valueobject.Measurement{{Name: "dbh", Kind: valueobject.MeasurementLength, Value: 0.31}, {Name: "height", Kind: valueobject.MeasurementLength, Value: 12}},
+		}
+		photo := entity.Photo{ID: uuid.New(), NoteID: b.ID}
+
+		noteRepo.EXPECT().GetByID(ctx, a.ID).Return(a, nil)
+		noteRepo.EXPECT().GetByID(ctx, b.ID).Return(b, nil)
+		photoRepo.EXPECT().GetByNoteID(ctx, a.ID).Return([]entity.Photo{}, nil)
+		photoRepo.EXPECT().GetByNoteID(ctx, b.ID).Return([]entity.Photo{photo}, nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil)
+		noteRepo.EXPECT().Merge(ctx, gomock.Any(), []uuid.UUID{b.ID}).Return(nil)
+
+		result, err := svc.Merge(ctx, note.MergeInput{
+			UserID:    userID,
+			NoteIDs:   []uuid.UUID{a.ID, b.ID},
+			TitleFrom: b.ID,
+			Content:   entity.MergeContentConcatenate,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, a.ID, result.ID)
+		assert.Equal(t, "Plot 3 (north)", result.Title)
+		assert.Equal(t, "Oak\n\nLichen on bark", result.Content)
+		assert.Equal(t, b.Location, result.Location)
+		assert.Equal(t, entity.SensitivityLow, result.Sensitivity)
+		require.Len(t, result.Measurements, 2)
+		assert.Equal(t, 0.3, result.Measurements[0].Value)
+		assert.Len(t, result.Photos, 1)
+	})
+
+	t.Run("rejects repeated notes", func(t *testing.T) {
+		svc := note.NewService(nil, nil, nil, nil, nil, nil, nil)
+		id := uuid.New()
+
+		result, err := svc.Merge(context.Background(), note.MergeInput{
+			UserID:  uuid.New(),
+			NoteIDs: []uuid.UUID{id, id},
+			Content: entity.MergeContentKeep,
+		})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrInvalidMerge)
+	})
+
+	t.Run("rejects notes of other users", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := note.NewService(noteRepo, nil, nil, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		a := &entity.Note{ID: uuid.New(), UserID: userID}
+		b := &entity.Note{ID: uuid.New(), UserID: uuid.New()}
+
+		noteRepo.EXPECT().GetByID(ctx, a.ID).Return(a, nil)
+		noteRepo.EXPECT().GetByID(ctx, b.ID).Return(b, nil)
+
+		result, err := svc.Merge(ctx, note.MergeInput{
+			UserID:  userID,
+			NoteIDs: []uuid.UUID{a.ID, b.ID},
+			Content: entity.MergeContentConcatenate,
+		})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("rejects collaborative notes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := note.NewService(noteRepo, nil, nil, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		a := &entity.Note{ID: uuid.New(), UserID: userID}
+		b := &entity.Note{ID: uuid.New(), UserID: userID, Collaborative: true}
+
+		noteRepo.EXPECT().GetByID(ctx, a.ID).Return(a, nil)
+		noteRepo.EXPECT().GetByID(ctx, b.ID).Return(b, nil)
+
+		result, err := svc.Merge(ctx, note.MergeInput{
+			UserID:  userID,
+			NoteIDs: []uuid.UUID{a.ID, b.ID},
+			Content: entity.MergeContentConcatenate,
+		})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrNoteCollaborative)
+	})
+}
+
+func TestService_Tags(t *testing.T) {
+	t.Run("adds normalized tags", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, nil, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		existing := &entity.Note{ID: uuid.New(), UserID: userID, Tags: []string{"wildlife"}}
+
+		noteRepo.EXPECT().GetByID(ctx, existing.ID).Return(existing, nil)
+		noteRepo.EXPECT().SetTags(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, n *entity.Note) error {
+			assert.Equal(t, []string{"soil-sample", "wildlife"}, n.Tags)
+			assert.Equal(t, "tablet-1", n.LastModifiedByDevice)
+			return nil
+		})
+		photoRepo.EXPECT().GetByNoteID(ctx, existing.ID).Return([]entity.Photo{}, nil)
+
+		result, err := svc.AddTags(ctx, note.TagsInput{
+			UserID:   userID,
+			NoteID:   existing.ID,
+			Tags:     []string{"Soil-Sample", "wildlife"},
+			DeviceID: "tablet-1",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"soil-sample", "wildlife"}, result.Tags)
+	})
+
+	t.Run("removes tags", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		svc := note.NewService(noteRepo, photoRepo, nil, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		existing := &entity.Note{ID: uuid.New(), UserID: userID, Tags: []string{"soil-sample", "wildlife"}}
+
+		noteRepo.EXPECT().GetByID(ctx, existing.ID).Return(existing, nil)
+		noteRepo.EXPECT().SetTags(ctx, gomock.Any()).Return(nil)
+		photoRepo.EXPECT().GetByNoteID(ctx, existing.ID).Return([]entity.Photo{}, nil)
+
+		result, err := svc.RemoveTags(ctx, note.TagsInput{UserID: userID, NoteID: existing.ID, Tags: []string{"wildlife", "unknown"}})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"soil-sample"}, result.Tags)
+	})
+
+	t.Run("rejects invalid tags", func(t *testing.T) {
+		svc := note.NewService(nil, nil, nil, nil, nil, nil, nil)
+
+		result, err := svc.AddTags(context.Background(), note.TagsInput{UserID: uuid.New(), NoteID: uuid.New(), Tags: []string{"soil sample"}})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrInvalidTag)
+	})
+
+	t.Run("rejects too many tags", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := note.NewService(noteRepo, nil, nil, ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		tags := make([]string, entity.MaxNoteTags)
+		for i := range tags {
+			tags[i] = "tag-" + strings.Repeat("a", i+1)
+		}
+		existing := &entity.Note{ID: uuid.New(), UserID: userID, Tags: tags}
+
+		noteRepo.EXPECT().GetByID(ctx, existing.ID).Return(existing, nil)
+
+		result, err := svc.AddTags(ctx, note.TagsInput{UserID: userID, NoteID: existing.ID, Tags: []string{"one-more"}})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrTooManyTags)
+	})
+}
+
+func TestService_Coverage(t *testing.T) {
+	t.Run("defaults the zoom", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := note.NewService(noteRepo, mocks.NewMockPhotoRepository(ctrl), mocks.NewMockQualityRuleRepository(ctrl), ownerOnly(ctrl), nil, nil, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		want := &entity.NoteCoverage{Zoom: note.DefaultCoverageZoom}
+		noteRepo.EXPECT().Coverage(ctx, userID, note.DefaultCoverageZoom, gomock.Any()).Return(want, nil)
+
+		coverage, err := svc.Coverage(ctx, userID, 0)
+
+		require.NoError(t, err)
+		assert.Equal(t, want, coverage)
+	})
+
+	t.Run("rejects zooms out of range", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		svc := note.NewService(mocks.NewMockNoteRepository(ctrl), mocks.NewMockPhotoRepository(ctrl), mocks.NewMockQualityRuleRepository(ctrl), ownerOnly(ctrl), nil, nil, nil)
+
+		for _, zoom := range []int{-1, note.MaxCoverageZoom + 1} {
+			_, err := svc.Coverage(context.Background(), uuid.New(), zoom)
+			assert.ErrorIs(t, err, domain.ErrInvalidZoom)
+		}
+	})
+}
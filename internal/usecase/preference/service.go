@@ -0,0 +1,94 @@
+package preference
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+)
+
+var notePrefixPattern = regexp.MustCompile(`^[A-Z0-9]{1,16}$`)
+
+type Service struct {
+	userRepo repository.UserRepository
+}
+
+func NewService(userRepo repository.UserRepository) *Service {
+	return &Service{userRepo: userRepo}
+}
+
+func (s *Service) Get(ctx context.Context, userID uuid.UUID) (*entity.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.UnitSystem == "" {
+		user.UnitSystem = valueobject.UnitSystemMetric
+	}
+	if user.NotePrefix == "" {
+		user.NotePrefix = entity.DefaultNotePrefix
+	}
+	return user, nil
+}
+
+// UnitSystem returns the unit system the user wants measurements presented in.
+func (s *Service) UnitSystem(ctx context.Context, userID uuid.UUID) (string, error) {
+	user, err := s.Get(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return user.UnitSystem, nil
+}
+
+// UpdateInput changes the fields that are set and keeps the others.
+type UpdateInput struct {
+	UserID     uuid.UUID
+	UnitSystem string
+	// NotePrefix is upper-cased; only notes created afterwards use it.
+	NotePrefix string
+	// NotifySyncConflicts is left unchanged when nil.
+	NotifySyncConflicts *bool
+	// SemanticSearch is left unchanged when nil.
+	SemanticSearch *bool
+}
+
+func (s *Service) Update(ctx context.Context, input UpdateInput) (*entity.User, error) {
+	if input.UnitSystem != "" && !valueobject.IsUnitSystem(input.UnitSystem) {
+		return nil, domain.ErrInvalidUnitSystem
+	}
+
+	prefix := strings.ToUpper(input.NotePrefix)
+	if prefix != "" && !notePrefixPattern.MatchString(prefix) {
+		return nil, domain.ErrInvalidNotePrefix
+	}
+
+	user, err := s.Get(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.UnitSystem != "" {
+		user.SetUnitSystem(input.UnitSystem)
+	}
+	if prefix != "" {
+		user.SetNotePrefix(prefix)
+	}
+	if input.NotifySyncConflicts != nil {
+		user.SetNotifySyncConflicts(*input.NotifySyncConflicts)
+	}
+	if input.SemanticSearch != nil {
+		user.SetSemanticSearch(*input.SemanticSearch)
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("updating user: %w", err)
+	}
+	return user, nil
+}
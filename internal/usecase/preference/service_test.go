@@ -0,0 +1,153 @@
+package preference_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/preference"
+)
+
+func TestService_UnitSystem(t *testing.T) {
+	t.Run("defaults to metric", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		svc := preference.NewService(userRepo)
+
+		ctx := context.Background()
+		userID := uuid.New()
+
+		userRepo.EXPECT().GetByID(ctx, userID).Return(&entity.User{ID: userID}, nil)
+
+		system, err := svc.UnitSystem(ctx, userID)
+
+		require.NoError(t, err)
+		assert.Equal(t, valueobject.UnitSystemMetric, system)
+	})
+}
+
+func TestService_Update(t *testing.T) {
+	t.Run("stores unit system and upper-cased prefix", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		svc := preference.NewService(userRepo)
+
+		ctx := context.Background()
+		userID := uuid.New()
+
+		userRepo.EXPECT().GetByID(ctx, userID).Return(&entity.User{ID: userID}, nil)
+		userRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, u *entity.User) error {
+				assert.Equal(t, valueobject.UnitSystemImperial, u.UnitSystem)
+				assert.Equal(t, "PLOT", u.NotePrefix)
+				return nil
+			})
+
+		user, err := svc.Update(ctx, preference.UpdateInput{
+			UserID:     userID,
+			UnitSystem: valueobject.UnitSystemImperial,
+			NotePrefix: "plot",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "PLOT", user.NotePrefix)
+	})
+
+	t.Run("keeps fields that are not set", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		svc := preference.NewService(userRepo)
+
+		ctx := context.Background()
+		userID := uuid.New()
+
+		userRepo.EXPECT().GetByID(ctx, userID).Return(&entity.User{ID: userID, UnitSystem: valueobject.UnitSystemImperial, NotifySyncConflicts: true}, nil)
+		userRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+		user, err := svc.Update(ctx, preference.UpdateInput{UserID: userID, NotePrefix: "TREE"})
+
+		require.NoError(t, err)
+		assert.Equal(t, valueobject.UnitSystemImperial, user.UnitSystem)
+		assert.Equal(t, "TREE", user.NotePrefix)
+		assert.True(t, user.NotifySyncConflicts)
+	})
+
+	t.Run("turns off sync conflict notifications", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		svc := preference.NewService(userRepo)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		notify := false
+
+		userRepo.EXPECT().GetByID(ctx, userID).Return(&entity.User{ID: userID, NotifySyncConflicts: true}, nil)
+		userRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+		user, err := svc.Update(ctx, preference.UpdateInput{UserID: userID, NotifySyncConflicts: &notify})
+
+		require.NoError(t, err)
+		assert.False(t, user.NotifySyncConflicts)
+	})
+
+	t.Run("opts in to semantic search", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		svc := preference.NewService(userRepo)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		enabled := true
+
+		userRepo.EXPECT().GetByID(ctx, userID).Return(&entity.User{ID: userID, NotifySyncConflicts: true}, nil)
+		userRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+		user, err := svc.Update(ctx, preference.UpdateInput{UserID: userID, SemanticSearch: &enabled})
+
+		require.NoError(t, err)
+		assert.True(t, user.SemanticSearch)
+		assert.True(t, user.NotifySyncConflicts)
+	})
+
+	t.Run("rejects unknown unit system", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc := preference.NewService(mocks.NewMockUserRepository(ctrl))
+
+		user, err := svc.Update(context.Background(), preference.UpdateInput{UserID: uuid.New(), UnitSystem: "nautical"})
+
+		assert.Nil(t, user)
+		assert.ErrorIs(t, err, domain.ErrInvalidUnitSystem)
+	})
+
+	t.Run("rejects invalid note prefix", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc := preference.NewService(mocks.NewMockUserRepository(ctrl))
+
+		user, err := svc.Update(context.Background(), preference.UpdateInput{UserID: uuid.New(), NotePrefix: "PLOT-A"})
+
+		assert.Nil(t, user)
+		assert.ErrorIs(t, err, domain.ErrInvalidNotePrefix)
+	})
+}
@@ -0,0 +1,44 @@
+package privacy
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/authz"
+)
+
+type Service struct {
+	noteRepo   repository.NoteRepository
+	authorizer *authz.Authorizer
+	scanner    entity.PIIScanner
+}
+
+func NewService(noteRepo repository.NoteRepository, authorizer *authz.Authorizer, scanner entity.PIIScanner) *Service {
+	return &Service{
+		noteRepo:   noteRepo,
+		authorizer: authorizer,
+		scanner:    scanner,
+	}
+}
+
+// Lint scans a note for personal and sensitive data before it leaves the
+// account. Only users who may share the note can lint it.
+func (s *Service) Lint(ctx context.Context, userID, noteID uuid.UUID) ([]entity.PIIFinding, error) {
+	note, err := s.noteRepo.GetByID(ctx, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if note.IsDeleted() {
+		return nil, domain.ErrNoteNotFound
+	}
+
+	if err := s.authorizer.Authorize(ctx, userID, authz.ActionShare, note); err != nil {
+		return nil, err
+	}
+
+	return s.scanner.Scan(note), nil
+}
@@ -0,0 +1,100 @@
+package privacy_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/authz"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/privacy"
+)
+
+var allDetectors = entity.PIIScanner{
+	Detectors:       []string{entity.PIIEmail, entity.PIIPhone, entity.PIICoordinates, entity.PIIPreciseLocation},
+	PreciseAccuracy: 100,
+}
+
+func TestService_Lint(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reports personal data with character offsets", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := privacy.NewService(noteRepo, authz.NewAuthorizer(nil, nil, nil), allDetectors)
+
+		accuracy := 5.0
+		note := &entity.Note{
+			ID:       uuid.New(),
+			UserID:   uuid.New(),
+			Title:    "Ninho encontrado",
+			Content:  "Contacto: joão@example.com ou +351 912 345 678. Ninho em -25.428412, -49.273301.",
+			Location: valueobject.NewLocation(-25.4284, -49.2733, nil, &accuracy),
+		}
+		noteRepo.EXPECT().GetByID(ctx, note.ID).Return(note, nil)
+
+		findings, err := svc.Lint(ctx, note.UserID, note.ID)
+
+		require.NoError(t, err)
+		require.Len(t, findings, 4)
+		assert.Equal(t, entity.PIIFinding{Kind: entity.PIIEmail, Field: "content", Match: "joão@example.com", Start: 10, End: 26}, findings[0])
+		assert.Equal(t, entity.PIIPhone, findings[1].Kind)
+		assert.Equal(t, "+351 912 345 678", findings[1].Match)
+		assert.Equal(t, entity.PIICoordinates, findings[2].Kind)
+		assert.Equal(t, "-25.428412, -49.273301", findings[2].Match)
+		assert.Equal(t, entity.PIIFinding{Kind: entity.PIIPreciseLocation, Field: "location"}, findings[3])
+	})
+
+	t.Run("ignores dates, measurements and disabled detectors", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := privacy.NewService(noteRepo, authz.NewAuthorizer(nil, nil, nil), entity.PIIScanner{
+			Detectors: []string{entity.PIIEmail, entity.PIIPhone},
+		})
+
+		note := &entity.Note{
+			ID:       uuid.New(),
+			UserID:   uuid.New(),
+			Content:  "Visita de 2024-05-12, altura 21.5 m, 12 indivíduos em -25.428412, -49.273301.",
+			Location: valueobject.NewLocation(-25.4284, -49.2733, nil, nil),
+		}
+		noteRepo.EXPECT().GetByID(ctx, note.ID).Return(note, nil)
+
+		findings, err := svc.Lint(ctx, note.UserID, note.ID)
+
+		require.NoError(t, err)
+		assert.Empty(t, findings)
+	})
+
+	t.Run("denies users who cannot share the note", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		shareRepo := mocks.NewMockShareRepository(ctrl)
+		orgRepo := mocks.NewMockOrganizationRepository(ctrl)
+		svc := privacy.NewService(noteRepo, authz.NewAuthorizer(shareRepo, orgRepo, nil), allDetectors)
+
+		viewerID := uuid.New()
+		note := &entity.Note{ID: uuid.New(), UserID: uuid.New()}
+		noteRepo.EXPECT().GetByID(ctx, note.ID).Return(note, nil)
+		shareRepo.EXPECT().GetRole(ctx, note.ID, viewerID).Return(entity.ShareRoleViewer, nil)
+		orgRepo.EXPECT().IsAdminOver(ctx, viewerID, note.UserID).Return(false, nil).AnyTimes()
+
+		findings, err := svc.Lint(ctx, viewerID, note.ID)
+
+		assert.Nil(t, findings)
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+}
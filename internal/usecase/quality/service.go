@@ -0,0 +1,112 @@
+package quality
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+)
+
+const reevaluatePageSize = 100
+
+type Service struct {
+	ruleRepo  repository.QualityRuleRepository
+	noteRepo  repository.NoteRepository
+	photoRepo repository.PhotoRepository
+}
+
+func NewService(ruleRepo repository.QualityRuleRepository, noteRepo repository.NoteRepository, photoRepo repository.PhotoRepository) *Service {
+	return &Service{
+		ruleRepo:  ruleRepo,
+		noteRepo:  noteRepo,
+		photoRepo: photoRepo,
+	}
+}
+
+func (s *Service) GetRules(ctx context.Context, userID uuid.UUID) (*entity.QualityRules, error) {
+	rules, err := s.ruleRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("getting quality rules: %w", err)
+	}
+	return rules, nil
+}
+
+type RulesInput struct {
+	UserID           uuid.UUID
+	RequirePhoto     bool
+	MinContentLength int
+	Fence            *valueobject.BoundingBox
+	MaxAccuracy      *float64
+}
+
+// UpdateRules replaces the user's rules and re-evaluates every note so the
+// stored results and the report reflect the new rules.
+func (s *Service) UpdateRules(ctx context.Context, input RulesInput) (*entity.QualityRules, error) {
+	if input.Fence != nil && !input.Fence.IsValid() {
+		return nil, domain.ErrInvalidBoundingBox
+	}
+
+	rules := &entity.QualityRules{
+		UserID:           input.UserID,
+		RequirePhoto:     input.RequirePhoto,
+		MinContentLength: input.MinContentLength,
+		Fence:            input.Fence,
+		MaxAccuracy:      input.MaxAccuracy,
+		UpdatedAt:        time.Now().UTC(),
+	}
+
+	if err := s.ruleRepo.Upsert(ctx, rules); err != nil {
+		return nil, fmt.Errorf("saving quality rules: %w", err)
+	}
+
+	if err := s.reevaluate(ctx, rules); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (s *Service) reevaluate(ctx context.Context, rules *entity.QualityRules) error {
+	for page := 1; ; page++ {
+		notes, info, err := s.noteRepo.List(ctx, rules.UserID, repository.NoteListParams{
+			Pagination: pagination.Params{Page: page, PerPage: reevaluatePageSize},
+		})
+		if err != nil {
+			return fmt.Errorf("listing notes: %w", err)
+		}
+
+		for i := range notes {
+			photoCount := 0
+			if rules.RequirePhoto {
+				photos, err := s.photoRepo.GetByNoteID(ctx, notes[i].ID)
+				if err != nil {
+					return fmt.Errorf("loading photos: %w", err)
+				}
+				photoCount = len(photos)
+			}
+
+			if err := s.noteRepo.UpdateQuality(ctx, notes[i].UserID, notes[i].ID, rules.Evaluate(&notes[i], photoCount)); err != nil {
+				return fmt.Errorf("updating note quality: %w", err)
+			}
+		}
+
+		if page >= info.TotalPages {
+			return nil
+		}
+	}
+}
+
+func (s *Service) Report(ctx context.Context, userID uuid.UUID) (*entity.QualityReport, error) {
+	report, err := s.noteRepo.GetQualityReport(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("getting quality report: %w", err)
+	}
+	return report, nil
+}
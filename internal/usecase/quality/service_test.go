@@ -0,0 +1,95 @@
+package quality_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/quality"
+)
+
+func TestService_UpdateRules(t *testing.T) {
+	t.Run("saves rules and re-evaluates notes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		svc := quality.NewService(ruleRepo, noteRepo, photoRepo)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		inside := entity.Note{ID: uuid.New(), UserID: userID, Location: valueobject.NewLocation(-23.5, -46.6, nil, nil)}
+		outside := entity.Note{ID: uuid.New(), UserID: userID, Location: valueobject.NewLocation(40.7, -74.0, nil, nil)}
+		pageInfo := &pagination.Info{Page: 1, PerPage: 100, TotalItems: 2, TotalPages: 1}
+
+		ruleRepo.EXPECT().Upsert(ctx, gomock.Any()).Return(nil)
+		noteRepo.EXPECT().List(ctx, userID, gomock.Any()).Return([]entity.Note{inside, outside}, pageInfo, nil)
+		noteRepo.EXPECT().UpdateQuality(ctx, userID, inside.ID, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _, _ uuid.UUID, result entity.QualityResult) error {
+				assert.Equal(t, entity.QualityPassed, result.Status)
+				return nil
+			})
+		noteRepo.EXPECT().UpdateQuality(ctx, userID, outside.ID, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _, _ uuid.UUID, result entity.QualityResult) error {
+				assert.Equal(t, entity.QualityFailed, result.Status)
+				assert.Equal(t, []string{entity.QualityRuleWithinFence}, result.Failed)
+				return nil
+			})
+
+		rules, err := svc.UpdateRules(ctx, quality.RulesInput{
+			UserID: userID,
+			Fence:  valueobject.NewBoundingBox(-24, -23, -47, -46),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, userID, rules.UserID)
+		assert.NotNil(t, rules.Fence)
+	})
+
+	t.Run("rejects invalid fence", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc := quality.NewService(mocks.NewMockQualityRuleRepository(ctrl), nil, nil)
+
+		rules, err := svc.UpdateRules(context.Background(), quality.RulesInput{
+			UserID: uuid.New(),
+			Fence:  valueobject.NewBoundingBox(10, -10, 0, 1),
+		})
+
+		assert.Nil(t, rules)
+		assert.ErrorIs(t, err, domain.ErrInvalidBoundingBox)
+	})
+}
+
+func TestService_Report(t *testing.T) {
+	t.Run("returns report from repository", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := quality.NewService(nil, noteRepo, nil)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		report := &entity.QualityReport{Total: 3, Failed: 1, Passed: 2, RuleFailures: map[string]int{"required_photo": 1}}
+
+		noteRepo.EXPECT().GetQualityReport(ctx, userID).Return(report, nil)
+
+		result, err := svc.Report(ctx, userID)
+
+		require.NoError(t, err)
+		assert.Equal(t, report, result)
+	})
+}
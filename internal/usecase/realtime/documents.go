@@ -0,0 +1,273 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/crdt"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/authz"
+)
+
+const (
+	// ServerReplica is the replica that inserts a note's content when its
+	// document is created.
+	ServerReplica = "server"
+	// MaxDocumentOps bounds the ops of one update.
+	MaxDocumentOps = 1000
+	// documentBuffer is how many updates a watcher can fall behind on a
+	// note before the next ones are dropped; the app then sees a gap in the
+	// seq numbers and fetches what it missed.
+	documentBuffer = 64
+)
+
+// DocumentRelay carries document updates between API instances, like Relay
+// does for change events.
+type DocumentRelay interface {
+	Publish(ctx context.Context, update entity.DocumentUpdate) error
+	Listen(ctx context.Context, deliver func(entity.DocumentUpdate)) error
+}
+
+// Documents is the collaborative editing mode of notes. A collaborative
+// note's content is a CRDT document that co-editors change with updates
+// instead of saving the whole content, so concurrent edits merge instead of
+// the last save winning. The note's content follows the document.
+type Documents struct {
+	noteRepo   repository.NoteRepository
+	docRepo    repository.NoteDocumentRepository
+	authorizer *authz.Authorizer
+	relay      DocumentRelay
+
+	mu       sync.Mutex
+	watchers map[uuid.UUID]map[chan entity.DocumentUpdate]struct{}
+}
+
+// NewDocuments returns the service. Without a relay only the connections to
+// this instance hear about updates made through it.
+func NewDocuments(
+	noteRepo repository.NoteRepository,
+	docRepo repository.NoteDocumentRepository,
+	authorizer *authz.Authorizer,
+	relay DocumentRelay,
+) *Documents {
+	return &Documents{
+		noteRepo:   noteRepo,
+		docRepo:    docRepo,
+		authorizer: authorizer,
+		relay:      relay,
+		watchers:   make(map[uuid.UUID]map[chan entity.DocumentUpdate]struct{}),
+	}
+}
+
+// Enable makes the note collaborative, starting its document from the
+// current content. Only the owner can; enabling it again returns the
+// existing document.
+func (s *Documents) Enable(ctx context.Context, userID, noteID uuid.UUID) (*entity.NoteDocument, error) {
+	note, err := s.note(ctx, userID, noteID, authz.ActionShare)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := entity.NewNoteDocument(noteID, crdt.FromText(ServerReplica, note.Content).Snapshot())
+	if err := s.docRepo.Create(ctx, doc); err != nil {
+		if errors.Is(err, domain.ErrDocumentExists) {
+			return s.docRepo.Get(ctx, noteID)
+		}
+		return nil, fmt.Errorf("creating note document: %w", err)
+	}
+	return doc, nil
+}
+
+// Disable turns the note back into one whose content is replaced on every
+// save. The content stays as the document left it.
+func (s *Documents) Disable(ctx context.Context, userID, noteID uuid.UUID) error {
+	if _, err := s.note(ctx, userID, noteID, authz.ActionShare); err != nil {
+		return err
+	}
+	return s.docRepo.Delete(ctx, noteID)
+}
+
+// Get returns the note's document for a user who can read the note.
+func (s *Documents) Get(ctx context.Context, userID, noteID uuid.UUID) (*entity.NoteDocument, error) {
+	if _, err := s.note(ctx, userID, noteID, authz.ActionRead); err != nil {
+		return nil, err
+	}
+	return s.docRepo.Get(ctx, noteID)
+}
+
+type ApplyInput struct {
+	UserID uuid.UUID
+	// DeviceID is the registered device that made the update; uuid.Nil when
+	// the token is not bound to one.
+	DeviceID uuid.UUID
+	NoteID   uuid.UUID
+	Ops      []crdt.Op
+}
+
+// Apply applies an update from a user who can edit the note, saves the
+// resulting content and sends the update to the note's watchers. It
+// returns the seq of the document afterwards; an update that was already
+// applied changes nothing and gets the current seq.
+func (s *Documents) Apply(ctx context.Context, input ApplyInput) (int64, error) {
+	if len(input.Ops) == 0 || len(input.Ops) > MaxDocumentOps {
+		return 0, fmt.Errorf("%w: an update has 1 to %d ops", domain.ErrInvalidDocumentUpdate, MaxDocumentOps)
+	}
+	if _, err := s.note(ctx, input.UserID, input.NoteID, authz.ActionEdit); err != nil {
+		return 0, err
+	}
+
+	var seq int64
+	update, err := s.docRepo.Append(ctx, input.NoteID, func(doc *entity.NoteDocument) (*entity.DocumentUpdate, error) {
+		seq = doc.Seq()
+		state, err := doc.State()
+		if err != nil {
+			return nil, fmt.Errorf("rebuilding note document: %w", err)
+		}
+		changed, err := state.Apply(input.Ops)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", domain.ErrInvalidDocumentUpdate, err)
+		}
+		if !changed {
+			return nil, nil
+		}
+		if state.Len() > entity.MaxNoteContentLength {
+			return nil, fmt.Errorf("%w: content over %d characters", domain.ErrInvalidDocumentUpdate, entity.MaxNoteContentLength)
+		}
+
+		// Read again under the document's lock, so a concurrent save of
+		// the note's other fields is not undone.
+		note, err := s.noteRepo.GetByID(ctx, input.NoteID)
+		if err != nil {
+			return nil, err
+		}
+		note.Update(note.Title, state.Text(), note.Location)
+		if err := s.noteRepo.Update(ctx, note); err != nil {
+			return nil, fmt.Errorf("updating note: %w", err)
+		}
+
+		update := &entity.DocumentUpdate{UserID: &input.UserID, Ops: input.Ops, CreatedAt: note.UpdatedAt}
+		if input.DeviceID != uuid.Nil {
+			update.DeviceID = &input.DeviceID
+		}
+		return update, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if update == nil {
+		return seq, nil
+	}
+
+	// The update is saved; watchers on other instances that miss it catch
+	// up from the gap in seq.
+	_ = s.publish(context.WithoutCancel(ctx), *update)
+	return update.Seq, nil
+}
+
+// Compact folds the logs of up to limit documents with at least minUpdates
+// updates into their snapshots, and returns how many it compacted.
+func (s *Documents) Compact(ctx context.Context, minUpdates, limit int) (int, error) {
+	noteIDs, err := s.docRepo.ListCompactable(ctx, minUpdates, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	compacted := 0
+	for _, noteID := range noteIDs {
+		doc, err := s.docRepo.Get(ctx, noteID)
+		if errors.Is(err, domain.ErrDocumentNotFound) {
+			continue
+		}
+		if err != nil {
+			return compacted, err
+		}
+		state, err := doc.State()
+		if err != nil {
+			return compacted, fmt.Errorf("rebuilding document of note %s: %w", noteID, err)
+		}
+		if err := s.docRepo.Compact(ctx, noteID, state.Snapshot(), doc.Seq()); err != nil {
+			return compacted, err
+		}
+		compacted++
+	}
+	return compacted, nil
+}
+
+// Watch returns the updates to the note's document, including those of the
+// watcher itself, and a function that stops watching.
+func (s *Documents) Watch(noteID uuid.UUID) (<-chan entity.DocumentUpdate, func()) {
+	updates := make(chan entity.DocumentUpdate, documentBuffer)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.watchers[noteID] == nil {
+		s.watchers[noteID] = make(map[chan entity.DocumentUpdate]struct{})
+	}
+	s.watchers[noteID][updates] = struct{}{}
+
+	var once sync.Once
+	return updates, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			delete(s.watchers[noteID], updates)
+			if len(s.watchers[noteID]) == 0 {
+				delete(s.watchers, noteID)
+			}
+		})
+	}
+}
+
+// Run delivers the updates relayed from every instance until ctx is done.
+// Without a relay it returns at once.
+func (s *Documents) Run(ctx context.Context) error {
+	if s.relay == nil {
+		return nil
+	}
+	return s.relay.Listen(ctx, s.deliver)
+}
+
+// note returns the note for a user allowed the action on it.
+func (s *Documents) note(ctx context.Context, userID, noteID uuid.UUID, action authz.Action) (*entity.Note, error) {
+	note, err := s.noteRepo.GetByID(ctx, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if note.IsDeleted() {
+		return nil, domain.ErrNoteNotFound
+	}
+	if err := s.authorizer.Authorize(ctx, userID, action, note); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+func (s *Documents) publish(ctx context.Context, update entity.DocumentUpdate) error {
+	if s.relay == nil {
+		s.deliver(update)
+		return nil
+	}
+	if err := s.relay.Publish(ctx, update); err != nil {
+		s.deliver(update)
+		return fmt.Errorf("relaying document update: %w", err)
+	}
+	return nil
+}
+
+func (s *Documents) deliver(update entity.DocumentUpdate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for updates := range s.watchers[update.NoteID] {
+		select {
+		case updates <- update:
+		default:
+		}
+	}
+}
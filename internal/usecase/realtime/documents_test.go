@@ -0,0 +1,164 @@
+package realtime_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/crdt"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/authz"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/realtime"
+)
+
+func TestDocuments(t *testing.T) {
+	ctx := context.Background()
+	ownerID, editorID := uuid.New(), uuid.New()
+
+	setup := func(t *testing.T) (*realtime.Documents, *mocks.MockNoteRepository, *mocks.MockNoteDocumentRepository, *mocks.MockShareRepository) {
+		ctrl := gomock.NewController(t)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		docRepo := mocks.NewMockNoteDocumentRepository(ctrl)
+		shareRepo := mocks.NewMockShareRepository(ctrl)
+		authorizer := authz.NewAuthorizer(shareRepo, mocks.NewMockOrganizationRepository(ctrl), nil)
+		return realtime.NewDocuments(noteRepo, docRepo, authorizer, nil), noteRepo, docRepo, shareRepo
+	}
+
+	// appendTo runs Append's fn against doc, numbering the update as the
+	// repository would.
+	appendTo := func(doc *entity.NoteDocument) func(context.Context, uuid.UUID, func(*entity.NoteDocument) (*entity.DocumentUpdate, error)) (*entity.DocumentUpdate, error) {
+		return func(_ context.Context, _ uuid.UUID, fn func(*entity.NoteDocument) (*entity.DocumentUpdate, error)) (*entity.DocumentUpdate, error) {
+			update, err := fn(doc)
+			if err != nil || update == nil {
+				return nil, err
+			}
+			update.NoteID = doc.NoteID
+			update.Seq = doc.Seq() + 1
+			doc.Updates = append(doc.Updates, *update)
+			return update, nil
+		}
+	}
+
+	t.Run("enable starts the document from the content", func(t *testing.T) {
+		documents, noteRepo, docRepo, _ := setup(t)
+		note := &entity.Note{ID: uuid.New(), UserID: ownerID, Content: "plot 7"}
+		noteRepo.EXPECT().GetByID(ctx, note.ID).Return(note, nil)
+		docRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+		doc, err := documents.Enable(ctx, ownerID, note.ID)
+
+		require.NoError(t, err)
+		state, err := doc.State()
+		require.NoError(t, err)
+		assert.Equal(t, "plot 7", state.Text())
+	})
+
+	t.Run("only the owner enables it", func(t *testing.T) {
+		documents, noteRepo, _, shareRepo := setup(t)
+		note := &entity.Note{ID: uuid.New(), UserID: ownerID}
+		noteRepo.EXPECT().GetByID(ctx, note.ID).Return(note, nil)
+		shareRepo.EXPECT().GetRole(ctx, note.ID, editorID).Return(entity.ShareRoleEditor, nil)
+
+		_, err := documents.Enable(ctx, editorID, note.ID)
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("editors' updates merge into the content and reach watchers", func(t *testing.T) {
+		documents, noteRepo, docRepo, shareRepo := setup(t)
+		note := &entity.Note{ID: uuid.New(), UserID: ownerID, Title: "Transect", Content: "abc"}
+		doc := entity.NewNoteDocument(note.ID, crdt.FromText(realtime.ServerReplica, "abc").Snapshot())
+		noteRepo.EXPECT().GetByID(gomock.Any(), note.ID).Return(note, nil).AnyTimes()
+		shareRepo.EXPECT().GetRole(ctx, note.ID, editorID).Return(entity.ShareRoleEditor, nil).AnyTimes()
+		docRepo.EXPECT().Append(ctx, note.ID, gomock.Any()).DoAndReturn(appendTo(doc)).AnyTimes()
+		var saved []string
+		noteRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, n *entity.Note) error {
+			saved = append(saved, n.Content)
+			return nil
+		}).Times(2)
+
+		updates, stop := documents.Watch(note.ID)
+		defer stop()
+
+		// Both start from "abc" without seeing each other's edit.
+		owner, editor := crdt.FromText(realtime.ServerReplica, "abc"), crdt.FromText(realtime.ServerReplica, "abc")
+		ownerOp, err := owner.Insert("owner", 3, "!")
+		require.NoError(t, err)
+		editorOps, err := editor.Delete(0, 1)
+		require.NoError(t, err)
+
+		seq, err := documents.Apply(ctx, realtime.ApplyInput{UserID: ownerID, NoteID: note.ID, Ops: []crdt.Op{ownerOp}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), seq)
+		deviceID := uuid.New()
+		seq, err = documents.Apply(ctx, realtime.ApplyInput{UserID: editorID, DeviceID: deviceID, NoteID: note.ID, Ops: editorOps})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), seq)
+
+		// A retry changes nothing and is not sent again.
+		seq, err = documents.Apply(ctx, realtime.ApplyInput{UserID: editorID, NoteID: note.ID, Ops: editorOps})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), seq)
+
+		assert.Equal(t, []string{"abc!", "bc!"}, saved)
+		first, second := <-updates, <-updates
+		assert.Equal(t, int64(1), first.Seq)
+		assert.Nil(t, first.DeviceID)
+		assert.Equal(t, int64(2), second.Seq)
+		assert.Equal(t, &deviceID, second.DeviceID)
+		assert.Empty(t, updates)
+	})
+
+	t.Run("rejects updates that do not fit the document", func(t *testing.T) {
+		documents, noteRepo, docRepo, _ := setup(t)
+		note := &entity.Note{ID: uuid.New(), UserID: ownerID}
+		doc := entity.NewNoteDocument(note.ID, crdt.FromText(realtime.ServerReplica, "abc").Snapshot())
+		noteRepo.EXPECT().GetByID(ctx, note.ID).Return(note, nil)
+		docRepo.EXPECT().Append(ctx, note.ID, gomock.Any()).DoAndReturn(appendTo(doc))
+
+		_, err := documents.Apply(ctx, realtime.ApplyInput{UserID: ownerID, NoteID: note.ID, Ops: []crdt.Op{
+			{Op: crdt.OpDelete, ID: crdt.ID{Replica: "ghost", Seq: 9}},
+		}})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidDocumentUpdate)
+		assert.ErrorIs(t, err, crdt.ErrInvalidOp)
+		assert.Empty(t, doc.Updates)
+	})
+
+	t.Run("viewers cannot send updates", func(t *testing.T) {
+		documents, noteRepo, _, shareRepo := setup(t)
+		note := &entity.Note{ID: uuid.New(), UserID: ownerID}
+		noteRepo.EXPECT().GetByID(ctx, note.ID).Return(note, nil)
+		shareRepo.EXPECT().GetRole(ctx, note.ID, editorID).Return(entity.ShareRoleViewer, nil)
+
+		_, err := documents.Apply(ctx, realtime.ApplyInput{UserID: editorID, NoteID: note.ID, Ops: []crdt.Op{
+			{Op: crdt.OpInsert, ID: crdt.ID{Replica: "viewer", Seq: 9}, Text: "x"},
+		}})
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("compact folds the log into the snapshot", func(t *testing.T) {
+		documents, _, docRepo, _ := setup(t)
+		noteID := uuid.New()
+		state := crdt.FromText(realtime.ServerReplica, "abc")
+		doc := entity.NewNoteDocument(noteID, state.Snapshot())
+		op, err := state.Insert("a", 0, ">")
+		require.NoError(t, err)
+		doc.Updates = []entity.DocumentUpdate{{NoteID: noteID, Seq: 1, Ops: []crdt.Op{op}}}
+		docRepo.EXPECT().ListCompactable(ctx, 1, 10).Return([]uuid.UUID{noteID}, nil)
+		docRepo.EXPECT().Get(ctx, noteID).Return(doc, nil)
+		docRepo.EXPECT().Compact(ctx, noteID, state.Snapshot(), int64(1)).Return(nil)
+
+		compacted, err := documents.Compact(ctx, 1, 10)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, compacted)
+	})
+}
@@ -0,0 +1,133 @@
+package realtime
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+// Relay carries change events between API instances, so a device connected
+// to one instance hears about a change made through another.
+type Relay interface {
+	Publish(ctx context.Context, event entity.ChangeEvent) error
+	// Listen calls deliver with every event published by any instance,
+	// including this one, until ctx is done.
+	Listen(ctx context.Context, deliver func(entity.ChangeEvent)) error
+}
+
+type subscriber struct {
+	deviceID uuid.UUID
+	events   chan entity.ChangeEvent
+}
+
+// Hub fans change events out to the connections of each user on this
+// instance. Without a relay it only sees changes made through this
+// instance.
+type Hub struct {
+	relay Relay
+
+	mu     sync.Mutex
+	subs   map[uuid.UUID]map[*subscriber]struct{}
+	closed bool
+}
+
+func NewHub(relay Relay) *Hub {
+	return &Hub{
+		relay: relay,
+		subs:  make(map[uuid.UUID]map[*subscriber]struct{}),
+	}
+}
+
+// Subscribe returns the events for the user's connection from the device,
+// and a function that ends the subscription. Events the connection has not
+// read yet are coalesced into the earliest, which already says there are
+// changes to pull. The channel is closed when the hub closes.
+func (h *Hub) Subscribe(userID, deviceID uuid.UUID) (<-chan entity.ChangeEvent, func()) {
+	sub := &subscriber{deviceID: deviceID, events: make(chan entity.ChangeEvent, 1)}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(sub.events)
+		return sub.events, func() {}
+	}
+	if h.subs[userID] == nil {
+		h.subs[userID] = make(map[*subscriber]struct{})
+	}
+	h.subs[userID][sub] = struct{}{}
+
+	var once sync.Once
+	return sub.events, func() {
+		once.Do(func() { h.unsubscribe(userID, sub) })
+	}
+}
+
+func (h *Hub) unsubscribe(userID uuid.UUID, sub *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[userID][sub]; !ok {
+		return
+	}
+	delete(h.subs[userID], sub)
+	if len(h.subs[userID]) == 0 {
+		delete(h.subs, userID)
+	}
+	close(sub.events)
+}
+
+// Publish tells the user's other devices about the change, through the
+// relay when there is one.
+func (h *Hub) Publish(ctx context.Context, event entity.ChangeEvent) error {
+	if h.relay == nil {
+		h.deliver(event)
+		return nil
+	}
+	if err := h.relay.Publish(ctx, event); err != nil {
+		// The devices connected here still hear about it.
+		h.deliver(event)
+		return fmt.Errorf("relaying change event: %w", err)
+	}
+	return nil
+}
+
+// Run delivers the events relayed from every instance until ctx is done.
+// Without a relay it returns at once.
+func (h *Hub) Run(ctx context.Context) error {
+	if h.relay == nil {
+		return nil
+	}
+	return h.relay.Listen(ctx, h.deliver)
+}
+
+func (h *Hub) deliver(event entity.ChangeEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subs[event.UserID] {
+		if event.DeviceID != uuid.Nil && sub.deviceID == event.DeviceID {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+		}
+	}
+}
+
+// Close ends every subscription, so open connections close on shutdown.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for userID, subs := range h.subs {
+		for sub := range subs {
+			close(sub.events)
+		}
+		delete(h.subs, userID)
+	}
+}
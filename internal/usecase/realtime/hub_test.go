@@ -0,0 +1,87 @@
+package realtime_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/realtime"
+)
+
+type failingRelay struct{}
+
+func (failingRelay) Publish(context.Context, entity.ChangeEvent) error {
+	return errors.New("redis down")
+}
+
+func (failingRelay) Listen(context.Context, func(entity.ChangeEvent)) error {
+	return nil
+}
+
+func TestHub_Publish(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	phone, tablet := uuid.New(), uuid.New()
+
+	t.Run("tells the user's other devices", func(t *testing.T) {
+		hub := realtime.NewHub(nil)
+		phoneEvents, stopPhone := hub.Subscribe(userID, phone)
+		defer stopPhone()
+		tabletEvents, stopTablet := hub.Subscribe(userID, tablet)
+		defer stopTablet()
+		otherEvents, stopOther := hub.Subscribe(uuid.New(), uuid.New())
+		defer stopOther()
+
+		event := entity.ChangeEvent{UserID: userID, DeviceID: phone, Since: time.Now()}
+		require.NoError(t, hub.Publish(ctx, event))
+
+		assert.Equal(t, event, <-tabletEvents)
+		assert.Empty(t, phoneEvents)
+		assert.Empty(t, otherEvents)
+	})
+
+	t.Run("coalesces unread events into the earliest", func(t *testing.T) {
+		hub := realtime.NewHub(nil)
+		events, stop := hub.Subscribe(userID, tablet)
+		defer stop()
+
+		first := time.Now()
+		require.NoError(t, hub.Publish(ctx, entity.ChangeEvent{UserID: userID, Since: first}))
+		require.NoError(t, hub.Publish(ctx, entity.ChangeEvent{UserID: userID, Since: first.Add(time.Second)}))
+
+		assert.Equal(t, first, (<-events).Since)
+		assert.Empty(t, events)
+	})
+
+	t.Run("delivers locally when the relay fails", func(t *testing.T) {
+		hub := realtime.NewHub(failingRelay{})
+		events, stop := hub.Subscribe(userID, tablet)
+		defer stop()
+
+		err := hub.Publish(ctx, entity.ChangeEvent{UserID: userID, DeviceID: phone})
+
+		assert.Error(t, err)
+		assert.Len(t, events, 1)
+	})
+}
+
+func TestHub_Close(t *testing.T) {
+	hub := realtime.NewHub(nil)
+	events, stop := hub.Subscribe(uuid.New(), uuid.New())
+
+	hub.Close()
+	stop()
+
+	_, open := <-events
+	assert.False(t, open)
+
+	late, _ := hub.Subscribe(uuid.New(), uuid.New())
+	_, open = <-late
+	assert.False(t, open)
+}
@@ -0,0 +1,185 @@
+package realtime
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/authz"
+)
+
+// presenceBuffer is how many events a connection can fall behind on a note
+// before the next ones are dropped; the snapshot sent on every heartbeat
+// catches it up.
+const presenceBuffer = 16
+
+// PresenceStore keeps who has each note open. Entries expire after their
+// ttl unless set again.
+type PresenceStore interface {
+	Set(ctx context.Context, presence entity.Presence, ttl time.Duration) error
+	Remove(ctx context.Context, noteID, userID, deviceID uuid.UUID) error
+	// List returns the unexpired entries of the note.
+	List(ctx context.Context, noteID uuid.UUID) ([]entity.Presence, error)
+}
+
+// PresenceRelay carries presence events between API instances, like Relay
+// does for change events.
+type PresenceRelay interface {
+	Publish(ctx context.Context, event entity.PresenceEvent) error
+	Listen(ctx context.Context, deliver func(entity.PresenceEvent)) error
+}
+
+// Presence tracks which devices have a note open and tells the others
+// looking at it, so collaborators on a shared note see each other and,
+// when the apps send them, each other's cursors. Nothing is persisted.
+type Presence struct {
+	noteRepo   repository.NoteRepository
+	authorizer *authz.Authorizer
+	store      PresenceStore
+	relay      PresenceRelay
+	ttl        time.Duration
+
+	mu       sync.Mutex
+	watchers map[uuid.UUID]map[chan entity.PresenceEvent]struct{}
+}
+
+// NewPresence keeps a device's presence for ttl after it was last refreshed.
+// Without a relay only the devices connected to this instance hear about
+// each other.
+func NewPresence(
+	noteRepo repository.NoteRepository,
+	authorizer *authz.Authorizer,
+	store PresenceStore,
+	relay PresenceRelay,
+	ttl time.Duration,
+) *Presence {
+	return &Presence{
+		noteRepo:   noteRepo,
+		authorizer: authorizer,
+		store:      store,
+		relay:      relay,
+		ttl:        ttl,
+		watchers:   make(map[uuid.UUID]map[chan entity.PresenceEvent]struct{}),
+	}
+}
+
+// Open marks the device as looking at the note, which the user must be
+// able to read, and returns who has it open, the device included.
+func (p *Presence) Open(ctx context.Context, presence entity.Presence) ([]entity.Presence, error) {
+	note, err := p.noteRepo.GetByID(ctx, presence.NoteID)
+	if err != nil {
+		return nil, err
+	}
+	if note.IsDeleted() {
+		return nil, domain.ErrNoteNotFound
+	}
+	if err := p.authorizer.Authorize(ctx, presence.UserID, authz.ActionRead, note); err != nil {
+		return nil, err
+	}
+
+	if err := p.Move(ctx, presence); err != nil {
+		return nil, err
+	}
+	return p.Viewers(ctx, presence.NoteID)
+}
+
+// Move updates the device's selection on a note it opened, and tells the
+// others.
+func (p *Presence) Move(ctx context.Context, presence entity.Presence) error {
+	presence.SeenAt = time.Now().UTC()
+	if err := p.store.Set(ctx, presence, p.ttl); err != nil {
+		return fmt.Errorf("setting presence: %w", err)
+	}
+	return p.publish(ctx, entity.PresenceEvent{Presence: presence})
+}
+
+// Keep refreshes the device's presence so it does not expire, and returns
+// who has the note open.
+func (p *Presence) Keep(ctx context.Context, presence entity.Presence) ([]entity.Presence, error) {
+	presence.SeenAt = time.Now().UTC()
+	if err := p.store.Set(ctx, presence, p.ttl); err != nil {
+		return nil, fmt.Errorf("setting presence: %w", err)
+	}
+	return p.Viewers(ctx, presence.NoteID)
+}
+
+// Close removes the device from the note and tells the others.
+func (p *Presence) Close(ctx context.Context, presence entity.Presence) error {
+	if err := p.store.Remove(ctx, presence.NoteID, presence.UserID, presence.DeviceID); err != nil {
+		return fmt.Errorf("removing presence: %w", err)
+	}
+	return p.publish(ctx, entity.PresenceEvent{Presence: presence, Left: true})
+}
+
+// Viewers returns the devices that have the note open.
+func (p *Presence) Viewers(ctx context.Context, noteID uuid.UUID) ([]entity.Presence, error) {
+	viewers, err := p.store.List(ctx, noteID)
+	if err != nil {
+		return nil, fmt.Errorf("listing presence: %w", err)
+	}
+	return viewers, nil
+}
+
+// Watch returns the presence events of the note, the watcher's own
+// included, and a function that stops watching.
+func (p *Presence) Watch(noteID uuid.UUID) (<-chan entity.PresenceEvent, func()) {
+	events := make(chan entity.PresenceEvent, presenceBuffer)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.watchers[noteID] == nil {
+		p.watchers[noteID] = make(map[chan entity.PresenceEvent]struct{})
+	}
+	p.watchers[noteID][events] = struct{}{}
+
+	var once sync.Once
+	return events, func() {
+		once.Do(func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			delete(p.watchers[noteID], events)
+			if len(p.watchers[noteID]) == 0 {
+				delete(p.watchers, noteID)
+			}
+		})
+	}
+}
+
+// Run delivers the events relayed from every instance until ctx is done.
+// Without a relay it returns at once.
+func (p *Presence) Run(ctx context.Context) error {
+	if p.relay == nil {
+		return nil
+	}
+	return p.relay.Listen(ctx, p.deliver)
+}
+
+func (p *Presence) publish(ctx context.Context, event entity.PresenceEvent) error {
+	if p.relay == nil {
+		p.deliver(event)
+		return nil
+	}
+	if err := p.relay.Publish(ctx, event); err != nil {
+		p.deliver(event)
+		return fmt.Errorf("relaying presence event: %w", err)
+	}
+	return nil
+}
+
+func (p *Presence) deliver(event entity.PresenceEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for events := range p.watchers[event.NoteID] {
+		select {
+		case events <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package realtime_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/cache"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/authz"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/realtime"
+)
+
+func TestPresence(t *testing.T) {
+	ctx := context.Background()
+	ownerID, editorID := uuid.New(), uuid.New()
+	note := &entity.Note{ID: uuid.New(), UserID: ownerID}
+
+	setup := func(t *testing.T, ttl time.Duration) (*realtime.Presence, *mocks.MockNoteRepository, *mocks.MockShareRepository, *mocks.MockOrganizationRepository) {
+		ctrl := gomock.NewController(t)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		shareRepo := mocks.NewMockShareRepository(ctrl)
+		orgRepo := mocks.NewMockOrganizationRepository(ctrl)
+		authorizer := authz.NewAuthorizer(shareRepo, orgRepo, nil)
+		return realtime.NewPresence(noteRepo, authorizer, cache.NewMemoryPresenceStore(), nil, ttl), noteRepo, shareRepo, orgRepo
+	}
+
+	t.Run("collaborators see each other open, move and close a note", func(t *testing.T) {
+		presence, noteRepo, shareRepo, _ := setup(t, time.Minute)
+		noteRepo.EXPECT().GetByID(ctx, note.ID).Return(note, nil).Times(2)
+		shareRepo.EXPECT().GetRole(ctx, note.ID, editorID).Return(entity.ShareRoleEditor, nil)
+
+		events, stop := presence.Watch(note.ID)
+		defer stop()
+
+		owner := entity.Presence{NoteID: note.ID, UserID: ownerID, DeviceID: uuid.New()}
+		viewers, err := presence.Open(ctx, owner)
+		require.NoError(t, err)
+		require.Len(t, viewers, 1)
+
+		editor := entity.Presence{NoteID: note.ID, UserID: editorID, DeviceID: uuid.New()}
+		viewers, err = presence.Open(ctx, editor)
+		require.NoError(t, err)
+		require.Len(t, viewers, 2)
+		assert.Equal(t, ownerID, viewers[0].UserID)
+		assert.Equal(t, editorID, viewers[1].UserID)
+
+		editor.Selection = &entity.Selection{Start: 4, End: 9}
+		require.NoError(t, presence.Move(ctx, editor))
+		require.NoError(t, presence.Close(ctx, owner))
+
+		var got []entity.PresenceEvent
+		for range 4 {
+			got = append(got, <-events)
+		}
+		assert.Equal(t, ownerID, got[0].UserID)
+		assert.Equal(t, editorID, got[1].UserID)
+		assert.Equal(t, &entity.Selection{Start: 4, End: 9}, got[2].Selection)
+		assert.True(t, got[3].Left)
+
+		viewers, err = presence.Keep(ctx, editor)
+		require.NoError(t, err)
+		require.Len(t, viewers, 1)
+		assert.Equal(t, editorID, viewers[0].UserID)
+		assert.Equal(t, &entity.Selection{Start: 4, End: 9}, viewers[0].Selection)
+	})
+
+	t.Run("refuses users who cannot read the note", func(t *testing.T) {
+		presence, noteRepo, shareRepo, orgRepo := setup(t, time.Minute)
+		strangerID := uuid.New()
+		noteRepo.EXPECT().GetByID(ctx, note.ID).Return(note, nil)
+		shareRepo.EXPECT().GetRole(ctx, note.ID, strangerID).Return("", nil)
+		orgRepo.EXPECT().IsAdminOver(ctx, strangerID, ownerID).Return(false, nil)
+
+		_, err := presence.Open(ctx, entity.Presence{NoteID: note.ID, UserID: strangerID, DeviceID: uuid.New()})
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+		viewers, err := presence.Viewers(ctx, note.ID)
+		require.NoError(t, err)
+		assert.Empty(t, viewers)
+	})
+
+	t.Run("forgets devices that stop refreshing", func(t *testing.T) {
+		presence, noteRepo, _, _ := setup(t, 20*time.Millisecond)
+		noteRepo.EXPECT().GetByID(ctx, note.ID).Return(note, nil)
+
+		_, err := presence.Open(ctx, entity.Presence{NoteID: note.ID, UserID: ownerID, DeviceID: uuid.New()})
+		require.NoError(t, err)
+		time.Sleep(30 * time.Millisecond)
+
+		viewers, err := presence.Viewers(ctx, note.ID)
+		require.NoError(t, err)
+		assert.Empty(t, viewers)
+	})
+}
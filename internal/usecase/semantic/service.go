@@ -0,0 +1,228 @@
+package semantic
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/embedding"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/authz"
+)
+
+const (
+	// maxQueryLength bounds the text of a search query in bytes, as for text
+	// search.
+	maxQueryLength = 200
+	// maxTextRunes bounds what is sent to the provider for one note, well
+	// within the input limit of common embedding models.
+	maxTextRunes = 8000
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// Service indexes the notes of users who turned semantic search on and
+// searches them by meaning. Notes are embedded in the background by Index,
+// so a new or edited note is found by its previous version, or not at all,
+// until the next run.
+type Service struct {
+	embedRepo  repository.NoteEmbeddingRepository
+	noteRepo   repository.NoteRepository
+	userRepo   repository.UserRepository
+	photoRepo  repository.PhotoRepository
+	authorizer *authz.Authorizer
+	provider   embedding.Provider
+	// model names the provider's model; embeddings of other models are
+	// replaced as notes are indexed again.
+	model string
+}
+
+func NewService(
+	embedRepo repository.NoteEmbeddingRepository,
+	noteRepo repository.NoteRepository,
+	userRepo repository.UserRepository,
+	photoRepo repository.PhotoRepository,
+	authorizer *authz.Authorizer,
+	provider embedding.Provider,
+	model string,
+) *Service {
+	return &Service{
+		embedRepo:  embedRepo,
+		noteRepo:   noteRepo,
+		userRepo:   userRepo,
+		photoRepo:  photoRepo,
+		authorizer: authorizer,
+		provider:   provider,
+		model:      model,
+	}
+}
+
+// Index embeds, batch by batch, the notes of opted-in users that changed
+// since they were last embedded, and returns how many it embedded.
+func (s *Service) Index(ctx context.Context, batchSize int) (int, error) {
+	batchSize = max(batchSize, 1)
+	total := 0
+	for {
+		notes, err := s.embedRepo.Pending(ctx, s.model, batchSize)
+		if err != nil {
+			return total, fmt.Errorf("listing notes to embed: %w", err)
+		}
+		if len(notes) == 0 {
+			return total, nil
+		}
+
+		texts := make([]string, len(notes))
+		for i := range notes {
+			texts[i] = noteText(&notes[i])
+		}
+		vectors, err := s.provider.Embed(ctx, texts)
+		if err != nil {
+			return total, fmt.Errorf("embedding notes: %w", err)
+		}
+
+		embeddings := make([]entity.NoteEmbedding, len(notes))
+		for i, n := range notes {
+			embeddings[i] = entity.NoteEmbedding{
+				NoteID: n.ID, UserID: n.UserID, Model: s.model, Vector: vectors[i], NoteUpdatedAt: n.UpdatedAt,
+			}
+		}
+		if err := s.embedRepo.Save(ctx, embeddings); err != nil {
+			return total, fmt.Errorf("saving embeddings: %w", err)
+		}
+
+		total += len(notes)
+		if len(notes) < batchSize {
+			return total, nil
+		}
+	}
+}
+
+// Prune deletes the embeddings that are no longer needed: those of deleted
+// notes and all of those of users who turned semantic search off.
+func (s *Service) Prune(ctx context.Context) (int64, error) {
+	pruned, err := s.embedRepo.Prune(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("pruning embeddings: %w", err)
+	}
+	return pruned, nil
+}
+
+type SearchInput struct {
+	UserID uuid.UUID
+	Query  string
+	// Limit defaults to 20 and is capped at 100.
+	Limit int
+}
+
+// Search returns the user's notes closest in meaning to the query, best
+// first, with the cosine similarity as score.
+func (s *Service) Search(ctx context.Context, input SearchInput) ([]entity.SearchHit, error) {
+	query := strings.TrimSpace(input.Query)
+	if query == "" || len(query) > maxQueryLength {
+		return nil, domain.ErrInvalidSearchQuery
+	}
+
+	if err := s.requireOptIn(ctx, input.UserID); err != nil {
+		return nil, err
+	}
+
+	vectors, err := s.provider.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("embedding query: %w", err)
+	}
+
+	hits, err := s.embedRepo.Search(ctx, input.UserID, s.model, vectors[0], clampLimit(input.Limit))
+	if err != nil {
+		return nil, fmt.Errorf("searching embeddings: %w", err)
+	}
+	return s.withPhotos(ctx, hits)
+}
+
+type RelatedInput struct {
+	UserID uuid.UUID
+	NoteID uuid.UUID
+	// Limit defaults to 20 and is capped at 100.
+	Limit int
+}
+
+// Related returns the user's notes closest in meaning to a note they can
+// read. It returns none while the note has not been embedded, which
+// includes notes shared by users who have semantic search off.
+func (s *Service) Related(ctx context.Context, input RelatedInput) ([]entity.SearchHit, error) {
+	if err := s.requireOptIn(ctx, input.UserID); err != nil {
+		return nil, err
+	}
+
+	note, err := s.noteRepo.GetByID(ctx, input.NoteID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizer.Authorize(ctx, input.UserID, authz.ActionRead, note); err != nil {
+		return nil, err
+	}
+	if note.IsDeleted() {
+		return nil, domain.ErrNoteNotFound
+	}
+
+	hits, err := s.embedRepo.Related(ctx, input.UserID, note.ID, s.model, clampLimit(input.Limit))
+	if err != nil {
+		return nil, fmt.Errorf("searching related notes: %w", err)
+	}
+	return s.withPhotos(ctx, hits)
+}
+
+func (s *Service) requireOptIn(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.SemanticSearch {
+		return domain.ErrSemanticSearchOff
+	}
+	return nil
+}
+
+func (s *Service) withPhotos(ctx context.Context, hits []entity.SearchHit) ([]entity.SearchHit, error) {
+	if len(hits) == 0 {
+		return hits, nil
+	}
+
+	ids := make([]uuid.UUID, len(hits))
+	for i := range hits {
+		ids[i] = hits[i].Note.ID
+	}
+	photos, err := s.photoRepo.GetByNoteIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("loading photos: %w", err)
+	}
+
+	byNote := make(map[uuid.UUID][]entity.Photo, len(hits))
+	for _, p := range photos {
+		byNote[p.NoteID] = append(byNote[p.NoteID], p)
+	}
+	for i := range hits {
+		hits[i].Note.Photos = byNote[hits[i].Note.ID]
+	}
+	return hits, nil
+}
+
+// noteText is what is embedded for a note: its title and content, cut to
+// maxTextRunes.
+func noteText(n *entity.Note) string {
+	text := strings.TrimSpace(n.Title + "\n\n" + n.Content)
+	if runes := []rune(text); len(runes) > maxTextRunes {
+		text = string(runes[:maxTextRunes])
+	}
+	return text
+}
+
+func clampLimit(limit int) int {
+	if limit <= 0 {
+		return defaultLimit
+	}
+	return min(limit, maxLimit)
+}
@@ -0,0 +1,176 @@
+package semantic_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/authz"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/semantic"
+)
+
+const model = "test-model"
+
+type fixture struct {
+	svc       *semantic.Service
+	embedRepo *mocks.MockNoteEmbeddingRepository
+	noteRepo  *mocks.MockNoteRepository
+	userRepo  *mocks.MockUserRepository
+	photoRepo *mocks.MockPhotoRepository
+	provider  *mocks.MockProvider
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctrl := gomock.NewController(t)
+	shareRepo := mocks.NewMockShareRepository(ctrl)
+	shareRepo.EXPECT().GetRole(gomock.Any(), gomock.Any(), gomock.Any()).Return("", nil).AnyTimes()
+	orgRepo := mocks.NewMockOrganizationRepository(ctrl)
+	orgRepo.EXPECT().IsAdminOver(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+
+	f := &fixture{
+		embedRepo: mocks.NewMockNoteEmbeddingRepository(ctrl),
+		noteRepo:  mocks.NewMockNoteRepository(ctrl),
+		userRepo:  mocks.NewMockUserRepository(ctrl),
+		photoRepo: mocks.NewMockPhotoRepository(ctrl),
+		provider:  mocks.NewMockProvider(ctrl),
+	}
+	f.svc = semantic.NewService(f.embedRepo, f.noteRepo, f.userRepo, f.photoRepo,
+		authz.NewAuthorizer(shareRepo, orgRepo, nil), f.provider, model)
+	return f
+}
+
+func TestService_Index(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("embeds pending notes in batches", func(t *testing.T) {
+		f := newFixture(t)
+		updated := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+		first := []entity.Note{
+			{ID: uuid.New(), UserID: uuid.New(), Title: "Oak survey", Content: "Three oaks", UpdatedAt: updated},
+			{ID: uuid.New(), UserID: uuid.New(), Title: "", Content: "Two eggs", UpdatedAt: updated},
+		}
+		second := []entity.Note{{ID: uuid.New(), UserID: uuid.New(), Title: "Ninho"}}
+
+		gomock.InOrder(
+			f.embedRepo.EXPECT().Pending(ctx, model, 2).Return(first, nil),
+			f.provider.EXPECT().Embed(ctx, []string{"Oak survey\n\nThree oaks", "Two eggs"}).
+				Return([][]float32{{1, 0}, {0, 1}}, nil),
+			f.embedRepo.EXPECT().Save(ctx, []entity.NoteEmbedding{
+				{NoteID: first[0].ID, UserID: first[0].UserID, Model: model, Vector: []float32{1, 0}, NoteUpdatedAt: updated},
+				{NoteID: first[1].ID, UserID: first[1].UserID, Model: model, Vector: []float32{0, 1}, NoteUpdatedAt: updated},
+			}).Return(nil),
+			f.embedRepo.EXPECT().Pending(ctx, model, 2).Return(second, nil),
+			f.provider.EXPECT().Embed(ctx, []string{"Ninho"}).Return([][]float32{{1, 1}}, nil),
+			f.embedRepo.EXPECT().Save(ctx, gomock.Len(1)).Return(nil),
+		)
+
+		indexed, err := f.svc.Index(ctx, 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, indexed)
+	})
+
+	t.Run("cuts long notes", func(t *testing.T) {
+		f := newFixture(t)
+		f.embedRepo.EXPECT().Pending(ctx, model, 10).Return([]entity.Note{{ID: uuid.New(), Content: strings.Repeat("á", 9000)}}, nil)
+		f.provider.EXPECT().Embed(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, texts []string) ([][]float32, error) {
+			assert.Equal(t, 8000, len([]rune(texts[0])))
+			return [][]float32{{1}}, nil
+		})
+		f.embedRepo.EXPECT().Save(ctx, gomock.Any()).Return(nil)
+
+		_, err := f.svc.Index(ctx, 10)
+		require.NoError(t, err)
+	})
+
+	t.Run("stops at a provider failure", func(t *testing.T) {
+		f := newFixture(t)
+		f.embedRepo.EXPECT().Pending(ctx, model, 10).Return([]entity.Note{{ID: uuid.New(), Title: "Oak"}}, nil)
+		f.provider.EXPECT().Embed(ctx, gomock.Any()).Return(nil, errors.New("status 500"))
+
+		indexed, err := f.svc.Index(ctx, 10)
+
+		assert.Error(t, err)
+		assert.Zero(t, indexed)
+	})
+}
+
+func TestService_Search(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("searches the user's embeddings", func(t *testing.T) {
+		f := newFixture(t)
+		userID := uuid.New()
+		hit := entity.SearchHit{Note: entity.Note{ID: uuid.New(), UserID: userID, Title: "Oak survey"}, Score: 0.83}
+
+		f.userRepo.EXPECT().GetByID(ctx, userID).Return(&entity.User{ID: userID, SemanticSearch: true}, nil)
+		f.provider.EXPECT().Embed(ctx, []string{"big trees"}).Return([][]float32{{0.5, 0.5}}, nil)
+		f.embedRepo.EXPECT().Search(ctx, userID, model, []float32{0.5, 0.5}, 20).Return([]entity.SearchHit{hit}, nil)
+		f.photoRepo.EXPECT().GetByNoteIDs(ctx, []uuid.UUID{hit.Note.ID}).
+			Return([]entity.Photo{{NoteID: hit.Note.ID, URL: "https://cdn/oak.jpg"}}, nil)
+
+		hits, err := f.svc.Search(ctx, semantic.SearchInput{UserID: userID, Query: "  big trees "})
+
+		require.NoError(t, err)
+		require.Len(t, hits, 1)
+		assert.InDelta(t, 0.83, hits[0].Score, 1e-9)
+		assert.Len(t, hits[0].Note.Photos, 1)
+	})
+
+	t.Run("refuses users who did not opt in", func(t *testing.T) {
+		f := newFixture(t)
+		userID := uuid.New()
+		f.userRepo.EXPECT().GetByID(ctx, userID).Return(&entity.User{ID: userID}, nil)
+
+		_, err := f.svc.Search(ctx, semantic.SearchInput{UserID: userID, Query: "trees"})
+		assert.ErrorIs(t, err, domain.ErrSemanticSearchOff)
+	})
+
+	t.Run("rejects an empty query", func(t *testing.T) {
+		f := newFixture(t)
+
+		_, err := f.svc.Search(ctx, semantic.SearchInput{UserID: uuid.New(), Query: " "})
+		assert.ErrorIs(t, err, domain.ErrInvalidSearchQuery)
+	})
+}
+
+func TestService_Related(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("finds notes close to the note", func(t *testing.T) {
+		f := newFixture(t)
+		userID := uuid.New()
+		n := &entity.Note{ID: uuid.New(), UserID: userID}
+
+		f.userRepo.EXPECT().GetByID(ctx, userID).Return(&entity.User{ID: userID, SemanticSearch: true}, nil)
+		f.noteRepo.EXPECT().GetByID(ctx, n.ID).Return(n, nil)
+		f.embedRepo.EXPECT().Related(ctx, userID, n.ID, model, 5).Return(nil, nil)
+
+		hits, err := f.svc.Related(ctx, semantic.RelatedInput{UserID: userID, NoteID: n.ID, Limit: 5})
+
+		require.NoError(t, err)
+		assert.Empty(t, hits)
+	})
+
+	t.Run("returns forbidden for notes the user cannot read", func(t *testing.T) {
+		f := newFixture(t)
+		userID := uuid.New()
+		n := &entity.Note{ID: uuid.New(), UserID: uuid.New()}
+
+		f.userRepo.EXPECT().GetByID(ctx, userID).Return(&entity.User{ID: userID, SemanticSearch: true}, nil)
+		f.noteRepo.EXPECT().GetByID(ctx, n.ID).Return(n, nil)
+
+		_, err := f.svc.Related(ctx, semantic.RelatedInput{UserID: userID, NoteID: n.ID})
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+}
@@ -0,0 +1,137 @@
+package share
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/authz"
+)
+
+type Service struct {
+	noteRepo   repository.NoteRepository
+	userRepo   repository.UserRepository
+	shareRepo  repository.ShareRepository
+	authorizer *authz.Authorizer
+}
+
+func NewService(
+	noteRepo repository.NoteRepository,
+	userRepo repository.UserRepository,
+	shareRepo repository.ShareRepository,
+	authorizer *authz.Authorizer,
+) *Service {
+	return &Service{
+		noteRepo:   noteRepo,
+		userRepo:   userRepo,
+		shareRepo:  shareRepo,
+		authorizer: authorizer,
+	}
+}
+
+type Grant struct {
+	Email string
+	Role  string
+}
+
+// BulkInput grants and revokes access to several notes at once. A user both
+// granted and revoked ends up with the granted role.
+type BulkInput struct {
+	UserID  uuid.UUID
+	NoteIDs []uuid.UUID
+	Grants  []Grant
+	Revokes []string
+}
+
+type BulkResult struct {
+	Granted int
+	Revoked int64
+}
+
+// Bulk applies the grants and revokes to every note in one transaction. Only
+// the owner can share a note; if any note is not theirs nothing is changed.
+func (s *Service) Bulk(ctx context.Context, input BulkInput) (*BulkResult, error) {
+	for _, noteID := range input.NoteIDs {
+		if _, err := s.manageableNote(ctx, input.UserID, noteID); err != nil {
+			return nil, err
+		}
+	}
+
+	var grantees []uuid.UUID
+	var roles []string
+	for _, g := range input.Grants {
+		if !entity.IsShareRole(g.Role) {
+			return nil, domain.ErrInvalidShareRole
+		}
+
+		user, err := s.userRepo.GetByEmail(ctx, g.Email)
+		if err != nil {
+			return nil, err
+		}
+		if user.ID == input.UserID {
+			return nil, domain.ErrShareWithOwner
+		}
+		grantees = append(grantees, user.ID)
+		roles = append(roles, g.Role)
+	}
+
+	var revokeIDs []uuid.UUID
+	for _, email := range input.Revokes {
+		user, err := s.userRepo.GetByEmail(ctx, email)
+		if errors.Is(err, domain.ErrUserNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		revokeIDs = append(revokeIDs, user.ID)
+	}
+
+	grants := make([]entity.NoteShare, 0, len(input.NoteIDs)*len(grantees))
+	for _, noteID := range input.NoteIDs {
+		for i, userID := range grantees {
+			grants = append(grants, *entity.NewNoteShare(noteID, userID, roles[i], input.UserID))
+		}
+	}
+
+	revoked, err := s.shareRepo.Apply(ctx, grants, input.NoteIDs, revokeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("applying shares: %w", err)
+	}
+
+	return &BulkResult{Granted: len(grants), Revoked: revoked}, nil
+}
+
+func (s *Service) List(ctx context.Context, userID, noteID uuid.UUID) ([]entity.NoteShare, error) {
+	if _, err := s.manageableNote(ctx, userID, noteID); err != nil {
+		return nil, err
+	}
+
+	shares, err := s.shareRepo.ListByNoteID(ctx, noteID)
+	if err != nil {
+		return nil, fmt.Errorf("listing shares: %w", err)
+	}
+	return shares, nil
+}
+
+func (s *Service) manageableNote(ctx context.Context, userID, noteID uuid.UUID) (*entity.Note, error) {
+	note, err := s.noteRepo.GetByID(ctx, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizer.Authorize(ctx, userID, authz.ActionShare, note); err != nil {
+		return nil, err
+	}
+
+	if note.IsDeleted() {
+		return nil, domain.ErrNoteNotFound
+	}
+
+	return note, nil
+}
@@ -0,0 +1,112 @@
+package share_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/authz"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/share"
+)
+
+func TestService_Bulk(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("grants every user on every note and revokes in one call", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		shareRepo := mocks.NewMockShareRepository(ctrl)
+		svc := share.NewService(noteRepo, userRepo, shareRepo, authz.NewAuthorizer(shareRepo, nil, nil))
+
+		ownerID := uuid.New()
+		alice := &entity.User{ID: uuid.New(), Email: "alice@example.com"}
+		bob := &entity.User{ID: uuid.New(), Email: "bob@example.com"}
+		noteIDs := []uuid.UUID{uuid.New(), uuid.New()}
+
+		for _, id := range noteIDs {
+			noteRepo.EXPECT().GetByID(ctx, id).Return(&entity.Note{ID: id, UserID: ownerID}, nil)
+		}
+		userRepo.EXPECT().GetByEmail(ctx, alice.Email).Return(alice, nil)
+		userRepo.EXPECT().GetByEmail(ctx, bob.Email).Return(bob, nil)
+		shareRepo.EXPECT().Apply(ctx, gomock.Any(), noteIDs, []uuid.UUID{bob.ID}).DoAndReturn(
+			func(_ context.Context, grants []entity.NoteShare, _, _ []uuid.UUID) (int64, error) {
+				require.Len(t, grants, 2)
+				for _, g := range grants {
+					assert.Equal(t, alice.ID, g.UserID)
+					assert.Equal(t, entity.ShareRoleEditor, g.Role)
+					assert.Equal(t, ownerID, g.GrantedBy)
+				}
+				return 2, nil
+			})
+
+		result, err := svc.Bulk(ctx, share.BulkInput{
+			UserID:  ownerID,
+			NoteIDs: noteIDs,
+			Grants:  []share.Grant{{Email: alice.Email, Role: entity.ShareRoleEditor}},
+			Revokes: []string{bob.Email},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Granted)
+		assert.Equal(t, int64(2), result.Revoked)
+	})
+
+	t.Run("changes nothing when a note belongs to someone else", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		shareRepo := mocks.NewMockShareRepository(ctrl)
+		orgRepo := mocks.NewMockOrganizationRepository(ctrl)
+		svc := share.NewService(noteRepo, nil, shareRepo, authz.NewAuthorizer(shareRepo, orgRepo, nil))
+
+		userID := uuid.New()
+		noteID := uuid.New()
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(&entity.Note{ID: noteID, UserID: uuid.New()}, nil)
+		shareRepo.EXPECT().GetRole(ctx, noteID, userID).Return(entity.ShareRoleEditor, nil)
+
+		result, err := svc.Bulk(ctx, share.BulkInput{
+			UserID:  userID,
+			NoteIDs: []uuid.UUID{noteID},
+			Grants:  []share.Grant{{Email: "x@example.com", Role: entity.ShareRoleViewer}},
+		})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("rejects sharing with the owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		svc := share.NewService(noteRepo, userRepo, nil, authz.NewAuthorizer(nil, nil, nil))
+
+		owner := &entity.User{ID: uuid.New(), Email: "me@example.com"}
+		noteID := uuid.New()
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(&entity.Note{ID: noteID, UserID: owner.ID}, nil)
+		userRepo.EXPECT().GetByEmail(ctx, owner.Email).Return(owner, nil)
+
+		result, err := svc.Bulk(ctx, share.BulkInput{
+			UserID:  owner.ID,
+			NoteIDs: []uuid.UUID{noteID},
+			Grants:  []share.Grant{{Email: owner.Email, Role: entity.ShareRoleViewer}},
+		})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrShareWithOwner)
+	})
+}
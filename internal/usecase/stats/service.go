@@ -0,0 +1,37 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+type Service struct {
+	statsRepo repository.UserStatsRepository
+}
+
+func NewService(statsRepo repository.UserStatsRepository) *Service {
+	return &Service{statsRepo: statsRepo}
+}
+
+func (s *Service) Get(ctx context.Context, userID uuid.UUID) (*entity.UserStats, error) {
+	stats, err := s.statsRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("getting user stats: %w", err)
+	}
+	return stats, nil
+}
+
+// Reconcile corrects counters that drifted from the data, for example after
+// rows were changed by hand with triggers disabled, and returns what it fixed.
+func (s *Service) Reconcile(ctx context.Context) ([]entity.StatsDrift, error) {
+	drifts, err := s.statsRepo.Reconcile(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reconciling user stats: %w", err)
+	}
+	return drifts, nil
+}
@@ -0,0 +1,77 @@
+package stats_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/stats"
+)
+
+func TestService_Get(t *testing.T) {
+	t.Run("returns stored counters", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		statsRepo := mocks.NewMockUserStatsRepository(ctrl)
+		svc := stats.NewService(statsRepo)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		stored := &entity.UserStats{UserID: userID, NoteCount: 3, PhotoCount: 5, StorageBytes: 4096}
+
+		statsRepo.EXPECT().Get(ctx, userID).Return(stored, nil)
+
+		result, err := svc.Get(ctx, userID)
+
+		require.NoError(t, err)
+		assert.Equal(t, stored, result)
+	})
+}
+
+func TestService_Reconcile(t *testing.T) {
+	t.Run("returns corrected counters", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		statsRepo := mocks.NewMockUserStatsRepository(ctrl)
+		svc := stats.NewService(statsRepo)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		drift := entity.StatsDrift{
+			Stored: entity.UserStats{UserID: userID, NoteCount: 4},
+			Actual: entity.UserStats{UserID: userID, NoteCount: 3},
+		}
+
+		statsRepo.EXPECT().Reconcile(ctx).Return([]entity.StatsDrift{drift}, nil)
+
+		result, err := svc.Reconcile(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, []entity.StatsDrift{drift}, result)
+	})
+
+	t.Run("wraps repository errors", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		statsRepo := mocks.NewMockUserStatsRepository(ctrl)
+		svc := stats.NewService(statsRepo)
+
+		repoErr := errors.New("connection reset")
+		statsRepo.EXPECT().Reconcile(gomock.Any()).Return(nil, repoErr)
+
+		result, err := svc.Reconcile(context.Background())
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, repoErr)
+	})
+}
@@ -0,0 +1,95 @@
+package summary
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/authz"
+)
+
+// Service serves note lists and maps from the note_summaries projection.
+type Service struct {
+	summaryRepo repository.NoteSummaryRepository
+	authorizer  *authz.Authorizer
+}
+
+func NewService(summaryRepo repository.NoteSummaryRepository, authorizer *authz.Authorizer) *Service {
+	return &Service{summaryRepo: summaryRepo, authorizer: authorizer}
+}
+
+type ListInput struct {
+	UserID  uuid.UUID
+	PerPage int
+	// Cursor continues a listing from a previous page's next_cursor.
+	Cursor string
+	// TeamID lists the notes of a team the user is a member of instead of
+	// the user's own.
+	TeamID      *uuid.UUID
+	BoundingBox *valueobject.BoundingBox
+	// Tags keeps only notes carrying all of these tags.
+	Tags []string
+	// Colors and Icons keep only notes with any one of these colors and
+	// any one of these icons.
+	Colors []string
+	Icons  []string
+}
+
+func (s *Service) List(ctx context.Context, input ListInput) ([]entity.NoteSummary, *pagination.Info, error) {
+	tags, ok := entity.NormalizeTags(input.Tags)
+	if !ok {
+		return nil, nil, domain.ErrInvalidTag
+	}
+	colors, ok := entity.NormalizeNoteColors(input.Colors)
+	if !ok {
+		return nil, nil, domain.ErrInvalidNoteColor
+	}
+	icons, ok := entity.NormalizeNoteIcons(input.Icons)
+	if !ok {
+		return nil, nil, domain.ErrInvalidNoteIcon
+	}
+
+	params := repository.NoteSummaryListParams{
+		PerPage:     pagination.NewParams(1, input.PerPage).PerPage,
+		TeamID:      input.TeamID,
+		BoundingBox: input.BoundingBox,
+		Tags:        tags,
+		Colors:      colors,
+		Icons:       icons,
+	}
+	if input.Cursor != "" {
+		after, err := pagination.DecodeCursor(input.Cursor)
+		if err != nil {
+			return nil, nil, domain.ErrInvalidCursor
+		}
+		params.After = after
+	}
+
+	if input.TeamID != nil {
+		if err := s.authorizer.AuthorizeTeam(ctx, input.UserID, *input.TeamID, authz.ActionRead); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	summaries, pageInfo, err := s.summaryRepo.List(ctx, input.UserID, params)
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing note summaries: %w", err)
+	}
+	return summaries, pageInfo, nil
+}
+
+// Rebuild recomputes the projection, for example after rows were changed by
+// hand with triggers disabled, and returns how many summaries it fixed.
+func (s *Service) Rebuild(ctx context.Context) (int64, error) {
+	fixed, err := s.summaryRepo.Rebuild(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("rebuilding note summaries: %w", err)
+	}
+	return fixed, nil
+}
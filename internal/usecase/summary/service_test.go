@@ -0,0 +1,77 @@
+package summary_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/authz"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/summary"
+)
+
+func TestService_List(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("lists the user's summaries with normalized tags", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		summaryRepo := mocks.NewMockNoteSummaryRepository(ctrl)
+		svc := summary.NewService(summaryRepo, authz.NewAuthorizer(nil, nil, nil))
+		userID := uuid.New()
+		stored := []entity.NoteSummary{{ID: uuid.New(), UserID: userID, Title: "Plot 1"}}
+
+		summaryRepo.EXPECT().List(ctx, userID, repository.NoteSummaryListParams{
+			PerPage: 20,
+			Tags:    []string{"soil-sample"},
+		}).Return(stored, &pagination.Info{PerPage: 20}, nil)
+
+		result, _, err := svc.List(ctx, summary.ListInput{UserID: userID, Tags: []string{" Soil-Sample "}})
+
+		require.NoError(t, err)
+		assert.Equal(t, stored, result)
+	})
+
+	t.Run("team summaries need membership", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		summaryRepo := mocks.NewMockNoteSummaryRepository(ctrl)
+		teamRepo := mocks.NewMockTeamRepository(ctrl)
+		svc := summary.NewService(summaryRepo, authz.NewAuthorizer(nil, nil, teamRepo))
+		userID, teamID := uuid.New(), uuid.New()
+
+		teamRepo.EXPECT().GetRole(ctx, teamID, userID).Return("", nil)
+
+		_, _, err := svc.List(ctx, summary.ListInput{UserID: userID, TeamID: &teamID})
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("rejects an invalid cursor", func(t *testing.T) {
+		svc := summary.NewService(mocks.NewMockNoteSummaryRepository(gomock.NewController(t)), authz.NewAuthorizer(nil, nil, nil))
+
+		_, _, err := svc.List(ctx, summary.ListInput{UserID: uuid.New(), Cursor: "not-a-cursor"})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+	})
+}
+
+func TestService_Rebuild(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	summaryRepo := mocks.NewMockNoteSummaryRepository(ctrl)
+	svc := summary.NewService(summaryRepo, authz.NewAuthorizer(nil, nil, nil))
+	ctx := context.Background()
+
+	summaryRepo.EXPECT().Rebuild(ctx).Return(int64(2), nil)
+
+	fixed, err := svc.Rebuild(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), fixed)
+}
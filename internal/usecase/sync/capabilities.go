@@ -0,0 +1,59 @@
+package sync
+
+// ProtocolVersion changes whenever the sync protocol changes in a way clients
+// must know about.
+const ProtocolVersion = 1
+
+// Cursor formats accepted by sync and the change and photo feeds.
+const (
+	CursorTypeTimestamp = "timestamp"
+	CursorTypeOpaque    = "opaque"
+)
+
+// Capabilities describes what this server supports, so clients can adapt at
+// runtime instead of assuming a server version.
+type Capabilities struct {
+	ProtocolVersion int
+	// MaxBatchSize is the most server notes one sync returns.
+	MaxBatchSize int
+	// MaxPhotosPerSync is the most photos a device may report in one sync.
+	MaxPhotosPerSync int
+	// MaxChangesLimit and MaxManifestLimit cap the change and photo feeds.
+	MaxChangesLimit         int
+	MaxManifestLimit        int
+	ConflictStrategies      []string
+	DefaultConflictStrategy string
+	// CursorTypes are the cursor formats accepted; sync_cursor is a
+	// timestamp, page tokens and feed cursors are opaque.
+	CursorTypes []string
+	PhotoSync   bool
+	SyncScopes  bool
+	// FieldMerge is set when notes sent with field_updated_at are merged
+	// field by field under last-write-wins.
+	FieldMerge bool
+	// Compression lists the request encodings accepted for sync bodies.
+	Compression []string
+}
+
+// Capabilities returns the sync features this server supports.
+func (s *Service) Capabilities() Capabilities {
+	strategies := []string{StrategyLastWriteWins, StrategyKeepBoth}
+	if s.conflictRepo != nil {
+		strategies = append(strategies, StrategyManual)
+	}
+
+	return Capabilities{
+		ProtocolVersion:         ProtocolVersion,
+		MaxBatchSize:            maxSyncLimit,
+		MaxPhotosPerSync:        maxSyncPhotos,
+		MaxChangesLimit:         maxChangesLimit,
+		MaxManifestLimit:        maxManifestLimit,
+		ConflictStrategies:      strategies,
+		DefaultConflictStrategy: StrategyLastWriteWins,
+		CursorTypes:             []string{CursorTypeTimestamp, CursorTypeOpaque},
+		PhotoSync:               true,
+		SyncScopes:              true,
+		FieldMerge:              true,
+		Compression:             []string{},
+	}
+}
@@ -0,0 +1,97 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+)
+
+const (
+	defaultChangesLimit = 500
+	maxChangesLimit     = 1000
+)
+
+type ChangesInput struct {
+	UserID uuid.UUID
+	// DeviceID optionally applies the device's sync scope.
+	DeviceID string
+	// Cursor is a NextCursor from a previous page, or an RFC3339 timestamp
+	// such as the new_cursor of a push sync. Empty starts from the beginning.
+	Cursor string
+	Limit  int
+}
+
+// Changes is a page of notes changed on the server, oldest first. Clients
+// pass NextCursor back until HasMore is false.
+type Changes struct {
+	Notes      []entity.Note
+	NextCursor string
+	HasMore    bool
+}
+
+// Changes lists server-side note changes without pushing anything or moving
+// the device's cursor.
+func (s *Service) Changes(ctx context.Context, input ChangesInput) (*Changes, error) {
+	after, err := parseCursor(input.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultChangesLimit
+	}
+	limit = min(limit, maxChangesLimit)
+
+	var scope entity.SyncScope
+	if input.DeviceID != "" {
+		device, err := s.deviceRepo.GetByUserAndDeviceID(ctx, input.UserID, input.DeviceID)
+		if err != nil {
+			return nil, fmt.Errorf("getting device: %w", err)
+		}
+		scope = device.Scope
+	}
+
+	// One extra row tells whether another page follows.
+	notes, err := s.noteRepo.GetModifiedAfter(ctx, input.UserID, after, limit+1, scope)
+	if err != nil {
+		return nil, fmt.Errorf("getting server changes: %w", err)
+	}
+
+	changes := &Changes{Notes: notes, NextCursor: input.Cursor}
+	if len(notes) > limit {
+		changes.Notes = notes[:limit]
+		changes.HasMore = true
+	}
+	if changes.Notes == nil {
+		changes.Notes = []entity.Note{}
+	}
+	if n := len(changes.Notes); n > 0 {
+		last := changes.Notes[n-1]
+		changes.NextCursor = pagination.Cursor{UpdatedAt: last.UpdatedAt, ID: last.ID}.Encode()
+	}
+
+	return changes, nil
+}
+
+// parseCursor reads an opaque (time, id) cursor or, to start from a point
+// in time, an RFC3339 timestamp.
+func parseCursor(token string) (pagination.Cursor, error) {
+	if token == "" {
+		return pagination.Cursor{}, nil
+	}
+	if at, err := time.Parse(time.RFC3339Nano, token); err == nil {
+		return pagination.Cursor{UpdatedAt: at}, nil
+	}
+	cursor, err := pagination.DecodeCursor(token)
+	if err != nil {
+		return pagination.Cursor{}, domain.ErrInvalidCursor
+	}
+	return *cursor, nil
+}
@@ -0,0 +1,153 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+// holdConflict stores the client version of a conflicting note under
+// StrategyManual instead of applying it.
+func (s *Service) holdConflict(ctx context.Context, cn ClientNote, input SyncInput, serverNote *entity.Note) (ConflictInfo, error) {
+	clientNote := clientNoteToEntity(cn, input.UserID, input.DeviceID, serverNote.ID)
+	conflict := entity.NewSyncConflict(serverNote, clientNote, input.DeviceID, s.conflictRetention)
+	if err := s.conflictRepo.Save(ctx, conflict); err != nil {
+		return ConflictInfo{}, fmt.Errorf("saving sync conflict: %w", err)
+	}
+
+	return ConflictInfo{
+		ClientID:      cn.ClientID,
+		Resolution:    ResolutionPending,
+		ServerVersion: serverNote,
+		ConflictID:    conflict.ID,
+	}, nil
+}
+
+// ListConflicts returns the user's pending conflicts, oldest first.
+func (s *Service) ListConflicts(ctx context.Context, userID uuid.UUID) ([]entity.SyncConflict, error) {
+	if s.conflictRepo == nil {
+		return nil, nil
+	}
+
+	conflicts, err := s.conflictRepo.ListPending(ctx, userID, s.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("listing sync conflicts: %w", err)
+	}
+	return conflicts, nil
+}
+
+type ReplayInput struct {
+	UserID     uuid.UUID
+	ConflictID uuid.UUID
+	// Side is entity.ConflictSideClient to apply the held client version or
+	// entity.ConflictSideServer to keep the stored note.
+	Side string
+	// DeviceID is recorded as the last device to modify the note when the
+	// client side is applied; empty uses the device that sent the version.
+	DeviceID string
+}
+
+// ReplayConflict resolves a pending conflict and returns the note as it
+// stands afterwards. The client version is only applied while the note is
+// still the version it conflicted with; otherwise it returns ErrNoteChanged
+// and the conflict stays pending, so the user can review the newer version
+// and keep the server side instead.
+func (s *Service) ReplayConflict(ctx context.Context, input ReplayInput) (*entity.Note, error) {
+	if s.conflictRepo == nil {
+		return nil, domain.ErrSyncConflictNotFound
+	}
+
+	conflict, err := s.conflictRepo.GetByID(ctx, input.ConflictID)
+	if err != nil {
+		return nil, err
+	}
+	if conflict.UserID != input.UserID || conflict.IsExpired(s.clock.Now()) {
+		return nil, domain.ErrSyncConflictNotFound
+	}
+	if !conflict.IsPending() {
+		return nil, domain.ErrSyncConflictResolved
+	}
+
+	note, err := s.noteRepo.GetByID(ctx, conflict.NoteID)
+	if err != nil {
+		return nil, fmt.Errorf("getting note: %w", err)
+	}
+
+	if input.Side == entity.ConflictSideClient {
+		if !note.UpdatedAt.Equal(conflict.ServerUpdatedAt) {
+			return nil, domain.ErrNoteChanged
+		}
+		if err := s.applyClientVersion(ctx, note, conflict, input.DeviceID); err != nil {
+			return nil, err
+		}
+	}
+
+	conflict.Resolve(input.Side)
+	if err := s.conflictRepo.Resolve(ctx, conflict); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+// applyClientVersion saves the conflict's client version over note the way
+// a winning sync push would.
+func (s *Service) applyClientVersion(ctx context.Context, note *entity.Note, conflict *entity.SyncConflict, deviceID string) error {
+	client := conflict.ClientVersion
+
+	content := client.Content
+	// A collaborative note's content only changes through its document.
+	if note.Collaborative {
+		content = note.Content
+	}
+	note.UpdateAt(client.Title, content, client.Location, s.clock.Now())
+	note.Measurements = client.Measurements
+	if client.Tags != nil {
+		note.Tags = client.Tags
+	}
+	if client.Color != nil {
+		note.Color = client.Color
+	}
+	if client.Icon != nil {
+		note.Icon = client.Icon
+	}
+	note.DeletedAt = nil
+	if client.IsDeleted() {
+		deletedAt := note.UpdatedAt
+		note.DeletedAt = &deletedAt
+	}
+	if deviceID == "" {
+		deviceID = conflict.DeviceID
+	}
+	note.MarkModifiedBy(deviceID)
+
+	note.Warnings = nil
+	note.Sanitize()
+
+	notes := []entity.Note{*note}
+	if err := s.evaluateQuality(ctx, note.UserID, notes); err != nil {
+		return err
+	}
+	if err := s.noteRepo.BatchUpsert(ctx, notes); err != nil {
+		return fmt.Errorf("upserting note: %w", err)
+	}
+	*note = notes[0]
+	return nil
+}
+
+// PruneConflicts deletes conflicts that expired before now.
+func (s *Service) PruneConflicts(ctx context.Context, now time.Time) (int64, error) {
+	if s.conflictRepo == nil {
+		return 0, nil
+	}
+
+	deleted, err := s.conflictRepo.DeleteExpired(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("pruning sync conflicts: %w", err)
+	}
+	return deleted, nil
+}
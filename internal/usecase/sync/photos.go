@@ -0,0 +1,236 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+)
+
+const (
+	defaultManifestLimit = 500
+	maxManifestLimit     = 1000
+)
+
+type PhotoManifestInput struct {
+	UserID uuid.UUID
+	// DeviceID optionally applies the device's sync scope.
+	DeviceID string
+	// Cursor is a NextCursor from a previous manifest, or an RFC3339
+	// timestamp. Empty starts from the beginning.
+	Cursor string
+	Limit  int
+}
+
+// PhotoManifest lists photo metadata changes since a cursor, without content.
+// Clients pass NextCursor back until HasMore is false.
+type PhotoManifest struct {
+	Added      []entity.Photo
+	Removed    []entity.PhotoTombstone
+	NextCursor string
+	HasMore    bool
+}
+
+// manifestEntry is an added photo or a tombstone at its position in the
+// manifest, (created_at, id) or (deleted_at, photo_id).
+type manifestEntry struct {
+	pos     pagination.Cursor
+	photo   *entity.Photo
+	removed *entity.PhotoTombstone
+}
+
+func (s *Service) PhotoManifest(ctx context.Context, input PhotoManifestInput) (*PhotoManifest, error) {
+	after, err := parseCursor(input.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultManifestLimit
+	}
+	limit = min(limit, maxManifestLimit)
+
+	manifest := &PhotoManifest{
+		Added:      []entity.Photo{},
+		Removed:    []entity.PhotoTombstone{},
+		NextCursor: input.Cursor,
+	}
+
+	if input.DeviceID != "" {
+		device, err := s.deviceRepo.GetByUserAndDeviceID(ctx, input.UserID, input.DeviceID)
+		if err != nil {
+			return nil, fmt.Errorf("getting device: %w", err)
+		}
+		if device.Scope.ExcludePhotos {
+			return manifest, nil
+		}
+	}
+
+	// Fetch one extra of each kind to tell whether another page follows.
+	added, err := s.photoRepo.GetCreatedAfter(ctx, input.UserID, after, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("getting added photos: %w", err)
+	}
+
+	removed, err := s.photoRepo.GetDeletedAfter(ctx, input.UserID, after, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("getting removed photos: %w", err)
+	}
+
+	entries := make([]manifestEntry, 0, len(added)+len(removed))
+	for i := range added {
+		entries = append(entries, manifestEntry{
+			pos:   pagination.Cursor{UpdatedAt: added[i].CreatedAt, ID: added[i].ID},
+			photo: &added[i],
+		})
+	}
+	for i := range removed {
+		entries = append(entries, manifestEntry{
+			pos:     pagination.Cursor{UpdatedAt: removed[i].DeletedAt, ID: removed[i].PhotoID},
+			removed: &removed[i],
+		})
+	}
+	// Same order as the repository: by time, ties broken by id.
+	slices.SortStableFunc(entries, func(a, b manifestEntry) int {
+		if c := a.pos.UpdatedAt.Compare(b.pos.UpdatedAt); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.pos.ID[:], b.pos.ID[:])
+	})
+
+	if len(entries) > limit {
+		entries = entries[:limit]
+		manifest.HasMore = true
+	}
+
+	for _, e := range entries {
+		if e.photo != nil {
+			manifest.Added = append(manifest.Added, *e.photo)
+		} else {
+			manifest.Removed = append(manifest.Removed, *e.removed)
+		}
+	}
+	if n := len(entries); n > 0 {
+		manifest.NextCursor = entries[n-1].pos.Encode()
+	}
+
+	return manifest, nil
+}
+
+// ClientPhoto is a photo the device holds, identified by the ID it generated
+// when the photo was taken.
+type ClientPhoto struct {
+	ClientID     string
+	NoteClientID string
+}
+
+const (
+	PhotoUploaded      = "uploaded"
+	PhotoPendingUpload = "pending_upload"
+	PhotoDeleted       = "deleted"
+	// PhotoMissingNote means the server has no live note with the photo's
+	// NoteClientID, so there is nowhere to upload it yet.
+	PhotoMissingNote = "missing_note"
+)
+
+// PhotoStatus tells the device what the server knows about one of its photos.
+type PhotoStatus struct {
+	ClientID string
+	Status   string
+	// NoteID is the note to upload to when Status is PhotoPendingUpload.
+	NoteID uuid.UUID
+	// Photo is the stored photo when Status is PhotoUploaded.
+	Photo *entity.Photo
+}
+
+func (p PhotoStatus) PendingUpload() bool {
+	return p.Status == PhotoPendingUpload
+}
+
+// reconcilePhotos compares the device's photos with the stored ones and
+// tombstones. It runs after the notes are saved, so photos of notes created in
+// the same sync can be uploaded right away.
+func (s *Service) reconcilePhotos(ctx context.Context, userID uuid.UUID, photos []ClientPhoto) ([]PhotoStatus, error) {
+	if len(photos) == 0 {
+		return nil, nil
+	}
+
+	clientIDs := make([]string, 0, len(photos))
+	for _, p := range photos {
+		clientIDs = append(clientIDs, p.ClientID)
+	}
+
+	stored, err := s.photoRepo.GetByClientIDs(ctx, userID, clientIDs)
+	if err != nil {
+		return nil, fmt.Errorf("getting photos by client id: %w", err)
+	}
+	byClientID := make(map[string]*entity.Photo, len(stored))
+	for i := range stored {
+		byClientID[stored[i].ClientID] = &stored[i]
+	}
+
+	removed, err := s.photoRepo.GetDeletedByClientIDs(ctx, userID, clientIDs)
+	if err != nil {
+		return nil, fmt.Errorf("getting deleted photos by client id: %w", err)
+	}
+	deleted := make(map[string]bool, len(removed))
+	for _, t := range removed {
+		deleted[t.ClientID] = true
+	}
+
+	noteIDs := make(map[string]uuid.UUID)
+	statuses := make([]PhotoStatus, 0, len(photos))
+	for _, p := range photos {
+		status := PhotoStatus{ClientID: p.ClientID}
+		switch {
+		case byClientID[p.ClientID] != nil:
+			status.Status = PhotoUploaded
+			status.Photo = byClientID[p.ClientID]
+			status.NoteID = status.Photo.NoteID
+		case deleted[p.ClientID]:
+			status.Status = PhotoDeleted
+		default:
+			noteID, ok := noteIDs[p.NoteClientID]
+			if !ok {
+				noteID, err = s.liveNoteID(ctx, userID, p.NoteClientID)
+				if err != nil {
+					return nil, err
+				}
+				noteIDs[p.NoteClientID] = noteID
+			}
+			if noteID == uuid.Nil {
+				status.Status = PhotoMissingNote
+			} else {
+				status.Status = PhotoPendingUpload
+				status.NoteID = noteID
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// liveNoteID returns the ID of the user's note with the client ID, or
+// uuid.Nil when there is none or it was deleted.
+func (s *Service) liveNoteID(ctx context.Context, userID uuid.UUID, clientID string) (uuid.UUID, error) {
+	note, err := s.noteRepo.GetByClientID(ctx, userID, clientID)
+	if errors.Is(err, domain.ErrNoteNotFound) {
+		return uuid.Nil, nil
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("getting note by client id: %w", err)
+	}
+	if note.IsDeleted() {
+		return uuid.Nil, nil
+	}
+	return note.ID, nil
+}
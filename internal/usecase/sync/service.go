@@ -2,25 +2,77 @@ package sync
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/notification"
 	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/clock"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/autotitle"
 )
 
 type Service struct {
 	noteRepo   repository.NoteRepository
+	photoRepo  repository.PhotoRepository
 	deviceRepo repository.DeviceRepository
+	ruleRepo   repository.QualityRuleRepository
+	userRepo   repository.UserRepository
+	notifier   notification.Notifier
+	pusher     notification.Pusher
+
+	conflictRepo      repository.SyncConflictRepository
+	conflictRetention time.Duration
+	titler            *autotitle.Titler
+	clock             clock.Clock
 }
 
-func NewService(noteRepo repository.NoteRepository, deviceRepo repository.DeviceRepository) *Service {
+// NewService creates the sync service. notifier may be nil, in which case
+// discarded edits are only reported in the sync response. pusher may be nil,
+// in which case other devices pick up synced notes on their next poll.
+// conflictRepo
+// holds the conflicts of StrategyManual for conflictRetention; when it is
+// nil the strategy is not offered and falls back to last-write-wins. titler
+// may be nil, in which case pushed notes keep the titles they are given.
+// clk sets the sync cursor and the limit for client timestamps; nil means
+// the system clock.
+func NewService(
+	noteRepo repository.NoteRepository,
+	photoRepo repository.PhotoRepository,
+	deviceRepo repository.DeviceRepository,
+	ruleRepo repository.QualityRuleRepository,
+	userRepo repository.UserRepository,
+	notifier notification.Notifier,
+	pusher notification.Pusher,
+	conflictRepo repository.SyncConflictRepository,
+	conflictRetention time.Duration,
+	titler *autotitle.Titler,
+	clk clock.Clock,
+) *Service {
+	if clk == nil {
+		clk = clock.System{}
+	}
 	return &Service{
-		noteRepo:   noteRepo,
-		deviceRepo: deviceRepo,
+		noteRepo:          noteRepo,
+		photoRepo:         photoRepo,
+		deviceRepo:        deviceRepo,
+		ruleRepo:          ruleRepo,
+		userRepo:          userRepo,
+		notifier:          notifier,
+		pusher:            pusher,
+		conflictRepo:      conflictRepo,
+		conflictRetention: conflictRetention,
+		titler:            titler,
+		clock:             clk,
 	}
 }
 
@@ -28,79 +80,264 @@ type SyncInput struct {
 	UserID      uuid.UUID
 	DeviceID    string
 	ClientNotes []ClientNote
-	SyncCursor  *time.Time
+	// ClientPhotos are reconciled against the server after the notes are
+	// saved; see SyncResult.Photos.
+	ClientPhotos []ClientPhoto
+	SyncCursor   *time.Time
+	// ConflictStrategy is one of the Strategy* values; empty means
+	// StrategyLastWriteWins.
+	ConflictStrategy string
+	// Limit caps ServerNotes; 0 or more than maxSyncLimit means maxSyncLimit.
+	Limit int
+	// PageToken is a NextPageToken from a previous sync. The device cursor
+	// only moves once the last page has been returned.
+	PageToken string
+	// SkipAutoTitle keeps the empty and placeholder titles of the pushed
+	// notes rather than generating them from the content.
+	SkipAutoTitle bool
 }
 
 type ClientNote struct {
-	ClientID  string
-	Title     string
-	Content   string
-	Latitude  *float64
-	Longitude *float64
-	Altitude  *float64
-	Accuracy  *float64
+	ClientID     string
+	Title        string
+	Content      string
+	Latitude     *float64
+	Longitude    *float64
+	Altitude     *float64
+	Accuracy     *float64
+	Measurements []valueobject.Measurement
+	// Tags replaces the note's tags when non-nil; nil leaves them unchanged.
+	Tags []string
+	// Color and Icon replace the note's color and icon when non-nil, and
+	// empty takes them off; nil leaves them unchanged.
+	Color     *string
+	Icon      *string
 	UpdatedAt time.Time
-	IsDeleted bool
+	// FieldsUpdatedAt are when the client last changed the title, content
+	// and location. When set, a last-write-wins conflict is merged field
+	// by field; see mergeFields.
+	FieldsUpdatedAt entity.NoteFieldTimes
+	IsDeleted       bool
 }
 
 type SyncResult struct {
 	ServerNotes []entity.Note
 	NewCursor   time.Time
+	Cursors     map[string]time.Time
 	Conflicts   []ConflictInfo
+	Warnings    []ClientWarning
+	Numbers     []NoteNumber
+	Photos      []PhotoStatus
+	// Created is how many notes the sync created: the client's new notes
+	// and the copies kept under StrategyKeepBoth.
+	Created int
+	// HasMore is true when server changes beyond ServerNotes remain. The
+	// client syncs again with NextPageToken until it is false; until then
+	// NewCursor and Cursors are not advanced.
+	HasMore       bool
+	NextPageToken string
+}
+
+// NoteNumber is the server-assigned number of a note the client pushed.
+type NoteNumber struct {
+	ClientID  string
+	Number    int64
+	Reference string
+}
+
+// ClientWarning is a non-fatal issue found in one of the client's notes.
+type ClientWarning struct {
+	ClientID string
+	valueobject.Warning
 }
 
 type ConflictInfo struct {
-	ClientID      string
-	Resolution    string
+	ClientID   string
+	Resolution string
+	// ServerVersion is the stored note the client version was compared
+	// with, or for ResolutionMerged the merged note now stored.
 	ServerVersion *entity.Note
+	// CopyID is the note created from the losing version under
+	// StrategyKeepBoth, or uuid.Nil when nothing was copied.
+	CopyID uuid.UUID
+	// ConflictID is the conflict held under StrategyManual, or uuid.Nil.
+	ConflictID uuid.UUID
 }
 
 const (
 	ResolutionClientWins = "client_wins"
 	ResolutionServerWins = "server_wins"
+	// ResolutionPending leaves the server version in place and holds the
+	// client version until the user replays one side.
+	ResolutionPending = "pending"
+	// ResolutionMerged stores the title, content and location each from
+	// the side that changed it last.
+	ResolutionMerged = "merged"
+)
+
+// Conflict strategies. The first two pick the winner by updated_at;
+// keep_both also saves the losing version as a new note titled
+// "<title> (conflict)". manual picks no winner: the client version is held
+// as a SyncConflict for the user to review.
+const (
+	StrategyLastWriteWins = "last_write_wins"
+	StrategyKeepBoth      = "keep_both"
+	StrategyManual        = "manual"
 )
 
+const (
+	conflictTitleSuffix = " (conflict)"
+	maxTitleLength      = 255
+)
+
+// maxClockSkew is how far in the future a client timestamp may be before it is
+// clamped, so a device with a wrong clock can't win every future conflict.
+const maxClockSkew = 5 * time.Minute
+
+const maxSyncLimit = 1000
+
+// maxSyncPhotos matches the limit on photos in a sync request.
+const maxSyncPhotos = 1000
+
 func (s *Service) BatchSync(ctx context.Context, input SyncInput) (*SyncResult, error) {
 	device, err := s.deviceRepo.GetByUserAndDeviceID(ctx, input.UserID, input.DeviceID)
 	if err != nil {
 		return nil, fmt.Errorf("getting device: %w", err)
 	}
 
-	cursor := device.SyncCursor
+	cursor := device.Cursor(entity.CursorNotes)
 	if input.SyncCursor != nil {
 		cursor = *input.SyncCursor
 	}
 
-	serverNotes, err := s.noteRepo.GetModifiedSince(ctx, input.UserID, cursor, 1000)
+	after := pagination.Cursor{UpdatedAt: cursor}
+	if input.PageToken != "" {
+		token, err := pagination.DecodeCursor(input.PageToken)
+		if err != nil {
+			return nil, domain.ErrInvalidCursor
+		}
+		after = *token
+	}
+
+	limit := input.Limit
+	if limit <= 0 || limit > maxSyncLimit {
+		limit = maxSyncLimit
+	}
+
+	// One extra row tells whether another page follows.
+	serverNotes, err := s.noteRepo.GetModifiedAfter(ctx, input.UserID, after, limit+1, device.Scope)
 	if err != nil {
 		return nil, fmt.Errorf("getting server changes: %w", err)
 	}
+	hasMore := len(serverNotes) > limit
+	if hasMore {
+		serverNotes = serverNotes[:limit]
+	}
+
+	var nextPageToken string
+	if hasMore {
+		last := serverNotes[len(serverNotes)-1]
+		nextPageToken = pagination.Cursor{UpdatedAt: last.UpdatedAt, ID: last.ID}.Encode()
+	}
 
+	// Client IDs are the user's own, so notes other team members created
+	// are only sent down; clients change them through the notes API.
 	serverNoteMap := make(map[string]*entity.Note)
 	for i := range serverNotes {
-		if serverNotes[i].ClientID != "" {
+		if serverNotes[i].ClientID != "" && serverNotes[i].UserID == input.UserID {
 			serverNoteMap[serverNotes[i].ClientID] = &serverNotes[i]
 		}
 	}
 
+	// A single page may miss the server side of a pushed note, which must
+	// still be checked for conflicts.
+	if hasMore || input.PageToken != "" {
+		if err := s.addPushedChanges(ctx, input, cursor, device.Scope, serverNoteMap); err != nil {
+			return nil, err
+		}
+	}
+
 	var conflicts []ConflictInfo
 	var notesToUpsert []entity.Note
+	// replaced holds the server notes the client's versions won over; the
+	// client needs no copy of what it just overwrote.
+	replaced := make(map[uuid.UUID]bool)
+	var warnings []ClientWarning
+	var discarded []entity.DiscardedEdit
+	var copies []int
+	// merges maps the conflicts of merged notes to the notes in
+	// notesToUpsert, which become their ServerVersion once saved.
+	merges := make(map[int]int)
+	var created int
+	keepBoth := input.ConflictStrategy == StrategyKeepBoth
+	manual := input.ConflictStrategy == StrategyManual && s.conflictRepo != nil
 
+	now := s.clock.Now()
 	for _, cn := range input.ClientNotes {
 		if cn.ClientID == "" {
 			continue
 		}
 
+		if cn.UpdatedAt.After(now.Add(maxClockSkew)) {
+			warnings = append(warnings, ClientWarning{
+				ClientID: cn.ClientID,
+				Warning: valueobject.NewWarning(
+					valueobject.WarningFutureTimestamp, "updated_at",
+					"updated_at is in the future and was set to the server time",
+				),
+			})
+			cn.UpdatedAt = now
+		}
+		cn.FieldsUpdatedAt = capFieldTimes(cn.FieldsUpdatedAt, cn.UpdatedAt)
+
 		serverNote, exists := serverNoteMap[cn.ClientID]
 
 		if exists {
-			if cn.UpdatedAt.After(serverNote.UpdatedAt) {
-				updatedNote := clientNoteToEntity(cn, input.UserID, serverNote.ID)
-				notesToUpsert = append(notesToUpsert, updatedNote)
+			if manual {
+				conflict, err := s.holdConflict(ctx, cn, input, serverNote)
+				if err != nil {
+					return nil, err
+				}
+				conflicts = append(conflicts, conflict)
+			} else if merged := s.mergeFields(ctx, cn, input, serverNote, now); merged != nil {
+				merges[len(conflicts)] = len(notesToUpsert)
+				notesToUpsert = append(notesToUpsert, *merged)
+				replaced[serverNote.ID] = true
 				conflicts = append(conflicts, ConflictInfo{
+					ClientID:   cn.ClientID,
+					Resolution: ResolutionMerged,
+				})
+			} else if cn.UpdatedAt.After(serverNote.UpdatedAt) {
+				updatedNote := clientNoteToEntity(cn, input.UserID, input.DeviceID, serverNote.ID)
+				keepCollaborativeContent(&updatedNote, serverNote)
+				keepOffloadedContent(&updatedNote, serverNote)
+				keepAutoTitle(&updatedNote, serverNote)
+				keepFieldTimes(&updatedNote, serverNote)
+				if !input.SkipAutoTitle {
+					s.titler.Retitle(ctx, &updatedNote, serverNote.Content)
+				}
+				notesToUpsert = append(notesToUpsert, updatedNote)
+				replaced[serverNote.ID] = true
+				conflict := ConflictInfo{
 					ClientID:      cn.ClientID,
 					Resolution:    ResolutionClientWins,
 					ServerVersion: serverNote,
+				}
+				if keepBoth && !serverNote.IsDeleted() {
+					copies = append(copies, len(notesToUpsert))
+					notesToUpsert = append(notesToUpsert, conflictCopy(*serverNote, input.DeviceID, now))
+					conflict.CopyID = notesToUpsert[len(notesToUpsert)-1].ID
+				}
+				conflicts = append(conflicts, conflict)
+			} else if keepBoth && !cn.IsDeleted {
+				copies = append(copies, len(notesToUpsert))
+				clientNote := clientNoteToEntity(cn, input.UserID, input.DeviceID, uuid.Nil)
+				notesToUpsert = append(notesToUpsert, conflictCopy(clientNote, input.DeviceID, now))
+				conflicts = append(conflicts, ConflictInfo{
+					ClientID:      cn.ClientID,
+					Resolution:    ResolutionServerWins,
+					ServerVersion: serverNote,
+					CopyID:        notesToUpsert[len(notesToUpsert)-1].ID,
 				})
 			} else {
 				conflicts = append(conflicts, ConflictInfo{
@@ -108,34 +345,402 @@ func (s *Service) BatchSync(ctx context.Context, input SyncInput) (*SyncResult,
 					Resolution:    ResolutionServerWins,
 					ServerVersion: serverNote,
 				})
+				discarded = append(discarded, entity.DiscardedEdit{
+					NoteID:          serverNote.ID,
+					ClientID:        cn.ClientID,
+					Reference:       serverNote.Reference,
+					Title:           serverNote.Title,
+					ClientTitle:     cn.Title,
+					ClientUpdatedAt: cn.UpdatedAt,
+					ServerUpdatedAt: serverNote.UpdatedAt,
+				})
 			}
 		} else {
-			newNote := clientNoteToEntity(cn, input.UserID, uuid.Nil)
+			newNote := clientNoteToEntity(cn, input.UserID, input.DeviceID, uuid.Nil)
+			if !input.SkipAutoTitle {
+				s.titler.Title(ctx, &newNote)
+			}
 			notesToUpsert = append(notesToUpsert, newNote)
+			created++
+		}
+	}
+
+	for i := range notesToUpsert {
+		notesToUpsert[i].Sanitize()
+		notesToUpsert[i].MarkSyncedFrom(device.ID)
+		for _, w := range notesToUpsert[i].Warnings {
+			warnings = append(warnings, ClientWarning{ClientID: notesToUpsert[i].ClientID, Warning: w})
 		}
 	}
 
 	if len(notesToUpsert) > 0 {
+		if err := s.evaluateQuality(ctx, input.UserID, notesToUpsert); err != nil {
+			return nil, err
+		}
 		if err := s.noteRepo.BatchUpsert(ctx, notesToUpsert); err != nil {
 			return nil, fmt.Errorf("upserting notes: %w", err)
 		}
 	}
 
-	newCursor := time.Now().UTC()
+	for c, n := range merges {
+		conflicts[c].ServerVersion = &notesToUpsert[n]
+	}
+
+	serverNotes = withoutOwnChanges(serverNotes, device.ID, replaced)
 
-	device.UpdateSyncCursor(newCursor)
-	if err := s.deviceRepo.Update(ctx, device); err != nil {
-		return nil, fmt.Errorf("updating device cursor: %w", err)
+	// Copies are created after the client's cursor, so they are returned now
+	// rather than in the next sync.
+	for _, i := range copies {
+		serverNotes = append(serverNotes, notesToUpsert[i])
+	}
+
+	var numbers []NoteNumber
+	for _, n := range notesToUpsert {
+		if n.Number > 0 {
+			numbers = append(numbers, NoteNumber{ClientID: n.ClientID, Number: n.Number, Reference: n.Reference})
+		}
+	}
+
+	photos, err := s.reconcilePhotos(ctx, input.UserID, input.ClientPhotos)
+	if err != nil {
+		return nil, err
+	}
+
+	newCursor := cursor
+	if !hasMore {
+		newCursor = s.clock.Now()
+		device.UpdateCursor(entity.CursorNotes, newCursor)
+		if err := s.deviceRepo.Update(ctx, device); err != nil {
+			return nil, fmt.Errorf("updating device cursor: %w", err)
+		}
+	}
+
+	s.notifyDiscarded(ctx, input.UserID, input.DeviceID, discarded)
+	if len(notesToUpsert) > 0 {
+		s.pushOtherDevices(ctx, input.UserID, device.ID)
 	}
 
 	return &SyncResult{
-		ServerNotes: serverNotes,
-		NewCursor:   newCursor,
-		Conflicts:   conflicts,
+		ServerNotes:   serverNotes,
+		NewCursor:     newCursor,
+		Cursors:       device.Cursors,
+		Conflicts:     conflicts,
+		Warnings:      warnings,
+		Numbers:       numbers,
+		Photos:        photos,
+		Created:       created + len(copies),
+		HasMore:       hasMore,
+		NextPageToken: nextPageToken,
 	}, nil
 }
 
-func clientNoteToEntity(cn ClientNote, userID uuid.UUID, existingID uuid.UUID) entity.Note {
+// withoutOwnChanges drops the server changes the device already has: notes
+// still as one of its earlier syncs pushed them, and the server versions its
+// notes replaced in this sync. Conflicts still carry the replaced versions,
+// which point into notes, so it is copied rather than filtered in place.
+func withoutOwnChanges(notes []entity.Note, deviceID uuid.UUID, replaced map[uuid.UUID]bool) []entity.Note {
+	kept := make([]entity.Note, 0, len(notes))
+	for _, n := range notes {
+		if !replaced[n.ID] && !n.PushedBy(deviceID) {
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
+
+// addPushedChanges adds to serverNotes the notes the client pushed that
+// changed on the server since cursor but are not on the current page.
+func (s *Service) addPushedChanges(ctx context.Context, input SyncInput, cursor time.Time, scope entity.SyncScope, serverNotes map[string]*entity.Note) error {
+	var missing []string
+	for _, cn := range input.ClientNotes {
+		if _, ok := serverNotes[cn.ClientID]; !ok && cn.ClientID != "" {
+			missing = append(missing, cn.ClientID)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	notes, err := s.noteRepo.GetByClientIDs(ctx, input.UserID, missing)
+	if err != nil {
+		return fmt.Errorf("getting pushed notes: %w", err)
+	}
+	for i := range notes {
+		if notes[i].UpdatedAt.After(cursor) && scope.IncludesNote(&notes[i]) {
+			serverNotes[notes[i].ClientID] = &notes[i]
+		}
+	}
+	return nil
+}
+
+// notifyDiscarded tells the user which of their edits lost to a newer server
+// version, unless they opted out. It is best effort: the sync is already
+// saved and the client has the conflicts in its response.
+func (s *Service) notifyDiscarded(ctx context.Context, userID uuid.UUID, deviceID string, edits []entity.DiscardedEdit) {
+	if s.notifier == nil || len(edits) == 0 {
+		return
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || !user.NotifySyncConflicts {
+		return
+	}
+
+	_ = s.notifier.SyncConflict(ctx, entity.SyncConflictNotice{
+		UserID:     userID,
+		Email:      user.Email,
+		DeviceID:   deviceID,
+		Notes:      edits,
+		OccurredAt: s.clock.Now(),
+	})
+}
+
+// pushOtherDevices wakes the user's other devices with a silent push so they
+// pull the notes this sync saved. Like notifyDiscarded it is best effort;
+// tokens the push service no longer accepts are cleared.
+func (s *Service) pushOtherDevices(ctx context.Context, userID, originID uuid.UUID) {
+	if s.pusher == nil {
+		return
+	}
+
+	devices, err := s.deviceRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return
+	}
+
+	for i := range devices {
+		device := &devices[i]
+		if device.ID == originID || device.PushToken == "" || !device.SupportsPush() {
+			continue
+		}
+		if err := s.pusher.SilentPush(ctx, *device); errors.Is(err, domain.ErrPushTokenInvalid) {
+			device.SetPushToken("")
+			_ = s.deviceRepo.SetPushToken(ctx, device)
+		}
+	}
+}
+
+// evaluateQuality checks incoming notes against the user's quality rules.
+// Photos are only looked up when a rule depends on them.
+func (s *Service) evaluateQuality(ctx context.Context, userID uuid.UUID, notes []entity.Note) error {
+	rules, err := s.ruleRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("getting quality rules: %w", err)
+	}
+
+	for i := range notes {
+		photoCount := 0
+		if rules.RequirePhoto {
+			if photoCount, err = s.countPhotos(ctx, &notes[i]); err != nil {
+				return err
+			}
+		}
+		notes[i].Quality = rules.Evaluate(&notes[i], photoCount)
+	}
+
+	return nil
+}
+
+// countPhotos counts the photos of the stored note the client note will be
+// upserted into, which may be outside the current sync window.
+func (s *Service) countPhotos(ctx context.Context, note *entity.Note) (int, error) {
+	existing, err := s.noteRepo.GetByClientID(ctx, note.UserID, note.ClientID)
+	if errors.Is(err, domain.ErrNoteNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("getting stored note: %w", err)
+	}
+
+	photos, err := s.photoRepo.GetByNoteID(ctx, existing.ID)
+	if err != nil {
+		return 0, fmt.Errorf("loading photos: %w", err)
+	}
+	return len(photos), nil
+}
+
+// conflictCopy turns the losing version of a conflict into a new note, with
+// its own client ID, so the user can review it alongside the winner.
+func conflictCopy(loser entity.Note, deviceID string, now time.Time) entity.Note {
+	note := loser
+	note.ID = uuid.New()
+	note.ClientID = uuid.New().String()
+	note.Number, note.Reference = 0, ""
+	note.Title = conflictTitle(loser.Title)
+	note.Measurements = slices.Clone(loser.Measurements)
+	note.Tags = slices.Clone(loser.Tags)
+	note.Photos = nil
+	note.Attachments = nil
+	note.Warnings = nil
+	note.MergedInto = nil
+	note.SetOriginDevice(deviceID)
+	note.CreatedAt, note.UpdatedAt = now, now
+	return note
+}
+
+// conflictTitle appends the conflict suffix, shortening the title if needed
+// to stay within the column limit.
+func conflictTitle(title string) string {
+	if runes := []rune(title); len(runes)+len(conflictTitleSuffix) > maxTitleLength {
+		title = string(runes[:maxTitleLength-len(conflictTitleSuffix)])
+	}
+	return title + conflictTitleSuffix
+}
+
+// keepOffloadedContent keeps the full content of a note whose content is
+// stored as an object when the client sends back the excerpt it pulled.
+func keepOffloadedContent(note *entity.Note, server *entity.Note) {
+	if server.ContentExcerpt && note.Content == server.Content {
+		note.ContentKey = server.ContentKey
+		note.ContentExcerpt = true
+	}
+}
+
+// keepAutoTitle keeps a generated title generated when the client sends it
+// back unchanged, so that it goes on following the content.
+func keepAutoTitle(note *entity.Note, server *entity.Note) {
+	note.AutoTitled = server.AutoTitled && note.Title == server.Title
+}
+
+// keepFieldTimes keeps the server's field times for the fields a winning
+// client version left as they were, so they don't look newer than they are
+// in later merges.
+func keepFieldTimes(note *entity.Note, server *entity.Note) {
+	times, st := note.FieldTimes(), server.FieldTimes()
+	if note.Title == server.Title {
+		times.Title = st.Title
+	}
+	if note.Content == server.Content {
+		times.Content = st.Content
+	}
+	if note.Location.Equal(server.Location) {
+		times.Location = st.Location
+	}
+	note.FieldsUpdatedAt = times
+}
+
+// keepCollaborativeContent leaves the content of a collaborative note as the
+// server has it; a whole copy from the client would undo the merged edits.
+func keepCollaborativeContent(note *entity.Note, server *entity.Note) {
+	if !server.Collaborative || note.Content == server.Content {
+		return
+	}
+	note.Content = server.Content
+	note.Warnings = append(note.Warnings, valueobject.NewWarning(
+		valueobject.WarningContentKept, "content",
+		"the note is edited collaboratively; its content was kept and the other fields saved",
+	))
+}
+
+// mergeFields merges a client version that carries field times into the
+// server version under last-write-wins: the title, content and location
+// each come from the side that changed it last, ties going to the server,
+// and everything else from the newer version as a whole. The merged note is
+// stamped now, or just after the newer version, so other devices pull it.
+//
+// It returns nil when there is nothing to merge: the client sent no field
+// times, either side is deleted, or the result is one side unchanged, which
+// whole-note last-write-wins already gives.
+func (s *Service) mergeFields(ctx context.Context, cn ClientNote, input SyncInput, server *entity.Note, now time.Time) *entity.Note {
+	if input.ConflictStrategy == StrategyKeepBoth || cn.FieldsUpdatedAt.IsZero() || cn.IsDeleted || server.IsDeleted() {
+		return nil
+	}
+
+	client := clientNoteToEntity(cn, input.UserID, input.DeviceID, server.ID)
+	keepCollaborativeContent(&client, server)
+	keepOffloadedContent(&client, server)
+
+	ct, st := client.FieldTimes(), server.FieldTimes()
+	titleDiffers := client.Title != server.Title
+	contentDiffers := client.Content != server.Content
+	locationDiffers := !client.Location.Equal(server.Location)
+	clientTitle := titleDiffers && ct.Title.After(st.Title)
+	clientContent := contentDiffers && ct.Content.After(st.Content)
+	clientLocation := locationDiffers && ct.Location.After(st.Location)
+
+	var merged entity.Note
+	if client.UpdatedAt.After(server.UpdatedAt) {
+		if clientTitle == titleDiffers && clientContent == contentDiffers && clientLocation == locationDiffers {
+			return nil
+		}
+		merged = client
+		if !clientTitle {
+			merged.Title = server.Title
+		}
+		if !clientContent {
+			merged.Content = server.Content
+			merged.ContentKey, merged.ContentExcerpt = server.ContentKey, server.ContentExcerpt
+		}
+		if !clientLocation {
+			merged.Location = server.Location
+		}
+		// Clients that predate tags, colors or icons send none; the
+		// server's stay.
+		if merged.Tags == nil {
+			merged.Tags = server.Tags
+		}
+	} else {
+		if !clientTitle && !clientContent && !clientLocation {
+			return nil
+		}
+		merged = *server
+		merged.Warnings = nil
+		merged.Photos, merged.Attachments = nil, nil
+		if clientTitle {
+			merged.Title = client.Title
+		}
+		if clientContent {
+			merged.Content = client.Content
+			merged.ContentKey, merged.ContentExcerpt = "", false
+		}
+		if clientLocation {
+			merged.Location = client.Location
+		}
+	}
+
+	merged.FieldsUpdatedAt = entity.NoteFieldTimes{
+		Title:    pick(clientTitle, ct.Title, st.Title),
+		Content:  pick(clientContent, ct.Content, st.Content),
+		Location: pick(clientLocation, ct.Location, st.Location),
+	}
+	merged.UpdatedAt = now
+	if latest := later(client.UpdatedAt, server.UpdatedAt); !now.After(latest) {
+		merged.UpdatedAt = latest.Add(time.Microsecond)
+	}
+	merged.MarkModifiedBy(input.DeviceID)
+	keepAutoTitle(&merged, server)
+	if !input.SkipAutoTitle {
+		s.titler.Retitle(ctx, &merged, server.Content)
+	}
+	return &merged
+}
+
+func pick(client bool, clientTime, serverTime time.Time) time.Time {
+	if client {
+		return clientTime
+	}
+	return serverTime
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// capFieldTimes keeps the client's field times at or before its note's
+// updated_at, so a wrong clock can't make a field win every future merge.
+func capFieldTimes(t entity.NoteFieldTimes, updatedAt time.Time) entity.NoteFieldTimes {
+	for _, f := range []*time.Time{&t.Title, &t.Content, &t.Location} {
+		if f.After(updatedAt) {
+			*f = updatedAt
+		}
+	}
+	return t
+}
+
+func clientNoteToEntity(cn ClientNote, userID uuid.UUID, deviceID string, existingID uuid.UUID) entity.Note {
 	var loc *valueobject.Location
 	if cn.Latitude != nil && cn.Longitude != nil {
 		loc = valueobject.NewLocation(*cn.Latitude, *cn.Longitude, cn.Altitude, cn.Accuracy)
@@ -147,15 +752,24 @@ func clientNoteToEntity(cn ClientNote, userID uuid.UUID, existingID uuid.UUID) e
 	}
 
 	note := entity.Note{
-		ID:        id,
-		UserID:    userID,
-		Title:     cn.Title,
-		Content:   cn.Content,
-		Location:  loc,
-		ClientID:  cn.ClientID,
-		CreatedAt: cn.UpdatedAt,
-		UpdatedAt: cn.UpdatedAt,
+		ID:              id,
+		UserID:          userID,
+		Title:           cn.Title,
+		Content:         cn.Content,
+		Location:        loc,
+		Measurements:    cn.Measurements,
+		Tags:            cn.Tags,
+		Color:           cn.Color,
+		Icon:            cn.Icon,
+		ClientID:        cn.ClientID,
+		Source:          entity.NoteSourceSync,
+		CreatedAt:       cn.UpdatedAt,
+		UpdatedAt:       cn.UpdatedAt,
+		FieldsUpdatedAt: cn.FieldsUpdatedAt,
 	}
+	// On conflict the upsert keeps the stored created_by_device and only
+	// overwrites last_modified_by_device.
+	note.SetOriginDevice(deviceID)
 
 	if cn.IsDeleted {
 		deletedAt := cn.UpdatedAt
@@ -164,3 +778,100 @@ func clientNoteToEntity(cn ClientNote, userID uuid.UUID, existingID uuid.UUID) e
 
 	return note
 }
+
+type ScopeInput struct {
+	UserID   uuid.UUID
+	DeviceID string
+	Scope    entity.SyncScope
+}
+
+// UpdateScope replaces the sync scope of the device. Widening the scope does
+// not rewind cursors; clients reset their cursor to pull the newly included notes.
+func (s *Service) UpdateScope(ctx context.Context, input ScopeInput) (*entity.Device, error) {
+	device, err := s.deviceRepo.GetByUserAndDeviceID(ctx, input.UserID, input.DeviceID)
+	if err != nil {
+		return nil, fmt.Errorf("getting device: %w", err)
+	}
+
+	device.SetScope(input.Scope)
+	if err := s.deviceRepo.Update(ctx, device); err != nil {
+		return nil, fmt.Errorf("updating device scope: %w", err)
+	}
+
+	return device, nil
+}
+
+type AuthorInput struct {
+	UserID   uuid.UUID
+	DeviceID string
+	Name     string
+}
+
+// SetActiveAuthor records who is using a shared device. Notes the device
+// creates are stamped with the name until it is changed or cleared.
+func (s *Service) SetActiveAuthor(ctx context.Context, input AuthorInput) (*entity.Device, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || utf8.RuneCountInString(name) > entity.MaxDeviceAuthorLength {
+		return nil, domain.ErrInvalidDeviceAuthor
+	}
+	return s.updateActiveAuthor(ctx, input.UserID, input.DeviceID, name)
+}
+
+// ClearActiveAuthor checks out whoever was using the device; its new notes
+// carry no author again.
+func (s *Service) ClearActiveAuthor(ctx context.Context, userID uuid.UUID, deviceID string) (*entity.Device, error) {
+	return s.updateActiveAuthor(ctx, userID, deviceID, "")
+}
+
+type PushTokenInput struct {
+	UserID   uuid.UUID
+	DeviceID string
+	Token    string
+}
+
+// SetPushToken registers the token the device's app received from APNs or
+// FCM, so the device is woken when the user's other devices sync.
+func (s *Service) SetPushToken(ctx context.Context, input PushTokenInput) error {
+	device, err := s.deviceRepo.GetByUserAndDeviceID(ctx, input.UserID, input.DeviceID)
+	if err != nil {
+		return fmt.Errorf("getting device: %w", err)
+	}
+	if !device.SupportsPush() {
+		return domain.ErrPushNotSupported
+	}
+
+	device.SetPushToken(strings.TrimSpace(input.Token))
+	if err := s.deviceRepo.SetPushToken(ctx, device); err != nil {
+		return fmt.Errorf("updating push token: %w", err)
+	}
+	return nil
+}
+
+// ClearPushToken stops pushes to the device, as when the user turns
+// notifications off or signs out.
+func (s *Service) ClearPushToken(ctx context.Context, userID uuid.UUID, deviceID string) error {
+	device, err := s.deviceRepo.GetByUserAndDeviceID(ctx, userID, deviceID)
+	if err != nil {
+		return fmt.Errorf("getting device: %w", err)
+	}
+
+	device.SetPushToken("")
+	if err := s.deviceRepo.SetPushToken(ctx, device); err != nil {
+		return fmt.Errorf("updating push token: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) updateActiveAuthor(ctx context.Context, userID uuid.UUID, deviceID, name string) (*entity.Device, error) {
+	device, err := s.deviceRepo.GetByUserAndDeviceID(ctx, userID, deviceID)
+	if err != nil {
+		return nil, fmt.Errorf("getting device: %w", err)
+	}
+
+	device.SetActiveAuthor(name)
+	if err := s.deviceRepo.Update(ctx, device); err != nil {
+		return nil, fmt.Errorf("updating device author: %w", err)
+	}
+
+	return device, nil
+}
@@ -2,6 +2,7 @@ package sync_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -10,8 +11,13 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
 	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/clock"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/pagination"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/autotitle"
 	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/sync"
 )
 
@@ -24,7 +30,8 @@ func TestService_BatchSync(t *testing.T) {
 
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
 		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
-		svc := sync.NewService(noteRepo, deviceRepo)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := sync.NewService(noteRepo, nil, deviceRepo, ruleRepo, nil, nil, nil, nil, 0, nil, nil)
 
 		userID := uuid.New()
 		deviceID := uuid.New()
@@ -36,8 +43,13 @@ func TestService_BatchSync(t *testing.T) {
 		}
 
 		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(device, nil)
-		noteRepo.EXPECT().GetModifiedSince(ctx, userID, gomock.Any(), 1000).Return([]entity.Note{}, nil)
-		noteRepo.EXPECT().BatchUpsert(ctx, gomock.Any()).Return(nil)
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, gomock.Any(), 1001, entity.SyncScope{}).Return([]entity.Note{}, nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil)
+		noteRepo.EXPECT().BatchUpsert(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, notes []entity.Note) error {
+				notes[0].AssignNumber("PLOT", 42)
+				return nil
+			})
 		deviceRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
 
 		result, err := svc.BatchSync(ctx, sync.SyncInput{
@@ -56,6 +68,7 @@ func TestService_BatchSync(t *testing.T) {
 		require.NoError(t, err)
 		assert.Empty(t, result.ServerNotes)
 		assert.Empty(t, result.Conflicts)
+		assert.Equal(t, []sync.NoteNumber{{ClientID: "note-1", Number: 42, Reference: "PLOT-0042"}}, result.Numbers)
 	})
 
 	t.Run("returns server notes since cursor", func(t *testing.T) {
@@ -64,7 +77,7 @@ func TestService_BatchSync(t *testing.T) {
 
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
 		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
-		svc := sync.NewService(noteRepo, deviceRepo)
+		svc := sync.NewService(noteRepo, nil, deviceRepo, nil, nil, nil, nil, nil, 0, nil, nil)
 
 		userID := uuid.New()
 		deviceID := uuid.New()
@@ -81,7 +94,7 @@ func TestService_BatchSync(t *testing.T) {
 		}
 
 		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(device, nil)
-		noteRepo.EXPECT().GetModifiedSince(ctx, userID, syncCursor, 1000).Return(serverNotes, nil)
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, pagination.Cursor{UpdatedAt: syncCursor}, 1001, entity.SyncScope{}).Return(serverNotes, nil)
 		deviceRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
 
 		result, err := svc.BatchSync(ctx, sync.SyncInput{
@@ -95,13 +108,52 @@ func TestService_BatchSync(t *testing.T) {
 		assert.Equal(t, "Server Note", result.ServerNotes[0].Title)
 	})
 
+	t.Run("sends team notes of other members without matching client ids", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := sync.NewService(noteRepo, nil, deviceRepo, ruleRepo, nil, nil, nil, nil, 0, nil, nil)
+
+		userID := uuid.New()
+		teamID := uuid.New()
+		device := &entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "device-123"}
+		teamNote := entity.Note{ID: uuid.New(), UserID: uuid.New(), TeamID: &teamID, Title: "Colleague's note", ClientID: "note-1"}
+
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(device, nil)
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, gomock.Any(), 1001, entity.SyncScope{}).Return([]entity.Note{teamNote}, nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil)
+		noteRepo.EXPECT().BatchUpsert(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, notes []entity.Note) error {
+				require.Len(t, notes, 1)
+				assert.Equal(t, userID, notes[0].UserID)
+				assert.NotEqual(t, teamNote.ID, notes[0].ID, "the user's note-1 is not the colleague's")
+				return nil
+			})
+		deviceRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+		result, err := svc.BatchSync(ctx, sync.SyncInput{
+			UserID:      userID,
+			DeviceID:    "device-123",
+			ClientNotes: []sync.ClientNote{{ClientID: "note-1", Title: "Mine", UpdatedAt: time.Now()}},
+		})
+
+		require.NoError(t, err)
+		assert.Empty(t, result.Conflicts)
+		require.Len(t, result.ServerNotes, 1)
+		assert.Equal(t, teamNote.ID, result.ServerNotes[0].ID)
+	})
+
 	t.Run("client wins conflict when more recent", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
 		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
-		svc := sync.NewService(noteRepo, deviceRepo)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := sync.NewService(noteRepo, nil, deviceRepo, ruleRepo, nil, nil, nil, nil, 0, nil, nil)
 
 		userID := uuid.New()
 		deviceID := uuid.New()
@@ -125,7 +177,8 @@ func TestService_BatchSync(t *testing.T) {
 		}
 
 		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(device, nil)
-		noteRepo.EXPECT().GetModifiedSince(ctx, userID, gomock.Any(), 1000).Return([]entity.Note{serverNote}, nil)
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, gomock.Any(), 1001, entity.SyncScope{}).Return([]entity.Note{serverNote}, nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil)
 		noteRepo.EXPECT().BatchUpsert(ctx, gomock.Any()).Return(nil)
 		deviceRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
 
@@ -146,6 +199,54 @@ func TestService_BatchSync(t *testing.T) {
 		assert.Len(t, result.Conflicts, 1)
 		assert.Equal(t, "client_wins", result.Conflicts[0].Resolution)
 		assert.Equal(t, "conflict-note", result.Conflicts[0].ClientID)
+		assert.Equal(t, "Server Version", result.Conflicts[0].ServerVersion.Title)
+		assert.Empty(t, result.ServerNotes, "the replaced server version is not sent back")
+	})
+
+	t.Run("skips notes the device pushed that nobody changed since", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := sync.NewService(noteRepo, nil, deviceRepo, ruleRepo, nil, nil, nil, nil, 0, nil, nil)
+
+		userID := uuid.New()
+		device := &entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "device-123"}
+		pushedAt := time.Now().Add(-time.Minute)
+
+		pushed := entity.Note{ID: uuid.New(), UserID: userID, ClientID: "pushed", UpdatedAt: pushedAt}
+		pushed.MarkSyncedFrom(device.ID)
+		editedSince := pushed
+		editedSince.ID, editedSince.ClientID = uuid.New(), "edited-since"
+		editedSince.UpdatedAt = pushedAt.Add(time.Second)
+		otherDevice := entity.Note{ID: uuid.New(), UserID: userID, ClientID: "other", UpdatedAt: pushedAt}
+		otherDevice.MarkSyncedFrom(uuid.New())
+
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(device, nil)
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, gomock.Any(), 1001, entity.SyncScope{}).
+			Return([]entity.Note{pushed, editedSince, otherDevice}, nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil)
+		noteRepo.EXPECT().BatchUpsert(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, notes []entity.Note) error {
+			require.Len(t, notes, 1)
+			assert.True(t, notes[0].PushedBy(device.ID))
+			return nil
+		})
+		deviceRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+		result, err := svc.BatchSync(ctx, sync.SyncInput{
+			UserID:      userID,
+			DeviceID:    "device-123",
+			ClientNotes: []sync.ClientNote{{ClientID: "new-note", Title: "New", UpdatedAt: time.Now()}},
+		})
+
+		require.NoError(t, err)
+		var clientIDs []string
+		for _, n := range result.ServerNotes {
+			clientIDs = append(clientIDs, n.ClientID)
+		}
+		assert.Equal(t, []string{"edited-since", "other"}, clientIDs)
 	})
 
 	t.Run("server wins conflict when more recent", func(t *testing.T) {
@@ -154,7 +255,7 @@ func TestService_BatchSync(t *testing.T) {
 
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
 		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
-		svc := sync.NewService(noteRepo, deviceRepo)
+		svc := sync.NewService(noteRepo, nil, deviceRepo, nil, nil, nil, nil, nil, 0, nil, nil)
 
 		userID := uuid.New()
 		deviceID := uuid.New()
@@ -178,7 +279,7 @@ func TestService_BatchSync(t *testing.T) {
 		}
 
 		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(device, nil)
-		noteRepo.EXPECT().GetModifiedSince(ctx, userID, gomock.Any(), 1000).Return([]entity.Note{serverNote}, nil)
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, gomock.Any(), 1001, entity.SyncScope{}).Return([]entity.Note{serverNote}, nil)
 		deviceRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
 
 		result, err := svc.BatchSync(ctx, sync.SyncInput{
@@ -199,13 +300,273 @@ func TestService_BatchSync(t *testing.T) {
 		assert.Equal(t, "server_wins", result.Conflicts[0].Resolution)
 	})
 
+	t.Run("keep both copies the losing server version", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := sync.NewService(noteRepo, nil, deviceRepo, ruleRepo, nil, nil, nil, nil, 0, nil, nil)
+
+		userID := uuid.New()
+		device := &entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "device-123"}
+		serverNote := entity.Note{
+			ID:        uuid.New(),
+			UserID:    userID,
+			Number:    7,
+			Reference: "NOTE-0007",
+			Title:     "Server Version",
+			Content:   "Edited on the web",
+			ClientID:  "conflict-note",
+			UpdatedAt: time.Now().Add(-1 * time.Hour),
+		}
+
+		var upserted []entity.Note
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(device, nil)
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, gomock.Any(), 1001, entity.SyncScope{}).Return([]entity.Note{serverNote}, nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil)
+		noteRepo.EXPECT().BatchUpsert(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, notes []entity.Note) error {
+			upserted = notes
+			return nil
+		})
+		deviceRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+		result, err := svc.BatchSync(ctx, sync.SyncInput{
+			UserID:           userID,
+			DeviceID:         "device-123",
+			ConflictStrategy: sync.StrategyKeepBoth,
+			ClientNotes: []sync.ClientNote{
+				{ClientID: "conflict-note", Title: "Client Version", Content: "Edited offline", UpdatedAt: time.Now()},
+			},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, upserted, 2)
+		assert.Equal(t, serverNote.ID, upserted[0].ID)
+		assert.Equal(t, "Client Version", upserted[0].Title)
+
+		copied := upserted[1]
+		assert.NotEqual(t, serverNote.ID, copied.ID)
+		assert.NotEqual(t, "conflict-note", copied.ClientID)
+		assert.Equal(t, "Server Version (conflict)", copied.Title)
+		assert.Equal(t, "Edited on the web", copied.Content)
+		assert.Zero(t, copied.Number)
+
+		require.Len(t, result.Conflicts, 1)
+		assert.Equal(t, sync.ResolutionClientWins, result.Conflicts[0].Resolution)
+		assert.Equal(t, copied.ID, result.Conflicts[0].CopyID)
+		assert.Equal(t, copied.ID, result.ServerNotes[len(result.ServerNotes)-1].ID)
+	})
+
+	t.Run("client echoing an offloaded excerpt keeps the full content", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := sync.NewService(noteRepo, nil, deviceRepo, ruleRepo, nil, nil, nil, nil, 0, nil, nil)
+
+		userID := uuid.New()
+		device := &entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "device-123"}
+		serverNote := entity.Note{
+			ID:             uuid.New(),
+			UserID:         userID,
+			Title:          "Survey log",
+			Content:        "Start of a long log",
+			ContentKey:     "notes/log/content-1.txt",
+			ContentExcerpt: true,
+			ClientID:       "long-note",
+			UpdatedAt:      time.Now().Add(-1 * time.Hour),
+		}
+
+		var upserted []entity.Note
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(device, nil)
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, gomock.Any(), 1001, entity.SyncScope{}).Return([]entity.Note{serverNote}, nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil)
+		noteRepo.EXPECT().BatchUpsert(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, notes []entity.Note) error {
+			upserted = notes
+			return nil
+		})
+		deviceRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+		_, err := svc.BatchSync(ctx, sync.SyncInput{
+			UserID:   userID,
+			DeviceID: "device-123",
+			ClientNotes: []sync.ClientNote{
+				{ClientID: "long-note", Title: "Survey log (day 2)", Content: "Start of a long log", UpdatedAt: time.Now()},
+				{ClientID: "other-note", Title: "Other", Content: "Start of a long log", UpdatedAt: time.Now()},
+			},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, upserted, 2)
+		assert.Equal(t, "Survey log (day 2)", upserted[0].Title)
+		assert.True(t, upserted[0].ContentExcerpt)
+		assert.Equal(t, "notes/log/content-1.txt", upserted[0].ContentKey)
+		assert.False(t, upserted[1].ContentExcerpt)
+	})
+
+	t.Run("titles pushed notes without a title and keeps generated titles following the content", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := sync.NewService(noteRepo, nil, deviceRepo, ruleRepo, nil, nil, nil, nil, 0, autotitle.NewTitler(nil), nil)
+
+		userID := uuid.New()
+		device := &entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "device-123"}
+		serverNote := entity.Note{
+			ID:         uuid.New(),
+			UserID:     userID,
+			Title:      "Heron nest",
+			Content:    "Heron nest",
+			AutoTitled: true,
+			ClientID:   "titled-note",
+			UpdatedAt:  time.Now().Add(-1 * time.Hour),
+		}
+
+		var upserted []entity.Note
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(device, nil).Times(2)
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, gomock.Any(), 1001, entity.SyncScope{}).Return([]entity.Note{serverNote}, nil).Times(2)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil).Times(2)
+		noteRepo.EXPECT().BatchUpsert(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, notes []entity.Note) error {
+			upserted = notes
+			return nil
+		}).Times(2)
+		deviceRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil).Times(2)
+
+		notes := []sync.ClientNote{
+			{ClientID: "titled-note", Title: "Heron nest", Content: "Heron nest, two eggs", UpdatedAt: time.Now()},
+			{ClientID: "voice-note", Title: "Voice note", Content: "Fence down. Cattle out.", UpdatedAt: time.Now()},
+		}
+		_, err := svc.BatchSync(ctx, sync.SyncInput{UserID: userID, DeviceID: "device-123", ClientNotes: notes})
+		require.NoError(t, err)
+		require.Len(t, upserted, 2)
+		assert.Equal(t, "Heron nest, two eggs", upserted[0].Title)
+		assert.True(t, upserted[0].AutoTitled)
+		assert.Equal(t, "Fence down.", upserted[1].Title)
+		assert.True(t, upserted[1].AutoTitled)
+
+		_, err = svc.BatchSync(ctx, sync.SyncInput{UserID: userID, DeviceID: "device-123", ClientNotes: notes, SkipAutoTitle: true})
+		require.NoError(t, err)
+		require.Len(t, upserted, 2)
+		assert.Equal(t, "Heron nest", upserted[0].Title)
+		assert.True(t, upserted[0].AutoTitled)
+		assert.Equal(t, "Voice note", upserted[1].Title)
+		assert.False(t, upserted[1].AutoTitled)
+	})
+
+	t.Run("client winning over a collaborative note keeps its content", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := sync.NewService(noteRepo, nil, deviceRepo, ruleRepo, nil, nil, nil, nil, 0, nil, nil)
+
+		userID := uuid.New()
+		device := &entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "device-123"}
+		serverNote := entity.Note{
+			ID:            uuid.New(),
+			UserID:        userID,
+			Title:         "Transect",
+			Content:       "merged edits",
+			ClientID:      "shared-note",
+			Collaborative: true,
+			UpdatedAt:     time.Now().Add(-1 * time.Hour),
+		}
+
+		var upserted []entity.Note
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(device, nil)
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, gomock.Any(), 1001, entity.SyncScope{}).Return([]entity.Note{serverNote}, nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil)
+		noteRepo.EXPECT().BatchUpsert(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, notes []entity.Note) error {
+			upserted = notes
+			return nil
+		})
+		deviceRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+		result, err := svc.BatchSync(ctx, sync.SyncInput{
+			UserID:   userID,
+			DeviceID: "device-123",
+			ClientNotes: []sync.ClientNote{
+				{ClientID: "shared-note", Title: "Transect 4", Content: "an old copy", UpdatedAt: time.Now()},
+			},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, upserted, 1)
+		assert.Equal(t, "Transect 4", upserted[0].Title)
+		assert.Equal(t, "merged edits", upserted[0].Content)
+		require.Len(t, result.Warnings, 1)
+		assert.Equal(t, valueobject.WarningContentKept, result.Warnings[0].Code)
+		assert.Equal(t, "shared-note", result.Warnings[0].ClientID)
+	})
+
+	t.Run("keep both copies the losing client version", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := sync.NewService(noteRepo, nil, deviceRepo, ruleRepo, nil, nil, nil, nil, 0, nil, nil)
+
+		userID := uuid.New()
+		device := &entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "device-123"}
+		serverNote := entity.Note{
+			ID:        uuid.New(),
+			UserID:    userID,
+			Title:     "Server Version",
+			ClientID:  "conflict-note",
+			UpdatedAt: time.Now(),
+		}
+		longTitle := strings.Repeat("a", 255)
+
+		var upserted []entity.Note
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(device, nil)
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, gomock.Any(), 1001, entity.SyncScope{}).Return([]entity.Note{serverNote}, nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil)
+		noteRepo.EXPECT().BatchUpsert(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, notes []entity.Note) error {
+			upserted = notes
+			return nil
+		})
+		deviceRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+		result, err := svc.BatchSync(ctx, sync.SyncInput{
+			UserID:           userID,
+			DeviceID:         "device-123",
+			ConflictStrategy: sync.StrategyKeepBoth,
+			ClientNotes: []sync.ClientNote{
+				{ClientID: "conflict-note", Title: longTitle, Content: "Edited offline", UpdatedAt: time.Now().Add(-1 * time.Hour)},
+			},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, upserted, 1)
+		assert.NotEqual(t, "conflict-note", upserted[0].ClientID)
+		assert.Equal(t, "Edited offline", upserted[0].Content)
+		assert.Len(t, []rune(upserted[0].Title), 255)
+		assert.True(t, strings.HasSuffix(upserted[0].Title, " (conflict)"))
+
+		require.Len(t, result.Conflicts, 1)
+		assert.Equal(t, sync.ResolutionServerWins, result.Conflicts[0].Resolution)
+		assert.Equal(t, upserted[0].ID, result.Conflicts[0].CopyID)
+	})
+
 	t.Run("handles deleted notes from client", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
 		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
-		svc := sync.NewService(noteRepo, deviceRepo)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := sync.NewService(noteRepo, nil, deviceRepo, ruleRepo, nil, nil, nil, nil, 0, nil, nil)
 
 		userID := uuid.New()
 		deviceID := uuid.New()
@@ -217,7 +578,8 @@ func TestService_BatchSync(t *testing.T) {
 		}
 
 		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(device, nil)
-		noteRepo.EXPECT().GetModifiedSince(ctx, userID, gomock.Any(), 1000).Return([]entity.Note{}, nil)
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, gomock.Any(), 1001, entity.SyncScope{}).Return([]entity.Note{}, nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil)
 		noteRepo.EXPECT().BatchUpsert(ctx, gomock.AssignableToTypeOf([]entity.Note{})).DoAndReturn(
 			func(ctx context.Context, notes []entity.Note) error {
 				assert.Len(t, notes, 1)
@@ -250,7 +612,7 @@ func TestService_BatchSync(t *testing.T) {
 
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
 		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
-		svc := sync.NewService(noteRepo, deviceRepo)
+		svc := sync.NewService(noteRepo, nil, deviceRepo, nil, nil, nil, nil, nil, 0, nil, nil)
 
 		userID := uuid.New()
 		deviceID := uuid.New()
@@ -263,7 +625,7 @@ func TestService_BatchSync(t *testing.T) {
 		}
 
 		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(device, nil)
-		noteRepo.EXPECT().GetModifiedSince(ctx, userID, oldCursor, 1000).Return([]entity.Note{}, nil)
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, pagination.Cursor{UpdatedAt: oldCursor}, 1001, entity.SyncScope{}).Return([]entity.Note{}, nil)
 		deviceRepo.EXPECT().Update(ctx, gomock.AssignableToTypeOf(&entity.Device{})).DoAndReturn(
 			func(ctx context.Context, d *entity.Device) error {
 				assert.True(t, d.SyncCursor.After(oldCursor))
@@ -279,4 +641,1131 @@ func TestService_BatchSync(t *testing.T) {
 		require.NoError(t, err)
 		assert.True(t, result.NewCursor.After(oldCursor))
 	})
+
+	t.Run("prefers named notes cursor over legacy sync cursor", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		svc := sync.NewService(noteRepo, nil, deviceRepo, nil, nil, nil, nil, nil, 0, nil, nil)
+
+		userID := uuid.New()
+		legacyCursor := time.Now().Add(-2 * time.Hour)
+		notesCursor := time.Now().Add(-1 * time.Hour)
+		device := &entity.Device{
+			ID:         uuid.New(),
+			UserID:     userID,
+			DeviceID:   "device-123",
+			SyncCursor: legacyCursor,
+			Cursors: map[string]time.Time{
+				entity.CursorNotes: notesCursor,
+			},
+		}
+
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(device, nil)
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, pagination.Cursor{UpdatedAt: notesCursor}, 1001, entity.SyncScope{}).Return([]entity.Note{}, nil)
+		deviceRepo.EXPECT().Update(ctx, gomock.AssignableToTypeOf(&entity.Device{})).DoAndReturn(
+			func(ctx context.Context, d *entity.Device) error {
+				assert.True(t, d.Cursor(entity.CursorNotes).After(notesCursor))
+				return nil
+			})
+
+		result, err := svc.BatchSync(ctx, sync.SyncInput{
+			UserID:      userID,
+			DeviceID:    "device-123",
+			ClientNotes: []sync.ClientNote{},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, result.NewCursor, result.Cursors[entity.CursorNotes])
+	})
+
+	t.Run("pages server notes and advances the cursor on the last page", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		svc := sync.NewService(noteRepo, nil, deviceRepo, nil, nil, nil, nil, nil, 0, nil, nil)
+
+		userID := uuid.New()
+		oldCursor := time.Now().Add(-1 * time.Hour)
+		device := &entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "device-123", SyncCursor: oldCursor}
+		at := oldCursor.Add(time.Minute)
+		notes := []entity.Note{
+			{ID: uuid.New(), UserID: userID, UpdatedAt: at},
+			{ID: uuid.New(), UserID: userID, UpdatedAt: at},
+			{ID: uuid.New(), UserID: userID, UpdatedAt: at.Add(time.Second)},
+		}
+
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(device, nil).Times(2)
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, pagination.Cursor{UpdatedAt: oldCursor}, 3, entity.SyncScope{}).Return(notes, nil)
+
+		result, err := svc.BatchSync(ctx, sync.SyncInput{UserID: userID, DeviceID: "device-123", Limit: 2})
+
+		require.NoError(t, err)
+		assert.Len(t, result.ServerNotes, 2)
+		assert.True(t, result.HasMore)
+		assert.Equal(t, oldCursor, result.NewCursor)
+
+		next, err := pagination.DecodeCursor(result.NextPageToken)
+		require.NoError(t, err)
+		assert.Equal(t, notes[1].ID, next.ID)
+
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, *next, 3, entity.SyncScope{}).Return(notes[2:], nil)
+		deviceRepo.EXPECT().Update(ctx, gomock.AssignableToTypeOf(&entity.Device{})).Return(nil)
+
+		result, err = svc.BatchSync(ctx, sync.SyncInput{UserID: userID, DeviceID: "device-123", Limit: 2, PageToken: result.NextPageToken})
+
+		require.NoError(t, err)
+		assert.Len(t, result.ServerNotes, 1)
+		assert.False(t, result.HasMore)
+		assert.Empty(t, result.NextPageToken)
+		assert.True(t, result.NewCursor.After(oldCursor))
+	})
+
+	t.Run("checks pushed notes outside the page for conflicts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		svc := sync.NewService(noteRepo, nil, deviceRepo, nil, nil, nil, nil, nil, 0, nil, nil)
+
+		userID := uuid.New()
+		oldCursor := time.Now().Add(-2 * time.Hour)
+		device := &entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "device-123", SyncCursor: oldCursor}
+		page := []entity.Note{
+			{ID: uuid.New(), UserID: userID, ClientID: "first", UpdatedAt: oldCursor.Add(time.Minute)},
+			{ID: uuid.New(), UserID: userID, ClientID: "second", UpdatedAt: oldCursor.Add(2 * time.Minute)},
+		}
+		later := entity.Note{ID: uuid.New(), UserID: userID, ClientID: "later", Title: "Server Version", UpdatedAt: time.Now()}
+
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(device, nil)
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, gomock.Any(), 2, entity.SyncScope{}).Return(page, nil)
+		noteRepo.EXPECT().GetByClientIDs(ctx, userID, []string{"later"}).Return([]entity.Note{later}, nil)
+
+		result, err := svc.BatchSync(ctx, sync.SyncInput{
+			UserID:   userID,
+			DeviceID: "device-123",
+			Limit:    1,
+			ClientNotes: []sync.ClientNote{
+				{ClientID: "later", Title: "Client Version", Content: "Old", UpdatedAt: time.Now().Add(-1 * time.Hour)},
+			},
+		})
+
+		require.NoError(t, err)
+		assert.True(t, result.HasMore)
+		require.Len(t, result.Conflicts, 1)
+		assert.Equal(t, "server_wins", result.Conflicts[0].Resolution)
+		assert.Equal(t, later.ID, result.Conflicts[0].ServerVersion.ID)
+	})
+
+	t.Run("rejects malformed page token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		svc := sync.NewService(nil, nil, deviceRepo, nil, nil, nil, nil, nil, 0, nil, nil)
+
+		userID := uuid.New()
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(&entity.Device{UserID: userID, DeviceID: "device-123"}, nil)
+
+		result, err := svc.BatchSync(ctx, sync.SyncInput{UserID: userID, DeviceID: "device-123", PageToken: "not a token"})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+	})
+}
+
+func TestService_BatchSyncFieldMerge(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	serverTime := now.Add(-10 * time.Minute)
+
+	setup := func(t *testing.T) (*sync.Service, *mocks.MockNoteRepository, uuid.UUID, entity.Note) {
+		ctrl := gomock.NewController(t)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := sync.NewService(noteRepo, nil, deviceRepo, ruleRepo, nil, nil, nil, nil, 0, nil, clock.NewFake(now))
+
+		userID := uuid.New()
+		device := &entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "device-123"}
+		// The title was last changed an hour ago, the content just now.
+		serverNote := entity.Note{
+			ID: uuid.New(), UserID: userID, ClientID: "oak", Title: "Oak survey", Content: "Three oaks",
+			UpdatedAt:       serverTime,
+			FieldsUpdatedAt: entity.NoteFieldTimes{Title: serverTime.Add(-time.Hour), Content: serverTime},
+		}
+
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(device, nil)
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, gomock.Any(), 1001, entity.SyncScope{}).Return([]entity.Note{serverNote}, nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil).AnyTimes()
+		deviceRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+		return svc, noteRepo, userID, serverNote
+	}
+
+	t.Run("merges fields changed on different sides", func(t *testing.T) {
+		svc, noteRepo, userID, serverNote := setup(t)
+		clientTime := serverTime.Add(-30 * time.Minute)
+
+		noteRepo.EXPECT().BatchUpsert(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, notes []entity.Note) error {
+				require.Len(t, notes, 1)
+				assert.Equal(t, serverNote.ID, notes[0].ID)
+				assert.Equal(t, "Oak census", notes[0].Title)
+				assert.Equal(t, "Three oaks", notes[0].Content)
+				assert.Equal(t, now, notes[0].UpdatedAt)
+				assert.Equal(t, entity.NoteFieldTimes{
+					Title: clientTime, Content: serverTime, Location: serverTime,
+				}, notes[0].FieldsUpdatedAt)
+				return nil
+			})
+
+		result, err := svc.BatchSync(ctx, sync.SyncInput{
+			UserID:   userID,
+			DeviceID: "device-123",
+			ClientNotes: []sync.ClientNote{{
+				ClientID: "oak", Title: "Oak census", Content: "Two oaks",
+				UpdatedAt:       clientTime,
+				FieldsUpdatedAt: entity.NoteFieldTimes{Title: clientTime, Content: clientTime.Add(-time.Hour)},
+			}},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, result.Conflicts, 1)
+		assert.Equal(t, sync.ResolutionMerged, result.Conflicts[0].Resolution)
+		assert.Equal(t, "Oak census", result.Conflicts[0].ServerVersion.Title)
+		assert.Empty(t, result.ServerNotes)
+	})
+
+	t.Run("leaves a version newer on every field to last-write-wins", func(t *testing.T) {
+		svc, noteRepo, userID, _ := setup(t)
+		clientTime := now.Add(-time.Minute)
+
+		noteRepo.EXPECT().BatchUpsert(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, notes []entity.Note) error {
+				assert.Equal(t, "Two oaks", notes[0].Content)
+				assert.Equal(t, clientTime, notes[0].UpdatedAt)
+				return nil
+			})
+
+		result, err := svc.BatchSync(ctx, sync.SyncInput{
+			UserID:   userID,
+			DeviceID: "device-123",
+			ClientNotes: []sync.ClientNote{{
+				ClientID: "oak", Title: "Oak census", Content: "Two oaks",
+				UpdatedAt:       clientTime,
+				FieldsUpdatedAt: entity.NoteFieldTimes{Title: clientTime, Content: clientTime},
+			}},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, result.Conflicts, 1)
+		assert.Equal(t, sync.ResolutionClientWins, result.Conflicts[0].Resolution)
+	})
+
+	t.Run("does not merge under keep_both", func(t *testing.T) {
+		svc, noteRepo, userID, _ := setup(t)
+		clientTime := serverTime.Add(-30 * time.Minute)
+
+		noteRepo.EXPECT().BatchUpsert(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, notes []entity.Note) error {
+				require.Len(t, notes, 1)
+				assert.Equal(t, "Oak census (conflict)", notes[0].Title)
+				return nil
+			})
+
+		result, err := svc.BatchSync(ctx, sync.SyncInput{
+			UserID:           userID,
+			DeviceID:         "device-123",
+			ConflictStrategy: sync.StrategyKeepBoth,
+			ClientNotes: []sync.ClientNote{{
+				ClientID: "oak", Title: "Oak census", Content: "Two oaks",
+				UpdatedAt:       clientTime,
+				FieldsUpdatedAt: entity.NoteFieldTimes{Title: clientTime},
+			}},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, result.Conflicts, 1)
+		assert.Equal(t, sync.ResolutionServerWins, result.Conflicts[0].Resolution)
+	})
+}
+
+func TestService_BatchSyncWarnings(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("clamps future timestamps and flags low accuracy", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+		svc := sync.NewService(noteRepo, nil, deviceRepo, ruleRepo, nil, nil, nil, nil, 0, nil, clock.NewFake(now))
+
+		userID := uuid.New()
+		device := &entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "device-123"}
+		future := now.Add(24 * time.Hour)
+		lat, lng, accuracy := 37.77, -122.41, 250.0
+
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(device, nil)
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, gomock.Any(), 1001, entity.SyncScope{}).Return([]entity.Note{}, nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil)
+		noteRepo.EXPECT().BatchUpsert(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, notes []entity.Note) error {
+				require.Len(t, notes, 1)
+				assert.Equal(t, now, notes[0].UpdatedAt)
+				return nil
+			})
+		deviceRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+		result, err := svc.BatchSync(ctx, sync.SyncInput{
+			UserID:   userID,
+			DeviceID: "device-123",
+			ClientNotes: []sync.ClientNote{
+				{
+					ClientID:  "note-1",
+					Title:     "Note",
+					Content:   "Content",
+					Latitude:  &lat,
+					Longitude: &lng,
+					Accuracy:  &accuracy,
+					UpdatedAt: future,
+				},
+			},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, result.Warnings, 2)
+		codes := []string{result.Warnings[0].Code, result.Warnings[1].Code}
+		assert.ElementsMatch(t, []string{valueobject.WarningFutureTimestamp, valueobject.WarningLowAccuracy}, codes)
+		assert.Equal(t, "note-1", result.Warnings[0].ClientID)
+		assert.Equal(t, now, result.NewCursor)
+	})
+
+	t.Run("normalizes tags and drops invalid ones", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := sync.NewService(noteRepo, nil, deviceRepo, ruleRepo, nil, nil, nil, nil, 0, nil, nil)
+
+		userID := uuid.New()
+		device := &entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "device-123"}
+
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(device, nil)
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, gomock.Any(), 1001, entity.SyncScope{}).Return([]entity.Note{}, nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil)
+		noteRepo.EXPECT().BatchUpsert(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, notes []entity.Note) error {
+				require.Len(t, notes, 2)
+				assert.Equal(t, []string{"soil-sample", "wildlife"}, notes[0].Tags)
+				assert.Nil(t, notes[1].Tags)
+				return nil
+			})
+		deviceRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+		result, err := svc.BatchSync(ctx, sync.SyncInput{
+			UserID:   userID,
+			DeviceID: "device-123",
+			ClientNotes: []sync.ClientNote{
+				{ClientID: "note-1", Title: "Tagged", Tags: []string{"Wildlife", "soil-sample", "bad tag!"}, UpdatedAt: time.Now()},
+				{ClientID: "note-2", Title: "Untouched", UpdatedAt: time.Now()},
+			},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, result.Warnings, 1)
+		assert.Equal(t, valueobject.WarningTagDropped, result.Warnings[0].Code)
+		assert.Equal(t, "note-1", result.Warnings[0].ClientID)
+	})
+
+	t.Run("normalizes colors and icons and drops invalid ones", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := sync.NewService(noteRepo, nil, deviceRepo, ruleRepo, nil, nil, nil, nil, 0, nil, nil)
+
+		userID := uuid.New()
+		device := &entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "device-123"}
+		green, dragon, none := "Green", "dragon", ""
+
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(device, nil)
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, gomock.Any(), 1001, entity.SyncScope{}).Return([]entity.Note{}, nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil)
+		noteRepo.EXPECT().BatchUpsert(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, notes []entity.Note) error {
+				require.Len(t, notes, 2)
+				require.NotNil(t, notes[0].Color)
+				assert.Equal(t, "green", *notes[0].Color)
+				assert.Nil(t, notes[0].Icon, "an invalid icon leaves the stored one")
+				require.NotNil(t, notes[1].Color)
+				assert.Empty(t, *notes[1].Color, "an empty color takes it off")
+				assert.Nil(t, notes[1].Icon)
+				return nil
+			})
+		deviceRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+		result, err := svc.BatchSync(ctx, sync.SyncInput{
+			UserID:   userID,
+			DeviceID: "device-123",
+			ClientNotes: []sync.ClientNote{
+				{ClientID: "note-1", Title: "Nest", Color: &green, Icon: &dragon, UpdatedAt: time.Now()},
+				{ClientID: "note-2", Title: "Plain", Color: &none, UpdatedAt: time.Now()},
+			},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, result.Warnings, 1)
+		assert.Equal(t, valueobject.WarningStyleDropped, result.Warnings[0].Code)
+		assert.Equal(t, "icon", result.Warnings[0].Field)
+		assert.Equal(t, "note-1", result.Warnings[0].ClientID)
+	})
+}
+
+func TestService_BatchSyncQuality(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("counts photos of the stored note for the photo rule", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		svc := sync.NewService(noteRepo, photoRepo, deviceRepo, ruleRepo, nil, nil, nil, nil, 0, nil, nil)
+
+		userID := uuid.New()
+		storedID := uuid.New()
+		device := &entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "device-123"}
+		rules := &entity.QualityRules{UserID: userID, RequirePhoto: true}
+
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(device, nil)
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, gomock.Any(), 1001, entity.SyncScope{}).Return([]entity.Note{}, nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(rules, nil)
+		noteRepo.EXPECT().GetByClientID(ctx, userID, "with-photo").Return(&entity.Note{ID: storedID}, nil)
+		photoRepo.EXPECT().GetByNoteID(ctx, storedID).Return([]entity.Photo{{ID: uuid.New()}}, nil)
+		noteRepo.EXPECT().GetByClientID(ctx, userID, "new-note").Return(nil, domain.ErrNoteNotFound)
+		noteRepo.EXPECT().BatchUpsert(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, notes []entity.Note) error {
+				require.Len(t, notes, 2)
+				assert.Equal(t, entity.QualityPassed, notes[0].Quality.Status)
+				assert.Equal(t, entity.QualityFailed, notes[1].Quality.Status)
+				return nil
+			})
+		deviceRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+		_, err := svc.BatchSync(ctx, sync.SyncInput{
+			UserID:   userID,
+			DeviceID: "device-123",
+			ClientNotes: []sync.ClientNote{
+				{ClientID: "with-photo", Title: "Edited", UpdatedAt: time.Now()},
+				{ClientID: "new-note", Title: "New", UpdatedAt: time.Now()},
+			},
+		})
+
+		require.NoError(t, err)
+	})
+}
+
+func TestService_BatchSyncConflictNotification(t *testing.T) {
+	setup := func(t *testing.T, ctrl *gomock.Controller) (*sync.Service, *mocks.MockUserRepository, *mocks.MockNotifier, uuid.UUID, sync.SyncInput) {
+		t.Helper()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		notifier := mocks.NewMockNotifier(ctrl)
+		svc := sync.NewService(noteRepo, nil, deviceRepo, nil, userRepo, notifier, nil, nil, 0, nil, nil)
+
+		userID := uuid.New()
+		serverNote := entity.Note{
+			ID:        uuid.New(),
+			UserID:    userID,
+			Reference: "NOTE-0003",
+			Title:     "Server Version",
+			ClientID:  "conflict-note",
+			UpdatedAt: time.Now(),
+		}
+
+		deviceRepo.EXPECT().GetByUserAndDeviceID(gomock.Any(), userID, "device-123").Return(&entity.Device{UserID: userID, DeviceID: "device-123"}, nil)
+		noteRepo.EXPECT().GetModifiedAfter(gomock.Any(), userID, gomock.Any(), 1001, entity.SyncScope{}).Return([]entity.Note{serverNote}, nil)
+		deviceRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+		input := sync.SyncInput{
+			UserID:   userID,
+			DeviceID: "device-123",
+			ClientNotes: []sync.ClientNote{
+				{ClientID: "conflict-note", Title: "Client Version", UpdatedAt: time.Now().Add(-time.Hour)},
+			},
+		}
+		return svc, userRepo, notifier, serverNote.ID, input
+	}
+
+	t.Run("notifies the user of discarded client edits", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc, userRepo, notifier, noteID, input := setup(t, ctrl)
+
+		userRepo.EXPECT().GetByID(gomock.Any(), input.UserID).Return(&entity.User{ID: input.UserID, Email: "user@example.com", NotifySyncConflicts: true}, nil)
+		notifier.EXPECT().SyncConflict(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, notice entity.SyncConflictNotice) error {
+				assert.Equal(t, "user@example.com", notice.Email)
+				assert.Equal(t, "device-123", notice.DeviceID)
+				require.Len(t, notice.Notes, 1)
+				assert.Equal(t, noteID, notice.Notes[0].NoteID)
+				assert.Equal(t, "NOTE-0003", notice.Notes[0].Reference)
+				assert.Equal(t, "Client Version", notice.Notes[0].ClientTitle)
+				return nil
+			})
+
+		result, err := svc.BatchSync(context.Background(), input)
+
+		require.NoError(t, err)
+		assert.Len(t, result.Conflicts, 1)
+	})
+
+	t.Run("skips users who opted out", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc, userRepo, _, _, input := setup(t, ctrl)
+
+		userRepo.EXPECT().GetByID(gomock.Any(), input.UserID).Return(&entity.User{ID: input.UserID, NotifySyncConflicts: false}, nil)
+
+		_, err := svc.BatchSync(context.Background(), input)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("does not fail the sync when notifying fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc, userRepo, notifier, _, input := setup(t, ctrl)
+
+		userRepo.EXPECT().GetByID(gomock.Any(), input.UserID).Return(&entity.User{ID: input.UserID, NotifySyncConflicts: true}, nil)
+		notifier.EXPECT().SyncConflict(gomock.Any(), gomock.Any()).Return(assert.AnError)
+
+		result, err := svc.BatchSync(context.Background(), input)
+
+		require.NoError(t, err)
+		assert.Len(t, result.Conflicts, 1)
+	})
+}
+
+func TestService_BatchSyncPush(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	origin := entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "phone", Platform: entity.PlatformIOS, PushToken: "origin-token"}
+	tablet := entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "tablet", Platform: entity.PlatformAndroid, PushToken: "tablet-token"}
+	laptop := entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "laptop", Platform: entity.PlatformWeb}
+
+	setup := func(t *testing.T) (*sync.Service, *mocks.MockDeviceRepository, *mocks.MockNoteRepository, *mocks.MockPusher) {
+		ctrl := gomock.NewController(t)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		pusher := mocks.NewMockPusher(ctrl)
+		svc := sync.NewService(noteRepo, nil, deviceRepo, ruleRepo, nil, nil, pusher, nil, 0, nil, nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil).AnyTimes()
+
+		originCopy := origin
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "phone").Return(&originCopy, nil)
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, gomock.Any(), 1001, entity.SyncScope{}).Return(nil, nil)
+		deviceRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+		return svc, deviceRepo, noteRepo, pusher
+	}
+
+	t.Run("wakes the user's other devices after saving notes", func(t *testing.T) {
+		svc, deviceRepo, noteRepo, pusher := setup(t)
+		noteRepo.EXPECT().BatchUpsert(ctx, gomock.Any()).Return(nil)
+		deviceRepo.EXPECT().ListByUserID(ctx, userID).Return([]entity.Device{origin, tablet, laptop}, nil)
+		pusher.EXPECT().SilentPush(ctx, tablet).Return(nil)
+
+		_, err := svc.BatchSync(ctx, sync.SyncInput{
+			UserID: userID, DeviceID: "phone",
+			ClientNotes: []sync.ClientNote{{ClientID: "note-1", Title: "New", UpdatedAt: time.Now()}},
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("clears tokens the push service rejects", func(t *testing.T) {
+		svc, deviceRepo, noteRepo, pusher := setup(t)
+		noteRepo.EXPECT().BatchUpsert(ctx, gomock.Any()).Return(nil)
+		deviceRepo.EXPECT().ListByUserID(ctx, userID).Return([]entity.Device{origin, tablet}, nil)
+		pusher.EXPECT().SilentPush(ctx, tablet).Return(domain.ErrPushTokenInvalid)
+		deviceRepo.EXPECT().SetPushToken(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, d *entity.Device) error {
+			assert.Equal(t, tablet.ID, d.ID)
+			assert.Empty(t, d.PushToken)
+			return nil
+		})
+
+		_, err := svc.BatchSync(ctx, sync.SyncInput{
+			UserID: userID, DeviceID: "phone",
+			ClientNotes: []sync.ClientNote{{ClientID: "note-1", Title: "New", UpdatedAt: time.Now()}},
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("pushes nothing when the sync saved no notes", func(t *testing.T) {
+		svc, _, _, _ := setup(t)
+
+		_, err := svc.BatchSync(ctx, sync.SyncInput{UserID: userID, DeviceID: "phone"})
+
+		require.NoError(t, err)
+	})
+}
+
+func TestService_BatchSyncPhotos(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	noteRepo := mocks.NewMockNoteRepository(ctrl)
+	photoRepo := mocks.NewMockPhotoRepository(ctrl)
+	deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+	svc := sync.NewService(noteRepo, photoRepo, deviceRepo, nil, nil, nil, nil, nil, 0, nil, nil)
+
+	userID := uuid.New()
+	noteID := uuid.New()
+	device := &entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "device-123"}
+	stored := entity.Photo{ID: uuid.New(), NoteID: noteID, ClientID: "photo-uploaded"}
+	clientIDs := []string{"photo-uploaded", "photo-deleted", "photo-new", "photo-orphan"}
+
+	deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(device, nil)
+	noteRepo.EXPECT().GetModifiedAfter(ctx, userID, gomock.Any(), 1001, entity.SyncScope{}).Return(nil, nil)
+	photoRepo.EXPECT().GetByClientIDs(ctx, userID, clientIDs).Return([]entity.Photo{stored}, nil)
+	photoRepo.EXPECT().GetDeletedByClientIDs(ctx, userID, clientIDs).Return([]entity.PhotoTombstone{
+		{PhotoID: uuid.New(), NoteID: noteID, ClientID: "photo-deleted"},
+	}, nil)
+	noteRepo.EXPECT().GetByClientID(ctx, userID, "note-1").Return(&entity.Note{ID: noteID, UserID: userID}, nil)
+	noteRepo.EXPECT().GetByClientID(ctx, userID, "note-gone").Return(nil, domain.ErrNoteNotFound)
+	deviceRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+	result, err := svc.BatchSync(ctx, sync.SyncInput{
+		UserID:   userID,
+		DeviceID: "device-123",
+		ClientPhotos: []sync.ClientPhoto{
+			{ClientID: "photo-uploaded", NoteClientID: "note-1"},
+			{ClientID: "photo-deleted", NoteClientID: "note-1"},
+			{ClientID: "photo-new", NoteClientID: "note-1"},
+			{ClientID: "photo-orphan", NoteClientID: "note-gone"},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, result.Photos, 4)
+
+	assert.Equal(t, sync.PhotoUploaded, result.Photos[0].Status)
+	assert.Equal(t, stored.ID, result.Photos[0].Photo.ID)
+	assert.False(t, result.Photos[0].PendingUpload())
+
+	assert.Equal(t, sync.PhotoDeleted, result.Photos[1].Status)
+
+	assert.Equal(t, sync.PhotoPendingUpload, result.Photos[2].Status)
+	assert.Equal(t, noteID, result.Photos[2].NoteID)
+	assert.True(t, result.Photos[2].PendingUpload())
+
+	assert.Equal(t, sync.PhotoMissingNote, result.Photos[3].Status)
+	assert.Equal(t, uuid.Nil, result.Photos[3].NoteID)
+}
+
+func TestService_PhotoManifest(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("merges added and removed photos in order", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		svc := sync.NewService(nil, photoRepo, nil, nil, nil, nil, nil, nil, 0, nil, nil)
+
+		userID := uuid.New()
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		after := pagination.Cursor{UpdatedAt: start}
+		added := []entity.Photo{
+			{ID: uuid.New(), Size: 100, Checksum: "abc", CreatedAt: start.Add(time.Minute)},
+			{ID: uuid.New(), Size: 200, Checksum: "def", CreatedAt: start.Add(3 * time.Minute)},
+		}
+		removed := []entity.PhotoTombstone{
+			{PhotoID: uuid.New(), DeletedAt: start.Add(2 * time.Minute)},
+		}
+
+		photoRepo.EXPECT().GetCreatedAfter(ctx, userID, after, 11).Return(added, nil)
+		photoRepo.EXPECT().GetDeletedAfter(ctx, userID, after, 11).Return(removed, nil)
+
+		manifest, err := svc.PhotoManifest(ctx, sync.PhotoManifestInput{UserID: userID, Cursor: "2024-01-01T00:00:00Z", Limit: 10})
+
+		require.NoError(t, err)
+		assert.Len(t, manifest.Added, 2)
+		assert.Len(t, manifest.Removed, 1)
+		assert.False(t, manifest.HasMore)
+
+		next, err := pagination.DecodeCursor(manifest.NextCursor)
+		require.NoError(t, err)
+		assert.Equal(t, added[1].ID, next.ID)
+	})
+
+	t.Run("does not skip entries sharing a timestamp across pages", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		svc := sync.NewService(nil, photoRepo, nil, nil, nil, nil, nil, nil, 0, nil, nil)
+
+		userID := uuid.New()
+		at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		ids := []uuid.UUID{
+			uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+			uuid.MustParse("00000000-0000-0000-0000-000000000002"),
+			uuid.MustParse("00000000-0000-0000-0000-000000000003"),
+		}
+		removed := []entity.PhotoTombstone{
+			{PhotoID: ids[0], DeletedAt: at},
+			{PhotoID: ids[1], DeletedAt: at},
+			{PhotoID: ids[2], DeletedAt: at},
+		}
+
+		photoRepo.EXPECT().GetCreatedAfter(ctx, userID, pagination.Cursor{}, 3).Return(nil, nil)
+		photoRepo.EXPECT().GetDeletedAfter(ctx, userID, pagination.Cursor{}, 3).Return(removed, nil)
+
+		manifest, err := svc.PhotoManifest(ctx, sync.PhotoManifestInput{UserID: userID, Limit: 2})
+
+		require.NoError(t, err)
+		require.Len(t, manifest.Removed, 2)
+		assert.True(t, manifest.HasMore)
+
+		next, err := pagination.DecodeCursor(manifest.NextCursor)
+		require.NoError(t, err)
+		assert.Equal(t, pagination.Cursor{UpdatedAt: at, ID: ids[1]}, *next)
+
+		photoRepo.EXPECT().GetCreatedAfter(ctx, userID, *next, 3).Return(nil, nil)
+		photoRepo.EXPECT().GetDeletedAfter(ctx, userID, *next, 3).Return(removed[2:], nil)
+
+		manifest, err = svc.PhotoManifest(ctx, sync.PhotoManifestInput{UserID: userID, Cursor: manifest.NextCursor, Limit: 2})
+
+		require.NoError(t, err)
+		require.Len(t, manifest.Removed, 1)
+		assert.Equal(t, ids[2], manifest.Removed[0].PhotoID)
+		assert.False(t, manifest.HasMore)
+	})
+
+	t.Run("returns empty manifest for device excluding photos", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		svc := sync.NewService(nil, photoRepo, deviceRepo, nil, nil, nil, nil, nil, 0, nil, nil)
+
+		userID := uuid.New()
+		device := &entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "tablet", Scope: entity.SyncScope{ExcludePhotos: true}}
+
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "tablet").Return(device, nil)
+
+		manifest, err := svc.PhotoManifest(ctx, sync.PhotoManifestInput{UserID: userID, DeviceID: "tablet", Cursor: "2024-01-01T00:00:00Z"})
+
+		require.NoError(t, err)
+		assert.Empty(t, manifest.Added)
+		assert.Empty(t, manifest.Removed)
+		assert.Equal(t, "2024-01-01T00:00:00Z", manifest.NextCursor)
+	})
+
+	t.Run("keeps cursor when nothing changed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		svc := sync.NewService(nil, photoRepo, nil, nil, nil, nil, nil, nil, 0, nil, nil)
+
+		userID := uuid.New()
+		cursor := pagination.Cursor{UpdatedAt: time.Now().UTC(), ID: uuid.New()}
+		token := cursor.Encode()
+
+		photoRepo.EXPECT().GetCreatedAfter(ctx, userID, gomock.Any(), gomock.Any()).Return(nil, nil)
+		photoRepo.EXPECT().GetDeletedAfter(ctx, userID, gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		manifest, err := svc.PhotoManifest(ctx, sync.PhotoManifestInput{UserID: userID, Cursor: token})
+
+		require.NoError(t, err)
+		assert.Empty(t, manifest.Added)
+		assert.Empty(t, manifest.Removed)
+		assert.Equal(t, token, manifest.NextCursor)
+	})
+
+	t.Run("rejects malformed cursor", func(t *testing.T) {
+		svc := sync.NewService(nil, nil, nil, nil, nil, nil, nil, nil, 0, nil, nil)
+
+		manifest, err := svc.PhotoManifest(ctx, sync.PhotoManifestInput{UserID: uuid.New(), Cursor: "not a cursor"})
+
+		assert.Nil(t, manifest)
+		assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+	})
+}
+
+func TestService_ManualConflicts(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("holds the client version instead of applying it", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		conflictRepo := mocks.NewMockSyncConflictRepository(ctrl)
+		svc := sync.NewService(noteRepo, nil, deviceRepo, nil, nil, nil, nil, conflictRepo, 24*time.Hour, nil, nil)
+
+		userID := uuid.New()
+		device := &entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "tablet"}
+		serverNote := entity.Note{
+			ID:        uuid.New(),
+			UserID:    userID,
+			Title:     "Server Version",
+			ClientID:  "conflict-note",
+			UpdatedAt: time.Now().Add(-time.Hour),
+		}
+
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "tablet").Return(device, nil)
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, gomock.Any(), 1001, entity.SyncScope{}).Return([]entity.Note{serverNote}, nil)
+		conflictRepo.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, c *entity.SyncConflict) error {
+			assert.Equal(t, serverNote.ID, c.NoteID)
+			assert.Equal(t, "tablet", c.DeviceID)
+			assert.Equal(t, "Client Version", c.ClientVersion.Title)
+			assert.True(t, serverNote.UpdatedAt.Equal(c.ServerUpdatedAt))
+			assert.WithinDuration(t, time.Now().Add(24*time.Hour), c.ExpiresAt, time.Minute)
+			return nil
+		})
+		deviceRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+		result, err := svc.BatchSync(ctx, sync.SyncInput{
+			UserID:           userID,
+			DeviceID:         "tablet",
+			ConflictStrategy: sync.StrategyManual,
+			ClientNotes: []sync.ClientNote{
+				{ClientID: "conflict-note", Title: "Client Version", Content: "Edited offline", UpdatedAt: time.Now()},
+			},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, result.Conflicts, 1)
+		assert.Equal(t, sync.ResolutionPending, result.Conflicts[0].Resolution)
+		assert.NotEqual(t, uuid.Nil, result.Conflicts[0].ConflictID)
+	})
+
+	conflictFor := func(userID uuid.UUID, serverUpdatedAt time.Time) *entity.SyncConflict {
+		return &entity.SyncConflict{
+			ID:              uuid.New(),
+			UserID:          userID,
+			NoteID:          uuid.New(),
+			ClientID:        "conflict-note",
+			DeviceID:        "tablet",
+			ClientVersion:   entity.Note{Title: "Client Version", Content: "Edited offline", Tags: []string{"plot-7"}},
+			ServerUpdatedAt: serverUpdatedAt,
+			ExpiresAt:       time.Now().Add(time.Hour),
+		}
+	}
+
+	t.Run("replays the client side onto an unchanged note", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		conflictRepo := mocks.NewMockSyncConflictRepository(ctrl)
+		svc := sync.NewService(noteRepo, nil, nil, ruleRepo, nil, nil, nil, conflictRepo, time.Hour, nil, nil)
+
+		userID := uuid.New()
+		updatedAt := time.Now().Add(-time.Hour).UTC()
+		conflict := conflictFor(userID, updatedAt)
+		note := &entity.Note{ID: conflict.NoteID, UserID: userID, Title: "Server Version", ClientID: "conflict-note", UpdatedAt: updatedAt}
+
+		conflictRepo.EXPECT().GetByID(ctx, conflict.ID).Return(conflict, nil)
+		noteRepo.EXPECT().GetByID(ctx, conflict.NoteID).Return(note, nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil)
+		noteRepo.EXPECT().BatchUpsert(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, notes []entity.Note) error {
+			require.Len(t, notes, 1)
+			assert.Equal(t, "Client Version", notes[0].Title)
+			assert.Equal(t, []string{"plot-7"}, notes[0].Tags)
+			assert.Equal(t, "desktop", notes[0].LastModifiedByDevice)
+			assert.True(t, notes[0].UpdatedAt.After(updatedAt))
+			return nil
+		})
+		conflictRepo.EXPECT().Resolve(ctx, conflict).Return(nil)
+
+		got, err := svc.ReplayConflict(ctx, sync.ReplayInput{
+			UserID: userID, ConflictID: conflict.ID, Side: entity.ConflictSideClient, DeviceID: "desktop",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Client Version", got.Title)
+		assert.Equal(t, entity.ConflictSideClient, conflict.Resolution)
+		assert.NotNil(t, conflict.ResolvedAt)
+	})
+
+	t.Run("refuses the client side once the note changed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		conflictRepo := mocks.NewMockSyncConflictRepository(ctrl)
+		svc := sync.NewService(noteRepo, nil, nil, nil, nil, nil, nil, conflictRepo, time.Hour, nil, nil)
+
+		userID := uuid.New()
+		conflict := conflictFor(userID, time.Now().Add(-time.Hour))
+		note := &entity.Note{ID: conflict.NoteID, UserID: userID, UpdatedAt: time.Now()}
+
+		conflictRepo.EXPECT().GetByID(ctx, conflict.ID).Return(conflict, nil)
+		noteRepo.EXPECT().GetByID(ctx, conflict.NoteID).Return(note, nil)
+
+		_, err := svc.ReplayConflict(ctx, sync.ReplayInput{UserID: userID, ConflictID: conflict.ID, Side: entity.ConflictSideClient})
+
+		assert.ErrorIs(t, err, domain.ErrNoteChanged)
+		assert.True(t, conflict.IsPending())
+	})
+
+	t.Run("keeps the server side of a changed note", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		conflictRepo := mocks.NewMockSyncConflictRepository(ctrl)
+		svc := sync.NewService(noteRepo, nil, nil, nil, nil, nil, nil, conflictRepo, time.Hour, nil, nil)
+
+		userID := uuid.New()
+		conflict := conflictFor(userID, time.Now().Add(-time.Hour))
+		note := &entity.Note{ID: conflict.NoteID, UserID: userID, Title: "Newer", UpdatedAt: time.Now()}
+
+		conflictRepo.EXPECT().GetByID(ctx, conflict.ID).Return(conflict, nil)
+		noteRepo.EXPECT().GetByID(ctx, conflict.NoteID).Return(note, nil)
+		conflictRepo.EXPECT().Resolve(ctx, conflict).Return(nil)
+
+		got, err := svc.ReplayConflict(ctx, sync.ReplayInput{UserID: userID, ConflictID: conflict.ID, Side: entity.ConflictSideServer})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Newer", got.Title)
+		assert.Equal(t, entity.ConflictSideServer, conflict.Resolution)
+	})
+
+	t.Run("hides other users' and expired conflicts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		conflictRepo := mocks.NewMockSyncConflictRepository(ctrl)
+		svc := sync.NewService(nil, nil, nil, nil, nil, nil, nil, conflictRepo, time.Hour, nil, nil)
+
+		userID := uuid.New()
+		other := conflictFor(uuid.New(), time.Now())
+		expired := conflictFor(userID, time.Now())
+		expired.ExpiresAt = time.Now().Add(-time.Minute)
+
+		conflictRepo.EXPECT().GetByID(ctx, other.ID).Return(other, nil)
+		conflictRepo.EXPECT().GetByID(ctx, expired.ID).Return(expired, nil)
+
+		_, err := svc.ReplayConflict(ctx, sync.ReplayInput{UserID: userID, ConflictID: other.ID, Side: entity.ConflictSideServer})
+		assert.ErrorIs(t, err, domain.ErrSyncConflictNotFound)
+
+		_, err = svc.ReplayConflict(ctx, sync.ReplayInput{UserID: userID, ConflictID: expired.ID, Side: entity.ConflictSideServer})
+		assert.ErrorIs(t, err, domain.ErrSyncConflictNotFound)
+	})
+}
+
+func TestService_UpdateScope(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("stores scope on device and applies it to sync", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		svc := sync.NewService(noteRepo, nil, deviceRepo, nil, nil, nil, nil, nil, 0, nil, nil)
+
+		userID := uuid.New()
+		notesSince := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+		scope := entity.SyncScope{NotesSince: &notesSince, ExcludePhotos: true}
+		device := &entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "tablet"}
+
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "tablet").Return(device, nil).Times(2)
+		deviceRepo.EXPECT().Update(ctx, device).Return(nil).Times(2)
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, gomock.Any(), 1001, scope).Return([]entity.Note{}, nil)
+
+		updated, err := svc.UpdateScope(ctx, sync.ScopeInput{UserID: userID, DeviceID: "tablet", Scope: scope})
+		require.NoError(t, err)
+		assert.Equal(t, scope, updated.Scope)
+
+		_, err = svc.BatchSync(ctx, sync.SyncInput{UserID: userID, DeviceID: "tablet"})
+		require.NoError(t, err)
+	})
+}
+
+func TestService_SetActiveAuthor(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("checks in and out of a shared device", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		svc := sync.NewService(nil, nil, deviceRepo, nil, nil, nil, nil, nil, 0, nil, nil)
+
+		userID := uuid.New()
+		device := &entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "kit-3"}
+
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "kit-3").Return(device, nil).Times(2)
+		deviceRepo.EXPECT().Update(ctx, device).Return(nil).Times(2)
+
+		updated, err := svc.SetActiveAuthor(ctx, sync.AuthorInput{UserID: userID, DeviceID: "kit-3", Name: " Ana Silva "})
+		require.NoError(t, err)
+		assert.Equal(t, "Ana Silva", updated.ActiveAuthor)
+		assert.NotNil(t, updated.ActiveAuthorSince)
+
+		updated, err = svc.ClearActiveAuthor(ctx, userID, "kit-3")
+		require.NoError(t, err)
+		assert.Empty(t, updated.ActiveAuthor)
+		assert.Nil(t, updated.ActiveAuthorSince)
+	})
+
+	t.Run("rejects a blank name", func(t *testing.T) {
+		svc := sync.NewService(nil, nil, nil, nil, nil, nil, nil, nil, 0, nil, nil)
+
+		_, err := svc.SetActiveAuthor(ctx, sync.AuthorInput{UserID: uuid.New(), DeviceID: "kit-3", Name: "  "})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidDeviceAuthor)
+	})
+}
+
+func TestService_SetPushToken(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("registers and clears the token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		svc := sync.NewService(nil, nil, deviceRepo, nil, nil, nil, nil, nil, 0, nil, nil)
+		device := &entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "phone", Platform: entity.PlatformIOS}
+
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "phone").Return(device, nil).Times(2)
+		gomock.InOrder(
+			deviceRepo.EXPECT().SetPushToken(ctx, device).DoAndReturn(func(_ context.Context, d *entity.Device) error {
+				assert.Equal(t, "apns-token", d.PushToken)
+				assert.NotNil(t, d.PushTokenUpdatedAt)
+				return nil
+			}),
+			deviceRepo.EXPECT().SetPushToken(ctx, device).DoAndReturn(func(_ context.Context, d *entity.Device) error {
+				assert.Empty(t, d.PushToken)
+				assert.Nil(t, d.PushTokenUpdatedAt)
+				return nil
+			}),
+		)
+
+		require.NoError(t, svc.SetPushToken(ctx, sync.PushTokenInput{UserID: userID, DeviceID: "phone", Token: " apns-token "}))
+		require.NoError(t, svc.ClearPushToken(ctx, userID, "phone"))
+	})
+
+	t.Run("rejects platforms without push", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		svc := sync.NewService(nil, nil, deviceRepo, nil, nil, nil, nil, nil, 0, nil, nil)
+
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "laptop").
+			Return(&entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "laptop", Platform: entity.PlatformWeb}, nil)
+
+		err := svc.SetPushToken(ctx, sync.PushTokenInput{UserID: userID, DeviceID: "laptop", Token: "token"})
+
+		assert.ErrorIs(t, err, domain.ErrPushNotSupported)
+	})
+}
+
+func TestService_Changes(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("pages through changes with a stable cursor", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := sync.NewService(noteRepo, nil, nil, nil, nil, nil, nil, nil, 0, nil, nil)
+
+		userID := uuid.New()
+		at := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+		notes := []entity.Note{
+			{ID: uuid.New(), UserID: userID, UpdatedAt: at},
+			{ID: uuid.New(), UserID: userID, UpdatedAt: at},
+			{ID: uuid.New(), UserID: userID, UpdatedAt: at.Add(time.Second)},
+		}
+
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, pagination.Cursor{}, 3, entity.SyncScope{}).Return(notes, nil)
+
+		changes, err := svc.Changes(ctx, sync.ChangesInput{UserID: userID, Limit: 2})
+
+		require.NoError(t, err)
+		assert.Len(t, changes.Notes, 2)
+		assert.True(t, changes.HasMore)
+
+		next, err := pagination.DecodeCursor(changes.NextCursor)
+		require.NoError(t, err)
+		assert.Equal(t, notes[1].ID, next.ID)
+		assert.True(t, at.Equal(next.UpdatedAt))
+
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, *next, 3, entity.SyncScope{}).Return(notes[2:], nil)
+
+		changes, err = svc.Changes(ctx, sync.ChangesInput{UserID: userID, Cursor: changes.NextCursor, Limit: 2})
+
+		require.NoError(t, err)
+		assert.Len(t, changes.Notes, 1)
+		assert.False(t, changes.HasMore)
+	})
+
+	t.Run("accepts a timestamp cursor and keeps it when nothing changed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		svc := sync.NewService(noteRepo, nil, deviceRepo, nil, nil, nil, nil, nil, 0, nil, nil)
+
+		userID := uuid.New()
+		cursor := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+		scope := entity.SyncScope{ExcludePhotos: true}
+		device := &entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "tablet", Scope: scope}
+
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "tablet").Return(device, nil)
+		noteRepo.EXPECT().GetModifiedAfter(ctx, userID, pagination.Cursor{UpdatedAt: cursor}, 501, scope).Return(nil, nil)
+
+		changes, err := svc.Changes(ctx, sync.ChangesInput{UserID: userID, DeviceID: "tablet", Cursor: "2024-01-15T10:00:00Z"})
+
+		require.NoError(t, err)
+		assert.Empty(t, changes.Notes)
+		assert.Equal(t, "2024-01-15T10:00:00Z", changes.NextCursor)
+		assert.False(t, changes.HasMore)
+	})
+
+	t.Run("rejects malformed cursor", func(t *testing.T) {
+		svc := sync.NewService(nil, nil, nil, nil, nil, nil, nil, nil, 0, nil, nil)
+
+		changes, err := svc.Changes(ctx, sync.ChangesInput{UserID: uuid.New(), Cursor: "not a cursor"})
+
+		assert.Nil(t, changes)
+		assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+	})
+}
+
+func TestService_Capabilities(t *testing.T) {
+	svc := sync.NewService(nil, nil, nil, nil, nil, nil, nil, nil, 0, nil, nil)
+
+	caps := svc.Capabilities()
+
+	assert.Equal(t, sync.ProtocolVersion, caps.ProtocolVersion)
+	assert.Equal(t, 1000, caps.MaxBatchSize)
+	assert.Equal(t, []string{sync.StrategyLastWriteWins, sync.StrategyKeepBoth}, caps.ConflictStrategies)
+	assert.Contains(t, caps.ConflictStrategies, caps.DefaultConflictStrategy)
+	assert.True(t, caps.PhotoSync)
+	assert.True(t, caps.FieldMerge)
+	assert.Empty(t, caps.Compression)
 }
@@ -0,0 +1,200 @@
+package team
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/authz"
+)
+
+type Service struct {
+	teamRepo   repository.TeamRepository
+	userRepo   repository.UserRepository
+	authorizer *authz.Authorizer
+}
+
+func NewService(
+	teamRepo repository.TeamRepository,
+	userRepo repository.UserRepository,
+	authorizer *authz.Authorizer,
+) *Service {
+	return &Service{
+		teamRepo:   teamRepo,
+		userRepo:   userRepo,
+		authorizer: authorizer,
+	}
+}
+
+// Create creates a team owned by the user.
+func (s *Service) Create(ctx context.Context, userID uuid.UUID, name string) (*entity.UserTeam, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > entity.MaxTeamNameLength {
+		return nil, domain.ErrInvalidTeamName
+	}
+
+	team := entity.NewTeam(name, userID)
+	owner := entity.TeamMember{
+		TeamID:     team.ID,
+		UserID:     userID,
+		Role:       entity.TeamRoleOwner,
+		CreatedAt:  team.CreatedAt,
+		AcceptedAt: &team.CreatedAt,
+	}
+	if err := s.teamRepo.Create(ctx, team, &owner); err != nil {
+		return nil, fmt.Errorf("creating team: %w", err)
+	}
+
+	return &entity.UserTeam{Team: *team, Membership: owner}, nil
+}
+
+// List returns the user's teams, including those they are invited to.
+func (s *Service) List(ctx context.Context, userID uuid.UUID) ([]entity.UserTeam, error) {
+	teams, err := s.teamRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("listing teams: %w", err)
+	}
+	return teams, nil
+}
+
+// Members lists the team's members and pending invitations; only members
+// can see them.
+func (s *Service) Members(ctx context.Context, userID, teamID uuid.UUID) ([]entity.TeamMember, error) {
+	if _, err := s.role(ctx, userID, teamID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.teamRepo.ListMembers(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("listing team members: %w", err)
+	}
+	return members, nil
+}
+
+type InviteInput struct {
+	UserID uuid.UUID
+	TeamID uuid.UUID
+	Email  string
+	Role   string
+}
+
+// Invite invites the user with the email to the team. Only the team's owner
+// and admins can invite; the invitation grants nothing until accepted.
+func (s *Service) Invite(ctx context.Context, input InviteInput) (*entity.TeamMember, error) {
+	if !entity.IsTeamInviteRole(input.Role) {
+		return nil, domain.ErrInvalidTeamRole
+	}
+	if err := s.requireManager(ctx, input.UserID, input.TeamID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, input.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	member := entity.NewTeamInvite(input.TeamID, user.ID, input.Role, input.UserID)
+	if err := s.teamRepo.AddMember(ctx, member); err != nil {
+		return nil, err
+	}
+	member.Email = user.Email
+	return member, nil
+}
+
+// Accept accepts the user's invitation to the team.
+func (s *Service) Accept(ctx context.Context, userID, teamID uuid.UUID) error {
+	return s.teamRepo.Accept(ctx, teamID, userID, time.Now().UTC())
+}
+
+type RoleInput struct {
+	UserID   uuid.UUID
+	TeamID   uuid.UUID
+	MemberID uuid.UUID
+	Role     string
+}
+
+// UpdateRole changes the role of a member or invitee. Only the team's owner
+// and admins can change roles, and never the owner's.
+func (s *Service) UpdateRole(ctx context.Context, input RoleInput) (*entity.TeamMember, error) {
+	if !entity.IsTeamInviteRole(input.Role) {
+		return nil, domain.ErrInvalidTeamRole
+	}
+
+	actorRole, err := s.role(ctx, input.UserID, input.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	member, err := s.teamRepo.GetMember(ctx, input.TeamID, input.MemberID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.CanChangeTeamRole(actorRole, member.Role, input.Role) {
+		return nil, domain.ErrForbidden
+	}
+
+	if err := s.teamRepo.UpdateRole(ctx, input.TeamID, input.MemberID, input.Role); err != nil {
+		return nil, err
+	}
+	member.Role = input.Role
+	return member, nil
+}
+
+// RemoveMember removes a member or cancels an invitation. Users can remove
+// themselves, to leave or decline, except the owner; otherwise only the
+// owner and admins can remove others, and never the owner.
+func (s *Service) RemoveMember(ctx context.Context, userID, teamID, memberID uuid.UUID) error {
+	member, err := s.teamRepo.GetMember(ctx, teamID, memberID)
+	if err != nil {
+		return err
+	}
+
+	if memberID != userID {
+		if err := s.requireManager(ctx, userID, teamID); err != nil {
+			return err
+		}
+	}
+	if member.Role == entity.TeamRoleOwner {
+		if memberID == userID {
+			return domain.ErrTeamOwnerCannotLeave
+		}
+		return domain.ErrForbidden
+	}
+
+	return s.teamRepo.RemoveMember(ctx, teamID, memberID)
+}
+
+// role returns the user's role in the team. It returns domain.ErrTeamNotFound
+// when the team does not exist and domain.ErrForbidden when the user is not
+// a member.
+func (s *Service) role(ctx context.Context, userID, teamID uuid.UUID) (string, error) {
+	if _, err := s.teamRepo.GetByID(ctx, teamID); err != nil {
+		return "", err
+	}
+
+	role, err := s.authorizer.TeamRole(ctx, userID, teamID)
+	if err != nil {
+		return "", err
+	}
+	if role == "" {
+		return "", domain.ErrForbidden
+	}
+	return role, nil
+}
+
+func (s *Service) requireManager(ctx context.Context, userID, teamID uuid.UUID) error {
+	role, err := s.role(ctx, userID, teamID)
+	if err != nil {
+		return err
+	}
+	if !entity.ManagesTeam(role) {
+		return domain.ErrForbidden
+	}
+	return nil
+}
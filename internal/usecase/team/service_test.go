@@ -0,0 +1,242 @@
+package team_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/authz"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/team"
+)
+
+func newService(ctrl *gomock.Controller) (*team.Service, *mocks.MockTeamRepository, *mocks.MockUserRepository) {
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	return team.NewService(teamRepo, userRepo, authz.NewAuthorizer(nil, nil, teamRepo)), teamRepo, userRepo
+}
+
+func TestService_Create(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("makes the user the owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		svc, teamRepo, _ := newService(ctrl)
+		userID := uuid.New()
+
+		teamRepo.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, tm *entity.Team, owner *entity.TeamMember) error {
+				assert.Equal(t, "River survey", tm.Name)
+				assert.Equal(t, userID, tm.CreatedBy)
+				assert.Equal(t, tm.ID, owner.TeamID)
+				assert.Equal(t, userID, owner.UserID)
+				assert.Equal(t, entity.TeamRoleOwner, owner.Role)
+				assert.False(t, owner.Pending())
+				return nil
+			})
+
+		created, err := svc.Create(ctx, userID, "  River survey ")
+
+		require.NoError(t, err)
+		assert.Equal(t, entity.TeamRoleOwner, created.Membership.Role)
+	})
+
+	t.Run("rejects a blank name", func(t *testing.T) {
+		svc, _, _ := newService(gomock.NewController(t))
+
+		_, err := svc.Create(ctx, uuid.New(), "   ")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidTeamName)
+	})
+}
+
+func TestService_Invite(t *testing.T) {
+	ctx := context.Background()
+	teamID := uuid.New()
+	invitee := &entity.User{ID: uuid.New(), Email: "colleague@example.com"}
+
+	t.Run("admins invite users by email", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		svc, teamRepo, userRepo := newService(ctrl)
+		adminID := uuid.New()
+
+		teamRepo.EXPECT().GetByID(ctx, teamID).Return(&entity.Team{ID: teamID}, nil)
+		teamRepo.EXPECT().GetRole(ctx, teamID, adminID).Return(entity.TeamRoleAdmin, nil)
+		userRepo.EXPECT().GetByEmail(ctx, invitee.Email).Return(invitee, nil)
+		teamRepo.EXPECT().AddMember(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, m *entity.TeamMember) error {
+			assert.Equal(t, invitee.ID, m.UserID)
+			assert.Equal(t, entity.TeamRoleEditor, m.Role)
+			assert.Equal(t, adminID, *m.InvitedBy)
+			assert.True(t, m.Pending())
+			return nil
+		})
+
+		member, err := svc.Invite(ctx, team.InviteInput{UserID: adminID, TeamID: teamID, Email: invitee.Email, Role: entity.TeamRoleEditor})
+
+		require.NoError(t, err)
+		assert.Equal(t, invitee.Email, member.Email)
+	})
+
+	t.Run("members cannot invite", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		svc, teamRepo, _ := newService(ctrl)
+		memberID := uuid.New()
+
+		teamRepo.EXPECT().GetByID(ctx, teamID).Return(&entity.Team{ID: teamID}, nil)
+		teamRepo.EXPECT().GetRole(ctx, teamID, memberID).Return(entity.TeamRoleEditor, nil)
+
+		_, err := svc.Invite(ctx, team.InviteInput{UserID: memberID, TeamID: teamID, Email: invitee.Email, Role: entity.TeamRoleEditor})
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("nobody is invited as owner", func(t *testing.T) {
+		svc, _, _ := newService(gomock.NewController(t))
+
+		_, err := svc.Invite(ctx, team.InviteInput{UserID: uuid.New(), TeamID: teamID, Email: invitee.Email, Role: entity.TeamRoleOwner})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidTeamRole)
+	})
+}
+
+func TestService_RemoveMember(t *testing.T) {
+	ctx := context.Background()
+	teamID := uuid.New()
+
+	t.Run("members leave on their own", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		svc, teamRepo, _ := newService(ctrl)
+		userID := uuid.New()
+
+		teamRepo.EXPECT().GetMember(ctx, teamID, userID).Return(&entity.TeamMember{Role: entity.TeamRoleEditor}, nil)
+		teamRepo.EXPECT().RemoveMember(ctx, teamID, userID).Return(nil)
+
+		assert.NoError(t, svc.RemoveMember(ctx, userID, teamID, userID))
+	})
+
+	t.Run("the owner cannot leave", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		svc, teamRepo, _ := newService(ctrl)
+		ownerID := uuid.New()
+
+		teamRepo.EXPECT().GetMember(ctx, teamID, ownerID).Return(&entity.TeamMember{Role: entity.TeamRoleOwner}, nil)
+
+		assert.ErrorIs(t, svc.RemoveMember(ctx, ownerID, teamID, ownerID), domain.ErrTeamOwnerCannotLeave)
+	})
+
+	t.Run("admins cannot remove the owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		svc, teamRepo, _ := newService(ctrl)
+		adminID, ownerID := uuid.New(), uuid.New()
+
+		teamRepo.EXPECT().GetMember(ctx, teamID, ownerID).Return(&entity.TeamMember{Role: entity.TeamRoleOwner}, nil)
+		teamRepo.EXPECT().GetByID(ctx, teamID).Return(&entity.Team{ID: teamID}, nil)
+		teamRepo.EXPECT().GetRole(ctx, teamID, adminID).Return(entity.TeamRoleAdmin, nil)
+
+		assert.ErrorIs(t, svc.RemoveMember(ctx, adminID, teamID, ownerID), domain.ErrForbidden)
+	})
+
+	t.Run("members cannot remove others", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		svc, teamRepo, _ := newService(ctrl)
+		userID, otherID := uuid.New(), uuid.New()
+
+		teamRepo.EXPECT().GetMember(ctx, teamID, otherID).Return(&entity.TeamMember{Role: entity.TeamRoleEditor}, nil)
+		teamRepo.EXPECT().GetByID(ctx, teamID).Return(&entity.Team{ID: teamID}, nil)
+		teamRepo.EXPECT().GetRole(ctx, teamID, userID).Return(entity.TeamRoleEditor, nil)
+
+		assert.ErrorIs(t, svc.RemoveMember(ctx, userID, teamID, otherID), domain.ErrForbidden)
+	})
+}
+
+func TestService_UpdateRole(t *testing.T) {
+	ctx := context.Background()
+	teamID := uuid.New()
+
+	t.Run("admins make editors viewers", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		svc, teamRepo, _ := newService(ctrl)
+		adminID, editorID := uuid.New(), uuid.New()
+
+		teamRepo.EXPECT().GetByID(ctx, teamID).Return(&entity.Team{ID: teamID}, nil)
+		teamRepo.EXPECT().GetRole(ctx, teamID, adminID).Return(entity.TeamRoleAdmin, nil)
+		teamRepo.EXPECT().GetMember(ctx, teamID, editorID).Return(&entity.TeamMember{UserID: editorID, Role: entity.TeamRoleEditor}, nil)
+		teamRepo.EXPECT().UpdateRole(ctx, teamID, editorID, entity.TeamRoleViewer).Return(nil)
+
+		member, err := svc.UpdateRole(ctx, team.RoleInput{UserID: adminID, TeamID: teamID, MemberID: editorID, Role: entity.TeamRoleViewer})
+
+		require.NoError(t, err)
+		assert.Equal(t, entity.TeamRoleViewer, member.Role)
+	})
+
+	t.Run("nobody changes the owner's role", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		svc, teamRepo, _ := newService(ctrl)
+		adminID, ownerID := uuid.New(), uuid.New()
+
+		teamRepo.EXPECT().GetByID(ctx, teamID).Return(&entity.Team{ID: teamID}, nil)
+		teamRepo.EXPECT().GetRole(ctx, teamID, adminID).Return(entity.TeamRoleAdmin, nil)
+		teamRepo.EXPECT().GetMember(ctx, teamID, ownerID).Return(&entity.TeamMember{Role: entity.TeamRoleOwner}, nil)
+
+		_, err := svc.UpdateRole(ctx, team.RoleInput{UserID: adminID, TeamID: teamID, MemberID: ownerID, Role: entity.TeamRoleViewer})
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("editors cannot change roles", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		svc, teamRepo, _ := newService(ctrl)
+		editorID, viewerID := uuid.New(), uuid.New()
+
+		teamRepo.EXPECT().GetByID(ctx, teamID).Return(&entity.Team{ID: teamID}, nil)
+		teamRepo.EXPECT().GetRole(ctx, teamID, editorID).Return(entity.TeamRoleEditor, nil)
+		teamRepo.EXPECT().GetMember(ctx, teamID, viewerID).Return(&entity.TeamMember{Role: entity.TeamRoleViewer}, nil)
+
+		_, err := svc.UpdateRole(ctx, team.RoleInput{UserID: editorID, TeamID: teamID, MemberID: viewerID, Role: entity.TeamRoleEditor})
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("nobody becomes owner", func(t *testing.T) {
+		svc, _, _ := newService(gomock.NewController(t))
+
+		_, err := svc.UpdateRole(ctx, team.RoleInput{UserID: uuid.New(), TeamID: teamID, MemberID: uuid.New(), Role: entity.TeamRoleOwner})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidTeamRole)
+	})
+}
+
+func TestService_Members(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("hidden from pending invitees and strangers", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		svc, teamRepo, _ := newService(ctrl)
+		teamID, userID := uuid.New(), uuid.New()
+
+		teamRepo.EXPECT().GetByID(ctx, teamID).Return(&entity.Team{ID: teamID}, nil)
+		teamRepo.EXPECT().GetRole(ctx, teamID, userID).Return("", nil)
+
+		_, err := svc.Members(ctx, userID, teamID)
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("reports a missing team", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		svc, teamRepo, _ := newService(ctrl)
+		teamID := uuid.New()
+
+		teamRepo.EXPECT().GetByID(ctx, teamID).Return(nil, domain.ErrTeamNotFound)
+
+		_, err := svc.Members(ctx, uuid.New(), teamID)
+
+		assert.ErrorIs(t, err, domain.ErrTeamNotFound)
+	})
+}
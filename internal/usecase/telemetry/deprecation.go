@@ -0,0 +1,161 @@
+package telemetry
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+type deprecationKey struct {
+	day        time.Time
+	name       string
+	platform   string
+	appVersion string
+}
+
+// DeprecationService counts the requests that use a deprecated endpoint or
+// field per app version, in memory, and persists the counts on Flush, like
+// Service does for all requests.
+type DeprecationService struct {
+	usageRepo    repository.DeprecationUsageRepository
+	deprecations []entity.Deprecation
+	retention    time.Duration
+
+	mu      sync.Mutex
+	pending map[deprecationKey]int64
+}
+
+// NewDeprecationService reports on deprecations, and keeps the daily counts
+// for retention; zero keeps them forever.
+func NewDeprecationService(usageRepo repository.DeprecationUsageRepository, deprecations []entity.Deprecation, retention time.Duration) *DeprecationService {
+	return &DeprecationService{
+		usageRepo:    usageRepo,
+		deprecations: deprecations,
+		retention:    retention,
+		pending:      make(map[deprecationKey]int64),
+	}
+}
+
+// RecordDeprecated counts a request from client that used the deprecation
+// named name.
+func (s *DeprecationService) RecordDeprecated(name string, client entity.ClientInfo) {
+	key := deprecationKey{
+		day:        entity.UsageDay(time.Now()),
+		name:       name,
+		platform:   client.Platform,
+		appVersion: client.AppVersion,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Names come from the registry, so only the client versions can grow
+	// the map; the same cap as the client counts bounds it.
+	if _, ok := s.pending[key]; !ok && len(s.pending) >= maxClientsPerDay {
+		return
+	}
+	s.pending[key]++
+}
+
+// Flush writes the accumulated counts. On a write failure they are kept for
+// the next flush.
+func (s *DeprecationService) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	batch := s.pending
+	s.pending = make(map[deprecationKey]int64)
+	s.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	usage := make([]entity.DeprecationUsage, 0, len(batch))
+	for key, requests := range batch {
+		usage = append(usage, entity.DeprecationUsage{
+			Day:        key.day,
+			Name:       key.name,
+			Platform:   key.platform,
+			AppVersion: key.appVersion,
+			Requests:   requests,
+		})
+	}
+
+	if err := s.usageRepo.Increment(ctx, usage); err != nil {
+		s.mu.Lock()
+		for key, requests := range batch {
+			s.pending[key] += requests
+		}
+		s.mu.Unlock()
+		return fmt.Errorf("persisting deprecation usage: %w", err)
+	}
+	return nil
+}
+
+// Prune deletes the daily counts older than the retention.
+func (s *DeprecationService) Prune(ctx context.Context, now time.Time) (int64, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+
+	deleted, err := s.usageRepo.DeleteBefore(ctx, now.Add(-s.retention))
+	if err != nil {
+		return 0, fmt.Errorf("pruning deprecation usage: %w", err)
+	}
+	return deleted, nil
+}
+
+// Report returns how much each registered deprecation was used over the last
+// days days, today included, with the app versions that used it, most used
+// first. Deprecations nothing used are listed too: they are ready to remove.
+func (s *DeprecationService) Report(ctx context.Context, days int) ([]entity.DeprecationReport, error) {
+	to := entity.UsageDay(time.Now())
+	from := to.AddDate(0, 0, -(days - 1))
+
+	usage, err := s.usageRepo.List(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("listing deprecation usage: %w", err)
+	}
+
+	type clientKey struct{ platform, appVersion string }
+	clients := make(map[string]map[clientKey]*entity.DeprecatedClient)
+	for _, u := range usage {
+		byClient, ok := clients[u.Name]
+		if !ok {
+			byClient = make(map[clientKey]*entity.DeprecatedClient)
+			clients[u.Name] = byClient
+		}
+		key := clientKey{u.Platform, u.AppVersion}
+		client, ok := byClient[key]
+		if !ok {
+			client = &entity.DeprecatedClient{Platform: u.Platform, AppVersion: u.AppVersion}
+			byClient[key] = client
+		}
+		client.Requests += u.Requests
+		client.LastSeen = maxTime(client.LastSeen, u.Day)
+	}
+
+	reports := make([]entity.DeprecationReport, 0, len(s.deprecations))
+	for _, d := range s.deprecations {
+		report := entity.DeprecationReport{Deprecation: d, Clients: []entity.DeprecatedClient{}}
+		for _, client := range clients[d.Name()] {
+			report.Requests += client.Requests
+			report.LastSeen = maxTime(report.LastSeen, client.LastSeen)
+			report.Clients = append(report.Clients, *client)
+		}
+		slices.SortFunc(report.Clients, func(a, b entity.DeprecatedClient) int {
+			return cmp.Or(
+				cmp.Compare(b.Requests, a.Requests),
+				cmp.Compare(a.Platform, b.Platform),
+				cmp.Compare(a.AppVersion, b.AppVersion),
+			)
+		})
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
@@ -0,0 +1,119 @@
+package telemetry_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/telemetry"
+)
+
+var (
+	legacyEndpoint = entity.Deprecation{Method: http.MethodGet, Route: "/api/v1/notes/legacy", Since: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)}
+	syncTags       = entity.Deprecation{Method: http.MethodPost, Route: "/api/v1/sync", Field: "notes[].tags", Since: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)}
+)
+
+func TestDeprecationService_Flush(t *testing.T) {
+	ios := entity.ClientInfo{Platform: entity.PlatformIOS, AppVersion: "2.3.1", OS: "iOS 17"}
+	android := entity.ClientInfo{Platform: entity.PlatformAndroid, AppVersion: "2.3.1", OS: "Android 14"}
+
+	t.Run("counts uses per deprecation and app version", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		usageRepo := mocks.NewMockDeprecationUsageRepository(ctrl)
+		svc := telemetry.NewDeprecationService(usageRepo, []entity.Deprecation{syncTags}, 0)
+
+		ctx := context.Background()
+		svc.RecordDeprecated(syncTags.Name(), ios)
+		svc.RecordDeprecated(syncTags.Name(), ios)
+		svc.RecordDeprecated(syncTags.Name(), android)
+
+		usageRepo.EXPECT().Increment(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, u []entity.DeprecationUsage) error {
+				require.Len(t, u, 2)
+				assert.ElementsMatch(t, []entity.DeprecationUsage{
+					{Day: entity.UsageDay(time.Now()), Name: "POST /api/v1/sync notes[].tags", Platform: entity.PlatformIOS, AppVersion: "2.3.1", Requests: 2},
+					{Day: entity.UsageDay(time.Now()), Name: "POST /api/v1/sync notes[].tags", Platform: entity.PlatformAndroid, AppVersion: "2.3.1", Requests: 1},
+				}, u)
+				return nil
+			})
+		require.NoError(t, svc.Flush(ctx))
+
+		// Nothing left to write.
+		require.NoError(t, svc.Flush(ctx))
+	})
+
+	t.Run("keeps counts for the next flush when the write fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		usageRepo := mocks.NewMockDeprecationUsageRepository(ctrl)
+		svc := telemetry.NewDeprecationService(usageRepo, []entity.Deprecation{syncTags}, 0)
+
+		ctx := context.Background()
+		svc.RecordDeprecated(syncTags.Name(), ios)
+
+		usageRepo.EXPECT().Increment(ctx, gomock.Any()).Return(errors.New("connection refused"))
+		require.Error(t, svc.Flush(ctx))
+
+		svc.RecordDeprecated(syncTags.Name(), ios)
+		usageRepo.EXPECT().Increment(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, u []entity.DeprecationUsage) error {
+				require.Len(t, u, 1)
+				assert.Equal(t, int64(2), u[0].Requests)
+				return nil
+			})
+		require.NoError(t, svc.Flush(ctx))
+	})
+}
+
+func TestDeprecationService_Report(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	usageRepo := mocks.NewMockDeprecationUsageRepository(ctrl)
+	svc := telemetry.NewDeprecationService(usageRepo, []entity.Deprecation{syncTags, legacyEndpoint}, 0)
+
+	ctx := context.Background()
+	today := entity.UsageDay(time.Now())
+	yesterday := today.AddDate(0, 0, -1)
+	usage := func(day time.Time, name, platform, version string, requests int64) entity.DeprecationUsage {
+		return entity.DeprecationUsage{Day: day, Name: name, Platform: platform, AppVersion: version, Requests: requests}
+	}
+
+	usageRepo.EXPECT().List(ctx, today.AddDate(0, 0, -29), today).Return([]entity.DeprecationUsage{
+		usage(yesterday, syncTags.Name(), entity.PlatformIOS, "2.2.0", 30),
+		usage(yesterday, syncTags.Name(), entity.PlatformAndroid, "2.3.1", 10),
+		usage(today, syncTags.Name(), entity.PlatformAndroid, "2.3.1", 40),
+		// Usage of a deprecation since removed from the registry.
+		usage(today, "GET /api/v1/removed", entity.PlatformIOS, "2.0.0", 5),
+	}, nil)
+
+	reports, err := svc.Report(ctx, 30)
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+
+	tags := reports[0]
+	assert.Equal(t, syncTags, tags.Deprecation)
+	assert.Equal(t, int64(80), tags.Requests)
+	assert.Equal(t, today, tags.LastSeen)
+	assert.Equal(t, []entity.DeprecatedClient{
+		{Platform: entity.PlatformAndroid, AppVersion: "2.3.1", Requests: 50, LastSeen: today},
+		{Platform: entity.PlatformIOS, AppVersion: "2.2.0", Requests: 30, LastSeen: yesterday},
+	}, tags.Clients)
+
+	unused := reports[1]
+	assert.Equal(t, legacyEndpoint, unused.Deprecation)
+	assert.Zero(t, unused.Requests)
+	assert.True(t, unused.LastSeen.IsZero())
+	assert.Empty(t, unused.Clients)
+}
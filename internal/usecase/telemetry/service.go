@@ -0,0 +1,226 @@
+package telemetry
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+// Headers are client-supplied, so the number of clients and devices tracked
+// per day is capped. Requests beyond the caps are dropped from the counts.
+const (
+	maxClientsPerDay    = 1000
+	maxDevicesPerClient = 100_000
+)
+
+type clientKey struct {
+	day time.Time
+	entity.ClientInfo
+}
+
+type counters struct {
+	requests int64
+	devices  int64
+}
+
+// Service counts API requests per client version in memory and persists
+// the counts on Flush, so request handling never waits on the database.
+type Service struct {
+	usageRepo repository.ClientUsageRepository
+	retention time.Duration
+
+	mu      sync.Mutex
+	pending map[clientKey]counters
+	// seen holds hashes of the devices already counted per client today,
+	// so a device counts once per day on each instance. With several
+	// instances a device can be counted once on each.
+	seen map[clientKey]map[uint64]struct{}
+}
+
+// NewService keeps the daily counts for retention; zero keeps them forever.
+func NewService(usageRepo repository.ClientUsageRepository, retention time.Duration) *Service {
+	return &Service{
+		usageRepo: usageRepo,
+		retention: retention,
+		pending:   make(map[clientKey]counters),
+		seen:      make(map[clientKey]map[uint64]struct{}),
+	}
+}
+
+// Record counts a request from client, made by deviceID when the client
+// sent one.
+func (s *Service) Record(client entity.ClientInfo, deviceID string) {
+	key := clientKey{day: entity.UsageDay(time.Now()), ClientInfo: client}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	devices, ok := s.seen[key]
+	if !ok {
+		if len(s.seen) >= maxClientsPerDay {
+			return
+		}
+		devices = make(map[uint64]struct{})
+		s.seen[key] = devices
+	}
+
+	c := s.pending[key]
+	c.requests++
+	if deviceID != "" && len(devices) < maxDevicesPerClient {
+		h := fnv.New64a()
+		h.Write([]byte(deviceID))
+		if sum := h.Sum64(); !hasKey(devices, sum) {
+			devices[sum] = struct{}{}
+			c.devices++
+		}
+	}
+	s.pending[key] = c
+}
+
+// Flush writes the accumulated counts. On a write failure they are kept for
+// the next flush.
+func (s *Service) Flush(ctx context.Context) error {
+	today := entity.UsageDay(time.Now())
+
+	s.mu.Lock()
+	batch := s.pending
+	s.pending = make(map[clientKey]counters)
+	for key := range s.seen {
+		if key.day.Before(today) {
+			delete(s.seen, key)
+		}
+	}
+	s.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	usage := make([]entity.ClientUsage, 0, len(batch))
+	for key, c := range batch {
+		usage = append(usage, entity.ClientUsage{
+			Day:        key.day,
+			ClientInfo: key.ClientInfo,
+			Requests:   c.requests,
+			Devices:    c.devices,
+		})
+	}
+
+	if err := s.usageRepo.Increment(ctx, usage); err != nil {
+		s.requeue(batch)
+		return fmt.Errorf("persisting client usage: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) requeue(batch map[clientKey]counters) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, c := range batch {
+		cur := s.pending[key]
+		cur.requests += c.requests
+		cur.devices += c.devices
+		s.pending[key] = cur
+	}
+}
+
+// Prune deletes the daily counts older than the retention.
+func (s *Service) Prune(ctx context.Context, now time.Time) (int64, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+
+	deleted, err := s.usageRepo.DeleteBefore(ctx, now.Add(-s.retention))
+	if err != nil {
+		return 0, fmt.Errorf("pruning client usage: %w", err)
+	}
+	return deleted, nil
+}
+
+type adoptionKey struct {
+	platform   string
+	appVersion string
+}
+
+// Adoption returns how much each app version was used over the last days
+// days, today included, by platform and then by requests, most used first.
+func (s *Service) Adoption(ctx context.Context, days int) ([]entity.ClientAdoption, error) {
+	to := entity.UsageDay(time.Now())
+	from := to.AddDate(0, 0, -(days - 1))
+
+	usage, err := s.usageRepo.List(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("listing client usage: %w", err)
+	}
+
+	versions := make(map[adoptionKey]*entity.ClientAdoption)
+	dailyDevices := make(map[adoptionKey]map[time.Time]int64)
+	platformRequests := make(map[string]int64)
+	for _, u := range usage {
+		key := adoptionKey{platform: u.Platform, appVersion: u.AppVersion}
+		a, ok := versions[key]
+		if !ok {
+			a = &entity.ClientAdoption{
+				Platform:   u.Platform,
+				AppVersion: u.AppVersion,
+				FirstSeen:  u.Day,
+				OS:         make(map[string]int64),
+			}
+			versions[key] = a
+			dailyDevices[key] = make(map[time.Time]int64)
+		}
+		a.Requests += u.Requests
+		a.OS[u.OS] += u.Requests
+		a.FirstSeen = minTime(a.FirstSeen, u.Day)
+		a.LastSeen = maxTime(a.LastSeen, u.Day)
+		dailyDevices[key][u.Day] += u.Devices
+		platformRequests[u.Platform] += u.Requests
+	}
+
+	adoption := make([]entity.ClientAdoption, 0, len(versions))
+	for key, a := range versions {
+		for _, devices := range dailyDevices[key] {
+			a.Devices = max(a.Devices, devices)
+		}
+		if total := platformRequests[a.Platform]; total > 0 {
+			a.Share = float64(a.Requests) / float64(total)
+		}
+		adoption = append(adoption, *a)
+	}
+
+	slices.SortFunc(adoption, func(a, b entity.ClientAdoption) int {
+		return cmp.Or(
+			cmp.Compare(a.Platform, b.Platform),
+			cmp.Compare(b.Requests, a.Requests),
+			cmp.Compare(a.AppVersion, b.AppVersion),
+		)
+	})
+	return adoption, nil
+}
+
+func hasKey(m map[uint64]struct{}, k uint64) bool {
+	_, ok := m[k]
+	return ok
+}
+
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,160 @@
+package telemetry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/telemetry"
+)
+
+func TestService_Flush(t *testing.T) {
+	ios := entity.ClientInfo{Platform: entity.PlatformIOS, AppVersion: "2.3.1", OS: "iOS 17"}
+
+	t.Run("counts requests and distinct devices per client", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		usageRepo := mocks.NewMockClientUsageRepository(ctrl)
+		svc := telemetry.NewService(usageRepo, 0)
+
+		ctx := context.Background()
+		svc.Record(ios, "device-1")
+		svc.Record(ios, "device-1")
+		svc.Record(ios, "device-2")
+		svc.Record(ios, "")
+
+		usageRepo.EXPECT().Increment(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, u []entity.ClientUsage) error {
+				require.Len(t, u, 1)
+				assert.Equal(t, ios, u[0].ClientInfo)
+				assert.Equal(t, entity.UsageDay(time.Now()), u[0].Day)
+				assert.Equal(t, int64(4), u[0].Requests)
+				assert.Equal(t, int64(2), u[0].Devices)
+				return nil
+			})
+		require.NoError(t, svc.Flush(ctx))
+
+		// A device already counted today is not counted again after a flush.
+		svc.Record(ios, "device-1")
+		usageRepo.EXPECT().Increment(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, u []entity.ClientUsage) error {
+				require.Len(t, u, 1)
+				assert.Equal(t, int64(1), u[0].Requests)
+				assert.Equal(t, int64(0), u[0].Devices)
+				return nil
+			})
+		require.NoError(t, svc.Flush(ctx))
+
+		// Nothing left to write.
+		require.NoError(t, svc.Flush(ctx))
+	})
+
+	t.Run("keeps counts for the next flush when the write fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		usageRepo := mocks.NewMockClientUsageRepository(ctrl)
+		svc := telemetry.NewService(usageRepo, 0)
+
+		ctx := context.Background()
+		svc.Record(ios, "device-1")
+
+		usageRepo.EXPECT().Increment(ctx, gomock.Any()).Return(errors.New("connection refused"))
+		require.Error(t, svc.Flush(ctx))
+
+		svc.Record(ios, "device-2")
+		usageRepo.EXPECT().Increment(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, u []entity.ClientUsage) error {
+				require.Len(t, u, 1)
+				assert.Equal(t, int64(2), u[0].Requests)
+				assert.Equal(t, int64(2), u[0].Devices)
+				return nil
+			})
+		require.NoError(t, svc.Flush(ctx))
+	})
+}
+
+func TestService_Prune(t *testing.T) {
+	t.Run("deletes counts older than the retention", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		usageRepo := mocks.NewMockClientUsageRepository(ctrl)
+		svc := telemetry.NewService(usageRepo, 90*24*time.Hour)
+
+		ctx := context.Background()
+		now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+		usageRepo.EXPECT().DeleteBefore(ctx, now.Add(-90*24*time.Hour)).Return(int64(3), nil)
+
+		deleted, err := svc.Prune(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), deleted)
+	})
+
+	t.Run("keeps everything without a retention", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc := telemetry.NewService(mocks.NewMockClientUsageRepository(ctrl), 0)
+
+		deleted, err := svc.Prune(context.Background(), time.Now())
+		require.NoError(t, err)
+		assert.Zero(t, deleted)
+	})
+}
+
+func TestService_Adoption(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	usageRepo := mocks.NewMockClientUsageRepository(ctrl)
+	svc := telemetry.NewService(usageRepo, 0)
+
+	ctx := context.Background()
+	today := entity.UsageDay(time.Now())
+	yesterday := today.AddDate(0, 0, -1)
+	usage := func(day time.Time, platform, version, os string, requests, devices int64) entity.ClientUsage {
+		return entity.ClientUsage{
+			Day:        day,
+			ClientInfo: entity.ClientInfo{Platform: platform, AppVersion: version, OS: os},
+			Requests:   requests,
+			Devices:    devices,
+		}
+	}
+
+	usageRepo.EXPECT().List(ctx, today.AddDate(0, 0, -6), today).Return([]entity.ClientUsage{
+		usage(yesterday, entity.PlatformIOS, "2.3.0", "iOS 16", 30, 5),
+		usage(yesterday, entity.PlatformIOS, "2.3.1", "iOS 17", 50, 8),
+		usage(today, entity.PlatformIOS, "2.3.1", "iOS 17", 60, 7),
+		usage(today, entity.PlatformIOS, "2.3.1", "iOS 16", 10, 2),
+		usage(today, entity.PlatformAndroid, "2.3.1", "Android 14", 40, 6),
+	}, nil)
+
+	adoption, err := svc.Adoption(ctx, 7)
+	require.NoError(t, err)
+	require.Len(t, adoption, 3)
+
+	assert.Equal(t, entity.PlatformAndroid, adoption[0].Platform)
+	assert.InDelta(t, 1.0, adoption[0].Share, 1e-9)
+
+	latest := adoption[1]
+	assert.Equal(t, "2.3.1", latest.AppVersion)
+	assert.Equal(t, int64(120), latest.Requests)
+	assert.InDelta(t, 0.8, latest.Share, 1e-9)
+	assert.Equal(t, int64(9), latest.Devices, "busiest day, summed over operating systems")
+	assert.Equal(t, yesterday, latest.FirstSeen)
+	assert.Equal(t, today, latest.LastSeen)
+	assert.Equal(t, map[string]int64{"iOS 17": 110, "iOS 16": 10}, latest.OS)
+
+	assert.Equal(t, "2.3.0", adoption[2].AppVersion)
+	assert.Equal(t, yesterday, adoption[2].LastSeen)
+}
@@ -0,0 +1,99 @@
+package upload
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/authz"
+)
+
+// batchWorkers bounds how many photos of one batch are processed at once, so
+// a large batch does not take all of the instance's image processing.
+const batchWorkers = 4
+
+type BatchUploadInput struct {
+	UserID uuid.UUID
+	NoteID uuid.UUID
+	Files  []BatchFile
+	// SetNoteLocation fills in the note's location from the EXIF position
+	// of the first photo, in file order, that has one, when the note has
+	// none.
+	SetNoteLocation bool
+}
+
+// BatchFile is one photo of a batch upload.
+type BatchFile struct {
+	File        io.Reader
+	Filename    string
+	ContentType string
+	Size        int64
+	// ClientID works as in UploadInput.
+	ClientID string
+}
+
+// BatchResult is the outcome of one file of a batch: Result when the photo
+// is stored, Err when it is not.
+type BatchResult struct {
+	Result *UploadResult
+	Err    error
+}
+
+// UploadBatch uploads the files to the note, batchWorkers at a time, and
+// returns their outcomes in file order. A file that fails does not stop the
+// others; only a note that cannot be found or edited fails the whole batch.
+func (s *Service) UploadBatch(ctx context.Context, input BatchUploadInput) ([]BatchResult, error) {
+	note, err := s.noteRepo.GetByID(ctx, input.NoteID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizer.Authorize(ctx, input.UserID, authz.ActionEdit, note); err != nil {
+		return nil, err
+	}
+	if note.IsDeleted() {
+		return nil, domain.ErrNoteNotFound
+	}
+
+	results := make([]BatchResult, len(input.Files))
+	workers := make(chan struct{}, batchWorkers)
+	var wg sync.WaitGroup
+	for i, f := range input.Files {
+		wg.Add(1)
+		workers <- struct{}{}
+		go func() {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+			result, err := s.Upload(ctx, UploadInput{
+				UserID:      input.UserID,
+				NoteID:      input.NoteID,
+				File:        f.File,
+				Filename:    f.Filename,
+				ContentType: f.ContentType,
+				Size:        f.Size,
+				ClientID:    f.ClientID,
+			})
+			results[i] = BatchResult{Result: result, Err: err}
+		}()
+	}
+	wg.Wait()
+
+	// The location is set once the photos are stored rather than by each
+	// upload, so that the first photo wins whichever finishes first.
+	if input.SetNoteLocation && note.Location == nil {
+		for _, r := range results {
+			if r.Err != nil || r.Result.Photo.Location == nil {
+				continue
+			}
+			note.Update(note.Title, note.Content, r.Result.Photo.Location)
+			r.Result.NoteLocationSet = s.noteRepo.Update(ctx, note) == nil
+			break
+		}
+	}
+
+	return results, nil
+}
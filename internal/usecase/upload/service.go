@@ -1,7 +1,11 @@
 package upload
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"path"
@@ -13,26 +17,36 @@ import (
 	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/storage"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/authz"
 )
 
 type Service struct {
 	photoRepo      repository.PhotoRepository
+	attachmentRepo repository.AttachmentRepository
 	noteRepo       repository.NoteRepository
+	ruleRepo       repository.QualityRuleRepository
 	storage        storage.ImageStorage
 	imageProcessor storage.ImageProcessor
+	authorizer     *authz.Authorizer
 }
 
 func NewService(
 	photoRepo repository.PhotoRepository,
+	attachmentRepo repository.AttachmentRepository,
 	noteRepo repository.NoteRepository,
+	ruleRepo repository.QualityRuleRepository,
 	imageStorage storage.ImageStorage,
 	imageProcessor storage.ImageProcessor,
+	authorizer *authz.Authorizer,
 ) *Service {
 	return &Service{
 		photoRepo:      photoRepo,
+		attachmentRepo: attachmentRepo,
 		noteRepo:       noteRepo,
+		ruleRepo:       ruleRepo,
 		storage:        imageStorage,
 		imageProcessor: imageProcessor,
+		authorizer:     authorizer,
 	}
 }
 
@@ -43,12 +57,23 @@ type UploadInput struct {
 	Filename    string
 	ContentType string
 	Size        int64
+	// ClientID is the device-generated photo ID. Uploading the same ClientID
+	// again returns the stored photo instead of creating a second one.
+	ClientID string
+	// SetNoteLocation fills in the note's location from the photo's EXIF
+	// position when the note has none.
+	SetNoteLocation bool
 }
 
 type UploadResult struct {
 	Photo     *entity.Photo
 	URL       string
 	SignedURL string
+	// NoteLocationSet reports that the note took the photo's position.
+	NoteLocationSet bool
+	// Duplicate reports that the file was already uploaded to the note and
+	// the stored photo is returned instead of a new one.
+	Duplicate bool
 }
 
 func (s *Service) Upload(ctx context.Context, input UploadInput) (*UploadResult, error) {
@@ -57,44 +82,197 @@ func (s *Service) Upload(ctx context.Context, input UploadInput) (*UploadResult,
 		return nil, err
 	}
 
-	if note.UserID != input.UserID {
-		return nil, domain.ErrForbidden
+	if err := s.authorizer.Authorize(ctx, input.UserID, authz.ActionEdit, note); err != nil {
+		return nil, err
 	}
 
 	if note.IsDeleted() {
 		return nil, domain.ErrNoteNotFound
 	}
 
-	processedReader, finalSize, width, height, err := s.imageProcessor.Process(input.File)
+	if input.ClientID != "" {
+		existing, err := s.photoRepo.GetByClientID(ctx, note.UserID, input.ClientID)
+		if err == nil {
+			return s.storedPhoto(existing, note, input.UserID)
+		}
+		if !errors.Is(err, domain.ErrPhotoNotFound) {
+			return nil, fmt.Errorf("getting photo by client id: %w", err)
+		}
+	}
+
+	// Resizing drops EXIF data, so it is read from the original upload.
+	original, err := io.ReadAll(input.File)
+	if err != nil {
+		return nil, fmt.Errorf("reading image: %w", err)
+	}
+
+	sum := sha256.Sum256(original)
+	sourceChecksum := hex.EncodeToString(sum[:])
+	existing, err := s.photoRepo.GetBySourceChecksum(ctx, note.UserID, note.ID, sourceChecksum)
+	if err == nil {
+		return s.duplicatePhoto(existing, note, input.UserID)
+	}
+	if !errors.Is(err, domain.ErrPhotoNotFound) {
+		return nil, fmt.Errorf("getting photo by checksum: %w", err)
+	}
+
+	metadata := s.imageProcessor.Metadata(original)
+
+	processedReader, finalSize, width, height, err := s.imageProcessor.Process(ctx, bytes.NewReader(original))
 	if err != nil {
 		return nil, fmt.Errorf("processing image: %w", err)
 	}
 
+	data, err := io.ReadAll(processedReader)
+	if err != nil {
+		return nil, fmt.Errorf("reading processed image: %w", err)
+	}
+
 	ext := path.Ext(input.Filename)
 	if ext == "" {
 		ext = ".jpg"
 	}
-	key := fmt.Sprintf("notes/%s/%s%s", input.NoteID, uuid.New().String(), ext)
+	base := fmt.Sprintf("notes/%s/%s", input.NoteID, uuid.New().String())
+	key := base + ext
 
-	if err := s.storage.Upload(ctx, key, processedReader, input.ContentType, finalSize); err != nil {
+	// Hash what is actually stored so clients can verify their cached copy.
+	hasher := sha256.New()
+	encryption, err := s.storage.Upload(ctx, key, io.TeeReader(bytes.NewReader(data), hasher), input.ContentType, finalSize)
+	if err != nil {
 		return nil, fmt.Errorf("uploading to storage: %w", err)
 	}
 
 	url := s.storage.GetURL(key)
 	signedURL, _ := s.storage.GetSignedURL(key, 24*time.Hour)
 
-	photo := entity.NewPhoto(input.NoteID, url, key, input.ContentType, finalSize, width, height)
+	photo := entity.NewPhoto(input.NoteID, note.UserID, url, key, input.ContentType, finalSize, width, height)
+	photo.Checksum = hex.EncodeToString(hasher.Sum(nil))
+	photo.SourceChecksum = sourceChecksum
+	photo.ClientID = input.ClientID
+	photo.Encryption = encryption
+	photo.Thumbnails = s.uploadThumbnails(ctx, base, data)
+	photo.TakenAt = metadata.TakenAt
+	photo.Location = metadata.Location
 
 	if err := s.photoRepo.Create(ctx, photo); err != nil {
-		_ = s.storage.Delete(ctx, key)
+		_ = s.deleteObjects(ctx, photo)
+		if errors.Is(err, domain.ErrPhotoAlreadyExists) {
+			// A concurrent retry with the same client ID, or an upload of the
+			// same file, stored it first.
+			if input.ClientID != "" {
+				if existing, getErr := s.photoRepo.GetByClientID(ctx, note.UserID, input.ClientID); getErr == nil {
+					return s.storedPhoto(existing, note, input.UserID)
+				}
+			}
+			if existing, getErr := s.photoRepo.GetBySourceChecksum(ctx, note.UserID, note.ID, sourceChecksum); getErr == nil {
+				return s.duplicatePhoto(existing, note, input.UserID)
+			}
+		}
 		return nil, fmt.Errorf("creating photo record: %w", err)
 	}
 
-	return &UploadResult{
+	// The photo is stored either way; a stale quality result is fixed by the next evaluation.
+	_ = s.refreshQuality(ctx, note)
+
+	result := &UploadResult{
 		Photo:     photo,
 		URL:       url,
 		SignedURL: signedURL,
-	}, nil
+	}
+
+	// Best effort as well: when it fails the client sees NoteLocationSet
+	// false and can still set the location itself.
+	if input.SetNoteLocation && note.Location == nil && photo.Location != nil {
+		note.Update(note.Title, note.Content, photo.Location)
+		result.NoteLocationSet = s.noteRepo.Update(ctx, note) == nil
+	}
+
+	return result, nil
+}
+
+// uploadThumbnails stores the thumbnails of the image under base and returns
+// the ones stored. Thumbnails are best effort: the photo is usable at full
+// size without them, so failures only leave sizes out.
+func (s *Service) uploadThumbnails(ctx context.Context, base string, data []byte) map[string]entity.PhotoThumbnail {
+	thumbnails, err := s.imageProcessor.Thumbnails(ctx, bytes.NewReader(data))
+	if err != nil || len(thumbnails) == 0 {
+		return nil
+	}
+
+	stored := make(map[string]entity.PhotoThumbnail, len(thumbnails))
+	for _, t := range thumbnails {
+		key := fmt.Sprintf("%s_%s.jpg", base, t.Size)
+		if _, err := s.storage.Upload(ctx, key, bytes.NewReader(t.Data), "image/jpeg", int64(len(t.Data))); err != nil {
+			continue
+		}
+		stored[t.Size] = entity.PhotoThumbnail{Key: key, URL: s.storage.GetURL(key), Width: t.Width, Height: t.Height}
+	}
+	return stored
+}
+
+// deleteObjects removes the photo and its thumbnails from storage, returning
+// the first error.
+func (s *Service) deleteObjects(ctx context.Context, photo *entity.Photo) error {
+	var firstErr error
+	for _, key := range photo.ObjectKeys() {
+		if err := s.storage.Delete(ctx, key); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// storedPhoto returns a photo already uploaded with the request's client ID.
+// Client IDs are unique per note owner, so one used on another note is an
+// error rather than a retry. A photo the owner has since excluded from
+// shares gets no signed URL unless the owner is the one retrying.
+func (s *Service) storedPhoto(photo *entity.Photo, note *entity.Note, userID uuid.UUID) (*UploadResult, error) {
+	if photo.NoteID != note.ID {
+		return nil, domain.ErrPhotoClientIDInUse
+	}
+	result := &UploadResult{Photo: photo, URL: photo.URL}
+	if photo.VisibleTo(note.UserID, userID) {
+		result.SignedURL, _ = s.storage.GetSignedURL(photo.Key, 24*time.Hour)
+	}
+	return result, nil
+}
+
+// duplicatePhoto returns the photo already uploaded to the note from the
+// same file. It keeps the client ID it was first uploaded with, if any.
+func (s *Service) duplicatePhoto(photo *entity.Photo, note *entity.Note, userID uuid.UUID) (*UploadResult, error) {
+	result, err := s.storedPhoto(photo, note, userID)
+	if err != nil {
+		return nil, err
+	}
+	result.Duplicate = true
+	return result, nil
+}
+
+// SetShareExclusion hides the photo from, or shows it again to, the users
+// its note is shared with. Only the note owner may change it.
+func (s *Service) SetShareExclusion(ctx context.Context, userID, photoID uuid.UUID, excluded bool) (*entity.Photo, error) {
+	photo, err := s.photoRepo.GetByID(ctx, photoID)
+	if err != nil {
+		return nil, err
+	}
+
+	note, err := s.noteRepo.GetByID(ctx, photo.NoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizer.Authorize(ctx, userID, authz.ActionShare, note); err != nil {
+		return nil, err
+	}
+
+	if photo.ExcludedFromShares == excluded {
+		return photo, nil
+	}
+	if err := s.photoRepo.SetExcludedFromShares(ctx, photo.UserID, photoID, excluded); err != nil {
+		return nil, err
+	}
+	photo.ExcludedFromShares = excluded
+	return photo, nil
 }
 
 func (s *Service) Delete(ctx context.Context, userID, photoID uuid.UUID) error {
@@ -108,17 +286,157 @@ func (s *Service) Delete(ctx context.Context, userID, photoID uuid.UUID) error {
 		return err
 	}
 
-	if note.UserID != userID {
-		return domain.ErrForbidden
+	if err := s.authorizer.Authorize(ctx, userID, authz.ActionEdit, note); err != nil {
+		return err
 	}
 
-	if err := s.photoRepo.Delete(ctx, photoID); err != nil {
+	if err := s.photoRepo.Delete(ctx, photo.UserID, photoID); err != nil {
 		return fmt.Errorf("deleting photo record: %w", err)
 	}
 
-	if err := s.storage.Delete(ctx, photo.Key); err != nil {
+	_ = s.refreshQuality(ctx, note)
+
+	if err := s.deleteObjects(ctx, photo); err != nil {
+		return fmt.Errorf("deleting from storage: %w", err)
+	}
+
+	return nil
+}
+
+type AudioUploadInput struct {
+	UserID      uuid.UUID
+	NoteID      uuid.UUID
+	File        io.Reader
+	Filename    string
+	ContentType string
+	Size        int64
+	// Duration is the recording length reported by the device.
+	Duration time.Duration
+	// ClientID is the device-generated attachment ID. Uploading the same
+	// ClientID again returns the stored attachment instead of a second one.
+	ClientID string
+}
+
+type AttachmentResult struct {
+	Attachment *entity.Attachment
+	SignedURL  string
+}
+
+// UploadAudio stores a voice memo on the note. Audio is stored as sent.
+func (s *Service) UploadAudio(ctx context.Context, input AudioUploadInput) (*AttachmentResult, error) {
+	note, err := s.noteRepo.GetByID(ctx, input.NoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizer.Authorize(ctx, input.UserID, authz.ActionEdit, note); err != nil {
+		return nil, err
+	}
+
+	if note.IsDeleted() {
+		return nil, domain.ErrNoteNotFound
+	}
+
+	if input.ClientID != "" {
+		existing, err := s.attachmentRepo.GetByClientID(ctx, note.UserID, input.ClientID)
+		if err == nil {
+			return s.storedAttachment(existing, input.NoteID)
+		}
+		if !errors.Is(err, domain.ErrAttachmentNotFound) {
+			return nil, fmt.Errorf("getting attachment by client id: %w", err)
+		}
+	}
+
+	ext := path.Ext(input.Filename)
+	if ext == "" {
+		ext = audioExtension(input.ContentType)
+	}
+	key := fmt.Sprintf("notes/%s/%s%s", input.NoteID, uuid.New().String(), ext)
+
+	hasher := sha256.New()
+	if _, err := s.storage.Upload(ctx, key, io.TeeReader(input.File, hasher), input.ContentType, input.Size); err != nil {
+		return nil, fmt.Errorf("uploading to storage: %w", err)
+	}
+
+	attachment := entity.NewAttachment(input.NoteID, entity.AttachmentKindAudio, s.storage.GetURL(key), key, input.ContentType, input.Size, input.Duration)
+	attachment.Checksum = hex.EncodeToString(hasher.Sum(nil))
+	attachment.ClientID = input.ClientID
+
+	if err := s.attachmentRepo.Create(ctx, attachment); err != nil {
+		_ = s.storage.Delete(ctx, key)
+		if errors.Is(err, domain.ErrAttachmentAlreadyExists) && input.ClientID != "" {
+			// A concurrent retry with the same client ID stored it first.
+			if existing, getErr := s.attachmentRepo.GetByClientID(ctx, note.UserID, input.ClientID); getErr == nil {
+				return s.storedAttachment(existing, input.NoteID)
+			}
+		}
+		return nil, fmt.Errorf("creating attachment record: %w", err)
+	}
+
+	signedURL, _ := s.storage.GetSignedURL(key, 24*time.Hour)
+	return &AttachmentResult{Attachment: attachment, SignedURL: signedURL}, nil
+}
+
+// storedAttachment returns an attachment already uploaded with the request's
+// client ID, as storedPhoto does for photos.
+func (s *Service) storedAttachment(attachment *entity.Attachment, noteID uuid.UUID) (*AttachmentResult, error) {
+	if attachment.NoteID != noteID {
+		return nil, domain.ErrAttachmentClientIDInUse
+	}
+	signedURL, _ := s.storage.GetSignedURL(attachment.Key, 24*time.Hour)
+	return &AttachmentResult{Attachment: attachment, SignedURL: signedURL}, nil
+}
+
+func (s *Service) DeleteAttachment(ctx context.Context, userID, attachmentID uuid.UUID) error {
+	attachment, err := s.attachmentRepo.GetByID(ctx, attachmentID)
+	if err != nil {
+		return err
+	}
+
+	note, err := s.noteRepo.GetByID(ctx, attachment.NoteID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.authorizer.Authorize(ctx, userID, authz.ActionEdit, note); err != nil {
+		return err
+	}
+
+	if err := s.attachmentRepo.Delete(ctx, attachmentID); err != nil {
+		return fmt.Errorf("deleting attachment record: %w", err)
+	}
+
+	if err := s.storage.Delete(ctx, attachment.Key); err != nil {
 		return fmt.Errorf("deleting from storage: %w", err)
 	}
+	return nil
+}
+
+func audioExtension(contentType string) string {
+	if contentType == "audio/mpeg" {
+		return ".mp3"
+	}
+	return ".m4a"
+}
+
+// refreshQuality re-evaluates the note after its photos changed. It only
+// writes when the owner's rules depend on photos.
+func (s *Service) refreshQuality(ctx context.Context, note *entity.Note) error {
+	rules, err := s.ruleRepo.GetByUserID(ctx, note.UserID)
+	if err != nil {
+		return fmt.Errorf("getting quality rules: %w", err)
+	}
+	if !rules.RequirePhoto {
+		return nil
+	}
 
+	photos, err := s.photoRepo.GetByNoteID(ctx, note.ID)
+	if err != nil {
+		return fmt.Errorf("loading photos: %w", err)
+	}
+
+	if err := s.noteRepo.UpdateQuality(ctx, note.UserID, note.ID, rules.Evaluate(note, len(photos))); err != nil {
+		return fmt.Errorf("updating note quality: %w", err)
+	}
 	return nil
 }
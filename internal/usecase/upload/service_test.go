@@ -3,6 +3,10 @@ package upload_test
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,12 +15,24 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	storagePort "github.com/marcos-nsantos/field-notes-backend/internal/adapter/storage"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
 	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/valueobject"
 	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/authz"
 	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/upload"
 )
 
+// ownerOnly returns an authorizer for notes that are not shared with anyone.
+func ownerOnly(ctrl *gomock.Controller) *authz.Authorizer {
+	shareRepo := mocks.NewMockShareRepository(ctrl)
+	shareRepo.EXPECT().GetRole(gomock.Any(), gomock.Any(), gomock.Any()).Return("", nil).AnyTimes()
+	orgRepo := mocks.NewMockOrganizationRepository(ctrl)
+	orgRepo.EXPECT().IsAdminOver(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+	return authz.NewAuthorizer(shareRepo, orgRepo, nil)
+}
+
 func TestService_Upload(t *testing.T) {
 	t.Run("uploads image successfully", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
@@ -24,9 +40,10 @@ func TestService_Upload(t *testing.T) {
 
 		photoRepo := mocks.NewMockPhotoRepository(ctrl)
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
 		storage := mocks.NewMockImageStorage(ctrl)
 		imageProcessor := mocks.NewMockImageProcessor(ctrl)
-		svc := upload.NewService(photoRepo, noteRepo, storage, imageProcessor)
+		svc := upload.NewService(photoRepo, nil, noteRepo, ruleRepo, storage, imageProcessor, ownerOnly(ctrl))
 
 		ctx := context.Background()
 		userID := uuid.New()
@@ -37,12 +54,29 @@ func TestService_Upload(t *testing.T) {
 		processedContent := []byte("processed image data")
 		processedReader := bytes.NewReader(processedContent)
 
+		sourceSum := sha256.Sum256(fileContent)
+		sourceChecksum := hex.EncodeToString(sourceSum[:])
+
 		noteRepo.EXPECT().GetByID(ctx, noteID).Return(note, nil)
-		imageProcessor.EXPECT().Process(gomock.Any()).Return(processedReader, int64(len(processedContent)), 800, 600, nil)
-		storage.EXPECT().Upload(ctx, gomock.Any(), processedReader, "image/jpeg", int64(len(processedContent))).Return(nil)
-		storage.EXPECT().GetURL(gomock.Any()).Return("http://storage/photo.jpg")
+		photoRepo.EXPECT().GetBySourceChecksum(ctx, userID, noteID, sourceChecksum).Return(nil, domain.ErrPhotoNotFound)
+		imageProcessor.EXPECT().Metadata(gomock.Any()).Return(storagePort.ImageMetadata{})
+		imageProcessor.EXPECT().Process(gomock.Any(), gomock.Any()).Return(processedReader, int64(len(processedContent)), 800, 600, nil)
+		storage.EXPECT().Upload(ctx, gomock.Any(), gomock.Any(), "image/jpeg", int64(len(processedContent))).DoAndReturn(
+			func(_ context.Context, _ string, r io.Reader, _ string, _ int64) (string, error) {
+				_, err := io.Copy(io.Discard, r)
+				return "aws:kms", err
+			})
+		storage.EXPECT().GetURL(gomock.Not(gomock.Cond(func(key string) bool { return strings.HasSuffix(key, "_small.jpg") }))).Return("http://storage/photo.jpg")
+		imageProcessor.EXPECT().Thumbnails(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, r io.Reader) ([]storagePort.Thumbnail, error) {
+			data, err := io.ReadAll(r)
+			assert.Equal(t, processedContent, data)
+			return []storagePort.Thumbnail{{Size: entity.ThumbnailSmall, Data: []byte("thumb"), Width: 256, Height: 192}}, err
+		})
+		storage.EXPECT().Upload(ctx, gomock.Cond(func(key string) bool { return strings.HasSuffix(key, "_small.jpg") }), gomock.Any(), "image/jpeg", int64(5)).Return("", nil)
+		storage.EXPECT().GetURL(gomock.Cond(func(key string) bool { return strings.HasSuffix(key, "_small.jpg") })).Return("http://storage/photo_small.jpg")
 		storage.EXPECT().GetSignedURL(gomock.Any(), 24*time.Hour).Return("http://storage/photo.jpg?signed=1", nil)
 		photoRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil)
 
 		result, err := svc.Upload(ctx, upload.UploadInput{
 			UserID:      userID,
@@ -55,19 +89,304 @@ func TestService_Upload(t *testing.T) {
 
 		require.NoError(t, err)
 		assert.NotNil(t, result.Photo)
+		sum := sha256.Sum256(processedContent)
+		assert.Equal(t, hex.EncodeToString(sum[:]), result.Photo.Checksum)
+		assert.Equal(t, sourceChecksum, result.Photo.SourceChecksum)
+		assert.Equal(t, userID, result.Photo.UserID)
+		assert.False(t, result.Duplicate)
+		assert.Equal(t, "aws:kms", result.Photo.Encryption)
+		assert.Equal(t, "http://storage/photo_small.jpg", result.Photo.Thumbnails[entity.ThumbnailSmall].URL)
+		assert.Equal(t, 256, result.Photo.Thumbnails[entity.ThumbnailSmall].Width)
 		assert.Equal(t, "http://storage/photo.jpg", result.URL)
 		assert.Contains(t, result.SignedURL, "signed")
 	})
 
+	t.Run("records exif data and sets the note location when asked", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		storage := mocks.NewMockImageStorage(ctrl)
+		imageProcessor := mocks.NewMockImageProcessor(ctrl)
+		svc := upload.NewService(photoRepo, nil, noteRepo, ruleRepo, storage, imageProcessor, ownerOnly(ctrl))
+
+		ctx := context.Background()
+		userID := uuid.New()
+		noteID := uuid.New()
+		note := &entity.Note{ID: noteID, UserID: userID, Title: "Oak"}
+		original := []byte("original with exif")
+		takenAt := time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC)
+		position := valueobject.NewLocation(38.7, -9.1, nil, nil)
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(note, nil)
+		photoRepo.EXPECT().GetBySourceChecksum(ctx, userID, noteID, gomock.Any()).Return(nil, domain.ErrPhotoNotFound)
+		imageProcessor.EXPECT().Metadata(original).Return(storagePort.ImageMetadata{TakenAt: &takenAt, Location: position})
+		imageProcessor.EXPECT().Process(gomock.Any(), gomock.Any()).Return(bytes.NewReader([]byte("resized")), int64(7), 800, 600, nil)
+		imageProcessor.EXPECT().Thumbnails(gomock.Any(), gomock.Any()).Return(nil, nil)
+		storage.EXPECT().Upload(ctx, gomock.Any(), gomock.Any(), "image/jpeg", int64(7)).Return("", nil)
+		storage.EXPECT().GetURL(gomock.Any()).Return("http://storage/photo.jpg")
+		storage.EXPECT().GetSignedURL(gomock.Any(), 24*time.Hour).Return("", nil)
+		photoRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil)
+		noteRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, n *entity.Note) error {
+			assert.Equal(t, position, n.Location)
+			assert.Equal(t, "Oak", n.Title)
+			return nil
+		})
+
+		result, err := svc.Upload(ctx, upload.UploadInput{
+			UserID:          userID,
+			NoteID:          noteID,
+			File:            bytes.NewReader(original),
+			ContentType:     "image/jpeg",
+			Size:            int64(len(original)),
+			SetNoteLocation: true,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, &takenAt, result.Photo.TakenAt)
+		assert.Equal(t, position, result.Photo.Location)
+		assert.True(t, result.NoteLocationSet)
+	})
+
+	t.Run("keeps an existing note location", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		storage := mocks.NewMockImageStorage(ctrl)
+		imageProcessor := mocks.NewMockImageProcessor(ctrl)
+		svc := upload.NewService(photoRepo, nil, noteRepo, ruleRepo, storage, imageProcessor, ownerOnly(ctrl))
+
+		ctx := context.Background()
+		userID := uuid.New()
+		noteID := uuid.New()
+		note := &entity.Note{ID: noteID, UserID: userID, Location: valueobject.NewLocation(41.1, -8.6, nil, nil)}
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(note, nil)
+		photoRepo.EXPECT().GetBySourceChecksum(ctx, userID, noteID, gomock.Any()).Return(nil, domain.ErrPhotoNotFound)
+		imageProcessor.EXPECT().Metadata(gomock.Any()).Return(storagePort.ImageMetadata{Location: valueobject.NewLocation(38.7, -9.1, nil, nil)})
+		imageProcessor.EXPECT().Process(gomock.Any(), gomock.Any()).Return(bytes.NewReader([]byte("resized")), int64(7), 800, 600, nil)
+		imageProcessor.EXPECT().Thumbnails(gomock.Any(), gomock.Any()).Return(nil, nil)
+		storage.EXPECT().Upload(ctx, gomock.Any(), gomock.Any(), "image/jpeg", int64(7)).Return("", nil)
+		storage.EXPECT().GetURL(gomock.Any()).Return("http://storage/photo.jpg")
+		storage.EXPECT().GetSignedURL(gomock.Any(), 24*time.Hour).Return("", nil)
+		photoRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil)
+
+		result, err := svc.Upload(ctx, upload.UploadInput{
+			UserID: userID, NoteID: noteID, File: strings.NewReader("data"), ContentType: "image/jpeg", SetNoteLocation: true,
+		})
+
+		require.NoError(t, err)
+		assert.False(t, result.NoteLocationSet)
+		assert.InDelta(t, 41.1, note.Location.Latitude, 0.0001)
+	})
+
+	t.Run("returns stored photo for repeated client id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		storage := mocks.NewMockImageStorage(ctrl)
+		svc := upload.NewService(photoRepo, nil, noteRepo, nil, storage, mocks.NewMockImageProcessor(ctrl), ownerOnly(ctrl))
+
+		ctx := context.Background()
+		userID := uuid.New()
+		noteID := uuid.New()
+		note := &entity.Note{ID: noteID, UserID: userID}
+		existing := &entity.Photo{ID: uuid.New(), NoteID: noteID, URL: "http://storage/a.jpg", Key: "notes/a.jpg", ClientID: "photo-1"}
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(note, nil)
+		photoRepo.EXPECT().GetByClientID(ctx, userID, "photo-1").Return(existing, nil)
+		storage.EXPECT().GetSignedURL("notes/a.jpg", 24*time.Hour).Return("http://storage/a.jpg?signed=1", nil)
+
+		result, err := svc.Upload(ctx, upload.UploadInput{
+			UserID:      userID,
+			NoteID:      noteID,
+			File:        bytes.NewReader([]byte("data")),
+			Filename:    "photo.jpg",
+			ContentType: "image/jpeg",
+			Size:        4,
+			ClientID:    "photo-1",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, existing.ID, result.Photo.ID)
+		assert.Equal(t, "http://storage/a.jpg", result.URL)
+	})
+
+	t.Run("rejects a client id used on another note", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := upload.NewService(photoRepo, nil, noteRepo, nil, mocks.NewMockImageStorage(ctrl), mocks.NewMockImageProcessor(ctrl), ownerOnly(ctrl))
+
+		ctx := context.Background()
+		userID := uuid.New()
+		noteID := uuid.New()
+		note := &entity.Note{ID: noteID, UserID: userID}
+		existing := &entity.Photo{ID: uuid.New(), NoteID: uuid.New(), Key: "notes/a.jpg", ClientID: "photo-1"}
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(note, nil)
+		photoRepo.EXPECT().GetByClientID(ctx, userID, "photo-1").Return(existing, nil)
+
+		result, err := svc.Upload(ctx, upload.UploadInput{
+			UserID:      userID,
+			NoteID:      noteID,
+			File:        bytes.NewReader([]byte("data")),
+			Filename:    "photo.jpg",
+			ContentType: "image/jpeg",
+			Size:        4,
+			ClientID:    "photo-1",
+		})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrPhotoClientIDInUse)
+	})
+
+	t.Run("returns the photo a concurrent retry stored first", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		storageClient := mocks.NewMockImageStorage(ctrl)
+		imageProcessor := mocks.NewMockImageProcessor(ctrl)
+		svc := upload.NewService(photoRepo, nil, noteRepo, nil, storageClient, imageProcessor, ownerOnly(ctrl))
+
+		ctx := context.Background()
+		userID := uuid.New()
+		noteID := uuid.New()
+		note := &entity.Note{ID: noteID, UserID: userID}
+		winner := &entity.Photo{ID: uuid.New(), NoteID: noteID, URL: "http://storage/a.jpg", Key: "notes/a.jpg", ClientID: "photo-1"}
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(note, nil)
+		gomock.InOrder(
+			photoRepo.EXPECT().GetByClientID(ctx, userID, "photo-1").Return(nil, domain.ErrPhotoNotFound),
+			photoRepo.EXPECT().GetByClientID(ctx, userID, "photo-1").Return(winner, nil),
+		)
+		photoRepo.EXPECT().GetBySourceChecksum(ctx, userID, noteID, gomock.Any()).Return(nil, domain.ErrPhotoNotFound)
+		imageProcessor.EXPECT().Metadata(gomock.Any()).Return(storagePort.ImageMetadata{})
+		imageProcessor.EXPECT().Process(gomock.Any(), gomock.Any()).Return(bytes.NewReader([]byte("processed")), int64(9), 800, 600, nil)
+		imageProcessor.EXPECT().Thumbnails(gomock.Any(), gomock.Any()).Return(nil, nil)
+		storageClient.EXPECT().Upload(ctx, gomock.Any(), gomock.Any(), "image/jpeg", int64(9)).Return("", nil)
+		storageClient.EXPECT().GetURL(gomock.Any()).Return("http://storage/b.jpg")
+		storageClient.EXPECT().GetSignedURL(gomock.Any(), 24*time.Hour).Return("signed", nil).Times(2)
+		photoRepo.EXPECT().Create(ctx, gomock.Any()).Return(domain.ErrPhotoAlreadyExists)
+		storageClient.EXPECT().Delete(ctx, gomock.Any()).Return(nil)
+
+		result, err := svc.Upload(ctx, upload.UploadInput{
+			UserID:      userID,
+			NoteID:      noteID,
+			File:        bytes.NewReader([]byte("data")),
+			Filename:    "photo.jpg",
+			ContentType: "image/jpeg",
+			Size:        4,
+			ClientID:    "photo-1",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, winner.ID, result.Photo.ID)
+		assert.Equal(t, "http://storage/a.jpg", result.URL)
+	})
+
+	t.Run("returns the photo already uploaded from the same file", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		storageClient := mocks.NewMockImageStorage(ctrl)
+		svc := upload.NewService(photoRepo, nil, noteRepo, nil, storageClient, mocks.NewMockImageProcessor(ctrl), ownerOnly(ctrl))
+
+		ctx := context.Background()
+		userID := uuid.New()
+		noteID := uuid.New()
+		note := &entity.Note{ID: noteID, UserID: userID}
+		sum := sha256.Sum256([]byte("data"))
+		existing := &entity.Photo{
+			ID: uuid.New(), NoteID: noteID, URL: "http://storage/a.jpg", Key: "notes/a.jpg",
+			ClientID: "photo-1", SourceChecksum: hex.EncodeToString(sum[:]),
+		}
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(note, nil)
+		photoRepo.EXPECT().GetByClientID(ctx, userID, "photo-2").Return(nil, domain.ErrPhotoNotFound)
+		photoRepo.EXPECT().GetBySourceChecksum(ctx, userID, noteID, existing.SourceChecksum).Return(existing, nil)
+		storageClient.EXPECT().GetSignedURL("notes/a.jpg", 24*time.Hour).Return("http://storage/a.jpg?signed=1", nil)
+
+		result, err := svc.Upload(ctx, upload.UploadInput{
+			UserID:      userID,
+			NoteID:      noteID,
+			File:        bytes.NewReader([]byte("data")),
+			Filename:    "copy.jpg",
+			ContentType: "image/jpeg",
+			Size:        4,
+			ClientID:    "photo-2",
+		})
+
+		require.NoError(t, err)
+		assert.True(t, result.Duplicate)
+		assert.Equal(t, existing.ID, result.Photo.ID)
+		assert.Equal(t, "photo-1", result.Photo.ClientID)
+	})
+
+	t.Run("returns the photo a concurrent upload of the same file stored first", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		storageClient := mocks.NewMockImageStorage(ctrl)
+		imageProcessor := mocks.NewMockImageProcessor(ctrl)
+		svc := upload.NewService(photoRepo, nil, noteRepo, nil, storageClient, imageProcessor, ownerOnly(ctrl))
+
+		ctx := context.Background()
+		userID := uuid.New()
+		noteID := uuid.New()
+		note := &entity.Note{ID: noteID, UserID: userID}
+		winner := &entity.Photo{ID: uuid.New(), NoteID: noteID, URL: "http://storage/a.jpg", Key: "notes/a.jpg"}
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(note, nil)
+		gomock.InOrder(
+			photoRepo.EXPECT().GetBySourceChecksum(ctx, userID, noteID, gomock.Any()).Return(nil, domain.ErrPhotoNotFound),
+			photoRepo.EXPECT().GetBySourceChecksum(ctx, userID, noteID, gomock.Any()).Return(winner, nil),
+		)
+		imageProcessor.EXPECT().Metadata(gomock.Any()).Return(storagePort.ImageMetadata{})
+		imageProcessor.EXPECT().Process(gomock.Any(), gomock.Any()).Return(bytes.NewReader([]byte("processed")), int64(9), 800, 600, nil)
+		imageProcessor.EXPECT().Thumbnails(gomock.Any(), gomock.Any()).Return(nil, nil)
+		storageClient.EXPECT().Upload(ctx, gomock.Any(), gomock.Any(), "image/jpeg", int64(9)).Return("", nil)
+		storageClient.EXPECT().GetURL(gomock.Any()).Return("http://storage/b.jpg")
+		storageClient.EXPECT().GetSignedURL(gomock.Any(), 24*time.Hour).Return("signed", nil).Times(2)
+		photoRepo.EXPECT().Create(ctx, gomock.Any()).Return(domain.ErrPhotoAlreadyExists)
+		storageClient.EXPECT().Delete(ctx, gomock.Any()).Return(nil)
+
+		result, err := svc.Upload(ctx, upload.UploadInput{
+			UserID: userID, NoteID: noteID, File: strings.NewReader("data"), Filename: "photo.jpg", ContentType: "image/jpeg", Size: 4,
+		})
+
+		require.NoError(t, err)
+		assert.True(t, result.Duplicate)
+		assert.Equal(t, winner.ID, result.Photo.ID)
+	})
+
 	t.Run("returns forbidden for non-owner", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		photoRepo := mocks.NewMockPhotoRepository(ctrl)
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
 		storage := mocks.NewMockImageStorage(ctrl)
 		imageProcessor := mocks.NewMockImageProcessor(ctrl)
-		svc := upload.NewService(photoRepo, noteRepo, storage, imageProcessor)
+		svc := upload.NewService(photoRepo, nil, noteRepo, ruleRepo, storage, imageProcessor, ownerOnly(ctrl))
 
 		ctx := context.Background()
 		ownerID := uuid.New()
@@ -90,15 +409,43 @@ func TestService_Upload(t *testing.T) {
 		assert.ErrorIs(t, err, domain.ErrForbidden)
 	})
 
+	t.Run("team viewers cannot add photos", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		teamRepo := mocks.NewMockTeamRepository(ctrl)
+		svc := upload.NewService(nil, nil, noteRepo, nil, nil, nil, authz.NewAuthorizer(nil, nil, teamRepo))
+
+		ctx := context.Background()
+		viewerID, teamID := uuid.New(), uuid.New()
+		note := &entity.Note{ID: uuid.New(), UserID: uuid.New(), TeamID: &teamID}
+
+		noteRepo.EXPECT().GetByID(ctx, note.ID).Return(note, nil)
+		teamRepo.EXPECT().GetRole(ctx, teamID, viewerID).Return(entity.TeamRoleViewer, nil)
+
+		_, err := svc.Upload(ctx, upload.UploadInput{
+			UserID:      viewerID,
+			NoteID:      note.ID,
+			File:        bytes.NewReader([]byte("data")),
+			Filename:    "photo.jpg",
+			ContentType: "image/jpeg",
+			Size:        4,
+		})
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
 	t.Run("returns not found for deleted note", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		photoRepo := mocks.NewMockPhotoRepository(ctrl)
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
 		storage := mocks.NewMockImageStorage(ctrl)
 		imageProcessor := mocks.NewMockImageProcessor(ctrl)
-		svc := upload.NewService(photoRepo, noteRepo, storage, imageProcessor)
+		svc := upload.NewService(photoRepo, nil, noteRepo, ruleRepo, storage, imageProcessor, ownerOnly(ctrl))
 
 		ctx := context.Background()
 		userID := uuid.New()
@@ -127,9 +474,10 @@ func TestService_Upload(t *testing.T) {
 
 		photoRepo := mocks.NewMockPhotoRepository(ctrl)
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
 		storage := mocks.NewMockImageStorage(ctrl)
 		imageProcessor := mocks.NewMockImageProcessor(ctrl)
-		svc := upload.NewService(photoRepo, noteRepo, storage, imageProcessor)
+		svc := upload.NewService(photoRepo, nil, noteRepo, ruleRepo, storage, imageProcessor, ownerOnly(ctrl))
 
 		ctx := context.Background()
 		userID := uuid.New()
@@ -156,9 +504,10 @@ func TestService_Upload(t *testing.T) {
 
 		photoRepo := mocks.NewMockPhotoRepository(ctrl)
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
 		storageClient := mocks.NewMockImageStorage(ctrl)
 		imageProcessor := mocks.NewMockImageProcessor(ctrl)
-		svc := upload.NewService(photoRepo, noteRepo, storageClient, imageProcessor)
+		svc := upload.NewService(photoRepo, nil, noteRepo, ruleRepo, storageClient, imageProcessor, ownerOnly(ctrl))
 
 		ctx := context.Background()
 		userID := uuid.New()
@@ -168,12 +517,19 @@ func TestService_Upload(t *testing.T) {
 		processedReader := bytes.NewReader([]byte("processed"))
 
 		noteRepo.EXPECT().GetByID(ctx, noteID).Return(note, nil)
-		imageProcessor.EXPECT().Process(gomock.Any()).Return(processedReader, int64(9), 800, 600, nil)
-		storageClient.EXPECT().Upload(ctx, gomock.Any(), processedReader, "image/jpeg", int64(9)).Return(nil)
-		storageClient.EXPECT().GetURL(gomock.Any()).Return("http://storage/photo.jpg")
+		photoRepo.EXPECT().GetBySourceChecksum(ctx, userID, noteID, gomock.Any()).Return(nil, domain.ErrPhotoNotFound)
+		imageProcessor.EXPECT().Metadata(gomock.Any()).Return(storagePort.ImageMetadata{})
+		imageProcessor.EXPECT().Process(gomock.Any(), gomock.Any()).Return(processedReader, int64(9), 800, 600, nil)
+		imageProcessor.EXPECT().Thumbnails(gomock.Any(), gomock.Any()).Return([]storagePort.Thumbnail{{Size: entity.ThumbnailSmall, Data: []byte("thumb")}}, nil)
+		storageClient.EXPECT().Upload(ctx, gomock.Any(), gomock.Any(), "image/jpeg", gomock.Any()).Return("", nil).Times(2)
+		storageClient.EXPECT().GetURL(gomock.Any()).Return("http://storage/photo.jpg").Times(2)
 		storageClient.EXPECT().GetSignedURL(gomock.Any(), 24*time.Hour).Return("http://storage/photo.jpg?signed=1", nil)
 		photoRepo.EXPECT().Create(ctx, gomock.Any()).Return(domain.ErrPhotoNotFound)
-		storageClient.EXPECT().Delete(ctx, gomock.Any()).Return(nil)
+		var deleted []string
+		storageClient.EXPECT().Delete(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, key string) error {
+			deleted = append(deleted, key)
+			return nil
+		}).Times(2)
 
 		result, err := svc.Upload(ctx, upload.UploadInput{
 			UserID:      userID,
@@ -186,6 +542,130 @@ func TestService_Upload(t *testing.T) {
 
 		assert.Nil(t, result)
 		assert.Error(t, err)
+		require.Len(t, deleted, 2)
+		assert.True(t, strings.HasSuffix(deleted[1], "_small.jpg"))
+	})
+}
+
+func TestService_UploadBatch(t *testing.T) {
+	// expectStored expects the uploads of n files that are stored.
+	expectStored := func(photoRepo *mocks.MockPhotoRepository, ruleRepo *mocks.MockQualityRuleRepository, storage *mocks.MockImageStorage, imageProcessor *mocks.MockImageProcessor, n int) {
+		photoRepo.EXPECT().GetBySourceChecksum(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, domain.ErrPhotoNotFound).Times(n)
+		imageProcessor.EXPECT().Process(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, r io.Reader) (io.Reader, int64, int, int, error) {
+			return r, 4, 800, 600, nil
+		}).Times(n)
+		imageProcessor.EXPECT().Thumbnails(gomock.Any(), gomock.Any()).Return(nil, nil).Times(n)
+		storage.EXPECT().Upload(gomock.Any(), gomock.Any(), gomock.Any(), "image/jpeg", int64(4)).Return("", nil).Times(n)
+		storage.EXPECT().GetURL(gomock.Any()).Return("http://storage/photo.jpg").Times(n)
+		storage.EXPECT().GetSignedURL(gomock.Any(), 24*time.Hour).Return("", nil).Times(n)
+		photoRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(n)
+		ruleRepo.EXPECT().GetByUserID(gomock.Any(), gomock.Any()).Return(&entity.QualityRules{}, nil).Times(n)
+	}
+
+	t.Run("reports each file in order", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		storage := mocks.NewMockImageStorage(ctrl)
+		imageProcessor := mocks.NewMockImageProcessor(ctrl)
+		svc := upload.NewService(photoRepo, nil, noteRepo, ruleRepo, storage, imageProcessor, ownerOnly(ctrl))
+
+		ctx := context.Background()
+		userID := uuid.New()
+		note := entity.Note{ID: uuid.New(), UserID: userID}
+
+		noteRepo.EXPECT().GetByID(ctx, note.ID).DoAndReturn(func(context.Context, uuid.UUID) (*entity.Note, error) {
+			n := note
+			return &n, nil
+		}).Times(4)
+		photoRepo.EXPECT().GetByClientID(ctx, userID, "taken").
+			Return(&entity.Photo{ID: uuid.New(), NoteID: uuid.New(), ClientID: "taken"}, nil)
+		photoRepo.EXPECT().GetByClientID(ctx, userID, gomock.Not("taken")).Return(nil, domain.ErrPhotoNotFound).Times(2)
+		imageProcessor.EXPECT().Metadata(gomock.Any()).Return(storagePort.ImageMetadata{}).Times(2)
+		expectStored(photoRepo, ruleRepo, storage, imageProcessor, 2)
+
+		results, err := svc.UploadBatch(ctx, upload.BatchUploadInput{
+			UserID: userID,
+			NoteID: note.ID,
+			Files: []upload.BatchFile{
+				{File: strings.NewReader("img1"), Filename: "1.jpg", ContentType: "image/jpeg", Size: 4, ClientID: "one"},
+				{File: strings.NewReader("img2"), Filename: "2.jpg", ContentType: "image/jpeg", Size: 4, ClientID: "taken"},
+				{File: strings.NewReader("img3"), Filename: "3.jpg", ContentType: "image/jpeg", Size: 4, ClientID: "three"},
+			},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		require.NoError(t, results[0].Err)
+		assert.Equal(t, "one", results[0].Result.Photo.ClientID)
+		assert.ErrorIs(t, results[1].Err, domain.ErrPhotoClientIDInUse)
+		require.NoError(t, results[2].Err)
+		assert.Equal(t, "three", results[2].Result.Photo.ClientID)
+	})
+
+	t.Run("sets the note location from the first photo that has one", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		storage := mocks.NewMockImageStorage(ctrl)
+		imageProcessor := mocks.NewMockImageProcessor(ctrl)
+		svc := upload.NewService(photoRepo, nil, noteRepo, ruleRepo, storage, imageProcessor, ownerOnly(ctrl))
+
+		ctx := context.Background()
+		userID := uuid.New()
+		note := entity.Note{ID: uuid.New(), UserID: userID, Title: "Oak"}
+		first := valueobject.NewLocation(38.7, -9.1, nil, nil)
+		second := valueobject.NewLocation(41.1, -8.6, nil, nil)
+
+		noteRepo.EXPECT().GetByID(ctx, note.ID).DoAndReturn(func(context.Context, uuid.UUID) (*entity.Note, error) {
+			n := note
+			return &n, nil
+		}).Times(4)
+		imageProcessor.EXPECT().Metadata([]byte("none")).Return(storagePort.ImageMetadata{})
+		imageProcessor.EXPECT().Metadata([]byte("gps1")).Return(storagePort.ImageMetadata{Location: first})
+		imageProcessor.EXPECT().Metadata([]byte("gps2")).Return(storagePort.ImageMetadata{Location: second})
+		expectStored(photoRepo, ruleRepo, storage, imageProcessor, 3)
+		noteRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, n *entity.Note) error {
+			assert.Equal(t, first, n.Location)
+			return nil
+		})
+
+		results, err := svc.UploadBatch(ctx, upload.BatchUploadInput{
+			UserID: userID,
+			NoteID: note.ID,
+			Files: []upload.BatchFile{
+				{File: strings.NewReader("none"), ContentType: "image/jpeg", Size: 4},
+				{File: strings.NewReader("gps1"), ContentType: "image/jpeg", Size: 4},
+				{File: strings.NewReader("gps2"), ContentType: "image/jpeg", Size: 4},
+			},
+			SetNoteLocation: true,
+		})
+
+		require.NoError(t, err)
+		assert.False(t, results[0].Result.NoteLocationSet)
+		assert.True(t, results[1].Result.NoteLocationSet)
+		assert.False(t, results[2].Result.NoteLocationSet)
+	})
+
+	t.Run("fails as a whole for a note the user cannot edit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := upload.NewService(nil, nil, noteRepo, nil, nil, nil, ownerOnly(ctrl))
+
+		ctx := context.Background()
+		note := &entity.Note{ID: uuid.New(), UserID: uuid.New()}
+		noteRepo.EXPECT().GetByID(ctx, note.ID).Return(note, nil)
+
+		results, err := svc.UploadBatch(ctx, upload.BatchUploadInput{
+			UserID: uuid.New(),
+			NoteID: note.ID,
+			Files:  []upload.BatchFile{{File: strings.NewReader("img1"), ContentType: "image/jpeg", Size: 4}},
+		})
+
+		assert.Nil(t, results)
+		assert.ErrorIs(t, err, domain.ErrForbidden)
 	})
 }
 
@@ -196,20 +676,57 @@ func TestService_Delete(t *testing.T) {
 
 		photoRepo := mocks.NewMockPhotoRepository(ctrl)
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
+		storageClient := mocks.NewMockImageStorage(ctrl)
+		imageProcessor := mocks.NewMockImageProcessor(ctrl)
+		svc := upload.NewService(photoRepo, nil, noteRepo, ruleRepo, storageClient, imageProcessor, ownerOnly(ctrl))
+
+		ctx := context.Background()
+		userID := uuid.New()
+		noteID := uuid.New()
+		photoID := uuid.New()
+		photo := &entity.Photo{ID: photoID, NoteID: noteID, UserID: userID, Key: "notes/123/photo.jpg"}
+		note := &entity.Note{ID: noteID, UserID: userID}
+
+		photoRepo.EXPECT().GetByID(ctx, photoID).Return(photo, nil)
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(note, nil)
+		photoRepo.EXPECT().Delete(ctx, userID, photoID).Return(nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{}, nil)
+		storageClient.EXPECT().Delete(ctx, "notes/123/photo.jpg").Return(nil)
+
+		err := svc.Delete(ctx, userID, photoID)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("re-evaluates quality when rules require a photo", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
 		storageClient := mocks.NewMockImageStorage(ctrl)
 		imageProcessor := mocks.NewMockImageProcessor(ctrl)
-		svc := upload.NewService(photoRepo, noteRepo, storageClient, imageProcessor)
+		svc := upload.NewService(photoRepo, nil, noteRepo, ruleRepo, storageClient, imageProcessor, ownerOnly(ctrl))
 
 		ctx := context.Background()
 		userID := uuid.New()
 		noteID := uuid.New()
 		photoID := uuid.New()
-		photo := &entity.Photo{ID: photoID, NoteID: noteID, Key: "notes/123/photo.jpg"}
+		photo := &entity.Photo{ID: photoID, NoteID: noteID, UserID: userID, Key: "notes/123/photo.jpg"}
 		note := &entity.Note{ID: noteID, UserID: userID}
 
 		photoRepo.EXPECT().GetByID(ctx, photoID).Return(photo, nil)
 		noteRepo.EXPECT().GetByID(ctx, noteID).Return(note, nil)
-		photoRepo.EXPECT().Delete(ctx, photoID).Return(nil)
+		photoRepo.EXPECT().Delete(ctx, userID, photoID).Return(nil)
+		ruleRepo.EXPECT().GetByUserID(ctx, userID).Return(&entity.QualityRules{RequirePhoto: true}, nil)
+		photoRepo.EXPECT().GetByNoteID(ctx, noteID).Return([]entity.Photo{}, nil)
+		noteRepo.EXPECT().UpdateQuality(ctx, userID, noteID, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _, _ uuid.UUID, result entity.QualityResult) error {
+				assert.Equal(t, entity.QualityFailed, result.Status)
+				return nil
+			})
 		storageClient.EXPECT().Delete(ctx, "notes/123/photo.jpg").Return(nil)
 
 		err := svc.Delete(ctx, userID, photoID)
@@ -223,9 +740,10 @@ func TestService_Delete(t *testing.T) {
 
 		photoRepo := mocks.NewMockPhotoRepository(ctrl)
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
 		storageClient := mocks.NewMockImageStorage(ctrl)
 		imageProcessor := mocks.NewMockImageProcessor(ctrl)
-		svc := upload.NewService(photoRepo, noteRepo, storageClient, imageProcessor)
+		svc := upload.NewService(photoRepo, nil, noteRepo, ruleRepo, storageClient, imageProcessor, ownerOnly(ctrl))
 
 		ctx := context.Background()
 		ownerID := uuid.New()
@@ -249,9 +767,10 @@ func TestService_Delete(t *testing.T) {
 
 		photoRepo := mocks.NewMockPhotoRepository(ctrl)
 		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		ruleRepo := mocks.NewMockQualityRuleRepository(ctrl)
 		storageClient := mocks.NewMockImageStorage(ctrl)
 		imageProcessor := mocks.NewMockImageProcessor(ctrl)
-		svc := upload.NewService(photoRepo, noteRepo, storageClient, imageProcessor)
+		svc := upload.NewService(photoRepo, nil, noteRepo, ruleRepo, storageClient, imageProcessor, ownerOnly(ctrl))
 
 		ctx := context.Background()
 		userID := uuid.New()
@@ -264,3 +783,256 @@ func TestService_Delete(t *testing.T) {
 		assert.ErrorIs(t, err, domain.ErrPhotoNotFound)
 	})
 }
+
+func TestService_SetShareExclusion(t *testing.T) {
+	setup := func(t *testing.T) (*mocks.MockPhotoRepository, *mocks.MockNoteRepository, *upload.Service) {
+		ctrl := gomock.NewController(t)
+		photoRepo := mocks.NewMockPhotoRepository(ctrl)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := upload.NewService(photoRepo, nil, noteRepo, nil, nil, nil, ownerOnly(ctrl))
+		return photoRepo, noteRepo, svc
+	}
+
+	t.Run("excludes photo for owner", func(t *testing.T) {
+		photoRepo, noteRepo, svc := setup(t)
+
+		ctx := context.Background()
+		ownerID := uuid.New()
+		photo := &entity.Photo{ID: uuid.New(), NoteID: uuid.New(), UserID: ownerID}
+
+		photoRepo.EXPECT().GetByID(ctx, photo.ID).Return(photo, nil)
+		noteRepo.EXPECT().GetByID(ctx, photo.NoteID).Return(&entity.Note{ID: photo.NoteID, UserID: ownerID}, nil)
+		photoRepo.EXPECT().SetExcludedFromShares(ctx, ownerID, photo.ID, true).Return(nil)
+
+		got, err := svc.SetShareExclusion(ctx, ownerID, photo.ID, true)
+
+		require.NoError(t, err)
+		assert.True(t, got.ExcludedFromShares)
+	})
+
+	t.Run("skips the write when unchanged", func(t *testing.T) {
+		photoRepo, noteRepo, svc := setup(t)
+
+		ctx := context.Background()
+		ownerID := uuid.New()
+		photo := &entity.Photo{ID: uuid.New(), NoteID: uuid.New(), ExcludedFromShares: true}
+
+		photoRepo.EXPECT().GetByID(ctx, photo.ID).Return(photo, nil)
+		noteRepo.EXPECT().GetByID(ctx, photo.NoteID).Return(&entity.Note{ID: photo.NoteID, UserID: ownerID}, nil)
+
+		got, err := svc.SetShareExclusion(ctx, ownerID, photo.ID, true)
+
+		require.NoError(t, err)
+		assert.True(t, got.ExcludedFromShares)
+	})
+
+	t.Run("returns forbidden for non-owner", func(t *testing.T) {
+		photoRepo, noteRepo, svc := setup(t)
+
+		ctx := context.Background()
+		photo := &entity.Photo{ID: uuid.New(), NoteID: uuid.New()}
+
+		photoRepo.EXPECT().GetByID(ctx, photo.ID).Return(photo, nil)
+		noteRepo.EXPECT().GetByID(ctx, photo.NoteID).Return(&entity.Note{ID: photo.NoteID, UserID: uuid.New()}, nil)
+
+		_, err := svc.SetShareExclusion(ctx, uuid.New(), photo.ID, true)
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+}
+
+func TestService_UploadAudio(t *testing.T) {
+	t.Run("stores the audio and records its checksum", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		attachmentRepo := mocks.NewMockAttachmentRepository(ctrl)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		storage := mocks.NewMockImageStorage(ctrl)
+		svc := upload.NewService(nil, attachmentRepo, noteRepo, nil, storage, nil, ownerOnly(ctrl))
+
+		ctx := context.Background()
+		userID := uuid.New()
+		noteID := uuid.New()
+		content := []byte("fake audio data")
+		sum := sha256.Sum256(content)
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(&entity.Note{ID: noteID, UserID: userID}, nil)
+		attachmentRepo.EXPECT().GetByClientID(ctx, userID, "memo-1").Return(nil, domain.ErrAttachmentNotFound)
+		storage.EXPECT().Upload(ctx, gomock.Any(), gomock.Any(), "audio/m4a", int64(len(content))).DoAndReturn(
+			func(_ context.Context, key string, r io.Reader, _ string, _ int64) (string, error) {
+				assert.True(t, strings.HasPrefix(key, "notes/"+noteID.String()+"/"))
+				assert.True(t, strings.HasSuffix(key, ".m4a"))
+				_, err := io.Copy(io.Discard, r)
+				return key, err
+			})
+		storage.EXPECT().GetURL(gomock.Any()).Return("https://example.com/memo.m4a")
+		attachmentRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+		storage.EXPECT().GetSignedURL(gomock.Any(), 24*time.Hour).Return("https://example.com/memo.m4a?signed=xxx", nil)
+
+		result, err := svc.UploadAudio(ctx, upload.AudioUploadInput{
+			UserID:      userID,
+			NoteID:      noteID,
+			File:        bytes.NewReader(content),
+			Filename:    "memo.m4a",
+			ContentType: "audio/m4a",
+			Size:        int64(len(content)),
+			Duration:    42 * time.Second,
+			ClientID:    "memo-1",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, entity.AttachmentKindAudio, result.Attachment.Kind)
+		assert.Equal(t, 42*time.Second, result.Attachment.Duration)
+		assert.Equal(t, hex.EncodeToString(sum[:]), result.Attachment.Checksum)
+		assert.Equal(t, "memo-1", result.Attachment.ClientID)
+		assert.NotEmpty(t, result.SignedURL)
+	})
+
+	t.Run("returns stored attachment for repeated client id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		attachmentRepo := mocks.NewMockAttachmentRepository(ctrl)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		storage := mocks.NewMockImageStorage(ctrl)
+		svc := upload.NewService(nil, attachmentRepo, noteRepo, nil, storage, nil, ownerOnly(ctrl))
+
+		ctx := context.Background()
+		userID := uuid.New()
+		noteID := uuid.New()
+		existing := &entity.Attachment{ID: uuid.New(), NoteID: noteID, Key: "notes/x/memo.m4a", ClientID: "memo-1"}
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(&entity.Note{ID: noteID, UserID: userID}, nil)
+		attachmentRepo.EXPECT().GetByClientID(ctx, userID, "memo-1").Return(existing, nil)
+		storage.EXPECT().GetSignedURL("notes/x/memo.m4a", 24*time.Hour).Return("signed", nil)
+
+		result, err := svc.UploadAudio(ctx, upload.AudioUploadInput{
+			UserID: userID, NoteID: noteID, File: strings.NewReader("audio"), ContentType: "audio/m4a",
+			Duration: time.Second, ClientID: "memo-1",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, existing.ID, result.Attachment.ID)
+	})
+
+	t.Run("rejects a client id used on another note", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		attachmentRepo := mocks.NewMockAttachmentRepository(ctrl)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := upload.NewService(nil, attachmentRepo, noteRepo, nil, mocks.NewMockImageStorage(ctrl), nil, ownerOnly(ctrl))
+
+		ctx := context.Background()
+		userID := uuid.New()
+		noteID := uuid.New()
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(&entity.Note{ID: noteID, UserID: userID}, nil)
+		attachmentRepo.EXPECT().GetByClientID(ctx, userID, "memo-1").Return(&entity.Attachment{NoteID: uuid.New()}, nil)
+
+		_, err := svc.UploadAudio(ctx, upload.AudioUploadInput{
+			UserID: userID, NoteID: noteID, File: strings.NewReader("audio"), ContentType: "audio/m4a",
+			Duration: time.Second, ClientID: "memo-1",
+		})
+
+		assert.ErrorIs(t, err, domain.ErrAttachmentClientIDInUse)
+	})
+
+	t.Run("returns forbidden for non-owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := upload.NewService(nil, mocks.NewMockAttachmentRepository(ctrl), noteRepo, nil, mocks.NewMockImageStorage(ctrl), nil, ownerOnly(ctrl))
+
+		ctx := context.Background()
+		noteID := uuid.New()
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(&entity.Note{ID: noteID, UserID: uuid.New()}, nil)
+
+		_, err := svc.UploadAudio(ctx, upload.AudioUploadInput{
+			UserID: uuid.New(), NoteID: noteID, File: strings.NewReader("audio"), ContentType: "audio/m4a", Duration: time.Second,
+		})
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("cleans up storage on db error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		attachmentRepo := mocks.NewMockAttachmentRepository(ctrl)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		storage := mocks.NewMockImageStorage(ctrl)
+		svc := upload.NewService(nil, attachmentRepo, noteRepo, nil, storage, nil, ownerOnly(ctrl))
+
+		ctx := context.Background()
+		userID := uuid.New()
+		noteID := uuid.New()
+		var storedKey string
+
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(&entity.Note{ID: noteID, UserID: userID}, nil)
+		storage.EXPECT().Upload(ctx, gomock.Any(), gomock.Any(), "audio/mpeg", int64(5)).DoAndReturn(
+			func(_ context.Context, key string, r io.Reader, _ string, _ int64) (string, error) {
+				storedKey = key
+				assert.True(t, strings.HasSuffix(key, ".mp3"))
+				_, err := io.Copy(io.Discard, r)
+				return key, err
+			})
+		storage.EXPECT().GetURL(gomock.Any()).Return("https://example.com/memo.mp3")
+		attachmentRepo.EXPECT().Create(ctx, gomock.Any()).Return(assert.AnError)
+		storage.EXPECT().Delete(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, key string) error {
+			assert.Equal(t, storedKey, key)
+			return nil
+		})
+
+		_, err := svc.UploadAudio(ctx, upload.AudioUploadInput{
+			UserID: userID, NoteID: noteID, File: strings.NewReader("audio"), ContentType: "audio/mpeg", Size: 5, Duration: time.Second,
+		})
+
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestService_DeleteAttachment(t *testing.T) {
+	t.Run("deletes the record and the stored object", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		attachmentRepo := mocks.NewMockAttachmentRepository(ctrl)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		storage := mocks.NewMockImageStorage(ctrl)
+		svc := upload.NewService(nil, attachmentRepo, noteRepo, nil, storage, nil, ownerOnly(ctrl))
+
+		ctx := context.Background()
+		userID := uuid.New()
+		noteID := uuid.New()
+		attachment := &entity.Attachment{ID: uuid.New(), NoteID: noteID, Key: "notes/x/memo.m4a"}
+
+		attachmentRepo.EXPECT().GetByID(ctx, attachment.ID).Return(attachment, nil)
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(&entity.Note{ID: noteID, UserID: userID}, nil)
+		attachmentRepo.EXPECT().Delete(ctx, attachment.ID).Return(nil)
+		storage.EXPECT().Delete(ctx, "notes/x/memo.m4a").Return(nil)
+
+		require.NoError(t, svc.DeleteAttachment(ctx, userID, attachment.ID))
+	})
+
+	t.Run("returns forbidden for non-owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		attachmentRepo := mocks.NewMockAttachmentRepository(ctrl)
+		noteRepo := mocks.NewMockNoteRepository(ctrl)
+		svc := upload.NewService(nil, attachmentRepo, noteRepo, nil, mocks.NewMockImageStorage(ctrl), nil, ownerOnly(ctrl))
+
+		ctx := context.Background()
+		noteID := uuid.New()
+		attachment := &entity.Attachment{ID: uuid.New(), NoteID: noteID}
+
+		attachmentRepo.EXPECT().GetByID(ctx, attachment.ID).Return(attachment, nil)
+		noteRepo.EXPECT().GetByID(ctx, noteID).Return(&entity.Note{ID: noteID, UserID: uuid.New()}, nil)
+
+		assert.ErrorIs(t, svc.DeleteAttachment(ctx, uuid.New(), attachment.ID), domain.ErrForbidden)
+	})
+}
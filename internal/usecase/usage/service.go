@@ -0,0 +1,177 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+)
+
+const defaultUsageWindow = 30 * 24 * time.Hour
+
+// X-Device-ID is client-supplied and only checked against the user's devices
+// on Flush, so the keys a user can add between flushes are capped. Traffic
+// for further device IDs is dropped until the next flush.
+const (
+	maxPendingDevicesPerUser = 20
+	maxDeviceIDLength        = 255
+)
+
+type usageKey struct {
+	userID   uuid.UUID
+	deviceID string
+	day      time.Time
+}
+
+type counters struct {
+	bytesIn  int64
+	bytesOut int64
+}
+
+// Service accumulates per-device traffic in memory and persists it on Flush,
+// so request handling never waits on the database for accounting.
+type Service struct {
+	usageRepo  repository.DeviceUsageRepository
+	deviceRepo repository.DeviceRepository
+
+	mu      sync.Mutex
+	pending map[usageKey]counters
+	// perUser counts the pending keys of each user.
+	perUser map[uuid.UUID]int
+}
+
+func NewService(usageRepo repository.DeviceUsageRepository, deviceRepo repository.DeviceRepository) *Service {
+	return &Service{
+		usageRepo:  usageRepo,
+		deviceRepo: deviceRepo,
+		pending:    make(map[usageKey]counters),
+		perUser:    make(map[uuid.UUID]int),
+	}
+}
+
+// Record adds traffic for the client device of a user to today's totals.
+func (s *Service) Record(userID uuid.UUID, deviceID string, bytesIn, bytesOut int64) {
+	if userID == uuid.Nil || deviceID == "" || len(deviceID) > maxDeviceIDLength || (bytesIn <= 0 && bytesOut <= 0) {
+		return
+	}
+
+	key := usageKey{userID: userID, deviceID: deviceID, day: entity.UsageDay(time.Now())}
+
+	s.mu.Lock()
+	c, ok := s.pending[key]
+	if !ok {
+		if s.perUser[userID] >= maxPendingDevicesPerUser {
+			s.mu.Unlock()
+			return
+		}
+		s.perUser[userID]++
+	}
+	c.bytesIn += max(bytesIn, 0)
+	c.bytesOut += max(bytesOut, 0)
+	s.pending[key] = c
+	s.mu.Unlock()
+}
+
+// Flush writes the accumulated counters. Traffic from devices that were never
+// registered is dropped; on a write failure the counters are kept for the next flush.
+func (s *Service) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	batch := s.pending
+	s.pending = make(map[usageKey]counters)
+	s.perUser = make(map[uuid.UUID]int)
+	s.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	devices := make(map[string]uuid.UUID)
+	usage := make([]entity.DeviceUsage, 0, len(batch))
+	for key, c := range batch {
+		lookup := key.userID.String() + "/" + key.deviceID
+		id, ok := devices[lookup]
+		if !ok {
+			device, err := s.deviceRepo.GetByUserAndDeviceID(ctx, key.userID, key.deviceID)
+			switch {
+			case errors.Is(err, domain.ErrDeviceNotFound):
+			case err != nil:
+				s.requeue(batch)
+				return fmt.Errorf("getting device: %w", err)
+			default:
+				id = device.ID
+			}
+			devices[lookup] = id
+		}
+		if id == uuid.Nil {
+			continue
+		}
+
+		usage = append(usage, entity.DeviceUsage{
+			DeviceID: id,
+			Day:      key.day,
+			BytesIn:  c.bytesIn,
+			BytesOut: c.bytesOut,
+		})
+	}
+
+	if err := s.usageRepo.Increment(ctx, usage); err != nil {
+		s.requeue(batch)
+		return fmt.Errorf("persisting device usage: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) requeue(batch map[usageKey]counters) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, c := range batch {
+		cur, ok := s.pending[key]
+		if !ok {
+			s.perUser[key.userID]++
+		}
+		cur.bytesIn += c.bytesIn
+		cur.bytesOut += c.bytesOut
+		s.pending[key] = cur
+	}
+}
+
+type DeviceUsageInput struct {
+	UserID   uuid.UUID
+	DeviceID string
+	From     *time.Time
+	To       *time.Time
+}
+
+// GetDeviceUsage returns the daily usage of one of the user's devices. The
+// range defaults to the last 30 days.
+func (s *Service) GetDeviceUsage(ctx context.Context, input DeviceUsageInput) ([]entity.DeviceUsage, error) {
+	device, err := s.deviceRepo.GetByUserAndDeviceID(ctx, input.UserID, input.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	to := time.Now().UTC()
+	if input.To != nil {
+		to = *input.To
+	}
+	from := to.Add(-defaultUsageWindow)
+	if input.From != nil {
+		from = *input.From
+	}
+
+	usage, err := s.usageRepo.ListByDevice(ctx, device.ID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("listing device usage: %w", err)
+	}
+
+	return usage, nil
+}
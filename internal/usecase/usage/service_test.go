@@ -0,0 +1,165 @@
+package usage_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
+	"github.com/marcos-nsantos/field-notes-backend/internal/mocks"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/usage"
+)
+
+func TestService_Flush(t *testing.T) {
+	t.Run("aggregates recorded traffic per device", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		usageRepo := mocks.NewMockDeviceUsageRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		svc := usage.NewService(usageRepo, deviceRepo)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		deviceID := uuid.New()
+
+		svc.Record(userID, "device-123", 100, 1000)
+		svc.Record(userID, "device-123", 50, 500)
+
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").
+			Return(&entity.Device{ID: deviceID, UserID: userID, DeviceID: "device-123"}, nil)
+		usageRepo.EXPECT().Increment(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, u []entity.DeviceUsage) error {
+				require.Len(t, u, 1)
+				assert.Equal(t, deviceID, u[0].DeviceID)
+				assert.Equal(t, int64(150), u[0].BytesIn)
+				assert.Equal(t, int64(1500), u[0].BytesOut)
+				return nil
+			})
+
+		require.NoError(t, svc.Flush(ctx))
+
+		// Nothing left to write after a successful flush.
+		require.NoError(t, svc.Flush(ctx))
+	})
+
+	t.Run("drops traffic from unregistered devices", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		usageRepo := mocks.NewMockDeviceUsageRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		svc := usage.NewService(usageRepo, deviceRepo)
+
+		ctx := context.Background()
+		userID := uuid.New()
+
+		svc.Record(userID, "unknown", 10, 10)
+
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "unknown").Return(nil, domain.ErrDeviceNotFound)
+		usageRepo.EXPECT().Increment(ctx, []entity.DeviceUsage{}).Return(nil)
+
+		require.NoError(t, svc.Flush(ctx))
+	})
+
+	t.Run("caps the device IDs recorded per user between flushes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		usageRepo := mocks.NewMockDeviceUsageRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		svc := usage.NewService(usageRepo, deviceRepo)
+
+		ctx := context.Background()
+		userID := uuid.New()
+
+		for i := range 100 {
+			svc.Record(userID, fmt.Sprintf("bogus-%d", i), 10, 10)
+		}
+		svc.Record(userID, strings.Repeat("x", 1000), 10, 10)
+
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, gomock.Any()).Return(nil, domain.ErrDeviceNotFound).Times(20)
+		usageRepo.EXPECT().Increment(ctx, []entity.DeviceUsage{}).Return(nil)
+
+		require.NoError(t, svc.Flush(ctx))
+	})
+
+	t.Run("keeps counters when persisting fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		usageRepo := mocks.NewMockDeviceUsageRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		svc := usage.NewService(usageRepo, deviceRepo)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		device := &entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "device-123"}
+
+		svc.Record(userID, "device-123", 10, 20)
+
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(device, nil).Times(2)
+		usageRepo.EXPECT().Increment(ctx, gomock.Any()).Return(errors.New("db down"))
+		usageRepo.EXPECT().Increment(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, u []entity.DeviceUsage) error {
+				require.Len(t, u, 1)
+				assert.Equal(t, int64(10), u[0].BytesIn)
+				assert.Equal(t, int64(20), u[0].BytesOut)
+				return nil
+			})
+
+		assert.Error(t, svc.Flush(ctx))
+		require.NoError(t, svc.Flush(ctx))
+	})
+}
+
+func TestService_GetDeviceUsage(t *testing.T) {
+	t.Run("returns not found for another user's device", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		usageRepo := mocks.NewMockDeviceUsageRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		svc := usage.NewService(usageRepo, deviceRepo)
+
+		ctx := context.Background()
+		userID := uuid.New()
+
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(nil, domain.ErrDeviceNotFound)
+
+		result, err := svc.GetDeviceUsage(ctx, usage.DeviceUsageInput{UserID: userID, DeviceID: "device-123"})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrDeviceNotFound)
+	})
+
+	t.Run("lists usage for the device", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		usageRepo := mocks.NewMockDeviceUsageRepository(ctrl)
+		deviceRepo := mocks.NewMockDeviceRepository(ctrl)
+		svc := usage.NewService(usageRepo, deviceRepo)
+
+		ctx := context.Background()
+		userID := uuid.New()
+		device := &entity.Device{ID: uuid.New(), UserID: userID, DeviceID: "device-123"}
+		days := []entity.DeviceUsage{{DeviceID: device.ID, BytesIn: 1, BytesOut: 2}}
+
+		deviceRepo.EXPECT().GetByUserAndDeviceID(ctx, userID, "device-123").Return(device, nil)
+		usageRepo.EXPECT().ListByDevice(ctx, device.ID, gomock.Any(), gomock.Any()).Return(days, nil)
+
+		result, err := svc.GetDeviceUsage(ctx, usage.DeviceUsageInput{UserID: userID, DeviceID: "device-123"})
+
+		require.NoError(t, err)
+		assert.Equal(t, days, result)
+	})
+}
@@ -0,0 +1,17 @@
+CREATE TABLE device_cursors (
+    device_id UUID NOT NULL REFERENCES devices(id) ON DELETE CASCADE,
+    stream VARCHAR(50) NOT NULL,
+    cursor TIMESTAMPTZ NOT NULL DEFAULT '1970-01-01 00:00:00+00',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (device_id, stream)
+);
+
+-- Carry the legacy single cursor over as the notes stream so existing devices
+-- don't re-download everything. devices.sync_cursor keeps being written for
+-- older deployments during the rollout.
+INSERT INTO device_cursors (device_id, stream, cursor, updated_at)
+SELECT id, 'notes', sync_cursor, updated_at
+FROM devices
+WHERE sync_cursor > '1970-01-01 00:00:00+00'
+ON CONFLICT (device_id, stream) DO NOTHING;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_notes_user_last_modified_by_device;
+DROP INDEX IF EXISTS idx_notes_user_created_by_device;
+
+ALTER TABLE notes
+    DROP COLUMN IF EXISTS last_modified_by_device,
+    DROP COLUMN IF EXISTS created_by_device;
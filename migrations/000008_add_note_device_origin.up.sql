@@ -0,0 +1,6 @@
+ALTER TABLE notes
+    ADD COLUMN created_by_device VARCHAR(255),
+    ADD COLUMN last_modified_by_device VARCHAR(255);
+
+CREATE INDEX idx_notes_user_created_by_device ON notes(user_id, created_by_device);
+CREATE INDEX idx_notes_user_last_modified_by_device ON notes(user_id, last_modified_by_device);
@@ -0,0 +1,9 @@
+CREATE TABLE device_usage (
+    device_id UUID NOT NULL REFERENCES devices(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    bytes_in BIGINT NOT NULL DEFAULT 0,
+    bytes_out BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (device_id, day)
+);
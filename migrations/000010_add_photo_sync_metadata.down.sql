@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS photo_tombstones;
+
+DROP INDEX IF EXISTS idx_photos_created_at;
+
+ALTER TABLE photos DROP COLUMN IF EXISTS checksum;
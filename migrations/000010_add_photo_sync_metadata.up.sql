@@ -0,0 +1,13 @@
+ALTER TABLE photos ADD COLUMN checksum VARCHAR(64);
+
+CREATE INDEX idx_photos_created_at ON photos(created_at);
+
+-- Photos are hard-deleted; tombstones let clients learn about removals.
+CREATE TABLE photo_tombstones (
+    photo_id UUID PRIMARY KEY,
+    note_id UUID NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    deleted_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_photo_tombstones_user_deleted_at ON photo_tombstones(user_id, deleted_at);
@@ -0,0 +1,3 @@
+ALTER TABLE devices
+    DROP COLUMN IF EXISTS scope_exclude_photos,
+    DROP COLUMN IF EXISTS scope_notes_since;
@@ -0,0 +1,3 @@
+ALTER TABLE devices
+    ADD COLUMN scope_notes_since TIMESTAMPTZ,
+    ADD COLUMN scope_exclude_photos BOOLEAN NOT NULL DEFAULT FALSE;
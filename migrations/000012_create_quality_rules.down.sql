@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_notes_quality_status;
+
+ALTER TABLE notes
+    DROP COLUMN IF EXISTS quality_checked_at,
+    DROP COLUMN IF EXISTS quality_failed,
+    DROP COLUMN IF EXISTS quality_passed,
+    DROP COLUMN IF EXISTS quality_status;
+
+DROP TABLE IF EXISTS quality_rules;
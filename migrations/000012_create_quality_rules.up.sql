@@ -0,0 +1,19 @@
+CREATE TABLE quality_rules (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    require_photo BOOLEAN NOT NULL DEFAULT FALSE,
+    min_content_length INTEGER NOT NULL DEFAULT 0,
+    fence_min_lat DOUBLE PRECISION,
+    fence_max_lat DOUBLE PRECISION,
+    fence_min_lng DOUBLE PRECISION,
+    fence_max_lng DOUBLE PRECISION,
+    max_accuracy DOUBLE PRECISION,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE notes
+    ADD COLUMN quality_status VARCHAR(16) NOT NULL DEFAULT 'unchecked',
+    ADD COLUMN quality_passed TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN quality_failed TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN quality_checked_at TIMESTAMPTZ;
+
+CREATE INDEX idx_notes_quality_status ON notes(user_id, quality_status) WHERE deleted_at IS NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS unit_system;
+
+ALTER TABLE notes
+    DROP COLUMN IF EXISTS measurements;
@@ -0,0 +1,5 @@
+ALTER TABLE notes
+    ADD COLUMN measurements JSONB NOT NULL DEFAULT '[]';
+
+ALTER TABLE users
+    ADD COLUMN unit_system VARCHAR(16) NOT NULL DEFAULT 'metric';
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_notes_user_reference;
+DROP INDEX IF EXISTS idx_notes_user_number;
+
+ALTER TABLE notes
+    DROP COLUMN IF EXISTS reference,
+    DROP COLUMN IF EXISTS number;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS last_note_number,
+    DROP COLUMN IF EXISTS note_prefix;
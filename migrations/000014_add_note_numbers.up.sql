@@ -0,0 +1,28 @@
+ALTER TABLE users
+    ADD COLUMN note_prefix VARCHAR(16) NOT NULL DEFAULT 'NOTE',
+    ADD COLUMN last_note_number BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE notes
+    ADD COLUMN number BIGINT,
+    ADD COLUMN reference VARCHAR(40);
+
+UPDATE notes n
+SET number = s.rn
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY created_at, id) AS rn
+    FROM notes
+) s
+WHERE n.id = s.id;
+
+UPDATE notes
+SET reference = 'NOTE-' || CASE WHEN number < 10000 THEN LPAD(number::text, 4, '0') ELSE number::text END;
+
+UPDATE users u
+SET last_note_number = COALESCE((SELECT MAX(number) FROM notes WHERE user_id = u.id), 0);
+
+ALTER TABLE notes
+    ALTER COLUMN number SET NOT NULL,
+    ALTER COLUMN reference SET NOT NULL;
+
+CREATE UNIQUE INDEX idx_notes_user_number ON notes(user_id, number);
+CREATE INDEX idx_notes_user_reference ON notes(user_id, reference);
@@ -0,0 +1,2 @@
+ALTER TABLE users
+    ADD COLUMN notify_sync_conflicts BOOLEAN NOT NULL DEFAULT TRUE;
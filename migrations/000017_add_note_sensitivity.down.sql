@@ -0,0 +1,2 @@
+ALTER TABLE notes
+    DROP COLUMN IF EXISTS sensitivity;
@@ -0,0 +1,3 @@
+ALTER TABLE notes
+    ADD COLUMN sensitivity VARCHAR(16) NOT NULL DEFAULT 'none'
+        CHECK (sensitivity IN ('none', 'low', 'high'));
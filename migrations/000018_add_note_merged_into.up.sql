@@ -0,0 +1,2 @@
+ALTER TABLE notes
+    ADD COLUMN merged_into UUID REFERENCES notes(id) ON DELETE SET NULL;
@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS user_stats_photo_write ON photos;
+DROP TRIGGER IF EXISTS user_stats_note_delete ON notes;
+DROP TRIGGER IF EXISTS user_stats_note_write ON notes;
+DROP TRIGGER IF EXISTS user_stats_user_insert ON users;
+DROP FUNCTION IF EXISTS user_stats_on_photo();
+DROP FUNCTION IF EXISTS user_stats_on_note();
+DROP FUNCTION IF EXISTS user_stats_on_user();
+DROP FUNCTION IF EXISTS bump_user_stats(UUID, BIGINT, BIGINT, BIGINT);
+DROP TABLE IF EXISTS user_stats;
@@ -0,0 +1,97 @@
+CREATE TABLE user_stats (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    note_count BIGINT NOT NULL DEFAULT 0,
+    photo_count BIGINT NOT NULL DEFAULT 0,
+    storage_bytes BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Only updates existing rows: while a user is being deleted its stats row is
+-- already gone and cascaded deletes must not recreate it.
+CREATE FUNCTION bump_user_stats(p_user_id UUID, p_notes BIGINT, p_photos BIGINT, p_bytes BIGINT)
+RETURNS VOID AS $$
+BEGIN
+    UPDATE user_stats
+    SET note_count = note_count + p_notes,
+        photo_count = photo_count + p_photos,
+        storage_bytes = storage_bytes + p_bytes,
+        updated_at = NOW()
+    WHERE user_id = p_user_id;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION user_stats_on_user() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO user_stats (user_id) VALUES (NEW.id) ON CONFLICT DO NOTHING;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER user_stats_user_insert
+    AFTER INSERT ON users
+    FOR EACH ROW EXECUTE FUNCTION user_stats_on_user();
+
+-- note_count counts notes that are not deleted.
+CREATE FUNCTION user_stats_on_note() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        IF NEW.deleted_at IS NULL THEN
+            PERFORM bump_user_stats(NEW.user_id, 1, 0, 0);
+        END IF;
+        RETURN NEW;
+    ELSIF TG_OP = 'UPDATE' THEN
+        IF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+            PERFORM bump_user_stats(NEW.user_id, -1, 0, 0);
+        ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+            PERFORM bump_user_stats(NEW.user_id, 1, 0, 0);
+        END IF;
+        RETURN NEW;
+    ELSE
+        -- Runs before the delete so the note's photos, removed by the
+        -- cascade once the note is gone, can still be counted here.
+        PERFORM bump_user_stats(
+            OLD.user_id,
+            CASE WHEN OLD.deleted_at IS NULL THEN -1 ELSE 0 END,
+            -(SELECT COUNT(*) FROM photos WHERE note_id = OLD.id),
+            -(SELECT COALESCE(SUM(size), 0) FROM photos WHERE note_id = OLD.id)
+        );
+        RETURN OLD;
+    END IF;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER user_stats_note_write
+    AFTER INSERT OR UPDATE OF deleted_at ON notes
+    FOR EACH ROW EXECUTE FUNCTION user_stats_on_note();
+
+CREATE TRIGGER user_stats_note_delete
+    BEFORE DELETE ON notes
+    FOR EACH ROW EXECUTE FUNCTION user_stats_on_note();
+
+-- Photos count towards the owner of their note. Deletes cascaded from a note
+-- find no note and were already counted by user_stats_note_delete.
+CREATE FUNCTION user_stats_on_photo() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM bump_user_stats(n.user_id, 0, -1, -OLD.size)
+        FROM notes n WHERE n.id = OLD.note_id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        PERFORM bump_user_stats(n.user_id, 0, 1, NEW.size)
+        FROM notes n WHERE n.id = NEW.note_id;
+        RETURN NEW;
+    END IF;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER user_stats_photo_write
+    AFTER INSERT OR UPDATE OF note_id, size OR DELETE ON photos
+    FOR EACH ROW EXECUTE FUNCTION user_stats_on_photo();
+
+INSERT INTO user_stats (user_id, note_count, photo_count, storage_bytes)
+SELECT u.id,
+       (SELECT COUNT(*) FROM notes n WHERE n.user_id = u.id AND n.deleted_at IS NULL),
+       (SELECT COUNT(*) FROM photos p JOIN notes n ON n.id = p.note_id WHERE n.user_id = u.id),
+       (SELECT COALESCE(SUM(p.size), 0) FROM photos p JOIN notes n ON n.id = p.note_id WHERE n.user_id = u.id)
+FROM users u;
@@ -0,0 +1,5 @@
+ALTER TABLE devices
+    DROP COLUMN IF EXISTS last_ip,
+    DROP COLUMN IF EXISTS last_city,
+    DROP COLUMN IF EXISTS last_country,
+    DROP COLUMN IF EXISTS last_access_at;
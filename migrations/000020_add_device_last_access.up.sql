@@ -0,0 +1,5 @@
+ALTER TABLE devices
+    ADD COLUMN last_ip VARCHAR(45),
+    ADD COLUMN last_city VARCHAR(128),
+    ADD COLUMN last_country VARCHAR(64),
+    ADD COLUMN last_access_at TIMESTAMPTZ;
@@ -0,0 +1,27 @@
+CREATE TABLE auth_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device_id UUID NOT NULL REFERENCES devices(id) ON DELETE CASCADE,
+    kind VARCHAR(16) NOT NULL,
+    ip VARCHAR(45) NOT NULL,
+    city VARCHAR(128),
+    country VARCHAR(64),
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_auth_events_created_at ON auth_events(created_at);
+
+CREATE TABLE security_alerts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device_id UUID REFERENCES devices(id) ON DELETE SET NULL,
+    kind VARCHAR(32) NOT NULL,
+    fingerprint VARCHAR(128) NOT NULL,
+    detail TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, kind, fingerprint)
+);
+
+CREATE INDEX idx_security_alerts_created_at ON security_alerts(created_at DESC);
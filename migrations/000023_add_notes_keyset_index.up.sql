@@ -0,0 +1 @@
+CREATE INDEX idx_notes_user_updated_id ON notes(user_id, updated_at DESC, id DESC);
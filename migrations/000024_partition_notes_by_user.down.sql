@@ -0,0 +1,58 @@
+DROP TRIGGER IF EXISTS notes_cascade_delete ON notes;
+DROP FUNCTION IF EXISTS notes_on_delete();
+
+ALTER TABLE notes RENAME TO notes_partitioned;
+ALTER TABLE photos RENAME TO photos_partitioned;
+
+CREATE TABLE notes (LIKE notes_partitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS);
+CREATE TABLE photos (LIKE photos_partitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS);
+
+INSERT INTO notes SELECT * FROM notes_partitioned;
+INSERT INTO photos SELECT * FROM photos_partitioned;
+
+DROP TABLE notes_partitioned;
+DROP TABLE photos_partitioned;
+
+ALTER TABLE notes ADD PRIMARY KEY (id);
+ALTER TABLE notes ADD CONSTRAINT unique_user_client_id UNIQUE (user_id, client_id);
+ALTER TABLE notes ADD CONSTRAINT notes_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE notes ADD CONSTRAINT notes_merged_into_fkey
+    FOREIGN KEY (merged_into) REFERENCES notes(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_notes_user_id ON notes(user_id);
+CREATE INDEX idx_notes_user_updated ON notes(user_id, updated_at);
+CREATE INDEX idx_notes_user_updated_id ON notes(user_id, updated_at DESC, id DESC);
+CREATE INDEX idx_notes_location ON notes USING GIST(location);
+CREATE INDEX idx_notes_client_id ON notes(client_id) WHERE client_id IS NOT NULL;
+CREATE INDEX idx_notes_not_deleted ON notes(user_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_notes_user_created_by_device ON notes(user_id, created_by_device);
+CREATE INDEX idx_notes_user_last_modified_by_device ON notes(user_id, last_modified_by_device);
+CREATE INDEX idx_notes_quality_status ON notes(user_id, quality_status) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_notes_user_number ON notes(user_id, number);
+CREATE INDEX idx_notes_user_reference ON notes(user_id, reference);
+
+ALTER TABLE photos ADD PRIMARY KEY (id);
+ALTER TABLE photos DROP COLUMN user_id;
+ALTER TABLE photos ADD CONSTRAINT photos_note_id_fkey
+    FOREIGN KEY (note_id) REFERENCES notes(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_photos_note_id ON photos(note_id);
+CREATE INDEX idx_photos_created_at ON photos(created_at);
+
+ALTER TABLE note_shares ADD CONSTRAINT note_shares_note_id_fkey
+    FOREIGN KEY (note_id) REFERENCES notes(id) ON DELETE CASCADE;
+ALTER TABLE note_tags ADD CONSTRAINT note_tags_note_id_fkey
+    FOREIGN KEY (note_id) REFERENCES notes(id) ON DELETE CASCADE;
+
+CREATE TRIGGER user_stats_note_write
+    AFTER INSERT OR UPDATE OF deleted_at ON notes
+    FOR EACH ROW EXECUTE FUNCTION user_stats_on_note();
+
+CREATE TRIGGER user_stats_note_delete
+    BEFORE DELETE ON notes
+    FOR EACH ROW EXECUTE FUNCTION user_stats_on_note();
+
+CREATE TRIGGER user_stats_photo_write
+    AFTER INSERT OR UPDATE OF note_id, size OR DELETE ON photos
+    FOR EACH ROW EXECUTE FUNCTION user_stats_on_photo();
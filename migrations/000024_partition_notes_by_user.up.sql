@@ -0,0 +1,98 @@
+-- Notes and photos are hash-partitioned by user_id so vacuum and index
+-- maintenance work on one partition at a time instead of one huge table.
+-- Queries that filter on user_id are pruned to a single partition: listing,
+-- sync, and updates and deletes, which are given the owner of the row they
+-- change. Lookups by ID alone, such as opening a note or photo (the caller
+-- may be a collaborator who does not know the owner) or loading the photos
+-- of a note, cannot be pruned and probe an index in each of the 16
+-- partitions.
+--
+-- A partitioned table can only enforce unique constraints that include the
+-- partition key, so primary keys become (id, user_id) and foreign keys can no
+-- longer reference notes(id). notes_on_delete below keeps the ON DELETE
+-- behaviour those foreign keys had.
+
+ALTER TABLE photos ADD COLUMN user_id UUID;
+UPDATE photos p SET user_id = n.user_id FROM notes n WHERE n.id = p.note_id;
+ALTER TABLE photos ALTER COLUMN user_id SET NOT NULL;
+
+ALTER TABLE photos DROP CONSTRAINT photos_note_id_fkey;
+ALTER TABLE note_shares DROP CONSTRAINT note_shares_note_id_fkey;
+ALTER TABLE note_tags DROP CONSTRAINT note_tags_note_id_fkey;
+ALTER TABLE notes DROP CONSTRAINT notes_merged_into_fkey;
+
+ALTER TABLE notes RENAME TO notes_unpartitioned;
+ALTER TABLE photos RENAME TO photos_unpartitioned;
+
+CREATE TABLE notes (LIKE notes_unpartitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS)
+    PARTITION BY HASH (user_id);
+CREATE TABLE photos (LIKE photos_unpartitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS)
+    PARTITION BY HASH (user_id);
+
+-- 16 partitions each; changing the count later means rewriting the tables.
+DO $$
+BEGIN
+    FOR i IN 0..15 LOOP
+        EXECUTE format('CREATE TABLE notes_p%s PARTITION OF notes FOR VALUES WITH (MODULUS 16, REMAINDER %s)', i, i);
+        EXECUTE format('CREATE TABLE photos_p%s PARTITION OF photos FOR VALUES WITH (MODULUS 16, REMAINDER %s)', i, i);
+    END LOOP;
+END $$;
+
+-- Copied before the triggers exist so user_stats is not counted twice.
+INSERT INTO notes SELECT * FROM notes_unpartitioned;
+INSERT INTO photos SELECT * FROM photos_unpartitioned;
+
+DROP TABLE notes_unpartitioned;
+DROP TABLE photos_unpartitioned;
+
+ALTER TABLE notes ADD PRIMARY KEY (id, user_id);
+ALTER TABLE notes ADD CONSTRAINT unique_user_client_id UNIQUE (user_id, client_id);
+ALTER TABLE notes ADD CONSTRAINT notes_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_notes_user_id ON notes(user_id);
+CREATE INDEX idx_notes_user_updated ON notes(user_id, updated_at);
+CREATE INDEX idx_notes_user_updated_id ON notes(user_id, updated_at DESC, id DESC);
+CREATE INDEX idx_notes_location ON notes USING GIST(location);
+CREATE INDEX idx_notes_client_id ON notes(client_id) WHERE client_id IS NOT NULL;
+CREATE INDEX idx_notes_not_deleted ON notes(user_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_notes_user_created_by_device ON notes(user_id, created_by_device);
+CREATE INDEX idx_notes_user_last_modified_by_device ON notes(user_id, last_modified_by_device);
+CREATE INDEX idx_notes_quality_status ON notes(user_id, quality_status) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_notes_user_number ON notes(user_id, number);
+CREATE INDEX idx_notes_user_reference ON notes(user_id, reference);
+
+ALTER TABLE photos ADD PRIMARY KEY (id, user_id);
+ALTER TABLE photos ADD CONSTRAINT photos_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_photos_note_id ON photos(note_id);
+CREATE INDEX idx_photos_created_at ON photos(created_at);
+
+CREATE TRIGGER user_stats_note_write
+    AFTER INSERT OR UPDATE OF deleted_at ON notes
+    FOR EACH ROW EXECUTE FUNCTION user_stats_on_note();
+
+CREATE TRIGGER user_stats_note_delete
+    BEFORE DELETE ON notes
+    FOR EACH ROW EXECUTE FUNCTION user_stats_on_note();
+
+CREATE TRIGGER user_stats_photo_write
+    AFTER INSERT OR UPDATE OF note_id, size OR DELETE ON photos
+    FOR EACH ROW EXECUTE FUNCTION user_stats_on_photo();
+
+-- Stands in for the foreign keys to notes(id). Photos and merged notes always
+-- belong to the deleted note's owner, so those deletes stay in one partition.
+CREATE FUNCTION notes_on_delete() RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM photos WHERE user_id = OLD.user_id AND note_id = OLD.id;
+    DELETE FROM note_shares WHERE note_id = OLD.id;
+    DELETE FROM note_tags WHERE note_id = OLD.id;
+    UPDATE notes SET merged_into = NULL WHERE user_id = OLD.user_id AND merged_into = OLD.id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notes_cascade_delete
+    AFTER DELETE ON notes
+    FOR EACH ROW EXECUTE FUNCTION notes_on_delete();
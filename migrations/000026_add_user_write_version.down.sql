@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS photos_write_version ON photos;
+DROP TRIGGER IF EXISTS notes_write_version ON notes;
+DROP FUNCTION IF EXISTS bump_write_version();
+ALTER TABLE users DROP COLUMN IF EXISTS write_version;
@@ -0,0 +1,23 @@
+-- Logical clock of each user's writes. Replicas carry it along with the rows
+-- it covers, so comparing a replica's value with the one a client last saw
+-- tells whether that replica already shows the client's writes.
+ALTER TABLE users ADD COLUMN write_version BIGINT NOT NULL DEFAULT 0;
+
+CREATE FUNCTION bump_write_version() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE users SET write_version = write_version + 1 WHERE id = OLD.user_id;
+    ELSE
+        UPDATE users SET write_version = write_version + 1 WHERE id = NEW.user_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notes_write_version
+    AFTER INSERT OR UPDATE OR DELETE ON notes
+    FOR EACH ROW EXECUTE FUNCTION bump_write_version();
+
+CREATE TRIGGER photos_write_version
+    AFTER INSERT OR UPDATE OR DELETE ON photos
+    FOR EACH ROW EXECUTE FUNCTION bump_write_version();
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_photo_tombstones_user_client_id;
+ALTER TABLE photo_tombstones DROP COLUMN IF EXISTS client_id;
+
+DROP INDEX IF EXISTS idx_photos_user_client_id;
+ALTER TABLE photos DROP COLUMN IF EXISTS client_id;
@@ -0,0 +1,11 @@
+-- Client-generated photo IDs let devices reconcile offline captures with
+-- what the server already has. They are unique per note owner.
+ALTER TABLE photos ADD COLUMN client_id VARCHAR(36);
+
+CREATE UNIQUE INDEX idx_photos_user_client_id ON photos(user_id, client_id)
+    WHERE client_id IS NOT NULL;
+
+ALTER TABLE photo_tombstones ADD COLUMN client_id VARCHAR(36);
+
+CREATE INDEX idx_photo_tombstones_user_client_id ON photo_tombstones(user_id, client_id)
+    WHERE client_id IS NOT NULL;
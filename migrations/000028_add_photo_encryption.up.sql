@@ -0,0 +1,3 @@
+-- Server-side encryption reported by S3 for each stored photo, kept for
+-- compliance reporting. NULL for photos uploaded before it was recorded.
+ALTER TABLE photos ADD COLUMN encryption VARCHAR(20);
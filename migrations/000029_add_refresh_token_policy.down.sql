@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_refresh_tokens_user_platform;
+
+ALTER TABLE refresh_tokens
+    DROP COLUMN IF EXISTS platform,
+    DROP COLUMN IF EXISTS access_ttl_seconds,
+    DROP COLUMN IF EXISTS refresh_ttl_seconds,
+    DROP COLUMN IF EXISTS max_sessions;
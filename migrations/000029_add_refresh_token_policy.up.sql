@@ -0,0 +1,10 @@
+-- The platform and session policy each refresh token was issued under, for
+-- auditing. NULL on tokens issued before policies were recorded.
+ALTER TABLE refresh_tokens
+    ADD COLUMN platform VARCHAR(20),
+    ADD COLUMN access_ttl_seconds INTEGER,
+    ADD COLUMN refresh_ttl_seconds INTEGER,
+    ADD COLUMN max_sessions INTEGER;
+
+CREATE INDEX idx_refresh_tokens_user_platform ON refresh_tokens(user_id, platform, created_at)
+    WHERE revoked_at IS NULL;
@@ -0,0 +1,16 @@
+DROP TRIGGER notes_cascade_delete ON notes;
+DROP FUNCTION notes_on_delete();
+
+CREATE FUNCTION notes_on_delete() RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM photos WHERE user_id = OLD.user_id AND note_id = OLD.id;
+    DELETE FROM note_shares WHERE note_id = OLD.id;
+    DELETE FROM note_tags WHERE note_id = OLD.id;
+    UPDATE notes SET merged_into = NULL WHERE user_id = OLD.user_id AND merged_into = OLD.id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notes_cascade_delete
+    AFTER DELETE ON notes
+    FOR EACH ROW EXECUTE FUNCTION notes_on_delete();
@@ -0,0 +1,22 @@
+-- notes_on_delete ran its cleanup once per deleted note, so purging an
+-- account issued four statements per note. As a statement-level trigger it
+-- cleans up every note a DELETE removed in one pass.
+DROP TRIGGER notes_cascade_delete ON notes;
+DROP FUNCTION notes_on_delete();
+
+CREATE FUNCTION notes_on_delete() RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM photos p USING deleted_notes d
+        WHERE p.user_id = d.user_id AND p.note_id = d.id;
+    DELETE FROM note_shares s USING deleted_notes d WHERE s.note_id = d.id;
+    DELETE FROM note_tags t USING deleted_notes d WHERE t.note_id = d.id;
+    UPDATE notes n SET merged_into = NULL FROM deleted_notes d
+        WHERE n.user_id = d.user_id AND n.merged_into = d.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notes_cascade_delete
+    AFTER DELETE ON notes
+    REFERENCING OLD TABLE AS deleted_notes
+    FOR EACH STATEMENT EXECUTE FUNCTION notes_on_delete();
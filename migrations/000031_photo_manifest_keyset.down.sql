@@ -0,0 +1,16 @@
+CREATE OR REPLACE FUNCTION notes_on_delete() RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM photos p USING deleted_notes d
+        WHERE p.user_id = d.user_id AND p.note_id = d.id;
+    DELETE FROM note_shares s USING deleted_notes d WHERE s.note_id = d.id;
+    DELETE FROM note_tags t USING deleted_notes d WHERE t.note_id = d.id;
+    UPDATE notes n SET merged_into = NULL FROM deleted_notes d
+        WHERE n.user_id = d.user_id AND n.merged_into = d.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS idx_photo_tombstones_user_deleted_id;
+CREATE INDEX idx_photo_tombstones_user_deleted_at ON photo_tombstones(user_id, deleted_at);
+
+DROP INDEX IF EXISTS idx_photos_user_created_id;
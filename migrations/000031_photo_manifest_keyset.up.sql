@@ -0,0 +1,27 @@
+-- The photo manifest pages on (time, id) so photos and tombstones sharing a
+-- timestamp, such as every photo removed with a note, are never skipped.
+CREATE INDEX idx_photos_user_created_id ON photos(user_id, created_at, id);
+
+DROP INDEX idx_photo_tombstones_user_deleted_at;
+CREATE INDEX idx_photo_tombstones_user_deleted_id ON photo_tombstones(user_id, deleted_at, photo_id);
+
+-- Photos removed with a hard-deleted note get tombstones too, so devices
+-- drop them. Nothing is recorded when the whole account is being deleted.
+CREATE OR REPLACE FUNCTION notes_on_delete() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO photo_tombstones (photo_id, note_id, user_id, client_id, deleted_at)
+    SELECT p.id, p.note_id, p.user_id, p.client_id, NOW()
+    FROM photos p
+    JOIN deleted_notes d ON d.user_id = p.user_id AND d.id = p.note_id
+    WHERE EXISTS (SELECT 1 FROM users u WHERE u.id = d.user_id)
+    ON CONFLICT (photo_id) DO NOTHING;
+
+    DELETE FROM photos p USING deleted_notes d
+        WHERE p.user_id = d.user_id AND p.note_id = d.id;
+    DELETE FROM note_shares s USING deleted_notes d WHERE s.note_id = d.id;
+    DELETE FROM note_tags t USING deleted_notes d WHERE t.note_id = d.id;
+    UPDATE notes n SET merged_into = NULL FROM deleted_notes d
+        WHERE n.user_id = d.user_id AND n.merged_into = d.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
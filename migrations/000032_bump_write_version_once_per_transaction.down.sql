@@ -0,0 +1,10 @@
+CREATE OR REPLACE FUNCTION bump_write_version() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE users SET write_version = write_version + 1 WHERE id = OLD.user_id;
+    ELSE
+        UPDATE users SET write_version = write_version + 1 WHERE id = NEW.user_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
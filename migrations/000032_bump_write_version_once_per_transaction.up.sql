@@ -0,0 +1,26 @@
+-- bump_write_version updated the users row once per written note or photo,
+-- so a 1000-note sync rewrote the same row 1000 times in one transaction.
+-- BatchUpsert writes one note per statement, so a statement-level trigger
+-- would not help; instead each user is bumped once per transaction. The
+-- users already bumped are kept in a transaction-local setting.
+CREATE OR REPLACE FUNCTION bump_write_version() RETURNS TRIGGER AS $$
+DECLARE
+    uid TEXT;
+    bumped TEXT;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        uid := OLD.user_id::text;
+    ELSE
+        uid := NEW.user_id::text;
+    END IF;
+
+    bumped := coalesce(current_setting('field_notes.write_version_bumped', true), '');
+    IF position(uid IN bumped) > 0 THEN
+        RETURN NULL;
+    END IF;
+
+    UPDATE users SET write_version = write_version + 1 WHERE id = uid::uuid;
+    PERFORM set_config('field_notes.write_version_bumped', bumped || uid || ',', true);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1 @@
+ALTER TABLE organizations ALTER COLUMN oidc_client_secret TYPE VARCHAR(512);
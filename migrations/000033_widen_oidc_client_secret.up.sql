@@ -0,0 +1,4 @@
+-- OIDC client secrets are now stored encrypted (versioned, base64-encoded
+-- AES-GCM), which is longer than the plaintext. Existing plaintext secrets
+-- keep working and are encrypted the next time an admin updates them.
+ALTER TABLE organizations ALTER COLUMN oidc_client_secret TYPE TEXT;
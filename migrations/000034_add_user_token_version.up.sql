@@ -0,0 +1,4 @@
+-- Generation of the user's access tokens. Tokens carry the version they were
+-- issued under and are rejected once it falls behind this value, so bumping
+-- it invalidates every outstanding access token at once.
+ALTER TABLE users ADD COLUMN token_version INTEGER NOT NULL DEFAULT 0;
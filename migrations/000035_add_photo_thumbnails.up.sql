@@ -0,0 +1,3 @@
+-- Thumbnails generated on upload, keyed by size ("small", "medium"), each
+-- with its storage key, URL and dimensions. Older photos have none.
+ALTER TABLE photos ADD COLUMN thumbnails JSONB NOT NULL DEFAULT '{}';
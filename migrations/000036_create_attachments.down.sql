@@ -0,0 +1,13 @@
+CREATE OR REPLACE FUNCTION notes_on_delete() RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM photos p USING deleted_notes d
+        WHERE p.user_id = d.user_id AND p.note_id = d.id;
+    DELETE FROM note_shares s USING deleted_notes d WHERE s.note_id = d.id;
+    DELETE FROM note_tags t USING deleted_notes d WHERE t.note_id = d.id;
+    UPDATE notes n SET merged_into = NULL FROM deleted_notes d
+        WHERE n.user_id = d.user_id AND n.merged_into = d.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TABLE IF EXISTS attachments;
@@ -0,0 +1,34 @@
+-- Non-image media of a note, such as voice memos. Like photos, rows carry the
+-- note owner's user_id and are removed by notes_on_delete, since notes(id)
+-- cannot be referenced by a foreign key.
+CREATE TABLE attachments (
+    id UUID PRIMARY KEY,
+    note_id UUID NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(16) NOT NULL,
+    url TEXT NOT NULL,
+    key TEXT NOT NULL,
+    mime_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    checksum VARCHAR(64),
+    client_id VARCHAR(36),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_attachments_note_id ON attachments(note_id);
+CREATE UNIQUE INDEX idx_attachments_user_client_id ON attachments(user_id, client_id) WHERE client_id IS NOT NULL;
+
+CREATE OR REPLACE FUNCTION notes_on_delete() RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM photos p USING deleted_notes d
+        WHERE p.user_id = d.user_id AND p.note_id = d.id;
+    DELETE FROM attachments a USING deleted_notes d
+        WHERE a.user_id = d.user_id AND a.note_id = d.id;
+    DELETE FROM note_shares s USING deleted_notes d WHERE s.note_id = d.id;
+    DELETE FROM note_tags t USING deleted_notes d WHERE t.note_id = d.id;
+    UPDATE notes n SET merged_into = NULL FROM deleted_notes d
+        WHERE n.user_id = d.user_id AND n.merged_into = d.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
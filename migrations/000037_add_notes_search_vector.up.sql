@@ -0,0 +1,9 @@
+-- Full-text search over title and content. The 'simple' configuration does
+-- no stemming or stop words, since notes are written in several languages.
+-- Title matches weigh more than content matches.
+ALTER TABLE notes ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(content, '')), 'B')
+) STORED;
+
+CREATE INDEX idx_notes_search_vector ON notes USING GIN(search_vector);
@@ -0,0 +1,2 @@
+ALTER TABLE photos DROP COLUMN IF EXISTS location;
+ALTER TABLE photos DROP COLUMN IF EXISTS taken_at;
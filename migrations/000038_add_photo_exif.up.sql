@@ -0,0 +1,4 @@
+-- Capture time and position read from the photo's EXIF data on upload.
+-- Photos without EXIF, and those uploaded before it was read, have neither.
+ALTER TABLE photos ADD COLUMN taken_at TIMESTAMPTZ;
+ALTER TABLE photos ADD COLUMN location GEOGRAPHY(Point, 4326);
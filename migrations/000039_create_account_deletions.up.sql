@@ -0,0 +1,14 @@
+-- A locked account can no longer log in and is waiting to be purged.
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMPTZ;
+
+-- Audit trail of account deletions. user_id has no foreign key: the row
+-- outlives the user it records.
+CREATE TABLE account_deletions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL UNIQUE,
+    requested_at TIMESTAMPTZ NOT NULL,
+    purged_at TIMESTAMPTZ,
+    objects_deleted INT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_account_deletions_pending ON account_deletions(requested_at) WHERE purged_at IS NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE notes DROP COLUMN IF EXISTS content_url;
+ALTER TABLE notes DROP COLUMN IF EXISTS content_key;
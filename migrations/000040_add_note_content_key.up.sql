@@ -0,0 +1,5 @@
+-- Content longer than the offload threshold is stored as an object; the row
+-- keeps an excerpt in content (so search only covers the excerpt) and the
+-- object's key and URL.
+ALTER TABLE notes ADD COLUMN content_key TEXT;
+ALTER TABLE notes ADD COLUMN content_url TEXT;
@@ -0,0 +1,37 @@
+-- Client versions held back by the manual conflict strategy. A device has
+-- at most one pending conflict per note; pushing the note again replaces
+-- the stored client version. notes(id) cannot be referenced by a foreign
+-- key, so rows are removed with their note by notes_on_delete.
+CREATE TABLE sync_conflicts (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    note_id UUID NOT NULL,
+    client_id VARCHAR(36) NOT NULL,
+    device_id VARCHAR(255) NOT NULL,
+    client_version JSONB NOT NULL,
+    server_updated_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    resolution VARCHAR(10),
+    resolved_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_sync_conflicts_pending ON sync_conflicts(note_id, device_id) WHERE resolved_at IS NULL;
+CREATE INDEX idx_sync_conflicts_user ON sync_conflicts(user_id, created_at);
+CREATE INDEX idx_sync_conflicts_expires_at ON sync_conflicts(expires_at);
+
+-- Pending conflicts go with their note, like its other rows.
+CREATE OR REPLACE FUNCTION notes_on_delete() RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM photos p USING deleted_notes d
+        WHERE p.user_id = d.user_id AND p.note_id = d.id;
+    DELETE FROM attachments a USING deleted_notes d
+        WHERE a.user_id = d.user_id AND a.note_id = d.id;
+    DELETE FROM note_shares s USING deleted_notes d WHERE s.note_id = d.id;
+    DELETE FROM note_tags t USING deleted_notes d WHERE t.note_id = d.id;
+    DELETE FROM sync_conflicts c USING deleted_notes d WHERE c.note_id = d.id;
+    UPDATE notes n SET merged_into = NULL FROM deleted_notes d
+        WHERE n.user_id = d.user_id AND n.merged_into = d.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,12 @@
+-- Accounts at social identity providers (Google, Apple) linked to users.
+CREATE TABLE auth_providers (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(20) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX idx_auth_providers_user_id ON auth_providers(user_id);
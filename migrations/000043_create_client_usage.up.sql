@@ -0,0 +1,12 @@
+-- Daily request counts per client app version, platform and OS, for
+-- deciding when old app versions can stop being supported. No user or
+-- device identifiers are kept.
+CREATE TABLE client_usage (
+    day DATE NOT NULL,
+    platform VARCHAR(20) NOT NULL,
+    app_version VARCHAR(20) NOT NULL,
+    os VARCHAR(20) NOT NULL,
+    requests BIGINT NOT NULL DEFAULT 0,
+    devices BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, platform, app_version, os)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE notes
+    DROP COLUMN source_meta,
+    DROP COLUMN source;
@@ -0,0 +1,7 @@
+-- How each note was created: manual, sync, import or integration:<name>,
+-- with optional details such as the imported file in source_meta. Notes
+-- created before sources were recorded can't be told apart and count as
+-- manual.
+ALTER TABLE notes
+    ADD COLUMN source VARCHAR(52) NOT NULL DEFAULT 'manual',
+    ADD COLUMN source_meta JSONB;
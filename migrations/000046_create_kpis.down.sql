@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS daily_kpis;
+DROP TABLE IF EXISTS kpi_events;
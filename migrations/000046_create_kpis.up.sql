@@ -0,0 +1,23 @@
+-- Daily counts of product events, added to by every instance as it flushes
+-- its in-memory counters.
+CREATE TABLE kpi_events (
+    day DATE NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    count BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, name)
+);
+
+-- The KPIs of each day as last computed by the kpi-compute job, for
+-- dashboards that shouldn't query the tables behind them.
+CREATE TABLE daily_kpis (
+    day DATE PRIMARY KEY,
+    active_users BIGINT NOT NULL DEFAULT 0,
+    notes_created BIGINT NOT NULL DEFAULT 0,
+    syncs BIGINT NOT NULL DEFAULT 0,
+    sync_failures BIGINT NOT NULL DEFAULT 0,
+    notes_pushed BIGINT NOT NULL DEFAULT 0,
+    conflicts BIGINT NOT NULL DEFAULT 0,
+    photo_uploads BIGINT NOT NULL DEFAULT 0,
+    photo_upload_failures BIGINT NOT NULL DEFAULT 0,
+    computed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
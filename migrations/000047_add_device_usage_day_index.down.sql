@@ -0,0 +1 @@
+DROP INDEX CONCURRENTLY IF EXISTS idx_device_usage_day;
@@ -0,0 +1,2 @@
+-- Daily active users are counted from one day of device_usage.
+CREATE INDEX CONCURRENTLY idx_device_usage_day ON device_usage(day);
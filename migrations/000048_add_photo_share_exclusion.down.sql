@@ -0,0 +1 @@
+ALTER TABLE photos DROP COLUMN IF EXISTS excluded_from_shares;
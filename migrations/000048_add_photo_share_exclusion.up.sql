@@ -0,0 +1,3 @@
+-- Photos the owner keeps out of shares: other users with access to the note
+-- don't see them.
+ALTER TABLE photos ADD COLUMN excluded_from_shares BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_notes_team_updated_id;
+ALTER TABLE notes DROP COLUMN IF EXISTS team_id;
+DROP TABLE IF EXISTS team_members;
+DROP TABLE IF EXISTS teams;
@@ -0,0 +1,30 @@
+-- Teams are groups users create themselves to share field projects, unlike
+-- organizations, which mirror an identity provider. Members join by
+-- accepting an invitation; until then accepted_at is NULL.
+CREATE TABLE teams (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE team_members (
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(16) NOT NULL CHECK (role IN ('owner', 'admin', 'member')),
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    accepted_at TIMESTAMPTZ,
+
+    PRIMARY KEY (team_id, user_id)
+);
+
+CREATE INDEX idx_team_members_user_id ON team_members(user_id);
+
+-- Notes owned by a team. user_id stays the member who created the note, as
+-- the partition key.
+ALTER TABLE notes ADD COLUMN team_id UUID REFERENCES teams(id);
+
+-- schemacheck:ignore index-not-concurrent (partitioned tables can't be indexed concurrently; the column was just added and is empty)
+CREATE INDEX idx_notes_team_updated_id ON notes(team_id, updated_at DESC, id DESC) WHERE team_id IS NOT NULL;
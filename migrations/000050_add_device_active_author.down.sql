@@ -0,0 +1,4 @@
+ALTER TABLE notes DROP COLUMN IF EXISTS author;
+
+ALTER TABLE devices DROP COLUMN IF EXISTS active_author_since;
+ALTER TABLE devices DROP COLUMN IF EXISTS active_author;
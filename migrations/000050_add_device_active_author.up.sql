@@ -0,0 +1,6 @@
+-- The person currently using a shared device, stamped onto the notes it
+-- creates until changed. It is a label, not an account.
+ALTER TABLE devices ADD COLUMN active_author VARCHAR(64);
+ALTER TABLE devices ADD COLUMN active_author_since TIMESTAMPTZ;
+
+ALTER TABLE notes ADD COLUMN author VARCHAR(64);
@@ -0,0 +1,7 @@
+ALTER TABLE team_members DROP CONSTRAINT team_members_role_check;
+
+-- Viewers regain edit access; the role did not exist before.
+UPDATE team_members SET role = 'member' WHERE role IN ('editor', 'viewer');
+
+ALTER TABLE team_members ADD CONSTRAINT team_members_role_check
+    CHECK (role IN ('owner', 'admin', 'member'));
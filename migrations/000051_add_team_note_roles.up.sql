@@ -0,0 +1,8 @@
+-- Team members become editors or viewers of the team's notes. Existing
+-- members could edit, so they become editors.
+ALTER TABLE team_members DROP CONSTRAINT team_members_role_check;
+
+UPDATE team_members SET role = 'editor' WHERE role = 'member';
+
+ALTER TABLE team_members ADD CONSTRAINT team_members_role_check
+    CHECK (role IN ('owner', 'admin', 'editor', 'viewer'));
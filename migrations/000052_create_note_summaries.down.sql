@@ -0,0 +1,13 @@
+DROP TRIGGER IF EXISTS note_summaries_photo_delete ON photos;
+DROP TRIGGER IF EXISTS note_summaries_photo_update ON photos;
+DROP TRIGGER IF EXISTS note_summaries_photo_insert ON photos;
+DROP TRIGGER IF EXISTS note_summaries_tag_delete ON note_tags;
+DROP TRIGGER IF EXISTS note_summaries_tag_insert ON note_tags;
+DROP TRIGGER IF EXISTS note_summaries_note_delete ON notes;
+DROP TRIGGER IF EXISTS note_summaries_note_update ON notes;
+DROP TRIGGER IF EXISTS note_summaries_note_insert ON notes;
+DROP FUNCTION IF EXISTS note_summaries_on_child();
+DROP FUNCTION IF EXISTS note_summaries_on_note();
+DROP FUNCTION IF EXISTS refresh_note_summaries(UUID[]);
+DROP VIEW IF EXISTS note_summary_rows;
+DROP TABLE IF EXISTS note_summaries;
@@ -0,0 +1,141 @@
+-- note_summaries is a read-optimized copy of what note lists and maps show:
+-- no content beyond an excerpt and no joins to tags or photos. Triggers on
+-- notes, note_tags and photos keep it current; deleted notes have no row.
+-- The note-summaries-rebuild job refills it from scratch.
+CREATE TABLE note_summaries (
+    note_id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    team_id UUID,
+    title VARCHAR(255) NOT NULL,
+    excerpt TEXT NOT NULL,
+    -- The first photo that is not encrypted or kept out of shares, so every
+    -- user with access to the note sees the same cover.
+    cover_url TEXT,
+    location GEOGRAPHY(POINT, 4326),
+    sensitivity VARCHAR(16) NOT NULL,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    photo_count INT NOT NULL DEFAULT 0,
+    -- Photos other users with access to the note can see.
+    shared_photo_count INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_note_summaries_user_updated_id ON note_summaries(user_id, updated_at DESC, note_id DESC);
+CREATE INDEX idx_note_summaries_team_updated_id ON note_summaries(team_id, updated_at DESC, note_id DESC) WHERE team_id IS NOT NULL;
+CREATE INDEX idx_note_summaries_location ON note_summaries USING GIST(location);
+
+-- note_summary_rows computes the summary rows from the source tables; the
+-- triggers and the rebuild job both fill note_summaries from it.
+CREATE VIEW note_summary_rows AS
+SELECT n.id AS note_id, n.user_id, n.team_id, n.title,
+       left(n.content, 280) AS excerpt,
+       (SELECT COALESCE(p.thumbnails->'small'->>'url', p.url)
+        FROM photos p
+        WHERE p.user_id = n.user_id AND p.note_id = n.id
+          AND p.encryption IS NULL AND NOT p.excluded_from_shares
+        ORDER BY p.created_at, p.id
+        LIMIT 1) AS cover_url,
+       n.location, n.sensitivity,
+       COALESCE((SELECT array_agg(t.name ORDER BY t.name)
+                 FROM note_tags nt JOIN tags t ON t.id = nt.tag_id
+                 WHERE nt.note_id = n.id), '{}') AS tags,
+       (SELECT COUNT(*) FROM photos p WHERE p.user_id = n.user_id AND p.note_id = n.id)::INT AS photo_count,
+       (SELECT COUNT(*) FROM photos p
+        WHERE p.user_id = n.user_id AND p.note_id = n.id AND NOT p.excluded_from_shares)::INT AS shared_photo_count,
+       n.created_at, n.updated_at
+FROM notes n
+WHERE n.deleted_at IS NULL;
+
+-- Upserts rather than deleting and reinserting, so two transactions
+-- refreshing the same note don't collide on the primary key.
+CREATE FUNCTION refresh_note_summaries(p_note_ids UUID[]) RETURNS VOID AS $$
+BEGIN
+    INSERT INTO note_summaries
+    SELECT * FROM note_summary_rows WHERE note_id = ANY(p_note_ids)
+    ON CONFLICT (note_id) DO UPDATE SET
+        user_id = EXCLUDED.user_id,
+        team_id = EXCLUDED.team_id,
+        title = EXCLUDED.title,
+        excerpt = EXCLUDED.excerpt,
+        cover_url = EXCLUDED.cover_url,
+        location = EXCLUDED.location,
+        sensitivity = EXCLUDED.sensitivity,
+        tags = EXCLUDED.tags,
+        photo_count = EXCLUDED.photo_count,
+        shared_photo_count = EXCLUDED.shared_photo_count,
+        created_at = EXCLUDED.created_at,
+        updated_at = EXCLUDED.updated_at;
+
+    DELETE FROM note_summaries s
+    WHERE s.note_id = ANY(p_note_ids)
+      AND NOT EXISTS (SELECT 1 FROM note_summary_rows r WHERE r.note_id = s.note_id);
+END;
+$$ LANGUAGE plpgsql;
+
+-- Statement-level, so a sync batch or a tag replacement refreshes each
+-- note once per statement rather than once per row.
+CREATE FUNCTION note_summaries_on_note() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM refresh_note_summaries(ARRAY(SELECT id FROM changed_notes));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER note_summaries_note_insert
+    AFTER INSERT ON notes
+    REFERENCING NEW TABLE AS changed_notes
+    FOR EACH STATEMENT EXECUTE FUNCTION note_summaries_on_note();
+
+CREATE TRIGGER note_summaries_note_update
+    AFTER UPDATE ON notes
+    REFERENCING NEW TABLE AS changed_notes
+    FOR EACH STATEMENT EXECUTE FUNCTION note_summaries_on_note();
+
+CREATE TRIGGER note_summaries_note_delete
+    AFTER DELETE ON notes
+    REFERENCING OLD TABLE AS changed_notes
+    FOR EACH STATEMENT EXECUTE FUNCTION note_summaries_on_note();
+
+-- Tag and photo changes refresh the notes they belong to, before and after
+-- the change, since a photo can move to another note on merge.
+CREATE FUNCTION note_summaries_on_child() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        PERFORM refresh_note_summaries(ARRAY(SELECT DISTINCT note_id FROM new_rows));
+    ELSIF TG_OP = 'DELETE' THEN
+        PERFORM refresh_note_summaries(ARRAY(SELECT DISTINCT note_id FROM old_rows));
+    ELSE
+        PERFORM refresh_note_summaries(ARRAY(
+            SELECT note_id FROM old_rows UNION SELECT note_id FROM new_rows));
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER note_summaries_tag_insert
+    AFTER INSERT ON note_tags
+    REFERENCING NEW TABLE AS new_rows
+    FOR EACH STATEMENT EXECUTE FUNCTION note_summaries_on_child();
+
+CREATE TRIGGER note_summaries_tag_delete
+    AFTER DELETE ON note_tags
+    REFERENCING OLD TABLE AS old_rows
+    FOR EACH STATEMENT EXECUTE FUNCTION note_summaries_on_child();
+
+CREATE TRIGGER note_summaries_photo_insert
+    AFTER INSERT ON photos
+    REFERENCING NEW TABLE AS new_rows
+    FOR EACH STATEMENT EXECUTE FUNCTION note_summaries_on_child();
+
+CREATE TRIGGER note_summaries_photo_update
+    AFTER UPDATE ON photos
+    REFERENCING OLD TABLE AS old_rows NEW TABLE AS new_rows
+    FOR EACH STATEMENT EXECUTE FUNCTION note_summaries_on_child();
+
+CREATE TRIGGER note_summaries_photo_delete
+    AFTER DELETE ON photos
+    REFERENCING OLD TABLE AS old_rows
+    FOR EACH STATEMENT EXECUTE FUNCTION note_summaries_on_child();
+
+INSERT INTO note_summaries SELECT * FROM note_summary_rows;
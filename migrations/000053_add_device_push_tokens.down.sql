@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_devices_push_token;
+
+ALTER TABLE devices DROP COLUMN IF EXISTS push_token_updated_at;
+ALTER TABLE devices DROP COLUMN IF EXISTS push_token;
@@ -0,0 +1,6 @@
+-- The FCM or APNs token a device's app registered for silent pushes. A token
+-- identifies one app install, so it belongs to at most one device row.
+ALTER TABLE devices ADD COLUMN push_token TEXT;
+ALTER TABLE devices ADD COLUMN push_token_updated_at TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX idx_devices_push_token ON devices (push_token) WHERE push_token IS NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE notes DROP COLUMN IF EXISTS synced_updated_at;
+ALTER TABLE notes DROP COLUMN IF EXISTS synced_by_device;
@@ -0,0 +1,5 @@
+-- The device (devices.id) whose sync last wrote the note, and the updated_at
+-- it wrote. Every other write moves updated_at, so while the two match the
+-- note is unchanged since that device pushed it and need not be sent back.
+ALTER TABLE notes ADD COLUMN synced_by_device UUID;
+ALTER TABLE notes ADD COLUMN synced_updated_at TIMESTAMPTZ;
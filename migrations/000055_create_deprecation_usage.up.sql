@@ -0,0 +1,11 @@
+-- Daily request counts per deprecated endpoint or field and app version, so
+-- a deprecation can be removed once no supported version uses it. No user
+-- or device identifiers are kept.
+CREATE TABLE deprecation_usage (
+    day DATE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    platform VARCHAR(20) NOT NULL,
+    app_version VARCHAR(20) NOT NULL,
+    requests BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, name, platform, app_version)
+);
@@ -0,0 +1,15 @@
+-- Security-relevant account activity, for users to review. Rows go with the
+-- account; device_id is cleared when the device is removed.
+CREATE TABLE audit_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device_id UUID REFERENCES devices(id) ON DELETE SET NULL,
+    action VARCHAR(32) NOT NULL,
+    target VARCHAR(255) NOT NULL DEFAULT '',
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_events_user_created ON audit_events(user_id, created_at DESC);
+CREATE INDEX idx_audit_events_created_at ON audit_events(created_at);
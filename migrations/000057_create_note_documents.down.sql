@@ -0,0 +1,42 @@
+CREATE OR REPLACE FUNCTION notes_on_delete() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO photo_tombstones (photo_id, note_id, user_id, client_id, deleted_at)
+    SELECT p.id, p.note_id, p.user_id, p.client_id, NOW()
+    FROM photos p
+    JOIN deleted_notes d ON d.user_id = p.user_id AND d.id = p.note_id
+    WHERE EXISTS (SELECT 1 FROM users u WHERE u.id = d.user_id)
+    ON CONFLICT (photo_id) DO NOTHING;
+
+    INSERT INTO storage_orphans (key)
+    SELECT k.key FROM (
+        SELECT p.user_id, p.key FROM photos p
+        JOIN deleted_notes d ON d.user_id = p.user_id AND d.id = p.note_id
+        UNION ALL
+        SELECT p.user_id, t.value->>'key' FROM photos p
+        JOIN deleted_notes d ON d.user_id = p.user_id AND d.id = p.note_id
+        CROSS JOIN jsonb_each(p.thumbnails) t
+        UNION ALL
+        SELECT a.user_id, a.key FROM attachments a
+        JOIN deleted_notes d ON d.user_id = a.user_id AND d.id = a.note_id
+        UNION ALL
+        SELECT d.user_id, d.content_key FROM deleted_notes d
+    ) k
+    WHERE k.key IS NOT NULL
+      AND EXISTS (SELECT 1 FROM users u WHERE u.id = k.user_id)
+    ON CONFLICT (key) DO NOTHING;
+
+    DELETE FROM photos p USING deleted_notes d
+        WHERE p.user_id = d.user_id AND p.note_id = d.id;
+    DELETE FROM attachments a USING deleted_notes d
+        WHERE a.user_id = d.user_id AND a.note_id = d.id;
+    DELETE FROM note_shares s USING deleted_notes d WHERE s.note_id = d.id;
+    DELETE FROM note_tags t USING deleted_notes d WHERE t.note_id = d.id;
+    DELETE FROM sync_conflicts c USING deleted_notes d WHERE c.note_id = d.id;
+    UPDATE notes n SET merged_into = NULL FROM deleted_notes d
+        WHERE n.user_id = d.user_id AND n.merged_into = d.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TABLE IF EXISTS note_document_updates;
+DROP TABLE IF EXISTS note_documents;
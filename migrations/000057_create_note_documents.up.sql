@@ -0,0 +1,66 @@
+-- Collaborative notes keep their content as a CRDT document: a snapshot
+-- plus the log of updates applied since, numbered by seq. notes.content
+-- holds the document's text after every update. Compaction folds old
+-- updates into the snapshot; snapshot_seq is the last update it contains.
+CREATE TABLE note_documents (
+    note_id UUID PRIMARY KEY,
+    snapshot JSONB NOT NULL,
+    snapshot_seq BIGINT NOT NULL DEFAULT 0,
+    seq BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Updates outlive their author's account or device, since later updates
+-- may build on them.
+CREATE TABLE note_document_updates (
+    note_id UUID NOT NULL REFERENCES note_documents(note_id) ON DELETE CASCADE,
+    seq BIGINT NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    device_id UUID REFERENCES devices(id) ON DELETE SET NULL,
+    ops JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (note_id, seq)
+);
+
+-- Documents go with their note, like its other rows.
+CREATE OR REPLACE FUNCTION notes_on_delete() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO photo_tombstones (photo_id, note_id, user_id, client_id, deleted_at)
+    SELECT p.id, p.note_id, p.user_id, p.client_id, NOW()
+    FROM photos p
+    JOIN deleted_notes d ON d.user_id = p.user_id AND d.id = p.note_id
+    WHERE EXISTS (SELECT 1 FROM users u WHERE u.id = d.user_id)
+    ON CONFLICT (photo_id) DO NOTHING;
+
+    INSERT INTO storage_orphans (key)
+    SELECT k.key FROM (
+        SELECT p.user_id, p.key FROM photos p
+        JOIN deleted_notes d ON d.user_id = p.user_id AND d.id = p.note_id
+        UNION ALL
+        SELECT p.user_id, t.value->>'key' FROM photos p
+        JOIN deleted_notes d ON d.user_id = p.user_id AND d.id = p.note_id
+        CROSS JOIN jsonb_each(p.thumbnails) t
+        UNION ALL
+        SELECT a.user_id, a.key FROM attachments a
+        JOIN deleted_notes d ON d.user_id = a.user_id AND d.id = a.note_id
+        UNION ALL
+        SELECT d.user_id, d.content_key FROM deleted_notes d
+    ) k
+    WHERE k.key IS NOT NULL
+      AND EXISTS (SELECT 1 FROM users u WHERE u.id = k.user_id)
+    ON CONFLICT (key) DO NOTHING;
+
+    DELETE FROM photos p USING deleted_notes d
+        WHERE p.user_id = d.user_id AND p.note_id = d.id;
+    DELETE FROM attachments a USING deleted_notes d
+        WHERE a.user_id = d.user_id AND a.note_id = d.id;
+    DELETE FROM note_shares s USING deleted_notes d WHERE s.note_id = d.id;
+    DELETE FROM note_tags t USING deleted_notes d WHERE t.note_id = d.id;
+    DELETE FROM sync_conflicts c USING deleted_notes d WHERE c.note_id = d.id;
+    DELETE FROM note_documents doc USING deleted_notes d WHERE doc.note_id = d.id;
+    UPDATE notes n SET merged_into = NULL FROM deleted_notes d
+        WHERE n.user_id = d.user_id AND n.merged_into = d.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,51 @@
+-- A view can't lose columns through CREATE OR REPLACE.
+DROP VIEW note_summary_rows;
+
+CREATE VIEW note_summary_rows AS
+SELECT n.id AS note_id, n.user_id, n.team_id, n.title,
+       left(n.content, 280) AS excerpt,
+       (SELECT COALESCE(p.thumbnails->'small'->>'url', p.url)
+        FROM photos p
+        WHERE p.user_id = n.user_id AND p.note_id = n.id
+          AND p.encryption IS NULL AND NOT p.excluded_from_shares
+        ORDER BY p.created_at, p.id
+        LIMIT 1) AS cover_url,
+       n.location, n.sensitivity,
+       COALESCE((SELECT array_agg(t.name ORDER BY t.name)
+                 FROM note_tags nt JOIN tags t ON t.id = nt.tag_id
+                 WHERE nt.note_id = n.id), '{}') AS tags,
+       (SELECT COUNT(*) FROM photos p WHERE p.user_id = n.user_id AND p.note_id = n.id)::INT AS photo_count,
+       (SELECT COUNT(*) FROM photos p
+        WHERE p.user_id = n.user_id AND p.note_id = n.id AND NOT p.excluded_from_shares)::INT AS shared_photo_count,
+       n.created_at, n.updated_at
+FROM notes n
+WHERE n.deleted_at IS NULL;
+
+CREATE OR REPLACE FUNCTION refresh_note_summaries(p_note_ids UUID[]) RETURNS VOID AS $$
+BEGIN
+    INSERT INTO note_summaries
+    SELECT * FROM note_summary_rows WHERE note_id = ANY(p_note_ids)
+    ON CONFLICT (note_id) DO UPDATE SET
+        user_id = EXCLUDED.user_id,
+        team_id = EXCLUDED.team_id,
+        title = EXCLUDED.title,
+        excerpt = EXCLUDED.excerpt,
+        cover_url = EXCLUDED.cover_url,
+        location = EXCLUDED.location,
+        sensitivity = EXCLUDED.sensitivity,
+        tags = EXCLUDED.tags,
+        photo_count = EXCLUDED.photo_count,
+        shared_photo_count = EXCLUDED.shared_photo_count,
+        created_at = EXCLUDED.created_at,
+        updated_at = EXCLUDED.updated_at;
+
+    DELETE FROM note_summaries s
+    WHERE s.note_id = ANY(p_note_ids)
+      AND NOT EXISTS (SELECT 1 FROM note_summary_rows r WHERE r.note_id = s.note_id);
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE note_summaries DROP COLUMN IF EXISTS icon;
+ALTER TABLE note_summaries DROP COLUMN IF EXISTS color;
+ALTER TABLE notes DROP COLUMN IF EXISTS icon;
+ALTER TABLE notes DROP COLUMN IF EXISTS color;
@@ -0,0 +1,4 @@
+-- Set while a note's title is one the server generated from its content,
+-- so clients can tell it apart and the title follows the content until the
+-- user sets one.
+ALTER TABLE notes ADD COLUMN auto_titled BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,111 @@
+-- note_revisions keeps every version of a note that an update replaced, so
+-- a version lost to a last-write-wins sync can be looked at and restored.
+-- Rows are only ever added; they go when their note is hard-deleted.
+-- revision numbers a note's versions from 1, oldest first. updated_at is
+-- when the version was saved and created_at when it was replaced.
+CREATE TABLE note_revisions (
+    note_id UUID NOT NULL,
+    revision INT NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    -- Offloaded content stays in storage for as long as a revision points
+    -- to it, so the object a write replaces is only removed with the note.
+    content_key TEXT,
+    content_url TEXT,
+    location GEOGRAPHY(POINT, 4326),
+    altitude DOUBLE PRECISION,
+    accuracy DOUBLE PRECISION,
+    measurements JSONB NOT NULL DEFAULT '[]',
+    color VARCHAR(32),
+    icon VARCHAR(32),
+    last_modified_by_device VARCHAR(255),
+    updated_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (note_id, revision)
+);
+
+-- A version is kept when an update changes what the user wrote; tags,
+-- quality results and deletion are not part of it. Edits to the content of
+-- a collaborative note are left out, since its document already merges
+-- them and they come a few keystrokes at a time.
+CREATE FUNCTION note_revisions_on_update() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO note_revisions (
+        note_id, revision, user_id, title, content, content_key, content_url,
+        location, altitude, accuracy, measurements, color, icon,
+        last_modified_by_device, updated_at
+    )
+    SELECT o.id,
+           COALESCE((SELECT MAX(r.revision) FROM note_revisions r WHERE r.note_id = o.id), 0) + 1,
+           o.user_id, o.title, o.content, o.content_key, o.content_url,
+           o.location, o.altitude, o.accuracy, o.measurements, o.color, o.icon,
+           o.last_modified_by_device, o.updated_at
+    FROM old_notes o
+    JOIN new_notes n ON n.user_id = o.user_id AND n.id = o.id
+    WHERE n.title IS DISTINCT FROM o.title
+       OR n.content_key IS DISTINCT FROM o.content_key
+       OR n.location IS DISTINCT FROM o.location
+       OR n.altitude IS DISTINCT FROM o.altitude
+       OR n.accuracy IS DISTINCT FROM o.accuracy
+       OR n.measurements IS DISTINCT FROM o.measurements
+       OR n.color IS DISTINCT FROM o.color
+       OR n.icon IS DISTINCT FROM o.icon
+       OR (n.content IS DISTINCT FROM o.content
+           AND NOT EXISTS (SELECT 1 FROM note_documents d WHERE d.note_id = o.id));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Statement-level like the other note triggers, so a merge or a sync batch
+-- compares each note once.
+CREATE TRIGGER note_revisions_note_update
+    AFTER UPDATE ON notes
+    REFERENCING OLD TABLE AS old_notes NEW TABLE AS new_notes
+    FOR EACH STATEMENT EXECUTE FUNCTION note_revisions_on_update();
+
+-- Revisions go with their note, and their content objects with them.
+CREATE OR REPLACE FUNCTION notes_on_delete() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO photo_tombstones (photo_id, note_id, user_id, client_id, deleted_at)
+    SELECT p.id, p.note_id, p.user_id, p.client_id, NOW()
+    FROM photos p
+    JOIN deleted_notes d ON d.user_id = p.user_id AND d.id = p.note_id
+    WHERE EXISTS (SELECT 1 FROM users u WHERE u.id = d.user_id)
+    ON CONFLICT (photo_id) DO NOTHING;
+
+    INSERT INTO storage_orphans (key)
+    SELECT k.key FROM (
+        SELECT p.user_id, p.key FROM photos p
+        JOIN deleted_notes d ON d.user_id = p.user_id AND d.id = p.note_id
+        UNION ALL
+        SELECT p.user_id, t.value->>'key' FROM photos p
+        JOIN deleted_notes d ON d.user_id = p.user_id AND d.id = p.note_id
+        CROSS JOIN jsonb_each(p.thumbnails) t
+        UNION ALL
+        SELECT a.user_id, a.key FROM attachments a
+        JOIN deleted_notes d ON d.user_id = a.user_id AND d.id = a.note_id
+        UNION ALL
+        SELECT d.user_id, d.content_key FROM deleted_notes d
+        UNION ALL
+        SELECT r.user_id, r.content_key FROM note_revisions r
+        JOIN deleted_notes d ON d.id = r.note_id
+    ) k
+    WHERE k.key IS NOT NULL
+      AND EXISTS (SELECT 1 FROM users u WHERE u.id = k.user_id)
+    ON CONFLICT (key) DO NOTHING;
+
+    DELETE FROM photos p USING deleted_notes d
+        WHERE p.user_id = d.user_id AND p.note_id = d.id;
+    DELETE FROM attachments a USING deleted_notes d
+        WHERE a.user_id = d.user_id AND a.note_id = d.id;
+    DELETE FROM note_shares s USING deleted_notes d WHERE s.note_id = d.id;
+    DELETE FROM note_tags t USING deleted_notes d WHERE t.note_id = d.id;
+    DELETE FROM sync_conflicts c USING deleted_notes d WHERE c.note_id = d.id;
+    DELETE FROM note_documents doc USING deleted_notes d WHERE doc.note_id = d.id;
+    DELETE FROM note_revisions r USING deleted_notes d WHERE r.note_id = d.id;
+    UPDATE notes n SET merged_into = NULL FROM deleted_notes d
+        WHERE n.user_id = d.user_id AND n.merged_into = d.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
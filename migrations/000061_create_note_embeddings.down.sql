@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS note_embeddings;
+ALTER TABLE users DROP COLUMN IF EXISTS semantic_search;
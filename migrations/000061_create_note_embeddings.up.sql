@@ -0,0 +1,33 @@
+-- Semantic search is opt-in: the notes of a user who has not turned it on
+-- are never sent to the embeddings provider.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS semantic_search BOOLEAN NOT NULL DEFAULT false;
+
+-- Embeddings are stored with pgvector, which the stock PostGIS image does
+-- not ship. Without it the table is left out and the API refuses to start
+-- with semantic search configured. The migration can be run again once
+-- pgvector is installed (migrate force 60, then migrate up).
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = 'vector') THEN
+        RAISE NOTICE 'pgvector is not available; skipping note_embeddings';
+        RETURN;
+    END IF;
+
+    CREATE EXTENSION IF NOT EXISTS vector;
+
+    -- One embedding per note, of the version with note_updated_at, so later
+    -- edits are embedded again. The vector's dimension is the model's, and
+    -- only vectors of the same model are compared. Searches are per user
+    -- and scan that user's rows, so there is no approximate index.
+    CREATE TABLE IF NOT EXISTS note_embeddings (
+        note_id UUID PRIMARY KEY,
+        user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+        model VARCHAR(100) NOT NULL,
+        embedding vector NOT NULL,
+        note_updated_at TIMESTAMPTZ NOT NULL,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+    );
+
+    CREATE INDEX IF NOT EXISTS idx_note_embeddings_user_model ON note_embeddings(user_id, model);
+END
+$$;
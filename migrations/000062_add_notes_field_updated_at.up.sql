@@ -0,0 +1,8 @@
+-- When the title, content and location of a note last changed, as
+-- {"title": ..., "content": ..., "location": ...}, so sync can merge
+-- concurrent edits of different fields. Existing notes start with all three
+-- at updated_at.
+ALTER TABLE notes ADD COLUMN field_updated_at JSONB NOT NULL DEFAULT '{}';
+
+UPDATE notes SET field_updated_at = jsonb_build_object(
+    'title', updated_at, 'content', updated_at, 'location', updated_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_photos_user_note_source_checksum;
+ALTER TABLE photos DROP COLUMN IF EXISTS source_checksum;
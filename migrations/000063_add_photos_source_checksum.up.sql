@@ -0,0 +1,9 @@
+-- SHA-256 of the file as uploaded, before it is resized, so that uploading
+-- the same file to a note again returns the stored photo. Photos uploaded
+-- before have none: the original bytes are not kept.
+ALTER TABLE photos ADD COLUMN source_checksum VARCHAR(64);
+
+-- schemacheck:ignore index-not-concurrent (photos is partitioned, where
+-- CONCURRENTLY is not supported; the column was just added, so the index is empty)
+CREATE UNIQUE INDEX idx_photos_user_note_source_checksum ON photos(user_id, note_id, source_checksum)
+    WHERE source_checksum IS NOT NULL;
@@ -0,0 +1,8 @@
+// Package migrations embeds the SQL migrations so the binary does not depend
+// on the directory being deployed next to it.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
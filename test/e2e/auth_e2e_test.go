@@ -3,6 +3,7 @@ package e2e_test
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -78,9 +79,61 @@ func TestE2E_Auth_RegisterAndLogin(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
 		resp.Body.Close()
+
+		// 6. The access token is no longer accepted
+		resp, err = app.get("/notes", authHeader(newAccessToken))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		resp.Body.Close()
 	})
 }
 
+func TestE2E_Auth_DeleteAccount(t *testing.T) {
+	app := setupTestApp(t)
+	defer app.cleanup(t)
+
+	resp, err := app.post("/auth/register", map[string]string{
+		"email":    "leaving@example.com",
+		"password": "securePassword123",
+		"name":     "Leaving User",
+	}, nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	loginReq := map[string]string{
+		"email":     "leaving@example.com",
+		"password":  "securePassword123",
+		"device_id": "device-001",
+		"platform":  "ios",
+	}
+	resp, err = app.post("/auth/login", loginReq, nil)
+	require.NoError(t, err)
+	var loginResp map[string]any
+	parseResponse(t, resp, &loginResp)
+	accessToken := loginResp["access_token"].(string)
+	refreshToken := loginResp["refresh_token"].(string)
+
+	resp, err = app.delete("/me", authHeader(accessToken))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = app.get("/notes", authHeader(accessToken))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = app.post("/auth/refresh", map[string]string{"refresh_token": refreshToken}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = app.post("/auth/login", loginReq, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp.Body.Close()
+}
+
 func TestE2E_Auth_Register_DuplicateEmail(t *testing.T) {
 	app := setupTestApp(t)
 	defer app.cleanup(t)
@@ -286,3 +339,82 @@ func TestE2E_Auth_MultipleDevices(t *testing.T) {
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 	resp.Body.Close()
 }
+
+func TestE2E_Auth_AuditLog(t *testing.T) {
+	app := setupTestApp(t)
+	defer app.cleanup(t)
+
+	resp, err := app.post("/auth/register", map[string]string{
+		"email":    "audit@example.com",
+		"password": "correctPassword",
+		"name":     "Audit User",
+	}, nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	loginReq := map[string]string{
+		"email":     "audit@example.com",
+		"password":  "wrongPassword",
+		"device_id": "device-001",
+		"platform":  "ios",
+	}
+	resp, err = app.post("/auth/login", loginReq, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp.Body.Close()
+
+	loginReq["password"] = "correctPassword"
+	resp, err = app.post("/auth/login", loginReq, map[string]string{"User-Agent": "FieldNotes/2.4.0"})
+	require.NoError(t, err)
+	var loginResp map[string]any
+	parseResponse(t, resp, &loginResp)
+	accessToken := loginResp["access_token"].(string)
+
+	resp, err = app.get("/me/audit", authHeader(accessToken))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var auditResp struct {
+		Events []struct {
+			Action    string `json:"action"`
+			Target    string `json:"target"`
+			DeviceID  string `json:"device_id"`
+			IP        string `json:"ip"`
+			UserAgent string `json:"user_agent"`
+		} `json:"events"`
+	}
+	parseResponse(t, resp, &auditResp)
+	require.Len(t, auditResp.Events, 3)
+	assert.Equal(t, "login", auditResp.Events[0].Action)
+	assert.Equal(t, "password", auditResp.Events[0].Target)
+	assert.NotEmpty(t, auditResp.Events[0].DeviceID)
+	assert.NotEmpty(t, auditResp.Events[0].IP)
+	assert.Equal(t, "FieldNotes/2.4.0", auditResp.Events[0].UserAgent)
+	assert.Equal(t, "login_failed", auditResp.Events[1].Action)
+	assert.Equal(t, "account_created", auditResp.Events[2].Action)
+}
+
+func TestE2E_Auth_Scenario_RefreshTokenExpires(t *testing.T) {
+	app := setupTestApp(t)
+	defer app.cleanup(t)
+
+	s := app.Scenario(t).CreateUserWithNotes(0)
+	device := s.Devices[0]
+
+	// The test app issues refresh tokens for 24 hours.
+	s.AdvanceClock(23 * time.Hour)
+	resp, err := app.post("/auth/refresh", map[string]string{"refresh_token": device.RefreshToken}, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var refreshed struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	parseResponse(t, resp, &refreshed)
+
+	s.AdvanceClock(25 * time.Hour)
+	resp, err = app.post("/auth/refresh", map[string]string{"refresh_token": refreshed.RefreshToken}, nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
@@ -0,0 +1,252 @@
+package e2e_test
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// scenarioUsers numbers the users of scenarios so that they never share an
+// email within a test binary.
+var scenarioUsers atomic.Int64
+
+// Scenario builds the state an end-to-end test starts from, through the API
+// and in the order the steps are chained:
+//
+//	s := app.Scenario(t).CreateUserWithNotes(3).WithPhotos(2).WithDevices(2)
+//	s.AdvanceClock(time.Minute)
+//	s.Devices[1].Sync(t, s.Devices[1].Edit(s.Notes[0], "Revisited"))
+//
+// Notes, deletions, sync cursors and refresh tokens are stamped by app.Clock,
+// so timestamps only move when the scenario advances the clock.
+type Scenario struct {
+	t   *testing.T
+	app *TestApp
+
+	Email string
+	// Devices are signed-in devices of the user, the first being the one
+	// that created the user.
+	Devices []*Device
+	Notes   []ScenarioNote
+}
+
+type ScenarioNote struct {
+	ID       string
+	ClientID string
+	Title    string
+	// PhotoIDs are the note's photos, in upload order.
+	PhotoIDs []string
+}
+
+// Device is one signed-in device of a scenario's user, with the sync cursor
+// it was last given.
+type Device struct {
+	app *TestApp
+
+	ID           string
+	Token        string
+	RefreshToken string
+	Cursor       *time.Time
+}
+
+func (app *TestApp) Scenario(t *testing.T) *Scenario {
+	t.Helper()
+	return &Scenario{t: t, app: app}
+}
+
+// CreateUserWithNotes registers a user, signs in its first device and
+// creates n notes on it, one a second apart on the clock.
+func (s *Scenario) CreateUserWithNotes(n int) *Scenario {
+	s.t.Helper()
+	require.Empty(s.t, s.Email, "the scenario already has a user")
+
+	s.Email = fmt.Sprintf("scenario-%d@example.com", scenarioUsers.Add(1))
+	resp, err := s.app.post("/auth/register", map[string]string{
+		"email":    s.Email,
+		"password": "password123",
+		"name":     "Scenario User",
+	}, nil)
+	require.NoError(s.t, err)
+	require.Equal(s.t, http.StatusCreated, resp.StatusCode)
+	resp.Body.Close()
+
+	s.Devices = append(s.Devices, s.login())
+
+	for i := range n {
+		if i > 0 {
+			s.app.Clock.Advance(time.Second)
+		}
+		s.createNote(fmt.Sprintf("Note %d", i+1))
+	}
+	return s
+}
+
+// WithPhotos uploads n photos to each of the notes.
+func (s *Scenario) WithPhotos(n int) *Scenario {
+	s.t.Helper()
+
+	for i := range s.Notes {
+		for j := range n {
+			photoID := s.app.uploadPhoto(s.t, s.Devices[0].Token, s.Notes[i].ID, fmt.Sprintf("photo-%d.jpg", j+1))
+			s.Notes[i].PhotoIDs = append(s.Notes[i].PhotoIDs, photoID)
+		}
+	}
+	return s
+}
+
+// WithDevices signs the user in on more devices until it has n.
+func (s *Scenario) WithDevices(n int) *Scenario {
+	s.t.Helper()
+	require.NotEmpty(s.t, s.Email, "create the user first")
+
+	for len(s.Devices) < n {
+		s.Devices = append(s.Devices, s.login())
+	}
+	return s
+}
+
+func (s *Scenario) AdvanceClock(d time.Duration) *Scenario {
+	s.app.Clock.Advance(d)
+	return s
+}
+
+func (s *Scenario) login() *Device {
+	s.t.Helper()
+
+	deviceID := fmt.Sprintf("device-%03d", len(s.Devices)+1)
+	resp, err := s.app.post("/auth/login", map[string]string{
+		"email":     s.Email,
+		"password":  "password123",
+		"device_id": deviceID,
+		"platform":  "ios",
+	}, nil)
+	require.NoError(s.t, err)
+	require.Equal(s.t, http.StatusOK, resp.StatusCode)
+
+	var login struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	parseResponse(s.t, resp, &login)
+	return &Device{app: s.app, ID: deviceID, Token: login.AccessToken, RefreshToken: login.RefreshToken}
+}
+
+func (s *Scenario) createNote(title string) {
+	s.t.Helper()
+
+	clientID := fmt.Sprintf("client-%d", len(s.Notes)+1)
+	resp, err := s.app.post("/notes", map[string]any{
+		"client_id": clientID,
+		"title":     title,
+		"content":   "Created by the scenario",
+	}, authHeader(s.Devices[0].Token))
+	require.NoError(s.t, err)
+	require.Equal(s.t, http.StatusCreated, resp.StatusCode)
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	parseResponse(s.t, resp, &created)
+	s.Notes = append(s.Notes, ScenarioNote{ID: created.ID, ClientID: clientID, Title: title})
+}
+
+// syncResult is the part of a sync response scenarios look at.
+type syncResult struct {
+	NewCursor   time.Time `json:"new_cursor"`
+	ServerNotes []struct {
+		ID             string               `json:"id"`
+		ClientID       string               `json:"client_id"`
+		Title          string               `json:"title"`
+		UpdatedAt      time.Time            `json:"updated_at"`
+		FieldUpdatedAt map[string]time.Time `json:"field_updated_at"`
+	} `json:"server_notes"`
+	Conflicts []struct {
+		ClientID   string `json:"client_id"`
+		Resolution string `json:"resolution"`
+	} `json:"conflicts"`
+	Warnings []struct {
+		ClientID string `json:"client_id"`
+		Code     string `json:"code"`
+	} `json:"warnings"`
+}
+
+// Edit is a client version of the note with a new title, stamped with the
+// current time of the clock.
+func (d *Device) Edit(note ScenarioNote, title string) map[string]any {
+	return d.EditAt(note, title, d.app.Clock.Now())
+}
+
+// EditAt is Edit with the device's own idea of the time, for devices whose
+// clock is off.
+func (d *Device) EditAt(note ScenarioNote, title string, at time.Time) map[string]any {
+	return map[string]any{
+		"client_id":  note.ClientID,
+		"title":      title,
+		"content":    "Edited on " + d.ID,
+		"updated_at": at.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+// Sync pushes the notes from the device's cursor and moves the cursor to
+// the one returned.
+func (d *Device) Sync(t *testing.T, notes ...map[string]any) syncResult {
+	t.Helper()
+
+	body := map[string]any{
+		"device_id": d.ID,
+		"notes":     append([]map[string]any{}, notes...),
+	}
+	if d.Cursor != nil {
+		body["sync_cursor"] = d.Cursor.Format(time.RFC3339Nano)
+	}
+
+	resp, err := d.app.post("/sync", body, authHeader(d.Token))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result syncResult
+	parseResponse(t, resp, &result)
+	d.Cursor = &result.NewCursor
+	return result
+}
+
+// uploadPhoto uploads a stand-in JPEG to the note and returns the photo's
+// ID; the test app's image processor takes any bytes.
+func (app *TestApp) uploadPhoto(t *testing.T, token, noteID, filename string) string {
+	t.Helper()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, filename))
+	header.Set("Content-Type", "image/jpeg")
+	part, err := form.CreatePart(header)
+	require.NoError(t, err)
+	_, err = part.Write([]byte("jpeg " + filename))
+	require.NoError(t, err)
+	require.NoError(t, form.Close())
+
+	req, err := http.NewRequest(http.MethodPost, app.BaseURL+apiBasePath+"/upload/"+noteID, &body)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := app.httpClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var uploaded struct {
+		Photo struct {
+			ID string `json:"id"`
+		} `json:"photo"`
+	}
+	parseResponse(t, resp, &uploaded)
+	return uploaded.Photo.ID
+}
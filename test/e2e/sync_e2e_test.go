@@ -460,3 +460,117 @@ func TestE2E_Sync_MultipleDevices(t *testing.T) {
 	note := serverNotes[0].(map[string]any)
 	assert.Equal(t, "Note from Device 1", note["title"])
 }
+
+func TestE2E_Sync_Scenario_LastWriteWins(t *testing.T) {
+	app := setupTestApp(t)
+	defer app.cleanup(t)
+
+	s := app.Scenario(t).CreateUserWithNotes(2).WithPhotos(1).WithDevices(2)
+	phone, tablet := s.Devices[0], s.Devices[1]
+
+	first := tablet.Sync(t)
+	require.Len(t, first.ServerNotes, 2)
+	phone.Sync(t)
+
+	// Both devices edit the same note offline, the tablet a minute later,
+	// and the tablet syncs first.
+	s.AdvanceClock(time.Minute)
+	phoneEdit := phone.Edit(s.Notes[0], "From the phone")
+	s.AdvanceClock(time.Minute)
+	tabletEdit := tablet.Edit(s.Notes[0], "From the tablet")
+
+	result := tablet.Sync(t, tabletEdit)
+	require.Len(t, result.Conflicts, 1)
+	assert.Equal(t, "client_wins", result.Conflicts[0].Resolution)
+
+	result = phone.Sync(t, phoneEdit)
+	require.Len(t, result.Conflicts, 1)
+	assert.Equal(t, "server_wins", result.Conflicts[0].Resolution)
+	require.Len(t, result.ServerNotes, 1)
+	assert.Equal(t, "From the tablet", result.ServerNotes[0].Title)
+	assert.True(t, result.ServerNotes[0].UpdatedAt.Equal(app.Clock.Now()))
+
+	resp, err := app.get("/notes/"+s.Notes[0].ID, authHeader(phone.Token))
+	require.NoError(t, err)
+	var note map[string]any
+	parseResponse(t, resp, &note)
+	assert.Equal(t, "From the tablet", note["title"])
+	assert.Len(t, note["photos"], 1)
+}
+
+func TestE2E_Sync_Scenario_FutureTimestamp(t *testing.T) {
+	app := setupTestApp(t)
+	defer app.cleanup(t)
+
+	s := app.Scenario(t).CreateUserWithNotes(1).WithDevices(2)
+	phone, tablet := s.Devices[0], s.Devices[1]
+	tablet.Sync(t)
+	s.AdvanceClock(time.Minute)
+
+	// The phone's clock runs an hour ahead; its edit is stamped with the
+	// server time instead, so a later edit from the tablet still wins.
+	result := phone.Sync(t, phone.EditAt(s.Notes[0], "Ahead of time", app.Clock.Now().Add(time.Hour)))
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, "FUTURE_TIMESTAMP_CLAMPED", result.Warnings[0].Code)
+
+	s.AdvanceClock(time.Minute)
+	result = tablet.Sync(t, tablet.Edit(s.Notes[0], "On time"))
+	require.Len(t, result.Conflicts, 1)
+	assert.Equal(t, "client_wins", result.Conflicts[0].Resolution)
+}
+
+func TestE2E_Sync_Scenario_FieldMerge(t *testing.T) {
+	app := setupTestApp(t)
+	defer app.cleanup(t)
+
+	s := app.Scenario(t).CreateUserWithNotes(1).WithDevices(2)
+	phone, tablet := s.Devices[0], s.Devices[1]
+	pulled := tablet.Sync(t)
+	require.Len(t, pulled.ServerNotes, 1)
+	phone.Sync(t)
+	original := s.Notes[0]
+	fields := pulled.ServerNotes[0].FieldUpdatedAt
+
+	// The phone renames the note and the tablet rewrites its content, each
+	// offline; the tablet syncs first and the phone's older edit merges in.
+	// Each sends the pulled times for the fields it left alone.
+	s.AdvanceClock(time.Minute)
+	renamedAt := app.Clock.Now()
+	s.AdvanceClock(time.Minute)
+	rewrittenAt := app.Clock.Now()
+
+	tablet.Sync(t, map[string]any{
+		"client_id":  original.ClientID,
+		"title":      original.Title,
+		"content":    "Rewritten on the tablet",
+		"updated_at": rewrittenAt,
+		"field_updated_at": map[string]any{
+			"title": fields["title"], "content": rewrittenAt, "location": fields["location"],
+		},
+	})
+
+	s.AdvanceClock(time.Minute)
+	result := phone.Sync(t, map[string]any{
+		"client_id":  original.ClientID,
+		"title":      "Renamed on the phone",
+		"content":    "Created by the scenario",
+		"updated_at": renamedAt,
+		"field_updated_at": map[string]any{
+			"title": renamedAt, "content": fields["content"], "location": fields["location"],
+		},
+	})
+	require.Len(t, result.Conflicts, 1)
+	assert.Equal(t, "merged", result.Conflicts[0].Resolution)
+
+	resp, err := app.get("/notes/"+original.ID, authHeader(tablet.Token))
+	require.NoError(t, err)
+	var note map[string]any
+	parseResponse(t, resp, &note)
+	assert.Equal(t, "Renamed on the phone", note["title"])
+	assert.Equal(t, "Rewritten on the tablet", note["content"])
+
+	// The tablet pulls the merged note on its next sync.
+	result = tablet.Sync(t)
+	require.Len(t, result.ServerNotes, 1)
+	assert.Equal(t, "Renamed on the phone", result.ServerNotes[0].Title)
+}
@@ -7,8 +7,6 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
-	"path/filepath"
-	"runtime"
 	"testing"
 	"time"
 
@@ -22,14 +20,30 @@ import (
 
 	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/handler"
 	pgRepo "github.com/marcos-nsantos/field-notes-backend/internal/adapter/repository/postgres"
+	"github.com/marcos-nsantos/field-notes-backend/internal/adapter/storage"
+	"github.com/marcos-nsantos/field-notes-backend/internal/domain/entity"
 	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/auth"
 	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/database"
 	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/middleware"
 	"github.com/marcos-nsantos/field-notes-backend/internal/infrastructure/server"
+	"github.com/marcos-nsantos/field-notes-backend/internal/pkg/clock"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/account"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/anomaly"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/audit"
 	authUC "github.com/marcos-nsantos/field-notes-backend/internal/usecase/auth"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/authz"
 	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/note"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/preference"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/privacy"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/quality"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/share"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/stats"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/summary"
 	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/sync"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/team"
 	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/upload"
+	"github.com/marcos-nsantos/field-notes-backend/internal/usecase/usage"
+	"github.com/marcos-nsantos/field-notes-backend/migrations"
 )
 
 const (
@@ -37,14 +51,18 @@ const (
 	testDBPassword = "testpass"
 	testDBName     = "testdb"
 	testJWTSecret  = "test-secret-key-for-e2e-tests"
+	testSSOKey     = "IxhGeSNi1MOzqY+dTltMC9dLkiPjhwbGZM9zN+e1sNg="
 	apiBasePath    = "/api/v1"
 )
 
 type TestApp struct {
-	Server     *httptest.Server
-	Pool       *pgxpool.Pool
-	Container  testcontainers.Container
-	BaseURL    string
+	Server    *httptest.Server
+	Pool      *pgxpool.Pool
+	Container testcontainers.Container
+	BaseURL   string
+	// Clock stamps notes and sync cursors. It starts at the current time and
+	// only moves when a test advances it.
+	Clock      *clock.Fake
 	httpClient *http.Client
 }
 
@@ -80,50 +98,96 @@ func setupTestApp(t *testing.T) *TestApp {
 	require.NoError(t, err)
 
 	// Run migrations
-	migrationsPath := getMigrationsPath()
-	err = database.RunMigrations(ctx, pool, migrationsPath)
+	err = database.RunMigrations(ctx, pool, migrations.FS)
 	require.NoError(t, err)
 
+	// Postgres keeps microseconds; starting there lets tests compare
+	// timestamps read back with the clock.
+	clk := clock.NewFake(time.Now().Truncate(time.Microsecond))
+
 	// Initialize repositories
 	userRepo := pgRepo.NewUserRepo(pool)
-	noteRepo := pgRepo.NewNoteRepo(pool)
-	photoRepo := pgRepo.NewPhotoRepo(pool)
+	noteRepo := pgRepo.NewNoteRepo(pool, nil, nil, clk)
+	photoRepo := pgRepo.NewPhotoRepo(pool, clk)
+	attachmentRepo := pgRepo.NewAttachmentRepo(pool)
 	deviceRepo := pgRepo.NewDeviceRepo(pool)
-	refreshTokenRepo := pgRepo.NewRefreshTokenRepo(pool)
+	refreshTokenRepo := pgRepo.NewRefreshTokenRepo(pool, clk)
+	ssoSecrets, err := auth.NewSecretBox(testSSOKey)
+	require.NoError(t, err)
+	orgRepo := pgRepo.NewOrganizationRepo(pool, ssoSecrets)
+	deviceUsageRepo := pgRepo.NewDeviceUsageRepo(pool)
+	qualityRuleRepo := pgRepo.NewQualityRuleRepo(pool)
+	shareRepo := pgRepo.NewShareRepo(pool)
+	teamRepo := pgRepo.NewTeamRepo(pool)
 
 	// Initialize infrastructure services
 	jwtSvc := auth.NewJWTService(testJWTSecret, 15*time.Minute)
 	passwordHasher := auth.NewPasswordHasher(4) // Lower cost for faster tests
+	oidcClient := auth.NewOIDCClient("http://localhost", 5*time.Second, time.Minute)
 
 	// Stub storage for e2e tests (avoids S3 dependency)
 	stubStorage := &stubImageStorage{}
 	stubProcessor := &stubImageProcessor{}
 
 	// Initialize use cases
-	authSvc := authUC.NewService(userRepo, deviceRepo, refreshTokenRepo, jwtSvc, passwordHasher, 24*time.Hour)
-	noteSvc := note.NewService(noteRepo, photoRepo)
-	syncSvc := sync.NewService(noteRepo, deviceRepo)
-	uploadSvc := upload.NewService(photoRepo, noteRepo, stubStorage, stubProcessor)
+	auditRecorder := audit.NewRecorder(pgRepo.NewAuditEventRepo(pool))
+	authSvc := authUC.NewService(userRepo, deviceRepo, refreshTokenRepo, orgRepo, jwtSvc, passwordHasher, oidcClient, nil, pgRepo.NewAuthEventRepo(pool), nil, nil, 24*time.Hour, authUC.PasswordResetConfig{}, authUC.SessionConfig{}, nil, nil, auditRecorder, nil, clk)
+	authorizer := authz.NewAuthorizer(shareRepo, orgRepo, teamRepo)
+	noteSvc := note.NewService(noteRepo, photoRepo, qualityRuleRepo, authorizer, auditRecorder, nil, clk)
+	syncSvc := sync.NewService(noteRepo, photoRepo, deviceRepo, qualityRuleRepo, userRepo, nil, nil, pgRepo.NewSyncConflictRepo(pool), 24*time.Hour, nil, clk)
+	uploadSvc := upload.NewService(photoRepo, attachmentRepo, noteRepo, qualityRuleRepo, stubStorage, stubProcessor, authorizer)
+	usageSvc := usage.NewService(deviceUsageRepo, deviceRepo)
+	qualitySvc := quality.NewService(qualityRuleRepo, noteRepo, photoRepo)
+	prefSvc := preference.NewService(userRepo)
+	shareSvc := share.NewService(noteRepo, userRepo, shareRepo, authorizer)
+	teamSvc := team.NewService(teamRepo, userRepo, authorizer)
+	privacySvc := privacy.NewService(noteRepo, authorizer, entity.PIIScanner{Detectors: []string{entity.PIIEmail, entity.PIIPhone, entity.PIICoordinates}})
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authSvc)
-	noteHandler := handler.NewNoteHandler(noteSvc)
-	syncHandler := handler.NewSyncHandler(syncSvc)
+	noteHandler := handler.NewNoteHandler(noteSvc, prefSvc, entity.LocationMask{})
+	summaryHandler := handler.NewNoteSummaryHandler(summary.NewService(pgRepo.NewNoteSummaryRepo(pool, nil), authorizer), entity.LocationMask{})
+	syncHandler := handler.NewSyncHandler(syncSvc, prefSvc, entity.LocationMask{})
 	uploadHandler := handler.NewUploadHandler(uploadSvc)
+	deviceHandler := handler.NewDeviceHandler(usageSvc, syncSvc)
+	qualityHandler := handler.NewQualityHandler(qualitySvc)
+	prefHandler := handler.NewPreferenceHandler(prefSvc)
+	shareHandler := handler.NewShareHandler(shareSvc)
+	teamHandler := handler.NewTeamHandler(teamSvc)
+	privacyHandler := handler.NewPrivacyHandler(privacySvc)
+	labelHandler := handler.NewLabelHandler(noteSvc, "fieldnotes://notes/{id}")
+	statsHandler := handler.NewStatsHandler(stats.NewService(pgRepo.NewUserStatsRepo(pool)))
+	accountHandler := handler.NewAccountHandler(account.NewService(pgRepo.NewAccountDeletionRepo(pool), stubStorage, time.Hour, auditRecorder))
+	alertHandler := handler.NewAlertHandler(anomaly.NewService(
+		pgRepo.NewAuthEventRepo(pool), pgRepo.NewSecurityAlertRepo(pool), deviceUsageRepo, deviceRepo, userRepo, nil, anomaly.Thresholds{},
+	))
 
 	// Initialize middleware
-	authMiddleware := middleware.NewAuthMiddleware(jwtSvc)
+	authMiddleware := middleware.NewAuthMiddleware(jwtSvc, userRepo)
 
 	// Create router
 	logger, _ := zap.NewDevelopment()
 	router := server.NewRouter(server.RouterConfig{
-		AuthHandler:    authHandler,
-		NoteHandler:    noteHandler,
-		SyncHandler:    syncHandler,
-		UploadHandler:  uploadHandler,
-		AuthMiddleware: authMiddleware,
-		Logger:         logger,
-		Environment:    "test",
+		AuthHandler:       authHandler,
+		NoteHandler:       noteHandler,
+		SummaryHandler:    summaryHandler,
+		SyncHandler:       syncHandler,
+		UploadHandler:     uploadHandler,
+		DeviceHandler:     deviceHandler,
+		QualityHandler:    qualityHandler,
+		PreferenceHandler: prefHandler,
+		ShareHandler:      shareHandler,
+		TeamHandler:       teamHandler,
+		PrivacyHandler:    privacyHandler,
+		LabelHandler:      labelHandler,
+		StatsHandler:      statsHandler,
+		AccountHandler:    accountHandler,
+		AlertHandler:      alertHandler,
+		AuditHandler:      handler.NewAuditHandler(auditRecorder),
+		AuthMiddleware:    authMiddleware,
+		UsageRecorder:     usageSvc,
+		Logger:            logger,
+		Environment:       "test",
 	})
 
 	// Create test server
@@ -134,6 +198,7 @@ func setupTestApp(t *testing.T) *TestApp {
 		Pool:      pool,
 		Container: pgContainer,
 		BaseURL:   ts.URL,
+		Clock:     clk,
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
@@ -215,8 +280,8 @@ func authHeader(token string) map[string]string {
 
 type stubImageStorage struct{}
 
-func (s *stubImageStorage) Upload(ctx context.Context, key string, reader io.Reader, contentType string, size int64) error {
-	return nil
+func (s *stubImageStorage) Upload(ctx context.Context, key string, reader io.Reader, contentType string, size int64) (string, error) {
+	return "AES256", nil
 }
 
 func (s *stubImageStorage) Delete(ctx context.Context, key string) error {
@@ -231,16 +296,21 @@ func (s *stubImageStorage) GetSignedURL(key string, duration time.Duration) (str
 	return "https://stub-storage.example.com/" + key + "?signed=true", nil
 }
 
+func (s *stubImageStorage) Download(ctx context.Context, key string) ([]byte, error) {
+	return nil, nil
+}
+
 type stubImageProcessor struct{}
 
-func (s *stubImageProcessor) Process(reader io.Reader, contentType string) (io.Reader, int64, int, int, error) {
+func (s *stubImageProcessor) Process(_ context.Context, reader io.Reader) (io.Reader, int64, int, int, error) {
 	data, _ := io.ReadAll(reader)
 	return bytes.NewReader(data), int64(len(data)), 800, 600, nil
 }
 
-// getMigrationsPath returns the absolute path to the migrations directory
-func getMigrationsPath() string {
-	_, filename, _, _ := runtime.Caller(0)
-	testDir := filepath.Dir(filename)
-	return filepath.Join(testDir, "..", "..", "migrations")
+func (s *stubImageProcessor) Thumbnails(_ context.Context, reader io.Reader) ([]storage.Thumbnail, error) {
+	return nil, nil
+}
+
+func (s *stubImageProcessor) Metadata(data []byte) storage.ImageMetadata {
+	return storage.ImageMetadata{}
 }